@@ -26,6 +26,7 @@ import (
 	posthogTelemetry "github.com/devtron-labs/common-lib/telemetry"
 	util4 "github.com/devtron-labs/common-lib/utils/k8s"
 	"github.com/devtron-labs/devtron/api/apiToken"
+	appAsCode2 "github.com/devtron-labs/devtron/api/appAsCode"
 	appStoreRestHandler "github.com/devtron-labs/devtron/api/appStore"
 	chartGroup2 "github.com/devtron-labs/devtron/api/appStore/chartGroup"
 	chartProvider "github.com/devtron-labs/devtron/api/appStore/chartProvider"
@@ -33,6 +34,7 @@ import (
 	appStoreDiscover "github.com/devtron-labs/devtron/api/appStore/discover"
 	appStoreValues "github.com/devtron-labs/devtron/api/appStore/values"
 	"github.com/devtron-labs/devtron/api/argoApplication"
+	"github.com/devtron-labs/devtron/api/artifactPromotion"
 	"github.com/devtron-labs/devtron/api/auth/sso"
 	"github.com/devtron-labs/devtron/api/auth/user"
 	chartRepo "github.com/devtron-labs/devtron/api/chartRepo"
@@ -44,12 +46,15 @@ import (
 	"github.com/devtron-labs/devtron/api/externalLink"
 	fluxApplication "github.com/devtron-labs/devtron/api/fluxApplication"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	"github.com/devtron-labs/devtron/api/imageSigning"
 	"github.com/devtron-labs/devtron/api/k8s"
 	"github.com/devtron-labs/devtron/api/module"
+	"github.com/devtron-labs/devtron/api/pluginCatalog"
 	"github.com/devtron-labs/devtron/api/resourceScan"
 	"github.com/devtron-labs/devtron/api/restHandler"
 	"github.com/devtron-labs/devtron/api/restHandler/app/appInfo"
 	appList2 "github.com/devtron-labs/devtron/api/restHandler/app/appList"
+	appTemplate3 "github.com/devtron-labs/devtron/api/restHandler/app/appTemplate"
 	configDiff2 "github.com/devtron-labs/devtron/api/restHandler/app/configDiff"
 	pipeline3 "github.com/devtron-labs/devtron/api/restHandler/app/pipeline"
 	pipeline2 "github.com/devtron-labs/devtron/api/restHandler/app/pipeline/configure"
@@ -63,6 +68,7 @@ import (
 	app3 "github.com/devtron-labs/devtron/api/router/app"
 	appInfo2 "github.com/devtron-labs/devtron/api/router/app/appInfo"
 	"github.com/devtron-labs/devtron/api/router/app/appList"
+	appTemplate2 "github.com/devtron-labs/devtron/api/router/app/appTemplate"
 	configDiff3 "github.com/devtron-labs/devtron/api/router/app/configDiff"
 	pipeline5 "github.com/devtron-labs/devtron/api/router/app/pipeline"
 	pipeline4 "github.com/devtron-labs/devtron/api/router/app/pipeline/configure"
@@ -74,6 +80,7 @@ import (
 	"github.com/devtron-labs/devtron/api/sse"
 	"github.com/devtron-labs/devtron/api/team"
 	"github.com/devtron-labs/devtron/api/terminal"
+	"github.com/devtron-labs/devtron/api/testReport"
 	"github.com/devtron-labs/devtron/api/userResource"
 	util5 "github.com/devtron-labs/devtron/api/util"
 	webhookHelm "github.com/devtron-labs/devtron/api/webhook/helm"
@@ -115,6 +122,7 @@ import (
 	read4 "github.com/devtron-labs/devtron/pkg/app/appDetails/read"
 	"github.com/devtron-labs/devtron/pkg/app/dbMigration"
 	"github.com/devtron-labs/devtron/pkg/app/status"
+	"github.com/devtron-labs/devtron/pkg/appAsCode"
 	"github.com/devtron-labs/devtron/pkg/appClone"
 	"github.com/devtron-labs/devtron/pkg/appClone/batch"
 	"github.com/devtron-labs/devtron/pkg/appStatus"
@@ -122,12 +130,15 @@ import (
 	repository4 "github.com/devtron-labs/devtron/pkg/appStore/chartGroup/repository"
 	repository9 "github.com/devtron-labs/devtron/pkg/appStore/installedApp/repository"
 	deployment3 "github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode/deployment"
+	"github.com/devtron-labs/devtron/pkg/appTemplate"
 	"github.com/devtron-labs/devtron/pkg/appWorkflow"
 	"github.com/devtron-labs/devtron/pkg/asyncProvider"
 	"github.com/devtron-labs/devtron/pkg/attributes"
 	"github.com/devtron-labs/devtron/pkg/build"
 	"github.com/devtron-labs/devtron/pkg/build/artifacts/imageTagging"
 	pipeline6 "github.com/devtron-labs/devtron/pkg/build/pipeline"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/chartRefMigration"
 	"github.com/devtron-labs/devtron/pkg/bulkAction/service"
 	"github.com/devtron-labs/devtron/pkg/chart"
 	"github.com/devtron-labs/devtron/pkg/chart/gitOpsConfig"
@@ -140,6 +151,8 @@ import (
 	deployment2 "github.com/devtron-labs/devtron/pkg/deployment"
 	"github.com/devtron-labs/devtron/pkg/deployment/common"
 	git2 "github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/monorepo"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/pullRequest"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/configMapAndSecret"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/publish"
@@ -164,10 +177,12 @@ import (
 	"github.com/devtron-labs/devtron/pkg/pipeline/workflowStatus"
 	repository6 "github.com/devtron-labs/devtron/pkg/pipeline/workflowStatus/repository"
 	"github.com/devtron-labs/devtron/pkg/plugin"
+	"github.com/devtron-labs/devtron/pkg/plugin/catalog"
 	"github.com/devtron-labs/devtron/pkg/policyGovernance"
 	resourceGroup2 "github.com/devtron-labs/devtron/pkg/resourceGroup"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
 	"github.com/devtron-labs/devtron/pkg/sql"
+	testReport2 "github.com/devtron-labs/devtron/pkg/testReport"
 	"github.com/devtron-labs/devtron/pkg/ucid"
 	util3 "github.com/devtron-labs/devtron/pkg/util"
 	"github.com/devtron-labs/devtron/pkg/variables"
@@ -223,6 +238,15 @@ func InitializeApp() (*App, error) {
 		userResource.UserResourceWireSet,
 		policyGovernance.PolicyGovernanceWireSet,
 		resourceScan.ScanningResultWireSet,
+		imageSigning.ImageSigningWireSet,
+		artifactPromotion.ArtifactPromotionWireSet,
+		pluginCatalog.PluginCatalogWireSet,
+		testReport.TestReportWireSet,
+		testReport2.TestReportWireSet,
+		appAsCode2.AppAsCodeWireSet,
+		appAsCode.AppAsCodeWireSet,
+		pullRequest.GitOpsPullRequestWireSet,
+		monorepo.GitOpsMonorepoWireSet,
 		executor.ExecutorWireSet,
 		fluxcd.DeploymentWireSet,
 		// -------wireset end ----------
@@ -338,6 +362,11 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(pipeline5.DevtronAppAutoCompleteRouter), new(*pipeline5.DevtronAppAutoCompleteRouterImpl)),
 		workflow2.NewAppWorkflowRouterImpl,
 		wire.Bind(new(workflow2.AppWorkflowRouter), new(*workflow2.AppWorkflowRouterImpl)),
+		appTemplate2.NewAppTemplateRouterImpl,
+		wire.Bind(new(appTemplate2.AppTemplateRouter), new(*appTemplate2.AppTemplateRouterImpl)),
+		appTemplate3.NewAppTemplateRestHandlerImpl,
+		wire.Bind(new(appTemplate3.AppTemplateRestHandler), new(*appTemplate3.AppTemplateRestHandlerImpl)),
+		appTemplate.AppTemplateWireSet,
 
 		pipeline.NewCiCdPipelineOrchestrator,
 		wire.Bind(new(pipeline.CiCdPipelineOrchestrator), new(*pipeline.CiCdPipelineOrchestratorImpl)),
@@ -374,6 +403,8 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(read2.ChartReadService), new(*read2.ChartReadServiceImpl)),
 		service.NewBulkUpdateServiceImpl,
 		wire.Bind(new(service.BulkUpdateService), new(*service.BulkUpdateServiceImpl)),
+		bulkEdit.BulkEditWireSet,
+		chartRefMigration.ChartRefMigrationWireSet,
 
 		repository.NewImageTagRepository,
 		wire.Bind(new(repository.ImageTagRepository), new(*repository.ImageTagRepositoryImpl)),
@@ -641,6 +672,11 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(chartGroup2.ChartGroupRouter), new(*chartGroup2.ChartGroupRouterImpl)),
 		repository4.NewChartGroupDeploymentRepositoryImpl,
 		wire.Bind(new(repository4.ChartGroupDeploymentRepository), new(*repository4.ChartGroupDeploymentRepositoryImpl)),
+		repository4.NewChartGroupInstallRepositoryImpl,
+		wire.Bind(new(repository4.ChartGroupInstallRepository), new(*repository4.ChartGroupInstallRepositoryImpl)),
+		chartGroup.GetChartGroupInstallConfig,
+		chartGroup.NewChartGroupInstallServiceImpl,
+		wire.Bind(new(chartGroup.ChartGroupInstallService), new(*chartGroup.ChartGroupInstallServiceImpl)),
 		repository9.NewClusterInstalledAppsRepositoryImpl,
 		wire.Bind(new(repository9.ClusterInstalledAppsRepository), new(*repository9.ClusterInstalledAppsRepositoryImpl)),
 
@@ -714,11 +750,16 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(router.BulkUpdateRouter), new(*router.BulkUpdateRouterImpl)),
 		restHandler.NewBulkUpdateRestHandlerImpl,
 		wire.Bind(new(restHandler.BulkUpdateRestHandler), new(*restHandler.BulkUpdateRestHandlerImpl)),
+		restHandler.NewBulkEditRestHandlerImpl,
+		wire.Bind(new(restHandler.BulkEditRestHandler), new(*restHandler.BulkEditRestHandlerImpl)),
+		restHandler.NewChartRefMigrationRestHandlerImpl,
+		wire.Bind(new(restHandler.ChartRefMigrationRestHandler), new(*restHandler.ChartRefMigrationRestHandlerImpl)),
 
 		router.NewCoreAppRouterImpl,
 		wire.Bind(new(router.CoreAppRouter), new(*router.CoreAppRouterImpl)),
 		restHandler.NewCoreAppRestHandlerImpl,
 		wire.Bind(new(restHandler.CoreAppRestHandler), new(*restHandler.CoreAppRestHandlerImpl)),
+		wire.Bind(new(appAsCode.AppDefinitionManager), new(*restHandler.CoreAppRestHandlerImpl)),
 
 		// Webhook
 		restHandler.NewGitHostRestHandlerImpl,
@@ -810,6 +851,7 @@ func InitializeApp() (*App, error) {
 
 		// plugin starts
 		plugin.WireSet,
+		catalog.PluginCatalogWireSet,
 		restHandler.NewGlobalPluginRestHandler,
 		wire.Bind(new(restHandler.GlobalPluginRestHandler), new(*restHandler.GlobalPluginRestHandlerImpl)),
 
@@ -0,0 +1,276 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appAsCode
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/appAsCode"
+	"github.com/devtron-labs/devtron/pkg/appAsCode/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+)
+
+type AppAsCodeRestHandler interface {
+	SaveSource(w http.ResponseWriter, r *http.Request)
+	GetAllSources(w http.ResponseWriter, r *http.Request)
+	GetSource(w http.ResponseWriter, r *http.Request)
+	DeleteSource(w http.ResponseWriter, r *http.Request)
+	SyncSource(w http.ResponseWriter, r *http.Request)
+	GetSourceApps(w http.ResponseWriter, r *http.Request)
+	GetAppStatus(w http.ResponseWriter, r *http.Request)
+	ExportApp(w http.ResponseWriter, r *http.Request)
+}
+
+type AppAsCodeRestHandlerImpl struct {
+	logger           *zap.SugaredLogger
+	userService      user.UserService
+	appAsCodeService appAsCode.AppAsCodeService
+	enforcer         casbin.Enforcer
+	enforcerUtil     rbac.EnforcerUtil
+	validator        *validator.Validate
+}
+
+func NewAppAsCodeRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	appAsCodeService appAsCode.AppAsCodeService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate) *AppAsCodeRestHandlerImpl {
+	return &AppAsCodeRestHandlerImpl{
+		logger:           logger,
+		userService:      userService,
+		appAsCodeService: appAsCodeService,
+		enforcer:         enforcer,
+		enforcerUtil:     enforcerUtil,
+		validator:        validator,
+	}
+}
+
+// checkAppAccess writes the error response and returns false if the caller can not perform the action on the app
+func (handler *AppAsCodeRestHandlerImpl) checkAppAccess(w http.ResponseWriter, r *http.Request, appId int, action string) bool {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return false
+	}
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, action, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (handler *AppAsCodeRestHandlerImpl) SaveSource(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	var request bean.AppAsCodeSourceDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveSource", "err", err, "payload", r.Body)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveSource", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var resp *bean.AppAsCodeSourceDto
+	if request.Id > 0 {
+		resp, err = handler.appAsCodeService.UpdateSource(&request, userId)
+	} else {
+		resp, err = handler.appAsCodeService.CreateSource(&request, userId)
+	}
+	if err != nil {
+		handler.logger.Errorw("service err, SaveSource", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *AppAsCodeRestHandlerImpl) GetAllSources(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	resp, err := handler.appAsCodeService.GetAllSources()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllSources", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *AppAsCodeRestHandlerImpl) GetSource(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	resp, err := handler.appAsCodeService.GetSource(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetSource", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *AppAsCodeRestHandlerImpl) DeleteSource(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionDelete, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	err = handler.appAsCodeService.DeleteSource(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteSource", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *AppAsCodeRestHandlerImpl) SyncSource(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	resp, err := handler.appAsCodeService.SyncSource(r.Context(), id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, SyncSource", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *AppAsCodeRestHandlerImpl) GetSourceApps(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	resp, err := handler.appAsCodeService.GetSourceApps(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetSourceApps", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *AppAsCodeRestHandlerImpl) GetAppStatus(w http.ResponseWriter, r *http.Request) {
+	appId, err := common.ExtractIntPathParam(w, r, "appId")
+	if err != nil {
+		return
+	}
+	if ok := handler.checkAppAccess(w, r, appId, casbin.ActionGet); !ok {
+		return
+	}
+	resp, err := handler.appAsCodeService.GetAppStatus(appId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAppStatus", "appId", appId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// ExportApp needs the same access as reading the whole app, update on the app and on every environment it overrides
+func (handler *AppAsCodeRestHandlerImpl) ExportApp(w http.ResponseWriter, r *http.Request) {
+	appId, err := common.ExtractIntPathParam(w, r, "appId")
+	if err != nil {
+		return
+	}
+	if ok := handler.checkAppAccess(w, r, appId, casbin.ActionUpdate); !ok {
+		return
+	}
+	token := r.Header.Get("token")
+	enforce := func(resource, action, object string) bool {
+		return handler.enforcer.Enforce(token, resource, action, object)
+	}
+	resp, err := handler.appAsCodeService.ExportApp(r.Context(), appId, enforce)
+	if err != nil {
+		handler.logger.Errorw("service err, ExportApp", "appId", appId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appAsCode
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type AppAsCodeRouter interface {
+	InitAppAsCodeRouter(router *mux.Router)
+}
+
+type AppAsCodeRouterImpl struct {
+	appAsCodeRestHandler AppAsCodeRestHandler
+}
+
+func NewAppAsCodeRouterImpl(appAsCodeRestHandler AppAsCodeRestHandler) *AppAsCodeRouterImpl {
+	return &AppAsCodeRouterImpl{appAsCodeRestHandler: appAsCodeRestHandler}
+}
+
+func (router *AppAsCodeRouterImpl) InitAppAsCodeRouter(appAsCodeRouter *mux.Router) {
+	appAsCodeRouter.Path("/source").HandlerFunc(router.appAsCodeRestHandler.SaveSource).Methods("POST", "PUT")
+	appAsCodeRouter.Path("/source").HandlerFunc(router.appAsCodeRestHandler.GetAllSources).Methods("GET")
+	appAsCodeRouter.Path("/source/{id}").HandlerFunc(router.appAsCodeRestHandler.GetSource).Methods("GET")
+	appAsCodeRouter.Path("/source/{id}").HandlerFunc(router.appAsCodeRestHandler.DeleteSource).Methods("DELETE")
+	appAsCodeRouter.Path("/source/{id}/sync").HandlerFunc(router.appAsCodeRestHandler.SyncSource).Methods("POST")
+	appAsCodeRouter.Path("/source/{id}/app").HandlerFunc(router.appAsCodeRestHandler.GetSourceApps).Methods("GET")
+	appAsCodeRouter.Path("/app/{appId}").HandlerFunc(router.appAsCodeRestHandler.GetAppStatus).Methods("GET")
+	appAsCodeRouter.Path("/app/{appId}/export").HandlerFunc(router.appAsCodeRestHandler.ExportApp).Methods("GET")
+}
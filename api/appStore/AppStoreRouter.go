@@ -107,4 +107,6 @@ func (router AppStoreRouterImpl) Init(configRouter *mux.Router) {
 		HandlerFunc(router.deployRestHandler.MigrateDeploymentTypeForChartStore).Methods("POST")
 	configRouter.Path("/installed-app/trigger").
 		HandlerFunc(router.deployRestHandler.TriggerChartStoreAppAfterMigration).Methods("POST")
+	configRouter.Path("/installed-app/{installedAppId}/adopt/gitops").
+		HandlerFunc(router.deployRestHandler.MigrateAdoptedReleaseToGitOps).Methods("POST")
 }
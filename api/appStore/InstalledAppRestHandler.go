@@ -70,6 +70,7 @@ type InstalledAppRestHandler interface {
 	FetchNotesForArgoInstalledApp(w http.ResponseWriter, r *http.Request)
 	MigrateDeploymentTypeForChartStore(w http.ResponseWriter, r *http.Request)
 	TriggerChartStoreAppAfterMigration(w http.ResponseWriter, r *http.Request)
+	MigrateAdoptedReleaseToGitOps(w http.ResponseWriter, r *http.Request)
 }
 
 type InstalledAppRestHandlerImpl struct {
@@ -400,6 +401,7 @@ func (handler *InstalledAppRestHandlerImpl) DeployBulk(w http.ResponseWriter, r
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	} else {
+		authRes.InstallationId = res.InstallationId
 		res = authRes
 	}
 	common.WriteJsonResp(w, err, res, http.StatusOK)
@@ -971,3 +973,33 @@ func (handler *InstalledAppRestHandlerImpl) TriggerChartStoreAppAfterMigration(w
 	common.WriteJsonResp(w, nil, resp, http.StatusOK)
 	return
 }
+
+func (handler *InstalledAppRestHandlerImpl) MigrateAdoptedReleaseToGitOps(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	installedAppId, err := strconv.Atoi(vars["installedAppId"])
+	if err != nil {
+		handler.Logger.Errorw("request err, MigrateAdoptedReleaseToGitOps", "installedAppId", vars["installedAppId"], "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("token")
+	// same as migrate api, the helm release is removed from helm's storage before argo-cd takes over its resources
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceHelmApp, casbin.ActionDelete, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	resp, err := handler.installedAppDeploymentTypeChangeService.MigrateAdoptedReleaseToArgoCd(r.Context(), installedAppId, userId)
+	if err != nil {
+		handler.Logger.Errorw("service err, MigrateAdoptedReleaseToGitOps", "installedAppId", installedAppId, "err", err)
+		common.WriteJsonResp(w, err, resp, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
@@ -34,22 +34,25 @@ import (
 const CHART_GROUP_DELETE_SUCCESS_RESP = "Chart group deleted successfully."
 
 type ChartGroupRestHandlerImpl struct {
-	ChartGroupService chartGroup.ChartGroupService
-	Logger            *zap.SugaredLogger
-	userAuthService   user.UserService
-	enforcer          casbin.Enforcer
-	validator         *validator.Validate
+	ChartGroupService        chartGroup.ChartGroupService
+	chartGroupInstallService chartGroup.ChartGroupInstallService
+	Logger                   *zap.SugaredLogger
+	userAuthService          user.UserService
+	enforcer                 casbin.Enforcer
+	validator                *validator.Validate
 }
 
 func NewChartGroupRestHandlerImpl(ChartGroupService chartGroup.ChartGroupService,
 	Logger *zap.SugaredLogger, userAuthService user.UserService,
-	enforcer casbin.Enforcer, validator *validator.Validate) *ChartGroupRestHandlerImpl {
+	enforcer casbin.Enforcer, validator *validator.Validate,
+	chartGroupInstallService chartGroup.ChartGroupInstallService) *ChartGroupRestHandlerImpl {
 	return &ChartGroupRestHandlerImpl{
-		ChartGroupService: ChartGroupService,
-		Logger:            Logger,
-		userAuthService:   userAuthService,
-		validator:         validator,
-		enforcer:          enforcer,
+		ChartGroupService:        ChartGroupService,
+		chartGroupInstallService: chartGroupInstallService,
+		Logger:                   Logger,
+		userAuthService:          userAuthService,
+		validator:                validator,
+		enforcer:                 enforcer,
 	}
 }
 
@@ -62,6 +65,8 @@ type ChartGroupRestHandler interface {
 	GetChartGroupInstallationDetail(w http.ResponseWriter, r *http.Request)
 	GetChartGroupListMin(w http.ResponseWriter, r *http.Request)
 	DeleteChartGroup(w http.ResponseWriter, r *http.Request)
+	GetChartGroupInstallation(w http.ResponseWriter, r *http.Request)
+	GetChartGroupInstallations(w http.ResponseWriter, r *http.Request)
 }
 
 func (impl *ChartGroupRestHandlerImpl) CreateChartGroup(w http.ResponseWriter, r *http.Request) {
@@ -357,3 +362,67 @@ func (impl *ChartGroupRestHandlerImpl) DeleteChartGroup(w http.ResponseWriter, r
 	}
 	common.WriteJsonResp(w, err, CHART_GROUP_DELETE_SUCCESS_RESP, http.StatusOK)
 }
+
+func (impl *ChartGroupRestHandlerImpl) GetChartGroupInstallation(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	installationId, err := strconv.Atoi(vars["installationId"])
+	if err != nil {
+		impl.Logger.Errorw("request err, GetChartGroupInstallation", "err", err, "installationId", installationId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	//RBAC block starts from here
+	token := r.Header.Get("token")
+	rbacObject := ""
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceChartGroup, casbin.ActionGet, rbacObject); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//RBAC block ends here
+
+	res, err := impl.chartGroupInstallService.GetInstallation(installationId)
+	if err != nil {
+		impl.Logger.Errorw("service err, GetChartGroupInstallation", "err", err, "installationId", installationId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (impl *ChartGroupRestHandlerImpl) GetChartGroupInstallations(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	chartGroupId, err := strconv.Atoi(vars["chartGroupId"])
+	if err != nil {
+		impl.Logger.Errorw("request err, GetChartGroupInstallations", "err", err, "chartGroupId", chartGroupId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	//RBAC block starts from here
+	token := r.Header.Get("token")
+	rbacObject := ""
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceChartGroup, casbin.ActionGet, rbacObject); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//RBAC block ends here
+
+	res, err := impl.chartGroupInstallService.GetInstallations(chartGroupId)
+	if err != nil {
+		impl.Logger.Errorw("service err, GetChartGroupInstallations", "err", err, "chartGroupId", chartGroupId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
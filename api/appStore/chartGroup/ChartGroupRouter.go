@@ -45,6 +45,10 @@ func (impl *ChartGroupRouterImpl) InitChartGroupRouter(chartGroupRouter *mux.Rou
 		HandlerFunc(impl.ChartGroupRestHandler.GetChartGroupWithChartMetaData).Methods("GET")
 	chartGroupRouter.Path("/installation-detail/{chartGroupId}").
 		HandlerFunc(impl.ChartGroupRestHandler.GetChartGroupInstallationDetail).Methods("GET")
+	chartGroupRouter.Path("/installation/{installationId}").
+		HandlerFunc(impl.ChartGroupRestHandler.GetChartGroupInstallation).Methods("GET")
+	chartGroupRouter.Path("/installations/{chartGroupId}").
+		HandlerFunc(impl.ChartGroupRestHandler.GetChartGroupInstallations).Methods("GET")
 
 	chartGroupRouter.Path("/list/min").
 		HandlerFunc(impl.ChartGroupRestHandler.GetChartGroupListMin).Methods("GET")
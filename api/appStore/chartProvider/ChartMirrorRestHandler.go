@@ -0,0 +1,255 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chartProvider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/appStore/chartMirror"
+	"github.com/devtron-labs/devtron/pkg/appStore/chartMirror/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type ChartMirrorRestHandler interface {
+	GetChartMirrors(w http.ResponseWriter, r *http.Request)
+	GetChartMirror(w http.ResponseWriter, r *http.Request)
+	CreateChartMirror(w http.ResponseWriter, r *http.Request)
+	UpdateChartMirror(w http.ResponseWriter, r *http.Request)
+	SyncChartMirror(w http.ResponseWriter, r *http.Request)
+	ExportChartMirrors(w http.ResponseWriter, r *http.Request)
+	ImportChartMirrorBundle(w http.ResponseWriter, r *http.Request)
+}
+
+type ChartMirrorRestHandlerImpl struct {
+	logger             *zap.SugaredLogger
+	userAuthService    user.UserService
+	validator          *validator.Validate
+	chartMirrorService chartMirror.ChartMirrorService
+	enforcer           casbin.Enforcer
+}
+
+func NewChartMirrorRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService, validator *validator.Validate,
+	chartMirrorService chartMirror.ChartMirrorService, enforcer casbin.Enforcer) *ChartMirrorRestHandlerImpl {
+	return &ChartMirrorRestHandlerImpl{
+		logger:             logger,
+		userAuthService:    userAuthService,
+		validator:          validator,
+		chartMirrorService: chartMirrorService,
+		enforcer:           enforcer,
+	}
+}
+
+func (handler *ChartMirrorRestHandlerImpl) GetChartMirrors(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authorise(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	res, err := handler.chartMirrorService.GetMirrors()
+	if err != nil {
+		handler.logger.Errorw("service err, GetChartMirrors", "err", err, "userId", userId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ChartMirrorRestHandlerImpl) GetChartMirror(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authorise(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid chart mirror id", http.StatusBadRequest)
+		return
+	}
+	res, err := handler.chartMirrorService.GetMirror(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetChartMirror", "err", err, "id", id, "userId", userId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ChartMirrorRestHandlerImpl) CreateChartMirror(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authorise(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	request, ok := handler.decodeMirrorRequest(w, r)
+	if !ok {
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, CreateChartMirror", "payload", request, "userId", userId)
+	res, err := handler.chartMirrorService.CreateMirror(request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateChartMirror", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ChartMirrorRestHandlerImpl) UpdateChartMirror(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authorise(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	request, ok := handler.decodeMirrorRequest(w, r)
+	if !ok {
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, UpdateChartMirror", "payload", request, "userId", userId)
+	res, err := handler.chartMirrorService.UpdateMirror(request)
+	if err != nil {
+		handler.logger.Errorw("service err, UpdateChartMirror", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ChartMirrorRestHandlerImpl) SyncChartMirror(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authorise(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid chart mirror id", http.StatusBadRequest)
+		return
+	}
+	handler.logger.Infow("request payload, SyncChartMirror", "id", id, "userId", userId)
+	res, err := handler.chartMirrorService.SyncMirror(r.Context(), id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, SyncChartMirror", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ChartMirrorRestHandlerImpl) ExportChartMirrors(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authorise(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	var mirrorIds []int
+	if ids := r.URL.Query().Get("ids"); len(ids) > 0 {
+		for _, id := range strings.Split(ids, ",") {
+			mirrorId, err := strconv.Atoi(strings.TrimSpace(id))
+			if err != nil {
+				common.WriteJsonResp(w, err, "invalid chart mirror ids", http.StatusBadRequest)
+				return
+			}
+			mirrorIds = append(mirrorIds, mirrorId)
+		}
+	}
+	handler.logger.Infow("request payload, ExportChartMirrors", "mirrorIds", mirrorIds, "userId", userId)
+	bundle := &bytes.Buffer{}
+	err := handler.chartMirrorService.ExportBundle(r.Context(), mirrorIds, bundle)
+	if err != nil {
+		handler.logger.Errorw("service err, ExportChartMirrors", "err", err, "mirrorIds", mirrorIds)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(common.CONTENT_DISPOSITION, "attachment; filename=chart-mirror-bundle.tar.gz")
+	w.Header().Set(common.CONTENT_TYPE, bean.BundleContentType)
+	w.Header().Set(common.CONTENT_LENGTH, strconv.Itoa(bundle.Len()))
+	if _, err = bundle.WriteTo(w); err != nil {
+		handler.logger.Errorw("error in writing chart mirror bundle", "err", err, "mirrorIds", mirrorIds)
+	}
+}
+
+func (handler *ChartMirrorRestHandlerImpl) ImportChartMirrorBundle(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.authorise(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	file, _, err := r.FormFile(bean.BundleFileFormName)
+	if err != nil {
+		handler.logger.Errorw("request err, ImportChartMirrorBundle", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	request := &bean.BundleImportRequest{
+		TargetRegistryId: r.FormValue("targetRegistryId"),
+		TargetRepository: r.FormValue("targetRepository"),
+		UserId:           userId,
+	}
+	if err = handler.validator.Struct(request); err != nil {
+		handler.logger.Errorw("validation err, ImportChartMirrorBundle", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	handler.logger.Infow("request payload, ImportChartMirrorBundle", "payload", request, "userId", userId)
+	res, err := handler.chartMirrorService.ImportBundle(r.Context(), file, request)
+	if err != nil {
+		handler.logger.Errorw("service err, ImportChartMirrorBundle", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ChartMirrorRestHandlerImpl) decodeMirrorRequest(w http.ResponseWriter, r *http.Request) (*bean.ChartMirrorDto, bool) {
+	request := &bean.ChartMirrorDto{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, decode chart mirror", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, chart mirror", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return request, true
+}
+
+// authorise checks that the user is logged in and is allowed the action on global resources, mirrors are managed by
+// super admins like chart providers
+func (handler *ChartMirrorRestHandlerImpl) authorise(w http.ResponseWriter, r *http.Request, action string) (int32, bool) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusUnauthorized)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, action, "*"); !ok {
+		handler.logger.Infow("user forbidden to manage chart mirrors", "userId", userId, "action", action)
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
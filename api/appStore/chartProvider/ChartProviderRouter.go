@@ -26,11 +26,14 @@ type ChartProviderRouter interface {
 
 type ChartProviderRouterImpl struct {
 	chartProviderRestHandler ChartProviderRestHandler
+	chartMirrorRestHandler   ChartMirrorRestHandler
 }
 
-func NewChartProviderRouterImpl(chartProviderRestHandler ChartProviderRestHandler) *ChartProviderRouterImpl {
+func NewChartProviderRouterImpl(chartProviderRestHandler ChartProviderRestHandler,
+	chartMirrorRestHandler ChartMirrorRestHandler) *ChartProviderRouterImpl {
 	return &ChartProviderRouterImpl{
 		chartProviderRestHandler: chartProviderRestHandler,
+		chartMirrorRestHandler:   chartMirrorRestHandler,
 	}
 }
 
@@ -41,4 +44,19 @@ func (router ChartProviderRouterImpl) Init(configRouter *mux.Router) {
 		HandlerFunc(router.chartProviderRestHandler.ToggleChartProvider).Methods("POST")
 	configRouter.Path("/sync-chart").
 		HandlerFunc(router.chartProviderRestHandler.SyncChartProvider).Methods("POST")
+
+	configRouter.Path("/mirror").
+		HandlerFunc(router.chartMirrorRestHandler.GetChartMirrors).Methods("GET")
+	configRouter.Path("/mirror").
+		HandlerFunc(router.chartMirrorRestHandler.CreateChartMirror).Methods("POST")
+	configRouter.Path("/mirror").
+		HandlerFunc(router.chartMirrorRestHandler.UpdateChartMirror).Methods("PUT")
+	configRouter.Path("/mirror/export").
+		HandlerFunc(router.chartMirrorRestHandler.ExportChartMirrors).Methods("GET")
+	configRouter.Path("/mirror/import").
+		HandlerFunc(router.chartMirrorRestHandler.ImportChartMirrorBundle).Methods("POST")
+	configRouter.Path("/mirror/{id:[0-9]+}").
+		HandlerFunc(router.chartMirrorRestHandler.GetChartMirror).Methods("GET")
+	configRouter.Path("/mirror/{id:[0-9]+}/sync").
+		HandlerFunc(router.chartMirrorRestHandler.SyncChartMirror).Methods("POST")
 }
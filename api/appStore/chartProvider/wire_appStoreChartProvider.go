@@ -17,6 +17,7 @@
 package chartProvider
 
 import (
+	"github.com/devtron-labs/devtron/pkg/appStore/chartMirror"
 	chartProviderService "github.com/devtron-labs/devtron/pkg/appStore/chartProvider"
 	"github.com/google/wire"
 )
@@ -26,5 +27,8 @@ var AppStoreChartProviderWireSet = wire.NewSet(
 	wire.Bind(new(chartProviderService.ChartProviderService), new(*chartProviderService.ChartProviderServiceImpl)),
 	NewChartProviderRestHandlerImpl,
 	wire.Bind(new(ChartProviderRestHandler), new(*ChartProviderRestHandlerImpl)),
+	chartMirror.ChartMirrorWireSet,
+	NewChartMirrorRestHandlerImpl,
+	wire.Bind(new(ChartMirrorRestHandler), new(*ChartMirrorRestHandlerImpl)),
 	NewChartProviderRouterImpl,
 	wire.Bind(new(ChartProviderRouter), new(*ChartProviderRouterImpl)))
@@ -26,11 +26,17 @@ type AppStoreDeploymentRouter interface {
 
 type AppStoreDeploymentRouterImpl struct {
 	appStoreDeploymentRestHandler AppStoreDeploymentRestHandler
+	upgradeAdvisorRestHandler     UpgradeAdvisorRestHandler
+	releaseAdoptionRestHandler    ReleaseAdoptionRestHandler
 }
 
-func NewAppStoreDeploymentRouterImpl(appStoreDeploymentRestHandler AppStoreDeploymentRestHandler) *AppStoreDeploymentRouterImpl {
+func NewAppStoreDeploymentRouterImpl(appStoreDeploymentRestHandler AppStoreDeploymentRestHandler,
+	upgradeAdvisorRestHandler UpgradeAdvisorRestHandler,
+	releaseAdoptionRestHandler ReleaseAdoptionRestHandler) *AppStoreDeploymentRouterImpl {
 	return &AppStoreDeploymentRouterImpl{
 		appStoreDeploymentRestHandler: appStoreDeploymentRestHandler,
+		upgradeAdvisorRestHandler:     upgradeAdvisorRestHandler,
+		releaseAdoptionRestHandler:    releaseAdoptionRestHandler,
 	}
 }
 
@@ -56,4 +62,25 @@ func (router AppStoreDeploymentRouterImpl) Init(configRouter *mux.Router) {
 	configRouter.Path("/application/update/project").
 		HandlerFunc(router.appStoreDeploymentRestHandler.UpdateProjectHelmApp).Methods("PUT")
 
+	configRouter.Path("/upgrade-advisor").
+		HandlerFunc(router.upgradeAdvisorRestHandler.GetUpgradeAdvisories).Methods("GET")
+
+	configRouter.Path("/upgrade-advisor/{installedAppId}").
+		HandlerFunc(router.upgradeAdvisorRestHandler.GetUpgradeAdvisory).Methods("GET")
+
+	configRouter.Path("/upgrade-advisor/{installedAppId}/refresh").
+		HandlerFunc(router.upgradeAdvisorRestHandler.RefreshUpgradeAdvisory).Methods("POST")
+
+	configRouter.Path("/upgrade-advisor/{installedAppId}/manifest-diff").
+		HandlerFunc(router.upgradeAdvisorRestHandler.GetUpgradeManifestDiff).Methods("POST")
+
+	configRouter.Path("/adopt/candidates").Queries("appId", "{appId}").
+		HandlerFunc(router.releaseAdoptionRestHandler.GetAdoptionCandidates).Methods("GET")
+
+	configRouter.Path("/adopt").
+		HandlerFunc(router.releaseAdoptionRestHandler.AdoptRelease).Methods("POST")
+
+	configRouter.Path("/adopt/{installedAppId}").
+		HandlerFunc(router.releaseAdoptionRestHandler.GetAdoption).Methods("GET")
+
 }
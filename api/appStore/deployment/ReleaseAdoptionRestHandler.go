@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appStoreDeployment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/helm-app/service"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/appStore/adoption"
+	"github.com/devtron-labs/devtron/pkg/appStore/adoption/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+)
+
+type ReleaseAdoptionRestHandler interface {
+	GetAdoptionCandidates(w http.ResponseWriter, r *http.Request)
+	AdoptRelease(w http.ResponseWriter, r *http.Request)
+	GetAdoption(w http.ResponseWriter, r *http.Request)
+}
+
+type ReleaseAdoptionRestHandlerImpl struct {
+	logger                 *zap.SugaredLogger
+	userAuthService        user.UserService
+	enforcer               casbin.Enforcer
+	enforcerUtilHelm       rbac.EnforcerUtilHelm
+	helmAppService         service.HelmAppService
+	releaseAdoptionService adoption.ReleaseAdoptionService
+	validator              *validator.Validate
+}
+
+func NewReleaseAdoptionRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService,
+	enforcer casbin.Enforcer, enforcerUtilHelm rbac.EnforcerUtilHelm, helmAppService service.HelmAppService,
+	releaseAdoptionService adoption.ReleaseAdoptionService, validator *validator.Validate) *ReleaseAdoptionRestHandlerImpl {
+	return &ReleaseAdoptionRestHandlerImpl{
+		logger:                 logger,
+		userAuthService:        userAuthService,
+		enforcer:               enforcer,
+		enforcerUtilHelm:       enforcerUtilHelm,
+		helmAppService:         helmAppService,
+		releaseAdoptionService: releaseAdoptionService,
+		validator:              validator,
+	}
+}
+
+// isAuthorized checks access on the helm app of the release, adopted releases keep being authorised as helm apps of
+// their cluster, namespace and release name
+func (handler *ReleaseAdoptionRestHandlerImpl) isAuthorized(token, action string, clusterId int, namespace, releaseName string) bool {
+	rbacObject, rbacObject2 := handler.enforcerUtilHelm.GetHelmObjectByClusterIdNamespaceAndAppName(clusterId, namespace, releaseName)
+	return handler.enforcer.Enforce(token, casbin.ResourceHelmApp, action, rbacObject) || handler.enforcer.Enforce(token, casbin.ResourceHelmApp, action, rbacObject2)
+}
+
+func (handler *ReleaseAdoptionRestHandlerImpl) GetAdoptionCandidates(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appIdentifier, err := handler.helmAppService.DecodeAppId(r.URL.Query().Get("appId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.isAuthorized(r.Header.Get("token"), casbin.ActionGet, appIdentifier.ClusterId, appIdentifier.Namespace, appIdentifier.ReleaseName) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	candidates, err := handler.releaseAdoptionService.GetAdoptionCandidates(r.Context(), appIdentifier)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAdoptionCandidates", "appIdentifier", appIdentifier, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, candidates, http.StatusOK)
+}
+
+func (handler *ReleaseAdoptionRestHandlerImpl) AdoptRelease(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.AdoptReleaseRequest{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("request err, AdoptRelease", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, AdoptRelease", "payload", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	appIdentifier, err := handler.helmAppService.DecodeAppId(request.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.isAuthorized(r.Header.Get("token"), casbin.ActionUpdate, appIdentifier.ClusterId, appIdentifier.Namespace, appIdentifier.ReleaseName) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := handler.releaseAdoptionService.AdoptRelease(r.Context(), appIdentifier, request)
+	if err != nil {
+		handler.logger.Errorw("service err, AdoptRelease", "payload", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *ReleaseAdoptionRestHandlerImpl) GetAdoption(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	installedAppId, err := common.ExtractIntPathParam(w, r, "installedAppId")
+	if err != nil {
+		return
+	}
+	res, err := handler.releaseAdoptionService.GetAdoption(installedAppId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAdoption", "installedAppId", installedAppId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.isAuthorized(r.Header.Get("token"), casbin.ActionGet, res.ClusterId, res.Namespace, res.ReleaseName) {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), nil, http.StatusForbidden)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
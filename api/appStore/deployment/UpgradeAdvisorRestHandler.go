@@ -0,0 +1,196 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appStoreDeployment
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	util2 "github.com/devtron-labs/devtron/util"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+type UpgradeAdvisorRestHandler interface {
+	GetUpgradeAdvisories(w http.ResponseWriter, r *http.Request)
+	GetUpgradeAdvisory(w http.ResponseWriter, r *http.Request)
+	RefreshUpgradeAdvisory(w http.ResponseWriter, r *http.Request)
+	GetUpgradeManifestDiff(w http.ResponseWriter, r *http.Request)
+}
+
+type UpgradeAdvisorRestHandlerImpl struct {
+	logger                *zap.SugaredLogger
+	userAuthService       user.UserService
+	enforcer              casbin.Enforcer
+	enforcerUtil          rbac.EnforcerUtil
+	enforcerUtilHelm      rbac.EnforcerUtilHelm
+	upgradeAdvisorService upgradeAdvisor.UpgradeAdvisorService
+}
+
+func NewUpgradeAdvisorRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService,
+	enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil, enforcerUtilHelm rbac.EnforcerUtilHelm,
+	upgradeAdvisorService upgradeAdvisor.UpgradeAdvisorService) *UpgradeAdvisorRestHandlerImpl {
+	return &UpgradeAdvisorRestHandlerImpl{
+		logger:                logger,
+		userAuthService:       userAuthService,
+		enforcer:              enforcer,
+		enforcerUtil:          enforcerUtil,
+		enforcerUtilHelm:      enforcerUtilHelm,
+		upgradeAdvisorService: upgradeAdvisorService,
+	}
+}
+
+// isAuthorized checks get access on the helm app the advisory is for
+func (handler *UpgradeAdvisorRestHandlerImpl) isAuthorized(token string, advisory *bean.UpgradeAdvisory) bool {
+	var rbacObject, rbacObject2 string
+	if util2.IsHelmApp(advisory.AppOfferingMode) {
+		rbacObject, rbacObject2 = handler.enforcerUtilHelm.GetHelmObjectByClusterIdNamespaceAndAppName(advisory.ClusterId, advisory.Namespace, advisory.AppName)
+	} else {
+		rbacObject, rbacObject2 = handler.enforcerUtil.GetHelmObjectByAppNameAndEnvId(advisory.AppName, advisory.EnvironmentId)
+	}
+	if rbacObject2 == "" {
+		return handler.enforcer.Enforce(token, casbin.ResourceHelmApp, casbin.ActionGet, rbacObject)
+	}
+	return handler.enforcer.Enforce(token, casbin.ResourceHelmApp, casbin.ActionGet, rbacObject) || handler.enforcer.Enforce(token, casbin.ResourceHelmApp, casbin.ActionGet, rbacObject2)
+}
+
+func (handler *UpgradeAdvisorRestHandlerImpl) GetUpgradeAdvisories(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	filter := &bean.AdvisoryFilter{}
+	if upgradeTypes := r.URL.Query().Get("upgradeType"); len(upgradeTypes) > 0 {
+		for _, upgradeType := range strings.Split(upgradeTypes, ",") {
+			if !bean.UpgradeType(upgradeType).IsValid() {
+				common.WriteJsonResp(w, fmt.Errorf("invalid upgradeType %q", upgradeType), nil, http.StatusBadRequest)
+				return
+			}
+			filter.UpgradeTypes = append(filter.UpgradeTypes, bean.UpgradeType(upgradeType))
+		}
+	}
+	filter.EnvIds, err = common.ExtractIntArrayFromQueryParam(r, "envIds")
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	filter.AppStoreId, err = common.ExtractIntQueryParam(w, r, "appStoreId", 0)
+	if err != nil {
+		return
+	}
+	advisories, err := handler.upgradeAdvisorService.GetAdvisories(filter)
+	if err != nil {
+		handler.logger.Errorw("service err, GetUpgradeAdvisories", "filter", filter, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	authorizedAdvisories := make([]*bean.UpgradeAdvisory, 0, len(advisories))
+	for _, advisory := range advisories {
+		if handler.isAuthorized(token, advisory) {
+			authorizedAdvisories = append(authorizedAdvisories, advisory)
+		}
+	}
+	common.WriteJsonResp(w, nil, authorizedAdvisories, http.StatusOK)
+}
+
+// getAuthorizedAdvisory returns the advisory of the installed app in the path, nil when the response has been written
+func (handler *UpgradeAdvisorRestHandlerImpl) getAuthorizedAdvisory(w http.ResponseWriter, r *http.Request, userId int32) *bean.UpgradeAdvisory {
+	installedAppId, err := common.ExtractIntPathParam(w, r, "installedAppId")
+	if err != nil {
+		return nil
+	}
+	advisory, err := handler.upgradeAdvisorService.GetAdvisory(installedAppId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAdvisory", "installedAppId", installedAppId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil
+	}
+	if !handler.isAuthorized(r.Header.Get("token"), advisory) {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), nil, http.StatusForbidden)
+		return nil
+	}
+	return advisory
+}
+
+func (handler *UpgradeAdvisorRestHandlerImpl) GetUpgradeAdvisory(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	advisory := handler.getAuthorizedAdvisory(w, r, userId)
+	if advisory == nil {
+		return
+	}
+	common.WriteJsonResp(w, nil, advisory, http.StatusOK)
+}
+
+func (handler *UpgradeAdvisorRestHandlerImpl) RefreshUpgradeAdvisory(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	advisory := handler.getAuthorizedAdvisory(w, r, userId)
+	if advisory == nil {
+		return
+	}
+	err = handler.upgradeAdvisorService.RefreshAdvisory(advisory.InstalledAppId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, RefreshUpgradeAdvisory", "installedAppId", advisory.InstalledAppId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	advisory, err = handler.upgradeAdvisorService.GetAdvisory(advisory.InstalledAppId, userId)
+	common.WriteJsonResp(w, err, advisory, http.StatusOK)
+}
+
+func (handler *UpgradeAdvisorRestHandlerImpl) GetUpgradeManifestDiff(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.ManifestDiffRequest{}
+	if r.ContentLength != 0 {
+		if err = json.NewDecoder(r.Body).Decode(request); err != nil {
+			handler.logger.Errorw("request err, GetUpgradeManifestDiff", "err", err)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	advisory := handler.getAuthorizedAdvisory(w, r, userId)
+	if advisory == nil {
+		return
+	}
+	request.InstalledAppId = advisory.InstalledAppId
+	manifestDiff, err := handler.upgradeAdvisorService.GetManifestDiff(r.Context(), request)
+	if err != nil {
+		handler.logger.Errorw("service err, GetUpgradeManifestDiff", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, manifestDiff, http.StatusOK)
+}
@@ -19,6 +19,7 @@ package appStoreDeployment
 import (
 	"github.com/devtron-labs/devtron/client/argocdServer"
 	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appStore/adoption"
 	installedAppReader "github.com/devtron-labs/devtron/pkg/appStore/installedApp/read"
 	repository3 "github.com/devtron-labs/devtron/pkg/appStore/installedApp/repository"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service"
@@ -28,6 +29,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode/deploymentTypeChange"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode/resource"
 	appStoreDeploymentCommon "github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/common"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor"
 	"github.com/google/wire"
 )
 
@@ -45,6 +47,10 @@ var EAModeWireSet = wire.NewSet(
 	wire.Bind(new(service.AppStoreDeploymentDBService), new(*service.AppStoreDeploymentDBServiceImpl)),
 	NewAppStoreDeploymentRestHandlerImpl,
 	wire.Bind(new(AppStoreDeploymentRestHandler), new(*AppStoreDeploymentRestHandlerImpl)),
+	NewUpgradeAdvisorRestHandlerImpl,
+	wire.Bind(new(UpgradeAdvisorRestHandler), new(*UpgradeAdvisorRestHandlerImpl)),
+	NewReleaseAdoptionRestHandlerImpl,
+	wire.Bind(new(ReleaseAdoptionRestHandler), new(*ReleaseAdoptionRestHandlerImpl)),
 	NewAppStoreDeploymentRouterImpl,
 	wire.Bind(new(AppStoreDeploymentRouter), new(*AppStoreDeploymentRouterImpl)),
 	repository3.NewInstalledAppVersionHistoryRepositoryImpl,
@@ -60,6 +66,10 @@ var EAModeWireSet = wire.NewSet(
 	wire.Bind(new(EAMode.InstalledAppDBService), new(*EAMode.InstalledAppDBServiceImpl)),
 
 	installedAppReader.EAWireSet,
+
+	upgradeAdvisor.UpgradeAdvisorWireSet,
+
+	adoption.ReleaseAdoptionWireSet,
 )
 
 var FullModeWireSet = wire.NewSet(
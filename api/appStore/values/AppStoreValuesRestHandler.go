@@ -24,6 +24,8 @@ import (
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
 	"github.com/devtron-labs/devtron/pkg/appStore/values/service"
+	"github.com/devtron-labs/devtron/pkg/appStore/valuesSchema"
+	valuesSchemaBean "github.com/devtron-labs/devtron/pkg/appStore/valuesSchema/bean"
 	"github.com/devtron-labs/devtron/pkg/auth/user"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
@@ -38,20 +40,26 @@ type AppStoreValuesRestHandler interface {
 	FindValuesByAppStoreIdAndReferenceType(w http.ResponseWriter, r *http.Request)
 	FetchTemplateValuesByAppStoreId(w http.ResponseWriter, r *http.Request)
 	GetSelectedChartMetadata(w http.ResponseWriter, r *http.Request)
+
+	GetValuesSchema(w http.ResponseWriter, r *http.Request)
+	ValidateValues(w http.ResponseWriter, r *http.Request)
 }
 
 type AppStoreValuesRestHandlerImpl struct {
 	Logger                *zap.SugaredLogger
 	userAuthService       user.UserService
 	appStoreValuesService service.AppStoreValuesService
+	valuesSchemaService   valuesSchema.ValuesSchemaService
 }
 
 func NewAppStoreValuesRestHandlerImpl(Logger *zap.SugaredLogger, userAuthService user.UserService,
-	appStoreValuesService service.AppStoreValuesService) *AppStoreValuesRestHandlerImpl {
+	appStoreValuesService service.AppStoreValuesService,
+	valuesSchemaService valuesSchema.ValuesSchemaService) *AppStoreValuesRestHandlerImpl {
 	return &AppStoreValuesRestHandlerImpl{
 		Logger:                Logger,
 		userAuthService:       userAuthService,
 		appStoreValuesService: appStoreValuesService,
+		valuesSchemaService:   valuesSchemaService,
 	}
 }
 
@@ -241,3 +249,48 @@ func (handler AppStoreValuesRestHandlerImpl) GetSelectedChartMetadata(w http.Res
 	}
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
+
+func (handler AppStoreValuesRestHandlerImpl) GetValuesSchema(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appStoreApplicationVersionId, err := common.ExtractIntPathParam(w, r, "appStoreApplicationVersionId")
+	if err != nil {
+		return
+	}
+	res, err := handler.valuesSchemaService.GetValuesSchema(appStoreApplicationVersionId)
+	if err != nil {
+		handler.Logger.Errorw("service err, GetValuesSchema", "err", err, "appStoreApplicationVersionId", appStoreApplicationVersionId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
+func (handler AppStoreValuesRestHandlerImpl) ValidateValues(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appStoreApplicationVersionId, err := common.ExtractIntPathParam(w, r, "appStoreApplicationVersionId")
+	if err != nil {
+		return
+	}
+	var request valuesSchemaBean.ValidateValuesRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.Logger.Errorw("request err, ValidateValues", "err", err, "appStoreApplicationVersionId", appStoreApplicationVersionId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.valuesSchemaService.ValidateValues(appStoreApplicationVersionId, request.ValuesYaml)
+	if err != nil {
+		handler.Logger.Errorw("service err, ValidateValues", "err", err, "appStoreApplicationVersionId", appStoreApplicationVersionId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
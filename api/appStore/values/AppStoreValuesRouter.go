@@ -56,4 +56,9 @@ func (router AppStoreValuesRouterImpl) Init(configRouter *mux.Router) {
 	configRouter.Path("/chart/selected/metadata").
 		HandlerFunc(router.appStoreValuesRestHandler.GetSelectedChartMetadata).Methods("POST")
 
+	configRouter.Path("/schema/{appStoreApplicationVersionId}").
+		HandlerFunc(router.appStoreValuesRestHandler.GetValuesSchema).Methods("GET")
+	configRouter.Path("/schema/{appStoreApplicationVersionId}/validate").
+		HandlerFunc(router.appStoreValuesRestHandler.ValidateValues).Methods("POST")
+
 }
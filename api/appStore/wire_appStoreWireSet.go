@@ -18,6 +18,7 @@ package appStore
 
 import (
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service"
+	"github.com/devtron-labs/devtron/pkg/appStore/valuesSchema"
 	"github.com/google/wire"
 )
 
@@ -28,6 +29,8 @@ var EAModeWireSet = wire.NewSet(
 
 	service.NewAppAppStoreValidatorImpl,
 	wire.Bind(new(service.AppStoreValidator), new(*service.AppStoreValidatorImpl)),
+
+	valuesSchema.ValuesSchemaWireSet,
 )
 
 var FullModeWireSet = wire.NewSet(
@@ -0,0 +1,273 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package artifactPromotion
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/artifactPromotion"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/artifactPromotion/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+)
+
+type ArtifactPromotionRestHandler interface {
+	SavePolicy(w http.ResponseWriter, r *http.Request)
+	GetAllPolicies(w http.ResponseWriter, r *http.Request)
+	DeletePolicy(w http.ResponseWriter, r *http.Request)
+	PromoteArtifact(w http.ResponseWriter, r *http.Request)
+	ApprovePromotion(w http.ResponseWriter, r *http.Request)
+	CancelPromotion(w http.ResponseWriter, r *http.Request)
+	GetPromotionHistory(w http.ResponseWriter, r *http.Request)
+}
+
+type ArtifactPromotionRestHandlerImpl struct {
+	logger                   *zap.SugaredLogger
+	userService              user.UserService
+	artifactPromotionService artifactPromotion.ArtifactPromotionService
+	enforcer                 casbin.Enforcer
+	enforcerUtil             rbac.EnforcerUtil
+	validator                *validator.Validate
+}
+
+func NewArtifactPromotionRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	artifactPromotionService artifactPromotion.ArtifactPromotionService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate) *ArtifactPromotionRestHandlerImpl {
+	return &ArtifactPromotionRestHandlerImpl{
+		logger:                   logger,
+		userService:              userService,
+		artifactPromotionService: artifactPromotionService,
+		enforcer:                 enforcer,
+		enforcerUtil:             enforcerUtil,
+		validator:                validator,
+	}
+}
+
+func (handler *ArtifactPromotionRestHandlerImpl) SavePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	var request bean.PromotionPolicyDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SavePolicy", "err", err, "payload", r.Body)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SavePolicy", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.artifactPromotionService.SavePolicy(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SavePolicy", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ArtifactPromotionRestHandlerImpl) GetAllPolicies(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	resp, err := handler.artifactPromotionService.GetAllPolicies()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllPolicies", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ArtifactPromotionRestHandlerImpl) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionDelete, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	err = handler.artifactPromotionService.DeletePolicy(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeletePolicy", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *ArtifactPromotionRestHandlerImpl) PromoteArtifact(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.PromoteArtifactRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, PromoteArtifact", "err", err, "payload", r.Body)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, PromoteArtifact", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// RBAC: promotion is a deployment decision, trigger access is required on every target environment
+	token := r.Header.Get("token")
+	for _, targetPipelineId := range request.TargetPipelineIds {
+		object := handler.enforcerUtil.GetAppRBACByAppIdAndPipelineId(request.AppId, targetPipelineId)
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionTrigger, object); !ok {
+			common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return
+		}
+	}
+	// RBAC
+	request.UserId = userId
+	resp, err := handler.artifactPromotionService.PromoteArtifact(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, PromoteArtifact", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// checkTriggerAccessOnRequest writes the error response and returns false if the caller cannot trigger on the promotion target
+func (handler *ArtifactPromotionRestHandlerImpl) checkTriggerAccessOnRequest(w http.ResponseWriter, r *http.Request) (userId int32, promotionRequestId int, ok bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+	promotionRequestId, err = common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return 0, 0, false
+	}
+	_, targetPipeline, err := handler.artifactPromotionService.GetPipelinesForRequest(promotionRequestId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPipelinesForRequest", "err", err, "promotionRequestId", promotionRequestId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return 0, 0, false
+	}
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACByAppIdAndPipelineId(targetPipeline.AppId, targetPipeline.Id)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionTrigger, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return 0, 0, false
+	}
+	return userId, promotionRequestId, true
+}
+
+func (handler *ArtifactPromotionRestHandlerImpl) ApprovePromotion(w http.ResponseWriter, r *http.Request) {
+	userId, promotionRequestId, ok := handler.checkTriggerAccessOnRequest(w, r)
+	if !ok {
+		return
+	}
+	resp, err := handler.artifactPromotionService.ApprovePromotion(promotionRequestId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, ApprovePromotion", "err", err, "promotionRequestId", promotionRequestId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ArtifactPromotionRestHandlerImpl) CancelPromotion(w http.ResponseWriter, r *http.Request) {
+	userId, promotionRequestId, ok := handler.checkTriggerAccessOnRequest(w, r)
+	if !ok {
+		return
+	}
+	err := handler.artifactPromotionService.CancelPromotion(promotionRequestId, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, CancelPromotion", "err", err, "promotionRequestId", promotionRequestId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, promotionRequestId, http.StatusOK)
+}
+
+func (handler *ArtifactPromotionRestHandlerImpl) GetPromotionHistory(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := common.ExtractIntQueryParam(w, r, "appId", 0)
+	if err != nil {
+		return
+	}
+	artifactId, err := common.ExtractIntQueryParam(w, r, "artifactId", 0)
+	if err != nil {
+		return
+	}
+	pipelineId, err := common.ExtractIntQueryParam(w, r, "pipelineId", 0)
+	if err != nil {
+		return
+	}
+	// RBAC
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	// RBAC
+	filter := &bean.PromotionHistoryFilter{AppId: appId, ArtifactId: artifactId, TargetPipelineId: pipelineId}
+	resp, err := handler.artifactPromotionService.GetPromotionHistory(filter)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPromotionHistory", "err", err, "filter", filter)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
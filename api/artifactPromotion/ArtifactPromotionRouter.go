@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package artifactPromotion
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type ArtifactPromotionRouter interface {
+	InitArtifactPromotionRouter(router *mux.Router)
+}
+
+type ArtifactPromotionRouterImpl struct {
+	artifactPromotionRestHandler ArtifactPromotionRestHandler
+}
+
+func NewArtifactPromotionRouterImpl(artifactPromotionRestHandler ArtifactPromotionRestHandler) *ArtifactPromotionRouterImpl {
+	return &ArtifactPromotionRouterImpl{artifactPromotionRestHandler: artifactPromotionRestHandler}
+}
+
+func (router *ArtifactPromotionRouterImpl) InitArtifactPromotionRouter(promotionRouter *mux.Router) {
+	promotionRouter.Path("/policy").HandlerFunc(router.artifactPromotionRestHandler.SavePolicy).Methods("POST", "PUT")
+	promotionRouter.Path("/policy").HandlerFunc(router.artifactPromotionRestHandler.GetAllPolicies).Methods("GET")
+	promotionRouter.Path("/policy/{id}").HandlerFunc(router.artifactPromotionRestHandler.DeletePolicy).Methods("DELETE")
+
+	promotionRouter.Path("/promote").HandlerFunc(router.artifactPromotionRestHandler.PromoteArtifact).Methods("POST")
+	promotionRouter.Path("/request/{id}/approve").HandlerFunc(router.artifactPromotionRestHandler.ApprovePromotion).Methods("POST")
+	promotionRouter.Path("/request/{id}/cancel").HandlerFunc(router.artifactPromotionRestHandler.CancelPromotion).Methods("POST")
+	promotionRouter.Path("/history").
+		HandlerFunc(router.artifactPromotionRestHandler.GetPromotionHistory).
+		Queries("appId", "{appId}").
+		Methods("GET")
+}
@@ -134,4 +134,7 @@ type ArtifactsListFilterOptions struct {
 
 	// UseCdStageQueryV2 is to set query version
 	UseCdStageQueryV2 bool
+
+	// IncludePromotedArtifacts lists artifacts promoted to PipelineId along with the artifacts of the parent stage
+	IncludePromotedArtifacts bool
 }
@@ -23,7 +23,7 @@ import (
 
 type GitOpsConfigDto struct {
 	Id                    int             `json:"id,omitempty"`
-	Provider              string          `json:"provider" validate:"oneof=GITLAB GITHUB AZURE_DEVOPS BITBUCKET_CLOUD"`
+	Provider              string          `json:"provider" validate:"oneof=GITLAB GITHUB AZURE_DEVOPS BITBUCKET_CLOUD GITEA GIT_SSH"`
 	Username              string          `json:"username"`
 	Token                 string          `json:"token"`
 	GitLabGroupId         string          `json:"gitLabGroupId"`
@@ -33,13 +33,22 @@ type GitOpsConfigDto struct {
 	AzureProjectName      string          `json:"azureProjectName"`
 	BitBucketWorkspaceId  string          `json:"bitBucketWorkspaceId"`
 	BitBucketProjectKey   string          `json:"bitBucketProjectKey"`
+	GiteaOrgName          string          `json:"giteaOrgName"`
+	SshPrivateKey         string          `json:"sshPrivateKey"`
+	SshKnownHosts         string          `json:"sshKnownHosts"`
+	RepoHookUrl           string          `json:"repoHookUrl"`
+	DryRunRepoName        string          `json:"dryRunRepoName"`
+	RepositoryLayout      string          `json:"repositoryLayout,omitempty" validate:"omitempty,oneof=PER_APP MONOREPO"`
+	MonorepoName          string          `json:"monorepoName"`
+	MonorepoPathTemplate  string          `json:"monorepoPathTemplate"`
 	AllowCustomRepository bool            `json:"allowCustomRepository"`
 	EnableTLSVerification bool            `json:"enableTLSVerification"`
 	TLSConfig             *bean.TLSConfig `json:"tlsConfig"`
 
-	IsCADataPresent      bool `json:"isCADataPresent"`
-	IsTLSCertDataPresent bool `json:"isTLSCertDataPresent"`
-	IsTLSKeyDataPresent  bool `json:"isTLSKeyDataPresent"`
+	IsCADataPresent        bool `json:"isCADataPresent"`
+	IsTLSCertDataPresent   bool `json:"isTLSCertDataPresent"`
+	IsTLSKeyDataPresent    bool `json:"isTLSKeyDataPresent"`
+	IsSshPrivateKeyPresent bool `json:"isSshPrivateKeyPresent"`
 
 	// TODO refactoring: create different struct for internal fields
 	GitRepoName    string `json:"-"`
@@ -53,6 +62,24 @@ func (dto GitOpsConfigDto) GetHostUrl() string {
 	return dto.Host
 }
 
+const (
+	// RepositoryLayoutPerApp creates a gitops repository for every devtron app
+	RepositoryLayoutPerApp = "PER_APP"
+	// RepositoryLayoutMonorepo keeps the charts of all devtron apps in a single repository
+	RepositoryLayoutMonorepo = "MONOREPO"
+)
+
+func (dto GitOpsConfigDto) GetRepositoryLayout() string {
+	if len(dto.RepositoryLayout) == 0 {
+		return RepositoryLayoutPerApp
+	}
+	return dto.RepositoryLayout
+}
+
+func (dto GitOpsConfigDto) IsMonorepoLayout() bool {
+	return dto.GetRepositoryLayout() == RepositoryLayoutMonorepo
+}
+
 type GitRepoRequestDto struct {
 	Host                 string `json:"host"`
 	Provider             string `json:"provider"`
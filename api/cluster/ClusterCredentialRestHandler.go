@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/cluster/credential"
+	"github.com/devtron-labs/devtron/pkg/cluster/credential/bean"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+type ClusterCredentialRestHandler interface {
+	GetAllCredentialExpiry(w http.ResponseWriter, r *http.Request)
+	GetCredentialExpiry(w http.ResponseWriter, r *http.Request)
+	RotateServiceAccountToken(w http.ResponseWriter, r *http.Request)
+	GetRotationAudits(w http.ResponseWriter, r *http.Request)
+}
+
+type ClusterCredentialRestHandlerImpl struct {
+	logger                   *zap.SugaredLogger
+	userService              user.UserService
+	enforcer                 casbin.Enforcer
+	clusterCredentialService credential.ClusterCredentialService
+}
+
+func NewClusterCredentialRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	enforcer casbin.Enforcer,
+	clusterCredentialService credential.ClusterCredentialService) *ClusterCredentialRestHandlerImpl {
+	return &ClusterCredentialRestHandlerImpl{
+		logger:                   logger,
+		userService:              userService,
+		enforcer:                 enforcer,
+		clusterCredentialService: clusterCredentialService,
+	}
+}
+
+// authorize allows only super admins, same as the cluster config the credentials belong to
+func (impl ClusterCredentialRestHandlerImpl) authorize(w http.ResponseWriter, r *http.Request, action string) (int32, bool) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, action, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized User"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
+
+func (impl ClusterCredentialRestHandlerImpl) GetAllCredentialExpiry(w http.ResponseWriter, r *http.Request) {
+	if _, ok := impl.authorize(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := impl.clusterCredentialService.GetAllCredentialExpiry()
+	if err != nil {
+		impl.logger.Errorw("service err, GetAllCredentialExpiry", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl ClusterCredentialRestHandlerImpl) GetCredentialExpiry(w http.ResponseWriter, r *http.Request) {
+	if _, ok := impl.authorize(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	clusterId, err := strconv.Atoi(mux.Vars(r)["clusterId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := impl.clusterCredentialService.GetCredentialExpiry(clusterId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetCredentialExpiry", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl ClusterCredentialRestHandlerImpl) RotateServiceAccountToken(w http.ResponseWriter, r *http.Request) {
+	userId, ok := impl.authorize(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	clusterId, err := strconv.Atoi(mux.Vars(r)["clusterId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request := &bean.RotateCredentialRequest{}
+	// the body is optional, the service account of the current token is rotated by default
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil && err != io.EOF {
+		impl.logger.Errorw("request err, RotateServiceAccountToken", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.ClusterId, request.UserId = clusterId, userId
+	impl.logger.Infow("request payload, RotateServiceAccountToken", "payload", request)
+	res, err := impl.clusterCredentialService.RotateServiceAccountToken(r.Context(), request)
+	if err != nil {
+		impl.logger.Errorw("service err, RotateServiceAccountToken", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl ClusterCredentialRestHandlerImpl) GetRotationAudits(w http.ResponseWriter, r *http.Request) {
+	if _, ok := impl.authorize(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	clusterId, err := strconv.Atoi(mux.Vars(r)["clusterId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := impl.clusterCredentialService.GetRotationAudits(clusterId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetRotationAudits", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/bean"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type ClusterDiscoveryRestHandler interface {
+	SaveCloudCredential(w http.ResponseWriter, r *http.Request)
+	UpdateCloudCredential(w http.ResponseWriter, r *http.Request)
+	GetCloudCredential(w http.ResponseWriter, r *http.Request)
+	GetAllCloudCredentials(w http.ResponseWriter, r *http.Request)
+	DeleteCloudCredential(w http.ResponseWriter, r *http.Request)
+	DiscoverClusters(w http.ResponseWriter, r *http.Request)
+	ImportClusters(w http.ResponseWriter, r *http.Request)
+}
+
+type ClusterDiscoveryRestHandlerImpl struct {
+	logger                  *zap.SugaredLogger
+	userService             user.UserService
+	validator               *validator.Validate
+	enforcer                casbin.Enforcer
+	clusterDiscoveryService discovery.ClusterDiscoveryService
+}
+
+func NewClusterDiscoveryRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	validator *validator.Validate,
+	enforcer casbin.Enforcer,
+	clusterDiscoveryService discovery.ClusterDiscoveryService) *ClusterDiscoveryRestHandlerImpl {
+	return &ClusterDiscoveryRestHandlerImpl{
+		logger:                  logger,
+		userService:             userService,
+		validator:               validator,
+		enforcer:                enforcer,
+		clusterDiscoveryService: clusterDiscoveryService,
+	}
+}
+
+// authorize allows only super admins, cloud credentials give access to every cluster of the cloud account
+func (impl ClusterDiscoveryRestHandlerImpl) authorize(w http.ResponseWriter, r *http.Request, action string) (int32, bool) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, action, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized User"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
+
+func (impl ClusterDiscoveryRestHandlerImpl) decodeCloudCredential(w http.ResponseWriter, r *http.Request) (*bean.CloudCredentialDto, bool) {
+	request := &bean.CloudCredentialDto{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		impl.logger.Errorw("request err, decodeCloudCredential", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err, decodeCloudCredential", "err", err, "name", request.Name)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return request, true
+}
+
+func (impl ClusterDiscoveryRestHandlerImpl) SaveCloudCredential(w http.ResponseWriter, r *http.Request) {
+	userId, ok := impl.authorize(w, r, casbin.ActionCreate)
+	if !ok {
+		return
+	}
+	// not logging the request as it contains the credential secret
+	request, ok := impl.decodeCloudCredential(w, r)
+	if !ok {
+		return
+	}
+	request.UserId = userId
+	res, err := impl.clusterDiscoveryService.SaveCloudCredential(request)
+	if err != nil {
+		impl.logger.Errorw("service err, SaveCloudCredential", "err", err, "name", request.Name)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl ClusterDiscoveryRestHandlerImpl) UpdateCloudCredential(w http.ResponseWriter, r *http.Request) {
+	userId, ok := impl.authorize(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	request, ok := impl.decodeCloudCredential(w, r)
+	if !ok {
+		return
+	}
+	request.UserId = userId
+	res, err := impl.clusterDiscoveryService.UpdateCloudCredential(request)
+	if err != nil {
+		impl.logger.Errorw("service err, UpdateCloudCredential", "err", err, "id", request.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl ClusterDiscoveryRestHandlerImpl) GetCloudCredential(w http.ResponseWriter, r *http.Request) {
+	if _, ok := impl.authorize(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := impl.clusterDiscoveryService.GetCloudCredential(id)
+	if err != nil {
+		impl.logger.Errorw("service err, GetCloudCredential", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl ClusterDiscoveryRestHandlerImpl) GetAllCloudCredentials(w http.ResponseWriter, r *http.Request) {
+	if _, ok := impl.authorize(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := impl.clusterDiscoveryService.GetAllCloudCredentials()
+	if err != nil {
+		impl.logger.Errorw("service err, GetAllCloudCredentials", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl ClusterDiscoveryRestHandlerImpl) DeleteCloudCredential(w http.ResponseWriter, r *http.Request) {
+	userId, ok := impl.authorize(w, r, casbin.ActionDelete)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.clusterDiscoveryService.DeleteCloudCredential(id, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, DeleteCloudCredential", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "cloud credential deleted successfully", http.StatusOK)
+}
+
+func (impl ClusterDiscoveryRestHandlerImpl) DiscoverClusters(w http.ResponseWriter, r *http.Request) {
+	if _, ok := impl.authorize(w, r, casbin.ActionCreate); !ok {
+		return
+	}
+	cloudCredentialId, err := strconv.Atoi(mux.Vars(r)["cloudCredentialId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := impl.clusterDiscoveryService.DiscoverClusters(r.Context(), cloudCredentialId)
+	if err != nil {
+		impl.logger.Errorw("service err, DiscoverClusters", "err", err, "cloudCredentialId", cloudCredentialId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl ClusterDiscoveryRestHandlerImpl) ImportClusters(w http.ResponseWriter, r *http.Request) {
+	userId, ok := impl.authorize(w, r, casbin.ActionCreate)
+	if !ok {
+		return
+	}
+	request := &bean.ImportClustersRequest{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		impl.logger.Errorw("request err, ImportClusters", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err, ImportClusters", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	res, err := impl.clusterDiscoveryService.ImportClusters(r.Context(), request)
+	if err != nil {
+		impl.logger.Errorw("service err, ImportClusters", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
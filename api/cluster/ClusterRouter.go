@@ -25,12 +25,17 @@ type ClusterRouter interface {
 }
 
 type ClusterRouterImpl struct {
-	clusterRestHandler ClusterRestHandler
+	clusterRestHandler           ClusterRestHandler
+	clusterDiscoveryRestHandler  ClusterDiscoveryRestHandler
+	clusterCredentialRestHandler ClusterCredentialRestHandler
 }
 
-func NewClusterRouterImpl(handler ClusterRestHandler) *ClusterRouterImpl {
+func NewClusterRouterImpl(handler ClusterRestHandler, clusterDiscoveryRestHandler ClusterDiscoveryRestHandler,
+	clusterCredentialRestHandler ClusterCredentialRestHandler) *ClusterRouterImpl {
 	return &ClusterRouterImpl{
-		clusterRestHandler: handler,
+		clusterRestHandler:           handler,
+		clusterDiscoveryRestHandler:  clusterDiscoveryRestHandler,
+		clusterCredentialRestHandler: clusterCredentialRestHandler,
 	}
 }
 
@@ -92,4 +97,48 @@ func (impl ClusterRouterImpl) InitClusterRouter(clusterRouter *mux.Router) {
 	clusterRouter.Path("/description").
 		Methods("PUT").
 		HandlerFunc(impl.clusterRestHandler.UpdateClusterDescription)
+
+	clusterRouter.Path("/cloud-credential").
+		Methods("GET").
+		HandlerFunc(impl.clusterDiscoveryRestHandler.GetAllCloudCredentials)
+
+	clusterRouter.Path("/cloud-credential/{id}").
+		Methods("GET").
+		HandlerFunc(impl.clusterDiscoveryRestHandler.GetCloudCredential)
+
+	clusterRouter.Path("/cloud-credential").
+		Methods("POST").
+		HandlerFunc(impl.clusterDiscoveryRestHandler.SaveCloudCredential)
+
+	clusterRouter.Path("/cloud-credential").
+		Methods("PUT").
+		HandlerFunc(impl.clusterDiscoveryRestHandler.UpdateCloudCredential)
+
+	clusterRouter.Path("/cloud-credential/{id}").
+		Methods("DELETE").
+		HandlerFunc(impl.clusterDiscoveryRestHandler.DeleteCloudCredential)
+
+	clusterRouter.Path("/discovery/{cloudCredentialId}").
+		Methods("GET").
+		HandlerFunc(impl.clusterDiscoveryRestHandler.DiscoverClusters)
+
+	clusterRouter.Path("/discovery/import").
+		Methods("POST").
+		HandlerFunc(impl.clusterDiscoveryRestHandler.ImportClusters)
+
+	clusterRouter.Path("/credential/expiry").
+		Methods("GET").
+		HandlerFunc(impl.clusterCredentialRestHandler.GetAllCredentialExpiry)
+
+	clusterRouter.Path("/credential/expiry/{clusterId}").
+		Methods("GET").
+		HandlerFunc(impl.clusterCredentialRestHandler.GetCredentialExpiry)
+
+	clusterRouter.Path("/credential/rotate/{clusterId}").
+		Methods("POST").
+		HandlerFunc(impl.clusterCredentialRestHandler.RotateServiceAccountToken)
+
+	clusterRouter.Path("/credential/rotate/{clusterId}/audit").
+		Methods("GET").
+		HandlerFunc(impl.clusterCredentialRestHandler.GetRotationAudits)
 }
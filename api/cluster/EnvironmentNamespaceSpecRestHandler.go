@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cluster
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec/bean"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"net/http"
+	"strconv"
+)
+
+// authorizeNamespaceSpec returns the environment id of the path after checking the access of the user to the environment
+func (impl EnvironmentRestHandlerImpl) authorizeNamespaceSpec(w http.ResponseWriter, r *http.Request, action string) (int, int32, bool) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+	envId, err := strconv.Atoi(mux.Vars(r)["envId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, 0, false
+	}
+	environment, err := impl.environmentClusterMappingsService.FindById(envId)
+	if err != nil {
+		impl.logger.Errorw("error in getting environment", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return 0, 0, false
+	}
+	if ok := impl.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobalEnvironment, action, environment.EnvironmentIdentifier); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, 0, false
+	}
+	return envId, userId, true
+}
+
+func (impl EnvironmentRestHandlerImpl) GetNamespaceSpec(w http.ResponseWriter, r *http.Request) {
+	envId, _, ok := impl.authorizeNamespaceSpec(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	res, err := impl.namespaceSpecService.GetNamespaceSpec(r.Context(), envId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetNamespaceSpec", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl EnvironmentRestHandlerImpl) SaveNamespaceSpec(w http.ResponseWriter, r *http.Request) {
+	envId, userId, ok := impl.authorizeNamespaceSpec(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	var spec bean.NamespaceSpec
+	err := json.NewDecoder(r.Body).Decode(&spec)
+	if err != nil {
+		impl.logger.Errorw("request err, SaveNamespaceSpec", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := impl.namespaceSpecService.SaveNamespaceSpec(envId, &spec, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, SaveNamespaceSpec", "err", err, "envId", envId, "payload", spec)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl EnvironmentRestHandlerImpl) DeleteNamespaceSpec(w http.ResponseWriter, r *http.Request) {
+	envId, userId, ok := impl.authorizeNamespaceSpec(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	err := impl.namespaceSpecService.DeleteNamespaceSpec(r.Context(), envId, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, DeleteNamespaceSpec", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, envId, http.StatusOK)
+}
+
+func (impl EnvironmentRestHandlerImpl) ReconcileNamespaceSpec(w http.ResponseWriter, r *http.Request) {
+	envId, _, ok := impl.authorizeNamespaceSpec(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	res, err := impl.namespaceSpecService.ReconcileNamespaceSpec(r.Context(), envId)
+	if err != nil {
+		impl.logger.Errorw("service err, ReconcileNamespaceSpec", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
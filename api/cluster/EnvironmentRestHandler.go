@@ -22,6 +22,7 @@ import (
 	bean3 "github.com/devtron-labs/devtron/pkg/cluster/bean"
 	request "github.com/devtron-labs/devtron/pkg/cluster/environment"
 	bean2 "github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment/read"
 	"github.com/devtron-labs/devtron/util/commonEnforcementFunctionsUtil"
 	"net/http"
@@ -61,6 +62,10 @@ type EnvironmentRestHandler interface {
 	GetEnvironmentConnection(w http.ResponseWriter, r *http.Request)
 	DeleteEnvironment(w http.ResponseWriter, r *http.Request)
 	GetCombinedEnvironmentListForDropDownByClusterIds(w http.ResponseWriter, r *http.Request)
+	GetNamespaceSpec(w http.ResponseWriter, r *http.Request)
+	SaveNamespaceSpec(w http.ResponseWriter, r *http.Request)
+	DeleteNamespaceSpec(w http.ResponseWriter, r *http.Request)
+	ReconcileNamespaceSpec(w http.ResponseWriter, r *http.Request)
 }
 
 type EnvironmentRestHandlerImpl struct {
@@ -75,6 +80,7 @@ type EnvironmentRestHandlerImpl struct {
 	k8sUtil                           *k8s2.K8sServiceImpl
 	cfg                               *bean.Config
 	rbacEnforcementUtil               commonEnforcementFunctionsUtil.CommonEnforcementUtil
+	namespaceSpecService              namespaceSpec.NamespaceSpecService
 }
 
 type ClusterReachableResponse struct {
@@ -83,7 +89,8 @@ type ClusterReachableResponse struct {
 }
 
 func NewEnvironmentRestHandlerImpl(svc request.EnvironmentService, environmentReadService read.EnvironmentReadService, logger *zap.SugaredLogger, userService user.UserService, validator *validator.Validate, enforcer casbin.Enforcer, deleteService delete2.DeleteService, k8sUtil *k8s2.K8sServiceImpl, k8sCommonService k8s.K8sCommonService,
-	rbacEnforcementUtil commonEnforcementFunctionsUtil.CommonEnforcementUtil) *EnvironmentRestHandlerImpl {
+	rbacEnforcementUtil commonEnforcementFunctionsUtil.CommonEnforcementUtil,
+	namespaceSpecService namespaceSpec.NamespaceSpecService) *EnvironmentRestHandlerImpl {
 	cfg := &bean.Config{}
 	err := env.Parse(cfg)
 	if err != nil {
@@ -103,6 +110,7 @@ func NewEnvironmentRestHandlerImpl(svc request.EnvironmentService, environmentRe
 		k8sUtil:                           k8sUtil,
 		k8sCommonService:                  k8sCommonService,
 		rbacEnforcementUtil:               rbacEnforcementUtil,
+		namespaceSpecService:              namespaceSpecService,
 	}
 }
 
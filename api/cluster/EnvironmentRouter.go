@@ -77,4 +77,16 @@ func (impl EnvironmentRouterImpl) InitEnvironmentClusterMappingsRouter(environme
 	environmentClusterMappingsRouter.Path("/{envId}/connection").
 		Methods("GET").
 		HandlerFunc(impl.environmentClusterMappingsRestHandler.GetEnvironmentConnection)
+	environmentClusterMappingsRouter.Path("/{envId}/namespace-spec").
+		Methods("GET").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.GetNamespaceSpec)
+	environmentClusterMappingsRouter.Path("/{envId}/namespace-spec").
+		Methods("PUT").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.SaveNamespaceSpec)
+	environmentClusterMappingsRouter.Path("/{envId}/namespace-spec").
+		Methods("DELETE").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.DeleteNamespaceSpec)
+	environmentClusterMappingsRouter.Path("/{envId}/namespace-spec/reconcile").
+		Methods("POST").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.ReconcileNamespaceSpec)
 }
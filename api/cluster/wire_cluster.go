@@ -18,7 +18,10 @@ package cluster
 
 import (
 	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/cluster/credential"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec"
 	read2 "github.com/devtron-labs/devtron/pkg/cluster/environment/read"
 	repository3 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	"github.com/devtron-labs/devtron/pkg/cluster/rbac"
@@ -58,11 +61,20 @@ var ClusterWireSet = wire.NewSet(
 
 	NewClusterRestHandlerImpl,
 	wire.Bind(new(ClusterRestHandler), new(*ClusterRestHandlerImpl)),
+	discovery.ClusterDiscoveryWireSet,
+	NewClusterDiscoveryRestHandlerImpl,
+	wire.Bind(new(ClusterDiscoveryRestHandler), new(*ClusterDiscoveryRestHandlerImpl)),
+	credential.ClusterCredentialWireSet,
+	credential.NewCredentialExpiryEventNotifierImpl,
+	wire.Bind(new(credential.CredentialExpiryNotifier), new(*credential.CredentialExpiryEventNotifierImpl)),
+	NewClusterCredentialRestHandlerImpl,
+	wire.Bind(new(ClusterCredentialRestHandler), new(*ClusterCredentialRestHandlerImpl)),
 	NewClusterRouterImpl,
 	wire.Bind(new(ClusterRouter), new(*ClusterRouterImpl)),
 
 	repository3.NewEnvironmentRepositoryImpl,
 	wire.Bind(new(repository3.EnvironmentRepository), new(*repository3.EnvironmentRepositoryImpl)),
+	namespaceSpec.NamespaceSpecWireSet,
 	environment.NewEnvironmentServiceImpl,
 	wire.Bind(new(environment.EnvironmentService), new(*environment.EnvironmentServiceImpl)),
 	read2.NewEnvironmentReadServiceImpl,
@@ -99,10 +111,19 @@ var ClusterWireSetEa = wire.NewSet(
 
 	NewClusterRestHandlerImpl,
 	wire.Bind(new(ClusterRestHandler), new(*ClusterRestHandlerImpl)),
+	discovery.ClusterDiscoveryWireSet,
+	NewClusterDiscoveryRestHandlerImpl,
+	wire.Bind(new(ClusterDiscoveryRestHandler), new(*ClusterDiscoveryRestHandlerImpl)),
+	credential.ClusterCredentialWireSet,
+	credential.NewCredentialExpiryLogNotifierImpl,
+	wire.Bind(new(credential.CredentialExpiryNotifier), new(*credential.CredentialExpiryLogNotifierImpl)),
+	NewClusterCredentialRestHandlerImpl,
+	wire.Bind(new(ClusterCredentialRestHandler), new(*ClusterCredentialRestHandlerImpl)),
 	NewClusterRouterImpl,
 	wire.Bind(new(ClusterRouter), new(*ClusterRouterImpl)),
 	repository3.NewEnvironmentRepositoryImpl,
 	wire.Bind(new(repository3.EnvironmentRepository), new(*repository3.EnvironmentRepositoryImpl)),
+	namespaceSpec.NamespaceSpecWireSet,
 	environment.NewEnvironmentServiceImpl,
 	wire.Bind(new(environment.EnvironmentService), new(*environment.EnvironmentServiceImpl)),
 	read2.NewEnvironmentReadServiceImpl,
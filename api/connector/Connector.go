@@ -41,6 +41,7 @@ var delimiter = []byte("\n\n")
 type Pump interface {
 	StartStreamWithTransformer(w http.ResponseWriter, recv func() (proto.Message, error), err error, transformer func(interface{}) interface{})
 	StartK8sStreamWithHeartBeat(w http.ResponseWriter, isReconnect bool, stream io.ReadCloser, err error)
+	StartJsonStreamWithHeartBeat(w http.ResponseWriter, recv func() (interface{}, error), err error)
 }
 
 type PumpImpl struct {
@@ -81,30 +82,9 @@ func (impl PumpImpl) StartK8sStreamWithHeartBeat(w http.ResponseWriter, isReconn
 		}
 	}
 	// heartbeat start
-	ticker := time.NewTicker(30 * time.Second)
-	done := make(chan bool)
 	var mux sync.Mutex
-	go func() error {
-		for {
-			select {
-			case <-done:
-				return nil
-			case t := <-ticker.C:
-				mux.Lock()
-				err := impl.sendEvent(nil, []byte("PING"), []byte(t.String()), w)
-				mux.Unlock()
-				if err != nil {
-					impl.logger.Errorw("error in writing PING over sse", "err", err)
-					return err
-				}
-				f.Flush()
-			}
-		}
-	}()
-	defer func() {
-		ticker.Stop()
-		done <- true
-	}()
+	stopHeartBeat := impl.startHeartBeat(w, f, &mux)
+	defer stopHeartBeat()
 
 	bufReader := bufio.NewReader(stream)
 	eof := false
@@ -143,6 +123,92 @@ func (impl PumpImpl) StartK8sStreamWithHeartBeat(w http.ResponseWriter, isReconn
 	// heartbeat end
 }
 
+// startHeartBeat writes a PING event every 30 seconds until the returned func is called, writes are serialised through mux
+func (impl PumpImpl) startHeartBeat(w http.ResponseWriter, f http.Flusher, mux *sync.Mutex) func() {
+	ticker := time.NewTicker(30 * time.Second)
+	// done is closed rather than sent on, the heartbeat goroutine may already have exited on a write error
+	done := make(chan struct{})
+	go func() error {
+		for {
+			select {
+			case <-done:
+				return nil
+			case t := <-ticker.C:
+				mux.Lock()
+				err := impl.sendEvent(nil, []byte("PING"), []byte(t.String()), w)
+				if err == nil {
+					f.Flush()
+				}
+				mux.Unlock()
+				if err != nil {
+					impl.logger.Errorw("error in writing PING over sse", "err", err)
+					return err
+				}
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+// StartJsonStreamWithHeartBeat writes every object received from recv as a json event until recv returns io.EOF
+func (impl PumpImpl) StartJsonStreamWithHeartBeat(w http.ResponseWriter, recv func() (interface{}, error), err error) {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "unexpected server doesnt support streaming", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Transfer-Encoding", "chunked")
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "no-cache, no-transform")
+
+	if err != nil {
+		err := impl.sendEvent(nil, []byte("CUSTOM_ERR_STREAM"), []byte(err.Error()), w)
+		if err != nil {
+			impl.logger.Errorw("error in writing data over sse", "err", err)
+		}
+		return
+	}
+	var mux sync.Mutex
+	stopHeartBeat := impl.startHeartBeat(w, f, &mux)
+	defer stopHeartBeat()
+	for {
+		obj, err := recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			impl.logger.Errorw("error in receiving data, StartJsonStreamWithHeartBeat", "err", err)
+			mux.Lock()
+			if err := impl.sendEvent(nil, []byte("CUSTOM_ERR_STREAM"), []byte(err.Error()), w); err != nil {
+				impl.logger.Errorw("error in writing data over sse", "err", err)
+			}
+			mux.Unlock()
+			return
+		}
+		payload, err := json.Marshal(obj)
+		if err != nil {
+			impl.logger.Errorw("error in marshaling data, StartJsonStreamWithHeartBeat", "err", err)
+			continue
+		}
+		mux.Lock()
+		err = impl.sendEvent(nil, nil, payload, w)
+		if err == nil {
+			f.Flush()
+		}
+		mux.Unlock()
+		if err != nil {
+			impl.logger.Errorw("error in writing data over sse", "err", err)
+			return
+		}
+	}
+}
+
 func (impl PumpImpl) StartStreamWithTransformer(w http.ResponseWriter, recv func() (proto.Message, error), err error, transformer func(interface{}) interface{}) {
 	f, ok := w.(http.Flusher)
 	if !ok {
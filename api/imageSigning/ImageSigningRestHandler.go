@@ -0,0 +1,253 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageSigning
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+)
+
+type ImageSigningRestHandler interface {
+	SaveSigningKey(w http.ResponseWriter, r *http.Request)
+	GetAllSigningKeys(w http.ResponseWriter, r *http.Request)
+	DeleteSigningKey(w http.ResponseWriter, r *http.Request)
+	SavePolicy(w http.ResponseWriter, r *http.Request)
+	GetAllPolicies(w http.ResponseWriter, r *http.Request)
+	DeletePolicy(w http.ResponseWriter, r *http.Request)
+	GetArtifactSignatureDetail(w http.ResponseWriter, r *http.Request)
+}
+
+type ImageSigningRestHandlerImpl struct {
+	logger              *zap.SugaredLogger
+	userService         user.UserService
+	imageSigningService imageSigning.ImageSigningService
+	enforcer            casbin.Enforcer
+	enforcerUtil        rbac.EnforcerUtil
+	validator           *validator.Validate
+}
+
+func NewImageSigningRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	imageSigningService imageSigning.ImageSigningService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate) *ImageSigningRestHandlerImpl {
+	return &ImageSigningRestHandlerImpl{
+		logger:              logger,
+		userService:         userService,
+		imageSigningService: imageSigningService,
+		enforcer:            enforcer,
+		enforcerUtil:        enforcerUtil,
+		validator:           validator,
+	}
+}
+
+func (handler *ImageSigningRestHandlerImpl) SaveSigningKey(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	var request bean.SigningKeyDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveSigningKey", "err", err, "payload", r.Body)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveSigningKey", "err", err, "name", request.Name)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.imageSigningService.SaveSigningKey(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SaveSigningKey", "err", err, "name", request.Name)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) GetAllSigningKeys(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	resp, err := handler.imageSigningService.GetAllSigningKeys()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllSigningKeys", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) DeleteSigningKey(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionDelete, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	err = handler.imageSigningService.DeleteSigningKey(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteSigningKey", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) SavePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	var request bean.SignaturePolicyDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SavePolicy", "err", err, "payload", r.Body)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SavePolicy", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.imageSigningService.SavePolicy(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, SavePolicy", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) GetAllPolicies(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	resp, err := handler.imageSigningService.GetAllPolicies()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllPolicies", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionDelete, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	err = handler.imageSigningService.DeletePolicy(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeletePolicy", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *ImageSigningRestHandlerImpl) GetArtifactSignatureDetail(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	artifactId, err := common.ExtractIntPathParam(w, r, "artifactId")
+	if err != nil {
+		return
+	}
+	appId, err := common.ExtractIntQueryParam(w, r, "appId", 0)
+	if err != nil {
+		return
+	}
+	// RBAC
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	// RBAC
+	resp, err := handler.imageSigningService.GetArtifactSignatureDetail(artifactId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetArtifactSignatureDetail", "err", err, "artifactId", artifactId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
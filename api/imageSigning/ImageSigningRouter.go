@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageSigning
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type ImageSigningRouter interface {
+	InitImageSigningRouter(router *mux.Router)
+}
+
+type ImageSigningRouterImpl struct {
+	imageSigningRestHandler ImageSigningRestHandler
+}
+
+func NewImageSigningRouterImpl(imageSigningRestHandler ImageSigningRestHandler) *ImageSigningRouterImpl {
+	return &ImageSigningRouterImpl{imageSigningRestHandler: imageSigningRestHandler}
+}
+
+func (router *ImageSigningRouterImpl) InitImageSigningRouter(signingRouter *mux.Router) {
+	signingRouter.Path("/key").HandlerFunc(router.imageSigningRestHandler.SaveSigningKey).Methods("POST", "PUT")
+	signingRouter.Path("/key").HandlerFunc(router.imageSigningRestHandler.GetAllSigningKeys).Methods("GET")
+	signingRouter.Path("/key/{id}").HandlerFunc(router.imageSigningRestHandler.DeleteSigningKey).Methods("DELETE")
+
+	signingRouter.Path("/policy").HandlerFunc(router.imageSigningRestHandler.SavePolicy).Methods("POST", "PUT")
+	signingRouter.Path("/policy").HandlerFunc(router.imageSigningRestHandler.GetAllPolicies).Methods("GET")
+	signingRouter.Path("/policy/{id}").HandlerFunc(router.imageSigningRestHandler.DeletePolicy).Methods("DELETE")
+
+	signingRouter.Path("/artifact/{artifactId}").
+		HandlerFunc(router.imageSigningRestHandler.GetArtifactSignatureDetail).
+		Queries("appId", "{appId}").
+		Methods("GET")
+}
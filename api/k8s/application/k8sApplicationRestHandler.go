@@ -44,6 +44,8 @@ import (
 	application2 "github.com/devtron-labs/devtron/pkg/k8s/application"
 	bean2 "github.com/devtron-labs/devtron/pkg/k8s/application/bean"
 	bean3 "github.com/devtron-labs/devtron/pkg/k8s/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceSearch"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceWatch"
 	"github.com/devtron-labs/devtron/pkg/terminal"
 	"github.com/devtron-labs/devtron/util"
 	"github.com/devtron-labs/devtron/util/rbac"
@@ -80,6 +82,14 @@ type K8sApplicationRestHandler interface {
 	CreateEphemeralContainer(w http.ResponseWriter, r *http.Request)
 	DeleteEphemeralContainer(w http.ResponseWriter, r *http.Request)
 	GetAllApiResourceGVKWithoutAuthorization(w http.ResponseWriter, r *http.Request)
+	SearchResources(w http.ResponseWriter, r *http.Request)
+	SaveResourceView(w http.ResponseWriter, r *http.Request)
+	UpdateResourceView(w http.ResponseWriter, r *http.Request)
+	GetResourceView(w http.ResponseWriter, r *http.Request)
+	GetAllResourceViews(w http.ResponseWriter, r *http.Request)
+	DeleteResourceView(w http.ResponseWriter, r *http.Request)
+	SearchResourceView(w http.ResponseWriter, r *http.Request)
+	WatchResources(w http.ResponseWriter, r *http.Request)
 }
 
 type K8sApplicationRestHandlerImpl struct {
@@ -97,9 +107,13 @@ type K8sApplicationRestHandlerImpl struct {
 	terminalEnvVariables       *util.TerminalEnvVariables
 	fluxAppService             fluxApplication.FluxApplicationService
 	argoApplicationReadService read.ArgoApplicationReadService
+	resourceSearchService      resourceSearch.ResourceSearchService
+	resourceWatchService       resourceWatch.ResourceWatchService
 }
 
 func NewK8sApplicationRestHandlerImpl(logger *zap.SugaredLogger, k8sApplicationService application2.K8sApplicationService, pump connector.Pump, terminalSessionHandler terminal.TerminalSessionHandler, enforcer casbin.Enforcer, enforcerUtilHelm rbac.EnforcerUtilHelm, enforcerUtil rbac.EnforcerUtil, helmAppService client.HelmAppService, userService user.UserService, k8sCommonService k8s.K8sCommonService, validator *validator.Validate, envVariables *util.EnvironmentVariables, fluxAppService fluxApplication.FluxApplicationService, argoApplicationReadService read.ArgoApplicationReadService,
+	resourceSearchService resourceSearch.ResourceSearchService,
+	resourceWatchService resourceWatch.ResourceWatchService,
 ) *K8sApplicationRestHandlerImpl {
 	return &K8sApplicationRestHandlerImpl{
 		logger:                     logger,
@@ -116,6 +130,8 @@ func NewK8sApplicationRestHandlerImpl(logger *zap.SugaredLogger, k8sApplicationS
 		terminalEnvVariables:       envVariables.TerminalEnvVariables,
 		fluxAppService:             fluxAppService,
 		argoApplicationReadService: argoApplicationReadService,
+		resourceSearchService:      resourceSearchService,
+		resourceWatchService:       resourceWatchService,
 	}
 }
 
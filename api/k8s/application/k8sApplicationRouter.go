@@ -83,6 +83,28 @@ func (impl *K8sApplicationRouterImpl) InitK8sApplicationRouter(k8sAppRouter *mux
 	k8sAppRouter.Path("/resource/list").
 		HandlerFunc(impl.k8sApplicationRestHandler.GetResourceList).Methods("POST")
 
+	k8sAppRouter.Path("/resource/search").
+		HandlerFunc(impl.k8sApplicationRestHandler.SearchResources).Methods("POST")
+
+	k8sAppRouter.Path("/resource/watch").
+		Queries("clusterId", "{clusterId}").
+		Queries("version", "{version}").
+		Queries("kind", "{kind}").
+		HandlerFunc(impl.k8sApplicationRestHandler.WatchResources).Methods("GET")
+
+	k8sAppRouter.Path("/resource/view").
+		HandlerFunc(impl.k8sApplicationRestHandler.GetAllResourceViews).Methods("GET")
+	k8sAppRouter.Path("/resource/view").
+		HandlerFunc(impl.k8sApplicationRestHandler.SaveResourceView).Methods("POST")
+	k8sAppRouter.Path("/resource/view").
+		HandlerFunc(impl.k8sApplicationRestHandler.UpdateResourceView).Methods("PUT")
+	k8sAppRouter.Path("/resource/view/{id}").
+		HandlerFunc(impl.k8sApplicationRestHandler.GetResourceView).Methods("GET")
+	k8sAppRouter.Path("/resource/view/{id}").
+		HandlerFunc(impl.k8sApplicationRestHandler.DeleteResourceView).Methods("DELETE")
+	k8sAppRouter.Path("/resource/view/{id}/search").
+		HandlerFunc(impl.k8sApplicationRestHandler.SearchResourceView).Methods("POST")
+
 	k8sAppRouter.Path("/resources/apply").
 		HandlerFunc(impl.k8sApplicationRestHandler.ApplyResources).Methods("POST")
 
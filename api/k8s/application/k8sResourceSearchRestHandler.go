@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package application
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	bean3 "github.com/devtron-labs/devtron/pkg/k8s/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceSearch"
+	searchBean "github.com/devtron-labs/devtron/pkg/k8s/resourceSearch/bean"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+// getResourceAccessValidator returns the per resource rbac of the resource list, super admins are allowed every resource
+func (handler *K8sApplicationRestHandlerImpl) getResourceAccessValidator(token string) resourceSearch.ResourceAccessValidator {
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); ok {
+		return func(token, clusterName string, request bean3.ResourceRequestBean, casbinAction string) bool {
+			return true
+		}
+	}
+	return handler.verifyRbacForCluster
+}
+
+func (handler *K8sApplicationRestHandlerImpl) SearchResources(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	query := &searchBean.ResourceSearchQuery{}
+	err = json.NewDecoder(r.Body).Decode(query)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request body", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	response, err := handler.resourceSearchService.SearchResources(r.Context(), token, query, handler.getResourceAccessValidator(token))
+	if err != nil {
+		handler.logger.Errorw("error in searching resources", "query", query, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, response, http.StatusOK)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) decodeResourceView(w http.ResponseWriter, r *http.Request) (*searchBean.ResourceViewDto, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return nil, false
+	}
+	request := &searchBean.ResourceViewDto{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request body", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, decodeResourceView", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	request.UserId = userId
+	return request, true
+}
+
+func (handler *K8sApplicationRestHandlerImpl) SaveResourceView(w http.ResponseWriter, r *http.Request) {
+	request, ok := handler.decodeResourceView(w, r)
+	if !ok {
+		return
+	}
+	response, err := handler.resourceSearchService.SaveView(request)
+	if err != nil {
+		handler.logger.Errorw("error in saving resource view", "payload", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, response, http.StatusOK)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) UpdateResourceView(w http.ResponseWriter, r *http.Request) {
+	request, ok := handler.decodeResourceView(w, r)
+	if !ok {
+		return
+	}
+	response, err := handler.resourceSearchService.UpdateView(request)
+	if err != nil {
+		handler.logger.Errorw("error in updating resource view", "payload", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, response, http.StatusOK)
+}
+
+// getResourceViewId returns the logged-in user and the view id of the path
+func (handler *K8sApplicationRestHandlerImpl) getResourceViewId(w http.ResponseWriter, r *http.Request) (int32, int, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return userId, id, true
+}
+
+func (handler *K8sApplicationRestHandlerImpl) GetResourceView(w http.ResponseWriter, r *http.Request) {
+	userId, id, ok := handler.getResourceViewId(w, r)
+	if !ok {
+		return
+	}
+	response, err := handler.resourceSearchService.GetView(id, userId)
+	if err != nil {
+		handler.logger.Errorw("error in getting resource view", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, response, http.StatusOK)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) GetAllResourceViews(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	response, err := handler.resourceSearchService.GetAllViews(userId)
+	if err != nil {
+		handler.logger.Errorw("error in getting resource views", "userId", userId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, response, http.StatusOK)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) DeleteResourceView(w http.ResponseWriter, r *http.Request) {
+	userId, id, ok := handler.getResourceViewId(w, r)
+	if !ok {
+		return
+	}
+	err := handler.resourceSearchService.DeleteView(id, userId)
+	if err != nil {
+		handler.logger.Errorw("error in deleting resource view", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "view deleted successfully", http.StatusOK)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) SearchResourceView(w http.ResponseWriter, r *http.Request) {
+	userId, id, ok := handler.getResourceViewId(w, r)
+	if !ok {
+		return
+	}
+	token := r.Header.Get("token")
+	response, err := handler.resourceSearchService.SearchView(r.Context(), token, id, userId, handler.getResourceAccessValidator(token))
+	if err != nil {
+		handler.logger.Errorw("error in searching resources of view", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, response, http.StatusOK)
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package application
+
+import (
+	"context"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceWatch/bean"
+	"io"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"net/http"
+	"strconv"
+)
+
+// WatchResources streams the add, update and delete events of the selected resources over sse, filtered by the rbac of the user
+func (handler *K8sApplicationRestHandlerImpl) WatchResources(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	v := r.URL.Query()
+	clusterId, err := strconv.Atoi(v.Get("clusterId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request := &bean.ResourceWatchRequest{
+		ClusterId: clusterId,
+		GroupVersionKind: schema.GroupVersionKind{
+			Group:   v.Get("group"),
+			Version: v.Get("version"),
+			Kind:    v.Get("kind"),
+		},
+		Namespace:     v.Get("namespace"),
+		LabelSelector: v.Get("labelSelector"),
+	}
+	ctx, cancel := context.WithCancel(r.Context())
+	if cn, ok := w.(http.CloseNotifier); ok {
+		go func(done <-chan struct{}, closed <-chan bool) {
+			select {
+			case <-done:
+			case <-closed:
+				cancel()
+			}
+		}(ctx.Done(), cn.CloseNotify())
+	}
+	defer cancel()
+	events, err := handler.resourceWatchService.Subscribe(ctx, token, request, handler.getResourceAccessValidator(token))
+	//err is handled inside StartJsonStreamWithHeartBeat method
+	handler.pump.StartJsonStreamWithHeartBeat(w, func() (interface{}, error) {
+		event, ok := <-events
+		if !ok {
+			return nil, io.EOF
+		}
+		return event, nil
+	}, err)
+}
@@ -36,6 +36,8 @@ import (
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	"github.com/devtron-labs/devtron/pkg/k8s/capacity"
 	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/costAllocation"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
@@ -51,17 +53,30 @@ type K8sCapacityRestHandler interface {
 	CordonOrUnCordonNode(w http.ResponseWriter, r *http.Request)
 	DrainNode(w http.ResponseWriter, r *http.Request)
 	EditNodeTaints(w http.ResponseWriter, r *http.Request)
+	CreateNodeMaintenance(w http.ResponseWriter, r *http.Request)
+	GetNodeMaintenanceList(w http.ResponseWriter, r *http.Request)
+	GetNodeMaintenance(w http.ResponseWriter, r *http.Request)
+	PauseNodeMaintenance(w http.ResponseWriter, r *http.Request)
+	ResumeNodeMaintenance(w http.ResponseWriter, r *http.Request)
+	AbortNodeMaintenance(w http.ResponseWriter, r *http.Request)
+	GetCostAllocation(w http.ResponseWriter, r *http.Request)
+	GetIdleCost(w http.ResponseWriter, r *http.Request)
+	GetPriceSheets(w http.ResponseWriter, r *http.Request)
+	SavePriceSheet(w http.ResponseWriter, r *http.Request)
+	DeletePriceSheet(w http.ResponseWriter, r *http.Request)
 }
 type K8sCapacityRestHandlerImpl struct {
-	logger             *zap.SugaredLogger
-	k8sCapacityService capacity.K8sCapacityService
-	userService        user.UserService
-	enforcer           casbin.Enforcer
-	clusterService     cluster.ClusterService
-	environmentService environment.EnvironmentService
-	clusterRbacService rbac.ClusterRbacService
-	clusterReadService read.ClusterReadService
-	validator          *validator.Validate
+	logger                 *zap.SugaredLogger
+	k8sCapacityService     capacity.K8sCapacityService
+	userService            user.UserService
+	enforcer               casbin.Enforcer
+	clusterService         cluster.ClusterService
+	environmentService     environment.EnvironmentService
+	clusterRbacService     rbac.ClusterRbacService
+	clusterReadService     read.ClusterReadService
+	validator              *validator.Validate
+	nodeMaintenanceService nodeMaintenance.NodeMaintenanceService
+	costAllocationService  costAllocation.CostAllocationService
 }
 
 func NewK8sCapacityRestHandlerImpl(logger *zap.SugaredLogger,
@@ -70,17 +85,21 @@ func NewK8sCapacityRestHandlerImpl(logger *zap.SugaredLogger,
 	clusterService cluster.ClusterService,
 	environmentService environment.EnvironmentService,
 	clusterRbacService rbac.ClusterRbacService,
-	clusterReadService read.ClusterReadService, validator *validator.Validate) *K8sCapacityRestHandlerImpl {
+	clusterReadService read.ClusterReadService, validator *validator.Validate,
+	nodeMaintenanceService nodeMaintenance.NodeMaintenanceService,
+	costAllocationService costAllocation.CostAllocationService) *K8sCapacityRestHandlerImpl {
 	return &K8sCapacityRestHandlerImpl{
-		logger:             logger,
-		k8sCapacityService: k8sCapacityService,
-		userService:        userService,
-		enforcer:           enforcer,
-		clusterService:     clusterService,
-		environmentService: environmentService,
-		clusterRbacService: clusterRbacService,
-		clusterReadService: clusterReadService,
-		validator:          validator,
+		logger:                 logger,
+		k8sCapacityService:     k8sCapacityService,
+		userService:            userService,
+		enforcer:               enforcer,
+		clusterService:         clusterService,
+		environmentService:     environmentService,
+		clusterRbacService:     clusterRbacService,
+		clusterReadService:     clusterReadService,
+		validator:              validator,
+		nodeMaintenanceService: nodeMaintenanceService,
+		costAllocationService:  costAllocationService,
 	}
 }
 
@@ -63,4 +63,38 @@ func (impl *K8sCapacityRouterImpl) InitK8sCapacityRouter(k8sCapacityRouter *mux.
 
 	k8sCapacityRouter.Path("/node/taints/edit").
 		HandlerFunc(impl.k8sCapacityRestHandler.EditNodeTaints).Methods("PUT")
+
+	k8sCapacityRouter.Path("/node/maintenance").
+		HandlerFunc(impl.k8sCapacityRestHandler.CreateNodeMaintenance).Methods("POST")
+
+	k8sCapacityRouter.Path("/node/maintenance").
+		Queries("clusterId", "{clusterId}").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetNodeMaintenanceList).Methods("GET")
+
+	k8sCapacityRouter.Path("/node/maintenance/{id}").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetNodeMaintenance).Methods("GET")
+
+	k8sCapacityRouter.Path("/node/maintenance/{id}/pause").
+		HandlerFunc(impl.k8sCapacityRestHandler.PauseNodeMaintenance).Methods("PUT")
+
+	k8sCapacityRouter.Path("/node/maintenance/{id}/resume").
+		HandlerFunc(impl.k8sCapacityRestHandler.ResumeNodeMaintenance).Methods("PUT")
+
+	k8sCapacityRouter.Path("/node/maintenance/{id}/abort").
+		HandlerFunc(impl.k8sCapacityRestHandler.AbortNodeMaintenance).Methods("PUT")
+
+	k8sCapacityRouter.Path("/cost/allocation").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetCostAllocation).Methods("GET")
+
+	k8sCapacityRouter.Path("/cost/idle").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetIdleCost).Methods("GET")
+
+	k8sCapacityRouter.Path("/cost/price-sheet").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetPriceSheets).Methods("GET")
+
+	k8sCapacityRouter.Path("/cost/price-sheet").
+		HandlerFunc(impl.k8sCapacityRestHandler.SavePriceSheet).Methods("PUT")
+
+	k8sCapacityRouter.Path("/cost/price-sheet/{clusterId}").
+		HandlerFunc(impl.k8sCapacityRestHandler.DeletePriceSheet).Methods("DELETE")
 }
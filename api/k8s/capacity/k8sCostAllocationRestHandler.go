@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package capacity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/k8s/costAllocation/bean"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// checkSuperAdmin allows cost reports and price sheets to super admins only, they span every cluster and team
+func (handler *K8sCapacityRestHandlerImpl) checkSuperAdmin(w http.ResponseWriter, r *http.Request, action string) (int32, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	if ok := handler.enforcer.Enforce(r.Header.Get("token"), casbin.ResourceGlobal, action, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
+
+// getReportRange returns the dates of the from and to query params, the last 30 days when not given
+func getReportRange(r *http.Request) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -29)
+	var err error
+	if v := r.URL.Query().Get("from"); len(v) > 0 {
+		if from, err = time.Parse(bean.DateLayout, v); err != nil {
+			return from, to, fmt.Errorf("invalid from %s, expected format %s", v, bean.DateLayout)
+		}
+	}
+	if v := r.URL.Query().Get("to"); len(v) > 0 {
+		if to, err = time.Parse(bean.DateLayout, v); err != nil {
+			return from, to, fmt.Errorf("invalid to %s, expected format %s", v, bean.DateLayout)
+		}
+	}
+	return from, to, nil
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetCostAllocation(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkSuperAdmin(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	from, to, err := getReportRange(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request := &bean.ReportRequest{
+		From:    from,
+		To:      to,
+		GroupBy: bean.GroupBy(r.URL.Query().Get("groupBy")),
+	}
+	if v := r.URL.Query().Get("clusterId"); len(v) > 0 {
+		request.ClusterId, err = strconv.Atoi(v)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	resp, err := handler.costAllocationService.GetAllocationReport(request)
+	if err != nil {
+		handler.logger.Errorw("error in getting cost allocation report", "err", err, "from", from, "to", to, "groupBy", request.GroupBy)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetIdleCost(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkSuperAdmin(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	from, to, err := getReportRange(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	resp, err := handler.costAllocationService.GetIdleCostReport(from, to)
+	if err != nil {
+		handler.logger.Errorw("error in getting idle cost report", "err", err, "from", from, "to", to)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetPriceSheets(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkSuperAdmin(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	resp, err := handler.costAllocationService.GetPriceSheets()
+	if err != nil {
+		handler.logger.Errorw("error in getting price sheets", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) SavePriceSheet(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkSuperAdmin(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	var request bean.PriceSheetDto
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request body", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation error", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.costAllocationService.SavePriceSheet(&request)
+	if err != nil {
+		handler.logger.Errorw("error in saving price sheet", "err", err, "req", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) DeletePriceSheet(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkSuperAdmin(w, r, casbin.ActionDelete)
+	if !ok {
+		return
+	}
+	clusterId, err := strconv.Atoi(mux.Vars(r)["clusterId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.costAllocationService.DeletePriceSheet(clusterId, userId)
+	if err != nil {
+		handler.logger.Errorw("error in deleting price sheet", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, clusterId, http.StatusOK)
+}
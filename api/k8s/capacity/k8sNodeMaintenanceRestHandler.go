@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package capacity
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance/bean"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+// checkAuthorisationForAllNodes checks the access of the user to every node of the cluster, a workflow spans many nodes
+func (handler *K8sCapacityRestHandlerImpl) checkAuthorisationForAllNodes(w http.ResponseWriter, token string, clusterId int, action string) bool {
+	authenticated, err := handler.clusterRbacService.CheckAuthorisationForNodeWithClusterId(token, clusterId, "", action)
+	if err != nil {
+		handler.logger.Errorw("error in checking rbac for cluster", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	if !authenticated {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (handler *K8sCapacityRestHandlerImpl) CreateNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.CreateWorkflowRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request body", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation error", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.checkAuthorisationForAllNodes(w, r.Header.Get("token"), request.ClusterId, casbin.ActionUpdate) {
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.nodeMaintenanceService.CreateWorkflow(r.Context(), &request)
+	if err != nil {
+		handler.logger.Errorw("error in creating node maintenance workflow", "err", err, "req", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetNodeMaintenanceList(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	clusterId, err := strconv.Atoi(r.URL.Query().Get("clusterId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.checkAuthorisationForAllNodes(w, r.Header.Get("token"), clusterId, casbin.ActionGet) {
+		return
+	}
+	resp, err := handler.nodeMaintenanceService.GetWorkflowsByClusterId(clusterId)
+	if err != nil {
+		handler.logger.Errorw("error in getting node maintenance workflows", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// getNodeMaintenance returns the workflow of the path after checking the access of the user to the nodes of its cluster
+func (handler *K8sCapacityRestHandlerImpl) getNodeMaintenance(w http.ResponseWriter, r *http.Request, action string) (*bean.WorkflowDto, int32, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return nil, 0, false
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, 0, false
+	}
+	workflow, err := handler.nodeMaintenanceService.GetWorkflow(id)
+	if err != nil {
+		handler.logger.Errorw("error in getting node maintenance workflow", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, 0, false
+	}
+	if !handler.checkAuthorisationForAllNodes(w, r.Header.Get("token"), workflow.ClusterId, action) {
+		return nil, 0, false
+	}
+	return workflow, userId, true
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	workflow, _, ok := handler.getNodeMaintenance(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	common.WriteJsonResp(w, nil, workflow, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) PauseNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	workflow, userId, ok := handler.getNodeMaintenance(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	if err := handler.nodeMaintenanceService.PauseWorkflow(workflow.Id, userId); err != nil {
+		handler.logger.Errorw("error in pausing node maintenance workflow", "err", err, "id", workflow.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "Node maintenance paused.", http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) ResumeNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	workflow, userId, ok := handler.getNodeMaintenance(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	if err := handler.nodeMaintenanceService.ResumeWorkflow(workflow.Id, userId); err != nil {
+		handler.logger.Errorw("error in resuming node maintenance workflow", "err", err, "id", workflow.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "Node maintenance resumed.", http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) AbortNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	workflow, userId, ok := handler.getNodeMaintenance(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	if err := handler.nodeMaintenanceService.AbortWorkflow(workflow.Id, userId); err != nil {
+		handler.logger.Errorw("error in aborting node maintenance workflow", "err", err, "id", workflow.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "Node maintenance aborted.", http.StatusOK)
+}
@@ -24,7 +24,11 @@ import (
 	"github.com/devtron-labs/devtron/pkg/k8s"
 	application2 "github.com/devtron-labs/devtron/pkg/k8s/application"
 	capacity2 "github.com/devtron-labs/devtron/pkg/k8s/capacity"
+	"github.com/devtron-labs/devtron/pkg/k8s/costAllocation"
 	"github.com/devtron-labs/devtron/pkg/k8s/informer"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceSearch"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceWatch"
 	"github.com/devtron-labs/devtron/pkg/terminal"
 	"github.com/google/wire"
 )
@@ -50,7 +54,11 @@ var K8sApplicationWireSet = wire.NewSet(
 	wire.Bind(new(capacity.K8sCapacityRestHandler), new(*capacity.K8sCapacityRestHandlerImpl)),
 	capacity2.NewK8sCapacityServiceImpl,
 	wire.Bind(new(capacity2.K8sCapacityService), new(*capacity2.K8sCapacityServiceImpl)),
+	nodeMaintenance.NodeMaintenanceWireSet,
+	costAllocation.CostAllocationWireSet,
 	informer.NewGlobalMapClusterNamespace,
 	informer.NewK8sInformerFactoryImpl,
 	wire.Bind(new(informer.K8sInformerFactory), new(*informer.K8sInformerFactoryImpl)),
+	resourceSearch.ResourceSearchWireSet,
+	resourceWatch.ResourceWatchWireSet,
 )
@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package pluginCatalog
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/plugin/catalog"
+	"github.com/devtron-labs/devtron/pkg/plugin/catalog/bean"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+)
+
+type PluginCatalogRestHandler interface {
+	SaveSource(w http.ResponseWriter, r *http.Request)
+	GetAllSources(w http.ResponseWriter, r *http.Request)
+	GetSource(w http.ResponseWriter, r *http.Request)
+	DeleteSource(w http.ResponseWriter, r *http.Request)
+	SyncSource(w http.ResponseWriter, r *http.Request)
+}
+
+type PluginCatalogRestHandlerImpl struct {
+	logger               *zap.SugaredLogger
+	userService          user.UserService
+	pluginCatalogService catalog.PluginCatalogService
+	enforcer             casbin.Enforcer
+	validator            *validator.Validate
+}
+
+func NewPluginCatalogRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	pluginCatalogService catalog.PluginCatalogService,
+	enforcer casbin.Enforcer,
+	validator *validator.Validate) *PluginCatalogRestHandlerImpl {
+	return &PluginCatalogRestHandlerImpl{
+		logger:               logger,
+		userService:          userService,
+		pluginCatalogService: pluginCatalogService,
+		enforcer:             enforcer,
+		validator:            validator,
+	}
+}
+
+func (handler *PluginCatalogRestHandlerImpl) SaveSource(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	var request bean.PluginCatalogSourceDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SaveSource", "err", err, "payload", r.Body)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SaveSource", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	var resp *bean.PluginCatalogSourceDto
+	if request.Id > 0 {
+		resp, err = handler.pluginCatalogService.UpdateSource(&request, userId)
+	} else {
+		resp, err = handler.pluginCatalogService.CreateSource(&request, userId)
+	}
+	if err != nil {
+		handler.logger.Errorw("service err, SaveSource", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *PluginCatalogRestHandlerImpl) GetAllSources(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	resp, err := handler.pluginCatalogService.GetAllSources()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAllSources", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *PluginCatalogRestHandlerImpl) GetSource(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	resp, err := handler.pluginCatalogService.GetSource(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetSource", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *PluginCatalogRestHandlerImpl) DeleteSource(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionDelete, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	err = handler.pluginCatalogService.DeleteSource(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteSource", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *PluginCatalogRestHandlerImpl) SyncSource(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	id, err := common.ExtractIntPathParam(w, r, "id")
+	if err != nil {
+		return
+	}
+	resp, err := handler.pluginCatalogService.SyncSource(r.Context(), id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, SyncSource", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package pluginCatalog
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type PluginCatalogRouter interface {
+	InitPluginCatalogRouter(router *mux.Router)
+}
+
+type PluginCatalogRouterImpl struct {
+	pluginCatalogRestHandler PluginCatalogRestHandler
+}
+
+func NewPluginCatalogRouterImpl(pluginCatalogRestHandler PluginCatalogRestHandler) *PluginCatalogRouterImpl {
+	return &PluginCatalogRouterImpl{pluginCatalogRestHandler: pluginCatalogRestHandler}
+}
+
+func (router *PluginCatalogRouterImpl) InitPluginCatalogRouter(catalogRouter *mux.Router) {
+	catalogRouter.Path("/source").HandlerFunc(router.pluginCatalogRestHandler.SaveSource).Methods("POST", "PUT")
+	catalogRouter.Path("/source").HandlerFunc(router.pluginCatalogRestHandler.GetAllSources).Methods("GET")
+	catalogRouter.Path("/source/{id}").HandlerFunc(router.pluginCatalogRestHandler.GetSource).Methods("GET")
+	catalogRouter.Path("/source/{id}").HandlerFunc(router.pluginCatalogRestHandler.DeleteSource).Methods("DELETE")
+	catalogRouter.Path("/source/{id}/sync").HandlerFunc(router.pluginCatalogRestHandler.SyncSource).Methods("POST")
+}
@@ -0,0 +1,202 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package restHandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/sql/repository/helper"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type BulkEditRestHandler interface {
+	PreviewBulkEdit(w http.ResponseWriter, r *http.Request)
+	GetBulkEdits(w http.ResponseWriter, r *http.Request)
+	GetBulkEdit(w http.ResponseWriter, r *http.Request)
+	ApplyBulkEdit(w http.ResponseWriter, r *http.Request)
+	ResumeBulkEdit(w http.ResponseWriter, r *http.Request)
+	RollbackBulkEdit(w http.ResponseWriter, r *http.Request)
+}
+
+type BulkEditRestHandlerImpl struct {
+	logger          *zap.SugaredLogger
+	userAuthService user.UserService
+	validator       *validator.Validate
+	bulkEditService bulkEdit.BulkEditService
+	enforcer        casbin.Enforcer
+	enforcerUtil    rbac.EnforcerUtil
+}
+
+func NewBulkEditRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService, validator *validator.Validate,
+	bulkEditService bulkEdit.BulkEditService, enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil) *BulkEditRestHandlerImpl {
+	return &BulkEditRestHandlerImpl{
+		logger:          logger,
+		userAuthService: userAuthService,
+		validator:       validator,
+		bulkEditService: bulkEditService,
+		enforcer:        enforcer,
+		enforcerUtil:    enforcerUtil,
+	}
+}
+
+func (handler *BulkEditRestHandlerImpl) PreviewBulkEdit(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.BulkEditRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if err = handler.validator.Struct(request); err != nil {
+		handler.logger.Errorw("validation err, PreviewBulkEdit", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, PreviewBulkEdit", "payload", request, "userId", userId)
+	res, err := handler.bulkEditService.Preview(&request, handler.getUpdateAuthChecker(r.Header.Get("token")))
+	if err != nil {
+		handler.logger.Errorw("service err, PreviewBulkEdit", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *BulkEditRestHandlerImpl) GetBulkEdits(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	res, err := handler.bulkEditService.GetBulkEdits()
+	if err != nil {
+		handler.logger.Errorw("service err, GetBulkEdits", "err", err, "userId", userId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *BulkEditRestHandlerImpl) GetBulkEdit(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, ok := handler.getBulkEditId(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.bulkEditService.GetBulkEdit(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetBulkEdit", "err", err, "id", id, "userId", userId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	appResourceObjects, envResourceObjects := handler.enforcerUtil.GetRbacObjectsForAllAppsAndEnvironments()
+	for _, target := range res.Targets {
+		if !handler.checkGetAuth(token, target.AppId, target.EnvId, appResourceObjects, envResourceObjects) {
+			common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+			return
+		}
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *BulkEditRestHandlerImpl) ApplyBulkEdit(w http.ResponseWriter, r *http.Request) {
+	handler.handleBulkEditAction(w, r, "ApplyBulkEdit", handler.bulkEditService.Apply)
+}
+
+func (handler *BulkEditRestHandlerImpl) ResumeBulkEdit(w http.ResponseWriter, r *http.Request) {
+	handler.handleBulkEditAction(w, r, "ResumeBulkEdit", handler.bulkEditService.Resume)
+}
+
+func (handler *BulkEditRestHandlerImpl) RollbackBulkEdit(w http.ResponseWriter, r *http.Request) {
+	handler.handleBulkEditAction(w, r, "RollbackBulkEdit", handler.bulkEditService.Rollback)
+}
+
+func (handler *BulkEditRestHandlerImpl) handleBulkEditAction(w http.ResponseWriter, r *http.Request, action string,
+	perform func(id int, userId int32, checkAuth bulkEdit.CheckAuth) (*bean.BulkEditDto, error)) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, ok := handler.getBulkEditId(w, r)
+	if !ok {
+		return
+	}
+	handler.logger.Infow("request, "+action, "id", id, "userId", userId)
+	res, err := perform(id, userId, handler.getUpdateAuthChecker(r.Header.Get("token")))
+	if err != nil {
+		handler.logger.Errorw("service err, "+action, "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *BulkEditRestHandlerImpl) getBulkEditId(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid bulk edit id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// getUpdateAuthChecker requires update access on the app and, for environment targets, on the app environment
+func (handler *BulkEditRestHandlerImpl) getUpdateAuthChecker(token string) bulkEdit.CheckAuth {
+	rbacObjects := handler.enforcerUtil.GetRbacObjectsForAllApps(helper.CustomApp)
+	return func(appId int, appName string, envId int) bool {
+		if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionUpdate, rbacObjects[appId]); !ok {
+			return false
+		}
+		if envId > 0 {
+			resourceName := handler.enforcerUtil.GetAppRBACByAppNameAndEnvId(appName, envId)
+			return handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionUpdate, resourceName)
+		}
+		return true
+	}
+}
+
+func (handler *BulkEditRestHandlerImpl) checkGetAuth(token string, appId int, envId int, appResourceObjects map[int]string, envResourceObjects map[string]string) bool {
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, appResourceObjects[appId]); !ok {
+		return false
+	}
+	if envId > 0 {
+		envResourceName := envResourceObjects[fmt.Sprintf("%d-%d", envId, appId)]
+		return handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionGet, envResourceName)
+	}
+	return true
+}
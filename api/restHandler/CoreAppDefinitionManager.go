@@ -0,0 +1,300 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package restHandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/app"
+	appAsCodeBean "github.com/devtron-labs/devtron/pkg/appAsCode/bean"
+	appAsCodeHelper "github.com/devtron-labs/devtron/pkg/appAsCode/helper"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	bean3 "github.com/devtron-labs/devtron/pkg/chart/bean"
+	"strings"
+)
+
+// the methods below implement appAsCode.AppDefinitionManager so that app-as-code sources create and update apps
+// through the same flows as the whole app api, syncs are run by devtron and are not limited by rbac
+
+func (handler CoreAppRestHandlerImpl) GetAppDefinition(ctx context.Context, appId int, enforce func(resource, action, object string) bool) (*appBean.AppDetail, error) {
+	appDetail, err, _ := handler.buildAppDetail(ctx, appId, enforce)
+	return appDetail, err
+}
+
+func (handler CoreAppRestHandlerImpl) CreateAppFromDefinition(ctx context.Context, definition *appBean.AppDetail, userId int32) (int, error) {
+	if err := handler.validator.Struct(definition); err != nil {
+		return 0, err
+	}
+	appId, err, _ := handler.createApp(ctx, definition, userId, appAsCodeHelper.AllowAllEnforce)
+	return appId, err
+}
+
+func (handler CoreAppRestHandlerImpl) ApplyAppDefinitionSection(ctx context.Context, appId int, section string, desired *appBean.AppDetail, current *appBean.AppDetail, userId int32) ([]string, error) {
+	handler.logger.Infow("applying app definition section", "appId", appId, "section", section)
+	switch {
+	case section == appAsCodeBean.SectionMetadata:
+		return nil, handler.applyAppMetadata(appId, desired.Metadata, userId)
+	case section == appAsCodeBean.SectionGitMaterials:
+		return handler.applyGitMaterials(appId, desired.GitMaterials, userId)
+	case section == appAsCodeBean.SectionDockerConfig:
+		return handler.applyDockerConfig(appId, desired.DockerConfig, current.DockerConfig, userId)
+	case section == appAsCodeBean.SectionGlobalDeploymentTemplate:
+		return handler.applyDeploymentTemplate(ctx, appId, desired.GlobalDeploymentTemplate, userId)
+	case section == appAsCodeBean.SectionGlobalConfigMaps:
+		skipped := getRemovedNames("config map", getConfigMapNames(desired.GlobalConfigMaps), getConfigMapNames(current.GlobalConfigMaps))
+		if len(desired.GlobalConfigMaps) > 0 {
+			if err, _ := handler.createGlobalConfigMaps(appId, userId, desired.GlobalConfigMaps); err != nil {
+				return nil, err
+			}
+		}
+		return skipped, nil
+	case section == appAsCodeBean.SectionGlobalSecrets:
+		skipped := getRemovedNames("secret", getSecretNames(desired.GlobalSecrets), getSecretNames(current.GlobalSecrets))
+		if len(desired.GlobalSecrets) > 0 {
+			if err, _ := handler.createGlobalSecrets(appId, userId, desired.GlobalSecrets); err != nil {
+				return nil, err
+			}
+		}
+		return skipped, nil
+	case strings.HasPrefix(section, appAsCodeBean.SectionWorkflowPrefix):
+		return handler.applyWorkflow(ctx, appId, strings.TrimPrefix(section, appAsCodeBean.SectionWorkflowPrefix), desired, current, userId)
+	case strings.HasPrefix(section, appAsCodeBean.SectionEnvironmentOverridePrefix):
+		return handler.applyEnvironmentOverride(ctx, appId, strings.TrimPrefix(section, appAsCodeBean.SectionEnvironmentOverridePrefix), desired, current, userId)
+	}
+	return nil, fmt.Errorf("unknown app definition section %q", section)
+}
+
+func (handler CoreAppRestHandlerImpl) applyAppMetadata(appId int, appMetadata *appBean.AppMetadata, userId int32) error {
+	if err := handler.validator.Struct(appMetadata); err != nil {
+		return err
+	}
+	team, err := handler.teamReadService.FindByTeamName(appMetadata.ProjectName)
+	if err != nil {
+		handler.logger.Errorw("error in getting project of app definition", "projectName", appMetadata.ProjectName, "err", err)
+		return err
+	}
+	appMetaInfo, err := handler.appCrudOperationService.GetAppMetaInfo(appId, app.ZERO_INSTALLED_APP_ID, app.ZERO_ENVIRONMENT_ID)
+	if err != nil {
+		return err
+	}
+	updateAppRequest := &bean.CreateAppDTO{
+		Id:          appId,
+		AppName:     appMetaInfo.AppName,
+		Description: appMetaInfo.Description,
+		TeamId:      team.Id,
+		UserId:      userId,
+	}
+	for _, label := range appMetadata.Labels {
+		updateAppRequest.AppLabels = append(updateAppRequest.AppLabels, &bean.Label{Key: label.Key, Value: label.Value, Propagate: label.Propagate})
+	}
+	_, err = handler.appCrudOperationService.UpdateApp(updateAppRequest)
+	if err != nil {
+		handler.logger.Errorw("error in updating app metadata from app definition", "appId", appId, "err", err)
+	}
+	return err
+}
+
+// applyGitMaterials adds new materials and updates existing ones, materials are matched by checkout path
+func (handler CoreAppRestHandlerImpl) applyGitMaterials(appId int, gitMaterials []*appBean.GitMaterial, userId int32) ([]string, error) {
+	currentMaterials := make(map[string]*bean.GitMaterial)
+	for _, material := range handler.pipelineBuilder.GetMaterialsForAppId(appId) {
+		currentMaterials[material.CheckoutPath] = material
+	}
+	newMaterials := make([]*appBean.GitMaterial, 0)
+	for _, material := range gitMaterials {
+		currentMaterial, ok := currentMaterials[material.CheckoutPath]
+		if !ok {
+			newMaterials = append(newMaterials, material)
+			continue
+		}
+		delete(currentMaterials, material.CheckoutPath)
+		gitProvider, err := handler.gitProviderReadService.FindByUrl(material.GitProviderUrl)
+		if err != nil {
+			handler.logger.Errorw("error in getting git provider of material", "gitProviderUrl", material.GitProviderUrl, "err", err)
+			return nil, err
+		}
+		if currentMaterial.Url == material.GitRepoUrl && currentMaterial.GitProviderId == gitProvider.Id && currentMaterial.FetchSubmodules == material.FetchSubmodules {
+			continue
+		}
+		updatedMaterial := *currentMaterial
+		updatedMaterial.Url = material.GitRepoUrl
+		updatedMaterial.GitProviderId = gitProvider.Id
+		updatedMaterial.FetchSubmodules = material.FetchSubmodules
+		_, err = handler.pipelineBuilder.UpdateMaterialsForApp(&bean.UpdateMaterialDTO{AppId: appId, Material: &updatedMaterial, UserId: userId})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(newMaterials) > 0 {
+		if err, _ := handler.createGitMaterials(appId, newMaterials, userId); err != nil {
+			return nil, err
+		}
+	}
+	skipped := make([]string, 0)
+	for checkoutPath := range currentMaterials {
+		skipped = append(skipped, fmt.Sprintf("material with checkout path %s is not removed, removing materials is not supported", checkoutPath))
+	}
+	return skipped, nil
+}
+
+func (handler CoreAppRestHandlerImpl) applyDockerConfig(appId int, dockerConfig *appBean.DockerConfig, currentDockerConfig *appBean.DockerConfig, userId int32) ([]string, error) {
+	if dockerConfig == nil {
+		return []string{"build configuration is not removed, removing it is not supported"}, nil
+	} else if currentDockerConfig == nil {
+		err, _ := handler.createDockerConfig(appId, dockerConfig, userId)
+		return nil, err
+	}
+	setCiBuildConfigFromDockerBuildConfig(dockerConfig)
+	if dockerConfig.CiBuildConfig == nil {
+		return nil, fmt.Errorf("ciBuildConfig is required in dockerConfig")
+	}
+	ciConfig, err := handler.pipelineBuilder.GetCiPipeline(appId)
+	if err != nil {
+		handler.logger.Errorw("error in getting ci config of app", "appId", appId, "err", err)
+		return nil, err
+	}
+	gitMaterial, err := handler.gitMaterialReadService.FindByAppIdAndCheckoutPath(appId, dockerConfig.CheckoutPath)
+	if err != nil {
+		handler.logger.Errorw("error in getting material of build configuration", "appId", appId, "checkoutPath", dockerConfig.CheckoutPath, "err", err)
+		return nil, err
+	}
+	dockerConfig.CiBuildConfig.GitMaterialId = gitMaterial.Id
+	ciConfig.DockerRegistry = dockerConfig.DockerRegistry
+	ciConfig.DockerRepository = dockerConfig.DockerRepository
+	ciConfig.CiBuildConfig = dockerConfig.CiBuildConfig
+	ciConfig.UserId = userId
+	_, err = handler.pipelineBuilder.UpdateCiTemplate(ciConfig)
+	if err != nil {
+		handler.logger.Errorw("error in updating ci template from app definition", "appId", appId, "err", err)
+	}
+	return nil, err
+}
+
+func (handler CoreAppRestHandlerImpl) applyDeploymentTemplate(ctx context.Context, appId int, deploymentTemplate *appBean.DeploymentTemplate, userId int32) ([]string, error) {
+	if deploymentTemplate == nil {
+		return []string{"deployment template is not removed, removing it is not supported"}, nil
+	}
+	latestChart, err := handler.chartRepo.FindLatestChartForAppByAppId(appId)
+	if util.IsErrNoRows(err) {
+		err, _ = handler.createDeploymentTemplate(ctx, appId, deploymentTemplate, userId)
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+	if latestChart.ChartRefId != deploymentTemplate.ChartRefId {
+		return []string{fmt.Sprintf("chart ref %d is not changed to %d, charts of apps are changed with the chart ref migration", latestChart.ChartRefId, deploymentTemplate.ChartRefId)}, nil
+	}
+	template, err := json.Marshal(deploymentTemplate.Template)
+	if err != nil {
+		return nil, err
+	}
+	_, err = handler.chartService.UpdateAppOverride(ctx, &bean3.TemplateRequest{
+		Id:                  latestChart.Id,
+		AppId:               appId,
+		ChartRefId:          latestChart.ChartRefId,
+		ValuesOverride:      template,
+		IsAppMetricsEnabled: deploymentTemplate.ShowAppMetrics,
+		IsBasicViewLocked:   deploymentTemplate.IsBasicViewLocked,
+		CurrentViewEditor:   deploymentTemplate.CurrentViewEditor,
+		UserId:              userId,
+	})
+	if err != nil {
+		handler.logger.Errorw("error in updating deployment template from app definition", "appId", appId, "err", err)
+	}
+	return nil, err
+}
+
+// applyWorkflow creates workflows new to the app, pipelines of existing workflows are not changed
+func (handler CoreAppRestHandlerImpl) applyWorkflow(ctx context.Context, appId int, workflowName string, desired *appBean.AppDetail, current *appBean.AppDetail, userId int32) ([]string, error) {
+	workflow, currentWorkflow := findAppWorkflow(desired.AppWorkflows, workflowName), findAppWorkflow(current.AppWorkflows, workflowName)
+	if workflow == nil {
+		return []string{fmt.Sprintf("workflow %s is not removed, removing workflows is not supported", workflowName)}, nil
+	} else if currentWorkflow != nil {
+		return []string{fmt.Sprintf("changes to the existing workflow %s are not applied, its pipelines have to be changed from devtron", workflowName)}, nil
+	}
+	workflows := []*appBean.AppWorkflow{workflow}
+	err, _ := handler.ValidateAppWorkflowRequest(&appBean.AppWorkflowCloneDto{AppId: appId, AppName: desired.Metadata.AppName, AppWorkflows: workflows}, appAsCodeHelper.AllowAllEnforce)
+	if err != nil {
+		return nil, err
+	}
+	err, _ = handler.createWorkflows(ctx, appId, userId, workflows)
+	return nil, err
+}
+
+func (handler CoreAppRestHandlerImpl) applyEnvironmentOverride(ctx context.Context, appId int, envName string, desired *appBean.AppDetail, current *appBean.AppDetail, userId int32) ([]string, error) {
+	override, currentOverride := desired.EnvironmentOverrides[envName], current.EnvironmentOverrides[envName]
+	if override == nil {
+		return []string{fmt.Sprintf("overrides of environment %s are not removed, removing overrides is not supported", envName)}, nil
+	}
+	environmentOverrides := map[string]*appBean.EnvironmentOverride{envName: override}
+	err, _ := handler.ValidateAppWorkflowRequest(&appBean.AppWorkflowCloneDto{AppId: appId, AppName: desired.Metadata.AppName, EnvironmentOverrides: environmentOverrides}, appAsCodeHelper.AllowAllEnforce)
+	if err != nil {
+		return nil, err
+	}
+	skipped := make([]string, 0)
+	if currentOverride != nil {
+		if currentOverride.DeploymentTemplate != nil && currentOverride.DeploymentTemplate.IsOverride && (override.DeploymentTemplate == nil || !override.DeploymentTemplate.IsOverride) {
+			skipped = append(skipped, "deployment template override is not removed, removing it is not supported")
+		}
+		skipped = append(skipped, getRemovedNames("config map", getConfigMapNames(override.ConfigMaps), getConfigMapNames(currentOverride.ConfigMaps))...)
+		skipped = append(skipped, getRemovedNames("secret", getSecretNames(override.Secrets), getSecretNames(currentOverride.Secrets))...)
+	}
+	err, _ = handler.createEnvOverrides(ctx, appId, userId, environmentOverrides)
+	return skipped, err
+}
+
+func findAppWorkflow(workflows []*appBean.AppWorkflow, name string) *appBean.AppWorkflow {
+	for _, workflow := range workflows {
+		if workflow.Name == name {
+			return workflow
+		}
+	}
+	return nil
+}
+
+func getConfigMapNames(configMaps []*appBean.ConfigMap) []string {
+	names := make([]string, 0, len(configMaps))
+	for _, configMap := range configMaps {
+		names = append(names, configMap.Name)
+	}
+	return names
+}
+
+func getSecretNames(secrets []*appBean.Secret) []string {
+	names := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		names = append(names, secret.Name)
+	}
+	return names
+}
+
+func getRemovedNames(kind string, desiredNames, currentNames []string) []string {
+	desired := make(map[string]bool, len(desiredNames))
+	for _, name := range desiredNames {
+		desired[name] = true
+	}
+	skipped := make([]string, 0)
+	for _, name := range currentNames {
+		if !desired[name] {
+			skipped = append(skipped, fmt.Sprintf("%s %s is not removed, removing it is not supported", kind, name))
+		}
+	}
+	return skipped
+}
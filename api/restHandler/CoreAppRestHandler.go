@@ -146,6 +146,16 @@ func NewCoreAppRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.U
 	return handler
 }
 
+// rbacEnforceFunc checks access of the caller on a casbin resource object,
+// flows run by devtron itself (like app-as-code syncs) use appAsCodeHelper.AllowAllEnforce
+type rbacEnforceFunc func(resource, action, object string) bool
+
+func (handler CoreAppRestHandlerImpl) getTokenEnforceFunc(token string) rbacEnforceFunc {
+	return func(resource, action, object string) bool {
+		return handler.enforcer.Enforce(token, resource, action, object)
+	}
+}
+
 func (handler CoreAppRestHandlerImpl) GetAppAllDetail(w http.ResponseWriter, r *http.Request) {
 
 	userId, err := handler.userAuthService.GetLoggedInUser(r)
@@ -173,36 +183,41 @@ func (handler CoreAppRestHandlerImpl) GetAppAllDetail(w http.ResponseWriter, r *
 	//rbac implementation ends here for app
 
 	handler.logger.Debugw("Getting app detail v2", "appId", appId)
+	appDetail, err, statusCode := handler.buildAppDetail(r.Context(), appId, handler.getTokenEnforceFunc(token))
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, statusCode)
+		return
+	}
+	common.WriteJsonResp(w, nil, appDetail, http.StatusOK)
+}
 
+// buildAppDetail builds the whole app payload, access to every environment override is checked with enforce
+func (handler CoreAppRestHandlerImpl) buildAppDetail(ctx context.Context, appId int, enforce rbacEnforceFunc) (*appBean.AppDetail, error, int) {
 	//get/build app metadata starts
 	appMetadataResp, err, statusCode := handler.buildAppMetadata(appId)
 	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
+		return nil, err, statusCode
 	}
 	//get/build app metadata ends
 
 	//get/build git materials starts
 	gitMaterialsResp, err, statusCode := handler.buildAppGitMaterials(appId)
 	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
+		return nil, err, statusCode
 	}
 	//get/build git materials ends
 
 	//get/build docker config starts
 	dockerConfig, err, statusCode := handler.buildDockerConfig(appId)
 	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
+		return nil, err, statusCode
 	}
 	//get/build docker config ends
 
 	//get/build global deployment template starts
 	globalDeploymentTemplateResp, err, statusCode := handler.buildAppDeploymentTemplate(appId)
 	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
+		return nil, err, statusCode
 	}
 	//get/build global deployment template ends
 
@@ -211,36 +226,32 @@ func (handler CoreAppRestHandlerImpl) GetAppAllDetail(w http.ResponseWriter, r *
 	wfCloneRequest := &appWorkflowBean.WorkflowCloneRequest{AppId: appId}
 	appWorkflows, err, statusCode := handler.buildAppWorkflows(wfCloneRequest)
 	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
+		return nil, err, statusCode
 	}
 	//get/build app workflows ends
 
 	//get/build global config maps starts
 	globalConfigMapsResp, err, statusCode := handler.buildAppGlobalConfigMaps(appId)
 	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
+		return nil, err, statusCode
 	}
 	//get/build global config maps ends
 
 	//get/build global secrets starts
 	globalSecretsResp, err, statusCode := handler.buildAppGlobalSecrets(appId)
 	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
+		return nil, err, statusCode
 	}
 	//get/build global secrets ends
 
 	//get/build environment override starts
-	environmentOverrides, err, statusCode := handler.buildEnvironmentOverrides(r.Context(), appId, token)
+	environmentOverrides, err, statusCode := handler.buildEnvironmentOverrides(ctx, appId, enforce)
 	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
+		return nil, err, statusCode
 	}
 	//get/build environment override ends
 
-	//build full object for response
+	//build full object
 	appDetail := &appBean.AppDetail{
 		Metadata:                 appMetadataResp,
 		GitMaterials:             gitMaterialsResp,
@@ -251,9 +262,7 @@ func (handler CoreAppRestHandlerImpl) GetAppAllDetail(w http.ResponseWriter, r *
 		GlobalSecrets:            globalSecretsResp,
 		EnvironmentOverrides:     environmentOverrides,
 	}
-	//end
-
-	common.WriteJsonResp(w, nil, appDetail, http.StatusOK)
+	return appDetail, nil, http.StatusOK
 }
 
 func (handler CoreAppRestHandlerImpl) CreateApp(w http.ResponseWriter, r *http.Request) {
@@ -304,24 +313,34 @@ func (handler CoreAppRestHandlerImpl) CreateApp(w http.ResponseWriter, r *http.R
 
 	handler.logger.Infow("creating app v2", "createAppRequest", createAppRequest)
 
+	_, err, statusCode := handler.createApp(ctx, &createAppRequest, userId, handler.getTokenEnforceFunc(token))
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, statusCode)
+		return
+	}
+
+	common.WriteJsonResp(w, nil, APP_CREATE_SUCCESSFUL_RESP, http.StatusOK)
+}
+
+// createApp validates the payload against the environments allowed by enforce and creates all components of the app,
+// the app is deleted again if any component fails to be created
+func (handler CoreAppRestHandlerImpl) createApp(ctx context.Context, request *appBean.AppDetail, userId int32, enforce rbacEnforceFunc) (int, error, int) {
 	// validate payload starts
 	createAppWorkflowReq := appBean.AppWorkflowCloneDto{
-		AppName:              createAppRequest.Metadata.AppName,
-		AppWorkflows:         createAppRequest.AppWorkflows,
-		EnvironmentOverrides: createAppRequest.EnvironmentOverrides,
+		AppName:              request.Metadata.AppName,
+		AppWorkflows:         request.AppWorkflows,
+		EnvironmentOverrides: request.EnvironmentOverrides,
 	}
-	err, statusCode := handler.ValidateAppWorkflowRequest(&createAppWorkflowReq, token)
+	err, statusCode := handler.ValidateAppWorkflowRequest(&createAppWorkflowReq, enforce)
 	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
+		return 0, err, statusCode
 	}
 	// validate payload ends
 
 	//creating blank app starts
-	createBlankAppResp, err, statusCode := handler.createBlankApp(createAppRequest.Metadata, userId)
+	createBlankAppResp, err, statusCode := handler.createBlankApp(request.Metadata, userId)
 	if err != nil {
-		common.WriteJsonResp(w, err, nil, statusCode)
-		return
+		return 0, err, statusCode
 	}
 	//creating blank app ends
 
@@ -331,111 +350,104 @@ func (handler CoreAppRestHandlerImpl) CreateApp(w http.ResponseWriter, r *http.R
 	var errResp *multierror.Error
 
 	//creating git material starts
-	if createAppRequest.GitMaterials != nil {
-		err, statusCode = handler.createGitMaterials(appId, createAppRequest.GitMaterials, userId)
+	if request.GitMaterials != nil {
+		err, statusCode = handler.createGitMaterials(appId, request.GitMaterials, userId)
 		if err != nil {
 			errResp = multierror.Append(errResp, err)
 			errInAppDelete := handler.deleteApp(ctx, appId, userId)
 			if errInAppDelete != nil {
 				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
 			}
-			common.WriteJsonResp(w, errResp, nil, statusCode)
-			return
+			return 0, errResp, statusCode
 		}
 	}
 	//creating git material ends
 
 	//creating docker config
-	if createAppRequest.DockerConfig != nil {
-		err, statusCode = handler.createDockerConfig(appId, createAppRequest.DockerConfig, userId)
+	if request.DockerConfig != nil {
+		err, statusCode = handler.createDockerConfig(appId, request.DockerConfig, userId)
 		if err != nil {
 			errResp = multierror.Append(errResp, err)
 			errInAppDelete := handler.deleteApp(ctx, appId, userId)
 			if errInAppDelete != nil {
 				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
 			}
-			common.WriteJsonResp(w, errResp, nil, statusCode)
-			return
+			return 0, errResp, statusCode
 		}
 	}
 	//creating docker config ends
 
 	//creating deployment template starts
-	if createAppRequest.GlobalDeploymentTemplate != nil {
-		err, statusCode = handler.createDeploymentTemplate(ctx, appId, createAppRequest.GlobalDeploymentTemplate, userId)
+	if request.GlobalDeploymentTemplate != nil {
+		err, statusCode = handler.createDeploymentTemplate(ctx, appId, request.GlobalDeploymentTemplate, userId)
 		if err != nil {
 			errResp = multierror.Append(errResp, err)
 			errInAppDelete := handler.deleteApp(ctx, appId, userId)
 			if errInAppDelete != nil {
 				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
 			}
-			common.WriteJsonResp(w, errResp, nil, statusCode)
-			return
+			return 0, errResp, statusCode
 		}
 	}
 	//creating deployment template ends
 
 	//creating global configMaps starts
-	if createAppRequest.GlobalConfigMaps != nil {
-		err, statusCode = handler.createGlobalConfigMaps(appId, userId, createAppRequest.GlobalConfigMaps)
+	if request.GlobalConfigMaps != nil {
+		err, statusCode = handler.createGlobalConfigMaps(appId, userId, request.GlobalConfigMaps)
 		if err != nil {
 			errResp = multierror.Append(errResp, err)
 			errInAppDelete := handler.deleteApp(ctx, appId, userId)
 			if errInAppDelete != nil {
 				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
 			}
-			common.WriteJsonResp(w, errResp, nil, statusCode)
-			return
+			return 0, errResp, statusCode
 		}
 	}
 	//creating global configMaps ends
 
 	//creating global secrets starts
-	if createAppRequest.GlobalSecrets != nil {
-		err, statusCode = handler.createGlobalSecrets(appId, userId, createAppRequest.GlobalSecrets)
+	if request.GlobalSecrets != nil {
+		err, statusCode = handler.createGlobalSecrets(appId, userId, request.GlobalSecrets)
 		if err != nil {
 			errResp = multierror.Append(errResp, err)
 			errInAppDelete := handler.deleteApp(ctx, appId, userId)
 			if errInAppDelete != nil {
 				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
 			}
-			common.WriteJsonResp(w, errResp, nil, statusCode)
-			return
+			return 0, errResp, statusCode
 		}
 	}
 	//creating global secrets ends
 
 	//creating workflow starts
-	if createAppRequest.AppWorkflows != nil {
-		err, statusCode = handler.createWorkflows(ctx, appId, userId, createAppRequest.AppWorkflows)
+	if request.AppWorkflows != nil {
+		err, statusCode = handler.createWorkflows(ctx, appId, userId, request.AppWorkflows)
 		if err != nil {
 			errResp = multierror.Append(errResp, err)
 			errInAppDelete := handler.deleteApp(ctx, appId, userId)
 			if errInAppDelete != nil {
 				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
 			}
-			common.WriteJsonResp(w, errResp, nil, statusCode)
-			return
+			return 0, errResp, statusCode
 		}
 	}
 	//creating workflow ends
 
 	//creating environment override starts
-	if createAppRequest.EnvironmentOverrides != nil {
-		err, statusCode = handler.createEnvOverrides(ctx, appId, userId, createAppRequest.EnvironmentOverrides)
+	if request.EnvironmentOverrides != nil {
+		err, statusCode = handler.createEnvOverrides(ctx, appId, userId, request.EnvironmentOverrides)
 		if err != nil {
 			errResp = multierror.Append(errResp, err)
 			errInAppDelete := handler.deleteApp(ctx, appId, userId)
 			if errInAppDelete != nil {
 				errResp = multierror.Append(errResp, fmt.Errorf("%s : %w", APP_DELETE_FAILED_RESP, errInAppDelete))
 			}
-			common.WriteJsonResp(w, errResp, nil, statusCode)
-			return
+			return 0, errResp, statusCode
 		}
 	}
 	//creating environment override ends
 
-	common.WriteJsonResp(w, nil, APP_CREATE_SUCCESSFUL_RESP, http.StatusOK)
+	return appId, nil, http.StatusOK
 }
 
 //GetApp related methods starts
@@ -1064,7 +1076,7 @@ func (handler CoreAppRestHandlerImpl) buildAppSecrets(appId int, envId int, secr
 }
 
 // get/build environment overrides
-func (handler CoreAppRestHandlerImpl) buildEnvironmentOverrides(ctx context.Context, appId int, token string) (map[string]*appBean.EnvironmentOverride, error, int) {
+func (handler CoreAppRestHandlerImpl) buildEnvironmentOverrides(ctx context.Context, appId int, enforce rbacEnforceFunc) (map[string]*appBean.EnvironmentOverride, error, int) {
 	handler.logger.Debugw("Getting app detail - env override", "appId", appId)
 
 	appEnvironments, err := handler.appListingService.FetchOtherEnvironment(ctx, appId)
@@ -1076,7 +1088,7 @@ func (handler CoreAppRestHandlerImpl) buildEnvironmentOverrides(ctx context.Cont
 	environmentOverrides := make(map[string]*appBean.EnvironmentOverride)
 	if len(appEnvironments) > 0 {
 		for _, appEnvironment := range appEnvironments {
-			environmentOverride, err, _ := handler.buildEnvironmentOverride(appId, appEnvironment.EnvironmentId, token)
+			environmentOverride, err, _ := handler.buildEnvironmentOverride(appId, appEnvironment.EnvironmentId, enforce)
 			if err != nil {
 				handler.logger.Errorw("service err", "err", err)
 				return nil, err, http.StatusInternalServerError
@@ -1093,12 +1105,12 @@ func (handler CoreAppRestHandlerImpl) buildEnvironmentOverrides(ctx context.Cont
 }
 
 // get/build environment overrides
-func (handler CoreAppRestHandlerImpl) buildEnvironmentOverride(appId int, environmentId int, token string) (map[string]*appBean.EnvironmentOverride, error, int) {
+func (handler CoreAppRestHandlerImpl) buildEnvironmentOverride(appId int, environmentId int, enforce rbacEnforceFunc) (map[string]*appBean.EnvironmentOverride, error, int) {
 	handler.logger.Debugw("Getting app detail - env override", "appId", appId)
 	environmentOverrides := make(map[string]*appBean.EnvironmentOverride)
 	//check RBAC for environment
 	object := handler.enforcerUtil.GetEnvRBACNameByAppId(appId, environmentId)
-	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionUpdate, object); !ok {
+	if ok := enforce(casbin.ResourceEnvironment, casbin.ActionUpdate, object); !ok {
 		handler.logger.Errorw("Unauthorized User for env update action", "appId", appId, "envId", environmentId)
 		return nil, fmt.Errorf("unauthorized user"), http.StatusForbidden
 	}
@@ -1326,20 +1338,7 @@ func (handler CoreAppRestHandlerImpl) createGitMaterials(appId int, gitMaterials
 // create docker config
 func (handler CoreAppRestHandlerImpl) createDockerConfig(appId int, dockerConfig *appBean.DockerConfig, userId int32) (error, int) {
 	handler.logger.Infow("Create App - creating docker config", "appId", appId, "DockerConfig", dockerConfig)
-	dockerBuildConfig := dockerConfig.DockerBuildConfig
-	if dockerBuildConfig != nil {
-		dockerConfig.CheckoutPath = dockerBuildConfig.GitCheckoutPath
-		dockerConfig.CiBuildConfig = &pipelineBean.CiBuildConfigBean{
-			CiBuildType: pipelineBean.SELF_DOCKERFILE_BUILD_TYPE,
-			DockerBuildConfig: &pipelineBean.DockerBuildConfig{
-				DockerfilePath:     dockerBuildConfig.DockerfileRelativePath,
-				DockerBuildOptions: dockerBuildConfig.DockerBuildOptions,
-				Args:               dockerBuildConfig.Args,
-				TargetPlatform:     dockerBuildConfig.TargetPlatform,
-				BuildContext:       dockerBuildConfig.BuildContext,
-			},
-		}
-	}
+	setCiBuildConfigFromDockerBuildConfig(dockerConfig)
 	createDockerConfigRequest := &bean.CiConfigRequest{
 		AppId:            appId,
 		UserId:           userId,
@@ -1367,6 +1366,24 @@ func (handler CoreAppRestHandlerImpl) createDockerConfig(appId int, dockerConfig
 	return nil, http.StatusOK
 }
 
+// setCiBuildConfigFromDockerBuildConfig converts the older dockerBuildConfig of the payload into ciBuildConfig
+func setCiBuildConfigFromDockerBuildConfig(dockerConfig *appBean.DockerConfig) {
+	dockerBuildConfig := dockerConfig.DockerBuildConfig
+	if dockerBuildConfig != nil {
+		dockerConfig.CheckoutPath = dockerBuildConfig.GitCheckoutPath
+		dockerConfig.CiBuildConfig = &pipelineBean.CiBuildConfigBean{
+			CiBuildType: pipelineBean.SELF_DOCKERFILE_BUILD_TYPE,
+			DockerBuildConfig: &pipelineBean.DockerBuildConfig{
+				DockerfilePath:     dockerBuildConfig.DockerfileRelativePath,
+				DockerBuildOptions: dockerBuildConfig.DockerBuildOptions,
+				Args:               dockerBuildConfig.Args,
+				TargetPlatform:     dockerBuildConfig.TargetPlatform,
+				BuildContext:       dockerBuildConfig.BuildContext,
+			},
+		}
+	}
+}
+
 // create global template
 func (handler CoreAppRestHandlerImpl) createDeploymentTemplate(ctx context.Context, appId int, deploymentTemplate *appBean.DeploymentTemplate, userId int32) (error, int) {
 	handler.logger.Infow("Create App - creating deployment template", "appId", appId, "DeploymentStrategy", deploymentTemplate)
@@ -2117,7 +2134,7 @@ func convertCdDeploymentStrategies(deploymentStrategies []*appBean.DeploymentStr
 	return convertedStrategies, nil
 }
 
-func (handler CoreAppRestHandlerImpl) validateCdPipelines(cdPipelines []*appBean.CdPipelineDetails, appName, token string) (error, int) {
+func (handler CoreAppRestHandlerImpl) validateCdPipelines(cdPipelines []*appBean.CdPipelineDetails, appName string, enforce rbacEnforceFunc) (error, int) {
 	for _, cdPipeline := range cdPipelines {
 		envName := cdPipeline.EnvironmentName
 		envModel, err := handler.environmentRepository.FindByName(envName)
@@ -2129,7 +2146,7 @@ func (handler CoreAppRestHandlerImpl) validateCdPipelines(cdPipelines []*appBean
 		}
 		// validation RBAC starts
 		object := handler.enforcerUtil.GetAppRBACByAppNameAndEnvId(appName, envModel.Id)
-		if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionCreate, object); !ok {
+		if ok := enforce(casbin.ResourceEnvironment, casbin.ActionCreate, object); !ok {
 			return fmt.Errorf("unauthorized user for the environment %s", envName), http.StatusForbidden
 		}
 		// validation RBAC ends
@@ -2145,7 +2162,7 @@ func (handler CoreAppRestHandlerImpl) validateCdPipelines(cdPipelines []*appBean
 	return nil, http.StatusOK
 }
 
-func (handler CoreAppRestHandlerImpl) ValidateAppWorkflowRequest(createAppWorkflowRequest *appBean.AppWorkflowCloneDto, token string) (error, int) {
+func (handler CoreAppRestHandlerImpl) ValidateAppWorkflowRequest(createAppWorkflowRequest *appBean.AppWorkflowCloneDto, enforce rbacEnforceFunc) (error, int) {
 	// validation for app workflow request
 	if createAppWorkflowRequest.AppWorkflows != nil {
 		for _, workflow := range createAppWorkflowRequest.AppWorkflows {
@@ -2210,7 +2227,7 @@ func (handler CoreAppRestHandlerImpl) ValidateAppWorkflowRequest(createAppWorkfl
 			}
 			// validate environment name and rbac object of payload
 			if workflow.CdPipelines != nil {
-				err, statusCode := handler.validateCdPipelines(workflow.CdPipelines, createAppWorkflowRequest.AppName, token)
+				err, statusCode := handler.validateCdPipelines(workflow.CdPipelines, createAppWorkflowRequest.AppName, enforce)
 				if err != nil {
 					return err, statusCode
 				}
@@ -2230,7 +2247,7 @@ func (handler CoreAppRestHandlerImpl) ValidateAppWorkflowRequest(createAppWorkfl
 			}
 			// validate RBAC starts
 			object := handler.enforcerUtil.GetAppRBACByAppNameAndEnvId(createAppWorkflowRequest.AppName, envModel.Id)
-			if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionUpdate, object); !ok {
+			if ok := enforce(casbin.ResourceEnvironment, casbin.ActionUpdate, object); !ok {
 				return fmt.Errorf("unauthorized user for the environment '%s'", envName), http.StatusForbidden
 			}
 			// validate RBAC ends
@@ -2281,7 +2298,7 @@ func (handler CoreAppRestHandlerImpl) CreateAppWorkflow(w http.ResponseWriter, r
 	//rbac ends
 
 	// validate payload starts
-	err, statusCode := handler.ValidateAppWorkflowRequest(&createAppRequest, token)
+	err, statusCode := handler.ValidateAppWorkflowRequest(&createAppRequest, handler.getTokenEnforceFunc(token))
 	if err != nil {
 		common.WriteJsonResp(w, err, nil, statusCode)
 		return
@@ -2354,7 +2371,7 @@ func (handler CoreAppRestHandlerImpl) GetAppWorkflow(w http.ResponseWriter, r *h
 	//get/build app workflows ends
 
 	//get/build environment override starts
-	environmentOverrides, err, statusCode := handler.buildEnvironmentOverrides(r.Context(), appId, token)
+	environmentOverrides, err, statusCode := handler.buildEnvironmentOverrides(r.Context(), appId, handler.getTokenEnforceFunc(token))
 	if err != nil {
 		common.WriteJsonResp(w, err, nil, statusCode)
 		return
@@ -2440,9 +2457,9 @@ func (handler CoreAppRestHandlerImpl) GetAppWorkflowAndOverridesSample(w http.Re
 	//get/build environment override starts
 	environmentOverrides := make(map[string]*appBean.EnvironmentOverride)
 	if wfCloneRequest.EnvironmentId > 0 {
-		environmentOverrides, err, _ = handler.buildEnvironmentOverride(appId, wfCloneRequest.EnvironmentId, token)
+		environmentOverrides, err, _ = handler.buildEnvironmentOverride(appId, wfCloneRequest.EnvironmentId, handler.getTokenEnforceFunc(token))
 	} else {
-		environmentOverrides, err, _ = handler.buildEnvironmentOverrides(r.Context(), appId, token)
+		environmentOverrides, err, _ = handler.buildEnvironmentOverrides(r.Context(), appId, handler.getTokenEnforceFunc(token))
 	}
 	if err != nil {
 		handler.logger.Errorw("error on GetAppWorkflowAndOverridesSample", "err", err)
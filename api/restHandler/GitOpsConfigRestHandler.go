@@ -30,6 +30,10 @@ import (
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
 	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/monorepo"
+	monorepoBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/monorepo/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/pullRequest"
+	prBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/pullRequest/bean"
 	"github.com/devtron-labs/devtron/pkg/gitops"
 	"github.com/devtron-labs/devtron/pkg/team"
 	"github.com/gorilla/mux"
@@ -45,6 +49,12 @@ type GitOpsConfigRestHandler interface {
 	GetGitOpsConfigByProvider(w http.ResponseWriter, r *http.Request)
 	GitOpsConfigured(w http.ResponseWriter, r *http.Request)
 	GitOpsValidator(w http.ResponseWriter, r *http.Request)
+
+	GetAllPrModeConfigs(w http.ResponseWriter, r *http.Request)
+	GetPrModeConfig(w http.ResponseWriter, r *http.Request)
+	SavePrModeConfig(w http.ResponseWriter, r *http.Request)
+
+	MigrateAppsToMonorepo(w http.ResponseWriter, r *http.Request)
 }
 
 type GitOpsConfigRestHandlerImpl struct {
@@ -55,13 +65,17 @@ type GitOpsConfigRestHandlerImpl struct {
 	validator           *validator.Validate
 	enforcer            casbin.Enforcer
 	teamService         team.TeamService
+	pullRequestService  pullRequest.GitOpsPullRequestService
+	monorepoService     monorepo.GitOpsMonorepoService
 }
 
 func NewGitOpsConfigRestHandlerImpl(
 	logger *zap.SugaredLogger,
 	moduleReadService moduleRead.ModuleReadService,
 	gitOpsConfigService gitops.GitOpsConfigService, userAuthService user.UserService,
-	validator *validator.Validate, enforcer casbin.Enforcer, teamService team.TeamService) *GitOpsConfigRestHandlerImpl {
+	validator *validator.Validate, enforcer casbin.Enforcer, teamService team.TeamService,
+	pullRequestService pullRequest.GitOpsPullRequestService,
+	monorepoService monorepo.GitOpsMonorepoService) *GitOpsConfigRestHandlerImpl {
 	return &GitOpsConfigRestHandlerImpl{
 		logger:              logger,
 		moduleReadService:   moduleReadService,
@@ -70,6 +84,8 @@ func NewGitOpsConfigRestHandlerImpl(
 		validator:           validator,
 		enforcer:            enforcer,
 		teamService:         teamService,
+		pullRequestService:  pullRequestService,
+		monorepoService:     monorepoService,
 	}
 }
 
@@ -317,3 +333,117 @@ func (impl GitOpsConfigRestHandlerImpl) GitOpsValidator(w http.ResponseWriter, r
 	detailedErrorGitOpsConfigResponse := impl.gitOpsConfigService.GitOpsValidateDryRun(argoModule.IsInstalled(), &bean)
 	common.WriteJsonResp(w, nil, detailedErrorGitOpsConfigResponse, http.StatusOK)
 }
+
+func (impl GitOpsConfigRestHandlerImpl) GetAllPrModeConfigs(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := impl.pullRequestService.GetAllPrModeConfigs()
+	if err != nil {
+		impl.logger.Errorw("service err, GetAllPrModeConfigs", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl GitOpsConfigRestHandlerImpl) GetPrModeConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	envId, err := strconv.Atoi(mux.Vars(r)["envId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := impl.pullRequestService.GetPrModeConfig(envId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetPrModeConfig", "envId", envId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl GitOpsConfigRestHandlerImpl) SavePrModeConfig(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	// pr mode changes how deployments of every app in the environment are delivered, hence super admin only
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	var request prBean.PrModeConfigDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("request err, SavePrModeConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err, SavePrModeConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := impl.pullRequestService.SavePrModeConfig(&request, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, SavePrModeConfig", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl GitOpsConfigRestHandlerImpl) MigrateAppsToMonorepo(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	// migration rewrites the gitops repositories of the apps across all environments, hence super admin only
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	var request monorepoBean.MigrationRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("request err, MigrateAppsToMonorepo", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err, MigrateAppsToMonorepo", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	res, err := impl.monorepoService.MigrateApps(r.Context(), &request)
+	if err != nil {
+		impl.logger.Errorw("service err, MigrateAppsToMonorepo", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
@@ -51,28 +51,33 @@ type GlobalPluginRestHandler interface {
 	GetAllUniqueTags(w http.ResponseWriter, r *http.Request)
 	MigratePluginData(w http.ResponseWriter, r *http.Request)
 	GetAllPluginMinData(w http.ResponseWriter, r *http.Request)
+
+	GetOutdatedPluginUsages(w http.ResponseWriter, r *http.Request)
+	BulkUpgradePluginVersion(w http.ResponseWriter, r *http.Request)
 }
 
 func NewGlobalPluginRestHandler(logger *zap.SugaredLogger, globalPluginService plugin.GlobalPluginService,
 	enforcerUtil rbac.EnforcerUtil, enforcer casbin.Enforcer, pipelineBuilder pipeline.PipelineBuilder,
-	userService user.UserService) *GlobalPluginRestHandlerImpl {
+	userService user.UserService, pluginUpgradeService plugin.PluginUpgradeService) *GlobalPluginRestHandlerImpl {
 	return &GlobalPluginRestHandlerImpl{
-		logger:              logger,
-		globalPluginService: globalPluginService,
-		enforcerUtil:        enforcerUtil,
-		enforcer:            enforcer,
-		pipelineBuilder:     pipelineBuilder,
-		userService:         userService,
+		logger:               logger,
+		globalPluginService:  globalPluginService,
+		enforcerUtil:         enforcerUtil,
+		enforcer:             enforcer,
+		pipelineBuilder:      pipelineBuilder,
+		userService:          userService,
+		pluginUpgradeService: pluginUpgradeService,
 	}
 }
 
 type GlobalPluginRestHandlerImpl struct {
-	logger              *zap.SugaredLogger
-	globalPluginService plugin.GlobalPluginService
-	enforcerUtil        rbac.EnforcerUtil
-	enforcer            casbin.Enforcer
-	pipelineBuilder     pipeline.PipelineBuilder
-	userService         user.UserService
+	logger               *zap.SugaredLogger
+	globalPluginService  plugin.GlobalPluginService
+	enforcerUtil         rbac.EnforcerUtil
+	enforcer             casbin.Enforcer
+	pipelineBuilder      pipeline.PipelineBuilder
+	userService          user.UserService
+	pluginUpgradeService plugin.PluginUpgradeService
 }
 
 // Deprecated: method patchPlugin
@@ -513,3 +518,66 @@ func (handler *GlobalPluginRestHandlerImpl) GetAllPluginMinData(w http.ResponseW
 	}
 	common.WriteJsonResp(w, nil, pluginDetail, http.StatusOK)
 }
+
+func (handler *GlobalPluginRestHandlerImpl) GetOutdatedPluginUsages(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	var parentPluginIds []int
+	if parentPluginIdsParam := r.URL.Query().Get("parentPluginIds"); len(parentPluginIdsParam) > 0 {
+		for _, parentPluginIdStr := range strings.Split(parentPluginIdsParam, ",") {
+			parentPluginId, err := strconv.Atoi(strings.TrimSpace(parentPluginIdStr))
+			if err != nil {
+				common.WriteJsonResp(w, err, "invalid query param 'parentPluginIds'", http.StatusBadRequest)
+				return
+			}
+			parentPluginIds = append(parentPluginIds, parentPluginId)
+		}
+	}
+	outdatedUsages, err := handler.pluginUpgradeService.GetOutdatedPluginUsages(parentPluginIds)
+	if err != nil {
+		handler.logger.Errorw("service error, GetOutdatedPluginUsages", "parentPluginIds", parentPluginIds, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, outdatedUsages, http.StatusOK)
+}
+
+func (handler *GlobalPluginRestHandlerImpl) BulkUpgradePluginVersion(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	var request bean.PluginBulkUpgradeRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, BulkUpgradePluginVersion", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if len(request.PipelineStageStepIds) == 0 {
+		common.WriteJsonResp(w, fmt.Errorf("pipelineStageStepIds is required"), "pipelineStageStepIds is required", http.StatusBadRequest)
+		return
+	}
+	handler.logger.Infow("request payload received for bulk plugin upgrade", "request", request, "userId", userId)
+	response, err := handler.pluginUpgradeService.BulkUpgradePluginVersion(&request, userId)
+	if err != nil {
+		handler.logger.Errorw("service error, BulkUpgradePluginVersion", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, response, http.StatusOK)
+}
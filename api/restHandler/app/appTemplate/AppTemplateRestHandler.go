@@ -0,0 +1,287 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appTemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/appTemplate"
+	"github.com/devtron-labs/devtron/pkg/appTemplate/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	bean3 "github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/team/read"
+	util2 "github.com/devtron-labs/devtron/util"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type AppTemplateRestHandler interface {
+	CreateTemplate(w http.ResponseWriter, r *http.Request)
+	UpdateTemplate(w http.ResponseWriter, r *http.Request)
+	GetTemplate(w http.ResponseWriter, r *http.Request)
+	GetTemplates(w http.ResponseWriter, r *http.Request)
+	DeleteTemplate(w http.ResponseWriter, r *http.Request)
+	CreateApp(w http.ResponseWriter, r *http.Request)
+	GetTemplateApps(w http.ResponseWriter, r *http.Request)
+}
+
+type AppTemplateRestHandlerImpl struct {
+	logger             *zap.SugaredLogger
+	userAuthService    user.UserService
+	validator          *validator.Validate
+	appTemplateService appTemplate.AppTemplateService
+	teamReadService    read.TeamReadService
+	enforcer           casbin.Enforcer
+	enforcerUtil       rbac.EnforcerUtil
+}
+
+func NewAppTemplateRestHandlerImpl(logger *zap.SugaredLogger, userAuthService user.UserService, validator *validator.Validate,
+	appTemplateService appTemplate.AppTemplateService, teamReadService read.TeamReadService,
+	enforcer casbin.Enforcer, enforcerUtil rbac.EnforcerUtil) *AppTemplateRestHandlerImpl {
+	return &AppTemplateRestHandlerImpl{
+		logger:             logger,
+		userAuthService:    userAuthService,
+		validator:          validator,
+		appTemplateService: appTemplateService,
+		teamReadService:    teamReadService,
+		enforcer:           enforcer,
+		enforcerUtil:       enforcerUtil,
+	}
+}
+
+func (handler *AppTemplateRestHandlerImpl) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	handler.saveTemplate(w, r, false)
+}
+
+func (handler *AppTemplateRestHandlerImpl) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	handler.saveTemplate(w, r, true)
+}
+
+func (handler *AppTemplateRestHandlerImpl) saveTemplate(w http.ResponseWriter, r *http.Request, isUpdate bool) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	// templates are curated by the platform team
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	var request bean.AppTemplateRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if isUpdate {
+		id, ok := handler.getTemplateId(w, r)
+		if !ok {
+			return
+		}
+		request.Id = id
+	}
+	if err = handler.validator.Struct(request); err != nil {
+		handler.logger.Errorw("validation err, SaveAppTemplate", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, SaveAppTemplate", "name", request.Name, "id", request.Id, "userId", userId)
+	var res *bean.AppTemplateDto
+	if isUpdate {
+		res, err = handler.appTemplateService.UpdateTemplate(&request)
+	} else {
+		res, err = handler.appTemplateService.CreateTemplate(&request)
+	}
+	if err != nil {
+		handler.logger.Errorw("service err, SaveAppTemplate", "err", err, "name", request.Name, "id", request.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *AppTemplateRestHandlerImpl) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, ok := handler.getTemplateId(w, r)
+	if !ok {
+		return
+	}
+	version := 0
+	if versionParam := r.URL.Query().Get("version"); len(versionParam) > 0 {
+		version, err = strconv.Atoi(versionParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, "invalid version", http.StatusBadRequest)
+			return
+		}
+	}
+	res, err := handler.appTemplateService.GetTemplate(id, version)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAppTemplate", "err", err, "id", id, "version", version)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *AppTemplateRestHandlerImpl) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	res, err := handler.appTemplateService.GetTemplates()
+	if err != nil {
+		handler.logger.Errorw("service err, GetAppTemplates", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *AppTemplateRestHandlerImpl) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	id, ok := handler.getTemplateId(w, r)
+	if !ok {
+		return
+	}
+	handler.logger.Infow("request, DeleteAppTemplate", "id", id, "userId", userId)
+	err = handler.appTemplateService.DeleteTemplate(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteAppTemplate", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "app template deleted", http.StatusOK)
+}
+
+func (handler *AppTemplateRestHandlerImpl) CreateApp(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, ok := handler.getTemplateId(w, r)
+	if !ok {
+		return
+	}
+	var request bean.CreateAppFromTemplateRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if err = handler.validator.Struct(request); err != nil {
+		handler.logger.Errorw("validation err, CreateAppFromTemplate", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	project, err := handler.teamReadService.FindOne(request.TeamId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// same as app creation, access to all the apps of the project is needed
+	token := r.Header.Get("token")
+	object := fmt.Sprintf("%s/%s", project.Name, "*")
+	if ok := handler.enforcerUtil.CheckAppRbacForAppOrJob(token, object, casbin.ActionCreate); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	if strings.Contains(request.AppName, bean3.UniquePlaceHolderForAppName) {
+		common.WriteJsonResp(w, err, "app creation failed due to validation on app-name as it contains not allowed place-holder in name", http.StatusBadRequest)
+		return
+	}
+	request.TemplateId = id
+	request.UserId = userId
+	handler.logger.Infow("request payload, CreateAppFromTemplate", "templateId", id, "appName", request.AppName, "version", request.Version, "userId", userId)
+	ctx := util2.SetTokenInContext(r.Context(), token)
+	res, err := handler.appTemplateService.CreateApp(ctx, &request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateAppFromTemplate", "err", err, "templateId", id, "appName", request.AppName)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler *AppTemplateRestHandlerImpl) GetTemplateApps(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, ok := handler.getTemplateId(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.appTemplateService.GetTemplateApps(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAppTemplateApps", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// only the apps the user can see are reported
+	token := r.Header.Get("token")
+	appIds := make([]int, 0, len(res))
+	for _, templateApp := range res {
+		appIds = append(appIds, templateApp.AppId)
+	}
+	rbacObjects := handler.enforcerUtil.GetRbacObjectsByAppIds(appIds)
+	objects := make([]string, 0, len(rbacObjects))
+	for _, object := range rbacObjects {
+		objects = append(objects, object)
+	}
+	results := handler.enforcer.EnforceInBatch(token, casbin.ResourceApplications, casbin.ActionGet, objects)
+	authorisedApps := make([]*bean.TemplateAppDto, 0, len(res))
+	for _, templateApp := range res {
+		if results[rbacObjects[templateApp.AppId]] {
+			authorisedApps = append(authorisedApps, templateApp)
+		}
+	}
+	common.WriteJsonResp(w, nil, authorisedApps, http.StatusOK)
+}
+
+func (handler *AppTemplateRestHandlerImpl) getTemplateId(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid app template id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
@@ -25,6 +25,7 @@ import (
 	bean4 "github.com/devtron-labs/devtron/pkg/auth/user/bean"
 	util3 "github.com/devtron-labs/devtron/pkg/auth/user/util"
 	bean3 "github.com/devtron-labs/devtron/pkg/chart/bean"
+	postRenderBean "github.com/devtron-labs/devtron/pkg/deployment/manifest/postRender/bean"
 
 	devtronAppGitOpConfigBean "github.com/devtron-labs/devtron/pkg/chart/gitOpsConfig/bean"
 	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
@@ -101,6 +102,10 @@ type DevtronAppDeploymentConfigRestHandler interface {
 	GetEnvConfigOverride(w http.ResponseWriter, r *http.Request)
 	EnvConfigOverrideReset(w http.ResponseWriter, r *http.Request)
 
+	GetPostRenderPatches(w http.ResponseWriter, r *http.Request)
+	SavePostRenderPatches(w http.ResponseWriter, r *http.Request)
+	DeletePostRenderPatches(w http.ResponseWriter, r *http.Request)
+
 	UpdateAppOverride(w http.ResponseWriter, r *http.Request)
 	GetConfigmapSecretsForDeploymentStages(w http.ResponseWriter, r *http.Request)
 	GetDeploymentPipelineStrategy(w http.ResponseWriter, r *http.Request)
@@ -1505,6 +1510,103 @@ func (handler *PipelineConfigRestHandlerImpl) EnvConfigOverrideReset(w http.Resp
 	common.WriteJsonResp(w, err, isSuccess, http.StatusOK)
 }
 
+func (handler *PipelineConfigRestHandlerImpl) GetPostRenderPatches(w http.ResponseWriter, r *http.Request) {
+	appId, environmentId, _, ok := handler.checkPostRenderPatchesAuth(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	resp, err := handler.postRenderService.GetPatches(appId, environmentId)
+	if err != nil {
+		handler.Logger.Errorw("service err, GetPostRenderPatches", "err", err, "appId", appId, "environmentId", environmentId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *PipelineConfigRestHandlerImpl) SavePostRenderPatches(w http.ResponseWriter, r *http.Request) {
+	appId, environmentId, userId, ok := handler.checkPostRenderPatchesAuth(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	var request postRenderBean.PostRenderPatchesDto
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.Logger.Errorw("request err, SavePostRenderPatches", "err", err, "appId", appId, "environmentId", environmentId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.AppId, request.EnvId, request.UserId = appId, environmentId, userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.Logger.Errorw("validation err, SavePostRenderPatches", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	handler.Logger.Infow("request payload, SavePostRenderPatches", "appId", appId, "environmentId", environmentId)
+	resp, err := handler.postRenderService.SavePatches(&request)
+	if err != nil {
+		handler.Logger.Errorw("service err, SavePostRenderPatches", "err", err, "appId", appId, "environmentId", environmentId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *PipelineConfigRestHandlerImpl) DeletePostRenderPatches(w http.ResponseWriter, r *http.Request) {
+	appId, environmentId, userId, ok := handler.checkPostRenderPatchesAuth(w, r, casbin.ActionDelete)
+	if !ok {
+		return
+	}
+	handler.Logger.Infow("request payload, DeletePostRenderPatches", "appId", appId, "environmentId", environmentId)
+	err := handler.postRenderService.DeletePatches(appId, environmentId, userId)
+	if err != nil {
+		handler.Logger.Errorw("service err, DeletePostRenderPatches", "err", err, "appId", appId, "environmentId", environmentId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, true, http.StatusOK)
+}
+
+// checkPostRenderPatchesAuth reads the app and environment of a post render patches request and enforces the action
+// on both, the response is written when it returns false
+func (handler *PipelineConfigRestHandlerImpl) checkPostRenderPatchesAuth(w http.ResponseWriter, r *http.Request, action string) (int, int, int32, bool) {
+	userId, err := handler.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, 0, 0, false
+	}
+	token := r.Header.Get("token")
+	vars := mux.Vars(r)
+	appId, err := strconv.Atoi(vars["appId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, 0, 0, false
+	}
+	environmentId, err := strconv.Atoi(vars["environmentId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, 0, 0, false
+	}
+	app, err := handler.pipelineBuilder.GetApp(appId)
+	if err != nil {
+		handler.Logger.Errorw("service err, checkPostRenderPatchesAuth", "err", err, "appId", appId, "environmentId", environmentId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, 0, 0, false
+	}
+	resourceName := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, action, resourceName); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return 0, 0, 0, false
+	}
+	object := handler.enforcerUtil.GetAppRBACByAppNameAndEnvId(app.AppName, environmentId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceEnvironment, action, object); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return 0, 0, 0, false
+	}
+	return appId, environmentId, userId, true
+}
+
 func (handler *PipelineConfigRestHandlerImpl) ListDeploymentHistory(w http.ResponseWriter, r *http.Request) {
 	userId, err := handler.userAuthService.GetLoggedInUser(r)
 	if userId == 0 || err != nil {
@@ -33,6 +33,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deployedAppMetrics"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef"
 	validator2 "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/validator"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/postRender"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps"
 	"github.com/devtron-labs/devtron/pkg/pipeline/draftAwareConfigService"
 	security2 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning"
@@ -144,6 +145,7 @@ type PipelineConfigRestHandlerImpl struct {
 	draftAwareResourceService           draftAwareConfigService.DraftAwareConfigService
 	ciHandlerService                    trigger.HandlerService
 	cdHandlerService                    devtronApps.HandlerService
+	postRenderService                   postRender.PostRenderService
 }
 
 func NewPipelineRestHandlerImpl(pipelineBuilder pipeline.PipelineBuilder, Logger *zap.SugaredLogger,
@@ -181,6 +183,7 @@ func NewPipelineRestHandlerImpl(pipelineBuilder pipeline.PipelineBuilder, Logger
 	draftAwareResourceService draftAwareConfigService.DraftAwareConfigService,
 	ciHandlerService trigger.HandlerService,
 	cdHandlerService devtronApps.HandlerService,
+	postRenderService postRender.PostRenderService,
 ) *PipelineConfigRestHandlerImpl {
 	envConfig := &PipelineRestHandlerEnvConfig{}
 	err := env.Parse(envConfig)
@@ -226,6 +229,7 @@ func NewPipelineRestHandlerImpl(pipelineBuilder pipeline.PipelineBuilder, Logger
 		draftAwareResourceService:           draftAwareResourceService,
 		ciHandlerService:                    ciHandlerService,
 		cdHandlerService:                    cdHandlerService,
+		postRenderService:                   postRenderService,
 	}
 }
 
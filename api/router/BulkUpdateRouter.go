@@ -26,12 +26,17 @@ type BulkUpdateRouter interface {
 }
 
 type BulkUpdateRouterImpl struct {
-	restHandler restHandler.BulkUpdateRestHandler
+	restHandler                  restHandler.BulkUpdateRestHandler
+	bulkEditRestHandler          restHandler.BulkEditRestHandler
+	chartRefMigrationRestHandler restHandler.ChartRefMigrationRestHandler
 }
 
-func NewBulkUpdateRouterImpl(handler restHandler.BulkUpdateRestHandler) *BulkUpdateRouterImpl {
+func NewBulkUpdateRouterImpl(handler restHandler.BulkUpdateRestHandler, bulkEditRestHandler restHandler.BulkEditRestHandler,
+	chartRefMigrationRestHandler restHandler.ChartRefMigrationRestHandler) *BulkUpdateRouterImpl {
 	router := &BulkUpdateRouterImpl{
-		restHandler: handler,
+		restHandler:                  handler,
+		bulkEditRestHandler:          bulkEditRestHandler,
+		chartRefMigrationRestHandler: chartRefMigrationRestHandler,
 	}
 	return router
 }
@@ -48,4 +53,16 @@ func (router BulkUpdateRouterImpl) initBulkUpdateRouter(bulkRouter *mux.Router)
 	bulkRouter.Path("/v1beta1/build").HandlerFunc(router.restHandler.BulkBuildTrigger).Methods("POST")
 	bulkRouter.Path("/v1beta1/cd-pipeline").HandlerFunc(router.restHandler.HandleCdPipelineBulkAction).Methods("POST")
 
+	bulkRouter.Path("/v2/bulk-edit/preview").HandlerFunc(router.bulkEditRestHandler.PreviewBulkEdit).Methods("POST")
+	bulkRouter.Path("/v2/bulk-edit").HandlerFunc(router.bulkEditRestHandler.GetBulkEdits).Methods("GET")
+	bulkRouter.Path("/v2/bulk-edit/{id:[0-9]+}").HandlerFunc(router.bulkEditRestHandler.GetBulkEdit).Methods("GET")
+	bulkRouter.Path("/v2/bulk-edit/{id:[0-9]+}/apply").HandlerFunc(router.bulkEditRestHandler.ApplyBulkEdit).Methods("POST")
+	bulkRouter.Path("/v2/bulk-edit/{id:[0-9]+}/resume").HandlerFunc(router.bulkEditRestHandler.ResumeBulkEdit).Methods("POST")
+	bulkRouter.Path("/v2/bulk-edit/{id:[0-9]+}/rollback").HandlerFunc(router.bulkEditRestHandler.RollbackBulkEdit).Methods("POST")
+
+	bulkRouter.Path("/v2/chart-ref-migration/dry-run").HandlerFunc(router.chartRefMigrationRestHandler.DryRunChartRefMigration).Methods("POST")
+	bulkRouter.Path("/v2/chart-ref-migration").HandlerFunc(router.chartRefMigrationRestHandler.GetChartRefMigrations).Methods("GET")
+	bulkRouter.Path("/v2/chart-ref-migration/{id:[0-9]+}").HandlerFunc(router.chartRefMigrationRestHandler.GetChartRefMigration).Methods("GET")
+	bulkRouter.Path("/v2/chart-ref-migration/{id:[0-9]+}/commit").HandlerFunc(router.chartRefMigrationRestHandler.CommitChartRefMigration).Methods("POST")
+
 }
@@ -53,4 +53,16 @@ func (impl GitOpsConfigRouterImpl) InitGitOpsConfigRouter(configRouter *mux.Rout
 	configRouter.Path("/validate").
 		HandlerFunc(impl.gitOpsConfigRestHandler.GitOpsValidator).
 		Methods("POST")
+	configRouter.Path("/pr-mode").
+		HandlerFunc(impl.gitOpsConfigRestHandler.GetAllPrModeConfigs).
+		Methods("GET")
+	configRouter.Path("/pr-mode/{envId}").
+		HandlerFunc(impl.gitOpsConfigRestHandler.GetPrModeConfig).
+		Methods("GET")
+	configRouter.Path("/pr-mode").
+		HandlerFunc(impl.gitOpsConfigRestHandler.SavePrModeConfig).
+		Methods("PUT")
+	configRouter.Path("/monorepo/migrate").
+		HandlerFunc(impl.gitOpsConfigRestHandler.MigrateAppsToMonorepo).
+		Methods("POST")
 }
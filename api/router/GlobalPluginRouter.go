@@ -72,4 +72,9 @@ func (impl *GlobalPluginRouterImpl) initGlobalPluginRouter(globalPluginRouter *m
 	globalPluginRouter.Path("/list/v2/min").
 		HandlerFunc(impl.globalPluginRestHandler.GetAllPluginMinData).Methods("GET")
 
+	globalPluginRouter.Path("/upgrade/outdated").
+		HandlerFunc(impl.globalPluginRestHandler.GetOutdatedPluginUsages).Methods("GET")
+	globalPluginRouter.Path("/upgrade/bulk").
+		HandlerFunc(impl.globalPluginRestHandler.BulkUpgradePluginVersion).Methods("POST")
+
 }
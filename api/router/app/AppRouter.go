@@ -21,6 +21,7 @@ import (
 	workflow2 "github.com/devtron-labs/devtron/api/restHandler/app/workflow"
 	"github.com/devtron-labs/devtron/api/router/app/appInfo"
 	appList2 "github.com/devtron-labs/devtron/api/router/app/appList"
+	"github.com/devtron-labs/devtron/api/router/app/appTemplate"
 	pipeline2 "github.com/devtron-labs/devtron/api/router/app/pipeline"
 	"github.com/devtron-labs/devtron/api/router/app/pipeline/configure"
 	"github.com/devtron-labs/devtron/api/router/app/pipeline/history"
@@ -44,6 +45,7 @@ type AppRouterImpl struct {
 	pipelineStatusRouter         status.PipelineStatusRouter
 	appWorkflowRouter            workflow.AppWorkflowRouter
 	devtronAppAutoCompleteRouter pipeline2.DevtronAppAutoCompleteRouter
+	appTemplateRouter            appTemplate.AppTemplateRouter
 
 	// TODO remove these dependencies after migration
 	appWorkflowRestHandler  workflow2.AppWorkflowRestHandler
@@ -60,6 +62,7 @@ func NewAppRouterImpl(appFilteringRouter appList2.AppFilteringRouter,
 	pipelineStatusRouter status.PipelineStatusRouter,
 	appWorkflowRouter workflow.AppWorkflowRouter,
 	devtronAppAutoCompleteRouter pipeline2.DevtronAppAutoCompleteRouter,
+	appTemplateRouter appTemplate.AppTemplateRouter,
 	appWorkflowRestHandler workflow2.AppWorkflowRestHandler,
 	appListingRestHandler appList.AppListingRestHandler,
 	appFilteringRestHandler appList.AppFilteringRestHandler) *AppRouterImpl {
@@ -73,6 +76,7 @@ func NewAppRouterImpl(appFilteringRouter appList2.AppFilteringRouter,
 		pipelineStatusRouter:         pipelineStatusRouter,
 		appWorkflowRouter:            appWorkflowRouter,
 		devtronAppAutoCompleteRouter: devtronAppAutoCompleteRouter,
+		appTemplateRouter:            appTemplateRouter,
 		appWorkflowRestHandler:       appWorkflowRestHandler,
 		appListingRestHandler:        appListingRestHandler,
 		appFilteringRestHandler:      appFilteringRestHandler,
@@ -101,6 +105,9 @@ func (router AppRouterImpl) InitAppRouter(AppRouter *mux.Router) {
 	appWorkflowRouter := AppRouter.PathPrefix("/app-wf").Subrouter()
 	router.appWorkflowRouter.InitAppWorkflowRouter(appWorkflowRouter)
 
+	appTemplateRouter := AppRouter.PathPrefix("/template").Subrouter()
+	router.appTemplateRouter.InitAppTemplateRouter(appTemplateRouter)
+
 	// TODO refactoring: categorise and move to respective folders
 	AppRouter.Path("/allApps").
 		HandlerFunc(router.appListingRestHandler.FetchAllDevtronManagedApps).
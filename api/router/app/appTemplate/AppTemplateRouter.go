@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appTemplate
+
+import (
+	"github.com/devtron-labs/devtron/api/restHandler/app/appTemplate"
+	"github.com/gorilla/mux"
+)
+
+type AppTemplateRouter interface {
+	InitAppTemplateRouter(appTemplateRouter *mux.Router)
+}
+
+type AppTemplateRouterImpl struct {
+	appTemplateRestHandler appTemplate.AppTemplateRestHandler
+}
+
+func NewAppTemplateRouterImpl(appTemplateRestHandler appTemplate.AppTemplateRestHandler) *AppTemplateRouterImpl {
+	return &AppTemplateRouterImpl{
+		appTemplateRestHandler: appTemplateRestHandler,
+	}
+}
+
+func (router AppTemplateRouterImpl) InitAppTemplateRouter(appTemplateRouter *mux.Router) {
+	appTemplateRouter.Path("").
+		HandlerFunc(router.appTemplateRestHandler.CreateTemplate).Methods("POST")
+
+	appTemplateRouter.Path("").
+		HandlerFunc(router.appTemplateRestHandler.GetTemplates).Methods("GET")
+
+	appTemplateRouter.Path("/{id:[0-9]+}").
+		HandlerFunc(router.appTemplateRestHandler.GetTemplate).Methods("GET")
+
+	appTemplateRouter.Path("/{id:[0-9]+}").
+		HandlerFunc(router.appTemplateRestHandler.UpdateTemplate).Methods("PUT")
+
+	appTemplateRouter.Path("/{id:[0-9]+}").
+		HandlerFunc(router.appTemplateRestHandler.DeleteTemplate).Methods("DELETE")
+
+	appTemplateRouter.Path("/{id:[0-9]+}/app").
+		HandlerFunc(router.appTemplateRestHandler.CreateApp).Methods("POST")
+
+	appTemplateRouter.Path("/{id:[0-9]+}/apps").
+		HandlerFunc(router.appTemplateRestHandler.GetTemplateApps).Methods("GET")
+}
@@ -122,6 +122,9 @@ func (router PipelineConfigRouterImpl) InitPipelineConfigRouter(configRouter *mu
 	configRouter.Path("/upgrade/all/{chartRefId}").HandlerFunc(router.restHandler.UpgradeForAllApps).Methods("POST")
 
 	configRouter.Path("/env/reset/{appId}/{environmentId}/{id}").HandlerFunc(router.restHandler.EnvConfigOverrideReset).Methods("DELETE")
+	configRouter.Path("/env/post-render/{appId}/{environmentId}").HandlerFunc(router.restHandler.GetPostRenderPatches).Methods("GET")
+	configRouter.Path("/env/post-render/{appId}/{environmentId}").HandlerFunc(router.restHandler.SavePostRenderPatches).Methods("PUT")
+	configRouter.Path("/env/post-render/{appId}/{environmentId}").HandlerFunc(router.restHandler.DeletePostRenderPatches).Methods("DELETE")
 	configRouter.Path("/env/namespace/{appId}/{environmentId}").HandlerFunc(router.restHandler.EnvConfigOverrideCreateNamespace).Methods("POST")
 
 	configRouter.Path("/cd-pipeline/workflow/history/{appId}/{environmentId}/{pipelineId}").HandlerFunc(router.restHandler.ListDeploymentHistory).Methods("GET")
@@ -19,10 +19,12 @@ package router
 import (
 	"encoding/json"
 	"github.com/devtron-labs/devtron/api/apiToken"
+	"github.com/devtron-labs/devtron/api/appAsCode"
 	"github.com/devtron-labs/devtron/api/appStore"
 	"github.com/devtron-labs/devtron/api/appStore/chartGroup"
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
 	"github.com/devtron-labs/devtron/api/argoApplication"
+	"github.com/devtron-labs/devtron/api/artifactPromotion"
 	"github.com/devtron-labs/devtron/api/auth/sso"
 	"github.com/devtron-labs/devtron/api/auth/user"
 	"github.com/devtron-labs/devtron/api/chartRepo"
@@ -33,10 +35,12 @@ import (
 	"github.com/devtron-labs/devtron/api/externalLink"
 	fluxApplication2 "github.com/devtron-labs/devtron/api/fluxApplication"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	"github.com/devtron-labs/devtron/api/imageSigning"
 	"github.com/devtron-labs/devtron/api/infraConfig"
 	"github.com/devtron-labs/devtron/api/k8s/application"
 	"github.com/devtron-labs/devtron/api/k8s/capacity"
 	"github.com/devtron-labs/devtron/api/module"
+	"github.com/devtron-labs/devtron/api/pluginCatalog"
 	"github.com/devtron-labs/devtron/api/resourceScan"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/api/router/app"
@@ -44,6 +48,7 @@ import (
 	"github.com/devtron-labs/devtron/api/server"
 	"github.com/devtron-labs/devtron/api/team"
 	terminal2 "github.com/devtron-labs/devtron/api/terminal"
+	"github.com/devtron-labs/devtron/api/testReport"
 	"github.com/devtron-labs/devtron/api/userResource"
 	webhookHelm "github.com/devtron-labs/devtron/api/webhook/helm"
 	"github.com/devtron-labs/devtron/client/cron"
@@ -124,6 +129,11 @@ type MuxRouter struct {
 	devtronResourceRouter              devtronResource.DevtronResourceRouter
 	scanningResultRouter               resourceScan.ScanningResultRouter
 	userResourceRouter                 userResource.Router
+	imageSigningRouter                 imageSigning.ImageSigningRouter
+	artifactPromotionRouter            artifactPromotion.ArtifactPromotionRouter
+	pluginCatalogRouter                pluginCatalog.PluginCatalogRouter
+	testReportRouter                   testReport.TestReportRouter
+	appAsCodeRouter                    appAsCode.AppAsCodeRouter
 }
 
 func NewMuxRouter(logger *zap.SugaredLogger,
@@ -159,6 +169,11 @@ func NewMuxRouter(logger *zap.SugaredLogger,
 	fluxApplicationRouter fluxApplication2.FluxApplicationRouter,
 	scanningResultRouter resourceScan.ScanningResultRouter,
 	userResourceRouter userResource.Router,
+	imageSigningRouter imageSigning.ImageSigningRouter,
+	artifactPromotionRouter artifactPromotion.ArtifactPromotionRouter,
+	pluginCatalogRouter pluginCatalog.PluginCatalogRouter,
+	testReportRouter testReport.TestReportRouter,
+	appAsCodeRouter appAsCode.AppAsCodeRouter,
 ) *MuxRouter {
 	r := &MuxRouter{
 		Router:                             mux.NewRouter(),
@@ -226,6 +241,11 @@ func NewMuxRouter(logger *zap.SugaredLogger,
 		fluxApplicationRouter:              fluxApplicationRouter,
 		scanningResultRouter:               scanningResultRouter,
 		userResourceRouter:                 userResourceRouter,
+		imageSigningRouter:                 imageSigningRouter,
+		artifactPromotionRouter:            artifactPromotionRouter,
+		pluginCatalogRouter:                pluginCatalogRouter,
+		testReportRouter:                   testReportRouter,
+		appAsCodeRouter:                    appAsCodeRouter,
 	}
 	return r
 }
@@ -335,6 +355,21 @@ func (r MuxRouter) Init() {
 	policyRouter := r.Router.PathPrefix("/orchestrator/security/policy").Subrouter()
 	r.policyRouter.InitPolicyRouter(policyRouter)
 
+	imageSigningRouter := r.Router.PathPrefix("/orchestrator/security/signing").Subrouter()
+	r.imageSigningRouter.InitImageSigningRouter(imageSigningRouter)
+
+	artifactPromotionRouter := r.Router.PathPrefix("/orchestrator/artifact-promotion").Subrouter()
+	r.artifactPromotionRouter.InitArtifactPromotionRouter(artifactPromotionRouter)
+
+	pluginCatalogRouter := r.Router.PathPrefix("/orchestrator/plugin/catalog").Subrouter()
+	r.pluginCatalogRouter.InitPluginCatalogRouter(pluginCatalogRouter)
+
+	testReportRouter := r.Router.PathPrefix("/orchestrator/test-report").Subrouter()
+	r.testReportRouter.InitTestReportRouter(testReportRouter)
+
+	appAsCodeRouter := r.Router.PathPrefix("/orchestrator/app-as-code").Subrouter()
+	r.appAsCodeRouter.InitAppAsCodeRouter(appAsCodeRouter)
+
 	gitOpsRouter := r.Router.PathPrefix("/orchestrator/gitops").Subrouter()
 	r.gitOpsConfigRouter.InitGitOpsConfigRouter(gitOpsRouter)
 
@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testReport
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/testReport"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type TestReportRestHandler interface {
+	GetCiWorkflowTestReport(w http.ResponseWriter, r *http.Request)
+	GetCdWorkflowRunnerTestReport(w http.ResponseWriter, r *http.Request)
+	GetCiPipelineFlakyTests(w http.ResponseWriter, r *http.Request)
+	GetCdPipelineFlakyTests(w http.ResponseWriter, r *http.Request)
+}
+
+type TestReportRestHandlerImpl struct {
+	logger            *zap.SugaredLogger
+	userService       user.UserService
+	testReportService testReport.TestReportService
+	enforcer          casbin.Enforcer
+	enforcerUtil      rbac.EnforcerUtil
+}
+
+func NewTestReportRestHandlerImpl(logger *zap.SugaredLogger,
+	userService user.UserService,
+	testReportService testReport.TestReportService,
+	enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil) *TestReportRestHandlerImpl {
+	return &TestReportRestHandlerImpl{
+		logger:            logger,
+		userService:       userService,
+		testReportService: testReportService,
+		enforcer:          enforcer,
+		enforcerUtil:      enforcerUtil,
+	}
+}
+
+func (handler *TestReportRestHandlerImpl) GetCiWorkflowTestReport(w http.ResponseWriter, r *http.Request) {
+	if !handler.isLoggedIn(w, r) {
+		return
+	}
+	ciWorkflowId, err := common.ExtractIntPathParam(w, r, "ciWorkflowId")
+	if err != nil {
+		return
+	}
+	resp, err := handler.testReportService.GetCiWorkflowTestReport(ciWorkflowId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetCiWorkflowTestReport", "err", err, "ciWorkflowId", ciWorkflowId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.checkAppGetAccess(w, r, resp.AppId) {
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *TestReportRestHandlerImpl) GetCdWorkflowRunnerTestReport(w http.ResponseWriter, r *http.Request) {
+	if !handler.isLoggedIn(w, r) {
+		return
+	}
+	cdWorkflowRunnerId, err := common.ExtractIntPathParam(w, r, "cdWorkflowRunnerId")
+	if err != nil {
+		return
+	}
+	resp, err := handler.testReportService.GetCdWorkflowRunnerTestReport(cdWorkflowRunnerId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetCdWorkflowRunnerTestReport", "err", err, "cdWorkflowRunnerId", cdWorkflowRunnerId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.checkAppGetAccess(w, r, resp.AppId) {
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *TestReportRestHandlerImpl) GetCiPipelineFlakyTests(w http.ResponseWriter, r *http.Request) {
+	if !handler.isLoggedIn(w, r) {
+		return
+	}
+	ciPipelineId, err := common.ExtractIntPathParam(w, r, "ciPipelineId")
+	if err != nil {
+		return
+	}
+	resp, err := handler.testReportService.GetCiPipelineFlakyTests(ciPipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetCiPipelineFlakyTests", "err", err, "ciPipelineId", ciPipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.checkAppGetAccess(w, r, resp.AppId) {
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *TestReportRestHandlerImpl) GetCdPipelineFlakyTests(w http.ResponseWriter, r *http.Request) {
+	if !handler.isLoggedIn(w, r) {
+		return
+	}
+	cdPipelineId, err := common.ExtractIntPathParam(w, r, "cdPipelineId")
+	if err != nil {
+		return
+	}
+	resp, err := handler.testReportService.GetCdPipelineFlakyTests(cdPipelineId)
+	if err != nil {
+		handler.logger.Errorw("service err, GetCdPipelineFlakyTests", "err", err, "cdPipelineId", cdPipelineId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !handler.checkAppGetAccess(w, r, resp.AppId) {
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *TestReportRestHandlerImpl) isLoggedIn(w http.ResponseWriter, r *http.Request) bool {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// checkAppGetAccess writes the error response and returns false if the caller cannot view the app, test reports
+// are visible to everyone who can view the build history
+func (handler *TestReportRestHandlerImpl) checkAppGetAccess(w http.ResponseWriter, r *http.Request, appId int) bool {
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return false
+	}
+	return true
+}
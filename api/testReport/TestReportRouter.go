@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testReport
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type TestReportRouter interface {
+	InitTestReportRouter(router *mux.Router)
+}
+
+type TestReportRouterImpl struct {
+	testReportRestHandler TestReportRestHandler
+}
+
+func NewTestReportRouterImpl(testReportRestHandler TestReportRestHandler) *TestReportRouterImpl {
+	return &TestReportRouterImpl{testReportRestHandler: testReportRestHandler}
+}
+
+func (router *TestReportRouterImpl) InitTestReportRouter(testReportRouter *mux.Router) {
+	testReportRouter.Path("/ci-workflow/{ciWorkflowId}").HandlerFunc(router.testReportRestHandler.GetCiWorkflowTestReport).Methods("GET")
+	testReportRouter.Path("/cd-workflow-runner/{cdWorkflowRunnerId}").HandlerFunc(router.testReportRestHandler.GetCdWorkflowRunnerTestReport).Methods("GET")
+	testReportRouter.Path("/ci-pipeline/{ciPipelineId}/flaky").HandlerFunc(router.testReportRestHandler.GetCiPipelineFlakyTests).Methods("GET")
+	testReportRouter.Path("/cd-pipeline/{cdPipelineId}/flaky").HandlerFunc(router.testReportRestHandler.GetCdPipelineFlakyTests).Methods("GET")
+}
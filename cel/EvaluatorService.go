@@ -69,9 +69,11 @@ func (impl *EvaluatorServiceImpl) Validate(request Request) (*cel.Ast, *cel.Env,
 		declarations = append(declarations, declaration)
 	}
 
-	env, err := cel.NewEnv(
-		cel.Declarations(declarations...),
-	)
+	envOptions := []cel.EnvOption{cel.Declarations(declarations...)}
+	if request.CrossTypeNumericComparisons {
+		envOptions = append(envOptions, cel.CrossTypeNumericComparisons(true))
+	}
+	env, err := cel.NewEnv(envOptions...)
 
 	if err != nil {
 		return nil, nil, err
@@ -93,6 +95,8 @@ func getDeclarationType(paramType ParamValuesType) (*expr.Type, error) {
 		return decls.Dyn, nil
 	case ParamTypeInteger:
 		return decls.Int, nil
+	case ParamTypeDouble:
+		return decls.Double, nil
 	case ParamTypeBool:
 		return decls.Bool, nil
 	case ParamTypeList:
@@ -6,6 +6,7 @@ const (
 	ParamTypeString         ParamValuesType = "string"
 	ParamTypeObject         ParamValuesType = "object"
 	ParamTypeInteger        ParamValuesType = "integer"
+	ParamTypeDouble         ParamValuesType = "double"
 	ParamTypeList           ParamValuesType = "list"
 	ParamTypeBool           ParamValuesType = "bool"
 	ParamTypeMapStringToAny ParamValuesType = "mapStringToAny"
@@ -26,9 +27,26 @@ const ContainerImage ParamName = "containerImage"
 const ContainerImageTag ParamName = "containerImageTag"
 const ImageLabels ParamName = "imageLabels"
 
+// artifact promotion facts
+const SourceEnvName ParamName = "sourceEnvName"
+const DeployedOnSource ParamName = "deployedOnSource"
+const PostStageStatus ParamName = "postStageStatus"
+const PostStageTestsPassed ParamName = "postStageTestsPassed"
+const ArtifactAgeHours ParamName = "artifactAgeHours"
+const IsScanned ParamName = "isScanned"
+const IsVulnerable ParamName = "isVulnerable"
+const ApprovalCount ParamName = "approvalCount"
+const HasTestReport ParamName = "hasTestReport"
+const TestsFailed ParamName = "testsFailed"
+const TestPassRate ParamName = "testPassRate"
+const TestLineCoverage ParamName = "testLineCoverage"
+
 type Request struct {
 	Expression         string             `json:"expression"`
 	ExpressionMetadata ExpressionMetadata `json:"params"`
+	// CrossTypeNumericComparisons allows comparing double params such as pass rates with integer literals,
+	// only set for artifact promotion conditions
+	CrossTypeNumericComparisons bool `json:"-"`
 }
 
 type ExpressionMetadata struct {
@@ -49,6 +49,8 @@ type AppTemplate struct {
 	RepoPath        string
 	RepoUrl         string
 	AutoSyncEnabled bool
+	// ReleaseName is the helm release name the chart is rendered with, argo cd uses the application name when empty
+	ReleaseName string
 }
 
 const (
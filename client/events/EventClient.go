@@ -94,6 +94,9 @@ type Payload struct {
 	BuildHistoryLink      string                         `json:"buildHistoryLink"`
 	MaterialTriggerInfo   *buildBean.MaterialTriggerInfo `json:"material"`
 	FailureReason         string                         `json:"failureReason"`
+	ClusterName           string                         `json:"clusterName,omitempty"`
+	CredentialType        string                         `json:"credentialType,omitempty"`
+	CredentialExpiresAt   string                         `json:"credentialExpiresAt,omitempty"`
 }
 
 type EventRESTClientImpl struct {
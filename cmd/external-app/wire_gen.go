@@ -65,7 +65,12 @@ import (
 	"github.com/devtron-labs/devtron/pkg/apiToken"
 	app2 "github.com/devtron-labs/devtron/pkg/app"
 	"github.com/devtron-labs/devtron/pkg/app/dbMigration"
+	"github.com/devtron-labs/devtron/pkg/appStore/adoption"
+	repository21 "github.com/devtron-labs/devtron/pkg/appStore/adoption/repository"
 	repository9 "github.com/devtron-labs/devtron/pkg/appStore/chartGroup/repository"
+	"github.com/devtron-labs/devtron/pkg/appStore/chartMirror"
+	read11 "github.com/devtron-labs/devtron/pkg/appStore/chartMirror/read"
+	repository22 "github.com/devtron-labs/devtron/pkg/appStore/chartMirror/repository"
 	"github.com/devtron-labs/devtron/pkg/appStore/chartProvider"
 	"github.com/devtron-labs/devtron/pkg/appStore/discover/repository"
 	service3 "github.com/devtron-labs/devtron/pkg/appStore/discover/service"
@@ -75,8 +80,12 @@ import (
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/EAMode"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/EAMode/deployment"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/common"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor"
+	repository19 "github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/repository"
 	"github.com/devtron-labs/devtron/pkg/appStore/values/repository"
 	service4 "github.com/devtron-labs/devtron/pkg/appStore/values/service"
+	"github.com/devtron-labs/devtron/pkg/appStore/valuesSchema"
+	repository20 "github.com/devtron-labs/devtron/pkg/appStore/valuesSchema/repository"
 	"github.com/devtron-labs/devtron/pkg/argoApplication"
 	read9 "github.com/devtron-labs/devtron/pkg/argoApplication/read"
 	config3 "github.com/devtron-labs/devtron/pkg/argoApplication/read/config"
@@ -92,7 +101,14 @@ import (
 	"github.com/devtron-labs/devtron/pkg/chartRepo"
 	"github.com/devtron-labs/devtron/pkg/chartRepo/repository"
 	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/cluster/credential"
+	repository14 "github.com/devtron-labs/devtron/pkg/cluster/credential/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/provider"
+	repository13 "github.com/devtron-labs/devtron/pkg/cluster/discovery/repository"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec"
+	repository18 "github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec/repository"
 	read8 "github.com/devtron-labs/devtron/pkg/cluster/environment/read"
 	repository4 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	rbac2 "github.com/devtron-labs/devtron/pkg/cluster/rbac"
@@ -113,7 +129,14 @@ import (
 	k8s2 "github.com/devtron-labs/devtron/pkg/k8s"
 	"github.com/devtron-labs/devtron/pkg/k8s/application"
 	"github.com/devtron-labs/devtron/pkg/k8s/capacity"
+	"github.com/devtron-labs/devtron/pkg/k8s/costAllocation"
+	repository17 "github.com/devtron-labs/devtron/pkg/k8s/costAllocation/repository"
 	"github.com/devtron-labs/devtron/pkg/k8s/informer"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance"
+	repository16 "github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance/repository"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceSearch"
+	repository15 "github.com/devtron-labs/devtron/pkg/k8s/resourceSearch/repository"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceWatch"
 	"github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs"
 	repository10 "github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs/repository"
 	"github.com/devtron-labs/devtron/pkg/module"
@@ -254,7 +277,16 @@ func InitializeApp() (*App, error) {
 	}
 	attributesServiceImpl := attributes.NewAttributesServiceImpl(sugaredLogger, attributesRepositoryImpl)
 	grafanaClientImpl := grafana.NewGrafanaClientImpl(sugaredLogger, httpClient, grafanaClientConfig, attributesServiceImpl)
-	environmentServiceImpl := environment.NewEnvironmentServiceImpl(environmentRepositoryImpl, clusterServiceImpl, sugaredLogger, k8sServiceImpl, k8sInformerFactoryImpl, userAuthServiceImpl, attributesRepositoryImpl, clusterReadServiceImpl, grafanaClientImpl)
+	namespaceSpecConfig, err := namespaceSpec.GetNamespaceSpecConfig()
+	if err != nil {
+		return nil, err
+	}
+	namespaceSpecRepositoryImpl := repository18.NewNamespaceSpecRepositoryImpl(db, sugaredLogger)
+	namespaceSpecServiceImpl, err := namespaceSpec.NewNamespaceSpecServiceImpl(sugaredLogger, environmentRepositoryImpl, clusterReadServiceImpl, k8sServiceImpl, namespaceSpecRepositoryImpl, namespaceSpecConfig, cronLoggerImpl)
+	if err != nil {
+		return nil, err
+	}
+	environmentServiceImpl := environment.NewEnvironmentServiceImpl(environmentRepositoryImpl, clusterServiceImpl, sugaredLogger, k8sServiceImpl, k8sInformerFactoryImpl, userAuthServiceImpl, attributesRepositoryImpl, clusterReadServiceImpl, grafanaClientImpl, namespaceSpecServiceImpl)
 	chartRepoRepositoryImpl := chartRepoRepository.NewChartRepoRepositoryImpl(db)
 	acdAuthConfig, err := util3.GetACDAuthConfig()
 	if err != nil {
@@ -333,7 +365,33 @@ func InitializeApp() (*App, error) {
 	clusterDescriptionServiceImpl := cluster.NewClusterDescriptionServiceImpl(clusterDescriptionRepositoryImpl, userRepositoryImpl, sugaredLogger)
 	clusterRbacServiceImpl := rbac2.NewClusterRbacServiceImpl(environmentServiceImpl, enforcerImpl, enforcerUtilImpl, clusterServiceImpl, sugaredLogger, userServiceImpl, clusterReadServiceImpl)
 	clusterRestHandlerImpl := cluster2.NewClusterRestHandlerImpl(clusterServiceImpl, genericNoteServiceImpl, clusterDescriptionServiceImpl, sugaredLogger, userServiceImpl, validate, enforcerImpl, deleteServiceImpl, environmentServiceImpl, clusterRbacServiceImpl)
-	clusterRouterImpl := cluster2.NewClusterRouterImpl(clusterRestHandlerImpl)
+	clusterDiscoveryConfig, err := discovery.GetClusterDiscoveryConfig()
+	if err != nil {
+		return nil, err
+	}
+	cloudCredentialRepositoryImpl := repository13.NewCloudCredentialRepositoryImpl(db, sugaredLogger)
+	clusterCloudSourceRepositoryImpl := repository13.NewClusterCloudSourceRepositoryImpl(db, sugaredLogger)
+	providers := provider.NewProviders(sugaredLogger)
+	serviceAccountTokenIssuerImpl := discovery.NewServiceAccountTokenIssuerImpl(sugaredLogger, k8sServiceImpl, clusterDiscoveryConfig)
+	clusterDiscoveryServiceImpl, err := discovery.NewClusterDiscoveryServiceImpl(sugaredLogger, cloudCredentialRepositoryImpl, clusterCloudSourceRepositoryImpl, clusterServiceImpl, providers, serviceAccountTokenIssuerImpl, clusterDiscoveryConfig, cronLoggerImpl)
+	if err != nil {
+		return nil, err
+	}
+	clusterDiscoveryRestHandlerImpl := cluster2.NewClusterDiscoveryRestHandlerImpl(sugaredLogger, userServiceImpl, validate, enforcerImpl, clusterDiscoveryServiceImpl)
+	clusterCredentialConfig, err := credential.GetClusterCredentialConfig()
+	if err != nil {
+		return nil, err
+	}
+	clusterCredentialExpiryRepositoryImpl := repository14.NewClusterCredentialExpiryRepositoryImpl(db, sugaredLogger)
+	clusterCredentialRotationAuditRepositoryImpl := repository14.NewClusterCredentialRotationAuditRepositoryImpl(db, sugaredLogger)
+	serviceAccountTokenRotatorImpl := credential.NewServiceAccountTokenRotatorImpl(sugaredLogger, k8sServiceImpl)
+	credentialExpiryLogNotifierImpl := credential.NewCredentialExpiryLogNotifierImpl(sugaredLogger)
+	clusterCredentialServiceImpl, err := credential.NewClusterCredentialServiceImpl(sugaredLogger, clusterServiceImpl, clusterCredentialExpiryRepositoryImpl, clusterCredentialRotationAuditRepositoryImpl, serviceAccountTokenRotatorImpl, credentialExpiryLogNotifierImpl, clusterCredentialConfig, cronLoggerImpl)
+	if err != nil {
+		return nil, err
+	}
+	clusterCredentialRestHandlerImpl := cluster2.NewClusterCredentialRestHandlerImpl(sugaredLogger, userServiceImpl, enforcerImpl, clusterCredentialServiceImpl)
+	clusterRouterImpl := cluster2.NewClusterRouterImpl(clusterRestHandlerImpl, clusterDiscoveryRestHandlerImpl, clusterCredentialRestHandlerImpl)
 	dashboardConfig, err := dashboard.GetConfig()
 	if err != nil {
 		return nil, err
@@ -346,9 +404,17 @@ func InitializeApp() (*App, error) {
 	gitOpsConfigReadServiceImpl := config2.NewGitOpsConfigReadServiceImpl(sugaredLogger, gitOpsConfigRepositoryImpl, userServiceImpl, environmentVariables, moduleReadServiceImpl)
 	deploymentTypeOverrideServiceImpl := providerConfig.NewDeploymentTypeOverrideServiceImpl(sugaredLogger, environmentVariables, attributesServiceImpl)
 	chartTemplateServiceImpl := util.NewChartTemplateServiceImpl(sugaredLogger)
-	appStoreDeploymentCommonServiceImpl := appStoreDeploymentCommon.NewAppStoreDeploymentCommonServiceImpl(sugaredLogger, appStoreApplicationVersionRepositoryImpl, chartTemplateServiceImpl, userServiceImpl, helmAppServiceImpl, installedAppDBServiceImpl)
-	eaModeDeploymentServiceImpl := deployment.NewEAModeDeploymentServiceImpl(sugaredLogger, helmAppServiceImpl, appStoreApplicationVersionRepositoryImpl, helmAppClientImpl, installedAppRepositoryImpl, ociRegistryConfigRepositoryImpl, appStoreDeploymentCommonServiceImpl, helmAppReadServiceImpl)
-	appStoreValidatorImpl := service2.NewAppAppStoreValidatorImpl(sugaredLogger)
+	chartMirrorRepositoryImpl := repository22.NewChartMirrorRepositoryImpl(db, sugaredLogger)
+	chartMirrorReadServiceImpl := read11.NewChartMirrorReadServiceImpl(sugaredLogger, chartMirrorRepositoryImpl, dockerArtifactStoreRepositoryImpl, ociRegistryConfigRepositoryImpl)
+	appStoreDeploymentCommonServiceImpl := appStoreDeploymentCommon.NewAppStoreDeploymentCommonServiceImpl(sugaredLogger, appStoreApplicationVersionRepositoryImpl, chartTemplateServiceImpl, userServiceImpl, helmAppServiceImpl, installedAppDBServiceImpl, chartMirrorReadServiceImpl)
+	eaModeDeploymentServiceImpl := deployment.NewEAModeDeploymentServiceImpl(sugaredLogger, helmAppServiceImpl, appStoreApplicationVersionRepositoryImpl, helmAppClientImpl, installedAppRepositoryImpl, ociRegistryConfigRepositoryImpl, appStoreDeploymentCommonServiceImpl, helmAppReadServiceImpl, chartMirrorReadServiceImpl)
+	valuesSchemaRepositoryImpl := repository20.NewValuesSchemaRepositoryImpl(db, sugaredLogger)
+	valuesSchemaConfig, err := valuesSchema.GetValuesSchemaConfig()
+	if err != nil {
+		return nil, err
+	}
+	valuesSchemaServiceImpl := valuesSchema.NewValuesSchemaServiceImpl(sugaredLogger, valuesSchemaRepositoryImpl, appStoreApplicationVersionRepositoryImpl, valuesSchemaConfig)
+	appStoreValidatorImpl := service2.NewAppAppStoreValidatorImpl(sugaredLogger, valuesSchemaServiceImpl)
 	appStoreDeploymentDBServiceImpl := service2.NewAppStoreDeploymentDBServiceImpl(sugaredLogger, installedAppRepositoryImpl, appStoreApplicationVersionRepositoryImpl, appRepositoryImpl, environmentServiceImpl, installedAppVersionHistoryRepositoryImpl, environmentVariables, gitOpsConfigReadServiceImpl, deploymentTypeOverrideServiceImpl, eaModeDeploymentServiceImpl, appStoreValidatorImpl, installedAppDBServiceImpl, deploymentConfigServiceImpl, clusterReadServiceImpl)
 	chartGroupDeploymentRepositoryImpl := repository9.NewChartGroupDeploymentRepositoryImpl(db, sugaredLogger)
 	acdConfig, err := argocdServer.GetACDDeploymentConfig()
@@ -373,10 +439,21 @@ func InitializeApp() (*App, error) {
 	helmAppRestHandlerImpl := client2.NewHelmAppRestHandlerImpl(sugaredLogger, helmAppServiceImpl, enforcerImpl, clusterServiceImpl, enforcerUtilHelmImpl, appStoreDeploymentServiceImpl, installedAppDBServiceImpl, userServiceImpl, attributesServiceImpl, serverEnvConfigServerEnvConfig, fluxApplicationServiceImpl, argoApplicationServiceImpl)
 	helmAppRouterImpl := client2.NewHelmAppRouterImpl(helmAppRestHandlerImpl)
 	environmentReadServiceImpl := read8.NewEnvironmentReadServiceImpl(sugaredLogger, environmentRepositoryImpl)
-	environmentRestHandlerImpl := cluster2.NewEnvironmentRestHandlerImpl(environmentServiceImpl, environmentReadServiceImpl, sugaredLogger, userServiceImpl, validate, enforcerImpl, deleteServiceImpl, k8sServiceImpl, k8sCommonServiceImpl, commonEnforcementUtilImpl)
+	environmentRestHandlerImpl := cluster2.NewEnvironmentRestHandlerImpl(environmentServiceImpl, environmentReadServiceImpl, sugaredLogger, userServiceImpl, validate, enforcerImpl, deleteServiceImpl, k8sServiceImpl, k8sCommonServiceImpl, commonEnforcementUtilImpl, namespaceSpecServiceImpl)
 	environmentRouterImpl := cluster2.NewEnvironmentRouterImpl(environmentRestHandlerImpl)
 	argoApplicationReadServiceImpl := read9.NewArgoApplicationReadServiceImpl(sugaredLogger, clusterRepositoryImpl, k8sServiceImpl, helmAppClientImpl, helmAppServiceImpl)
-	k8sApplicationRestHandlerImpl := application2.NewK8sApplicationRestHandlerImpl(sugaredLogger, k8sApplicationServiceImpl, pumpImpl, terminalSessionHandlerImpl, enforcerImpl, enforcerUtilHelmImpl, enforcerUtilImpl, helmAppServiceImpl, userServiceImpl, k8sCommonServiceImpl, validate, environmentVariables, fluxApplicationServiceImpl, argoApplicationReadServiceImpl)
+	resourceSearchConfig, err := resourceSearch.GetResourceSearchConfig()
+	if err != nil {
+		return nil, err
+	}
+	resourceBrowserViewRepositoryImpl := repository15.NewResourceBrowserViewRepositoryImpl(db, sugaredLogger)
+	resourceSearchServiceImpl := resourceSearch.NewResourceSearchServiceImpl(sugaredLogger, k8sCommonServiceImpl, k8sServiceImpl, resourceBrowserViewRepositoryImpl, resourceSearchConfig)
+	resourceWatchConfig, err := resourceWatch.GetResourceWatchConfig()
+	if err != nil {
+		return nil, err
+	}
+	resourceWatchServiceImpl := resourceWatch.NewResourceWatchServiceImpl(sugaredLogger, k8sCommonServiceImpl, k8sServiceImpl, resourceWatchConfig)
+	k8sApplicationRestHandlerImpl := application2.NewK8sApplicationRestHandlerImpl(sugaredLogger, k8sApplicationServiceImpl, pumpImpl, terminalSessionHandlerImpl, enforcerImpl, enforcerUtilHelmImpl, enforcerUtilImpl, helmAppServiceImpl, userServiceImpl, k8sCommonServiceImpl, validate, environmentVariables, fluxApplicationServiceImpl, argoApplicationReadServiceImpl, resourceSearchServiceImpl, resourceWatchServiceImpl)
 	k8sApplicationRouterImpl := application2.NewK8sApplicationRouterImpl(k8sApplicationRestHandlerImpl)
 	chartRepositoryRestHandlerImpl := chartRepo2.NewChartRepositoryRestHandlerImpl(sugaredLogger, userServiceImpl, chartRepositoryServiceImpl, enforcerImpl, validate, deleteServiceImpl, attributesServiceImpl)
 	chartRepositoryRouterImpl := chartRepo2.NewChartRepositoryRouterImpl(chartRepositoryRestHandlerImpl)
@@ -385,13 +462,35 @@ func InitializeApp() (*App, error) {
 	appStoreDiscoverRouterImpl := appStoreDiscover.NewAppStoreDiscoverRouterImpl(appStoreRestHandlerImpl)
 	appStoreVersionValuesRepositoryImpl := appStoreValuesRepository.NewAppStoreVersionValuesRepositoryImpl(sugaredLogger, db)
 	appStoreValuesServiceImpl := service4.NewAppStoreValuesServiceImpl(sugaredLogger, appStoreApplicationVersionRepositoryImpl, installedAppRepositoryImpl, installedAppReadServiceEAImpl, appStoreVersionValuesRepositoryImpl, userServiceImpl)
-	appStoreValuesRestHandlerImpl := appStoreValues.NewAppStoreValuesRestHandlerImpl(sugaredLogger, userServiceImpl, appStoreValuesServiceImpl)
+	appStoreValuesRestHandlerImpl := appStoreValues.NewAppStoreValuesRestHandlerImpl(sugaredLogger, userServiceImpl, appStoreValuesServiceImpl, valuesSchemaServiceImpl)
 	appStoreValuesRouterImpl := appStoreValues.NewAppStoreValuesRouterImpl(appStoreValuesRestHandlerImpl)
 	appStoreDeploymentRestHandlerImpl := appStoreDeployment.NewAppStoreDeploymentRestHandlerImpl(sugaredLogger, userServiceImpl, enforcerImpl, enforcerUtilImpl, enforcerUtilHelmImpl, appStoreDeploymentServiceImpl, appStoreDeploymentDBServiceImpl, validate, helmAppServiceImpl, installedAppDBServiceImpl, attributesServiceImpl)
-	appStoreDeploymentRouterImpl := appStoreDeployment.NewAppStoreDeploymentRouterImpl(appStoreDeploymentRestHandlerImpl)
+	chartUpgradeAdvisoryRepositoryImpl := repository19.NewChartUpgradeAdvisoryRepositoryImpl(db, sugaredLogger)
+	upgradeAdvisorConfig, err := upgradeAdvisor.GetUpgradeAdvisorConfig()
+	if err != nil {
+		return nil, err
+	}
+	upgradeAdvisorServiceImpl, err := upgradeAdvisor.NewUpgradeAdvisorServiceImpl(sugaredLogger, chartUpgradeAdvisoryRepositoryImpl, appStoreApplicationVersionRepositoryImpl, installedAppRepositoryImpl, helmAppServiceImpl, upgradeAdvisorConfig, cronLoggerImpl)
+	if err != nil {
+		return nil, err
+	}
+	upgradeAdvisorRestHandlerImpl := appStoreDeployment.NewUpgradeAdvisorRestHandlerImpl(sugaredLogger, userServiceImpl, enforcerImpl, enforcerUtilImpl, enforcerUtilHelmImpl, upgradeAdvisorServiceImpl)
+	helmReleaseAdoptionRepositoryImpl := repository21.NewHelmReleaseAdoptionRepositoryImpl(db, sugaredLogger)
+	releaseAdoptionServiceImpl := adoption.NewReleaseAdoptionServiceImpl(sugaredLogger, helmReleaseAdoptionRepositoryImpl, helmAppServiceImpl, appStoreApplicationVersionRepositoryImpl, installedAppReadServiceEAImpl, appStoreDeploymentServiceImpl)
+	releaseAdoptionRestHandlerImpl := appStoreDeployment.NewReleaseAdoptionRestHandlerImpl(sugaredLogger, userServiceImpl, enforcerImpl, enforcerUtilHelmImpl, helmAppServiceImpl, releaseAdoptionServiceImpl, validate)
+	appStoreDeploymentRouterImpl := appStoreDeployment.NewAppStoreDeploymentRouterImpl(appStoreDeploymentRestHandlerImpl, upgradeAdvisorRestHandlerImpl, releaseAdoptionRestHandlerImpl)
 	chartProviderServiceImpl := chartProvider.NewChartProviderServiceImpl(sugaredLogger, chartRepoRepositoryImpl, chartRepositoryServiceImpl, dockerArtifactStoreRepositoryImpl, ociRegistryConfigRepositoryImpl)
 	chartProviderRestHandlerImpl := chartProvider2.NewChartProviderRestHandlerImpl(sugaredLogger, userServiceImpl, validate, chartProviderServiceImpl, enforcerImpl)
-	chartProviderRouterImpl := chartProvider2.NewChartProviderRouterImpl(chartProviderRestHandlerImpl)
+	chartMirrorConfig, err := chartMirror.GetChartMirrorConfig()
+	if err != nil {
+		return nil, err
+	}
+	chartMirrorServiceImpl, err := chartMirror.NewChartMirrorServiceImpl(sugaredLogger, chartMirrorRepositoryImpl, appStoreApplicationVersionRepositoryImpl, dockerArtifactStoreRepositoryImpl, chartMirrorConfig, cronLoggerImpl)
+	if err != nil {
+		return nil, err
+	}
+	chartMirrorRestHandlerImpl := chartProvider2.NewChartMirrorRestHandlerImpl(sugaredLogger, userServiceImpl, validate, chartMirrorServiceImpl, enforcerImpl)
+	chartProviderRouterImpl := chartProvider2.NewChartProviderRouterImpl(chartProviderRestHandlerImpl, chartMirrorRestHandlerImpl)
 	dockerRegRestHandlerImpl := restHandler.NewDockerRegRestHandlerImpl(dockerRegistryConfigImpl, sugaredLogger, chartProviderServiceImpl, userServiceImpl, validate, enforcerImpl, teamServiceImpl, deleteServiceImpl)
 	dockerRegRouterImpl := router.NewDockerRegRouterImpl(dockerRegRestHandlerImpl)
 	posthogClient, err := telemetry.NewPosthogClient(sugaredLogger)
@@ -456,7 +555,25 @@ func InitializeApp() (*App, error) {
 	apiTokenRestHandlerImpl := apiToken2.NewApiTokenRestHandlerImpl(sugaredLogger, apiTokenServiceImpl, userServiceImpl, enforcerImpl, validate)
 	apiTokenRouterImpl := apiToken2.NewApiTokenRouterImpl(apiTokenRestHandlerImpl)
 	k8sCapacityServiceImpl := capacity.NewK8sCapacityServiceImpl(sugaredLogger, k8sApplicationServiceImpl, k8sServiceImpl, k8sCommonServiceImpl)
-	k8sCapacityRestHandlerImpl := capacity2.NewK8sCapacityRestHandlerImpl(sugaredLogger, k8sCapacityServiceImpl, userServiceImpl, enforcerImpl, clusterServiceImpl, environmentServiceImpl, clusterRbacServiceImpl, clusterReadServiceImpl, validate)
+	nodeMaintenanceConfig, err := nodeMaintenance.GetNodeMaintenanceConfig()
+	if err != nil {
+		return nil, err
+	}
+	nodeMaintenanceRepositoryImpl := repository16.NewNodeMaintenanceRepositoryImpl(db, sugaredLogger, transactionUtilImpl)
+	nodeMaintenanceServiceImpl, err := nodeMaintenance.NewNodeMaintenanceServiceImpl(sugaredLogger, k8sCommonServiceImpl, k8sCapacityServiceImpl, nodeMaintenanceRepositoryImpl, nodeMaintenanceConfig, cronLoggerImpl)
+	if err != nil {
+		return nil, err
+	}
+	costAllocationConfig, err := costAllocation.GetCostAllocationConfig()
+	if err != nil {
+		return nil, err
+	}
+	costAllocationRepositoryImpl := repository17.NewCostAllocationRepositoryImpl(db, sugaredLogger, transactionUtilImpl)
+	costAllocationServiceImpl, err := costAllocation.NewCostAllocationServiceImpl(sugaredLogger, clusterServiceImpl, k8sCommonServiceImpl, k8sServiceImpl, appRepositoryImpl, environmentRepositoryImpl, teamRepositoryImpl, costAllocationRepositoryImpl, costAllocationConfig, cronLoggerImpl)
+	if err != nil {
+		return nil, err
+	}
+	k8sCapacityRestHandlerImpl := capacity2.NewK8sCapacityRestHandlerImpl(sugaredLogger, k8sCapacityServiceImpl, userServiceImpl, enforcerImpl, clusterServiceImpl, environmentServiceImpl, clusterRbacServiceImpl, clusterReadServiceImpl, validate, nodeMaintenanceServiceImpl, costAllocationServiceImpl)
 	k8sCapacityRouterImpl := capacity2.NewK8sCapacityRouterImpl(k8sCapacityRestHandlerImpl)
 	webhookHelmServiceImpl := webhookHelm.NewWebhookHelmServiceImpl(sugaredLogger, helmAppServiceImpl, clusterServiceImpl, chartRepositoryServiceImpl, attributesServiceImpl)
 	webhookHelmRestHandlerImpl := webhookHelm2.NewWebhookHelmRestHandlerImpl(sugaredLogger, webhookHelmServiceImpl, userServiceImpl, enforcerImpl, validate)
@@ -25,6 +25,17 @@ import (
 
 const EmptyLikeRegex = "%%"
 
+// promotedArtifactsQuery selects artifacts promoted to a cd pipeline, these are deployable irrespective of the parent stage
+const promotedArtifactsQuery = "SELECT apr.ci_artifact_id FROM artifact_promotion_request apr WHERE apr.target_pipeline_id = %d AND apr.status = 'PROMOTED'"
+
+// getPromotedArtifactsCondition returns an OR condition on artifactIdColumn for artifacts promoted to the pipeline, if requested
+func getPromotedArtifactsCondition(listingFilterOptions bean.ArtifactsListFilterOptions, artifactIdColumn string) string {
+	if !listingFilterOptions.IncludePromotedArtifacts {
+		return ""
+	}
+	return fmt.Sprintf(" OR %s IN (%s)", artifactIdColumn, fmt.Sprintf(promotedArtifactsQuery, listingFilterOptions.PipelineId))
+}
+
 func BuildQueryForParentTypeCIOrWebhook(listingFilterOpts bean.ArtifactsListFilterOptions) (string, []interface{}) {
 	commonPaginatedQueryPart, commonPaginatedQueryParams := " cia.image LIKE ?", []interface{}{listingFilterOpts.SearchString}
 	orderByClause := " ORDER BY cia.id DESC"
@@ -38,6 +49,13 @@ func BuildQueryForParentTypeCIOrWebhook(listingFilterOpts bean.ArtifactsListFilt
 			" INNER JOIN ci_pipeline cp ON (cp.id=cia.pipeline_id or (cp.id=cia.component_id and cia.data_source='post_ci' ) )" +
 			" INNER JOIN pipeline p ON (p.ci_pipeline_id = cp.id and p.id=? )" +
 			" WHERE "
+		if listingFilterOpts.IncludePromotedArtifacts {
+			// promoted artifacts are built by other ci pipelines, so the ci pipeline is matched in a sub query instead of a join
+			remainingQuery = " FROM ci_artifact cia" +
+				" WHERE (EXISTS (SELECT 1 FROM ci_pipeline cp INNER JOIN pipeline p ON (p.ci_pipeline_id = cp.id and p.id=? )" +
+				" WHERE cp.id=cia.pipeline_id or (cp.id=cia.component_id and cia.data_source='post_ci' ))" +
+				getPromotedArtifactsCondition(listingFilterOpts, "cia.id") + ") AND "
+		}
 		remainingQueryParams = []interface{}{listingFilterOpts.PipelineId}
 		if len(listingFilterOpts.ExcludeArtifactIds) > 0 {
 			remainingQuery += "cia.id NOT IN (?) AND "
@@ -51,7 +69,7 @@ func BuildQueryForParentTypeCIOrWebhook(listingFilterOpts bean.ArtifactsListFilt
 	} else if listingFilterOpts.ParentStageType == bean.WEBHOOK_WORKFLOW_TYPE {
 		selectQuery := " SELECT cia.* "
 		remainingQuery := " FROM ci_artifact cia " +
-			" WHERE cia.external_ci_pipeline_id = ? AND "
+			" WHERE (cia.external_ci_pipeline_id = ?" + getPromotedArtifactsCondition(listingFilterOpts, "cia.id") + ") AND "
 		remainingQueryParams = []interface{}{listingFilterOpts.ParentId}
 		if len(listingFilterOpts.ExcludeArtifactIds) > 0 {
 			remainingQuery += "cia.id NOT IN (?) AND "
@@ -83,10 +101,10 @@ func BuildQueryForArtifactsForCdStage(listingFilterOptions bean.ArtifactsListFil
 		" LEFT JOIN cd_workflow_runner ON cd_workflow_runner.cd_workflow_id=cd_workflow.id " +
 		" Where (((cd_workflow_runner.id in (select MAX(cd_workflow_runner.id) OVER (PARTITION BY cd_workflow.ci_artifact_id) FROM cd_workflow_runner inner join cd_workflow on cd_workflow.id=cd_workflow_runner.cd_workflow_id))" +
 		" AND ((cd_workflow.pipeline_id= %v and cd_workflow_runner.workflow_type = '%v' ) OR (cd_workflow.pipeline_id = %v AND cd_workflow_runner.workflow_type = '%v' AND cd_workflow_runner.status IN ('Healthy','Succeeded') )))" +
-		" OR (ci_artifact.component_id = %v  and ci_artifact.data_source= '%v' ))" +
+		" OR (ci_artifact.component_id = %v  and ci_artifact.data_source= '%v' )%s)" +
 		" AND (ci_artifact.image LIKE '%v' )"
 
-	commonQuery = fmt.Sprintf(commonQuery, listingFilterOptions.PipelineId, listingFilterOptions.StageType, listingFilterOptions.ParentId, listingFilterOptions.ParentStageType, listingFilterOptions.ParentId, listingFilterOptions.PluginStage, listingFilterOptions.SearchString)
+	commonQuery = fmt.Sprintf(commonQuery, listingFilterOptions.PipelineId, listingFilterOptions.StageType, listingFilterOptions.ParentId, listingFilterOptions.ParentStageType, listingFilterOptions.ParentId, listingFilterOptions.PluginStage, getPromotedArtifactsCondition(listingFilterOptions, "ci_artifact.id"), listingFilterOptions.SearchString)
 	if len(listingFilterOptions.ExcludeArtifactIds) > 0 {
 		commonQuery = commonQuery + fmt.Sprintf(" AND ( ci_artifact.id NOT IN (%v))", helper.GetCommaSepratedString(listingFilterOptions.ExcludeArtifactIds))
 	}
@@ -116,7 +134,7 @@ func buildQueryForArtifactsForCdStageV2(listingFilterOptions bean.ArtifactsListF
 		"           )"+
 		"      )   ) ", listingFilterOptions.PipelineId, listingFilterOptions.ParentId, listingFilterOptions.PipelineId, listingFilterOptions.StageType, listingFilterOptions.ParentId, listingFilterOptions.ParentStageType)
 
-	whereCondition = fmt.Sprintf(" %s OR (ci_artifact.component_id = %d  AND ci_artifact.data_source= '%s' ))", whereCondition, listingFilterOptions.ParentId, listingFilterOptions.PluginStage)
+	whereCondition = fmt.Sprintf(" %s OR (ci_artifact.component_id = %d  AND ci_artifact.data_source= '%s' )%s)", whereCondition, listingFilterOptions.ParentId, listingFilterOptions.PluginStage, getPromotedArtifactsCondition(listingFilterOptions, "ci_artifact.id"))
 	if listingFilterOptions.SearchString != EmptyLikeRegex {
 		whereCondition = whereCondition + fmt.Sprintf(" AND ci_artifact.image LIKE '%s' ", listingFilterOptions.SearchString)
 	}
@@ -59,6 +59,14 @@ type GitOpsConfig struct {
 	TlsCert               string   `sql:"tls_cert"`
 	TlsKey                string   `sql:"tls_key"`
 	CaCert                string   `sql:"ca_cert"`
+	GiteaOrgName          string   `sql:"gitea_org_name"`
+	SshKey                string   `sql:"ssh_key"`
+	SshKnownHosts         string   `sql:"ssh_known_hosts"`
+	RepoHookUrl           string   `sql:"repo_hook_url"`
+	DryRunRepoName        string   `sql:"dry_run_repo_name"`
+	RepositoryLayout      string   `sql:"repository_layout"`
+	MonorepoName          string   `sql:"monorepo_name"`
+	MonorepoPathTemplate  string   `sql:"monorepo_path_template"`
 	sql.AuditLog
 }
 
@@ -65,6 +65,7 @@ type CdWorkflowRepository interface {
 
 	FindByWorkflowIdAndRunnerType(ctx context.Context, wfId int, runnerType apiBean.WorkflowType) (CdWorkflowRunner, error)
 	FindLatestByPipelineIdAndRunnerType(pipelineId int, runnerType apiBean.WorkflowType) (CdWorkflowRunner, error)
+	FindLatestRunnerByPipelineIdArtifactIdAndRunnerType(pipelineId, artifactId int, runnerType apiBean.WorkflowType) (CdWorkflowRunner, error)
 	SaveWorkFlows(wfs ...*CdWorkflow) error
 	IsLatestWf(pipelineId int, wfId int) (bool, error)
 	FindLatestCdWorkflowByPipelineId(pipelineIds []int) (*CdWorkflow, error)
@@ -80,6 +81,13 @@ type CdWorkflowRepository interface {
 	MigrateIsArtifactUploaded(wfrId int, isArtifactUploaded bool)
 	MigrateCdArtifactLocation(wfrId int, cdArtifactLocation string)
 	FindDeployedCdWorkflowRunnersByPipelineId(pipelineId int) ([]*CdWorkflowRunner, error)
+	// FindDeployRunnersAwaitingMerge returns deploy runners of active pipelines waiting for their gitops pull request
+	FindDeployRunnersAwaitingMerge() ([]*CdWorkflowRunner, error)
+	// FindPreviousDeployRunnersByPullRequestState returns the deploy runners of the pipeline before currentWfrId with their pull request in the given state
+	FindPreviousDeployRunnersByPullRequestState(pipelineId int, currentWfrId int, pullRequestState string) ([]*CdWorkflowRunner, error)
+	// UpdateWorkFlowRunnerWithTxIfStatus updates the runner only while it is still in expectedStatus,
+	// false is returned when another process moved the runner on in the meantime
+	UpdateWorkFlowRunnerWithTxIfStatus(wfr *CdWorkflowRunner, expectedStatus string, tx *pg.Tx) (bool, error)
 }
 
 type CdWorkflowRepositoryImpl struct {
@@ -127,6 +135,10 @@ type CdWorkflowRunner struct {
 	ImagePathReservationIds []int                               `sql:"image_path_reservation_ids" pg:",array,notnull"`
 	ReferenceId             *string                             `sql:"reference_id"`
 	ImageState              constants.ImageStateWhileDeployment `sql:"image_state"` // image_state currently not utilized in oss
+	PullRequestUrl          string                              `sql:"pull_request_url"`
+	PullRequestNumber       int                                 `sql:"pull_request_number"`
+	PullRequestState        string                              `sql:"pull_request_state"`
+	PullRequestBranch       string                              `sql:"pull_request_branch"`
 	CdWorkflow              *CdWorkflow
 	sql.AuditLog
 }
@@ -570,6 +582,23 @@ func (impl *CdWorkflowRepositoryImpl) FindLatestByPipelineIdAndRunnerType(pipeli
 	return wfr, err
 }
 
+func (impl *CdWorkflowRepositoryImpl) FindLatestRunnerByPipelineIdArtifactIdAndRunnerType(pipelineId, artifactId int, runnerType apiBean.WorkflowType) (CdWorkflowRunner, error) {
+	wfr := CdWorkflowRunner{}
+	err := impl.dbConnection.
+		Model(&wfr).
+		Column("cd_workflow_runner.*", "CdWorkflow", "CdWorkflow.Pipeline", "CdWorkflow.CiArtifact").
+		Where("cd_workflow.pipeline_id = ?", pipelineId).
+		Where("cd_workflow.ci_artifact_id = ?", artifactId).
+		Where("cd_workflow_runner.workflow_type = ?", runnerType).
+		Order("cd_workflow_runner.id DESC").
+		Limit(1).
+		Select()
+	if err != nil {
+		return wfr, err
+	}
+	return wfr, err
+}
+
 func (impl *CdWorkflowRepositoryImpl) IsLatestWf(pipelineId int, wfId int) (bool, error) {
 	exists, err := impl.dbConnection.Model(&CdWorkflow{}).
 		Where("pipeline_id =?", pipelineId).
@@ -677,7 +706,7 @@ func (impl *CdWorkflowRepositoryImpl) FetchArtifactsByCdPipelineIdV2(listingFilt
 func (impl *CdWorkflowRepositoryImpl) GetLatestTriggersOfPipelinesStuckInNonTerminalStatuses(getPipelineDeployedWithinHours int, deploymentAppType string) ([]*CdWorkflowRunner, error) {
 	var wfrList []*CdWorkflowRunner
 	excludedStatusList := cdWorkflow.WfrTerminalStatusList
-	excludedStatusList = append(excludedStatusList, cdWorkflow.WorkflowInitiated, cdWorkflow.WorkflowInQueue, cdWorkflow.WorkflowStarting)
+	excludedStatusList = append(excludedStatusList, cdWorkflow.WorkflowInitiated, cdWorkflow.WorkflowInQueue, cdWorkflow.WorkflowStarting, cdWorkflow.WorkflowAwaitingMerge)
 	err := impl.dbConnection.
 		Model(&wfrList).
 		Column("cd_workflow_runner.*", "CdWorkflow.id", "CdWorkflow.pipeline_id", "CdWorkflow.Pipeline.id", "CdWorkflow.Pipeline.app_id", "CdWorkflow.Pipeline.environment_id", "CdWorkflow.Pipeline.deployment_app_name", "CdWorkflow.Pipeline.deleted", "CdWorkflow.Pipeline.Environment").
@@ -811,3 +840,49 @@ SELECT "app_id","env_id","ci_artifact_id","parent_ci_artifact","scanned" FROM Ra
 	}
 	return runners, nil
 }
+
+func (impl *CdWorkflowRepositoryImpl) FindDeployRunnersAwaitingMerge() ([]*CdWorkflowRunner, error) {
+	var wfrList []*CdWorkflowRunner
+	err := impl.dbConnection.
+		Model(&wfrList).
+		Column("cd_workflow_runner.*", "CdWorkflow.id", "CdWorkflow.pipeline_id", "CdWorkflow.Pipeline.id", "CdWorkflow.Pipeline.app_id", "CdWorkflow.Pipeline.environment_id", "CdWorkflow.Pipeline.deployment_app_name", "CdWorkflow.Pipeline.deleted").
+		Where("cd_workflow_runner.workflow_type = ?", apiBean.CD_WORKFLOW_TYPE_DEPLOY).
+		Where("cd_workflow_runner.status = ?", cdWorkflow.WorkflowAwaitingMerge).
+		Where("cd_workflow__pipeline.deleted = ?", false).
+		Order("cd_workflow_runner.id ASC").
+		Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting deploy runners awaiting merge", "err", err)
+		return nil, err
+	}
+	return wfrList, nil
+}
+
+func (impl *CdWorkflowRepositoryImpl) FindPreviousDeployRunnersByPullRequestState(pipelineId int, currentWfrId int, pullRequestState string) ([]*CdWorkflowRunner, error) {
+	var wfrList []*CdWorkflowRunner
+	err := impl.dbConnection.
+		Model(&wfrList).
+		Column("cd_workflow_runner.*", "CdWorkflow").
+		Where("cd_workflow.pipeline_id = ?", pipelineId).
+		Where("cd_workflow_runner.id < ?", currentWfrId).
+		Where("cd_workflow_runner.workflow_type = ?", apiBean.CD_WORKFLOW_TYPE_DEPLOY).
+		Where("cd_workflow_runner.pull_request_state = ?", pullRequestState).
+		Order("cd_workflow_runner.id ASC").
+		Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting previous deploy runners by pull request state", "pipelineId", pipelineId, "pullRequestState", pullRequestState, "err", err)
+		return nil, err
+	}
+	return wfrList, nil
+}
+
+func (impl *CdWorkflowRepositoryImpl) UpdateWorkFlowRunnerWithTxIfStatus(wfr *CdWorkflowRunner, expectedStatus string, tx *pg.Tx) (bool, error) {
+	res, err := tx.Model(wfr).
+		WherePK().
+		Where("status = ?", expectedStatus).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
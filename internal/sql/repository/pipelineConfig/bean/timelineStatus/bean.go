@@ -39,6 +39,9 @@ const (
 	TIMELINE_STATUS_GIT_COMMIT_FAILED            TimelineStatus = "GIT_COMMIT_FAILED"
 	TIMELINE_STATUS_ARGOCD_SYNC_INITIATED        TimelineStatus = "ARGOCD_SYNC_INITIATED"
 	TIMELINE_STATUS_ARGOCD_SYNC_COMPLETED        TimelineStatus = "ARGOCD_SYNC_COMPLETED"
+	// TIMELINE_STATUS_GIT_PULL_REQUEST_OPENED - values are committed to a branch of the gitops repo and wait for the pull request merge
+	TIMELINE_STATUS_GIT_PULL_REQUEST_OPENED TimelineStatus = "GIT_PULL_REQUEST_OPENED"
+	TIMELINE_STATUS_GIT_PULL_REQUEST_MERGED TimelineStatus = "GIT_PULL_REQUEST_MERGED"
 	// TIMELINE_STATUS_DEPLOYMENT_TRIGGERED - is not a terminal status.
 	// It indicates that the deployment request has been served to Kubernetes CD agents (helm/ ArgoCD).
 	TIMELINE_STATUS_DEPLOYMENT_TRIGGERED TimelineStatus = "DEPLOYMENT_TRIGGERED"
@@ -62,4 +65,7 @@ const (
 	TIMELINE_DESCRIPTION_ARGOCD_SYNC_COMPLETED        string = "ArgoCD sync completed."
 	TIMELINE_DESCRIPTION_DEPLOYMENT_COMPLETED         string = "Deployment has been performed successfully. Waiting for application to be healthy..."
 	TIMELINE_DESCRIPTION_DEPLOYMENT_SUPERSEDED        string = "This deployment is superseded."
+	TIMELINE_DESCRIPTION_GIT_PULL_REQUEST_OPENED      string = "Pull request %s opened. Waiting for it to be merged..."
+	TIMELINE_DESCRIPTION_GIT_PULL_REQUEST_MERGED      string = "Pull request %s merged."
+	TIMELINE_DESCRIPTION_GIT_PULL_REQUEST_CLOSED      string = "Deployment failed: pull request %s was closed without merging."
 )
@@ -35,6 +35,8 @@ const (
 	WorkflowTypePre            = "PRE"
 	WorkflowTypePost           = "POST"
 	WorkflowWaitingToStart     = "WaitingToStart"
+	// WorkflowAwaitingMerge - values are committed to a pull request, deployment resumes once it is merged
+	WorkflowAwaitingMerge = "AwaitingMerge"
 )
 
 func (a WorkflowStatus) String() string {
@@ -44,12 +46,13 @@ func (a WorkflowStatus) String() string {
 var ErrorDeploymentSuperseded = errors.New(NEW_DEPLOYMENT_INITIATED)
 
 const (
-	WORKFLOW_EXECUTOR_TYPE_AWF    = "AWF"
-	WORKFLOW_EXECUTOR_TYPE_SYSTEM = "SYSTEM"
-	NEW_DEPLOYMENT_INITIATED      = "A new deployment was initiated before this deployment completed!"
-	PIPELINE_DELETED              = "The pipeline has been deleted!"
-	FOUND_VULNERABILITY           = "Found vulnerability on image"
-	GITOPS_REPO_NOT_CONFIGURED    = "GitOps repository is not configured for the app"
+	WORKFLOW_EXECUTOR_TYPE_AWF          = "AWF"
+	WORKFLOW_EXECUTOR_TYPE_SYSTEM       = "SYSTEM"
+	NEW_DEPLOYMENT_INITIATED            = "A new deployment was initiated before this deployment completed!"
+	PIPELINE_DELETED                    = "The pipeline has been deleted!"
+	FOUND_VULNERABILITY                 = "Found vulnerability on image"
+	GITOPS_REPO_NOT_CONFIGURED          = "GitOps repository is not configured for the app"
+	IMAGE_SIGNATURE_VERIFICATION_FAILED = "Image signature verification failed"
 )
 
 type WorkflowExecutorType string
@@ -515,6 +515,34 @@ func (_m *CdWorkflowRepository) FindLatestByPipelineIdAndRunnerType(pipelineId i
 	return r0, r1
 }
 
+// FindLatestRunnerByPipelineIdArtifactIdAndRunnerType provides a mock function with given fields: pipelineId, artifactId, runnerType
+func (_m *CdWorkflowRepository) FindLatestRunnerByPipelineIdArtifactIdAndRunnerType(pipelineId int, artifactId int, runnerType bean.WorkflowType) (pipelineConfig.CdWorkflowRunner, error) {
+	ret := _m.Called(pipelineId, artifactId, runnerType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLatestRunnerByPipelineIdArtifactIdAndRunnerType")
+	}
+
+	var r0 pipelineConfig.CdWorkflowRunner
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int, bean.WorkflowType) (pipelineConfig.CdWorkflowRunner, error)); ok {
+		return rf(pipelineId, artifactId, runnerType)
+	}
+	if rf, ok := ret.Get(0).(func(int, int, bean.WorkflowType) pipelineConfig.CdWorkflowRunner); ok {
+		r0 = rf(pipelineId, artifactId, runnerType)
+	} else {
+		r0 = ret.Get(0).(pipelineConfig.CdWorkflowRunner)
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int, bean.WorkflowType) error); ok {
+		r1 = rf(pipelineId, artifactId, runnerType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindLatestCdWorkflowByPipelineId provides a mock function with given fields: pipelineIds
 func (_m *CdWorkflowRepository) FindLatestCdWorkflowByPipelineId(pipelineIds []int) (*pipelineConfig.CdWorkflow, error) {
 	ret := _m.Called(pipelineIds)
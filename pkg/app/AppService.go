@@ -488,6 +488,10 @@ func (impl *AppServiceImpl) CheckIfPipelineUpdateEventIsValid(app *v1alpha1.Appl
 		// drop event
 		return isValid, pipeline, cdWfr, pipelineOverride, nil
 	}
+	if cdWfr.Status == cdWorkflow2.WorkflowAwaitingMerge {
+		// values are not on the target revision till the pull request is merged, drop event
+		return isValid, pipeline, cdWfr, pipelineOverride, nil
+	}
 	deploymentConfig, err := impl.deploymentConfigService.GetConfigForDevtronApps(pipeline.AppId, pipeline.EnvironmentId)
 	if err != nil {
 		impl.logger.Errorw("error in getting deployment config by appId and environmentId", "appId", pipeline.AppId, "environmentId", pipeline.EnvironmentId, "err", err)
@@ -792,7 +796,11 @@ func (impl *AppServiceImpl) CreateGitOpsRepo(app *app.App, targetRevision string
 		impl.logger.Errorw("error in getting deployment config for devtron apps", "appId", app.Id, "err", err)
 		return "", nil, err
 	}
-	gitOpsRepoName = impl.gitOpsConfigReadService.GetGitOpsRepoName(app.AppName)
+	gitOpsRepoName, err = impl.gitOpsConfigReadService.GetDevtronAppGitOpsRepoName(app.AppName)
+	if err != nil {
+		impl.logger.Errorw("error in getting gitops repo name", "appName", app.AppName, "err", err)
+		return "", nil, err
+	}
 	chartGitAttr, err = impl.gitOperationService.CreateGitRepositoryForDevtronApp(context.Background(), gitOpsRepoName, targetRevision, userId)
 	if err != nil {
 		impl.logger.Errorw("error in pushing chart to git ", "gitOpsRepoName", gitOpsRepoName, "err", err)
@@ -16,7 +16,10 @@
 
 package bean
 
-import "time"
+import (
+	gitBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/bean"
+	"time"
+)
 
 const WORKFLOW_EXIST_ERROR = "workflow with this name already exist in this app"
 const Workflows = "workflows"
@@ -44,13 +47,32 @@ type ManifestPushTemplate struct {
 	BuiltChartBytes        *[]byte
 	MergedValues           string
 	ArgoSyncNeeded         bool
+	// PullRequestMode commits the chart and values to PullRequestBranch and opens a pull request against TargetRevision
+	PullRequestMode   bool
+	PullRequestBranch string
+}
+
+// GetCommitRevision returns the branch the chart and values are pushed to
+func (m *ManifestPushTemplate) GetCommitRevision() string {
+	if m.PullRequestMode {
+		return m.PullRequestBranch
+	}
+	return m.TargetRevision
 }
 
 type ManifestPushResponse struct {
 	NewGitRepoUrl string
-	CommitHash    string
-	CommitTime    time.Time
-	Error         error
+	// GitOpsPathPrefix is the directory of the app when the new repository is the gitops monorepo
+	GitOpsPathPrefix string
+	CommitHash       string
+	CommitTime       time.Time
+	// PullRequest is set when the values are committed in pull request mode
+	PullRequest *gitBean.PullRequestInfo
+	Error       error
+}
+
+func (m ManifestPushResponse) IsPullRequestOpened() bool {
+	return m.PullRequest != nil
 }
 
 func (m ManifestPushResponse) IsNewGitRepoConfigured() bool {
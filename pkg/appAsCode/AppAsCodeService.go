@@ -0,0 +1,528 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appAsCode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/client/gitSensor"
+	"github.com/devtron-labs/devtron/internal/sql/constants"
+	appRepository "github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appAsCode/bean"
+	"github.com/devtron-labs/devtron/pkg/appAsCode/helper"
+	"github.com/devtron-labs/devtron/pkg/appAsCode/repository"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	gitProviderRepository "github.com/devtron-labs/devtron/pkg/build/git/gitProvider/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cronUtil "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+const (
+	gitSensorTimeout = 2 * time.Minute
+	// sourceSyncTimeout is the time after which the sync claim of a source is taken as abandoned by a lost orchestrator
+	sourceSyncTimeout = 30 * time.Minute
+)
+
+var errSourceSyncInProgress = util.NewApiError(http.StatusConflict, "source is already being synced", "app-as-code source sync in progress")
+
+type AppAsCodeConfig struct {
+	SyncIntervalMins int `env:"APP_AS_CODE_SYNC_INTERVAL_MINS" envDefault:"5" description:"Interval in minutes at which app-as-code sources are checked for drift, new commits are picked up from git-sensor, 0 disables the periodic sync"`
+}
+
+// AppDefinitionManager reads and writes whole apps in the format of appBean.AppDetail, it is implemented by the core app api
+type AppDefinitionManager interface {
+	// GetAppDefinition builds the current state of the app, enforce is checked for every environment override
+	GetAppDefinition(ctx context.Context, appId int, enforce func(resource, action, object string) bool) (*appBean.AppDetail, error)
+	CreateAppFromDefinition(ctx context.Context, definition *appBean.AppDetail, userId int32) (int, error)
+	// ApplyAppDefinitionSection updates a section of an existing app to the desired definition,
+	// the returned messages describe the changes of the section which are not supported and were skipped
+	ApplyAppDefinitionSection(ctx context.Context, appId int, section string, desired *appBean.AppDetail, current *appBean.AppDetail, userId int32) ([]string, error)
+}
+
+// AppAsCodeService manages git repositories holding declarative app definitions and reconciles the apps defined in them
+type AppAsCodeService interface {
+	CreateSource(request *bean.AppAsCodeSourceDto, userId int32) (*bean.AppAsCodeSourceDto, error)
+	UpdateSource(request *bean.AppAsCodeSourceDto, userId int32) (*bean.AppAsCodeSourceDto, error)
+	// DeleteSource stops managing the apps of the source, the apps themselves are left untouched
+	DeleteSource(id int, userId int32) error
+	GetSource(id int) (*bean.AppAsCodeSourceDto, error)
+	GetAllSources() ([]*bean.AppAsCodeSourceDto, error)
+	GetSourceApps(sourceId int) ([]*bean.AppAsCodeAppDto, error)
+	// GetAppStatus returns the sync state of an app, nil if the app is not managed by any source
+	GetAppStatus(appId int) (*bean.AppAsCodeAppDto, error)
+	// SyncSource applies the definitions in the source to the apps and records changes made outside git as drift
+	SyncSource(ctx context.Context, id int, userId int32) (*bean.SourceSyncResponse, error)
+	// HandleNewCommit syncs the source registered with git-sensor as ciPipelineMaterialId, false if the material is not of any source
+	HandleNewCommit(ciPipelineMaterialId int) (bool, error)
+	ExportApp(ctx context.Context, appId int, enforce func(resource, action, object string) bool) (*bean.ExportAppResponse, error)
+}
+
+type AppAsCodeServiceImpl struct {
+	logger                *zap.SugaredLogger
+	appAsCodeRepository   repository.AppAsCodeRepository
+	appDefinitionManager  AppDefinitionManager
+	appRepository         appRepository.AppRepository
+	gitProviderRepository gitProviderRepository.GitProviderRepository
+	gitSensorClient       gitSensor.Client
+}
+
+func NewAppAsCodeServiceImpl(logger *zap.SugaredLogger,
+	appAsCodeRepository repository.AppAsCodeRepository,
+	appDefinitionManager AppDefinitionManager,
+	appRepository appRepository.AppRepository,
+	gitProviderRepository gitProviderRepository.GitProviderRepository,
+	gitSensorClient gitSensor.Client,
+	cronLogger *cronUtil.CronLoggerImpl) (*AppAsCodeServiceImpl, error) {
+	impl := &AppAsCodeServiceImpl{
+		logger:                logger,
+		appAsCodeRepository:   appAsCodeRepository,
+		appDefinitionManager:  appDefinitionManager,
+		appRepository:         appRepository,
+		gitProviderRepository: gitProviderRepository,
+		gitSensorClient:       gitSensorClient,
+	}
+	cfg := &AppAsCodeConfig{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.SyncIntervalMins > 0 {
+		syncCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
+		_, err := syncCron.AddFunc(fmt.Sprintf("@every %dm", cfg.SyncIntervalMins), impl.syncAllSources)
+		if err != nil {
+			logger.Errorw("error in adding app-as-code sync cron", "err", err)
+			return nil, err
+		}
+		syncCron.Start()
+	}
+	return impl, nil
+}
+
+func (impl *AppAsCodeServiceImpl) CreateSource(request *bean.AppAsCodeSourceDto, userId int32) (*bean.AppAsCodeSourceDto, error) {
+	if err := impl.checkSourceName(request.Name); err != nil {
+		return nil, err
+	}
+	source := &repository.AppAsCodeSource{Active: true, AuditLog: sql.NewDefaultAuditLog(userId)}
+	setSourceFields(source, request)
+	var err error
+	source.GitMaterialId, source.CiPipelineMaterialId, err = impl.appAsCodeRepository.ReserveGitSensorMaterialIds()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := impl.appAsCodeRepository.StartTx()
+	if err != nil {
+		return nil, err
+	}
+	defer impl.appAsCodeRepository.RollbackTx(tx)
+	// saved before the registration so that a failed registration leaves no source behind
+	if err = impl.appAsCodeRepository.SaveSource(source, tx); err != nil {
+		impl.logger.Errorw("error in saving app-as-code source", "request", request, "err", err)
+		return nil, err
+	}
+	if err = impl.registerSourceInGitSensor(source, true); err != nil {
+		return nil, err
+	}
+	if err = impl.appAsCodeRepository.CommitTx(tx); err != nil {
+		impl.logger.Errorw("error in committing app-as-code source", "sourceId", source.Id, "err", err)
+		if unregisterErr := impl.unregisterSourceFromGitSensor(source); unregisterErr != nil {
+			impl.logger.Errorw("error in rolling back git-sensor registration of app-as-code source", "sourceId", source.Id, "err", unregisterErr)
+		}
+		return nil, err
+	}
+	return buildSourceDto(source), nil
+}
+
+func (impl *AppAsCodeServiceImpl) UpdateSource(request *bean.AppAsCodeSourceDto, userId int32) (*bean.AppAsCodeSourceDto, error) {
+	source, err := impl.getSourceById(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if source.Name != request.Name {
+		if err = impl.checkSourceName(request.Name); err != nil {
+			return nil, err
+		}
+	}
+	repoChanged := source.GitProviderId != request.GitProviderId || source.GitRepoUrl != request.GitRepoUrl || source.GitBranch != request.GitBranch
+	setSourceFields(source, request)
+	if repoChanged {
+		if err = impl.registerSourceInGitSensor(source, false); err != nil {
+			return nil, err
+		}
+		// the stored definitions are of the old branch, the next sync has to fetch the new one
+		source.LastSyncedCommit = ""
+	}
+	source.UpdateAuditLog(userId)
+	if err = impl.appAsCodeRepository.UpdateSource(source); err != nil {
+		impl.logger.Errorw("error in updating app-as-code source", "request", request, "err", err)
+		return nil, err
+	}
+	return buildSourceDto(source), nil
+}
+
+func (impl *AppAsCodeServiceImpl) checkSourceName(name string) error {
+	existing, err := impl.appAsCodeRepository.FindActiveSourceByName(name)
+	if err != nil && !errors.Is(err, pg.ErrNoRows) {
+		impl.logger.Errorw("error in checking app-as-code source name", "name", name, "err", err)
+		return err
+	} else if existing != nil && existing.Id > 0 {
+		return util.NewApiError(http.StatusConflict, "source with the same name already exists", "source with the same name already exists")
+	}
+	return nil
+}
+
+func (impl *AppAsCodeServiceImpl) DeleteSource(id int, userId int32) error {
+	source, err := impl.getSourceById(id)
+	if err != nil {
+		return err
+	}
+	if err = impl.unregisterSourceFromGitSensor(source); err != nil {
+		return err
+	}
+	apps, err := impl.appAsCodeRepository.FindActiveAppsBySourceId(id)
+	if err != nil {
+		return err
+	}
+	for _, app := range apps {
+		app.Active = false
+		app.UpdateAuditLog(userId)
+		if err = impl.appAsCodeRepository.UpdateApp(app); err != nil {
+			impl.logger.Errorw("error in releasing app of app-as-code source", "sourceId", id, "appId", app.AppId, "err", err)
+			return err
+		}
+	}
+	source.Active = false
+	source.UpdateAuditLog(userId)
+	return impl.appAsCodeRepository.UpdateSource(source)
+}
+
+func (impl *AppAsCodeServiceImpl) GetSource(id int) (*bean.AppAsCodeSourceDto, error) {
+	source, err := impl.getSourceById(id)
+	if err != nil {
+		return nil, err
+	}
+	return buildSourceDto(source), nil
+}
+
+func (impl *AppAsCodeServiceImpl) GetAllSources() ([]*bean.AppAsCodeSourceDto, error) {
+	sources, err := impl.appAsCodeRepository.FindAllActiveSources()
+	if err != nil {
+		return nil, err
+	}
+	sourceDtos := make([]*bean.AppAsCodeSourceDto, 0, len(sources))
+	for _, source := range sources {
+		sourceDtos = append(sourceDtos, buildSourceDto(source))
+	}
+	return sourceDtos, nil
+}
+
+func (impl *AppAsCodeServiceImpl) GetSourceApps(sourceId int) ([]*bean.AppAsCodeAppDto, error) {
+	source, err := impl.getSourceById(sourceId)
+	if err != nil {
+		return nil, err
+	}
+	apps, err := impl.appAsCodeRepository.FindActiveAppsBySourceId(sourceId)
+	if err != nil {
+		return nil, err
+	}
+	appDtos := make([]*bean.AppAsCodeAppDto, 0, len(apps))
+	for _, app := range apps {
+		appDtos = append(appDtos, buildAppDto(app, source.Name))
+	}
+	return appDtos, nil
+}
+
+func (impl *AppAsCodeServiceImpl) GetAppStatus(appId int) (*bean.AppAsCodeAppDto, error) {
+	app, err := impl.appAsCodeRepository.FindActiveAppByAppId(appId)
+	if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in getting app-as-code app", "appId", appId, "err", err)
+		return nil, err
+	}
+	source, err := impl.getSourceById(app.SourceId)
+	if err != nil {
+		return nil, err
+	}
+	return buildAppDto(app, source.Name), nil
+}
+
+func (impl *AppAsCodeServiceImpl) ExportApp(ctx context.Context, appId int, enforce func(resource, action, object string) bool) (*bean.ExportAppResponse, error) {
+	appDetail, err := impl.appDefinitionManager.GetAppDefinition(ctx, appId, enforce)
+	if err != nil {
+		impl.logger.Errorw("error in getting app definition for export", "appId", appId, "err", err)
+		return nil, err
+	}
+	content, err := helper.RenderAppDefinition(appDetail)
+	if err != nil {
+		return nil, err
+	}
+	return &bean.ExportAppResponse{
+		AppId:    appId,
+		AppName:  appDetail.Metadata.AppName,
+		FileName: appDetail.Metadata.AppName + ".yaml",
+		Content:  string(content),
+	}, nil
+}
+
+func (impl *AppAsCodeServiceImpl) getSourceById(id int) (*repository.AppAsCodeSource, error) {
+	source, err := impl.appAsCodeRepository.FindActiveSourceById(id)
+	if errors.Is(err, pg.ErrNoRows) {
+		return nil, util.NewApiError(http.StatusNotFound, "app-as-code source not found", err.Error())
+	} else if err != nil {
+		impl.logger.Errorw("error in getting app-as-code source", "id", id, "err", err)
+		return nil, err
+	}
+	return source, nil
+}
+
+func (impl *AppAsCodeServiceImpl) syncAllSources() {
+	sources, err := impl.appAsCodeRepository.FindAllActiveSources()
+	if err != nil {
+		return
+	}
+	for _, source := range sources {
+		_, err = impl.SyncSource(context.Background(), source.Id, userBean.SystemUserId)
+		if errors.Is(err, errSourceSyncInProgress) {
+			impl.logger.Debugw("skipping app-as-code source being synced", "sourceId", source.Id)
+		} else if err != nil {
+			impl.logger.Errorw("error in syncing app-as-code source", "sourceId", source.Id, "err", err)
+		}
+	}
+}
+
+func (impl *AppAsCodeServiceImpl) SyncSource(ctx context.Context, id int, userId int32) (*bean.SourceSyncResponse, error) {
+	// the claim keeps the cron, git-sensor notifications and the api of all orchestrators from syncing a source concurrently
+	claimed, err := impl.appAsCodeRepository.ClaimSourceSync(id, time.Now().Add(-sourceSyncTimeout))
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		// not found is reported for sources which are missing or deleted
+		if _, err = impl.getSourceById(id); err != nil {
+			return nil, err
+		}
+		return nil, errSourceSyncInProgress
+	}
+	defer func() {
+		if err := impl.appAsCodeRepository.ReleaseSourceSync(id); err != nil {
+			impl.logger.Errorw("error in releasing app-as-code source sync", "sourceId", id, "err", err)
+		}
+	}()
+	source, err := impl.getSourceById(id)
+	if err != nil {
+		return nil, err
+	}
+	response := &bean.SourceSyncResponse{SourceId: source.Id, Apps: make([]*bean.AppAsCodeAppDto, 0)}
+	definitionFiles, commit, err := impl.getDefinitionFiles(source)
+	if err != nil {
+		response.Status = bean.SyncStatusFailed
+		response.Error = err.Error()
+	} else {
+		response.Commit = commit
+		response.Apps, err = impl.syncDefinitionFiles(ctx, source, commit, definitionFiles, userId)
+		if err != nil {
+			return nil, err
+		}
+		response.Status = helper.GetSyncStatus(response.Apps)
+		source.LastSyncedCommit = commit
+	}
+	source.LastSyncedOn = time.Now()
+	source.LastSyncStatus = string(response.Status)
+	source.LastSyncMessage = response.Error
+	source.UpdateAuditLog(userId)
+	if err = impl.appAsCodeRepository.UpdateSource(source); err != nil {
+		impl.logger.Errorw("error in saving app-as-code source sync result", "sourceId", source.Id, "err", err)
+		return nil, err
+	}
+	return response, nil
+}
+
+func (impl *AppAsCodeServiceImpl) HandleNewCommit(ciPipelineMaterialId int) (bool, error) {
+	source, err := impl.appAsCodeRepository.FindActiveSourceByCiPipelineMaterialId(ciPipelineMaterialId)
+	if errors.Is(err, pg.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in getting app-as-code source by ci pipeline material", "ciPipelineMaterialId", ciPipelineMaterialId, "err", err)
+		return false, err
+	}
+	go func() {
+		_, err := impl.SyncSource(context.Background(), source.Id, userBean.SystemUserId)
+		if errors.Is(err, errSourceSyncInProgress) {
+			// the running sync is of an older commit, the periodic sync picks up the new one
+			impl.logger.Infow("app-as-code source is being synced, new commit is synced later", "sourceId", source.Id)
+		} else if err != nil {
+			impl.logger.Errorw("error in syncing app-as-code source on new commit", "sourceId", source.Id, "err", err)
+		}
+	}()
+	return true, nil
+}
+
+// getDefinitionFiles returns the definitions at the head of the source branch as polled by git-sensor, the repo is only
+// cloned when the head moved since the last sync, otherwise the definitions stored by the last sync are reused to check for drift
+func (impl *AppAsCodeServiceImpl) getDefinitionFiles(source *repository.AppAsCodeSource) ([]*bean.AppDefinitionFile, string, error) {
+	headCommit, err := impl.getHeadCommit(source)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(headCommit) == 0 || headCommit != source.LastSyncedCommit {
+		return impl.fetchDefinitionFiles(source)
+	}
+	managedApps, err := impl.appAsCodeRepository.FindActiveAppsBySourceId(source.Id)
+	if err != nil {
+		return nil, "", err
+	}
+	definitionFiles := make([]*bean.AppDefinitionFile, 0, len(managedApps))
+	for _, managedApp := range managedApps {
+		definitionFiles = append(definitionFiles, &bean.AppDefinitionFile{Path: managedApp.FilePath, Content: []byte(managedApp.Definition)})
+	}
+	return definitionFiles, headCommit, nil
+}
+
+// getHeadCommit returns the last commit on the source branch seen by git-sensor, empty if it has not polled the branch yet
+func (impl *AppAsCodeServiceImpl) getHeadCommit(source *repository.AppAsCodeSource) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitSensorTimeout)
+	defer cancel()
+	materials, err := impl.gitSensorClient.GetHeadForPipelineMaterials(ctx, &gitSensor.HeadRequest{MaterialIds: []int{source.CiPipelineMaterialId}})
+	if err != nil {
+		impl.logger.Errorw("error in getting head commit of app-as-code source from git-sensor", "sourceId", source.Id, "err", err)
+		return "", err
+	}
+	for _, material := range materials {
+		if material.Id == source.CiPipelineMaterialId {
+			return material.GitCommit.Commit, nil
+		}
+	}
+	return "", nil
+}
+
+// registerSourceInGitSensor makes git-sensor poll the branch of the source, newRepo adds the repo instead of updating it
+func (impl *AppAsCodeServiceImpl) registerSourceInGitSensor(source *repository.AppAsCodeSource, newRepo bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), gitSensorTimeout)
+	defer cancel()
+	gitMaterial := buildGitSensorMaterial(source)
+	var err error
+	if newRepo {
+		err = impl.gitSensorClient.AddRepo(ctx, []*gitSensor.GitMaterial{gitMaterial})
+	} else {
+		err = impl.gitSensorClient.UpdateRepo(ctx, gitMaterial)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in registering app-as-code source repo in git-sensor", "sourceId", source.Id, "repoUrl", source.GitRepoUrl, "err", err)
+		return err
+	}
+	err = impl.gitSensorClient.SavePipelineMaterial(ctx, []*gitSensor.CiPipelineMaterial{buildGitSensorPipelineMaterial(source, true)})
+	if err != nil {
+		impl.logger.Errorw("error in registering app-as-code source branch in git-sensor", "sourceId", source.Id, "branch", source.GitBranch, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *AppAsCodeServiceImpl) unregisterSourceFromGitSensor(source *repository.AppAsCodeSource) error {
+	ctx, cancel := context.WithTimeout(context.Background(), gitSensorTimeout)
+	defer cancel()
+	err := impl.gitSensorClient.SavePipelineMaterial(ctx, []*gitSensor.CiPipelineMaterial{buildGitSensorPipelineMaterial(source, false)})
+	if err != nil {
+		impl.logger.Errorw("error in deactivating app-as-code source branch in git-sensor", "sourceId", source.Id, "err", err)
+		return err
+	}
+	gitMaterial := buildGitSensorMaterial(source)
+	gitMaterial.Deleted = true
+	if err = impl.gitSensorClient.UpdateRepo(ctx, gitMaterial); err != nil {
+		impl.logger.Errorw("error in deleting app-as-code source repo in git-sensor", "sourceId", source.Id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func buildGitSensorMaterial(source *repository.AppAsCodeSource) *gitSensor.GitMaterial {
+	return &gitSensor.GitMaterial{
+		Id:            source.GitMaterialId,
+		GitProviderId: source.GitProviderId,
+		Url:           source.GitRepoUrl,
+		Name:          fmt.Sprintf("app-as-code-%s", source.Name),
+	}
+}
+
+func buildGitSensorPipelineMaterial(source *repository.AppAsCodeSource, active bool) *gitSensor.CiPipelineMaterial {
+	return &gitSensor.CiPipelineMaterial{
+		Id:            source.CiPipelineMaterialId,
+		GitMaterialId: source.GitMaterialId,
+		Type:          gitSensor.SourceType(constants.SOURCE_TYPE_BRANCH_FIXED),
+		Value:         source.GitBranch,
+		Active:        active,
+	}
+}
+
+func setSourceFields(source *repository.AppAsCodeSource, request *bean.AppAsCodeSourceDto) {
+	source.Name = request.Name
+	source.GitProviderId = request.GitProviderId
+	source.GitRepoUrl = request.GitRepoUrl
+	source.GitBranch = request.GitBranch
+	source.SpecPath = request.SpecPath
+	source.DriftMode = string(request.DriftMode)
+}
+
+func buildSourceDto(source *repository.AppAsCodeSource) *bean.AppAsCodeSourceDto {
+	sourceDto := &bean.AppAsCodeSourceDto{
+		Id:               source.Id,
+		Name:             source.Name,
+		GitProviderId:    source.GitProviderId,
+		GitRepoUrl:       source.GitRepoUrl,
+		GitBranch:        source.GitBranch,
+		SpecPath:         source.SpecPath,
+		DriftMode:        bean.DriftMode(source.DriftMode),
+		LastSyncedCommit: source.LastSyncedCommit,
+		LastSyncStatus:   bean.SyncStatus(source.LastSyncStatus),
+		LastSyncMessage:  source.LastSyncMessage,
+	}
+	if !source.LastSyncedOn.IsZero() {
+		lastSyncedOn := source.LastSyncedOn
+		sourceDto.LastSyncedOn = &lastSyncedOn
+	}
+	return sourceDto
+}
+
+func buildAppDto(app *repository.AppAsCodeApp, sourceName string) *bean.AppAsCodeAppDto {
+	appDto := &bean.AppAsCodeAppDto{
+		SourceId:      app.SourceId,
+		SourceName:    sourceName,
+		AppId:         app.AppId,
+		AppName:       app.AppName,
+		FilePath:      app.FilePath,
+		Status:        bean.AppSyncStatus(app.Status),
+		Message:       app.Message,
+		AppliedCommit: app.AppliedCommit,
+	}
+	if !app.LastSyncedOn.IsZero() {
+		lastSyncedOn := app.LastSyncedOn
+		appDto.LastSyncedOn = &lastSyncedOn
+	}
+	if len(app.SectionResults) > 0 {
+		_ = json.Unmarshal([]byte(app.SectionResults), &appDto.SectionResults)
+	}
+	return appDto
+}
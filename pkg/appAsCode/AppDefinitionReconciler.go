@@ -0,0 +1,284 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appAsCode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	appHelper "github.com/devtron-labs/devtron/internal/sql/repository/helper"
+	"github.com/devtron-labs/devtron/pkg/appAsCode/bean"
+	"github.com/devtron-labs/devtron/pkg/appAsCode/helper"
+	"github.com/devtron-labs/devtron/pkg/appAsCode/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"strings"
+	"time"
+)
+
+// syncDefinitionFiles reconciles every definition of the source, apps whose definition was removed from the source are released
+func (impl *AppAsCodeServiceImpl) syncDefinitionFiles(ctx context.Context, source *repository.AppAsCodeSource, commit string,
+	definitionFiles []*bean.AppDefinitionFile, userId int32) ([]*bean.AppAsCodeAppDto, error) {
+	managedApps, err := impl.appAsCodeRepository.FindActiveAppsBySourceId(source.Id)
+	if err != nil {
+		return nil, err
+	}
+	managedAppsByPath := make(map[string]*repository.AppAsCodeApp, len(managedApps))
+	for _, managedApp := range managedApps {
+		managedAppsByPath[managedApp.FilePath] = managedApp
+	}
+	results := make([]*bean.AppAsCodeAppDto, 0, len(definitionFiles))
+	for _, definitionFile := range definitionFiles {
+		managedApp, ok := managedAppsByPath[definitionFile.Path]
+		if !ok {
+			managedApp = &repository.AppAsCodeApp{SourceId: source.Id, FilePath: definitionFile.Path, Active: true, AuditLog: sql.NewDefaultAuditLog(userId)}
+		}
+		delete(managedAppsByPath, definitionFile.Path)
+		managedApp.Definition = string(definitionFile.Content)
+		impl.syncDefinitionFile(ctx, source, commit, definitionFile, managedApp, userId)
+		if err = impl.saveManagedApp(managedApp, userId); err != nil {
+			return nil, err
+		}
+		results = append(results, buildAppDto(managedApp, source.Name))
+	}
+	for _, removedApp := range managedAppsByPath {
+		removedApp.Active = false
+		removedApp.Status = string(bean.AppSyncStatusRemoved)
+		removedApp.Message = "definition removed from the source, the app is no longer managed and is left as it is"
+		removedApp.SectionResults = ""
+		if err = impl.saveManagedApp(removedApp, userId); err != nil {
+			return nil, err
+		}
+		results = append(results, buildAppDto(removedApp, source.Name))
+	}
+	return results, nil
+}
+
+func (impl *AppAsCodeServiceImpl) saveManagedApp(managedApp *repository.AppAsCodeApp, userId int32) error {
+	managedApp.LastSyncedOn = time.Now()
+	var err error
+	if managedApp.Id == 0 {
+		err = impl.appAsCodeRepository.SaveApp(managedApp)
+	} else {
+		managedApp.UpdateAuditLog(userId)
+		err = impl.appAsCodeRepository.UpdateApp(managedApp)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving app-as-code app", "sourceId", managedApp.SourceId, "filePath", managedApp.FilePath, "err", err)
+	}
+	return err
+}
+
+// syncDefinitionFile brings the app to the definition and records the outcome on managedApp. Changes are detected per section:
+// a section changed in git since the last sync is applied, a section whose state changed since the last sync has drifted
+// and is either reverted to git or reported depending on the drift mode of the source
+func (impl *AppAsCodeServiceImpl) syncDefinitionFile(ctx context.Context, source *repository.AppAsCodeSource, commit string,
+	definitionFile *bean.AppDefinitionFile, managedApp *repository.AppAsCodeApp, userId int32) {
+	managedApp.SectionResults = ""
+	definition, err := helper.ParseAppDefinition(definitionFile.Content)
+	if err != nil {
+		setManagedAppStatus(managedApp, bean.AppSyncStatusInvalid, err.Error())
+		return
+	}
+	desired := definition.Spec
+	appName := desired.Metadata.AppName
+	if managedApp.AppId > 0 && managedApp.AppName != appName {
+		setManagedAppStatus(managedApp, bean.AppSyncStatusInvalid, fmt.Sprintf("app name can not be changed from %q, add a new definition file for a new app", managedApp.AppName))
+		return
+	}
+	existingApp, err := impl.appRepository.FindActiveByName(appName)
+	if err != nil && !errors.Is(err, pg.ErrNoRows) {
+		setManagedAppStatus(managedApp, bean.AppSyncStatusFailed, err.Error())
+		return
+	}
+	if existingApp != nil && existingApp.Id > 0 {
+		if existingApp.AppType != appHelper.CustomApp {
+			setManagedAppStatus(managedApp, bean.AppSyncStatusInvalid, fmt.Sprintf("%q is not a devtron app", appName))
+			return
+		}
+		otherManagedApp, err := impl.appAsCodeRepository.FindActiveAppByAppId(existingApp.Id)
+		if err != nil && !errors.Is(err, pg.ErrNoRows) {
+			setManagedAppStatus(managedApp, bean.AppSyncStatusFailed, err.Error())
+			return
+		} else if err == nil && otherManagedApp.Id != managedApp.Id {
+			setManagedAppStatus(managedApp, bean.AppSyncStatusInvalid, fmt.Sprintf("app %q is already managed by %s of source %d", appName, otherManagedApp.FilePath, otherManagedApp.SourceId))
+			return
+		} else if managedApp.AppId != existingApp.Id && !definition.Adopt {
+			// an app created outside the source is only taken over when the definition asks for it
+			setManagedAppStatus(managedApp, bean.AppSyncStatusInvalid, fmt.Sprintf("app %q already exists and is not managed by any source, set adopt: true in the definition to manage it from git", appName))
+			return
+		}
+	}
+	specHashes, err := helper.HashSections(desired)
+	if err != nil {
+		setManagedAppStatus(managedApp, bean.AppSyncStatusFailed, err.Error())
+		return
+	}
+	managedApp.AppName = appName
+	if existingApp == nil || existingApp.Id == 0 {
+		impl.createDefinedApp(ctx, commit, desired, specHashes, managedApp, userId)
+		return
+	}
+	managedApp.AppId = existingApp.Id
+	impl.reconcileDefinedApp(ctx, bean.DriftMode(source.DriftMode), commit, desired, specHashes, managedApp, userId)
+}
+
+func (impl *AppAsCodeServiceImpl) createDefinedApp(ctx context.Context, commit string, desired *appBean.AppDetail, specHashes map[string]string,
+	managedApp *repository.AppAsCodeApp, userId int32) {
+	// a new app has no values to keep in place of redacted secret data
+	if err := helper.FillRedactedSecrets(desired, &appBean.AppDetail{}); err != nil {
+		setManagedAppStatus(managedApp, bean.AppSyncStatusInvalid, err.Error())
+		return
+	}
+	appId, err := impl.appDefinitionManager.CreateAppFromDefinition(ctx, desired, userId)
+	if err != nil {
+		impl.logger.Errorw("error in creating app from definition", "appName", managedApp.AppName, "filePath", managedApp.FilePath, "err", err)
+		setManagedAppStatus(managedApp, bean.AppSyncStatusFailed, err.Error())
+		return
+	}
+	managedApp.AppId = appId
+	current, err := impl.appDefinitionManager.GetAppDefinition(ctx, appId, helper.AllowAllEnforce)
+	if err != nil {
+		setManagedAppStatus(managedApp, bean.AppSyncStatusFailed, fmt.Sprintf("app created but its state could not be read: %s", err.Error()))
+		return
+	}
+	stateHashes, err := helper.HashSections(current)
+	if err != nil {
+		setManagedAppStatus(managedApp, bean.AppSyncStatusFailed, err.Error())
+		return
+	}
+	sectionResults := make([]*bean.SectionResult, 0, len(specHashes))
+	for _, section := range helper.OrderSections(specHashes) {
+		sectionResults = append(sectionResults, &bean.SectionResult{Section: section, Action: bean.SectionActionCreated})
+	}
+	impl.setManagedAppResult(managedApp, commit, sectionResults, specHashes, stateHashes)
+}
+
+func (impl *AppAsCodeServiceImpl) reconcileDefinedApp(ctx context.Context, driftMode bean.DriftMode, commit string, desired *appBean.AppDetail,
+	specHashes map[string]string, managedApp *repository.AppAsCodeApp, userId int32) {
+	current, err := impl.appDefinitionManager.GetAppDefinition(ctx, managedApp.AppId, helper.AllowAllEnforce)
+	if err != nil {
+		setManagedAppStatus(managedApp, bean.AppSyncStatusFailed, err.Error())
+		return
+	}
+	// hashes of the desired state are taken before the redacted values are filled so that secrets changed in devtron
+	// are not mistaken for changes in git
+	if err = helper.FillRedactedSecrets(desired, current); err != nil {
+		setManagedAppStatus(managedApp, bean.AppSyncStatusInvalid, err.Error())
+		return
+	}
+	currentHashes, err := helper.HashSections(current)
+	if err != nil {
+		setManagedAppStatus(managedApp, bean.AppSyncStatusFailed, err.Error())
+		return
+	}
+	// an app adopted by a source has no previous sync, all its sections are applied once
+	adopted := len(managedApp.SpecHashes) == 0
+	lastSpecHashes, lastStateHashes := make(map[string]string), make(map[string]string)
+	if !adopted {
+		_ = json.Unmarshal([]byte(managedApp.SpecHashes), &lastSpecHashes)
+		_ = json.Unmarshal([]byte(managedApp.StateHashes), &lastStateHashes)
+	}
+	newSpecHashes, newStateHashes := make(map[string]string), make(map[string]string)
+	sectionResults := make([]*bean.SectionResult, 0)
+	appliedSections := make([]string, 0)
+	for _, section := range helper.OrderSections(specHashes, lastSpecHashes, currentHashes, lastStateHashes) {
+		changedInGit := specHashes[section] != lastSpecHashes[section]
+		drifted := !adopted && currentHashes[section] != lastStateHashes[section]
+		if !changedInGit && !drifted {
+			setHash(newSpecHashes, section, specHashes[section])
+			setHash(newStateHashes, section, currentHashes[section])
+			continue
+		}
+		if !changedInGit && driftMode == bean.DriftModeWarnOnly {
+			// the last synced state is kept so that the drift is reported until it is resolved
+			result := &bean.SectionResult{Section: section, Action: bean.SectionActionDrifted}
+			result.Desired, _ = helper.GetSectionJson(desired, section)
+			result.Current, _ = helper.GetSectionJson(current, section)
+			sectionResults = append(sectionResults, result)
+			setHash(newSpecHashes, section, specHashes[section])
+			setHash(newStateHashes, section, lastStateHashes[section])
+			continue
+		}
+		result := &bean.SectionResult{Section: section, Action: bean.SectionActionApplied}
+		if !changedInGit {
+			result.Action = bean.SectionActionReverted
+		}
+		skipped, err := impl.appDefinitionManager.ApplyAppDefinitionSection(ctx, managedApp.AppId, section, desired, current, userId)
+		if err != nil {
+			impl.logger.Errorw("error in applying app definition section", "appId", managedApp.AppId, "section", section, "err", err)
+			// hashes of the last sync are kept so that the section is applied again on the next sync
+			result.Action = bean.SectionActionFailed
+			result.Message = err.Error()
+			setHash(newSpecHashes, section, lastSpecHashes[section])
+			setHash(newStateHashes, section, lastStateHashes[section])
+		} else {
+			result.Message = strings.Join(skipped, "; ")
+			setHash(newSpecHashes, section, specHashes[section])
+			appliedSections = append(appliedSections, section)
+		}
+		sectionResults = append(sectionResults, result)
+	}
+	if len(appliedSections) > 0 {
+		updated, err := impl.appDefinitionManager.GetAppDefinition(ctx, managedApp.AppId, helper.AllowAllEnforce)
+		if err != nil {
+			setManagedAppStatus(managedApp, bean.AppSyncStatusFailed, fmt.Sprintf("changes applied but the app state could not be read: %s", err.Error()))
+			return
+		}
+		updatedHashes, err := helper.HashSections(updated)
+		if err != nil {
+			setManagedAppStatus(managedApp, bean.AppSyncStatusFailed, err.Error())
+			return
+		}
+		for _, section := range appliedSections {
+			setHash(newStateHashes, section, updatedHashes[section])
+		}
+	}
+	impl.setManagedAppResult(managedApp, commit, sectionResults, newSpecHashes, newStateHashes)
+}
+
+func (impl *AppAsCodeServiceImpl) setManagedAppResult(managedApp *repository.AppAsCodeApp, commit string, sectionResults []*bean.SectionResult,
+	specHashes, stateHashes map[string]string) {
+	status := helper.GetAppSyncStatus(sectionResults)
+	messages := make([]string, 0)
+	for _, result := range sectionResults {
+		if len(result.Message) > 0 {
+			messages = append(messages, fmt.Sprintf("%s: %s", result.Section, result.Message))
+		}
+	}
+	specHashesJson, _ := json.Marshal(specHashes)
+	stateHashesJson, _ := json.Marshal(stateHashes)
+	sectionResultsJson, _ := json.Marshal(sectionResults)
+	managedApp.SpecHashes = string(specHashesJson)
+	managedApp.StateHashes = string(stateHashesJson)
+	managedApp.SectionResults = string(sectionResultsJson)
+	managedApp.AppliedCommit = commit
+	setManagedAppStatus(managedApp, status, strings.Join(messages, "\n"))
+}
+
+func setManagedAppStatus(managedApp *repository.AppAsCodeApp, status bean.AppSyncStatus, message string) {
+	managedApp.Status = string(status)
+	managedApp.Message = message
+}
+
+func setHash(hashes map[string]string, section, hash string) {
+	if len(hash) > 0 {
+		hashes[section] = hash
+	}
+}
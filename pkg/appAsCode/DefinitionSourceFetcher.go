@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appAsCode
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/appAsCode/bean"
+	"github.com/devtron-labs/devtron/pkg/appAsCode/helper"
+	"github.com/devtron-labs/devtron/pkg/appAsCode/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
+	"strconv"
+)
+
+// fetchDefinitionFiles clones the source repo and reads all app definitions under its spec path along with the head commit,
+// it is only called when git-sensor reports a commit which is not synced yet
+func (impl *AppAsCodeServiceImpl) fetchDefinitionFiles(source *repository.AppAsCodeSource) ([]*bean.AppDefinitionFile, string, error) {
+	gitProvider, err := impl.gitProviderRepository.FindOne(strconv.Itoa(source.GitProviderId))
+	if err != nil {
+		impl.logger.Errorw("error in getting git provider of app-as-code source", "sourceId", source.Id, "gitProviderId", source.GitProviderId, "err", err)
+		return nil, "", err
+	}
+	specPath := source.SpecPath
+	if len(specPath) == 0 {
+		specPath = bean.DefaultSpecPath
+	}
+	repoFiles, commit, err := git.CloneAndReadFiles(&gitProvider, impl.logger, source.GitRepoUrl, source.GitBranch,
+		fmt.Sprintf("app-as-code-%d", source.Id), specPath, helper.IsDefinitionFile)
+	if err != nil {
+		impl.logger.Errorw("error in reading app definitions of app-as-code source", "sourceId", source.Id, "repoUrl", source.GitRepoUrl, "err", err)
+		return nil, "", err
+	}
+	definitionFiles := make([]*bean.AppDefinitionFile, 0, len(repoFiles))
+	for _, repoFile := range repoFiles {
+		definitionFiles = append(definitionFiles, &bean.AppDefinitionFile{Path: repoFile.Path, Content: repoFile.Content})
+	}
+	return definitionFiles, commit, nil
+}
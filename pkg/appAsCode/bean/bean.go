@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"encoding/json"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"time"
+)
+
+type DriftMode string
+
+const (
+	// DriftModeGitWins reverts changes made to a managed app outside git on the next sync
+	DriftModeGitWins DriftMode = "GIT_WINS"
+	// DriftModeWarnOnly keeps changes made outside git and reports them as drift
+	DriftModeWarnOnly DriftMode = "WARN_ONLY"
+)
+
+type SyncStatus string
+
+const (
+	SyncStatusSucceeded          SyncStatus = "SUCCEEDED"
+	SyncStatusPartiallySucceeded SyncStatus = "PARTIALLY_SUCCEEDED"
+	SyncStatusFailed             SyncStatus = "FAILED"
+)
+
+type AppSyncStatus string
+
+const (
+	AppSyncStatusInSync  AppSyncStatus = "IN_SYNC"
+	AppSyncStatusApplied AppSyncStatus = "APPLIED"
+	AppSyncStatusDrifted AppSyncStatus = "DRIFTED"
+	AppSyncStatusFailed  AppSyncStatus = "FAILED"
+	AppSyncStatusInvalid AppSyncStatus = "INVALID"
+	// AppSyncStatusRemoved is set when the definition file is removed from the source, the app itself is left untouched
+	AppSyncStatusRemoved AppSyncStatus = "REMOVED"
+)
+
+type SectionAction string
+
+const (
+	SectionActionCreated SectionAction = "CREATED"
+	SectionActionApplied SectionAction = "APPLIED"
+	// SectionActionReverted is a drifted section overwritten with the definition in git
+	SectionActionReverted SectionAction = "REVERTED"
+	SectionActionDrifted  SectionAction = "DRIFTED"
+	SectionActionFailed   SectionAction = "FAILED"
+)
+
+// sections of an app definition, changes are detected and applied per section
+const (
+	SectionMetadata                  = "metadata"
+	SectionGitMaterials              = "gitMaterials"
+	SectionDockerConfig              = "dockerConfig"
+	SectionGlobalDeploymentTemplate  = "globalDeploymentTemplate"
+	SectionGlobalConfigMaps          = "globalConfigMaps"
+	SectionGlobalSecrets             = "globalSecrets"
+	SectionWorkflowPrefix            = "workflow."
+	SectionEnvironmentOverridePrefix = "environmentOverride."
+)
+
+const (
+	AppDefinitionApiVersion = "app.devtron.ai/v1"
+	AppDefinitionKind       = "Application"
+	DefaultSpecPath         = "apps"
+	// RedactedSecretValue replaces secret data in exported definitions, a redacted value in git keeps the value set in devtron
+	RedactedSecretValue = "********"
+)
+
+type AppAsCodeSourceDto struct {
+	Id               int        `json:"id"`
+	Name             string     `json:"name" validate:"required,min=3,max=250"`
+	GitProviderId    int        `json:"gitProviderId" validate:"required,gt=0"`
+	GitRepoUrl       string     `json:"gitRepoUrl" validate:"required"`
+	GitBranch        string     `json:"gitBranch" validate:"required"`
+	SpecPath         string     `json:"specPath,omitempty"` // directory in the git repo holding app definitions, defaults to DefaultSpecPath
+	DriftMode        DriftMode  `json:"driftMode" validate:"oneof=GIT_WINS WARN_ONLY"`
+	LastSyncedCommit string     `json:"lastSyncedCommit,omitempty"`
+	LastSyncedOn     *time.Time `json:"lastSyncedOn,omitempty"`
+	LastSyncStatus   SyncStatus `json:"lastSyncStatus,omitempty"`
+	LastSyncMessage  string     `json:"lastSyncMessage,omitempty"`
+}
+
+// AppAsCodeAppDto is the sync state of an app managed by a source
+type AppAsCodeAppDto struct {
+	SourceId       int              `json:"sourceId"`
+	SourceName     string           `json:"sourceName,omitempty"`
+	AppId          int              `json:"appId,omitempty"`
+	AppName        string           `json:"appName,omitempty"`
+	FilePath       string           `json:"filePath"`
+	Status         AppSyncStatus    `json:"status"`
+	Message        string           `json:"message,omitempty"`
+	AppliedCommit  string           `json:"appliedCommit,omitempty"`
+	LastSyncedOn   *time.Time       `json:"lastSyncedOn,omitempty"`
+	SectionResults []*SectionResult `json:"sectionResults,omitempty"`
+}
+
+type SectionResult struct {
+	Section string        `json:"section"`
+	Action  SectionAction `json:"action"`
+	// Message lists the parts of the section which could not be applied
+	Message string `json:"message,omitempty"`
+	// Desired and Current are set for drifted sections, secret data is redacted
+	Desired json.RawMessage `json:"desired,omitempty"`
+	Current json.RawMessage `json:"current,omitempty"`
+}
+
+type SourceSyncResponse struct {
+	SourceId int                `json:"sourceId"`
+	Commit   string             `json:"commit,omitempty"`
+	Status   SyncStatus         `json:"status"`
+	Error    string             `json:"error,omitempty"`
+	Apps     []*AppAsCodeAppDto `json:"apps"`
+}
+
+type ExportAppResponse struct {
+	AppId    int    `json:"appId"`
+	AppName  string `json:"appName"`
+	FileName string `json:"fileName"`
+	Content  string `json:"content"`
+}
+
+// AppDefinition is the documented yaml format of an app kept in an app-as-code source, see specs/app_as_code.yaml
+type AppDefinition struct {
+	ApiVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Adopt has to be set for the definition of an existing app which is not managed by any source yet
+	Adopt bool               `json:"adopt,omitempty"`
+	Spec  *appBean.AppDetail `json:"spec"`
+}
+
+// AppDefinitionFile is a raw definition read from a source
+type AppDefinitionFile struct {
+	Path    string
+	Content []byte
+}
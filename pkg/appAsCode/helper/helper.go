@@ -0,0 +1,289 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/pkg/appAsCode/bean"
+	"path/filepath"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strings"
+)
+
+func IsDefinitionFile(fileName string) bool {
+	extension := filepath.Ext(fileName)
+	return extension == ".yaml" || extension == ".yml"
+}
+
+// ParseAppDefinition decodes a definition file strictly so that typos in field names are reported instead of being ignored
+func ParseAppDefinition(content []byte) (*bean.AppDefinition, error) {
+	jsonContent, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid yaml: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonContent))
+	decoder.DisallowUnknownFields()
+	definition := &bean.AppDefinition{}
+	if err = decoder.Decode(definition); err != nil {
+		return nil, fmt.Errorf("invalid app definition: %w", err)
+	}
+	if definition.ApiVersion != bean.AppDefinitionApiVersion || definition.Kind != bean.AppDefinitionKind {
+		return nil, fmt.Errorf("apiVersion and kind must be %q and %q", bean.AppDefinitionApiVersion, bean.AppDefinitionKind)
+	}
+	spec := definition.Spec
+	if spec == nil || spec.Metadata == nil || len(spec.Metadata.AppName) == 0 || len(spec.Metadata.ProjectName) == 0 {
+		return nil, errors.New("spec.metadata.appName and spec.metadata.projectName are required")
+	}
+	checkoutPaths := make(map[string]bool)
+	for _, material := range spec.GitMaterials {
+		if checkoutPaths[material.CheckoutPath] {
+			return nil, fmt.Errorf("git materials have the same checkout path %q", material.CheckoutPath)
+		}
+		checkoutPaths[material.CheckoutPath] = true
+	}
+	workflowNames := make(map[string]bool)
+	for _, workflow := range spec.AppWorkflows {
+		if len(workflow.Name) == 0 {
+			return nil, errors.New("workflow name is required")
+		} else if workflow.CiPipeline == nil {
+			return nil, fmt.Errorf("workflow %q requires a ciPipeline", workflow.Name)
+		} else if workflowNames[workflow.Name] {
+			return nil, fmt.Errorf("workflow %q is defined more than once", workflow.Name)
+		}
+		workflowNames[workflow.Name] = true
+	}
+	return definition, nil
+}
+
+// RenderAppDefinition renders an app in the definition format with secret data redacted
+func RenderAppDefinition(appDetail *appBean.AppDetail) ([]byte, error) {
+	redacted, err := RedactSecrets(appDetail)
+	if err != nil {
+		return nil, err
+	}
+	// an exported app already exists, committing its definition to a source adopts it
+	definition := &bean.AppDefinition{
+		ApiVersion: bean.AppDefinitionApiVersion,
+		Kind:       bean.AppDefinitionKind,
+		Adopt:      true,
+		Spec:       redacted,
+	}
+	return yaml.Marshal(definition)
+}
+
+// RedactSecrets returns a copy of the app with the data of secrets defined in devtron replaced by bean.RedactedSecretValue,
+// external secrets only hold references and are kept as they are
+func RedactSecrets(appDetail *appBean.AppDetail) (*appBean.AppDetail, error) {
+	appDetailJson, err := json.Marshal(appDetail)
+	if err != nil {
+		return nil, err
+	}
+	redacted := &appBean.AppDetail{}
+	if err = json.Unmarshal(appDetailJson, redacted); err != nil {
+		return nil, err
+	}
+	redactSecretData(redacted.GlobalSecrets)
+	for _, override := range redacted.EnvironmentOverrides {
+		if override != nil {
+			redactSecretData(override.Secrets)
+		}
+	}
+	return redacted, nil
+}
+
+func redactSecretData(secrets []*appBean.Secret) {
+	for _, secret := range secrets {
+		if secret.IsExternal {
+			continue
+		}
+		for key := range secret.Data {
+			secret.Data[key] = bean.RedactedSecretValue
+		}
+	}
+}
+
+// FillRedactedSecrets replaces redacted values of the desired app with the values currently set on the same secret key
+func FillRedactedSecrets(desired, current *appBean.AppDetail) error {
+	if err := fillSecretData(desired.GlobalSecrets, current.GlobalSecrets, "global"); err != nil {
+		return err
+	}
+	for envName, override := range desired.EnvironmentOverrides {
+		if override == nil {
+			continue
+		}
+		var currentSecrets []*appBean.Secret
+		if currentOverride := current.EnvironmentOverrides[envName]; currentOverride != nil {
+			currentSecrets = currentOverride.Secrets
+		}
+		if err := fillSecretData(override.Secrets, currentSecrets, envName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fillSecretData(desired, current []*appBean.Secret, scope string) error {
+	currentSecrets := make(map[string]*appBean.Secret, len(current))
+	for _, secret := range current {
+		currentSecrets[secret.Name] = secret
+	}
+	for _, secret := range desired {
+		for key, value := range secret.Data {
+			if value != bean.RedactedSecretValue {
+				continue
+			}
+			currentSecret, ok := currentSecrets[secret.Name]
+			if !ok || currentSecret.Data[key] == nil {
+				return fmt.Errorf("%s secret %q has a redacted value for key %q which is not set in devtron", scope, secret.Name, key)
+			}
+			secret.Data[key] = currentSecret.Data[key]
+		}
+	}
+	return nil
+}
+
+// GetSections splits the app into the sections changes are tracked for, empty sections are left out
+func GetSections(appDetail *appBean.AppDetail) map[string]interface{} {
+	sections := make(map[string]interface{})
+	if appDetail.Metadata != nil {
+		sections[bean.SectionMetadata] = appDetail.Metadata
+	}
+	if len(appDetail.GitMaterials) > 0 {
+		sections[bean.SectionGitMaterials] = appDetail.GitMaterials
+	}
+	if appDetail.DockerConfig != nil {
+		sections[bean.SectionDockerConfig] = appDetail.DockerConfig
+	}
+	if appDetail.GlobalDeploymentTemplate != nil {
+		sections[bean.SectionGlobalDeploymentTemplate] = appDetail.GlobalDeploymentTemplate
+	}
+	if len(appDetail.GlobalConfigMaps) > 0 {
+		sections[bean.SectionGlobalConfigMaps] = appDetail.GlobalConfigMaps
+	}
+	if len(appDetail.GlobalSecrets) > 0 {
+		sections[bean.SectionGlobalSecrets] = appDetail.GlobalSecrets
+	}
+	for _, workflow := range appDetail.AppWorkflows {
+		sections[bean.SectionWorkflowPrefix+workflow.Name] = workflow
+	}
+	for envName, override := range appDetail.EnvironmentOverrides {
+		if override != nil {
+			sections[bean.SectionEnvironmentOverridePrefix+envName] = override
+		}
+	}
+	return sections
+}
+
+// HashSections returns a digest per section, secret data is only kept as part of the digest
+func HashSections(appDetail *appBean.AppDetail) (map[string]string, error) {
+	hashes := make(map[string]string)
+	for section, value := range GetSections(appDetail) {
+		sectionJson, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		digest := sha256.Sum256(sectionJson)
+		hashes[section] = hex.EncodeToString(digest[:])
+	}
+	return hashes, nil
+}
+
+// GetSectionJson returns the json of a section with secret data redacted, null if the app does not have the section
+func GetSectionJson(appDetail *appBean.AppDetail, section string) (json.RawMessage, error) {
+	redacted, err := RedactSecrets(appDetail)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(GetSections(redacted)[section])
+}
+
+// OrderSections returns the union of sections in the order they have to be applied in,
+// materials are needed by the build config and workflows, workflows create the environments overridden later
+func OrderSections(sectionMaps ...map[string]string) []string {
+	fixedOrder := []string{bean.SectionMetadata, bean.SectionGitMaterials, bean.SectionDockerConfig, bean.SectionGlobalDeploymentTemplate,
+		bean.SectionGlobalConfigMaps, bean.SectionGlobalSecrets}
+	var workflows, environmentOverrides []string
+	seen := make(map[string]bool)
+	for _, sectionMap := range sectionMaps {
+		for section := range sectionMap {
+			if seen[section] {
+				continue
+			}
+			seen[section] = true
+			if strings.HasPrefix(section, bean.SectionWorkflowPrefix) {
+				workflows = append(workflows, section)
+			} else if strings.HasPrefix(section, bean.SectionEnvironmentOverridePrefix) {
+				environmentOverrides = append(environmentOverrides, section)
+			}
+		}
+	}
+	sort.Strings(workflows)
+	sort.Strings(environmentOverrides)
+	sections := make([]string, 0, len(seen))
+	for _, section := range fixedOrder {
+		if seen[section] {
+			sections = append(sections, section)
+		}
+	}
+	sections = append(sections, workflows...)
+	return append(sections, environmentOverrides...)
+}
+
+func GetAppSyncStatus(sectionResults []*bean.SectionResult) bean.AppSyncStatus {
+	status := bean.AppSyncStatusInSync
+	for _, result := range sectionResults {
+		switch result.Action {
+		case bean.SectionActionFailed:
+			return bean.AppSyncStatusFailed
+		case bean.SectionActionDrifted:
+			status = bean.AppSyncStatusDrifted
+		default:
+			if status == bean.AppSyncStatusInSync {
+				status = bean.AppSyncStatusApplied
+			}
+		}
+	}
+	return status
+}
+
+func GetSyncStatus(apps []*bean.AppAsCodeAppDto) bean.SyncStatus {
+	failed := 0
+	for _, app := range apps {
+		if app.Status == bean.AppSyncStatusFailed || app.Status == bean.AppSyncStatusInvalid {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return bean.SyncStatusSucceeded
+	} else if failed == len(apps) {
+		return bean.SyncStatusFailed
+	}
+	return bean.SyncStatusPartiallySucceeded
+}
+
+// AllowAllEnforce is the enforce func of app reads and writes made on behalf of a sync, sources are managed by super admins
+// and syncs are run by devtron so they are not limited by rbac
+func AllowAllEnforce(resource, action, object string) bool {
+	return true
+}
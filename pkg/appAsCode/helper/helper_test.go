@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package helper
+
+import (
+	"testing"
+
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/pkg/appAsCode/bean"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAppDefinition(t *testing.T) {
+	_, err := ParseAppDefinition([]byte("apiVersion: app.devtron.ai/v1\nkind: Plugin\nspec:\n  metadata:\n    appName: demo\n    projectName: team\n"))
+	assert.Error(t, err)
+	_, err = ParseAppDefinition([]byte("apiVersion: app.devtron.ai/v1\nkind: Application\nspec:\n  metadata:\n    appName: demo\n"))
+	assert.Error(t, err)
+	_, err = ParseAppDefinition([]byte("apiVersion: app.devtron.ai/v1\nkind: Application\nspec:\n  metadata:\n    appName: demo\n    projectName: team\n  gitMaterial: []\n"))
+	assert.Error(t, err)
+	_, err = ParseAppDefinition([]byte("apiVersion: app.devtron.ai/v1\nkind: Application\nspec:\n  metadata:\n    appName: demo\n    projectName: team\n  workflows:\n  - name: wf\n    ciPipeline:\n      name: ci\n  - name: wf\n    ciPipeline:\n      name: ci\n"))
+	assert.Error(t, err)
+	_, err = ParseAppDefinition([]byte("apiVersion: app.devtron.ai/v1\nkind: Application\nspec:\n  metadata:\n    appName: demo\n    projectName: team\n  workflows:\n  - name: wf\n"))
+	assert.Error(t, err)
+
+	definition, err := ParseAppDefinition([]byte("apiVersion: app.devtron.ai/v1\nkind: Application\nspec:\n  metadata:\n    appName: demo\n    projectName: team\n  workflows:\n  - name: wf\n    ciPipeline:\n      name: ci\n  environmentOverride:\n    dev:\n      configMaps:\n      - name: cm\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "demo", definition.Spec.Metadata.AppName)
+	assert.Equal(t, []string{bean.SectionMetadata, "workflow.wf", "environmentOverride.dev"}, OrderSections(mustHash(t, definition.Spec)))
+}
+
+func TestRenderAppDefinition(t *testing.T) {
+	content, err := RenderAppDefinition(&appBean.AppDetail{Metadata: &appBean.AppMetadata{AppName: "demo", ProjectName: "team"}})
+	assert.NoError(t, err)
+	definition, err := ParseAppDefinition(content)
+	assert.NoError(t, err)
+	assert.True(t, definition.Adopt)
+	assert.Equal(t, "demo", definition.Spec.Metadata.AppName)
+}
+
+func TestRedactAndFillSecrets(t *testing.T) {
+	current := &appBean.AppDetail{
+		GlobalSecrets: []*appBean.Secret{
+			{Name: "db", Data: map[string]interface{}{"password": "cGFzcw=="}},
+			{Name: "vault", IsExternal: true, ExternalSecretData: []*appBean.ExternalSecret{{Key: "path"}}},
+		},
+	}
+	redacted, err := RedactSecrets(current)
+	assert.NoError(t, err)
+	assert.Equal(t, bean.RedactedSecretValue, redacted.GlobalSecrets[0].Data["password"])
+	assert.Equal(t, "cGFzcw==", current.GlobalSecrets[0].Data["password"])
+	assert.Equal(t, "path", redacted.GlobalSecrets[1].ExternalSecretData[0].Key)
+
+	desired := &appBean.AppDetail{GlobalSecrets: []*appBean.Secret{{Name: "db", Data: map[string]interface{}{"password": bean.RedactedSecretValue, "user": "YWRtaW4="}}}}
+	assert.NoError(t, FillRedactedSecrets(desired, current))
+	assert.Equal(t, "cGFzcw==", desired.GlobalSecrets[0].Data["password"])
+	assert.Equal(t, "YWRtaW4=", desired.GlobalSecrets[0].Data["user"])
+
+	desired = &appBean.AppDetail{EnvironmentOverrides: map[string]*appBean.EnvironmentOverride{
+		"dev": {Secrets: []*appBean.Secret{{Name: "db", Data: map[string]interface{}{"password": bean.RedactedSecretValue}}}},
+	}}
+	assert.Error(t, FillRedactedSecrets(desired, current))
+}
+
+func TestHashSections(t *testing.T) {
+	appDetail := &appBean.AppDetail{
+		Metadata:         &appBean.AppMetadata{AppName: "demo", ProjectName: "team"},
+		GlobalConfigMaps: []*appBean.ConfigMap{{Name: "cm", Data: map[string]interface{}{"a": "1"}}},
+	}
+	hashes := mustHash(t, appDetail)
+	assert.Len(t, hashes, 2)
+	appDetail.GlobalConfigMaps[0].Data["a"] = "2"
+	changed := mustHash(t, appDetail)
+	assert.Equal(t, hashes[bean.SectionMetadata], changed[bean.SectionMetadata])
+	assert.NotEqual(t, hashes[bean.SectionGlobalConfigMaps], changed[bean.SectionGlobalConfigMaps])
+}
+
+func TestGetAppSyncStatus(t *testing.T) {
+	assert.Equal(t, bean.AppSyncStatusInSync, GetAppSyncStatus(nil))
+	assert.Equal(t, bean.AppSyncStatusDrifted, GetAppSyncStatus([]*bean.SectionResult{{Action: bean.SectionActionApplied}, {Action: bean.SectionActionDrifted}}))
+	assert.Equal(t, bean.AppSyncStatusFailed, GetAppSyncStatus([]*bean.SectionResult{{Action: bean.SectionActionDrifted}, {Action: bean.SectionActionFailed}}))
+}
+
+func mustHash(t *testing.T, appDetail *appBean.AppDetail) map[string]string {
+	hashes, err := HashSections(appDetail)
+	assert.NoError(t, err)
+	return hashes
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type AppAsCodeSource struct {
+	tableName     struct{} `sql:"app_as_code_source" pg:",discard_unknown_columns"`
+	Id            int      `sql:"id,pk"`
+	Name          string   `sql:"name,notnull"`
+	GitProviderId int      `sql:"git_provider_id,notnull"`
+	GitRepoUrl    string   `sql:"git_repo_url,notnull"`
+	GitBranch     string   `sql:"git_branch"`
+	SpecPath      string   `sql:"spec_path"`
+	DriftMode     string   `sql:"drift_mode,notnull"`
+	// GitMaterialId and CiPipelineMaterialId register the source with git-sensor which polls its branch
+	GitMaterialId        int       `sql:"git_material_id"`
+	CiPipelineMaterialId int       `sql:"ci_pipeline_material_id"`
+	Active               bool      `sql:"active,notnull"`
+	LastSyncedCommit     string    `sql:"last_synced_commit"`
+	LastSyncedOn         time.Time `sql:"last_synced_on"`
+	LastSyncStatus       string    `sql:"last_sync_status"`
+	LastSyncMessage      string    `sql:"last_sync_message"`
+	sql.AuditLog
+}
+
+// AppAsCodeApp is an app managed by a source, the section digests are of the definition and of the app state as of the last sync
+type AppAsCodeApp struct {
+	tableName struct{} `sql:"app_as_code_app" pg:",discard_unknown_columns"`
+	Id        int      `sql:"id,pk"`
+	SourceId  int      `sql:"source_id,notnull"`
+	AppId     int      `sql:"app_id"`
+	AppName   string   `sql:"app_name"`
+	FilePath  string   `sql:"file_path,notnull"`
+	// Definition is the content of the definition file as of AppliedCommit, reused by syncs while the branch has no new commits
+	Definition     string    `sql:"definition"`
+	Status         string    `sql:"status,notnull"`
+	Message        string    `sql:"message"`
+	SectionResults string    `sql:"section_results"`
+	SpecHashes     string    `sql:"spec_hashes"`
+	StateHashes    string    `sql:"state_hashes"`
+	AppliedCommit  string    `sql:"applied_commit"`
+	LastSyncedOn   time.Time `sql:"last_synced_on"`
+	Active         bool      `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type AppAsCodeRepository interface {
+	sql.TransactionWrapper
+	SaveSource(source *AppAsCodeSource, tx *pg.Tx) error
+	UpdateSource(source *AppAsCodeSource) error
+	// ClaimSourceSync marks the active source as being synced unless a sync started after staleBefore still holds it,
+	// false if it does. sync_started_on is not part of the model so that updates of the source do not overwrite the claim
+	ClaimSourceSync(id int, staleBefore time.Time) (bool, error)
+	ReleaseSourceSync(id int) error
+	FindActiveSourceById(id int) (*AppAsCodeSource, error)
+	FindActiveSourceByName(name string) (*AppAsCodeSource, error)
+	FindAllActiveSources() ([]*AppAsCodeSource, error)
+	FindActiveSourceByCiPipelineMaterialId(ciPipelineMaterialId int) (*AppAsCodeSource, error)
+	// ReserveGitSensorMaterialIds takes ids from the sequences of git materials and ci pipeline materials, git-sensor
+	// keeps sources and app materials in the same tables
+	ReserveGitSensorMaterialIds() (gitMaterialId int, ciPipelineMaterialId int, err error)
+
+	SaveApp(app *AppAsCodeApp) error
+	UpdateApp(app *AppAsCodeApp) error
+	FindActiveAppsBySourceId(sourceId int) ([]*AppAsCodeApp, error)
+	FindActiveAppByAppId(appId int) (*AppAsCodeApp, error)
+}
+
+type AppAsCodeRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewAppAsCodeRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *AppAsCodeRepositoryImpl {
+	return &AppAsCodeRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: sql.NewTransactionUtilImpl(dbConnection),
+	}
+}
+
+func (impl *AppAsCodeRepositoryImpl) SaveSource(source *AppAsCodeSource, tx *pg.Tx) error {
+	return tx.Insert(source)
+}
+
+func (impl *AppAsCodeRepositoryImpl) UpdateSource(source *AppAsCodeSource) error {
+	return impl.dbConnection.Update(source)
+}
+
+func (impl *AppAsCodeRepositoryImpl) ClaimSourceSync(id int, staleBefore time.Time) (bool, error) {
+	result, err := impl.dbConnection.Model((*AppAsCodeSource)(nil)).
+		Set("sync_started_on = ?", time.Now()).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Where("sync_started_on IS NULL OR sync_started_on < ?", staleBefore).
+		Update()
+	if err != nil {
+		impl.logger.Errorw("error in claiming app-as-code source sync", "id", id, "err", err)
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (impl *AppAsCodeRepositoryImpl) ReleaseSourceSync(id int) error {
+	_, err := impl.dbConnection.Model((*AppAsCodeSource)(nil)).
+		Set("sync_started_on = NULL").
+		Where("id = ?", id).
+		Update()
+	return err
+}
+
+func (impl *AppAsCodeRepositoryImpl) FindActiveSourceById(id int) (*AppAsCodeSource, error) {
+	source := &AppAsCodeSource{}
+	err := impl.dbConnection.Model(source).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return source, err
+}
+
+func (impl *AppAsCodeRepositoryImpl) FindActiveSourceByName(name string) (*AppAsCodeSource, error) {
+	source := &AppAsCodeSource{}
+	err := impl.dbConnection.Model(source).
+		Where("name = ?", name).
+		Where("active = ?", true).
+		Select()
+	return source, err
+}
+
+func (impl *AppAsCodeRepositoryImpl) FindAllActiveSources() ([]*AppAsCodeSource, error) {
+	var sources []*AppAsCodeSource
+	err := impl.dbConnection.Model(&sources).
+		Where("active = ?", true).
+		Order("id").
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting active app-as-code sources", "err", err)
+		return nil, err
+	}
+	return sources, nil
+}
+
+func (impl *AppAsCodeRepositoryImpl) FindActiveSourceByCiPipelineMaterialId(ciPipelineMaterialId int) (*AppAsCodeSource, error) {
+	source := &AppAsCodeSource{}
+	err := impl.dbConnection.Model(source).
+		Where("ci_pipeline_material_id = ?", ciPipelineMaterialId).
+		Where("active = ?", true).
+		Select()
+	return source, err
+}
+
+func (impl *AppAsCodeRepositoryImpl) ReserveGitSensorMaterialIds() (int, int, error) {
+	var ids struct {
+		GitMaterialId        int
+		CiPipelineMaterialId int
+	}
+	_, err := impl.dbConnection.QueryOne(&ids, "SELECT nextval('git_material_id_seq') AS git_material_id, nextval('ci_pipeline_material_id_seq') AS ci_pipeline_material_id")
+	if err != nil {
+		impl.logger.Errorw("error in reserving git-sensor material ids", "err", err)
+		return 0, 0, err
+	}
+	return ids.GitMaterialId, ids.CiPipelineMaterialId, nil
+}
+
+func (impl *AppAsCodeRepositoryImpl) SaveApp(app *AppAsCodeApp) error {
+	return impl.dbConnection.Insert(app)
+}
+
+func (impl *AppAsCodeRepositoryImpl) UpdateApp(app *AppAsCodeApp) error {
+	return impl.dbConnection.Update(app)
+}
+
+func (impl *AppAsCodeRepositoryImpl) FindActiveAppsBySourceId(sourceId int) ([]*AppAsCodeApp, error) {
+	var apps []*AppAsCodeApp
+	err := impl.dbConnection.Model(&apps).
+		Where("source_id = ?", sourceId).
+		Where("active = ?", true).
+		Order("file_path").
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting apps of app-as-code source", "sourceId", sourceId, "err", err)
+		return nil, err
+	}
+	return apps, nil
+}
+
+func (impl *AppAsCodeRepositoryImpl) FindActiveAppByAppId(appId int) (*AppAsCodeApp, error) {
+	app := &AppAsCodeApp{}
+	err := impl.dbConnection.Model(app).
+		Where("app_id = ?", appId).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return app, err
+}
@@ -0,0 +1,233 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adoption
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	openapi "github.com/devtron-labs/devtron/api/helm-app/openapiClient"
+	helmAppService "github.com/devtron-labs/devtron/api/helm-app/service"
+	helmBean "github.com/devtron-labs/devtron/api/helm-app/service/bean"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appStore/adoption/bean"
+	"github.com/devtron-labs/devtron/pkg/appStore/adoption/helper"
+	"github.com/devtron-labs/devtron/pkg/appStore/adoption/repository"
+	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
+	appStoreDiscoverRepository "github.com/devtron-labs/devtron/pkg/appStore/discover/repository"
+	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/read"
+	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type ReleaseAdoptionService interface {
+	// GetAdoptionCandidates returns the chart versions an external helm release can be adopted with, those synced
+	// with the name and version of the chart of the release
+	GetAdoptionCandidates(ctx context.Context, appIdentifier *helmBean.AppIdentifier) (*bean.AdoptionCandidates, error)
+	// AdoptRelease links an external helm release to a chart version of the chart store keeping the values of its
+	// current revision, from then on the release is upgraded through Devtron
+	AdoptRelease(ctx context.Context, appIdentifier *helmBean.AppIdentifier, request *bean.AdoptReleaseRequest) (*bean.ReleaseAdoptionDto, error)
+	GetAdoption(installedAppId int) (*bean.ReleaseAdoptionDto, error)
+}
+
+type ReleaseAdoptionServiceImpl struct {
+	logger                               *zap.SugaredLogger
+	repository                           repository.HelmReleaseAdoptionRepository
+	helmAppService                       helmAppService.HelmAppService
+	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository
+	installedAppReadService              read.InstalledAppReadServiceEA
+	appStoreDeploymentService            service.AppStoreDeploymentService
+}
+
+func NewReleaseAdoptionServiceImpl(logger *zap.SugaredLogger,
+	repository repository.HelmReleaseAdoptionRepository,
+	helmAppService helmAppService.HelmAppService,
+	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository,
+	installedAppReadService read.InstalledAppReadServiceEA,
+	appStoreDeploymentService service.AppStoreDeploymentService) *ReleaseAdoptionServiceImpl {
+	return &ReleaseAdoptionServiceImpl{
+		logger:                               logger,
+		repository:                           repository,
+		helmAppService:                       helmAppService,
+		appStoreApplicationVersionRepository: appStoreApplicationVersionRepository,
+		installedAppReadService:              installedAppReadService,
+		appStoreDeploymentService:            appStoreDeploymentService,
+	}
+}
+
+func (impl *ReleaseAdoptionServiceImpl) GetAdoptionCandidates(ctx context.Context, appIdentifier *helmBean.AppIdentifier) (*bean.AdoptionCandidates, error) {
+	candidates := &bean.AdoptionCandidates{
+		AppId:       impl.helmAppService.EncodeAppId(appIdentifier),
+		ClusterId:   appIdentifier.ClusterId,
+		Namespace:   appIdentifier.Namespace,
+		ReleaseName: appIdentifier.ReleaseName,
+		Candidates:  make([]*bean.AdoptionCandidate, 0),
+	}
+	installedApp, err := impl.installedAppReadService.GetInstalledAppByAppName(appIdentifier.GetUniqueAppNameIdentifier())
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting installed app of release", "appIdentifier", appIdentifier, "err", err)
+		return nil, err
+	} else if err == nil {
+		candidates.InstalledAppId = installedApp.Id
+	}
+	isInstalled, err := impl.helmAppService.IsReleaseInstalled(ctx, appIdentifier)
+	if err != nil {
+		impl.logger.Errorw("error in checking if the release is installed", "appIdentifier", appIdentifier, "err", err)
+		return nil, err
+	}
+	if !isInstalled {
+		return nil, util.NewApiError(http.StatusNotFound, "release not found", "release is not installed")
+	}
+	releaseInfo, err := impl.helmAppService.GetValuesYaml(ctx, appIdentifier)
+	if err != nil {
+		impl.logger.Errorw("error in getting values of release", "appIdentifier", appIdentifier, "err", err)
+		return nil, err
+	}
+	if releaseInfo.GetDeployedAppDetail() != nil {
+		candidates.ChartName = releaseInfo.GetDeployedAppDetail().GetChartName()
+		candidates.ChartVersion = releaseInfo.GetDeployedAppDetail().GetChartVersion()
+	}
+	candidates.ValuesYaml, err = helper.NormaliseValues(releaseInfo.GetOverrideValues())
+	if err != nil {
+		impl.logger.Errorw("error in parsing values of release", "appIdentifier", appIdentifier, "err", err)
+		return nil, util.NewApiError(http.StatusUnprocessableEntity, "values of the release could not be parsed", err.Error())
+	}
+	if len(candidates.ChartName) == 0 || len(candidates.ChartVersion) == 0 {
+		return candidates, nil
+	}
+	chartVersions, err := impl.appStoreApplicationVersionRepository.FindByChartNameAndVersion(candidates.ChartName, candidates.ChartVersion)
+	if err != nil {
+		impl.logger.Errorw("error in finding chart versions of release", "chartName", candidates.ChartName, "chartVersion", candidates.ChartVersion, "err", err)
+		return nil, err
+	}
+	for _, chartVersion := range chartVersions {
+		candidates.Candidates = append(candidates.Candidates, &bean.AdoptionCandidate{
+			AppStoreApplicationVersionId: chartVersion.AppStoreApplicationVersionId,
+			AppStoreId:                   chartVersion.ChartId,
+			ChartName:                    chartVersion.ChartName,
+			Version:                      chartVersion.Version,
+			ChartRepoId:                  chartVersion.ChartRepoId,
+			ChartRepoName:                chartVersion.ChartRepoName,
+			DockerArtifactStoreId:        chartVersion.DockerArtifactStoreId,
+			Deprecated:                   chartVersion.Deprecated,
+		})
+	}
+	return candidates, nil
+}
+
+func (impl *ReleaseAdoptionServiceImpl) AdoptRelease(ctx context.Context, appIdentifier *helmBean.AppIdentifier, request *bean.AdoptReleaseRequest) (*bean.ReleaseAdoptionDto, error) {
+	candidates, err := impl.GetAdoptionCandidates(ctx, appIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if candidates.InstalledAppId > 0 {
+		return nil, util.NewApiError(http.StatusConflict, "release is already managed by Devtron", fmt.Sprintf("release is linked to installed app %d", candidates.InstalledAppId))
+	}
+	candidate, err := helper.SelectCandidate(candidates.Candidates, request.AppStoreApplicationVersionId)
+	if err != nil {
+		return nil, impl.getCandidateError(candidates, err)
+	}
+	valuesYaml := candidates.ValuesYaml
+	appStoreApplicationVersionId := float32(candidate.AppStoreApplicationVersionId)
+	referenceValueKind := appStoreBean.REFERENCE_TYPE_DEFAULT
+	linkRequest := &openapi.UpdateReleaseWithChartLinkingRequest{
+		AppId:                        &candidates.AppId,
+		ValuesYaml:                   &valuesYaml,
+		AppStoreApplicationVersionId: &appStoreApplicationVersionId,
+		ReferenceValueId:             &appStoreApplicationVersionId,
+		ReferenceValueKind:           &referenceValueKind,
+	}
+	_, isChartRepoActive, err := impl.appStoreDeploymentService.LinkHelmApplicationToChartStore(ctx, linkRequest, appIdentifier, request.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in linking release to chart store", "appIdentifier", appIdentifier, "appStoreApplicationVersionId", candidate.AppStoreApplicationVersionId, "err", err)
+		return nil, err
+	} else if !isChartRepoActive {
+		return nil, util.NewApiError(http.StatusNotAcceptable, "chart repo is disabled", "chart repo is disabled")
+	}
+	installedApp, err := impl.installedAppReadService.GetInstalledAppByAppName(appIdentifier.GetUniqueAppNameIdentifier())
+	if err != nil {
+		impl.logger.Errorw("error in getting installed app of adopted release", "appIdentifier", appIdentifier, "err", err)
+		return nil, err
+	}
+	adoption := &repository.HelmReleaseAdoption{
+		InstalledAppId:               installedApp.Id,
+		ClusterId:                    appIdentifier.ClusterId,
+		Namespace:                    appIdentifier.Namespace,
+		ReleaseName:                  appIdentifier.ReleaseName,
+		ChartName:                    candidates.ChartName,
+		ChartVersion:                 candidates.ChartVersion,
+		AppStoreApplicationVersionId: candidate.AppStoreApplicationVersionId,
+		Status:                       bean.AdoptionStatusAdopted,
+		AuditLog:                     sql.NewDefaultAuditLog(request.UserId),
+	}
+	if err = impl.repository.Save(adoption); err != nil {
+		impl.logger.Errorw("error in saving release adoption", "appIdentifier", appIdentifier, "installedAppId", installedApp.Id, "err", err)
+		return nil, err
+	}
+	impl.logger.Infow("adopted external helm release", "appIdentifier", appIdentifier, "installedAppId", installedApp.Id, "appStoreApplicationVersionId", candidate.AppStoreApplicationVersionId)
+	return impl.getAdoptionDto(adoption), nil
+}
+
+func (impl *ReleaseAdoptionServiceImpl) GetAdoption(installedAppId int) (*bean.ReleaseAdoptionDto, error) {
+	adoption, err := impl.repository.FindByInstalledAppId(installedAppId)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "installed app was not adopted from an external helm release", err.Error())
+	} else if err != nil {
+		impl.logger.Errorw("error in getting release adoption", "installedAppId", installedAppId, "err", err)
+		return nil, err
+	}
+	return impl.getAdoptionDto(adoption), nil
+}
+
+func (impl *ReleaseAdoptionServiceImpl) getCandidateError(candidates *bean.AdoptionCandidates, err error) error {
+	chart := fmt.Sprintf("%s %s", candidates.ChartName, candidates.ChartVersion)
+	switch {
+	case errors.Is(err, helper.ErrNoCandidate):
+		return util.NewApiError(http.StatusNotFound,
+			fmt.Sprintf("chart %s of the release is not in any active chart repository or OCI registry, sync the chart repository or link the release manually", chart), err.Error())
+	case errors.Is(err, helper.ErrAmbiguousCandidates):
+		return util.NewApiError(http.StatusBadRequest,
+			fmt.Sprintf("chart %s of the release is in %s, select the chart version to adopt the release with", chart, helper.GetCandidateSources(candidates.Candidates)), err.Error())
+	case errors.Is(err, helper.ErrCandidateMismatch):
+		return util.NewApiError(http.StatusBadRequest, fmt.Sprintf("selected chart version is not %s of the release", chart), err.Error())
+	}
+	return err
+}
+
+func (impl *ReleaseAdoptionServiceImpl) getAdoptionDto(adoption *repository.HelmReleaseAdoption) *bean.ReleaseAdoptionDto {
+	appIdentifier := &helmBean.AppIdentifier{
+		ClusterId:   adoption.ClusterId,
+		Namespace:   adoption.Namespace,
+		ReleaseName: adoption.ReleaseName,
+	}
+	return &bean.ReleaseAdoptionDto{
+		InstalledAppId:               adoption.InstalledAppId,
+		AppId:                        impl.helmAppService.EncodeAppId(appIdentifier),
+		ClusterId:                    adoption.ClusterId,
+		Namespace:                    adoption.Namespace,
+		ReleaseName:                  adoption.ReleaseName,
+		ChartName:                    adoption.ChartName,
+		ChartVersion:                 adoption.ChartVersion,
+		AppStoreApplicationVersionId: adoption.AppStoreApplicationVersionId,
+		Status:                       adoption.Status,
+		Message:                      adoption.Message,
+		AdoptedOn:                    adoption.CreatedOn,
+		AdoptedBy:                    adoption.CreatedBy,
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type AdoptionStatus string
+
+const (
+	AdoptionStatusAdopted                  AdoptionStatus = "adopted"
+	AdoptionStatusGitOpsMigrationInitiated AdoptionStatus = "gitops_migration_initiated"
+	AdoptionStatusGitOpsMigrated           AdoptionStatus = "gitops_migrated"
+	AdoptionStatusGitOpsMigrationFailed    AdoptionStatus = "gitops_migration_failed"
+)
+
+const (
+	// HelmStorageOwnerLabel and HelmStorageNameLabel are set by helm on the secrets it stores the revisions of a release in
+	HelmStorageOwnerLabel = "owner"
+	HelmStorageNameLabel  = "name"
+	HelmStorageOwner      = "helm"
+)
+
+// AdoptionCandidate is a chart version synced from an active chart repository or OCI registry with the name and
+// version of the chart of an external release
+type AdoptionCandidate struct {
+	AppStoreApplicationVersionId int    `json:"appStoreApplicationVersionId"`
+	AppStoreId                   int    `json:"appStoreId"`
+	ChartName                    string `json:"chartName"`
+	Version                      string `json:"version"`
+	ChartRepoId                  int    `json:"chartRepoId,omitempty"`
+	ChartRepoName                string `json:"chartRepoName,omitempty"`
+	DockerArtifactStoreId        string `json:"dockerArtifactStoreId,omitempty"`
+	Deprecated                   bool   `json:"deprecated"`
+}
+
+type AdoptionCandidates struct {
+	AppId        string `json:"appId"`
+	ClusterId    int    `json:"clusterId"`
+	Namespace    string `json:"namespace"`
+	ReleaseName  string `json:"releaseName"`
+	ChartName    string `json:"chartName"`
+	ChartVersion string `json:"chartVersion"`
+	// ValuesYaml are the values supplied by the user to the current revision of the release, these are kept on adoption
+	ValuesYaml string               `json:"valuesYaml"`
+	Candidates []*AdoptionCandidate `json:"candidates"`
+	// InstalledAppId is set when the release is already managed by Devtron
+	InstalledAppId int `json:"installedAppId,omitempty"`
+}
+
+type AdoptReleaseRequest struct {
+	AppId string `json:"appId" validate:"required"`
+	// AppStoreApplicationVersionId picks the chart version to adopt the release with, it can be left out when a
+	// single chart version matches the release
+	AppStoreApplicationVersionId int   `json:"appStoreApplicationVersionId"`
+	UserId                       int32 `json:"-"`
+}
+
+type ReleaseAdoptionDto struct {
+	InstalledAppId               int            `json:"installedAppId"`
+	AppId                        string         `json:"appId"`
+	ClusterId                    int            `json:"clusterId"`
+	Namespace                    string         `json:"namespace"`
+	ReleaseName                  string         `json:"releaseName"`
+	ChartName                    string         `json:"chartName"`
+	ChartVersion                 string         `json:"chartVersion"`
+	AppStoreApplicationVersionId int            `json:"appStoreApplicationVersionId"`
+	Status                       AdoptionStatus `json:"status"`
+	Message                      string         `json:"message,omitempty"`
+	AdoptedOn                    time.Time      `json:"adoptedOn"`
+	AdoptedBy                    int32          `json:"adoptedBy"`
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/pkg/appStore/adoption/bean"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+var (
+	ErrNoCandidate         = errors.New("no chart version matches the release")
+	ErrAmbiguousCandidates = errors.New("more than one chart version matches the release")
+	ErrCandidateMismatch   = errors.New("chart version does not match the release")
+)
+
+// SelectCandidate picks the chart version to adopt a release with. A requested chart version must be one of the
+// candidates, without one the candidate is picked only if it is the single one.
+func SelectCandidate(candidates []*bean.AdoptionCandidate, appStoreApplicationVersionId int) (*bean.AdoptionCandidate, error) {
+	if appStoreApplicationVersionId > 0 {
+		for _, candidate := range candidates {
+			if candidate.AppStoreApplicationVersionId == appStoreApplicationVersionId {
+				return candidate, nil
+			}
+		}
+		return nil, ErrCandidateMismatch
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, ErrNoCandidate
+	case 1:
+		return candidates[0], nil
+	default:
+		return nil, ErrAmbiguousCandidates
+	}
+}
+
+// GetCandidateSources lists the chart repositories or OCI registries of the candidates, for error messages
+func GetCandidateSources(candidates []*bean.AdoptionCandidate) string {
+	sources := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if len(candidate.ChartRepoName) > 0 {
+			sources = append(sources, candidate.ChartRepoName)
+		} else {
+			sources = append(sources, candidate.DockerArtifactStoreId)
+		}
+	}
+	return strings.Join(sources, ", ")
+}
+
+// NormaliseValues converts the user supplied values of a release, returned as json or yaml, to yaml. Releases
+// installed without values get empty values.
+func NormaliseValues(values string) (string, error) {
+	values = strings.TrimSpace(values)
+	if len(values) == 0 || values == "{}" || values == "null" {
+		return "", nil
+	}
+	valuesJson, err := yaml.YAMLToJSON([]byte(values))
+	if err != nil {
+		return "", err
+	}
+	valuesYaml, err := yaml.JSONToYAML(valuesJson)
+	if err != nil {
+		return "", err
+	}
+	return string(valuesYaml), nil
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package helper
+
+import (
+	"github.com/devtron-labs/devtron/pkg/appStore/adoption/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSelectCandidate(t *testing.T) {
+	bitnami := &bean.AdoptionCandidate{AppStoreApplicationVersionId: 11, ChartName: "redis", Version: "18.1.0", ChartRepoName: "bitnami"}
+	mirror := &bean.AdoptionCandidate{AppStoreApplicationVersionId: 12, ChartName: "redis", Version: "18.1.0", DockerArtifactStoreId: "ecr-mirror"}
+
+	candidate, err := SelectCandidate([]*bean.AdoptionCandidate{bitnami}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, bitnami, candidate)
+
+	_, err = SelectCandidate(nil, 0)
+	assert.Equal(t, ErrNoCandidate, err)
+
+	_, err = SelectCandidate([]*bean.AdoptionCandidate{bitnami, mirror}, 0)
+	assert.Equal(t, ErrAmbiguousCandidates, err)
+
+	candidate, err = SelectCandidate([]*bean.AdoptionCandidate{bitnami, mirror}, 12)
+	assert.Nil(t, err)
+	assert.Equal(t, mirror, candidate)
+
+	_, err = SelectCandidate([]*bean.AdoptionCandidate{bitnami, mirror}, 13)
+	assert.Equal(t, ErrCandidateMismatch, err)
+
+	assert.Equal(t, "bitnami, ecr-mirror", GetCandidateSources([]*bean.AdoptionCandidate{bitnami, mirror}))
+}
+
+func TestNormaliseValues(t *testing.T) {
+	values, err := NormaliseValues(`{"replica":{"count":2},"auth":{"enabled":false}}`)
+	assert.Nil(t, err)
+	assert.Equal(t, "auth:\n  enabled: false\nreplica:\n  count: 2\n", values)
+
+	values, err = NormaliseValues("replica:\n  count: 2\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "replica:\n  count: 2\n", values)
+
+	for _, empty := range []string{"", " ", "{}", "null"} {
+		values, err = NormaliseValues(empty)
+		assert.Nil(t, err)
+		assert.Equal(t, "", values)
+	}
+
+	_, err = NormaliseValues("replica: [")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/appStore/adoption/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type HelmReleaseAdoption struct {
+	tableName                    struct{}            `sql:"helm_release_adoption" pg:",discard_unknown_columns"`
+	Id                           int                 `sql:"id,pk"`
+	InstalledAppId               int                 `sql:"installed_app_id,notnull"`
+	ClusterId                    int                 `sql:"cluster_id,notnull"`
+	Namespace                    string              `sql:"namespace,notnull"`
+	ReleaseName                  string              `sql:"release_name,notnull"`
+	ChartName                    string              `sql:"chart_name,notnull"`
+	ChartVersion                 string              `sql:"chart_version,notnull"`
+	AppStoreApplicationVersionId int                 `sql:"app_store_application_version_id,notnull"`
+	Status                       bean.AdoptionStatus `sql:"status,notnull"`
+	Message                      string              `sql:"message"`
+	sql.AuditLog
+}
+
+type HelmReleaseAdoptionRepository interface {
+	Save(adoption *HelmReleaseAdoption) error
+	Update(adoption *HelmReleaseAdoption) error
+	FindByInstalledAppId(installedAppId int) (*HelmReleaseAdoption, error)
+}
+
+type HelmReleaseAdoptionRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewHelmReleaseAdoptionRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *HelmReleaseAdoptionRepositoryImpl {
+	return &HelmReleaseAdoptionRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *HelmReleaseAdoptionRepositoryImpl) Save(adoption *HelmReleaseAdoption) error {
+	return impl.dbConnection.Insert(adoption)
+}
+
+func (impl *HelmReleaseAdoptionRepositoryImpl) Update(adoption *HelmReleaseAdoption) error {
+	return impl.dbConnection.Update(adoption)
+}
+
+func (impl *HelmReleaseAdoptionRepositoryImpl) FindByInstalledAppId(installedAppId int) (*HelmReleaseAdoption, error) {
+	adoption := &HelmReleaseAdoption{}
+	err := impl.dbConnection.Model(adoption).
+		Where("installed_app_id = ?", installedAppId).
+		Select()
+	return adoption, err
+}
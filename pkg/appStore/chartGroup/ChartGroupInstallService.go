@@ -0,0 +1,620 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chartGroup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	k8s2 "github.com/devtron-labs/common-lib/utils/k8s"
+	helmBean "github.com/devtron-labs/devtron/api/helm-app/service/bean"
+	"github.com/devtron-labs/devtron/client/argocdServer"
+	"github.com/devtron-labs/devtron/internal/util"
+	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
+	chartGroupBean "github.com/devtron-labs/devtron/pkg/appStore/chartGroup/bean"
+	"github.com/devtron-labs/devtron/pkg/appStore/chartGroup/helper"
+	repository2 "github.com/devtron-labs/devtron/pkg/appStore/chartGroup/repository"
+	service2 "github.com/devtron-labs/devtron/pkg/appStore/installedApp/service"
+	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/FullMode"
+	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cronUtil "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	helmService "github.com/devtron-labs/devtron/api/helm-app/service"
+)
+
+type ChartGroupInstallConfig struct {
+	PollIntervalSecs int `env:"CHART_GROUP_INSTALL_POLL_INTERVAL_SECS" envDefault:"30" description:"Interval at which the readiness of deployed chart group entries is checked and the entries depending on them are deployed"`
+}
+
+func GetChartGroupInstallConfig() (*ChartGroupInstallConfig, error) {
+	cfg := &ChartGroupInstallConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+type ChartGroupInstallService interface {
+	// CreateInstallation saves the installation of the charts of a chart group in the transaction the installed apps are
+	// created in, nothing is deployed until ProcessInstallation is called
+	CreateInstallation(request *ChartGroupInstallRequest, installAppVersions []*appStoreBean.InstallAppVersionDTO, tx *pg.Tx) (*repository2.ChartGroupInstall, error)
+	// ProcessInstallation checks the readiness of the deployed entries, applies the failure policy and deploys the
+	// entries whose dependencies are ready
+	ProcessInstallation(installationId int) error
+	// ProcessInProgressInstallations is run by the poll cron for every installation in progress
+	ProcessInProgressInstallations()
+	GetInstallation(installationId int) (*chartGroupBean.ChartGroupInstallDto, error)
+	GetInstallations(chartGroupId int) ([]*chartGroupBean.ChartGroupInstallDto, error)
+}
+
+type ChartGroupInstallServiceImpl struct {
+	logger                          *zap.SugaredLogger
+	chartGroupInstallRepository     repository2.ChartGroupInstallRepository
+	chartGroupEntriesRepository     repository2.ChartGroupEntriesRepository
+	appStoreDeploymentService       service2.AppStoreDeploymentService
+	appStoreDeploymentDBService     service2.AppStoreDeploymentDBService
+	installAppService               FullMode.InstalledAppDBExtendedService
+	appStoreAppsEventPublishService out.AppStoreAppsEventPublishService
+	helmAppService                  helmService.HelmAppService
+	argoClientWrapperService        argocdServer.ArgoClientWrapperService
+	k8sCommonService                k8s.K8sCommonService
+	K8sUtil                         *k8s2.K8sServiceImpl
+}
+
+func NewChartGroupInstallServiceImpl(logger *zap.SugaredLogger,
+	chartGroupInstallRepository repository2.ChartGroupInstallRepository,
+	chartGroupEntriesRepository repository2.ChartGroupEntriesRepository,
+	appStoreDeploymentService service2.AppStoreDeploymentService,
+	appStoreDeploymentDBService service2.AppStoreDeploymentDBService,
+	installAppService FullMode.InstalledAppDBExtendedService,
+	appStoreAppsEventPublishService out.AppStoreAppsEventPublishService,
+	helmAppService helmService.HelmAppService,
+	argoClientWrapperService argocdServer.ArgoClientWrapperService,
+	k8sCommonService k8s.K8sCommonService,
+	K8sUtil *k8s2.K8sServiceImpl,
+	config *ChartGroupInstallConfig,
+	cronLogger *cronUtil.CronLoggerImpl) (*ChartGroupInstallServiceImpl, error) {
+	impl := &ChartGroupInstallServiceImpl{
+		logger:                          logger,
+		chartGroupInstallRepository:     chartGroupInstallRepository,
+		chartGroupEntriesRepository:     chartGroupEntriesRepository,
+		appStoreDeploymentService:       appStoreDeploymentService,
+		appStoreDeploymentDBService:     appStoreDeploymentDBService,
+		installAppService:               installAppService,
+		appStoreAppsEventPublishService: appStoreAppsEventPublishService,
+		helmAppService:                  helmAppService,
+		argoClientWrapperService:        argoClientWrapperService,
+		k8sCommonService:                k8sCommonService,
+		K8sUtil:                         K8sUtil,
+	}
+	if config.PollIntervalSecs > 0 {
+		pollCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+		_, err := pollCron.AddFunc(fmt.Sprintf("@every %ds", config.PollIntervalSecs), impl.ProcessInProgressInstallations)
+		if err != nil {
+			logger.Errorw("error in adding chart group install poll cron", "err", err)
+			return nil, err
+		}
+		pollCron.Start()
+	}
+	return impl, nil
+}
+
+// failedDeploymentStatuses are the statuses an installed app is left in when its deployment could not be done
+var failedDeploymentStatuses = map[appStoreBean.AppstoreDeploymentStatus]bool{
+	appStoreBean.QUE_ERROR:     true,
+	appStoreBean.DEQUE_ERROR:   true,
+	appStoreBean.TRIGGER_ERROR: true,
+	appStoreBean.GIT_ERROR:     true,
+	appStoreBean.ACD_ERROR:     true,
+	appStoreBean.HELM_ERROR:    true,
+}
+
+func (impl *ChartGroupInstallServiceImpl) CreateInstallation(request *ChartGroupInstallRequest, installAppVersions []*appStoreBean.InstallAppVersionDTO, tx *pg.Tx) (*repository2.ChartGroupInstall, error) {
+	chartGroupEntries, err := impl.chartGroupEntriesRepository.FindEntriesWithChartMetaByChartGroupId([]int{request.ChartGroupId})
+	if err != nil {
+		impl.logger.Errorw("error in getting chart group entries", "chartGroupId", request.ChartGroupId, "err", err)
+		return nil, err
+	}
+	chartGroupEntryMap := make(map[int]*repository2.ChartGroupEntry, len(chartGroupEntries))
+	for _, chartGroupEntry := range chartGroupEntries {
+		chartGroupEntryMap[chartGroupEntry.Id] = chartGroupEntry
+	}
+	failurePolicy := request.FailurePolicy
+	if len(failurePolicy) == 0 {
+		failurePolicy = chartGroupBean.FailurePolicyContinue
+	}
+	now := time.Now()
+	install := &repository2.ChartGroupInstall{
+		ChartGroupId:  request.ChartGroupId,
+		ProjectId:     request.ProjectId,
+		FailurePolicy: failurePolicy,
+		Status:        chartGroupBean.InstallStatusInProgress,
+		StartedOn:     now,
+		AuditLog:      sql.NewDefaultAuditLog(request.UserId),
+	}
+	err = impl.chartGroupInstallRepository.SaveInstall(install, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving chart group install", "chartGroupId", request.ChartGroupId, "err", err)
+		return nil, err
+	}
+	defaultReadiness, err := json.Marshal(chartGroupBean.GetDefaultReadinessCriteria())
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*repository2.ChartGroupInstallEntry, 0, len(installAppVersions))
+	for _, installAppVersion := range installAppVersions {
+		entry := &repository2.ChartGroupInstallEntry{
+			ChartGroupInstallId:          install.Id,
+			ChartGroupEntryId:            installAppVersion.ChartGroupEntryId,
+			InstalledAppId:               installAppVersion.InstalledAppId,
+			InstalledAppVersionId:        installAppVersion.InstalledAppVersionId,
+			InstalledAppVersionHistoryId: installAppVersion.InstalledAppVersionHistoryId,
+			AppName:                      installAppVersion.AppName,
+			EnvironmentId:                installAppVersion.EnvironmentId,
+			Status:                       chartGroupBean.EntryStatusWaiting,
+			Readiness:                    string(defaultReadiness),
+			AuditLog:                     sql.NewDefaultAuditLog(request.UserId),
+		}
+		// charts added to the request outside the chart group are keyed by their app name and depend on nothing
+		entry.EntryKey = installAppVersion.AppName
+		if chartGroupEntry, ok := chartGroupEntryMap[installAppVersion.ChartGroupEntryId]; ok {
+			if len(chartGroupEntry.EntryKey) > 0 {
+				entry.EntryKey = chartGroupEntry.EntryKey
+			} else if chartGroupEntry.AppStoreApplicationVersion != nil {
+				entry.EntryKey = chartGroupEntry.AppStoreApplicationVersion.Name
+			}
+			entry.DependsOn = chartGroupEntry.DependsOn
+			if len(chartGroupEntry.Readiness) > 0 {
+				entry.Readiness = chartGroupEntry.Readiness
+			}
+		}
+		entries = append(entries, entry)
+	}
+	err = impl.chartGroupInstallRepository.SaveEntries(entries, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving chart group install entries", "installationId", install.Id, "err", err)
+		return nil, err
+	}
+	return install, nil
+}
+
+func (impl *ChartGroupInstallServiceImpl) ProcessInProgressInstallations() {
+	installs, err := impl.chartGroupInstallRepository.FindInProgressInstalls()
+	if err != nil {
+		impl.logger.Errorw("error in getting in progress chart group installs", "err", err)
+		return
+	}
+	for _, install := range installs {
+		err = impl.ProcessInstallation(install.Id)
+		if err != nil {
+			impl.logger.Errorw("error in processing chart group install", "installationId", install.Id, "err", err)
+		}
+	}
+}
+
+func (impl *ChartGroupInstallServiceImpl) ProcessInstallation(installationId int) error {
+	install, err := impl.chartGroupInstallRepository.FindInstallById(installationId)
+	if err != nil {
+		impl.logger.Errorw("error in getting chart group install", "installationId", installationId, "err", err)
+		return err
+	}
+	if install.Status != chartGroupBean.InstallStatusInProgress {
+		return nil
+	}
+	entries, err := impl.chartGroupInstallRepository.FindEntriesByInstallId(installationId)
+	if err != nil {
+		impl.logger.Errorw("error in getting chart group install entries", "installationId", installationId, "err", err)
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Status == chartGroupBean.EntryStatusDeploying {
+			impl.updateReadiness(entry)
+		}
+	}
+	var failedEntry *repository2.ChartGroupInstallEntry
+	rollingBack := false
+	for _, entry := range entries {
+		if entry.Status == chartGroupBean.EntryStatusFailed && failedEntry == nil {
+			failedEntry = entry
+		}
+		// a rollback left halfway is carried on even when the failed entry is rolled back already
+		rollingBack = rollingBack || entry.Status == chartGroupBean.EntryStatusRolledBack
+	}
+	if rollingBack {
+		return impl.rollbackInstallation(install, entries)
+	}
+	if failedEntry != nil {
+		switch install.FailurePolicy {
+		case chartGroupBean.FailurePolicyRollback:
+			install.Message = fmt.Sprintf("rolled back as entry %s failed", failedEntry.EntryKey)
+			return impl.rollbackInstallation(install, entries)
+		case chartGroupBean.FailurePolicyStop:
+			for _, entry := range entries {
+				if entry.Status == chartGroupBean.EntryStatusWaiting {
+					impl.updateEntryStatus(entry, chartGroupBean.EntryStatusSkipped, fmt.Sprintf("installation stopped as entry %s failed", failedEntry.EntryKey))
+				}
+			}
+		default:
+			nodes := getDependencyNodes(entries)
+			for _, i := range helper.GetBlocked(nodes) {
+				impl.updateEntryStatus(entries[i], chartGroupBean.EntryStatusSkipped, "skipped as an entry it depends on did not get ready")
+			}
+		}
+	}
+	var toDeploy []*repository2.ChartGroupInstallEntry
+	for _, i := range helper.GetDeployable(getDependencyNodes(entries)) {
+		toDeploy = append(toDeploy, entries[i])
+	}
+	impl.deployEntries(toDeploy)
+	impl.updateInstallStatus(install, entries)
+	return nil
+}
+
+func getDependencyNodes(entries []*repository2.ChartGroupInstallEntry) []*chartGroupBean.DependencyNode {
+	nodes := make([]*chartGroupBean.DependencyNode, 0, len(entries))
+	for _, entry := range entries {
+		nodes = append(nodes, &chartGroupBean.DependencyNode{
+			Key:       entry.EntryKey,
+			DependsOn: getDependsOn(entry.DependsOn),
+			Status:    entry.Status,
+		})
+	}
+	return nodes
+}
+
+func getDependsOn(dependsOn string) []string {
+	var keys []string
+	if len(dependsOn) > 0 {
+		_ = json.Unmarshal([]byte(dependsOn), &keys)
+	}
+	return keys
+}
+
+func getReadiness(readiness string) *chartGroupBean.ReadinessCriteria {
+	criteria := chartGroupBean.GetDefaultReadinessCriteria()
+	if len(readiness) > 0 {
+		_ = json.Unmarshal([]byte(readiness), criteria)
+	}
+	if criteria.TimeoutSeconds <= 0 {
+		criteria.TimeoutSeconds = chartGroupBean.DefaultReadinessTimeoutSeconds
+	}
+	return criteria
+}
+
+// deployEntries publishes the bulk deploy events of the entries, an entry is only deployed by the orchestrator which
+// moves it out of waiting
+func (impl *ChartGroupInstallServiceImpl) deployEntries(entries []*repository2.ChartGroupInstallEntry) {
+	for _, entry := range entries {
+		now := time.Now()
+		entry.Status = chartGroupBean.EntryStatusDeploying
+		entry.Message = ""
+		entry.DeployedOn = now
+		entry.ReadinessDeadline = now.Add(time.Duration(getReadiness(entry.Readiness).TimeoutSeconds) * time.Second)
+		entry.UpdatedOn = now
+		claimed, err := impl.chartGroupInstallRepository.UpdateEntryStatusIfCurrent(entry, chartGroupBean.EntryStatusWaiting)
+		if err != nil || !claimed {
+			if err != nil {
+				impl.logger.Errorw("error in updating chart group install entry", "entryId", entry.Id, "err", err)
+			}
+			entry.Status = chartGroupBean.EntryStatusWaiting
+			continue
+		}
+		installAppVersion := &appStoreBean.InstallAppVersionDTO{
+			InstalledAppId:               entry.InstalledAppId,
+			InstalledAppVersionId:        entry.InstalledAppVersionId,
+			InstalledAppVersionHistoryId: entry.InstalledAppVersionHistoryId,
+		}
+		publishErrMap := impl.appStoreAppsEventPublishService.PublishBulkDeployEvent([]*appStoreBean.InstallAppVersionDTO{installAppVersion})
+		deploymentStatus := appStoreBean.ENQUEUED
+		if publishErr, ok := publishErrMap[entry.InstalledAppVersionId]; !ok || publishErr != nil {
+			deploymentStatus = appStoreBean.QUE_ERROR
+			impl.updateEntryStatus(entry, chartGroupBean.EntryStatusFailed, "deployment could not be enqueued")
+		}
+		_, err = impl.appStoreDeploymentDBService.AppStoreDeployOperationStatusUpdate(entry.InstalledAppId, deploymentStatus)
+		if err != nil {
+			impl.logger.Errorw("error in updating chart group install entry deployment status", "installedAppId", entry.InstalledAppId, "err", err)
+		}
+	}
+}
+
+// updateReadiness moves a deploying entry to ready or failed once its readiness criteria are decided
+func (impl *ChartGroupInstallServiceImpl) updateReadiness(entry *repository2.ChartGroupInstallEntry) {
+	ready, failed, message := impl.checkReadiness(entry)
+	if !ready && !failed && time.Now().After(entry.ReadinessDeadline) {
+		failed = true
+		message = fmt.Sprintf("not ready within %ds: %s", getReadiness(entry.Readiness).TimeoutSeconds, message)
+	}
+	if ready {
+		entry.ReadyOn = time.Now()
+		impl.updateEntryStatus(entry, chartGroupBean.EntryStatusReady, "")
+	} else if failed {
+		impl.updateEntryStatus(entry, chartGroupBean.EntryStatusFailed, message)
+	} else if entry.Message != message {
+		impl.updateEntryStatus(entry, chartGroupBean.EntryStatusDeploying, message)
+	}
+}
+
+// checkReadiness tells whether the app of the entry meets its readiness criteria or can not meet them anymore,
+// the message tells what is being waited for
+func (impl *ChartGroupInstallServiceImpl) checkReadiness(entry *repository2.ChartGroupInstallEntry) (ready bool, failed bool, message string) {
+	installAppVersion, err := impl.installAppService.GetInstalledAppVersion(entry.InstalledAppVersionId, entry.UpdatedBy)
+	if err != nil {
+		impl.logger.Errorw("error in getting installed app version", "installedAppVersionId", entry.InstalledAppVersionId, "err", err)
+		return false, false, "waiting for deployment details"
+	}
+	if failedDeploymentStatuses[installAppVersion.Status] {
+		return false, true, fmt.Sprintf("deployment failed with status %s", installAppVersion.Status.String())
+	}
+	if installAppVersion.Status != appStoreBean.DEPLOY_SUCCESS {
+		return false, false, "waiting for deployment"
+	}
+	readiness := getReadiness(entry.Readiness)
+	ctx := context.Background()
+	switch readiness.Type {
+	case chartGroupBean.ReadinessTypeDeployed:
+		return true, false, ""
+	case chartGroupBean.ReadinessTypeConditions:
+		return impl.checkResourceConditions(ctx, installAppVersion, readiness.Conditions)
+	default:
+		return impl.checkHealth(ctx, installAppVersion)
+	}
+}
+
+func (impl *ChartGroupInstallServiceImpl) checkHealth(ctx context.Context, installAppVersion *appStoreBean.InstallAppVersionDTO) (bool, bool, string) {
+	if util.IsAcdApp(installAppVersion.DeploymentAppType) {
+		argoApplication, err := impl.argoClientWrapperService.GetArgoAppByName(ctx, installAppVersion.ACDAppName)
+		if err != nil {
+			impl.logger.Errorw("error in getting argo cd application", "acdAppName", installAppVersion.ACDAppName, "err", err)
+			return false, false, "waiting for argo cd application"
+		}
+		health := string(argoApplication.Status.Health.Status)
+		return health == chartGroupBean.HealthyStatus, false, fmt.Sprintf("app is %s", health)
+	}
+	appIdentifier := &helmBean.AppIdentifier{
+		ClusterId:   installAppVersion.ClusterId,
+		Namespace:   installAppVersion.Namespace,
+		ReleaseName: installAppVersion.AppName,
+	}
+	appStatus, err := impl.helmAppService.GetApplicationAndReleaseStatus(ctx, appIdentifier)
+	if err != nil {
+		impl.logger.Errorw("error in getting helm release status", "appIdentifier", appIdentifier, "err", err)
+		return false, false, "waiting for helm release"
+	}
+	if strings.EqualFold(appStatus.GetReleaseStatus(), chartGroupBean.HelmReleaseStatusFailed) {
+		return false, true, "helm release failed"
+	}
+	return appStatus.GetApplicationStatus() == chartGroupBean.HealthyStatus, false, fmt.Sprintf("app is %s", appStatus.GetApplicationStatus())
+}
+
+func (impl *ChartGroupInstallServiceImpl) checkResourceConditions(ctx context.Context, installAppVersion *appStoreBean.InstallAppVersionDTO, conditions []*chartGroupBean.ResourceCondition) (bool, bool, string) {
+	restConfig, err, _ := impl.k8sCommonService.GetRestConfigByClusterId(ctx, installAppVersion.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting rest config by cluster id", "clusterId", installAppVersion.ClusterId, "err", err)
+		return false, false, "waiting for cluster"
+	}
+	for _, condition := range conditions {
+		namespace := condition.Namespace
+		if len(namespace) == 0 {
+			namespace = installAppVersion.Namespace
+		}
+		gvk := schema.GroupVersionKind{Group: condition.Group, Version: condition.Version, Kind: condition.Kind}
+		resource, err := impl.K8sUtil.GetResource(ctx, namespace, condition.Name, gvk, restConfig)
+		if err != nil {
+			return false, false, fmt.Sprintf("waiting for %s %s", condition.Kind, condition.Name)
+		}
+		if met, message := helper.IsConditionMet(&resource.Manifest, condition); !met {
+			return false, false, message
+		}
+	}
+	return true, false, ""
+}
+
+// rollbackInstallation deletes the apps of the installation, the last deployed first. Entries never deployed are
+// force deleted as they have nothing on the cluster
+func (impl *ChartGroupInstallServiceImpl) rollbackInstallation(install *repository2.ChartGroupInstall, entries []*repository2.ChartGroupInstallEntry) error {
+	rollbackEntries := make([]*repository2.ChartGroupInstallEntry, len(entries))
+	copy(rollbackEntries, entries)
+	sort.SliceStable(rollbackEntries, func(i, j int) bool {
+		return rollbackEntries[i].DeployedOn.After(rollbackEntries[j].DeployedOn)
+	})
+	rollbackFailed := false
+	for _, entry := range rollbackEntries {
+		if entry.Status == chartGroupBean.EntryStatusRolledBack {
+			continue
+		}
+		currentStatus := entry.Status
+		entry.Status = chartGroupBean.EntryStatusRolledBack
+		entry.UpdatedOn = time.Now()
+		claimed, err := impl.chartGroupInstallRepository.UpdateEntryStatusIfCurrent(entry, currentStatus)
+		if err != nil || !claimed {
+			if err != nil {
+				impl.logger.Errorw("error in updating chart group install entry", "entryId", entry.Id, "err", err)
+				rollbackFailed = true
+			}
+			entry.Status = currentStatus
+			continue
+		}
+		err = impl.deleteInstalledApp(entry, install.UpdatedBy, currentStatus == chartGroupBean.EntryStatusWaiting)
+		if err != nil {
+			rollbackFailed = true
+			impl.updateEntryStatus(entry, currentStatus, fmt.Sprintf("rollback failed: %s", err.Error()))
+		}
+	}
+	if rollbackFailed {
+		// the installation stays in progress so that the rollback is retried on the next poll
+		install.UpdatedOn = time.Now()
+		return impl.chartGroupInstallRepository.UpdateInstall(install)
+	}
+	install.Status = chartGroupBean.InstallStatusRolledBack
+	install.FinishedOn = time.Now()
+	install.UpdatedOn = time.Now()
+	return impl.chartGroupInstallRepository.UpdateInstall(install)
+}
+
+func (impl *ChartGroupInstallServiceImpl) deleteInstalledApp(entry *repository2.ChartGroupInstallEntry, userId int32, forceDelete bool) error {
+	installAppVersion, err := impl.installAppService.GetInstalledAppVersion(entry.InstalledAppVersionId, userId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil
+		}
+		if apiErr, ok := err.(*util.ApiError); ok && apiErr.HttpStatusCode == http.StatusBadRequest {
+			// the installed app was deleted already
+			return nil
+		}
+		impl.logger.Errorw("error in getting installed app version", "installedAppVersionId", entry.InstalledAppVersionId, "err", err)
+		return err
+	}
+	deleteRequest := &appStoreBean.InstallAppVersionDTO{
+		InstalledAppId:  installAppVersion.InstalledAppId,
+		AppId:           installAppVersion.AppId,
+		AppName:         installAppVersion.AppName,
+		Namespace:       installAppVersion.Namespace,
+		ClusterId:       installAppVersion.ClusterId,
+		EnvironmentId:   installAppVersion.EnvironmentId,
+		AppOfferingMode: installAppVersion.AppOfferingMode,
+		UserId:          userId,
+		ForceDelete:     forceDelete,
+	}
+	_, err = impl.appStoreDeploymentService.DeleteInstalledApp(context.Background(), deleteRequest)
+	if err != nil {
+		impl.logger.Errorw("error in deleting installed app of chart group install entry", "installedAppId", entry.InstalledAppId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ChartGroupInstallServiceImpl) updateEntryStatus(entry *repository2.ChartGroupInstallEntry, status chartGroupBean.EntryStatus, message string) {
+	entry.Status = status
+	entry.Message = message
+	entry.UpdatedOn = time.Now()
+	err := impl.chartGroupInstallRepository.UpdateEntry(entry)
+	if err != nil {
+		impl.logger.Errorw("error in updating chart group install entry", "entryId", entry.Id, "status", status, "err", err)
+	}
+}
+
+// updateInstallStatus finishes the installation once every entry is ready, failed or skipped
+func (impl *ChartGroupInstallServiceImpl) updateInstallStatus(install *repository2.ChartGroupInstall, entries []*repository2.ChartGroupInstallEntry) {
+	readyCount, failedCount, skippedCount := 0, 0, 0
+	for _, entry := range entries {
+		switch entry.Status {
+		case chartGroupBean.EntryStatusReady:
+			readyCount++
+		case chartGroupBean.EntryStatusFailed:
+			failedCount++
+		case chartGroupBean.EntryStatusSkipped:
+			skippedCount++
+		default:
+			if !entry.Status.IsTerminal() {
+				return
+			}
+		}
+	}
+	if failedCount == 0 && skippedCount == 0 {
+		install.Status = chartGroupBean.InstallStatusSucceeded
+	} else {
+		install.Status = chartGroupBean.InstallStatusFailed
+	}
+	install.Message = fmt.Sprintf("%d/%d entries ready, %d failed, %d skipped", readyCount, len(entries), failedCount, skippedCount)
+	install.FinishedOn = time.Now()
+	install.UpdatedOn = time.Now()
+	err := impl.chartGroupInstallRepository.UpdateInstall(install)
+	if err != nil {
+		impl.logger.Errorw("error in updating chart group install", "installationId", install.Id, "err", err)
+	}
+}
+
+func (impl *ChartGroupInstallServiceImpl) GetInstallation(installationId int) (*chartGroupBean.ChartGroupInstallDto, error) {
+	install, err := impl.chartGroupInstallRepository.FindInstallById(installationId)
+	if err != nil {
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "chart group installation not found", err.Error())
+		}
+		impl.logger.Errorw("error in getting chart group install", "installationId", installationId, "err", err)
+		return nil, err
+	}
+	entries, err := impl.chartGroupInstallRepository.FindEntriesByInstallId(installationId)
+	if err != nil {
+		impl.logger.Errorw("error in getting chart group install entries", "installationId", installationId, "err", err)
+		return nil, err
+	}
+	installDto := getChartGroupInstallDto(install)
+	for _, entry := range entries {
+		installDto.Entries = append(installDto.Entries, getChartGroupInstallEntryDto(entry))
+	}
+	return installDto, nil
+}
+
+func (impl *ChartGroupInstallServiceImpl) GetInstallations(chartGroupId int) ([]*chartGroupBean.ChartGroupInstallDto, error) {
+	installs, err := impl.chartGroupInstallRepository.FindInstallsByChartGroupId(chartGroupId)
+	if err != nil {
+		impl.logger.Errorw("error in getting chart group installs", "chartGroupId", chartGroupId, "err", err)
+		return nil, err
+	}
+	installDtos := make([]*chartGroupBean.ChartGroupInstallDto, 0, len(installs))
+	for _, install := range installs {
+		installDtos = append(installDtos, getChartGroupInstallDto(install))
+	}
+	return installDtos, nil
+}
+
+func getChartGroupInstallDto(install *repository2.ChartGroupInstall) *chartGroupBean.ChartGroupInstallDto {
+	return &chartGroupBean.ChartGroupInstallDto{
+		Id:            install.Id,
+		ChartGroupId:  install.ChartGroupId,
+		FailurePolicy: install.FailurePolicy,
+		Status:        install.Status,
+		Message:       install.Message,
+		StartedOn:     install.StartedOn,
+		FinishedOn:    getTimeOrNil(install.FinishedOn),
+		StartedBy:     install.CreatedBy,
+	}
+}
+
+func getChartGroupInstallEntryDto(entry *repository2.ChartGroupInstallEntry) *chartGroupBean.ChartGroupInstallEntryDto {
+	return &chartGroupBean.ChartGroupInstallEntryDto{
+		Id:                entry.Id,
+		ChartGroupEntryId: entry.ChartGroupEntryId,
+		EntryKey:          entry.EntryKey,
+		DependsOn:         getDependsOn(entry.DependsOn),
+		Readiness:         getReadiness(entry.Readiness),
+		InstalledAppId:    entry.InstalledAppId,
+		AppName:           entry.AppName,
+		EnvironmentId:     entry.EnvironmentId,
+		Status:            entry.Status,
+		Message:           entry.Message,
+		DeployedOn:        getTimeOrNil(entry.DeployedOn),
+		ReadyOn:           getTimeOrNil(entry.ReadyOn),
+		ReadinessDeadline: getTimeOrNil(entry.ReadinessDeadline),
+	}
+}
+
+func getTimeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
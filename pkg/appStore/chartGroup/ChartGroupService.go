@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha1"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/devtron-labs/devtron/client/argocdServer"
@@ -42,12 +43,15 @@ import (
 	"github.com/devtron-labs/devtron/pkg/team/read"
 	repository3 "github.com/devtron-labs/devtron/pkg/team/repository"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
+	chartGroupBean "github.com/devtron-labs/devtron/pkg/appStore/chartGroup/bean"
+	"github.com/devtron-labs/devtron/pkg/appStore/chartGroup/helper"
 	repository2 "github.com/devtron-labs/devtron/pkg/appStore/chartGroup/repository"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/repository"
 	appStoreValuesRepository "github.com/devtron-labs/devtron/pkg/appStore/values/repository"
@@ -81,6 +85,7 @@ type ChartGroupServiceImpl struct {
 	installAppService                    FullMode.InstalledAppDBExtendedService
 	appStoreAppsEventPublishService      out.AppStoreAppsEventPublishService
 	teamReadService                      read.TeamReadService
+	chartGroupInstallService             ChartGroupInstallService
 }
 
 func NewChartGroupServiceImpl(logger *zap.SugaredLogger,
@@ -104,7 +109,8 @@ func NewChartGroupServiceImpl(logger *zap.SugaredLogger,
 	gitOperationService git.GitOperationService,
 	installAppService FullMode.InstalledAppDBExtendedService,
 	appStoreAppsEventPublishService out.AppStoreAppsEventPublishService,
-	teamReadService read.TeamReadService) (*ChartGroupServiceImpl, error) {
+	teamReadService read.TeamReadService,
+	chartGroupInstallService ChartGroupInstallService) (*ChartGroupServiceImpl, error) {
 	impl := &ChartGroupServiceImpl{
 		logger:                               logger,
 		chartGroupEntriesRepository:          chartGroupEntriesRepository,
@@ -128,6 +134,7 @@ func NewChartGroupServiceImpl(logger *zap.SugaredLogger,
 		appStoreAppsEventPublishService:      appStoreAppsEventPublishService,
 		appStoreRepository:                   appStoreRepository,
 		teamReadService:                      teamReadService,
+		chartGroupInstallService:             chartGroupInstallService,
 	}
 	return impl, nil
 }
@@ -169,6 +176,10 @@ type ChartGroupEntryBean struct {
 	AppStoreApplicationVersionId int            `json:"appStoreApplicationVersionId,omitempty"` //AppStoreApplicationVersionId
 	ChartMetaData                *ChartMetaData `json:"chartMetaData,omitempty"`
 	ReferenceType                string         `json:"referenceType, omitempty"`
+	// Key is referred to in the dependencies of other entries, the chart name is used when left out
+	Key       string                            `json:"key,omitempty" validate:"max=250"`
+	DependsOn []string                          `json:"dependsOn,omitempty"`
+	Readiness *chartGroupBean.ReadinessCriteria `json:"readiness,omitempty"`
 }
 
 type ChartMetaData struct {
@@ -253,6 +264,10 @@ func (impl *ChartGroupServiceImpl) SaveChartGroupEntries(req *ChartGroupBean) (*
 		impl.logger.Errorw("error in fetching chart group", "id", req.Id, "err", err)
 		return nil, err
 	}
+	err = impl.validateEntryDependencies(req.ChartGroupEntries)
+	if err != nil {
+		return nil, err
+	}
 	var newEntries []*ChartGroupEntryBean
 	oldEntriesMap := make(map[int]*ChartGroupEntryBean)
 	for _, entryBean := range req.ChartGroupEntries {
@@ -270,6 +285,10 @@ func (impl *ChartGroupServiceImpl) SaveChartGroupEntries(req *ChartGroupBean) (*
 			//update
 			existingEntry.AppStoreApplicationVersionId = entry.AppStoreApplicationVersionId
 			existingEntry.AppStoreValuesVersionId = entry.AppStoreValuesVersionId
+			existingEntry.EntryKey, existingEntry.DependsOn, existingEntry.Readiness, err = getEntryDependencyFields(entry)
+			if err != nil {
+				return nil, err
+			}
 		} else {
 			//delete
 			existingEntry.Deleted = true
@@ -281,7 +300,14 @@ func (impl *ChartGroupServiceImpl) SaveChartGroupEntries(req *ChartGroupBean) (*
 
 	var createEntries []*repository2.ChartGroupEntry
 	for _, entryBean := range newEntries {
+		entryKey, dependsOn, readiness, err := getEntryDependencyFields(entryBean)
+		if err != nil {
+			return nil, err
+		}
 		entry := &repository2.ChartGroupEntry{
+			EntryKey:                     entryKey,
+			DependsOn:                    dependsOn,
+			Readiness:                    readiness,
 			AppStoreValuesVersionId:      entryBean.AppStoreValuesVersionId,
 			AppStoreApplicationVersionId: entryBean.AppStoreApplicationVersionId,
 			ChartGroupId:                 group.Id,
@@ -304,6 +330,65 @@ func (impl *ChartGroupServiceImpl) SaveChartGroupEntries(req *ChartGroupBean) (*
 	return impl.GetChartGroupWithChartMetaData(req.Id)
 }
 
+// validateEntryDependencies checks that the entries form a DAG and that their readiness criteria are complete.
+// Entries without a key are keyed by their chart name, which has to be unique once entries depend on each other
+func (impl *ChartGroupServiceImpl) validateEntryDependencies(entries []*ChartGroupEntryBean) error {
+	hasDependencies := false
+	keyEntryCount := make(map[string]int, len(entries))
+	explicitKeys := make(map[string]bool, len(entries))
+	nodes := make([]*chartGroupBean.DependencyNode, 0, len(entries))
+	for _, entry := range entries {
+		key := entry.Key
+		if len(key) > 0 {
+			explicitKeys[key] = true
+		} else {
+			appStoreApplicationVersion, err := impl.appStoreApplicationVersionRepository.FindById(entry.AppStoreApplicationVersionId)
+			if err != nil {
+				impl.logger.Errorw("error in getting app store application version", "id", entry.AppStoreApplicationVersionId, "err", err)
+				return err
+			}
+			key = appStoreApplicationVersion.Name
+		}
+		keyEntryCount[key]++
+		hasDependencies = hasDependencies || len(entry.DependsOn) > 0
+		nodes = append(nodes, &chartGroupBean.DependencyNode{Key: key, DependsOn: entry.DependsOn})
+		if err := helper.ValidateReadiness(entry.Readiness); err != nil {
+			errMsg := fmt.Sprintf("invalid readiness of entry %s: %s", key, err.Error())
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+	}
+	for key, count := range keyEntryCount {
+		if count > 1 && (hasDependencies || explicitKeys[key]) {
+			errMsg := fmt.Sprintf("entry key %s is used by %d entries, set distinct keys for entries of the same chart", key, count)
+			return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+	}
+	if err := helper.ValidateDependencies(nodes); err != nil {
+		return util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	return nil
+}
+
+// getEntryDependencyFields returns the key, dependencies and readiness of the entry as stored in chart_group_entry
+func getEntryDependencyFields(entry *ChartGroupEntryBean) (string, string, string, error) {
+	var dependsOn, readiness string
+	if len(entry.DependsOn) > 0 {
+		dependsOnJson, err := json.Marshal(entry.DependsOn)
+		if err != nil {
+			return "", "", "", err
+		}
+		dependsOn = string(dependsOnJson)
+	}
+	if entry.Readiness != nil {
+		readinessJson, err := json.Marshal(entry.Readiness)
+		if err != nil {
+			return "", "", "", err
+		}
+		readiness = string(readinessJson)
+	}
+	return entry.Key, dependsOn, readiness, nil
+}
+
 func (impl *ChartGroupServiceImpl) GetChartGroupWithChartMetaData(chartGroupId int) (*ChartGroupBean, error) {
 	chartGroup, err := impl.chartGroupRepository.FindById(chartGroupId)
 	if err != nil {
@@ -371,6 +456,12 @@ func (impl *ChartGroupServiceImpl) charterEntryAdopter(chartGroupEntry *reposito
 			AppStoreApplicationVersion: chartGroupEntry.AppStoreApplicationVersion.Version,
 			IsChartRepoActive:          isChartRepoActive,
 		},
+		Key:       chartGroupEntry.EntryKey,
+		DependsOn: getDependsOn(chartGroupEntry.DependsOn),
+		Readiness: getReadiness(chartGroupEntry.Readiness),
+	}
+	if len(entry.Key) == 0 {
+		entry.Key = chartGroupEntry.AppStoreApplicationVersion.Name
 	}
 	return entry
 }
@@ -591,7 +682,13 @@ func (impl *ChartGroupServiceImpl) DeployBulk(chartGroupInstallRequest *ChartGro
 		}
 		installAppVersions = append(installAppVersions, installAppVersionDTO)
 	}
+	var install *repository2.ChartGroupInstall
 	if chartGroupInstallRequest.ChartGroupId > 0 {
+		install, err = impl.chartGroupInstallService.CreateInstallation(chartGroupInstallRequest, installAppVersions, tx)
+		if err != nil {
+			impl.logger.Errorw("DeployBulk, error in creating chart group installation", "err", err)
+			return nil, err
+		}
 		groupINstallationId, err := getInstallationId(installAppVersions)
 		if err != nil {
 			return nil, err
@@ -611,6 +708,14 @@ func (impl *ChartGroupServiceImpl) DeployBulk(chartGroupInstallRequest *ChartGro
 		impl.logger.Errorw("DeployBulk, error in tx commit", "err", err)
 		return nil, err
 	}
+	if install != nil {
+		// entries of a chart group are deployed in the order of their dependencies, the ones depending on nothing now
+		err = impl.chartGroupInstallService.ProcessInstallation(install.Id)
+		if err != nil {
+			impl.logger.Errorw("DeployBulk, error in processing chart group installation", "installationId", install.Id, "err", err)
+		}
+		return &ChartGroupInstallAppRes{InstallationId: install.Id}, nil
+	}
 	//nats event
 	impl.TriggerDeploymentEventAndHandleStatusUpdate(installAppVersions)
 	// TODO refactoring: why empty obj ??
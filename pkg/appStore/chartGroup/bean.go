@@ -16,12 +16,16 @@
 
 package chartGroup
 
+import chartGroupBean "github.com/devtron-labs/devtron/pkg/appStore/chartGroup/bean"
+
 // / bean for v2
 type ChartGroupInstallRequest struct {
 	ProjectId                     int                              `json:"projectId"  validate:"required,number"`
 	ChartGroupInstallChartRequest []*ChartGroupInstallChartRequest `json:"charts" validate:"dive,required"`
 	ChartGroupId                  int                              `json:"chartGroupId"` //optional
-	UserId                        int32                            `json:"-"`
+	// FailurePolicy decides what happens to the rest of a chart group installation when an entry fails, continue by default
+	FailurePolicy chartGroupBean.FailurePolicy `json:"failurePolicy,omitempty" validate:"omitempty,oneof=stop continue rollback"`
+	UserId        int32                        `json:"-"`
 }
 
 type ChartGroupInstallChartRequest struct {
@@ -42,6 +46,8 @@ type ChartGroupInstallMetadata struct {
 type ChartGroupInstallAppRes struct {
 	ChartGroupInstallMetadata []ChartGroupInstallMetadata `json:"chartGroupInstallMetadata"`
 	Summary                   string                      `json:"summary"`
+	// InstallationId tracks the progress of a chart group installation, set only when a chart group is installed
+	InstallationId int `json:"installationId,omitempty"`
 }
 type TriggerStatus string
 type Reason string
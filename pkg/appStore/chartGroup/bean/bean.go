@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+// FailurePolicy decides what happens to the rest of a chart group installation when an entry fails
+type FailurePolicy string
+
+const (
+	// FailurePolicyStop skips every entry not deployed yet, deployed entries are left as they are
+	FailurePolicyStop FailurePolicy = "stop"
+	// FailurePolicyContinue skips the entries depending on the failed one and deploys the others
+	FailurePolicyContinue FailurePolicy = "continue"
+	// FailurePolicyRollback deletes the apps installed by the installation, the last deployed first
+	FailurePolicyRollback FailurePolicy = "rollback"
+)
+
+type InstallStatus string
+
+const (
+	InstallStatusInProgress InstallStatus = "InProgress"
+	InstallStatusSucceeded  InstallStatus = "Succeeded"
+	InstallStatusFailed     InstallStatus = "Failed"
+	InstallStatusRolledBack InstallStatus = "RolledBack"
+)
+
+type EntryStatus string
+
+const (
+	// EntryStatusWaiting entries wait for the entries they depend on to be ready
+	EntryStatusWaiting EntryStatus = "Waiting"
+	// EntryStatusDeploying entries are deployed and wait for their readiness criteria
+	EntryStatusDeploying  EntryStatus = "Deploying"
+	EntryStatusReady      EntryStatus = "Ready"
+	EntryStatusFailed     EntryStatus = "Failed"
+	EntryStatusSkipped    EntryStatus = "Skipped"
+	EntryStatusRolledBack EntryStatus = "RolledBack"
+)
+
+func (status EntryStatus) IsTerminal() bool {
+	return status == EntryStatusReady || status == EntryStatusFailed || status == EntryStatusSkipped || status == EntryStatusRolledBack
+}
+
+type ReadinessType string
+
+const (
+	// ReadinessTypeDeployed entries are ready once the deployment is triggered without errors
+	ReadinessTypeDeployed ReadinessType = "deployed"
+	// ReadinessTypeHealthy entries are ready once the app is healthy
+	ReadinessTypeHealthy ReadinessType = "healthy"
+	// ReadinessTypeConditions entries are ready once every resource condition is met
+	ReadinessTypeConditions ReadinessType = "conditions"
+)
+
+const (
+	DefaultReadinessTimeoutSeconds = 600
+	// HealthyStatus is the health of argo cd applications and helm releases whose resources are ready
+	HealthyStatus = "Healthy"
+	// HelmReleaseStatusFailed is the status of a helm release whose install or upgrade failed
+	HelmReleaseStatusFailed = "failed"
+)
+
+// ResourceCondition is met when the resource has a status condition of the type with the status,
+// the namespace of the app is used when it is left out
+type ResourceCondition struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version" validate:"required"`
+	Kind      string `json:"kind" validate:"required"`
+	Name      string `json:"name" validate:"required"`
+	Namespace string `json:"namespace,omitempty"`
+	Type      string `json:"type" validate:"required"`
+	// Status is True when left out
+	Status string `json:"status,omitempty"`
+}
+
+type ReadinessCriteria struct {
+	Type           ReadinessType        `json:"type" validate:"oneof=deployed healthy conditions"`
+	Conditions     []*ResourceCondition `json:"conditions,omitempty" validate:"dive"`
+	TimeoutSeconds int                  `json:"timeoutSeconds,omitempty" validate:"min=0"`
+}
+
+// GetDefaultReadinessCriteria is used for entries which do not declare their readiness, they wait for the app to be healthy
+func GetDefaultReadinessCriteria() *ReadinessCriteria {
+	return &ReadinessCriteria{Type: ReadinessTypeHealthy, TimeoutSeconds: DefaultReadinessTimeoutSeconds}
+}
+
+// DependencyNode is an entry of a chart group as seen by the dependency graph, several installed charts of
+// one entry share its key. Status is only set for the entries of an installation
+type DependencyNode struct {
+	Key       string
+	DependsOn []string
+	Status    EntryStatus
+}
+
+type ChartGroupInstallEntryDto struct {
+	Id                int                `json:"id"`
+	ChartGroupEntryId int                `json:"chartGroupEntryId,omitempty"`
+	EntryKey          string             `json:"entryKey"`
+	DependsOn         []string           `json:"dependsOn"`
+	Readiness         *ReadinessCriteria `json:"readiness"`
+	InstalledAppId    int                `json:"installedAppId"`
+	AppName           string             `json:"appName"`
+	EnvironmentId     int                `json:"environmentId"`
+	Status            EntryStatus        `json:"status"`
+	Message           string             `json:"message,omitempty"`
+	DeployedOn        *time.Time         `json:"deployedOn,omitempty"`
+	ReadyOn           *time.Time         `json:"readyOn,omitempty"`
+	ReadinessDeadline *time.Time         `json:"readinessDeadline,omitempty"`
+}
+
+type ChartGroupInstallDto struct {
+	Id            int                          `json:"id"`
+	ChartGroupId  int                          `json:"chartGroupId"`
+	FailurePolicy FailurePolicy                `json:"failurePolicy"`
+	Status        InstallStatus                `json:"status"`
+	Message       string                       `json:"message,omitempty"`
+	StartedOn     time.Time                    `json:"startedOn"`
+	FinishedOn    *time.Time                   `json:"finishedOn,omitempty"`
+	StartedBy     int32                        `json:"startedBy"`
+	Entries       []*ChartGroupInstallEntryDto `json:"entries,omitempty"`
+}
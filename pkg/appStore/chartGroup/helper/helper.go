@@ -0,0 +1,204 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/appStore/chartGroup/bean"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sort"
+	"strings"
+)
+
+// ValidateDependencies checks that the entries only depend on keys of other entries and that the dependencies
+// do not form a cycle
+func ValidateDependencies(nodes []*bean.DependencyNode) error {
+	dependsOn := make(map[string]map[string]bool, len(nodes))
+	for _, node := range nodes {
+		if _, ok := dependsOn[node.Key]; !ok {
+			dependsOn[node.Key] = make(map[string]bool)
+		}
+		for _, dependency := range node.DependsOn {
+			dependsOn[node.Key][dependency] = true
+		}
+	}
+	for key, dependencies := range dependsOn {
+		for dependency := range dependencies {
+			if dependency == key {
+				return fmt.Errorf("entry %q depends on itself", key)
+			}
+			if _, ok := dependsOn[dependency]; !ok {
+				return fmt.Errorf("entry %q depends on %q which is not in the chart group", key, dependency)
+			}
+		}
+	}
+	// kahn's algorithm, the keys left once no key is free of dependencies are on a cycle
+	pending := make(map[string]int, len(dependsOn))
+	dependents := make(map[string][]string, len(dependsOn))
+	var free []string
+	for key, dependencies := range dependsOn {
+		pending[key] = len(dependencies)
+		if len(dependencies) == 0 {
+			free = append(free, key)
+		}
+		for dependency := range dependencies {
+			dependents[dependency] = append(dependents[dependency], key)
+		}
+	}
+	for len(free) > 0 {
+		key := free[0]
+		free = free[1:]
+		delete(pending, key)
+		for _, dependent := range dependents[key] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				free = append(free, dependent)
+			}
+		}
+	}
+	if len(pending) > 0 {
+		cycle := make([]string, 0, len(pending))
+		for key := range pending {
+			cycle = append(cycle, key)
+		}
+		sort.Strings(cycle)
+		return fmt.Errorf("dependencies of entries %s form a cycle", strings.Join(cycle, ", "))
+	}
+	return nil
+}
+
+// ValidateReadiness checks the readiness criteria and sets the default timeout
+func ValidateReadiness(readiness *bean.ReadinessCriteria) error {
+	if readiness == nil {
+		return nil
+	}
+	switch readiness.Type {
+	case bean.ReadinessTypeDeployed, bean.ReadinessTypeHealthy, bean.ReadinessTypeConditions:
+	default:
+		return fmt.Errorf("readiness type %q is not one of %s, %s, %s", readiness.Type, bean.ReadinessTypeDeployed, bean.ReadinessTypeHealthy, bean.ReadinessTypeConditions)
+	}
+	for _, condition := range readiness.Conditions {
+		if len(condition.Version) == 0 || len(condition.Kind) == 0 || len(condition.Name) == 0 || len(condition.Type) == 0 {
+			return fmt.Errorf("conditions need the version, kind and name of the resource and the condition type")
+		}
+	}
+	if readiness.TimeoutSeconds < 0 {
+		return fmt.Errorf("readiness timeout can not be negative")
+	}
+	if readiness.Type == bean.ReadinessTypeConditions && len(readiness.Conditions) == 0 {
+		return fmt.Errorf("readiness of type %s needs at least one condition", bean.ReadinessTypeConditions)
+	}
+	if readiness.Type != bean.ReadinessTypeConditions && len(readiness.Conditions) > 0 {
+		return fmt.Errorf("conditions are only checked for readiness of type %s", bean.ReadinessTypeConditions)
+	}
+	if readiness.TimeoutSeconds == 0 {
+		readiness.TimeoutSeconds = bean.DefaultReadinessTimeoutSeconds
+	}
+	return nil
+}
+
+// GetDeployable returns the indexes of the waiting nodes whose dependencies are all ready. A dependency is ready
+// when every node with its key is, dependencies on keys not in the installation are ignored
+func GetDeployable(nodes []*bean.DependencyNode) []int {
+	ready := getKeysWithAll(nodes, func(status bean.EntryStatus) bool { return status == bean.EntryStatusReady })
+	var deployable []int
+	for i, node := range nodes {
+		if node.Status != bean.EntryStatusWaiting {
+			continue
+		}
+		canDeploy := true
+		for _, dependency := range node.DependsOn {
+			if isReady, ok := ready[dependency]; ok && !isReady {
+				canDeploy = false
+				break
+			}
+		}
+		if canDeploy {
+			deployable = append(deployable, i)
+		}
+	}
+	return deployable
+}
+
+// GetBlocked returns the indexes of the waiting nodes which can never be deployed, the ones depending directly or
+// through other entries on a failed, skipped or rolled back node
+func GetBlocked(nodes []*bean.DependencyNode) []int {
+	blockedKeys := make(map[string]bool)
+	for _, node := range nodes {
+		if node.Status == bean.EntryStatusFailed || node.Status == bean.EntryStatusSkipped || node.Status == bean.EntryStatusRolledBack {
+			blockedKeys[node.Key] = true
+		}
+	}
+	blocked := make(map[int]bool)
+	for changed := true; changed; {
+		changed = false
+		for i, node := range nodes {
+			if node.Status != bean.EntryStatusWaiting || blocked[i] {
+				continue
+			}
+			for _, dependency := range node.DependsOn {
+				if blockedKeys[dependency] {
+					blocked[i] = true
+					blockedKeys[node.Key] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	indexes := make([]int, 0, len(blocked))
+	for i := range blocked {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// getKeysWithAll tells for every key whether all the nodes with it satisfy the check
+func getKeysWithAll(nodes []*bean.DependencyNode, check func(status bean.EntryStatus) bool) map[string]bool {
+	keys := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		satisfied, ok := keys[node.Key]
+		keys[node.Key] = (!ok || satisfied) && check(node.Status)
+	}
+	return keys
+}
+
+// IsConditionMet tells whether the resource has the status condition, the message describes the condition
+// found when it is not met
+func IsConditionMet(resource *unstructured.Unstructured, condition *bean.ResourceCondition) (bool, string) {
+	expectedStatus := condition.Status
+	if len(expectedStatus) == 0 {
+		expectedStatus = "True"
+	}
+	conditions, found, err := unstructured.NestedSlice(resource.Object, "status", "conditions")
+	if err != nil || !found {
+		return false, fmt.Sprintf("%s %s has no status conditions", condition.Kind, condition.Name)
+	}
+	for _, item := range conditions {
+		resourceCondition, ok := item.(map[string]interface{})
+		if !ok || resourceCondition["type"] != condition.Type {
+			continue
+		}
+		status, _ := resourceCondition["status"].(string)
+		if strings.EqualFold(status, expectedStatus) {
+			return true, ""
+		}
+		return false, fmt.Sprintf("condition %s of %s %s is %s", condition.Type, condition.Kind, condition.Name, status)
+	}
+	return false, fmt.Sprintf("%s %s has no condition %s", condition.Kind, condition.Name, condition.Type)
+}
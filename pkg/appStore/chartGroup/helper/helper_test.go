@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package helper
+
+import (
+	"github.com/devtron-labs/devtron/pkg/appStore/chartGroup/bean"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"testing"
+)
+
+func TestValidateDependencies(t *testing.T) {
+	t.Run("valid dag", func(t *testing.T) {
+		err := ValidateDependencies([]*bean.DependencyNode{
+			{Key: "crds"},
+			{Key: "operator", DependsOn: []string{"crds"}},
+			{Key: "instance", DependsOn: []string{"crds", "operator"}},
+		})
+		assert.Nil(t, err)
+	})
+	t.Run("unknown dependency", func(t *testing.T) {
+		err := ValidateDependencies([]*bean.DependencyNode{{Key: "operator", DependsOn: []string{"crds"}}})
+		assert.NotNil(t, err)
+	})
+	t.Run("self dependency", func(t *testing.T) {
+		err := ValidateDependencies([]*bean.DependencyNode{{Key: "operator", DependsOn: []string{"operator"}}})
+		assert.NotNil(t, err)
+	})
+	t.Run("cycle", func(t *testing.T) {
+		err := ValidateDependencies([]*bean.DependencyNode{
+			{Key: "a", DependsOn: []string{"c"}},
+			{Key: "b", DependsOn: []string{"a"}},
+			{Key: "c", DependsOn: []string{"b"}},
+			{Key: "d"},
+		})
+		assert.EqualError(t, err, "dependencies of entries a, b, c form a cycle")
+	})
+}
+
+func TestValidateReadiness(t *testing.T) {
+	readiness := &bean.ReadinessCriteria{Type: bean.ReadinessTypeHealthy}
+	assert.Nil(t, ValidateReadiness(readiness))
+	assert.Equal(t, bean.DefaultReadinessTimeoutSeconds, readiness.TimeoutSeconds)
+	assert.NotNil(t, ValidateReadiness(&bean.ReadinessCriteria{Type: bean.ReadinessTypeConditions}))
+	assert.NotNil(t, ValidateReadiness(&bean.ReadinessCriteria{Type: "ready"}))
+	assert.NotNil(t, ValidateReadiness(&bean.ReadinessCriteria{Type: bean.ReadinessTypeConditions,
+		Conditions: []*bean.ResourceCondition{{Kind: "Deployment", Name: "operator"}}}))
+	assert.NotNil(t, ValidateReadiness(&bean.ReadinessCriteria{Type: bean.ReadinessTypeDeployed,
+		Conditions: []*bean.ResourceCondition{{Kind: "Deployment", Name: "operator", Type: "Available"}}}))
+}
+
+func TestGetDeployable(t *testing.T) {
+	nodes := []*bean.DependencyNode{
+		{Key: "crds", Status: bean.EntryStatusReady},
+		{Key: "operator", DependsOn: []string{"crds"}, Status: bean.EntryStatusWaiting},
+		{Key: "operator", DependsOn: []string{"crds"}, Status: bean.EntryStatusDeploying},
+		{Key: "instance", DependsOn: []string{"operator"}, Status: bean.EntryStatusWaiting},
+		{Key: "dashboard", DependsOn: []string{"removed"}, Status: bean.EntryStatusWaiting},
+	}
+	assert.Equal(t, []int{1, 4}, GetDeployable(nodes))
+	nodes[1].Status = bean.EntryStatusReady
+	nodes[2].Status = bean.EntryStatusReady
+	assert.Equal(t, []int{3, 4}, GetDeployable(nodes))
+}
+
+func TestGetBlocked(t *testing.T) {
+	nodes := []*bean.DependencyNode{
+		{Key: "crds", Status: bean.EntryStatusFailed},
+		{Key: "instance", DependsOn: []string{"operator"}, Status: bean.EntryStatusWaiting},
+		{Key: "operator", DependsOn: []string{"crds"}, Status: bean.EntryStatusWaiting},
+		{Key: "dashboard", Status: bean.EntryStatusWaiting},
+	}
+	assert.Equal(t, []int{1, 2}, GetBlocked(nodes))
+}
+
+func TestIsConditionMet(t *testing.T) {
+	resource := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "True"},
+				map[string]interface{}{"type": "Progressing", "status": "False"},
+			},
+		},
+	}}
+	met, _ := IsConditionMet(resource, &bean.ResourceCondition{Kind: "Deployment", Name: "operator", Type: "Available"})
+	assert.True(t, met)
+	met, msg := IsConditionMet(resource, &bean.ResourceCondition{Kind: "Deployment", Name: "operator", Type: "Progressing"})
+	assert.False(t, met)
+	assert.Equal(t, "condition Progressing of Deployment operator is False", msg)
+	met, _ = IsConditionMet(resource, &bean.ResourceCondition{Kind: "Deployment", Name: "operator", Type: "Progressing", Status: "False"})
+	assert.True(t, met)
+	met, _ = IsConditionMet(&unstructured.Unstructured{Object: map[string]interface{}{}}, &bean.ResourceCondition{Type: "Ready"})
+	assert.False(t, met)
+}
@@ -31,6 +31,9 @@ type ChartGroupEntry struct {
 	AppStoreValuesVersionId      int      `sql:"app_store_values_version_id"`      //AppStoreVersionValuesId
 	AppStoreApplicationVersionId int      `sql:"app_store_application_version_id"` //AppStoreApplicationVersionId
 	ChartGroupId                 int      `sql:"chart_group_id"`
+	EntryKey                     string   `sql:"entry_key"`
+	DependsOn                    string   `sql:"depends_on"` //json list of entry keys
+	Readiness                    string   `sql:"readiness"`  //json of bean.ReadinessCriteria
 	Deleted                      bool     `sql:"deleted,notnull"`
 	sql.AuditLog
 	AppStoreApplicationVersion *appStoreDiscoverRepository.AppStoreApplicationVersion
@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/appStore/chartGroup/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ChartGroupInstall struct {
+	TableName     struct{}           `sql:"chart_group_install" pg:",discard_unknown_columns"`
+	Id            int                `sql:"id,pk"`
+	ChartGroupId  int                `sql:"chart_group_id"`
+	ProjectId     int                `sql:"project_id"`
+	FailurePolicy bean.FailurePolicy `sql:"failure_policy"`
+	Status        bean.InstallStatus `sql:"status"`
+	Message       string             `sql:"message"`
+	StartedOn     time.Time          `sql:"started_on"`
+	FinishedOn    time.Time          `sql:"finished_on"`
+	sql.AuditLog
+}
+
+type ChartGroupInstallEntry struct {
+	TableName                    struct{}         `sql:"chart_group_install_entry" pg:",discard_unknown_columns"`
+	Id                           int              `sql:"id,pk"`
+	ChartGroupInstallId          int              `sql:"chart_group_install_id"`
+	ChartGroupEntryId            int              `sql:"chart_group_entry_id"`
+	EntryKey                     string           `sql:"entry_key"`
+	DependsOn                    string           `sql:"depends_on"` //json list of entry keys
+	Readiness                    string           `sql:"readiness"`  //json of bean.ReadinessCriteria
+	InstalledAppId               int              `sql:"installed_app_id"`
+	InstalledAppVersionId        int              `sql:"installed_app_version_id"`
+	InstalledAppVersionHistoryId int              `sql:"installed_app_version_history_id"`
+	AppName                      string           `sql:"app_name"`
+	EnvironmentId                int              `sql:"environment_id"`
+	Status                       bean.EntryStatus `sql:"status"`
+	Message                      string           `sql:"message"`
+	DeployedOn                   time.Time        `sql:"deployed_on"`
+	ReadyOn                      time.Time        `sql:"ready_on"`
+	ReadinessDeadline            time.Time        `sql:"readiness_deadline"`
+	sql.AuditLog
+}
+
+type ChartGroupInstallRepository interface {
+	SaveInstall(install *ChartGroupInstall, tx *pg.Tx) error
+	SaveEntries(entries []*ChartGroupInstallEntry, tx *pg.Tx) error
+	UpdateInstall(install *ChartGroupInstall) error
+	UpdateEntry(entry *ChartGroupInstallEntry) error
+	// UpdateEntryStatusIfCurrent updates the entry only if its status is still the given one, it tells whether the
+	// entry was updated so that only one of the orchestrator replicas acts on it
+	UpdateEntryStatusIfCurrent(entry *ChartGroupInstallEntry, currentStatus bean.EntryStatus) (bool, error)
+	FindInstallById(id int) (*ChartGroupInstall, error)
+	FindInstallsByChartGroupId(chartGroupId int) ([]*ChartGroupInstall, error)
+	FindInProgressInstalls() ([]*ChartGroupInstall, error)
+	FindEntriesByInstallId(installId int) ([]*ChartGroupInstallEntry, error)
+}
+
+type ChartGroupInstallRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewChartGroupInstallRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ChartGroupInstallRepositoryImpl {
+	return &ChartGroupInstallRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ChartGroupInstallRepositoryImpl) SaveInstall(install *ChartGroupInstall, tx *pg.Tx) error {
+	return tx.Insert(install)
+}
+
+func (impl *ChartGroupInstallRepositoryImpl) SaveEntries(entries []*ChartGroupInstallEntry, tx *pg.Tx) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return tx.Insert(&entries)
+}
+
+func (impl *ChartGroupInstallRepositoryImpl) UpdateInstall(install *ChartGroupInstall) error {
+	return impl.dbConnection.Update(install)
+}
+
+func (impl *ChartGroupInstallRepositoryImpl) UpdateEntry(entry *ChartGroupInstallEntry) error {
+	return impl.dbConnection.Update(entry)
+}
+
+func (impl *ChartGroupInstallRepositoryImpl) UpdateEntryStatusIfCurrent(entry *ChartGroupInstallEntry, currentStatus bean.EntryStatus) (bool, error) {
+	res, err := impl.dbConnection.Model(entry).
+		WherePK().
+		Where("status = ?", currentStatus).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (impl *ChartGroupInstallRepositoryImpl) FindInstallById(id int) (*ChartGroupInstall, error) {
+	install := &ChartGroupInstall{}
+	err := impl.dbConnection.Model(install).
+		Where("id = ?", id).
+		Select()
+	return install, err
+}
+
+func (impl *ChartGroupInstallRepositoryImpl) FindInstallsByChartGroupId(chartGroupId int) ([]*ChartGroupInstall, error) {
+	var installs []*ChartGroupInstall
+	err := impl.dbConnection.Model(&installs).
+		Where("chart_group_id = ?", chartGroupId).
+		Order("id DESC").
+		Select()
+	return installs, err
+}
+
+func (impl *ChartGroupInstallRepositoryImpl) FindInProgressInstalls() ([]*ChartGroupInstall, error) {
+	var installs []*ChartGroupInstall
+	err := impl.dbConnection.Model(&installs).
+		Where("status = ?", bean.InstallStatusInProgress).
+		Order("id ASC").
+		Select()
+	return installs, err
+}
+
+func (impl *ChartGroupInstallRepositoryImpl) FindEntriesByInstallId(installId int) ([]*ChartGroupInstallEntry, error) {
+	var entries []*ChartGroupInstallEntry
+	err := impl.dbConnection.Model(&entries).
+		Where("chart_group_install_id = ?", installId).
+		Order("id ASC").
+		Select()
+	return entries, err
+}
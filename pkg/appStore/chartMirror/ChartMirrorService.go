@@ -0,0 +1,761 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chartMirror
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appStore/chartMirror/bean"
+	"github.com/devtron-labs/devtron/pkg/appStore/chartMirror/helper"
+	"github.com/devtron-labs/devtron/pkg/appStore/chartMirror/repository"
+	appStoreDiscoverRepository "github.com/devtron-labs/devtron/pkg/appStore/discover/repository"
+	appStoreDeploymentCommon "github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/common"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cronUtil "github.com/devtron-labs/devtron/util/cron"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"k8s.io/helm/pkg/repo"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"sigs.k8s.io/yaml"
+)
+
+type ChartMirrorConfig struct {
+	SyncIntervalMins int `env:"CHART_MIRROR_SYNC_INTERVAL_MINS" envDefault:"0" description:"Interval at which active chart mirrors are synced with their source, mirrors are only synced manually when it is 0"`
+}
+
+func GetChartMirrorConfig() (*ChartMirrorConfig, error) {
+	cfg := &ChartMirrorConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+type ChartMirrorService interface {
+	CreateMirror(request *bean.ChartMirrorDto) (*bean.ChartMirrorDto, error)
+	UpdateMirror(request *bean.ChartMirrorDto) (*bean.ChartMirrorDto, error)
+	GetMirror(id int) (*bean.ChartMirrorDto, error)
+	GetMirrors() ([]*bean.ChartMirrorDto, error)
+	// SyncMirror pulls the versions of the chart matching the version constraint of the mirror from the source of the
+	// chart and pushes them to the target registry, versions already mirrored are skipped
+	SyncMirror(ctx context.Context, id int, userId int32) (*bean.ChartMirrorSyncResponse, error)
+	// SyncActiveMirrors is run by the sync cron for every active mirror
+	SyncActiveMirrors()
+	// ExportBundle writes the mirrored versions of the mirrors to a bundle with their chart archives, all active
+	// mirrors are exported when no mirror is given
+	ExportBundle(ctx context.Context, mirrorIds []int, writer io.Writer) error
+	// ImportBundle pushes the charts of a bundle to the target registry and records them in the manifest of mirrored
+	// charts, the charts are linked to the chart versions of the chart store with the same name and version
+	ImportBundle(ctx context.Context, reader io.Reader, request *bean.BundleImportRequest) (*bean.BundleImportResponse, error)
+}
+
+type ChartMirrorServiceImpl struct {
+	logger                               *zap.SugaredLogger
+	chartMirrorRepository                repository.ChartMirrorRepository
+	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository
+	dockerArtifactStoreRepository        dockerRegistryRepository.DockerArtifactStoreRepository
+}
+
+func NewChartMirrorServiceImpl(logger *zap.SugaredLogger,
+	chartMirrorRepository repository.ChartMirrorRepository,
+	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository,
+	config *ChartMirrorConfig,
+	cronLogger *cronUtil.CronLoggerImpl) (*ChartMirrorServiceImpl, error) {
+	impl := &ChartMirrorServiceImpl{
+		logger:                               logger,
+		chartMirrorRepository:                chartMirrorRepository,
+		appStoreApplicationVersionRepository: appStoreApplicationVersionRepository,
+		dockerArtifactStoreRepository:        dockerArtifactStoreRepository,
+	}
+	if config.SyncIntervalMins > 0 {
+		syncCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+		_, err := syncCron.AddFunc(fmt.Sprintf("@every %dm", config.SyncIntervalMins), impl.SyncActiveMirrors)
+		if err != nil {
+			logger.Errorw("error in adding chart mirror sync cron", "err", err)
+			return nil, err
+		}
+		syncCron.Start()
+	}
+	return impl, nil
+}
+
+func (impl *ChartMirrorServiceImpl) CreateMirror(request *bean.ChartMirrorDto) (*bean.ChartMirrorDto, error) {
+	if err := helper.ValidateVersionConstraint(request.VersionConstraint); err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	if _, err := impl.getTargetRegistry(request.TargetRegistryId); err != nil {
+		return nil, err
+	}
+	versions, err := impl.appStoreApplicationVersionRepository.FindVersionsByAppStoreId(request.AppStoreId)
+	if err != nil {
+		impl.logger.Errorw("error in getting versions of chart", "appStoreId", request.AppStoreId, "err", err)
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, util.NewApiError(http.StatusNotFound, "chart not found", fmt.Sprintf("no versions found for app store %d", request.AppStoreId))
+	}
+	appStoreAppVersion, err := impl.appStoreApplicationVersionRepository.FindById(versions[0].Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting chart version", "appStoreApplicationVersionId", versions[0].Id, "err", err)
+		return nil, err
+	}
+	chartName := appStoreDeploymentCommon.GetChartNameFromAppStoreApplicationVersion(appStoreAppVersion)
+	_, err = impl.chartMirrorRepository.FindMirrorByChartNameAndTarget(chartName, request.TargetRegistryId, request.TargetRepository)
+	if err == nil {
+		return nil, util.NewApiError(http.StatusConflict, "chart is already mirrored to the repository", fmt.Sprintf("mirror of %s to %s/%s exists", chartName, request.TargetRegistryId, request.TargetRepository))
+	} else if !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting chart mirror", "chartName", chartName, "err", err)
+		return nil, err
+	}
+	mirror := &repository.ChartMirror{
+		AppStoreId:        request.AppStoreId,
+		ChartName:         chartName,
+		VersionConstraint: strings.TrimSpace(request.VersionConstraint),
+		TargetRegistryId:  request.TargetRegistryId,
+		TargetRepository:  strings.Trim(request.TargetRepository, "/"),
+		Active:            true,
+		AuditLog:          sql.NewDefaultAuditLog(request.UserId),
+	}
+	if err = impl.chartMirrorRepository.SaveMirror(mirror); err != nil {
+		impl.logger.Errorw("error in saving chart mirror", "mirror", mirror, "err", err)
+		return nil, err
+	}
+	return adaptMirror(mirror, nil), nil
+}
+
+func (impl *ChartMirrorServiceImpl) UpdateMirror(request *bean.ChartMirrorDto) (*bean.ChartMirrorDto, error) {
+	mirror, err := impl.getMirror(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err = helper.ValidateVersionConstraint(request.VersionConstraint); err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	if _, err = impl.getTargetRegistry(request.TargetRegistryId); err != nil {
+		return nil, err
+	}
+	mirror.VersionConstraint = strings.TrimSpace(request.VersionConstraint)
+	mirror.TargetRegistryId = request.TargetRegistryId
+	mirror.TargetRepository = strings.Trim(request.TargetRepository, "/")
+	mirror.Active = request.Active
+	mirror.UpdatedOn = time.Now()
+	mirror.UpdatedBy = request.UserId
+	if err = impl.chartMirrorRepository.UpdateMirror(mirror); err != nil {
+		impl.logger.Errorw("error in updating chart mirror", "mirrorId", mirror.Id, "err", err)
+		return nil, err
+	}
+	return impl.GetMirror(mirror.Id)
+}
+
+func (impl *ChartMirrorServiceImpl) GetMirror(id int) (*bean.ChartMirrorDto, error) {
+	mirror, err := impl.getMirror(id)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := impl.chartMirrorRepository.FindVersionsByMirrorId(mirror.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting mirrored versions", "mirrorId", mirror.Id, "err", err)
+		return nil, err
+	}
+	return adaptMirror(mirror, versions), nil
+}
+
+func (impl *ChartMirrorServiceImpl) GetMirrors() ([]*bean.ChartMirrorDto, error) {
+	mirrors, err := impl.chartMirrorRepository.FindAllMirrors()
+	if err != nil {
+		impl.logger.Errorw("error in getting chart mirrors", "err", err)
+		return nil, err
+	}
+	mirrorDtos := make([]*bean.ChartMirrorDto, 0, len(mirrors))
+	for _, mirror := range mirrors {
+		mirrorDtos = append(mirrorDtos, adaptMirror(mirror, nil))
+	}
+	return mirrorDtos, nil
+}
+
+func (impl *ChartMirrorServiceImpl) SyncActiveMirrors() {
+	mirrors, err := impl.chartMirrorRepository.FindActiveMirrors()
+	if err != nil {
+		impl.logger.Errorw("error in getting active chart mirrors", "err", err)
+		return
+	}
+	for _, mirror := range mirrors {
+		if mirror.AppStoreId == 0 {
+			// mirrors created by an import have no source to sync with
+			continue
+		}
+		_, err = impl.SyncMirror(context.Background(), mirror.Id, userBean.SystemUserId)
+		if err != nil {
+			impl.logger.Errorw("error in syncing chart mirror", "mirrorId", mirror.Id, "err", err)
+		}
+	}
+}
+
+func (impl *ChartMirrorServiceImpl) SyncMirror(ctx context.Context, id int, userId int32) (*bean.ChartMirrorSyncResponse, error) {
+	mirror, err := impl.getMirror(id)
+	if err != nil {
+		return nil, err
+	}
+	if mirror.AppStoreId == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "mirror has no chart store source to sync with", fmt.Sprintf("mirror %d was created by a bundle import", mirror.Id))
+	}
+	registry, err := impl.getTargetRegistry(mirror.TargetRegistryId)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := impl.appStoreApplicationVersionRepository.FindVersionsByAppStoreId(mirror.AppStoreId)
+	if err != nil {
+		impl.logger.Errorw("error in getting versions of chart", "appStoreId", mirror.AppStoreId, "err", err)
+		return nil, err
+	}
+	versionIds := make(map[string]int, len(versions))
+	versionNames := make([]string, 0, len(versions))
+	for _, version := range versions {
+		versionIds[version.Version] = version.Id
+		versionNames = append(versionNames, version.Version)
+	}
+	matchingVersions, err := helper.FilterVersions(versionNames, mirror.VersionConstraint)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	response := &bean.ChartMirrorSyncResponse{ChartMirrorId: mirror.Id, Versions: make([]*bean.MirroredVersionDto, 0, len(matchingVersions))}
+	mirrorRepository := helper.GetMirrorRepository(mirror.TargetRepository, mirror.ChartName)
+	indexFiles := make(map[int]*repo.IndexFile)
+	for _, chartVersion := range matchingVersions {
+		mirroredVersion, err := impl.chartMirrorRepository.FindVersionByMirrorIdAndChartVersion(mirror.Id, chartVersion)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in getting mirrored version", "mirrorId", mirror.Id, "chartVersion", chartVersion, "err", err)
+			return nil, err
+		}
+		if err == nil && mirroredVersion.Status == bean.MirrorStatusMirrored &&
+			mirroredVersion.TargetRegistryId == mirror.TargetRegistryId && mirroredVersion.MirrorRepository == mirrorRepository {
+			response.Skipped++
+			continue
+		}
+		if util.IsErrNoRows(err) {
+			mirroredVersion = &repository.ChartMirrorVersion{
+				ChartMirrorId: mirror.Id,
+				ChartName:     mirror.ChartName,
+				ChartVersion:  chartVersion,
+				AuditLog:      sql.NewDefaultAuditLog(userId),
+			}
+		}
+		mirroredVersion.AppStoreApplicationVersionId = versionIds[chartVersion]
+		mirroredVersion.TargetRegistryId = mirror.TargetRegistryId
+		mirroredVersion.MirrorRepository = mirrorRepository
+		impl.mirrorVersion(ctx, mirroredVersion, registry, indexFiles)
+		mirroredVersion.UpdatedOn = time.Now()
+		mirroredVersion.UpdatedBy = userId
+		if mirroredVersion.Id == 0 {
+			err = impl.chartMirrorRepository.SaveVersion(mirroredVersion)
+		} else {
+			err = impl.chartMirrorRepository.UpdateVersion(mirroredVersion)
+		}
+		if err != nil {
+			impl.logger.Errorw("error in saving mirrored version", "mirrorId", mirror.Id, "chartVersion", chartVersion, "err", err)
+			return nil, err
+		}
+		if mirroredVersion.Status == bean.MirrorStatusMirrored {
+			response.Mirrored++
+		} else {
+			response.Failed++
+		}
+		response.Versions = append(response.Versions, adaptMirroredVersion(mirroredVersion))
+	}
+	mirror.LastSyncedOn = time.Now()
+	mirror.UpdatedOn = time.Now()
+	mirror.UpdatedBy = userId
+	if err = impl.chartMirrorRepository.UpdateMirror(mirror); err != nil {
+		impl.logger.Errorw("error in updating last sync of chart mirror", "mirrorId", mirror.Id, "err", err)
+		return nil, err
+	}
+	return response, nil
+}
+
+// mirrorVersion pulls the chart archive of the version from its source and pushes it to the registry, the outcome is
+// set on the mirrored version
+func (impl *ChartMirrorServiceImpl) mirrorVersion(ctx context.Context, mirroredVersion *repository.ChartMirrorVersion,
+	registry *dockerRegistryRepository.DockerArtifactStore, indexFiles map[int]*repo.IndexFile) {
+	chartArchive, sourceUrl, err := impl.pullChartArchive(ctx, mirroredVersion.AppStoreApplicationVersionId, indexFiles)
+	if err == nil {
+		mirroredVersion.SourceUrl = sourceUrl
+		mirroredVersion.Digest = helper.GetChartArchiveDigest(chartArchive)
+		mirroredVersion.ManifestDigest, err = impl.pushChartArchive(ctx, registry, mirroredVersion.MirrorRepository, mirroredVersion.ChartVersion, chartArchive)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in mirroring chart version", "chartName", mirroredVersion.ChartName, "chartVersion", mirroredVersion.ChartVersion, "err", err)
+		mirroredVersion.Status = bean.MirrorStatusFailed
+		mirroredVersion.Message = err.Error()
+		return
+	}
+	mirroredVersion.Status = bean.MirrorStatusMirrored
+	mirroredVersion.Message = ""
+	mirroredVersion.MirroredOn = time.Now()
+}
+
+// pullChartArchive pulls the chart archive of a chart store version from the chart repository or the OCI registry it
+// was synced from, index files of chart repositories are cached in indexFiles for the versions of a sync
+func (impl *ChartMirrorServiceImpl) pullChartArchive(ctx context.Context, appStoreApplicationVersionId int, indexFiles map[int]*repo.IndexFile) ([]byte, string, error) {
+	appStoreAppVersion, err := impl.appStoreApplicationVersionRepository.FindById(appStoreApplicationVersionId)
+	if err != nil {
+		return nil, "", fmt.Errorf("error in getting chart version %d: %w", appStoreApplicationVersionId, err)
+	}
+	chartName := appStoreDeploymentCommon.GetChartNameFromAppStoreApplicationVersion(appStoreAppVersion)
+	if store := appStoreAppVersion.AppStore.DockerArtifactStore; store != nil && len(appStoreAppVersion.AppStore.DockerArtifactStoreId) > 0 {
+		chartArchive, err := impl.pullFromRegistry(ctx, store, appStoreAppVersion.AppStore.Name, appStoreAppVersion.Version)
+		host, _ := helper.GetRegistryHost(store.RegistryURL)
+		return chartArchive, fmt.Sprintf("oci://%s/%s:%s", host, appStoreAppVersion.AppStore.Name, appStoreAppVersion.Version), err
+	}
+	chartRepo := appStoreAppVersion.AppStore.ChartRepo
+	if chartRepo == nil {
+		return nil, "", fmt.Errorf("chart %s has no chart repository or registry", chartName)
+	}
+	indexFile, ok := indexFiles[chartRepo.Id]
+	if !ok {
+		indexContent, err := impl.httpGet(strings.TrimSuffix(chartRepo.Url, "/")+"/index.yaml", chartRepo)
+		if err != nil {
+			return nil, "", fmt.Errorf("error in getting index of chart repository %s: %w", chartRepo.Name, err)
+		}
+		indexFile = &repo.IndexFile{}
+		if err = yaml.Unmarshal(indexContent, indexFile); err != nil {
+			return nil, "", fmt.Errorf("error in reading index of chart repository %s: %w", chartRepo.Name, err)
+		}
+		indexFiles[chartRepo.Id] = indexFile
+	}
+	chartVersion, err := indexFile.Get(chartName, appStoreAppVersion.Version)
+	if err != nil {
+		return nil, "", fmt.Errorf("chart %s %s not found in chart repository %s: %w", chartName, appStoreAppVersion.Version, chartRepo.Name, err)
+	}
+	if len(chartVersion.URLs) == 0 {
+		return nil, "", fmt.Errorf("chart %s %s has no url in chart repository %s", chartName, appStoreAppVersion.Version, chartRepo.Name)
+	}
+	chartUrl, err := repo.ResolveReferenceURL(chartRepo.Url, chartVersion.URLs[0])
+	if err != nil {
+		return nil, "", err
+	}
+	chartArchive, err := impl.httpGet(chartUrl, chartRepo)
+	if err != nil {
+		return nil, "", fmt.Errorf("error in downloading chart %s: %w", chartUrl, err)
+	}
+	if len(chartVersion.Digest) > 0 {
+		checksum := sha256.Sum256(chartArchive)
+		if hex.EncodeToString(checksum[:]) != chartVersion.Digest {
+			return nil, "", fmt.Errorf("digest of chart %s does not match the index of chart repository %s", chartUrl, chartRepo.Name)
+		}
+	}
+	return chartArchive, chartUrl, nil
+}
+
+func (impl *ChartMirrorServiceImpl) httpGet(url string, chartRepo *chartRepoRepository.ChartRepo) ([]byte, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(chartRepo.UserName) > 0 || len(chartRepo.Password) > 0 {
+		request.SetBasicAuth(chartRepo.UserName, chartRepo.Password)
+	}
+	client := &http.Client{
+		Timeout: 5 * time.Minute,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: chartRepo.AllowInsecureConnection},
+		},
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, response.Status)
+	}
+	return io.ReadAll(response.Body)
+}
+
+func (impl *ChartMirrorServiceImpl) newRemoteRepository(registry *dockerRegistryRepository.DockerArtifactStore, repositoryName string) (*remote.Repository, error) {
+	registryHost, plainHTTP := helper.GetRegistryHost(registry.RegistryURL)
+	ociRepo, err := remote.NewRepository(fmt.Sprintf("%s/%s", registryHost, repositoryName))
+	if err != nil {
+		return nil, err
+	}
+	ociRepo.PlainHTTP = plainHTTP
+	ociRepo.Client = &auth.Client{
+		Client: auth.DefaultClient.Client,
+		Cache:  auth.NewCache(),
+		Credential: auth.StaticCredential(ociRepo.Reference.Registry, auth.Credential{
+			Username: registry.Username,
+			Password: registry.Password,
+		}),
+	}
+	return ociRepo, nil
+}
+
+// pullFromRegistry fetches the chart content layer of a chart pushed to an OCI registry
+func (impl *ChartMirrorServiceImpl) pullFromRegistry(ctx context.Context, registry *dockerRegistryRepository.DockerArtifactStore, repositoryName, tag string) ([]byte, error) {
+	ociRepo, err := impl.newRemoteRepository(registry, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+	manifestDescriptor, manifestReader, err := ociRepo.FetchReference(ctx, tag)
+	if err != nil {
+		return nil, fmt.Errorf("error in fetching %s:%s: %w", repositoryName, tag, err)
+	}
+	defer manifestReader.Close()
+	manifestContent, err := content.ReadAll(manifestReader, manifestDescriptor)
+	if err != nil {
+		return nil, err
+	}
+	chartManifest := &ocispec.Manifest{}
+	if err = json.Unmarshal(manifestContent, chartManifest); err != nil {
+		return nil, err
+	}
+	for _, layer := range chartManifest.Layers {
+		if layer.MediaType == bean.HelmChartContentLayerMediaType {
+			return content.FetchAll(ctx, ociRepo, layer)
+		}
+	}
+	return nil, fmt.Errorf("%s:%s is not a helm chart", repositoryName, tag)
+}
+
+// pushChartArchive pushes a chart archive to the registry the way helm does and returns the digest of the manifest
+func (impl *ChartMirrorServiceImpl) pushChartArchive(ctx context.Context, registry *dockerRegistryRepository.DockerArtifactStore, repositoryName, tag string, chartArchive []byte) (string, error) {
+	chart, err := loader.LoadArchive(bytes.NewReader(chartArchive))
+	if err != nil {
+		return "", fmt.Errorf("invalid chart archive: %w", err)
+	}
+	if chart.Metadata.Version != tag {
+		return "", fmt.Errorf("chart archive has version %s, expected %s", chart.Metadata.Version, tag)
+	}
+	configContent, err := json.Marshal(chart.Metadata)
+	if err != nil {
+		return "", err
+	}
+	ociRepo, err := impl.newRemoteRepository(registry, repositoryName)
+	if err != nil {
+		return "", err
+	}
+	configDescriptor := content.NewDescriptorFromBytes(bean.HelmChartConfigMediaType, configContent)
+	layerDescriptor := content.NewDescriptorFromBytes(bean.HelmChartContentLayerMediaType, chartArchive)
+	for _, blob := range []struct {
+		descriptor ocispec.Descriptor
+		content    []byte
+	}{{configDescriptor, configContent}, {layerDescriptor, chartArchive}} {
+		exists, err := ociRepo.Exists(ctx, blob.descriptor)
+		if err != nil {
+			return "", fmt.Errorf("error in checking blob %s in %s: %w", blob.descriptor.Digest, repositoryName, err)
+		}
+		if exists {
+			continue
+		}
+		if err = ociRepo.Push(ctx, blob.descriptor, bytes.NewReader(blob.content)); err != nil {
+			return "", fmt.Errorf("error in pushing blob %s to %s: %w", blob.descriptor.Digest, repositoryName, err)
+		}
+	}
+	chartManifest := ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    configDescriptor,
+		Layers:    []ocispec.Descriptor{layerDescriptor},
+	}
+	manifestContent, err := json.Marshal(chartManifest)
+	if err != nil {
+		return "", err
+	}
+	manifestDescriptor := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, manifestContent)
+	if err = ociRepo.PushReference(ctx, manifestDescriptor, bytes.NewReader(manifestContent), tag); err != nil {
+		return "", fmt.Errorf("error in pushing %s:%s: %w", repositoryName, tag, err)
+	}
+	return manifestDescriptor.Digest.String(), nil
+}
+
+func (impl *ChartMirrorServiceImpl) ExportBundle(ctx context.Context, mirrorIds []int, writer io.Writer) error {
+	var mirrors []*repository.ChartMirror
+	if len(mirrorIds) == 0 {
+		activeMirrors, err := impl.chartMirrorRepository.FindActiveMirrors()
+		if err != nil {
+			impl.logger.Errorw("error in getting active chart mirrors", "err", err)
+			return err
+		}
+		mirrors = activeMirrors
+	}
+	for _, mirrorId := range mirrorIds {
+		mirror, err := impl.getMirror(mirrorId)
+		if err != nil {
+			return err
+		}
+		mirrors = append(mirrors, mirror)
+	}
+	manifest := &bean.BundleManifest{
+		Version:    bean.BundleManifestVersion,
+		ExportedOn: time.Now(),
+		Charts:     make([]*bean.BundleChart, 0),
+	}
+	chartArchives := make(map[string][]byte)
+	registries := make(map[string]*dockerRegistryRepository.DockerArtifactStore)
+	for _, mirror := range mirrors {
+		mirroredVersions, err := impl.chartMirrorRepository.FindVersionsByMirrorId(mirror.Id)
+		if err != nil {
+			impl.logger.Errorw("error in getting mirrored versions", "mirrorId", mirror.Id, "err", err)
+			return err
+		}
+		for _, mirroredVersion := range mirroredVersions {
+			if mirroredVersion.Status != bean.MirrorStatusMirrored {
+				continue
+			}
+			file := helper.GetBundleChartFile(mirroredVersion.ChartName, mirroredVersion.ChartVersion)
+			if _, ok := chartArchives[file]; ok {
+				continue
+			}
+			registry, ok := registries[mirroredVersion.TargetRegistryId]
+			if !ok {
+				registry, err = impl.getTargetRegistry(mirroredVersion.TargetRegistryId)
+				if err != nil {
+					return err
+				}
+				registries[mirroredVersion.TargetRegistryId] = registry
+			}
+			chartArchive, err := impl.pullFromRegistry(ctx, registry, mirroredVersion.MirrorRepository, mirroredVersion.ChartVersion)
+			if err != nil {
+				impl.logger.Errorw("error in pulling mirrored chart", "mirrorId", mirror.Id, "chartVersion", mirroredVersion.ChartVersion, "err", err)
+				return err
+			}
+			chartDigest := helper.GetChartArchiveDigest(chartArchive)
+			if len(mirroredVersion.Digest) > 0 && chartDigest != mirroredVersion.Digest {
+				return util.NewApiError(http.StatusConflict, "mirrored chart has been modified in the registry",
+					fmt.Sprintf("digest of %s:%s is %s, manifest has %s", mirroredVersion.MirrorRepository, mirroredVersion.ChartVersion, chartDigest, mirroredVersion.Digest))
+			}
+			chartArchives[file] = chartArchive
+			manifest.Charts = append(manifest.Charts, &bean.BundleChart{
+				ChartName:    mirroredVersion.ChartName,
+				ChartVersion: mirroredVersion.ChartVersion,
+				Digest:       chartDigest,
+				File:         file,
+				SourceUrl:    mirroredVersion.SourceUrl,
+			})
+		}
+	}
+	return helper.WriteBundle(writer, manifest, chartArchives)
+}
+
+func (impl *ChartMirrorServiceImpl) ImportBundle(ctx context.Context, reader io.Reader, request *bean.BundleImportRequest) (*bean.BundleImportResponse, error) {
+	registry, err := impl.getTargetRegistry(request.TargetRegistryId)
+	if err != nil {
+		return nil, err
+	}
+	manifest, chartArchives, err := helper.ReadBundle(reader)
+	if err != nil {
+		impl.logger.Errorw("error in reading chart mirror bundle", "err", err)
+		return nil, util.NewApiError(http.StatusBadRequest, "invalid bundle", err.Error())
+	}
+	targetRepository := strings.Trim(request.TargetRepository, "/")
+	response := &bean.BundleImportResponse{Versions: make([]*bean.MirroredVersionDto, 0, len(manifest.Charts))}
+	mirrors := make(map[string]*repository.ChartMirror)
+	for _, chart := range manifest.Charts {
+		mirror, ok := mirrors[chart.ChartName]
+		if !ok {
+			mirror, err = impl.getOrCreateImportMirror(chart, registry.Id, targetRepository, request.UserId)
+			if err != nil {
+				return nil, err
+			}
+			mirrors[chart.ChartName] = mirror
+		}
+		mirrorRepository := helper.GetMirrorRepository(targetRepository, chart.ChartName)
+		manifestDigest, err := impl.pushChartArchive(ctx, registry, mirrorRepository, chart.ChartVersion, chartArchives[chart.File])
+		if err != nil {
+			impl.logger.Errorw("error in pushing chart of bundle", "chartName", chart.ChartName, "chartVersion", chart.ChartVersion, "err", err)
+			return nil, err
+		}
+		mirroredVersion, err := impl.chartMirrorRepository.FindVersionByMirrorIdAndChartVersion(mirror.Id, chart.ChartVersion)
+		if util.IsErrNoRows(err) {
+			mirroredVersion = &repository.ChartMirrorVersion{
+				ChartMirrorId: mirror.Id,
+				ChartName:     chart.ChartName,
+				ChartVersion:  chart.ChartVersion,
+				AuditLog:      sql.NewDefaultAuditLog(request.UserId),
+			}
+		} else if err != nil {
+			impl.logger.Errorw("error in getting mirrored version", "mirrorId", mirror.Id, "chartVersion", chart.ChartVersion, "err", err)
+			return nil, err
+		}
+		appStoreApplicationVersionId, err := impl.findAppStoreApplicationVersionId(mirror.AppStoreId, chart.ChartName, chart.ChartVersion)
+		if err != nil {
+			return nil, err
+		}
+		mirroredVersion.AppStoreApplicationVersionId = appStoreApplicationVersionId
+		mirroredVersion.SourceUrl = chart.SourceUrl
+		mirroredVersion.TargetRegistryId = registry.Id
+		mirroredVersion.MirrorRepository = mirrorRepository
+		mirroredVersion.Digest = chart.Digest
+		mirroredVersion.ManifestDigest = manifestDigest
+		mirroredVersion.Status = bean.MirrorStatusMirrored
+		mirroredVersion.Message = ""
+		mirroredVersion.MirroredOn = time.Now()
+		mirroredVersion.UpdatedOn = time.Now()
+		mirroredVersion.UpdatedBy = request.UserId
+		if mirroredVersion.Id == 0 {
+			err = impl.chartMirrorRepository.SaveVersion(mirroredVersion)
+		} else {
+			err = impl.chartMirrorRepository.UpdateVersion(mirroredVersion)
+		}
+		if err != nil {
+			impl.logger.Errorw("error in saving imported version", "mirrorId", mirror.Id, "chartVersion", chart.ChartVersion, "err", err)
+			return nil, err
+		}
+		response.Imported++
+		response.Versions = append(response.Versions, adaptMirroredVersion(mirroredVersion))
+	}
+	return response, nil
+}
+
+// getOrCreateImportMirror returns the mirror of the chart to the target repository, a mirror linked to the chart store
+// is created when the chart is found in it
+func (impl *ChartMirrorServiceImpl) getOrCreateImportMirror(chart *bean.BundleChart, registryId, targetRepository string, userId int32) (*repository.ChartMirror, error) {
+	mirror, err := impl.chartMirrorRepository.FindMirrorByChartNameAndTarget(chart.ChartName, registryId, targetRepository)
+	if err == nil {
+		return mirror, nil
+	} else if !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting chart mirror", "chartName", chart.ChartName, "err", err)
+		return nil, err
+	}
+	mirror = &repository.ChartMirror{
+		ChartName:        chart.ChartName,
+		TargetRegistryId: registryId,
+		TargetRepository: targetRepository,
+		Active:           true,
+		LastSyncedOn:     time.Now(),
+		AuditLog:         sql.NewDefaultAuditLog(userId),
+	}
+	candidates, err := impl.appStoreApplicationVersionRepository.FindByChartNameAndVersion(chart.ChartName, chart.ChartVersion)
+	if err != nil {
+		impl.logger.Errorw("error in getting chart store versions", "chartName", chart.ChartName, "chartVersion", chart.ChartVersion, "err", err)
+		return nil, err
+	}
+	if len(candidates) == 1 {
+		mirror.AppStoreId = candidates[0].ChartId
+	}
+	if err = impl.chartMirrorRepository.SaveMirror(mirror); err != nil {
+		impl.logger.Errorw("error in saving chart mirror", "mirror", mirror, "err", err)
+		return nil, err
+	}
+	return mirror, nil
+}
+
+// findAppStoreApplicationVersionId links an imported chart to the chart store version of the chart of the mirror, 0 is
+// returned when the chart store has no such version and installs keep pulling from the source
+func (impl *ChartMirrorServiceImpl) findAppStoreApplicationVersionId(appStoreId int, chartName, chartVersion string) (int, error) {
+	if appStoreId == 0 {
+		return 0, nil
+	}
+	candidates, err := impl.appStoreApplicationVersionRepository.FindByChartNameAndVersion(chartName, chartVersion)
+	if err != nil {
+		impl.logger.Errorw("error in getting chart store versions", "chartName", chartName, "chartVersion", chartVersion, "err", err)
+		return 0, err
+	}
+	for _, candidate := range candidates {
+		if candidate.ChartId == appStoreId {
+			return candidate.AppStoreApplicationVersionId, nil
+		}
+	}
+	return 0, nil
+}
+
+func (impl *ChartMirrorServiceImpl) getMirror(id int) (*repository.ChartMirror, error) {
+	mirror, err := impl.chartMirrorRepository.FindMirrorById(id)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "chart mirror not found", fmt.Sprintf("chart mirror %d not found", id))
+	} else if err != nil {
+		impl.logger.Errorw("error in getting chart mirror", "id", id, "err", err)
+		return nil, err
+	}
+	return mirror, nil
+}
+
+// getTargetRegistry returns the registry charts are mirrored to, it has to be an OCI registry configured in Devtron
+func (impl *ChartMirrorServiceImpl) getTargetRegistry(registryId string) (*dockerRegistryRepository.DockerArtifactStore, error) {
+	registry, err := impl.dockerArtifactStoreRepository.FindOne(registryId)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "target registry not found", fmt.Sprintf("registry %s not found", registryId))
+	} else if err != nil {
+		impl.logger.Errorw("error in getting target registry", "registryId", registryId, "err", err)
+		return nil, err
+	}
+	if !registry.IsOCICompliantRegistry {
+		return nil, util.NewApiError(http.StatusBadRequest, "target registry is not an OCI registry", fmt.Sprintf("registry %s is not OCI compliant", registryId))
+	}
+	return registry, nil
+}
+
+func adaptMirror(mirror *repository.ChartMirror, versions []*repository.ChartMirrorVersion) *bean.ChartMirrorDto {
+	mirrorDto := &bean.ChartMirrorDto{
+		Id:                mirror.Id,
+		AppStoreId:        mirror.AppStoreId,
+		ChartName:         mirror.ChartName,
+		VersionConstraint: mirror.VersionConstraint,
+		TargetRegistryId:  mirror.TargetRegistryId,
+		TargetRepository:  mirror.TargetRepository,
+		Active:            mirror.Active,
+	}
+	if !mirror.LastSyncedOn.IsZero() {
+		lastSyncedOn := mirror.LastSyncedOn
+		mirrorDto.LastSyncedOn = &lastSyncedOn
+	}
+	for _, version := range versions {
+		mirrorDto.Versions = append(mirrorDto.Versions, adaptMirroredVersion(version))
+	}
+	return mirrorDto
+}
+
+func adaptMirroredVersion(version *repository.ChartMirrorVersion) *bean.MirroredVersionDto {
+	versionDto := &bean.MirroredVersionDto{
+		Id:                           version.Id,
+		ChartMirrorId:                version.ChartMirrorId,
+		AppStoreApplicationVersionId: version.AppStoreApplicationVersionId,
+		ChartName:                    version.ChartName,
+		ChartVersion:                 version.ChartVersion,
+		SourceUrl:                    version.SourceUrl,
+		TargetRegistryId:             version.TargetRegistryId,
+		MirrorRepository:             version.MirrorRepository,
+		Digest:                       version.Digest,
+		ManifestDigest:               version.ManifestDigest,
+		Status:                       version.Status,
+		Message:                      version.Message,
+	}
+	if !version.MirroredOn.IsZero() {
+		mirroredOn := version.MirroredOn
+		versionDto.MirroredOn = &mirroredOn
+	}
+	return versionDto
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"time"
+
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+)
+
+type MirrorStatus string
+
+const (
+	MirrorStatusMirrored MirrorStatus = "mirrored"
+	MirrorStatusFailed   MirrorStatus = "failed"
+)
+
+const (
+	// HelmChartConfigMediaType and HelmChartContentLayerMediaType are the media types helm uses when it pushes a
+	// chart to an OCI registry, charts mirrored with them can be pulled back with helm and kubelink
+	HelmChartConfigMediaType       = "application/vnd.cncf.helm.config.v1+json"
+	HelmChartContentLayerMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+	BundleManifestFileName = "manifest.json"
+	BundleChartsDir        = "charts"
+	BundleManifestVersion  = 1
+	BundleContentType      = "application/gzip"
+	BundleFileFormName     = "bundle"
+)
+
+type ChartMirrorDto struct {
+	Id         int    `json:"id"`
+	AppStoreId int    `json:"appStoreId"`
+	ChartName  string `json:"chartName"`
+	// VersionConstraint is a semver range of chart versions to mirror, all versions are mirrored when it is empty
+	VersionConstraint string `json:"versionConstraint"`
+	// TargetRegistryId is the OCI registry configured in Devtron the charts are pushed to
+	TargetRegistryId string `json:"targetRegistryId" validate:"required"`
+	// TargetRepository is the repository path in the target registry, charts are pushed to <TargetRepository>/<ChartName>
+	TargetRepository string                `json:"targetRepository" validate:"required"`
+	Active           bool                  `json:"active"`
+	LastSyncedOn     *time.Time            `json:"lastSyncedOn,omitempty"`
+	Versions         []*MirroredVersionDto `json:"versions,omitempty"`
+	UserId           int32                 `json:"-"`
+}
+
+// MirroredVersionDto is an entry of the manifest of mirrored charts
+type MirroredVersionDto struct {
+	Id                           int          `json:"id"`
+	ChartMirrorId                int          `json:"chartMirrorId"`
+	AppStoreApplicationVersionId int          `json:"appStoreApplicationVersionId,omitempty"`
+	ChartName                    string       `json:"chartName"`
+	ChartVersion                 string       `json:"chartVersion"`
+	SourceUrl                    string       `json:"sourceUrl,omitempty"`
+	TargetRegistryId             string       `json:"targetRegistryId"`
+	MirrorRepository             string       `json:"mirrorRepository"`
+	Digest                       string       `json:"digest,omitempty"`
+	ManifestDigest               string       `json:"manifestDigest,omitempty"`
+	Status                       MirrorStatus `json:"status"`
+	Message                      string       `json:"message,omitempty"`
+	MirroredOn                   *time.Time   `json:"mirroredOn,omitempty"`
+}
+
+type ChartMirrorSyncResponse struct {
+	ChartMirrorId int                   `json:"chartMirrorId"`
+	Mirrored      int                   `json:"mirrored"`
+	Failed        int                   `json:"failed"`
+	Skipped       int                   `json:"skipped"`
+	Versions      []*MirroredVersionDto `json:"versions"`
+}
+
+// MirroredChart is where a chart version has been mirrored to, installs of the chart version are pointed to it
+type MirroredChart struct {
+	Registry *dockerRegistryRepository.DockerArtifactStore
+	// IsPublic is set when the chart pull config of the registry is public
+	IsPublic bool
+	// Repository is the full repository of the chart in the registry, including the chart name
+	Repository   string
+	ChartName    string
+	ChartVersion string
+	Digest       string
+}
+
+// BundleManifest is kept in the bundle a mirror is exported to along with the chart archives it lists
+type BundleManifest struct {
+	Version    int            `json:"version"`
+	ExportedOn time.Time      `json:"exportedOn"`
+	Charts     []*BundleChart `json:"charts"`
+}
+
+type BundleChart struct {
+	ChartName    string `json:"chartName"`
+	ChartVersion string `json:"chartVersion"`
+	// Digest is the sha256 digest of the chart archive, it is verified on import
+	Digest    string `json:"digest"`
+	File      string `json:"file"`
+	SourceUrl string `json:"sourceUrl,omitempty"`
+}
+
+type BundleImportRequest struct {
+	TargetRegistryId string `json:"targetRegistryId" validate:"required"`
+	TargetRepository string `json:"targetRepository" validate:"required"`
+	UserId           int32  `json:"-"`
+}
+
+type BundleImportResponse struct {
+	Imported int                   `json:"imported"`
+	Versions []*MirroredVersionDto `json:"versions"`
+}
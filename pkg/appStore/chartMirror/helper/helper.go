@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/devtron-labs/devtron/pkg/appStore/chartMirror/bean"
+	"github.com/opencontainers/go-digest"
+)
+
+var (
+	ErrBundleManifestNotFound = errors.New("manifest.json not found in bundle")
+	ErrBundleDigestMismatch   = errors.New("digest of chart archive in bundle does not match the manifest")
+)
+
+// ValidateVersionConstraint checks that the constraint is a valid semver range, an empty constraint matches all versions
+func ValidateVersionConstraint(versionConstraint string) error {
+	if len(strings.TrimSpace(versionConstraint)) == 0 {
+		return nil
+	}
+	_, err := semver.NewConstraint(versionConstraint)
+	if err != nil {
+		return fmt.Errorf("invalid version constraint %q: %w", versionConstraint, err)
+	}
+	return nil
+}
+
+// FilterVersions returns the versions matching the constraint, versions which are not valid semver are only kept
+// when all versions are mirrored
+func FilterVersions(versions []string, versionConstraint string) ([]string, error) {
+	filtered := make([]string, 0, len(versions))
+	if len(strings.TrimSpace(versionConstraint)) == 0 {
+		return append(filtered, versions...), nil
+	}
+	constraint, err := semver.NewConstraint(versionConstraint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version constraint %q: %w", versionConstraint, err)
+	}
+	for _, version := range versions {
+		parsedVersion, err := semver.NewVersion(version)
+		if err != nil {
+			continue
+		}
+		if constraint.Check(parsedVersion) {
+			filtered = append(filtered, version)
+		}
+	}
+	return filtered, nil
+}
+
+// GetMirrorRepository is the repository a chart is pushed to in the target registry, it follows the convention of
+// the chart store where the repository of an OCI chart ends with the chart name
+func GetMirrorRepository(targetRepository, chartName string) string {
+	return path.Join(strings.Trim(targetRepository, "/"), chartName)
+}
+
+// GetRegistryHost strips the scheme of a registry url, plainHTTP is set for registries served over http
+func GetRegistryHost(registryUrl string) (host string, plainHTTP bool) {
+	registryUrl = strings.TrimSpace(registryUrl)
+	plainHTTP = strings.HasPrefix(registryUrl, "http://")
+	host = strings.TrimPrefix(strings.TrimPrefix(strings.TrimPrefix(registryUrl, "oci://"), "https://"), "http://")
+	return strings.TrimSuffix(host, "/"), plainHTTP
+}
+
+func GetChartArchiveDigest(chartArchive []byte) string {
+	return digest.FromBytes(chartArchive).String()
+}
+
+func GetBundleChartFile(chartName, chartVersion string) string {
+	return path.Join(bean.BundleChartsDir, fmt.Sprintf("%s-%s.tgz", chartName, chartVersion))
+}
+
+// WriteBundle writes the manifest and the chart archives it lists to a gzipped tarball, chartArchives is keyed on the
+// file of the chart in the manifest
+func WriteBundle(writer io.Writer, manifest *bean.BundleManifest, chartArchives map[string][]byte) error {
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	gzipWriter := gzip.NewWriter(writer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	if err = writeTarFile(tarWriter, bean.BundleManifestFileName, manifestContent); err != nil {
+		return err
+	}
+	files := make([]string, 0, len(chartArchives))
+	for file := range chartArchives {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		if err = writeTarFile(tarWriter, file, chartArchives[file]); err != nil {
+			return err
+		}
+	}
+	if err = tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+func writeTarFile(tarWriter *tar.Writer, name string, fileContent []byte) error {
+	header := &tar.Header{
+		Name: name,
+		Mode: 0644,
+		Size: int64(len(fileContent)),
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tarWriter.Write(fileContent)
+	return err
+}
+
+// ReadBundle reads a bundle written by WriteBundle and verifies the digest of every chart archive listed in its manifest
+func ReadBundle(reader io.Reader) (*bean.BundleManifest, map[string][]byte, error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bundle is not a gzipped tarball: %w", err)
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+	files := make(map[string][]byte)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		buf := &bytes.Buffer{}
+		if _, err = io.Copy(buf, tarReader); err != nil {
+			return nil, nil, err
+		}
+		files[path.Clean(header.Name)] = buf.Bytes()
+	}
+	manifestContent, ok := files[bean.BundleManifestFileName]
+	if !ok {
+		return nil, nil, ErrBundleManifestNotFound
+	}
+	manifest := &bean.BundleManifest{}
+	if err = json.Unmarshal(manifestContent, manifest); err != nil {
+		return nil, nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+	chartArchives := make(map[string][]byte, len(manifest.Charts))
+	for _, chart := range manifest.Charts {
+		chartArchive, ok := files[path.Clean(chart.File)]
+		if !ok {
+			return nil, nil, fmt.Errorf("chart archive %s listed in the manifest is missing in the bundle", chart.File)
+		}
+		if GetChartArchiveDigest(chartArchive) != chart.Digest {
+			return nil, nil, fmt.Errorf("%w: %s", ErrBundleDigestMismatch, chart.File)
+		}
+		chartArchives[chart.File] = chartArchive
+	}
+	return manifest, chartArchives, nil
+}
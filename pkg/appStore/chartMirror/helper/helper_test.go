@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package helper
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/appStore/chartMirror/bean"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterVersions(t *testing.T) {
+	versions := []string{"18.1.0", "18.0.2", "17.9.1", "19.0.0-rc.1", "latest"}
+
+	filtered, err := FilterVersions(versions, "")
+	assert.Nil(t, err)
+	assert.Equal(t, versions, filtered)
+
+	filtered, err = FilterVersions(versions, ">=18.0.0 <19.0.0")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"18.1.0", "18.0.2"}, filtered)
+
+	filtered, err = FilterVersions(versions, "~17.9")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"17.9.1"}, filtered)
+
+	_, err = FilterVersions(versions, ">= what")
+	assert.NotNil(t, err)
+	assert.NotNil(t, ValidateVersionConstraint(">= what"))
+	assert.Nil(t, ValidateVersionConstraint("^18"))
+}
+
+func TestGetMirrorRepositoryAndRegistryHost(t *testing.T) {
+	assert.Equal(t, "mirror/bitnami/redis", GetMirrorRepository("/mirror/bitnami/", "redis"))
+	assert.Equal(t, "redis", GetMirrorRepository("", "redis"))
+
+	host, plainHTTP := GetRegistryHost("https://registry.internal:5000/")
+	assert.Equal(t, "registry.internal:5000", host)
+	assert.False(t, plainHTTP)
+	host, plainHTTP = GetRegistryHost("http://registry.internal")
+	assert.Equal(t, "registry.internal", host)
+	assert.True(t, plainHTTP)
+}
+
+func TestWriteAndReadBundle(t *testing.T) {
+	redis := []byte("redis chart archive")
+	nginx := []byte("nginx chart archive")
+	manifest := &bean.BundleManifest{
+		Version:    bean.BundleManifestVersion,
+		ExportedOn: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Charts: []*bean.BundleChart{
+			{ChartName: "redis", ChartVersion: "18.1.0", Digest: GetChartArchiveDigest(redis), File: GetBundleChartFile("redis", "18.1.0")},
+			{ChartName: "nginx", ChartVersion: "15.0.0", Digest: GetChartArchiveDigest(nginx), File: GetBundleChartFile("nginx", "15.0.0")},
+		},
+	}
+	archives := map[string][]byte{
+		"charts/redis-18.1.0.tgz": redis,
+		"charts/nginx-15.0.0.tgz": nginx,
+	}
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WriteBundle(buf, manifest, archives))
+
+	readManifest, readArchives, err := ReadBundle(bytes.NewReader(buf.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, manifest, readManifest)
+	assert.Equal(t, archives, readArchives)
+
+	manifest.Charts[0].Digest = GetChartArchiveDigest([]byte("tampered"))
+	buf.Reset()
+	assert.Nil(t, WriteBundle(buf, manifest, archives))
+	_, _, err = ReadBundle(bytes.NewReader(buf.Bytes()))
+	assert.True(t, errors.Is(err, ErrBundleDigestMismatch))
+
+	_, _, err = ReadBundle(bytes.NewReader([]byte("not a bundle")))
+	assert.NotNil(t, err)
+}
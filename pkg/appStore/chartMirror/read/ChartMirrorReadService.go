@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package read
+
+import (
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appStore/chartMirror/bean"
+	"github.com/devtron-labs/devtron/pkg/appStore/chartMirror/repository"
+	"go.uber.org/zap"
+)
+
+type ChartMirrorReadService interface {
+	// GetMirroredChart returns where a chart version has been mirrored to, nil is returned when the chart version is
+	// not mirrored by an active mirror and installs should pull it from its source
+	GetMirroredChart(appStoreApplicationVersionId int) (*bean.MirroredChart, error)
+}
+
+type ChartMirrorReadServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	chartMirrorRepository         repository.ChartMirrorRepository
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository
+	ociRegistryConfigRepository   dockerRegistryRepository.OCIRegistryConfigRepository
+}
+
+func NewChartMirrorReadServiceImpl(logger *zap.SugaredLogger, chartMirrorRepository repository.ChartMirrorRepository,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository,
+	ociRegistryConfigRepository dockerRegistryRepository.OCIRegistryConfigRepository) *ChartMirrorReadServiceImpl {
+	return &ChartMirrorReadServiceImpl{
+		logger:                        logger,
+		chartMirrorRepository:         chartMirrorRepository,
+		dockerArtifactStoreRepository: dockerArtifactStoreRepository,
+		ociRegistryConfigRepository:   ociRegistryConfigRepository,
+	}
+}
+
+func (impl *ChartMirrorReadServiceImpl) GetMirroredChart(appStoreApplicationVersionId int) (*bean.MirroredChart, error) {
+	mirroredVersion, err := impl.chartMirrorRepository.FindMirroredVersionByAppStoreApplicationVersionId(appStoreApplicationVersionId)
+	if util.IsErrNoRows(err) {
+		return nil, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in getting mirrored chart version", "appStoreApplicationVersionId", appStoreApplicationVersionId, "err", err)
+		return nil, err
+	}
+	registry, err := impl.dockerArtifactStoreRepository.FindOne(mirroredVersion.TargetRegistryId)
+	if util.IsErrNoRows(err) {
+		// the registry has been deleted, installs fall back to the source of the chart
+		impl.logger.Warnw("registry of mirrored chart version not found", "appStoreApplicationVersionId", appStoreApplicationVersionId, "registryId", mirroredVersion.TargetRegistryId)
+		return nil, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in getting registry of mirrored chart version", "registryId", mirroredVersion.TargetRegistryId, "err", err)
+		return nil, err
+	}
+	mirroredChart := &bean.MirroredChart{
+		Registry:     registry,
+		Repository:   mirroredVersion.MirrorRepository,
+		ChartName:    mirroredVersion.ChartName,
+		ChartVersion: mirroredVersion.ChartVersion,
+		Digest:       mirroredVersion.Digest,
+	}
+	ociRegistryConfig, err := impl.ociRegistryConfigRepository.FindOneByDockerRegistryIdAndRepositoryType(registry.Id, dockerRegistryRepository.OCI_REGISRTY_REPO_TYPE_CHART)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting chart config of registry", "registryId", registry.Id, "err", err)
+		return nil, err
+	} else if err == nil {
+		mirroredChart.IsPublic = ociRegistryConfig.IsPublic
+	}
+	return mirroredChart, nil
+}
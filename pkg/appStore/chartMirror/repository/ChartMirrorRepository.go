@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/appStore/chartMirror/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type ChartMirror struct {
+	tableName         struct{}  `sql:"chart_mirror" pg:",discard_unknown_columns"`
+	Id                int       `sql:"id,pk"`
+	AppStoreId        int       `sql:"app_store_id"`
+	ChartName         string    `sql:"chart_name,notnull"`
+	VersionConstraint string    `sql:"version_constraint"`
+	TargetRegistryId  string    `sql:"target_registry_id,notnull"`
+	TargetRepository  string    `sql:"target_repository,notnull"`
+	Active            bool      `sql:"active,notnull"`
+	LastSyncedOn      time.Time `sql:"last_synced_on"`
+	sql.AuditLog
+}
+
+type ChartMirrorVersion struct {
+	tableName                    struct{}          `sql:"chart_mirror_version" pg:",discard_unknown_columns"`
+	Id                           int               `sql:"id,pk"`
+	ChartMirrorId                int               `sql:"chart_mirror_id,notnull"`
+	AppStoreApplicationVersionId int               `sql:"app_store_application_version_id"`
+	ChartName                    string            `sql:"chart_name,notnull"`
+	ChartVersion                 string            `sql:"chart_version,notnull"`
+	SourceUrl                    string            `sql:"source_url"`
+	TargetRegistryId             string            `sql:"target_registry_id,notnull"`
+	MirrorRepository             string            `sql:"mirror_repository,notnull"`
+	Digest                       string            `sql:"digest"`
+	ManifestDigest               string            `sql:"manifest_digest"`
+	Status                       bean.MirrorStatus `sql:"status,notnull"`
+	Message                      string            `sql:"message"`
+	MirroredOn                   time.Time         `sql:"mirrored_on"`
+	sql.AuditLog
+}
+
+type ChartMirrorRepository interface {
+	SaveMirror(mirror *ChartMirror) error
+	UpdateMirror(mirror *ChartMirror) error
+	FindMirrorById(id int) (*ChartMirror, error)
+	FindAllMirrors() ([]*ChartMirror, error)
+	FindActiveMirrors() ([]*ChartMirror, error)
+	FindMirrorByChartNameAndTarget(chartName, targetRegistryId, targetRepository string) (*ChartMirror, error)
+
+	SaveVersion(version *ChartMirrorVersion) error
+	UpdateVersion(version *ChartMirrorVersion) error
+	FindVersionsByMirrorId(mirrorId int) ([]*ChartMirrorVersion, error)
+	FindVersionByMirrorIdAndChartVersion(mirrorId int, chartVersion string) (*ChartMirrorVersion, error)
+	// FindMirroredVersionByAppStoreApplicationVersionId returns the latest successful mirror of a chart version among
+	// active mirrors
+	FindMirroredVersionByAppStoreApplicationVersionId(appStoreApplicationVersionId int) (*ChartMirrorVersion, error)
+}
+
+type ChartMirrorRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewChartMirrorRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ChartMirrorRepositoryImpl {
+	return &ChartMirrorRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ChartMirrorRepositoryImpl) SaveMirror(mirror *ChartMirror) error {
+	return impl.dbConnection.Insert(mirror)
+}
+
+func (impl *ChartMirrorRepositoryImpl) UpdateMirror(mirror *ChartMirror) error {
+	return impl.dbConnection.Update(mirror)
+}
+
+func (impl *ChartMirrorRepositoryImpl) FindMirrorById(id int) (*ChartMirror, error) {
+	mirror := &ChartMirror{}
+	err := impl.dbConnection.Model(mirror).
+		Where("id = ?", id).
+		Select()
+	return mirror, err
+}
+
+func (impl *ChartMirrorRepositoryImpl) FindAllMirrors() ([]*ChartMirror, error) {
+	var mirrors []*ChartMirror
+	err := impl.dbConnection.Model(&mirrors).
+		Order("id ASC").
+		Select()
+	return mirrors, err
+}
+
+func (impl *ChartMirrorRepositoryImpl) FindActiveMirrors() ([]*ChartMirror, error) {
+	var mirrors []*ChartMirror
+	err := impl.dbConnection.Model(&mirrors).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return mirrors, err
+}
+
+func (impl *ChartMirrorRepositoryImpl) FindMirrorByChartNameAndTarget(chartName, targetRegistryId, targetRepository string) (*ChartMirror, error) {
+	mirror := &ChartMirror{}
+	err := impl.dbConnection.Model(mirror).
+		Where("chart_name = ?", chartName).
+		Where("target_registry_id = ?", targetRegistryId).
+		Where("target_repository = ?", targetRepository).
+		Limit(1).
+		Select()
+	return mirror, err
+}
+
+func (impl *ChartMirrorRepositoryImpl) SaveVersion(version *ChartMirrorVersion) error {
+	return impl.dbConnection.Insert(version)
+}
+
+func (impl *ChartMirrorRepositoryImpl) UpdateVersion(version *ChartMirrorVersion) error {
+	return impl.dbConnection.Update(version)
+}
+
+func (impl *ChartMirrorRepositoryImpl) FindVersionsByMirrorId(mirrorId int) ([]*ChartMirrorVersion, error) {
+	var versions []*ChartMirrorVersion
+	err := impl.dbConnection.Model(&versions).
+		Where("chart_mirror_id = ?", mirrorId).
+		Order("id ASC").
+		Select()
+	return versions, err
+}
+
+func (impl *ChartMirrorRepositoryImpl) FindVersionByMirrorIdAndChartVersion(mirrorId int, chartVersion string) (*ChartMirrorVersion, error) {
+	version := &ChartMirrorVersion{}
+	err := impl.dbConnection.Model(version).
+		Where("chart_mirror_id = ?", mirrorId).
+		Where("chart_version = ?", chartVersion).
+		Select()
+	return version, err
+}
+
+func (impl *ChartMirrorRepositoryImpl) FindMirroredVersionByAppStoreApplicationVersionId(appStoreApplicationVersionId int) (*ChartMirrorVersion, error) {
+	version := &ChartMirrorVersion{}
+	err := impl.dbConnection.Model(version).
+		Join("INNER JOIN chart_mirror cm ON cm.id = chart_mirror_version.chart_mirror_id").
+		Where("chart_mirror_version.app_store_application_version_id = ?", appStoreApplicationVersionId).
+		Where("chart_mirror_version.status = ?", bean.MirrorStatusMirrored).
+		Where("cm.active = ?", true).
+		Order("chart_mirror_version.mirrored_on DESC").
+		Limit(1).
+		Select()
+	return version, err
+}
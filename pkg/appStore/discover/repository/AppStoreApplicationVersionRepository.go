@@ -31,12 +31,18 @@ type AppStoreApplicationVersionRepository interface {
 	FindWithFilter(filter *appStoreBean.AppStoreFilter) ([]appStoreBean.AppStoreWithVersion, error)
 	FindById(id int) (*AppStoreApplicationVersion, error)
 	FindVersionsByAppStoreId(id int) ([]*AppStoreApplicationVersion, error)
+	// FindVersionsByAppStoreIds returns the id, version and deprecation of every version of the app stores
+	FindVersionsByAppStoreIds(appStoreIds []int) ([]*AppStoreApplicationVersion, error)
 	FindChartVersionByAppStoreId(id int) ([]*AppStoreApplicationVersion, error)
 	FindByIds(ids []int) ([]*AppStoreApplicationVersion, error)
 	GetChartInfoById(id int) (*AppStoreApplicationVersion, error)
+	// GetValuesAndSchemaById returns the default values and the values schema of the version
+	GetValuesAndSchemaById(id int) (*AppStoreApplicationVersion, error)
 	FindLatestVersionByAppStoreIdForChartRepo(id int) (int, error)
 	FindLatestVersionByAppStoreIdForOCIRepo(id int) (int, error)
 	SearchAppStoreChartByName(chartName string) ([]*appStoreBean.ChartRepoSearch, error)
+	// FindByChartNameAndVersion returns the versions of active charts with exactly the given name and version
+	FindByChartNameAndVersion(chartName, version string) ([]*appStoreBean.ChartRepoSearch, error)
 }
 
 type AppStoreApplicationVersionRepositoryImpl struct {
@@ -93,6 +99,13 @@ func (impl AppStoreApplicationVersionRepositoryImpl) GetChartInfoById(id int) (*
 	return &appStoreWithVersion, err
 }
 
+func (impl AppStoreApplicationVersionRepositoryImpl) GetValuesAndSchemaById(id int) (*AppStoreApplicationVersion, error) {
+	var appStoreWithVersion AppStoreApplicationVersion
+	err := impl.dbConnection.Model(&appStoreWithVersion).Column("id", "version", "app_store_id", "raw_values", "values_schema_json").
+		Where("id= ?", id).Select()
+	return &appStoreWithVersion, err
+}
+
 func updateFindWithFilterQuery(filter *appStoreBean.AppStoreFilter, updateAction FilterQueryUpdateAction) (string, []interface{}) {
 	query := ""
 	var queryParams []interface{}
@@ -255,6 +268,19 @@ func (impl AppStoreApplicationVersionRepositoryImpl) FindVersionsByAppStoreId(id
 	return appStoreApplicationVersions, err
 }
 
+func (impl AppStoreApplicationVersionRepositoryImpl) FindVersionsByAppStoreIds(appStoreIds []int) ([]*AppStoreApplicationVersion, error) {
+	var appStoreApplicationVersions []*AppStoreApplicationVersion
+	if len(appStoreIds) == 0 {
+		return appStoreApplicationVersions, nil
+	}
+	err := impl.dbConnection.
+		Model(&appStoreApplicationVersions).
+		Column("app_store_application_version.id", "app_store_application_version.version", "app_store_application_version.deprecated", "app_store_application_version.app_store_id").
+		Where("app_store_id in (?)", pg.In(appStoreIds)).
+		Select()
+	return appStoreApplicationVersions, err
+}
+
 func (impl *AppStoreApplicationVersionRepositoryImpl) FindLatestVersionByAppStoreIdForChartRepo(id int) (int, error) {
 	var appStoreApplicationVersionId int
 	queryTemp := "SELECT asv.id AS app_store_application_version_id  FROM app_store_application_version AS asv  JOIN app_store AS ap ON asv.app_store_id = ap.id WHERE ap.id = ? order by created desc limit 1;"
@@ -291,3 +317,20 @@ func (impl *AppStoreApplicationVersionRepositoryImpl) SearchAppStoreChartByName(
 	}
 	return chartRepos, err
 }
+
+func (impl *AppStoreApplicationVersionRepositoryImpl) FindByChartNameAndVersion(chartName, version string) ([]*appStoreBean.ChartRepoSearch, error) {
+	var chartRepos []*appStoreBean.ChartRepoSearch
+	query := `select asv.id as app_store_application_version_id, asv.version, asv.deprecated, aps.id as chart_id, 
+				aps.name as chart_name, chr.id as chart_repo_id, chr.name as chart_repo_name , das.id as docker_artifact_store_id 
+				from app_store_application_version asv 
+				inner join app_store aps on asv.app_store_id = aps.id 
+				left join chart_repo chr on aps.chart_repo_id = chr.id 
+				left join docker_artifact_store das on aps.docker_artifact_store_id = das.id 
+				where aps.name = ? and asv.version = ? and aps.active = true 
+				order by chr.name, das.id;`
+	_, err := impl.dbConnection.Query(&chartRepos, query, chartName, version)
+	if err != nil {
+		return nil, err
+	}
+	return chartRepos, nil
+}
@@ -128,7 +128,11 @@ func (impl *AppStoreDeploymentDBServiceImpl) AppStoreDeployOperationDB(installRe
 	// setting additional env data required in appStoreBean.InstallAppVersionDTO
 	appStoreAdapter.UpdateAdditionalEnvDetails(installRequest, environment)
 
-	impl.appStoreValidator.Validate(installRequest, environment)
+	err = impl.appStoreValidator.Validate(installRequest, environment)
+	if err != nil {
+		impl.logger.Errorw("error in validating install request", "appStoreVersion", installRequest.AppStoreVersion, "err", err)
+		return nil, err
+	}
 
 	// Stage 1:  Create App in tx (Only if AppId is not set already)
 	if installRequest.AppId == 0 {
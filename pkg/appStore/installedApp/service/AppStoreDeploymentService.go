@@ -578,6 +578,11 @@ func (impl *AppStoreDeploymentServiceImpl) GetDeploymentHistoryInfo(ctx context.
 }
 
 func (impl *AppStoreDeploymentServiceImpl) updateInstalledApp(ctx context.Context, upgradeAppRequest *appStoreBean.InstallAppVersionDTO, tx *pg.Tx) (*appStoreBean.InstallAppVersionDTO, error) {
+	err := impl.appStoreValidator.ValidateValues(upgradeAppRequest)
+	if err != nil {
+		impl.logger.Errorw("error in validating values of update request", "installedAppId", upgradeAppRequest.InstalledAppId, "err", err)
+		return nil, err
+	}
 	installedApp, err := impl.installedAppService.GetInstalledAppById(upgradeAppRequest.InstalledAppId)
 	if err != nil {
 		impl.logger.Errorw("error in fetching installed app by id", "installedAppId", upgradeAppRequest.InstalledAppId, "err", err)
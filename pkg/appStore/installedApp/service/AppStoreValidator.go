@@ -17,27 +17,63 @@
 package service
 
 import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
 	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
+	"github.com/devtron-labs/devtron/pkg/appStore/valuesSchema"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
 	"go.uber.org/zap"
+	"net/http"
+	"strings"
 )
 
 type AppStoreValidator interface {
 	Validate(installAppVersionRequest *appStoreBean.InstallAppVersionDTO, environment *bean.EnvironmentBean) error
+	// ValidateValues validates the values of the request against the values schema of the chart version
+	ValidateValues(installAppVersionRequest *appStoreBean.InstallAppVersionDTO) error
 }
 
 type AppStoreValidatorImpl struct {
-	logger *zap.SugaredLogger
+	logger              *zap.SugaredLogger
+	valuesSchemaService valuesSchema.ValuesSchemaService
 }
 
 func NewAppAppStoreValidatorImpl(
 	logger *zap.SugaredLogger,
+	valuesSchemaService valuesSchema.ValuesSchemaService,
 ) *AppStoreValidatorImpl {
 	return &AppStoreValidatorImpl{
-		logger: logger,
+		logger:              logger,
+		valuesSchemaService: valuesSchemaService,
 	}
 }
 
 func (impl *AppStoreValidatorImpl) Validate(installAppVersionRequest *appStoreBean.InstallAppVersionDTO, environment *bean.EnvironmentBean) error {
-	return nil
+	// linked helm releases keep the values they were deployed with
+	if installAppVersionRequest.IsChartLinkRequest {
+		return nil
+	}
+	return impl.ValidateValues(installAppVersionRequest)
+}
+
+func (impl *AppStoreValidatorImpl) ValidateValues(installAppVersionRequest *appStoreBean.InstallAppVersionDTO) error {
+	result, err := impl.valuesSchemaService.ValidateValues(installAppVersionRequest.AppStoreVersion, installAppVersionRequest.ValuesOverrideYaml)
+	if err != nil {
+		// a chart whose schema cannot be read is left to helm to validate
+		impl.logger.Warnw("could not validate values against values schema", "appStoreVersion", installAppVersionRequest.AppStoreVersion, "err", err)
+		return nil
+	}
+	if result.Valid || !result.Enforced {
+		return nil
+	}
+	messages := make([]string, 0, len(result.Errors))
+	for _, validationError := range result.Errors {
+		if len(validationError.Path) == 0 {
+			messages = append(messages, validationError.Message)
+		} else {
+			messages = append(messages, fmt.Sprintf("%s: %s", validationError.Path, validationError.Message))
+		}
+	}
+	message := fmt.Sprintf("values do not match the values schema of the chart, %s", strings.Join(messages, "; "))
+	return util.NewApiError(http.StatusBadRequest, message, message)
 }
@@ -26,6 +26,8 @@ import (
 	"github.com/devtron-labs/devtron/api/helm-app/service/read"
 	repository2 "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/timelineStatus"
+	chartMirrorBean "github.com/devtron-labs/devtron/pkg/appStore/chartMirror/bean"
+	chartMirrorRead "github.com/devtron-labs/devtron/pkg/appStore/chartMirror/read"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/bean"
 	appStoreDeploymentCommon "github.com/devtron-labs/devtron/pkg/appStore/installedApp/service/common"
 	util2 "github.com/devtron-labs/devtron/pkg/appStore/util"
@@ -65,6 +67,7 @@ type EAModeDeploymentServiceImpl struct {
 	helmAppClient               gRPC.HelmAppClient // TODO refactoring: use HelmAppService instead
 	installedAppRepository      repository.InstalledAppRepository
 	OCIRegistryConfigRepository repository2.OCIRegistryConfigRepository
+	chartMirrorReadService      chartMirrorRead.ChartMirrorReadService
 }
 
 func NewEAModeDeploymentServiceImpl(logger *zap.SugaredLogger, helmAppService client.HelmAppService,
@@ -74,6 +77,7 @@ func NewEAModeDeploymentServiceImpl(logger *zap.SugaredLogger, helmAppService cl
 	OCIRegistryConfigRepository repository2.OCIRegistryConfigRepository,
 	appStoreDeploymentCommonService appStoreDeploymentCommon.AppStoreDeploymentCommonService,
 	helmAppReadService read.HelmAppReadService,
+	chartMirrorReadService chartMirrorRead.ChartMirrorReadService,
 ) *EAModeDeploymentServiceImpl {
 	return &EAModeDeploymentServiceImpl{
 		Logger:                               logger,
@@ -84,6 +88,7 @@ func NewEAModeDeploymentServiceImpl(logger *zap.SugaredLogger, helmAppService cl
 		installedAppRepository:               installedAppRepository,
 		OCIRegistryConfigRepository:          OCIRegistryConfigRepository,
 		appStoreDeploymentCommonService:      appStoreDeploymentCommonService,
+		chartMirrorReadService:               chartMirrorReadService,
 	}
 }
 
@@ -106,8 +111,17 @@ func (impl *EAModeDeploymentServiceImpl) InstallApp(installAppVersionRequest *ap
 	var IsOCIRepo bool
 	var registryCredential *gRPC.RegistryCredential
 	var chartRepository *gRPC.ChartRepository
+	mirroredChart, err := impl.chartMirrorReadService.GetMirroredChart(appStoreAppVersion.Id)
+	if err != nil {
+		impl.Logger.Errorw("error in fetching mirrored chart", "appStoreApplicationVersionId", appStoreAppVersion.Id, "err", err)
+		return nil, err
+	}
 	dockerRegistryId := appStoreAppVersion.AppStore.DockerArtifactStoreId
-	if dockerRegistryId != "" {
+	if mirroredChart != nil {
+		// charts mirrored to an internal registry are pulled from it, the source may not be reachable from the cluster
+		IsOCIRepo = true
+		registryCredential = getMirroredChartRegistryCredential(mirroredChart)
+	} else if dockerRegistryId != "" {
 		ociRegistryConfigs, err := impl.OCIRegistryConfigRepository.FindByDockerRegistryId(dockerRegistryId)
 		if err != nil {
 			impl.Logger.Errorw("error in fetching oci registry config", "err", err)
@@ -338,8 +352,16 @@ func (impl *EAModeDeploymentServiceImpl) updateApplicationWithChartInfo(ctx cont
 	var IsOCIRepo bool
 	var registryCredential *gRPC.RegistryCredential
 	var chartRepository *gRPC.ChartRepository
+	mirroredChart, err := impl.chartMirrorReadService.GetMirroredChart(appStoreApplicationVersion.Id)
+	if err != nil {
+		impl.Logger.Errorw("error in fetching mirrored chart", "appStoreApplicationVersionId", appStoreApplicationVersion.Id, "err", err)
+		return err
+	}
 	dockerRegistryId := appStoreApplicationVersion.AppStore.DockerArtifactStoreId
-	if dockerRegistryId != "" {
+	if mirroredChart != nil {
+		IsOCIRepo = true
+		registryCredential = getMirroredChartRegistryCredential(mirroredChart)
+	} else if dockerRegistryId != "" {
 		ociRegistryConfigs, err := impl.OCIRegistryConfigRepository.FindByDockerRegistryId(dockerRegistryId)
 		if err != nil {
 			impl.Logger.Errorw("error in fetching oci registry config", "err", err)
@@ -477,3 +499,20 @@ func (impl *EAModeDeploymentServiceImpl) GetAcdAppGitOpsRepoURL(appName string,
 func (impl *EAModeDeploymentServiceImpl) CreateArgoRepoSecretIfNeeded(appStoreApplicationVersion *appStoreDiscoverRepository.AppStoreApplicationVersion) error {
 	return errors.New("this is not implemented")
 }
+
+func getMirroredChartRegistryCredential(mirroredChart *chartMirrorBean.MirroredChart) *gRPC.RegistryCredential {
+	return &gRPC.RegistryCredential{
+		RegistryUrl:         mirroredChart.Registry.RegistryURL,
+		Username:            mirroredChart.Registry.Username,
+		Password:            mirroredChart.Registry.Password,
+		AwsRegion:           mirroredChart.Registry.AWSRegion,
+		AccessKey:           mirroredChart.Registry.AWSAccessKeyId,
+		SecretKey:           mirroredChart.Registry.AWSSecretAccessKey,
+		RegistryType:        string(mirroredChart.Registry.RegistryType),
+		RepoName:            mirroredChart.Repository,
+		IsPublic:            mirroredChart.IsPublic,
+		Connection:          mirroredChart.Registry.Connection,
+		RegistryName:        mirroredChart.Registry.Id,
+		RegistryCertificate: mirroredChart.Registry.Cert,
+	}
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/app/status"
 	"github.com/devtron-labs/devtron/pkg/appStatus"
+	adoptionRepository "github.com/devtron-labs/devtron/pkg/appStore/adoption/repository"
 	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
 	repository2 "github.com/devtron-labs/devtron/pkg/appStore/chartGroup/repository"
 	appStoreDiscoverRepository "github.com/devtron-labs/devtron/pkg/appStore/discover/repository"
@@ -94,6 +95,7 @@ type FullModeDeploymentServiceImpl struct {
 	environmentRepository                repository5.EnvironmentRepository
 	deploymentConfigService              common.DeploymentConfigService
 	chartTemplateService                 util.ChartTemplateService
+	helmReleaseAdoptionRepository        adoptionRepository.HelmReleaseAdoptionRepository
 }
 
 func NewFullModeDeploymentServiceImpl(
@@ -117,7 +119,8 @@ func NewFullModeDeploymentServiceImpl(
 	gitOpsValidationService validation.GitOpsValidationService,
 	environmentRepository repository5.EnvironmentRepository,
 	deploymentConfigService common.DeploymentConfigService,
-	chartTemplateService util.ChartTemplateService) *FullModeDeploymentServiceImpl {
+	chartTemplateService util.ChartTemplateService,
+	helmReleaseAdoptionRepository adoptionRepository.HelmReleaseAdoptionRepository) *FullModeDeploymentServiceImpl {
 	return &FullModeDeploymentServiceImpl{
 		Logger:                               logger,
 		argoK8sClient:                        argoK8sClient,
@@ -140,6 +143,7 @@ func NewFullModeDeploymentServiceImpl(
 		environmentRepository:                environmentRepository,
 		deploymentConfigService:              deploymentConfigService,
 		chartTemplateService:                 chartTemplateService,
+		helmReleaseAdoptionRepository:        helmReleaseAdoptionRepository,
 	}
 }
 
@@ -153,7 +157,11 @@ func (impl *FullModeDeploymentServiceImpl) InstallApp(installAppVersionRequest *
 		return nil, err
 	}
 	//STEP 5: createInArgo
-	err = impl.createInArgo(ctx, chartGitAttr, *installAppVersionRequest.Environment, installAppVersionRequest.ACDAppName)
+	releaseName, err := impl.getAdoptedReleaseName(installAppVersionRequest.InstalledAppId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.createInArgo(ctx, chartGitAttr, *installAppVersionRequest.Environment, installAppVersionRequest.ACDAppName, releaseName)
 	if err != nil {
 		impl.Logger.Errorw("error in create in argo", "err", err)
 		return nil, err
@@ -164,7 +164,23 @@ func (impl *FullModeDeploymentServiceImpl) DeleteACD(acdAppName string, ctx cont
 	return nil
 }
 
-func (impl *FullModeDeploymentServiceImpl) createInArgo(ctx context.Context, chartGitAttribute *commonBean.ChartGitAttribute, envModel bean.EnvironmentBean, argocdAppName string) error {
+// getAdoptedReleaseName returns the name of the external helm release the installed app was adopted from, releases
+// adopted into argo cd keep being rendered with it so that the names of their resources do not change
+func (impl *FullModeDeploymentServiceImpl) getAdoptedReleaseName(installedAppId int) (string, error) {
+	if installedAppId == 0 {
+		return "", nil
+	}
+	adoption, err := impl.helmReleaseAdoptionRepository.FindByInstalledAppId(installedAppId)
+	if util.IsErrNoRows(err) {
+		return "", nil
+	} else if err != nil {
+		impl.Logger.Errorw("error in getting release adoption", "installedAppId", installedAppId, "err", err)
+		return "", err
+	}
+	return adoption.ReleaseName, nil
+}
+
+func (impl *FullModeDeploymentServiceImpl) createInArgo(ctx context.Context, chartGitAttribute *commonBean.ChartGitAttribute, envModel bean.EnvironmentBean, argocdAppName, releaseName string) error {
 	appNamespace := envModel.Namespace
 	if appNamespace == "" {
 		appNamespace = bean2.DefaultNamespace
@@ -179,6 +195,7 @@ func (impl *FullModeDeploymentServiceImpl) createInArgo(ctx context.Context, cha
 		RepoPath:        chartGitAttribute.ChartLocation,
 		RepoUrl:         chartGitAttribute.RepoUrl,
 		AutoSyncEnabled: impl.acdConfig.ArgoCDAutoSyncEnabled,
+		ReleaseName:     releaseName,
 	}
 	_, err := impl.argoK8sClient.CreateAcdApp(ctx, appReq, argocdServer.ARGOCD_APPLICATION_TEMPLATE)
 	//create
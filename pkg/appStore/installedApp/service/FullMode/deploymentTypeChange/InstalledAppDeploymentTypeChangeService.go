@@ -31,6 +31,8 @@ import (
 	appRepository "github.com/devtron-labs/devtron/internal/sql/repository/app"
 	appStatus2 "github.com/devtron-labs/devtron/internal/sql/repository/appStatus"
 	"github.com/devtron-labs/devtron/internal/util"
+	adoptionBean "github.com/devtron-labs/devtron/pkg/appStore/adoption/bean"
+	adoptionRepository "github.com/devtron-labs/devtron/pkg/appStore/adoption/repository"
 	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
 	"github.com/devtron-labs/devtron/pkg/appStore/chartGroup"
 	repository2 "github.com/devtron-labs/devtron/pkg/appStore/installedApp/repository"
@@ -52,6 +54,7 @@ import (
 	util3 "github.com/devtron-labs/devtron/util"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"net/http"
@@ -63,6 +66,9 @@ type InstalledAppDeploymentTypeChangeService interface {
 	MigrateDeploymentType(ctx context.Context, request *bean.DeploymentAppTypeChangeRequest) (*bean.DeploymentAppTypeChangeResponse, error)
 	// TriggerAfterMigration triggers all the installed apps for which the deployment types were migrated via MigrateDeploymentType
 	TriggerAfterMigration(ctx context.Context, request *bean.DeploymentAppTypeChangeRequest) (*bean.DeploymentAppTypeChangeResponse, error)
+	// MigrateAdoptedReleaseToArgoCd switches an adopted helm release to GitOps without uninstalling it, so that argo-cd
+	// takes over the live resources of the release instead of recreating them
+	MigrateAdoptedReleaseToArgoCd(ctx context.Context, installedAppId int, userId int32) (*bean.DeploymentAppTypeChangeResponse, error)
 }
 
 type InstalledAppDeploymentTypeChangeServiceImpl struct {
@@ -84,6 +90,7 @@ type InstalledAppDeploymentTypeChangeServiceImpl struct {
 	clusterReadService            read.ClusterReadService
 	deploymentConfigService       common.DeploymentConfigService
 	argoApplicationService        argoApplication.ArgoApplicationService
+	helmReleaseAdoptionRepository adoptionRepository.HelmReleaseAdoptionRepository
 }
 
 func NewInstalledAppDeploymentTypeChangeServiceImpl(logger *zap.SugaredLogger,
@@ -101,7 +108,8 @@ func NewInstalledAppDeploymentTypeChangeServiceImpl(logger *zap.SugaredLogger,
 	clusterReadService read.ClusterReadService,
 	appRepository appRepository.AppRepository,
 	deploymentConfigService common.DeploymentConfigService,
-	argoApplicationService argoApplication.ArgoApplicationService) *InstalledAppDeploymentTypeChangeServiceImpl {
+	argoApplicationService argoApplication.ArgoApplicationService,
+	helmReleaseAdoptionRepository adoptionRepository.HelmReleaseAdoptionRepository) *InstalledAppDeploymentTypeChangeServiceImpl {
 	return &InstalledAppDeploymentTypeChangeServiceImpl{
 		logger:                        logger,
 		installedAppRepository:        installedAppRepository,
@@ -121,6 +129,7 @@ func NewInstalledAppDeploymentTypeChangeServiceImpl(logger *zap.SugaredLogger,
 		appRepository:                 appRepository,
 		deploymentConfigService:       deploymentConfigService,
 		argoApplicationService:        argoApplicationService,
+		helmReleaseAdoptionRepository: helmReleaseAdoptionRepository,
 	}
 }
 
@@ -665,3 +674,91 @@ func appendToDeploymentChangeStatusList(installedApps []*bean.DeploymentChangeSt
 		Status:         status,
 	})
 }
+
+func (impl *InstalledAppDeploymentTypeChangeServiceImpl) MigrateAdoptedReleaseToArgoCd(ctx context.Context, installedAppId int, userId int32) (*bean.DeploymentAppTypeChangeResponse, error) {
+	adoption, err := impl.helmReleaseAdoptionRepository.FindByInstalledAppId(installedAppId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching helm release adoption", "installedAppId", installedAppId, "err", err)
+		return nil, err
+	} else if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "installed app was not adopted from an external helm release", "helm release adoption not found")
+	}
+	installedApps, err := impl.installedAppRepository.FindInstalledAppByIds([]int{installedAppId})
+	if err != nil {
+		impl.logger.Errorw("error in fetching installed app", "installedAppId", installedAppId, "err", err)
+		return nil, err
+	} else if len(installedApps) == 0 {
+		return nil, util.NewApiError(http.StatusNotFound, "installed app not found", "installed app not found")
+	}
+	installedApp := installedApps[0]
+	response := &bean.DeploymentAppTypeChangeResponse{
+		EnvId:                 installedApp.EnvironmentId,
+		DesiredDeploymentType: bean2.ArgoCd,
+	}
+	if installedApp.DeploymentAppType != bean2.Helm {
+		return response, util.NewApiError(http.StatusBadRequest, "only helm deployed apps can be migrated to gitops", "installed app is not of helm deployment type")
+	}
+	gitOpsConfigurationStatus, err := impl.gitOpsConfigReadService.IsGitOpsConfigured()
+	if err != nil {
+		impl.logger.Errorw("error in checking gitops configuration", "err", err)
+		return response, err
+	} else if !gitOpsConfigurationStatus.IsGitOpsConfiguredAndArgoCdInstalled() {
+		return response, util.NewApiError(http.StatusPreconditionFailed, "gitops is not configured or argo-cd is not installed", "gitops not configured")
+	}
+	isClusterReachable, err := impl.clusterReadService.IsClusterReachable(adoption.ClusterId)
+	if err != nil {
+		return response, err
+	}
+	if !isClusterReachable {
+		return response, &util.ApiError{HttpStatusCode: http.StatusUnprocessableEntity, InternalMessage: "cluster unreachable", UserMessage: "cluster unreachable"}
+	}
+
+	//helm uninstall would delete the live resources of the release, so only the revisions stored by helm are removed
+	//and argo-cd adopts the resources left in the cluster as the application uses the release name of the adopted release
+	_, v1Client, err := impl.k8sCommonService.GetCoreClientByClusterId(adoption.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting core client by cluster id", "clusterId", adoption.ClusterId, "err", err)
+		return response, err
+	}
+	labelSelector := fmt.Sprintf("%s=%s,%s=%s", adoptionBean.HelmStorageOwnerLabel, adoptionBean.HelmStorageOwner, adoptionBean.HelmStorageNameLabel, adoption.ReleaseName)
+	err = v1Client.Secrets(adoption.Namespace).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		impl.logger.Errorw("error in deleting helm release storage secrets", "releaseName", adoption.ReleaseName, "namespace", adoption.Namespace, "err", err)
+		return response, err
+	}
+
+	appId := installedApp.AppId
+	err = impl.performDbOperationsAfterMigrations(bean2.ArgoCd, []int{installedAppId}, []*int{&appId}, userId, int(appStoreBean.DEPLOY_INIT))
+	if err != nil {
+		impl.logger.Errorw("error in performing db operations after migrating adopted release", "installedAppId", installedAppId, "err", err)
+		return response, err
+	}
+	adoption.Status = adoptionBean.AdoptionStatusGitOpsMigrationInitiated
+	adoption.Message = ""
+	adoption.UpdateAuditLog(userId)
+	err = impl.helmReleaseAdoptionRepository.Update(adoption)
+	if err != nil {
+		impl.logger.Errorw("error in updating helm release adoption", "installedAppId", installedAppId, "err", err)
+		return response, err
+	}
+
+	response, err = impl.TriggerAfterMigration(ctx, &bean.DeploymentAppTypeChangeRequest{
+		EnvId:                 installedApp.EnvironmentId,
+		DesiredDeploymentType: bean2.ArgoCd,
+		IncludeApps:           []int{installedApp.AppId},
+		UserId:                userId,
+	})
+	adoption.Status = adoptionBean.AdoptionStatusGitOpsMigrated
+	if err != nil {
+		adoption.Status = adoptionBean.AdoptionStatusGitOpsMigrationFailed
+		adoption.Message = err.Error()
+	} else if len(response.FailedPipelines) > 0 {
+		adoption.Status = adoptionBean.AdoptionStatusGitOpsMigrationFailed
+		adoption.Message = response.FailedPipelines[0].Error
+	}
+	adoption.UpdateAuditLog(userId)
+	if updateErr := impl.helmReleaseAdoptionRepository.Update(adoption); updateErr != nil {
+		impl.logger.Errorw("error in updating helm release adoption status", "installedAppId", installedAppId, "err", updateErr)
+	}
+	return response, err
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
 	"github.com/devtron-labs/devtron/internal/util"
 	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
+	chartMirrorRead "github.com/devtron-labs/devtron/pkg/appStore/chartMirror/read"
 	appStoreDiscoverRepository "github.com/devtron-labs/devtron/pkg/appStore/discover/repository"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/adapter"
 	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/repository"
@@ -63,6 +64,7 @@ type AppStoreDeploymentCommonServiceImpl struct {
 	helmAppService                       service.HelmAppService
 	userService                          user.UserService
 	installedAppDBService                EAMode.InstalledAppDBService
+	chartMirrorReadService               chartMirrorRead.ChartMirrorReadService
 }
 
 func NewAppStoreDeploymentCommonServiceImpl(
@@ -72,6 +74,7 @@ func NewAppStoreDeploymentCommonServiceImpl(
 	userService user.UserService,
 	helmAppService service.HelmAppService,
 	installedAppDBService EAMode.InstalledAppDBService,
+	chartMirrorReadService chartMirrorRead.ChartMirrorReadService,
 ) *AppStoreDeploymentCommonServiceImpl {
 	return &AppStoreDeploymentCommonServiceImpl{
 		logger:                               logger,
@@ -80,6 +83,7 @@ func NewAppStoreDeploymentCommonServiceImpl(
 		userService:                          userService,
 		helmAppService:                       helmAppService,
 		installedAppDBService:                installedAppDBService,
+		chartMirrorReadService:               chartMirrorReadService,
 	}
 }
 func (impl *AppStoreDeploymentCommonServiceImpl) GetDeploymentHistoryFromDB(ctx context.Context, installedApp *appStoreBean.InstallAppVersionDTO) (*gRPC.HelmAppDeploymentHistory, error) {
@@ -207,7 +211,20 @@ func (impl AppStoreDeploymentCommonServiceImpl) GetRequirementsString(appStoreAp
 		Name:    GetChartNameFromAppStoreApplicationVersion(appStoreAppVersion),
 		Version: appStoreAppVersion.Version,
 	}
-	if appStoreAppVersion.AppStore.ChartRepo != nil {
+	mirroredChart, err := impl.chartMirrorReadService.GetMirroredChart(appStoreAppVersion.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting mirrored chart", "appStoreApplicationVersionId", appStoreAppVersion.Id, "err", err)
+		return "", err
+	}
+	if mirroredChart != nil {
+		// the dependency is pointed to the internal registry the chart is mirrored to
+		repositoryURL, repositoryName, err := sanitizeRepoNameAndURLForOCIRepo(mirroredChart.Registry.RegistryURL, mirroredChart.Repository)
+		if err != nil {
+			impl.logger.Errorw("error in getting sanitized repository name and url", "repositoryURL", repositoryURL, "repositoryName", repositoryName, "err", err)
+			return "", err
+		}
+		dependency.Repository = repositoryURL
+	} else if appStoreAppVersion.AppStore.ChartRepo != nil {
 		dependency.Repository = appStoreAppVersion.AppStore.ChartRepo.Url
 	} else if appStoreAppVersion.AppStore.DockerArtifactStore != nil {
 		repositoryURL, repositoryName, err := sanitizeRepoNameAndURLForOCIRepo(appStoreAppVersion.AppStore.DockerArtifactStore.RegistryURL, appStoreAppVersion.AppStore.Name)
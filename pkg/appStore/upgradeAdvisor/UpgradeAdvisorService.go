@@ -0,0 +1,339 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgradeAdvisor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	helmAppService "github.com/devtron-labs/devtron/api/helm-app/service"
+	openapi2 "github.com/devtron-labs/devtron/api/openapi/openapiClient"
+	"github.com/devtron-labs/devtron/internal/util"
+	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
+	appStoreDiscoverRepository "github.com/devtron-labs/devtron/pkg/appStore/discover/repository"
+	installedAppRepository "github.com/devtron-labs/devtron/pkg/appStore/installedApp/repository"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/adapter"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/bean"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/helper"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/repository"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cronUtil "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type UpgradeAdvisorConfig struct {
+	RefreshIntervalMins int `env:"CHART_UPGRADE_ADVISOR_INTERVAL_MINS" envDefault:"360" description:"Interval at which the installed chart store apps are compared with the synced chart versions for upgrades, 0 disables the upgrade advisor"`
+}
+
+func GetUpgradeAdvisorConfig() (*UpgradeAdvisorConfig, error) {
+	cfg := &UpgradeAdvisorConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+type UpgradeAdvisorService interface {
+	// RefreshAdvisories compares every installed chart store app with the versions of its chart synced from the chart repositories
+	RefreshAdvisories()
+	RefreshAdvisory(installedAppId int, userId int32) error
+	GetAdvisories(filter *bean.AdvisoryFilter) ([]*bean.UpgradeAdvisory, error)
+	// GetAdvisory returns the advisory of the installed app, computed now if the advisor has not yet run for it
+	GetAdvisory(installedAppId int, userId int32) (*bean.UpgradeAdvisory, error)
+	// GetManifestDiff renders the installed app with the chart version to upgrade to and diffs it with the deployed version
+	GetManifestDiff(ctx context.Context, request *bean.ManifestDiffRequest) (*bean.ManifestDiff, error)
+}
+
+type UpgradeAdvisorServiceImpl struct {
+	logger                               *zap.SugaredLogger
+	repository                           repository.ChartUpgradeAdvisoryRepository
+	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository
+	installedAppRepository               installedAppRepository.InstalledAppRepository
+	helmAppService                       helmAppService.HelmAppService
+}
+
+func NewUpgradeAdvisorServiceImpl(logger *zap.SugaredLogger,
+	repository repository.ChartUpgradeAdvisoryRepository,
+	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository,
+	installedAppRepository installedAppRepository.InstalledAppRepository,
+	helmAppService helmAppService.HelmAppService,
+	config *UpgradeAdvisorConfig,
+	cronLogger *cronUtil.CronLoggerImpl) (*UpgradeAdvisorServiceImpl, error) {
+	impl := &UpgradeAdvisorServiceImpl{
+		logger:                               logger,
+		repository:                           repository,
+		appStoreApplicationVersionRepository: appStoreApplicationVersionRepository,
+		installedAppRepository:               installedAppRepository,
+		helmAppService:                       helmAppService,
+	}
+	if config.RefreshIntervalMins > 0 {
+		advisorCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+		_, err := advisorCron.AddFunc(fmt.Sprintf("@every %dm", config.RefreshIntervalMins), impl.RefreshAdvisories)
+		if err != nil {
+			logger.Errorw("error in adding chart upgrade advisor cron", "err", err)
+			return nil, err
+		}
+		advisorCron.Start()
+	}
+	return impl, nil
+}
+
+func (impl *UpgradeAdvisorServiceImpl) RefreshAdvisories() {
+	charts, err := impl.repository.GetActiveInstalledAppCharts()
+	if err != nil {
+		return
+	}
+	if err = impl.refreshAdvisories(charts, 1); err != nil {
+		impl.logger.Errorw("error in refreshing chart upgrade advisories", "err", err)
+		return
+	}
+	if err = impl.repository.DeleteInactive(); err != nil {
+		impl.logger.Errorw("error in deleting advisories of deleted installed apps", "err", err)
+	}
+}
+
+func (impl *UpgradeAdvisorServiceImpl) RefreshAdvisory(installedAppId int, userId int32) error {
+	chart, err := impl.repository.GetActiveInstalledAppChart(installedAppId)
+	if util.IsErrNoRows(err) {
+		return util.NewApiError(http.StatusNotFound, "installed app not found", err.Error())
+	} else if err != nil {
+		impl.logger.Errorw("error in getting installed app chart", "installedAppId", installedAppId, "err", err)
+		return err
+	}
+	return impl.refreshAdvisories([]*repository.InstalledAppChart{chart}, userId)
+}
+
+type pendingAdvisory struct {
+	chart       *repository.InstalledAppChart
+	latest      *bean.ChartVersion
+	upgradeType bean.UpgradeType
+	err         error
+}
+
+func (impl *UpgradeAdvisorServiceImpl) refreshAdvisories(charts []*repository.InstalledAppChart, userId int32) error {
+	if len(charts) == 0 {
+		return nil
+	}
+	appStoreIds := make([]int, 0, len(charts))
+	seenAppStoreIds := make(map[int]bool, len(charts))
+	for _, chart := range charts {
+		if !seenAppStoreIds[chart.AppStoreId] {
+			seenAppStoreIds[chart.AppStoreId] = true
+			appStoreIds = append(appStoreIds, chart.AppStoreId)
+		}
+	}
+	versions, err := impl.appStoreApplicationVersionRepository.FindVersionsByAppStoreIds(appStoreIds)
+	if err != nil {
+		impl.logger.Errorw("error in getting chart versions", "appStoreIds", appStoreIds, "err", err)
+		return err
+	}
+	versionsByAppStoreId := make(map[int][]*bean.ChartVersion, len(appStoreIds))
+	versionById := make(map[int]string, len(versions))
+	for _, version := range versions {
+		versionById[version.Id] = version.Version
+		versionsByAppStoreId[version.AppStoreId] = append(versionsByAppStoreId[version.AppStoreId], &bean.ChartVersion{
+			AppStoreApplicationVersionId: version.Id,
+			Version:                      version.Version,
+			Deprecated:                   version.Deprecated,
+		})
+	}
+
+	// the default values and schemas are loaded only for the versions an upgrade is advised between
+	pending := make([]*pendingAdvisory, 0, len(charts))
+	var detailIds []int
+	for _, chart := range charts {
+		advisory := &pendingAdvisory{chart: chart}
+		advisory.latest, advisory.upgradeType, advisory.err = helper.GetLatestVersion(versionById[chart.AppStoreApplicationVersionId], versionsByAppStoreId[chart.AppStoreId])
+		if advisory.latest != nil {
+			detailIds = append(detailIds, chart.AppStoreApplicationVersionId, advisory.latest.AppStoreApplicationVersionId)
+		}
+		pending = append(pending, advisory)
+	}
+	details, err := impl.appStoreApplicationVersionRepository.FindByIds(detailIds)
+	if err != nil {
+		impl.logger.Errorw("error in getting chart versions", "ids", detailIds, "err", err)
+		return err
+	}
+	detailById := make(map[int]*appStoreDiscoverRepository.AppStoreApplicationVersion, len(details))
+	for _, detail := range details {
+		detailById[detail.Id] = detail
+	}
+
+	computedOn := time.Now()
+	for _, advisory := range pending {
+		model := &repository.ChartUpgradeAdvisory{
+			InstalledAppId:                      advisory.chart.InstalledAppId,
+			InstalledAppVersionId:               advisory.chart.InstalledAppVersionId,
+			CurrentAppStoreApplicationVersionId: advisory.chart.AppStoreApplicationVersionId,
+			CurrentVersion:                      versionById[advisory.chart.AppStoreApplicationVersionId],
+			UpgradeType:                         advisory.upgradeType,
+			ComputedOn:                          computedOn,
+			AuditLog:                            sql.NewDefaultAuditLog(userId),
+		}
+		if advisory.err != nil {
+			model.Message = advisory.err.Error()
+		}
+		if advisory.latest != nil {
+			model.LatestAppStoreApplicationVersionId = advisory.latest.AppStoreApplicationVersionId
+			model.LatestVersion = advisory.latest.Version
+			impl.setValuesDiff(model, advisory.chart, detailById)
+		}
+		if err = impl.repository.Upsert(model); err != nil {
+			impl.logger.Errorw("error in saving chart upgrade advisory", "installedAppId", model.InstalledAppId, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *UpgradeAdvisorServiceImpl) setValuesDiff(model *repository.ChartUpgradeAdvisory, chart *repository.InstalledAppChart,
+	detailById map[int]*appStoreDiscoverRepository.AppStoreApplicationVersion) {
+	current, latest := detailById[model.CurrentAppStoreApplicationVersionId], detailById[model.LatestAppStoreApplicationVersionId]
+	if current == nil || latest == nil {
+		return
+	}
+	valuesDiff, err := helper.GetValuesDiff(current.RawValues, latest.RawValues, chart.ValuesYaml, current.ValuesSchemaJson, latest.ValuesSchemaJson)
+	if err != nil {
+		impl.logger.Warnw("could not diff values for chart upgrade", "installedAppId", model.InstalledAppId, "err", err)
+		model.Message = err.Error()
+		return
+	}
+	valuesDiffJson, err := json.Marshal(valuesDiff)
+	if err != nil {
+		model.Message = err.Error()
+		return
+	}
+	model.ValuesDiff = string(valuesDiffJson)
+}
+
+func (impl *UpgradeAdvisorServiceImpl) GetAdvisories(filter *bean.AdvisoryFilter) ([]*bean.UpgradeAdvisory, error) {
+	if len(filter.UpgradeTypes) == 0 {
+		filter.UpgradeTypes = []bean.UpgradeType{bean.UpgradeTypePatch, bean.UpgradeTypeMinor, bean.UpgradeTypeMajor}
+	}
+	details, err := impl.repository.FindAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	advisories := make([]*bean.UpgradeAdvisory, 0, len(details))
+	for _, detail := range details {
+		advisory, err := adapter.GetUpgradeAdvisory(detail)
+		if err != nil {
+			impl.logger.Errorw("error in reading chart upgrade advisory", "installedAppId", detail.InstalledAppId, "err", err)
+			return nil, err
+		}
+		advisories = append(advisories, advisory)
+	}
+	return advisories, nil
+}
+
+func (impl *UpgradeAdvisorServiceImpl) GetAdvisory(installedAppId int, userId int32) (*bean.UpgradeAdvisory, error) {
+	detail, err := impl.repository.FindByInstalledAppId(installedAppId)
+	if util.IsErrNoRows(err) {
+		if err = impl.RefreshAdvisory(installedAppId, userId); err != nil {
+			return nil, err
+		}
+		detail, err = impl.repository.FindByInstalledAppId(installedAppId)
+	}
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "installed app not found", err.Error())
+	} else if err != nil {
+		impl.logger.Errorw("error in getting chart upgrade advisory", "installedAppId", installedAppId, "err", err)
+		return nil, err
+	}
+	return adapter.GetUpgradeAdvisory(detail)
+}
+
+func (impl *UpgradeAdvisorServiceImpl) GetManifestDiff(ctx context.Context, request *bean.ManifestDiffRequest) (*bean.ManifestDiff, error) {
+	installedAppVersion, err := impl.installedAppRepository.GetActiveInstalledAppVersionByInstalledAppId(request.InstalledAppId)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "installed app not found", err.Error())
+	} else if err != nil {
+		impl.logger.Errorw("error in getting installed app version", "installedAppId", request.InstalledAppId, "err", err)
+		return nil, err
+	}
+	targetVersionId := request.AppStoreApplicationVersionId
+	if targetVersionId == 0 {
+		advisory, err := impl.GetAdvisory(request.InstalledAppId, 1)
+		if err != nil {
+			return nil, err
+		}
+		if advisory.LatestAppStoreApplicationVersionId == 0 {
+			return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("%s is on the latest version of its chart", advisory.AppName), "no upgrade available")
+		}
+		targetVersionId = advisory.LatestAppStoreApplicationVersionId
+	}
+	targetVersion, err := impl.appStoreApplicationVersionRepository.FindById(targetVersionId)
+	if util.IsErrNoRows(err) || err == nil && targetVersion.AppStoreId != installedAppVersion.AppStoreApplicationVersion.AppStoreId {
+		return nil, util.NewApiError(http.StatusBadRequest, "chart version to upgrade to is not a version of the installed chart", fmt.Sprintf("invalid appStoreApplicationVersionId %d", targetVersionId))
+	} else if err != nil {
+		impl.logger.Errorw("error in getting chart version", "appStoreApplicationVersionId", targetVersionId, "err", err)
+		return nil, err
+	}
+	valuesYaml := request.ValuesYaml
+	if len(valuesYaml) == 0 {
+		valuesYaml = installedAppVersion.ValuesYaml
+	}
+	fromManifest, err := impl.templateChart(ctx, installedAppVersion, installedAppVersion.AppStoreApplicationVersionId, installedAppVersion.ValuesYaml)
+	if err != nil {
+		return nil, err
+	}
+	toManifest, err := impl.templateChart(ctx, installedAppVersion, targetVersionId, valuesYaml)
+	if err != nil {
+		return nil, err
+	}
+	manifestDiff, err := helper.GetManifestDiff(fromManifest, toManifest, installedAppVersion.AppStoreApplicationVersion.Version, targetVersion.Version)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusUnprocessableEntity, "could not diff the rendered manifests", err.Error())
+	}
+	return manifestDiff, nil
+}
+
+// templateChart renders the installed app with the chart version and values, like helm template
+func (impl *UpgradeAdvisorServiceImpl) templateChart(ctx context.Context, installedAppVersion *installedAppRepository.InstalledAppVersions,
+	appStoreApplicationVersionId int, valuesYaml string) (string, error) {
+	installedApp := installedAppVersion.InstalledApp
+	releaseName := installedApp.App.AppName
+	if len(installedApp.App.DisplayName) > 0 {
+		releaseName = installedApp.App.DisplayName
+	}
+	envId := int32(installedApp.EnvironmentId)
+	clusterId := int32(installedApp.Environment.ClusterId)
+	namespace := installedApp.Environment.Namespace
+	// as virtual environment doesn't exist on actual cluster, default cluster is used for rendering
+	if installedApp.Environment.IsVirtualEnvironment {
+		envId, clusterId = 0, clusterBean.DefaultClusterId
+		namespace = appStoreBean.DEFAULT_NAMESPACE
+	}
+	versionId := int32(appStoreApplicationVersionId)
+	request := &openapi2.TemplateChartRequest{
+		EnvironmentId:                &envId,
+		ClusterId:                    &clusterId,
+		Namespace:                    &namespace,
+		ReleaseName:                  &releaseName,
+		AppStoreApplicationVersionId: &versionId,
+		ValuesYaml:                   &valuesYaml,
+	}
+	response, err := impl.helmAppService.TemplateChart(ctx, request)
+	if err != nil {
+		impl.logger.Errorw("error in templating chart for upgrade diff", "installedAppId", installedApp.Id, "appStoreApplicationVersionId", appStoreApplicationVersionId, "err", err)
+		return "", err
+	}
+	return response.GetManifest(), nil
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/bean"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/repository"
+)
+
+func GetUpgradeAdvisory(detail *repository.ChartUpgradeAdvisoryDetail) (*bean.UpgradeAdvisory, error) {
+	appName := detail.AppName
+	if len(detail.DisplayName) > 0 {
+		appName = detail.DisplayName
+	}
+	advisory := &bean.UpgradeAdvisory{
+		InstalledAppId:                      detail.InstalledAppId,
+		InstalledAppVersionId:               detail.InstalledAppVersionId,
+		AppName:                             appName,
+		EnvironmentId:                       detail.EnvironmentId,
+		EnvironmentName:                     detail.EnvironmentName,
+		Namespace:                           detail.Namespace,
+		ClusterId:                           detail.ClusterId,
+		AppStoreId:                          detail.AppStoreId,
+		ChartName:                           detail.ChartName,
+		CurrentVersion:                      detail.CurrentVersion,
+		CurrentAppStoreApplicationVersionId: detail.CurrentAppStoreApplicationVersionId,
+		LatestVersion:                       detail.LatestVersion,
+		LatestAppStoreApplicationVersionId:  detail.LatestAppStoreApplicationVersionId,
+		UpgradeType:                         detail.UpgradeType,
+		Message:                             detail.Message,
+		ComputedOn:                          detail.ComputedOn,
+		AppOfferingMode:                     detail.AppOfferingMode,
+	}
+	if len(detail.ValuesDiff) > 0 {
+		advisory.ValuesDiff = &bean.ValuesDiff{}
+		if err := json.Unmarshal([]byte(detail.ValuesDiff), advisory.ValuesDiff); err != nil {
+			return nil, err
+		}
+	}
+	return advisory, nil
+}
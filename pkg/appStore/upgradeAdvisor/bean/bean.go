@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type UpgradeType string
+
+const (
+	UpgradeTypeNone  UpgradeType = "none"
+	UpgradeTypePatch UpgradeType = "patch"
+	UpgradeTypeMinor UpgradeType = "minor"
+	UpgradeTypeMajor UpgradeType = "major"
+)
+
+func (t UpgradeType) IsValid() bool {
+	return t == UpgradeTypeNone || t == UpgradeTypePatch || t == UpgradeTypeMinor || t == UpgradeTypeMajor
+}
+
+type ObjectChange string
+
+const (
+	ObjectAdded    ObjectChange = "added"
+	ObjectRemoved  ObjectChange = "removed"
+	ObjectModified ObjectChange = "modified"
+)
+
+type RenamedKey struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ValuesDiff is the impact of a chart upgrade on the values overridden by the user, keys are dot separated paths
+type ValuesDiff struct {
+	// RemovedKeys are overridden keys the new chart neither has in its default values nor in its schema
+	RemovedKeys []string `json:"removedKeys"`
+	// RenamedKeys are removed keys with a single key of the same name added at another path by the new chart
+	RenamedKeys []*RenamedKey `json:"renamedKeys"`
+	// NewlyRequiredKeys are required by the schema of the new chart, not by the old one, and set neither by the user nor the defaults
+	NewlyRequiredKeys []string `json:"newlyRequiredKeys"`
+}
+
+func (d *ValuesDiff) IsEmpty() bool {
+	return d == nil || len(d.RemovedKeys) == 0 && len(d.RenamedKeys) == 0 && len(d.NewlyRequiredKeys) == 0
+}
+
+type ChartVersion struct {
+	AppStoreApplicationVersionId int
+	Version                      string
+	Deprecated                   bool
+}
+
+type UpgradeAdvisory struct {
+	InstalledAppId                      int         `json:"installedAppId"`
+	InstalledAppVersionId               int         `json:"installedAppVersionId"`
+	AppName                             string      `json:"appName"`
+	EnvironmentId                       int         `json:"environmentId"`
+	EnvironmentName                     string      `json:"environmentName"`
+	Namespace                           string      `json:"namespace"`
+	ClusterId                           int         `json:"clusterId"`
+	AppStoreId                          int         `json:"appStoreId"`
+	ChartName                           string      `json:"chartName"`
+	CurrentVersion                      string      `json:"currentVersion"`
+	CurrentAppStoreApplicationVersionId int         `json:"currentAppStoreApplicationVersionId"`
+	LatestVersion                       string      `json:"latestVersion,omitempty"`
+	LatestAppStoreApplicationVersionId  int         `json:"latestAppStoreApplicationVersionId,omitempty"`
+	UpgradeType                         UpgradeType `json:"upgradeType"`
+	ValuesDiff                          *ValuesDiff `json:"valuesDiff,omitempty"`
+	Message                             string      `json:"message,omitempty"`
+	ComputedOn                          time.Time   `json:"computedOn"`
+	AppOfferingMode                     string      `json:"-"`
+}
+
+type AdvisoryFilter struct {
+	// UpgradeTypes defaults to every type other than none
+	UpgradeTypes []UpgradeType
+	EnvIds       []int
+	AppStoreId   int
+}
+
+type ManifestDiffRequest struct {
+	InstalledAppId int `json:"installedAppId"`
+	// AppStoreApplicationVersionId is the version to upgrade to, the latest version when 0
+	AppStoreApplicationVersionId int `json:"appStoreApplicationVersionId"`
+	// ValuesYaml to upgrade with, the values of the deployed version when empty
+	ValuesYaml string `json:"valuesYaml,omitempty"`
+}
+
+type ObjectDiff struct {
+	Kind      string       `json:"kind"`
+	Name      string       `json:"name"`
+	Namespace string       `json:"namespace,omitempty"`
+	Change    ObjectChange `json:"change"`
+	// Diff is the unified diff of the rendered object
+	Diff string `json:"diff"`
+}
+
+type ManifestDiff struct {
+	FromVersion    string        `json:"fromVersion"`
+	ToVersion      string        `json:"toVersion"`
+	Objects        []*ObjectDiff `json:"objects"`
+	UnchangedCount int           `json:"unchangedCount"`
+}
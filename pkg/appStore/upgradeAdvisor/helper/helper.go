@@ -0,0 +1,408 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"fmt"
+	"github.com/Masterminds/semver"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/bean"
+	"github.com/pmezard/go-difflib/difflib"
+	"reflect"
+	"regexp"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strings"
+)
+
+// GetLatestVersion returns the highest non deprecated version above current with the type of the upgrade to it,
+// nil with UpgradeTypeNone when current is the latest. Pre-releases are considered only when current is one.
+func GetLatestVersion(current string, versions []*bean.ChartVersion) (*bean.ChartVersion, bean.UpgradeType, error) {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return nil, bean.UpgradeTypeNone, fmt.Errorf("installed chart version %q is not semver", current)
+	}
+	var latest *bean.ChartVersion
+	var latestVersion *semver.Version
+	for _, version := range versions {
+		if version.Deprecated {
+			continue
+		}
+		candidate, err := semver.NewVersion(version.Version)
+		if err != nil || len(candidate.Prerelease()) > 0 && len(currentVersion.Prerelease()) == 0 {
+			continue
+		}
+		if !candidate.GreaterThan(currentVersion) || latestVersion != nil && !candidate.GreaterThan(latestVersion) {
+			continue
+		}
+		latest, latestVersion = version, candidate
+	}
+	if latest == nil {
+		return nil, bean.UpgradeTypeNone, nil
+	}
+	return latest, ClassifyUpgrade(currentVersion, latestVersion), nil
+}
+
+func ClassifyUpgrade(from, to *semver.Version) bean.UpgradeType {
+	switch {
+	case to.Major() != from.Major():
+		return bean.UpgradeTypeMajor
+	case to.Minor() != from.Minor():
+		return bean.UpgradeTypeMinor
+	case to.GreaterThan(from):
+		return bean.UpgradeTypePatch
+	}
+	return bean.UpgradeTypeNone
+}
+
+// valuesShape is the set of keys a chart accepts, from its default values and its values schema
+type valuesShape struct {
+	// paths are all the keys, leaf or not
+	paths map[string]bool
+	// openPaths are keys whose children are free form: empty or null defaults and schema objects without properties
+	// or allowing additional properties
+	openPaths map[string]bool
+	// leaves are the keys holding a value with their last segment
+	leaves map[string]string
+}
+
+func newValuesShape(defaults map[string]interface{}, schema map[string]interface{}) *valuesShape {
+	shape := &valuesShape{paths: map[string]bool{}, openPaths: map[string]bool{}, leaves: map[string]string{}}
+	shape.addValues("", defaults)
+	shape.addSchema("", schema)
+	return shape
+}
+
+func (s *valuesShape) addValues(prefix string, values map[string]interface{}) {
+	for key, value := range values {
+		path := joinPath(prefix, key)
+		s.paths[path] = true
+		child, isMap := value.(map[string]interface{})
+		switch {
+		case isMap && len(child) > 0:
+			s.addValues(path, child)
+		case isMap || value == nil:
+			s.openPaths[path] = true
+		default:
+			s.leaves[path] = key
+		}
+	}
+}
+
+func (s *valuesShape) addSchema(prefix string, schema map[string]interface{}) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	for key, property := range properties {
+		path := joinPath(prefix, key)
+		s.paths[path] = true
+		propertySchema, _ := property.(map[string]interface{})
+		if isObjectSchema(propertySchema) {
+			if _, hasProperties := propertySchema["properties"]; !hasProperties || allowsAdditionalProperties(propertySchema) {
+				s.openPaths[path] = true
+			}
+			s.addSchema(path, propertySchema)
+		} else if _, isLeaf := s.leaves[path]; !isLeaf && !s.openPaths[path] {
+			s.leaves[path] = key
+		}
+	}
+}
+
+// has tells whether the chart accepts the key
+func (s *valuesShape) has(path string) bool {
+	if s.paths[path] {
+		return true
+	}
+	for parent := parentPath(path); len(parent) > 0; parent = parentPath(parent) {
+		if s.openPaths[parent] {
+			return true
+		}
+	}
+	return false
+}
+
+// GetValuesDiff finds the keys overridden by the user, values differing from the defaults of the installed chart,
+// which the new chart no longer accepts, and the keys the schema of the new chart newly requires which are missing
+func GetValuesDiff(oldDefaultsYaml, newDefaultsYaml, userValuesYaml, oldSchemaJson, newSchemaJson string) (*bean.ValuesDiff, error) {
+	oldDefaults, err := parseValues(oldDefaultsYaml)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default values of the installed chart: %w", err)
+	}
+	newDefaults, err := parseValues(newDefaultsYaml)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default values of the new chart: %w", err)
+	}
+	userValues, err := parseValues(userValuesYaml)
+	if err != nil {
+		return nil, fmt.Errorf("invalid values of the installed app: %w", err)
+	}
+	oldSchema, err := parseValues(oldSchemaJson)
+	if err != nil {
+		return nil, fmt.Errorf("invalid values schema of the installed chart: %w", err)
+	}
+	newSchema, err := parseValues(newSchemaJson)
+	if err != nil {
+		return nil, fmt.Errorf("invalid values schema of the new chart: %w", err)
+	}
+	oldShape, newShape := newValuesShape(oldDefaults, oldSchema), newValuesShape(newDefaults, newSchema)
+
+	oldLeaves, userLeaves := map[string]interface{}{}, map[string]interface{}{}
+	flattenValues("", oldDefaults, oldLeaves)
+	flattenValues("", userValues, userLeaves)
+	diff := &bean.ValuesDiff{RemovedKeys: []string{}, RenamedKeys: []*bean.RenamedKey{}, NewlyRequiredKeys: []string{}}
+	for path, value := range userLeaves {
+		if oldValue, ok := oldLeaves[path]; ok && reflect.DeepEqual(oldValue, value) {
+			continue
+		}
+		if !oldShape.has(path) || newShape.has(path) {
+			continue
+		}
+		if renamedTo := findRenamedKey(path, oldShape, newShape); len(renamedTo) > 0 {
+			diff.RenamedKeys = append(diff.RenamedKeys, &bean.RenamedKey{From: path, To: renamedTo})
+		} else {
+			diff.RemovedKeys = append(diff.RemovedKeys, path)
+		}
+	}
+
+	oldRequired := map[string]bool{}
+	for _, path := range requiredPaths("", oldSchema) {
+		oldRequired[path] = true
+	}
+	userShape := newValuesShape(userValues, nil)
+	for _, path := range requiredPaths("", newSchema) {
+		if oldRequired[path] || userShape.paths[path] || hasDefault(path, newDefaults) {
+			continue
+		}
+		diff.NewlyRequiredKeys = append(diff.NewlyRequiredKeys, path)
+	}
+	sort.Strings(diff.RemovedKeys)
+	sort.Strings(diff.NewlyRequiredKeys)
+	sort.Slice(diff.RenamedKeys, func(i, j int) bool {
+		return diff.RenamedKeys[i].From < diff.RenamedKeys[j].From
+	})
+	return diff, nil
+}
+
+// findRenamedKey returns the only key added by the new chart with the name of the removed key
+func findRenamedKey(removedPath string, oldShape, newShape *valuesShape) string {
+	name := lastSegment(removedPath)
+	var renamedTo []string
+	for path, leaf := range newShape.leaves {
+		if leaf == name && !oldShape.has(path) {
+			renamedTo = append(renamedTo, path)
+		}
+	}
+	if len(renamedTo) != 1 {
+		return ""
+	}
+	return renamedTo[0]
+}
+
+func requiredPaths(prefix string, schema map[string]interface{}) []string {
+	var paths []string
+	required, _ := schema["required"].([]interface{})
+	for _, key := range required {
+		if name, ok := key.(string); ok {
+			paths = append(paths, joinPath(prefix, name))
+		}
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	for key, property := range properties {
+		if propertySchema, ok := property.(map[string]interface{}); ok {
+			paths = append(paths, requiredPaths(joinPath(prefix, key), propertySchema)...)
+		}
+	}
+	return paths
+}
+
+// hasDefault tells whether the defaults set the key, a null default is no value
+func hasDefault(path string, defaults map[string]interface{}) bool {
+	var current interface{} = defaults
+	for _, segment := range strings.Split(path, ".") {
+		values, ok := current.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if current, ok = values[segment]; !ok {
+			return false
+		}
+	}
+	return current != nil
+}
+
+func flattenValues(prefix string, values map[string]interface{}, leaves map[string]interface{}) {
+	for key, value := range values {
+		path := joinPath(prefix, key)
+		if child, ok := value.(map[string]interface{}); ok && len(child) > 0 {
+			flattenValues(path, child, leaves)
+			continue
+		}
+		leaves[path] = value
+	}
+}
+
+func isObjectSchema(schema map[string]interface{}) bool {
+	if schema == nil {
+		return false
+	}
+	if _, ok := schema["properties"]; ok {
+		return true
+	}
+	switch schemaType := schema["type"].(type) {
+	case string:
+		return schemaType == "object"
+	case []interface{}:
+		for _, t := range schemaType {
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func allowsAdditionalProperties(schema map[string]interface{}) bool {
+	additionalProperties, ok := schema["additionalProperties"]
+	if !ok {
+		return false
+	}
+	allowed, isBool := additionalProperties.(bool)
+	return !isBool || allowed
+}
+
+func parseValues(values string) (map[string]interface{}, error) {
+	parsed := map[string]interface{}{}
+	if len(strings.TrimSpace(values)) == 0 {
+		return parsed, nil
+	}
+	err := yaml.Unmarshal([]byte(values), &parsed)
+	return parsed, err
+}
+
+func joinPath(prefix, key string) string {
+	if len(prefix) == 0 {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func parentPath(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+func lastSegment(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
+
+var manifestSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+type renderedObject struct {
+	kind      string
+	name      string
+	namespace string
+	yaml      string
+}
+
+func (o *renderedObject) key() string {
+	return fmt.Sprintf("%s/%s/%s", o.kind, o.namespace, o.name)
+}
+
+// parseManifest splits a rendered manifest into its objects, each re-marshalled so that the comments and the
+// ordering of the keys in the templates do not show in diffs
+func parseManifest(manifest string) (map[string]*renderedObject, error) {
+	objects := make(map[string]*renderedObject)
+	for _, document := range manifestSeparator.Split(manifest, -1) {
+		var object map[string]interface{}
+		if err := yaml.Unmarshal([]byte(document), &object); err != nil {
+			return nil, err
+		}
+		if len(object) == 0 {
+			continue
+		}
+		normalized, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+		metadata, _ := object["metadata"].(map[string]interface{})
+		rendered := &renderedObject{yaml: string(normalized)}
+		rendered.kind, _ = object["kind"].(string)
+		rendered.name, _ = metadata["name"].(string)
+		rendered.namespace, _ = metadata["namespace"].(string)
+		objects[rendered.key()] = rendered
+	}
+	return objects, nil
+}
+
+// GetManifestDiff diffs the objects of two rendered manifests of a release
+func GetManifestDiff(fromManifest, toManifest string, fromVersion, toVersion string) (*bean.ManifestDiff, error) {
+	fromObjects, err := parseManifest(fromManifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest of version %s: %w", fromVersion, err)
+	}
+	toObjects, err := parseManifest(toManifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest of version %s: %w", toVersion, err)
+	}
+	diff := &bean.ManifestDiff{FromVersion: fromVersion, ToVersion: toVersion, Objects: []*bean.ObjectDiff{}}
+	keys := make(map[string]bool, len(fromObjects)+len(toObjects))
+	for key := range fromObjects {
+		keys[key] = true
+	}
+	for key := range toObjects {
+		keys[key] = true
+	}
+	for key := range keys {
+		from, to := fromObjects[key], toObjects[key]
+		object, change := to, bean.ObjectModified
+		fromYaml, toYaml := "", ""
+		switch {
+		case from == nil:
+			change, toYaml = bean.ObjectAdded, to.yaml
+		case to == nil:
+			object, change, fromYaml = from, bean.ObjectRemoved, from.yaml
+		case from.yaml == to.yaml:
+			diff.UnchangedCount++
+			continue
+		default:
+			fromYaml, toYaml = from.yaml, to.yaml
+		}
+		unifiedDiff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(fromYaml),
+			B:        difflib.SplitLines(toYaml),
+			FromFile: fromVersion,
+			ToFile:   toVersion,
+			Context:  3,
+		})
+		if err != nil {
+			return nil, err
+		}
+		diff.Objects = append(diff.Objects, &bean.ObjectDiff{
+			Kind:      object.kind,
+			Name:      object.name,
+			Namespace: object.namespace,
+			Change:    change,
+			Diff:      unifiedDiff,
+		})
+	}
+	sort.Slice(diff.Objects, func(i, j int) bool {
+		if diff.Objects[i].Kind != diff.Objects[j].Kind {
+			return diff.Objects[i].Kind < diff.Objects[j].Kind
+		}
+		return diff.Objects[i].Name < diff.Objects[j].Name
+	})
+	return diff, nil
+}
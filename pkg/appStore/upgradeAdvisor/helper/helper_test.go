@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package helper
+
+import (
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetLatestVersion(t *testing.T) {
+	versions := []*bean.ChartVersion{
+		{AppStoreApplicationVersionId: 1, Version: "1.2.3"},
+		{AppStoreApplicationVersionId: 2, Version: "1.2.5"},
+		{AppStoreApplicationVersionId: 3, Version: "1.3.0"},
+		{AppStoreApplicationVersionId: 4, Version: "2.0.0", Deprecated: true},
+		{AppStoreApplicationVersionId: 5, Version: "2.1.0-rc.1"},
+		{AppStoreApplicationVersionId: 6, Version: "latest"},
+	}
+	latest, upgradeType, err := GetLatestVersion("1.2.3", versions)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, latest.AppStoreApplicationVersionId)
+	assert.Equal(t, bean.UpgradeTypeMinor, upgradeType)
+
+	latest, upgradeType, err = GetLatestVersion("1.3.0", versions)
+	assert.Nil(t, err)
+	assert.Nil(t, latest)
+	assert.Equal(t, bean.UpgradeTypeNone, upgradeType)
+
+	// pre-releases are offered to pre-releases only
+	latest, upgradeType, err = GetLatestVersion("2.0.0-beta.1", versions)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, latest.AppStoreApplicationVersionId)
+	assert.Equal(t, bean.UpgradeTypeMinor, upgradeType)
+
+	latest, upgradeType, err = GetLatestVersion("1.2.4", versions[:2])
+	assert.Nil(t, err)
+	assert.Equal(t, 2, latest.AppStoreApplicationVersionId)
+	assert.Equal(t, bean.UpgradeTypePatch, upgradeType)
+
+	_, _, err = GetLatestVersion("stable", versions)
+	assert.NotNil(t, err)
+}
+
+func TestGetValuesDiff(t *testing.T) {
+	oldDefaults := `
+image:
+  repository: nginx
+  tag: "1.25"
+service:
+  port: 80
+  nodePort: null
+persistence:
+  size: 8Gi
+podAnnotations: {}
+`
+	newDefaults := `
+image:
+  repository: nginx
+  tag: "1.27"
+service:
+  ports:
+    http: 80
+  nodePort: null
+storage:
+  size: 8Gi
+podAnnotations: {}
+`
+	userValues := `
+image:
+  repository: nginx
+  tag: "1.25"
+service:
+  port: 8080
+  nodePort: 30080
+persistence:
+  size: 20Gi
+podAnnotations:
+  prometheus.io/scrape: "true"
+custom: value
+`
+	newSchema := `{
+  "type": "object",
+  "required": ["auth"],
+  "properties": {
+    "auth": {"type": "object", "required": ["password", "username"], "properties": {"password": {"type": "string"}, "username": {"type": "string"}}},
+    "service": {"type": "object", "required": ["ports"], "properties": {"ports": {"type": "object"}}, "additionalProperties": false}
+  }
+}`
+	diff, err := GetValuesDiff(oldDefaults, newDefaults, userValues, "", newSchema)
+	assert.Nil(t, err)
+	// unchanged overrides and keys under free form maps are not reported, neither are keys unknown to both charts
+	assert.Equal(t, []string{"service.port"}, diff.RemovedKeys)
+	assert.Equal(t, []*bean.RenamedKey{{From: "persistence.size", To: "storage.size"}}, diff.RenamedKeys)
+	// service.ports has a default
+	assert.Equal(t, []string{"auth", "auth.password", "auth.username"}, diff.NewlyRequiredKeys)
+	assert.False(t, diff.IsEmpty())
+
+	diff, err = GetValuesDiff(oldDefaults, oldDefaults, userValues, newSchema, newSchema)
+	assert.Nil(t, err)
+	assert.True(t, diff.IsEmpty())
+
+	_, err = GetValuesDiff(oldDefaults, newDefaults, "image: [", "", "")
+	assert.NotNil(t, err)
+}
+
+func TestGetManifestDiff(t *testing.T) {
+	fromManifest := `---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  ports:
+  - port: 80
+---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+---
+# Source: app/templates/pvc.yaml
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: app
+`
+	toManifest := `---
+# Source: app/templates/svc.yaml
+kind: Service
+apiVersion: v1
+metadata:
+  name: app
+spec:
+  ports:
+  - port: 80
+---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 2
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app
+`
+	diff, err := GetManifestDiff(fromManifest, toManifest, "1.0.0", "2.0.0")
+	assert.Nil(t, err)
+	// moved comments and reordered keys are no change
+	assert.Equal(t, 1, diff.UnchangedCount)
+	assert.Len(t, diff.Objects, 3)
+	assert.Equal(t, "Deployment", diff.Objects[0].Kind)
+	assert.Equal(t, bean.ObjectModified, diff.Objects[0].Change)
+	assert.Contains(t, diff.Objects[0].Diff, "-  replicas: 1\n+  replicas: 2\n")
+	assert.Equal(t, "PersistentVolumeClaim", diff.Objects[1].Kind)
+	assert.Equal(t, bean.ObjectRemoved, diff.Objects[1].Change)
+	assert.Equal(t, "ServiceAccount", diff.Objects[2].Kind)
+	assert.Equal(t, bean.ObjectAdded, diff.Objects[2].Change)
+	assert.Contains(t, diff.Objects[2].Diff, "+kind: ServiceAccount")
+}
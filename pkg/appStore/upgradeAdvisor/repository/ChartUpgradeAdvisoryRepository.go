@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ChartUpgradeAdvisory struct {
+	tableName                           struct{}         `sql:"chart_upgrade_advisory" pg:",discard_unknown_columns"`
+	Id                                  int              `sql:"id,pk"`
+	InstalledAppId                      int              `sql:"installed_app_id,notnull"`
+	InstalledAppVersionId               int              `sql:"installed_app_version_id,notnull"`
+	CurrentAppStoreApplicationVersionId int              `sql:"current_app_store_application_version_id,notnull"`
+	CurrentVersion                      string           `sql:"current_version,notnull"`
+	LatestAppStoreApplicationVersionId  int              `sql:"latest_app_store_application_version_id"`
+	LatestVersion                       string           `sql:"latest_version"`
+	UpgradeType                         bean.UpgradeType `sql:"upgrade_type,notnull"`
+	ValuesDiff                          string           `sql:"values_diff"`
+	Message                             string           `sql:"message"`
+	ComputedOn                          time.Time        `sql:"computed_on,notnull"`
+	sql.AuditLog
+}
+
+// ChartUpgradeAdvisoryDetail is an advisory with the app, environment and chart it is for
+type ChartUpgradeAdvisoryDetail struct {
+	ChartUpgradeAdvisory
+	AppName         string `sql:"app_name"`
+	DisplayName     string `sql:"display_name"`
+	AppOfferingMode string `sql:"app_offering_mode"`
+	EnvironmentId   int    `sql:"environment_id"`
+	EnvironmentName string `sql:"environment_name"`
+	Namespace       string `sql:"namespace"`
+	ClusterId       int    `sql:"cluster_id"`
+	AppStoreId      int    `sql:"app_store_id"`
+	ChartName       string `sql:"chart_name"`
+}
+
+// InstalledAppChart is the deployed chart version and values of an active installed app
+type InstalledAppChart struct {
+	InstalledAppId               int    `sql:"installed_app_id"`
+	InstalledAppVersionId        int    `sql:"installed_app_version_id"`
+	AppStoreApplicationVersionId int    `sql:"app_store_application_version_id"`
+	AppStoreId                   int    `sql:"app_store_id"`
+	ValuesYaml                   string `sql:"values_yaml_raw"`
+}
+
+type ChartUpgradeAdvisoryRepository interface {
+	// Upsert creates or replaces the advisory of the installed app
+	Upsert(advisory *ChartUpgradeAdvisory) error
+	FindByInstalledAppId(installedAppId int) (*ChartUpgradeAdvisoryDetail, error)
+	FindAll(filter *bean.AdvisoryFilter) ([]*ChartUpgradeAdvisoryDetail, error)
+	// DeleteInactive removes the advisories of the installed apps deleted since they were computed
+	DeleteInactive() error
+	GetActiveInstalledAppCharts() ([]*InstalledAppChart, error)
+	GetActiveInstalledAppChart(installedAppId int) (*InstalledAppChart, error)
+}
+
+type ChartUpgradeAdvisoryRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewChartUpgradeAdvisoryRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ChartUpgradeAdvisoryRepositoryImpl {
+	return &ChartUpgradeAdvisoryRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ChartUpgradeAdvisoryRepositoryImpl) Upsert(advisory *ChartUpgradeAdvisory) error {
+	_, err := impl.dbConnection.Model(advisory).
+		OnConflict("(installed_app_id) DO UPDATE").
+		Set("installed_app_version_id = EXCLUDED.installed_app_version_id").
+		Set("current_app_store_application_version_id = EXCLUDED.current_app_store_application_version_id").
+		Set("current_version = EXCLUDED.current_version").
+		Set("latest_app_store_application_version_id = EXCLUDED.latest_app_store_application_version_id").
+		Set("latest_version = EXCLUDED.latest_version").
+		Set("upgrade_type = EXCLUDED.upgrade_type").
+		Set("values_diff = EXCLUDED.values_diff").
+		Set("message = EXCLUDED.message").
+		Set("computed_on = EXCLUDED.computed_on").
+		Set("updated_on = EXCLUDED.updated_on").
+		Set("updated_by = EXCLUDED.updated_by").
+		Insert()
+	return err
+}
+
+const advisoryDetailQuery = `SELECT cua.*, a.app_name, a.display_name, a.app_offering_mode, env.id AS environment_id,
+		env.environment_name, env.namespace, env.cluster_id, aps.id AS app_store_id, aps.name AS chart_name
+	FROM chart_upgrade_advisory cua
+		INNER JOIN installed_apps ia ON ia.id = cua.installed_app_id AND ia.active = true
+		INNER JOIN app a ON a.id = ia.app_id AND a.active = true
+		INNER JOIN environment env ON env.id = ia.environment_id
+		INNER JOIN app_store_application_version asv ON asv.id = cua.current_app_store_application_version_id
+		INNER JOIN app_store aps ON aps.id = asv.app_store_id`
+
+func (impl *ChartUpgradeAdvisoryRepositoryImpl) FindByInstalledAppId(installedAppId int) (*ChartUpgradeAdvisoryDetail, error) {
+	advisory := &ChartUpgradeAdvisoryDetail{}
+	_, err := impl.dbConnection.QueryOne(advisory, advisoryDetailQuery+" WHERE cua.installed_app_id = ?;", installedAppId)
+	return advisory, err
+}
+
+func (impl *ChartUpgradeAdvisoryRepositoryImpl) FindAll(filter *bean.AdvisoryFilter) ([]*ChartUpgradeAdvisoryDetail, error) {
+	query := advisoryDetailQuery + " WHERE cua.upgrade_type IN (?)"
+	queryParams := []interface{}{pg.In(filter.UpgradeTypes)}
+	if len(filter.EnvIds) > 0 {
+		query += " AND env.id IN (?)"
+		queryParams = append(queryParams, pg.In(filter.EnvIds))
+	}
+	if filter.AppStoreId > 0 {
+		query += " AND aps.id = ?"
+		queryParams = append(queryParams, filter.AppStoreId)
+	}
+	query += " ORDER BY a.app_name, env.environment_name;"
+	var advisories []*ChartUpgradeAdvisoryDetail
+	_, err := impl.dbConnection.Query(&advisories, query, queryParams...)
+	if err != nil {
+		impl.logger.Errorw("error in getting chart upgrade advisories", "filter", filter, "err", err)
+		return nil, err
+	}
+	return advisories, nil
+}
+
+func (impl *ChartUpgradeAdvisoryRepositoryImpl) DeleteInactive() error {
+	_, err := impl.dbConnection.Model((*ChartUpgradeAdvisory)(nil)).
+		Where("installed_app_id NOT IN (SELECT id FROM installed_apps WHERE active = true)").
+		Delete()
+	return err
+}
+
+// installedAppChartQuery picks the latest active version of each installed app
+const installedAppChartQuery = `SELECT DISTINCT ON (ia.id) ia.id AS installed_app_id, iav.id AS installed_app_version_id,
+		iav.app_store_application_version_id, asv.app_store_id, iav.values_yaml_raw
+	FROM installed_apps ia
+		INNER JOIN installed_app_versions iav ON iav.installed_app_id = ia.id AND iav.active = true
+		INNER JOIN app_store_application_version asv ON asv.id = iav.app_store_application_version_id
+		INNER JOIN app_store aps ON aps.id = asv.app_store_id AND aps.active = true
+	WHERE ia.active = true`
+
+func (impl *ChartUpgradeAdvisoryRepositoryImpl) GetActiveInstalledAppCharts() ([]*InstalledAppChart, error) {
+	var charts []*InstalledAppChart
+	_, err := impl.dbConnection.Query(&charts, installedAppChartQuery+" ORDER BY ia.id, iav.id DESC;")
+	if err != nil {
+		impl.logger.Errorw("error in getting installed app charts", "err", err)
+		return nil, err
+	}
+	return charts, nil
+}
+
+func (impl *ChartUpgradeAdvisoryRepositoryImpl) GetActiveInstalledAppChart(installedAppId int) (*InstalledAppChart, error) {
+	chart := &InstalledAppChart{}
+	_, err := impl.dbConnection.QueryOne(chart, installedAppChartQuery+" AND ia.id = ? ORDER BY ia.id, iav.id DESC;", installedAppId)
+	return chart, err
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package valuesSchema
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/util"
+	appStoreDiscoverRepository "github.com/devtron-labs/devtron/pkg/appStore/discover/repository"
+	"github.com/devtron-labs/devtron/pkg/appStore/valuesSchema/bean"
+	"github.com/devtron-labs/devtron/pkg/appStore/valuesSchema/helper"
+	"github.com/devtron-labs/devtron/pkg/appStore/valuesSchema/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+type ValuesSchemaConfig struct {
+	EnforceInferredSchema bool `env:"VALUES_SCHEMA_ENFORCE_INFERRED" envDefault:"false" description:"Reject chart store installs and updates whose values do not match the schema inferred from the default values of charts without values.schema.json"`
+}
+
+func GetValuesSchemaConfig() (*ValuesSchemaConfig, error) {
+	cfg := &ValuesSchemaConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+type ValuesSchemaService interface {
+	// GetValuesSchema returns the values schema of the chart version with the fields to build a values form with
+	GetValuesSchema(appStoreApplicationVersionId int) (*bean.ValuesSchemaDto, error)
+	// ValidateValues validates values to deploy the chart version with against its values schema
+	ValidateValues(appStoreApplicationVersionId int, valuesYaml string) (*bean.ValidationResult, error)
+}
+
+type ValuesSchemaServiceImpl struct {
+	logger                               *zap.SugaredLogger
+	repository                           repository.ValuesSchemaRepository
+	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository
+	config                               *ValuesSchemaConfig
+}
+
+func NewValuesSchemaServiceImpl(logger *zap.SugaredLogger,
+	repository repository.ValuesSchemaRepository,
+	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository,
+	config *ValuesSchemaConfig) *ValuesSchemaServiceImpl {
+	return &ValuesSchemaServiceImpl{
+		logger:                               logger,
+		repository:                           repository,
+		appStoreApplicationVersionRepository: appStoreApplicationVersionRepository,
+		config:                               config,
+	}
+}
+
+func (impl *ValuesSchemaServiceImpl) GetValuesSchema(appStoreApplicationVersionId int) (*bean.ValuesSchemaDto, error) {
+	version, schema, err := impl.getSchema(appStoreApplicationVersionId)
+	if err != nil {
+		return nil, err
+	}
+	schemaMap := map[string]interface{}{}
+	if err = json.Unmarshal([]byte(schema.Schema), &schemaMap); err != nil {
+		impl.logger.Errorw("error in reading values schema", "appStoreApplicationVersionId", appStoreApplicationVersionId, "err", err)
+		return nil, err
+	}
+	return &bean.ValuesSchemaDto{
+		AppStoreApplicationVersionId: appStoreApplicationVersionId,
+		Source:                       schema.Source,
+		Schema:                       json.RawMessage(schema.Schema),
+		Fields:                       helper.GetFormFields(schemaMap, version.RawValues),
+	}, nil
+}
+
+func (impl *ValuesSchemaServiceImpl) ValidateValues(appStoreApplicationVersionId int, valuesYaml string) (*bean.ValidationResult, error) {
+	version, schema, err := impl.getSchema(appStoreApplicationVersionId)
+	if err != nil {
+		return nil, err
+	}
+	validationErrors, err := helper.ValidateValues(schema.Schema, version.RawValues, valuesYaml)
+	if err != nil {
+		impl.logger.Errorw("error in validating values", "appStoreApplicationVersionId", appStoreApplicationVersionId, "source", schema.Source, "err", err)
+		return nil, util.NewApiError(http.StatusUnprocessableEntity, "values schema of the chart is invalid", err.Error())
+	}
+	return &bean.ValidationResult{
+		Valid:    len(validationErrors) == 0,
+		Source:   schema.Source,
+		Enforced: schema.Source == bean.SchemaSourceChart || impl.config.EnforceInferredSchema,
+		Errors:   validationErrors,
+	}, nil
+}
+
+// getSchema returns the chart version with its schema, stored the first time it is asked for. The values.schema.json
+// of the chart is used when it has one, else the schema is inferred from the default values.
+func (impl *ValuesSchemaServiceImpl) getSchema(appStoreApplicationVersionId int) (*appStoreDiscoverRepository.AppStoreApplicationVersion, *repository.AppStoreValuesSchema, error) {
+	version, err := impl.appStoreApplicationVersionRepository.GetValuesAndSchemaById(appStoreApplicationVersionId)
+	if util.IsErrNoRows(err) {
+		return nil, nil, util.NewApiError(http.StatusNotFound, "chart version not found", err.Error())
+	} else if err != nil {
+		impl.logger.Errorw("error in getting chart version", "appStoreApplicationVersionId", appStoreApplicationVersionId, "err", err)
+		return nil, nil, err
+	}
+	schema, err := impl.repository.FindByAppStoreApplicationVersionId(appStoreApplicationVersionId)
+	if err == nil {
+		return version, schema, nil
+	} else if !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in getting values schema", "appStoreApplicationVersionId", appStoreApplicationVersionId, "err", err)
+		return nil, nil, err
+	}
+	schema = &repository.AppStoreValuesSchema{
+		AppStoreApplicationVersionId: appStoreApplicationVersionId,
+		Source:                       bean.SchemaSourceChart,
+		Schema:                       strings.TrimSpace(version.ValuesSchemaJson),
+		AuditLog:                     sql.NewDefaultAuditLog(1),
+	}
+	if len(schema.Schema) == 0 || !json.Valid([]byte(schema.Schema)) {
+		inferredSchema, err := helper.InferSchema(version.RawValues)
+		if err != nil {
+			impl.logger.Warnw("could not infer values schema from default values", "appStoreApplicationVersionId", appStoreApplicationVersionId, "err", err)
+			return nil, nil, util.NewApiError(http.StatusUnprocessableEntity, fmt.Sprintf("could not infer values schema from the default values of chart version %s", version.Version), err.Error())
+		}
+		inferredSchemaJson, err := json.Marshal(inferredSchema)
+		if err != nil {
+			return nil, nil, err
+		}
+		schema.Source, schema.Schema = bean.SchemaSourceInferred, string(inferredSchemaJson)
+	}
+	if err = impl.repository.Save(schema); err != nil {
+		impl.logger.Errorw("error in saving values schema", "appStoreApplicationVersionId", appStoreApplicationVersionId, "err", err)
+		return nil, nil, err
+	}
+	return version, schema, nil
+}
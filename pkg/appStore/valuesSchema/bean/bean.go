@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "encoding/json"
+
+type SchemaSource string
+
+const (
+	// SchemaSourceChart is the values.schema.json packaged with the chart
+	SchemaSourceChart SchemaSource = "chart"
+	// SchemaSourceInferred is inferred from the default values of the chart and the annotations in their comments
+	SchemaSourceInferred SchemaSource = "inferred"
+)
+
+const (
+	// SchemaAnnotation starts a comment of the default values with schema keywords for the key, separated by ;
+	// like "# @schema type:integer;minimum:1;required:true"
+	SchemaAnnotation = "@schema"
+	// DescriptionAnnotation starts a comment of the default values describing the key, as read by helm-docs
+	DescriptionAnnotation = "--"
+)
+
+type ValuesSchemaDto struct {
+	AppStoreApplicationVersionId int             `json:"appStoreApplicationVersionId"`
+	Source                       SchemaSource    `json:"source"`
+	Schema                       json.RawMessage `json:"schema"`
+	// Fields are the keys of the schema to build a form with, in the order of the default values
+	Fields []*FormField `json:"fields"`
+}
+
+type FormField struct {
+	// Path is the dot separated key
+	Path        string        `json:"path"`
+	Type        string        `json:"type,omitempty"`
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	Required    bool          `json:"required"`
+	Minimum     *float64      `json:"minimum,omitempty"`
+	Maximum     *float64      `json:"maximum,omitempty"`
+	Pattern     string        `json:"pattern,omitempty"`
+	// ItemType is the type of the items of arrays
+	ItemType string `json:"itemType,omitempty"`
+}
+
+type ValidateValuesRequest struct {
+	ValuesYaml string `json:"valuesYaml"`
+}
+
+type ValidationError struct {
+	// Path is the dot separated key the error is at, empty for the whole values
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+type ValidationResult struct {
+	Valid  bool         `json:"valid"`
+	Source SchemaSource `json:"source,omitempty"`
+	// Enforced tells whether installs and updates with these errors are rejected, schemas inferred from the default
+	// values are enforced only when configured
+	Enforced bool               `json:"enforced"`
+	Errors   []*ValidationError `json:"errors"`
+}
@@ -0,0 +1,351 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/appStore/valuesSchema/bean"
+	"github.com/xeipuuv/gojsonschema"
+	yamlv3 "gopkg.in/yaml.v3"
+	"math"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strings"
+)
+
+// rawAnnotationKeywords are kept as written in @schema annotations, the others are parsed as yaml
+var rawAnnotationKeywords = map[string]bool{"title": true, "description": true, "pattern": true, "format": true}
+
+// InferSchema infers a json schema from default values. The type and default of each key come from its default value,
+// comments starting with -- describe it, like helm-docs reads them, and comments starting with @schema add keywords
+// to it, like "# @schema enum:[ClusterIP,NodePort];required:true". Empty maps and nulls accept any value.
+func InferSchema(defaultsYaml string) (map[string]interface{}, error) {
+	document := &yamlv3.Node{}
+	if err := yamlv3.Unmarshal([]byte(defaultsYaml), document); err != nil {
+		return nil, err
+	}
+	schema := map[string]interface{}{"$schema": "http://json-schema.org/draft-07/schema#", "type": "object"}
+	if len(document.Content) == 0 {
+		return schema, nil
+	}
+	root := document.Content[0]
+	if root.Kind != yamlv3.MappingNode {
+		return nil, fmt.Errorf("default values are not a map")
+	}
+	for key, value := range inferNode(root) {
+		schema[key] = value
+	}
+	return schema, nil
+}
+
+func inferNode(node *yamlv3.Node) map[string]interface{} {
+	if node.Kind == yamlv3.AliasNode && node.Alias != nil {
+		return inferNode(node.Alias)
+	}
+	schema := map[string]interface{}{}
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		schema["type"] = "object"
+		properties := map[string]interface{}{}
+		var required []interface{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			property := inferNode(value)
+			if annotate(property, key.HeadComment, key.LineComment, value.LineComment) {
+				required = append(required, key.Value)
+			}
+			properties[key.Value] = property
+		}
+		if len(properties) > 0 {
+			schema["properties"] = properties
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	case yamlv3.SequenceNode:
+		schema["type"] = "array"
+		if len(node.Content) > 0 {
+			schema["items"] = inferNode(node.Content[0])
+		}
+		var defaultValue interface{}
+		if err := node.Decode(&defaultValue); err == nil {
+			schema["default"] = defaultValue
+		}
+	case yamlv3.ScalarNode:
+		switch node.ShortTag() {
+		case "!!str":
+			schema["type"] = "string"
+		case "!!int":
+			schema["type"] = "integer"
+		case "!!float":
+			schema["type"] = "number"
+		case "!!bool":
+			schema["type"] = "boolean"
+		case "!!null":
+			return schema
+		}
+		var defaultValue interface{}
+		if err := node.Decode(&defaultValue); err == nil {
+			schema["default"] = defaultValue
+		}
+	}
+	return schema
+}
+
+// annotate applies the annotations in the comments of a key to its schema and tells whether the key is required
+func annotate(schema map[string]interface{}, comments ...string) bool {
+	required := false
+	var description []string
+	inDescription := false
+	for _, comment := range comments {
+		for _, line := range strings.Split(comment, "\n") {
+			line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+			switch {
+			case strings.HasPrefix(line, bean.SchemaAnnotation):
+				inDescription = false
+				if applySchemaAnnotation(schema, strings.TrimPrefix(line, bean.SchemaAnnotation)) {
+					required = true
+				}
+			case strings.HasPrefix(line, bean.DescriptionAnnotation):
+				inDescription = true
+				description = append(description, strings.TrimSpace(strings.TrimPrefix(line, bean.DescriptionAnnotation)))
+			case inDescription && len(line) > 0:
+				description = append(description, line)
+			default:
+				inDescription = false
+			}
+		}
+	}
+	if _, described := schema["description"]; !described && len(description) > 0 {
+		schema["description"] = strings.Join(description, " ")
+	}
+	return required
+}
+
+func applySchemaAnnotation(schema map[string]interface{}, annotation string) bool {
+	required := false
+	for _, keyword := range strings.Split(annotation, ";") {
+		name, value, found := strings.Cut(strings.TrimSpace(keyword), ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !found || len(name) == 0 {
+			continue
+		}
+		if rawAnnotationKeywords[name] {
+			schema[name] = value
+			continue
+		}
+		var parsed interface{}
+		if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+			parsed = value
+		}
+		if name == "required" {
+			required = parsed == true
+			continue
+		}
+		schema[name] = parsed
+	}
+	return required
+}
+
+// ValidateValues validates the values deployed with the overrides against the schema, like helm the overrides are
+// merged into the defaults first. Invalid yaml is reported as a validation error.
+func ValidateValues(schemaJson, defaultsYaml, valuesYaml string) ([]*bean.ValidationError, error) {
+	schema := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(schemaJson), &schema); err != nil {
+		return nil, fmt.Errorf("invalid values schema: %w", err)
+	}
+	defaults := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(defaultsYaml), &defaults); err != nil {
+		return nil, fmt.Errorf("invalid default values: %w", err)
+	}
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(valuesYaml), &values); err != nil {
+		return []*bean.ValidationError{{Message: fmt.Sprintf("invalid yaml: %s", err.Error())}}, nil
+	}
+	result, err := gojsonschema.Validate(gojsonschema.NewGoLoader(schema), gojsonschema.NewGoLoader(MergeValues(defaults, values)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid values schema: %w", err)
+	}
+	validationErrors := make([]*bean.ValidationError, 0, len(result.Errors()))
+	for _, resultError := range result.Errors() {
+		path := resultError.Field()
+		if path == gojsonschema.STRING_CONTEXT_ROOT {
+			path = ""
+		}
+		if property, ok := resultError.Details()["property"].(string); ok && resultError.Type() == "required" {
+			path = joinPath(path, property)
+		}
+		validationErrors = append(validationErrors, &bean.ValidationError{Path: path, Message: resultError.Description()})
+	}
+	sort.SliceStable(validationErrors, func(i, j int) bool {
+		return validationErrors[i].Path < validationErrors[j].Path
+	})
+	return validationErrors, nil
+}
+
+// MergeValues merges overrides into values like helm, maps are merged and a null override removes the key
+func MergeValues(values, overrides map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		merged[key] = value
+	}
+	for key, override := range overrides {
+		if override == nil {
+			delete(merged, key)
+			continue
+		}
+		overrideMap, isOverrideMap := override.(map[string]interface{})
+		valueMap, isValueMap := merged[key].(map[string]interface{})
+		if isOverrideMap && isValueMap {
+			merged[key] = MergeValues(valueMap, overrideMap)
+			continue
+		}
+		merged[key] = override
+	}
+	return merged
+}
+
+// GetFormFields lists the keys of the schema a form is built with, objects with properties are flattened into their
+// keys. Fields are in the order of the default values, keys not in the defaults come last.
+func GetFormFields(schema map[string]interface{}, defaultsYaml string) []*bean.FormField {
+	defaults := map[string]interface{}{}
+	_ = yaml.Unmarshal([]byte(defaultsYaml), &defaults)
+	order := map[string]int{}
+	document := &yamlv3.Node{}
+	if err := yamlv3.Unmarshal([]byte(defaultsYaml), document); err == nil && len(document.Content) > 0 {
+		indexKeys("", document.Content[0], order)
+	}
+	fields := make([]*bean.FormField, 0)
+	appendFields("", schema, defaults, &fields)
+	position := func(path string) int {
+		if index, ok := order[path]; ok {
+			return index
+		}
+		return math.MaxInt
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		if position(fields[i].Path) != position(fields[j].Path) {
+			return position(fields[i].Path) < position(fields[j].Path)
+		}
+		return fields[i].Path < fields[j].Path
+	})
+	return fields
+}
+
+func appendFields(prefix string, schema map[string]interface{}, defaults map[string]interface{}, fields *[]*bean.FormField) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := map[string]bool{}
+	if requiredKeys, ok := schema["required"].([]interface{}); ok {
+		for _, key := range requiredKeys {
+			if name, ok := key.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+	for key, property := range properties {
+		path := joinPath(prefix, key)
+		propertySchema, ok := property.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if nested, ok := propertySchema["properties"].(map[string]interface{}); ok && len(nested) > 0 {
+			appendFields(path, propertySchema, defaults, fields)
+			continue
+		}
+		field := &bean.FormField{
+			Path:     path,
+			Type:     schemaType(propertySchema),
+			Required: required[key],
+		}
+		field.Title, _ = propertySchema["title"].(string)
+		field.Description, _ = propertySchema["description"].(string)
+		field.Pattern, _ = propertySchema["pattern"].(string)
+		field.Enum, _ = propertySchema["enum"].([]interface{})
+		field.Minimum = number(propertySchema["minimum"])
+		field.Maximum = number(propertySchema["maximum"])
+		if defaultValue, ok := propertySchema["default"]; ok {
+			field.Default = defaultValue
+		} else {
+			field.Default = lookup(defaults, path)
+		}
+		if items, ok := propertySchema["items"].(map[string]interface{}); ok {
+			field.ItemType = schemaType(items)
+		}
+		*fields = append(*fields, field)
+	}
+}
+
+// indexKeys numbers the keys of the default values in the order they are written
+func indexKeys(prefix string, node *yamlv3.Node, order map[string]int) {
+	if node.Kind != yamlv3.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		path := joinPath(prefix, node.Content[i].Value)
+		order[path] = len(order)
+		indexKeys(path, node.Content[i+1], order)
+	}
+}
+
+// schemaType returns the type of a schema, the first type other than null when it has several
+func schemaType(schema map[string]interface{}) string {
+	switch schemaType := schema["type"].(type) {
+	case string:
+		return schemaType
+	case []interface{}:
+		for _, t := range schemaType {
+			if name, ok := t.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+func number(value interface{}) *float64 {
+	switch n := value.(type) {
+	case float64:
+		return &n
+	case int:
+		f := float64(n)
+		return &f
+	case int64:
+		f := float64(n)
+		return &f
+	}
+	return nil
+}
+
+func lookup(values map[string]interface{}, path string) interface{} {
+	var current interface{} = values
+	for _, segment := range strings.Split(path, ".") {
+		currentMap, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = currentMap[segment]
+	}
+	return current
+}
+
+func joinPath(prefix, key string) string {
+	if len(prefix) == 0 {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/appStore/valuesSchema/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const defaultValues = `
+# -- Number of replicas
+# @schema minimum:1;maximum:10
+replicaCount: 1
+image:
+  repository: nginx
+  # -- Tag of the image,
+  # defaults to the app version
+  tag: "" # @schema pattern:^[a-z0-9.-]*$
+service:
+  # @schema enum:[ClusterIP,NodePort,LoadBalancer];required:true
+  type: ClusterIP
+  port: 80
+podAnnotations: {}
+affinity:
+ingress:
+  hosts:
+    - host: chart.local
+`
+
+func TestInferSchema(t *testing.T) {
+	schema, err := InferSchema(defaultValues)
+	assert.Nil(t, err)
+	properties := schema["properties"].(map[string]interface{})
+
+	replicaCount := properties["replicaCount"].(map[string]interface{})
+	assert.Equal(t, "integer", replicaCount["type"])
+	assert.Equal(t, 1, replicaCount["default"])
+	assert.Equal(t, "Number of replicas", replicaCount["description"])
+	assert.Equal(t, 1.0, replicaCount["minimum"])
+	assert.Equal(t, 10.0, replicaCount["maximum"])
+
+	tag := properties["image"].(map[string]interface{})["properties"].(map[string]interface{})["tag"].(map[string]interface{})
+	assert.Equal(t, "string", tag["type"])
+	assert.Equal(t, "Tag of the image, defaults to the app version", tag["description"])
+	// patterns are kept as written
+	assert.Equal(t, "^[a-z0-9.-]*$", tag["pattern"])
+
+	service := properties["service"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"type"}, service["required"])
+	assert.Equal(t, []interface{}{"ClusterIP", "NodePort", "LoadBalancer"}, service["properties"].(map[string]interface{})["type"].(map[string]interface{})["enum"])
+
+	// empty maps and nulls accept anything
+	assert.Equal(t, map[string]interface{}{"type": "object"}, properties["podAnnotations"])
+	assert.Equal(t, map[string]interface{}{}, properties["affinity"])
+
+	hosts := properties["ingress"].(map[string]interface{})["properties"].(map[string]interface{})["hosts"].(map[string]interface{})
+	assert.Equal(t, "array", hosts["type"])
+	assert.Equal(t, "object", hosts["items"].(map[string]interface{})["type"])
+
+	_, err = InferSchema("- a\n- b\n")
+	assert.NotNil(t, err)
+}
+
+func TestValidateValues(t *testing.T) {
+	schema, err := InferSchema(defaultValues)
+	assert.Nil(t, err)
+	schemaJson, err := json.Marshal(schema)
+	assert.Nil(t, err)
+
+	validationErrors, err := ValidateValues(string(schemaJson), defaultValues, `
+replicaCount: 20
+image:
+  tag: "V1"
+service:
+  type: ExternalName
+podAnnotations:
+  prometheus.io/scrape: "true"
+affinity:
+  nodeAffinity: {}
+`)
+	assert.Nil(t, err)
+	paths := make([]string, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		paths = append(paths, validationError.Path)
+	}
+	assert.Equal(t, []string{"image.tag", "replicaCount", "service.type"}, paths)
+
+	// overrides are merged into the defaults, a null removes the key
+	validationErrors, err = ValidateValues(string(schemaJson), defaultValues, "service:\n  type: null\n")
+	assert.Nil(t, err)
+	assert.Equal(t, []*bean.ValidationError{{Path: "service.type", Message: "type is required"}}, validationErrors)
+
+	validationErrors, err = ValidateValues(string(schemaJson), defaultValues, "replicaCount: 2\n")
+	assert.Nil(t, err)
+	assert.Empty(t, validationErrors)
+
+	validationErrors, err = ValidateValues(string(schemaJson), defaultValues, "image: [")
+	assert.Nil(t, err)
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, "", validationErrors[0].Path)
+}
+
+func TestGetFormFields(t *testing.T) {
+	schema, err := InferSchema(defaultValues)
+	assert.Nil(t, err)
+	fields := GetFormFields(schema, defaultValues)
+	paths := make([]string, 0, len(fields))
+	for _, field := range fields {
+		paths = append(paths, field.Path)
+	}
+	assert.Equal(t, []string{"replicaCount", "image.repository", "image.tag", "service.type", "service.port", "podAnnotations", "affinity", "ingress.hosts"}, paths)
+	assert.Equal(t, "integer", fields[0].Type)
+	assert.Equal(t, 10.0, *fields[0].Maximum)
+	assert.True(t, fields[3].Required)
+	assert.Equal(t, "array", fields[7].Type)
+	assert.Equal(t, "object", fields[7].ItemType)
+}
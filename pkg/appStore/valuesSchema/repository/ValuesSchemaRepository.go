@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/appStore/valuesSchema/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type AppStoreValuesSchema struct {
+	tableName                    struct{}          `sql:"app_store_values_schema" pg:",discard_unknown_columns"`
+	Id                           int               `sql:"id,pk"`
+	AppStoreApplicationVersionId int               `sql:"app_store_application_version_id,notnull"`
+	Schema                       string            `sql:"schema,notnull"`
+	Source                       bean.SchemaSource `sql:"source,notnull"`
+	sql.AuditLog
+}
+
+type ValuesSchemaRepository interface {
+	// Save stores the schema of the chart version unless one is already stored
+	Save(schema *AppStoreValuesSchema) error
+	FindByAppStoreApplicationVersionId(appStoreApplicationVersionId int) (*AppStoreValuesSchema, error)
+}
+
+type ValuesSchemaRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewValuesSchemaRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ValuesSchemaRepositoryImpl {
+	return &ValuesSchemaRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ValuesSchemaRepositoryImpl) Save(schema *AppStoreValuesSchema) error {
+	_, err := impl.dbConnection.Model(schema).
+		OnConflict("(app_store_application_version_id) DO NOTHING").
+		Insert()
+	return err
+}
+
+func (impl *ValuesSchemaRepositoryImpl) FindByAppStoreApplicationVersionId(appStoreApplicationVersionId int) (*AppStoreValuesSchema, error) {
+	schema := &AppStoreValuesSchema{}
+	err := impl.dbConnection.Model(schema).
+		Where("app_store_application_version_id = ?", appStoreApplicationVersionId).
+		Select()
+	return schema, err
+}
@@ -0,0 +1,706 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appTemplate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/bean/AppView"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	helper2 "github.com/devtron-labs/devtron/internal/sql/repository/helper"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appTemplate/bean"
+	"github.com/devtron-labs/devtron/pkg/appTemplate/helper"
+	"github.com/devtron-labs/devtron/pkg/appTemplate/repository"
+	"github.com/devtron-labs/devtron/pkg/appWorkflow"
+	bean4 "github.com/devtron-labs/devtron/pkg/appWorkflow/bean"
+	"github.com/devtron-labs/devtron/pkg/attributes"
+	bean2 "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/chart"
+	bean5 "github.com/devtron-labs/devtron/pkg/chart/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	bean3 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type AppTemplateService interface {
+	CreateTemplate(request *bean.AppTemplateRequest) (*bean.AppTemplateDto, error)
+	// UpdateTemplate publishes a new version of the template, apps created from older versions are left untouched
+	UpdateTemplate(request *bean.AppTemplateRequest) (*bean.AppTemplateDto, error)
+	// GetTemplate returns the given version of the template, the latest one when version is 0
+	GetTemplate(id int, version int) (*bean.AppTemplateDto, error)
+	GetTemplates() ([]*bean.AppTemplateDto, error)
+	DeleteTemplate(id int, userId int32) error
+
+	CreateApp(ctx context.Context, request *bean.CreateAppFromTemplateRequest) (*bean.TemplateAppDto, error)
+	// GetTemplateApps lists the apps created from the template along with the version they were created from
+	GetTemplateApps(id int) ([]*bean.TemplateAppDto, error)
+}
+
+type AppTemplateServiceImpl struct {
+	logger                  *zap.SugaredLogger
+	appTemplateRepository   repository.AppTemplateRepository
+	appRepository           app.AppRepository
+	pipelineBuilder         pipeline.PipelineBuilder
+	chartService            chart.ChartService
+	configMapService        pipeline.ConfigMapService
+	appWorkflowService      appWorkflow.AppWorkflowService
+	attributesService       attributes.AttributesService
+	gitOpsConfigReadService config.GitOpsConfigReadService
+}
+
+func NewAppTemplateServiceImpl(logger *zap.SugaredLogger,
+	appTemplateRepository repository.AppTemplateRepository,
+	appRepository app.AppRepository,
+	pipelineBuilder pipeline.PipelineBuilder,
+	chartService chart.ChartService,
+	configMapService pipeline.ConfigMapService,
+	appWorkflowService appWorkflow.AppWorkflowService,
+	attributesService attributes.AttributesService,
+	gitOpsConfigReadService config.GitOpsConfigReadService) *AppTemplateServiceImpl {
+	return &AppTemplateServiceImpl{
+		logger:                  logger,
+		appTemplateRepository:   appTemplateRepository,
+		appRepository:           appRepository,
+		pipelineBuilder:         pipelineBuilder,
+		chartService:            chartService,
+		configMapService:        configMapService,
+		appWorkflowService:      appWorkflowService,
+		attributesService:       attributesService,
+		gitOpsConfigReadService: gitOpsConfigReadService,
+	}
+}
+
+func (impl *AppTemplateServiceImpl) CreateTemplate(request *bean.AppTemplateRequest) (*bean.AppTemplateDto, error) {
+	err := impl.validateTemplateRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := impl.appTemplateRepository.FindActiveTemplateByName(request.Name)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching app template by name", "name", request.Name, "err", err)
+		return nil, err
+	}
+	if existing != nil && existing.Id > 0 {
+		return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("app template %s already exists", request.Name), "app template already exists")
+	}
+	template := &repository.AppTemplate{
+		Name:          request.Name,
+		Description:   request.Description,
+		LatestVersion: 1,
+		Active:        true,
+		AuditLog:      sql.NewDefaultAuditLog(request.UserId),
+	}
+	tx, err := impl.appTemplateRepository.StartTx()
+	if err != nil {
+		return nil, err
+	}
+	defer impl.appTemplateRepository.RollbackTx(tx)
+	err = impl.appTemplateRepository.SaveTemplate(template, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving app template", "name", request.Name, "err", err)
+		return nil, err
+	}
+	version, err := impl.saveVersion(template, request, tx)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.appTemplateRepository.CommitTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return adaptToTemplateDto(template, version)
+}
+
+func (impl *AppTemplateServiceImpl) UpdateTemplate(request *bean.AppTemplateRequest) (*bean.AppTemplateDto, error) {
+	err := impl.validateTemplateRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	template, err := impl.getActiveTemplate(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if template.Name != request.Name {
+		existing, err := impl.appTemplateRepository.FindActiveTemplateByName(request.Name)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching app template by name", "name", request.Name, "err", err)
+			return nil, err
+		}
+		if existing != nil && existing.Id > 0 {
+			return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("app template %s already exists", request.Name), "app template already exists")
+		}
+	}
+	template.Name = request.Name
+	template.Description = request.Description
+	template.LatestVersion = template.LatestVersion + 1
+	template.UpdateAuditLog(request.UserId)
+	tx, err := impl.appTemplateRepository.StartTx()
+	if err != nil {
+		return nil, err
+	}
+	defer impl.appTemplateRepository.RollbackTx(tx)
+	err = impl.appTemplateRepository.UpdateTemplate(template, tx)
+	if err != nil {
+		impl.logger.Errorw("error in updating app template", "id", template.Id, "err", err)
+		return nil, err
+	}
+	version, err := impl.saveVersion(template, request, tx)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.appTemplateRepository.CommitTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return adaptToTemplateDto(template, version)
+}
+
+func (impl *AppTemplateServiceImpl) validateTemplateRequest(request *bean.AppTemplateRequest) error {
+	err := helper.ValidateParameters(request.Parameters)
+	if err == nil {
+		err = helper.CheckPlaceholders(request.Definition, request.Parameters)
+	}
+	if err == nil {
+		// a definition is checked by rendering it with sample values, this catches placeholders at places which do
+		// not accept the type of the parameter as well as broken references between materials and pipelines
+		var rendered json.RawMessage
+		rendered, err = helper.RenderDefinition(request.Definition, helper.SampleParameterValues(request.Parameters))
+		if err == nil {
+			_, err = helper.DecodeDefinition(rendered)
+		}
+	}
+	if err != nil {
+		return util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	return nil
+}
+
+func (impl *AppTemplateServiceImpl) saveVersion(template *repository.AppTemplate, request *bean.AppTemplateRequest, tx *pg.Tx) (*repository.AppTemplateVersion, error) {
+	parameters, err := json.Marshal(request.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	version := &repository.AppTemplateVersion{
+		AppTemplateId: template.Id,
+		Version:       template.LatestVersion,
+		Parameters:    string(parameters),
+		Definition:    string(request.Definition),
+		ChangeNote:    request.ChangeNote,
+		AuditLog:      sql.NewDefaultAuditLog(request.UserId),
+	}
+	err = impl.appTemplateRepository.SaveVersion(version, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving app template version", "templateId", template.Id, "version", version.Version, "err", err)
+		return nil, err
+	}
+	return version, nil
+}
+
+func (impl *AppTemplateServiceImpl) getActiveTemplate(id int) (*repository.AppTemplate, error) {
+	template, err := impl.appTemplateRepository.FindActiveTemplateById(id)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "app template not found", fmt.Sprintf("app template %d not found", id))
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching app template", "id", id, "err", err)
+		return nil, err
+	}
+	return template, nil
+}
+
+func (impl *AppTemplateServiceImpl) getVersion(template *repository.AppTemplate, version int) (*repository.AppTemplateVersion, error) {
+	if version == 0 {
+		version = template.LatestVersion
+	}
+	templateVersion, err := impl.appTemplateRepository.FindVersion(template.Id, version)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, fmt.Sprintf("version %d of app template not found", version), "app template version not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching app template version", "templateId", template.Id, "version", version, "err", err)
+		return nil, err
+	}
+	return templateVersion, nil
+}
+
+func (impl *AppTemplateServiceImpl) GetTemplate(id int, version int) (*bean.AppTemplateDto, error) {
+	template, err := impl.getActiveTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+	templateVersion, err := impl.getVersion(template, version)
+	if err != nil {
+		return nil, err
+	}
+	dto, err := adaptToTemplateDto(template, templateVersion)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := impl.appTemplateRepository.FindAllVersions(template.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching app template versions", "templateId", template.Id, "err", err)
+		return nil, err
+	}
+	for _, v := range versions {
+		dto.Versions = append(dto.Versions, &bean.TemplateVersionDto{
+			Version:    v.Version,
+			ChangeNote: v.ChangeNote,
+			CreatedBy:  v.CreatedBy,
+			CreatedOn:  v.CreatedOn,
+		})
+	}
+	return dto, nil
+}
+
+func (impl *AppTemplateServiceImpl) GetTemplates() ([]*bean.AppTemplateDto, error) {
+	templates, err := impl.appTemplateRepository.FindAllActiveTemplates()
+	if err != nil {
+		impl.logger.Errorw("error in fetching app templates", "err", err)
+		return nil, err
+	}
+	dtos := make([]*bean.AppTemplateDto, 0, len(templates))
+	for _, template := range templates {
+		dtos = append(dtos, &bean.AppTemplateDto{
+			Id:            template.Id,
+			Name:          template.Name,
+			Description:   template.Description,
+			LatestVersion: template.LatestVersion,
+			CreatedBy:     template.CreatedBy,
+			CreatedOn:     template.CreatedOn,
+			UpdatedOn:     template.UpdatedOn,
+		})
+	}
+	return dtos, nil
+}
+
+func (impl *AppTemplateServiceImpl) DeleteTemplate(id int, userId int32) error {
+	template, err := impl.getActiveTemplate(id)
+	if err != nil {
+		return err
+	}
+	template.Active = false
+	template.UpdateAuditLog(userId)
+	tx, err := impl.appTemplateRepository.StartTx()
+	if err != nil {
+		return err
+	}
+	defer impl.appTemplateRepository.RollbackTx(tx)
+	err = impl.appTemplateRepository.UpdateTemplate(template, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting app template", "id", id, "err", err)
+		return err
+	}
+	return impl.appTemplateRepository.CommitTx(tx)
+}
+
+func (impl *AppTemplateServiceImpl) CreateApp(ctx context.Context, request *bean.CreateAppFromTemplateRequest) (*bean.TemplateAppDto, error) {
+	template, err := impl.getActiveTemplate(request.TemplateId)
+	if err != nil {
+		return nil, err
+	}
+	templateVersion, err := impl.getVersion(template, request.Version)
+	if err != nil {
+		return nil, err
+	}
+	var parameters []*bean.Parameter
+	if len(templateVersion.Parameters) > 0 {
+		err = json.Unmarshal([]byte(templateVersion.Parameters), &parameters)
+		if err != nil {
+			impl.logger.Errorw("error in decoding app template parameters", "templateId", template.Id, "version", templateVersion.Version, "err", err)
+			return nil, err
+		}
+	}
+	values, err := helper.ResolveParameterValues(parameters, request.Parameters, request.AppName, request.TeamId)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	rendered, err := helper.RenderDefinition(json.RawMessage(templateVersion.Definition), values)
+	if err == nil {
+		var definition *bean.TemplateDefinition
+		definition, err = helper.DecodeDefinition(rendered)
+		if err == nil {
+			return impl.createAppFromDefinition(ctx, request, template, templateVersion, definition, helper.RedactParameterValues(parameters, values))
+		}
+	}
+	return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+}
+
+func (impl *AppTemplateServiceImpl) createAppFromDefinition(ctx context.Context, request *bean.CreateAppFromTemplateRequest, template *repository.AppTemplate,
+	templateVersion *repository.AppTemplateVersion, definition *bean.TemplateDefinition, values map[string]interface{}) (*bean.TemplateAppDto, error) {
+	createAppRequest := &bean2.CreateAppDTO{
+		AppName:   request.AppName,
+		UserId:    request.UserId,
+		TeamId:    request.TeamId,
+		AppLabels: mergeLabels(definition.Labels, request.Labels),
+		AppType:   helper2.CustomApp,
+	}
+	if len(request.Description) > 0 {
+		createAppRequest.GenericNote = &AppView.GenericNoteResponseBean{Description: request.Description}
+	}
+	createAppResponse, err := impl.pipelineBuilder.CreateApp(createAppRequest)
+	if err != nil {
+		impl.logger.Errorw("error in creating app from template", "templateId", template.Id, "appName", request.AppName, "err", err)
+		return nil, err
+	}
+	appId := createAppResponse.Id
+	// every step below creates its part of the app the same way the app clone does, an error leaves the app
+	// partially configured so that it can be completed from the app configuration
+	materialMapping, err := impl.createMaterials(appId, request.UserId, definition.Materials)
+	if err != nil {
+		return nil, err
+	}
+	if definition.BuildConfig != nil {
+		err = impl.createBuildConfig(appId, request.UserId, definition.BuildConfig, materialMapping)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if definition.DeploymentTemplate != nil {
+		templateRequest := bean5.TemplateRequest{
+			AppId:               appId,
+			ValuesOverride:      definition.DeploymentTemplate.ValuesOverride,
+			ChartRefId:          definition.DeploymentTemplate.ChartRefId,
+			IsAppMetricsEnabled: definition.DeploymentTemplate.IsAppMetricsEnabled,
+			IsBasicViewLocked:   definition.DeploymentTemplate.IsBasicViewLocked,
+			CurrentViewEditor:   definition.DeploymentTemplate.CurrentViewEditor,
+			UserId:              request.UserId,
+		}
+		_, err = impl.chartService.Create(templateRequest, ctx)
+		if err != nil {
+			impl.logger.Errorw("error in creating deployment template from app template", "appId", appId, "err", err)
+			return nil, err
+		}
+	}
+	err = impl.createConfigs(appId, request.UserId, definition.ConfigMaps, definition.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	sourceToNewPipelineId := make(map[int]int)
+	for _, workflow := range definition.Workflows {
+		err = impl.createWorkflow(ctx, appId, request.UserId, workflow, materialMapping, sourceToNewPipelineId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	encodedValues, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	templateApp := &repository.AppTemplateApp{
+		AppId:         appId,
+		AppTemplateId: template.Id,
+		Version:       templateVersion.Version,
+		Parameters:    string(encodedValues),
+		AuditLog:      sql.NewDefaultAuditLog(request.UserId),
+	}
+	err = impl.appTemplateRepository.SaveTemplateApp(templateApp)
+	if err != nil {
+		impl.logger.Errorw("error in saving app template app", "appId", appId, "templateId", template.Id, "err", err)
+		return nil, err
+	}
+	return adaptToTemplateAppDto(templateApp, request.AppName, template.LatestVersion), nil
+}
+
+func (impl *AppTemplateServiceImpl) createMaterials(appId int, userId int32, materials []*bean2.GitMaterial) (map[int]int, error) {
+	materialMapping := make(map[int]int)
+	for _, material := range materials {
+		createMaterialRequest := &bean2.CreateMaterialDTO{
+			AppId:  appId,
+			UserId: userId,
+			Material: []*bean2.GitMaterial{{
+				Name:            material.Name,
+				Url:             material.Url,
+				GitProviderId:   material.GitProviderId,
+				CheckoutPath:    material.CheckoutPath,
+				FetchSubmodules: material.FetchSubmodules,
+				FilterPattern:   material.FilterPattern,
+			}},
+		}
+		createMaterialResponse, err := impl.pipelineBuilder.CreateMaterialsForApp(createMaterialRequest)
+		if err != nil {
+			impl.logger.Errorw("error in creating git material from app template", "appId", appId, "url", material.Url, "err", err)
+			return nil, err
+		}
+		materialMapping[material.Id] = createMaterialResponse.Material[0].Id
+	}
+	return materialMapping, nil
+}
+
+func (impl *AppTemplateServiceImpl) createBuildConfig(appId int, userId int32, buildConfig *bean.BuildConfigDefinition, materialMapping map[int]int) error {
+	ciBuildConfig := buildConfig.CiBuildConfig
+	if ciBuildConfig != nil {
+		ciBuildConfig.GitMaterialId, ciBuildConfig.BuildContextGitMaterialId =
+			remapBuildConfigMaterials(ciBuildConfig.GitMaterialId, ciBuildConfig.BuildContextGitMaterialId, materialMapping)
+		ciBuildConfig.Id = 0
+	}
+	ciConfigRequest := &bean2.CiConfigRequest{
+		AppId:             appId,
+		DockerRegistry:    buildConfig.DockerRegistry,
+		DockerRepository:  buildConfig.DockerRepository,
+		DockerRegistryUrl: buildConfig.DockerRegistry,
+		CiBuildConfig:     ciBuildConfig,
+		ScanEnabled:       buildConfig.ScanEnabled,
+		UserId:            userId,
+	}
+	_, err := impl.pipelineBuilder.CreateCiPipeline(ciConfigRequest)
+	if err != nil {
+		impl.logger.Errorw("error in creating build config from app template", "appId", appId, "err", err)
+	}
+	return err
+}
+
+func (impl *AppTemplateServiceImpl) createConfigs(appId int, userId int32, configMaps, secrets []*bean3.ConfigData) error {
+	configRequest, err := impl.configMapService.CMGlobalFetch(appId)
+	if err != nil {
+		return err
+	}
+	configId := configRequest.Id
+	for _, configMap := range configMaps {
+		configRequest, err = impl.configMapService.CMGlobalAddUpdate(&bean3.ConfigDataRequest{
+			AppId:      appId,
+			ConfigData: []*bean3.ConfigData{configMap},
+			UserId:     userId,
+			Id:         configId,
+		})
+		if err != nil {
+			impl.logger.Errorw("error in creating config map from app template", "appId", appId, "name", configMap.Name, "err", err)
+			return err
+		}
+		configId = configRequest.Id
+	}
+	for _, secret := range secrets {
+		configRequest, err = impl.configMapService.CSGlobalAddUpdate(&bean3.ConfigDataRequest{
+			AppId:      appId,
+			ConfigData: []*bean3.ConfigData{secret},
+			UserId:     userId,
+			Id:         configId,
+		})
+		if err != nil {
+			impl.logger.Errorw("error in creating secret from app template", "appId", appId, "name", secret.Name, "err", err)
+			return err
+		}
+		configId = configRequest.Id
+	}
+	return nil
+}
+
+func (impl *AppTemplateServiceImpl) createWorkflow(ctx context.Context, appId int, userId int32, workflow *bean.WorkflowDefinition,
+	materialMapping map[int]int, sourceToNewPipelineId map[int]int) error {
+	appWorkflow, err := impl.appWorkflowService.CreateAppWorkflow(bean4.AppWorkflowDto{
+		Name:   workflow.Name,
+		AppId:  appId,
+		UserId: userId,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating workflow from app template", "appId", appId, "workflow", workflow.Name, "err", err)
+		return err
+	}
+	ciPipeline := workflow.CiPipeline
+	ciPipeline.Id = 0
+	for _, ciMaterial := range ciPipeline.CiMaterial {
+		ciMaterial.Id = 0
+		ciMaterial.GitMaterialId = materialMapping[ciMaterial.GitMaterialId]
+	}
+	if ciPipeline.IsDockerConfigOverridden && ciPipeline.DockerConfigOverride.CiBuildConfig != nil {
+		ciBuildConfig := ciPipeline.DockerConfigOverride.CiBuildConfig
+		ciBuildConfig.GitMaterialId, ciBuildConfig.BuildContextGitMaterialId =
+			remapBuildConfigMaterials(ciBuildConfig.GitMaterialId, ciBuildConfig.BuildContextGitMaterialId, materialMapping)
+		ciBuildConfig.Id = 0
+	}
+	ciConfig, err := impl.pipelineBuilder.PatchCiPipeline(&bean2.CiPatchRequest{
+		CiPipeline:    ciPipeline,
+		AppId:         appId,
+		Action:        bean2.CREATE,
+		AppWorkflowId: appWorkflow.Id,
+		UserId:        userId,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating ci pipeline from app template", "appId", appId, "workflow", workflow.Name, "err", err)
+		return err
+	}
+	ciPipelineId := ciConfig.CiPipelines[0].Id
+	for _, cdPipeline := range workflow.CdPipelines {
+		cdPipeline.CiPipelineId = ciPipelineId
+		cdPipeline.AppWorkflowId = appWorkflow.Id
+		cdPipeline.DeploymentAppType, err = impl.getDeploymentAppType(cdPipeline)
+		if err != nil {
+			return err
+		}
+		// the local id of the pipeline in the definition is used to resolve the parent of the following cd
+		// pipelines, the same way cloned pipelines are resolved
+		cdPipeline.RefPipelineId = cdPipeline.Id
+		cdPipeline.Id = 0
+		cdPipeline.SourceToNewPipelineId = sourceToNewPipelineId
+		if cdPipeline.ParentPipelineType != bean4.CD_PIPELINE_TYPE {
+			cdPipeline.ParentPipelineType = bean4.CI_PIPELINE_TYPE
+			cdPipeline.ParentPipelineId = 0
+		}
+		_, err = impl.pipelineBuilder.CreateCdPipelines(&bean2.CdPipelines{
+			Pipelines:     []*bean2.CDPipelineConfigObject{cdPipeline},
+			AppId:         appId,
+			UserId:        userId,
+			IsCloneAppReq: true,
+		}, ctx)
+		if err != nil {
+			impl.logger.Errorw("error in creating cd pipeline from app template", "appId", appId, "pipeline", cdPipeline.Name, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *AppTemplateServiceImpl) getDeploymentAppType(cdPipeline *bean2.CDPipelineConfigObject) (string, error) {
+	// by default all deployment types are allowed
+	allowedDeploymentAppTypes := map[string]bool{
+		util.PIPELINE_DEPLOYMENT_TYPE_ACD:  true,
+		util.PIPELINE_DEPLOYMENT_TYPE_HELM: true,
+	}
+	deploymentAppConfigForEnvironment, err := impl.attributesService.GetDeploymentEnforcementConfig(cdPipeline.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment config for environment", "envId", cdPipeline.EnvironmentId, "err", err)
+	}
+	for deploymentType, allowed := range deploymentAppConfigForEnvironment {
+		allowedDeploymentAppTypes[deploymentType] = allowed
+	}
+	gitOpsConfigurationStatus, err := impl.gitOpsConfigReadService.IsGitOpsConfigured()
+	if err != nil {
+		impl.logger.Errorw("error in checking if gitOps configured", "err", err)
+		return "", err
+	}
+	if allowedDeploymentAppTypes[cdPipeline.DeploymentAppType] {
+		return cdPipeline.DeploymentAppType, nil
+	} else if allowedDeploymentAppTypes[util.PIPELINE_DEPLOYMENT_TYPE_ACD] && gitOpsConfigurationStatus.IsGitOpsConfiguredAndArgoCdInstalled() {
+		return util.PIPELINE_DEPLOYMENT_TYPE_ACD, nil
+	} else if allowedDeploymentAppTypes[util.PIPELINE_DEPLOYMENT_TYPE_HELM] {
+		return util.PIPELINE_DEPLOYMENT_TYPE_HELM, nil
+	}
+	return cdPipeline.DeploymentAppType, nil
+}
+
+func (impl *AppTemplateServiceImpl) GetTemplateApps(id int) ([]*bean.TemplateAppDto, error) {
+	template, err := impl.getActiveTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+	templateApps, err := impl.appTemplateRepository.FindTemplateApps(template.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching app template apps", "templateId", template.Id, "err", err)
+		return nil, err
+	}
+	appIds := make([]int, 0, len(templateApps))
+	for _, templateApp := range templateApps {
+		appIds = append(appIds, templateApp.AppId)
+	}
+	appNames := make(map[int]string)
+	if len(appIds) > 0 {
+		apps, err := impl.appRepository.FindAppAndProjectByIdsIn(appIds)
+		if err != nil {
+			impl.logger.Errorw("error in fetching apps", "appIds", appIds, "err", err)
+			return nil, err
+		}
+		for _, app := range apps {
+			appNames[app.Id] = app.AppName
+		}
+	}
+	dtos := make([]*bean.TemplateAppDto, 0, len(templateApps))
+	for _, templateApp := range templateApps {
+		appName, ok := appNames[templateApp.AppId]
+		if !ok {
+			// app has been deleted since
+			continue
+		}
+		dtos = append(dtos, adaptToTemplateAppDto(templateApp, appName, template.LatestVersion))
+	}
+	return dtos, nil
+}
+
+// remapBuildConfigMaterials maps the material ids of the definition to the created materials, the build context
+// falls back to the dockerfile material the same way it does for cloned apps
+func remapBuildConfigMaterials(gitMaterialId, buildContextGitMaterialId int, materialMapping map[int]int) (int, int) {
+	gitMaterialId = materialMapping[gitMaterialId]
+	buildContextGitMaterialId = materialMapping[buildContextGitMaterialId]
+	if gitMaterialId == 0 {
+		for _, materialId := range materialMapping {
+			if gitMaterialId == 0 || materialId < gitMaterialId {
+				gitMaterialId = materialId
+			}
+		}
+	}
+	if buildContextGitMaterialId == 0 {
+		buildContextGitMaterialId = gitMaterialId
+	}
+	return gitMaterialId, buildContextGitMaterialId
+}
+
+// mergeLabels adds the labels of the request to the labels of the template, a label of the request replaces the
+// template label with the same key
+func mergeLabels(templateLabels, requestLabels []*bean2.Label) []*bean2.Label {
+	requestKeys := make(map[string]bool)
+	for _, label := range requestLabels {
+		requestKeys[label.Key] = true
+	}
+	labels := make([]*bean2.Label, 0, len(templateLabels)+len(requestLabels))
+	for _, label := range templateLabels {
+		if !requestKeys[label.Key] {
+			labels = append(labels, label)
+		}
+	}
+	return append(labels, requestLabels...)
+}
+
+func adaptToTemplateDto(template *repository.AppTemplate, version *repository.AppTemplateVersion) (*bean.AppTemplateDto, error) {
+	dto := &bean.AppTemplateDto{
+		Id:            template.Id,
+		Name:          template.Name,
+		Description:   template.Description,
+		LatestVersion: template.LatestVersion,
+		Version:       version.Version,
+		ChangeNote:    version.ChangeNote,
+		Definition:    json.RawMessage(version.Definition),
+		CreatedBy:     template.CreatedBy,
+		CreatedOn:     template.CreatedOn,
+		UpdatedOn:     template.UpdatedOn,
+	}
+	if len(version.Parameters) > 0 {
+		err := json.Unmarshal([]byte(version.Parameters), &dto.Parameters)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dto, nil
+}
+
+func adaptToTemplateAppDto(templateApp *repository.AppTemplateApp, appName string, latestVersion int) *bean.TemplateAppDto {
+	dto := &bean.TemplateAppDto{
+		AppId:         templateApp.AppId,
+		AppName:       appName,
+		TemplateId:    templateApp.AppTemplateId,
+		Version:       templateApp.Version,
+		LatestVersion: latestVersion,
+		IsOutdated:    templateApp.Version < latestVersion,
+		CreatedBy:     templateApp.CreatedBy,
+		CreatedOn:     templateApp.CreatedOn,
+	}
+	if len(templateApp.Parameters) > 0 {
+		_ = json.Unmarshal([]byte(templateApp.Parameters), &dto.Parameters)
+	}
+	return dto
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/pkg/bean"
+	buildBean "github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+)
+
+type ParameterType string
+
+const (
+	ParameterTypeString  ParameterType = "string"
+	ParameterTypeNumber  ParameterType = "number"
+	ParameterTypeBoolean ParameterType = "boolean"
+)
+
+// reserved parameters are always available in a template definition, their values come from the app create request
+const (
+	ParameterAppName = "appName"
+	ParameterTeamId  = "teamId"
+)
+
+// RedactedParameterValue replaces the value of sensitive parameters recorded on apps created from a template
+const RedactedParameterValue = "********"
+
+type Parameter struct {
+	Name        string        `json:"name" validate:"required"`
+	Description string        `json:"description,omitempty"`
+	Type        ParameterType `json:"type,omitempty"`
+	Required    bool          `json:"required"`
+	Default     interface{}   `json:"default,omitempty"`
+	// Sensitive values, like tokens used in secrets, are not recorded on the apps created from the template
+	Sensitive bool `json:"sensitive"`
+}
+
+// TemplateDefinition is the configuration of an app created from a template, it is saved with ${{ parameter }}
+// placeholders and decoded only after the parameters are substituted. Ids of materials and cd pipelines are local
+// to the definition and are only used to reference them from the build config, ci materials and parent pipelines
+type TemplateDefinition struct {
+	Labels             []*bean.Label                 `json:"labels,omitempty"`
+	Materials          []*bean.GitMaterial           `json:"materials,omitempty"`
+	BuildConfig        *BuildConfigDefinition        `json:"buildConfig,omitempty"`
+	DeploymentTemplate *DeploymentTemplateDefinition `json:"deploymentTemplate,omitempty"`
+	ConfigMaps         []*pipelineBean.ConfigData    `json:"configMaps,omitempty"`
+	Secrets            []*pipelineBean.ConfigData    `json:"secrets,omitempty"`
+	Workflows          []*WorkflowDefinition         `json:"workflows,omitempty"`
+}
+
+type BuildConfigDefinition struct {
+	DockerRegistry   string                       `json:"dockerRegistry"`
+	DockerRepository string                       `json:"dockerRepository"`
+	CiBuildConfig    *buildBean.CiBuildConfigBean `json:"ciBuildConfig"`
+	ScanEnabled      bool                         `json:"scanEnabled"`
+}
+
+type DeploymentTemplateDefinition struct {
+	ChartRefId          int                         `json:"chartRefId"`
+	ValuesOverride      json.RawMessage             `json:"valuesOverride"`
+	IsAppMetricsEnabled bool                        `json:"isAppMetricsEnabled"`
+	IsBasicViewLocked   bool                        `json:"isBasicViewLocked"`
+	CurrentViewEditor   models.ChartsViewEditorType `json:"currentViewEditor,omitempty"`
+}
+
+// WorkflowDefinition is a workflow with one ci pipeline and the cd pipelines deployed from it, the parent of a cd
+// pipeline is the ci pipeline unless parentPipelineType is CD_PIPELINE
+type WorkflowDefinition struct {
+	Name        string                         `json:"name"`
+	CiPipeline  *bean.CiPipeline               `json:"ciPipeline"`
+	CdPipelines []*bean.CDPipelineConfigObject `json:"cdPipelines,omitempty"`
+}
+
+type AppTemplateRequest struct {
+	Id          int             `json:"-"`
+	Name        string          `json:"name" validate:"required,max=250"`
+	Description string          `json:"description,omitempty"`
+	Parameters  []*Parameter    `json:"parameters,omitempty" validate:"dive"`
+	Definition  json.RawMessage `json:"definition" validate:"required"`
+	ChangeNote  string          `json:"changeNote,omitempty"`
+	UserId      int32           `json:"-"`
+}
+
+type AppTemplateDto struct {
+	Id            int                   `json:"id"`
+	Name          string                `json:"name"`
+	Description   string                `json:"description,omitempty"`
+	LatestVersion int                   `json:"latestVersion"`
+	Version       int                   `json:"version,omitempty"`
+	ChangeNote    string                `json:"changeNote,omitempty"`
+	Parameters    []*Parameter          `json:"parameters,omitempty"`
+	Definition    json.RawMessage       `json:"definition,omitempty"`
+	Versions      []*TemplateVersionDto `json:"versions,omitempty"`
+	CreatedBy     int32                 `json:"createdBy"`
+	CreatedOn     time.Time             `json:"createdOn"`
+	UpdatedOn     time.Time             `json:"updatedOn"`
+}
+
+type TemplateVersionDto struct {
+	Version    int       `json:"version"`
+	ChangeNote string    `json:"changeNote,omitempty"`
+	CreatedBy  int32     `json:"createdBy"`
+	CreatedOn  time.Time `json:"createdOn"`
+}
+
+type CreateAppFromTemplateRequest struct {
+	TemplateId  int                    `json:"-"`
+	Version     int                    `json:"version,omitempty"` // latest version when 0
+	AppName     string                 `json:"appName" validate:"name-component,max=100"`
+	TeamId      int                    `json:"teamId" validate:"number,required"`
+	Description string                 `json:"description,omitempty"`
+	Labels      []*bean.Label          `json:"labels,omitempty" validate:"dive"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	UserId      int32                  `json:"-"`
+}
+
+// TemplateAppDto is an app created from a template with the template version it was created from
+type TemplateAppDto struct {
+	AppId         int                    `json:"appId"`
+	AppName       string                 `json:"appName"`
+	TemplateId    int                    `json:"templateId"`
+	Version       int                    `json:"version"`
+	LatestVersion int                    `json:"latestVersion"`
+	IsOutdated    bool                   `json:"isOutdated"`
+	Parameters    map[string]interface{} `json:"parameters,omitempty"`
+	CreatedBy     int32                  `json:"createdBy"`
+	CreatedOn     time.Time              `json:"createdOn"`
+}
@@ -0,0 +1,317 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/appTemplate/bean"
+	appWorkflowBean "github.com/devtron-labs/devtron/pkg/appWorkflow/bean"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var (
+	placeholderRegex   = regexp.MustCompile(`\$\{\{\s*([a-zA-Z][a-zA-Z0-9_]*)\s*\}\}`)
+	parameterNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+)
+
+// ValidateParameters checks names, types and defaults of the declared parameters of a template
+func ValidateParameters(parameters []*bean.Parameter) error {
+	names := make(map[string]bool)
+	for _, parameter := range parameters {
+		if !parameterNameRegex.MatchString(parameter.Name) {
+			return fmt.Errorf("invalid parameter name %q", parameter.Name)
+		}
+		if parameter.Name == bean.ParameterAppName || parameter.Name == bean.ParameterTeamId {
+			return fmt.Errorf("parameter %q is reserved", parameter.Name)
+		}
+		if names[parameter.Name] {
+			return fmt.Errorf("parameter %q is declared more than once", parameter.Name)
+		}
+		names[parameter.Name] = true
+		switch parameter.Type {
+		case "":
+			parameter.Type = bean.ParameterTypeString
+		case bean.ParameterTypeString, bean.ParameterTypeNumber, bean.ParameterTypeBoolean:
+		default:
+			return fmt.Errorf("unsupported type %q of parameter %q", parameter.Type, parameter.Name)
+		}
+		if parameter.Default != nil {
+			if _, err := convertValue(parameter, parameter.Default); err != nil {
+				return fmt.Errorf("invalid default of parameter %q: %s", parameter.Name, err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// FindPlaceholders returns the sorted names of all parameters used in the definition
+func FindPlaceholders(definition json.RawMessage) []string {
+	found := make(map[string]bool)
+	for _, match := range placeholderRegex.FindAllSubmatch(definition, -1) {
+		found[string(match[1])] = true
+	}
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckPlaceholders verifies that every placeholder of the definition references a declared or reserved parameter
+func CheckPlaceholders(definition json.RawMessage, parameters []*bean.Parameter) error {
+	declared := map[string]bool{bean.ParameterAppName: true, bean.ParameterTeamId: true}
+	for _, parameter := range parameters {
+		declared[parameter.Name] = true
+	}
+	for _, name := range FindPlaceholders(definition) {
+		if !declared[name] {
+			return fmt.Errorf("parameter %q is used in the definition but not declared", name)
+		}
+	}
+	return nil
+}
+
+// ResolveParameterValues validates the values given for an app against the declared parameters, applies defaults
+// and adds the reserved parameters
+func ResolveParameterValues(parameters []*bean.Parameter, values map[string]interface{}, appName string, teamId int) (map[string]interface{}, error) {
+	declared := make(map[string]*bean.Parameter)
+	for _, parameter := range parameters {
+		declared[parameter.Name] = parameter
+	}
+	for name := range values {
+		if _, ok := declared[name]; !ok {
+			return nil, fmt.Errorf("unknown parameter %q", name)
+		}
+	}
+	resolved := map[string]interface{}{
+		bean.ParameterAppName: appName,
+		bean.ParameterTeamId:  json.Number(strconv.Itoa(teamId)),
+	}
+	for _, parameter := range parameters {
+		value, ok := values[parameter.Name]
+		if !ok || value == nil {
+			if parameter.Default == nil {
+				if parameter.Required {
+					return nil, fmt.Errorf("parameter %q is required", parameter.Name)
+				}
+				continue
+			}
+			value = parameter.Default
+		}
+		converted, err := convertValue(parameter, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of parameter %q: %s", parameter.Name, err.Error())
+		}
+		resolved[parameter.Name] = converted
+	}
+	return resolved, nil
+}
+
+// SampleParameterValues returns a value for every parameter, it is used to check that a definition decodes once
+// its placeholders are substituted
+func SampleParameterValues(parameters []*bean.Parameter) map[string]interface{} {
+	values := map[string]interface{}{
+		bean.ParameterAppName: "sample-app",
+		bean.ParameterTeamId:  json.Number("1"),
+	}
+	for _, parameter := range parameters {
+		switch parameter.Type {
+		case bean.ParameterTypeNumber:
+			values[parameter.Name] = json.Number("1")
+		case bean.ParameterTypeBoolean:
+			values[parameter.Name] = true
+		default:
+			values[parameter.Name] = "sample"
+		}
+	}
+	return values
+}
+
+func convertValue(parameter *bean.Parameter, value interface{}) (interface{}, error) {
+	switch parameter.Type {
+	case bean.ParameterTypeNumber:
+		switch typed := value.(type) {
+		case json.Number:
+			return typed, nil
+		case float64:
+			return json.Number(strconv.FormatFloat(typed, 'f', -1, 64)), nil
+		case int:
+			return json.Number(strconv.Itoa(typed)), nil
+		case string:
+			if _, err := strconv.ParseFloat(typed, 64); err == nil {
+				return json.Number(typed), nil
+			}
+		}
+		return nil, fmt.Errorf("expected a number")
+	case bean.ParameterTypeBoolean:
+		switch typed := value.(type) {
+		case bool:
+			return typed, nil
+		case string:
+			if parsed, err := strconv.ParseBool(typed); err == nil {
+				return parsed, nil
+			}
+		}
+		return nil, fmt.Errorf("expected a boolean")
+	default:
+		switch typed := value.(type) {
+		case string:
+			return typed, nil
+		case json.Number:
+			return typed.String(), nil
+		case float64:
+			return strconv.FormatFloat(typed, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(typed), nil
+		}
+		return nil, fmt.Errorf("expected a string")
+	}
+}
+
+// RenderDefinition substitutes the placeholders of the definition, a string which only holds a placeholder takes
+// the typed value of the parameter so that numbers and booleans can be templated, placeholders inside a longer
+// string or an object key are replaced by the text of the value
+func RenderDefinition(definition json.RawMessage, values map[string]interface{}) (json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(definition))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("definition is not valid json: %s", err.Error())
+	}
+	rendered, err := renderNode(document, values)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(rendered)
+}
+
+func renderNode(node interface{}, values map[string]interface{}) (interface{}, error) {
+	switch typed := node.(type) {
+	case map[string]interface{}:
+		rendered := make(map[string]interface{}, len(typed))
+		for key, value := range typed {
+			renderedKey, err := renderText(key, values)
+			if err != nil {
+				return nil, err
+			}
+			if rendered[renderedKey], err = renderNode(value, values); err != nil {
+				return nil, err
+			}
+		}
+		return rendered, nil
+	case []interface{}:
+		rendered := make([]interface{}, len(typed))
+		for i, value := range typed {
+			var err error
+			if rendered[i], err = renderNode(value, values); err != nil {
+				return nil, err
+			}
+		}
+		return rendered, nil
+	case string:
+		if match := placeholderRegex.FindStringSubmatch(typed); match != nil && match[0] == typed {
+			value, ok := values[match[1]]
+			if !ok {
+				return nil, fmt.Errorf("no value for parameter %q", match[1])
+			}
+			return value, nil
+		}
+		return renderText(typed, values)
+	default:
+		return node, nil
+	}
+}
+
+func renderText(text string, values map[string]interface{}) (string, error) {
+	var err error
+	rendered := placeholderRegex.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := placeholderRegex.FindStringSubmatch(placeholder)[1]
+		value, ok := values[name]
+		if !ok {
+			err = fmt.Errorf("no value for parameter %q", name)
+			return placeholder
+		}
+		return fmt.Sprint(value)
+	})
+	return rendered, err
+}
+
+// DecodeDefinition decodes a rendered definition and checks the references between its materials and pipelines
+func DecodeDefinition(rendered json.RawMessage) (*bean.TemplateDefinition, error) {
+	definition := &bean.TemplateDefinition{}
+	if err := json.Unmarshal(rendered, definition); err != nil {
+		return nil, fmt.Errorf("invalid definition: %s", err.Error())
+	}
+	materialIds := make(map[int]bool)
+	for _, material := range definition.Materials {
+		if material.Id <= 0 || materialIds[material.Id] {
+			return nil, fmt.Errorf("every material needs a unique positive id, found %d", material.Id)
+		}
+		materialIds[material.Id] = true
+	}
+	if definition.BuildConfig != nil && definition.BuildConfig.CiBuildConfig != nil {
+		buildConfig := definition.BuildConfig.CiBuildConfig
+		for _, materialId := range []int{buildConfig.GitMaterialId, buildConfig.BuildContextGitMaterialId} {
+			if materialId != 0 && !materialIds[materialId] {
+				return nil, fmt.Errorf("build config references unknown material %d", materialId)
+			}
+		}
+	}
+	if len(definition.Workflows) > 0 && (definition.BuildConfig == nil || len(definition.Materials) == 0) {
+		return nil, fmt.Errorf("workflows need materials and a build config")
+	}
+	cdPipelineIds := make(map[int]bool)
+	for _, workflow := range definition.Workflows {
+		if len(workflow.Name) == 0 || workflow.CiPipeline == nil {
+			return nil, fmt.Errorf("every workflow needs a name and a ci pipeline")
+		}
+		for _, ciMaterial := range workflow.CiPipeline.CiMaterial {
+			if !materialIds[ciMaterial.GitMaterialId] {
+				return nil, fmt.Errorf("ci pipeline of workflow %q references unknown material %d", workflow.Name, ciMaterial.GitMaterialId)
+			}
+		}
+		for _, cdPipeline := range workflow.CdPipelines {
+			if cdPipeline.ParentPipelineType == appWorkflowBean.CD_PIPELINE_TYPE && !cdPipelineIds[cdPipeline.ParentPipelineId] {
+				return nil, fmt.Errorf("cd pipeline %q of workflow %q must follow its parent cd pipeline %d", cdPipeline.Name, workflow.Name, cdPipeline.ParentPipelineId)
+			}
+			if cdPipeline.Id <= 0 || cdPipelineIds[cdPipeline.Id] {
+				return nil, fmt.Errorf("every cd pipeline needs a unique positive id, found %d", cdPipeline.Id)
+			}
+			cdPipelineIds[cdPipeline.Id] = true
+		}
+	}
+	return definition, nil
+}
+
+// RedactParameterValues hides the values of sensitive parameters before they are recorded on the created app
+func RedactParameterValues(parameters []*bean.Parameter, values map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		redacted[name] = value
+	}
+	for _, parameter := range parameters {
+		if _, ok := redacted[parameter.Name]; ok && parameter.Sensitive {
+			redacted[parameter.Name] = bean.RedactedParameterValue
+		}
+	}
+	return redacted
+}
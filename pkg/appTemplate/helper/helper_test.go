@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/devtron-labs/devtron/pkg/appTemplate/bean"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateParameters(t *testing.T) {
+	assert.NoError(t, ValidateParameters([]*bean.Parameter{{Name: "replicas", Type: bean.ParameterTypeNumber, Default: 2}}))
+	assert.Error(t, ValidateParameters([]*bean.Parameter{{Name: "replicas", Type: bean.ParameterTypeNumber, Default: "two"}}))
+	assert.Error(t, ValidateParameters([]*bean.Parameter{{Name: "a"}, {Name: "a"}}))
+	assert.Error(t, ValidateParameters([]*bean.Parameter{{Name: bean.ParameterAppName}}))
+	assert.Error(t, ValidateParameters([]*bean.Parameter{{Name: "1abc"}}))
+}
+
+func TestCheckPlaceholders(t *testing.T) {
+	definition := json.RawMessage(`{"materials":[{"url":"https://github.com/org/${{ appName }}.git","checkoutPath":"${{path}}"}]}`)
+	assert.Equal(t, []string{"appName", "path"}, FindPlaceholders(definition))
+	assert.NoError(t, CheckPlaceholders(definition, []*bean.Parameter{{Name: "path"}}))
+	assert.Error(t, CheckPlaceholders(definition, nil))
+}
+
+func TestResolveAndRender(t *testing.T) {
+	parameters := []*bean.Parameter{
+		{Name: "replicas", Type: bean.ParameterTypeNumber, Default: 1},
+		{Name: "metrics", Type: bean.ParameterTypeBoolean},
+		{Name: "token", Type: bean.ParameterTypeString, Required: true, Sensitive: true},
+	}
+	_, err := ResolveParameterValues(parameters, map[string]interface{}{}, "demo", 3)
+	assert.Error(t, err)
+	_, err = ResolveParameterValues(parameters, map[string]interface{}{"token": "x", "unknown": 1}, "demo", 3)
+	assert.Error(t, err)
+
+	values, err := ResolveParameterValues(parameters, map[string]interface{}{"token": "secret", "metrics": "true"}, "demo", 3)
+	assert.NoError(t, err)
+	rendered, err := RenderDefinition(json.RawMessage(`{"replicas":"${{ replicas }}","metrics":"${{metrics}}","name":"${{appName}}-svc","${{appName}}":"${{teamId}}"}`), values)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"replicas":1,"metrics":true,"name":"demo-svc","demo":3}`, string(rendered))
+
+	redacted := RedactParameterValues(parameters, values)
+	assert.Equal(t, bean.RedactedParameterValue, redacted["token"])
+	assert.Equal(t, "secret", values["token"])
+}
+
+func TestDecodeDefinition(t *testing.T) {
+	_, err := DecodeDefinition(json.RawMessage(`{"materials":[{"id":1},{"id":1}]}`))
+	assert.Error(t, err)
+	_, err = DecodeDefinition(json.RawMessage(`{"materials":[{"id":1}],"buildConfig":{},"workflows":[{"name":"wf","ciPipeline":{"ciMaterial":[{"gitMaterialId":2}]}}]}`))
+	assert.Error(t, err)
+	_, err = DecodeDefinition(json.RawMessage(`{"materials":[{"id":1}],"buildConfig":{},"workflows":[{"name":"wf","ciPipeline":{"ciMaterial":[{"gitMaterialId":1}]},"cdPipelines":[{"id":2,"parentPipelineType":"CD_PIPELINE","parentPipelineId":1},{"id":1}]}]}`))
+	assert.Error(t, err)
+	definition, err := DecodeDefinition(json.RawMessage(`{"materials":[{"id":1}],"buildConfig":{},"workflows":[{"name":"wf","ciPipeline":{"ciMaterial":[{"gitMaterialId":1}]},"cdPipelines":[{"id":1},{"id":2,"parentPipelineType":"CD_PIPELINE","parentPipelineId":1}]}]}`))
+	assert.NoError(t, err)
+	assert.Len(t, definition.Workflows[0].CdPipelines, 2)
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type AppTemplate struct {
+	tableName     struct{} `sql:"app_template" pg:",discard_unknown_columns"`
+	Id            int      `sql:"id,pk"`
+	Name          string   `sql:"name,notnull"`
+	Description   string   `sql:"description"`
+	LatestVersion int      `sql:"latest_version,notnull"`
+	Active        bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// AppTemplateVersion is an immutable published version of a template
+type AppTemplateVersion struct {
+	tableName     struct{} `sql:"app_template_version" pg:",discard_unknown_columns"`
+	Id            int      `sql:"id,pk"`
+	AppTemplateId int      `sql:"app_template_id,notnull"`
+	Version       int      `sql:"version,notnull"`
+	Parameters    string   `sql:"parameters"`
+	Definition    string   `sql:"definition,notnull"`
+	ChangeNote    string   `sql:"change_note"`
+	sql.AuditLog
+}
+
+// AppTemplateApp records the template version and the parameter values an app was created from
+type AppTemplateApp struct {
+	tableName     struct{} `sql:"app_template_app" pg:",discard_unknown_columns"`
+	Id            int      `sql:"id,pk"`
+	AppId         int      `sql:"app_id,notnull"`
+	AppTemplateId int      `sql:"app_template_id,notnull"`
+	Version       int      `sql:"version,notnull"`
+	Parameters    string   `sql:"parameters"`
+	sql.AuditLog
+}
+
+type AppTemplateRepository interface {
+	SaveTemplate(template *AppTemplate, tx *pg.Tx) error
+	UpdateTemplate(template *AppTemplate, tx *pg.Tx) error
+	FindActiveTemplateById(id int) (*AppTemplate, error)
+	FindActiveTemplateByName(name string) (*AppTemplate, error)
+	FindAllActiveTemplates() ([]*AppTemplate, error)
+
+	SaveVersion(version *AppTemplateVersion, tx *pg.Tx) error
+	FindVersion(templateId int, version int) (*AppTemplateVersion, error)
+	FindAllVersions(templateId int) ([]*AppTemplateVersion, error)
+
+	SaveTemplateApp(templateApp *AppTemplateApp) error
+	FindTemplateApps(templateId int) ([]*AppTemplateApp, error)
+	sql.TransactionWrapper
+}
+
+type AppTemplateRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewAppTemplateRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *AppTemplateRepositoryImpl {
+	return &AppTemplateRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: sql.NewTransactionUtilImpl(dbConnection),
+	}
+}
+
+func (impl *AppTemplateRepositoryImpl) SaveTemplate(template *AppTemplate, tx *pg.Tx) error {
+	return tx.Insert(template)
+}
+
+func (impl *AppTemplateRepositoryImpl) UpdateTemplate(template *AppTemplate, tx *pg.Tx) error {
+	return tx.Update(template)
+}
+
+func (impl *AppTemplateRepositoryImpl) FindActiveTemplateById(id int) (*AppTemplate, error) {
+	template := &AppTemplate{}
+	err := impl.dbConnection.Model(template).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return template, err
+}
+
+func (impl *AppTemplateRepositoryImpl) FindActiveTemplateByName(name string) (*AppTemplate, error) {
+	template := &AppTemplate{}
+	err := impl.dbConnection.Model(template).
+		Where("name = ?", name).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return template, err
+}
+
+func (impl *AppTemplateRepositoryImpl) FindAllActiveTemplates() ([]*AppTemplate, error) {
+	var templates []*AppTemplate
+	err := impl.dbConnection.Model(&templates).
+		Where("active = ?", true).
+		Order("name ASC").
+		Select()
+	return templates, err
+}
+
+func (impl *AppTemplateRepositoryImpl) SaveVersion(version *AppTemplateVersion, tx *pg.Tx) error {
+	return tx.Insert(version)
+}
+
+func (impl *AppTemplateRepositoryImpl) FindVersion(templateId int, version int) (*AppTemplateVersion, error) {
+	templateVersion := &AppTemplateVersion{}
+	err := impl.dbConnection.Model(templateVersion).
+		Where("app_template_id = ?", templateId).
+		Where("version = ?", version).
+		Select()
+	return templateVersion, err
+}
+
+func (impl *AppTemplateRepositoryImpl) FindAllVersions(templateId int) ([]*AppTemplateVersion, error) {
+	var versions []*AppTemplateVersion
+	err := impl.dbConnection.Model(&versions).
+		Column("id", "app_template_id", "version", "change_note", "created_on", "created_by", "updated_on", "updated_by").
+		Where("app_template_id = ?", templateId).
+		Order("version DESC").
+		Select()
+	return versions, err
+}
+
+func (impl *AppTemplateRepositoryImpl) SaveTemplateApp(templateApp *AppTemplateApp) error {
+	return impl.dbConnection.Insert(templateApp)
+}
+
+func (impl *AppTemplateRepositoryImpl) FindTemplateApps(templateId int) ([]*AppTemplateApp, error) {
+	var templateApps []*AppTemplateApp
+	err := impl.dbConnection.Model(&templateApps).
+		Where("app_template_id = ?", templateId).
+		Order("id DESC").
+		Select()
+	return templateApps, err
+}
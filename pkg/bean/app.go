@@ -34,6 +34,8 @@ import (
 	bean3 "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	artifactPromotionBean "github.com/devtron-labs/devtron/pkg/policyGovernance/artifactPromotion/bean"
+	imageSigningBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
 	"strings"
 	"time"
 )
@@ -860,35 +862,37 @@ type Rollback struct {
 }
 
 type CiArtifactBean struct {
-	Id                            int                       `json:"id"`
-	Image                         string                    `json:"image,notnull"`
-	ImageDigest                   string                    `json:"image_digest,notnull"`
-	MaterialInfo                  json.RawMessage           `json:"material_info"` //git material metadata json array string
-	DataSource                    string                    `json:"data_source,notnull"`
-	DeployedTime                  string                    `json:"deployed_time"`
-	Deployed                      bool                      `json:"deployed,notnull"`
-	Latest                        bool                      `json:"latest,notnull"`
-	LastSuccessfulTriggerOnParent bool                      `json:"lastSuccessfulTriggerOnParent,notnull"`
-	RunningOnParentCd             bool                      `json:"runningOnParentCd,omitempty"`
-	IsVulnerable                  bool                      `json:"vulnerable,notnull"`
-	ScanEnabled                   bool                      `json:"scanEnabled,notnull"`
-	Scanned                       bool                      `json:"scanned,notnull"`
-	WfrId                         int                       `json:"wfrId"`
-	DeployedBy                    string                    `json:"deployedBy"`
-	CiConfigureSourceType         constants.SourceType      `json:"ciConfigureSourceType"`
-	CiConfigureSourceValue        string                    `json:"ciConfigureSourceValue"`
-	ImageReleaseTags              []*repository2.ImageTag   `json:"imageReleaseTags"`
-	ImageComment                  *repository2.ImageComment `json:"imageComment"`
-	CreatedTime                   string                    `json:"createdTime"`
-	ExternalCiPipelineId          int                       `json:"-"`
-	ParentCiArtifact              int                       `json:"-"`
-	CiWorkflowId                  int                       `json:"-"`
-	RegistryType                  string                    `json:"registryType"`
-	RegistryName                  string                    `json:"registryName"`
-	TargetPlatforms               []*bean4.TargetPlatform   `json:"targetPlatforms"`
-	CiPipelineId                  int                       `json:"-"`
-	CredentialsSourceType         string                    `json:"-"`
-	CredentialsSourceValue        string                    `json:"-"`
+	Id                            int                                        `json:"id"`
+	Image                         string                                     `json:"image,notnull"`
+	ImageDigest                   string                                     `json:"image_digest,notnull"`
+	MaterialInfo                  json.RawMessage                            `json:"material_info"` //git material metadata json array string
+	DataSource                    string                                     `json:"data_source,notnull"`
+	DeployedTime                  string                                     `json:"deployed_time"`
+	Deployed                      bool                                       `json:"deployed,notnull"`
+	Latest                        bool                                       `json:"latest,notnull"`
+	LastSuccessfulTriggerOnParent bool                                       `json:"lastSuccessfulTriggerOnParent,notnull"`
+	RunningOnParentCd             bool                                       `json:"runningOnParentCd,omitempty"`
+	IsVulnerable                  bool                                       `json:"vulnerable,notnull"`
+	ScanEnabled                   bool                                       `json:"scanEnabled,notnull"`
+	Scanned                       bool                                       `json:"scanned,notnull"`
+	WfrId                         int                                        `json:"wfrId"`
+	DeployedBy                    string                                     `json:"deployedBy"`
+	CiConfigureSourceType         constants.SourceType                       `json:"ciConfigureSourceType"`
+	CiConfigureSourceValue        string                                     `json:"ciConfigureSourceValue"`
+	ImageReleaseTags              []*repository2.ImageTag                    `json:"imageReleaseTags"`
+	ImageComment                  *repository2.ImageComment                  `json:"imageComment"`
+	CreatedTime                   string                                     `json:"createdTime"`
+	ExternalCiPipelineId          int                                        `json:"-"`
+	ParentCiArtifact              int                                        `json:"-"`
+	CiWorkflowId                  int                                        `json:"-"`
+	RegistryType                  string                                     `json:"registryType"`
+	RegistryName                  string                                     `json:"registryName"`
+	TargetPlatforms               []*bean4.TargetPlatform                    `json:"targetPlatforms"`
+	ImageSignature                *imageSigningBean.ArtifactSignatureSummary `json:"imageSignature,omitempty"`
+	PromotionMetadata             *artifactPromotionBean.PromotionMetadata   `json:"promotionMetadata,omitempty"`
+	CiPipelineId                  int                                        `json:"-"`
+	CredentialsSourceType         string                                     `json:"-"`
+	CredentialsSourceValue        string                                     `json:"-"`
 }
 
 type CiArtifactResponse struct {
@@ -0,0 +1,883 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bulkEdit
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/common-lib/async"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit/bean"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit/helper"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit/repository"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/configMapAndSecret"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deployedAppMetrics"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/adapter"
+	"github.com/devtron-labs/devtron/pkg/pipeline/history"
+	historyRepository "github.com/devtron-labs/devtron/pkg/pipeline/history/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/variables"
+	variablesRepository "github.com/devtron-labs/devtron/pkg/variables/repository"
+	"go.uber.org/zap"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// staleRunAfter is the time since its last progress update after which a bulk edit left in progress or rolling back
+// is taken as abandoned by its orchestrator, progress is recorded after every target
+const staleRunAfter = 5 * time.Minute
+
+// CheckAuth is called for every target of a bulk edit, envId is bean.ResourceIdBase for base configuration
+type CheckAuth func(appId int, appName string, envId int) bool
+
+type BulkEditService interface {
+	// Preview resolves the targets of the request, renders the diff of every target and saves the bulk edit,
+	// a bulk edit can only be applied after it has been previewed
+	Preview(request *bean.BulkEditRequest, checkAuth CheckAuth) (*bean.BulkEditDto, error)
+	// Apply starts applying a previewed bulk edit in the background
+	Apply(id int, userId int32, checkAuth CheckAuth) (*bean.BulkEditDto, error)
+	// Resume retries the failed and pending targets of a bulk edit which did not complete
+	Resume(id int, userId int32, checkAuth CheckAuth) (*bean.BulkEditDto, error)
+	// Rollback restores every applied target which has not been changed since the bulk edit
+	Rollback(id int, userId int32, checkAuth CheckAuth) (*bean.BulkEditDto, error)
+	GetBulkEdit(id int) (*bean.BulkEditDto, error)
+	GetBulkEdits() ([]*bean.BulkEditDto, error)
+}
+
+type BulkEditServiceImpl struct {
+	logger                           *zap.SugaredLogger
+	bulkEditRepository               repository.BulkEditRepository
+	chartRepository                  chartRepoRepository.ChartRepository
+	envConfigOverrideRepository      chartConfig.EnvConfigOverrideRepository
+	configMapRepository              chartConfig.ConfigMapRepository
+	pipelineConfigRepository         chartConfig.PipelineConfigRepository
+	pipelineRepository               pipelineConfig.PipelineRepository
+	deploymentTemplateHistoryService deploymentTemplate.DeploymentTemplateHistoryService
+	configMapHistoryService          configMapAndSecret.ConfigMapHistoryService
+	pipelineStrategyHistoryService   history.PipelineStrategyHistoryService
+	scopedVariableManager            variables.ScopedVariableManager
+	deployedAppMetricsService        deployedAppMetrics.DeployedAppMetricsService
+	mergeUtil                        util.MergeUtil
+	asyncRunnable                    *async.Runnable
+	// runningBulkEdits holds the ids of bulk edits being applied or rolled back by this process
+	runningBulkEdits sync.Map
+}
+
+func NewBulkEditServiceImpl(logger *zap.SugaredLogger,
+	bulkEditRepository repository.BulkEditRepository,
+	chartRepository chartRepoRepository.ChartRepository,
+	envConfigOverrideRepository chartConfig.EnvConfigOverrideRepository,
+	configMapRepository chartConfig.ConfigMapRepository,
+	pipelineConfigRepository chartConfig.PipelineConfigRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	deploymentTemplateHistoryService deploymentTemplate.DeploymentTemplateHistoryService,
+	configMapHistoryService configMapAndSecret.ConfigMapHistoryService,
+	pipelineStrategyHistoryService history.PipelineStrategyHistoryService,
+	scopedVariableManager variables.ScopedVariableManager,
+	deployedAppMetricsService deployedAppMetrics.DeployedAppMetricsService,
+	mergeUtil util.MergeUtil,
+	asyncRunnable *async.Runnable) *BulkEditServiceImpl {
+	return &BulkEditServiceImpl{
+		logger:                           logger,
+		bulkEditRepository:               bulkEditRepository,
+		chartRepository:                  chartRepository,
+		envConfigOverrideRepository:      envConfigOverrideRepository,
+		configMapRepository:              configMapRepository,
+		pipelineConfigRepository:         pipelineConfigRepository,
+		pipelineRepository:               pipelineRepository,
+		deploymentTemplateHistoryService: deploymentTemplateHistoryService,
+		configMapHistoryService:          configMapHistoryService,
+		pipelineStrategyHistoryService:   pipelineStrategyHistoryService,
+		scopedVariableManager:            scopedVariableManager,
+		deployedAppMetricsService:        deployedAppMetricsService,
+		mergeUtil:                        mergeUtil,
+		asyncRunnable:                    asyncRunnable,
+	}
+}
+
+func (impl *BulkEditServiceImpl) Preview(request *bean.BulkEditRequest, checkAuth CheckAuth) (*bean.BulkEditDto, error) {
+	if err := helper.ValidateRequest(request); err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	targets, err := impl.resolveTargets(request)
+	if err != nil {
+		impl.logger.Errorw("error in resolving bulk edit targets", "request", request, "err", err)
+		return nil, err
+	}
+	if err = impl.checkTargetsAuth(targets, checkAuth); err != nil {
+		return nil, err
+	}
+	requestJson, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	bulkEdit := &repository.BulkEdit{
+		Name:         request.Name,
+		Request:      string(requestJson),
+		Status:       bean.BulkEditStatusPreviewed,
+		TotalTargets: len(targets),
+		AuditLog:     sql.NewDefaultAuditLog(request.UserId),
+	}
+	for _, target := range targets {
+		if target.Status == bean.TargetStatusPending {
+			bulkEdit.ChangedTargets++
+		}
+		target.AuditLog = sql.NewDefaultAuditLog(request.UserId)
+	}
+	tx, err := impl.bulkEditRepository.StartTx()
+	if err != nil {
+		return nil, err
+	}
+	defer impl.bulkEditRepository.RollbackTx(tx)
+	if err = impl.bulkEditRepository.SaveBulkEdit(bulkEdit, tx); err != nil {
+		impl.logger.Errorw("error in saving bulk edit", "err", err)
+		return nil, err
+	}
+	for _, target := range targets {
+		target.BulkEditId = bulkEdit.Id
+	}
+	if err = impl.bulkEditRepository.SaveTargets(targets, tx); err != nil {
+		impl.logger.Errorw("error in saving bulk edit targets", "bulkEditId", bulkEdit.Id, "err", err)
+		return nil, err
+	}
+	if err = impl.bulkEditRepository.CommitTx(tx); err != nil {
+		return nil, err
+	}
+	return impl.buildDto(bulkEdit, targets), nil
+}
+
+func (impl *BulkEditServiceImpl) Apply(id int, userId int32, checkAuth CheckAuth) (*bean.BulkEditDto, error) {
+	bulkEdit, targets, err := impl.getBulkEditForAction(id, checkAuth)
+	if err != nil {
+		return nil, err
+	}
+	if bulkEdit.Status != bean.BulkEditStatusPreviewed {
+		return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("bulk edit is %s, only previewed bulk edits can be applied", bulkEdit.Status), "bulk edit already applied")
+	}
+	return impl.startApply(bulkEdit, targets, userId)
+}
+
+func (impl *BulkEditServiceImpl) Resume(id int, userId int32, checkAuth CheckAuth) (*bean.BulkEditDto, error) {
+	bulkEdit, targets, err := impl.getBulkEditForAction(id, checkAuth)
+	if err != nil {
+		return nil, err
+	}
+	resumable := bulkEdit.Status == bean.BulkEditStatusPartiallyFailed || bulkEdit.Status == bean.BulkEditStatusInProgress
+	if !resumable {
+		return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("bulk edit is %s and can not be resumed", bulkEdit.Status), "bulk edit not resumable")
+	}
+	return impl.startApply(bulkEdit, targets, userId)
+}
+
+func (impl *BulkEditServiceImpl) Rollback(id int, userId int32, checkAuth CheckAuth) (*bean.BulkEditDto, error) {
+	bulkEdit, targets, err := impl.getBulkEditForAction(id, checkAuth)
+	if err != nil {
+		return nil, err
+	}
+	switch bulkEdit.Status {
+	case bean.BulkEditStatusCompleted, bean.BulkEditStatusPartiallyFailed, bean.BulkEditStatusRollbackPartiallyFailed, bean.BulkEditStatusRollingBack:
+	default:
+		return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("bulk edit is %s and can not be rolled back", bulkEdit.Status), "bulk edit not applied")
+	}
+	if err = impl.claimRun(bulkEdit, bean.BulkEditStatusRollingBack, userId); err != nil {
+		return nil, err
+	}
+	impl.asyncRunnable.Execute(func() {
+		impl.rollbackTargets(bulkEdit, targets, userId)
+	})
+	dto := impl.buildDto(bulkEdit, nil)
+	dto.IsRunning = true
+	return dto, nil
+}
+
+func (impl *BulkEditServiceImpl) GetBulkEdit(id int) (*bean.BulkEditDto, error) {
+	bulkEdit, err := impl.bulkEditRepository.FindBulkEditById(id)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "bulk edit not found", fmt.Sprintf("bulk edit %d not found", id))
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching bulk edit", "id", id, "err", err)
+		return nil, err
+	}
+	targets, err := impl.bulkEditRepository.FindTargetsByBulkEditId(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching bulk edit targets", "id", id, "err", err)
+		return nil, err
+	}
+	return impl.buildDto(bulkEdit, targets), nil
+}
+
+func (impl *BulkEditServiceImpl) GetBulkEdits() ([]*bean.BulkEditDto, error) {
+	bulkEdits, err := impl.bulkEditRepository.FindAllBulkEdits()
+	if err != nil {
+		impl.logger.Errorw("error in fetching bulk edits", "err", err)
+		return nil, err
+	}
+	dtos := make([]*bean.BulkEditDto, 0, len(bulkEdits))
+	for _, bulkEdit := range bulkEdits {
+		dto := impl.buildDto(bulkEdit, nil)
+		dto.Request = nil
+		dtos = append(dtos, dto)
+	}
+	return dtos, nil
+}
+
+func (impl *BulkEditServiceImpl) getBulkEditForAction(id int, checkAuth CheckAuth) (*repository.BulkEdit, []*repository.BulkEditTarget, error) {
+	bulkEdit, err := impl.bulkEditRepository.FindBulkEditById(id)
+	if util.IsErrNoRows(err) {
+		return nil, nil, util.NewApiError(http.StatusNotFound, "bulk edit not found", fmt.Sprintf("bulk edit %d not found", id))
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching bulk edit", "id", id, "err", err)
+		return nil, nil, err
+	}
+	targets, err := impl.bulkEditRepository.FindTargetsByBulkEditId(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching bulk edit targets", "id", id, "err", err)
+		return nil, nil, err
+	}
+	if err = impl.checkTargetsAuth(targets, checkAuth); err != nil {
+		return nil, nil, err
+	}
+	return bulkEdit, targets, nil
+}
+
+func (impl *BulkEditServiceImpl) checkTargetsAuth(targets []*repository.BulkEditTarget, checkAuth CheckAuth) error {
+	checked := make(map[string]bool)
+	var forbidden []string
+	for _, target := range targets {
+		key := fmt.Sprintf("%d-%d", target.AppId, target.EnvId)
+		if _, ok := checked[key]; ok {
+			continue
+		}
+		checked[key] = true
+		if !checkAuth(target.AppId, target.AppName, target.EnvId) {
+			if target.EnvId == bean.ResourceIdBase {
+				forbidden = append(forbidden, target.AppName)
+			} else {
+				forbidden = append(forbidden, fmt.Sprintf("%s/%s", target.AppName, target.EnvName))
+			}
+		}
+	}
+	if len(forbidden) > 0 {
+		return util.NewApiError(http.StatusForbidden, fmt.Sprintf("unauthorized for %s", strings.Join(forbidden, ", ")), "unauthorized user")
+	}
+	return nil
+}
+
+func (impl *BulkEditServiceImpl) startApply(bulkEdit *repository.BulkEdit, targets []*repository.BulkEditTarget, userId int32) (*bean.BulkEditDto, error) {
+	request := &bean.BulkEditRequest{}
+	if err := json.Unmarshal([]byte(bulkEdit.Request), request); err != nil {
+		impl.logger.Errorw("error in decoding bulk edit request", "id", bulkEdit.Id, "err", err)
+		return nil, err
+	}
+	if err := impl.claimRun(bulkEdit, bean.BulkEditStatusInProgress, userId); err != nil {
+		return nil, err
+	}
+	impl.asyncRunnable.Execute(func() {
+		impl.applyTargets(bulkEdit, request.Operations, targets, userId)
+	})
+	dto := impl.buildDto(bulkEdit, nil)
+	dto.IsRunning = true
+	return dto, nil
+}
+
+// claimRun moves the bulk edit to status for a run of this process, the update is conditional on the status and
+// last update read so that concurrent requests on different orchestrators start a single run
+func (impl *BulkEditServiceImpl) claimRun(bulkEdit *repository.BulkEdit, status bean.BulkEditStatus, userId int32) error {
+	isRunStatus := bulkEdit.Status == bean.BulkEditStatusInProgress || bulkEdit.Status == bean.BulkEditStatusRollingBack
+	if isRunStatus && time.Since(bulkEdit.UpdatedOn) < staleRunAfter {
+		return util.NewApiError(http.StatusConflict, "bulk edit is already running", "bulk edit already running")
+	}
+	if _, running := impl.runningBulkEdits.LoadOrStore(bulkEdit.Id, true); running {
+		return util.NewApiError(http.StatusConflict, "bulk edit is already running", "bulk edit already running")
+	}
+	expectedStatus, lastUpdatedOn := bulkEdit.Status, bulkEdit.UpdatedOn
+	bulkEdit.Status = status
+	bulkEdit.UpdateAuditLog(userId)
+	claimed, err := impl.bulkEditRepository.UpdateBulkEditIfUnchanged(bulkEdit, expectedStatus, lastUpdatedOn)
+	if err != nil {
+		impl.runningBulkEdits.Delete(bulkEdit.Id)
+		impl.logger.Errorw("error in updating bulk edit status", "id", bulkEdit.Id, "err", err)
+		return err
+	} else if !claimed {
+		impl.runningBulkEdits.Delete(bulkEdit.Id)
+		return util.NewApiError(http.StatusConflict, "bulk edit was changed by another request", "bulk edit changed concurrently")
+	}
+	return nil
+}
+
+func (impl *BulkEditServiceImpl) applyTargets(bulkEdit *repository.BulkEdit, operations []bean.Operation, targets []*repository.BulkEditTarget, userId int32) {
+	defer impl.runningBulkEdits.Delete(bulkEdit.Id)
+	for _, target := range targets {
+		if target.Status != bean.TargetStatusPending && target.Status != bean.TargetStatusFailed {
+			continue
+		}
+		target.Status, target.Error = impl.applyTarget(target, operations, userId)
+		target.UpdateAuditLog(userId)
+		if err := impl.bulkEditRepository.UpdateTarget(target); err != nil {
+			impl.logger.Errorw("error in updating bulk edit target", "targetId", target.Id, "err", err)
+		}
+		impl.updateProgress(bulkEdit, targets, userId, bean.BulkEditStatusInProgress)
+	}
+	status := bean.BulkEditStatusCompleted
+	if bulkEdit.Failed > 0 {
+		status = bean.BulkEditStatusPartiallyFailed
+	}
+	impl.updateProgress(bulkEdit, targets, userId, status)
+}
+
+// applyTarget re-applies the operations on the current document of the target so that changes made after the
+// preview are kept, the document before and after the edit are stored as the change set of the target
+func (impl *BulkEditServiceImpl) applyTarget(target *repository.BulkEditTarget, operations []bean.Operation, userId int32) (bean.TargetStatus, string) {
+	current, values, err := impl.loadDocument(target)
+	if err != nil {
+		return bean.TargetStatusFailed, err.Error()
+	}
+	updated, err := helper.ApplyOperations(current, operations)
+	if err != nil {
+		return bean.TargetStatusFailed, err.Error()
+	}
+	if helper.IsSameDocument(current, updated) {
+		target.Diff = ""
+		return bean.TargetStatusUnchanged, ""
+	}
+	diff, err := helper.RenderDiff(getDocumentName(target), current, updated, target.Kind == bean.TargetKindSecret)
+	if err != nil {
+		return bean.TargetStatusFailed, err.Error()
+	}
+	// the change set is encoded before writing, a target written without it could not be rolled back
+	beforeData, err := encodeChangeSet(target.Kind, current)
+	if err != nil {
+		return bean.TargetStatusFailed, err.Error()
+	}
+	afterData, err := encodeChangeSet(target.Kind, updated)
+	if err != nil {
+		return bean.TargetStatusFailed, err.Error()
+	}
+	if err = impl.writeDocument(target, updated, "", userId); err != nil {
+		impl.logger.Errorw("error in applying bulk edit target", "targetId", target.Id, "err", err)
+		return bean.TargetStatusFailed, err.Error()
+	}
+	target.Diff = diff
+	target.BeforeValues = values
+	target.BeforeData = beforeData
+	target.AfterData = afterData
+	return bean.TargetStatusSucceeded, ""
+}
+
+func (impl *BulkEditServiceImpl) rollbackTargets(bulkEdit *repository.BulkEdit, targets []*repository.BulkEditTarget, userId int32) {
+	defer impl.runningBulkEdits.Delete(bulkEdit.Id)
+	for _, target := range targets {
+		if target.Status != bean.TargetStatusSucceeded && target.Status != bean.TargetStatusRollbackFailed {
+			continue
+		}
+		target.Status, target.Error = impl.rollbackTarget(target, userId)
+		target.UpdateAuditLog(userId)
+		if err := impl.bulkEditRepository.UpdateTarget(target); err != nil {
+			impl.logger.Errorw("error in updating bulk edit target", "targetId", target.Id, "err", err)
+		}
+		impl.updateProgress(bulkEdit, targets, userId, bean.BulkEditStatusRollingBack)
+	}
+	status := bean.BulkEditStatusRolledBack
+	for _, target := range targets {
+		if target.Status == bean.TargetStatusRollbackConflict || target.Status == bean.TargetStatusRollbackFailed {
+			status = bean.BulkEditStatusRollbackPartiallyFailed
+			break
+		}
+	}
+	impl.updateProgress(bulkEdit, targets, userId, status)
+}
+
+// rollbackTarget restores the document before the edit only when it still matches the document written by the
+// bulk edit, a target changed since then is reported as a conflict and left untouched
+func (impl *BulkEditServiceImpl) rollbackTarget(target *repository.BulkEditTarget, userId int32) (bean.TargetStatus, string) {
+	current, _, err := impl.loadDocument(target)
+	if err != nil {
+		return bean.TargetStatusRollbackFailed, err.Error()
+	}
+	before, err := decodeChangeSet(target.Kind, target.BeforeData)
+	if err != nil {
+		return bean.TargetStatusRollbackFailed, err.Error()
+	}
+	after, err := decodeChangeSet(target.Kind, target.AfterData)
+	if err != nil {
+		return bean.TargetStatusRollbackFailed, err.Error()
+	}
+	if !helper.IsSameDocument(current, after) {
+		return bean.TargetStatusRollbackConflict, "target was changed after the bulk edit was applied"
+	}
+	if err = impl.writeDocument(target, before, target.BeforeValues, userId); err != nil {
+		impl.logger.Errorw("error in rolling back bulk edit target", "targetId", target.Id, "err", err)
+		return bean.TargetStatusRollbackFailed, err.Error()
+	}
+	return bean.TargetStatusRolledBack, ""
+}
+
+func (impl *BulkEditServiceImpl) updateProgress(bulkEdit *repository.BulkEdit, targets []*repository.BulkEditTarget, userId int32, status bean.BulkEditStatus) {
+	bulkEdit.Succeeded, bulkEdit.Failed, bulkEdit.RolledBack = 0, 0, 0
+	for _, target := range targets {
+		switch target.Status {
+		case bean.TargetStatusSucceeded, bean.TargetStatusRollbackConflict, bean.TargetStatusRollbackFailed:
+			bulkEdit.Succeeded++
+		case bean.TargetStatusFailed:
+			bulkEdit.Failed++
+		case bean.TargetStatusRolledBack:
+			bulkEdit.Succeeded++
+			bulkEdit.RolledBack++
+		}
+	}
+	bulkEdit.Status = status
+	bulkEdit.UpdateAuditLog(userId)
+	if err := impl.bulkEditRepository.UpdateBulkEdit(bulkEdit); err != nil {
+		impl.logger.Errorw("error in updating bulk edit progress", "id", bulkEdit.Id, "err", err)
+	}
+}
+
+// resolveTargets finds every document matched by the selector and target selectors and renders its preview
+func (impl *BulkEditServiceImpl) resolveTargets(request *bean.BulkEditRequest) ([]*repository.BulkEditTarget, error) {
+	selector := request.Selector
+	scope := selector.Scope
+	if len(scope) == 0 {
+		scope = bean.SelectorScopeAll
+		if len(selector.EnvironmentIds) > 0 || len(selector.ClusterIds) > 0 {
+			scope = bean.SelectorScopeEnvironment
+		}
+	}
+	var scopes []*repository.Scope
+	if scope == bean.SelectorScopeAll || scope == bean.SelectorScopeBase {
+		baseScopes, err := impl.bulkEditRepository.FindBaseScopes(selector)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, baseScopes...)
+	}
+	if scope == bean.SelectorScopeAll || scope == bean.SelectorScopeEnvironment {
+		envScopes, err := impl.bulkEditRepository.FindEnvironmentScopes(selector)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, envScopes...)
+	}
+	var targets []*repository.BulkEditTarget
+	for _, matchedScope := range scopes {
+		if !helper.MatchesChartVersion(selector.ChartVersion, matchedScope.ChartRefVersion) {
+			continue
+		}
+		for _, targetSelector := range request.Targets {
+			scopeTargets, err := impl.resolveScopeTargets(matchedScope, targetSelector)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, scopeTargets...)
+		}
+	}
+	for _, target := range targets {
+		impl.previewTarget(target, request.Operations)
+	}
+	return targets, nil
+}
+
+func (impl *BulkEditServiceImpl) resolveScopeTargets(scope *repository.Scope, targetSelector bean.TargetSelector) ([]*repository.BulkEditTarget, error) {
+	newTarget := func(resourceId int, resourceName string) *repository.BulkEditTarget {
+		return &repository.BulkEditTarget{
+			Kind:         targetSelector.Kind,
+			AppId:        scope.AppId,
+			AppName:      scope.AppName,
+			EnvId:        scope.EnvId,
+			EnvName:      scope.EnvName,
+			ResourceId:   resourceId,
+			ResourceName: resourceName,
+			Status:       bean.TargetStatusPending,
+		}
+	}
+	var targets []*repository.BulkEditTarget
+	switch targetSelector.Kind {
+	case bean.TargetKindDeploymentTemplate:
+		if scope.EnvId == bean.ResourceIdBase {
+			targets = append(targets, newTarget(scope.ChartId, ""))
+		} else if scope.EnvConfigOverrideId > 0 && scope.IsOverride {
+			// environments without an override inherit the base deployment template
+			targets = append(targets, newTarget(scope.EnvConfigOverrideId, ""))
+		}
+	case bean.TargetKindConfigMap, bean.TargetKindSecret:
+		resourceId, configData, err := impl.getConfigData(scope.AppId, scope.EnvId, targetSelector.Kind)
+		if err != nil {
+			return nil, err
+		}
+		if resourceId == 0 {
+			return nil, nil
+		}
+		names, err := helper.GetConfigNames(configData, targetSelector.Kind)
+		if err != nil {
+			impl.logger.Errorw("error in reading config names", "appId", scope.AppId, "envId", scope.EnvId, "err", err)
+			return nil, err
+		}
+		for _, name := range names {
+			if len(targetSelector.Names) == 0 || containsString(targetSelector.Names, name) {
+				targets = append(targets, newTarget(resourceId, name))
+			}
+		}
+	case bean.TargetKindPipelineStrategy:
+		if scope.EnvId == bean.ResourceIdBase {
+			return nil, nil
+		}
+		strategies, err := impl.pipelineConfigRepository.GetAllStrategyByPipelineId(scope.PipelineId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching pipeline strategies", "pipelineId", scope.PipelineId, "err", err)
+			return nil, err
+		}
+		sort.Slice(strategies, func(i, j int) bool { return strategies[i].Strategy < strategies[j].Strategy })
+		for _, strategy := range strategies {
+			if len(targetSelector.Strategies) == 0 || containsString(targetSelector.Strategies, string(strategy.Strategy)) {
+				targets = append(targets, newTarget(strategy.Id, string(strategy.Strategy)))
+			}
+		}
+	}
+	return targets, nil
+}
+
+func (impl *BulkEditServiceImpl) previewTarget(target *repository.BulkEditTarget, operations []bean.Operation) {
+	current, _, err := impl.loadDocument(target)
+	if err == nil {
+		var updated []byte
+		updated, err = helper.ApplyOperations(current, operations)
+		if err == nil {
+			target.Diff, err = helper.RenderDiff(getDocumentName(target), current, updated, target.Kind == bean.TargetKindSecret)
+			if err == nil && helper.IsSameDocument(current, updated) {
+				target.Status, target.Diff = bean.TargetStatusUnchanged, ""
+			}
+		}
+	}
+	if err != nil {
+		target.Status, target.Error, target.Diff = bean.TargetStatusInvalid, err.Error(), ""
+	}
+}
+
+// getConfigData returns the id and the configmap or secret json of the app or app environment, the id is 0 when
+// nothing is configured
+func (impl *BulkEditServiceImpl) getConfigData(appId, envId int, kind bean.TargetKind) (int, string, error) {
+	if envId == bean.ResourceIdBase {
+		model, err := impl.configMapRepository.GetByAppIdAppLevel(appId)
+		if util.IsErrNoRows(err) {
+			return 0, "", nil
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching app level config", "appId", appId, "err", err)
+			return 0, "", err
+		}
+		return model.Id, getConfigModelData(model.ConfigMapData, model.SecretData, kind), nil
+	}
+	model, err := impl.configMapRepository.GetByAppIdAndEnvIdEnvLevel(appId, envId)
+	if util.IsErrNoRows(err) {
+		return 0, "", nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching env level config", "appId", appId, "envId", envId, "err", err)
+		return 0, "", err
+	}
+	if model.Deleted {
+		return 0, "", nil
+	}
+	return model.Id, getConfigModelData(model.ConfigMapData, model.SecretData, kind), nil
+}
+
+// loadDocument returns the current json document of the target, for base deployment templates the merged chart
+// values are returned as well
+func (impl *BulkEditServiceImpl) loadDocument(target *repository.BulkEditTarget) ([]byte, string, error) {
+	switch target.Kind {
+	case bean.TargetKindDeploymentTemplate:
+		if target.EnvId == bean.ResourceIdBase {
+			chart, err := impl.chartRepository.FindById(target.ResourceId)
+			if err != nil {
+				return nil, "", err
+			}
+			if !chart.Latest {
+				return nil, "", fmt.Errorf("deployment template of %s has changed chart since the preview", target.AppName)
+			}
+			return []byte(chart.GlobalOverride), chart.Values, nil
+		}
+		envOverride, err := impl.envConfigOverrideRepository.GetByIdIncludingInactive(target.ResourceId)
+		if err != nil {
+			return nil, "", err
+		}
+		if !envOverride.Latest || !envOverride.Active || !envOverride.IsOverride {
+			return nil, "", fmt.Errorf("deployment template override of %s/%s is no longer in use", target.AppName, target.EnvName)
+		}
+		return []byte(envOverride.EnvOverrideValues), "", nil
+	case bean.TargetKindConfigMap, bean.TargetKindSecret:
+		var configData string
+		if target.EnvId == bean.ResourceIdBase {
+			model, err := impl.configMapRepository.GetByIdAppLevel(target.ResourceId)
+			if err != nil {
+				return nil, "", err
+			}
+			configData = getConfigModelData(model.ConfigMapData, model.SecretData, target.Kind)
+		} else {
+			model, err := impl.configMapRepository.GetByIdEnvLevel(target.ResourceId)
+			if err != nil {
+				return nil, "", err
+			}
+			if model.Deleted {
+				return nil, "", fmt.Errorf("%s of %s/%s has been deleted", target.Kind, target.AppName, target.EnvName)
+			}
+			configData = getConfigModelData(model.ConfigMapData, model.SecretData, target.Kind)
+		}
+		data, err := helper.GetConfigEntryData(configData, target.Kind, target.ResourceName)
+		return data, "", err
+	case bean.TargetKindPipelineStrategy:
+		strategy, err := impl.pipelineConfigRepository.FindById(target.ResourceId)
+		if err != nil {
+			return nil, "", err
+		}
+		if strategy.Deleted {
+			return nil, "", fmt.Errorf("%s strategy of %s/%s has been deleted", strategy.Strategy, target.AppName, target.EnvName)
+		}
+		return []byte(strategy.Config), "", nil
+	}
+	return nil, "", fmt.Errorf("unsupported target kind %q", target.Kind)
+}
+
+// writeDocument saves the document of the target along with the history and variable mappings created by the
+// regular update flows, values restores the merged chart values of a base deployment template on rollback
+func (impl *BulkEditServiceImpl) writeDocument(target *repository.BulkEditTarget, document []byte, values string, userId int32) error {
+	switch target.Kind {
+	case bean.TargetKindDeploymentTemplate:
+		if target.EnvId == bean.ResourceIdBase {
+			return impl.writeBaseDeploymentTemplate(target, document, values, userId)
+		}
+		return impl.writeEnvDeploymentTemplate(target, document, userId)
+	case bean.TargetKindConfigMap, bean.TargetKindSecret:
+		return impl.writeConfigData(target, document, userId)
+	case bean.TargetKindPipelineStrategy:
+		return impl.writePipelineStrategy(target, document, userId)
+	}
+	return fmt.Errorf("unsupported target kind %q", target.Kind)
+}
+
+func (impl *BulkEditServiceImpl) writeBaseDeploymentTemplate(target *repository.BulkEditTarget, document []byte, values string, userId int32) error {
+	chart, err := impl.chartRepository.FindById(target.ResourceId)
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		merged, err := impl.mergeUtil.JsonPatch([]byte(chart.Values), document)
+		if err != nil {
+			return err
+		}
+		values = string(merged)
+	}
+	chart.GlobalOverride = string(document)
+	chart.Values = values
+	chart.UpdatedOn = time.Now()
+	chart.UpdatedBy = userId
+	if err = impl.chartRepository.Update(chart); err != nil {
+		return err
+	}
+	isAppMetricsEnabled, err := impl.deployedAppMetricsService.GetMetricsFlagByAppId(chart.AppId)
+	if err != nil {
+		impl.logger.Errorw("error in getting app level metrics", "appId", chart.AppId, "err", err)
+		return err
+	}
+	if err = impl.deploymentTemplateHistoryService.CreateDeploymentTemplateHistoryFromGlobalTemplate(chart, nil, isAppMetricsEnabled); err != nil {
+		impl.logger.Errorw("error in creating deployment template history", "chartId", chart.Id, "err", err)
+	}
+	return impl.scopedVariableManager.ExtractAndMapVariables(chart.GlobalOverride, chart.Id, variablesRepository.EntityTypeDeploymentTemplateAppLevel, userId, nil)
+}
+
+func (impl *BulkEditServiceImpl) writeEnvDeploymentTemplate(target *repository.BulkEditTarget, document []byte, userId int32) error {
+	envOverride, err := impl.envConfigOverrideRepository.GetByIdIncludingInactive(target.ResourceId)
+	if err != nil {
+		return err
+	}
+	envOverride.EnvOverrideValues = string(document)
+	envOverride.UpdatedOn = time.Now()
+	envOverride.UpdatedBy = userId
+	if _, err = impl.envConfigOverrideRepository.Update(envOverride); err != nil {
+		return err
+	}
+	isAppMetricsEnabled, err := impl.deployedAppMetricsService.GetMetricsFlagForAPipelineByAppIdAndEnvId(target.AppId, target.EnvId)
+	if err != nil {
+		impl.logger.Errorw("error in getting env level metrics", "appId", target.AppId, "envId", target.EnvId, "err", err)
+		return err
+	}
+	if err = impl.deploymentTemplateHistoryService.CreateDeploymentTemplateHistoryFromEnvOverrideTemplate(adapter.EnvOverrideDBToDTO(envOverride), nil, isAppMetricsEnabled, 0); err != nil {
+		impl.logger.Errorw("error in creating env deployment template history", "envOverrideId", envOverride.Id, "err", err)
+	}
+	return impl.scopedVariableManager.ExtractAndMapVariables(envOverride.EnvOverrideValues, envOverride.Id, variablesRepository.EntityTypeDeploymentTemplateEnvLevel, userId, nil)
+}
+
+func (impl *BulkEditServiceImpl) writeConfigData(target *repository.BulkEditTarget, document []byte, userId int32) error {
+	configType := historyRepository.CONFIGMAP_TYPE
+	if target.Kind == bean.TargetKindSecret {
+		configType = historyRepository.SECRET_TYPE
+	}
+	if target.EnvId == bean.ResourceIdBase {
+		model, err := impl.configMapRepository.GetByIdAppLevel(target.ResourceId)
+		if err != nil {
+			return err
+		}
+		if model.ConfigMapData, model.SecretData, err = setConfigModelData(model.ConfigMapData, model.SecretData, target, document); err != nil {
+			return err
+		}
+		model.UpdatedOn = time.Now()
+		model.UpdatedBy = userId
+		if _, err = impl.configMapRepository.UpdateAppLevel(model); err != nil {
+			return err
+		}
+		if err = impl.configMapHistoryService.CreateHistoryFromAppLevelConfig(model, configType); err != nil {
+			impl.logger.Errorw("error in creating app level config history", "appId", model.AppId, "err", err)
+		}
+		return nil
+	}
+	model, err := impl.configMapRepository.GetByIdEnvLevel(target.ResourceId)
+	if err != nil {
+		return err
+	}
+	if model.ConfigMapData, model.SecretData, err = setConfigModelData(model.ConfigMapData, model.SecretData, target, document); err != nil {
+		return err
+	}
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+	if _, err = impl.configMapRepository.UpdateEnvLevel(model); err != nil {
+		return err
+	}
+	if err = impl.configMapHistoryService.CreateHistoryFromEnvLevelConfig(model, configType); err != nil {
+		impl.logger.Errorw("error in creating env level config history", "appId", model.AppId, "envId", model.EnvironmentId, "err", err)
+	}
+	return nil
+}
+
+func (impl *BulkEditServiceImpl) writePipelineStrategy(target *repository.BulkEditTarget, document []byte, userId int32) error {
+	strategy, err := impl.pipelineConfigRepository.FindById(target.ResourceId)
+	if err != nil {
+		return err
+	}
+	pipeline, err := impl.pipelineRepository.FindById(strategy.PipelineId)
+	if err != nil {
+		return err
+	}
+	strategy.Config = string(document)
+	strategy.UpdatedOn = time.Now()
+	strategy.UpdatedBy = userId
+	tx, err := impl.bulkEditRepository.StartTx()
+	if err != nil {
+		return err
+	}
+	defer impl.bulkEditRepository.RollbackTx(tx)
+	if err = impl.pipelineConfigRepository.Update(strategy, tx); err != nil {
+		return err
+	}
+	if _, err = impl.pipelineStrategyHistoryService.CreatePipelineStrategyHistory(strategy, pipeline.TriggerType, tx); err != nil {
+		return err
+	}
+	return impl.bulkEditRepository.CommitTx(tx)
+}
+
+func (impl *BulkEditServiceImpl) buildDto(bulkEdit *repository.BulkEdit, targets []*repository.BulkEditTarget) *bean.BulkEditDto {
+	dto := &bean.BulkEditDto{
+		Id:             bulkEdit.Id,
+		Name:           bulkEdit.Name,
+		Status:         bulkEdit.Status,
+		TotalTargets:   bulkEdit.TotalTargets,
+		ChangedTargets: bulkEdit.ChangedTargets,
+		Succeeded:      bulkEdit.Succeeded,
+		Failed:         bulkEdit.Failed,
+		RolledBack:     bulkEdit.RolledBack,
+		CreatedBy:      bulkEdit.CreatedBy,
+		CreatedOn:      bulkEdit.CreatedOn,
+		UpdatedOn:      bulkEdit.UpdatedOn,
+	}
+	_, dto.IsRunning = impl.runningBulkEdits.Load(bulkEdit.Id)
+	request := &bean.BulkEditRequest{}
+	if err := json.Unmarshal([]byte(bulkEdit.Request), request); err == nil {
+		dto.Request = request
+	}
+	for _, target := range targets {
+		dto.Targets = append(dto.Targets, &bean.TargetDto{
+			Id:           target.Id,
+			Kind:         target.Kind,
+			AppId:        target.AppId,
+			AppName:      target.AppName,
+			EnvId:        target.EnvId,
+			EnvName:      target.EnvName,
+			ResourceId:   target.ResourceId,
+			ResourceName: target.ResourceName,
+			Status:       target.Status,
+			Diff:         target.Diff,
+			Error:        target.Error,
+		})
+	}
+	return dto
+}
+
+func getDocumentName(target *repository.BulkEditTarget) string {
+	name := target.AppName
+	if target.EnvId != bean.ResourceIdBase {
+		name = fmt.Sprintf("%s/%s", name, target.EnvName)
+	}
+	name = fmt.Sprintf("%s/%s", name, target.Kind)
+	if len(target.ResourceName) > 0 {
+		name = fmt.Sprintf("%s/%s", name, target.ResourceName)
+	}
+	return name
+}
+
+// encodeChangeSet keeps secret values of the change set base64 encoded as they are stored for the secret itself
+func encodeChangeSet(kind bean.TargetKind, document []byte) (string, error) {
+	if kind != bean.TargetKindSecret {
+		return string(document), nil
+	}
+	encoded, err := helper.EncodeSecretData(document)
+	return string(encoded), err
+}
+
+func decodeChangeSet(kind bean.TargetKind, changeSet string) ([]byte, error) {
+	if kind != bean.TargetKindSecret {
+		return []byte(changeSet), nil
+	}
+	return helper.DecodeSecretData([]byte(changeSet))
+}
+
+func getConfigModelData(configMapData, secretData string, kind bean.TargetKind) string {
+	if kind == bean.TargetKindSecret {
+		return secretData
+	}
+	return configMapData
+}
+
+func setConfigModelData(configMapData, secretData string, target *repository.BulkEditTarget, document []byte) (string, string, error) {
+	var err error
+	if target.Kind == bean.TargetKindSecret {
+		secretData, err = helper.SetConfigEntryData(secretData, target.Kind, target.ResourceName, document)
+	} else {
+		configMapData, err = helper.SetConfigEntryData(configMapData, target.Kind, target.ResourceName, document)
+	}
+	return configMapData, secretData, err
+}
+
+func containsString(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type BulkEditStatus string
+
+const (
+	BulkEditStatusPreviewed               BulkEditStatus = "previewed"
+	BulkEditStatusInProgress              BulkEditStatus = "in_progress"
+	BulkEditStatusCompleted               BulkEditStatus = "completed"
+	BulkEditStatusPartiallyFailed         BulkEditStatus = "partially_failed"
+	BulkEditStatusRollingBack             BulkEditStatus = "rolling_back"
+	BulkEditStatusRolledBack              BulkEditStatus = "rolled_back"
+	BulkEditStatusRollbackPartiallyFailed BulkEditStatus = "rollback_partially_failed"
+)
+
+type TargetStatus string
+
+const (
+	TargetStatusPending          TargetStatus = "pending"
+	TargetStatusUnchanged        TargetStatus = "unchanged"
+	TargetStatusInvalid          TargetStatus = "invalid"
+	TargetStatusSucceeded        TargetStatus = "succeeded"
+	TargetStatusFailed           TargetStatus = "failed"
+	TargetStatusRolledBack       TargetStatus = "rolled_back"
+	TargetStatusRollbackConflict TargetStatus = "rollback_conflict"
+	TargetStatusRollbackFailed   TargetStatus = "rollback_failed"
+)
+
+type TargetKind string
+
+const (
+	TargetKindDeploymentTemplate TargetKind = "deployment-template"
+	TargetKindConfigMap          TargetKind = "configmap"
+	TargetKindSecret             TargetKind = "secret"
+	TargetKindPipelineStrategy   TargetKind = "pipeline-strategy"
+)
+
+type OperationType string
+
+const (
+	OperationTypeJsonPatch  OperationType = "jsonPatch"
+	OperationTypeMergePatch OperationType = "mergePatch"
+	OperationTypeSet        OperationType = "set"
+	OperationTypeDelete     OperationType = "delete"
+)
+
+type SelectorScope string
+
+const (
+	SelectorScopeAll         SelectorScope = "all"
+	SelectorScopeBase        SelectorScope = "base"
+	SelectorScopeEnvironment SelectorScope = "environment"
+)
+
+// ResourceIdBase is the environment id used for targets on base (app level) configuration
+const ResourceIdBase = 0
+
+const RedactedValuePrefix = "<redacted:"
+
+type LabelSelector struct {
+	Key string `json:"key" validate:"required"`
+	// Value is optional, an empty value matches any label with the key
+	Value string `json:"value,omitempty"`
+}
+
+type NameSelector struct {
+	Includes []string `json:"includes,omitempty"`
+	Excludes []string `json:"excludes,omitempty"`
+}
+
+type Selector struct {
+	AppNames       *NameSelector   `json:"appNames,omitempty"`
+	Labels         []LabelSelector `json:"labels,omitempty" validate:"dive"`
+	TeamIds        []int           `json:"teamIds,omitempty"`
+	EnvironmentIds []int           `json:"environmentIds,omitempty"`
+	ClusterIds     []int           `json:"clusterIds,omitempty"`
+	ChartRefIds    []int           `json:"chartRefIds,omitempty"`
+	ChartNames     []string        `json:"chartNames,omitempty"`
+	// ChartVersion is a semver constraint matched against the chart ref version, e.g. ">=4.18.0 <5.0.0"
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// Scope defaults to environment when environments or clusters are selected and to all otherwise
+	Scope SelectorScope `json:"scope,omitempty"`
+}
+
+type TargetSelector struct {
+	Kind TargetKind `json:"kind" validate:"required,oneof=deployment-template configmap secret pipeline-strategy"`
+	// Names filters configmaps and secrets by name, empty selects all
+	Names []string `json:"names,omitempty"`
+	// Strategies filters pipeline strategies, e.g. ROLLING or CANARY, empty selects all
+	Strategies []string `json:"strategies,omitempty"`
+}
+
+type Operation struct {
+	Type OperationType `json:"type" validate:"required,oneof=jsonPatch mergePatch set delete"`
+	// Patch is a RFC 6902 patch for jsonPatch and a RFC 7386 document for mergePatch
+	Patch json.RawMessage `json:"patch,omitempty"`
+	// Path is a yaml path like spec.containers[0].image used by set and delete
+	Path  string      `json:"path,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type BulkEditRequest struct {
+	Name       string           `json:"name,omitempty"`
+	Selector   *Selector        `json:"selector" validate:"required"`
+	Targets    []TargetSelector `json:"targets" validate:"required,min=1,dive"`
+	Operations []Operation      `json:"operations" validate:"required,min=1,dive"`
+	UserId     int32            `json:"-"`
+}
+
+type BulkEditDto struct {
+	Id             int              `json:"id"`
+	Name           string           `json:"name"`
+	Status         BulkEditStatus   `json:"status"`
+	Request        *BulkEditRequest `json:"request,omitempty"`
+	TotalTargets   int              `json:"totalTargets"`
+	ChangedTargets int              `json:"changedTargets"`
+	Succeeded      int              `json:"succeeded"`
+	Failed         int              `json:"failed"`
+	RolledBack     int              `json:"rolledBack"`
+	IsRunning      bool             `json:"isRunning"`
+	CreatedBy      int32            `json:"createdBy"`
+	CreatedOn      time.Time        `json:"createdOn"`
+	UpdatedOn      time.Time        `json:"updatedOn"`
+	Targets        []*TargetDto     `json:"targets,omitempty"`
+}
+
+type TargetDto struct {
+	Id           int          `json:"id"`
+	Kind         TargetKind   `json:"kind"`
+	AppId        int          `json:"appId"`
+	AppName      string       `json:"appName"`
+	EnvId        int          `json:"envId"`
+	EnvName      string       `json:"envName,omitempty"`
+	ResourceId   int          `json:"resourceId"`
+	ResourceName string       `json:"resourceName,omitempty"`
+	Status       TargetStatus `json:"status"`
+	Diff         string       `json:"diff,omitempty"`
+	Error        string       `json:"error,omitempty"`
+}
@@ -0,0 +1,503 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/semver/v3"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit/bean"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pmezard/go-difflib/difflib"
+	"reflect"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrConfigEntryNotFound = errors.New("config entry not found")
+
+const (
+	configMapListKey = "maps"
+	secretListKey    = "secrets"
+	configNameKey    = "name"
+	configDataKey    = "data"
+)
+
+// ValidateRequest checks the selector, targets and operations of a bulk edit before any target is resolved
+func ValidateRequest(request *bean.BulkEditRequest) error {
+	selector := request.Selector
+	switch selector.Scope {
+	case "", bean.SelectorScopeAll, bean.SelectorScopeBase, bean.SelectorScopeEnvironment:
+	default:
+		return fmt.Errorf("invalid selector scope %q", selector.Scope)
+	}
+	if len(selector.ChartVersion) > 0 {
+		if _, err := semver.NewConstraint(selector.ChartVersion); err != nil {
+			return fmt.Errorf("invalid chart version constraint %q: %s", selector.ChartVersion, err.Error())
+		}
+	}
+	for _, target := range request.Targets {
+		if target.Kind == bean.TargetKindPipelineStrategy && selector.Scope == bean.SelectorScopeBase {
+			return fmt.Errorf("pipeline strategies can not be edited with selector scope %q", bean.SelectorScopeBase)
+		}
+	}
+	for i, operation := range request.Operations {
+		if err := ValidateOperation(operation); err != nil {
+			return fmt.Errorf("invalid operation at index %d: %s", i, err.Error())
+		}
+	}
+	return nil
+}
+
+// ValidateOperation checks that the patch or path of a single operation can be parsed
+func ValidateOperation(operation bean.Operation) error {
+	switch operation.Type {
+	case bean.OperationTypeJsonPatch:
+		if _, err := jsonpatch.DecodePatch(operation.Patch); err != nil {
+			return fmt.Errorf("invalid json patch: %s", err.Error())
+		}
+	case bean.OperationTypeMergePatch:
+		patch := map[string]interface{}{}
+		if err := json.Unmarshal(operation.Patch, &patch); err != nil {
+			return fmt.Errorf("merge patch must be a json object: %s", err.Error())
+		}
+	case bean.OperationTypeSet, bean.OperationTypeDelete:
+		if _, err := ParsePath(operation.Path); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported operation type %q", operation.Type)
+	}
+	return nil
+}
+
+// ParsePath splits a yaml path like .spec.containers[0].image or metadata.labels["app.kubernetes.io/name"]
+// into map keys (string) and list indices (int)
+func ParsePath(path string) ([]interface{}, error) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	if len(path) == 0 || path == "." {
+		return nil, fmt.Errorf("path can not be empty")
+	}
+	var segments []interface{}
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			i++
+			end := i
+			for end < len(path) && path[end] != '.' && path[end] != '[' {
+				end++
+			}
+			if end == i {
+				return nil, fmt.Errorf("invalid path %q: empty key at position %d", path, i)
+			}
+			segments = append(segments, path[i:end])
+			i = end
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: missing closing bracket", path)
+			}
+			token := path[i+1 : i+end]
+			i += end + 1
+			if unquoted, err := strconv.Unquote(token); err == nil {
+				segments = append(segments, unquoted)
+				continue
+			}
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid path %q: %q is not a list index or quoted key", path, token)
+			}
+			segments = append(segments, index)
+		default:
+			if i != 0 {
+				return nil, fmt.Errorf("invalid path %q at position %d", path, i)
+			}
+			path = "." + path
+		}
+	}
+	return segments, nil
+}
+
+// ApplyOperations applies the operations in order on a json document, an empty document is treated as an empty object
+func ApplyOperations(document []byte, operations []bean.Operation) ([]byte, error) {
+	if len(bytes.TrimSpace(document)) == 0 {
+		document = []byte("{}")
+	}
+	var err error
+	for i, operation := range operations {
+		document, err = applyOperation(document, operation)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s): %s", i, operation.Type, err.Error())
+		}
+	}
+	return document, nil
+}
+
+func applyOperation(document []byte, operation bean.Operation) ([]byte, error) {
+	switch operation.Type {
+	case bean.OperationTypeJsonPatch:
+		patch, err := jsonpatch.DecodePatch(operation.Patch)
+		if err != nil {
+			return nil, err
+		}
+		return patch.Apply(document)
+	case bean.OperationTypeMergePatch:
+		return jsonpatch.MergePatch(document, operation.Patch)
+	case bean.OperationTypeSet, bean.OperationTypeDelete:
+		segments, err := ParsePath(operation.Path)
+		if err != nil {
+			return nil, err
+		}
+		var root interface{}
+		if err = json.Unmarshal(document, &root); err != nil {
+			return nil, err
+		}
+		if operation.Type == bean.OperationTypeSet {
+			root, err = setPath(root, segments, operation.Value)
+		} else {
+			root, err = deletePath(root, segments)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(root)
+	}
+	return nil, fmt.Errorf("unsupported operation type %q", operation.Type)
+}
+
+// setPath sets the value at the path creating missing maps on the way, list indices must already exist
+// except for the index right after the last element which appends
+func setPath(node interface{}, segments []interface{}, value interface{}) (interface{}, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+	switch segment := segments[0].(type) {
+	case string:
+		if node == nil {
+			node = map[string]interface{}{}
+		}
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("can not set key %q on a non object value", segment)
+		}
+		child, err := setPath(object[segment], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		object[segment] = child
+		return object, nil
+	case int:
+		if node == nil {
+			node = []interface{}{}
+		}
+		list, ok := node.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("can not set index %d on a non list value", segment)
+		}
+		if segment > len(list) {
+			return nil, fmt.Errorf("index %d out of range for list of length %d", segment, len(list))
+		}
+		if segment == len(list) {
+			list = append(list, nil)
+		}
+		child, err := setPath(list[segment], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		list[segment] = child
+		return list, nil
+	}
+	return nil, fmt.Errorf("invalid path segment %v", segments[0])
+}
+
+// deletePath removes the value at the path, a path which does not exist is left as is
+func deletePath(node interface{}, segments []interface{}) (interface{}, error) {
+	switch segment := segments[0].(type) {
+	case string:
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return node, nil
+		}
+		child, found := object[segment]
+		if !found {
+			return node, nil
+		}
+		if len(segments) == 1 {
+			delete(object, segment)
+			return object, nil
+		}
+		updated, err := deletePath(child, segments[1:])
+		if err != nil {
+			return nil, err
+		}
+		object[segment] = updated
+		return object, nil
+	case int:
+		list, ok := node.([]interface{})
+		if !ok || segment >= len(list) {
+			return node, nil
+		}
+		if len(segments) == 1 {
+			return append(list[:segment], list[segment+1:]...), nil
+		}
+		updated, err := deletePath(list[segment], segments[1:])
+		if err != nil {
+			return nil, err
+		}
+		list[segment] = updated
+		return list, nil
+	}
+	return nil, fmt.Errorf("invalid path segment %v", segments[0])
+}
+
+// IsSameDocument compares two json documents semantically, empty documents equal an empty object
+func IsSameDocument(first, second []byte) bool {
+	var firstValue, secondValue interface{}
+	if err := json.Unmarshal(normaliseDocument(first), &firstValue); err != nil {
+		return bytes.Equal(first, second)
+	}
+	if err := json.Unmarshal(normaliseDocument(second), &secondValue); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(firstValue, secondValue)
+}
+
+func normaliseDocument(document []byte) []byte {
+	if len(bytes.TrimSpace(document)) == 0 {
+		return []byte("{}")
+	}
+	return document
+}
+
+// RenderDiff renders both json documents as yaml and returns their unified diff, secret values are redacted
+// so that the diff only reveals which keys changed
+func RenderDiff(name string, before, after []byte, redact bool) (string, error) {
+	if redact {
+		var err error
+		if before, err = RedactDocument(before); err != nil {
+			return "", err
+		}
+		if after, err = RedactDocument(after); err != nil {
+			return "", err
+		}
+	}
+	beforeYaml, err := yaml.JSONToYAML(normaliseDocument(before))
+	if err != nil {
+		return "", err
+	}
+	afterYaml, err := yaml.JSONToYAML(normaliseDocument(after))
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(beforeYaml)),
+		B:        difflib.SplitLines(string(afterYaml)),
+		FromFile: fmt.Sprintf("a/%s", name),
+		ToFile:   fmt.Sprintf("b/%s", name),
+		Context:  3,
+	})
+}
+
+// RedactDocument replaces every scalar value of the document with a short digest of the value
+func RedactDocument(document []byte) ([]byte, error) {
+	var root interface{}
+	if err := json.Unmarshal(normaliseDocument(document), &root); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactValue(root))
+}
+
+func redactValue(node interface{}) interface{} {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			value[key] = redactValue(child)
+		}
+		return value
+	case []interface{}:
+		for i, child := range value {
+			value[i] = redactValue(child)
+		}
+		return value
+	case nil:
+		return nil
+	}
+	raw, _ := json.Marshal(node)
+	digest := sha256.Sum256(raw)
+	return fmt.Sprintf("%s%s>", bean.RedactedValuePrefix, hex.EncodeToString(digest[:])[:8])
+}
+
+func configListKey(kind bean.TargetKind) string {
+	if kind == bean.TargetKindSecret {
+		return secretListKey
+	}
+	return configMapListKey
+}
+
+func decodeConfigList(configData string, kind bean.TargetKind) (map[string]json.RawMessage, []map[string]json.RawMessage, error) {
+	root := map[string]json.RawMessage{}
+	var entries []map[string]json.RawMessage
+	if len(strings.TrimSpace(configData)) == 0 {
+		return root, entries, nil
+	}
+	if err := json.Unmarshal([]byte(configData), &root); err != nil {
+		return nil, nil, err
+	}
+	if raw, ok := root[configListKey(kind)]; ok && len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, nil, err
+		}
+	}
+	return root, entries, nil
+}
+
+func getEntryName(entry map[string]json.RawMessage) string {
+	var name string
+	_ = json.Unmarshal(entry[configNameKey], &name)
+	return name
+}
+
+// GetConfigNames returns the sorted names of configmaps or secrets stored in config map/secret data
+func GetConfigNames(configData string, kind bean.TargetKind) ([]string, error) {
+	_, entries, err := decodeConfigList(configData, kind)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, getEntryName(entry))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// GetConfigEntryData returns the data of the named configmap or secret, secret values are base64 decoded
+func GetConfigEntryData(configData string, kind bean.TargetKind, name string) ([]byte, error) {
+	_, entries, err := decodeConfigList(configData, kind)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if getEntryName(entry) != name {
+			continue
+		}
+		data := entry[configDataKey]
+		if len(data) == 0 || string(data) == "null" {
+			data = []byte("{}")
+		}
+		if kind == bean.TargetKindSecret {
+			return DecodeSecretData(data)
+		}
+		return data, nil
+	}
+	return nil, ErrConfigEntryNotFound
+}
+
+// SetConfigEntryData replaces the data of the named configmap or secret keeping every other field of the entry
+func SetConfigEntryData(configData string, kind bean.TargetKind, name string, data []byte) (string, error) {
+	root, entries, err := decodeConfigList(configData, kind)
+	if err != nil {
+		return "", err
+	}
+	if kind == bean.TargetKindSecret {
+		if data, err = EncodeSecretData(data); err != nil {
+			return "", err
+		}
+	}
+	found := false
+	for _, entry := range entries {
+		if getEntryName(entry) == name {
+			entry[configDataKey] = data
+			found = true
+		}
+	}
+	if !found {
+		return "", ErrConfigEntryNotFound
+	}
+	rawEntries, err := json.Marshal(entries)
+	if err != nil {
+		return "", err
+	}
+	root[configListKey(kind)] = rawEntries
+	updated, err := json.Marshal(root)
+	if err != nil {
+		return "", err
+	}
+	return string(updated), nil
+}
+
+// DecodeSecretData decodes the base64 values of secret data
+func DecodeSecretData(data []byte) ([]byte, error) {
+	encoded := map[string]string{}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("secret data is not a map of strings: %s", err.Error())
+	}
+	decoded := make(map[string]string, len(encoded))
+	for key, value := range encoded {
+		plain, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("secret key %q is not base64 encoded", key)
+		}
+		decoded[key] = string(plain)
+	}
+	return json.Marshal(decoded)
+}
+
+// EncodeSecretData base64 encodes the values of secret data, non string values are encoded as json
+func EncodeSecretData(data []byte) ([]byte, error) {
+	plain := map[string]interface{}{}
+	if err := json.Unmarshal(data, &plain); err != nil {
+		return nil, fmt.Errorf("secret data must be an object: %s", err.Error())
+	}
+	encoded := make(map[string]string, len(plain))
+	for key, value := range plain {
+		stringValue, ok := value.(string)
+		if !ok {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			stringValue = string(raw)
+		}
+		encoded[key] = base64.StdEncoding.EncodeToString([]byte(stringValue))
+	}
+	return json.Marshal(encoded)
+}
+
+// MatchesChartVersion checks the chart ref version against the semver constraint of the selector,
+// versions which are not valid semver never match a non empty constraint
+func MatchesChartVersion(constraint, version string) bool {
+	if len(constraint) == 0 {
+		return true
+	}
+	parsedConstraint, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false
+	}
+	parsedVersion, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	return parsedConstraint.Check(parsedVersion)
+}
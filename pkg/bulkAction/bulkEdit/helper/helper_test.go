@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit/bean"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	segments, err := ParsePath(`.spec.containers[0]["app.kubernetes.io/name"]`)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"spec", "containers", 0, "app.kubernetes.io/name"}, segments)
+
+	segments, err = ParsePath("resources.limits.cpu")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"resources", "limits", "cpu"}, segments)
+
+	_, err = ParsePath("")
+	assert.NotNil(t, err)
+	_, err = ParsePath("spec..replicas")
+	assert.NotNil(t, err)
+	_, err = ParsePath("spec[abc]")
+	assert.NotNil(t, err)
+}
+
+func TestApplyOperations(t *testing.T) {
+	document := []byte(`{"replicaCount":1,"resources":{"limits":{"cpu":"1"}},"ports":[{"port":80}]}`)
+	operations := []bean.Operation{
+		{Type: bean.OperationTypeJsonPatch, Patch: json.RawMessage(`[{"op":"replace","path":"/replicaCount","value":2}]`)},
+		{Type: bean.OperationTypeMergePatch, Patch: json.RawMessage(`{"resources":{"limits":{"memory":"1Gi"}}}`)},
+		{Type: bean.OperationTypeSet, Path: "ports[0].name", Value: "http"},
+		{Type: bean.OperationTypeSet, Path: "autoscaling.enabled", Value: true},
+		{Type: bean.OperationTypeDelete, Path: "resources.limits.cpu"},
+		{Type: bean.OperationTypeDelete, Path: "does.not.exist"},
+	}
+	result, err := ApplyOperations(document, operations)
+	assert.Nil(t, err)
+	expected := `{"autoscaling":{"enabled":true},"ports":[{"name":"http","port":80}],"replicaCount":2,"resources":{"limits":{"memory":"1Gi"}}}`
+	assert.True(t, IsSameDocument([]byte(expected), result), string(result))
+
+	_, err = ApplyOperations(document, []bean.Operation{{Type: bean.OperationTypeSet, Path: "ports[5]", Value: 1}})
+	assert.NotNil(t, err)
+
+	result, err = ApplyOperations(nil, []bean.Operation{{Type: bean.OperationTypeSet, Path: "a", Value: "b"}})
+	assert.Nil(t, err)
+	assert.True(t, IsSameDocument([]byte(`{"a":"b"}`), result))
+}
+
+func TestValidateRequest(t *testing.T) {
+	request := &bean.BulkEditRequest{
+		Selector:   &bean.Selector{ChartVersion: ">=4.18.0"},
+		Targets:    []bean.TargetSelector{{Kind: bean.TargetKindDeploymentTemplate}},
+		Operations: []bean.Operation{{Type: bean.OperationTypeSet, Path: "replicaCount", Value: 2}},
+	}
+	assert.Nil(t, ValidateRequest(request))
+
+	request.Selector.ChartVersion = "not-a-version"
+	assert.NotNil(t, ValidateRequest(request))
+	request.Selector.ChartVersion = ""
+
+	request.Operations = []bean.Operation{{Type: bean.OperationTypeJsonPatch, Patch: json.RawMessage(`{"op":"add"}`)}}
+	assert.NotNil(t, ValidateRequest(request))
+
+	request.Operations = []bean.Operation{{Type: bean.OperationTypeDelete, Path: "a"}}
+	request.Selector.Scope = bean.SelectorScopeBase
+	request.Targets = []bean.TargetSelector{{Kind: bean.TargetKindPipelineStrategy}}
+	assert.NotNil(t, ValidateRequest(request))
+}
+
+func TestConfigEntryData(t *testing.T) {
+	secretData := `{"secrets":[{"name":"db","type":"environment","data":{"PASSWORD":"c2VjcmV0"}},{"name":"other","data":{}}]}`
+	data, err := GetConfigEntryData(secretData, bean.TargetKindSecret, "db")
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"PASSWORD":"secret"}`, string(data))
+
+	updated, err := SetConfigEntryData(secretData, bean.TargetKindSecret, "db", []byte(`{"PASSWORD":"changed"}`))
+	assert.Nil(t, err)
+	assert.Contains(t, updated, `"type":"environment"`)
+	data, err = GetConfigEntryData(updated, bean.TargetKindSecret, "db")
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"PASSWORD":"changed"}`, string(data))
+
+	_, err = GetConfigEntryData(secretData, bean.TargetKindSecret, "missing")
+	assert.Equal(t, ErrConfigEntryNotFound, err)
+
+	names, err := GetConfigNames(`{"maps":[{"name":"b"},{"name":"a"}]}`, bean.TargetKindConfigMap)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+}
+
+func TestRenderDiff(t *testing.T) {
+	diff, err := RenderDiff("values.yaml", []byte(`{"replicaCount":1}`), []byte(`{"replicaCount":2}`), false)
+	assert.Nil(t, err)
+	assert.Contains(t, diff, "-replicaCount: 1")
+	assert.Contains(t, diff, "+replicaCount: 2")
+
+	diff, err = RenderDiff("db", []byte(`{"PASSWORD":"secret"}`), []byte(`{"PASSWORD":"changed"}`), true)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(diff, "secret") || strings.Contains(diff, "changed"))
+	assert.Contains(t, diff, bean.RedactedValuePrefix)
+}
+
+func TestMatchesChartVersion(t *testing.T) {
+	assert.True(t, MatchesChartVersion("", "anything"))
+	assert.True(t, MatchesChartVersion(">=4.18.0 <5.0.0", "4.19.0"))
+	assert.False(t, MatchesChartVersion(">=4.18.0", "4.17.0"))
+	assert.False(t, MatchesChartVersion(">=4.18.0", "invalid"))
+}
@@ -0,0 +1,265 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type BulkEdit struct {
+	tableName      struct{}            `sql:"bulk_edit" pg:",discard_unknown_columns"`
+	Id             int                 `sql:"id,pk"`
+	Name           string              `sql:"name"`
+	Request        string              `sql:"request,notnull"`
+	Status         bean.BulkEditStatus `sql:"status,notnull"`
+	TotalTargets   int                 `sql:"total_targets,notnull"`
+	ChangedTargets int                 `sql:"changed_targets,notnull"`
+	Succeeded      int                 `sql:"succeeded,notnull"`
+	Failed         int                 `sql:"failed,notnull"`
+	RolledBack     int                 `sql:"rolled_back,notnull"`
+	sql.AuditLog
+}
+
+// BulkEditTarget holds the preview diff of one document and, once applied, the change set used for rollback
+type BulkEditTarget struct {
+	tableName    struct{}          `sql:"bulk_edit_target" pg:",discard_unknown_columns"`
+	Id           int               `sql:"id,pk"`
+	BulkEditId   int               `sql:"bulk_edit_id,notnull"`
+	Kind         bean.TargetKind   `sql:"kind,notnull"`
+	AppId        int               `sql:"app_id,notnull"`
+	AppName      string            `sql:"app_name,notnull"`
+	EnvId        int               `sql:"env_id,notnull"`
+	EnvName      string            `sql:"env_name"`
+	ResourceId   int               `sql:"resource_id,notnull"`
+	ResourceName string            `sql:"resource_name"`
+	Status       bean.TargetStatus `sql:"status,notnull"`
+	Diff         string            `sql:"diff"`
+	Error        string            `sql:"error"`
+	BeforeData   string            `sql:"before_data"`
+	AfterData    string            `sql:"after_data"`
+	// BeforeValues keeps the merged chart values_yaml of base deployment templates so that rollback restores it exactly
+	BeforeValues string `sql:"before_values"`
+	sql.AuditLog
+}
+
+type Scope struct {
+	AppId               int    `sql:"app_id"`
+	AppName             string `sql:"app_name"`
+	EnvId               int    `sql:"env_id"`
+	EnvName             string `sql:"env_name"`
+	ClusterId           int    `sql:"cluster_id"`
+	ChartId             int    `sql:"chart_id"`
+	EnvConfigOverrideId int    `sql:"env_config_override_id"`
+	IsOverride          bool   `sql:"is_override"`
+	PipelineId          int    `sql:"pipeline_id"`
+	ChartRefId          int    `sql:"chart_ref_id"`
+	ChartRefVersion     string `sql:"chart_ref_version"`
+}
+
+type BulkEditRepository interface {
+	SaveBulkEdit(bulkEdit *BulkEdit, tx *pg.Tx) error
+	UpdateBulkEdit(bulkEdit *BulkEdit) error
+	// UpdateBulkEditIfUnchanged updates the bulk edit only if it is still in expectedStatus and was not updated since
+	// lastUpdatedOn, false if another request moved it meanwhile
+	UpdateBulkEditIfUnchanged(bulkEdit *BulkEdit, expectedStatus bean.BulkEditStatus, lastUpdatedOn time.Time) (bool, error)
+	FindBulkEditById(id int) (*BulkEdit, error)
+	FindAllBulkEdits() ([]*BulkEdit, error)
+	FindBulkEditsByStatus(status bean.BulkEditStatus) ([]*BulkEdit, error)
+
+	SaveTargets(targets []*BulkEditTarget, tx *pg.Tx) error
+	UpdateTarget(target *BulkEditTarget) error
+	FindTargetsByBulkEditId(bulkEditId int) ([]*BulkEditTarget, error)
+
+	// FindBaseScopes returns the latest base chart of every active devtron app matched by the selector,
+	// environment and cluster filters of the selector are ignored
+	FindBaseScopes(selector *bean.Selector) ([]*Scope, error)
+	// FindEnvironmentScopes returns every active cd pipeline matched by the selector with its latest env override
+	// and the chart ref in use for the environment
+	FindEnvironmentScopes(selector *bean.Selector) ([]*Scope, error)
+	sql.TransactionWrapper
+}
+
+type BulkEditRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewBulkEditRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *BulkEditRepositoryImpl {
+	return &BulkEditRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: sql.NewTransactionUtilImpl(dbConnection),
+	}
+}
+
+func (impl *BulkEditRepositoryImpl) SaveBulkEdit(bulkEdit *BulkEdit, tx *pg.Tx) error {
+	return tx.Insert(bulkEdit)
+}
+
+func (impl *BulkEditRepositoryImpl) UpdateBulkEdit(bulkEdit *BulkEdit) error {
+	return impl.dbConnection.Update(bulkEdit)
+}
+
+func (impl *BulkEditRepositoryImpl) UpdateBulkEditIfUnchanged(bulkEdit *BulkEdit, expectedStatus bean.BulkEditStatus, lastUpdatedOn time.Time) (bool, error) {
+	result, err := impl.dbConnection.Model(bulkEdit).
+		WherePK().
+		Where("status = ?", expectedStatus).
+		Where("updated_on = ?", lastUpdatedOn).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (impl *BulkEditRepositoryImpl) FindBulkEditById(id int) (*BulkEdit, error) {
+	bulkEdit := &BulkEdit{}
+	err := impl.dbConnection.Model(bulkEdit).
+		Where("id = ?", id).
+		Select()
+	return bulkEdit, err
+}
+
+func (impl *BulkEditRepositoryImpl) FindAllBulkEdits() ([]*BulkEdit, error) {
+	var bulkEdits []*BulkEdit
+	err := impl.dbConnection.Model(&bulkEdits).
+		Order("id DESC").
+		Select()
+	return bulkEdits, err
+}
+
+func (impl *BulkEditRepositoryImpl) FindBulkEditsByStatus(status bean.BulkEditStatus) ([]*BulkEdit, error) {
+	var bulkEdits []*BulkEdit
+	err := impl.dbConnection.Model(&bulkEdits).
+		Where("status = ?", status).
+		Order("id ASC").
+		Select()
+	return bulkEdits, err
+}
+
+func (impl *BulkEditRepositoryImpl) SaveTargets(targets []*BulkEditTarget, tx *pg.Tx) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	return tx.Insert(&targets)
+}
+
+func (impl *BulkEditRepositoryImpl) UpdateTarget(target *BulkEditTarget) error {
+	return impl.dbConnection.Update(target)
+}
+
+func (impl *BulkEditRepositoryImpl) FindTargetsByBulkEditId(bulkEditId int) ([]*BulkEditTarget, error) {
+	var targets []*BulkEditTarget
+	err := impl.dbConnection.Model(&targets).
+		Where("bulk_edit_id = ?", bulkEditId).
+		Order("id ASC").
+		Select()
+	return targets, err
+}
+
+func (impl *BulkEditRepositoryImpl) FindBaseScopes(selector *bean.Selector) ([]*Scope, error) {
+	var scopes []*Scope
+	query := "SELECT a.id AS app_id, a.app_name, ch.id AS chart_id, cr.id AS chart_ref_id, cr.version AS chart_ref_version " +
+		"FROM app a " +
+		"INNER JOIN charts ch ON ch.app_id = a.id AND ch.latest = true " +
+		"INNER JOIN chart_ref cr ON cr.id = ch.chart_ref_id " +
+		"WHERE a.active = true AND a.app_type = 0"
+	conditions, params := buildAppConditions(selector)
+	query = query + conditions + " ORDER BY a.app_name;"
+	_, err := impl.dbConnection.Query(&scopes, query, params...)
+	if err != nil {
+		impl.logger.Errorw("error in fetching base scopes for bulk edit", "selector", selector, "err", err)
+	}
+	return scopes, err
+}
+
+func (impl *BulkEditRepositoryImpl) FindEnvironmentScopes(selector *bean.Selector) ([]*Scope, error) {
+	var scopes []*Scope
+	query := "SELECT a.id AS app_id, a.app_name, e.id AS env_id, e.environment_name AS env_name, e.cluster_id, p.id AS pipeline_id, " +
+		"ch.id AS chart_id, COALESCE(eco.id, 0) AS env_config_override_id, COALESCE(eco.is_override, false) AS is_override, " +
+		"cr.id AS chart_ref_id, cr.version AS chart_ref_version " +
+		"FROM pipeline p " +
+		"INNER JOIN app a ON a.id = p.app_id " +
+		"INNER JOIN environment e ON e.id = p.environment_id " +
+		"INNER JOIN charts ch ON ch.app_id = a.id AND ch.latest = true " +
+		"LEFT JOIN env_config_override eco ON eco.target_environment = e.id AND eco.latest = true AND eco.active = true " +
+		"AND eco.chart_id IN (SELECT c.id FROM charts c WHERE c.app_id = a.id) " +
+		"LEFT JOIN charts ech ON ech.id = eco.chart_id " +
+		"INNER JOIN chart_ref cr ON cr.id = COALESCE(ech.chart_ref_id, ch.chart_ref_id) " +
+		"WHERE p.deleted = false AND a.active = true AND a.app_type = 0 AND e.active = true"
+	conditions, params := buildAppConditions(selector)
+	if len(selector.EnvironmentIds) > 0 {
+		conditions += " AND e.id IN (?)"
+		params = append(params, pg.In(selector.EnvironmentIds))
+	}
+	if len(selector.ClusterIds) > 0 {
+		conditions += " AND e.cluster_id IN (?)"
+		params = append(params, pg.In(selector.ClusterIds))
+	}
+	query = query + conditions + " ORDER BY a.app_name, e.environment_name;"
+	_, err := impl.dbConnection.Query(&scopes, query, params...)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment scopes for bulk edit", "selector", selector, "err", err)
+	}
+	return scopes, err
+}
+
+// buildAppConditions builds the app, label, team and chart ref filters shared by base and environment scopes,
+// it expects the app to be aliased as a and the chart ref as cr
+func buildAppConditions(selector *bean.Selector) (string, []interface{}) {
+	var conditions strings.Builder
+	var params []interface{}
+	if selector.AppNames != nil {
+		if len(selector.AppNames.Includes) > 0 {
+			conditions.WriteString(" AND a.app_name LIKE ANY (array[?])")
+			params = append(params, pg.In(selector.AppNames.Includes))
+		}
+		if len(selector.AppNames.Excludes) > 0 {
+			conditions.WriteString(" AND a.app_name NOT LIKE ALL (array[?])")
+			params = append(params, pg.In(selector.AppNames.Excludes))
+		}
+	}
+	for _, label := range selector.Labels {
+		if len(label.Value) > 0 {
+			conditions.WriteString(" AND EXISTS (SELECT 1 FROM app_label al WHERE al.app_id = a.id AND al.key = ? AND al.value = ?)")
+			params = append(params, label.Key, label.Value)
+		} else {
+			conditions.WriteString(" AND EXISTS (SELECT 1 FROM app_label al WHERE al.app_id = a.id AND al.key = ?)")
+			params = append(params, label.Key)
+		}
+	}
+	if len(selector.TeamIds) > 0 {
+		conditions.WriteString(" AND a.team_id IN (?)")
+		params = append(params, pg.In(selector.TeamIds))
+	}
+	if len(selector.ChartRefIds) > 0 {
+		conditions.WriteString(" AND cr.id IN (?)")
+		params = append(params, pg.In(selector.ChartRefIds))
+	}
+	if len(selector.ChartNames) > 0 {
+		conditions.WriteString(" AND cr.name IN (?)")
+		params = append(params, pg.In(selector.ChartNames))
+	}
+	return conditions.String(), params
+}
@@ -0,0 +1,660 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chartRefMigration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/common-lib/async"
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit"
+	bulkEditBean "github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit/bean"
+	bulkEditHelper "github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit/helper"
+	bulkEditRepository "github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit/repository"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/chartRefMigration/bean"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/chartRefMigration/helper"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/chartRefMigration/repository"
+	"github.com/devtron-labs/devtron/pkg/chart"
+	chartBean "github.com/devtron-labs/devtron/pkg/chart/bean"
+	chartRead "github.com/devtron-labs/devtron/pkg/chart/read"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deployedAppMetrics"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef"
+	chartRefBean "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/validator"
+	"github.com/devtron-labs/devtron/pkg/generateManifest"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type ChartRefMigrationService interface {
+	// DryRun resolves the targets of the request, migrates and validates their values against the target chart and
+	// saves the report with the values and rendered manifest diff of every target, only a dry run can be committed
+	DryRun(ctx context.Context, request *bean.ChartRefMigrationRequest, checkAuth bulkEdit.CheckAuth) (*bean.ChartRefMigrationDto, error)
+	// Commit moves the ready targets of a dry run, or the failed targets of a partially failed migration, to the
+	// target chart in the background
+	Commit(ctx context.Context, id int, userId int32, checkAuth bulkEdit.CheckAuth) (*bean.ChartRefMigrationDto, error)
+	GetMigration(id int) (*bean.ChartRefMigrationDto, error)
+	GetMigrations() ([]*bean.ChartRefMigrationDto, error)
+	// RegisterMigrationRule adds a rule applied to every migration before the custom rules of the request
+	RegisterMigrationRule(rule MigrationRule)
+}
+
+type ChartRefMigrationServiceImpl struct {
+	logger                              *zap.SugaredLogger
+	chartRefMigrationRepository         repository.ChartRefMigrationRepository
+	bulkEditRepository                  bulkEditRepository.BulkEditRepository
+	chartRepository                     chartRepoRepository.ChartRepository
+	envConfigOverrideRepository         chartConfig.EnvConfigOverrideRepository
+	chartRefService                     chartRef.ChartRefService
+	chartService                        chart.ChartService
+	chartReadService                    chartRead.ChartReadService
+	propertiesConfigService             pipeline.PropertiesConfigService
+	deploymentTemplateValidationService validator.DeploymentTemplateValidationService
+	deploymentTemplateService           generateManifest.DeploymentTemplateService
+	deployedAppMetricsService           deployedAppMetrics.DeployedAppMetricsService
+	asyncRunnable                       *async.Runnable
+	rulesLock                           sync.RWMutex
+	rules                               []MigrationRule
+	// runningMigrations holds the ids of migrations being committed by this process
+	runningMigrations sync.Map
+}
+
+func NewChartRefMigrationServiceImpl(logger *zap.SugaredLogger,
+	chartRefMigrationRepository repository.ChartRefMigrationRepository,
+	bulkEditRepository bulkEditRepository.BulkEditRepository,
+	chartRepository chartRepoRepository.ChartRepository,
+	envConfigOverrideRepository chartConfig.EnvConfigOverrideRepository,
+	chartRefService chartRef.ChartRefService,
+	chartService chart.ChartService,
+	chartReadService chartRead.ChartReadService,
+	propertiesConfigService pipeline.PropertiesConfigService,
+	deploymentTemplateValidationService validator.DeploymentTemplateValidationService,
+	deploymentTemplateService generateManifest.DeploymentTemplateService,
+	deployedAppMetricsService deployedAppMetrics.DeployedAppMetricsService,
+	asyncRunnable *async.Runnable) *ChartRefMigrationServiceImpl {
+	impl := &ChartRefMigrationServiceImpl{
+		logger:                              logger,
+		chartRefMigrationRepository:         chartRefMigrationRepository,
+		bulkEditRepository:                  bulkEditRepository,
+		chartRepository:                     chartRepository,
+		envConfigOverrideRepository:         envConfigOverrideRepository,
+		chartRefService:                     chartRefService,
+		chartService:                        chartService,
+		chartReadService:                    chartReadService,
+		propertiesConfigService:             propertiesConfigService,
+		deploymentTemplateValidationService: deploymentTemplateValidationService,
+		deploymentTemplateService:           deploymentTemplateService,
+		deployedAppMetricsService:           deployedAppMetricsService,
+		asyncRunnable:                       asyncRunnable,
+	}
+	impl.RegisterMigrationRule(&chartSpecificPatchRule{chartRefService: chartRefService})
+	return impl
+}
+
+func (impl *ChartRefMigrationServiceImpl) RegisterMigrationRule(rule MigrationRule) {
+	impl.rulesLock.Lock()
+	defer impl.rulesLock.Unlock()
+	impl.rules = append(impl.rules, rule)
+}
+
+func (impl *ChartRefMigrationServiceImpl) DryRun(ctx context.Context, request *bean.ChartRefMigrationRequest, checkAuth bulkEdit.CheckAuth) (*bean.ChartRefMigrationDto, error) {
+	if err := helper.ValidateRequest(request); err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	targetChartRef, err := impl.chartRefService.FindById(request.TargetChartRefId)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusBadRequest, "target chart ref not found", fmt.Sprintf("chart ref %d not found", request.TargetChartRefId))
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching target chart ref", "chartRefId", request.TargetChartRefId, "err", err)
+		return nil, err
+	}
+	targets, scopes, err := impl.resolveTargets(request)
+	if err != nil {
+		impl.logger.Errorw("error in resolving chart ref migration targets", "request", request, "err", err)
+		return nil, err
+	}
+	if err = impl.checkTargetsAuth(targets, checkAuth); err != nil {
+		return nil, err
+	}
+	rules := impl.getRules(request)
+	sourceChartRefs := make(map[int]*chartRefBean.ChartRefDto)
+	for i, target := range targets {
+		sourceChartRef, ok := sourceChartRefs[target.SourceChartRefId]
+		if !ok {
+			sourceChartRef, err = impl.chartRefService.FindById(target.SourceChartRefId)
+			if err != nil {
+				impl.logger.Errorw("error in fetching source chart ref", "chartRefId", target.SourceChartRefId, "err", err)
+				return nil, err
+			}
+			sourceChartRefs[target.SourceChartRefId] = sourceChartRef
+		}
+		target.SourceChartName, target.SourceChartVersion = sourceChartRef.Name, sourceChartRef.Version
+		impl.dryRunTarget(ctx, target, scopes[i], sourceChartRef, targetChartRef, rules)
+	}
+	requestJson, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	migration := &repository.ChartRefMigration{
+		Name:               request.Name,
+		Request:            string(requestJson),
+		Status:             bean.MigrationStatusDryRun,
+		TargetChartRefId:   targetChartRef.Id,
+		TargetChartName:    targetChartRef.Name,
+		TargetChartVersion: targetChartRef.Version,
+		TotalTargets:       len(targets),
+		AuditLog:           sql.NewDefaultAuditLog(request.UserId),
+	}
+	for _, target := range targets {
+		if target.Status == bean.TargetStatusReady {
+			migration.ReadyTargets++
+		}
+		target.AuditLog = sql.NewDefaultAuditLog(request.UserId)
+	}
+	tx, err := impl.chartRefMigrationRepository.StartTx()
+	if err != nil {
+		return nil, err
+	}
+	defer impl.chartRefMigrationRepository.RollbackTx(tx)
+	if err = impl.chartRefMigrationRepository.SaveMigration(migration, tx); err != nil {
+		impl.logger.Errorw("error in saving chart ref migration", "err", err)
+		return nil, err
+	}
+	for _, target := range targets {
+		target.ChartRefMigrationId = migration.Id
+	}
+	if err = impl.chartRefMigrationRepository.SaveTargets(targets, tx); err != nil {
+		impl.logger.Errorw("error in saving chart ref migration targets", "migrationId", migration.Id, "err", err)
+		return nil, err
+	}
+	if err = impl.chartRefMigrationRepository.CommitTx(tx); err != nil {
+		return nil, err
+	}
+	return impl.buildDto(migration, targets), nil
+}
+
+func (impl *ChartRefMigrationServiceImpl) Commit(ctx context.Context, id int, userId int32, checkAuth bulkEdit.CheckAuth) (*bean.ChartRefMigrationDto, error) {
+	migration, targets, err := impl.getMigrationWithTargets(id)
+	if err != nil {
+		return nil, err
+	}
+	if migration.Status != bean.MigrationStatusDryRun && migration.Status != bean.MigrationStatusPartiallyFailed {
+		return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("chart ref migration is %s and can not be committed", migration.Status), "chart ref migration already committed")
+	}
+	if err = impl.checkTargetsAuth(targets, checkAuth); err != nil {
+		return nil, err
+	}
+	request := &bean.ChartRefMigrationRequest{}
+	if err = json.Unmarshal([]byte(migration.Request), request); err != nil {
+		impl.logger.Errorw("error in decoding chart ref migration request", "id", migration.Id, "err", err)
+		return nil, err
+	}
+	targetChartRef, err := impl.chartRefService.FindById(migration.TargetChartRefId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching target chart ref", "chartRefId", migration.TargetChartRefId, "err", err)
+		return nil, err
+	}
+	if _, running := impl.runningMigrations.LoadOrStore(migration.Id, true); running {
+		return nil, util.NewApiError(http.StatusConflict, "chart ref migration is already running", "chart ref migration already running")
+	}
+	migration.Status = bean.MigrationStatusInProgress
+	migration.UpdateAuditLog(userId)
+	if err = impl.chartRefMigrationRepository.UpdateMigration(migration); err != nil {
+		impl.runningMigrations.Delete(migration.Id)
+		impl.logger.Errorw("error in updating chart ref migration status", "id", migration.Id, "err", err)
+		return nil, err
+	}
+	// the commit outlives the api request, so only the values of the request context are kept
+	commitCtx := context.WithoutCancel(ctx)
+	rules := impl.getRules(request)
+	impl.asyncRunnable.Execute(func() {
+		impl.commitTargets(commitCtx, migration, targets, targetChartRef, rules, userId)
+	})
+	dto := impl.buildDto(migration, nil)
+	dto.IsRunning = true
+	return dto, nil
+}
+
+func (impl *ChartRefMigrationServiceImpl) GetMigration(id int) (*bean.ChartRefMigrationDto, error) {
+	migration, targets, err := impl.getMigrationWithTargets(id)
+	if err != nil {
+		return nil, err
+	}
+	return impl.buildDto(migration, targets), nil
+}
+
+func (impl *ChartRefMigrationServiceImpl) GetMigrations() ([]*bean.ChartRefMigrationDto, error) {
+	migrations, err := impl.chartRefMigrationRepository.FindAllMigrations()
+	if err != nil {
+		impl.logger.Errorw("error in fetching chart ref migrations", "err", err)
+		return nil, err
+	}
+	dtos := make([]*bean.ChartRefMigrationDto, 0, len(migrations))
+	for _, migration := range migrations {
+		dto := impl.buildDto(migration, nil)
+		dto.Request = nil
+		dtos = append(dtos, dto)
+	}
+	return dtos, nil
+}
+
+func (impl *ChartRefMigrationServiceImpl) getMigrationWithTargets(id int) (*repository.ChartRefMigration, []*repository.ChartRefMigrationTarget, error) {
+	migration, err := impl.chartRefMigrationRepository.FindMigrationById(id)
+	if util.IsErrNoRows(err) {
+		return nil, nil, util.NewApiError(http.StatusNotFound, "chart ref migration not found", fmt.Sprintf("chart ref migration %d not found", id))
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching chart ref migration", "id", id, "err", err)
+		return nil, nil, err
+	}
+	targets, err := impl.chartRefMigrationRepository.FindTargetsByMigrationId(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching chart ref migration targets", "id", id, "err", err)
+		return nil, nil, err
+	}
+	return migration, targets, nil
+}
+
+func (impl *ChartRefMigrationServiceImpl) checkTargetsAuth(targets []*repository.ChartRefMigrationTarget, checkAuth bulkEdit.CheckAuth) error {
+	var forbidden []string
+	for _, target := range targets {
+		if !checkAuth(target.AppId, target.AppName, target.EnvId) {
+			forbidden = append(forbidden, getTargetName(target))
+		}
+	}
+	if len(forbidden) > 0 {
+		return util.NewApiError(http.StatusForbidden, fmt.Sprintf("unauthorized for %s", strings.Join(forbidden, ", ")), "unauthorized user")
+	}
+	return nil
+}
+
+// resolveTargets finds the base deployment template and every overridden environment deployment template matched
+// by the selector which is not on the target chart yet, the scope of every target is returned at the same index
+func (impl *ChartRefMigrationServiceImpl) resolveTargets(request *bean.ChartRefMigrationRequest) ([]*repository.ChartRefMigrationTarget, []*bulkEditRepository.Scope, error) {
+	selector := request.Selector
+	scope := selector.Scope
+	if len(scope) == 0 {
+		scope = bulkEditBean.SelectorScopeAll
+		if len(selector.EnvironmentIds) > 0 || len(selector.ClusterIds) > 0 {
+			scope = bulkEditBean.SelectorScopeEnvironment
+		}
+	}
+	var matchedScopes []*bulkEditRepository.Scope
+	if scope == bulkEditBean.SelectorScopeAll || scope == bulkEditBean.SelectorScopeBase {
+		baseScopes, err := impl.bulkEditRepository.FindBaseScopes(selector)
+		if err != nil {
+			return nil, nil, err
+		}
+		matchedScopes = append(matchedScopes, baseScopes...)
+	}
+	if scope == bulkEditBean.SelectorScopeAll || scope == bulkEditBean.SelectorScopeEnvironment {
+		envScopes, err := impl.bulkEditRepository.FindEnvironmentScopes(selector)
+		if err != nil {
+			return nil, nil, err
+		}
+		matchedScopes = append(matchedScopes, envScopes...)
+	}
+	var targets []*repository.ChartRefMigrationTarget
+	var scopes []*bulkEditRepository.Scope
+	for _, matchedScope := range matchedScopes {
+		if matchedScope.ChartRefId == request.TargetChartRefId || !bulkEditHelper.MatchesChartVersion(selector.ChartVersion, matchedScope.ChartRefVersion) {
+			continue
+		}
+		// environments without an override follow the base deployment template
+		if matchedScope.EnvId != bulkEditBean.ResourceIdBase && (matchedScope.EnvConfigOverrideId == 0 || !matchedScope.IsOverride) {
+			continue
+		}
+		targets = append(targets, &repository.ChartRefMigrationTarget{
+			AppId:            matchedScope.AppId,
+			AppName:          matchedScope.AppName,
+			EnvId:            matchedScope.EnvId,
+			EnvName:          matchedScope.EnvName,
+			SourceChartRefId: matchedScope.ChartRefId,
+			Status:           bean.TargetStatusReady,
+		})
+		scopes = append(scopes, matchedScope)
+	}
+	return targets, scopes, nil
+}
+
+func (impl *ChartRefMigrationServiceImpl) getRules(request *bean.ChartRefMigrationRequest) []MigrationRule {
+	impl.rulesLock.RLock()
+	rules := append([]MigrationRule{}, impl.rules...)
+	impl.rulesLock.RUnlock()
+	for _, rule := range request.Rules {
+		rules = append(rules, &customRule{rule: rule})
+	}
+	return rules
+}
+
+// dryRunTarget fills the report of a target, the manifest diff compares the manifest rendered with the current
+// chart and values with the manifest rendered with the target chart and migrated values
+func (impl *ChartRefMigrationServiceImpl) dryRunTarget(ctx context.Context, target *repository.ChartRefMigrationTarget, scope *bulkEditRepository.Scope,
+	sourceChartRef, targetChartRef *chartRefBean.ChartRefDto, rules []MigrationRule) {
+	values, err := impl.getValues(scope)
+	if err != nil {
+		target.Status, target.Message = bean.TargetStatusInvalid, err.Error()
+		return
+	}
+	migrated, appliedRules, status, message := impl.migrateValues(ctx, values, scope.AppId, scope.EnvId, scope.ClusterId, sourceChartRef, targetChartRef, rules)
+	target.AppliedRules = strings.Join(appliedRules, ",")
+	if status != bean.TargetStatusReady {
+		target.Status, target.Message = status, message
+		return
+	}
+	name := getTargetName(target)
+	if target.ValuesDiff, err = bulkEditHelper.RenderDiff(name+"/values.yaml", values, migrated, false); err != nil {
+		target.Status, target.Message = bean.TargetStatusInvalid, err.Error()
+		return
+	}
+	after, err := impl.renderManifest(ctx, scope, targetChartRef.Id, migrated)
+	if err != nil {
+		target.Status, target.Message = bean.TargetStatusInvalid, fmt.Sprintf("manifest could not be rendered with the target chart: %s", err.Error())
+		return
+	}
+	before, err := impl.renderManifest(ctx, scope, sourceChartRef.Id, values)
+	if err != nil {
+		target.Message = fmt.Sprintf("current manifest could not be rendered, the diff shows the whole target manifest: %s", err.Error())
+	}
+	if target.ManifestDiff, err = helper.RenderManifestDiff(name+"/manifest.yaml", before, after); err != nil {
+		target.Status, target.Message = bean.TargetStatusInvalid, err.Error()
+	}
+}
+
+// migrateValues applies the migration rules and checks the migrated values against the target chart
+func (impl *ChartRefMigrationServiceImpl) migrateValues(ctx context.Context, values []byte, appId, envId, clusterId int,
+	sourceChartRef, targetChartRef *chartRefBean.ChartRefDto, rules []MigrationRule) (json.RawMessage, []string, bean.TargetStatus, string) {
+	if !helper.IsCompatible(sourceChartRef, targetChartRef) {
+		return nil, nil, bean.TargetStatusIncompatible, fmt.Sprintf("%q chart is not compatible with %q chart", helper.GetChartType(sourceChartRef), helper.GetChartType(targetChartRef))
+	}
+	migrated := json.RawMessage(values)
+	var appliedRules []string
+	for _, rule := range rules {
+		if !rule.Applies(sourceChartRef, targetChartRef) {
+			continue
+		}
+		updated, err := rule.Migrate(migrated, sourceChartRef, targetChartRef)
+		if err != nil {
+			return nil, appliedRules, bean.TargetStatusInvalid, fmt.Sprintf("migration rule %q failed: %s", rule.Name(), err.Error())
+		}
+		if !bulkEditHelper.IsSameDocument(migrated, updated) {
+			appliedRules = append(appliedRules, rule.Name())
+		}
+		migrated = updated
+	}
+	if helper.GetChartType(targetChartRef) != chartRefBean.DeploymentChartType {
+		enabled, err := impl.deploymentTemplateValidationService.FlaggerCanaryEnabled(migrated)
+		if err != nil {
+			return nil, appliedRules, bean.TargetStatusInvalid, err.Error()
+		} else if enabled {
+			return nil, appliedRules, bean.TargetStatusIncompatible, fmt.Sprintf("%q charts do not support flaggerCanary", helper.GetChartType(targetChartRef))
+		}
+	}
+	scope := resourceQualifiers.Scope{AppId: appId, EnvId: envId, ClusterId: clusterId}
+	valid, err := impl.deploymentTemplateValidationService.DeploymentTemplateValidate(ctx, migrated, targetChartRef.Id, scope)
+	if !valid {
+		message := "template schema validation error"
+		if err != nil {
+			message = fmt.Sprintf("%s: %s", message, err.Error())
+		}
+		return nil, appliedRules, bean.TargetStatusInvalid, message
+	}
+	return migrated, appliedRules, bean.TargetStatusReady, ""
+}
+
+func (impl *ChartRefMigrationServiceImpl) getValues(scope *bulkEditRepository.Scope) ([]byte, error) {
+	if scope.EnvId == bulkEditBean.ResourceIdBase {
+		chart, err := impl.chartRepository.FindById(scope.ChartId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching chart", "chartId", scope.ChartId, "err", err)
+			return nil, err
+		}
+		return []byte(chart.GlobalOverride), nil
+	}
+	envOverride, err := impl.envConfigOverrideRepository.GetByIdIncludingInactive(scope.EnvConfigOverrideId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching env config override", "envConfigOverrideId", scope.EnvConfigOverrideId, "err", err)
+		return nil, err
+	}
+	return []byte(envOverride.EnvOverrideValues), nil
+}
+
+func (impl *ChartRefMigrationServiceImpl) renderManifest(ctx context.Context, scope *bulkEditRepository.Scope, chartRefId int, values []byte) (string, error) {
+	response, err := impl.deploymentTemplateService.GetDeploymentTemplateWithResolvedData(ctx, generateManifest.DeploymentTemplateRequest{
+		AppId:           scope.AppId,
+		EnvId:           scope.EnvId,
+		PipelineId:      scope.PipelineId,
+		ChartRefId:      chartRefId,
+		Values:          string(values),
+		RequestDataMode: generateManifest.Manifest,
+	})
+	if err != nil {
+		return "", err
+	}
+	return response.Data, nil
+}
+
+func (impl *ChartRefMigrationServiceImpl) commitTargets(ctx context.Context, migration *repository.ChartRefMigration, targets []*repository.ChartRefMigrationTarget,
+	targetChartRef *chartRefBean.ChartRefDto, rules []MigrationRule, userId int32) {
+	defer impl.runningMigrations.Delete(migration.Id)
+	for _, target := range targets {
+		if target.Status != bean.TargetStatusReady && target.Status != bean.TargetStatusFailed {
+			continue
+		}
+		var message string
+		target.Status, message = impl.commitTarget(ctx, target, targetChartRef, rules, userId)
+		if target.Status == bean.TargetStatusFailed || len(message) > 0 {
+			target.Message = message
+		}
+		target.UpdateAuditLog(userId)
+		if err := impl.chartRefMigrationRepository.UpdateTarget(target); err != nil {
+			impl.logger.Errorw("error in updating chart ref migration target", "targetId", target.Id, "err", err)
+		}
+		impl.updateProgress(migration, targets, userId, bean.MigrationStatusInProgress)
+	}
+	status := bean.MigrationStatusCompleted
+	if migration.Failed > 0 {
+		status = bean.MigrationStatusPartiallyFailed
+	}
+	impl.updateProgress(migration, targets, userId, status)
+}
+
+// commitTarget migrates the current values of the target again so that changes made after the dry run are kept
+func (impl *ChartRefMigrationServiceImpl) commitTarget(ctx context.Context, target *repository.ChartRefMigrationTarget,
+	targetChartRef *chartRefBean.ChartRefDto, rules []MigrationRule, userId int32) (bean.TargetStatus, string) {
+	sourceChartRef, err := impl.chartRefService.FindById(target.SourceChartRefId)
+	if err != nil {
+		return bean.TargetStatusFailed, err.Error()
+	}
+	if target.EnvId == bulkEditBean.ResourceIdBase {
+		return impl.commitBaseTarget(ctx, target, sourceChartRef, targetChartRef, rules, userId)
+	}
+	return impl.commitEnvTarget(ctx, target, sourceChartRef, targetChartRef, rules, userId)
+}
+
+func (impl *ChartRefMigrationServiceImpl) commitBaseTarget(ctx context.Context, target *repository.ChartRefMigrationTarget,
+	sourceChartRef, targetChartRef *chartRefBean.ChartRefDto, rules []MigrationRule, userId int32) (bean.TargetStatus, string) {
+	template, err := impl.chartReadService.FindLatestChartForAppByAppId(target.AppId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching latest chart", "appId", target.AppId, "err", err)
+		return bean.TargetStatusFailed, err.Error()
+	}
+	if template.ChartRefId == targetChartRef.Id {
+		return bean.TargetStatusSucceeded, "already on the target chart"
+	} else if template.ChartRefId != sourceChartRef.Id {
+		return bean.TargetStatusFailed, "deployment template has changed chart since the dry run"
+	}
+	migrated, _, status, message := impl.migrateValues(ctx, template.DefaultAppOverride, target.AppId, target.EnvId, 0, sourceChartRef, targetChartRef, rules)
+	if status != bean.TargetStatusReady {
+		return bean.TargetStatusFailed, message
+	}
+	existingChart, err := impl.chartRepository.FindChartByAppIdAndRefId(target.AppId, targetChartRef.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching chart for chart ref", "appId", target.AppId, "chartRefId", targetChartRef.Id, "err", err)
+		return bean.TargetStatusFailed, err.Error()
+	}
+	if existingChart != nil && existingChart.Id > 0 {
+		// the app was on the target chart before, its chart is updated and marked latest again
+		_, err = impl.chartService.UpdateAppOverride(ctx, &chartBean.TemplateRequest{
+			Id:                  existingChart.Id,
+			AppId:               target.AppId,
+			ChartRefId:          targetChartRef.Id,
+			ValuesOverride:      migrated,
+			IsAppMetricsEnabled: template.IsAppMetricsEnabled,
+			IsBasicViewLocked:   template.IsBasicViewLocked,
+			CurrentViewEditor:   template.CurrentViewEditor,
+			UserId:              userId,
+		})
+	} else {
+		_, err = impl.chartService.Create(chartBean.TemplateRequest{
+			AppId:               target.AppId,
+			ChartRefId:          targetChartRef.Id,
+			ChartRepositoryId:   template.ChartRepositoryId,
+			ValuesOverride:      migrated,
+			IsAppMetricsEnabled: template.IsAppMetricsEnabled,
+			IsBasicViewLocked:   template.IsBasicViewLocked,
+			CurrentViewEditor:   template.CurrentViewEditor,
+			UserId:              userId,
+		}, ctx)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in moving deployment template to target chart", "appId", target.AppId, "chartRefId", targetChartRef.Id, "err", err)
+		return bean.TargetStatusFailed, err.Error()
+	}
+	return bean.TargetStatusSucceeded, ""
+}
+
+func (impl *ChartRefMigrationServiceImpl) commitEnvTarget(ctx context.Context, target *repository.ChartRefMigrationTarget,
+	sourceChartRef, targetChartRef *chartRefBean.ChartRefDto, rules []MigrationRule, userId int32) (bean.TargetStatus, string) {
+	envConfigProperties, err := impl.propertiesConfigService.GetLatestEnvironmentProperties(target.AppId, target.EnvId)
+	if err != nil || envConfigProperties == nil {
+		impl.logger.Errorw("error in fetching env properties", "appId", target.AppId, "envId", target.EnvId, "err", err)
+		return bean.TargetStatusFailed, "env properties not found"
+	}
+	if !envConfigProperties.IsOverride {
+		return bean.TargetStatusFailed, "specific environment is no longer overridden"
+	}
+	if envConfigProperties.ChartRefId == targetChartRef.Id {
+		return bean.TargetStatusSucceeded, "already on the target chart"
+	} else if envConfigProperties.ChartRefId != sourceChartRef.Id {
+		return bean.TargetStatusFailed, "deployment template override has changed chart since the dry run"
+	}
+	migrated, _, status, message := impl.migrateValues(ctx, envConfigProperties.EnvOverrideValues, target.AppId, target.EnvId, envConfigProperties.ClusterId, sourceChartRef, targetChartRef, rules)
+	if status != bean.TargetStatusReady {
+		return bean.TargetStatusFailed, message
+	}
+	envMetrics, err := impl.deployedAppMetricsService.GetMetricsFlagByAppIdAndEnvId(target.AppId, target.EnvId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching env metrics", "appId", target.AppId, "envId", target.EnvId, "err", err)
+		return bean.TargetStatusFailed, err.Error()
+	}
+	envConfigProperties.EnvOverrideValues = migrated
+	envConfigProperties.ChartRefId = targetChartRef.Id
+	envConfigProperties.EnvironmentId = target.EnvId
+	envConfigProperties.AppMetrics = &envMetrics
+	envConfigProperties.UserId = userId
+	envConfigProperties.MergeStrategy = models.MERGE_STRATEGY_REPLACE
+	_, err = impl.propertiesConfigService.ChangeChartRefForEnvConfigOverride(ctx, &chartBean.ChartRefChangeRequest{
+		AppId:               target.AppId,
+		EnvId:               target.EnvId,
+		TargetChartRefId:    targetChartRef.Id,
+		EnvConfigProperties: envConfigProperties,
+		EnvMetrics:          envMetrics,
+		UserId:              userId,
+	}, userId)
+	if err != nil {
+		impl.logger.Errorw("error in moving deployment template override to target chart", "appId", target.AppId, "envId", target.EnvId, "chartRefId", targetChartRef.Id, "err", err)
+		return bean.TargetStatusFailed, err.Error()
+	}
+	return bean.TargetStatusSucceeded, ""
+}
+
+func (impl *ChartRefMigrationServiceImpl) updateProgress(migration *repository.ChartRefMigration, targets []*repository.ChartRefMigrationTarget, userId int32, status bean.MigrationStatus) {
+	migration.Succeeded, migration.Failed = 0, 0
+	for _, target := range targets {
+		switch target.Status {
+		case bean.TargetStatusSucceeded:
+			migration.Succeeded++
+		case bean.TargetStatusFailed:
+			migration.Failed++
+		}
+	}
+	migration.Status = status
+	migration.UpdateAuditLog(userId)
+	if err := impl.chartRefMigrationRepository.UpdateMigration(migration); err != nil {
+		impl.logger.Errorw("error in updating chart ref migration progress", "id", migration.Id, "err", err)
+	}
+}
+
+func (impl *ChartRefMigrationServiceImpl) buildDto(migration *repository.ChartRefMigration, targets []*repository.ChartRefMigrationTarget) *bean.ChartRefMigrationDto {
+	dto := &bean.ChartRefMigrationDto{
+		Id:                 migration.Id,
+		Name:               migration.Name,
+		Status:             migration.Status,
+		TargetChartRefId:   migration.TargetChartRefId,
+		TargetChartName:    migration.TargetChartName,
+		TargetChartVersion: migration.TargetChartVersion,
+		TotalTargets:       migration.TotalTargets,
+		ReadyTargets:       migration.ReadyTargets,
+		Succeeded:          migration.Succeeded,
+		Failed:             migration.Failed,
+		CreatedBy:          migration.CreatedBy,
+		CreatedOn:          migration.CreatedOn,
+		UpdatedOn:          migration.UpdatedOn,
+	}
+	_, dto.IsRunning = impl.runningMigrations.Load(migration.Id)
+	request := &bean.ChartRefMigrationRequest{}
+	if err := json.Unmarshal([]byte(migration.Request), request); err == nil {
+		dto.Request = request
+	}
+	for _, target := range targets {
+		targetDto := &bean.MigrationTargetDto{
+			Id:                 target.Id,
+			AppId:              target.AppId,
+			AppName:            target.AppName,
+			EnvId:              target.EnvId,
+			EnvName:            target.EnvName,
+			SourceChartRefId:   target.SourceChartRefId,
+			SourceChartName:    target.SourceChartName,
+			SourceChartVersion: target.SourceChartVersion,
+			Status:             target.Status,
+			ValuesDiff:         target.ValuesDiff,
+			ManifestDiff:       target.ManifestDiff,
+			Message:            target.Message,
+		}
+		if len(target.AppliedRules) > 0 {
+			targetDto.AppliedRules = strings.Split(target.AppliedRules, ",")
+		}
+		dto.Targets = append(dto.Targets, targetDto)
+	}
+	return dto
+}
+
+func getTargetName(target *repository.ChartRefMigrationTarget) string {
+	if target.EnvId == bulkEditBean.ResourceIdBase {
+		return target.AppName
+	}
+	return fmt.Sprintf("%s/%s", target.AppName, target.EnvName)
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chartRefMigration
+
+import (
+	"encoding/json"
+	bulkEditHelper "github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit/helper"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/chartRefMigration/bean"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/chartRefMigration/helper"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef"
+	chartRefBean "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef/bean"
+)
+
+// MigrationRule rewrites deployment template values written for the source chart so that they keep their meaning
+// with the target chart, rules are applied in registration order followed by the custom rules of the request
+type MigrationRule interface {
+	Name() string
+	Applies(source, target *chartRefBean.ChartRefDto) bool
+	Migrate(values json.RawMessage, source, target *chartRefBean.ChartRefDto) (json.RawMessage, error)
+}
+
+// chartSpecificPatchRule applies the patches performed by the single app chart switch, like moving the winter
+// soldier config between deployment and rollout charts
+type chartSpecificPatchRule struct {
+	chartRefService chartRef.ChartRefService
+}
+
+func (rule *chartSpecificPatchRule) Name() string {
+	return "chart-specific-patch"
+}
+
+func (rule *chartSpecificPatchRule) Applies(source, target *chartRefBean.ChartRefDto) bool {
+	return helper.GetChartType(source) != helper.GetChartType(target)
+}
+
+func (rule *chartSpecificPatchRule) Migrate(values json.RawMessage, source, target *chartRefBean.ChartRefDto) (json.RawMessage, error) {
+	return rule.chartRefService.PerformChartSpecificPatchForSwitch(values, &chartRefBean.ChartRefSwitchRequest{
+		OldChartType: helper.GetChartType(source),
+		NewChartType: helper.GetChartType(target),
+	})
+}
+
+// customRule applies the operations of a rule declared in the migration request
+type customRule struct {
+	rule bean.CustomRule
+}
+
+func (rule *customRule) Name() string {
+	return rule.rule.Name
+}
+
+func (rule *customRule) Applies(source, target *chartRefBean.ChartRefDto) bool {
+	return helper.RuleApplies(rule.rule, source, target)
+}
+
+func (rule *customRule) Migrate(values json.RawMessage, source, target *chartRefBean.ChartRefDto) (json.RawMessage, error) {
+	return bulkEditHelper.ApplyOperations(values, rule.rule.Operations)
+}
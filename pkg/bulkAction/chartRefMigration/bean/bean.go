@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"time"
+
+	bulkEditBean "github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit/bean"
+)
+
+type MigrationStatus string
+
+const (
+	MigrationStatusDryRun          MigrationStatus = "dry_run"
+	MigrationStatusInProgress      MigrationStatus = "in_progress"
+	MigrationStatusCompleted       MigrationStatus = "completed"
+	MigrationStatusPartiallyFailed MigrationStatus = "partially_failed"
+)
+
+type TargetStatus string
+
+const (
+	TargetStatusReady        TargetStatus = "ready"
+	TargetStatusIncompatible TargetStatus = "incompatible"
+	TargetStatusInvalid      TargetStatus = "invalid"
+	TargetStatusSucceeded    TargetStatus = "succeeded"
+	TargetStatusFailed       TargetStatus = "failed"
+)
+
+// CustomRule is a migration rule declared in the request, its operations use the bulk edit v2 patch language and are
+// applied when the source and target chart versions match the semver constraints
+type CustomRule struct {
+	Name               string                   `json:"name" validate:"required"`
+	SourceChartVersion string                   `json:"sourceChartVersion,omitempty"`
+	TargetChartVersion string                   `json:"targetChartVersion,omitempty"`
+	Operations         []bulkEditBean.Operation `json:"operations" validate:"required,min=1,dive"`
+}
+
+type ChartRefMigrationRequest struct {
+	Name             string                 `json:"name,omitempty"`
+	Selector         *bulkEditBean.Selector `json:"selector" validate:"required"`
+	TargetChartRefId int                    `json:"targetChartRefId" validate:"required,number"`
+	Rules            []CustomRule           `json:"rules,omitempty" validate:"dive"`
+	UserId           int32                  `json:"-"`
+}
+
+type ChartRefMigrationDto struct {
+	Id                 int                       `json:"id"`
+	Name               string                    `json:"name"`
+	Status             MigrationStatus           `json:"status"`
+	TargetChartRefId   int                       `json:"targetChartRefId"`
+	TargetChartName    string                    `json:"targetChartName"`
+	TargetChartVersion string                    `json:"targetChartVersion"`
+	Request            *ChartRefMigrationRequest `json:"request,omitempty"`
+	TotalTargets       int                       `json:"totalTargets"`
+	ReadyTargets       int                       `json:"readyTargets"`
+	Succeeded          int                       `json:"succeeded"`
+	Failed             int                       `json:"failed"`
+	IsRunning          bool                      `json:"isRunning"`
+	CreatedBy          int32                     `json:"createdBy"`
+	CreatedOn          time.Time                 `json:"createdOn"`
+	UpdatedOn          time.Time                 `json:"updatedOn"`
+	Targets            []*MigrationTargetDto     `json:"targets,omitempty"`
+}
+
+type MigrationTargetDto struct {
+	Id                 int          `json:"id"`
+	AppId              int          `json:"appId"`
+	AppName            string       `json:"appName"`
+	EnvId              int          `json:"envId"`
+	EnvName            string       `json:"envName,omitempty"`
+	SourceChartRefId   int          `json:"sourceChartRefId"`
+	SourceChartName    string       `json:"sourceChartName"`
+	SourceChartVersion string       `json:"sourceChartVersion"`
+	Status             TargetStatus `json:"status"`
+	AppliedRules       []string     `json:"appliedRules,omitempty"`
+	ValuesDiff         string       `json:"valuesDiff,omitempty"`
+	ManifestDiff       string       `json:"manifestDiff,omitempty"`
+	Message            string       `json:"message,omitempty"`
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"fmt"
+	"github.com/Masterminds/semver/v3"
+	bulkEditHelper "github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit/helper"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/chartRefMigration/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef"
+	chartRefBean "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef/bean"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// ValidateRequest checks the selector constraint and the custom rules of a migration before any target is resolved
+func ValidateRequest(request *bean.ChartRefMigrationRequest) error {
+	if len(request.Selector.ChartVersion) > 0 {
+		if _, err := semver.NewConstraint(request.Selector.ChartVersion); err != nil {
+			return fmt.Errorf("invalid chart version constraint %q: %s", request.Selector.ChartVersion, err.Error())
+		}
+	}
+	for _, rule := range request.Rules {
+		for _, constraint := range []string{rule.SourceChartVersion, rule.TargetChartVersion} {
+			if len(constraint) == 0 {
+				continue
+			}
+			if _, err := semver.NewConstraint(constraint); err != nil {
+				return fmt.Errorf("invalid chart version constraint %q in rule %q: %s", constraint, rule.Name, err.Error())
+			}
+		}
+		for i, operation := range rule.Operations {
+			if err := bulkEditHelper.ValidateOperation(operation); err != nil {
+				return fmt.Errorf("invalid operation at index %d in rule %q: %s", i, rule.Name, err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// IsCompatible reports whether values of the source chart can be moved to the target chart, charts of the same
+// type (including the legacy reference charts without a name) are always compatible, otherwise the chart
+// compatibility matrix decides
+func IsCompatible(source, target *chartRefBean.ChartRefDto) bool {
+	if source.Name == target.Name {
+		return true
+	}
+	return chartRef.CheckCompatibility(GetChartType(source), GetChartType(target))
+}
+
+// GetChartType maps the legacy reference charts, which were saved without a name, to the deployment chart type
+func GetChartType(chartRefDto *chartRefBean.ChartRefDto) string {
+	if len(chartRefDto.Name) == 0 {
+		return chartRefBean.DeploymentChartType
+	}
+	return chartRefDto.Name
+}
+
+// RuleApplies checks the source and target chart versions against the constraints of a custom rule
+func RuleApplies(rule bean.CustomRule, source, target *chartRefBean.ChartRefDto) bool {
+	return bulkEditHelper.MatchesChartVersion(rule.SourceChartVersion, source.Version) &&
+		bulkEditHelper.MatchesChartVersion(rule.TargetChartVersion, target.Version)
+}
+
+// RenderManifestDiff returns the unified diff between the manifest rendered with the current chart and values
+// and the manifest rendered with the target chart and migrated values
+func RenderManifestDiff(name, before, after string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(before),
+		B:        difflib.SplitLines(after),
+		FromFile: fmt.Sprintf("a/%s", name),
+		ToFile:   fmt.Sprintf("b/%s", name),
+		Context:  3,
+	})
+}
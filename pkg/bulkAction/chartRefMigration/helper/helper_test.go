@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	bulkEditBean "github.com/devtron-labs/devtron/pkg/bulkAction/bulkEdit/bean"
+	"github.com/devtron-labs/devtron/pkg/bulkAction/chartRefMigration/bean"
+	chartRefBean "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef/bean"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsCompatible(t *testing.T) {
+	legacy := &chartRefBean.ChartRefDto{Version: "3.9.0"}
+	deployment := &chartRefBean.ChartRefDto{Name: chartRefBean.DeploymentChartType, Version: "4.19.0"}
+	rollout := &chartRefBean.ChartRefDto{Name: chartRefBean.RolloutChartType, Version: "4.18.0"}
+	statefulSet := &chartRefBean.ChartRefDto{Name: chartRefBean.StatefulSetChartType, Version: "4.18.0"}
+	custom := &chartRefBean.ChartRefDto{Name: "my-chart", Version: "1.0.0"}
+	customNext := &chartRefBean.ChartRefDto{Name: "my-chart", Version: "1.1.0"}
+
+	assert.True(t, IsCompatible(legacy, rollout))
+	assert.True(t, IsCompatible(legacy, deployment))
+	assert.True(t, IsCompatible(rollout, deployment))
+	assert.True(t, IsCompatible(custom, customNext))
+	assert.False(t, IsCompatible(rollout, statefulSet))
+	assert.False(t, IsCompatible(custom, deployment))
+}
+
+func TestRuleApplies(t *testing.T) {
+	rule := bean.CustomRule{Name: "rename", SourceChartVersion: "< 4.0.0", TargetChartVersion: ">= 4.18.0"}
+	assert.True(t, RuleApplies(rule, &chartRefBean.ChartRefDto{Version: "3.9.0"}, &chartRefBean.ChartRefDto{Version: "4.18.0"}))
+	assert.False(t, RuleApplies(rule, &chartRefBean.ChartRefDto{Version: "4.1.0"}, &chartRefBean.ChartRefDto{Version: "4.18.0"}))
+	assert.True(t, RuleApplies(bean.CustomRule{Name: "always"}, &chartRefBean.ChartRefDto{Version: "x"}, &chartRefBean.ChartRefDto{Version: "y"}))
+}
+
+func TestValidateRequest(t *testing.T) {
+	request := &bean.ChartRefMigrationRequest{
+		Selector:         &bulkEditBean.Selector{},
+		TargetChartRefId: 10,
+		Rules: []bean.CustomRule{{
+			Name:       "rename",
+			Operations: []bulkEditBean.Operation{{Type: bulkEditBean.OperationTypeJsonPatch, Patch: json.RawMessage(`[{"op":"move","from":"/server","path":"/service"}]`)}},
+		}},
+	}
+	assert.NoError(t, ValidateRequest(request))
+
+	request.Rules[0].SourceChartVersion = "not a version"
+	assert.Error(t, ValidateRequest(request))
+
+	request.Rules[0].SourceChartVersion = ""
+	request.Rules[0].Operations[0].Patch = json.RawMessage(`{"op":"move"}`)
+	assert.Error(t, ValidateRequest(request))
+}
+
+func TestRenderManifestDiff(t *testing.T) {
+	diff, err := RenderManifestDiff("manifest.yaml", "kind: Deployment\nreplicas: 1\n", "kind: Rollout\nreplicas: 1\n")
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(diff, "-kind: Deployment"))
+	assert.True(t, strings.Contains(diff, "+kind: Rollout"))
+
+	diff, err = RenderManifestDiff("manifest.yaml", "kind: Deployment\n", "kind: Deployment\n")
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+}
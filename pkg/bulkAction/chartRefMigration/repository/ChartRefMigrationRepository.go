@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/bulkAction/chartRefMigration/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type ChartRefMigration struct {
+	tableName          struct{}             `sql:"chart_ref_migration" pg:",discard_unknown_columns"`
+	Id                 int                  `sql:"id,pk"`
+	Name               string               `sql:"name"`
+	Request            string               `sql:"request,notnull"`
+	Status             bean.MigrationStatus `sql:"status,notnull"`
+	TargetChartRefId   int                  `sql:"target_chart_ref_id,notnull"`
+	TargetChartName    string               `sql:"target_chart_name"`
+	TargetChartVersion string               `sql:"target_chart_version"`
+	TotalTargets       int                  `sql:"total_targets,notnull"`
+	ReadyTargets       int                  `sql:"ready_targets,notnull"`
+	Succeeded          int                  `sql:"succeeded,notnull"`
+	Failed             int                  `sql:"failed,notnull"`
+	sql.AuditLog
+}
+
+// ChartRefMigrationTarget is the base or environment deployment template of one app with its dry run report
+type ChartRefMigrationTarget struct {
+	tableName           struct{}          `sql:"chart_ref_migration_target" pg:",discard_unknown_columns"`
+	Id                  int               `sql:"id,pk"`
+	ChartRefMigrationId int               `sql:"chart_ref_migration_id,notnull"`
+	AppId               int               `sql:"app_id,notnull"`
+	AppName             string            `sql:"app_name,notnull"`
+	EnvId               int               `sql:"env_id,notnull"`
+	EnvName             string            `sql:"env_name"`
+	SourceChartRefId    int               `sql:"source_chart_ref_id,notnull"`
+	SourceChartName     string            `sql:"source_chart_name"`
+	SourceChartVersion  string            `sql:"source_chart_version"`
+	Status              bean.TargetStatus `sql:"status,notnull"`
+	// AppliedRules is the comma separated list of migration rules which changed the values
+	AppliedRules string `sql:"applied_rules"`
+	ValuesDiff   string `sql:"values_diff"`
+	ManifestDiff string `sql:"manifest_diff"`
+	Message      string `sql:"message"`
+	sql.AuditLog
+}
+
+type ChartRefMigrationRepository interface {
+	SaveMigration(migration *ChartRefMigration, tx *pg.Tx) error
+	UpdateMigration(migration *ChartRefMigration) error
+	FindMigrationById(id int) (*ChartRefMigration, error)
+	FindAllMigrations() ([]*ChartRefMigration, error)
+
+	SaveTargets(targets []*ChartRefMigrationTarget, tx *pg.Tx) error
+	UpdateTarget(target *ChartRefMigrationTarget) error
+	FindTargetsByMigrationId(migrationId int) ([]*ChartRefMigrationTarget, error)
+	sql.TransactionWrapper
+}
+
+type ChartRefMigrationRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewChartRefMigrationRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ChartRefMigrationRepositoryImpl {
+	return &ChartRefMigrationRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: sql.NewTransactionUtilImpl(dbConnection),
+	}
+}
+
+func (impl *ChartRefMigrationRepositoryImpl) SaveMigration(migration *ChartRefMigration, tx *pg.Tx) error {
+	return tx.Insert(migration)
+}
+
+func (impl *ChartRefMigrationRepositoryImpl) UpdateMigration(migration *ChartRefMigration) error {
+	return impl.dbConnection.Update(migration)
+}
+
+func (impl *ChartRefMigrationRepositoryImpl) FindMigrationById(id int) (*ChartRefMigration, error) {
+	migration := &ChartRefMigration{}
+	err := impl.dbConnection.Model(migration).
+		Where("id = ?", id).
+		Select()
+	return migration, err
+}
+
+func (impl *ChartRefMigrationRepositoryImpl) FindAllMigrations() ([]*ChartRefMigration, error) {
+	var migrations []*ChartRefMigration
+	err := impl.dbConnection.Model(&migrations).
+		Order("id DESC").
+		Select()
+	return migrations, err
+}
+
+func (impl *ChartRefMigrationRepositoryImpl) SaveTargets(targets []*ChartRefMigrationTarget, tx *pg.Tx) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	return tx.Insert(&targets)
+}
+
+func (impl *ChartRefMigrationRepositoryImpl) UpdateTarget(target *ChartRefMigrationTarget) error {
+	return impl.dbConnection.Update(target)
+}
+
+func (impl *ChartRefMigrationRepositoryImpl) FindTargetsByMigrationId(migrationId int) ([]*ChartRefMigrationTarget, error) {
+	var targets []*ChartRefMigrationTarget
+	err := impl.dbConnection.Model(&targets).
+		Where("chart_ref_migration_id = ?", migrationId).
+		Order("id ASC").
+		Select()
+	return targets, err
+}
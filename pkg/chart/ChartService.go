@@ -35,6 +35,7 @@ import (
 	adapter2 "github.com/devtron-labs/devtron/pkg/deployment/common/adapter"
 	bean2 "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/monorepo"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deployedAppMetrics"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deployedAppMetrics/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate"
@@ -97,6 +98,7 @@ type ChartServiceImpl struct {
 	deploymentConfigService          common.DeploymentConfigService
 	envConfigOverrideReadService     read.EnvConfigOverrideService
 	chartReadService                 read2.ChartReadService
+	gitOpsMonorepoService            monorepo.GitOpsMonorepoService
 }
 
 func NewChartServiceImpl(chartRepository chartRepoRepository.ChartRepository,
@@ -115,7 +117,8 @@ func NewChartServiceImpl(chartRepository chartRepoRepository.ChartRepository,
 	gitOpsConfigReadService config.GitOpsConfigReadService,
 	deploymentConfigService common.DeploymentConfigService,
 	envConfigOverrideReadService read.EnvConfigOverrideService,
-	chartReadService read2.ChartReadService) *ChartServiceImpl {
+	chartReadService read2.ChartReadService,
+	gitOpsMonorepoService monorepo.GitOpsMonorepoService) *ChartServiceImpl {
 	return &ChartServiceImpl{
 		chartRepository:                  chartRepository,
 		logger:                           logger,
@@ -134,6 +137,7 @@ func NewChartServiceImpl(chartRepository chartRepoRepository.ChartRepository,
 		deploymentConfigService:          deploymentConfigService,
 		envConfigOverrideReadService:     envConfigOverrideReadService,
 		chartReadService:                 chartReadService,
+		gitOpsMonorepoService:            gitOpsMonorepoService,
 	}
 }
 
@@ -1005,6 +1009,11 @@ func (impl *ChartServiceImpl) ConfigureGitOpsRepoUrlForApp(appId int, repoUrl, c
 		return nil, err
 	}
 	deploymentConfig = deploymentConfig.SetRepoURL(repoUrl)
+	err = impl.gitOpsMonorepoService.ApplyPathPrefix(deploymentConfig)
+	if err != nil {
+		impl.logger.Errorw("error in applying gitops monorepo path", "appId", appId, "err", err)
+		return nil, err
+	}
 	deploymentConfig.SetChartLocation(chartLocation)
 
 	deploymentConfig, err = impl.deploymentConfigService.CreateOrUpdateConfig(nil, deploymentConfig, userId)
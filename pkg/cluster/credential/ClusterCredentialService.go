@@ -0,0 +1,368 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package credential
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/credential/adapter"
+	"github.com/devtron-labs/devtron/pkg/cluster/credential/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/credential/helper"
+	"github.com/devtron-labs/devtron/pkg/cluster/credential/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cronUtil "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ClusterCredentialConfig struct {
+	ExpiryWarningDays               int   `env:"CLUSTER_CREDENTIAL_EXPIRY_WARNING_DAYS" envDefault:"15" description:"Cluster credentials expiring within these many days are notified and reported as expiring soon"`
+	ExpiryCheckIntervalMins         int   `env:"CLUSTER_CREDENTIAL_EXPIRY_CHECK_INTERVAL_MINS" envDefault:"360" description:"Interval at which the expiry of cluster tokens and certificates is recorded and notified, 0 disables the check"`
+	ExpiryNotificationIntervalHours int   `env:"CLUSTER_CREDENTIAL_EXPIRY_NOTIFICATION_INTERVAL_HOURS" envDefault:"24" description:"Minimum interval between two expiry notifications of a cluster"`
+	RotationTokenExpirySecs         int64 `env:"CLUSTER_CREDENTIAL_ROTATION_TOKEN_EXPIRY_SECS" envDefault:"0" description:"Expiry of the service account tokens issued on rotation, 0 keeps the lifetime of the current token"`
+}
+
+func GetClusterCredentialConfig() (*ClusterCredentialConfig, error) {
+	cfg := &ClusterCredentialConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+type ClusterCredentialService interface {
+	GetCredentialExpiry(clusterId int) (*bean.ClusterCredentialExpiryDto, error)
+	GetAllCredentialExpiry() ([]*bean.ClusterCredentialExpiryDto, error)
+	// CheckCredentialExpiry records the credential expiry of all active clusters and notifies the ones expiring
+	// within the warning days
+	CheckCredentialExpiry()
+
+	// RotateServiceAccountToken re-issues the token of the service account the cluster connects with and saves it in
+	// the cluster config and the argocd cluster secret. The previous config is restored if saving fails, every
+	// attempt is audited.
+	RotateServiceAccountToken(ctx context.Context, request *bean.RotateCredentialRequest) (*bean.RotationAuditDto, error)
+	GetRotationAudits(clusterId int) ([]*bean.RotationAuditDto, error)
+}
+
+type ClusterCredentialServiceImpl struct {
+	logger                     *zap.SugaredLogger
+	clusterService             cluster.ClusterService
+	credentialExpiryRepository repository.ClusterCredentialExpiryRepository
+	rotationAuditRepository    repository.ClusterCredentialRotationAuditRepository
+	tokenRotator               ServiceAccountTokenRotator
+	expiryNotifier             CredentialExpiryNotifier
+	config                     *ClusterCredentialConfig
+}
+
+func NewClusterCredentialServiceImpl(logger *zap.SugaredLogger,
+	clusterService cluster.ClusterService,
+	credentialExpiryRepository repository.ClusterCredentialExpiryRepository,
+	rotationAuditRepository repository.ClusterCredentialRotationAuditRepository,
+	tokenRotator ServiceAccountTokenRotator,
+	expiryNotifier CredentialExpiryNotifier,
+	config *ClusterCredentialConfig,
+	cronLogger *cronUtil.CronLoggerImpl) (*ClusterCredentialServiceImpl, error) {
+	impl := &ClusterCredentialServiceImpl{
+		logger:                     logger,
+		clusterService:             clusterService,
+		credentialExpiryRepository: credentialExpiryRepository,
+		rotationAuditRepository:    rotationAuditRepository,
+		tokenRotator:               tokenRotator,
+		expiryNotifier:             expiryNotifier,
+		config:                     config,
+	}
+	if config.ExpiryCheckIntervalMins > 0 {
+		expiryCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+		_, err := expiryCron.AddFunc(fmt.Sprintf("@every %dm", config.ExpiryCheckIntervalMins), impl.CheckCredentialExpiry)
+		if err != nil {
+			logger.Errorw("error in adding cluster credential expiry check cron", "err", err)
+			return nil, err
+		}
+		expiryCron.Start()
+	}
+	return impl, nil
+}
+
+func (impl *ClusterCredentialServiceImpl) GetCredentialExpiry(clusterId int) (*bean.ClusterCredentialExpiryDto, error) {
+	existingCluster, err := impl.getCluster(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	expiry := impl.parseCredentialExpiry(existingCluster, time.Now())
+	recorded, err := impl.credentialExpiryRepository.FindByClusterId(clusterId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting cluster credential expiry", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	expiry.LastNotifiedOn = recorded.LastNotifiedOn
+	return expiry, nil
+}
+
+func (impl *ClusterCredentialServiceImpl) GetAllCredentialExpiry() ([]*bean.ClusterCredentialExpiryDto, error) {
+	clusters, err := impl.clusterService.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in getting active clusters", "err", err)
+		return nil, err
+	}
+	recordedExpiries, err := impl.credentialExpiryRepository.FindAllActive()
+	if err != nil {
+		return nil, err
+	}
+	recordedByClusterId := make(map[int]*repository.ClusterCredentialExpiry, len(recordedExpiries))
+	for _, recorded := range recordedExpiries {
+		recordedByClusterId[recorded.ClusterId] = recorded
+	}
+	now := time.Now()
+	expiries := make([]*bean.ClusterCredentialExpiryDto, 0, len(clusters))
+	for i := range clusters {
+		if clusters[i].IsVirtualCluster {
+			continue
+		}
+		expiry := impl.parseCredentialExpiry(&clusters[i], now)
+		if recorded, ok := recordedByClusterId[clusters[i].Id]; ok {
+			expiry.LastNotifiedOn = recorded.LastNotifiedOn
+		}
+		expiries = append(expiries, expiry)
+	}
+	return expiries, nil
+}
+
+func (impl *ClusterCredentialServiceImpl) CheckCredentialExpiry() {
+	clusters, err := impl.clusterService.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in getting active clusters for credential expiry check", "err", err)
+		return
+	}
+	now := time.Now()
+	for i := range clusters {
+		if clusters[i].IsVirtualCluster {
+			continue
+		}
+		expiry := impl.parseCredentialExpiry(&clusters[i], now)
+		if err = impl.recordCredentialExpiry(expiry, now, true); err != nil {
+			impl.logger.Errorw("error in recording cluster credential expiry", "clusterId", expiry.ClusterId, "err", err)
+		}
+	}
+}
+
+// recordCredentialExpiry saves the parsed expiry of the cluster, the cluster is notified when its credentials are
+// expiring and it was not notified within the notification interval
+func (impl *ClusterCredentialServiceImpl) recordCredentialExpiry(expiry *bean.ClusterCredentialExpiryDto, now time.Time, notify bool) error {
+	recorded, err := impl.credentialExpiryRepository.FindByClusterId(expiry.ClusterId)
+	if err != nil && err != pg.ErrNoRows {
+		return err
+	}
+	adapter.UpdateCredentialExpiry(recorded, expiry)
+	recorded.LastCheckedOn = now
+	notifyAfter := now.Add(-time.Duration(impl.config.ExpiryNotificationIntervalHours) * time.Hour)
+	if notify && expiry.IsExpiring() && (recorded.LastNotifiedOn == nil || recorded.LastNotifiedOn.Before(notifyAfter)) {
+		if err = impl.expiryNotifier.NotifyExpiry(expiry); err != nil {
+			impl.logger.Errorw("error in notifying cluster credential expiry", "clusterId", expiry.ClusterId, "err", err)
+		} else {
+			recorded.LastNotifiedOn = &now
+		}
+	}
+	if recorded.Id == 0 {
+		recorded.AuditLog = sql.NewDefaultAuditLog(userBean.SystemUserId)
+		return impl.credentialExpiryRepository.Save(recorded)
+	}
+	recorded.UpdateAuditLog(userBean.SystemUserId)
+	return impl.credentialExpiryRepository.Update(recorded)
+}
+
+func (impl *ClusterCredentialServiceImpl) parseCredentialExpiry(existingCluster *clusterBean.ClusterBean, now time.Time) *bean.ClusterCredentialExpiryDto {
+	expiry := &bean.ClusterCredentialExpiryDto{ClusterId: existingCluster.Id, ClusterName: existingCluster.ClusterName}
+	var parseErrors []string
+	if claims, err := helper.ParseTokenClaims(existingCluster.Config[commonBean.BearerToken]); err != nil {
+		parseErrors = append(parseErrors, err.Error())
+	} else if claims != nil {
+		expiry.TokenExpiresAt = claims.ExpiresAt
+	}
+	var err error
+	if expiry.ClientCertExpiresAt, err = helper.ParseCertificateExpiry(existingCluster.Config[commonBean.CertData]); err != nil {
+		parseErrors = append(parseErrors, fmt.Sprintf("client certificate: %s", err.Error()))
+	}
+	if !existingCluster.InsecureSkipTLSVerify {
+		if expiry.CaCertExpiresAt, err = helper.ParseCertificateExpiry(existingCluster.Config[commonBean.CertificateAuthorityData]); err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("ca certificate: %s", err.Error()))
+		}
+	}
+	expiry.ParseError = strings.Join(parseErrors, ", ")
+	expiry.SetExpiryStatus(now, now.AddDate(0, 0, impl.config.ExpiryWarningDays))
+	return expiry
+}
+
+func (impl *ClusterCredentialServiceImpl) RotateServiceAccountToken(ctx context.Context, request *bean.RotateCredentialRequest) (*bean.RotationAuditDto, error) {
+	existingCluster, err := impl.getCluster(request.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	if existingCluster.IsVirtualCluster || existingCluster.ClusterName == clusterBean.DefaultCluster {
+		return nil, util.NewApiError(http.StatusBadRequest, "credentials of this cluster cannot be rotated", "credentials of virtual and default cluster cannot be rotated")
+	}
+	claims, err := helper.ParseTokenClaims(existingCluster.Config[commonBean.BearerToken])
+	if err != nil {
+		impl.logger.Warnw("error in parsing current bearer token of cluster", "clusterId", request.ClusterId, "err", err)
+	}
+	if claims == nil {
+		claims = &helper.TokenClaims{}
+	}
+	target := &bean.RotationTarget{
+		Namespace:  request.ServiceAccountNamespace,
+		Name:       request.ServiceAccountName,
+		ExpirySecs: request.TokenExpirySecs,
+	}
+	if len(target.Name) == 0 {
+		target.Namespace, target.Name = claims.ServiceAccountNamespace, claims.ServiceAccountName
+	}
+	if len(target.Namespace) == 0 || len(target.Name) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "service account is required as the current credentials are not of a service account",
+			"service account not found in current bearer token")
+	}
+	if target.ExpirySecs == 0 {
+		target.ExpirySecs = impl.config.RotationTokenExpirySecs
+	}
+	if target.ExpirySecs == 0 {
+		target.ExpirySecs = int64(claims.GetLifetime().Seconds())
+	}
+	audit := &repository.ClusterCredentialRotationAudit{
+		ClusterId:               request.ClusterId,
+		ServiceAccountNamespace: target.Namespace,
+		ServiceAccountName:      target.Name,
+		PreviousExpiresAt:       claims.ExpiresAt,
+		AuditLog:                sql.NewDefaultAuditLog(request.UserId),
+	}
+	issuedToken, status, err := impl.rotateToken(ctx, existingCluster, claims, target, request.UserId)
+	audit.Status = string(status)
+	if issuedToken != nil {
+		audit.NewExpiresAt = issuedToken.ExpiresAt
+	}
+	if err != nil {
+		audit.Message = util.GetClientErrorDetailedMessage(err)
+	}
+	if saveErr := impl.rotationAuditRepository.Save(audit); saveErr != nil {
+		impl.logger.Errorw("error in saving cluster credential rotation audit", "clusterId", request.ClusterId, "status", status, "err", saveErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return adapter.GetRotationAuditDto(audit), nil
+}
+
+func (impl *ClusterCredentialServiceImpl) rotateToken(ctx context.Context, existingCluster *clusterBean.ClusterBean, claims *helper.TokenClaims,
+	target *bean.RotationTarget, userId int32) (*bean.IssuedToken, bean.RotationStatus, error) {
+	clusterConfig := existingCluster.GetClusterConfig()
+	clusterConfig.ClusterName = existingCluster.ClusterName
+	issuedToken, err := impl.tokenRotator.IssueToken(ctx, clusterConfig, target)
+	if err != nil {
+		impl.logger.Errorw("error in issuing service account token", "clusterId", existingCluster.Id, "err", err)
+		return nil, bean.RotationStatusFailed, err
+	}
+	newClusterConfig := existingCluster.GetClusterConfig()
+	newClusterConfig.ClusterName = existingCluster.ClusterName
+	newClusterConfig.BearerToken = issuedToken.Token
+	if err = impl.tokenRotator.VerifyToken(ctx, newClusterConfig); err != nil {
+		impl.logger.Errorw("error in connecting to cluster with the issued token", "clusterId", existingCluster.Id, "err", err)
+		impl.revokeIssuedToken(ctx, clusterConfig, target, issuedToken)
+		return issuedToken, bean.RotationStatusFailed, err
+	}
+	previousConfig := make(map[string]string, len(existingCluster.Config))
+	updatedConfig := make(map[string]string, len(existingCluster.Config))
+	for key, value := range existingCluster.Config {
+		previousConfig[key], updatedConfig[key] = value, value
+	}
+	updatedConfig[commonBean.BearerToken] = issuedToken.Token
+	existingCluster.Config = updatedConfig
+	// the argocd cluster secret is upserted by the update as well
+	_, err = impl.clusterService.Update(ctx, existingCluster, userId)
+	if err != nil {
+		impl.logger.Errorw("error in saving rotated cluster token, restoring previous config", "clusterId", existingCluster.Id, "err", err)
+		if restoreErr := impl.restoreClusterConfig(ctx, existingCluster.Id, previousConfig, userId); restoreErr != nil {
+			// the new token may be in use now, it is not revoked
+			impl.logger.Errorw("error in restoring previous cluster config", "clusterId", existingCluster.Id, "err", restoreErr)
+			return issuedToken, bean.RotationStatusFailed, fmt.Errorf("%s, restoring previous config failed: %s",
+				util.GetClientErrorDetailedMessage(err), util.GetClientErrorDetailedMessage(restoreErr))
+		}
+		impl.revokeIssuedToken(ctx, clusterConfig, target, issuedToken)
+		return issuedToken, bean.RotationStatusRolledBack, err
+	}
+	if len(claims.SecretName) > 0 {
+		// the previous token was read from a service account token secret and stays valid until the secret is deleted
+		if err = impl.tokenRotator.RevokeSecretToken(ctx, newClusterConfig, claims.ServiceAccountNamespace, claims.SecretName); err != nil {
+			impl.logger.Errorw("error in revoking previous service account token secret", "clusterId", existingCluster.Id, "secret", claims.SecretName, "err", err)
+		}
+	}
+	if err = impl.recordCredentialExpiry(impl.parseCredentialExpiry(existingCluster, time.Now()), time.Now(), false); err != nil {
+		impl.logger.Errorw("error in recording cluster credential expiry", "clusterId", existingCluster.Id, "err", err)
+	}
+	return issuedToken, bean.RotationStatusSucceeded, nil
+}
+
+// restoreClusterConfig saves previousConfig again if the failed update had already saved the new config
+func (impl *ClusterCredentialServiceImpl) restoreClusterConfig(ctx context.Context, clusterId int, previousConfig map[string]string, userId int32) error {
+	savedCluster, err := impl.clusterService.FindById(clusterId)
+	if err != nil {
+		return err
+	}
+	if savedCluster.Config[commonBean.BearerToken] == previousConfig[commonBean.BearerToken] {
+		return nil
+	}
+	savedCluster.Config = previousConfig
+	_, err = impl.clusterService.Update(ctx, savedCluster, userId)
+	return err
+}
+
+func (impl *ClusterCredentialServiceImpl) revokeIssuedToken(ctx context.Context, clusterConfig *k8s.ClusterConfig, target *bean.RotationTarget, issuedToken *bean.IssuedToken) {
+	if len(issuedToken.SecretName) == 0 {
+		// tokens issued through the TokenRequest api cannot be revoked, they expire
+		return
+	}
+	if err := impl.tokenRotator.RevokeSecretToken(ctx, clusterConfig, target.Namespace, issuedToken.SecretName); err != nil {
+		impl.logger.Errorw("error in revoking issued service account token secret", "secret", issuedToken.SecretName, "err", err)
+	}
+}
+
+func (impl *ClusterCredentialServiceImpl) GetRotationAudits(clusterId int) ([]*bean.RotationAuditDto, error) {
+	audits, err := impl.rotationAuditRepository.FindByClusterId(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	auditDtos := make([]*bean.RotationAuditDto, 0, len(audits))
+	for _, audit := range audits {
+		auditDtos = append(auditDtos, adapter.GetRotationAuditDto(audit))
+	}
+	return auditDtos, nil
+}
+
+func (impl *ClusterCredentialServiceImpl) getCluster(clusterId int) (*clusterBean.ClusterBean, error) {
+	existingCluster, err := impl.clusterService.FindById(clusterId)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "cluster not found", "cluster not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in getting cluster", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	return existingCluster, nil
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package credential
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/credential/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/credential/repository"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func buildToken(claims string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"RS256"}`)) + "." + encode([]byte(claims)) + ".signature"
+}
+
+type fakeClusterService struct {
+	cluster.ClusterService
+	cluster   *clusterBean.ClusterBean
+	updateErr error
+	updates   []string
+}
+
+func (f *fakeClusterService) FindById(id int) (*clusterBean.ClusterBean, error) {
+	if f.cluster == nil || f.cluster.Id != id {
+		return nil, pg.ErrNoRows
+	}
+	saved := *f.cluster
+	saved.Config = make(map[string]string)
+	for key, value := range f.cluster.Config {
+		saved.Config[key] = value
+	}
+	return &saved, nil
+}
+
+func (f *fakeClusterService) FindAllActive() ([]clusterBean.ClusterBean, error) {
+	return []clusterBean.ClusterBean{*f.cluster}, nil
+}
+
+func (f *fakeClusterService) Update(ctx context.Context, bean *clusterBean.ClusterBean, userId int32) (*clusterBean.ClusterBean, error) {
+	f.updates = append(f.updates, bean.Config[commonBean.BearerToken])
+	// the db is updated before the argocd cluster secret, a failing update has saved the config already
+	f.cluster = bean
+	if f.updateErr != nil {
+		err := f.updateErr
+		f.updateErr = nil
+		return nil, err
+	}
+	return bean, nil
+}
+
+type fakeTokenRotator struct {
+	issued  *bean.IssuedToken
+	revoked []string
+}
+
+func (f *fakeTokenRotator) IssueToken(ctx context.Context, clusterConfig *k8s.ClusterConfig, target *bean.RotationTarget) (*bean.IssuedToken, error) {
+	return f.issued, nil
+}
+
+func (f *fakeTokenRotator) VerifyToken(ctx context.Context, clusterConfig *k8s.ClusterConfig) error {
+	return nil
+}
+
+func (f *fakeTokenRotator) RevokeSecretToken(ctx context.Context, clusterConfig *k8s.ClusterConfig, namespace, secretName string) error {
+	f.revoked = append(f.revoked, namespace+"/"+secretName)
+	return nil
+}
+
+type fakeExpiryRepository struct {
+	repository.ClusterCredentialExpiryRepository
+	expiry *repository.ClusterCredentialExpiry
+}
+
+func (f *fakeExpiryRepository) FindByClusterId(clusterId int) (*repository.ClusterCredentialExpiry, error) {
+	if f.expiry == nil {
+		return &repository.ClusterCredentialExpiry{}, pg.ErrNoRows
+	}
+	return f.expiry, nil
+}
+
+func (f *fakeExpiryRepository) Save(expiry *repository.ClusterCredentialExpiry) error {
+	expiry.Id = 1
+	f.expiry = expiry
+	return nil
+}
+
+func (f *fakeExpiryRepository) Update(expiry *repository.ClusterCredentialExpiry) error {
+	f.expiry = expiry
+	return nil
+}
+
+type fakeRotationAuditRepository struct {
+	repository.ClusterCredentialRotationAuditRepository
+	audits []*repository.ClusterCredentialRotationAudit
+}
+
+func (f *fakeRotationAuditRepository) Save(audit *repository.ClusterCredentialRotationAudit) error {
+	f.audits = append(f.audits, audit)
+	return nil
+}
+
+type fakeExpiryNotifier struct {
+	notified []int
+}
+
+func (f *fakeExpiryNotifier) NotifyExpiry(expiry *bean.ClusterCredentialExpiryDto) error {
+	f.notified = append(f.notified, expiry.ClusterId)
+	return nil
+}
+
+func newTestService(clusterService *fakeClusterService, rotator *fakeTokenRotator) (*ClusterCredentialServiceImpl, *fakeRotationAuditRepository, *fakeExpiryNotifier) {
+	auditRepository := &fakeRotationAuditRepository{}
+	notifier := &fakeExpiryNotifier{}
+	config := &ClusterCredentialConfig{ExpiryWarningDays: 15, ExpiryNotificationIntervalHours: 24}
+	impl, _ := NewClusterCredentialServiceImpl(zap.NewNop().Sugar(), clusterService, &fakeExpiryRepository{}, auditRepository,
+		rotator, notifier, config, nil)
+	return impl, auditRepository, notifier
+}
+
+func TestRotateServiceAccountToken(t *testing.T) {
+	legacyToken := buildToken(`{"sub":"system:serviceaccount:devtroncd:devtron","kubernetes.io/serviceaccount/namespace":"devtroncd",
+		"kubernetes.io/serviceaccount/service-account.name":"devtron","kubernetes.io/serviceaccount/secret.name":"devtron-token-old"}`)
+	newCluster := func() *clusterBean.ClusterBean {
+		return &clusterBean.ClusterBean{Id: 2, ClusterName: "prod", ServerUrl: "https://prod", Active: true,
+			Config: map[string]string{commonBean.BearerToken: legacyToken}}
+	}
+
+	t.Run("legacy token is rotated and its secret revoked", func(t *testing.T) {
+		clusterService := &fakeClusterService{cluster: newCluster()}
+		rotator := &fakeTokenRotator{issued: &bean.IssuedToken{Token: "new-token", SecretName: "devtron-token-new"}}
+		impl, auditRepository, _ := newTestService(clusterService, rotator)
+
+		audit, err := impl.RotateServiceAccountToken(context.Background(), &bean.RotateCredentialRequest{ClusterId: 2, UserId: 5})
+		assert.Nil(t, err)
+		assert.Equal(t, bean.RotationStatusSucceeded, audit.Status)
+		assert.Equal(t, "devtroncd", audit.ServiceAccountNamespace)
+		assert.Equal(t, "new-token", clusterService.cluster.Config[commonBean.BearerToken])
+		assert.Equal(t, []string{"devtroncd/devtron-token-old"}, rotator.revoked)
+		assert.Len(t, auditRepository.audits, 1)
+	})
+
+	t.Run("previous config is restored when saving fails", func(t *testing.T) {
+		clusterService := &fakeClusterService{cluster: newCluster(), updateErr: errors.New("argocd unavailable")}
+		rotator := &fakeTokenRotator{issued: &bean.IssuedToken{Token: "new-token", SecretName: "devtron-token-new"}}
+		impl, auditRepository, _ := newTestService(clusterService, rotator)
+
+		_, err := impl.RotateServiceAccountToken(context.Background(), &bean.RotateCredentialRequest{ClusterId: 2, UserId: 5})
+		assert.NotNil(t, err)
+		assert.Equal(t, []string{"new-token", legacyToken}, clusterService.updates)
+		assert.Equal(t, legacyToken, clusterService.cluster.Config[commonBean.BearerToken])
+		assert.Equal(t, []string{"devtroncd/devtron-token-new"}, rotator.revoked)
+		assert.Len(t, auditRepository.audits, 1)
+		assert.Equal(t, string(bean.RotationStatusRolledBack), auditRepository.audits[0].Status)
+	})
+
+	t.Run("service account is required for non service account tokens", func(t *testing.T) {
+		existingCluster := newCluster()
+		existingCluster.Config[commonBean.BearerToken] = "static-token"
+		impl, auditRepository, _ := newTestService(&fakeClusterService{cluster: existingCluster}, &fakeTokenRotator{})
+
+		_, err := impl.RotateServiceAccountToken(context.Background(), &bean.RotateCredentialRequest{ClusterId: 2, UserId: 5})
+		assert.NotNil(t, err)
+		assert.Empty(t, auditRepository.audits)
+	})
+}
+
+func TestCheckCredentialExpiry(t *testing.T) {
+	expiringToken := buildToken(`{"exp":` + strconv.FormatInt(time.Now().Add(72*time.Hour).Unix(), 10) + `}`)
+	clusterService := &fakeClusterService{cluster: &clusterBean.ClusterBean{Id: 3, ClusterName: "stage",
+		Config: map[string]string{commonBean.BearerToken: expiringToken}}}
+	impl, _, notifier := newTestService(clusterService, &fakeTokenRotator{})
+
+	impl.CheckCredentialExpiry()
+	impl.CheckCredentialExpiry()
+	// notified once within the notification interval
+	assert.Equal(t, []int{3}, notifier.notified)
+
+	expiry, err := impl.GetCredentialExpiry(3)
+	assert.Nil(t, err)
+	assert.Equal(t, bean.ExpiryStatusExpiringSoon, expiry.Status)
+	assert.Equal(t, bean.CredentialTypeBearerToken, expiry.ExpiringCredential)
+	assert.NotNil(t, expiry.LastNotifiedOn)
+}
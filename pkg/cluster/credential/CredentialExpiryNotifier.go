@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package credential
+
+import (
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/pkg/cluster/credential/bean"
+	util "github.com/devtron-labs/devtron/util/event"
+	"go.uber.org/zap"
+	"time"
+)
+
+// CredentialExpiryNotifier warns about cluster credentials expiring soon
+type CredentialExpiryNotifier interface {
+	NotifyExpiry(expiry *bean.ClusterCredentialExpiryDto) error
+}
+
+// CredentialExpiryEventNotifierImpl sends the expiry warning as a notification event, matched to the notification
+// rules of the cluster
+type CredentialExpiryEventNotifierImpl struct {
+	logger       *zap.SugaredLogger
+	eventClient  client.EventClient
+	eventFactory client.EventFactory
+}
+
+func NewCredentialExpiryEventNotifierImpl(logger *zap.SugaredLogger, eventClient client.EventClient,
+	eventFactory client.EventFactory) *CredentialExpiryEventNotifierImpl {
+	return &CredentialExpiryEventNotifierImpl{
+		logger:       logger,
+		eventClient:  eventClient,
+		eventFactory: eventFactory,
+	}
+}
+
+func (impl *CredentialExpiryEventNotifierImpl) NotifyExpiry(expiry *bean.ClusterCredentialExpiryDto) error {
+	event, err := impl.eventFactory.Build(util.ClusterCredentialExpiry, nil, 0, nil, util.CLUSTER)
+	if err != nil {
+		impl.logger.Errorw("error in building cluster credential expiry event", "clusterId", expiry.ClusterId, "err", err)
+		return err
+	}
+	event.ClusterId = expiry.ClusterId
+	event.Payload = &client.Payload{
+		ClusterName:         expiry.ClusterName,
+		CredentialType:      string(expiry.ExpiringCredential),
+		CredentialExpiresAt: expiry.ExpiresAt.Format(time.RFC1123),
+	}
+	_, err = impl.eventClient.WriteNotificationEvent(event)
+	if err != nil {
+		impl.logger.Errorw("error in sending cluster credential expiry event", "clusterId", expiry.ClusterId, "err", err)
+		return err
+	}
+	return nil
+}
+
+// CredentialExpiryLogNotifierImpl only logs the expiry warning, used where notifications are not available
+type CredentialExpiryLogNotifierImpl struct {
+	logger *zap.SugaredLogger
+}
+
+func NewCredentialExpiryLogNotifierImpl(logger *zap.SugaredLogger) *CredentialExpiryLogNotifierImpl {
+	return &CredentialExpiryLogNotifierImpl{logger: logger}
+}
+
+func (impl *CredentialExpiryLogNotifierImpl) NotifyExpiry(expiry *bean.ClusterCredentialExpiryDto) error {
+	impl.logger.Warnw("cluster credential expiring", "clusterId", expiry.ClusterId, "clusterName", expiry.ClusterName,
+		"credential", expiry.ExpiringCredential, "expiresAt", expiry.ExpiresAt)
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package credential
+
+import (
+	"context"
+	"fmt"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/pkg/cluster/credential/bean"
+	"go.uber.org/zap"
+	authenticationV1 "k8s.io/api/authentication/v1"
+	coreV1 "k8s.io/api/core/v1"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"time"
+)
+
+const (
+	managedByLabelKey    = "app.kubernetes.io/managed-by"
+	tokenSecretPollDelay = 500 * time.Millisecond
+	tokenSecretTimeout   = 30 * time.Second
+)
+
+// ServiceAccountTokenRotator re-issues the token of the service account devtron connects to a cluster with
+type ServiceAccountTokenRotator interface {
+	// IssueToken issues a new token for the service account of target, through the TokenRequest api when target has
+	// an expiry and through a new service account token secret otherwise. The previous token stays valid.
+	IssueToken(ctx context.Context, clusterConfig *k8s.ClusterConfig, target *bean.RotationTarget) (*bean.IssuedToken, error)
+	// VerifyToken checks that clusterConfig authenticates against the cluster, /version is not used as it is
+	// served to anonymous users as well
+	VerifyToken(ctx context.Context, clusterConfig *k8s.ClusterConfig) error
+	// RevokeSecretToken deletes the service account token secret, invalidating the legacy token read from it
+	RevokeSecretToken(ctx context.Context, clusterConfig *k8s.ClusterConfig, namespace, secretName string) error
+}
+
+type ServiceAccountTokenRotatorImpl struct {
+	logger  *zap.SugaredLogger
+	k8sUtil *k8s.K8sServiceImpl
+}
+
+func NewServiceAccountTokenRotatorImpl(logger *zap.SugaredLogger, k8sUtil *k8s.K8sServiceImpl) *ServiceAccountTokenRotatorImpl {
+	return &ServiceAccountTokenRotatorImpl{
+		logger:  logger,
+		k8sUtil: k8sUtil,
+	}
+}
+
+func (impl *ServiceAccountTokenRotatorImpl) IssueToken(ctx context.Context, clusterConfig *k8s.ClusterConfig, target *bean.RotationTarget) (*bean.IssuedToken, error) {
+	_, _, clientSet, err := impl.k8sUtil.GetK8sConfigAndClients(clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error in getting client set", "clusterName", clusterConfig.ClusterName, "err", err)
+		return nil, err
+	}
+	_, err = clientSet.CoreV1().ServiceAccounts(target.Namespace).Get(ctx, target.Name, metaV1.GetOptions{})
+	if err != nil {
+		impl.logger.Errorw("error in getting service account", "clusterName", clusterConfig.ClusterName, "namespace", target.Namespace, "name", target.Name, "err", err)
+		return nil, err
+	}
+	if target.ExpirySecs > 0 {
+		tokenRequest := &authenticationV1.TokenRequest{
+			Spec: authenticationV1.TokenRequestSpec{ExpirationSeconds: &target.ExpirySecs},
+		}
+		tokenRequest, err = clientSet.CoreV1().ServiceAccounts(target.Namespace).CreateToken(ctx, target.Name, tokenRequest, metaV1.CreateOptions{})
+		if err != nil {
+			impl.logger.Errorw("error in requesting service account token", "clusterName", clusterConfig.ClusterName, "namespace", target.Namespace, "name", target.Name, "err", err)
+			return nil, err
+		}
+		expiresAt := tokenRequest.Status.ExpirationTimestamp.Time
+		return &bean.IssuedToken{Token: tokenRequest.Status.Token, ExpiresAt: &expiresAt}, nil
+	}
+	secret := &coreV1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-token-", target.Name),
+			Namespace:    target.Namespace,
+			Labels:       map[string]string{managedByLabelKey: "devtron"},
+			Annotations:  map[string]string{coreV1.ServiceAccountNameKey: target.Name},
+		},
+		Type: coreV1.SecretTypeServiceAccountToken,
+	}
+	secret, err = clientSet.CoreV1().Secrets(target.Namespace).Create(ctx, secret, metaV1.CreateOptions{})
+	if err != nil {
+		impl.logger.Errorw("error in creating service account token secret", "clusterName", clusterConfig.ClusterName, "namespace", target.Namespace, "name", target.Name, "err", err)
+		return nil, err
+	}
+	// the token controller populates the token of the secret asynchronously
+	var token string
+	err = wait.PollUntilContextTimeout(ctx, tokenSecretPollDelay, tokenSecretTimeout, true, func(ctx context.Context) (bool, error) {
+		populated, err := clientSet.CoreV1().Secrets(target.Namespace).Get(ctx, secret.Name, metaV1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		token = string(populated.Data[coreV1.ServiceAccountTokenKey])
+		return len(token) > 0, nil
+	})
+	if err != nil {
+		impl.logger.Errorw("error in waiting for service account token secret", "clusterName", clusterConfig.ClusterName, "secret", secret.Name, "err", err)
+		if revokeErr := impl.RevokeSecretToken(ctx, clusterConfig, target.Namespace, secret.Name); revokeErr != nil {
+			impl.logger.Errorw("error in deleting service account token secret", "secret", secret.Name, "err", revokeErr)
+		}
+		return nil, err
+	}
+	return &bean.IssuedToken{Token: token, SecretName: secret.Name}, nil
+}
+
+func (impl *ServiceAccountTokenRotatorImpl) VerifyToken(ctx context.Context, clusterConfig *k8s.ClusterConfig) error {
+	_, _, clientSet, err := impl.k8sUtil.GetK8sConfigAndClients(clusterConfig)
+	if err != nil {
+		return err
+	}
+	_, err = clientSet.CoreV1().Namespaces().List(ctx, metaV1.ListOptions{Limit: 1})
+	return err
+}
+
+func (impl *ServiceAccountTokenRotatorImpl) RevokeSecretToken(ctx context.Context, clusterConfig *k8s.ClusterConfig, namespace, secretName string) error {
+	_, _, clientSet, err := impl.k8sUtil.GetK8sConfigAndClients(clusterConfig)
+	if err != nil {
+		return err
+	}
+	err = clientSet.CoreV1().Secrets(namespace).Delete(ctx, secretName, metaV1.DeleteOptions{})
+	if err != nil && !k8sError.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"github.com/devtron-labs/devtron/pkg/cluster/credential/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/credential/repository"
+)
+
+func GetRotationAuditDto(audit *repository.ClusterCredentialRotationAudit) *bean.RotationAuditDto {
+	return &bean.RotationAuditDto{
+		Id:                      audit.Id,
+		ClusterId:               audit.ClusterId,
+		ServiceAccountNamespace: audit.ServiceAccountNamespace,
+		ServiceAccountName:      audit.ServiceAccountName,
+		Status:                  bean.RotationStatus(audit.Status),
+		Message:                 audit.Message,
+		PreviousExpiresAt:       audit.PreviousExpiresAt,
+		NewExpiresAt:            audit.NewExpiresAt,
+		CreatedBy:               audit.CreatedBy,
+		CreatedOn:               audit.CreatedOn,
+	}
+}
+
+// UpdateCredentialExpiry copies the parsed expiries of dto to the recorded expiry of the cluster
+func UpdateCredentialExpiry(expiry *repository.ClusterCredentialExpiry, dto *bean.ClusterCredentialExpiryDto) {
+	expiry.ClusterId = dto.ClusterId
+	expiry.TokenExpiresAt = dto.TokenExpiresAt
+	expiry.ClientCertExpiresAt = dto.ClientCertExpiresAt
+	expiry.CaCertExpiresAt = dto.CaCertExpiresAt
+	expiry.ParseError = dto.ParseError
+	expiry.Active = true
+}
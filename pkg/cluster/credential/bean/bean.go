@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bean
+
+import (
+	"time"
+)
+
+type CredentialType string
+
+const (
+	CredentialTypeBearerToken       CredentialType = "BearerToken"
+	CredentialTypeClientCertificate CredentialType = "ClientCertificate"
+	CredentialTypeCaCertificate     CredentialType = "CaCertificate"
+)
+
+type ExpiryStatus string
+
+const (
+	ExpiryStatusValid        ExpiryStatus = "Valid"
+	ExpiryStatusExpiringSoon ExpiryStatus = "ExpiringSoon"
+	ExpiryStatusExpired      ExpiryStatus = "Expired"
+	// ExpiryStatusUnknown is set when none of the credentials carry an expiry, like opaque tokens and legacy service account tokens
+	ExpiryStatusUnknown ExpiryStatus = "Unknown"
+)
+
+type ClusterCredentialExpiryDto struct {
+	ClusterId           int        `json:"clusterId"`
+	ClusterName         string     `json:"clusterName"`
+	TokenExpiresAt      *time.Time `json:"tokenExpiresAt,omitempty"`
+	ClientCertExpiresAt *time.Time `json:"clientCertExpiresAt,omitempty"`
+	CaCertExpiresAt     *time.Time `json:"caCertExpiresAt,omitempty"`
+	// ExpiresAt is the earliest expiry among the credentials, ExpiringCredential is the credential it belongs to
+	ExpiresAt          *time.Time     `json:"expiresAt,omitempty"`
+	ExpiringCredential CredentialType `json:"expiringCredential,omitempty"`
+	Status             ExpiryStatus   `json:"status"`
+	ParseError         string         `json:"parseError,omitempty"`
+	LastNotifiedOn     *time.Time     `json:"lastNotifiedOn,omitempty"`
+}
+
+// SetExpiryStatus sets the earliest expiry of the credentials and its status, credentials expiring before warnBefore are expiring soon
+func (dto *ClusterCredentialExpiryDto) SetExpiryStatus(now, warnBefore time.Time) {
+	dto.ExpiresAt, dto.ExpiringCredential = nil, ""
+	credentialTypes := []CredentialType{CredentialTypeBearerToken, CredentialTypeClientCertificate, CredentialTypeCaCertificate}
+	for i, expiresAt := range []*time.Time{dto.TokenExpiresAt, dto.ClientCertExpiresAt, dto.CaCertExpiresAt} {
+		if expiresAt == nil {
+			continue
+		}
+		if dto.ExpiresAt == nil || expiresAt.Before(*dto.ExpiresAt) {
+			dto.ExpiresAt, dto.ExpiringCredential = expiresAt, credentialTypes[i]
+		}
+	}
+	switch {
+	case dto.ExpiresAt == nil:
+		dto.Status = ExpiryStatusUnknown
+	case !dto.ExpiresAt.After(now):
+		dto.Status = ExpiryStatusExpired
+	case dto.ExpiresAt.Before(warnBefore):
+		dto.Status = ExpiryStatusExpiringSoon
+	default:
+		dto.Status = ExpiryStatusValid
+	}
+}
+
+func (dto *ClusterCredentialExpiryDto) IsExpiring() bool {
+	return dto.Status == ExpiryStatusExpiringSoon || dto.Status == ExpiryStatusExpired
+}
+
+type RotateCredentialRequest struct {
+	ClusterId int `json:"clusterId"`
+	// ServiceAccountNamespace and ServiceAccountName default to the service account of the current token
+	ServiceAccountNamespace string `json:"serviceAccountNamespace,omitempty"`
+	ServiceAccountName      string `json:"serviceAccountName,omitempty"`
+	// TokenExpirySecs overrides the expiry of the issued token, a token request is used when set
+	TokenExpirySecs int64 `json:"tokenExpirySecs,omitempty"`
+	UserId          int32 `json:"-"`
+}
+
+// RotationTarget is the service account whose token is re-issued, ExpirySecs 0 issues a non expiring service account secret token
+type RotationTarget struct {
+	Namespace  string
+	Name       string
+	ExpirySecs int64
+}
+
+type IssuedToken struct {
+	Token     string
+	ExpiresAt *time.Time
+	// SecretName is set for tokens issued through a service account token secret, the secret is deleted to revoke the token
+	SecretName string
+}
+
+type RotationStatus string
+
+const (
+	RotationStatusSucceeded RotationStatus = "Succeeded"
+	RotationStatusFailed    RotationStatus = "Failed"
+	// RotationStatusRolledBack is set when the new token was issued but saving it failed and the previous config was restored
+	RotationStatusRolledBack RotationStatus = "RolledBack"
+)
+
+type RotationAuditDto struct {
+	Id                      int            `json:"id"`
+	ClusterId               int            `json:"clusterId"`
+	ServiceAccountNamespace string         `json:"serviceAccountNamespace,omitempty"`
+	ServiceAccountName      string         `json:"serviceAccountName,omitempty"`
+	Status                  RotationStatus `json:"status"`
+	Message                 string         `json:"message,omitempty"`
+	PreviousExpiresAt       *time.Time     `json:"previousExpiresAt,omitempty"`
+	NewExpiresAt            *time.Time     `json:"newExpiresAt,omitempty"`
+	CreatedBy               int32          `json:"createdBy"`
+	CreatedOn               time.Time      `json:"createdOn"`
+}
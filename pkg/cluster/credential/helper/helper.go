@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package helper
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const serviceAccountSubjectPrefix = "system:serviceaccount:"
+
+// TokenClaims are the claims of a kubernetes service account jwt, both bound (TokenRequest) and legacy secret tokens
+type TokenClaims struct {
+	ExpiresAt               *time.Time
+	IssuedAt                *time.Time
+	ServiceAccountNamespace string
+	ServiceAccountName      string
+	// SecretName is set for legacy tokens read from a service account token secret
+	SecretName string
+}
+
+type jwtClaims struct {
+	Exp        *int64 `json:"exp"`
+	Iat        *int64 `json:"iat"`
+	Sub        string `json:"sub"`
+	Kubernetes *struct {
+		Namespace      string `json:"namespace"`
+		ServiceAccount *struct {
+			Name string `json:"name"`
+		} `json:"serviceaccount"`
+	} `json:"kubernetes.io"`
+	LegacyNamespace  string `json:"kubernetes.io/serviceaccount/namespace"`
+	LegacyName       string `json:"kubernetes.io/serviceaccount/service-account.name"`
+	LegacySecretName string `json:"kubernetes.io/serviceaccount/secret.name"`
+}
+
+// ParseTokenClaims reads the claims of a jwt bearer token without verifying its signature, nil is returned for
+// tokens which are not jwt, like static tokens
+func ParseTokenClaims(token string) (*TokenClaims, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bearer token payload: %w", err)
+	}
+	claims := &jwtClaims{}
+	if err = json.Unmarshal(payload, claims); err != nil {
+		return nil, fmt.Errorf("invalid bearer token claims: %w", err)
+	}
+	tokenClaims := &TokenClaims{
+		ExpiresAt:               unixTime(claims.Exp),
+		IssuedAt:                unixTime(claims.Iat),
+		ServiceAccountNamespace: claims.LegacyNamespace,
+		ServiceAccountName:      claims.LegacyName,
+		SecretName:              claims.LegacySecretName,
+	}
+	if claims.Kubernetes != nil && claims.Kubernetes.ServiceAccount != nil {
+		tokenClaims.ServiceAccountNamespace = claims.Kubernetes.Namespace
+		tokenClaims.ServiceAccountName = claims.Kubernetes.ServiceAccount.Name
+	}
+	if len(tokenClaims.ServiceAccountName) == 0 && strings.HasPrefix(claims.Sub, serviceAccountSubjectPrefix) {
+		// sub is system:serviceaccount:<namespace>:<name>
+		subject := strings.SplitN(strings.TrimPrefix(claims.Sub, serviceAccountSubjectPrefix), ":", 2)
+		if len(subject) == 2 {
+			tokenClaims.ServiceAccountNamespace, tokenClaims.ServiceAccountName = subject[0], subject[1]
+		}
+	}
+	return tokenClaims, nil
+}
+
+// GetLifetime returns the duration the token was issued for, 0 when the token does not expire
+func (claims *TokenClaims) GetLifetime() time.Duration {
+	if claims.ExpiresAt == nil || claims.IssuedAt == nil {
+		return 0
+	}
+	return claims.ExpiresAt.Sub(*claims.IssuedAt)
+}
+
+// ParseCertificateExpiry returns the earliest expiry among the certificates of pem data, base64 encoded pem is
+// accepted as well. nil is returned when there is no certificate.
+func ParseCertificateExpiry(data string) (*time.Time, error) {
+	data = strings.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	pemData := []byte(data)
+	if !strings.HasPrefix(data, "-----BEGIN") {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, fmt.Errorf("certificate is neither pem nor base64 encoded pem")
+		}
+		pemData = decoded
+	}
+	var expiresAt *time.Time
+	for {
+		var block *pem.Block
+		block, pemData = pem.Decode(pemData)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %w", err)
+		}
+		if expiresAt == nil || certificate.NotAfter.Before(*expiresAt) {
+			notAfter := certificate.NotAfter
+			expiresAt = &notAfter
+		}
+	}
+	return expiresAt, nil
+}
+
+func unixTime(secs *int64) *time.Time {
+	if secs == nil {
+		return nil
+	}
+	t := time.Unix(*secs, 0)
+	return &t
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package helper
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildToken(claims string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"RS256"}`)) + "." + encode([]byte(claims)) + ".signature"
+}
+
+func buildCertificate(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestParseTokenClaims(t *testing.T) {
+	t.Run("bound service account token", func(t *testing.T) {
+		claims, err := ParseTokenClaims(buildToken(`{"exp":1700003600,"iat":1700000000,"sub":"system:serviceaccount:kube-system:devtron",
+			"kubernetes.io":{"namespace":"kube-system","serviceaccount":{"name":"devtron"}}}`))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1700003600), claims.ExpiresAt.Unix())
+		assert.Equal(t, time.Hour, claims.GetLifetime())
+		assert.Equal(t, "kube-system", claims.ServiceAccountNamespace)
+		assert.Equal(t, "devtron", claims.ServiceAccountName)
+		assert.Empty(t, claims.SecretName)
+	})
+	t.Run("legacy secret token", func(t *testing.T) {
+		claims, err := ParseTokenClaims(buildToken(`{"sub":"system:serviceaccount:devtroncd:devtron",
+			"kubernetes.io/serviceaccount/namespace":"devtroncd","kubernetes.io/serviceaccount/service-account.name":"devtron",
+			"kubernetes.io/serviceaccount/secret.name":"devtron-token-abcde"}`))
+		assert.Nil(t, err)
+		assert.Nil(t, claims.ExpiresAt)
+		assert.Equal(t, time.Duration(0), claims.GetLifetime())
+		assert.Equal(t, "devtroncd", claims.ServiceAccountNamespace)
+		assert.Equal(t, "devtron", claims.ServiceAccountName)
+		assert.Equal(t, "devtron-token-abcde", claims.SecretName)
+	})
+	t.Run("service account from subject", func(t *testing.T) {
+		claims, err := ParseTokenClaims(buildToken(`{"exp":1700003600,"sub":"system:serviceaccount:ns:sa"}`))
+		assert.Nil(t, err)
+		assert.Equal(t, "ns", claims.ServiceAccountNamespace)
+		assert.Equal(t, "sa", claims.ServiceAccountName)
+	})
+	t.Run("opaque token", func(t *testing.T) {
+		claims, err := ParseTokenClaims("static-token")
+		assert.Nil(t, err)
+		assert.Nil(t, claims)
+	})
+	t.Run("invalid payload", func(t *testing.T) {
+		_, err := ParseTokenClaims("a.%%%.c")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestParseCertificateExpiry(t *testing.T) {
+	earliest := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+	bundle := append(buildCertificate(t, earliest.Add(48*time.Hour)), buildCertificate(t, earliest)...)
+
+	expiresAt, err := ParseCertificateExpiry(string(bundle))
+	assert.Nil(t, err)
+	assert.True(t, earliest.Equal(*expiresAt))
+
+	expiresAt, err = ParseCertificateExpiry(base64.StdEncoding.EncodeToString(bundle))
+	assert.Nil(t, err)
+	assert.True(t, earliest.Equal(*expiresAt))
+
+	expiresAt, err = ParseCertificateExpiry("")
+	assert.Nil(t, err)
+	assert.Nil(t, expiresAt)
+
+	_, err = ParseCertificateExpiry("not a certificate")
+	assert.NotNil(t, err)
+}
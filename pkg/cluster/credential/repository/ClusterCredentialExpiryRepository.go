@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ClusterCredentialExpiry is the expiry of the credentials stored in the config of a cluster, recorded by the expiry check
+type ClusterCredentialExpiry struct {
+	tableName           struct{}   `sql:"cluster_credential_expiry" pg:",discard_unknown_columns"`
+	Id                  int        `sql:"id,pk"`
+	ClusterId           int        `sql:"cluster_id,notnull"`
+	TokenExpiresAt      *time.Time `sql:"token_expires_at"`
+	ClientCertExpiresAt *time.Time `sql:"client_cert_expires_at"`
+	CaCertExpiresAt     *time.Time `sql:"ca_cert_expires_at"`
+	ParseError          string     `sql:"parse_error"`
+	LastCheckedOn       time.Time  `sql:"last_checked_on"`
+	LastNotifiedOn      *time.Time `sql:"last_notified_on"`
+	Active              bool       `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ClusterCredentialExpiryRepository interface {
+	Save(expiry *ClusterCredentialExpiry) error
+	Update(expiry *ClusterCredentialExpiry) error
+	FindByClusterId(clusterId int) (*ClusterCredentialExpiry, error)
+	FindAllActive() ([]*ClusterCredentialExpiry, error)
+}
+
+type ClusterCredentialExpiryRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewClusterCredentialExpiryRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ClusterCredentialExpiryRepositoryImpl {
+	return &ClusterCredentialExpiryRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ClusterCredentialExpiryRepositoryImpl) Save(expiry *ClusterCredentialExpiry) error {
+	return impl.dbConnection.Insert(expiry)
+}
+
+func (impl *ClusterCredentialExpiryRepositoryImpl) Update(expiry *ClusterCredentialExpiry) error {
+	return impl.dbConnection.Update(expiry)
+}
+
+func (impl *ClusterCredentialExpiryRepositoryImpl) FindByClusterId(clusterId int) (*ClusterCredentialExpiry, error) {
+	expiry := &ClusterCredentialExpiry{}
+	err := impl.dbConnection.Model(expiry).
+		Where("cluster_id = ?", clusterId).
+		Where("active = ?", true).
+		Select()
+	return expiry, err
+}
+
+func (impl *ClusterCredentialExpiryRepositoryImpl) FindAllActive() ([]*ClusterCredentialExpiry, error) {
+	var expiries []*ClusterCredentialExpiry
+	err := impl.dbConnection.Model(&expiries).
+		Where("active = ?", true).
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting cluster credential expiries", "err", err)
+		return nil, err
+	}
+	return expiries, nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ClusterCredentialRotationAudit records every service account token rotation attempt of a cluster
+type ClusterCredentialRotationAudit struct {
+	tableName               struct{}   `sql:"cluster_credential_rotation_audit" pg:",discard_unknown_columns"`
+	Id                      int        `sql:"id,pk"`
+	ClusterId               int        `sql:"cluster_id,notnull"`
+	ServiceAccountNamespace string     `sql:"service_account_namespace"`
+	ServiceAccountName      string     `sql:"service_account_name"`
+	Status                  string     `sql:"status,notnull"`
+	Message                 string     `sql:"message"`
+	PreviousExpiresAt       *time.Time `sql:"previous_expires_at"`
+	NewExpiresAt            *time.Time `sql:"new_expires_at"`
+	sql.AuditLog
+}
+
+type ClusterCredentialRotationAuditRepository interface {
+	Save(audit *ClusterCredentialRotationAudit) error
+	// FindByClusterId returns the latest rotation audits of the cluster first
+	FindByClusterId(clusterId int) ([]*ClusterCredentialRotationAudit, error)
+}
+
+type ClusterCredentialRotationAuditRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewClusterCredentialRotationAuditRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ClusterCredentialRotationAuditRepositoryImpl {
+	return &ClusterCredentialRotationAuditRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ClusterCredentialRotationAuditRepositoryImpl) Save(audit *ClusterCredentialRotationAudit) error {
+	return impl.dbConnection.Insert(audit)
+}
+
+func (impl *ClusterCredentialRotationAuditRepositoryImpl) FindByClusterId(clusterId int) ([]*ClusterCredentialRotationAudit, error) {
+	var audits []*ClusterCredentialRotationAudit
+	err := impl.dbConnection.Model(&audits).
+		Where("cluster_id = ?", clusterId).
+		Order("id DESC").
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting cluster credential rotation audits", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	return audits, nil
+}
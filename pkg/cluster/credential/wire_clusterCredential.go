@@ -0,0 +1,34 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package credential
+
+import (
+	"github.com/devtron-labs/devtron/pkg/cluster/credential/repository"
+	"github.com/google/wire"
+)
+
+// ClusterCredentialWireSet needs a CredentialExpiryNotifier binding, notifications are not available in every mode
+var ClusterCredentialWireSet = wire.NewSet(
+	GetClusterCredentialConfig,
+	repository.NewClusterCredentialExpiryRepositoryImpl,
+	wire.Bind(new(repository.ClusterCredentialExpiryRepository), new(*repository.ClusterCredentialExpiryRepositoryImpl)),
+	repository.NewClusterCredentialRotationAuditRepositoryImpl,
+	wire.Bind(new(repository.ClusterCredentialRotationAuditRepository), new(*repository.ClusterCredentialRotationAuditRepositoryImpl)),
+	NewServiceAccountTokenRotatorImpl,
+	wire.Bind(new(ServiceAccountTokenRotator), new(*ServiceAccountTokenRotatorImpl)),
+	NewClusterCredentialServiceImpl,
+	wire.Bind(new(ClusterCredentialService), new(*ClusterCredentialServiceImpl)),
+)
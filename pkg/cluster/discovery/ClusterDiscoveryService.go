@@ -0,0 +1,446 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/adapter"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/provider"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cronUtil "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type ClusterDiscoveryConfig struct {
+	ServiceAccountNamespace  string `env:"CLOUD_CLUSTER_SERVICE_ACCOUNT_NAMESPACE" envDefault:"kube-system" description:"Namespace of the service account created in clusters imported from cloud credentials"`
+	ServiceAccountName       string `env:"CLOUD_CLUSTER_SERVICE_ACCOUNT_NAME" envDefault:"devtron-cluster-manager" description:"Name of the service account and cluster role binding created in clusters imported from cloud credentials"`
+	ClusterRoleName          string `env:"CLOUD_CLUSTER_SERVICE_ACCOUNT_CLUSTER_ROLE" envDefault:"cluster-admin" description:"Cluster role bound to the service account of imported clusters, used to scope the access of devtron"`
+	TokenExpirySecs          int64  `env:"CLOUD_CLUSTER_TOKEN_EXPIRY_SECS" envDefault:"86400" description:"Expiry of the service account tokens issued for imported clusters"`
+	TokenRefreshIntervalMins int    `env:"CLOUD_CLUSTER_TOKEN_REFRESH_INTERVAL_MINS" envDefault:"30" description:"Interval at which service account tokens of imported clusters past half of their expiry are re-issued, 0 disables the refresh"`
+}
+
+func GetClusterDiscoveryConfig() (*ClusterDiscoveryConfig, error) {
+	cfg := &ClusterDiscoveryConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+type ClusterDiscoveryService interface {
+	SaveCloudCredential(request *bean.CloudCredentialDto) (*bean.CloudCredentialDto, error)
+	UpdateCloudCredential(request *bean.CloudCredentialDto) (*bean.CloudCredentialDto, error)
+	GetCloudCredential(id int) (*bean.CloudCredentialDto, error)
+	GetAllCloudCredentials() ([]*bean.CloudCredentialDto, error)
+	// DeleteCloudCredential is not allowed while clusters imported with the credential are active, as their
+	// tokens are refreshed through it
+	DeleteCloudCredential(id int, userId int32) error
+
+	// DiscoverClusters lists the clusters of the cloud credential, clusters already added in devtron are marked imported
+	DiscoverClusters(ctx context.Context, cloudCredentialId int) ([]*bean.DiscoveredCluster, error)
+	// ImportClusters creates a service account in each of the selected clusters and saves the cluster with its token
+	ImportClusters(ctx context.Context, request *bean.ImportClustersRequest) ([]*bean.ImportClusterResult, error)
+	// RefreshClusterTokens re-issues the tokens of imported clusters past half of their expiry
+	RefreshClusterTokens()
+}
+
+type ClusterDiscoveryServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	cloudCredentialRepository    repository.CloudCredentialRepository
+	clusterCloudSourceRepository repository.ClusterCloudSourceRepository
+	clusterService               cluster.ClusterService
+	providers                    provider.Providers
+	tokenIssuer                  ServiceAccountTokenIssuer
+	config                       *ClusterDiscoveryConfig
+}
+
+func NewClusterDiscoveryServiceImpl(logger *zap.SugaredLogger,
+	cloudCredentialRepository repository.CloudCredentialRepository,
+	clusterCloudSourceRepository repository.ClusterCloudSourceRepository,
+	clusterService cluster.ClusterService,
+	providers provider.Providers,
+	tokenIssuer ServiceAccountTokenIssuer,
+	config *ClusterDiscoveryConfig,
+	cronLogger *cronUtil.CronLoggerImpl) (*ClusterDiscoveryServiceImpl, error) {
+	impl := &ClusterDiscoveryServiceImpl{
+		logger:                       logger,
+		cloudCredentialRepository:    cloudCredentialRepository,
+		clusterCloudSourceRepository: clusterCloudSourceRepository,
+		clusterService:               clusterService,
+		providers:                    providers,
+		tokenIssuer:                  tokenIssuer,
+		config:                       config,
+	}
+	if config.TokenRefreshIntervalMins > 0 {
+		refreshCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+		_, err := refreshCron.AddFunc(fmt.Sprintf("@every %dm", config.TokenRefreshIntervalMins), impl.RefreshClusterTokens)
+		if err != nil {
+			logger.Errorw("error in adding cloud cluster token refresh cron", "err", err)
+			return nil, err
+		}
+		refreshCron.Start()
+	}
+	return impl, nil
+}
+
+func (impl *ClusterDiscoveryServiceImpl) SaveCloudCredential(request *bean.CloudCredentialDto) (*bean.CloudCredentialDto, error) {
+	err := request.ValidateProviderCredential()
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	if request.IsSecretEmpty() {
+		return nil, util.NewApiError(http.StatusBadRequest, "credential secret is required", "credential secret is required")
+	}
+	existing, err := impl.cloudCredentialRepository.FindByName(request.Name)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting cloud credential by name", "name", request.Name, "err", err)
+		return nil, err
+	} else if existing.Id > 0 {
+		return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("cloud credential %s already exists", request.Name), "cloud credential already exists")
+	}
+	model, err := adapter.BuildCloudCredential(request)
+	if err != nil {
+		return nil, err
+	}
+	model.Id = 0
+	err = impl.cloudCredentialRepository.Save(model)
+	if err != nil {
+		impl.logger.Errorw("error in saving cloud credential", "name", request.Name, "err", err)
+		return nil, err
+	}
+	request.Id = model.Id
+	return request.HideSecrets(), nil
+}
+
+func (impl *ClusterDiscoveryServiceImpl) UpdateCloudCredential(request *bean.CloudCredentialDto) (*bean.CloudCredentialDto, error) {
+	err := request.ValidateProviderCredential()
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	saved, err := impl.getCloudCredential(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if saved.Provider != request.Provider {
+		return nil, util.NewApiError(http.StatusBadRequest, "provider of a cloud credential can not be changed", "provider of a cloud credential can not be changed")
+	}
+	existing, err := impl.cloudCredentialRepository.FindByName(request.Name)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting cloud credential by name", "name", request.Name, "err", err)
+		return nil, err
+	} else if existing.Id > 0 && existing.Id != request.Id {
+		return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("cloud credential %s already exists", request.Name), "cloud credential already exists")
+	}
+	if request.IsSecretEmpty() {
+		request.CopySecretFrom(saved)
+	}
+	model, err := adapter.BuildCloudCredential(request)
+	if err != nil {
+		return nil, err
+	}
+	savedModel, err := impl.cloudCredentialRepository.FindById(request.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting cloud credential", "id", request.Id, "err", err)
+		return nil, err
+	}
+	model.CreatedOn, model.CreatedBy = savedModel.CreatedOn, savedModel.CreatedBy
+	err = impl.cloudCredentialRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in updating cloud credential", "id", request.Id, "err", err)
+		return nil, err
+	}
+	return request.HideSecrets(), nil
+}
+
+func (impl *ClusterDiscoveryServiceImpl) GetCloudCredential(id int) (*bean.CloudCredentialDto, error) {
+	credential, err := impl.getCloudCredential(id)
+	if err != nil {
+		return nil, err
+	}
+	return credential.HideSecrets(), nil
+}
+
+func (impl *ClusterDiscoveryServiceImpl) GetAllCloudCredentials() ([]*bean.CloudCredentialDto, error) {
+	models, err := impl.cloudCredentialRepository.FindAllActive()
+	if err != nil {
+		return nil, err
+	}
+	credentials := make([]*bean.CloudCredentialDto, 0, len(models))
+	for _, model := range models {
+		credential, err := adapter.GetCloudCredentialDto(model)
+		if err != nil {
+			impl.logger.Errorw("error in parsing cloud credential", "id", model.Id, "err", err)
+			return nil, err
+		}
+		credentials = append(credentials, credential.HideSecrets())
+	}
+	return credentials, nil
+}
+
+func (impl *ClusterDiscoveryServiceImpl) DeleteCloudCredential(id int, userId int32) error {
+	model, err := impl.cloudCredentialRepository.FindById(id)
+	if err == pg.ErrNoRows {
+		return util.NewApiError(http.StatusNotFound, "cloud credential not found", "cloud credential not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in getting cloud credential", "id", id, "err", err)
+		return err
+	}
+	sources, err := impl.clusterCloudSourceRepository.FindByCloudCredentialId(id)
+	if err != nil {
+		return err
+	}
+	if len(sources) > 0 {
+		return util.NewApiError(http.StatusPreconditionFailed,
+			fmt.Sprintf("cloud credential is used by %d imported clusters, delete the clusters first", len(sources)),
+			"cloud credential is used by imported clusters")
+	}
+	model.Active = false
+	model.UpdatedOn = time.Now()
+	model.UpdatedBy = userId
+	return impl.cloudCredentialRepository.Update(model)
+}
+
+func (impl *ClusterDiscoveryServiceImpl) DiscoverClusters(ctx context.Context, cloudCredentialId int) ([]*bean.DiscoveredCluster, error) {
+	credential, err := impl.getCloudCredential(cloudCredentialId)
+	if err != nil {
+		return nil, err
+	}
+	clusterProvider, err := impl.providers.Get(credential.Provider)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	discoveredClusters, err := clusterProvider.ListClusters(ctx, credential)
+	if err != nil {
+		impl.logger.Errorw("error in listing clusters of cloud credential", "cloudCredentialId", cloudCredentialId, "err", err)
+		return nil, util.NewApiError(http.StatusBadGateway, fmt.Sprintf("failed to list clusters from %s: %s", credential.Provider, err.Error()), err.Error())
+	}
+	existingClusters, err := impl.clusterService.FindAllWithoutConfig()
+	if err != nil {
+		impl.logger.Errorw("error in getting clusters", "err", err)
+		return nil, err
+	}
+	clustersByServerUrl := make(map[string]*clusterBean.ClusterBean, len(existingClusters))
+	for _, existingCluster := range existingClusters {
+		clustersByServerUrl[normalizeServerUrl(existingCluster.ServerUrl)] = existingCluster
+	}
+	for _, discoveredCluster := range discoveredClusters {
+		if existingCluster, ok := clustersByServerUrl[normalizeServerUrl(discoveredCluster.ServerUrl)]; ok && len(discoveredCluster.ServerUrl) > 0 {
+			discoveredCluster.ImportedClusterId = existingCluster.Id
+			discoveredCluster.ImportedClusterName = existingCluster.ClusterName
+		}
+	}
+	return discoveredClusters, nil
+}
+
+func (impl *ClusterDiscoveryServiceImpl) ImportClusters(ctx context.Context, request *bean.ImportClustersRequest) ([]*bean.ImportClusterResult, error) {
+	discoveredClusters, err := impl.DiscoverClusters(ctx, request.CloudCredentialId)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := impl.getCloudCredential(request.CloudCredentialId)
+	if err != nil {
+		return nil, err
+	}
+	clusterProvider, err := impl.providers.Get(credential.Provider)
+	if err != nil {
+		return nil, err
+	}
+	discoveredClustersById := make(map[string]*bean.DiscoveredCluster, len(discoveredClusters))
+	for _, discoveredCluster := range discoveredClusters {
+		discoveredClustersById[discoveredCluster.ProviderClusterId] = discoveredCluster
+	}
+	results := make([]*bean.ImportClusterResult, 0, len(request.Clusters))
+	for _, entry := range request.Clusters {
+		result := &bean.ImportClusterResult{ProviderClusterId: entry.ProviderClusterId, ClusterName: entry.ClusterName}
+		results = append(results, result)
+		discoveredCluster, ok := discoveredClustersById[entry.ProviderClusterId]
+		if !ok {
+			result.Status, result.Message = bean.ImportStatusFailed, "cluster not found in the cloud account"
+			continue
+		}
+		if len(result.ClusterName) == 0 {
+			result.ClusterName = getClusterName(discoveredCluster.Name)
+		}
+		if discoveredCluster.ImportedClusterId > 0 {
+			result.ClusterId = discoveredCluster.ImportedClusterId
+			result.Status, result.Message = bean.ImportStatusSkipped, fmt.Sprintf("already added as cluster %s", discoveredCluster.ImportedClusterName)
+			continue
+		}
+		clusterId, err := impl.importCluster(ctx, clusterProvider, credential, entry, result.ClusterName, request.UserId)
+		result.ClusterId = clusterId
+		if err != nil {
+			impl.logger.Errorw("error in importing cluster", "providerClusterId", entry.ProviderClusterId, "err", err)
+			result.Status, result.Message = bean.ImportStatusFailed, util.GetClientErrorDetailedMessage(err)
+			continue
+		}
+		result.Status = bean.ImportStatusImported
+	}
+	return results, nil
+}
+
+func (impl *ClusterDiscoveryServiceImpl) importCluster(ctx context.Context, clusterProvider provider.ClusterDiscoveryProvider,
+	credential *bean.CloudCredentialDto, entry *bean.ImportClusterEntry, clusterName string, userId int32) (int, error) {
+	access, err := clusterProvider.GetClusterAccess(ctx, credential, entry.ProviderClusterId)
+	if err != nil {
+		return 0, err
+	}
+	token, expiresAt, err := impl.tokenIssuer.IssueToken(ctx, getClusterConfig(clusterName, access))
+	if err != nil {
+		return 0, err
+	}
+	clusterRequest := &clusterBean.ClusterBean{
+		ClusterName: clusterName,
+		ServerUrl:   access.ServerUrl,
+		Active:      true,
+		IsProd:      entry.IsProd,
+		Config: map[string]string{
+			commonBean.BearerToken:              token,
+			commonBean.CertificateAuthorityData: access.CaData,
+		},
+	}
+	savedCluster, err := impl.clusterService.Save(ctx, clusterRequest, userId)
+	if err != nil {
+		return 0, err
+	}
+	source := &repository.ClusterCloudSource{
+		ClusterId:               savedCluster.Id,
+		CloudCredentialId:       credential.Id,
+		ProviderClusterId:       entry.ProviderClusterId,
+		ServiceAccountNamespace: impl.config.ServiceAccountNamespace,
+		ServiceAccountName:      impl.config.ServiceAccountName,
+		TokenExpiresAt:          expiresAt,
+		LastRefreshedOn:         time.Now(),
+		Active:                  true,
+	}
+	source.AuditLog = sql.NewDefaultAuditLog(userId)
+	err = impl.clusterCloudSourceRepository.Save(source)
+	if err != nil {
+		// the cluster is saved, only its token will not be refreshed
+		impl.logger.Errorw("error in saving cluster cloud source", "clusterId", savedCluster.Id, "err", err)
+		return savedCluster.Id, err
+	}
+	return savedCluster.Id, nil
+}
+
+func (impl *ClusterDiscoveryServiceImpl) RefreshClusterTokens() {
+	refreshBefore := time.Now().Add(time.Duration(impl.config.TokenExpirySecs/2) * time.Second)
+	sources, err := impl.clusterCloudSourceRepository.FindAllExpiringBefore(refreshBefore)
+	if err != nil {
+		return
+	}
+	for _, source := range sources {
+		err = impl.refreshClusterToken(source)
+		source.LastRefreshedOn = time.Now()
+		source.RefreshError = ""
+		if err != nil {
+			impl.logger.Errorw("error in refreshing token of imported cluster", "clusterId", source.ClusterId, "err", err)
+			source.RefreshError = err.Error()
+		}
+		source.UpdatedOn = time.Now()
+		source.UpdatedBy = userBean.SystemUserId
+		if err = impl.clusterCloudSourceRepository.Update(source); err != nil {
+			impl.logger.Errorw("error in updating cluster cloud source", "clusterId", source.ClusterId, "err", err)
+		}
+	}
+}
+
+func (impl *ClusterDiscoveryServiceImpl) refreshClusterToken(source *repository.ClusterCloudSource) error {
+	ctx := context.Background()
+	credential, err := impl.getCloudCredential(source.CloudCredentialId)
+	if err != nil {
+		return err
+	}
+	clusterProvider, err := impl.providers.Get(credential.Provider)
+	if err != nil {
+		return err
+	}
+	access, err := clusterProvider.GetClusterAccess(ctx, credential, source.ProviderClusterId)
+	if err != nil {
+		return err
+	}
+	existingCluster, err := impl.clusterService.FindById(source.ClusterId)
+	if err != nil {
+		return err
+	}
+	token, expiresAt, err := impl.tokenIssuer.IssueToken(ctx, getClusterConfig(existingCluster.ClusterName, access))
+	if err != nil {
+		return err
+	}
+	if existingCluster.Config == nil {
+		existingCluster.Config = make(map[string]string)
+	}
+	existingCluster.ServerUrl = access.ServerUrl
+	existingCluster.Config[commonBean.BearerToken] = token
+	existingCluster.Config[commonBean.CertificateAuthorityData] = access.CaData
+	_, err = impl.clusterService.Update(ctx, existingCluster, userBean.SystemUserId)
+	if err != nil {
+		return err
+	}
+	source.TokenExpiresAt = expiresAt
+	return nil
+}
+
+func (impl *ClusterDiscoveryServiceImpl) getCloudCredential(id int) (*bean.CloudCredentialDto, error) {
+	model, err := impl.cloudCredentialRepository.FindById(id)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "cloud credential not found", "cloud credential not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in getting cloud credential", "id", id, "err", err)
+		return nil, err
+	}
+	credential, err := adapter.GetCloudCredentialDto(model)
+	if err != nil {
+		impl.logger.Errorw("error in parsing cloud credential", "id", id, "err", err)
+		return nil, err
+	}
+	return credential, nil
+}
+
+func getClusterConfig(clusterName string, access *bean.ClusterAccess) *k8s.ClusterConfig {
+	return &k8s.ClusterConfig{
+		ClusterName: clusterName,
+		Host:        access.ServerUrl,
+		BearerToken: access.BearerToken,
+		CAData:      access.CaData,
+		CertData:    access.CertData,
+		KeyData:     access.KeyData,
+	}
+}
+
+var clusterNameInvalidCharsRegex = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// getClusterName converts the cloud provider cluster name to a devtron cluster name, lower case alphanumerics and '-'
+func getClusterName(name string) string {
+	return strings.Trim(clusterNameInvalidCharsRegex.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func normalizeServerUrl(serverUrl string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(strings.ToLower(serverUrl), "/"), ":443")
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/adapter"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/provider"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/repository"
+	"github.com/go-pg/pg"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClusterProvider struct {
+	clusters  []*bean.DiscoveredCluster
+	accessErr error
+}
+
+func (f *fakeClusterProvider) ListClusters(ctx context.Context, credential *bean.CloudCredentialDto) ([]*bean.DiscoveredCluster, error) {
+	return f.clusters, nil
+}
+
+func (f *fakeClusterProvider) GetClusterAccess(ctx context.Context, credential *bean.CloudCredentialDto, providerClusterId string) (*bean.ClusterAccess, error) {
+	if f.accessErr != nil {
+		return nil, f.accessErr
+	}
+	for _, c := range f.clusters {
+		if c.ProviderClusterId == providerClusterId {
+			return &bean.ClusterAccess{ServerUrl: c.ServerUrl, CaData: "ca-" + c.Name, BearerToken: "cloud-token", ExpiresAt: time.Now().Add(time.Hour)}, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+type fakeTokenIssuer struct {
+	issued []*k8s.ClusterConfig
+}
+
+func (f *fakeTokenIssuer) IssueToken(ctx context.Context, clusterConfig *k8s.ClusterConfig) (string, time.Time, error) {
+	f.issued = append(f.issued, clusterConfig)
+	return "sa-token", time.Now().Add(24 * time.Hour), nil
+}
+
+type fakeCloudCredentialRepository struct {
+	repository.CloudCredentialRepository
+	credential *repository.CloudCredential
+}
+
+func (f *fakeCloudCredentialRepository) FindById(id int) (*repository.CloudCredential, error) {
+	if f.credential == nil || f.credential.Id != id {
+		return &repository.CloudCredential{}, pg.ErrNoRows
+	}
+	return f.credential, nil
+}
+
+type fakeClusterCloudSourceRepository struct {
+	repository.ClusterCloudSourceRepository
+	saved []*repository.ClusterCloudSource
+}
+
+func (f *fakeClusterCloudSourceRepository) Save(source *repository.ClusterCloudSource) error {
+	f.saved = append(f.saved, source)
+	return nil
+}
+
+type fakeClusterService struct {
+	cluster.ClusterService
+	clusters []*clusterBean.ClusterBean
+}
+
+func (f *fakeClusterService) FindAllWithoutConfig() ([]*clusterBean.ClusterBean, error) {
+	return f.clusters, nil
+}
+
+func (f *fakeClusterService) Save(ctx context.Context, request *clusterBean.ClusterBean, userId int32) (*clusterBean.ClusterBean, error) {
+	request.Id = len(f.clusters) + 1
+	f.clusters = append(f.clusters, request)
+	return request, nil
+}
+
+func newTestDiscoveryService(t *testing.T, clusterProvider provider.ClusterDiscoveryProvider, existing []*clusterBean.ClusterBean) (*ClusterDiscoveryServiceImpl, *fakeClusterService, *fakeTokenIssuer, *fakeClusterCloudSourceRepository) {
+	logger, err := util.NewSugardLogger()
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	credential, err := adapter.BuildCloudCredential(&bean.CloudCredentialDto{
+		Id:       1,
+		Name:     "aws-prod",
+		Provider: bean.CloudProviderEKS,
+		Aws:      &bean.AwsCredential{AccessKeyId: "AKIA", SecretAccessKey: "secret", Regions: []string{"us-east-1"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to build credential: %v", err)
+	}
+	clusterService := &fakeClusterService{clusters: existing}
+	tokenIssuer := &fakeTokenIssuer{}
+	sourceRepository := &fakeClusterCloudSourceRepository{}
+	config := &ClusterDiscoveryConfig{ServiceAccountNamespace: "kube-system", ServiceAccountName: "devtron-cluster-manager", TokenExpirySecs: 86400}
+	impl, err := NewClusterDiscoveryServiceImpl(logger, &fakeCloudCredentialRepository{credential: credential}, sourceRepository,
+		clusterService, provider.Providers{bean.CloudProviderEKS: clusterProvider}, tokenIssuer, config, nil)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	return impl, clusterService, tokenIssuer, sourceRepository
+}
+
+func TestDiscoverClustersMarksImported(t *testing.T) {
+	clusterProvider := &fakeClusterProvider{clusters: []*bean.DiscoveredCluster{
+		{ProviderClusterId: "us-east-1/payments", Name: "payments", ServerUrl: "https://payments.eks.amazonaws.com"},
+		{ProviderClusterId: "us-east-1/search", Name: "search", ServerUrl: "https://search.eks.amazonaws.com"},
+	}}
+	existing := []*clusterBean.ClusterBean{{Id: 7, ClusterName: "payments-prod", ServerUrl: "https://payments.eks.amazonaws.com/"}}
+	impl, _, _, _ := newTestDiscoveryService(t, clusterProvider, existing)
+
+	clusters, err := impl.DiscoverClusters(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, clusters, 2)
+	assert.Equal(t, 7, clusters[0].ImportedClusterId)
+	assert.Equal(t, "payments-prod", clusters[0].ImportedClusterName)
+	assert.Equal(t, 0, clusters[1].ImportedClusterId)
+}
+
+func TestImportClusters(t *testing.T) {
+	clusterProvider := &fakeClusterProvider{clusters: []*bean.DiscoveredCluster{
+		{ProviderClusterId: "us-east-1/payments", Name: "payments", ServerUrl: "https://payments.eks.amazonaws.com"},
+		{ProviderClusterId: "us-east-1/Search_Cluster", Name: "Search_Cluster", ServerUrl: "https://search.eks.amazonaws.com"},
+	}}
+	existing := []*clusterBean.ClusterBean{{Id: 7, ClusterName: "payments-prod", ServerUrl: "https://payments.eks.amazonaws.com"}}
+	impl, clusterService, tokenIssuer, sourceRepository := newTestDiscoveryService(t, clusterProvider, existing)
+
+	results, err := impl.ImportClusters(context.Background(), &bean.ImportClustersRequest{
+		CloudCredentialId: 1,
+		Clusters: []*bean.ImportClusterEntry{
+			{ProviderClusterId: "us-east-1/payments"},
+			{ProviderClusterId: "us-east-1/Search_Cluster", IsProd: true},
+			{ProviderClusterId: "us-east-1/missing"},
+		},
+		UserId: 2,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, bean.ImportStatusSkipped, results[0].Status)
+	assert.Equal(t, 7, results[0].ClusterId)
+	assert.Equal(t, bean.ImportStatusImported, results[1].Status)
+	assert.Equal(t, "search-cluster", results[1].ClusterName)
+	assert.Equal(t, bean.ImportStatusFailed, results[2].Status)
+
+	// the cloud token is only used to create the service account, the cluster is saved with its token
+	assert.Len(t, tokenIssuer.issued, 1)
+	assert.Equal(t, "cloud-token", tokenIssuer.issued[0].BearerToken)
+	saved := clusterService.clusters[1]
+	assert.Equal(t, "sa-token", saved.Config[commonBean.BearerToken])
+	assert.Equal(t, "ca-Search_Cluster", saved.Config[commonBean.CertificateAuthorityData])
+	assert.True(t, saved.IsProd)
+	assert.Len(t, sourceRepository.saved, 1)
+	assert.Equal(t, saved.Id, sourceRepository.saved[0].ClusterId)
+	assert.Equal(t, "us-east-1/Search_Cluster", sourceRepository.saved[0].ProviderClusterId)
+}
+
+func TestImportClustersReportsAccessFailure(t *testing.T) {
+	clusterProvider := &fakeClusterProvider{
+		clusters:  []*bean.DiscoveredCluster{{ProviderClusterId: "us-east-1/payments", Name: "payments", ServerUrl: "https://payments.eks.amazonaws.com"}},
+		accessErr: errors.New("access denied"),
+	}
+	impl, clusterService, _, _ := newTestDiscoveryService(t, clusterProvider, nil)
+
+	results, err := impl.ImportClusters(context.Background(), &bean.ImportClustersRequest{
+		CloudCredentialId: 1,
+		Clusters:          []*bean.ImportClusterEntry{{ProviderClusterId: "us-east-1/payments", ClusterName: "payments"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, bean.ImportStatusFailed, results[0].Status)
+	assert.Equal(t, "access denied", results[0].Message)
+	assert.Empty(t, clusterService.clusters)
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package discovery
+
+import (
+	"context"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"go.uber.org/zap"
+	authenticationV1 "k8s.io/api/authentication/v1"
+	coreV1 "k8s.io/api/core/v1"
+	rbacV1 "k8s.io/api/rbac/v1"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+const managedByLabelKey = "app.kubernetes.io/managed-by"
+
+// ServiceAccountTokenIssuer issues the tokens devtron connects to imported clusters with
+type ServiceAccountTokenIssuer interface {
+	// IssueToken creates the service account and its cluster role binding when missing and requests a token
+	// of the configured expiry for it through the TokenRequest api
+	IssueToken(ctx context.Context, clusterConfig *k8s.ClusterConfig) (token string, expiresAt time.Time, err error)
+}
+
+type ServiceAccountTokenIssuerImpl struct {
+	logger  *zap.SugaredLogger
+	k8sUtil *k8s.K8sServiceImpl
+	config  *ClusterDiscoveryConfig
+}
+
+func NewServiceAccountTokenIssuerImpl(logger *zap.SugaredLogger, k8sUtil *k8s.K8sServiceImpl,
+	config *ClusterDiscoveryConfig) *ServiceAccountTokenIssuerImpl {
+	return &ServiceAccountTokenIssuerImpl{
+		logger:  logger,
+		k8sUtil: k8sUtil,
+		config:  config,
+	}
+}
+
+func (impl *ServiceAccountTokenIssuerImpl) IssueToken(ctx context.Context, clusterConfig *k8s.ClusterConfig) (string, time.Time, error) {
+	_, _, clientSet, err := impl.k8sUtil.GetK8sConfigAndClients(clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error in getting client set", "clusterName", clusterConfig.ClusterName, "err", err)
+		return "", time.Time{}, err
+	}
+	namespace, name := impl.config.ServiceAccountNamespace, impl.config.ServiceAccountName
+	labels := map[string]string{managedByLabelKey: "devtron"}
+	_, err = clientSet.CoreV1().Namespaces().Get(ctx, namespace, metaV1.GetOptions{})
+	if k8sError.IsNotFound(err) {
+		_, err = clientSet.CoreV1().Namespaces().Create(ctx, &coreV1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: namespace, Labels: labels}}, metaV1.CreateOptions{})
+	}
+	if err != nil && !k8sError.IsAlreadyExists(err) {
+		impl.logger.Errorw("error in creating service account namespace", "clusterName", clusterConfig.ClusterName, "namespace", namespace, "err", err)
+		return "", time.Time{}, err
+	}
+	_, err = clientSet.CoreV1().ServiceAccounts(namespace).Get(ctx, name, metaV1.GetOptions{})
+	if k8sError.IsNotFound(err) {
+		serviceAccount := &coreV1.ServiceAccount{ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}}
+		_, err = clientSet.CoreV1().ServiceAccounts(namespace).Create(ctx, serviceAccount, metaV1.CreateOptions{})
+	}
+	if err != nil && !k8sError.IsAlreadyExists(err) {
+		impl.logger.Errorw("error in creating service account", "clusterName", clusterConfig.ClusterName, "namespace", namespace, "name", name, "err", err)
+		return "", time.Time{}, err
+	}
+	clusterRoleBinding, err := clientSet.RbacV1().ClusterRoleBindings().Get(ctx, name, metaV1.GetOptions{})
+	if k8sError.IsNotFound(err) {
+		clusterRoleBinding = &rbacV1.ClusterRoleBinding{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Labels: labels},
+			RoleRef:    rbacV1.RoleRef{APIGroup: rbacV1.GroupName, Kind: "ClusterRole", Name: impl.config.ClusterRoleName},
+			Subjects:   []rbacV1.Subject{{Kind: rbacV1.ServiceAccountKind, Name: name, Namespace: namespace}},
+		}
+		_, err = clientSet.RbacV1().ClusterRoleBindings().Create(ctx, clusterRoleBinding, metaV1.CreateOptions{})
+	} else if err == nil && clusterRoleBinding.RoleRef.Name != impl.config.ClusterRoleName {
+		// role ref of a binding is immutable, the binding is left to be changed by the cluster admin
+		impl.logger.Warnw("service account is bound to a different cluster role", "clusterName", clusterConfig.ClusterName,
+			"clusterRoleBinding", name, "clusterRole", clusterRoleBinding.RoleRef.Name, "expectedClusterRole", impl.config.ClusterRoleName)
+	}
+	if err != nil && !k8sError.IsAlreadyExists(err) {
+		impl.logger.Errorw("error in creating cluster role binding", "clusterName", clusterConfig.ClusterName, "name", name, "err", err)
+		return "", time.Time{}, err
+	}
+	tokenRequest := &authenticationV1.TokenRequest{
+		Spec: authenticationV1.TokenRequestSpec{ExpirationSeconds: &impl.config.TokenExpirySecs},
+	}
+	tokenRequest, err = clientSet.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, name, tokenRequest, metaV1.CreateOptions{})
+	if err != nil {
+		impl.logger.Errorw("error in requesting service account token", "clusterName", clusterConfig.ClusterName, "namespace", namespace, "name", name, "err", err)
+		return "", time.Time{}, err
+	}
+	return tokenRequest.Status.Token, tokenRequest.Status.ExpirationTimestamp.Time, nil
+}
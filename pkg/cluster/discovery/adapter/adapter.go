@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+)
+
+// BuildCloudCredential keeps only the credential of the selected provider in config
+func BuildCloudCredential(dto *bean.CloudCredentialDto) (*repository.CloudCredential, error) {
+	var providerCredential interface{}
+	switch dto.Provider {
+	case bean.CloudProviderEKS:
+		providerCredential = dto.Aws
+	case bean.CloudProviderGKE:
+		providerCredential = dto.Gcp
+	case bean.CloudProviderAKS:
+		providerCredential = dto.Azure
+	}
+	config, err := json.Marshal(providerCredential)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.CloudCredential{
+		Id:       dto.Id,
+		Name:     dto.Name,
+		Provider: string(dto.Provider),
+		Config:   string(config),
+		Active:   true,
+		AuditLog: sql.NewDefaultAuditLog(dto.UserId),
+	}, nil
+}
+
+func GetCloudCredentialDto(model *repository.CloudCredential) (*bean.CloudCredentialDto, error) {
+	dto := &bean.CloudCredentialDto{
+		Id:       model.Id,
+		Name:     model.Name,
+		Provider: bean.CloudProvider(model.Provider),
+	}
+	var err error
+	switch dto.Provider {
+	case bean.CloudProviderEKS:
+		dto.Aws = &bean.AwsCredential{}
+		err = json.Unmarshal([]byte(model.Config), dto.Aws)
+	case bean.CloudProviderGKE:
+		dto.Gcp = &bean.GcpCredential{}
+		err = json.Unmarshal([]byte(model.Config), dto.Gcp)
+	case bean.CloudProviderAKS:
+		dto.Azure = &bean.AzureCredential{}
+		err = json.Unmarshal([]byte(model.Config), dto.Azure)
+	}
+	return dto, err
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bean
+
+import (
+	"fmt"
+	"time"
+)
+
+type CloudProvider string
+
+const (
+	CloudProviderEKS CloudProvider = "EKS"
+	CloudProviderGKE CloudProvider = "GKE"
+	CloudProviderAKS CloudProvider = "AKS"
+)
+
+// CloudCredentialDto holds the credential of one of the cloud providers, secrets are never returned in get
+// apis and are kept as saved when left empty in update
+type CloudCredentialDto struct {
+	Id       int              `json:"id"`
+	Name     string           `json:"name" validate:"required,max=100"`
+	Provider CloudProvider    `json:"provider" validate:"oneof=EKS GKE AKS"`
+	Aws      *AwsCredential   `json:"aws,omitempty"`
+	Gcp      *GcpCredential   `json:"gcp,omitempty"`
+	Azure    *AzureCredential `json:"azure,omitempty"`
+	UserId   int32            `json:"-"`
+}
+
+type AwsCredential struct {
+	AccessKeyId     string `json:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	// RoleArn is assumed with the access key when set, for listing clusters of another account
+	RoleArn string   `json:"roleArn,omitempty"`
+	Regions []string `json:"regions"`
+}
+
+type GcpCredential struct {
+	ProjectId string `json:"projectId"`
+	// ServiceAccountKey is the json key of the service account
+	ServiceAccountKey string `json:"serviceAccountKey,omitempty"`
+}
+
+type AzureCredential struct {
+	TenantId       string `json:"tenantId"`
+	ClientId       string `json:"clientId"`
+	ClientSecret   string `json:"clientSecret,omitempty"`
+	SubscriptionId string `json:"subscriptionId"`
+}
+
+// ValidateProviderCredential checks that the credential of the provider is set, secrets are checked
+// separately as they can be left empty in update
+func (c *CloudCredentialDto) ValidateProviderCredential() error {
+	switch c.Provider {
+	case CloudProviderEKS:
+		if c.Aws == nil || len(c.Aws.AccessKeyId) == 0 || len(c.Aws.Regions) == 0 {
+			return fmt.Errorf("aws accessKeyId and regions are required for %s", c.Provider)
+		}
+	case CloudProviderGKE:
+		if c.Gcp == nil {
+			return fmt.Errorf("gcp credential is required for %s", c.Provider)
+		}
+	case CloudProviderAKS:
+		if c.Azure == nil || len(c.Azure.TenantId) == 0 || len(c.Azure.ClientId) == 0 || len(c.Azure.SubscriptionId) == 0 {
+			return fmt.Errorf("azure tenantId, clientId and subscriptionId are required for %s", c.Provider)
+		}
+	default:
+		return fmt.Errorf("unsupported provider %q", c.Provider)
+	}
+	return nil
+}
+
+func (c *CloudCredentialDto) IsSecretEmpty() bool {
+	switch c.Provider {
+	case CloudProviderEKS:
+		return len(c.Aws.SecretAccessKey) == 0
+	case CloudProviderGKE:
+		return len(c.Gcp.ServiceAccountKey) == 0
+	case CloudProviderAKS:
+		return len(c.Azure.ClientSecret) == 0
+	}
+	return true
+}
+
+// CopySecretFrom sets the secret of the saved credential on requests where it is left empty
+func (c *CloudCredentialDto) CopySecretFrom(saved *CloudCredentialDto) {
+	switch {
+	case c.Aws != nil && saved.Aws != nil:
+		c.Aws.SecretAccessKey = saved.Aws.SecretAccessKey
+	case c.Gcp != nil && saved.Gcp != nil:
+		c.Gcp.ServiceAccountKey = saved.Gcp.ServiceAccountKey
+	case c.Azure != nil && saved.Azure != nil:
+		c.Azure.ClientSecret = saved.Azure.ClientSecret
+	}
+}
+
+func (c *CloudCredentialDto) HideSecrets() *CloudCredentialDto {
+	if c.Aws != nil {
+		c.Aws.SecretAccessKey = ""
+	}
+	if c.Gcp != nil {
+		c.Gcp.ServiceAccountKey = ""
+	}
+	if c.Azure != nil {
+		c.Azure.ClientSecret = ""
+	}
+	return c
+}
+
+// DiscoveredCluster is a cluster listed by the cloud provider, ProviderClusterId identifies it within the
+// credential (region/name for EKS, location/name for GKE and the resource id for AKS)
+type DiscoveredCluster struct {
+	ProviderClusterId   string `json:"providerClusterId"`
+	Name                string `json:"name"`
+	Region              string `json:"region"`
+	ServerUrl           string `json:"serverUrl"`
+	K8sVersion          string `json:"k8sVersion"`
+	Status              string `json:"status"`
+	ImportedClusterId   int    `json:"importedClusterId,omitempty"`
+	ImportedClusterName string `json:"importedClusterName,omitempty"`
+}
+
+// ClusterAccess is the short-lived access to a cluster issued from the cloud credential, CaData is pem encoded
+type ClusterAccess struct {
+	ServerUrl   string
+	CaData      string
+	BearerToken string
+	CertData    string
+	KeyData     string
+	ExpiresAt   time.Time
+}
+
+type ImportClustersRequest struct {
+	CloudCredentialId int                   `json:"cloudCredentialId" validate:"required"`
+	Clusters          []*ImportClusterEntry `json:"clusters" validate:"min=1,dive"`
+	UserId            int32                 `json:"-"`
+}
+
+type ImportClusterEntry struct {
+	ProviderClusterId string `json:"providerClusterId" validate:"required"`
+	// ClusterName defaults to the name of the cluster in the cloud provider
+	ClusterName string `json:"clusterName,omitempty"`
+	IsProd      bool   `json:"isProd"`
+}
+
+type ImportStatus string
+
+const (
+	ImportStatusImported ImportStatus = "Imported"
+	ImportStatusSkipped  ImportStatus = "Skipped"
+	ImportStatusFailed   ImportStatus = "Failed"
+)
+
+type ImportClusterResult struct {
+	ProviderClusterId string       `json:"providerClusterId"`
+	ClusterName       string       `json:"clusterName"`
+	ClusterId         int          `json:"clusterId,omitempty"`
+	Status            ImportStatus `json:"status"`
+	Message           string       `json:"message,omitempty"`
+}
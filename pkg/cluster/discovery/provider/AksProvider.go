@@ -0,0 +1,223 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/bean"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"k8s.io/client-go/tools/clientcmd"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	aksApiVersion            = "2024-02-01"
+	azureManagementScope     = "https://management.azure.com/.default"
+	aksEntraIdServerAppScope = "6dae42f8-4368-4678-94ff-3960e28e3630/.default"
+)
+
+type AksProvider struct {
+	logger             *zap.SugaredLogger
+	httpClient         *http.Client
+	managementEndpoint string
+	loginEndpoint      string
+}
+
+func NewAksProvider(logger *zap.SugaredLogger, httpClient *http.Client) *AksProvider {
+	return &AksProvider{
+		logger:             logger,
+		httpClient:         httpClient,
+		managementEndpoint: "https://management.azure.com",
+		loginEndpoint:      "https://login.microsoftonline.com",
+	}
+}
+
+type aksManagedCluster struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	Location   string `json:"location"`
+	Properties struct {
+		Fqdn                     string    `json:"fqdn"`
+		PrivateFqdn              string    `json:"privateFQDN"`
+		CurrentKubernetesVersion string    `json:"currentKubernetesVersion"`
+		ProvisioningState        string    `json:"provisioningState"`
+		AadProfile               *struct{} `json:"aadProfile"`
+	} `json:"properties"`
+}
+
+type aksListClustersResponse struct {
+	Value    []*aksManagedCluster `json:"value"`
+	NextLink string               `json:"nextLink"`
+}
+
+type aksCredentialResults struct {
+	Kubeconfigs []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"kubeconfigs"`
+}
+
+func (impl *AksProvider) ListClusters(ctx context.Context, credential *bean.CloudCredentialDto) ([]*bean.DiscoveredCluster, error) {
+	token, err := impl.getToken(ctx, credential.Azure, azureManagementScope)
+	if err != nil {
+		impl.logger.Errorw("error in getting azure management token", "err", err)
+		return nil, err
+	}
+	clusters := make([]*bean.DiscoveredCluster, 0)
+	reqUrl := fmt.Sprintf("%s/subscriptions/%s/providers/Microsoft.ContainerService/managedClusters?api-version=%s",
+		impl.managementEndpoint, url.PathEscape(credential.Azure.SubscriptionId), aksApiVersion)
+	for len(reqUrl) > 0 {
+		resp := &aksListClustersResponse{}
+		err = impl.doRequest(ctx, token, http.MethodGet, reqUrl, resp)
+		if err != nil {
+			impl.logger.Errorw("error in listing aks clusters", "subscriptionId", credential.Azure.SubscriptionId, "err", err)
+			return nil, err
+		}
+		for _, cluster := range resp.Value {
+			clusters = append(clusters, &bean.DiscoveredCluster{
+				ProviderClusterId: cluster.Id,
+				Name:              cluster.Name,
+				Region:            cluster.Location,
+				ServerUrl:         getAksServerUrl(cluster),
+				K8sVersion:        cluster.Properties.CurrentKubernetesVersion,
+				Status:            cluster.Properties.ProvisioningState,
+			})
+		}
+		reqUrl = resp.NextLink
+	}
+	return clusters, nil
+}
+
+// GetClusterAccess uses an entra id token of the service principal for clusters with entra id integration,
+// for the others it falls back to the admin credentials of the local accounts
+func (impl *AksProvider) GetClusterAccess(ctx context.Context, credential *bean.CloudCredentialDto, providerClusterId string) (*bean.ClusterAccess, error) {
+	subscriptionPrefix := "/subscriptions/" + credential.Azure.SubscriptionId + "/"
+	if !strings.HasPrefix(strings.ToLower(providerClusterId), strings.ToLower(subscriptionPrefix)) || strings.Contains(providerClusterId, "..") {
+		return nil, fmt.Errorf("aks cluster %q does not belong to subscription %s", providerClusterId, credential.Azure.SubscriptionId)
+	}
+	token, err := impl.getToken(ctx, credential.Azure, azureManagementScope)
+	if err != nil {
+		impl.logger.Errorw("error in getting azure management token", "err", err)
+		return nil, err
+	}
+	cluster := &aksManagedCluster{}
+	err = impl.doRequest(ctx, token, http.MethodGet, impl.getResourceUrl(providerClusterId, ""), cluster)
+	if err != nil {
+		impl.logger.Errorw("error in getting aks cluster", "providerClusterId", providerClusterId, "err", err)
+		return nil, err
+	}
+	credentialAction := "listClusterAdminCredential"
+	if cluster.Properties.AadProfile != nil {
+		credentialAction = "listClusterUserCredential"
+	}
+	credentialResults := &aksCredentialResults{}
+	err = impl.doRequest(ctx, token, http.MethodPost, impl.getResourceUrl(providerClusterId, credentialAction), credentialResults)
+	if err != nil {
+		impl.logger.Errorw("error in getting aks cluster credentials", "providerClusterId", providerClusterId, "err", err)
+		return nil, err
+	}
+	if len(credentialResults.Kubeconfigs) == 0 {
+		return nil, fmt.Errorf("no kubeconfig returned for aks cluster %s", cluster.Name)
+	}
+	kubeconfig, err := base64.StdEncoding.DecodeString(credentialResults.Kubeconfigs[0].Value)
+	if err != nil {
+		return nil, err
+	}
+	access, err := parseKubeconfigAccess(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig of aks cluster %s: %w", cluster.Name, err)
+	}
+	if cluster.Properties.AadProfile != nil {
+		clusterToken, err := impl.getToken(ctx, credential.Azure, aksEntraIdServerAppScope)
+		if err != nil {
+			impl.logger.Errorw("error in getting entra id token for aks cluster", "providerClusterId", providerClusterId, "err", err)
+			return nil, err
+		}
+		access.BearerToken = clusterToken.AccessToken
+		access.ExpiresAt = clusterToken.Expiry
+	}
+	return access, nil
+}
+
+func (impl *AksProvider) getToken(ctx context.Context, credential *bean.AzureCredential, scope string) (*oauth2.Token, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, impl.httpClient)
+	config := &clientcredentials.Config{
+		ClientID:     credential.ClientId,
+		ClientSecret: credential.ClientSecret,
+		TokenURL:     fmt.Sprintf("%s/%s/oauth2/v2.0/token", impl.loginEndpoint, url.PathEscape(credential.TenantId)),
+		Scopes:       []string{scope},
+	}
+	return config.Token(ctx)
+}
+
+func (impl *AksProvider) getResourceUrl(resourceId, action string) string {
+	if len(action) > 0 {
+		resourceId += "/" + action
+	}
+	return fmt.Sprintf("%s%s?api-version=%s", impl.managementEndpoint, resourceId, aksApiVersion)
+}
+
+func (impl *AksProvider) doRequest(ctx context.Context, token *oauth2.Token, method, reqUrl string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, reqUrl, nil)
+	if err != nil {
+		return err
+	}
+	token.SetAuthHeader(req)
+	return doJsonRequest(impl.httpClient, req, out)
+}
+
+func getAksServerUrl(cluster *aksManagedCluster) string {
+	fqdn := cluster.Properties.Fqdn
+	if len(fqdn) == 0 {
+		fqdn = cluster.Properties.PrivateFqdn
+	}
+	if len(fqdn) == 0 {
+		return ""
+	}
+	return "https://" + fqdn + ":443"
+}
+
+// parseKubeconfigAccess reads the server and credentials of the current context of kubeconfig
+func parseKubeconfigAccess(kubeconfig []byte) (*bean.ClusterAccess, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	kubeContext, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("current context %q not found", config.CurrentContext)
+	}
+	cluster, ok := config.Clusters[kubeContext.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("cluster %q not found", kubeContext.Cluster)
+	}
+	access := &bean.ClusterAccess{
+		ServerUrl: cluster.Server,
+		CaData:    string(cluster.CertificateAuthorityData),
+	}
+	if authInfo, ok := config.AuthInfos[kubeContext.AuthInfo]; ok {
+		access.BearerToken = authInfo.Token
+		access.CertData = string(authInfo.ClientCertificateData)
+		access.KeyData = string(authInfo.ClientKeyData)
+	}
+	return access, nil
+}
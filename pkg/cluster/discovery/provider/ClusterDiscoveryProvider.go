@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/bean"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ClusterDiscoveryProvider lists the kubernetes clusters a cloud credential has access to and issues
+// short-lived access to them from the same credential
+type ClusterDiscoveryProvider interface {
+	ListClusters(ctx context.Context, credential *bean.CloudCredentialDto) ([]*bean.DiscoveredCluster, error)
+	GetClusterAccess(ctx context.Context, credential *bean.CloudCredentialDto, providerClusterId string) (*bean.ClusterAccess, error)
+}
+
+type Providers map[bean.CloudProvider]ClusterDiscoveryProvider
+
+func NewProviders(logger *zap.SugaredLogger) Providers {
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	return Providers{
+		bean.CloudProviderEKS: NewEksProvider(logger, httpClient),
+		bean.CloudProviderGKE: NewGkeProvider(logger, httpClient),
+		bean.CloudProviderAKS: NewAksProvider(logger, httpClient),
+	}
+}
+
+func (p Providers) Get(provider bean.CloudProvider) (ClusterDiscoveryProvider, error) {
+	clusterProvider, ok := p[provider]
+	if !ok {
+		return nil, fmt.Errorf("cluster discovery is not supported for provider %q", provider)
+	}
+	return clusterProvider, nil
+}
+
+// doJsonRequest sends req and decodes the json response in out, non 2xx responses are returned as error
+func doJsonRequest(httpClient *http.Client, req *http.Request, out interface{}) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		if len(body) > 500 {
+			body = body[:500]
+		}
+		return fmt.Errorf("%s %s returned %d: %s", req.Method, req.URL.Path, resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/bean"
+	"go.uber.org/zap"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	eksServiceName = "eks"
+	// eksTokenPrefix and eksClusterIdHeader follow the token format of aws-iam-authenticator, the token is a
+	// presigned sts GetCallerIdentity url bound to the cluster name
+	eksTokenPrefix     = "k8s-aws-v1."
+	eksClusterIdHeader = "x-k8s-aws-id"
+	// eksTokenValidity is kept below the 15 minutes for which eks accepts the presigned url
+	eksTokenValidity = 14 * time.Minute
+)
+
+type EksProvider struct {
+	logger     *zap.SugaredLogger
+	httpClient *http.Client
+	// endpoint returns the eks api endpoint of the region
+	endpoint func(region string) string
+}
+
+func NewEksProvider(logger *zap.SugaredLogger, httpClient *http.Client) *EksProvider {
+	return &EksProvider{
+		logger:     logger,
+		httpClient: httpClient,
+		endpoint: func(region string) string {
+			return fmt.Sprintf("https://eks.%s.amazonaws.com", region)
+		},
+	}
+}
+
+type eksListClustersResponse struct {
+	Clusters  []string `json:"clusters"`
+	NextToken *string  `json:"nextToken"`
+}
+
+type eksDescribeClusterResponse struct {
+	Cluster struct {
+		Name                 string `json:"name"`
+		Endpoint             string `json:"endpoint"`
+		Version              string `json:"version"`
+		Status               string `json:"status"`
+		CertificateAuthority struct {
+			Data string `json:"data"`
+		} `json:"certificateAuthority"`
+	} `json:"cluster"`
+}
+
+func (impl *EksProvider) ListClusters(ctx context.Context, credential *bean.CloudCredentialDto) ([]*bean.DiscoveredCluster, error) {
+	clusters := make([]*bean.DiscoveredCluster, 0)
+	for _, region := range credential.Aws.Regions {
+		awsCredentials, err := impl.getCredentials(credential.Aws, region)
+		if err != nil {
+			return nil, err
+		}
+		names, err := impl.listClusterNames(ctx, awsCredentials, region)
+		if err != nil {
+			impl.logger.Errorw("error in listing eks clusters", "region", region, "err", err)
+			return nil, err
+		}
+		for _, name := range names {
+			cluster, err := impl.describeCluster(ctx, awsCredentials, region, name)
+			if err != nil {
+				impl.logger.Errorw("error in describing eks cluster", "region", region, "name", name, "err", err)
+				return nil, err
+			}
+			clusters = append(clusters, &bean.DiscoveredCluster{
+				ProviderClusterId: getEksProviderClusterId(region, name),
+				Name:              name,
+				Region:            region,
+				ServerUrl:         cluster.Cluster.Endpoint,
+				K8sVersion:        cluster.Cluster.Version,
+				Status:            cluster.Cluster.Status,
+			})
+		}
+	}
+	return clusters, nil
+}
+
+func (impl *EksProvider) GetClusterAccess(ctx context.Context, credential *bean.CloudCredentialDto, providerClusterId string) (*bean.ClusterAccess, error) {
+	region, name, err := parseEksProviderClusterId(providerClusterId)
+	if err != nil {
+		return nil, err
+	}
+	awsCredentials, err := impl.getCredentials(credential.Aws, region)
+	if err != nil {
+		return nil, err
+	}
+	cluster, err := impl.describeCluster(ctx, awsCredentials, region, name)
+	if err != nil {
+		impl.logger.Errorw("error in describing eks cluster", "region", region, "name", name, "err", err)
+		return nil, err
+	}
+	caData, err := base64.StdEncoding.DecodeString(cluster.Cluster.CertificateAuthority.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate authority of eks cluster %s: %w", name, err)
+	}
+	token, expiresAt, err := impl.getToken(awsCredentials, region, name)
+	if err != nil {
+		impl.logger.Errorw("error in generating eks token", "region", region, "name", name, "err", err)
+		return nil, err
+	}
+	return &bean.ClusterAccess{
+		ServerUrl:   cluster.Cluster.Endpoint,
+		CaData:      string(caData),
+		BearerToken: token,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+func (impl *EksProvider) getCredentials(credential *bean.AwsCredential, region string) (*credentials.Credentials, error) {
+	staticCredentials := credentials.NewStaticCredentials(credential.AccessKeyId, credential.SecretAccessKey, "")
+	if len(credential.RoleArn) == 0 {
+		return staticCredentials, nil
+	}
+	sess, err := session.NewSession(&aws.Config{Credentials: staticCredentials, Region: aws.String(region)})
+	if err != nil {
+		return nil, err
+	}
+	return stscreds.NewCredentials(sess, credential.RoleArn), nil
+}
+
+func (impl *EksProvider) listClusterNames(ctx context.Context, awsCredentials *credentials.Credentials, region string) ([]string, error) {
+	names := make([]string, 0)
+	var nextToken *string
+	for {
+		query := url.Values{}
+		if nextToken != nil {
+			query.Set("nextToken", *nextToken)
+		}
+		resp := &eksListClustersResponse{}
+		err := impl.doSignedRequest(ctx, awsCredentials, region, "/clusters", query, resp)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, resp.Clusters...)
+		if resp.NextToken == nil || len(*resp.NextToken) == 0 {
+			return names, nil
+		}
+		nextToken = resp.NextToken
+	}
+}
+
+func (impl *EksProvider) describeCluster(ctx context.Context, awsCredentials *credentials.Credentials, region, name string) (*eksDescribeClusterResponse, error) {
+	resp := &eksDescribeClusterResponse{}
+	err := impl.doSignedRequest(ctx, awsCredentials, region, "/clusters/"+url.PathEscape(name), nil, resp)
+	return resp, err
+}
+
+func (impl *EksProvider) doSignedRequest(ctx context.Context, awsCredentials *credentials.Credentials, region, path string, query url.Values, out interface{}) error {
+	reqUrl := impl.endpoint(region) + path
+	if len(query) > 0 {
+		reqUrl += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqUrl, nil)
+	if err != nil {
+		return err
+	}
+	_, err = v4.NewSigner(awsCredentials).Sign(req, nil, eksServiceName, region, time.Now())
+	if err != nil {
+		return err
+	}
+	return doJsonRequest(impl.httpClient, req, out)
+}
+
+func (impl *EksProvider) getToken(awsCredentials *credentials.Credentials, region, clusterName string) (string, time.Time, error) {
+	sess, err := session.NewSession(&aws.Config{Credentials: awsCredentials, Region: aws.String(region)})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req, _ := sts.New(sess).GetCallerIdentityRequest(&sts.GetCallerIdentityInput{})
+	req.HTTPRequest.Header.Add(eksClusterIdHeader, clusterName)
+	presignedUrl, err := req.Presign(time.Minute)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return eksTokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(presignedUrl)), time.Now().Add(eksTokenValidity), nil
+}
+
+func getEksProviderClusterId(region, name string) string {
+	return region + "/" + name
+}
+
+func parseEksProviderClusterId(providerClusterId string) (region, name string, err error) {
+	region, name, found := strings.Cut(providerClusterId, "/")
+	if !found || len(region) == 0 || len(name) == 0 {
+		return "", "", fmt.Errorf("invalid eks cluster id %q, expected <region>/<name>", providerClusterId)
+	}
+	return region, name, nil
+}
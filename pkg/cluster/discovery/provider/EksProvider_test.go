@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/bean"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEksProvider(t *testing.T) *EksProvider {
+	logger, err := util.NewSugardLogger()
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.URL.Path == "/clusters" && r.URL.Query().Get("nextToken") == "":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"clusters": []string{"payments"}, "nextToken": "page-2"})
+		case r.URL.Path == "/clusters":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"clusters": []string{"search"}})
+		case strings.HasPrefix(r.URL.Path, "/clusters/"):
+			name := strings.TrimPrefix(r.URL.Path, "/clusters/")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"cluster": map[string]interface{}{
+				"name":                 name,
+				"endpoint":             "https://" + name + ".eks.amazonaws.com",
+				"version":              "1.30",
+				"status":               "ACTIVE",
+				"certificateAuthority": map[string]string{"data": base64.StdEncoding.EncodeToString([]byte("ca-" + name))},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	impl := NewEksProvider(logger, server.Client())
+	impl.endpoint = func(region string) string { return server.URL }
+	return impl
+}
+
+func testAwsCredential() *bean.CloudCredentialDto {
+	return &bean.CloudCredentialDto{
+		Provider: bean.CloudProviderEKS,
+		Aws:      &bean.AwsCredential{AccessKeyId: "AKIAEXAMPLE", SecretAccessKey: "secret", Regions: []string{"us-east-1"}},
+	}
+}
+
+func TestEksListClusters(t *testing.T) {
+	impl := newTestEksProvider(t)
+	clusters, err := impl.ListClusters(context.Background(), testAwsCredential())
+	assert.NoError(t, err)
+	assert.Len(t, clusters, 2)
+	assert.Equal(t, "us-east-1/payments", clusters[0].ProviderClusterId)
+	assert.Equal(t, "https://search.eks.amazonaws.com", clusters[1].ServerUrl)
+	assert.Equal(t, "1.30", clusters[1].K8sVersion)
+}
+
+func TestEksGetClusterAccess(t *testing.T) {
+	impl := newTestEksProvider(t)
+	access, err := impl.GetClusterAccess(context.Background(), testAwsCredential(), "us-east-1/payments")
+	assert.NoError(t, err)
+	assert.Equal(t, "ca-payments", access.CaData)
+	assert.True(t, strings.HasPrefix(access.BearerToken, eksTokenPrefix))
+	presignedUrl, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(access.BearerToken, eksTokenPrefix))
+	assert.NoError(t, err)
+	assert.Contains(t, string(presignedUrl), "Action=GetCallerIdentity")
+	assert.Contains(t, string(presignedUrl), eksClusterIdHeader)
+
+	_, err = impl.GetClusterAccess(context.Background(), testAwsCredential(), "payments")
+	assert.Error(t, err)
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/cluster/discovery/bean"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const gkeCloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+type GkeProvider struct {
+	logger     *zap.SugaredLogger
+	httpClient *http.Client
+	endpoint   string
+}
+
+func NewGkeProvider(logger *zap.SugaredLogger, httpClient *http.Client) *GkeProvider {
+	return &GkeProvider{
+		logger:     logger,
+		httpClient: httpClient,
+		endpoint:   "https://container.googleapis.com",
+	}
+}
+
+type gkeCluster struct {
+	Name                 string `json:"name"`
+	Location             string `json:"location"`
+	Endpoint             string `json:"endpoint"`
+	CurrentMasterVersion string `json:"currentMasterVersion"`
+	Status               string `json:"status"`
+	MasterAuth           struct {
+		ClusterCaCertificate string `json:"clusterCaCertificate"`
+	} `json:"masterAuth"`
+}
+
+type gkeListClustersResponse struct {
+	Clusters []*gkeCluster `json:"clusters"`
+	// MissingZones are the zones which could not be reached, clusters in them are not listed
+	MissingZones []string `json:"missingZones"`
+}
+
+func (impl *GkeProvider) ListClusters(ctx context.Context, credential *bean.CloudCredentialDto) ([]*bean.DiscoveredCluster, error) {
+	token, projectId, err := impl.getToken(ctx, credential.Gcp)
+	if err != nil {
+		impl.logger.Errorw("error in getting gcp access token", "err", err)
+		return nil, err
+	}
+	resp := &gkeListClustersResponse{}
+	err = impl.doRequest(ctx, token, fmt.Sprintf("/v1/projects/%s/locations/-/clusters", url.PathEscape(projectId)), resp)
+	if err != nil {
+		impl.logger.Errorw("error in listing gke clusters", "projectId", projectId, "err", err)
+		return nil, err
+	}
+	if len(resp.MissingZones) > 0 {
+		impl.logger.Warnw("gke clusters of some zones could not be listed", "projectId", projectId, "zones", resp.MissingZones)
+	}
+	clusters := make([]*bean.DiscoveredCluster, 0, len(resp.Clusters))
+	for _, cluster := range resp.Clusters {
+		clusters = append(clusters, &bean.DiscoveredCluster{
+			ProviderClusterId: cluster.Location + "/" + cluster.Name,
+			Name:              cluster.Name,
+			Region:            cluster.Location,
+			ServerUrl:         getGkeServerUrl(cluster.Endpoint),
+			K8sVersion:        cluster.CurrentMasterVersion,
+			Status:            cluster.Status,
+		})
+	}
+	return clusters, nil
+}
+
+func (impl *GkeProvider) GetClusterAccess(ctx context.Context, credential *bean.CloudCredentialDto, providerClusterId string) (*bean.ClusterAccess, error) {
+	location, name, found := strings.Cut(providerClusterId, "/")
+	if !found || len(location) == 0 || len(name) == 0 {
+		return nil, fmt.Errorf("invalid gke cluster id %q, expected <location>/<name>", providerClusterId)
+	}
+	token, projectId, err := impl.getToken(ctx, credential.Gcp)
+	if err != nil {
+		impl.logger.Errorw("error in getting gcp access token", "err", err)
+		return nil, err
+	}
+	cluster := &gkeCluster{}
+	err = impl.doRequest(ctx, token, fmt.Sprintf("/v1/projects/%s/locations/%s/clusters/%s",
+		url.PathEscape(projectId), url.PathEscape(location), url.PathEscape(name)), cluster)
+	if err != nil {
+		impl.logger.Errorw("error in getting gke cluster", "projectId", projectId, "providerClusterId", providerClusterId, "err", err)
+		return nil, err
+	}
+	caData, err := base64.StdEncoding.DecodeString(cluster.MasterAuth.ClusterCaCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate authority of gke cluster %s: %w", name, err)
+	}
+	// gke accepts the oauth access token of the google service account as bearer token
+	return &bean.ClusterAccess{
+		ServerUrl:   getGkeServerUrl(cluster.Endpoint),
+		CaData:      string(caData),
+		BearerToken: token.AccessToken,
+		ExpiresAt:   token.Expiry,
+	}, nil
+}
+
+// getToken returns the access token of the service account key along with the project to list clusters in,
+// which defaults to the project of the service account
+func (impl *GkeProvider) getToken(ctx context.Context, credential *bean.GcpCredential) (*oauth2.Token, string, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, impl.httpClient)
+	googleCredentials, err := google.CredentialsFromJSON(ctx, []byte(credential.ServiceAccountKey), gkeCloudPlatformScope)
+	if err != nil {
+		return nil, "", err
+	}
+	token, err := googleCredentials.TokenSource.Token()
+	if err != nil {
+		return nil, "", err
+	}
+	projectId := credential.ProjectId
+	if len(projectId) == 0 {
+		projectId = googleCredentials.ProjectID
+	}
+	if len(projectId) == 0 {
+		return nil, "", fmt.Errorf("gcp project id is not set in the credential or the service account key")
+	}
+	return token, projectId, nil
+}
+
+func (impl *GkeProvider) doRequest(ctx context.Context, token *oauth2.Token, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, impl.endpoint+path, nil)
+	if err != nil {
+		return err
+	}
+	token.SetAuthHeader(req)
+	return doJsonRequest(impl.httpClient, req, out)
+}
+
+func getGkeServerUrl(endpoint string) string {
+	if len(endpoint) == 0 || strings.HasPrefix(endpoint, "https://") {
+		return endpoint
+	}
+	return "https://" + endpoint
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type CloudCredential struct {
+	tableName struct{} `sql:"cloud_credential" pg:",discard_unknown_columns"`
+	Id        int      `sql:"id,pk"`
+	Name      string   `sql:"name,notnull"`
+	Provider  string   `sql:"provider,notnull"`
+	Config    string   `sql:"config,notnull"`
+	Active    bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type CloudCredentialRepository interface {
+	Save(credential *CloudCredential) error
+	Update(credential *CloudCredential) error
+	FindById(id int) (*CloudCredential, error)
+	FindByName(name string) (*CloudCredential, error)
+	FindAllActive() ([]*CloudCredential, error)
+}
+
+type CloudCredentialRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCloudCredentialRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CloudCredentialRepositoryImpl {
+	return &CloudCredentialRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *CloudCredentialRepositoryImpl) Save(credential *CloudCredential) error {
+	return impl.dbConnection.Insert(credential)
+}
+
+func (impl *CloudCredentialRepositoryImpl) Update(credential *CloudCredential) error {
+	return impl.dbConnection.Update(credential)
+}
+
+func (impl *CloudCredentialRepositoryImpl) FindById(id int) (*CloudCredential, error) {
+	credential := &CloudCredential{}
+	err := impl.dbConnection.Model(credential).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return credential, err
+}
+
+func (impl *CloudCredentialRepositoryImpl) FindByName(name string) (*CloudCredential, error) {
+	credential := &CloudCredential{}
+	err := impl.dbConnection.Model(credential).
+		Where("name = ?", name).
+		Where("active = ?", true).
+		Select()
+	return credential, err
+}
+
+func (impl *CloudCredentialRepositoryImpl) FindAllActive() ([]*CloudCredential, error) {
+	var credentials []*CloudCredential
+	err := impl.dbConnection.Model(&credentials).
+		Where("active = ?", true).
+		Order("name").
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting cloud credentials", "err", err)
+		return nil, err
+	}
+	return credentials, nil
+}
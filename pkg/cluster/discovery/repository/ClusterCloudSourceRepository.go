@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ClusterCloudSource links a cluster to the cloud credential it was imported with
+type ClusterCloudSource struct {
+	tableName               struct{}  `sql:"cluster_cloud_source" pg:",discard_unknown_columns"`
+	Id                      int       `sql:"id,pk"`
+	ClusterId               int       `sql:"cluster_id,notnull"`
+	CloudCredentialId       int       `sql:"cloud_credential_id,notnull"`
+	ProviderClusterId       string    `sql:"provider_cluster_id,notnull"`
+	ServiceAccountNamespace string    `sql:"service_account_namespace,notnull"`
+	ServiceAccountName      string    `sql:"service_account_name,notnull"`
+	TokenExpiresAt          time.Time `sql:"token_expires_at"`
+	LastRefreshedOn         time.Time `sql:"last_refreshed_on"`
+	RefreshError            string    `sql:"refresh_error"`
+	Active                  bool      `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ClusterCloudSourceRepository interface {
+	Save(source *ClusterCloudSource) error
+	Update(source *ClusterCloudSource) error
+	FindByClusterId(clusterId int) (*ClusterCloudSource, error)
+	FindByCloudCredentialId(cloudCredentialId int) ([]*ClusterCloudSource, error)
+	// FindAllExpiringBefore returns the sources of active clusters whose token expires before expiresBefore
+	FindAllExpiringBefore(expiresBefore time.Time) ([]*ClusterCloudSource, error)
+}
+
+type ClusterCloudSourceRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewClusterCloudSourceRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ClusterCloudSourceRepositoryImpl {
+	return &ClusterCloudSourceRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ClusterCloudSourceRepositoryImpl) Save(source *ClusterCloudSource) error {
+	return impl.dbConnection.Insert(source)
+}
+
+func (impl *ClusterCloudSourceRepositoryImpl) Update(source *ClusterCloudSource) error {
+	return impl.dbConnection.Update(source)
+}
+
+func (impl *ClusterCloudSourceRepositoryImpl) FindByClusterId(clusterId int) (*ClusterCloudSource, error) {
+	source := &ClusterCloudSource{}
+	err := impl.dbConnection.Model(source).
+		Where("cluster_id = ?", clusterId).
+		Where("active = ?", true).
+		Select()
+	return source, err
+}
+
+func (impl *ClusterCloudSourceRepositoryImpl) FindByCloudCredentialId(cloudCredentialId int) ([]*ClusterCloudSource, error) {
+	var sources []*ClusterCloudSource
+	err := impl.dbConnection.Model(&sources).
+		Join("INNER JOIN cluster c ON c.id = cluster_cloud_source.cluster_id").
+		Where("cluster_cloud_source.cloud_credential_id = ?", cloudCredentialId).
+		Where("cluster_cloud_source.active = ?", true).
+		Where("c.active = ?", true).
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting cluster cloud sources", "cloudCredentialId", cloudCredentialId, "err", err)
+		return nil, err
+	}
+	return sources, nil
+}
+
+func (impl *ClusterCloudSourceRepositoryImpl) FindAllExpiringBefore(expiresBefore time.Time) ([]*ClusterCloudSource, error) {
+	var sources []*ClusterCloudSource
+	err := impl.dbConnection.Model(&sources).
+		Join("INNER JOIN cluster c ON c.id = cluster_cloud_source.cluster_id").
+		Where("cluster_cloud_source.active = ?", true).
+		Where("c.active = ?", true).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("cluster_cloud_source.token_expires_at IS NULL").
+				WhereOr("cluster_cloud_source.token_expires_at < ?", expiresBefore)
+			return q, nil
+		}).
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting expiring cluster cloud sources", "err", err)
+		return nil, err
+	}
+	return sources, nil
+}
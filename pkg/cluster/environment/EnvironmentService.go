@@ -25,8 +25,10 @@ import (
 	bean4 "github.com/devtron-labs/devtron/pkg/cluster/bean"
 	adapter2 "github.com/devtron-labs/devtron/pkg/cluster/environment/adapter"
 	bean2 "github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
+	"net/http"
 	"strconv"
 	"strings"
 	"sync"
@@ -83,6 +85,7 @@ type EnvironmentServiceImpl struct {
 	attributesRepository repository2.AttributesRepository
 	clusterReadService   read.ClusterReadService
 	grafanaClient        grafana.GrafanaClient
+	namespaceSpecService namespaceSpec.NamespaceSpecService
 }
 
 func NewEnvironmentServiceImpl(environmentRepository repository.EnvironmentRepository,
@@ -91,7 +94,8 @@ func NewEnvironmentServiceImpl(environmentRepository repository.EnvironmentRepos
 	//  propertiesConfigService pipeline.PropertiesConfigService,
 	userAuthService user.UserAuthService, attributesRepository repository2.AttributesRepository,
 	clusterReadService read.ClusterReadService,
-	grafanaClient grafana.GrafanaClient) *EnvironmentServiceImpl {
+	grafanaClient grafana.GrafanaClient,
+	namespaceSpecService namespaceSpec.NamespaceSpecService) *EnvironmentServiceImpl {
 	return &EnvironmentServiceImpl{
 		environmentRepository: environmentRepository,
 		logger:                logger,
@@ -103,6 +107,7 @@ func NewEnvironmentServiceImpl(environmentRepository repository.EnvironmentRepos
 		attributesRepository: attributesRepository,
 		clusterReadService:   clusterReadService,
 		grafanaClient:        grafanaClient,
+		namespaceSpecService: namespaceSpecService,
 	}
 }
 
@@ -125,6 +130,9 @@ func (impl EnvironmentServiceImpl) GetDataSourceName(bean *bean2.EnvironmentBean
 }
 
 func (impl EnvironmentServiceImpl) Create(mappings *bean2.EnvironmentBean, userId int32) (*bean2.EnvironmentBean, error) {
+	if err := impl.validateNamespaceSpec(mappings); err != nil {
+		return nil, err
+	}
 	existingEnvs, err := impl.environmentRepository.FindByClusterId(mappings.ClusterId)
 	if err != nil && !util.IsErrNoRows(err) {
 		impl.logger.Errorw("error while fetch", "err", err)
@@ -177,6 +185,11 @@ func (impl EnvironmentServiceImpl) Create(mappings *bean2.EnvironmentBean, userI
 		}
 
 	}
+	if mappings.NamespaceSpec != nil {
+		if _, err = impl.namespaceSpecService.SaveNamespaceSpec(model.Id, mappings.NamespaceSpec, userId); err != nil {
+			impl.logger.Errorw("error in saving namespace spec", "envId", model.Id, "err", err)
+		}
+	}
 
 	//ignore grafana if no prometheus url found
 	if len(clusterBean.PrometheusUrl) > 0 {
@@ -265,6 +278,9 @@ func (impl EnvironmentServiceImpl) Update(mappings *bean2.EnvironmentBean, userI
 		impl.logger.Errorw("error in finding environment for update", "err", err)
 		return mappings, err
 	}
+	if err = impl.validateNamespaceSpec(mappings); err != nil {
+		return nil, err
+	}
 	/*isNamespaceChange := false
 	if model.Namespace != mappings.Namespace {
 		isNamespaceChange = true
@@ -322,6 +338,11 @@ func (impl EnvironmentServiceImpl) Update(mappings *bean2.EnvironmentBean, userI
 		impl.logger.Errorw("error in updating environment", "err", err)
 		return mappings, err
 	}
+	if mappings.NamespaceSpec != nil {
+		if _, err = impl.namespaceSpecService.SaveNamespaceSpec(model.Id, mappings.NamespaceSpec, userId); err != nil {
+			impl.logger.Errorw("error in saving namespace spec", "envId", model.Id, "err", err)
+		}
+	}
 
 	mappings.Id = model.Id
 	return mappings, nil
@@ -472,6 +493,21 @@ func (impl EnvironmentServiceImpl) validateNamespaces(namespace string, envs []*
 	return nil
 }
 
+// validateNamespaceSpec rejects an invalid namespace spec before the environment is saved, the spec needs a namespace to manage
+func (impl EnvironmentServiceImpl) validateNamespaceSpec(mappings *bean2.EnvironmentBean) error {
+	if mappings.NamespaceSpec == nil {
+		return nil
+	}
+	if len(mappings.Namespace) == 0 {
+		return util.NewApiError(http.StatusBadRequest, "namespace is required to manage it by a namespace spec", "namespace spec without namespace")
+	}
+	if err := mappings.NamespaceSpec.Validate(); err != nil {
+		impl.logger.Errorw("invalid namespace spec", "env", mappings.Environment, "err", err)
+		return util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	return nil
+}
+
 func (impl EnvironmentServiceImpl) FindByIds(ids []*int) ([]*bean2.EnvironmentBean, error) {
 	models, err := impl.environmentRepository.FindByIds(ids)
 	if err != nil {
@@ -727,6 +763,11 @@ func (impl EnvironmentServiceImpl) Delete(deleteReq *bean2.EnvironmentBean, user
 		impl.logger.Errorw("error in deleting environment", "envId", deleteReq.Id, "envName", deleteReq.Environment)
 		return err
 	}
+	err = impl.namespaceSpecService.DeactivateNamespaceSpec(tx, deleteReq.Id, userId)
+	if err != nil {
+		impl.logger.Errorw("error in deleting namespace spec", "envId", deleteReq.Id, "err", err)
+		return err
+	}
 	//deleting auth roles entries for this environment
 	err = impl.userAuthService.DeleteRoles(bean.ENV_TYPE, deleteRequest.Name, tx, existingEnv.EnvironmentIdentifier, "")
 	if err != nil {
@@ -16,6 +16,10 @@
 
 package bean
 
+import (
+	namespaceSpecBean "github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec/bean"
+)
+
 type EnvironmentBean struct {
 	Id                     int               `json:"id,omitempty" validate:"number"`
 	Environment            string            `json:"environment_name,omitempty" validate:"required,max=50"`
@@ -40,6 +44,8 @@ type EnvironmentBean struct {
 	ClusterKeyData         string            `json:"-"`
 	ClusterCertData        string            `json:"-"`
 	DataSourceId           int               `json:"-"`
+	// NamespaceSpec is reconciled into the namespace on create and update when given, it is managed by the namespace spec apis after
+	NamespaceSpec *namespaceSpecBean.NamespaceSpec `json:"namespaceSpec,omitempty"`
 }
 
 type EnvDto struct {
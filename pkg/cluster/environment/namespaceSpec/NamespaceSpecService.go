@@ -0,0 +1,488 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package namespaceSpec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	k8s2 "github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/util"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec/adapter"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec/helper"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec/repository"
+	environmentRepository "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster/read"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cronUtil "github.com/devtron-labs/devtron/util/cron"
+	"github.com/gammazero/workerpool"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"net/http"
+	"time"
+)
+
+type NamespaceSpecConfig struct {
+	ReconcileIntervalMins int `env:"NAMESPACE_SPEC_RECONCILE_INTERVAL_MINS" envDefault:"10" description:"Interval at which the namespace specs of the environments are reconciled and out of band changes reverted, 0 disables the loop"`
+	ReconcileConcurrency  int `env:"NAMESPACE_SPEC_RECONCILE_CONCURRENCY" envDefault:"5" description:"Number of namespace specs reconciled in parallel"`
+	ReconcileTimeoutSecs  int `env:"NAMESPACE_SPEC_RECONCILE_TIMEOUT_SECS" envDefault:"30" description:"Timeout of reconciling the namespace spec of an environment"`
+}
+
+func GetNamespaceSpecConfig() (*NamespaceSpecConfig, error) {
+	cfg := &NamespaceSpecConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+type NamespaceSpecService interface {
+	// SaveNamespaceSpec saves the spec of the environment and reconciles it into the cluster, a failed reconcile is
+	// recorded in the status of the spec and retried by the reconcile loop
+	SaveNamespaceSpec(envId int, spec *bean.NamespaceSpec, userId int32) (*bean.NamespaceSpecDto, error)
+	// GetNamespaceSpec returns the spec of the environment with its status and the live usage of its quota
+	GetNamespaceSpec(ctx context.Context, envId int) (*bean.NamespaceSpecDto, error)
+	// DeleteNamespaceSpec stops managing the namespace, the objects and metadata of the spec are removed from it
+	DeleteNamespaceSpec(ctx context.Context, envId int, userId int32) error
+	// DeactivateNamespaceSpec stops managing the namespace of a deleted environment, the namespace is left as is
+	DeactivateNamespaceSpec(tx *pg.Tx, envId int, userId int32) error
+	ReconcileNamespaceSpec(ctx context.Context, envId int) (*bean.NamespaceSpecDto, error)
+	// ReconcileAll reconciles the specs of all the environments, changes found are recorded as drift
+	ReconcileAll()
+}
+
+type NamespaceSpecServiceImpl struct {
+	logger                  *zap.SugaredLogger
+	environmentRepository   environmentRepository.EnvironmentRepository
+	clusterReadService      read.ClusterReadService
+	K8sUtil                 *k8s2.K8sServiceImpl
+	namespaceSpecRepository repository.NamespaceSpecRepository
+	config                  *NamespaceSpecConfig
+}
+
+func NewNamespaceSpecServiceImpl(logger *zap.SugaredLogger,
+	environmentRepository environmentRepository.EnvironmentRepository,
+	clusterReadService read.ClusterReadService,
+	K8sUtil *k8s2.K8sServiceImpl,
+	namespaceSpecRepository repository.NamespaceSpecRepository,
+	config *NamespaceSpecConfig,
+	cronLogger *cronUtil.CronLoggerImpl) (*NamespaceSpecServiceImpl, error) {
+	impl := &NamespaceSpecServiceImpl{
+		logger:                  logger,
+		environmentRepository:   environmentRepository,
+		clusterReadService:      clusterReadService,
+		K8sUtil:                 K8sUtil,
+		namespaceSpecRepository: namespaceSpecRepository,
+		config:                  config,
+	}
+	if config.ReconcileIntervalMins > 0 {
+		reconcileCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+		_, err := reconcileCron.AddFunc(fmt.Sprintf("@every %dm", config.ReconcileIntervalMins), impl.ReconcileAll)
+		if err != nil {
+			logger.Errorw("error in adding namespace spec reconcile cron", "err", err)
+			return nil, err
+		}
+		reconcileCron.Start()
+	}
+	return impl, nil
+}
+
+func (impl *NamespaceSpecServiceImpl) SaveNamespaceSpec(envId int, spec *bean.NamespaceSpec, userId int32) (*bean.NamespaceSpecDto, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	environment, err := impl.getManageableEnvironment(envId)
+	if err != nil {
+		return nil, err
+	}
+	specJson, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	model, err := impl.namespaceSpecRepository.FindActiveByEnvironmentId(envId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting namespace spec", "envId", envId, "err", err)
+		return nil, err
+	}
+	model.Spec = string(specJson)
+	model.Status = bean.StatusPending
+	model.Message = ""
+	if model.Id > 0 {
+		model.UpdateAuditLog(userId)
+		err = impl.namespaceSpecRepository.Update(model)
+	} else {
+		model.EnvironmentId = envId
+		model.Active = true
+		model.AuditLog = sql.NewDefaultAuditLog(userId)
+		err = impl.namespaceSpecRepository.Save(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving namespace spec", "envId", envId, "err", err)
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(impl.config.ReconcileTimeoutSecs)*time.Second)
+	defer cancel()
+	return impl.reconcile(ctx, model, environment)
+}
+
+func (impl *NamespaceSpecServiceImpl) GetNamespaceSpec(ctx context.Context, envId int) (*bean.NamespaceSpecDto, error) {
+	model, environment, err := impl.getNamespaceSpec(envId)
+	if err != nil {
+		return nil, err
+	}
+	dto, err := adapter.GetNamespaceSpecDto(model, environment.Namespace)
+	if err != nil {
+		impl.logger.Errorw("error in parsing namespace spec", "envId", envId, "err", err)
+		return nil, err
+	}
+	if dto.Spec.ResourceQuota == nil {
+		return dto, nil
+	}
+	clientSet, err := impl.getClientSet(environment.ClusterId)
+	if err != nil {
+		impl.logger.Warnw("error in getting client of cluster for quota usage", "envId", envId, "err", err)
+		return dto, nil
+	}
+	quota, err := clientSet.CoreV1().ResourceQuotas(environment.Namespace).Get(ctx, bean.ResourceQuotaName, metav1.GetOptions{})
+	if err != nil {
+		impl.logger.Warnw("error in getting resource quota for usage", "envId", envId, "err", err)
+		return dto, nil
+	}
+	dto.QuotaUsage = helper.GetQuotaUsage(quota)
+	return dto, nil
+}
+
+func (impl *NamespaceSpecServiceImpl) DeleteNamespaceSpec(ctx context.Context, envId int, userId int32) error {
+	model, environment, err := impl.getNamespaceSpec(envId)
+	if err != nil {
+		return err
+	}
+	clientSet, err := impl.getClientSet(environment.ClusterId)
+	if err != nil {
+		return err
+	}
+	_, err = clientSet.CoreV1().Namespaces().Get(ctx, environment.Namespace, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		impl.logger.Errorw("error in getting namespace", "envId", envId, "namespace", environment.Namespace, "err", err)
+		return err
+	} else if err == nil {
+		// applying an empty spec removes what the spec set
+		if _, err = impl.apply(ctx, clientSet, envId, environment.Namespace, &bean.NamespaceSpec{}, false); err != nil {
+			impl.logger.Errorw("error in removing namespace spec from cluster", "envId", envId, "err", err)
+			return err
+		}
+	}
+	tx, err := impl.environmentRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err = impl.namespaceSpecRepository.DeactivateByEnvironmentId(tx, model.EnvironmentId, userId); err != nil {
+		impl.logger.Errorw("error in deleting namespace spec", "envId", envId, "err", err)
+		return err
+	}
+	return tx.Commit()
+}
+
+func (impl *NamespaceSpecServiceImpl) DeactivateNamespaceSpec(tx *pg.Tx, envId int, userId int32) error {
+	return impl.namespaceSpecRepository.DeactivateByEnvironmentId(tx, envId, userId)
+}
+
+func (impl *NamespaceSpecServiceImpl) ReconcileNamespaceSpec(ctx context.Context, envId int) (*bean.NamespaceSpecDto, error) {
+	model, environment, err := impl.getNamespaceSpec(envId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.reconcile(ctx, model, environment)
+}
+
+func (impl *NamespaceSpecServiceImpl) ReconcileAll() {
+	models, err := impl.namespaceSpecRepository.FindAllActive()
+	if err != nil {
+		return
+	}
+	wp := workerpool.New(max(impl.config.ReconcileConcurrency, 1))
+	for _, model := range models {
+		environment, err := impl.environmentRepository.FindById(model.EnvironmentId)
+		if err != nil {
+			impl.logger.Errorw("error in getting environment of namespace spec", "envId", model.EnvironmentId, "err", err)
+			continue
+		} else if environment.IsVirtualEnvironment || len(environment.Namespace) == 0 {
+			continue
+		}
+		wp.Submit(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(impl.config.ReconcileTimeoutSecs)*time.Second)
+			defer cancel()
+			if _, err := impl.reconcile(ctx, model, environment); err != nil {
+				impl.logger.Errorw("error in reconciling namespace spec", "envId", model.EnvironmentId, "err", err)
+			}
+		})
+	}
+	wp.StopWait()
+}
+
+// getManageableEnvironment returns the environment if its namespace can be managed by a spec
+func (impl *NamespaceSpecServiceImpl) getManageableEnvironment(envId int) (*environmentRepository.Environment, error) {
+	environment, err := impl.environmentRepository.FindById(envId)
+	if err != nil {
+		impl.logger.Errorw("error in getting environment", "envId", envId, "err", err)
+		if util.IsErrNoRows(err) {
+			return nil, util.NewApiError(http.StatusNotFound, "environment not found", err.Error())
+		}
+		return nil, err
+	}
+	if environment.IsVirtualEnvironment {
+		return nil, util.NewApiError(http.StatusBadRequest, "namespace of a virtual environment can not be managed", "virtual environment")
+	} else if len(environment.Namespace) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "environment has no namespace", "environment without namespace")
+	}
+	return environment, nil
+}
+
+func (impl *NamespaceSpecServiceImpl) getNamespaceSpec(envId int) (*repository.EnvironmentNamespaceSpec, *environmentRepository.Environment, error) {
+	model, err := impl.namespaceSpecRepository.FindActiveByEnvironmentId(envId)
+	if err != nil {
+		impl.logger.Errorw("error in getting namespace spec", "envId", envId, "err", err)
+		if util.IsErrNoRows(err) {
+			return nil, nil, util.NewApiError(http.StatusNotFound, "environment has no namespace spec", err.Error())
+		}
+		return nil, nil, err
+	}
+	environment, err := impl.getManageableEnvironment(envId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return model, environment, nil
+}
+
+func (impl *NamespaceSpecServiceImpl) getClientSet(clusterId int) (*kubernetes.Clientset, error) {
+	cluster, err := impl.clusterReadService.FindById(clusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting cluster", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	return impl.getClientSetOfCluster(cluster)
+}
+
+func (impl *NamespaceSpecServiceImpl) getClientSetOfCluster(cluster *clusterBean.ClusterBean) (*kubernetes.Clientset, error) {
+	_, _, clientSet, err := impl.K8sUtil.GetK8sConfigAndClients(cluster.GetClusterConfig())
+	if err != nil {
+		impl.logger.Errorw("error in getting client of cluster", "clusterId", cluster.Id, "err", err)
+		return nil, err
+	}
+	return clientSet, nil
+}
+
+// reconcile applies the spec and records the result in its status. Changes are recorded as drift once the spec has
+// been applied, they are made out of band then.
+func (impl *NamespaceSpecServiceImpl) reconcile(ctx context.Context, model *repository.EnvironmentNamespaceSpec, environment *environmentRepository.Environment) (*bean.NamespaceSpecDto, error) {
+	spec, err := adapter.GetNamespaceSpec(model)
+	if err != nil {
+		impl.logger.Errorw("error in parsing namespace spec", "envId", model.EnvironmentId, "err", err)
+		return nil, err
+	}
+	var drift []string
+	clientSet, err := impl.getClientSet(environment.ClusterId)
+	if err == nil {
+		drift, err = impl.apply(ctx, clientSet, model.EnvironmentId, environment.Namespace, spec, model.Status.DetectsDrift())
+	}
+	now := time.Now()
+	model.LastReconciledOn = &now
+	switch {
+	case err != nil:
+		impl.logger.Errorw("error in reconciling namespace spec", "envId", model.EnvironmentId, "namespace", environment.Namespace, "err", err)
+		model.Status, model.Message = bean.StatusFailed, err.Error()
+		err = impl.namespaceSpecRepository.UpdateStatus(model.Id, model.Status, model.Message, "", nil)
+	case len(drift) > 0:
+		impl.logger.Infow("reverted out of band changes of namespace", "envId", model.EnvironmentId, "namespace", environment.Namespace, "drift", drift)
+		driftJson, _ := json.Marshal(drift)
+		model.Status, model.Message, model.Drift, model.DriftDetectedOn = bean.StatusDrifted, "", string(driftJson), &now
+		err = impl.namespaceSpecRepository.UpdateStatus(model.Id, model.Status, model.Message, model.Drift, model.DriftDetectedOn)
+	default:
+		model.Status, model.Message = bean.StatusSynced, ""
+		err = impl.namespaceSpecRepository.UpdateStatus(model.Id, model.Status, model.Message, "", nil)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in updating namespace spec status", "envId", model.EnvironmentId, "err", err)
+		return nil, err
+	}
+	return adapter.GetNamespaceSpecDto(model, environment.Namespace)
+}
+
+// apply makes the namespace match the spec, the objects of the environment not in the spec are removed. Returns the
+// changes made when detectDrift.
+func (impl *NamespaceSpecServiceImpl) apply(ctx context.Context, clientSet *kubernetes.Clientset, envId int, namespace string, spec *bean.NamespaceSpec, detectDrift bool) ([]string, error) {
+	var changes []string
+	namespaceChange, err := impl.applyNamespace(ctx, clientSet, namespace, spec)
+	if err != nil {
+		return nil, err
+	} else if len(namespaceChange) > 0 {
+		changes = append(changes, namespaceChange)
+	}
+	selector := metav1.ListOptions{LabelSelector: helper.ManagedSelector(envId)}
+
+	var liveNames []string
+	if quotas, err := clientSet.CoreV1().ResourceQuotas(namespace).List(ctx, selector); err != nil {
+		return nil, err
+	} else {
+		for _, quota := range quotas.Items {
+			liveNames = append(liveNames, quota.Name)
+		}
+	}
+	var quotas []*corev1.ResourceQuota
+	if quota := helper.BuildResourceQuota(envId, namespace, spec); quota != nil {
+		quotas = append(quotas, quota)
+	}
+	quotaChanges, err := applyObjects(ctx, clientSet.CoreV1().ResourceQuotas(namespace), "ResourceQuota", envId, quotas, liveNames, helper.ResourceQuotaMatches, nil)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, quotaChanges...)
+
+	liveNames = nil
+	if limitRanges, err := clientSet.CoreV1().LimitRanges(namespace).List(ctx, selector); err != nil {
+		return nil, err
+	} else {
+		for _, limitRange := range limitRanges.Items {
+			liveNames = append(liveNames, limitRange.Name)
+		}
+	}
+	var limitRanges []*corev1.LimitRange
+	if limitRange := helper.BuildLimitRange(envId, namespace, spec); limitRange != nil {
+		limitRanges = append(limitRanges, limitRange)
+	}
+	limitRangeChanges, err := applyObjects(ctx, clientSet.CoreV1().LimitRanges(namespace), "LimitRange", envId, limitRanges, liveNames, helper.LimitRangeMatches, nil)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, limitRangeChanges...)
+
+	liveNames = nil
+	if policies, err := clientSet.NetworkingV1().NetworkPolicies(namespace).List(ctx, selector); err != nil {
+		return nil, err
+	} else {
+		for _, policy := range policies.Items {
+			liveNames = append(liveNames, policy.Name)
+		}
+	}
+	policyChanges, err := applyObjects(ctx, clientSet.NetworkingV1().NetworkPolicies(namespace), "NetworkPolicy", envId,
+		helper.BuildNetworkPolicies(envId, namespace, spec), liveNames, helper.NetworkPolicyMatches, nil)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, policyChanges...)
+
+	liveNames = nil
+	if roleBindings, err := clientSet.RbacV1().RoleBindings(namespace).List(ctx, selector); err != nil {
+		return nil, err
+	} else {
+		for _, roleBinding := range roleBindings.Items {
+			liveNames = append(liveNames, roleBinding.Name)
+		}
+	}
+	roleBindingChanges, err := applyObjects(ctx, clientSet.RbacV1().RoleBindings(namespace), "RoleBinding", envId,
+		helper.BuildRoleBindings(envId, namespace, spec), liveNames, helper.RoleBindingMatches, helper.RoleBindingNeedsRecreate)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, roleBindingChanges...)
+	if !detectDrift {
+		return nil, nil
+	}
+	return changes, nil
+}
+
+// applyNamespace creates the namespace with the metadata of the spec or sets the metadata on the existing namespace,
+// returns the change made if any
+func (impl *NamespaceSpecServiceImpl) applyNamespace(ctx context.Context, clientSet *kubernetes.Clientset, name string, spec *bean.NamespaceSpec) (string, error) {
+	namespace, err := clientSet.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		helper.ApplyMetadata(namespace, spec)
+		if _, err = clientSet.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{}); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Namespace/%s missing", name), nil
+	} else if err != nil {
+		return "", err
+	}
+	if !helper.ApplyMetadata(namespace, spec) {
+		return "", nil
+	}
+	if _, err = clientSet.CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{}); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Namespace/%s metadata modified", name), nil
+}
+
+type objectClient[T metav1.Object] interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (T, error)
+	Create(ctx context.Context, obj T, opts metav1.CreateOptions) (T, error)
+	Update(ctx context.Context, obj T, opts metav1.UpdateOptions) (T, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+}
+
+// applyObjects creates or updates the desired objects, recreating the ones needsRecreate tells cannot be updated, and
+// deletes the live objects of the environment not desired. Returns the changes made to the desired objects.
+func applyObjects[T metav1.Object](ctx context.Context, client objectClient[T], kind string, envId int, desired []T, liveNames []string,
+	matches func(desired, live T) bool, needsRecreate func(desired, live T) bool) ([]string, error) {
+	var changes []string
+	desiredNames := make(map[string]bool, len(desired))
+	for _, object := range desired {
+		desiredNames[object.GetName()] = true
+		live, err := client.Get(ctx, object.GetName(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			if _, err = client.Create(ctx, object, metav1.CreateOptions{}); err != nil {
+				return nil, err
+			}
+			changes = append(changes, fmt.Sprintf("%s/%s missing", kind, object.GetName()))
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if matches(object, live) && helper.HasManagedLabels(live, envId) {
+			continue
+		}
+		if needsRecreate != nil && needsRecreate(object, live) {
+			if err = client.Delete(ctx, object.GetName(), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			_, err = client.Create(ctx, object, metav1.CreateOptions{})
+		} else {
+			object.SetResourceVersion(live.GetResourceVersion())
+			_, err = client.Update(ctx, object, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, fmt.Sprintf("%s/%s modified", kind, object.GetName()))
+	}
+	for _, name := range liveNames {
+		if desiredNames[name] {
+			continue
+		}
+		if err := client.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
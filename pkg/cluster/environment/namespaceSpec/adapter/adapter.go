@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec/repository"
+)
+
+func GetNamespaceSpec(model *repository.EnvironmentNamespaceSpec) (*bean.NamespaceSpec, error) {
+	spec := &bean.NamespaceSpec{}
+	err := json.Unmarshal([]byte(model.Spec), spec)
+	return spec, err
+}
+
+func GetNamespaceSpecDto(model *repository.EnvironmentNamespaceSpec, namespace string) (*bean.NamespaceSpecDto, error) {
+	spec, err := GetNamespaceSpec(model)
+	if err != nil {
+		return nil, err
+	}
+	dto := &bean.NamespaceSpecDto{
+		EnvironmentId:    model.EnvironmentId,
+		Namespace:        namespace,
+		Spec:             spec,
+		Status:           model.Status,
+		Message:          model.Message,
+		DriftDetectedOn:  model.DriftDetectedOn,
+		LastReconciledOn: model.LastReconciledOn,
+	}
+	if len(model.Drift) > 0 {
+		if err = json.Unmarshal([]byte(model.Drift), &dto.Drift); err != nil {
+			return nil, err
+		}
+	}
+	return dto, nil
+}
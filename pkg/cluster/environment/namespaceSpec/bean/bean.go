@@ -0,0 +1,225 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bean
+
+import (
+	"fmt"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"strings"
+	"time"
+)
+
+type NetworkPolicyPreset string
+
+const (
+	// NetworkPolicyDenyAllIngress denies the traffic into every pod of the namespace
+	NetworkPolicyDenyAllIngress NetworkPolicyPreset = "deny-all-ingress"
+	// NetworkPolicyDenyAllEgress denies the traffic out of every pod of the namespace, DNS included
+	NetworkPolicyDenyAllEgress NetworkPolicyPreset = "deny-all-egress"
+	// NetworkPolicyAllowSameNamespace allows the traffic between the pods of the namespace
+	NetworkPolicyAllowSameNamespace NetworkPolicyPreset = "allow-same-namespace"
+	// NetworkPolicyAllowDnsEgress allows the pods of the namespace to resolve names, to be used with deny-all-egress
+	NetworkPolicyAllowDnsEgress NetworkPolicyPreset = "allow-dns-egress"
+)
+
+func (p NetworkPolicyPreset) IsValid() bool {
+	switch p {
+	case NetworkPolicyDenyAllIngress, NetworkPolicyDenyAllEgress, NetworkPolicyAllowSameNamespace, NetworkPolicyAllowDnsEgress:
+		return true
+	}
+	return false
+}
+
+var NetworkPolicyPresets = []NetworkPolicyPreset{NetworkPolicyDenyAllIngress, NetworkPolicyDenyAllEgress, NetworkPolicyAllowSameNamespace, NetworkPolicyAllowDnsEgress}
+
+const (
+	SubjectKindUser           = "User"
+	SubjectKindGroup          = "Group"
+	SubjectKindServiceAccount = "ServiceAccount"
+)
+
+type Status string
+
+const (
+	// StatusPending is the status of a spec not reconciled since it was saved
+	StatusPending Status = "Pending"
+	StatusSynced  Status = "Synced"
+	// StatusDrifted is the status of a spec whose objects were changed out of band and restored by the last reconcile
+	StatusDrifted Status = "Drifted"
+	StatusFailed  Status = "Failed"
+)
+
+// DetectsDrift tells whether the objects are expected to match the spec, changes found by the next reconcile are out of band then
+func (s Status) DetectsDrift() bool {
+	return s == StatusSynced || s == StatusDrifted
+}
+
+const (
+	// ManagedByLabel and EnvironmentIdLabel mark the objects of the namespace owned by the spec of an environment
+	ManagedByLabel      = "app.kubernetes.io/managed-by"
+	ManagedByValue      = "devtron"
+	EnvironmentIdLabel  = "devtron.ai/environment-id"
+	ReservedLabelPrefix = "devtron.ai/"
+	// ManagedLabelsAnnotation and ManagedAnnotationsAnnotation hold the keys of the namespace metadata set by the spec,
+	// keys removed from the spec are removed from the namespace
+	ManagedLabelsAnnotation      = "devtron.ai/managed-labels"
+	ManagedAnnotationsAnnotation = "devtron.ai/managed-annotations"
+
+	ResourceQuotaName       = "devtron-quota"
+	LimitRangeName          = "devtron-limits"
+	NetworkPolicyNamePrefix = "devtron-"
+	RoleBindingNamePrefix   = "devtron-"
+)
+
+type ResourceQuotaSpec struct {
+	// Hard are the limits of the namespace by resource name, requests.cpu: 4, limits.memory: 8Gi, pods: 20
+	Hard map[string]string `json:"hard"`
+}
+
+// LimitRangeSpec are the limits of the containers of the namespace by resource name
+type LimitRangeSpec struct {
+	Default        map[string]string `json:"default,omitempty"`
+	DefaultRequest map[string]string `json:"defaultRequest,omitempty"`
+	Max            map[string]string `json:"max,omitempty"`
+	Min            map[string]string `json:"min,omitempty"`
+}
+
+type SubjectSpec struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"` // of service accounts, the namespace of the environment by default
+}
+
+// RoleBindingSpec binds a cluster role in the namespace
+type RoleBindingSpec struct {
+	Name        string         `json:"name"`
+	ClusterRole string         `json:"clusterRole"`
+	Subjects    []*SubjectSpec `json:"subjects"`
+}
+
+type NamespaceSpec struct {
+	Labels          map[string]string     `json:"labels,omitempty"`
+	Annotations     map[string]string     `json:"annotations,omitempty"`
+	ResourceQuota   *ResourceQuotaSpec    `json:"resourceQuota,omitempty"`
+	LimitRange      *LimitRangeSpec       `json:"limitRange,omitempty"`
+	NetworkPolicies []NetworkPolicyPreset `json:"networkPolicies,omitempty"`
+	RoleBindings    []*RoleBindingSpec    `json:"roleBindings,omitempty"`
+}
+
+func (s *NamespaceSpec) Validate() error {
+	for key, value := range s.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid label %s: %s", key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid value of label %s: %s", key, strings.Join(errs, ", "))
+		}
+		if strings.HasPrefix(key, ReservedLabelPrefix) {
+			return fmt.Errorf("label %s uses the reserved prefix %s", key, ReservedLabelPrefix)
+		}
+	}
+	for key := range s.Annotations {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid annotation %s: %s", key, strings.Join(errs, ", "))
+		}
+		if strings.HasPrefix(key, ReservedLabelPrefix) {
+			return fmt.Errorf("annotation %s uses the reserved prefix %s", key, ReservedLabelPrefix)
+		}
+	}
+	if s.ResourceQuota != nil {
+		if len(s.ResourceQuota.Hard) == 0 {
+			return fmt.Errorf("resource quota without limits")
+		}
+		if err := validateQuantities("resource quota", s.ResourceQuota.Hard); err != nil {
+			return err
+		}
+	}
+	if s.LimitRange != nil {
+		for name, quantities := range map[string]map[string]string{"default": s.LimitRange.Default, "defaultRequest": s.LimitRange.DefaultRequest, "max": s.LimitRange.Max, "min": s.LimitRange.Min} {
+			if err := validateQuantities("limit range "+name, quantities); err != nil {
+				return err
+			}
+		}
+	}
+	presets := make(map[NetworkPolicyPreset]bool, len(s.NetworkPolicies))
+	for _, preset := range s.NetworkPolicies {
+		if !preset.IsValid() {
+			return fmt.Errorf("invalid network policy %s", preset)
+		} else if presets[preset] {
+			return fmt.Errorf("duplicate network policy %s", preset)
+		}
+		presets[preset] = true
+	}
+	names := make(map[string]bool, len(s.RoleBindings))
+	for _, roleBinding := range s.RoleBindings {
+		if roleBinding == nil {
+			return fmt.Errorf("empty role binding")
+		}
+		if errs := validation.IsDNS1123Subdomain(RoleBindingNamePrefix + roleBinding.Name); len(roleBinding.Name) == 0 || len(errs) > 0 {
+			return fmt.Errorf("invalid role binding name %s", roleBinding.Name)
+		} else if names[roleBinding.Name] {
+			return fmt.Errorf("duplicate role binding %s", roleBinding.Name)
+		}
+		names[roleBinding.Name] = true
+		if len(roleBinding.ClusterRole) == 0 {
+			return fmt.Errorf("cluster role of role binding %s is required", roleBinding.Name)
+		}
+		if len(roleBinding.Subjects) == 0 {
+			return fmt.Errorf("role binding %s without subjects", roleBinding.Name)
+		}
+		for _, subject := range roleBinding.Subjects {
+			if subject == nil || len(subject.Name) == 0 {
+				return fmt.Errorf("subject of role binding %s without name", roleBinding.Name)
+			}
+			switch subject.Kind {
+			case SubjectKindUser, SubjectKindGroup, SubjectKindServiceAccount:
+			default:
+				return fmt.Errorf("invalid subject kind %s of role binding %s", subject.Kind, roleBinding.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func validateQuantities(name string, quantities map[string]string) error {
+	for resourceName, quantity := range quantities {
+		if _, err := resource.ParseQuantity(quantity); err != nil {
+			return fmt.Errorf("invalid %s of %s %s: %s", resourceName, name, quantity, err.Error())
+		}
+	}
+	return nil
+}
+
+type QuotaUsage struct {
+	Resource       string  `json:"resource"`
+	Hard           string  `json:"hard"`
+	Used           string  `json:"used"`
+	UsedPercentage float64 `json:"usedPercentage"`
+}
+
+type NamespaceSpecDto struct {
+	EnvironmentId    int            `json:"environmentId"`
+	Namespace        string         `json:"namespace"`
+	Spec             *NamespaceSpec `json:"spec"`
+	Status           Status         `json:"status"`
+	Message          string         `json:"message,omitempty"`
+	Drift            []string       `json:"drift,omitempty"`
+	DriftDetectedOn  *time.Time     `json:"driftDetectedOn,omitempty"`
+	LastReconciledOn *time.Time     `json:"lastReconciledOn,omitempty"`
+	// QuotaUsage is the live usage of the resource quota, empty when the spec has no quota or the cluster is not reachable
+	QuotaUsage []*QuotaUsage `json:"quotaUsage,omitempty"`
+}
@@ -0,0 +1,237 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package helper
+
+import (
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec/bean"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ManagedLabels are the labels of the objects owned by the spec of the environment
+func ManagedLabels(envId int) map[string]string {
+	return map[string]string{
+		bean.ManagedByLabel:     bean.ManagedByValue,
+		bean.EnvironmentIdLabel: strconv.Itoa(envId),
+	}
+}
+
+// ManagedSelector selects the objects owned by the spec of the environment
+func ManagedSelector(envId int) string {
+	return bean.ManagedByLabel + "=" + bean.ManagedByValue + "," + bean.EnvironmentIdLabel + "=" + strconv.Itoa(envId)
+}
+
+func objectMeta(envId int, name, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: ManagedLabels(envId)}
+}
+
+func toResourceList(quantities map[string]string) corev1.ResourceList {
+	if len(quantities) == 0 {
+		return nil
+	}
+	resourceList := make(corev1.ResourceList, len(quantities))
+	for name, quantity := range quantities {
+		resourceList[corev1.ResourceName(name)] = resource.MustParse(quantity)
+	}
+	return resourceList
+}
+
+// BuildResourceQuota returns the quota of the spec, nil if the spec has none. The spec must be validated.
+func BuildResourceQuota(envId int, namespace string, spec *bean.NamespaceSpec) *corev1.ResourceQuota {
+	if spec.ResourceQuota == nil {
+		return nil
+	}
+	return &corev1.ResourceQuota{
+		ObjectMeta: objectMeta(envId, bean.ResourceQuotaName, namespace),
+		Spec:       corev1.ResourceQuotaSpec{Hard: toResourceList(spec.ResourceQuota.Hard)},
+	}
+}
+
+// BuildLimitRange returns the container limit range of the spec, nil if the spec has none. The spec must be validated.
+func BuildLimitRange(envId int, namespace string, spec *bean.NamespaceSpec) *corev1.LimitRange {
+	if spec.LimitRange == nil {
+		return nil
+	}
+	return &corev1.LimitRange{
+		ObjectMeta: objectMeta(envId, bean.LimitRangeName, namespace),
+		Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+			Type:           corev1.LimitTypeContainer,
+			Default:        toResourceList(spec.LimitRange.Default),
+			DefaultRequest: toResourceList(spec.LimitRange.DefaultRequest),
+			Max:            toResourceList(spec.LimitRange.Max),
+			Min:            toResourceList(spec.LimitRange.Min),
+		}}},
+	}
+}
+
+func BuildNetworkPolicies(envId int, namespace string, spec *bean.NamespaceSpec) []*networkingv1.NetworkPolicy {
+	policies := make([]*networkingv1.NetworkPolicy, 0, len(spec.NetworkPolicies))
+	for _, preset := range spec.NetworkPolicies {
+		policy := &networkingv1.NetworkPolicy{ObjectMeta: objectMeta(envId, bean.NetworkPolicyNamePrefix+string(preset), namespace)}
+		switch preset {
+		case bean.NetworkPolicyDenyAllIngress:
+			policy.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+		case bean.NetworkPolicyDenyAllEgress:
+			policy.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}
+		case bean.NetworkPolicyAllowSameNamespace:
+			policy.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+			policy.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}},
+			}}
+		case bean.NetworkPolicyAllowDnsEgress:
+			udp, tcp := corev1.ProtocolUDP, corev1.ProtocolTCP
+			dnsPort := intstr.FromInt32(53)
+			policy.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}
+			policy.Spec.Egress = []networkingv1.NetworkPolicyEgressRule{{
+				Ports: []networkingv1.NetworkPolicyPort{{Protocol: &udp, Port: &dnsPort}, {Protocol: &tcp, Port: &dnsPort}},
+				To:    []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}},
+			}}
+		}
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+func BuildRoleBindings(envId int, namespace string, spec *bean.NamespaceSpec) []*rbacv1.RoleBinding {
+	roleBindings := make([]*rbacv1.RoleBinding, 0, len(spec.RoleBindings))
+	for _, roleBindingSpec := range spec.RoleBindings {
+		roleBinding := &rbacv1.RoleBinding{
+			ObjectMeta: objectMeta(envId, bean.RoleBindingNamePrefix+roleBindingSpec.Name, namespace),
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: roleBindingSpec.ClusterRole},
+		}
+		for _, subjectSpec := range roleBindingSpec.Subjects {
+			subject := rbacv1.Subject{Kind: subjectSpec.Kind, Name: subjectSpec.Name}
+			if subjectSpec.Kind == bean.SubjectKindServiceAccount {
+				subject.Namespace = subjectSpec.Namespace
+				if len(subject.Namespace) == 0 {
+					subject.Namespace = namespace
+				}
+			} else {
+				// defaulted by the api server for users and groups
+				subject.APIGroup = rbacv1.GroupName
+			}
+			roleBinding.Subjects = append(roleBinding.Subjects, subject)
+		}
+		roleBindings = append(roleBindings, roleBinding)
+	}
+	return roleBindings
+}
+
+// HasManagedLabels tells whether the live object still carries the labels of the spec of the environment
+func HasManagedLabels(live metav1.Object, envId int) bool {
+	for key, value := range ManagedLabels(envId) {
+		if live.GetLabels()[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func ResourceQuotaMatches(desired, live *corev1.ResourceQuota) bool {
+	return equality.Semantic.DeepEqual(desired.Spec, live.Spec)
+}
+
+func LimitRangeMatches(desired, live *corev1.LimitRange) bool {
+	return equality.Semantic.DeepEqual(desired.Spec, live.Spec)
+}
+
+func NetworkPolicyMatches(desired, live *networkingv1.NetworkPolicy) bool {
+	return equality.Semantic.DeepEqual(desired.Spec, live.Spec)
+}
+
+func RoleBindingMatches(desired, live *rbacv1.RoleBinding) bool {
+	return equality.Semantic.DeepEqual(desired.RoleRef, live.RoleRef) && equality.Semantic.DeepEqual(desired.Subjects, live.Subjects)
+}
+
+// RoleBindingNeedsRecreate tells whether the live role binding is to be recreated, the role of a binding is immutable
+func RoleBindingNeedsRecreate(desired, live *rbacv1.RoleBinding) bool {
+	return !equality.Semantic.DeepEqual(desired.RoleRef, live.RoleRef)
+}
+
+// ApplyMetadata sets the labels and annotations of the spec on the namespace and removes the ones set by a previous spec
+// which are not in the spec anymore, returns whether the namespace changed
+func ApplyMetadata(namespace *corev1.Namespace, spec *bean.NamespaceSpec) bool {
+	if namespace.Labels == nil {
+		namespace.Labels = make(map[string]string)
+	}
+	if namespace.Annotations == nil {
+		namespace.Annotations = make(map[string]string)
+	}
+	changed := applyManagedKeys(namespace.Labels, namespace.Annotations, bean.ManagedLabelsAnnotation, spec.Labels)
+	changed = applyManagedKeys(namespace.Annotations, namespace.Annotations, bean.ManagedAnnotationsAnnotation, spec.Annotations) || changed
+	return changed
+}
+
+// applyManagedKeys sets desired on values, removing the keys listed in the managedKeysAnnotation which are not desired
+// anymore, and records the desired keys in the annotation
+func applyManagedKeys(values map[string]string, annotations map[string]string, managedKeysAnnotation string, desired map[string]string) bool {
+	changed := false
+	for _, key := range strings.Split(annotations[managedKeysAnnotation], ",") {
+		if _, ok := desired[key]; !ok && len(key) > 0 {
+			if _, exists := values[key]; exists {
+				delete(values, key)
+				changed = true
+			}
+		}
+	}
+	keys := make([]string, 0, len(desired))
+	for key, value := range desired {
+		if values[key] != value {
+			values[key] = value
+			changed = true
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	managedKeys := strings.Join(keys, ",")
+	if annotations[managedKeysAnnotation] != managedKeys {
+		if len(managedKeys) > 0 {
+			annotations[managedKeysAnnotation] = managedKeys
+		} else {
+			delete(annotations, managedKeysAnnotation)
+		}
+		changed = true
+	}
+	return changed
+}
+
+// GetQuotaUsage returns the usage of the quota by resource name
+func GetQuotaUsage(quota *corev1.ResourceQuota) []*bean.QuotaUsage {
+	names := make([]string, 0, len(quota.Status.Hard))
+	for name := range quota.Status.Hard {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	usage := make([]*bean.QuotaUsage, 0, len(names))
+	for _, name := range names {
+		hard := quota.Status.Hard[corev1.ResourceName(name)]
+		used := quota.Status.Used[corev1.ResourceName(name)]
+		quotaUsage := &bean.QuotaUsage{Resource: name, Hard: hard.String(), Used: used.String()}
+		if hard.MilliValue() > 0 {
+			quotaUsage.UsedPercentage = float64(used.MilliValue()) * 100 / float64(hard.MilliValue())
+		}
+		usage = append(usage, quotaUsage)
+	}
+	return usage
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package helper
+
+import (
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec/bean"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestApplyMetadata(t *testing.T) {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns", Labels: map[string]string{"owner": "infra"}}}
+	changed := ApplyMetadata(namespace, &bean.NamespaceSpec{
+		Labels:      map[string]string{"team": "payments", "tier": "backend"},
+		Annotations: map[string]string{"contact": "payments@example.com"},
+	})
+	assert.True(t, changed)
+	assert.Equal(t, map[string]string{"owner": "infra", "team": "payments", "tier": "backend"}, namespace.Labels)
+	assert.Equal(t, "team,tier", namespace.Annotations[bean.ManagedLabelsAnnotation])
+	assert.Equal(t, "payments@example.com", namespace.Annotations["contact"])
+
+	// unchanged spec leaves the namespace as is
+	assert.False(t, ApplyMetadata(namespace, &bean.NamespaceSpec{
+		Labels:      map[string]string{"team": "payments", "tier": "backend"},
+		Annotations: map[string]string{"contact": "payments@example.com"},
+	}))
+
+	// keys dropped from the spec are removed, keys set by others stay
+	changed = ApplyMetadata(namespace, &bean.NamespaceSpec{Labels: map[string]string{"team": "payments"}})
+	assert.True(t, changed)
+	assert.Equal(t, map[string]string{"owner": "infra", "team": "payments"}, namespace.Labels)
+	assert.NotContains(t, namespace.Annotations, "contact")
+	assert.NotContains(t, namespace.Annotations, bean.ManagedAnnotationsAnnotation)
+
+	// an empty spec removes everything it set
+	assert.True(t, ApplyMetadata(namespace, &bean.NamespaceSpec{}))
+	assert.Equal(t, map[string]string{"owner": "infra"}, namespace.Labels)
+	assert.Empty(t, namespace.Annotations)
+}
+
+func TestBuildRoleBindings(t *testing.T) {
+	spec := &bean.NamespaceSpec{RoleBindings: []*bean.RoleBindingSpec{{
+		Name:        "developers",
+		ClusterRole: "edit",
+		Subjects: []*bean.SubjectSpec{
+			{Kind: bean.SubjectKindGroup, Name: "devs"},
+			{Kind: bean.SubjectKindServiceAccount, Name: "ci"},
+		},
+	}}}
+	roleBindings := BuildRoleBindings(7, "payments", spec)
+	assert.Len(t, roleBindings, 1)
+	roleBinding := roleBindings[0]
+	assert.Equal(t, "devtron-developers", roleBinding.Name)
+	assert.Equal(t, "7", roleBinding.Labels[bean.EnvironmentIdLabel])
+	assert.Equal(t, rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "edit"}, roleBinding.RoleRef)
+	assert.Equal(t, []rbacv1.Subject{
+		{Kind: bean.SubjectKindGroup, Name: "devs", APIGroup: rbacv1.GroupName},
+		{Kind: bean.SubjectKindServiceAccount, Name: "ci", Namespace: "payments"},
+	}, roleBinding.Subjects)
+
+	live := roleBinding.DeepCopy()
+	assert.True(t, RoleBindingMatches(roleBinding, live))
+	live.RoleRef.Name = "admin"
+	assert.False(t, RoleBindingMatches(roleBinding, live))
+	assert.True(t, RoleBindingNeedsRecreate(roleBinding, live))
+}
+
+func TestResourceQuotaMatches(t *testing.T) {
+	spec := &bean.NamespaceSpec{ResourceQuota: &bean.ResourceQuotaSpec{Hard: map[string]string{"requests.memory": "1Gi"}}}
+	desired := BuildResourceQuota(1, "ns", spec)
+	live := desired.DeepCopy()
+	// the api server returns quantities in their canonical form
+	live.Spec.Hard[corev1.ResourceRequestsMemory] = resource.MustParse("1024Mi")
+	assert.True(t, ResourceQuotaMatches(desired, live))
+	live.Spec.Hard[corev1.ResourceRequestsMemory] = resource.MustParse("2Gi")
+	assert.False(t, ResourceQuotaMatches(desired, live))
+	assert.True(t, HasManagedLabels(live, 1))
+	assert.False(t, HasManagedLabels(live, 2))
+	assert.Nil(t, BuildResourceQuota(1, "ns", &bean.NamespaceSpec{}))
+}
+
+func TestGetQuotaUsage(t *testing.T) {
+	quota := &corev1.ResourceQuota{Status: corev1.ResourceQuotaStatus{
+		Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("2"), corev1.ResourcePods: resource.MustParse("10")},
+		Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("500m")},
+	}}
+	usage := GetQuotaUsage(quota)
+	assert.Len(t, usage, 2)
+	assert.Equal(t, &bean.QuotaUsage{Resource: "pods", Hard: "10", Used: "0", UsedPercentage: 0}, usage[0])
+	assert.Equal(t, &bean.QuotaUsage{Resource: "requests.cpu", Hard: "2", Used: "500m", UsedPercentage: 25}, usage[1])
+}
+
+func TestValidate(t *testing.T) {
+	valid := &bean.NamespaceSpec{
+		Labels:          map[string]string{"team": "payments"},
+		ResourceQuota:   &bean.ResourceQuotaSpec{Hard: map[string]string{"limits.cpu": "4"}},
+		LimitRange:      &bean.LimitRangeSpec{Default: map[string]string{"memory": "512Mi"}},
+		NetworkPolicies: []bean.NetworkPolicyPreset{bean.NetworkPolicyDenyAllIngress},
+		RoleBindings:    []*bean.RoleBindingSpec{{Name: "view", ClusterRole: "view", Subjects: []*bean.SubjectSpec{{Kind: bean.SubjectKindUser, Name: "a@b.com"}}}},
+	}
+	assert.NoError(t, valid.Validate())
+	invalid := []*bean.NamespaceSpec{
+		{Labels: map[string]string{"devtron.ai/environment-id": "1"}},
+		{Labels: map[string]string{"team": "not valid"}},
+		{ResourceQuota: &bean.ResourceQuotaSpec{}},
+		{ResourceQuota: &bean.ResourceQuotaSpec{Hard: map[string]string{"limits.cpu": "four"}}},
+		{LimitRange: &bean.LimitRangeSpec{Max: map[string]string{"cpu": "x"}}},
+		{NetworkPolicies: []bean.NetworkPolicyPreset{"allow-all"}},
+		{NetworkPolicies: []bean.NetworkPolicyPreset{bean.NetworkPolicyDenyAllEgress, bean.NetworkPolicyDenyAllEgress}},
+		{RoleBindings: []*bean.RoleBindingSpec{{Name: "view", ClusterRole: "view"}}},
+		{RoleBindings: []*bean.RoleBindingSpec{{Name: "view", ClusterRole: "view", Subjects: []*bean.SubjectSpec{{Kind: "Robot", Name: "r"}}}}},
+		{RoleBindings: []*bean.RoleBindingSpec{{Name: "Invalid_Name", ClusterRole: "view", Subjects: []*bean.SubjectSpec{{Kind: bean.SubjectKindUser, Name: "u"}}}}},
+	}
+	for _, spec := range invalid {
+		assert.Error(t, spec.Validate())
+	}
+}
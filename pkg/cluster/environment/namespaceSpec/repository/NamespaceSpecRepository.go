@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/namespaceSpec/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type EnvironmentNamespaceSpec struct {
+	tableName        struct{}    `sql:"environment_namespace_spec" pg:",discard_unknown_columns"`
+	Id               int         `sql:"id,pk"`
+	EnvironmentId    int         `sql:"environment_id,notnull"`
+	Spec             string      `sql:"spec,notnull"`
+	Active           bool        `sql:"active,notnull"`
+	Status           bean.Status `sql:"status,notnull"`
+	Message          string      `sql:"message"`
+	Drift            string      `sql:"drift"`
+	DriftDetectedOn  *time.Time  `sql:"drift_detected_on"`
+	LastReconciledOn *time.Time  `sql:"last_reconciled_on"`
+	sql.AuditLog
+}
+
+type NamespaceSpecRepository interface {
+	Save(spec *EnvironmentNamespaceSpec) error
+	Update(spec *EnvironmentNamespaceSpec) error
+	// UpdateStatus records the result of a reconcile, drift and driftDetectedOn are kept when driftDetectedOn is nil
+	UpdateStatus(id int, status bean.Status, message string, drift string, driftDetectedOn *time.Time) error
+	FindActiveByEnvironmentId(envId int) (*EnvironmentNamespaceSpec, error)
+	// FindAllActive returns the specs of the active environments
+	FindAllActive() ([]*EnvironmentNamespaceSpec, error)
+	DeactivateByEnvironmentId(tx *pg.Tx, envId int, userId int32) error
+}
+
+type NamespaceSpecRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewNamespaceSpecRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *NamespaceSpecRepositoryImpl {
+	return &NamespaceSpecRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *NamespaceSpecRepositoryImpl) Save(spec *EnvironmentNamespaceSpec) error {
+	return impl.dbConnection.Insert(spec)
+}
+
+func (impl *NamespaceSpecRepositoryImpl) Update(spec *EnvironmentNamespaceSpec) error {
+	return impl.dbConnection.Update(spec)
+}
+
+func (impl *NamespaceSpecRepositoryImpl) UpdateStatus(id int, status bean.Status, message string, drift string, driftDetectedOn *time.Time) error {
+	query := impl.dbConnection.Model((*EnvironmentNamespaceSpec)(nil)).
+		Set("status = ?", status).
+		Set("message = ?", message).
+		Set("last_reconciled_on = ?", time.Now())
+	if driftDetectedOn != nil {
+		query = query.
+			Set("drift = ?", drift).
+			Set("drift_detected_on = ?", driftDetectedOn)
+	}
+	_, err := query.
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Update()
+	return err
+}
+
+func (impl *NamespaceSpecRepositoryImpl) FindActiveByEnvironmentId(envId int) (*EnvironmentNamespaceSpec, error) {
+	spec := &EnvironmentNamespaceSpec{}
+	err := impl.dbConnection.Model(spec).
+		Where("environment_id = ?", envId).
+		Where("active = ?", true).
+		Select()
+	return spec, err
+}
+
+func (impl *NamespaceSpecRepositoryImpl) FindAllActive() ([]*EnvironmentNamespaceSpec, error) {
+	var specs []*EnvironmentNamespaceSpec
+	err := impl.dbConnection.Model(&specs).
+		Join("INNER JOIN environment env ON env.id = environment_namespace_spec.environment_id").
+		Where("environment_namespace_spec.active = ?", true).
+		Where("env.active = ?", true).
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting namespace specs", "err", err)
+		return nil, err
+	}
+	return specs, nil
+}
+
+func (impl *NamespaceSpecRepositoryImpl) DeactivateByEnvironmentId(tx *pg.Tx, envId int, userId int32) error {
+	_, err := tx.Model((*EnvironmentNamespaceSpec)(nil)).
+		Set("active = ?", false).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("environment_id = ?", envId).
+		Where("active = ?", true).
+		Update()
+	return err
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef"
 	chartRefBean "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/read"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/postRender"
 	"github.com/devtron-labs/devtron/pkg/generateManifest"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	"github.com/devtron-labs/devtron/pkg/pipeline/adapter"
@@ -83,6 +84,7 @@ type DeploymentConfigurationServiceImpl struct {
 	mergeUtil                            util.MergeUtil
 	HelmAppReadService                   read3.HelmAppReadService
 	chartReadService                     read4.ChartReadService
+	postRenderService                    postRender.PostRenderService
 }
 
 func NewDeploymentConfigurationServiceImpl(logger *zap.SugaredLogger,
@@ -111,6 +113,7 @@ func NewDeploymentConfigurationServiceImpl(logger *zap.SugaredLogger,
 	mergeUtil util.MergeUtil,
 	HelmAppReadService read3.HelmAppReadService,
 	chartReadService read4.ChartReadService,
+	postRenderService postRender.PostRenderService,
 ) (*DeploymentConfigurationServiceImpl, error) {
 	deploymentConfigurationService := &DeploymentConfigurationServiceImpl{
 		logger:                               logger,
@@ -139,6 +142,7 @@ func NewDeploymentConfigurationServiceImpl(logger *zap.SugaredLogger,
 		mergeUtil:                            mergeUtil,
 		HelmAppReadService:                   HelmAppReadService,
 		chartReadService:                     chartReadService,
+		postRenderService:                    postRenderService,
 	}
 
 	return deploymentConfigurationService, nil
@@ -335,7 +339,12 @@ func (impl *DeploymentConfigurationServiceImpl) GetManifest(ctx context.Context,
 		return nil, err
 	}
 
-	yamlSplits, err := kube.SplitYAML([]byte(templateChartResponse.GeneratedManifest))
+	generatedManifest, err := impl.postRenderService.PatchManifest(appId, envId, templateChartResponse.GeneratedManifest)
+	if err != nil {
+		impl.logger.Errorw("error in applying post render patches", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	yamlSplits, err := kube.SplitYAML([]byte(generatedManifest))
 	for _, yaml := range yamlSplits {
 		if (manifestRequest.ResourceType == bean.CM && yaml.GetKind() == "ConfigMap") || (manifestRequest.ResourceType == bean.CS && yaml.GetKind() == "Secret") {
 			name := yaml.GetName()
@@ -547,7 +556,8 @@ func (impl *DeploymentConfigurationServiceImpl) getDeploymentHistoryConfig(ctx c
 		WithResourceType(bean.DeploymentTemplate).
 		WithVariableSnapshot(map[string]map[string]string{bean.DeploymentTemplate.ToString(): variableSnapshotMap}).
 		WithResolvedValue(json.RawMessage(resolvedTemplate)).
-		WithDeploymentConfigMetadata(deploymentHistory.TemplateVersion, deploymentHistory.IsAppMetricsEnabled)
+		WithDeploymentConfigMetadata(deploymentHistory.TemplateVersion, deploymentHistory.IsAppMetricsEnabled).
+		WithPostRenderPatches(deploymentHistory.PostRenderPatches)
 	return deploymentConfig, nil
 }
 
@@ -780,13 +790,13 @@ func (impl *DeploymentConfigurationServiceImpl) getPipelineStrategyForPreviousDe
 	return pipelineConfig, nil
 }
 
-func (impl *DeploymentConfigurationServiceImpl) getDeploymentsConfigForPreviousDeployments(ctx context.Context, configDataQueryParams *bean2.ConfigDataQueryParams) (generateManifest.DeploymentTemplateResponse, error) {
+func (impl *DeploymentConfigurationServiceImpl) getDeploymentsConfigForPreviousDeployments(ctx context.Context, configDataQueryParams *bean2.ConfigDataQueryParams) (generateManifest.DeploymentTemplateResponse, string, error) {
 
 	var deploymentTemplateResponse generateManifest.DeploymentTemplateResponse
 	deplTemplate, err := impl.deploymentTemplateHistoryReadService.GetDeployedHistoryByPipelineIdAndWfrId(ctx, configDataQueryParams.PipelineId, configDataQueryParams.WfrId)
 	if err != nil {
 		impl.logger.Errorw("getDeploymentsConfigForPreviousDeployments, error in getting deployment template  by pipelineId and wfrId ", "pipelineId", configDataQueryParams.PipelineId, "wfrId", configDataQueryParams.WfrId, "err", err)
-		return deploymentTemplateResponse, err
+		return deploymentTemplateResponse, "", err
 	}
 	deploymentTemplateResponse = generateManifest.DeploymentTemplateResponse{
 		Data:                deplTemplate.CodeEditorValue.Value,
@@ -795,7 +805,11 @@ func (impl *DeploymentConfigurationServiceImpl) getDeploymentsConfigForPreviousD
 		TemplateVersion:     deplTemplate.TemplateVersion,
 		IsAppMetricsEnabled: *deplTemplate.IsAppMetricsEnabled,
 	}
-	return deploymentTemplateResponse, nil
+	var postRenderPatches string
+	if deplTemplate.PostRenderPatches != nil {
+		postRenderPatches = deplTemplate.PostRenderPatches.Value
+	}
+	return deploymentTemplateResponse, postRenderPatches, nil
 }
 
 func (impl *DeploymentConfigurationServiceImpl) getDeploymentAndCmCsConfigDataForPreviousDeployments(ctx context.Context, configDataQueryParams *bean2.ConfigDataQueryParams,
@@ -816,7 +830,7 @@ func (impl *DeploymentConfigurationServiceImpl) getDeploymentAndCmCsConfigDataFo
 		configDataDto.WithPipelineConfigData(pipelineStrategy)
 	}
 
-	deploymentTemplateData, err := impl.getDeploymentsConfigForPreviousDeployments(ctx, configDataQueryParams)
+	deploymentTemplateData, postRenderPatches, err := impl.getDeploymentsConfigForPreviousDeployments(ctx, configDataQueryParams)
 	if err != nil {
 		impl.logger.Errorw("error in getting deployment config", "configDataQueryParams", configDataQueryParams, "err", err)
 		return nil, err
@@ -834,7 +848,8 @@ func (impl *DeploymentConfigurationServiceImpl) getDeploymentAndCmCsConfigDataFo
 		WithConfigData(deploymentJson).
 		WithResourceType(bean.DeploymentTemplate).
 		WithResolvedValue(json.RawMessage(deploymentTemplateData.ResolvedData)).
-		WithVariableSnapshot(variableSnapShotMap)
+		WithVariableSnapshot(variableSnapShotMap).
+		WithPostRenderPatches(postRenderPatches)
 
 	configDataDto.WithDeploymentTemplateData(deploymentConfig)
 
@@ -1141,9 +1156,16 @@ func (impl *DeploymentConfigurationServiceImpl) getPublishedDeploymentConfig(ctx
 		variableSnapShotMap := make(map[string]map[string]string, len(deplTemplateResp.VariableSnapshot))
 		variableSnapShotMap[bean.DeploymentTemplate.ToString()] = deplTemplateResp.VariableSnapshot
 
+		postRenderPatches, err := impl.postRenderService.GetPatchesForHistory(appId, envId)
+		if err != nil {
+			impl.logger.Errorw("error in getting post render patches", "appId", appId, "envId", envId, "err", err)
+			return nil, err
+		}
+
 		return bean2.NewDeploymentAndCmCsConfig().WithConfigData(deploymentJson).WithResourceType(bean.DeploymentTemplate).
 			WithResolvedValue(json.RawMessage(deplTemplateResp.ResolvedData)).WithVariableSnapshot(variableSnapShotMap).
-			WithDeploymentConfigMetadata(deplTemplateResp.TemplateVersion, deplTemplateResp.IsAppMetricsEnabled), nil
+			WithDeploymentConfigMetadata(deplTemplateResp.TemplateVersion, deplTemplateResp.IsAppMetricsEnabled).
+			WithPostRenderPatches(postRenderPatches), nil
 	}
 	deplMetadata, err := impl.getBaseDeploymentTemplate(appId)
 	if err != nil {
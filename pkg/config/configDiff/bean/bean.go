@@ -98,6 +98,8 @@ type DeploymentAndCmCsConfig struct {
 	// for deployment template
 	TemplateVersion     string `json:"templateVersion,omitempty"`
 	IsAppMetricsEnabled bool   `json:"isAppMetricsEnabled,omitempty"`
+	// PostRenderPatches are applied to the manifest the deployment template renders on the environment
+	PostRenderPatches json.RawMessage `json:"postRenderPatches,omitempty"`
 	//for pipeline strategy
 	PipelineTriggerType pipelineConfig.TriggerType `json:"pipelineTriggerType,omitempty"`
 	Strategy            string                     `json:"strategy,omitempty"`
@@ -133,6 +135,13 @@ func (r *DeploymentAndCmCsConfig) WithDeploymentConfigMetadata(templateVersion s
 	return r
 }
 
+func (r *DeploymentAndCmCsConfig) WithPostRenderPatches(postRenderPatches string) *DeploymentAndCmCsConfig {
+	if len(postRenderPatches) != 0 {
+		r.PostRenderPatches = json.RawMessage(postRenderPatches)
+	}
+	return r
+}
+
 func (r *DeploymentAndCmCsConfig) WithPipelineStrategyMetadata(pipelineTriggerType pipelineConfig.TriggerType, strategy string) *DeploymentAndCmCsConfig {
 	r.PipelineTriggerType = pipelineTriggerType
 	r.Strategy = strategy
@@ -20,6 +20,17 @@ type ReleaseConfiguration struct {
 	Version    ReleaseConfigVersion `json:"version"`
 	ArgoCDSpec ArgoCDSpec           `json:"argoCDSpec"`
 	FluxCDSpec FluxCDSpec           `json:"fluxCDSpec"`
+	// GitOpsPathPrefix is the directory of the app in a gitops monorepo, chart location is kept under it
+	GitOpsPathPrefix string `json:"gitOpsPathPrefix,omitempty"`
+}
+
+// joinGitOpsPathPrefix moves chartLocation under the monorepo directory of the app, it is a no-op for
+// chart locations already under it
+func (r *ReleaseConfiguration) joinGitOpsPathPrefix(chartLocation string) string {
+	if len(r.GitOpsPathPrefix) == 0 || strings.HasPrefix(chartLocation, r.GitOpsPathPrefix+"/") {
+		return chartLocation
+	}
+	return path.Join(r.GitOpsPathPrefix, chartLocation)
 }
 
 type FluxCDSpec struct {
@@ -307,7 +318,7 @@ func (d *DeploymentConfig) SetRepoURL(repoURL string) *DeploymentConfig {
 
 func (d *DeploymentConfig) SetChartLocation(chartLocation string) {
 	if d.IsFluxRelease() && d.ReleaseConfiguration != nil {
-		d.ReleaseConfiguration.FluxCDSpec.ChartLocation = chartLocation
+		d.ReleaseConfiguration.FluxCDSpec.ChartLocation = d.ReleaseConfiguration.joinGitOpsPathPrefix(chartLocation)
 		return
 	}
 	if d.ReleaseConfiguration == nil ||
@@ -315,7 +326,30 @@ func (d *DeploymentConfig) SetChartLocation(chartLocation string) {
 			len(d.ReleaseConfiguration.FluxCDSpec.ChartLocation) == 0) {
 		return
 	}
-	d.ReleaseConfiguration.ArgoCDSpec.Spec.Source.Path = chartLocation
+	d.ReleaseConfiguration.ArgoCDSpec.Spec.Source.Path = d.ReleaseConfiguration.joinGitOpsPathPrefix(chartLocation)
+}
+
+func (d *DeploymentConfig) GetGitOpsPathPrefix() string {
+	if d.ReleaseConfiguration == nil {
+		return ""
+	}
+	return d.ReleaseConfiguration.GitOpsPathPrefix
+}
+
+// SetGitOpsPathPrefix moves the chart location from the current monorepo directory of the app to prefix,
+// an empty prefix moves it back to the repository root
+func (d *DeploymentConfig) SetGitOpsPathPrefix(prefix string) {
+	if d.ReleaseConfiguration == nil {
+		return
+	}
+	chartLocation := d.GetChartLocation()
+	if currentPrefix := d.ReleaseConfiguration.GitOpsPathPrefix; len(currentPrefix) != 0 {
+		chartLocation = strings.TrimPrefix(chartLocation, currentPrefix+"/")
+	}
+	d.ReleaseConfiguration.GitOpsPathPrefix = prefix
+	if len(chartLocation) != 0 {
+		d.SetChartLocation(chartLocation)
+	}
 }
 
 func (d *DeploymentConfig) GetRevision() string {
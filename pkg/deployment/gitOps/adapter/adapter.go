@@ -36,6 +36,14 @@ func GetGitOpsConfigBean(model *repository.GitOpsConfig) *apiGitOpsBean.GitOpsCo
 		AzureProjectName:      model.AzureProject,
 		BitBucketWorkspaceId:  model.BitBucketWorkspaceId,
 		BitBucketProjectKey:   model.BitBucketProjectKey,
+		GiteaOrgName:          model.GiteaOrgName,
+		SshPrivateKey:         model.SshKey,
+		SshKnownHosts:         model.SshKnownHosts,
+		RepoHookUrl:           model.RepoHookUrl,
+		DryRunRepoName:        model.DryRunRepoName,
+		RepositoryLayout:      model.RepositoryLayout,
+		MonorepoName:          model.MonorepoName,
+		MonorepoPathTemplate:  model.MonorepoPathTemplate,
 		AllowCustomRepository: model.AllowCustomRepository,
 		EnableTLSVerification: model.EnableTLSVerification,
 		TLSConfig: &apiBean.TLSConfig{
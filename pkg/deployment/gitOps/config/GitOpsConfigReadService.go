@@ -45,6 +45,10 @@ type GitOpsConfigReadService interface {
 	IsGitOpsConfigured() (*bean.GitOpsConfigurationStatus, error)
 	GetUserEmailIdAndNameForGitOpsCommit(userId int32) (string, string)
 	GetGitOpsRepoName(appName string) string
+	// GetDevtronAppGitOpsRepoName returns the shared repository in monorepo layout and the per app repository otherwise
+	GetDevtronAppGitOpsRepoName(appName string) (string, error)
+	// GetMonorepoLayout returns nil when the active gitops configuration creates a repository per app
+	GetMonorepoLayout() (*bean.MonorepoLayout, error)
 	GetGitOpsRepoNameFromUrl(gitRepoUrl string) string
 	GetBitbucketMetadata() (*bean.BitbucketProviderMetadata, error)
 	GetGitOpsConfigActive() (*bean2.GitOpsConfigDto, error)
@@ -136,6 +140,31 @@ func (impl *GitOpsConfigReadServiceImpl) GetGitOpsRepoName(appName string) strin
 	return repoName
 }
 
+func (impl *GitOpsConfigReadServiceImpl) GetDevtronAppGitOpsRepoName(appName string) (string, error) {
+	monorepoLayout, err := impl.GetMonorepoLayout()
+	if err != nil {
+		return "", err
+	} else if monorepoLayout != nil {
+		return monorepoLayout.RepoName, nil
+	}
+	return impl.GetGitOpsRepoName(appName), nil
+}
+
+func (impl *GitOpsConfigReadServiceImpl) GetMonorepoLayout() (*bean.MonorepoLayout, error) {
+	model, err := impl.gitOpsRepository.GetGitOpsConfigActive()
+	if err != nil && !errors.Is(err, pg.ErrNoRows) {
+		impl.logger.Errorw("error in fetching active gitOps config", "err", err)
+		return nil, err
+	}
+	if model == nil || model.Id == 0 || model.RepositoryLayout != bean2.RepositoryLayoutMonorepo {
+		return nil, nil
+	}
+	return &bean.MonorepoLayout{
+		RepoName:     model.MonorepoName,
+		PathTemplate: model.MonorepoPathTemplate,
+	}, nil
+}
+
 func (impl *GitOpsConfigReadServiceImpl) GetGitOpsRepoNameFromUrl(gitRepoUrl string) string {
 	return gitUtil.GetGitRepoNameFromGitRepoUrl(gitRepoUrl)
 }
@@ -184,6 +213,14 @@ func (impl *GitOpsConfigReadServiceImpl) GetAllGitOpsConfig() ([]*bean2.GitOpsCo
 			AzureProjectName:      model.AzureProject,
 			BitBucketWorkspaceId:  model.BitBucketWorkspaceId,
 			BitBucketProjectKey:   model.BitBucketProjectKey,
+			GiteaOrgName:          model.GiteaOrgName,
+			SshPrivateKey:         model.SshKey,
+			SshKnownHosts:         model.SshKnownHosts,
+			RepoHookUrl:           model.RepoHookUrl,
+			DryRunRepoName:        model.DryRunRepoName,
+			RepositoryLayout:      model.RepositoryLayout,
+			MonorepoName:          model.MonorepoName,
+			MonorepoPathTemplate:  model.MonorepoPathTemplate,
 			AllowCustomRepository: model.AllowCustomRepository,
 			TLSConfig: &bean3.TLSConfig{
 				CaData:      model.CaCert,
@@ -16,6 +16,14 @@
 
 package bean
 
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
 const (
 	GitOpsCommitDefaultEmailId = "devtron-bot@devtron.ai"
 	GitOpsCommitDefaultName    = "devtron bot"
@@ -39,3 +47,59 @@ type GitOpsConfigurationStatus struct {
 func (g *GitOpsConfigurationStatus) IsGitOpsConfiguredAndArgoCdInstalled() bool {
 	return g.IsGitOpsConfigured && g.IsArgoCdInstalled
 }
+
+const (
+	MonorepoPathTeamPlaceholder = "{team}"
+	MonorepoPathAppPlaceholder  = "{app}"
+	MonorepoPathEnvPlaceholder  = "{env}"
+)
+
+// MonorepoLayout is the active gitops configuration keeping the charts of all devtron apps in RepoName,
+// each app under the directory rendered from PathTemplate
+type MonorepoLayout struct {
+	RepoName     string
+	PathTemplate string
+}
+
+var monorepoPathPlaceholderRegex = regexp.MustCompile(`\{[^{}]*\}`)
+
+var monorepoPathSegmentRegex = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// ValidateMonorepoPathTemplate checks that the template only uses known placeholders and includes {app},
+// so that two apps never share a directory
+func ValidateMonorepoPathTemplate(pathTemplate string) error {
+	if !strings.Contains(pathTemplate, MonorepoPathAppPlaceholder) {
+		return fmt.Errorf("monorepo path template %q must contain %s", pathTemplate, MonorepoPathAppPlaceholder)
+	}
+	if strings.HasPrefix(pathTemplate, "/") || slices.Contains(strings.Split(pathTemplate, "/"), "..") {
+		return fmt.Errorf("monorepo path template %q must be relative to the repository root", pathTemplate)
+	}
+	for _, placeholder := range monorepoPathPlaceholderRegex.FindAllString(pathTemplate, -1) {
+		if placeholder != MonorepoPathTeamPlaceholder && placeholder != MonorepoPathAppPlaceholder && placeholder != MonorepoPathEnvPlaceholder {
+			return fmt.Errorf("unknown placeholder %s in monorepo path template, supported placeholders are %s, %s and %s",
+				placeholder, MonorepoPathTeamPlaceholder, MonorepoPathAppPlaceholder, MonorepoPathEnvPlaceholder)
+		}
+	}
+	return nil
+}
+
+// GetPathPrefix renders the directory of an app in the monorepo, envName is empty for the app level chart
+// in which case the {env} segment is dropped
+func (m *MonorepoLayout) GetPathPrefix(teamName, appName, envName string) string {
+	replacer := strings.NewReplacer(
+		MonorepoPathTeamPlaceholder, sanitizeMonorepoPathSegment(teamName),
+		MonorepoPathAppPlaceholder, sanitizeMonorepoPathSegment(appName),
+		MonorepoPathEnvPlaceholder, sanitizeMonorepoPathSegment(envName),
+	)
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(replacer.Replace(m.PathTemplate), "/") {
+		if len(segment) != 0 {
+			segments = append(segments, segment)
+		}
+	}
+	return path.Join(segments...)
+}
+
+func sanitizeMonorepoPathSegment(segment string) string {
+	return monorepoPathSegmentRegex.ReplaceAllString(segment, "-")
+}
@@ -0,0 +1,29 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package bean
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMonorepoPathTemplate(t *testing.T) {
+	assert.NoError(t, ValidateMonorepoPathTemplate("{team}/{app}/{env}"))
+	assert.NoError(t, ValidateMonorepoPathTemplate("apps/{app}"))
+	assert.Error(t, ValidateMonorepoPathTemplate("{team}/{env}"))
+	assert.Error(t, ValidateMonorepoPathTemplate("/{app}"))
+	assert.Error(t, ValidateMonorepoPathTemplate("../{app}"))
+	assert.Error(t, ValidateMonorepoPathTemplate("{cluster}/{app}"))
+}
+
+func TestMonorepoLayoutGetPathPrefix(t *testing.T) {
+	layout := &MonorepoLayout{RepoName: "gitops", PathTemplate: "{team}/{app}/{env}"}
+	assert.Equal(t, "payments/checkout/prod", layout.GetPathPrefix("payments", "checkout", "prod"))
+	// app level chart has no environment
+	assert.Equal(t, "payments/checkout", layout.GetPathPrefix("payments", "checkout", ""))
+	// names can not escape their segment
+	assert.Equal(t, "a-b/checkout/prod", layout.GetPathPrefix("a/b", "checkout", "prod"))
+}
@@ -37,6 +37,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -57,6 +58,17 @@ type GitOperationService interface {
 	ReloadGitOpsProvider() error
 	UpdateGitHostUrlByProvider(request *apiBean.GitOpsConfigDto) error
 	GetRepoUrlWithUserName(url string) (string, error)
+
+	// IsPullRequestSupported tells if the configured provider can deliver commits through pull requests
+	IsPullRequestSupported() bool
+	CreatePullRequestBranch(ctx context.Context, repoName, baseBranch, branch string) error
+	CreatePullRequest(ctx context.Context, request *bean.CreatePullRequestRequest) (*bean.PullRequestInfo, error)
+	GetPullRequest(ctx context.Context, repoName string, number int) (*bean.PullRequestInfo, error)
+	ClosePullRequest(ctx context.Context, repoName string, number int) error
+
+	// CopyChartToRepo copies the chart directory srcPath of srcRepoUrl to dstPath of dstRepoUrl and pushes it,
+	// used for moving apps between repositories
+	CopyChartToRepo(ctx context.Context, srcRepoUrl, srcTargetRevision, srcPath, dstRepoName, dstRepoUrl, dstTargetRevision, dstPath string, userId int32) (commitHash string, err error)
 }
 
 type GitOperationServiceImpl struct {
@@ -65,6 +77,8 @@ type GitOperationServiceImpl struct {
 	gitOpsConfigReadService config.GitOpsConfigReadService
 	chartTemplateService    util.ChartTemplateService
 	globalEnvVariables      *globalUtil.GlobalEnvVariables
+	// repoLocks holds a *sync.Mutex per repository name
+	repoLocks sync.Map
 }
 
 func NewGitOperationServiceImpl(logger *zap.SugaredLogger, gitFactory *GitFactory,
@@ -95,7 +109,7 @@ func (impl *GitOperationServiceImpl) CreateGitRepositoryForDevtronApp(ctx contex
 	gitRepoRequest := &apiBean.GitOpsConfigDto{
 		GitRepoName:          gitOpsRepoName,
 		TargetRevision:       targetRevision,
-		Description:          fmt.Sprintf("helm chart for " + gitOpsRepoName),
+		Description:          fmt.Sprintf("helm chart for %s", gitOpsRepoName),
 		BitBucketWorkspaceId: bitbucketMetadata.BitBucketWorkspaceId,
 		BitBucketProjectKey:  bitbucketMetadata.BitBucketProjectKey,
 	}
@@ -119,6 +133,7 @@ func getChartDirPathFromCloneDir(cloneDirPath string) (string, error) {
 func (impl *GitOperationServiceImpl) PushChartToGitRepo(ctx context.Context, gitOpsRepoName, chartLocation, tempReferenceTemplateDir, repoUrl, targetRevision string, userId int32) (err error) {
 	newCtx, span := otel.Tracer("orchestrator").Start(ctx, "GitOperationServiceImpl.PushChartToGitRepo")
 	defer span.End()
+	defer impl.lockRepo(gitOpsRepoName)()
 	chartDir := fmt.Sprintf("%s-%s", gitOpsRepoName, impl.chartTemplateService.GetDir())
 	clonedDir, err := impl.GetClonedDir(newCtx, chartDir, repoUrl, targetRevision)
 	defer impl.chartTemplateService.CleanDir(clonedDir)
@@ -257,6 +272,7 @@ func (impl *GitOperationServiceImpl) CommitValues(ctx context.Context, chartGitA
 	defer span.End()
 
 	impl.logger.Debugw("committing values to git", "chartGitAttr", chartGitAttr)
+	defer impl.lockRepo(chartGitAttr.ChartRepoName)()
 	bitbucketMetadata, err := impl.gitOpsConfigReadService.GetBitbucketMetadata()
 	if err != nil {
 		impl.logger.Errorw("error in getting bitbucket metadata", "err", err)
@@ -282,6 +298,18 @@ func (impl *GitOperationServiceImpl) CommitValues(ctx context.Context, chartGitA
 	return commitHash, commitTime, nil
 }
 
+// lockRepo serializes the commits of this instance on a repository, concurrent deployments of apps sharing
+// a monorepo would otherwise keep failing on conflicts until the retries run out.
+// The lock is process local and only cuts down the conflicts within a replica, commits of other replicas are
+// not serialized. Correctness across replicas relies on the push being rejected when the branch moved, the
+// commit is then retried on the pulled head ARGO_GIT_COMMIT_RETRY_COUNT_ON_CONFLICT times
+func (impl *GitOperationServiceImpl) lockRepo(repoName string) (unlock func()) {
+	lock, _ := impl.repoLocks.LoadOrStore(repoName, &sync.Mutex{})
+	mutex := lock.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
+
 func (impl *GitOperationServiceImpl) isRetryableGitCommitError(err error) bool {
 	return retryFunc.IsRetryableError(err)
 }
@@ -435,6 +463,13 @@ func (impl *GitOperationServiceImpl) UpdateGitHostUrlByProvider(request *apiBean
 		}
 	case bean.BITBUCKET_PROVIDER:
 		request.Host = BITBUCKET_CLONE_BASE_URL + request.BitBucketWorkspaceId
+	case bean.GITEA_PROVIDER:
+		// gitea clone urls are <host>/<org>/<repo>.git, same as github
+		orgUrl, err := buildGithubOrgUrl(request.Host, request.GiteaOrgName)
+		if err != nil {
+			return err
+		}
+		request.Host = orgUrl
 	}
 	return nil
 }
@@ -471,3 +506,113 @@ func (impl *GitOperationServiceImpl) addConfigFileToChart(config *ChartConfig, d
 func (impl *GitOperationServiceImpl) GetRepoUrlWithUserName(url string) (string, error) {
 	return url, nil
 }
+
+func (impl *GitOperationServiceImpl) getPullRequestClient() (GitOpsPullRequestClient, error) {
+	prClient, ok := impl.gitFactory.Client.(GitOpsPullRequestClient)
+	if !ok {
+		return nil, PullRequestNotSupportedError
+	}
+	return prClient, nil
+}
+
+func (impl *GitOperationServiceImpl) IsPullRequestSupported() bool {
+	_, err := impl.getPullRequestClient()
+	return err == nil
+}
+
+func (impl *GitOperationServiceImpl) CreatePullRequestBranch(ctx context.Context, repoName, baseBranch, branch string) error {
+	prClient, err := impl.getPullRequestClient()
+	if err != nil {
+		return err
+	}
+	if len(baseBranch) == 0 {
+		baseBranch = globalUtil.GetDefaultTargetRevision()
+	}
+	return prClient.CreateBranch(ctx, repoName, baseBranch, branch)
+}
+
+func (impl *GitOperationServiceImpl) CreatePullRequest(ctx context.Context, request *bean.CreatePullRequestRequest) (*bean.PullRequestInfo, error) {
+	prClient, err := impl.getPullRequestClient()
+	if err != nil {
+		return nil, err
+	}
+	if len(request.BaseBranch) == 0 {
+		request.BaseBranch = globalUtil.GetDefaultTargetRevision()
+	}
+	return prClient.CreatePullRequest(ctx, request)
+}
+
+func (impl *GitOperationServiceImpl) GetPullRequest(ctx context.Context, repoName string, number int) (*bean.PullRequestInfo, error) {
+	prClient, err := impl.getPullRequestClient()
+	if err != nil {
+		return nil, err
+	}
+	return prClient.GetPullRequest(ctx, repoName, number)
+}
+
+func (impl *GitOperationServiceImpl) ClosePullRequest(ctx context.Context, repoName string, number int) error {
+	prClient, err := impl.getPullRequestClient()
+	if err != nil {
+		return err
+	}
+	return prClient.ClosePullRequest(ctx, repoName, number)
+}
+
+func (impl *GitOperationServiceImpl) CopyChartToRepo(ctx context.Context, srcRepoUrl, srcTargetRevision, srcPath, dstRepoName, dstRepoUrl, dstTargetRevision, dstPath string, userId int32) (commitHash string, err error) {
+	newCtx, span := otel.Tracer("orchestrator").Start(ctx, "GitOperationServiceImpl.CopyChartToRepo")
+	defer span.End()
+	srcRepoName := impl.gitOpsConfigReadService.GetGitOpsRepoNameFromUrl(srcRepoUrl)
+	srcClonedDir, err := impl.GetClonedDir(newCtx, fmt.Sprintf("%s-%s", srcRepoName, impl.chartTemplateService.GetDir()), srcRepoUrl, srcTargetRevision)
+	defer impl.chartTemplateService.CleanDir(srcClonedDir)
+	if err != nil {
+		impl.logger.Errorw("error in cloning repo", "url", srcRepoUrl, "err", err)
+		return commitHash, err
+	}
+	srcDir := filepath.Join(srcClonedDir, srcPath)
+	if _, err = os.Stat(srcDir); err != nil {
+		impl.logger.Errorw("chart not found in source repo", "url", srcRepoUrl, "path", srcPath, "err", err)
+		return commitHash, fmt.Errorf("chart path %q not found in repository %s", srcPath, srcRepoUrl)
+	}
+
+	defer impl.lockRepo(dstRepoName)()
+	dstClonedDir, err := impl.GetClonedDir(newCtx, fmt.Sprintf("%s-%s", dstRepoName, impl.chartTemplateService.GetDir()), dstRepoUrl, dstTargetRevision)
+	defer impl.chartTemplateService.CleanDir(dstClonedDir)
+	if err != nil {
+		impl.logger.Errorw("error in cloning repo", "url", dstRepoUrl, "err", err)
+		return commitHash, err
+	}
+	dstDir := filepath.Join(dstClonedDir, dstPath)
+	userEmailId, userName := impl.gitOpsConfigReadService.GetUserEmailIdAndNameForGitOpsCommit(userId)
+	commitMessage := fmt.Sprintf("move %s from %s", dstPath, srcRepoName)
+	callback := func(retriesLeft int) error {
+		err := impl.GitPull(dstClonedDir, dstRepoUrl, dstTargetRevision)
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(dstDir, os.ModePerm)
+		if err != nil {
+			impl.logger.Errorw("error in making dir", "dir", dstDir, "err", err)
+			return err
+		}
+		err = dirCopy.Copy(srcDir, dstDir)
+		if err != nil {
+			impl.logger.Errorw("error copying dir", "err", err)
+			return err
+		}
+		commitHash, err = impl.gitFactory.GitOpsHelper.CommitAndPushAllChanges(newCtx, dstClonedDir, dstTargetRevision, commitMessage, userName, userEmailId)
+		if err != nil {
+			impl.logger.Errorw("error in pushing git", "url", dstRepoUrl, "err", err)
+			return retryFunc.NewRetryableError(err)
+		}
+		return nil
+	}
+	err = retryFunc.Retry(callback,
+		impl.isRetryableGitCommitError,
+		impl.globalEnvVariables.ArgoGitCommitRetryCountOnConflict,
+		time.Duration(impl.globalEnvVariables.ArgoGitCommitRetryDelayOnConflict)*time.Second,
+		impl.logger)
+	if err != nil {
+		return commitHash, err
+	}
+	return commitHash, nil
+}
@@ -39,6 +39,16 @@ type GitOpsClient interface {
 	CreateFirstCommitOnHead(ctx context.Context, config *gitOps.GitOpsConfigDto) (string, error)
 }
 
+// GitOpsPullRequestClient is implemented by the providers which can deliver commits through pull requests,
+// used for environments where direct pushes to the target revision are blocked by branch protection.
+type GitOpsPullRequestClient interface {
+	// CreateBranch creates branch from the head of baseBranch
+	CreateBranch(ctx context.Context, repoName, baseBranch, branch string) error
+	CreatePullRequest(ctx context.Context, request *bean.CreatePullRequestRequest) (*bean.PullRequestInfo, error)
+	GetPullRequest(ctx context.Context, repoName string, number int) (*bean.PullRequestInfo, error)
+	ClosePullRequest(ctx context.Context, repoName string, number int) error
+}
+
 func GetGitConfigAll(gitOpsConfigReadService config.GitOpsConfigReadService) ([]*bean.GitConfig, error) {
 	gitOpsConfigs, err := gitOpsConfigReadService.GetAllGitOpsConfig()
 	if err != nil && err != pg.ErrNoRows {
@@ -59,6 +69,11 @@ func GetGitConfigAll(gitOpsConfigReadService config.GitOpsConfigReadService) ([]
 			AzureProject:          gitOpsConfig.AzureProjectName,
 			BitbucketWorkspaceId:  gitOpsConfig.BitBucketWorkspaceId,
 			BitbucketProjectKey:   gitOpsConfig.BitBucketProjectKey,
+			GiteaOrgName:          gitOpsConfig.GiteaOrgName,
+			SshPrivateKey:         gitOpsConfig.SshPrivateKey,
+			SshKnownHosts:         gitOpsConfig.SshKnownHosts,
+			RepoHookUrl:           gitOpsConfig.RepoHookUrl,
+			DryRunRepoName:        gitOpsConfig.DryRunRepoName,
 			IsActiveConfig:        gitOpsConfig.Active,
 			CaCert:                gitOpsConfig.TLSConfig.CaData,
 			TLSCert:               gitOpsConfig.TLSConfig.TLSCertData,
@@ -93,6 +108,12 @@ func NewGitOpsClient(config *bean.GitConfig, logger *zap.SugaredLogger, gitOpsHe
 	} else if config.GitProvider == bean.BITBUCKET_PROVIDER {
 		gitBitbucketClient := NewGitBitbucketClient(config.GitUserName, config.GitToken, config.GitHost, logger, gitOpsHelper, tlsConfig)
 		return gitBitbucketClient, nil
+	} else if config.GitProvider == bean.GITEA_PROVIDER {
+		gitGiteaClient, err := NewGitGiteaClient(config.GitHost, config.GitToken, config.GiteaOrgName, logger, gitOpsHelper, tlsConfig)
+		return gitGiteaClient, err
+	} else if config.GitProvider == bean.GIT_SSH_PROVIDER {
+		gitSshClient, err := NewGitSshClient(config, logger, gitOpsHelper, tlsConfig)
+		return gitSshClient, err
 	} else {
 		logger.Warn("no gitops config provided, gitops will not work")
 		return &UnimplementedGitOpsClient{}, nil
@@ -42,6 +42,7 @@ type GitOpsHelper struct {
 	gitCommandManager git.GitCommandManager
 	tlsConfig         *bean.TLSConfig
 	isTlsEnabled      bool
+	sshAuth           *git.SshAuth
 }
 
 func NewGitOpsHelperImpl(auth *git.BasicAuth, logger *zap.SugaredLogger, tlsConfig *bean.TLSConfig, isTlsEnabled bool) (*GitOpsHelper, error) {
@@ -59,6 +60,41 @@ func (impl *GitOpsHelper) SetAuth(auth *git.BasicAuth) {
 	impl.Auth = auth
 }
 
+// SetSshAuth sets the deploy key used by providers pushing over ssh, nil keeps basic auth
+func (impl *GitOpsHelper) SetSshAuth(sshAuth *git.SshAuth) {
+	impl.sshAuth = sshAuth
+}
+
+// ListRemoteBranches lists the branches of a remote repository without cloning it,
+// an empty list with no error means the repository exists but has no commits yet
+func (impl *GitOpsHelper) ListRemoteBranches(url string) (branches []string, err error) {
+	start := time.Now()
+	defer func() {
+		util.TriggerGitOpsMetrics("ListRemoteBranches", "GitService", start, err)
+	}()
+	ctx := git.BuildGitContext(context.Background()).WithCredentials(impl.Auth).
+		WithTLSData(impl.tlsConfig.CaData, impl.tlsConfig.TLSKeyData, impl.tlsConfig.TLSCertData, impl.isTlsEnabled).
+		WithSshAuth(impl.sshAuth)
+	response, errMsg, err := impl.gitCommandManager.LsRemote(ctx, url)
+	if err != nil {
+		impl.logger.Errorw("error in git ls-remote", "url", url, "errMsg", errMsg, "err", err)
+		if len(errMsg) > 0 {
+			return nil, fmt.Errorf("%s", errMsg)
+		}
+		return nil, err
+	}
+	branches = make([]string, 0)
+	for _, line := range strings.Split(response, "\n") {
+		// each line is "<sha>\trefs/heads/<branch>"
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		branches = append(branches, strings.TrimPrefix(fields[1], "refs/heads/"))
+	}
+	return branches, nil
+}
+
 func (impl *GitOpsHelper) GetCloneDirectory(targetDir string) (clonedDir string) {
 	start := time.Now()
 	defer func() {
@@ -91,7 +127,8 @@ func (impl *GitOpsHelper) cloneAndFetch(url, targetDir string) (ctx git.GitConte
 	impl.logger.Debugw("git checkout ", "url", url, "dir", targetDir)
 	clonedDir = filepath.Join(bean2.GIT_WORKING_DIR, targetDir)
 	ctx = git.BuildGitContext(context.Background()).WithCredentials(impl.Auth).
-		WithTLSData(impl.tlsConfig.CaData, impl.tlsConfig.TLSKeyData, impl.tlsConfig.TLSCertData, impl.isTlsEnabled)
+		WithTLSData(impl.tlsConfig.CaData, impl.tlsConfig.TLSKeyData, impl.tlsConfig.TLSCertData, impl.isTlsEnabled).
+		WithSshAuth(impl.sshAuth)
 	err = impl.init(ctx, clonedDir, url, false)
 	if err != nil {
 		return ctx, clonedDir, err
@@ -99,7 +136,7 @@ func (impl *GitOpsHelper) cloneAndFetch(url, targetDir string) (ctx git.GitConte
 	_, errMsg, err := impl.gitCommandManager.Fetch(ctx, clonedDir)
 	if errMsg != "" {
 		impl.logger.Errorw("error in git fetch command", "errMsg", errMsg, "err", err)
-		return ctx, clonedDir, fmt.Errorf(errMsg)
+		return ctx, clonedDir, fmt.Errorf("%s", errMsg)
 	} else if err != nil {
 		impl.logger.Errorw("error in git fetch command", "clonedDir", clonedDir, "url", url, "err", err)
 		return ctx, clonedDir, fmt.Errorf("error in git fetch command: %v", err)
@@ -127,7 +164,8 @@ func (impl *GitOpsHelper) pullFromTargetRevision(ctx git.GitContext, clonedDir,
 func (impl *GitOpsHelper) Pull(repoRoot, targetRevision string) (err error) {
 	start := time.Now()
 	ctx := git.BuildGitContext(context.Background()).WithCredentials(impl.Auth).
-		WithTLSData(impl.tlsConfig.CaData, impl.tlsConfig.TLSKeyData, impl.tlsConfig.TLSCertData, impl.isTlsEnabled)
+		WithTLSData(impl.tlsConfig.CaData, impl.tlsConfig.TLSKeyData, impl.tlsConfig.TLSCertData, impl.isTlsEnabled).
+		WithSshAuth(impl.sshAuth)
 	err = impl.gitCommandManager.Pull(ctx, targetRevision, repoRoot)
 	if err != nil {
 		util.TriggerGitOpsMetrics("Pull", "GitService", start, err)
@@ -147,7 +185,8 @@ func (impl *GitOpsHelper) CommitAndPushAllChanges(ctx context.Context, repoRoot,
 		span.End()
 	}()
 	gitCtx := git.BuildGitContext(newCtx).WithCredentials(impl.Auth).
-		WithTLSData(impl.tlsConfig.CaData, impl.tlsConfig.TLSKeyData, impl.tlsConfig.TLSCertData, impl.isTlsEnabled)
+		WithTLSData(impl.tlsConfig.CaData, impl.tlsConfig.TLSKeyData, impl.tlsConfig.TLSCertData, impl.isTlsEnabled).
+		WithSshAuth(impl.sshAuth)
 	commitHash, err = impl.gitCommandManager.CommitAndPush(gitCtx, repoRoot, targetRevision, commitMsg, name, emailId)
 	if err != nil && strings.Contains(err.Error(), PushErrorMessage) {
 		return commitHash, fmt.Errorf("%s %v", "push failed due to conflicts", err)
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package git
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/constants"
+	gitProviderRepository "github.com/devtron-labs/devtron/pkg/build/git/gitProvider/repository"
+	bean2 "github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/bean"
+	git "github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/commandManager"
+	goGit "github.com/go-git/go-git/v5"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+)
+
+// CloneAndReadFiles clones the branch of the repository with the credentials of the git provider and reads the files
+// under dir accepted by isWanted along with the head commit, the cloned directory is removed before returning
+func CloneAndReadFiles(gitProvider *gitProviderRepository.GitProvider, logger *zap.SugaredLogger, repoUrl, branch, cloneDirName, dir string,
+	isWanted func(fileName string) bool) ([]*bean2.RepoFile, string, error) {
+	var basicAuth *git.BasicAuth
+	switch gitProvider.AuthMode {
+	case constants.AUTH_MODE_USERNAME_PASSWORD:
+		basicAuth = &git.BasicAuth{Username: gitProvider.UserName, Password: gitProvider.Password}
+	case constants.AUTH_MODE_ACCESS_TOKEN:
+		basicAuth = &git.BasicAuth{Username: gitProvider.UserName, Password: gitProvider.AccessToken}
+	case constants.AUTH_MODE_ANONYMOUS:
+		basicAuth = &git.BasicAuth{}
+	default:
+		return nil, "", fmt.Errorf("auth mode %q of git provider is not supported for reading repository files", gitProvider.AuthMode)
+	}
+	tlsConfig := &bean.TLSConfig{CaData: gitProvider.CaCert, TLSCertData: gitProvider.TlsCert, TLSKeyData: gitProvider.TlsKey}
+	gitHelper, err := NewGitOpsHelperImpl(basicAuth, logger, tlsConfig, gitProvider.EnableTLSVerification)
+	if err != nil {
+		return nil, "", err
+	}
+	clonedDir, err := gitHelper.Clone(repoUrl, cloneDirName, branch)
+	defer func() {
+		if clonedDir != "" {
+			_ = os.RemoveAll(clonedDir)
+		}
+	}()
+	if err != nil {
+		logger.Errorw("error in cloning repo", "repoUrl", repoUrl, "branch", branch, "err", err)
+		return nil, "", err
+	}
+	commit, err := getHeadCommit(clonedDir)
+	if err != nil {
+		logger.Errorw("error in reading head commit of cloned repo", "repoUrl", repoUrl, "err", err)
+		return nil, "", err
+	}
+	readDir := filepath.Join(clonedDir, filepath.Clean("/"+dir))
+	files := make([]*bean2.RepoFile, 0)
+	err = filepath.WalkDir(readDir, func(filePath string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// only regular files are read, symlinks in the repository could point outside the cloned directory
+		if !entry.Type().IsRegular() || !isWanted(entry.Name()) {
+			return nil
+		}
+		fileContent, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		relativePath, _ := filepath.Rel(clonedDir, filePath)
+		files = append(files, &bean2.RepoFile{Path: relativePath, Content: fileContent})
+		return nil
+	})
+	if err != nil {
+		logger.Errorw("error in reading files from cloned repo", "repoUrl", repoUrl, "dir", readDir, "err", err)
+		return nil, "", err
+	}
+	return files, commit, nil
+}
+
+func getHeadCommit(repoDir string) (string, error) {
+	repo, err := goGit.PlainOpen(repoDir)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
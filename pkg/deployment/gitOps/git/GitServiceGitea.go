@@ -0,0 +1,527 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/common-lib/utils/retryFunc"
+	"github.com/devtron-labs/common-lib/utils/runTime"
+	bean2 "github.com/devtron-labs/devtron/api/bean/gitOps"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/bean"
+	globalUtil "github.com/devtron-labs/devtron/util"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const GITEA_API_V1 = "api/v1"
+
+// GiteaClient talks to the gitea /api/v1 rest api, forgejo serves the same api so it is covered as well
+type GiteaClient struct {
+	apiBaseUrl   string
+	token        string
+	org          string
+	httpClient   *http.Client
+	logger       *zap.SugaredLogger
+	gitOpsHelper *GitOpsHelper
+}
+
+func NewGitGiteaClient(host string, token string, org string, logger *zap.SugaredLogger,
+	gitOpsHelper *GitOpsHelper, tlsConfig *tls.Config) (GiteaClient, error) {
+	hostUrl, err := url.Parse(host)
+	if err != nil {
+		logger.Errorw("error in creating gitea client", "host", host, "err", err)
+		return GiteaClient{}, err
+	}
+	return GiteaClient{
+		apiBaseUrl:   strings.TrimSuffix(hostUrl.String(), "/") + "/" + GITEA_API_V1,
+		token:        token,
+		org:          org,
+		httpClient:   globalUtil.GetHTTPClientWithTLSConfig(tlsConfig),
+		logger:       logger,
+		gitOpsHelper: gitOpsHelper,
+	}, nil
+}
+
+type giteaRepository struct {
+	CloneUrl string `json:"clone_url"`
+	Empty    bool   `json:"empty"`
+}
+
+type giteaCreateRepositoryRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Private     bool   `json:"private"`
+}
+
+type giteaIdentity struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type giteaFileOptions struct {
+	Content   string         `json:"content"`
+	Message   string         `json:"message"`
+	Branch    string         `json:"branch"`
+	Sha       string         `json:"sha,omitempty"`
+	Author    *giteaIdentity `json:"author,omitempty"`
+	Committer *giteaIdentity `json:"committer,omitempty"`
+}
+
+type giteaContent struct {
+	Sha string `json:"sha"`
+}
+
+type giteaFileResponse struct {
+	Commit struct {
+		Sha    string `json:"sha"`
+		Author struct {
+			Date time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+// GiteaApiError is returned for every non 2xx response of the gitea api
+type GiteaApiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *GiteaApiError) Error() string {
+	return fmt.Sprintf("gitea api error, status: %d, message: %s", e.StatusCode, e.Message)
+}
+
+func IsGiteaRepoNotFound(err error) bool {
+	var apiErr *GiteaApiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// isGiteaConflict covers both a stale sha on update and a file created in between,
+// gitea answers 409 or 422 depending on the version
+func isGiteaConflict(err error) bool {
+	var apiErr *GiteaApiError
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusConflict || apiErr.StatusCode == http.StatusUnprocessableEntity)
+}
+
+func (impl GiteaClient) doRequest(ctx context.Context, method, apiPath string, body, response interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, impl.apiBaseUrl+apiPath, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+impl.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := impl.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		apiErr := &GiteaApiError{StatusCode: resp.StatusCode, Message: string(respBody)}
+		errResp := &struct {
+			Message string `json:"message"`
+		}{}
+		if json.Unmarshal(respBody, errResp) == nil && len(errResp.Message) > 0 {
+			apiErr.Message = errResp.Message
+		}
+		return apiErr
+	}
+	if response != nil && len(respBody) > 0 {
+		return json.Unmarshal(respBody, response)
+	}
+	return nil
+}
+
+func (impl GiteaClient) repoPath(repoName string) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(impl.org), url.PathEscape(repoName))
+}
+
+func (impl GiteaClient) DeleteRepository(config *bean2.GitOpsConfigDto) error {
+	var err error
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("DeleteRepository", "GiteaClient", start, err)
+	}()
+	err = impl.doRequest(context.Background(), http.MethodDelete, impl.repoPath(config.GitRepoName), nil, nil)
+	if err != nil {
+		impl.logger.Errorw("repo deletion failed for gitea", "repo", config.GitRepoName, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl GiteaClient) CreateRepository(ctx context.Context, config *bean2.GitOpsConfigDto) (url string, isNew bool, isEmpty bool, detailedErrorGitOpsConfigActions DetailedErrorGitOpsConfigActions) {
+	var err error
+	start := time.Now()
+
+	detailedErrorGitOpsConfigActions.StageErrorMap = make(map[string]error)
+	repoExists := true
+	url, isEmpty, err = impl.getRepoUrl(ctx, config, IsGiteaRepoNotFound)
+	if err != nil {
+		if IsGiteaRepoNotFound(err) {
+			repoExists = false
+		} else {
+			impl.logger.Errorw("error in creating gitea repo", "err", err)
+			detailedErrorGitOpsConfigActions.StageErrorMap[bean.GetRepoUrlStage] = err
+			globalUtil.TriggerGitOpsMetrics("CreateRepository", "GiteaClient", start, err)
+			return "", false, isEmpty, detailedErrorGitOpsConfigActions
+		}
+	}
+	if repoExists {
+		detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.GetRepoUrlStage)
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GiteaClient", start, nil)
+		return url, false, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	repo := &giteaRepository{}
+	err1 := impl.doRequest(ctx, http.MethodPost, fmt.Sprintf("/orgs/%s/repos", impl.org), &giteaCreateRepositoryRequest{
+		Name:        config.GitRepoName,
+		Description: config.Description,
+		Private:     true,
+	}, repo)
+	if err1 != nil {
+		impl.logger.Errorw("error in creating gitea repo", "repo", config.GitRepoName, "err", err1)
+		url, isEmpty, err = impl.GetRepoUrl(config)
+		if err != nil {
+			impl.logger.Errorw("error in getting gitea repo", "repo", config.GitRepoName, "err", err)
+			detailedErrorGitOpsConfigActions.StageErrorMap[bean.CreateRepoStage] = err1
+			globalUtil.TriggerGitOpsMetrics("CreateRepository", "GiteaClient", start, err1)
+			return "", true, isEmpty, detailedErrorGitOpsConfigActions
+		}
+		detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.GetRepoUrlStage)
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GiteaClient", start, nil)
+		return url, false, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	impl.logger.Infow("gitea repo created", "cloneUrl", repo.CloneUrl)
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.CreateRepoStage)
+
+	validated, err := impl.ensureProjectAvailabilityOnHttp(config)
+	if err != nil {
+		impl.logger.Errorw("error in ensuring project availability gitea", "project", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[bean.CloneHttpStage] = err
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GiteaClient", start, err)
+		return repo.CloneUrl, true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	if !validated {
+		err = fmt.Errorf("unable to validate project:%s in given time", config.GitRepoName)
+		detailedErrorGitOpsConfigActions.StageErrorMap[bean.CloneHttpStage] = err
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GiteaClient", start, err)
+		return "", true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.CloneHttpStage)
+
+	_, err = impl.CreateReadme(ctx, config)
+	if err != nil {
+		impl.logger.Errorw("error in creating readme gitea", "project", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[bean.CreateReadmeStage] = err
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GiteaClient", start, err)
+		return repo.CloneUrl, true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	isEmpty = false //As we have created readme, repo is no longer empty
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.CreateReadmeStage)
+
+	validated, err = impl.ensureProjectAvailabilityOnSsh(config.GitRepoName, repo.CloneUrl, config.TargetRevision)
+	if err != nil {
+		impl.logger.Errorw("error in ensuring project availability gitea", "project", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[bean.CloneSshStage] = err
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GiteaClient", start, err)
+		return repo.CloneUrl, true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	if !validated {
+		err = fmt.Errorf("unable to validate project:%s in given time", config.GitRepoName)
+		detailedErrorGitOpsConfigActions.StageErrorMap[bean.CloneSshStage] = err
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GiteaClient", start, err)
+		return "", true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.CloneSshStage)
+	globalUtil.TriggerGitOpsMetrics("CreateRepository", "GiteaClient", start, nil)
+	return repo.CloneUrl, true, isEmpty, detailedErrorGitOpsConfigActions
+}
+
+func (impl GiteaClient) CreateFirstCommitOnHead(ctx context.Context, config *bean2.GitOpsConfigDto) (string, error) {
+	return impl.CreateReadme(ctx, config)
+}
+
+func (impl GiteaClient) CreateReadme(ctx context.Context, config *bean2.GitOpsConfigDto) (string, error) {
+	var err error
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("CreateReadme", "GiteaClient", start, err)
+	}()
+
+	cfg := &ChartConfig{
+		ChartName:      config.GitRepoName,
+		ChartLocation:  "",
+		FileName:       "README.md",
+		FileContent:    "@devtron",
+		ReleaseMessage: "readme",
+		ChartRepoName:  config.GitRepoName,
+		TargetRevision: config.TargetRevision,
+		UserName:       config.Username,
+		UserEmailId:    config.UserEmailId,
+	}
+	hash, _, err := impl.CommitValues(ctx, cfg, config, true)
+	if err != nil {
+		impl.logger.Errorw("error in creating readme gitea", "repo", config.GitRepoName, "err", err)
+	}
+	return hash, err
+}
+
+func (impl GiteaClient) CommitValues(ctx context.Context, config *ChartConfig, gitOpsConfig *bean2.GitOpsConfigDto, publishStatusConflictErrorMetrics bool) (commitHash string, commitTime time.Time, err error) {
+	start := time.Now()
+
+	branch := config.TargetRevision
+	if len(branch) == 0 {
+		branch = globalUtil.GetDefaultTargetRevision()
+	}
+	filePath := filepath.Join(config.ChartLocation, config.FileName)
+	contentPath := fmt.Sprintf("%s/contents/%s", impl.repoPath(config.ChartRepoName), filePath)
+	existing := &giteaContent{}
+	newFile := false
+	err = impl.doRequest(ctx, http.MethodGet, fmt.Sprintf("%s?ref=%s", contentPath, url.QueryEscape(branch)), nil, existing)
+	if err != nil {
+		if !IsGiteaRepoNotFound(err) {
+			impl.logger.Errorw("error in getting file gitea", "config", config, "err", err)
+			globalUtil.TriggerGitOpsMetrics("CommitValues", "GiteaClient", start, err)
+			return "", time.Time{}, err
+		}
+		newFile = true
+	}
+	author := &giteaIdentity{Name: config.UserName, Email: config.UserEmailId}
+	options := &giteaFileOptions{
+		Content:   base64.StdEncoding.EncodeToString([]byte(config.FileContent)),
+		Message:   config.ReleaseMessage,
+		Branch:    branch,
+		Author:    author,
+		Committer: author,
+	}
+	method := http.MethodPost
+	if !newFile {
+		method = http.MethodPut
+		options.Sha = existing.Sha
+	}
+	fileResponse := &giteaFileResponse{}
+	err = impl.doRequest(ctx, method, contentPath, options, fileResponse)
+	if err != nil && isGiteaConflict(err) {
+		impl.logger.Warnw("conflict found in commit gitea", "config", config, "err", err)
+		if publishStatusConflictErrorMetrics {
+			globalUtil.TriggerGitOpsMetrics("CommitValues", "GiteaClient", start, err)
+		}
+		return "", time.Time{}, retryFunc.NewRetryableError(err)
+	} else if err != nil {
+		impl.logger.Errorw("error in commit gitea", "config", config, "err", err)
+		globalUtil.TriggerGitOpsMetrics("CommitValues", "GiteaClient", start, err)
+		return "", time.Time{}, err
+	}
+	commitTime = time.Now() // default is current time, if found then will get updated accordingly
+	if !fileResponse.Commit.Author.Date.IsZero() {
+		commitTime = fileResponse.Commit.Author.Date
+	}
+	globalUtil.TriggerGitOpsMetrics("CommitValues", "GiteaClient", start, nil)
+	return fileResponse.Commit.Sha, commitTime, nil
+}
+
+func (impl GiteaClient) GetRepoUrl(config *bean2.GitOpsConfigDto) (repoUrl string, isRepoEmpty bool, err error) {
+	ctx := context.Background()
+	return impl.getRepoUrl(ctx, config, globalUtil.AllPublishableError())
+}
+
+func (impl GiteaClient) getRepoUrl(ctx context.Context, config *bean2.GitOpsConfigDto, isNonPublishableError globalUtil.EvalIsNonPublishableErr) (repoUrl string, isRepoEmpty bool, err error) {
+	start := time.Now()
+	defer func() {
+		if isNonPublishableError(err) {
+			impl.logger.Debugw("found non publishable error. skipping metrics publish!", "caller method", runTime.GetCallerFunctionName(), "err", err)
+			return
+		}
+		globalUtil.TriggerGitOpsMetrics("GetRepoUrl", "GiteaClient", start, err)
+	}()
+
+	repo := &giteaRepository{}
+	err = impl.doRequest(ctx, http.MethodGet, impl.repoPath(config.GitRepoName), nil, repo)
+	if err != nil {
+		impl.logger.Errorw("error in getting repo url by repo name", "org", impl.org, "gitRepoName", config.GitRepoName, "err", err)
+		return "", false, err
+	}
+	return repo.CloneUrl, repo.Empty, nil
+}
+
+func (impl GiteaClient) ensureProjectAvailabilityOnHttp(config *bean2.GitOpsConfigDto) (bool, error) {
+	var err error
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("ensureProjectAvailabilityOnHttp", "GiteaClient", start, err)
+	}()
+
+	count := 0
+	for count < 3 {
+		count = count + 1
+		_, _, err := impl.GetRepoUrl(config)
+		if err == nil {
+			return true, nil
+		}
+		impl.logger.Errorw("error in validating repo gitea", "project", config.GitRepoName, "err", err)
+		if !IsGiteaRepoNotFound(err) {
+			return false, err
+		}
+		time.Sleep(10 * time.Second)
+	}
+	return false, nil
+}
+
+func (impl GiteaClient) ensureProjectAvailabilityOnSsh(projectName string, repoUrl, targetRevision string) (bool, error) {
+	var err error
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("ensureProjectAvailabilityOnSsh", "GiteaClient", start, err)
+	}()
+
+	count := 0
+	for count < 3 {
+		count = count + 1
+		_, err := impl.gitOpsHelper.Clone(repoUrl, fmt.Sprintf("/ensure-clone/%s", projectName), targetRevision)
+		if err == nil {
+			impl.logger.Infow("gitea ensureProjectAvailability clone passed", "try count", count, "repoUrl", repoUrl)
+			return true, nil
+		} else {
+			impl.logger.Errorw("gitea ensureProjectAvailability clone failed", "try count", count, "err", err)
+		}
+		time.Sleep(10 * time.Second)
+	}
+	return false, nil
+}
+
+type giteaCreateBranchRequest struct {
+	NewBranchName string `json:"new_branch_name"`
+	OldBranchName string `json:"old_branch_name"`
+}
+
+type giteaCreatePullRequest struct {
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type giteaEditPullRequest struct {
+	State string `json:"state"`
+}
+
+type giteaPullRequest struct {
+	Number         int    `json:"number"`
+	HtmlUrl        string `json:"html_url"`
+	State          string `json:"state"`
+	Merged         bool   `json:"merged"`
+	MergeCommitSha string `json:"merge_commit_sha"`
+}
+
+func (pr *giteaPullRequest) toPullRequestInfo() *bean.PullRequestInfo {
+	prInfo := &bean.PullRequestInfo{
+		Number: pr.Number,
+		Url:    pr.HtmlUrl,
+		State:  bean.PullRequestOpen,
+	}
+	if pr.Merged {
+		prInfo.State = bean.PullRequestMerged
+		prInfo.MergeCommitSha = pr.MergeCommitSha
+	} else if pr.State == "closed" {
+		prInfo.State = bean.PullRequestClosed
+	}
+	return prInfo
+}
+
+func (impl GiteaClient) CreateBranch(ctx context.Context, repoName, baseBranch, branch string) (err error) {
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("CreateBranch", "GiteaClient", start, err)
+	}()
+	request := &giteaCreateBranchRequest{NewBranchName: branch, OldBranchName: baseBranch}
+	err = impl.doRequest(ctx, http.MethodPost, impl.repoPath(repoName)+"/branches", request, nil)
+	if err != nil {
+		impl.logger.Errorw("error in creating branch gitea", "repo", repoName, "branch", branch, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl GiteaClient) CreatePullRequest(ctx context.Context, request *bean.CreatePullRequestRequest) (prInfo *bean.PullRequestInfo, err error) {
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("CreatePullRequest", "GiteaClient", start, err)
+	}()
+	pr := &giteaPullRequest{}
+	err = impl.doRequest(ctx, http.MethodPost, impl.repoPath(request.RepoName)+"/pulls", &giteaCreatePullRequest{
+		Head:  request.HeadBranch,
+		Base:  request.BaseBranch,
+		Title: request.Title,
+		Body:  request.Description,
+	}, pr)
+	if err != nil {
+		impl.logger.Errorw("error in creating pull request gitea", "request", request, "err", err)
+		return nil, err
+	}
+	return pr.toPullRequestInfo(), nil
+}
+
+func (impl GiteaClient) GetPullRequest(ctx context.Context, repoName string, number int) (prInfo *bean.PullRequestInfo, err error) {
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("GetPullRequest", "GiteaClient", start, err)
+	}()
+	pr := &giteaPullRequest{}
+	err = impl.doRequest(ctx, http.MethodGet, fmt.Sprintf("%s/pulls/%d", impl.repoPath(repoName), number), nil, pr)
+	if err != nil {
+		impl.logger.Errorw("error in getting pull request gitea", "repo", repoName, "number", number, "err", err)
+		return nil, err
+	}
+	return pr.toPullRequestInfo(), nil
+}
+
+func (impl GiteaClient) ClosePullRequest(ctx context.Context, repoName string, number int) (err error) {
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("ClosePullRequest", "GiteaClient", start, err)
+	}()
+	err = impl.doRequest(ctx, http.MethodPatch, fmt.Sprintf("%s/pulls/%d", impl.repoPath(repoName), number), &giteaEditPullRequest{State: "closed"}, nil)
+	if err != nil {
+		impl.logger.Errorw("error in closing pull request gitea", "repo", repoName, "number", number, "err", err)
+		return err
+	}
+	return nil
+}
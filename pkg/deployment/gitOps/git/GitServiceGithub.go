@@ -360,3 +360,85 @@ func (impl GitHubClient) ensureProjectAvailabilityOnSsh(projectName string, repo
 	}
 	return false, nil
 }
+
+func (impl GitHubClient) CreateBranch(ctx context.Context, repoName, baseBranch, branch string) (err error) {
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("CreateBranch", "GitHubClient", start, err)
+	}()
+	baseRef, _, err := impl.client.Git.GetRef(ctx, impl.org, repoName, "refs/heads/"+baseBranch)
+	if err != nil {
+		impl.logger.Errorw("error in getting base branch ref github", "repo", repoName, "baseBranch", baseBranch, "err", err)
+		return err
+	}
+	ref := "refs/heads/" + branch
+	_, _, err = impl.client.Git.CreateRef(ctx, impl.org, repoName, &github.Reference{
+		Ref:    &ref,
+		Object: &github.GitObject{SHA: baseRef.Object.SHA},
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating branch github", "repo", repoName, "branch", branch, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl GitHubClient) CreatePullRequest(ctx context.Context, request *bean.CreatePullRequestRequest) (prInfo *bean.PullRequestInfo, err error) {
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("CreatePullRequest", "GitHubClient", start, err)
+	}()
+	pr, _, err := impl.client.PullRequests.Create(ctx, impl.org, request.RepoName, &github.NewPullRequest{
+		Title: &request.Title,
+		Head:  &request.HeadBranch,
+		Base:  &request.BaseBranch,
+		Body:  &request.Description,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating pull request github", "request", request, "err", err)
+		return nil, err
+	}
+	return getGithubPullRequestInfo(pr), nil
+}
+
+func (impl GitHubClient) GetPullRequest(ctx context.Context, repoName string, number int) (prInfo *bean.PullRequestInfo, err error) {
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("GetPullRequest", "GitHubClient", start, err)
+	}()
+	pr, _, err := impl.client.PullRequests.Get(ctx, impl.org, repoName, number)
+	if err != nil {
+		impl.logger.Errorw("error in getting pull request github", "repo", repoName, "number", number, "err", err)
+		return nil, err
+	}
+	return getGithubPullRequestInfo(pr), nil
+}
+
+func getGithubPullRequestInfo(pr *github.PullRequest) *bean.PullRequestInfo {
+	prInfo := &bean.PullRequestInfo{
+		Number: pr.GetNumber(),
+		Url:    pr.GetHTMLURL(),
+		State:  bean.PullRequestOpen,
+	}
+	if pr.GetMerged() {
+		prInfo.State = bean.PullRequestMerged
+		prInfo.MergeCommitSha = pr.GetMergeCommitSHA()
+	} else if pr.GetState() == "closed" {
+		prInfo.State = bean.PullRequestClosed
+	}
+	return prInfo
+}
+
+func (impl GitHubClient) ClosePullRequest(ctx context.Context, repoName string, number int) (err error) {
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("ClosePullRequest", "GitHubClient", start, err)
+	}()
+	state := "closed"
+	_, _, err = impl.client.PullRequests.Edit(ctx, impl.org, repoName, number, &github.PullRequest{State: &state})
+	if err != nil {
+		impl.logger.Errorw("error in closing pull request github", "repo", repoName, "number", number, "err", err)
+		return err
+	}
+	return nil
+}
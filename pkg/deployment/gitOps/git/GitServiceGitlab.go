@@ -398,3 +398,95 @@ func (impl GitLabClient) CommitValues(ctx context.Context, config *ChartConfig,
 	util.TriggerGitOpsMetrics("CommitValues", "GitLabClient", start, nil)
 	return c.ID, commitTime, err
 }
+
+func (impl GitLabClient) getProjectPath(repoName string) string {
+	return fmt.Sprintf("%s/%s", impl.config.GitlabGroupPath, repoName)
+}
+
+func (impl GitLabClient) CreateBranch(ctx context.Context, repoName, baseBranch, branch string) (err error) {
+	start := time.Now()
+	defer func() {
+		util.TriggerGitOpsMetrics("CreateBranch", "GitLabClient", start, err)
+	}()
+	_, _, err = impl.client.Branches.CreateBranch(impl.getProjectPath(repoName), &gitlab.CreateBranchOptions{
+		Branch: &branch,
+		Ref:    &baseBranch,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		impl.logger.Errorw("error in creating branch gitlab", "repo", repoName, "branch", branch, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl GitLabClient) CreatePullRequest(ctx context.Context, request *bean.CreatePullRequestRequest) (prInfo *bean.PullRequestInfo, err error) {
+	start := time.Now()
+	defer func() {
+		util.TriggerGitOpsMetrics("CreatePullRequest", "GitLabClient", start, err)
+	}()
+	removeSourceBranch := true
+	mr, _, err := impl.client.MergeRequests.CreateMergeRequest(impl.getProjectPath(request.RepoName), &gitlab.CreateMergeRequestOptions{
+		Title:              &request.Title,
+		Description:        &request.Description,
+		SourceBranch:       &request.HeadBranch,
+		TargetBranch:       &request.BaseBranch,
+		RemoveSourceBranch: &removeSourceBranch,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		impl.logger.Errorw("error in creating merge request gitlab", "request", request, "err", err)
+		return nil, err
+	}
+	return getGitlabPullRequestInfo(mr), nil
+}
+
+func (impl GitLabClient) GetPullRequest(ctx context.Context, repoName string, number int) (prInfo *bean.PullRequestInfo, err error) {
+	start := time.Now()
+	defer func() {
+		util.TriggerGitOpsMetrics("GetPullRequest", "GitLabClient", start, err)
+	}()
+	mr, _, err := impl.client.MergeRequests.GetMergeRequest(impl.getProjectPath(repoName), number, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		impl.logger.Errorw("error in getting merge request gitlab", "repo", repoName, "number", number, "err", err)
+		return nil, err
+	}
+	return getGitlabPullRequestInfo(mr), nil
+}
+
+func getGitlabPullRequestInfo(mr *gitlab.MergeRequest) *bean.PullRequestInfo {
+	prInfo := &bean.PullRequestInfo{
+		Number: mr.IID,
+		Url:    mr.WebURL,
+		State:  bean.PullRequestOpen,
+	}
+	switch mr.State {
+	case "merged":
+		prInfo.State = bean.PullRequestMerged
+		// fast-forward merges have no merge commit, the head of the source branch lands on the target
+		prInfo.MergeCommitSha = mr.MergeCommitSHA
+		if len(prInfo.MergeCommitSha) == 0 {
+			prInfo.MergeCommitSha = mr.SquashCommitSHA
+		}
+		if len(prInfo.MergeCommitSha) == 0 {
+			prInfo.MergeCommitSha = mr.SHA
+		}
+	case "closed":
+		prInfo.State = bean.PullRequestClosed
+	}
+	return prInfo
+}
+
+func (impl GitLabClient) ClosePullRequest(ctx context.Context, repoName string, number int) (err error) {
+	start := time.Now()
+	defer func() {
+		util.TriggerGitOpsMetrics("ClosePullRequest", "GitLabClient", start, err)
+	}()
+	stateEvent := "close"
+	_, _, err = impl.client.MergeRequests.UpdateMergeRequest(impl.getProjectPath(repoName), number, &gitlab.UpdateMergeRequestOptions{
+		StateEvent: &stateEvent,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		impl.logger.Errorw("error in closing merge request gitlab", "repo", repoName, "number", number, "err", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/common-lib/utils/retryFunc"
+	bean2 "github.com/devtron-labs/devtron/api/bean/gitOps"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/bean"
+	globalUtil "github.com/devtron-labs/devtron/util"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	SSH_URL_PROTOCOL  = "ssh://"
+	FILE_URL_PROTOCOL = "file://"
+
+	RepoHookActionCreate = "CREATE"
+	RepoHookActionDelete = "DELETE"
+)
+
+// GitSshClient is used for plain git servers without a repository api. Repositories live under the host url
+// (ssh://<user>@<host>[:port]/<path>) and are either pre-created or created by the configured repo hook,
+// commits are pushed over ssh with the deploy key set on the gitOpsHelper.
+type GitSshClient struct {
+	host         string
+	repoHookUrl  string
+	hookToken    string
+	httpClient   *http.Client
+	logger       *zap.SugaredLogger
+	gitOpsHelper *GitOpsHelper
+}
+
+// RepoHookRequest is posted to the repo hook url, the hook is expected to answer with a 2xx once the action is done
+type RepoHookRequest struct {
+	Action      string `json:"action"`
+	RepoName    string `json:"repoName"`
+	RepoUrl     string `json:"repoUrl"`
+	Description string `json:"description,omitempty"`
+}
+
+func NewGitSshClient(config *bean.GitConfig, logger *zap.SugaredLogger, gitOpsHelper *GitOpsHelper, tlsConfig *tls.Config) (GitSshClient, error) {
+	if !strings.HasPrefix(config.GitHost, SSH_URL_PROTOCOL) && !strings.HasPrefix(config.GitHost, FILE_URL_PROTOCOL) {
+		logger.Errorw("invalid host for git ssh provider", "host", config.GitHost)
+		return GitSshClient{}, fmt.Errorf("invalid host url '%s', expected %s<user>@<host>/<path>", config.GitHost, SSH_URL_PROTOCOL)
+	}
+	gitOpsHelper.SetSshAuth(config.GetSshAuth())
+	return GitSshClient{
+		host:         strings.TrimSuffix(config.GitHost, "/"),
+		repoHookUrl:  config.RepoHookUrl,
+		hookToken:    config.GitToken,
+		httpClient:   globalUtil.GetHTTPClientWithTLSConfig(tlsConfig),
+		logger:       logger,
+		gitOpsHelper: gitOpsHelper,
+	}, nil
+}
+
+func (impl GitSshClient) getRepoCloneUrl(repoName string) string {
+	return fmt.Sprintf("%s/%s.git", impl.host, repoName)
+}
+
+func (impl GitSshClient) DeleteRepository(config *bean2.GitOpsConfigDto) error {
+	var err error
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("DeleteRepository", "GitSshClient", start, err)
+	}()
+	if len(impl.repoHookUrl) == 0 {
+		err = fmt.Errorf("repository %s can not be deleted as no repo hook is configured, delete it on the git server", config.GitRepoName)
+		return err
+	}
+	err = impl.callRepoHook(context.Background(), RepoHookActionDelete, config)
+	if err != nil {
+		impl.logger.Errorw("repo deletion failed for git ssh", "repo", config.GitRepoName, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl GitSshClient) CreateRepository(ctx context.Context, config *bean2.GitOpsConfigDto) (url string, isNew bool, isEmpty bool, detailedErrorGitOpsConfigActions DetailedErrorGitOpsConfigActions) {
+	var err error
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("CreateRepository", "GitSshClient", start, err)
+	}()
+
+	detailedErrorGitOpsConfigActions.StageErrorMap = make(map[string]error)
+	url, isEmpty, err = impl.GetRepoUrl(config)
+	if err == nil {
+		detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.GetRepoUrlStage)
+		return url, false, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	if len(impl.repoHookUrl) == 0 {
+		impl.logger.Errorw("git ssh repo not reachable and no repo hook configured", "repo", config.GitRepoName, "err", err)
+		err = fmt.Errorf("repository %s is not reachable, create it on the git server or configure a repo hook: %v", impl.getRepoCloneUrl(config.GitRepoName), err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[bean.GetRepoUrlStage] = err
+		return "", false, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	err = impl.callRepoHook(ctx, RepoHookActionCreate, config)
+	if err != nil {
+		impl.logger.Errorw("error in creating git ssh repo through hook", "repo", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[bean.CreateRepoStage] = err
+		return "", true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.CreateRepoStage)
+
+	url, isEmpty, err = impl.GetRepoUrl(config)
+	if err != nil {
+		impl.logger.Errorw("git ssh repo not reachable after creation", "repo", config.GitRepoName, "err", err)
+		detailedErrorGitOpsConfigActions.StageErrorMap[bean.CloneSshStage] = err
+		return "", true, isEmpty, detailedErrorGitOpsConfigActions
+	}
+	detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.CloneSshStage)
+
+	if isEmpty {
+		_, err = impl.CreateReadme(ctx, config)
+		if err != nil {
+			impl.logger.Errorw("error in creating readme git ssh", "repo", config.GitRepoName, "err", err)
+			detailedErrorGitOpsConfigActions.StageErrorMap[bean.CreateReadmeStage] = err
+			return url, true, isEmpty, detailedErrorGitOpsConfigActions
+		}
+		isEmpty = false //As we have created readme, repo is no longer empty
+		detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, bean.CreateReadmeStage)
+	}
+	return url, true, isEmpty, detailedErrorGitOpsConfigActions
+}
+
+func (impl GitSshClient) CreateFirstCommitOnHead(ctx context.Context, config *bean2.GitOpsConfigDto) (string, error) {
+	return impl.CreateReadme(ctx, config)
+}
+
+func (impl GitSshClient) CreateReadme(ctx context.Context, config *bean2.GitOpsConfigDto) (string, error) {
+	var err error
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("CreateReadme", "GitSshClient", start, err)
+	}()
+
+	cfg := &ChartConfig{
+		ChartName:      config.GitRepoName,
+		ChartLocation:  "",
+		FileName:       "README.md",
+		FileContent:    "@devtron",
+		ReleaseMessage: "readme",
+		ChartRepoName:  config.GitRepoName,
+		TargetRevision: config.TargetRevision,
+		UserName:       config.Username,
+		UserEmailId:    config.UserEmailId,
+	}
+	hash, _, err := impl.CommitValues(ctx, cfg, config, true)
+	if err != nil {
+		impl.logger.Errorw("error in creating readme git ssh", "repo", config.GitRepoName, "err", err)
+	}
+	return hash, err
+}
+
+// CommitValues has no api to commit a single file, so the repository is cloned, the file written and all changes pushed
+func (impl GitSshClient) CommitValues(ctx context.Context, config *ChartConfig, gitOpsConfig *bean2.GitOpsConfigDto, publishStatusConflictErrorMetrics bool) (commitHash string, commitTime time.Time, err error) {
+	start := time.Now()
+
+	branch := config.TargetRevision
+	if len(branch) == 0 {
+		branch = globalUtil.GetDefaultTargetRevision()
+	}
+	targetDir := fmt.Sprintf("ssh-commit/%s-%d", config.ChartRepoName, time.Now().UnixNano())
+	clonedDir, err := impl.gitOpsHelper.Clone(impl.getRepoCloneUrl(config.ChartRepoName), targetDir, branch)
+	defer os.RemoveAll(clonedDir)
+	if err != nil {
+		impl.logger.Errorw("error in cloning repo git ssh", "repo", config.ChartRepoName, "err", err)
+		globalUtil.TriggerGitOpsMetrics("CommitValues", "GitSshClient", start, err)
+		return "", time.Time{}, err
+	}
+	filePath := filepath.Join(clonedDir, config.ChartLocation, config.FileName)
+	err = os.MkdirAll(filepath.Dir(filePath), 0755)
+	if err == nil {
+		err = os.WriteFile(filePath, []byte(config.FileContent), 0644)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in writing file git ssh", "filePath", filePath, "err", err)
+		globalUtil.TriggerGitOpsMetrics("CommitValues", "GitSshClient", start, err)
+		return "", time.Time{}, err
+	}
+	commitHash, err = impl.gitOpsHelper.CommitAndPushAllChanges(ctx, clonedDir, branch, config.ReleaseMessage, config.UserName, config.UserEmailId)
+	if err != nil && strings.Contains(err.Error(), "push failed due to conflicts") {
+		impl.logger.Warnw("conflict found in commit git ssh", "repo", config.ChartRepoName, "err", err)
+		if publishStatusConflictErrorMetrics {
+			globalUtil.TriggerGitOpsMetrics("CommitValues", "GitSshClient", start, err)
+		}
+		return "", time.Time{}, retryFunc.NewRetryableError(err)
+	} else if err != nil {
+		impl.logger.Errorw("error in commit git ssh", "repo", config.ChartRepoName, "err", err)
+		globalUtil.TriggerGitOpsMetrics("CommitValues", "GitSshClient", start, err)
+		return "", time.Time{}, err
+	}
+	globalUtil.TriggerGitOpsMetrics("CommitValues", "GitSshClient", start, nil)
+	return commitHash, time.Now(), nil
+}
+
+// GetRepoUrl checks the repository with git ls-remote, a repository without branches is reported as empty
+func (impl GitSshClient) GetRepoUrl(config *bean2.GitOpsConfigDto) (repoUrl string, isRepoEmpty bool, err error) {
+	start := time.Now()
+	defer func() {
+		globalUtil.TriggerGitOpsMetrics("GetRepoUrl", "GitSshClient", start, err)
+	}()
+	repoUrl = impl.getRepoCloneUrl(config.GitRepoName)
+	branches, err := impl.gitOpsHelper.ListRemoteBranches(repoUrl)
+	if err != nil {
+		impl.logger.Errorw("error in getting repo url by repo name", "repoUrl", repoUrl, "err", err)
+		return "", false, err
+	}
+	return repoUrl, len(branches) == 0, nil
+}
+
+func (impl GitSshClient) callRepoHook(ctx context.Context, action string, config *bean2.GitOpsConfigDto) error {
+	payload, err := json.Marshal(&RepoHookRequest{
+		Action:      action,
+		RepoName:    config.GitRepoName,
+		RepoUrl:     impl.getRepoCloneUrl(config.GitRepoName),
+		Description: config.Description,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, impl.repoHookUrl, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(impl.hookToken) > 0 {
+		req.Header.Set("Authorization", "Bearer "+impl.hookToken)
+	}
+	resp, err := impl.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("repo hook returned status %d for %s: %s", resp.StatusCode, action, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	apiBean "github.com/devtron-labs/devtron/api/bean/gitOps"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/bean"
+	validationBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/validation/bean"
+	"github.com/stretchr/testify/assert"
+)
+
+// newGitSshTestClient points the git ssh provider at a directory of local bare repositories,
+// file:// remotes go through the same clone/ls-remote/push paths as ssh:// ones
+func newGitSshTestClient(t *testing.T, repoHookUrl string) (GitSshClient, string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not found")
+	}
+	logger, err := util.NewSugardLogger()
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	reposDir := t.TempDir()
+	cfg := &bean.GitConfig{
+		GitProvider: bean.GIT_SSH_PROVIDER,
+		GitHost:     "file://" + reposDir,
+		RepoHookUrl: repoHookUrl,
+	}
+	gitOpsHelper, err := NewGitOpsHelperImpl(cfg.GetAuth(), logger, cfg.GetTLSConfig(), false)
+	if err != nil {
+		t.Fatalf("failed to create GitOpsHelperImpl: %v", err)
+	}
+	client, err := NewGitSshClient(cfg, logger, gitOpsHelper, nil)
+	if err != nil {
+		t.Fatalf("failed to create git ssh client: %v", err)
+	}
+	return client, reposDir
+}
+
+func initBareRepo(t *testing.T, reposDir, repoName string) {
+	out, err := exec.Command("git", "init", "--bare", filepath.Join(reposDir, repoName+".git")).CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to init bare repo: %v, %s", err, out)
+	}
+}
+
+func readBareRepoFile(t *testing.T, reposDir, repoName, branch, filePath string) string {
+	out, err := exec.Command("git", "--git-dir", filepath.Join(reposDir, repoName+".git"), "show", branch+":"+filePath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to read %s from bare repo: %v, %s", filePath, err, out)
+	}
+	return string(out)
+}
+
+func getGitSshTestConfigDto(repoName string) *apiBean.GitOpsConfigDto {
+	return &apiBean.GitOpsConfigDto{
+		GitRepoName:    repoName,
+		Provider:       bean.GIT_SSH_PROVIDER,
+		Username:       "devtron",
+		UserEmailId:    "devtron@devtron.ai",
+		TargetRevision: "master",
+	}
+}
+
+func TestGitSshProvider(t *testing.T) {
+	t.Run("pre-created repository is reported as existing and empty", func(t *testing.T) {
+		client, reposDir := newGitSshTestClient(t, "")
+		initBareRepo(t, reposDir, "pre-created")
+		config := getGitSshTestConfigDto("pre-created")
+
+		repoUrl, isNew, isEmpty, detailedErrors := client.CreateRepository(context.Background(), config)
+		assert.Empty(t, detailedErrors.StageErrorMap)
+		assert.Equal(t, "file://"+reposDir+"/pre-created.git", repoUrl)
+		assert.False(t, isNew)
+		assert.True(t, isEmpty)
+	})
+
+	t.Run("missing repository without hook fails on get repo url", func(t *testing.T) {
+		client, _ := newGitSshTestClient(t, "")
+		_, _, _, detailedErrors := client.CreateRepository(context.Background(), getGitSshTestConfigDto("missing"))
+		assert.Contains(t, detailedErrors.StageErrorMap, validationBean.GetRepoUrlStage)
+	})
+
+	t.Run("commit values pushes files and updates them", func(t *testing.T) {
+		client, reposDir := newGitSshTestClient(t, "")
+		initBareRepo(t, reposDir, "commit-values")
+		config := getGitSshTestConfigDto("commit-values")
+		chartConfig := &ChartConfig{
+			ChartLocation:  "env/dev",
+			FileName:       "values.yaml",
+			FileContent:    "replicaCount: 1\n",
+			ReleaseMessage: "first values",
+			ChartRepoName:  "commit-values",
+			TargetRevision: "master",
+			UserName:       "devtron",
+			UserEmailId:    "devtron@devtron.ai",
+		}
+		commitHash, _, err := client.CommitValues(context.Background(), chartConfig, config, true)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, commitHash)
+		assert.Equal(t, "replicaCount: 1\n", readBareRepoFile(t, reposDir, "commit-values", "master", "env/dev/values.yaml"))
+
+		chartConfig.FileContent = "replicaCount: 2\n"
+		chartConfig.ReleaseMessage = "second values"
+		secondHash, _, err := client.CommitValues(context.Background(), chartConfig, config, true)
+		assert.NoError(t, err)
+		assert.NotEqual(t, commitHash, secondHash)
+		assert.Equal(t, "replicaCount: 2\n", readBareRepoFile(t, reposDir, "commit-values", "master", "env/dev/values.yaml"))
+
+		_, isEmpty, err := client.GetRepoUrl(config)
+		assert.NoError(t, err)
+		assert.False(t, isEmpty)
+	})
+
+	t.Run("repo hook creates and deletes repositories", func(t *testing.T) {
+		var reposDir string
+		hookRequests := make([]RepoHookRequest, 0)
+		hookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hookRequest := RepoHookRequest{}
+			if err := json.NewDecoder(r.Body).Decode(&hookRequest); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			hookRequests = append(hookRequests, hookRequest)
+			if hookRequest.Action == RepoHookActionCreate {
+				initBareRepo(t, reposDir, hookRequest.RepoName)
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer hookServer.Close()
+		client, dir := newGitSshTestClient(t, hookServer.URL)
+		reposDir = dir
+		config := getGitSshTestConfigDto("hook-created")
+
+		repoUrl, isNew, isEmpty, detailedErrors := client.CreateRepository(context.Background(), config)
+		assert.Empty(t, detailedErrors.StageErrorMap)
+		assert.True(t, isNew)
+		assert.False(t, isEmpty)
+		assert.True(t, strings.HasSuffix(repoUrl, "/hook-created.git"))
+		assert.Equal(t, "@devtron", readBareRepoFile(t, reposDir, "hook-created", "master", "README.md"))
+
+		err := client.DeleteRepository(config)
+		assert.NoError(t, err)
+		if assert.Len(t, hookRequests, 2) {
+			assert.Equal(t, RepoHookActionCreate, hookRequests[0].Action)
+			assert.Equal(t, RepoHookActionDelete, hookRequests[1].Action)
+			assert.Equal(t, repoUrl, hookRequests[1].RepoUrl)
+		}
+	})
+}
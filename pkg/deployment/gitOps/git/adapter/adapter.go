@@ -33,6 +33,11 @@ func ConvertGitOpsConfigToGitConfig(dto *bean2.GitOpsConfigDto) *bean.GitConfig
 		AzureProject:          dto.AzureProjectName,
 		BitbucketWorkspaceId:  dto.BitBucketWorkspaceId,
 		BitbucketProjectKey:   dto.BitBucketProjectKey,
+		GiteaOrgName:          dto.GiteaOrgName,
+		SshPrivateKey:         dto.SshPrivateKey,
+		SshKnownHosts:         dto.SshKnownHosts,
+		RepoHookUrl:           dto.RepoHookUrl,
+		DryRunRepoName:        dto.DryRunRepoName,
 		EnableTLSVerification: dto.EnableTLSVerification,
 	}
 	if dto.TLSConfig != nil {
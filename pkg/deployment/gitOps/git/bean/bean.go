@@ -19,6 +19,7 @@ package bean
 import (
 	"github.com/devtron-labs/devtron/api/bean"
 	git "github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/commandManager"
+	"net/url"
 )
 
 type GitConfig struct {
@@ -33,6 +34,11 @@ type GitConfig struct {
 	AzureProject         string
 	BitbucketWorkspaceId string
 	BitbucketProjectKey  string
+	GiteaOrgName         string
+	SshPrivateKey        string
+	SshKnownHosts        string
+	RepoHookUrl          string // GIT_SSH only, called to create/delete repositories
+	DryRunRepoName       string // GIT_SSH only, pre-created repository used for validation when no hook is set
 
 	IsActiveConfig bool //flag to check if the gitOps config is active
 
@@ -66,3 +72,42 @@ func (cfg GitConfig) GetTLSConfig() *bean.TLSConfig {
 		TLSKeyData:  cfg.TLSKey,
 	}
 }
+
+// GetSshAuth returns the deploy key for providers pushing over ssh, nil when no key is configured.
+// The ssh user is taken from the host url (ssh://<user>@host) and defaults to git.
+func (cfg GitConfig) GetSshAuth() *git.SshAuth {
+	if len(cfg.SshPrivateKey) == 0 {
+		return nil
+	}
+	sshAuth := &git.SshAuth{
+		User:       git.DEFAULT_SSH_USER,
+		PrivateKey: cfg.SshPrivateKey,
+		KnownHosts: cfg.SshKnownHosts,
+	}
+	if hostUrl, err := url.Parse(cfg.GitHost); err == nil && hostUrl.User != nil && len(hostUrl.User.Username()) > 0 {
+		sshAuth.User = hostUrl.User.Username()
+	}
+	return sshAuth
+}
+
+type CreatePullRequestRequest struct {
+	RepoName    string
+	HeadBranch  string
+	BaseBranch  string
+	Title       string
+	Description string
+}
+
+type PullRequestInfo struct {
+	Number int
+	Url    string
+	State  PullRequestState
+	// MergeCommitSha is set once the pull request is merged
+	MergeCommitSha string
+}
+
+// RepoFile is a file read from a cloned repository, Path is relative to the repository root
+type RepoFile struct {
+	Path    string
+	Content []byte
+}
@@ -27,7 +27,24 @@ const (
 	GITHUB_PROVIDER       = "GITHUB"
 	AZURE_DEVOPS_PROVIDER = "AZURE_DEVOPS"
 	BITBUCKET_PROVIDER    = "BITBUCKET_CLOUD"
-	GITHUB_API_V3         = "api/v3"
-	GITHUB_HOST           = "github.com"
-	GIT_TLS_DIR           = "/tmp/gitops/tls"
+	// GITEA_PROVIDER covers Gitea and its Forgejo fork, both serve the same /api/v1
+	GITEA_PROVIDER = "GITEA"
+	// GIT_SSH_PROVIDER is a plain git server reached over ssh with a deploy key,
+	// repositories are pre-created or created by a configurable hook
+	GIT_SSH_PROVIDER = "GIT_SSH"
+	GITHUB_API_V3    = "api/v3"
+	GITHUB_HOST      = "github.com"
+	GIT_TLS_DIR      = "/tmp/gitops/tls"
 )
+
+type PullRequestState string
+
+const (
+	PullRequestOpen   PullRequestState = "OPEN"
+	PullRequestMerged PullRequestState = "MERGED"
+	PullRequestClosed PullRequestState = "CLOSED"
+)
+
+func (s PullRequestState) IsTerminal() bool {
+	return s == PullRequestMerged || s == PullRequestClosed
+}
@@ -99,7 +99,7 @@ func (impl *GitCliManagerImpl) gitInit(ctx GitContext, rootDir string) error {
 		impl.logger.Errorw("error encountered in createFilesForTlsData", "err", err)
 	}
 	defer git_manager.DeleteTlsFiles(tlsPathInfo)
-	output, errMsg, err := impl.runCommandWithCred(cmd, ctx.auth, tlsPathInfo)
+	output, errMsg, err := impl.runCommandWithCred(cmd, ctx, tlsPathInfo)
 	impl.logger.Debugw("git init output", "root", rootDir, "opt", output, "errMsg", errMsg, "error", err)
 	return err
 }
@@ -123,7 +123,7 @@ func (impl *GitCliManagerImpl) commit(ctx GitContext, rootDir string, commitMsg
 		impl.logger.Errorw("error encountered in createFilesForTlsData", "err", err)
 	}
 	defer git_manager.DeleteTlsFiles(tlsPathInfo)
-	output, errMsg, err := impl.runCommandWithCred(cmd, ctx.auth, tlsPathInfo)
+	output, errMsg, err := impl.runCommandWithCred(cmd, ctx, tlsPathInfo)
 	impl.logger.Debugw("git commit output", "root", rootDir, "opt", output, "errMsg", errMsg, "error", err)
 	return output, errMsg, err
 }
@@ -138,7 +138,7 @@ func (impl *GitCliManagerImpl) lastCommitHash(ctx GitContext, rootDir string) (r
 		impl.logger.Errorw("error encountered in createFilesForTlsData", "err", err)
 	}
 	defer git_manager.DeleteTlsFiles(tlsPathInfo)
-	output, errMsg, err := impl.runCommandWithCred(cmd, ctx.auth, tlsPathInfo)
+	output, errMsg, err := impl.runCommandWithCred(cmd, ctx, tlsPathInfo)
 	impl.logger.Debugw("git commit output", "root", rootDir, "opt", output, "errMsg", errMsg, "error", err)
 	return output, errMsg, err
 }
@@ -153,7 +153,7 @@ func (impl *GitCliManagerImpl) add(ctx GitContext, rootDir string) (response, er
 		impl.logger.Errorw("error encountered in createFilesForTlsData", "err", err)
 	}
 	defer git_manager.DeleteTlsFiles(tlsPathInfo)
-	output, errMsg, err := impl.runCommandWithCred(cmd, ctx.auth, tlsPathInfo)
+	output, errMsg, err := impl.runCommandWithCred(cmd, ctx, tlsPathInfo)
 	impl.logger.Debugw("git add output", "root", rootDir, "opt", output, "errMsg", errMsg, "error", err)
 	return output, errMsg, err
 }
@@ -168,7 +168,7 @@ func (impl *GitCliManagerImpl) push(ctx GitContext, rootDir, targetRevision stri
 		impl.logger.Errorw("error encountered in createFilesForTlsData", "err", err)
 	}
 	defer git_manager.DeleteTlsFiles(tlsPathInfo)
-	output, errMsg, err := impl.runCommandWithCred(cmd, ctx.auth, tlsPathInfo)
+	output, errMsg, err := impl.runCommandWithCred(cmd, ctx, tlsPathInfo)
 	impl.logger.Debugw("git add output", "root", rootDir, "opt", output, "errMsg", errMsg, "error", err)
 	return output, errMsg, err
 }
@@ -183,7 +183,7 @@ func (impl *GitCliManagerImpl) gitCreateRemote(ctx GitContext, rootDir string, u
 		impl.logger.Errorw("error encountered in createFilesForTlsData", "err", err)
 	}
 	defer git_manager.DeleteTlsFiles(tlsPathInfo)
-	output, errMsg, err := impl.runCommandWithCred(cmd, ctx.auth, tlsPathInfo)
+	output, errMsg, err := impl.runCommandWithCred(cmd, ctx, tlsPathInfo)
 	impl.logger.Debugw("git remote output", "url", url, "opt", output, "errMsg", errMsg, "error", err)
 	return err
 }
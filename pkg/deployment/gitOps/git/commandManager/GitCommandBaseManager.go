@@ -37,6 +37,10 @@ type GitCommandManagerBase interface {
 	// In that case, use GetCurrentBranch to get the default branch.
 	GetDefaultBranch(ctx GitContext, rootDir string) (response, errMsg string, err error)
 	PullCli(ctx GitContext, rootDir string, branch string) (response, errMsg string, err error)
+	// LsRemote lists the branches of a remote repository without cloning it.
+	//	command: git ls-remote --heads <remoteUrl>
+	// An empty response with no error means the repository exists but is empty.
+	LsRemote(ctx GitContext, remoteUrl string) (response, errMsg string, err error)
 }
 
 type GitManagerBaseImpl struct {
@@ -58,7 +62,7 @@ func (impl *GitManagerBaseImpl) Fetch(ctx GitContext, rootDir string) (response,
 		impl.logger.Errorw("error encountered in createFilesForTlsData", "err", err)
 	}
 	defer git_manager.DeleteTlsFiles(tlsPathInfo)
-	output, errMsg, err := impl.runCommandWithCred(cmd, ctx.auth, tlsPathInfo)
+	output, errMsg, err := impl.runCommandWithCred(cmd, ctx, tlsPathInfo)
 	impl.logger.Debugw("fetch output", "root", rootDir, "opt", output, "errMsg", errMsg, "error", err)
 	return output, errMsg, err
 }
@@ -77,7 +81,7 @@ func (impl *GitManagerBaseImpl) ListBranch(ctx GitContext, rootDir string) (resp
 		impl.logger.Errorw("error encountered in createFilesForTlsData", "err", err)
 	}
 	defer git_manager.DeleteTlsFiles(tlsPathInfo)
-	output, errMsg, err := impl.runCommandWithCred(cmd, ctx.auth, tlsPathInfo)
+	output, errMsg, err := impl.runCommandWithCred(cmd, ctx, tlsPathInfo)
 	impl.logger.Debugw("git branch -r output", "root", rootDir, "opt", output, "errMsg", errMsg, "error", err)
 	return output, errMsg, err
 }
@@ -96,7 +100,7 @@ func (impl *GitManagerBaseImpl) GetDefaultBranch(ctx GitContext, rootDir string)
 		impl.logger.Errorw("error encountered in createFilesForTlsData", "err", err)
 	}
 	defer git_manager.DeleteTlsFiles(tlsPathInfo)
-	output, errMsg, err := impl.runCommandWithCred(cmd, ctx.auth, tlsPathInfo)
+	output, errMsg, err := impl.runCommandWithCred(cmd, ctx, tlsPathInfo)
 	impl.logger.Debugw("git rev-parse --abbrev-ref origin/HEAD output", "root", rootDir, "opt", output, "errMsg", errMsg, "error", err)
 	return output, errMsg, err
 }
@@ -112,7 +116,7 @@ func (impl *GitManagerBaseImpl) PullCli(ctx GitContext, rootDir string, branch s
 		impl.logger.Errorw("error encountered in createFilesForTlsData", "err", err)
 	}
 	defer git_manager.DeleteTlsFiles(tlsPathInfo)
-	output, errMsg, err := impl.runCommandWithCred(cmd, ctx.auth, tlsPathInfo)
+	output, errMsg, err := impl.runCommandWithCred(cmd, ctx, tlsPathInfo)
 	if err != nil {
 		if !IsAlreadyUpToDateError(response, errMsg) {
 			util.TriggerGitOpsMetrics("Pull", "GitCli", start, err)
@@ -123,12 +127,41 @@ func (impl *GitManagerBaseImpl) PullCli(ctx GitContext, rootDir string, branch s
 	return output, errMsg, err
 }
 
-func (impl *GitManagerBaseImpl) runCommandWithCred(cmd *exec.Cmd, auth *BasicAuth, tlsPathInfo *git_manager.TlsPathInfo) (response, errMsg string, err error) {
+func (impl *GitManagerBaseImpl) LsRemote(ctx GitContext, remoteUrl string) (response, errMsg string, err error) {
+	start := time.Now()
+	defer func() {
+		util.TriggerGitOpsMetrics("LsRemote", "GitCli", start, err)
+	}()
+	impl.logger.Debugw("git ls-remote --heads", "remoteUrl", remoteUrl)
+	cmd, cancel := impl.createCmdWithContext(ctx, "git", "ls-remote", "--heads", remoteUrl)
+	defer cancel()
+	tlsPathInfo, err := git_manager.CreateFilesForTlsData(git_manager.BuildTlsData(ctx.TLSKey, ctx.TLSCertificate, ctx.CACert, ctx.TLSVerificationEnabled), TLS_FOLDER)
+	if err != nil {
+		//making it non-blocking
+		impl.logger.Errorw("error encountered in createFilesForTlsData", "err", err)
+	}
+	defer git_manager.DeleteTlsFiles(tlsPathInfo)
+	output, errMsg, err := impl.runCommandWithCred(cmd, ctx, tlsPathInfo)
+	impl.logger.Debugw("git ls-remote output", "remoteUrl", remoteUrl, "opt", output, "errMsg", errMsg, "error", err)
+	return output, errMsg, err
+}
+
+func (impl *GitManagerBaseImpl) runCommandWithCred(cmd *exec.Cmd, ctx GitContext, tlsPathInfo *git_manager.TlsPathInfo) (response, errMsg string, err error) {
+	auth := ctx.auth
 	cmd.Env = append(os.Environ(),
 		fmt.Sprintf("GIT_ASKPASS=%s", GIT_ASK_PASS),
 		fmt.Sprintf("GIT_USERNAME=%s", auth.Username),
 		fmt.Sprintf("GIT_PASSWORD=%s", auth.Password),
 	)
+	if ctx.sshAuth != nil {
+		sshCommand, cleanUp, err := createSshCommand(ctx.sshAuth)
+		if err != nil {
+			impl.logger.Errorw("error in creating ssh command for git", "err", err)
+			return "", "", err
+		}
+		defer cleanUp()
+		cmd.Env = append(cmd.Env, fmt.Sprintf("GIT_SSH_COMMAND=%s", sshCommand))
+	}
 	if tlsPathInfo != nil {
 		if tlsPathInfo.TlsKeyPath != "" && tlsPathInfo.TlsCertPath != "" {
 			cmd.Env = append(cmd.Env,
@@ -21,7 +21,11 @@ import (
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitSsh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"os"
 	"time"
 )
 
@@ -48,8 +52,12 @@ func (impl *GoGitSDKManagerImpl) Pull(ctx GitContext, targetRevision string, rep
 		return err
 	}
 	//-----------pull
+	authMethod, err := ctx.toAuthMethod()
+	if err != nil {
+		return err
+	}
 	pullOptions := &git.PullOptions{
-		Auth: ctx.auth.ToBasicAuth(),
+		Auth: authMethod,
 	}
 	if len(ctx.CACert) > 0 {
 		pullOptions.CABundle = []byte(ctx.CACert)
@@ -108,8 +116,12 @@ func (impl *GoGitSDKManagerImpl) CommitAndPush(ctx GitContext, repoRoot, targetR
 	impl.logger.Debugw("git hash", "repo", repoRoot, "hash", commit.String())
 	//-----------push
 
+	authMethod, err := ctx.toAuthMethod()
+	if err != nil {
+		return commit.String(), err
+	}
 	pushOptions := &git.PushOptions{
-		Auth: ctx.auth.ToBasicAuth(),
+		Auth: authMethod,
 	}
 	if len(ctx.CACert) > 0 {
 		pushOptions.CABundle = []byte(ctx.CACert)
@@ -125,3 +137,37 @@ func (auth *BasicAuth) ToBasicAuth() *http.BasicAuth {
 		Password: auth.Password,
 	}
 }
+
+// toAuthMethod prefers the ssh deploy key when present, falling back to basic auth over https
+func (gitCtx GitContext) toAuthMethod() (transport.AuthMethod, error) {
+	if gitCtx.sshAuth == nil {
+		return gitCtx.auth.ToBasicAuth(), nil
+	}
+	return gitCtx.sshAuth.ToPublicKeys()
+}
+
+func (auth *SshAuth) ToPublicKeys() (*gitSsh.PublicKeys, error) {
+	user := auth.User
+	if len(user) == 0 {
+		user = DEFAULT_SSH_USER
+	}
+	publicKeys, err := gitSsh.NewPublicKeys(user, []byte(auth.PrivateKey), "")
+	if err != nil {
+		return nil, err
+	}
+	if len(auth.KnownHosts) == 0 {
+		return nil, ErrSshKnownHostsMissing
+	}
+	// knownhosts only reads from files, the callback keeps the parsed keys in memory so the file can go right away
+	knownHostsFile, err := writeSshFile("known-hosts-*", auth.KnownHosts)
+	defer os.Remove(knownHostsFile)
+	if err != nil {
+		return nil, err
+	}
+	hostKeyCallback, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, err
+	}
+	publicKeys.HostKeyCallback = hostKeyCallback
+	return publicKeys, nil
+}
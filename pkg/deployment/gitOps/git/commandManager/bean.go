@@ -18,14 +18,21 @@ package commandManager
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
 const TLS_FOLDER = "/tmp/tls"
+const SSH_FOLDER = "/tmp/gitops-ssh"
+const DEFAULT_SSH_USER = "git"
+
+// ErrSshKnownHostsMissing is returned for git over ssh without known hosts, the host key is never left unverified
+var ErrSshKnownHostsMissing = errors.New("known hosts are required to verify the host key of the git server over ssh")
 
 type GitContext struct {
 	context.Context
 	auth                   *BasicAuth
+	sshAuth                *SshAuth
 	CACert                 string
 	TLSKey                 string
 	TLSCertificate         string
@@ -37,6 +44,12 @@ func (gitCtx GitContext) WithCredentials(auth *BasicAuth) GitContext {
 	return gitCtx
 }
 
+// WithSshAuth sets the deploy key used for repositories accessed over ssh, a nil value keeps basic auth
+func (gitCtx GitContext) WithSshAuth(sshAuth *SshAuth) GitContext {
+	gitCtx.sshAuth = sshAuth
+	return gitCtx
+}
+
 func (gitCtx GitContext) WithTLSData(caData string, tlsKey string, tlsCertificate string, tlsVerificationEnabled bool) GitContext {
 	gitCtx.CACert = caData
 	gitCtx.TLSKey = tlsKey
@@ -61,3 +74,11 @@ func (gitCtx GitContext) WithTimeout(timeoutSeconds int) (GitContext, context.Ca
 type BasicAuth struct {
 	Username, Password string
 }
+
+// SshAuth represents a deploy key used for git over ssh
+type SshAuth struct {
+	User       string
+	PrivateKey string
+	// KnownHosts is the content of a known_hosts file, git operations over ssh fail without it
+	KnownHosts string
+}
@@ -17,6 +17,7 @@
 package commandManager
 
 import (
+	"fmt"
 	"github.com/go-git/go-billy/v5/osfs"
 	"os"
 	"path/filepath"
@@ -42,3 +43,50 @@ func IsAlreadyUpToDateError(response, errMsg string) bool {
 	}
 	return false
 }
+
+// createSshCommand writes the deploy key and known hosts to files readable only by the current user
+// and returns the ssh command git should use along with a func removing those files
+func createSshCommand(sshAuth *SshAuth) (sshCommand string, cleanUp func(), err error) {
+	files := make([]string, 0, 2)
+	cleanUp = func() {
+		for _, file := range files {
+			_ = os.Remove(file)
+		}
+	}
+	if len(sshAuth.KnownHosts) == 0 {
+		return "", cleanUp, ErrSshKnownHostsMissing
+	}
+	keyFile, err := writeSshFile("key-*", sshAuth.PrivateKey)
+	if err != nil {
+		return "", cleanUp, err
+	}
+	files = append(files, keyFile)
+	knownHostsFile, err := writeSshFile("known-hosts-*", sshAuth.KnownHosts)
+	if err != nil {
+		return "", cleanUp, err
+	}
+	files = append(files, knownHostsFile)
+	sshCommand = fmt.Sprintf("ssh -i %s -o IdentitiesOnly=yes -o StrictHostKeyChecking=yes -o UserKnownHostsFile=%s", keyFile, knownHostsFile)
+	return sshCommand, cleanUp, nil
+}
+
+func writeSshFile(pattern string, content string) (string, error) {
+	err := os.MkdirAll(SSH_FOLDER, 0700)
+	if err != nil {
+		return "", err
+	}
+	file, err := os.CreateTemp(SSH_FOLDER, pattern)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	// ssh refuses keys readable by others and keys without a trailing new line
+	if !strings.HasSuffix(content, "\n") {
+		content = content + "\n"
+	}
+	if err = file.Chmod(0600); err != nil {
+		return file.Name(), err
+	}
+	_, err = file.WriteString(content)
+	return file.Name(), err
+}
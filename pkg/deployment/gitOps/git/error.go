@@ -21,3 +21,5 @@ import (
 )
 
 var BitbucketRepoNotFoundError = fmt.Errorf("404 Not Found")
+
+var PullRequestNotSupportedError = fmt.Errorf("pull request mode is not supported by the configured gitops provider")
@@ -0,0 +1,386 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package monorepo
+
+import (
+	"context"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/bean/gitOps"
+	"github.com/devtron-labs/devtron/client/argocdServer"
+	argoBean "github.com/devtron-labs/devtron/client/argocdServer/bean"
+	appRepository "github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/common"
+	"github.com/devtron-labs/devtron/pkg/deployment/common/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
+	configBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/config/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
+	monorepoBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/monorepo/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	globalUtil "github.com/devtron-labs/devtron/util"
+	"go.uber.org/zap"
+	"net/http"
+	"path"
+)
+
+type GitOpsMonorepoService interface {
+	// GetPathPrefix renders the monorepo directory of the app in envId, 0 for the app level chart.
+	// It is empty when the active gitops configuration creates a repository per app.
+	GetPathPrefix(appId, envId int) (string, error)
+	// ApplyPathPrefix moves the chart location of config under its monorepo directory when config points to the
+	// monorepo of the active gitops configuration, configs of per app repositories are left untouched
+	ApplyPathPrefix(config *bean.DeploymentConfig) error
+	// UpdatePathPrefix applies the path prefix on config and saves it if it was moved under a monorepo directory
+	UpdatePathPrefix(config *bean.DeploymentConfig, userId int32) (*bean.DeploymentConfig, error)
+	// MigrateApps copies the charts of the apps from their per app repositories to the monorepo and points the
+	// deployment configs and argocd applications to it, the per app repositories are kept as they are
+	MigrateApps(ctx context.Context, request *monorepoBean.MigrationRequest) (*monorepoBean.MigrationResponse, error)
+}
+
+type GitOpsMonorepoServiceImpl struct {
+	logger                   *zap.SugaredLogger
+	gitOpsConfigReadService  config.GitOpsConfigReadService
+	gitOperationService      git.GitOperationService
+	appRepository            appRepository.AppRepository
+	environmentRepository    repository.EnvironmentRepository
+	pipelineRepository       pipelineConfig.PipelineRepository
+	chartRepository          chartRepoRepository.ChartRepository
+	deploymentConfigService  common.DeploymentConfigService
+	argoClientWrapperService argocdServer.ArgoClientWrapperService
+	*sql.TransactionUtilImpl
+}
+
+func NewGitOpsMonorepoServiceImpl(logger *zap.SugaredLogger,
+	gitOpsConfigReadService config.GitOpsConfigReadService,
+	gitOperationService git.GitOperationService,
+	appRepository appRepository.AppRepository,
+	environmentRepository repository.EnvironmentRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	chartRepository chartRepoRepository.ChartRepository,
+	deploymentConfigService common.DeploymentConfigService,
+	argoClientWrapperService argocdServer.ArgoClientWrapperService,
+	transactionUtilImpl *sql.TransactionUtilImpl) *GitOpsMonorepoServiceImpl {
+	return &GitOpsMonorepoServiceImpl{
+		logger:                   logger,
+		gitOpsConfigReadService:  gitOpsConfigReadService,
+		gitOperationService:      gitOperationService,
+		appRepository:            appRepository,
+		environmentRepository:    environmentRepository,
+		pipelineRepository:       pipelineRepository,
+		chartRepository:          chartRepository,
+		deploymentConfigService:  deploymentConfigService,
+		argoClientWrapperService: argoClientWrapperService,
+		TransactionUtilImpl:      transactionUtilImpl,
+	}
+}
+
+func (impl *GitOpsMonorepoServiceImpl) GetPathPrefix(appId, envId int) (string, error) {
+	monorepoLayout, err := impl.gitOpsConfigReadService.GetMonorepoLayout()
+	if err != nil {
+		return "", err
+	} else if monorepoLayout == nil {
+		return "", nil
+	}
+	return impl.getPathPrefix(monorepoLayout, appId, envId)
+}
+
+func (impl *GitOpsMonorepoServiceImpl) getPathPrefix(monorepoLayout *configBean.MonorepoLayout, appId, envId int) (string, error) {
+	app, err := impl.appRepository.FindAppAndProjectByAppId(appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching app", "appId", appId, "err", err)
+		return "", err
+	}
+	var envName string
+	if envId > 0 {
+		env, err := impl.environmentRepository.FindById(envId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment", "envId", envId, "err", err)
+			return "", err
+		}
+		envName = env.Name
+	}
+	return monorepoLayout.GetPathPrefix(app.Team.Name, app.AppName, envName), nil
+}
+
+func (impl *GitOpsMonorepoServiceImpl) ApplyPathPrefix(config *bean.DeploymentConfig) error {
+	if config == nil || config.ReleaseConfiguration == nil || config.IsLinkedRelease() || len(config.GetGitOpsPathPrefix()) != 0 {
+		return nil
+	}
+	monorepoLayout, err := impl.gitOpsConfigReadService.GetMonorepoLayout()
+	if err != nil {
+		return err
+	} else if monorepoLayout == nil {
+		return nil
+	}
+	if impl.gitOpsConfigReadService.GetGitOpsRepoNameFromUrl(config.GetRepoURL()) != monorepoLayout.RepoName {
+		return nil
+	}
+	prefix, err := impl.getPathPrefix(monorepoLayout, config.AppId, config.EnvironmentId)
+	if err != nil {
+		return err
+	}
+	config.SetGitOpsPathPrefix(prefix)
+	return nil
+}
+
+func (impl *GitOpsMonorepoServiceImpl) UpdatePathPrefix(config *bean.DeploymentConfig, userId int32) (*bean.DeploymentConfig, error) {
+	if len(config.GetGitOpsPathPrefix()) != 0 {
+		return config, nil
+	}
+	err := impl.ApplyPathPrefix(config)
+	if err != nil {
+		impl.logger.Errorw("error in applying gitops monorepo path", "appId", config.AppId, "envId", config.EnvironmentId, "err", err)
+		return nil, err
+	}
+	if len(config.GetGitOpsPathPrefix()) == 0 {
+		return config, nil
+	}
+	config, err = impl.deploymentConfigService.CreateOrUpdateConfig(nil, config, userId)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment config", "appId", config.AppId, "envId", config.EnvironmentId, "err", err)
+		return nil, err
+	}
+	return config, nil
+}
+
+func (impl *GitOpsMonorepoServiceImpl) MigrateApps(ctx context.Context, request *monorepoBean.MigrationRequest) (*monorepoBean.MigrationResponse, error) {
+	monorepoLayout, err := impl.gitOpsConfigReadService.GetMonorepoLayout()
+	if err != nil {
+		return nil, err
+	} else if monorepoLayout == nil {
+		return nil, util.NewApiError(http.StatusPreconditionFailed,
+			"Active gitops configuration does not use the MONOREPO repository layout",
+			"active gitops configuration does not use the MONOREPO repository layout")
+	}
+	response := &monorepoBean.MigrationResponse{
+		Apps: make([]*monorepoBean.AppMigrationResult, 0, len(request.AppIds)),
+	}
+	var monorepoUrl string
+	for _, appId := range request.AppIds {
+		app, err := impl.appRepository.FindAppAndProjectByAppId(appId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching app", "appId", appId, "err", err)
+			response.Apps = append(response.Apps, monorepoBean.NewAppMigrationResult(appId, "").
+				WithStatus(monorepoBean.MigrationStatusFailed, "app not found"))
+			continue
+		}
+		result := monorepoBean.NewAppMigrationResult(app.Id, app.AppName)
+		response.Apps = append(response.Apps, result)
+		appConfig, envConfigs, skipReason, err := impl.getConfigsToMigrate(monorepoLayout, app.Id)
+		if err != nil {
+			result.WithStatus(monorepoBean.MigrationStatusFailed, err.Error())
+			continue
+		} else if len(skipReason) != 0 {
+			result.WithStatus(monorepoBean.MigrationStatusSkipped, skipReason)
+			continue
+		}
+		result.SourceRepoUrl = appConfig.GetRepoURL()
+		envPrefixes := make(map[int]string, len(envConfigs))
+		for _, envConfig := range envConfigs {
+			prefix, err := impl.getPathPrefix(monorepoLayout, app.Id, envConfig.EnvironmentId)
+			if err != nil {
+				break
+			}
+			envPrefixes[envConfig.EnvironmentId] = prefix
+			env, err := impl.environmentRepository.FindById(envConfig.EnvironmentId)
+			if err != nil {
+				impl.logger.Errorw("error in fetching environment", "envId", envConfig.EnvironmentId, "err", err)
+				break
+			}
+			result.Environments = append(result.Environments, &monorepoBean.EnvMigrationResult{
+				EnvironmentId:   envConfig.EnvironmentId,
+				EnvironmentName: env.Name,
+				SourcePath:      envConfig.GetChartLocation(),
+				TargetPath:      path.Join(prefix, envConfig.GetChartLocation()),
+			})
+		}
+		if len(result.Environments) != len(envConfigs) {
+			result.WithStatus(monorepoBean.MigrationStatusFailed, "error in resolving monorepo path of environments")
+			continue
+		}
+		if request.DryRun {
+			result.WithStatus(monorepoBean.MigrationStatusDryRun, "")
+			continue
+		}
+		if len(monorepoUrl) == 0 {
+			monorepoUrl, err = impl.createMonorepo(ctx, monorepoLayout, request.UserId)
+			if err != nil {
+				return nil, err
+			}
+		}
+		result.TargetRepoUrl = monorepoUrl
+		err = impl.migrateApp(ctx, monorepoLayout, monorepoUrl, appConfig, envConfigs, envPrefixes, result, request.UserId)
+		if err != nil {
+			impl.logger.Errorw("error in migrating app to gitops monorepo", "appId", app.Id, "err", err)
+			result.WithStatus(monorepoBean.MigrationStatusFailed, err.Error())
+			continue
+		}
+		result.WithStatus(monorepoBean.MigrationStatusMigrated, impl.patchArgoApplications(ctx, app.Id, envConfigs))
+	}
+	return response, nil
+}
+
+// getConfigsToMigrate returns the app level config and the configs of argocd pipelines deploying from the app repository,
+// skipReason is set for apps which can not be moved to the monorepo
+func (impl *GitOpsMonorepoServiceImpl) getConfigsToMigrate(monorepoLayout *configBean.MonorepoLayout, appId int) (appConfig *bean.DeploymentConfig, envConfigs []*bean.DeploymentConfig, skipReason string, err error) {
+	appConfig, err = impl.deploymentConfigService.GetConfigForDevtronApps(appId, 0)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment config", "appId", appId, "err", err)
+		return nil, nil, "", err
+	}
+	if gitOps.IsGitOpsRepoNotConfigured(appConfig.GetRepoURL()) {
+		return nil, nil, "gitops repository is not configured", nil
+	} else if common.IsCustomGitOpsRepo(appConfig.ConfigType) {
+		return nil, nil, "apps with custom gitops repository are not migrated", nil
+	} else if impl.gitOpsConfigReadService.GetGitOpsRepoNameFromUrl(appConfig.GetRepoURL()) == monorepoLayout.RepoName {
+		return nil, nil, "app is already in the monorepo", nil
+	}
+	pipelines, err := impl.pipelineRepository.FindActiveByAppId(appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipelines", "appId", appId, "err", err)
+		return nil, nil, "", err
+	}
+	envConfigs = make([]*bean.DeploymentConfig, 0, len(pipelines))
+	for _, pipeline := range pipelines {
+		envConfig, err := impl.deploymentConfigService.GetConfigForDevtronApps(appId, pipeline.EnvironmentId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching deployment config", "appId", appId, "envId", pipeline.EnvironmentId, "err", err)
+			return nil, nil, "", err
+		}
+		if envConfig.IsLinkedRelease() || envConfig.GetRepoURL() != appConfig.GetRepoURL() {
+			continue
+		} else if !envConfig.IsAcdRelease() {
+			return nil, nil, fmt.Sprintf("%s pipeline in environment %d deploys from the app repository, only argocd pipelines are migrated", envConfig.DeploymentAppType, pipeline.EnvironmentId), nil
+		}
+		envConfigs = append(envConfigs, envConfig)
+	}
+	return appConfig, envConfigs, "", nil
+}
+
+func (impl *GitOpsMonorepoServiceImpl) createMonorepo(ctx context.Context, monorepoLayout *configBean.MonorepoLayout, userId int32) (string, error) {
+	targetRevision := globalUtil.GetDefaultTargetRevision()
+	chartGitAttr, err := impl.gitOperationService.CreateGitRepositoryForDevtronApp(ctx, monorepoLayout.RepoName, targetRevision, userId)
+	if err != nil {
+		impl.logger.Errorw("error in creating gitops monorepo", "repoName", monorepoLayout.RepoName, "err", err)
+		return "", err
+	}
+	err = impl.argoClientWrapperService.RegisterGitOpsRepoInArgoWithRetry(ctx, chartGitAttr.RepoUrl, targetRevision, userId)
+	if err != nil {
+		impl.logger.Errorw("error in registering gitops monorepo in argocd", "repoUrl", chartGitAttr.RepoUrl, "err", err)
+		return "", err
+	}
+	return chartGitAttr.RepoUrl, nil
+}
+
+// migrateApp copies the charts of every environment to the monorepo and then switches charts and deployment configs
+// of the app to it in a single transaction
+func (impl *GitOpsMonorepoServiceImpl) migrateApp(ctx context.Context, monorepoLayout *configBean.MonorepoLayout, monorepoUrl string,
+	appConfig *bean.DeploymentConfig, envConfigs []*bean.DeploymentConfig, envPrefixes map[int]string,
+	result *monorepoBean.AppMigrationResult, userId int32) error {
+	for i, envConfig := range envConfigs {
+		envResult := result.Environments[i]
+		commitHash, err := impl.gitOperationService.CopyChartToRepo(ctx, envConfig.GetRepoURL(), envConfig.GetTargetRevision(), envResult.SourcePath,
+			monorepoLayout.RepoName, monorepoUrl, envConfig.GetTargetRevision(), envResult.TargetPath, userId)
+		if err != nil {
+			return fmt.Errorf("error in copying chart of environment %s: %w", envResult.EnvironmentName, err)
+		}
+		envResult.CommitHash = commitHash
+	}
+	appPrefix, err := impl.getPathPrefix(monorepoLayout, appConfig.AppId, 0)
+	if err != nil {
+		return err
+	}
+	tx, err := impl.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.RollbackTx(tx)
+	charts, err := impl.chartRepository.FindActiveChartsByAppId(appConfig.AppId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching charts", "appId", appConfig.AppId, "err", err)
+		return err
+	}
+	updatedCharts := make([]*chartRepoRepository.Chart, 0, len(charts))
+	for _, chart := range charts {
+		if !chart.IsCustomGitRepository {
+			chart.GitRepoUrl = monorepoUrl
+			chart.UpdateAuditLog(userId)
+			updatedCharts = append(updatedCharts, chart)
+		}
+	}
+	err = impl.chartRepository.UpdateAllInTx(tx, updatedCharts)
+	if err != nil {
+		impl.logger.Errorw("error in updating charts", "appId", appConfig.AppId, "err", err)
+		return err
+	}
+	appConfig.SetRepoURL(monorepoUrl).SetGitOpsPathPrefix(appPrefix)
+	_, err = impl.deploymentConfigService.CreateOrUpdateConfig(tx, appConfig, userId)
+	if err != nil {
+		impl.logger.Errorw("error in updating deployment config", "appId", appConfig.AppId, "err", err)
+		return err
+	}
+	for _, envConfig := range envConfigs {
+		envConfig.SetRepoURL(monorepoUrl).SetGitOpsPathPrefix(envPrefixes[envConfig.EnvironmentId])
+		_, err = impl.deploymentConfigService.CreateOrUpdateConfig(tx, envConfig, userId)
+		if err != nil {
+			impl.logger.Errorw("error in updating deployment config", "appId", envConfig.AppId, "envId", envConfig.EnvironmentId, "err", err)
+			return err
+		}
+	}
+	return impl.CommitTx(tx)
+}
+
+// patchArgoApplications points the created argocd applications to the monorepo, applications failing here are
+// patched on their next deployment, the returned message lists them
+func (impl *GitOpsMonorepoServiceImpl) patchArgoApplications(ctx context.Context, appId int, envConfigs []*bean.DeploymentConfig) string {
+	pipelines, err := impl.pipelineRepository.FindActiveByAppId(appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipelines", "appId", appId, "err", err)
+		return "argocd applications will be updated on next deployment"
+	}
+	argoAppNames := make(map[int]string, len(pipelines))
+	for _, pipeline := range pipelines {
+		if pipeline.DeploymentAppCreated {
+			argoAppNames[pipeline.EnvironmentId] = pipeline.DeploymentAppName
+		}
+	}
+	failedArgoApps := make([]string, 0)
+	for _, envConfig := range envConfigs {
+		argoAppName, ok := argoAppNames[envConfig.EnvironmentId]
+		if !ok {
+			continue
+		}
+		err = impl.argoClientWrapperService.PatchArgoCdApp(ctx, &argoBean.ArgoCdAppPatchReqDto{
+			ArgoAppName:    argoAppName,
+			ChartLocation:  envConfig.GetChartLocation(),
+			GitRepoUrl:     envConfig.GetRepoURL(),
+			TargetRevision: envConfig.GetTargetRevision(),
+			PatchType:      argoBean.PatchTypeMerge,
+		})
+		if err != nil {
+			impl.logger.Errorw("error in patching argocd application source", "argoAppName", argoAppName, "err", err)
+			failedArgoApps = append(failedArgoApps, argoAppName)
+		}
+	}
+	if len(failedArgoApps) != 0 {
+		return fmt.Sprintf("argocd applications %v will be updated on next deployment", failedArgoApps)
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bean
+
+type MigrationStatus string
+
+const (
+	MigrationStatusMigrated MigrationStatus = "Migrated"
+	MigrationStatusDryRun   MigrationStatus = "DryRun"
+	MigrationStatusSkipped  MigrationStatus = "Skipped"
+	MigrationStatusFailed   MigrationStatus = "Failed"
+)
+
+// MigrationRequest moves apps from their per app gitops repository to the monorepo of the active gitops configuration
+type MigrationRequest struct {
+	AppIds []int `json:"appIds" validate:"min=1"`
+	// DryRun only reports the paths the charts would be moved to
+	DryRun bool  `json:"dryRun"`
+	UserId int32 `json:"-"`
+}
+
+type MigrationResponse struct {
+	Apps []*AppMigrationResult `json:"apps"`
+}
+
+type AppMigrationResult struct {
+	AppId         int                   `json:"appId"`
+	AppName       string                `json:"appName"`
+	Status        MigrationStatus       `json:"status"`
+	Message       string                `json:"message,omitempty"`
+	SourceRepoUrl string                `json:"sourceRepoUrl,omitempty"`
+	TargetRepoUrl string                `json:"targetRepoUrl,omitempty"`
+	Environments  []*EnvMigrationResult `json:"environments"`
+}
+
+type EnvMigrationResult struct {
+	EnvironmentId   int    `json:"environmentId"`
+	EnvironmentName string `json:"environmentName"`
+	SourcePath      string `json:"sourcePath"`
+	TargetPath      string `json:"targetPath"`
+	CommitHash      string `json:"commitHash,omitempty"`
+}
+
+func NewAppMigrationResult(appId int, appName string) *AppMigrationResult {
+	return &AppMigrationResult{
+		AppId:        appId,
+		AppName:      appName,
+		Environments: make([]*EnvMigrationResult, 0),
+	}
+}
+
+func (r *AppMigrationResult) WithStatus(status MigrationStatus, message string) *AppMigrationResult {
+	r.Status = status
+	r.Message = message
+	return r
+}
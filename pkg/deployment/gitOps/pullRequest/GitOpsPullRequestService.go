@@ -0,0 +1,458 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package pullRequest
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	"github.com/devtron-labs/devtron/client/argocdServer"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/timelineStatus"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/app/status"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	environmentRepository "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/common"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
+	gitBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/pullRequest/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/pullRequest/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cronUtil "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type GitOpsPullRequestConfig struct {
+	PollIntervalSecs int `env:"GITOPS_PULL_REQUEST_POLL_INTERVAL_SECS" envDefault:"60" description:"Interval in seconds at which pull requests of deployments awaiting merge are polled, 0 disables the poller"`
+}
+
+// GitOpsPullRequestService manages the per environment pull request mode of gitops commits
+// and moves the deployments waiting on a pull request forward once it is merged or closed
+type GitOpsPullRequestService interface {
+	GetPrModeConfig(envId int) (*bean.PrModeConfigDto, error)
+	GetAllPrModeConfigs() ([]*bean.PrModeConfigDto, error)
+	SavePrModeConfig(request *bean.PrModeConfigDto, userId int32) (*bean.PrModeConfigDto, error)
+	IsPrModeEnabled(envId int) (bool, error)
+	// MarkRunnerAwaitingMerge stores the pull request on the deploy runner and parks the runner till the pull request is merged or closed.
+	// Pull requests of earlier deployments of the pipeline still open are closed and their runners superseded
+	MarkRunnerAwaitingMerge(ctx context.Context, wfrId int, branch string, prInfo *gitBean.PullRequestInfo, userId int32) error
+	IsRunnerAwaitingMerge(wfrId int) (bool, error)
+	// SyncRunnersAwaitingMerge resumes the deployments whose pull request is merged and fails the ones whose pull request is closed.
+	// Every replica polls, a runner is moved on only by the replica whose conditional status update succeeds
+	SyncRunnersAwaitingMerge()
+}
+
+type GitOpsPullRequestServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	prModeConfigRepository        repository.GitOpsPrModeConfigRepository
+	environmentRepository         environmentRepository.EnvironmentRepository
+	cdWorkflowRepository          pipelineConfig.CdWorkflowRepository
+	pipelineOverrideRepository    chartConfig.PipelineOverrideRepository
+	pipelineStatusTimelineService status.PipelineStatusTimelineService
+	gitOperationService           git.GitOperationService
+	gitOpsConfigReadService       config.GitOpsConfigReadService
+	deploymentConfigService       common.DeploymentConfigService
+	argoClientWrapperService      argocdServer.ArgoClientWrapperService
+	acdConfig                     *argocdServer.ACDConfig
+	*sql.TransactionUtilImpl
+}
+
+func NewGitOpsPullRequestServiceImpl(logger *zap.SugaredLogger,
+	prModeConfigRepository repository.GitOpsPrModeConfigRepository,
+	environmentRepository environmentRepository.EnvironmentRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	pipelineOverrideRepository chartConfig.PipelineOverrideRepository,
+	pipelineStatusTimelineService status.PipelineStatusTimelineService,
+	gitOperationService git.GitOperationService,
+	gitOpsConfigReadService config.GitOpsConfigReadService,
+	deploymentConfigService common.DeploymentConfigService,
+	argoClientWrapperService argocdServer.ArgoClientWrapperService,
+	acdConfig *argocdServer.ACDConfig,
+	transactionUtilImpl *sql.TransactionUtilImpl,
+	cronLogger *cronUtil.CronLoggerImpl) (*GitOpsPullRequestServiceImpl, error) {
+	impl := &GitOpsPullRequestServiceImpl{
+		logger:                        logger,
+		prModeConfigRepository:        prModeConfigRepository,
+		environmentRepository:         environmentRepository,
+		cdWorkflowRepository:          cdWorkflowRepository,
+		pipelineOverrideRepository:    pipelineOverrideRepository,
+		pipelineStatusTimelineService: pipelineStatusTimelineService,
+		gitOperationService:           gitOperationService,
+		gitOpsConfigReadService:       gitOpsConfigReadService,
+		deploymentConfigService:       deploymentConfigService,
+		argoClientWrapperService:      argoClientWrapperService,
+		acdConfig:                     acdConfig,
+		TransactionUtilImpl:           transactionUtilImpl,
+	}
+	cfg := &GitOpsPullRequestConfig{}
+	err := env.Parse(cfg)
+	if err != nil {
+		logger.Errorw("error in parsing gitops pull request config", "err", err)
+		return nil, err
+	}
+	if cfg.PollIntervalSecs > 0 {
+		pollCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+		_, err = pollCron.AddFunc(fmt.Sprintf("@every %ds", cfg.PollIntervalSecs), impl.SyncRunnersAwaitingMerge)
+		if err != nil {
+			logger.Errorw("error in adding gitops pull request poll cron", "err", err)
+			return nil, err
+		}
+		pollCron.Start()
+	}
+	return impl, nil
+}
+
+func (impl *GitOpsPullRequestServiceImpl) GetPrModeConfig(envId int) (*bean.PrModeConfigDto, error) {
+	environment, err := impl.environmentRepository.FindById(envId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting environment", "envId", envId, "err", err)
+		return nil, err
+	} else if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "environment not found", "environment not found")
+	}
+	prModeConfig, err := impl.prModeConfigRepository.FindByEnvironmentId(envId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting pr mode config", "envId", envId, "err", err)
+		return nil, err
+	}
+	return &bean.PrModeConfigDto{
+		EnvironmentId:   envId,
+		EnvironmentName: environment.Name,
+		Enabled:         prModeConfig.Enabled,
+	}, nil
+}
+
+func (impl *GitOpsPullRequestServiceImpl) GetAllPrModeConfigs() ([]*bean.PrModeConfigDto, error) {
+	prModeConfigs, err := impl.prModeConfigRepository.FindAllEnabled()
+	if err != nil {
+		return nil, err
+	}
+	response := make([]*bean.PrModeConfigDto, 0, len(prModeConfigs))
+	for _, prModeConfig := range prModeConfigs {
+		dto := &bean.PrModeConfigDto{EnvironmentId: prModeConfig.EnvironmentId, Enabled: prModeConfig.Enabled}
+		environment, err := impl.environmentRepository.FindById(prModeConfig.EnvironmentId)
+		if err == nil {
+			dto.EnvironmentName = environment.Name
+		}
+		response = append(response, dto)
+	}
+	return response, nil
+}
+
+func (impl *GitOpsPullRequestServiceImpl) SavePrModeConfig(request *bean.PrModeConfigDto, userId int32) (*bean.PrModeConfigDto, error) {
+	environment, err := impl.environmentRepository.FindById(request.EnvironmentId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting environment", "envId", request.EnvironmentId, "err", err)
+		return nil, err
+	} else if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "environment not found", "environment not found")
+	}
+	if request.Enabled && !impl.gitOperationService.IsPullRequestSupported() {
+		return nil, util.NewApiError(http.StatusBadRequest, git.PullRequestNotSupportedError.Error(), git.PullRequestNotSupportedError.Error())
+	}
+	prModeConfig, err := impl.prModeConfigRepository.FindByEnvironmentId(request.EnvironmentId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting pr mode config", "envId", request.EnvironmentId, "err", err)
+		return nil, err
+	}
+	prModeConfig.Enabled = request.Enabled
+	prModeConfig.UpdateAuditLog(userId)
+	if prModeConfig.Id == 0 {
+		prModeConfig.EnvironmentId = request.EnvironmentId
+		prModeConfig.CreateAuditLog(userId)
+		err = impl.prModeConfigRepository.Save(prModeConfig)
+	} else {
+		err = impl.prModeConfigRepository.Update(prModeConfig)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving pr mode config", "request", request, "err", err)
+		return nil, err
+	}
+	request.EnvironmentName = environment.Name
+	return request, nil
+}
+
+func (impl *GitOpsPullRequestServiceImpl) IsPrModeEnabled(envId int) (bool, error) {
+	prModeConfig, err := impl.prModeConfigRepository.FindByEnvironmentId(envId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting pr mode config", "envId", envId, "err", err)
+		return false, err
+	}
+	return prModeConfig.Enabled, nil
+}
+
+func (impl *GitOpsPullRequestServiceImpl) MarkRunnerAwaitingMerge(ctx context.Context, wfrId int, branch string, prInfo *gitBean.PullRequestInfo, userId int32) error {
+	runner, err := impl.cdWorkflowRepository.FindBasicWorkflowRunnerById(wfrId)
+	if err != nil {
+		impl.logger.Errorw("error in getting cd workflow runner", "wfrId", wfrId, "err", err)
+		return err
+	}
+	runner.PullRequestUrl = prInfo.Url
+	runner.PullRequestNumber = prInfo.Number
+	runner.PullRequestState = string(prInfo.State)
+	runner.PullRequestBranch = branch
+	runner.Status = cdWorkflow.WorkflowAwaitingMerge
+	runner.UpdateAuditLog(userId)
+	tx, err := impl.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.RollbackTx(tx)
+	err = impl.cdWorkflowRepository.UpdateWorkFlowRunnerWithTx(runner, tx)
+	if err != nil {
+		impl.logger.Errorw("error in updating cd workflow runner", "wfrId", wfrId, "err", err)
+		return err
+	}
+	timeline := impl.pipelineStatusTimelineService.NewDevtronAppPipelineStatusTimelineDbObject(wfrId, timelineStatus.TIMELINE_STATUS_GIT_PULL_REQUEST_OPENED,
+		fmt.Sprintf(timelineStatus.TIMELINE_DESCRIPTION_GIT_PULL_REQUEST_OPENED, prInfo.Url), userId)
+	err = impl.pipelineStatusTimelineService.SaveMultipleTimelinesIfNotAlreadyPresent([]*pipelineConfig.PipelineStatusTimeline{timeline}, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving pull request opened timeline", "wfrId", wfrId, "err", err)
+		return err
+	}
+	err = impl.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "wfrId", wfrId, "err", err)
+		return err
+	}
+	// failing to close older pull requests does not fail the new deployment
+	impl.supersedePreviousPullRequests(ctx, runner, userId)
+	return nil
+}
+
+// supersedePreviousPullRequests closes the pull requests of earlier deployments of the pipeline which are still open,
+// merging one of them after the new one would roll the environment back to older values
+func (impl *GitOpsPullRequestServiceImpl) supersedePreviousPullRequests(ctx context.Context, runner *pipelineConfig.CdWorkflowRunner, userId int32) {
+	pipeline := runner.CdWorkflow.Pipeline
+	previousRunners, err := impl.cdWorkflowRepository.FindPreviousDeployRunnersByPullRequestState(pipeline.Id, runner.Id, string(gitBean.PullRequestOpen))
+	if err != nil || len(previousRunners) == 0 {
+		return
+	}
+	deploymentConfig, err := impl.deploymentConfigService.GetConfigForDevtronApps(pipeline.AppId, pipeline.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in getting deployment config", "appId", pipeline.AppId, "envId", pipeline.EnvironmentId, "err", err)
+		return
+	}
+	repoName := impl.gitOpsConfigReadService.GetGitOpsRepoNameFromUrl(deploymentConfig.GetRepoURL())
+	for _, previousRunner := range previousRunners {
+		err = impl.supersedePullRequest(ctx, repoName, previousRunner, userId)
+		if err != nil {
+			impl.logger.Errorw("error in superseding pull request of previous deployment", "wfrId", previousRunner.Id, "pullRequest", previousRunner.PullRequestUrl, "err", err)
+		}
+	}
+}
+
+func (impl *GitOpsPullRequestServiceImpl) supersedePullRequest(ctx context.Context, repoName string, runner *pipelineConfig.CdWorkflowRunner, userId int32) error {
+	prInfo, err := impl.gitOperationService.GetPullRequest(ctx, repoName, runner.PullRequestNumber)
+	if err != nil {
+		return err
+	}
+	if prInfo.State == gitBean.PullRequestOpen {
+		err = impl.gitOperationService.ClosePullRequest(ctx, repoName, runner.PullRequestNumber)
+		if err != nil {
+			return err
+		}
+		prInfo.State = gitBean.PullRequestClosed
+	}
+	// the runner may already be failed as superseded by the trigger, only the pull request state is recorded then
+	currentStatus := runner.Status
+	isAwaitingMerge := currentStatus == cdWorkflow.WorkflowAwaitingMerge
+	if isAwaitingMerge {
+		runner.Status = cdWorkflow.WorkflowFailed
+		runner.Message = cdWorkflow.ErrorDeploymentSuperseded.Error()
+		runner.FinishedOn = time.Now()
+	}
+	runner.PullRequestState = string(prInfo.State)
+	runner.UpdateAuditLog(userId)
+	tx, err := impl.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.RollbackTx(tx)
+	updated, err := impl.cdWorkflowRepository.UpdateWorkFlowRunnerWithTxIfStatus(runner, currentStatus, tx)
+	if err != nil {
+		impl.logger.Errorw("error in updating cd workflow runner", "wfrId", runner.Id, "err", err)
+		return err
+	} else if !updated {
+		// the poller moved the runner on in the meantime and recorded the pull request state itself
+		return nil
+	}
+	if isAwaitingMerge {
+		timeline := impl.pipelineStatusTimelineService.NewDevtronAppPipelineStatusTimelineDbObject(runner.Id, timelineStatus.TIMELINE_STATUS_DEPLOYMENT_SUPERSEDED,
+			timelineStatus.TIMELINE_DESCRIPTION_DEPLOYMENT_SUPERSEDED, userId)
+		err = impl.pipelineStatusTimelineService.SaveMultipleTimelinesIfNotAlreadyPresent([]*pipelineConfig.PipelineStatusTimeline{timeline}, tx)
+		if err != nil {
+			impl.logger.Errorw("error in saving deployment superseded timeline", "wfrId", runner.Id, "err", err)
+			return err
+		}
+	}
+	return impl.CommitTx(tx)
+}
+
+func (impl *GitOpsPullRequestServiceImpl) IsRunnerAwaitingMerge(wfrId int) (bool, error) {
+	runner, err := impl.cdWorkflowRepository.FindBasicWorkflowRunnerById(wfrId)
+	if err != nil {
+		impl.logger.Errorw("error in getting cd workflow runner", "wfrId", wfrId, "err", err)
+		return false, err
+	}
+	return runner.Status == cdWorkflow.WorkflowAwaitingMerge, nil
+}
+
+func (impl *GitOpsPullRequestServiceImpl) SyncRunnersAwaitingMerge() {
+	runners, err := impl.cdWorkflowRepository.FindDeployRunnersAwaitingMerge()
+	if err != nil {
+		impl.logger.Errorw("error in getting runners awaiting merge", "err", err)
+		return
+	}
+	for _, runner := range runners {
+		err = impl.syncRunnerAwaitingMerge(context.Background(), runner)
+		if err != nil {
+			// the runner stays in AwaitingMerge and is picked up again on the next poll
+			impl.logger.Errorw("error in syncing runner awaiting merge", "wfrId", runner.Id, "pullRequest", runner.PullRequestUrl, "err", err)
+		}
+	}
+}
+
+func (impl *GitOpsPullRequestServiceImpl) syncRunnerAwaitingMerge(ctx context.Context, runner *pipelineConfig.CdWorkflowRunner) error {
+	pipeline := runner.CdWorkflow.Pipeline
+	deploymentConfig, err := impl.deploymentConfigService.GetConfigForDevtronApps(pipeline.AppId, pipeline.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in getting deployment config", "appId", pipeline.AppId, "envId", pipeline.EnvironmentId, "err", err)
+		return err
+	}
+	repoName := impl.gitOpsConfigReadService.GetGitOpsRepoNameFromUrl(deploymentConfig.GetRepoURL())
+	prInfo, err := impl.gitOperationService.GetPullRequest(ctx, repoName, runner.PullRequestNumber)
+	if err != nil {
+		return err
+	}
+	switch prInfo.State {
+	case gitBean.PullRequestMerged:
+		return impl.resumeMergedRunner(ctx, runner, deploymentConfig.GetTargetRevision(), deploymentConfig.IsArgoAppSyncAndRefreshSupported(), prInfo)
+	case gitBean.PullRequestClosed:
+		return impl.failClosedRunner(runner, prInfo)
+	}
+	return nil
+}
+
+func (impl *GitOpsPullRequestServiceImpl) resumeMergedRunner(ctx context.Context, runner *pipelineConfig.CdWorkflowRunner,
+	targetRevision string, isArgoSyncSupported bool, prInfo *gitBean.PullRequestInfo) error {
+	pipelineOverride, err := impl.pipelineOverrideRepository.FindLatestByCdWorkflowId(runner.CdWorkflowId)
+	if err != nil {
+		impl.logger.Errorw("error in getting pipeline override", "cdWorkflowId", runner.CdWorkflowId, "err", err)
+		return err
+	}
+	tx, err := impl.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.RollbackTx(tx)
+	runner.Status = cdWorkflow.WorkflowInProgress
+	runner.PullRequestState = string(prInfo.State)
+	runner.UpdateAuditLog(userBean.SYSTEM_USER_ID)
+	claimed, err := impl.cdWorkflowRepository.UpdateWorkFlowRunnerWithTxIfStatus(runner, cdWorkflow.WorkflowAwaitingMerge, tx)
+	if err != nil {
+		impl.logger.Errorw("error in updating cd workflow runner", "wfrId", runner.Id, "err", err)
+		return err
+	} else if !claimed {
+		impl.logger.Infow("runner is no longer awaiting merge, resumed by another replica or superseded", "wfrId", runner.Id)
+		return nil
+	}
+	// argo cd reports the merge commit as the synced revision, the status updater resolves the release by it
+	err = impl.pipelineOverrideRepository.UpdateCommitDetails(ctx, tx, pipelineOverride.Id, prInfo.MergeCommitSha, time.Now(), userBean.SYSTEM_USER_ID)
+	if err != nil {
+		impl.logger.Errorw("error in updating commit details", "pipelineOverrideId", pipelineOverride.Id, "err", err)
+		return err
+	}
+	timelines := []*pipelineConfig.PipelineStatusTimeline{
+		impl.pipelineStatusTimelineService.NewDevtronAppPipelineStatusTimelineDbObject(runner.Id, timelineStatus.TIMELINE_STATUS_GIT_PULL_REQUEST_MERGED,
+			fmt.Sprintf(timelineStatus.TIMELINE_DESCRIPTION_GIT_PULL_REQUEST_MERGED, prInfo.Url), userBean.SYSTEM_USER_ID),
+	}
+	if impl.acdConfig.IsManualSyncEnabled() && isArgoSyncSupported {
+		timelines = append(timelines, impl.pipelineStatusTimelineService.NewDevtronAppPipelineStatusTimelineDbObject(runner.Id,
+			timelineStatus.TIMELINE_STATUS_ARGOCD_SYNC_INITIATED, timelineStatus.TIMELINE_DESCRIPTION_ARGOCD_SYNC_INITIATED, userBean.SYSTEM_USER_ID))
+	}
+	err = impl.pipelineStatusTimelineService.SaveMultipleTimelinesIfNotAlreadyPresent(timelines, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving pull request merged timeline", "wfrId", runner.Id, "err", err)
+		return err
+	}
+	err = impl.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "wfrId", runner.Id, "err", err)
+		return err
+	}
+	if !isArgoSyncSupported {
+		return nil
+	}
+	// from here on the deployment is tracked by the argo cd status updater like any other gitops deployment
+	syncTime := time.Now()
+	err = impl.argoClientWrapperService.SyncArgoCDApplicationIfNeededAndRefresh(ctx, runner.CdWorkflow.Pipeline.DeploymentAppName, targetRevision)
+	if err != nil {
+		impl.logger.Errorw("error in syncing argo application after pull request merge", "argoAppName", runner.CdWorkflow.Pipeline.DeploymentAppName, "err", err)
+		return err
+	}
+	if impl.acdConfig.IsManualSyncEnabled() {
+		timeline := impl.pipelineStatusTimelineService.NewDevtronAppPipelineStatusTimelineDbObject(runner.Id,
+			timelineStatus.TIMELINE_STATUS_ARGOCD_SYNC_COMPLETED, timelineStatus.TIMELINE_DESCRIPTION_ARGOCD_SYNC_COMPLETED, userBean.SYSTEM_USER_ID)
+		timeline.StatusTime = syncTime
+		_, err = impl.pipelineStatusTimelineService.SaveTimelineIfNotAlreadyPresent(timeline, nil)
+		if err != nil {
+			impl.logger.Errorw("error in saving argocd sync completed timeline", "wfrId", runner.Id, "err", err)
+		}
+	}
+	return nil
+}
+
+func (impl *GitOpsPullRequestServiceImpl) failClosedRunner(runner *pipelineConfig.CdWorkflowRunner, prInfo *gitBean.PullRequestInfo) error {
+	description := fmt.Sprintf(timelineStatus.TIMELINE_DESCRIPTION_GIT_PULL_REQUEST_CLOSED, prInfo.Url)
+	runner.Status = cdWorkflow.WorkflowFailed
+	runner.Message = description
+	runner.FinishedOn = time.Now()
+	runner.PullRequestState = string(prInfo.State)
+	runner.UpdateAuditLog(userBean.SYSTEM_USER_ID)
+	tx, err := impl.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.RollbackTx(tx)
+	claimed, err := impl.cdWorkflowRepository.UpdateWorkFlowRunnerWithTxIfStatus(runner, cdWorkflow.WorkflowAwaitingMerge, tx)
+	if err != nil {
+		impl.logger.Errorw("error in updating cd workflow runner", "wfrId", runner.Id, "err", err)
+		return err
+	} else if !claimed {
+		impl.logger.Infow("runner is no longer awaiting merge, failed by another replica or superseded", "wfrId", runner.Id)
+		return nil
+	}
+	timeline := impl.pipelineStatusTimelineService.NewDevtronAppPipelineStatusTimelineDbObject(runner.Id, timelineStatus.TIMELINE_STATUS_DEPLOYMENT_FAILED, description, userBean.SYSTEM_USER_ID)
+	err = impl.pipelineStatusTimelineService.SaveTimeline(timeline, tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment failed timeline", "wfrId", runner.Id, "err", err)
+		return err
+	}
+	return impl.CommitTx(tx)
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bean
+
+import "fmt"
+
+type PrModeConfigDto struct {
+	EnvironmentId   int    `json:"environmentId" validate:"number,gt=0"`
+	EnvironmentName string `json:"environmentName,omitempty"`
+	// Enabled commits the deployment values of the environment to a generated branch and opens a pull request against the target revision
+	Enabled bool `json:"enabled"`
+}
+
+const (
+	PullRequestBranchPrefix = "devtron"
+	PullRequestTitleFormat  = "Deploy %s to %s (release-%d)"
+	PullRequestBodyFormat   = "Deployment values of app `%s` for namespace `%s` committed by Devtron.\n\nThe deployment resumes once this pull request is merged, closing it without merging fails the deployment."
+)
+
+// GetPullRequestBranchName returns the branch the values of a release are committed to,
+// one branch per pipeline override so that re-triggers never reuse an open pull request.
+func GetPullRequestBranchName(pipelineOverrideId, envId int) string {
+	return fmt.Sprintf("%s/release-%d-env-%d", PullRequestBranchPrefix, pipelineOverrideId, envId)
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type GitOpsPrModeConfig struct {
+	tableName     struct{} `sql:"gitops_pr_mode_config" pg:",discard_unknown_columns"`
+	Id            int      `sql:"id,pk"`
+	EnvironmentId int      `sql:"environment_id,notnull"`
+	Enabled       bool     `sql:"enabled,notnull"`
+	sql.AuditLog
+}
+
+type GitOpsPrModeConfigRepository interface {
+	Save(config *GitOpsPrModeConfig) error
+	Update(config *GitOpsPrModeConfig) error
+	FindByEnvironmentId(envId int) (*GitOpsPrModeConfig, error)
+	FindAllEnabled() ([]*GitOpsPrModeConfig, error)
+}
+
+type GitOpsPrModeConfigRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewGitOpsPrModeConfigRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *GitOpsPrModeConfigRepositoryImpl {
+	return &GitOpsPrModeConfigRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *GitOpsPrModeConfigRepositoryImpl) Save(config *GitOpsPrModeConfig) error {
+	return impl.dbConnection.Insert(config)
+}
+
+func (impl *GitOpsPrModeConfigRepositoryImpl) Update(config *GitOpsPrModeConfig) error {
+	return impl.dbConnection.Update(config)
+}
+
+func (impl *GitOpsPrModeConfigRepositoryImpl) FindByEnvironmentId(envId int) (*GitOpsPrModeConfig, error) {
+	config := &GitOpsPrModeConfig{}
+	err := impl.dbConnection.Model(config).
+		Where("environment_id = ?", envId).
+		Select()
+	return config, err
+}
+
+func (impl *GitOpsPrModeConfigRepositoryImpl) FindAllEnabled() ([]*GitOpsPrModeConfig, error) {
+	var configs []*GitOpsPrModeConfig
+	err := impl.dbConnection.Model(&configs).
+		Where("enabled = ?", true).
+		Order("environment_id").
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting pr mode enabled environments", "err", err)
+		return nil, err
+	}
+	return configs, nil
+}
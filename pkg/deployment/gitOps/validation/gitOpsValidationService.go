@@ -105,6 +105,17 @@ func (impl *GitOpsValidationServiceImpl) GitOpsValidateDryRun(isArgoModuleInstal
 		return detailedErrorGitOpsConfigResponse
 	}
 	appName := gitOpsBean.DryrunRepoName + globalUtil.Generate(6)
+	// without a repo hook, repositories of a plain ssh git server can neither be created nor deleted,
+	// so the dry run pushes to a pre-created repository and keeps it
+	isPreCreatedDryRunRepo := strings.ToUpper(config.Provider) == bean2.GIT_SSH_PROVIDER && len(config.RepoHookUrl) == 0
+	if isPreCreatedDryRunRepo {
+		if len(config.DryRunRepoName) == 0 {
+			detailedErrorGitOpsConfigActions.StageErrorMap[gitOpsBean.GetRepoUrlStage] = fmt.Errorf("dry run repository name is required when no repo hook is configured")
+			detailedErrorGitOpsConfigActions.ValidatedOn = time.Now()
+			return impl.convertDetailedErrorToResponse(detailedErrorGitOpsConfigActions)
+		}
+		appName = config.DryRunRepoName
+	}
 	//getting user name & emailId for commit author data
 	userEmailId, userName := impl.gitOpsConfigReadService.GetUserEmailIdAndNameForGitOpsCommit(config.UserId)
 	config.UserEmailId = userEmailId
@@ -156,6 +167,11 @@ func (impl *GitOpsValidationServiceImpl) GitOpsValidateDryRun(isArgoModuleInstal
 		detailedErrorGitOpsConfigActions.SuccessfulStages = append(detailedErrorGitOpsConfigActions.SuccessfulStages, gitOpsBean.CommitOnRestStage, gitOpsBean.PushStage)
 	}
 
+	if isPreCreatedDryRunRepo {
+		detailedErrorGitOpsConfigActions.ValidatedOn = time.Now()
+		defer impl.chartTemplateService.CleanDir(clonedDir)
+		return impl.convertDetailedErrorToResponse(detailedErrorGitOpsConfigActions)
+	}
 	err = client.DeleteRepository(config)
 	if err != nil {
 		impl.logger.Errorw("error in deleting repo", "err", err)
@@ -311,6 +327,13 @@ func (impl *GitOpsValidationServiceImpl) extractErrorMessageByProvider(err error
 		return fmt.Errorf("bitbucket client error: %s", err.Error())
 	case bean2.GITHUB_PROVIDER:
 		return fmt.Errorf("github client error: %s", err.Error())
+	case bean2.GITEA_PROVIDER:
+		if errorResponse, ok := err.(*git.GiteaApiError); ok {
+			return fmt.Errorf("gitea client error: %s", errorResponse.Message)
+		}
+		return fmt.Errorf("gitea client error: %s", err.Error())
+	case bean2.GIT_SSH_PROVIDER:
+		return fmt.Errorf("git ssh error: %s", err.Error())
 	}
 	return err
 }
@@ -344,6 +367,14 @@ func (impl *GitOpsValidationServiceImpl) getValidationErrorForNonOrganisationalU
 	case bean2.AZURE_DEVOPS_PROVIDER:
 		errorMessageKey = "The repository must belong to Azure DevOps Project"
 		errorMessage = fmt.Sprintf("%s as configured in global configurations > GitOps", activeGitOpsConfig.AzureProjectName)
+
+	case bean2.GITEA_PROVIDER:
+		errorMessageKey = "The repository must belong to Gitea organization"
+		errorMessage = fmt.Sprintf("%s as configured in global configurations > GitOps", activeGitOpsConfig.GiteaOrgName)
+
+	case bean2.GIT_SSH_PROVIDER:
+		errorMessageKey = "The repository must be under the git host"
+		errorMessage = fmt.Sprintf("%s as configured in global configurations > GitOps", activeGitOpsConfig.Host)
 	}
 	apiErrorMsg := fmt.Sprintf("%s: %s", errorMessageKey, errorMessage)
 	return util.NewApiError(http.StatusBadRequest, apiErrorMsg, apiErrorMsg).
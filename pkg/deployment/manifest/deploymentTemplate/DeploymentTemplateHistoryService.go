@@ -20,6 +20,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deployedAppMetrics"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef"
+	postRenderRepository "github.com/devtron-labs/devtron/pkg/deployment/manifest/postRender/repository"
 	"time"
 
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
@@ -48,13 +49,15 @@ type DeploymentTemplateHistoryServiceImpl struct {
 	scopedVariableManager               variables.ScopedVariableManager
 	deployedAppMetricsService           deployedAppMetrics.DeployedAppMetricsService
 	chartRefService                     chartRef.ChartRefService
+	postRenderPatchRepository           postRenderRepository.PostRenderPatchRepository
 }
 
 func NewDeploymentTemplateHistoryServiceImpl(logger *zap.SugaredLogger, deploymentTemplateHistoryRepository repository.DeploymentTemplateHistoryRepository,
 	pipelineRepository pipelineConfig.PipelineRepository, chartRepository chartRepoRepository.ChartRepository,
 	userService user.UserService, cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
 	scopedVariableManager variables.ScopedVariableManager, deployedAppMetricsService deployedAppMetrics.DeployedAppMetricsService,
-	chartRefService chartRef.ChartRefService) *DeploymentTemplateHistoryServiceImpl {
+	chartRefService chartRef.ChartRefService,
+	postRenderPatchRepository postRenderRepository.PostRenderPatchRepository) *DeploymentTemplateHistoryServiceImpl {
 	return &DeploymentTemplateHistoryServiceImpl{
 		logger:                              logger,
 		deploymentTemplateHistoryRepository: deploymentTemplateHistoryRepository,
@@ -65,6 +68,7 @@ func NewDeploymentTemplateHistoryServiceImpl(logger *zap.SugaredLogger, deployme
 		scopedVariableManager:               scopedVariableManager,
 		deployedAppMetricsService:           deployedAppMetricsService,
 		chartRefService:                     chartRefService,
+		postRenderPatchRepository:           postRenderPatchRepository,
 	}
 }
 
@@ -204,6 +208,14 @@ func (impl DeploymentTemplateHistoryServiceImpl) CreateDeploymentTemplateHistory
 	} else {
 		historyModel.Template = envOverride.Chart.GlobalOverride
 	}
+	// post render patches are applied to what the template renders, so they are kept with the deployed template
+	postRenderPatch, err := impl.postRenderPatchRepository.FindActiveByAppIdAndEnvId(pipeline.AppId, pipeline.EnvironmentId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("err in getting post render patches", "err", err, "appId", pipeline.AppId, "envId", pipeline.EnvironmentId)
+		return nil, err
+	} else if err == nil {
+		historyModel.PostRenderPatches = postRenderPatch.Patches
+	}
 	//creating new entry
 	history, err := impl.deploymentTemplateHistoryRepository.CreateHistory(historyModel)
 	if err != nil {
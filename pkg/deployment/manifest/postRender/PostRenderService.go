@@ -0,0 +1,273 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package postRender
+
+import (
+	"bytes"
+	"context"
+	"github.com/devtron-labs/devtron/api/helm-app/gRPC"
+	"github.com/devtron-labs/devtron/api/helm-app/service/read"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/postRender/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/postRender/helper"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/postRender/repository"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"net/http"
+	"time"
+)
+
+type PostRenderService interface {
+	GetPatches(appId, envId int) (*bean.PostRenderPatchesDto, error)
+	// SavePatches replaces the patches of an app on an environment, saving no patches deletes them
+	SavePatches(request *bean.PostRenderPatchesDto) (*bean.PostRenderPatchesDto, error)
+	DeletePatches(appId, envId int, userId int32) error
+	// GetPatchesForHistory returns the patches of an app on an environment as kept in deployment history, empty
+	// when there are none
+	GetPatchesForHistory(appId, envId int) (string, error)
+	// PatchManifest applies the patches of an app on an environment to its rendered manifest, for previews
+	PatchManifest(appId, envId int, manifest string) (string, error)
+	// PostRenderChartDir renders the chart saved in chartDir, applies the patches to the manifest and replaces the
+	// templates of the chart with it. The chart is left as is when there are no patches
+	PostRenderChartDir(ctx context.Context, request *bean.PostRenderRequest, chartDir string) error
+	// PostRenderChartArchive does what PostRenderChartDir does to a chart archive and returns the new archive
+	PostRenderChartArchive(ctx context.Context, request *bean.PostRenderRequest, chartArchive []byte) ([]byte, error)
+}
+
+type PostRenderServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	postRenderPatchRepository repository.PostRenderPatchRepository
+	helmAppClient             gRPC.HelmAppClient
+	helmAppReadService        read.HelmAppReadService
+	k8sCommonService          k8s.K8sCommonService
+}
+
+func NewPostRenderServiceImpl(logger *zap.SugaredLogger,
+	postRenderPatchRepository repository.PostRenderPatchRepository,
+	helmAppClient gRPC.HelmAppClient,
+	helmAppReadService read.HelmAppReadService,
+	k8sCommonService k8s.K8sCommonService) *PostRenderServiceImpl {
+	return &PostRenderServiceImpl{
+		logger:                    logger,
+		postRenderPatchRepository: postRenderPatchRepository,
+		helmAppClient:             helmAppClient,
+		helmAppReadService:        helmAppReadService,
+		k8sCommonService:          k8sCommonService,
+	}
+}
+
+func (impl *PostRenderServiceImpl) GetPatches(appId, envId int) (*bean.PostRenderPatchesDto, error) {
+	dto := &bean.PostRenderPatchesDto{AppId: appId, EnvId: envId, Patches: make([]*bean.Patch, 0)}
+	model, err := impl.postRenderPatchRepository.FindActiveByAppIdAndEnvId(appId, envId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching post render patches", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	} else if util.IsErrNoRows(err) {
+		return dto, nil
+	}
+	dto.Patches, err = helper.UnmarshalPatches(model.Patches)
+	if err != nil {
+		impl.logger.Errorw("error in parsing post render patches", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	dto.UpdatedOn = model.UpdatedOn.Format(time.RFC3339)
+	return dto, nil
+}
+
+func (impl *PostRenderServiceImpl) SavePatches(request *bean.PostRenderPatchesDto) (*bean.PostRenderPatchesDto, error) {
+	if len(request.Patches) == 0 {
+		err := impl.DeletePatches(request.AppId, request.EnvId, request.UserId)
+		if err != nil {
+			return nil, err
+		}
+		return impl.GetPatches(request.AppId, request.EnvId)
+	}
+	if err := helper.ValidatePatches(request.Patches); err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	patches, err := helper.MarshalPatches(request.Patches)
+	if err != nil {
+		return nil, err
+	}
+	model, err := impl.postRenderPatchRepository.FindActiveByAppIdAndEnvId(request.AppId, request.EnvId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching post render patches", "appId", request.AppId, "envId", request.EnvId, "err", err)
+		return nil, err
+	} else if util.IsErrNoRows(err) {
+		model = &repository.DeploymentPostRenderPatch{
+			AppId:    request.AppId,
+			EnvId:    request.EnvId,
+			Patches:  patches,
+			Active:   true,
+			AuditLog: sql.NewDefaultAuditLog(request.UserId),
+		}
+		err = impl.postRenderPatchRepository.Save(model)
+	} else {
+		model.Patches = patches
+		model.UpdateAuditLog(request.UserId)
+		err = impl.postRenderPatchRepository.Update(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving post render patches", "appId", request.AppId, "envId", request.EnvId, "err", err)
+		return nil, err
+	}
+	return impl.GetPatches(request.AppId, request.EnvId)
+}
+
+func (impl *PostRenderServiceImpl) DeletePatches(appId, envId int, userId int32) error {
+	model, err := impl.postRenderPatchRepository.FindActiveByAppIdAndEnvId(appId, envId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching post render patches", "appId", appId, "envId", envId, "err", err)
+		return err
+	} else if util.IsErrNoRows(err) {
+		return nil
+	}
+	model.Active = false
+	model.UpdateAuditLog(userId)
+	err = impl.postRenderPatchRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting post render patches", "appId", appId, "envId", envId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *PostRenderServiceImpl) GetPatchesForHistory(appId, envId int) (string, error) {
+	model, err := impl.postRenderPatchRepository.FindActiveByAppIdAndEnvId(appId, envId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching post render patches", "appId", appId, "envId", envId, "err", err)
+		return "", err
+	} else if util.IsErrNoRows(err) {
+		return "", nil
+	}
+	return model.Patches, nil
+}
+
+func (impl *PostRenderServiceImpl) PatchManifest(appId, envId int, manifest string) (string, error) {
+	patches, err := impl.getPatches(appId, envId)
+	if err != nil || len(patches) == 0 {
+		return manifest, err
+	}
+	patchedManifest, err := helper.ApplyPatches(manifest, patches)
+	if err != nil {
+		impl.logger.Errorw("error in applying post render patches", "appId", appId, "envId", envId, "err", err)
+		return manifest, util.NewApiError(http.StatusUnprocessableEntity, err.Error(), err.Error())
+	}
+	return patchedManifest, nil
+}
+
+func (impl *PostRenderServiceImpl) PostRenderChartDir(ctx context.Context, request *bean.PostRenderRequest, chartDir string) error {
+	patches, err := impl.getPatches(request.AppId, request.EnvId)
+	if err != nil || len(patches) == 0 {
+		return err
+	}
+	ch, err := loader.LoadDir(chartDir)
+	if err != nil {
+		impl.logger.Errorw("error in loading chart", "chartDir", chartDir, "err", err)
+		return err
+	}
+	chartArchive, err := helper.PackageChart(ch)
+	if err != nil {
+		impl.logger.Errorw("error in packaging chart", "chartDir", chartDir, "err", err)
+		return err
+	}
+	manifest, err := impl.renderAndPatch(ctx, request, ch, chartArchive, patches)
+	if err != nil {
+		return err
+	}
+	return helper.WritePostRenderedManifest(chartDir, manifest)
+}
+
+func (impl *PostRenderServiceImpl) PostRenderChartArchive(ctx context.Context, request *bean.PostRenderRequest, chartArchive []byte) ([]byte, error) {
+	patches, err := impl.getPatches(request.AppId, request.EnvId)
+	if err != nil || len(patches) == 0 {
+		return chartArchive, err
+	}
+	ch, err := loader.LoadArchive(bytes.NewReader(chartArchive))
+	if err != nil {
+		impl.logger.Errorw("error in loading chart archive", "appId", request.AppId, "envId", request.EnvId, "err", err)
+		return chartArchive, err
+	}
+	manifest, err := impl.renderAndPatch(ctx, request, ch, chartArchive, patches)
+	if err != nil {
+		return chartArchive, err
+	}
+	if err = helper.SetPostRenderedManifest(ch, manifest); err != nil {
+		return chartArchive, err
+	}
+	return helper.PackageChart(ch)
+}
+
+func (impl *PostRenderServiceImpl) getPatches(appId, envId int) ([]*bean.Patch, error) {
+	if envId == 0 {
+		return nil, nil
+	}
+	dto, err := impl.GetPatches(appId, envId)
+	if err != nil {
+		return nil, err
+	}
+	return dto.Patches, nil
+}
+
+func (impl *PostRenderServiceImpl) renderAndPatch(ctx context.Context, request *bean.PostRenderRequest, ch *chart.Chart, chartArchive []byte, patches []*bean.Patch) (string, error) {
+	if len(ch.Dependencies()) > 0 || len(ch.Metadata.Dependencies) > 0 {
+		return "", util.NewApiError(http.StatusPreconditionFailed, helper.ErrChartWithDependencies.Error(), helper.ErrChartWithDependencies.Error())
+	}
+	clusterConfig, err := impl.helmAppReadService.GetClusterConf(request.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster config", "clusterId", request.ClusterId, "err", err)
+		return "", err
+	}
+	k8sVersion := request.K8sVersion
+	if len(k8sVersion) == 0 {
+		serverVersion, err := impl.k8sCommonService.GetK8sServerVersion(request.ClusterId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching k8s version of cluster", "clusterId", request.ClusterId, "err", err)
+			return "", err
+		}
+		k8sVersion = serverVersion.String()
+	}
+	chartName, chartVersion := request.ChartName, request.ChartVersion
+	if len(chartName) == 0 {
+		chartName, chartVersion = ch.Metadata.Name, ch.Metadata.Version
+	}
+	templateChartResponse, err := impl.helmAppClient.TemplateChart(ctx, &gRPC.InstallReleaseRequest{
+		ChartName:    chartName,
+		ChartVersion: chartVersion,
+		ValuesYaml:   request.ValuesYaml,
+		K8SVersion:   k8sVersion,
+		ReleaseIdentifier: &gRPC.ReleaseIdentifier{
+			ReleaseName:      request.ReleaseName,
+			ReleaseNamespace: request.Namespace,
+			ClusterConfig:    clusterConfig,
+		},
+		ChartContent: &gRPC.ChartContent{Content: chartArchive},
+	})
+	if err != nil {
+		impl.logger.Errorw("error in rendering chart for post render", "appId", request.AppId, "envId", request.EnvId, "err", err)
+		return "", err
+	}
+	manifest, err := helper.ApplyPatches(templateChartResponse.GeneratedManifest, patches)
+	if err != nil {
+		impl.logger.Errorw("error in applying post render patches", "appId", request.AppId, "envId", request.EnvId, "err", err)
+		return "", util.NewApiError(http.StatusUnprocessableEntity, err.Error(), err.Error())
+	}
+	return manifest, nil
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+type PatchType string
+
+const (
+	// PatchTypeStrategicMerge patches are merged into the objects they target the way kubectl patch merges them,
+	// objects of kinds unknown to Devtron, custom resources for example, get a json merge patch instead
+	PatchTypeStrategicMerge PatchType = "strategicMerge"
+	// PatchTypeJson6902 patches are a list of RFC 6902 operations applied to the objects they target
+	PatchTypeJson6902 PatchType = "json6902"
+)
+
+const (
+	// PostRenderedTemplateName replaces the templates of a chart once its manifest is post rendered, it reads the
+	// manifest from PostRenderedManifestFile so that helm does not evaluate the rendered manifest as a template again
+	PostRenderedTemplateName = "templates/post-rendered.yaml"
+	PostRenderedManifestFile = "post-rendered/manifest.yaml"
+	PostRenderedTemplate     = `{{ .Files.Get "post-rendered/manifest.yaml" }}`
+)
+
+// PatchTarget selects the objects of a manifest a patch applies to, empty fields match any value
+type PatchTarget struct {
+	Group         string `json:"group,omitempty"`
+	Version       string `json:"version,omitempty"`
+	Kind          string `json:"kind,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+type Patch struct {
+	Type PatchType `json:"type" validate:"oneof=strategicMerge json6902"`
+	// Target is required for json6902 patches, strategic merge patches target the object with the kind and name
+	// of the patch when it is left out
+	Target *PatchTarget `json:"target,omitempty"`
+	// Patch is a yaml or json document, a partial object for strategic merge patches and a list of operations
+	// for json6902 patches
+	Patch string `json:"patch" validate:"required"`
+}
+
+type PostRenderPatchesDto struct {
+	AppId     int      `json:"appId"`
+	EnvId     int      `json:"envId"`
+	Patches   []*Patch `json:"patches" validate:"dive"`
+	UpdatedOn string   `json:"updatedOn,omitempty"`
+	UserId    int32    `json:"-"`
+}
+
+// PostRenderRequest has what the chart of a deployment is rendered with before the patches are applied
+type PostRenderRequest struct {
+	AppId        int
+	EnvId        int
+	ClusterId    int
+	ReleaseName  string
+	Namespace    string
+	ChartName    string
+	ChartVersion string
+	ValuesYaml   string
+	// K8sVersion is the version the chart is rendered for, the version of the cluster is used when empty
+	K8sVersion string
+}
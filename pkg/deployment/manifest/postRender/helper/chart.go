@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/postRender/bean"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"os"
+	"path/filepath"
+)
+
+var ErrChartWithDependencies = errors.New("post render patches are not supported for charts with dependencies")
+
+// SetPostRenderedManifest replaces the templates of a chart with the post rendered manifest, the chart then renders
+// the manifest as is whatever values it is installed with
+func SetPostRenderedManifest(ch *chart.Chart, manifest string) error {
+	if len(ch.Dependencies()) > 0 || (ch.Metadata != nil && len(ch.Metadata.Dependencies) > 0) {
+		return ErrChartWithDependencies
+	}
+	ch.Templates = []*chart.File{{Name: bean.PostRenderedTemplateName, Data: []byte(bean.PostRenderedTemplate)}}
+	files := make([]*chart.File, 0, len(ch.Files)+1)
+	for _, file := range ch.Files {
+		if file.Name != bean.PostRenderedManifestFile {
+			files = append(files, file)
+		}
+	}
+	ch.Files = append(files, &chart.File{Name: bean.PostRenderedManifestFile, Data: []byte(manifest)})
+	return nil
+}
+
+// WritePostRenderedManifest does what SetPostRenderedManifest does to a chart saved in chartDir
+func WritePostRenderedManifest(chartDir string, manifest string) error {
+	templatesDir := filepath.Join(chartDir, chartutil.TemplatesDir)
+	if err := os.RemoveAll(templatesDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(templatesDir, os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(chartDir, bean.PostRenderedTemplateName), []byte(bean.PostRenderedTemplate), 0644); err != nil {
+		return err
+	}
+	manifestFile := filepath.Join(chartDir, bean.PostRenderedManifestFile)
+	if err := os.MkdirAll(filepath.Dir(manifestFile), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(manifestFile, []byte(manifest), 0644)
+}
+
+// PackageChart returns the chart archive helm is given the chart with
+func PackageChart(ch *chart.Chart) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "post-render-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	archivePath, err := chartutil.Save(ch, dir)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(archivePath)
+}
@@ -0,0 +1,222 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/postRender/bean"
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+const manifestSeparator = "---\n"
+
+// ValidatePatches checks that every patch can be parsed and has a target, it does not need a manifest to do so
+func ValidatePatches(patches []*bean.Patch) error {
+	for i, patch := range patches {
+		if _, err := getPatchTarget(patch); err != nil {
+			return fmt.Errorf("patch %d: %w", i+1, err)
+		}
+		patchJson, err := yaml.YAMLToJSON([]byte(patch.Patch))
+		if err != nil {
+			return fmt.Errorf("patch %d: invalid yaml: %w", i+1, err)
+		}
+		switch patch.Type {
+		case bean.PatchTypeStrategicMerge:
+			partialObject := make(map[string]interface{})
+			if err = json.Unmarshal(patchJson, &partialObject); err != nil {
+				return fmt.Errorf("patch %d: strategic merge patch is not an object: %w", i+1, err)
+			}
+		case bean.PatchTypeJson6902:
+			if _, err = jsonpatch.DecodePatch(patchJson); err != nil {
+				return fmt.Errorf("patch %d: invalid json6902 patch: %w", i+1, err)
+			}
+		default:
+			return fmt.Errorf("patch %d: unknown patch type %q", i+1, patch.Type)
+		}
+		if patch.Target != nil && len(patch.Target.LabelSelector) > 0 {
+			if _, err = labels.Parse(patch.Target.LabelSelector); err != nil {
+				return fmt.Errorf("patch %d: invalid label selector: %w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyPatches applies the patches in order to the objects of a rendered manifest, every patch must match at least
+// one object so that a patch left behind by a renamed object does not go unnoticed
+func ApplyPatches(manifest string, patches []*bean.Patch) (string, error) {
+	if len(patches) == 0 {
+		return manifest, nil
+	}
+	objects, err := kube.SplitYAML([]byte(manifest))
+	if err != nil {
+		return manifest, err
+	}
+	for i, patch := range patches {
+		target, err := getPatchTarget(patch)
+		if err != nil {
+			return manifest, fmt.Errorf("patch %d: %w", i+1, err)
+		}
+		matched := false
+		for j, object := range objects {
+			ok, err := isTargetMatching(target, object)
+			if err != nil {
+				return manifest, fmt.Errorf("patch %d: %w", i+1, err)
+			} else if !ok {
+				continue
+			}
+			objects[j], err = applyPatch(object, patch)
+			if err != nil {
+				return manifest, fmt.Errorf("patch %d on %s %s: %w", i+1, object.GetKind(), object.GetName(), err)
+			}
+			matched = true
+		}
+		if !matched {
+			return manifest, fmt.Errorf("patch %d: no object in the manifest matches its target", i+1)
+		}
+	}
+	return joinObjects(objects)
+}
+
+// MarshalPatches returns the patches as stored in db and in deployment history
+func MarshalPatches(patches []*bean.Patch) (string, error) {
+	patchesJson, err := json.Marshal(patches)
+	if err != nil {
+		return "", err
+	}
+	return string(patchesJson), nil
+}
+
+func UnmarshalPatches(patchesJson string) ([]*bean.Patch, error) {
+	patches := make([]*bean.Patch, 0)
+	if len(patchesJson) == 0 {
+		return patches, nil
+	}
+	err := json.Unmarshal([]byte(patchesJson), &patches)
+	return patches, err
+}
+
+func getPatchTarget(patch *bean.Patch) (*bean.PatchTarget, error) {
+	if patch.Target != nil {
+		return patch.Target, nil
+	}
+	if patch.Type != bean.PatchTypeStrategicMerge {
+		return nil, errors.New("target is required")
+	}
+	// the object a strategic merge patch is written for is its target, the way kustomize picks it
+	partialObject := &unstructured.Unstructured{}
+	patchJson, err := yaml.YAMLToJSON([]byte(patch.Patch))
+	if err != nil {
+		return nil, fmt.Errorf("invalid yaml: %w", err)
+	}
+	if err = partialObject.UnmarshalJSON(patchJson); err != nil {
+		return nil, fmt.Errorf("target is required when the patch has no kind and name: %w", err)
+	}
+	if len(partialObject.GetKind()) == 0 || len(partialObject.GetName()) == 0 {
+		return nil, errors.New("target is required when the patch has no kind and name")
+	}
+	gvk := partialObject.GroupVersionKind()
+	return &bean.PatchTarget{
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Name:      partialObject.GetName(),
+		Namespace: partialObject.GetNamespace(),
+	}, nil
+}
+
+func isTargetMatching(target *bean.PatchTarget, object *unstructured.Unstructured) (bool, error) {
+	gvk := object.GroupVersionKind()
+	if !isFieldMatching(target.Group, gvk.Group) || !isFieldMatching(target.Version, gvk.Version) ||
+		!isFieldMatching(target.Kind, gvk.Kind) || !isFieldMatching(target.Name, object.GetName()) ||
+		!isFieldMatching(target.Namespace, object.GetNamespace()) {
+		return false, nil
+	}
+	if len(target.LabelSelector) == 0 {
+		return true, nil
+	}
+	selector, err := labels.Parse(target.LabelSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid label selector: %w", err)
+	}
+	return selector.Matches(labels.Set(object.GetLabels())), nil
+}
+
+func isFieldMatching(targetValue, value string) bool {
+	return len(targetValue) == 0 || targetValue == value
+}
+
+func applyPatch(object *unstructured.Unstructured, patch *bean.Patch) (*unstructured.Unstructured, error) {
+	objectJson, err := object.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	patchJson, err := yaml.YAMLToJSON([]byte(patch.Patch))
+	if err != nil {
+		return nil, err
+	}
+	var patchedJson []byte
+	switch patch.Type {
+	case bean.PatchTypeStrategicMerge:
+		patchedJson, err = applyStrategicMergePatch(object.GroupVersionKind(), objectJson, patchJson)
+	case bean.PatchTypeJson6902:
+		var operations jsonpatch.Patch
+		operations, err = jsonpatch.DecodePatch(patchJson)
+		if err == nil {
+			patchedJson, err = operations.Apply(objectJson)
+		}
+	default:
+		err = fmt.Errorf("unknown patch type %q", patch.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	patchedObject := &unstructured.Unstructured{}
+	err = patchedObject.UnmarshalJSON(patchedJson)
+	return patchedObject, err
+}
+
+func applyStrategicMergePatch(gvk schema.GroupVersionKind, objectJson, patchJson []byte) ([]byte, error) {
+	dataStruct, err := scheme.Scheme.New(gvk)
+	if err != nil {
+		// kinds without a go type, custom resources for example, have no merge keys to merge lists by
+		return jsonpatch.MergePatch(objectJson, patchJson)
+	}
+	return strategicpatch.StrategicMergePatch(objectJson, patchJson, dataStruct)
+}
+
+func joinObjects(objects []*unstructured.Unstructured) (string, error) {
+	documents := make([]string, 0, len(objects))
+	for _, object := range objects {
+		document, err := yaml.Marshal(object.Object)
+		if err != nil {
+			return "", err
+		}
+		documents = append(documents, string(document))
+	}
+	return manifestSeparator + strings.Join(documents, manifestSeparator), nil
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package helper
+
+import (
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/postRender/bean"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"testing"
+)
+
+const renderedManifest = `---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    app: app
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: app:1
+---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  ports:
+    - port: 80
+---
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: app
+spec:
+  endpoints:
+    - port: http
+`
+
+func getObject(t *testing.T, manifest, kind string) *unstructured.Unstructured {
+	objects, err := kube.SplitYAML([]byte(manifest))
+	assert.NoError(t, err)
+	for _, object := range objects {
+		if object.GetKind() == kind {
+			return object
+		}
+	}
+	t.Fatalf("%s not found in manifest", kind)
+	return nil
+}
+
+func TestApplyPatches(t *testing.T) {
+	patched, err := ApplyPatches(renderedManifest, []*bean.Patch{
+		{
+			// target taken from the patch, containers are merged by name
+			Type: bean.PatchTypeStrategicMerge,
+			Patch: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+        - name: proxy
+          image: envoy:1`,
+		},
+		{
+			Type:   bean.PatchTypeJson6902,
+			Target: &bean.PatchTarget{Kind: "Service", Name: "app"},
+			Patch:  `[{"op": "add", "path": "/metadata/annotations", "value": {"team": "payments"}}]`,
+		},
+		{
+			// custom resources get a json merge patch, lists are replaced
+			Type:   bean.PatchTypeStrategicMerge,
+			Target: &bean.PatchTarget{Group: "monitoring.coreos.com", Kind: "ServiceMonitor"},
+			Patch:  `{"spec": {"endpoints": [{"port": "metrics"}]}}`,
+		},
+	})
+	assert.NoError(t, err)
+
+	containers, _, _ := unstructured.NestedSlice(getObject(t, patched, "Deployment").Object, "spec", "template", "spec", "containers")
+	assert.Len(t, containers, 2)
+	assert.Equal(t, map[string]string{"team": "payments"}, getObject(t, patched, "Service").GetAnnotations())
+	endpoints, _, _ := unstructured.NestedSlice(getObject(t, patched, "ServiceMonitor").Object, "spec", "endpoints")
+	assert.Equal(t, []interface{}{map[string]interface{}{"port": "metrics"}}, endpoints)
+}
+
+func TestApplyPatchesByLabelSelector(t *testing.T) {
+	patched, err := ApplyPatches(renderedManifest, []*bean.Patch{{
+		Type:   bean.PatchTypeJson6902,
+		Target: &bean.PatchTarget{LabelSelector: "app=app"},
+		Patch:  "- op: replace\n  path: /metadata/name\n  value: renamed",
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed", getObject(t, patched, "Deployment").GetName())
+	assert.Equal(t, "app", getObject(t, patched, "Service").GetName())
+}
+
+func TestApplyPatchesWithoutMatch(t *testing.T) {
+	_, err := ApplyPatches(renderedManifest, []*bean.Patch{{
+		Type:   bean.PatchTypeJson6902,
+		Target: &bean.PatchTarget{Kind: "Ingress"},
+		Patch:  `[{"op": "remove", "path": "/spec"}]`,
+	}})
+	assert.ErrorContains(t, err, "no object in the manifest matches")
+
+	unchanged, err := ApplyPatches(renderedManifest, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, renderedManifest, unchanged)
+}
+
+func TestValidatePatches(t *testing.T) {
+	assert.NoError(t, ValidatePatches([]*bean.Patch{{
+		Type:  bean.PatchTypeStrategicMerge,
+		Patch: "kind: Deployment\nmetadata:\n  name: app",
+	}}))
+	assert.ErrorContains(t, ValidatePatches([]*bean.Patch{{
+		Type:  bean.PatchTypeStrategicMerge,
+		Patch: "spec:\n  replicas: 2",
+	}}), "target is required")
+	assert.ErrorContains(t, ValidatePatches([]*bean.Patch{{
+		Type:   bean.PatchTypeJson6902,
+		Target: &bean.PatchTarget{Kind: "Deployment"},
+		Patch:  `{"op": "add"}`,
+	}}), "invalid json6902 patch")
+	assert.ErrorContains(t, ValidatePatches([]*bean.Patch{{
+		Type:  bean.PatchTypeJson6902,
+		Patch: `[]`,
+	}}), "target is required")
+}
+
+func TestSetPostRenderedManifest(t *testing.T) {
+	ch := &chart.Chart{
+		Metadata:  &chart.Metadata{Name: "app", Version: "1.0.0"},
+		Templates: []*chart.File{{Name: "templates/deployment.yaml"}, {Name: "templates/_helpers.tpl"}},
+		Files:     []*chart.File{{Name: "README.md"}},
+	}
+	assert.NoError(t, SetPostRenderedManifest(ch, renderedManifest))
+	assert.Equal(t, []*chart.File{{Name: bean.PostRenderedTemplateName, Data: []byte(bean.PostRenderedTemplate)}}, ch.Templates)
+	assert.Len(t, ch.Files, 2)
+	assert.Equal(t, renderedManifest, string(ch.Files[1].Data))
+
+	ch.Metadata.Dependencies = []*chart.Dependency{{Name: "redis"}}
+	assert.ErrorIs(t, SetPostRenderedManifest(ch, renderedManifest), ErrChartWithDependencies)
+}
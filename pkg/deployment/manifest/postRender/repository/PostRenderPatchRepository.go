@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type DeploymentPostRenderPatch struct {
+	tableName struct{} `sql:"deployment_post_render_patch" pg:",discard_unknown_columns"`
+	Id        int      `sql:"id,pk"`
+	AppId     int      `sql:"app_id,notnull"`
+	EnvId     int      `sql:"env_id,notnull"`
+	Patches   string   `sql:"patches,notnull"`
+	Active    bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type PostRenderPatchRepository interface {
+	Save(patch *DeploymentPostRenderPatch) error
+	Update(patch *DeploymentPostRenderPatch) error
+	FindActiveByAppIdAndEnvId(appId, envId int) (*DeploymentPostRenderPatch, error)
+}
+
+type PostRenderPatchRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewPostRenderPatchRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *PostRenderPatchRepositoryImpl {
+	return &PostRenderPatchRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *PostRenderPatchRepositoryImpl) Save(patch *DeploymentPostRenderPatch) error {
+	return impl.dbConnection.Insert(patch)
+}
+
+func (impl *PostRenderPatchRepositoryImpl) Update(patch *DeploymentPostRenderPatch) error {
+	return impl.dbConnection.Update(patch)
+}
+
+func (impl *PostRenderPatchRepositoryImpl) FindActiveByAppIdAndEnvId(appId, envId int) (*DeploymentPostRenderPatch, error) {
+	patch := &DeploymentPostRenderPatch{}
+	err := impl.dbConnection.Model(patch).
+		Where("app_id = ?", appId).
+		Where("env_id = ?", envId).
+		Where("active = ?", true).
+		Select()
+	return patch, err
+}
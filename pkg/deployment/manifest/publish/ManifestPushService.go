@@ -32,6 +32,9 @@ import (
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
 	gitOpsBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/config/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
+	gitBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/monorepo"
+	prBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/pullRequest/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	globalUtil "github.com/devtron-labs/devtron/util"
@@ -60,6 +63,7 @@ type GitOpsManifestPushServiceImpl struct {
 	argoClientWrapperService      argocdServer.ArgoClientWrapperService
 	deploymentConfigService       common.DeploymentConfigService
 	chartTemplateService          util.ChartTemplateService
+	gitOpsMonorepoService         monorepo.GitOpsMonorepoService
 	*sql.TransactionUtilImpl
 }
 
@@ -74,7 +78,8 @@ func NewGitOpsManifestPushServiceImpl(logger *zap.SugaredLogger,
 	argoClientWrapperService argocdServer.ArgoClientWrapperService,
 	transactionUtilImpl *sql.TransactionUtilImpl,
 	deploymentConfigService common.DeploymentConfigService,
-	chartTemplateService util.ChartTemplateService) *GitOpsManifestPushServiceImpl {
+	chartTemplateService util.ChartTemplateService,
+	gitOpsMonorepoService monorepo.GitOpsMonorepoService) *GitOpsManifestPushServiceImpl {
 	return &GitOpsManifestPushServiceImpl{
 		logger:                        logger,
 		pipelineStatusTimelineService: pipelineStatusTimelineService,
@@ -88,6 +93,7 @@ func NewGitOpsManifestPushServiceImpl(logger *zap.SugaredLogger,
 		TransactionUtilImpl:           transactionUtilImpl,
 		deploymentConfigService:       deploymentConfigService,
 		chartTemplateService:          chartTemplateService,
+		gitOpsMonorepoService:         gitOpsMonorepoService,
 	}
 }
 
@@ -96,7 +102,11 @@ func (impl *GitOpsManifestPushServiceImpl) createRepoForGitOperation(manifestPus
 	if manifestPushTemplate.IsCustomGitRepository {
 		return manifestPushTemplate.RepoUrl, nil
 	}
-	gitOpsRepoName := impl.gitOpsConfigReadService.GetGitOpsRepoName(manifestPushTemplate.AppName)
+	gitOpsRepoName, err := impl.gitOpsConfigReadService.GetDevtronAppGitOpsRepoName(manifestPushTemplate.AppName)
+	if err != nil {
+		impl.logger.Errorw("error in getting gitops repo name", "appName", manifestPushTemplate.AppName, "err", err)
+		return "", err
+	}
 	targetRevision := globalUtil.GetDefaultTargetRevision()
 	if len(manifestPushTemplate.TargetRevision) != 0 {
 		targetRevision = manifestPushTemplate.TargetRevision
@@ -115,6 +125,25 @@ func (impl *GitOpsManifestPushServiceImpl) createRepoForGitOperation(manifestPus
 	return chartGitAttr.RepoUrl, nil
 }
 
+func (impl *GitOpsManifestPushServiceImpl) updateGitOpsMonorepoPath(manifestPushTemplate *bean.ManifestPushTemplate, repoUrl string) (string, error) {
+	if manifestPushTemplate.IsCustomGitRepository {
+		return "", nil
+	}
+	deploymentConfig, err := impl.deploymentConfigService.GetConfigForDevtronApps(manifestPushTemplate.AppId, manifestPushTemplate.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in getting deployment config", "appId", manifestPushTemplate.AppId, "envId", manifestPushTemplate.EnvironmentId, "err", err)
+		return "", err
+	}
+	deploymentConfig, err = impl.gitOpsMonorepoService.UpdatePathPrefix(deploymentConfig.SetRepoURL(repoUrl), manifestPushTemplate.UserId)
+	if err != nil {
+		return "", err
+	}
+	if len(deploymentConfig.GetGitOpsPathPrefix()) != 0 {
+		manifestPushTemplate.ChartLocation = deploymentConfig.GetChartLocation()
+	}
+	return deploymentConfig.GetGitOpsPathPrefix(), nil
+}
+
 func (impl *GitOpsManifestPushServiceImpl) validateManifestPushRequest(globalGitOpsConfigStatus *gitOpsBean.GitOpsConfigurationStatus, manifestPushTemplate *bean.ManifestPushTemplate) error {
 	if manifestPushTemplate.ReleaseMode == util.PIPELINE_RELEASE_MODE_LINK {
 		if gitOps.IsGitOpsRepoNotConfigured(manifestPushTemplate.RepoUrl) {
@@ -177,8 +206,27 @@ func (impl *GitOpsManifestPushServiceImpl) PushChart(ctx context.Context, manife
 			manifestPushResponse.Error = err
 			return manifestPushResponse
 		}
+		// in gitops monorepo the chart is pushed under the directory of the app
+		pathPrefix, err := impl.updateGitOpsMonorepoPath(manifestPushTemplate, newGitRepoUrl)
+		if err != nil {
+			manifestPushResponse.Error = err
+			impl.SaveTimelineForError(manifestPushTemplate, err)
+			return manifestPushResponse
+		}
+		manifestPushResponse.GitOpsPathPrefix = pathPrefix
 
 	}
+	// in pull request mode chart and values are pushed to a branch cut from the target revision
+	if manifestPushTemplate.PullRequestMode {
+		err = impl.gitOperationService.CreatePullRequestBranch(newCtx, impl.gitOpsConfigReadService.GetGitOpsRepoNameFromUrl(manifestPushTemplate.RepoUrl),
+			manifestPushTemplate.TargetRevision, manifestPushTemplate.PullRequestBranch)
+		if err != nil {
+			impl.logger.Errorw("error in creating pull request branch", "branch", manifestPushTemplate.PullRequestBranch, "err", err)
+			manifestPushResponse.Error = err
+			impl.SaveTimelineForError(manifestPushTemplate, err)
+			return manifestPushResponse
+		}
+	}
 	// 4. Push Chart to Git Repository
 	err = impl.pushChartToGitRepo(newCtx, manifestPushTemplate)
 	if err != nil {
@@ -198,6 +246,15 @@ func (impl *GitOpsManifestPushServiceImpl) PushChart(ctx context.Context, manife
 	}
 	manifestPushResponse.CommitHash = commitHash
 	manifestPushResponse.CommitTime = commitTime
+	if manifestPushTemplate.PullRequestMode {
+		manifestPushResponse.PullRequest, err = impl.openPullRequest(newCtx, manifestPushTemplate)
+		if err != nil {
+			impl.logger.Errorw("error in opening pull request", "branch", manifestPushTemplate.PullRequestBranch, "err", err)
+			manifestPushResponse.Error = err
+			impl.SaveTimelineForError(manifestPushTemplate, err)
+			return manifestPushResponse
+		}
+	}
 	// 6. Update commit details in PipelineConfigOverride and Deployment Status Timelines
 	tx, err := impl.TransactionUtilImpl.StartTx()
 	defer impl.TransactionUtilImpl.RollbackTx(tx)
@@ -216,7 +273,8 @@ func (impl *GitOpsManifestPushServiceImpl) PushChart(ctx context.Context, manife
 	}
 	gitCommitTimeline := impl.pipelineStatusTimelineService.NewDevtronAppPipelineStatusTimelineDbObject(manifestPushTemplate.WorkflowRunnerId, timelineStatus.TIMELINE_STATUS_GIT_COMMIT, timelineStatus.TIMELINE_DESCRIPTION_ARGOCD_GIT_COMMIT, manifestPushTemplate.UserId)
 	timelines := []*pipelineConfig.PipelineStatusTimeline{gitCommitTimeline}
-	if impl.acdConfig.IsManualSyncEnabled() && manifestPushTemplate.ArgoSyncNeeded {
+	// in pull request mode argo cd is synced once the pull request is merged
+	if impl.acdConfig.IsManualSyncEnabled() && manifestPushTemplate.ArgoSyncNeeded && !manifestPushTemplate.PullRequestMode {
 		// if manual sync is enabled, add ARGOCD_SYNC_INITIATED_TIMELINE
 		argoCDSyncInitiatedTimeline := impl.pipelineStatusTimelineService.NewDevtronAppPipelineStatusTimelineDbObject(manifestPushTemplate.WorkflowRunnerId, timelineStatus.TIMELINE_STATUS_ARGOCD_SYNC_INITIATED, timelineStatus.TIMELINE_DESCRIPTION_ARGOCD_SYNC_INITIATED, manifestPushTemplate.UserId)
 		timelines = append(timelines, argoCDSyncInitiatedTimeline)
@@ -246,7 +304,7 @@ func (impl *GitOpsManifestPushServiceImpl) pushChartToGitRepo(ctx context.Contex
 		impl.logger.Errorw("err in getting chart info", "err", err)
 		return err
 	}
-	err = impl.gitOperationService.PushChartToGitRepo(newCtx, gitOpsRepoName, manifestPushTemplate.ChartLocation, manifestPushTemplate.BuiltChartPath, manifestPushTemplate.RepoUrl, manifestPushTemplate.TargetRevision, manifestPushTemplate.UserId)
+	err = impl.gitOperationService.PushChartToGitRepo(newCtx, gitOpsRepoName, manifestPushTemplate.ChartLocation, manifestPushTemplate.BuiltChartPath, manifestPushTemplate.RepoUrl, manifestPushTemplate.GetCommitRevision(), manifestPushTemplate.UserId)
 	if err != nil {
 		impl.logger.Errorw("error in pushing chart to git", "err", err)
 		return err
@@ -270,7 +328,7 @@ func (impl *GitOpsManifestPushServiceImpl) commitValuesToGit(ctx context.Context
 		ChartName:      manifestPushTemplate.ChartName,
 		ChartLocation:  manifestPushTemplate.ChartLocation,
 		ChartRepoName:  chartRepoName,
-		TargetRevision: manifestPushTemplate.TargetRevision,
+		TargetRevision: manifestPushTemplate.GetCommitRevision(),
 		ReleaseMessage: fmt.Sprintf("release-%d-env-%d ", manifestPushTemplate.PipelineOverrideId, manifestPushTemplate.TargetEnvironmentId),
 		UserName:       userName,
 		UserEmailId:    userEmailId,
@@ -285,6 +343,19 @@ func (impl *GitOpsManifestPushServiceImpl) commitValuesToGit(ctx context.Context
 	return commitHash, commitTime, nil
 }
 
+func (impl *GitOpsManifestPushServiceImpl) openPullRequest(ctx context.Context, manifestPushTemplate *bean.ManifestPushTemplate) (*gitBean.PullRequestInfo, error) {
+	newCtx, span := otel.Tracer("orchestrator").Start(ctx, "GitOpsManifestPushServiceImpl.openPullRequest")
+	defer span.End()
+	request := &gitBean.CreatePullRequestRequest{
+		RepoName:    impl.gitOpsConfigReadService.GetGitOpsRepoNameFromUrl(manifestPushTemplate.RepoUrl),
+		HeadBranch:  manifestPushTemplate.PullRequestBranch,
+		BaseBranch:  manifestPushTemplate.TargetRevision,
+		Title:       fmt.Sprintf(prBean.PullRequestTitleFormat, manifestPushTemplate.AppName, manifestPushTemplate.EnvironmentName, manifestPushTemplate.PipelineOverrideId),
+		Description: fmt.Sprintf(prBean.PullRequestBodyFormat, manifestPushTemplate.AppName, manifestPushTemplate.EnvironmentName),
+	}
+	return impl.gitOperationService.CreatePullRequest(newCtx, request)
+}
+
 func (impl *GitOpsManifestPushServiceImpl) SaveTimelineForError(manifestPushTemplate *bean.ManifestPushTemplate, gitCommitErr error) {
 	timeline := impl.pipelineStatusTimelineService.NewDevtronAppPipelineStatusTimelineDbObject(manifestPushTemplate.WorkflowRunnerId, timelineStatus.TIMELINE_STATUS_GIT_COMMIT_FAILED, fmt.Sprintf("Git commit failed - %v", gitCommitErr), manifestPushTemplate.UserId)
 	timelineErr := impl.pipelineStatusTimelineService.SaveTimeline(timeline, nil)
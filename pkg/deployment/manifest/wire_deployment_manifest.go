@@ -20,6 +20,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/configMapAndSecret"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deployedAppMetrics"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/postRender"
 	"github.com/google/wire"
 )
 
@@ -27,6 +28,7 @@ var DeploymentManifestWireSet = wire.NewSet(
 	deployedAppMetrics.AppMetricsWireSet,
 	deploymentTemplate.DeploymentTemplateWireSet,
 	configMapAndSecret.ConfigMapAndSecretWireSet,
+	postRender.PostRenderWireSet,
 
 	NewManifestCreationServiceImpl,
 	wire.Bind(new(ManifestCreationService), new(*ManifestCreationServiceImpl)),
@@ -20,8 +20,8 @@ import (
 	"bufio"
 	"context"
 	"github.com/devtron-labs/common-lib/async"
-	service2 "github.com/devtron-labs/devtron/pkg/workflow/trigger/audit/service"
 	"github.com/devtron-labs/devtron/client/fluxcd"
+	service2 "github.com/devtron-labs/devtron/pkg/workflow/trigger/audit/service"
 	"os"
 	"time"
 
@@ -55,7 +55,10 @@ import (
 	bean9 "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/monorepo"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/pullRequest"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/postRender"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/publish"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/userDeploymentRequest/service"
@@ -69,6 +72,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/plugin"
 	security2 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning"
 	read2 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/read"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/pkg/variables"
 	"github.com/devtron-labs/devtron/pkg/workflow/cd"
@@ -174,6 +178,10 @@ type HandlerServiceImpl struct {
 	asyncRunnable                       *async.Runnable
 	workflowTriggerAuditService         service2.WorkflowTriggerAuditService
 	fluxCdDeploymentService             fluxcd.DeploymentService
+	imageSigningService                 imageSigning.ImageSigningService
+	gitOpsPullRequestService            pullRequest.GitOpsPullRequestService
+	gitOpsMonorepoService               monorepo.GitOpsMonorepoService
+	postRenderService                   postRender.PostRenderService
 }
 
 func NewHandlerServiceImpl(logger *zap.SugaredLogger,
@@ -238,7 +246,11 @@ func NewHandlerServiceImpl(logger *zap.SugaredLogger,
 	deploymentEventHandler app.DeploymentEventHandler,
 	asyncRunnable *async.Runnable,
 	workflowTriggerAuditService service2.WorkflowTriggerAuditService,
-	fluxCdDeploymentService fluxcd.DeploymentService) (*HandlerServiceImpl, error) {
+	fluxCdDeploymentService fluxcd.DeploymentService,
+	imageSigningService imageSigning.ImageSigningService,
+	gitOpsPullRequestService pullRequest.GitOpsPullRequestService,
+	gitOpsMonorepoService monorepo.GitOpsMonorepoService,
+	postRenderService postRender.PostRenderService) (*HandlerServiceImpl, error) {
 	impl := &HandlerServiceImpl{
 		logger:                              logger,
 		cdWorkflowCommonService:             cdWorkflowCommonService,
@@ -307,7 +319,11 @@ func NewHandlerServiceImpl(logger *zap.SugaredLogger,
 		deploymentEventHandler:      deploymentEventHandler,
 		asyncRunnable:               asyncRunnable,
 		workflowTriggerAuditService: workflowTriggerAuditService,
-		fluxCdDeploymentService:  fluxCdDeploymentService,
+		fluxCdDeploymentService:     fluxCdDeploymentService,
+		imageSigningService:         imageSigningService,
+		gitOpsPullRequestService:    gitOpsPullRequestService,
+		gitOpsMonorepoService:       gitOpsMonorepoService,
+		postRenderService:           postRenderService,
 	}
 	config, err := types.GetCdConfig()
 	if err != nil {
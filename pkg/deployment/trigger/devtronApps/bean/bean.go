@@ -110,6 +110,7 @@ const (
 type ValidateDeploymentTriggerObj struct {
 	Runner               *pipelineConfig.CdWorkflowRunner
 	CdPipeline           *pipelineConfig.Pipeline
+	Artifact             *repository.CiArtifact
 	ImageDigest          string
 	DeploymentConfig     *bean2.DeploymentConfig
 	TriggeredBy          int32
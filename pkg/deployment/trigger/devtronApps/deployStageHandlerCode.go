@@ -48,8 +48,10 @@ import (
 	bean2 "github.com/devtron-labs/devtron/pkg/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/common"
 	bean9 "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
+	prBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/pullRequest/bean"
 	bean10 "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/bean"
 	bean5 "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef/bean"
+	postRenderBean "github.com/devtron-labs/devtron/pkg/deployment/manifest/postRender/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/adapter"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/helper"
@@ -151,6 +153,29 @@ func (impl *HandlerServiceImpl) validateDeploymentTriggerRequest(ctx context.Con
 		}
 		return fmt.Errorf("found vulnerability for image digest %s", validateDeploymentTriggerObj.ImageDigest)
 	}
+	// if request is for rollback then bypass signature verification, the artifact was verified when it was first deployed
+	if !validateDeploymentTriggerObj.IsDeploymentTypeRollback() {
+		err = impl.validateImageSignature(validateDeploymentTriggerObj)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *HandlerServiceImpl) validateImageSignature(validateDeploymentTriggerObj *bean.ValidateDeploymentTriggerObj) error {
+	cdPipeline := validateDeploymentTriggerObj.CdPipeline
+	verificationResult, err := impl.imageSigningService.VerifyArtifactForDeployment(validateDeploymentTriggerObj.Artifact, cdPipeline.Id, cdPipeline.EnvironmentId, validateDeploymentTriggerObj.TriggeredBy)
+	if err != nil {
+		impl.logger.Errorw("error in verifying image signature", "artifactId", validateDeploymentTriggerObj.Artifact.Id, "cdPipelineId", cdPipeline.Id, "err", err)
+		return err
+	}
+	if verificationResult.IsBlocking() {
+		if err = impl.cdWorkflowCommonService.MarkCurrentDeploymentFailed(validateDeploymentTriggerObj.Runner, errors.New(cdWorkflow.IMAGE_SIGNATURE_VERIFICATION_FAILED), validateDeploymentTriggerObj.TriggeredBy); err != nil {
+			impl.logger.Errorw("error while updating current runner status to failed, TriggerDeployment", "wfrId", validateDeploymentTriggerObj.Runner.Id, "err", err)
+		}
+		return fmt.Errorf("image signature verification failed for image digest %s", validateDeploymentTriggerObj.ImageDigest)
+	}
 	return nil
 }
 
@@ -302,7 +327,7 @@ func (impl *HandlerServiceImpl) ManualCdTrigger(triggerContext bean.TriggerConte
 			impl.logger.Errorw("error in creating timeline status for deployment initiation, ManualCdTrigger", "err", err, "timeline", timeline)
 		}
 		if isNotHibernateRequest(overrideRequest.DeploymentType) {
-			validateReqObj := adapter.NewValidateDeploymentTriggerObj(runner, cdPipeline, artifact.ImageDigest, envDeploymentConfig, overrideRequest.UserId, overrideRequest.IsRollbackDeployment)
+			validateReqObj := adapter.NewValidateDeploymentTriggerObj(runner, cdPipeline, artifact, envDeploymentConfig, overrideRequest.UserId, overrideRequest.IsRollbackDeployment)
 			validationErr := impl.validateDeploymentTriggerRequest(ctx, validateReqObj)
 			if validationErr != nil {
 				impl.logger.Errorw("validation error deployment request", "cdWfr", runner.Id, "err", validationErr)
@@ -443,7 +468,7 @@ func (impl *HandlerServiceImpl) TriggerAutomaticDeployment(request bean.CdTrigge
 		impl.logger.Errorw("error in fetching environment deployment config by appId and envId", "appId", pipeline.AppId, "envId", pipeline.EnvironmentId, "err", err)
 		return err
 	}
-	validationErr := impl.validateDeploymentTriggerRequest(ctx, adapter.NewValidateDeploymentTriggerObj(runner, pipeline, artifact.ImageDigest, envDeploymentConfig, triggeredBy, false))
+	validationErr := impl.validateDeploymentTriggerRequest(ctx, adapter.NewValidateDeploymentTriggerObj(runner, pipeline, artifact, envDeploymentConfig, triggeredBy, false))
 	if validationErr != nil {
 		impl.logger.Errorw("validation error deployment request", "cdWfr", runner.Id, "err", validationErr)
 		return validationErr
@@ -674,20 +699,54 @@ func (impl *HandlerServiceImpl) performGitOps(ctx context.Context,
 		impl.logger.Errorw("error in updating the workflow runner status", "err", err)
 		return err
 	}
+	if triggerEvent.ManifestStorageType == bean2.ManifestStorageGit && !valuesOverrideResponse.DeploymentConfig.IsLinkedRelease() {
+		// configs of environments created before the app moved to the gitops monorepo get their monorepo directory here
+		valuesOverrideResponse.DeploymentConfig, err = impl.gitOpsMonorepoService.UpdatePathPrefix(valuesOverrideResponse.DeploymentConfig, overrideRequest.UserId)
+		if err != nil {
+			return err
+		}
+	}
+	// post render patches of the environment replace the templates of the built chart with the patched manifest
+	err = impl.postRenderService.PostRenderChartDir(newCtx, impl.getPostRenderRequest(overrideRequest, valuesOverrideResponse, ""), builtChartPath)
+	if err != nil {
+		impl.logger.Errorw("error in applying post render patches", "appId", overrideRequest.AppId, "envId", overrideRequest.EnvId, "err", err)
+		return err
+	}
 	manifestPushTemplate, err := impl.buildManifestPushTemplate(overrideRequest, valuesOverrideResponse, builtChartPath)
 	if err != nil {
 		impl.logger.Errorw("error in building manifest push template", "err", err)
 		return err
 	}
+	if triggerEvent.ManifestStorageType == bean2.ManifestStorageGit && util.IsAcdApp(overrideRequest.DeploymentAppType) {
+		manifestPushTemplate.PullRequestMode, err = impl.gitOpsPullRequestService.IsPrModeEnabled(overrideRequest.EnvId)
+		if err != nil {
+			impl.logger.Errorw("error in checking pull request mode of environment", "envId", overrideRequest.EnvId, "err", err)
+			return err
+		}
+		if manifestPushTemplate.PullRequestMode {
+			manifestPushTemplate.PullRequestBranch = prBean.GetPullRequestBranchName(manifestPushTemplate.PipelineOverrideId, overrideRequest.EnvId)
+		}
+	}
 	manifestPushService := impl.getManifestPushService(triggerEvent.ManifestStorageType)
 	manifestPushResponse := manifestPushService.PushChart(newCtx, manifestPushTemplate)
 	if manifestPushResponse.Error != nil {
 		impl.logger.Errorw("error in pushing manifest to git/helm", "err", manifestPushResponse.Error, "git_repo_url", manifestPushTemplate.RepoUrl)
 		return manifestPushResponse.Error
 	}
+	if manifestPushResponse.IsPullRequestOpened() {
+		// runner waits in AwaitingMerge, argo cd is synced once the pull request is merged
+		err = impl.gitOpsPullRequestService.MarkRunnerAwaitingMerge(newCtx, overrideRequest.WfrId, manifestPushTemplate.PullRequestBranch, manifestPushResponse.PullRequest, overrideRequest.UserId)
+		if err != nil {
+			impl.logger.Errorw("error in marking runner awaiting merge", "wfrId", overrideRequest.WfrId, "err", err)
+			return err
+		}
+	}
 	if manifestPushResponse.IsNewGitRepoConfigured() {
 		// Update GitOps repo url after repo new repo created
 		valuesOverrideResponse.DeploymentConfig.SetRepoURL(manifestPushResponse.NewGitRepoUrl)
+		if len(manifestPushResponse.GitOpsPathPrefix) != 0 {
+			valuesOverrideResponse.DeploymentConfig.SetGitOpsPathPrefix(manifestPushResponse.GitOpsPathPrefix)
+		}
 	}
 	valuesOverrideResponse.ManifestPushTemplate = manifestPushTemplate
 	return nil
@@ -767,6 +826,18 @@ func (impl *HandlerServiceImpl) triggerPipeline(overrideRequest *bean3.ValuesOve
 	return valuesOverrideResponse.PipelineOverride.PipelineReleaseCounter, nil
 }
 
+func (impl *HandlerServiceImpl) getPostRenderRequest(overrideRequest *bean3.ValuesOverrideRequest, valuesOverrideResponse *app.ValuesOverrideResponse, k8sVersion string) *postRenderBean.PostRenderRequest {
+	return &postRenderBean.PostRenderRequest{
+		AppId:       overrideRequest.AppId,
+		EnvId:       overrideRequest.EnvId,
+		ClusterId:   valuesOverrideResponse.EnvOverride.Environment.ClusterId,
+		ReleaseName: valuesOverrideResponse.Pipeline.DeploymentAppName,
+		Namespace:   valuesOverrideResponse.EnvOverride.Namespace,
+		ValuesYaml:  valuesOverrideResponse.MergedValues,
+		K8sVersion:  k8sVersion,
+	}
+}
+
 func (impl *HandlerServiceImpl) buildManifestPushTemplate(overrideRequest *bean3.ValuesOverrideRequest, valuesOverrideResponse *app.ValuesOverrideResponse, builtChartPath string) (*bean4.ManifestPushTemplate, error) {
 
 	manifestPushTemplate := &bean4.ManifestPushTemplate{
@@ -868,6 +939,11 @@ func (impl *HandlerServiceImpl) createHelmAppForCdPipeline(ctx context.Context,
 			impl.logger.Errorw("error, getReferenceChartByteForHelmTypeApp", "envOverride", envOverride, "err", err)
 			return false, nil, err
 		}
+		referenceChartByte, err = impl.postRenderService.PostRenderChartArchive(newCtx, impl.getPostRenderRequest(overrideRequest, valuesOverrideResponse, sanitizedK8sVersion), referenceChartByte)
+		if err != nil {
+			impl.logger.Errorw("error in applying post render patches", "appId", overrideRequest.AppId, "envId", overrideRequest.EnvId, "err", err)
+			return false, nil, err
+		}
 		if pipelineModel.DeploymentAppCreated {
 			req := &gRPC.UpgradeReleaseRequest{
 				ReleaseIdentifier: releaseIdentifier,
@@ -1014,7 +1090,14 @@ func (impl *HandlerServiceImpl) deployArgoCdApp(ctx context.Context, overrideReq
 		impl.logger.Errorw("error in updating argocd app ", "err", err)
 		return err
 	}
-	if valuesOverrideResponse.DeploymentConfig.IsArgoAppSyncAndRefreshSupported() {
+	isAwaitingMerge, err := impl.gitOpsPullRequestService.IsRunnerAwaitingMerge(overrideRequest.WfrId)
+	if err != nil {
+		impl.logger.Errorw("error in checking if runner is awaiting pull request merge", "wfrId", overrideRequest.WfrId, "err", err)
+		return err
+	}
+	if isAwaitingMerge {
+		impl.logger.Infow("values are committed to a pull request, skipping argo cd sync till it is merged", "wfrId", overrideRequest.WfrId)
+	} else if valuesOverrideResponse.DeploymentConfig.IsArgoAppSyncAndRefreshSupported() {
 		syncTime := time.Now()
 		targetRevision := valuesOverrideResponse.DeploymentConfig.GetTargetRevision()
 		err = impl.argoClientWrapperService.SyncArgoCDApplicationIfNeededAndRefresh(newCtx, valuesOverrideResponse.Pipeline.DeploymentAppName, targetRevision)
@@ -24,6 +24,7 @@ import (
 	"github.com/devtron-labs/devtron/api/bean"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	bean3 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	imageSigningBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
 	"github.com/devtron-labs/devtron/util"
 	"time"
 )
@@ -88,6 +89,8 @@ type CiCompleteEvent struct {
 	TargetPlatforms               []string                 `json:"targetPlatforms"`
 	pluginImageDetails            *registry.ImageDetailsFromCR
 	PluginArtifacts               *PluginArtifacts `json:"pluginArtifacts"`
+	// KeylessSignatures are reported by ci-runner when the image is signed keyless in the post build step
+	KeylessSignatures []*imageSigningBean.KeylessSignatureMetadata `json:"keylessSignatures"`
 }
 
 func (c *CiCompleteEvent) GetPluginImageDetails() *registry.ImageDetailsFromCR {
@@ -21,6 +21,7 @@ import (
 	pubsub "github.com/devtron-labs/common-lib/pubsub-lib"
 	"github.com/devtron-labs/common-lib/pubsub-lib/model"
 	"github.com/devtron-labs/devtron/client/gitSensor"
+	"github.com/devtron-labs/devtron/pkg/appAsCode"
 	"github.com/devtron-labs/devtron/pkg/build/git/gitWebhook"
 	"go.uber.org/zap"
 )
@@ -29,14 +30,16 @@ type CIPipelineEventProcessorImpl struct {
 	logger            *zap.SugaredLogger
 	pubSubClient      *pubsub.PubSubClientServiceImpl
 	gitWebhookService gitWebhook.GitWebhookService
+	appAsCodeService  appAsCode.AppAsCodeService
 }
 
 func NewCIPipelineEventProcessorImpl(logger *zap.SugaredLogger, pubSubClient *pubsub.PubSubClientServiceImpl,
-	gitWebhookService gitWebhook.GitWebhookService) *CIPipelineEventProcessorImpl {
+	gitWebhookService gitWebhook.GitWebhookService, appAsCodeService appAsCode.AppAsCodeService) *CIPipelineEventProcessorImpl {
 	ciPipelineEventProcessorImpl := &CIPipelineEventProcessorImpl{
 		logger:            logger,
 		pubSubClient:      pubSubClient,
 		gitWebhookService: gitWebhookService,
+		appAsCodeService:  appAsCodeService,
 	}
 	return ciPipelineEventProcessorImpl
 }
@@ -50,6 +53,14 @@ func (impl *CIPipelineEventProcessorImpl) SubscribeNewCIMaterialEvent() error {
 			impl.logger.Error("Error while unmarshalling json response", "error", err)
 			return
 		}
+		// app-as-code sources are polled by git-sensor as well, their commits sync the source instead of triggering a ci
+		isAppAsCodeSource, err := impl.appAsCodeService.HandleNewCommit(ciPipelineMaterial.Id)
+		if err != nil {
+			impl.logger.Errorw("error in handling new commit of app-as-code source", "ciPipelineMaterialId", ciPipelineMaterial.Id, "err", err)
+			return
+		} else if isAppAsCodeSource {
+			return
+		}
 		resp, err := impl.gitWebhookService.HandleGitWebhook(ciPipelineMaterial)
 		impl.logger.Debug(resp)
 		if err != nil {
@@ -54,6 +54,7 @@ import (
 	eventProcessorBean "github.com/devtron-labs/devtron/pkg/eventProcessor/out/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	"github.com/devtron-labs/devtron/pkg/pipeline/executors"
+	"github.com/devtron-labs/devtron/pkg/testReport"
 	"github.com/devtron-labs/devtron/pkg/ucid"
 	"github.com/devtron-labs/devtron/pkg/workflow/cd"
 	"github.com/devtron-labs/devtron/pkg/workflow/cd/adapter"
@@ -106,6 +107,7 @@ type WorkflowEventProcessorImpl struct {
 	ciArtifactRepository    repository.CiArtifactRepository
 	cdWorkflowRepository    pipelineConfig.CdWorkflowRepository
 	deploymentConfigService common.DeploymentConfigService
+	testReportService       testReport.TestReportService
 }
 
 func NewWorkflowEventProcessorImpl(logger *zap.SugaredLogger,
@@ -131,7 +133,8 @@ func NewWorkflowEventProcessorImpl(logger *zap.SugaredLogger,
 	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
 	deploymentConfigService common.DeploymentConfigService,
 	ciHandlerService trigger.HandlerService,
-	asyncRunnable *async.Runnable) (*WorkflowEventProcessorImpl, error) {
+	asyncRunnable *async.Runnable,
+	testReportService testReport.TestReportService) (*WorkflowEventProcessorImpl, error) {
 	impl := &WorkflowEventProcessorImpl{
 		logger:                          logger,
 		pubSubClient:                    pubSubClient,
@@ -161,6 +164,7 @@ func NewWorkflowEventProcessorImpl(logger *zap.SugaredLogger,
 		deploymentConfigService:         deploymentConfigService,
 		ciHandlerService:                ciHandlerService,
 		asyncRunnable:                   asyncRunnable,
+		testReportService:               testReportService,
 	}
 	appServiceConfig, err := app.GetAppServiceConfig()
 	if err != nil {
@@ -427,6 +431,14 @@ func (impl *WorkflowEventProcessorImpl) SubscribeCIWorkflowStatusUpdate() error
 			return
 		}
 		if stateChanged {
+			// test reports are parsed from the uploaded artifacts once the workflow completes
+			if _, status, _, _, _, _ := pipeline.ExtractWorkflowStatus(wfStatus); testReport.IsReportIngestionStatus(status) {
+				impl.asyncRunnable.Execute(func() {
+					if err := impl.testReportService.IngestCiWorkflowReports(ciWfId); err != nil {
+						impl.logger.Errorw("error in ingesting ci workflow test reports", "ciWorkflowId", ciWfId, "err", err)
+					}
+				})
+			}
 			// check if we need to re-trigger the ci
 			err = impl.ciHandlerService.CheckAndReTriggerCI(wfStatus)
 			if err != nil {
@@ -487,6 +499,13 @@ func (impl *WorkflowEventProcessorImpl) SubscribeCDWorkflowStatusUpdate() error
 		}
 
 		if stateChanged {
+			if testReport.IsReportIngestionStatus(status) {
+				impl.asyncRunnable.Execute(func() {
+					if err := impl.testReportService.IngestCdWorkflowRunnerReports(wfrId); err != nil {
+						impl.logger.Errorw("error in ingesting cd workflow runner test reports", "wfrId", wfrId, "err", err)
+					}
+				})
+			}
 			wfr, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(wfrId)
 			if err != nil {
 				impl.logger.Errorw("could not get wf runner", "wfrId", wfrId, "err", err)
@@ -756,6 +775,7 @@ func (impl *WorkflowEventProcessorImpl) BuildCiArtifactRequest(event bean.CiComp
 		PluginArtifactStage:           event.PluginArtifactStage,
 		IsScanEnabled:                 event.IsScanEnabled,
 		TargetPlatforms:               event.TargetPlatforms,
+		KeylessSignatures:             event.KeylessSignatures,
 	}
 	// if DataSource is empty, repository.WEBHOOK is considered as default
 	if request.DataSource == "" {
@@ -38,6 +38,7 @@ import (
 	cdConfigRead "github.com/devtron-labs/devtron/pkg/deployment/common/read"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/read"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/postRender"
 	bean2 "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
 	k8s2 "github.com/devtron-labs/devtron/pkg/k8s"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
@@ -92,6 +93,7 @@ type DeploymentTemplateServiceImpl struct {
 	mergeUtil                            *util.MergeUtil
 	deploymentTemplateHistoryReadService read.DeploymentTemplateHistoryReadService
 	deploymentConfigReadService          cdConfigRead.DeploymentConfigReadService
+	postRenderService                    postRender.PostRenderService
 }
 
 func GetRestartWorkloadConfig() (*RestartWorkloadConfig, error) {
@@ -119,6 +121,7 @@ func NewDeploymentTemplateServiceImpl(Logger *zap.SugaredLogger, chartService ch
 	mergeUtil *util.MergeUtil,
 	deploymentTemplateHistoryReadService read.DeploymentTemplateHistoryReadService,
 	deploymentConfigReadService cdConfigRead.DeploymentConfigReadService,
+	postRenderService postRender.PostRenderService,
 ) (*DeploymentTemplateServiceImpl, error) {
 	deploymentTemplateServiceImpl := &DeploymentTemplateServiceImpl{
 		Logger:                               Logger,
@@ -141,6 +144,7 @@ func NewDeploymentTemplateServiceImpl(Logger *zap.SugaredLogger, chartService ch
 		mergeUtil:                            mergeUtil,
 		deploymentTemplateHistoryReadService: deploymentTemplateHistoryReadService,
 		deploymentConfigReadService:          deploymentConfigReadService,
+		postRenderService:                    postRenderService,
 	}
 	cfg, err := GetRestartWorkloadConfig()
 	if err != nil {
@@ -523,8 +527,16 @@ func (impl DeploymentTemplateServiceImpl) GenerateManifest(ctx context.Context,
 		}
 		return nil, err
 	}
+	generatedManifest := templateChartResponse.GeneratedManifest
+	if request.EnvId > 0 {
+		generatedManifest, err = impl.postRenderService.PatchManifest(request.AppId, request.EnvId, generatedManifest)
+		if err != nil {
+			impl.Logger.Errorw("error in applying post render patches", "appId", request.AppId, "envId", request.EnvId, "err", err)
+			return nil, err
+		}
+	}
 	response := &openapi2.TemplateChartResponse{
-		Manifest: &templateChartResponse.GeneratedManifest,
+		Manifest: &generatedManifest,
 	}
 
 	return response, nil
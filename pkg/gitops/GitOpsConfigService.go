@@ -35,7 +35,9 @@ import (
 	repository2 "github.com/devtron-labs/devtron/client/argocdServer/repository"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
+	gitOpsConfigBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/config/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
+	gitBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/validation"
 	gitOpsBean "github.com/devtron-labs/devtron/pkg/gitops/bean"
 	moduleBean "github.com/devtron-labs/devtron/pkg/module/bean"
@@ -43,6 +45,7 @@ import (
 	moduleReadBean "github.com/devtron-labs/devtron/pkg/module/read/bean"
 	moduleErr "github.com/devtron-labs/devtron/pkg/module/read/error"
 	util2 "github.com/devtron-labs/devtron/util"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -55,6 +58,7 @@ import (
 	util3 "github.com/devtron-labs/devtron/pkg/util"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
 	k8sError "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/yaml"
 )
@@ -123,6 +127,10 @@ func NewGitOpsConfigServiceImpl(Logger *zap.SugaredLogger,
 }
 
 func (impl *GitOpsConfigServiceImpl) ValidateAndCreateGitOpsConfig(config *apiBean.GitOpsConfigDto) (apiBean.DetailedErrorGitOpsConfigResponse, error) {
+	err := validateRepositoryLayout(config)
+	if err != nil {
+		return apiBean.DetailedErrorGitOpsConfigResponse{}, err
+	}
 	argoModule, err := impl.moduleReadService.GetModuleInfoByName(moduleBean.ModuleNameArgoCd)
 	if err != nil && !errors.Is(err, moduleErr.ModuleNotFoundError) {
 		impl.logger.Errorw("error in getting argo module", "error", err)
@@ -171,6 +179,18 @@ func (impl *GitOpsConfigServiceImpl) updateArgoCdUserDetailIfNotPresent(argoModu
 }
 
 func (impl *GitOpsConfigServiceImpl) ValidateAndUpdateGitOpsConfig(config *apiBean.GitOpsConfigDto) (apiBean.DetailedErrorGitOpsConfigResponse, error) {
+	err := impl.fillSshPrivateKeyIfHidden(config)
+	if err != nil {
+		return apiBean.DetailedErrorGitOpsConfigResponse{}, err
+	}
+	err = validateRepositoryLayout(config)
+	if err != nil {
+		return apiBean.DetailedErrorGitOpsConfigResponse{}, err
+	}
+	err = validateSshKnownHosts(config)
+	if err != nil {
+		return apiBean.DetailedErrorGitOpsConfigResponse{}, err
+	}
 	isTokenEmpty := config.Token == ""
 	isTlsDetailsEmpty := config.EnableTLSVerification &&
 		(config.TLSConfig == nil ||
@@ -238,7 +258,13 @@ func (impl *GitOpsConfigServiceImpl) ValidateAndUpdateGitOpsConfig(config *apiBe
 // step-3: add repository URL in argocd-cm, argocd-cm will have reference to secret created in step-3 for credentials
 // step-4: upsert cluster in acd
 func (impl *GitOpsConfigServiceImpl) registerGitOpsClientConfig(ctx context.Context, model *repository.GitOpsConfig, request *apiBean.GitOpsConfigDto) (*apiBean.GitOpsConfigDto, error) {
-	if model.EnableTLSVerification {
+	if model.Provider == gitBean.GIT_SSH_PROVIDER {
+		err := impl.registerSshRepoCredsInArgo(ctx, model, request)
+		if err != nil {
+			impl.logger.Errorw("error in saving ssh repo credential template to argocd", "err", err)
+			return nil, err
+		}
+	} else if model.EnableTLSVerification {
 		err := impl.gitOperationService.UpdateGitHostUrlByProvider(request)
 		if err != nil {
 			return nil, err
@@ -415,6 +441,14 @@ func (impl *GitOpsConfigServiceImpl) createGitOpsConfig(ctx context.Context, req
 		AllowCustomRepository: request.AllowCustomRepository,
 		BitBucketWorkspaceId:  request.BitBucketWorkspaceId,
 		BitBucketProjectKey:   request.BitBucketProjectKey,
+		GiteaOrgName:          request.GiteaOrgName,
+		SshKey:                request.SshPrivateKey,
+		SshKnownHosts:         request.SshKnownHosts,
+		RepoHookUrl:           request.RepoHookUrl,
+		DryRunRepoName:        request.DryRunRepoName,
+		RepositoryLayout:      request.GetRepositoryLayout(),
+		MonorepoName:          request.MonorepoName,
+		MonorepoPathTemplate:  request.MonorepoPathTemplate,
 		EnableTLSVerification: request.EnableTLSVerification,
 		AuditLog:              sql.AuditLog{CreatedBy: request.UserId, CreatedOn: time.Now(), UpdatedOn: time.Now(), UpdatedBy: request.UserId},
 	}
@@ -523,8 +557,137 @@ func (impl *GitOpsConfigServiceImpl) addCACertInArgoIfPresent(ctx context.Contex
 	return nil
 }
 
+// registerSshRepoCredsInArgo saves the deploy key as a credential template for the ssh host, so argocd can pull
+// every repository under it, and adds the known hosts as ssh certificates
+func (impl *GitOpsConfigServiceImpl) registerSshRepoCredsInArgo(ctx context.Context, model *repository.GitOpsConfig, request *apiBean.GitOpsConfigDto) error {
+	err := impl.gitOperationService.UpdateGitHostUrlByProvider(request)
+	if err != nil {
+		return err
+	}
+	_, err = impl.argoClientWrapperService.CreateRepoCreds(ctx, &repocreds2.RepoCredsCreateRequest{
+		Creds: &v1alpha1.RepoCreds{
+			URL:           request.Host,
+			SSHPrivateKey: model.SshKey,
+		},
+		Upsert: true,
+	})
+	if err != nil {
+		return err
+	}
+	certificates := getSshKnownHostCertificates(model.SshKnownHosts)
+	if len(certificates) == 0 {
+		return nil
+	}
+	_, err = impl.argoClientWrapperService.CreateCertificate(ctx, &certificate2.RepositoryCertificateCreateRequest{
+		Certificates: &v1alpha1.RepositoryCertificateList{
+			Items: certificates,
+		},
+		Upsert: true,
+	})
+	return err
+}
+
+// getSshKnownHostCertificates converts known_hosts lines (<hosts> <key type> <key>) to argocd ssh certificates,
+// hashed host names are skipped as argocd needs the server name
+func getSshKnownHostCertificates(knownHosts string) []v1alpha1.RepositoryCertificate {
+	certificates := make([]v1alpha1.RepositoryCertificate, 0)
+	for _, line := range strings.Split(knownHosts, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "|") {
+			continue
+		}
+		for _, host := range strings.Split(fields[0], ",") {
+			certificates = append(certificates, v1alpha1.RepositoryCertificate{
+				ServerName:  host,
+				CertType:    "ssh",
+				CertSubType: fields[1],
+				CertData:    []byte(fields[2]),
+			})
+		}
+	}
+	return certificates
+}
+
+// validateRepositoryLayout checks the shared repository and its path template when charts of all apps are kept in a monorepo
+func validateRepositoryLayout(config *apiBean.GitOpsConfigDto) error {
+	if !config.IsMonorepoLayout() {
+		return nil
+	}
+	if len(config.MonorepoName) == 0 {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: "monorepo name is required for MONOREPO repository layout",
+			UserMessage:     "monorepo name is required for MONOREPO repository layout",
+		}
+	}
+	err := gitOpsConfigBean.ValidateMonorepoPathTemplate(config.MonorepoPathTemplate)
+	if err != nil {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: err.Error(),
+			UserMessage:     err.Error(),
+		}
+	}
+	return nil
+}
+
+// validateSshKnownHosts requires parsable known hosts for git over ssh, the host key of the git server is always verified
+func validateSshKnownHosts(config *apiBean.GitOpsConfigDto) error {
+	if config.Provider != gitBean.GIT_SSH_PROVIDER {
+		return nil
+	}
+	errMsg := ""
+	entries := 0
+	knownHosts := []byte(config.SshKnownHosts)
+	for {
+		_, _, _, _, rest, err := ssh.ParseKnownHosts(knownHosts)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			errMsg = fmt.Sprintf("invalid ssh known hosts: %s", err.Error())
+			break
+		}
+		entries++
+		knownHosts = rest
+	}
+	if len(errMsg) == 0 && entries == 0 {
+		errMsg = "ssh known hosts are required for GIT_SSH provider"
+	}
+	if len(errMsg) > 0 {
+		return &util.ApiError{
+			HttpStatusCode:  http.StatusBadRequest,
+			InternalMessage: errMsg,
+			UserMessage:     errMsg,
+		}
+	}
+	return nil
+}
+
+// fillSshPrivateKeyIfHidden sets the saved deploy key on requests coming from FE where the key is hidden
+func (impl *GitOpsConfigServiceImpl) fillSshPrivateKeyIfHidden(config *apiBean.GitOpsConfigDto) error {
+	if config.Provider != gitBean.GIT_SSH_PROVIDER || len(config.SshPrivateKey) > 0 || !config.IsSshPrivateKeyPresent || config.Id == 0 {
+		return nil
+	}
+	model, err := impl.gitOpsRepository.GetGitOpsConfigById(config.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching gitOps config for ssh key", "id", config.Id, "err", err)
+		return &util.ApiError{
+			InternalMessage: "gitops config update failed, does not exist",
+			UserMessage:     "gitops config update failed, does not exist",
+		}
+	}
+	config.SshPrivateKey = model.SshKey
+	return nil
+}
+
 func (impl *GitOpsConfigServiceImpl) patchGitOpsClientConfig(model *repository.GitOpsConfig, request *apiBean.GitOpsConfigDto) error {
-	if model.EnableTLSVerification {
+	if model.Provider == gitBean.GIT_SSH_PROVIDER {
+		err := impl.registerSshRepoCredsInArgo(context.Background(), model, request)
+		if err != nil {
+			impl.logger.Errorw("error in saving ssh repo credential template to argocd", "err", err)
+			return err
+		}
+	} else if model.EnableTLSVerification {
 		err := impl.gitOperationService.UpdateGitHostUrlByProvider(request)
 		if err != nil {
 			return err
@@ -686,6 +849,19 @@ func (impl *GitOpsConfigServiceImpl) updateGitOpsConfig(request *apiBean.GitOpsC
 	model.AzureProject = request.AzureProjectName
 	model.BitBucketWorkspaceId = request.BitBucketWorkspaceId
 	model.BitBucketProjectKey = request.BitBucketProjectKey
+	model.GiteaOrgName = request.GiteaOrgName
+	model.SshKnownHosts = request.SshKnownHosts
+	model.RepoHookUrl = request.RepoHookUrl
+	model.DryRunRepoName = request.DryRunRepoName
+	model.RepositoryLayout = request.GetRepositoryLayout()
+	model.MonorepoName = request.MonorepoName
+	model.MonorepoPathTemplate = request.MonorepoPathTemplate
+	// the key is hidden in FE, an empty value keeps the saved key unless it was removed explicitly
+	if len(request.SshPrivateKey) > 0 {
+		model.SshKey = request.SshPrivateKey
+	} else if !request.IsSshPrivateKeyPresent {
+		model.SshKey = ""
+	}
 	model.AllowCustomRepository = request.AllowCustomRepository
 	model.EnableTLSVerification = request.EnableTLSVerification
 	model.UpdatedBy = request.UserId
@@ -789,6 +965,13 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigById(id int) (*apiBean.GitOp
 		AzureProjectName:      model.AzureProject,
 		BitBucketWorkspaceId:  model.BitBucketWorkspaceId,
 		BitBucketProjectKey:   model.BitBucketProjectKey,
+		GiteaOrgName:          model.GiteaOrgName,
+		SshKnownHosts:         model.SshKnownHosts,
+		RepoHookUrl:           model.RepoHookUrl,
+		DryRunRepoName:        model.DryRunRepoName,
+		RepositoryLayout:      model.RepositoryLayout,
+		MonorepoName:          model.MonorepoName,
+		MonorepoPathTemplate:  model.MonorepoPathTemplate,
 		AllowCustomRepository: model.AllowCustomRepository,
 		EnableTLSVerification: model.EnableTLSVerification,
 		TLSConfig: &bean.TLSConfig{ // sending empty values as they are hidden in FE
@@ -796,9 +979,10 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigById(id int) (*apiBean.GitOp
 			TLSCertData: "",
 			TLSKeyData:  "",
 		},
-		IsCADataPresent:      len(model.CaCert) > 0,
-		IsTLSCertDataPresent: len(model.TlsCert) > 0,
-		IsTLSKeyDataPresent:  len(model.TlsKey) > 0,
+		IsCADataPresent:        len(model.CaCert) > 0,
+		IsTLSCertDataPresent:   len(model.TlsCert) > 0,
+		IsTLSKeyDataPresent:    len(model.TlsKey) > 0,
+		IsSshPrivateKeyPresent: len(model.SshKey) > 0,
 	}
 	return config, err
 }
@@ -824,6 +1008,13 @@ func (impl *GitOpsConfigServiceImpl) GetAllGitOpsConfig() ([]*apiBean.GitOpsConf
 			AzureProjectName:      model.AzureProject,
 			BitBucketWorkspaceId:  model.BitBucketWorkspaceId,
 			BitBucketProjectKey:   model.BitBucketProjectKey,
+			GiteaOrgName:          model.GiteaOrgName,
+			SshKnownHosts:         model.SshKnownHosts,
+			RepoHookUrl:           model.RepoHookUrl,
+			DryRunRepoName:        model.DryRunRepoName,
+			RepositoryLayout:      model.RepositoryLayout,
+			MonorepoName:          model.MonorepoName,
+			MonorepoPathTemplate:  model.MonorepoPathTemplate,
 			AllowCustomRepository: model.AllowCustomRepository,
 			EnableTLSVerification: model.EnableTLSVerification,
 			TLSConfig: &bean.TLSConfig{ // sending empty values as they are hidden in FE
@@ -831,9 +1022,10 @@ func (impl *GitOpsConfigServiceImpl) GetAllGitOpsConfig() ([]*apiBean.GitOpsConf
 				TLSCertData: "",
 				TLSKeyData:  "",
 			},
-			IsCADataPresent:      len(model.CaCert) > 0,
-			IsTLSCertDataPresent: len(model.TlsCert) > 0,
-			IsTLSKeyDataPresent:  len(model.TlsKey) > 0,
+			IsCADataPresent:        len(model.CaCert) > 0,
+			IsTLSCertDataPresent:   len(model.TlsCert) > 0,
+			IsTLSKeyDataPresent:    len(model.TlsKey) > 0,
+			IsSshPrivateKeyPresent: len(model.SshKey) > 0,
 		}
 		configs = append(configs, config)
 	}
@@ -859,6 +1051,13 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigByProvider(provider string)
 		AzureProjectName:      model.AzureProject,
 		BitBucketWorkspaceId:  model.BitBucketWorkspaceId,
 		BitBucketProjectKey:   model.BitBucketProjectKey,
+		GiteaOrgName:          model.GiteaOrgName,
+		SshKnownHosts:         model.SshKnownHosts,
+		RepoHookUrl:           model.RepoHookUrl,
+		DryRunRepoName:        model.DryRunRepoName,
+		RepositoryLayout:      model.RepositoryLayout,
+		MonorepoName:          model.MonorepoName,
+		MonorepoPathTemplate:  model.MonorepoPathTemplate,
 		AllowCustomRepository: model.AllowCustomRepository,
 		EnableTLSVerification: model.EnableTLSVerification,
 		TLSConfig: &bean.TLSConfig{ // sending empty values as they are hidden in FE
@@ -866,15 +1065,22 @@ func (impl *GitOpsConfigServiceImpl) GetGitOpsConfigByProvider(provider string)
 			TLSCertData: "",
 			TLSKeyData:  "",
 		},
-		IsCADataPresent:      len(model.CaCert) > 0,
-		IsTLSCertDataPresent: len(model.TlsCert) > 0,
-		IsTLSKeyDataPresent:  len(model.TlsKey) > 0,
+		IsCADataPresent:        len(model.CaCert) > 0,
+		IsTLSCertDataPresent:   len(model.TlsCert) > 0,
+		IsTLSKeyDataPresent:    len(model.TlsKey) > 0,
+		IsSshPrivateKeyPresent: len(model.SshKey) > 0,
 	}
 
 	return config, err
 }
 
 func (impl *GitOpsConfigServiceImpl) GitOpsValidateDryRun(isArgoModuleInstalled bool, config *apiBean.GitOpsConfigDto) apiBean.DetailedErrorGitOpsConfigResponse {
+	if err := impl.fillSshPrivateKeyIfHidden(config); err != nil {
+		return apiBean.DetailedErrorGitOpsConfigResponse{
+			StageErrorMap: map[string]string{"Fetch saved ssh key": err.Error()},
+			ValidatedOn:   time.Now(),
+		}
+	}
 
 	isTokenEmpty := config.Token == ""
 	isTlsDetailsEmpty := config.EnableTLSVerification && (len(config.TLSConfig.CaData) == 0 && len(config.TLSConfig.TLSCertData) == 0 && len(config.TLSConfig.TLSKeyData) == 0)
@@ -0,0 +1,533 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package costAllocation
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env"
+	k8s2 "github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	environmentRepository "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/k8s/costAllocation/adapter"
+	"github.com/devtron-labs/devtron/pkg/k8s/costAllocation/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/costAllocation/helper"
+	"github.com/devtron-labs/devtron/pkg/k8s/costAllocation/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	teamRepository "github.com/devtron-labs/devtron/pkg/team/repository"
+	cronUtil "github.com/devtron-labs/devtron/util/cron"
+	"github.com/gammazero/workerpool"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"sort"
+	"time"
+)
+
+type CostAllocationConfig struct {
+	SampleIntervalMins int `env:"COST_ALLOCATION_SAMPLE_INTERVAL_MINS" envDefault:"60" description:"Interval at which the resources of the clusters are sampled to allocate their cost, 0 disables cost allocation"`
+	Concurrency        int `env:"COST_ALLOCATION_CONCURRENCY" envDefault:"5" description:"Number of clusters sampled in parallel for cost allocation"`
+	ClusterTimeoutSecs int `env:"COST_ALLOCATION_CLUSTER_TIMEOUT_SECS" envDefault:"60" description:"Timeout of sampling the resources of a cluster for cost allocation"`
+}
+
+func GetCostAllocationConfig() (*CostAllocationConfig, error) {
+	cfg := &CostAllocationConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+type CostAllocationService interface {
+	// SampleClusters allocates the cost of the current interval of every cluster to the apps, environments and namespaces running on it
+	SampleClusters()
+	GetAllocationReport(request *bean.ReportRequest) (*bean.AllocationReport, error)
+	// GetIdleCostReport returns the cost of the clusters not requested or used by any pod
+	GetIdleCostReport(from, to time.Time) (*bean.IdleCostReport, error)
+	GetPriceSheets() ([]*bean.PriceSheetDto, error)
+	// SavePriceSheet creates or updates the default price sheet for clusterId 0, else the override of the cluster
+	SavePriceSheet(request *bean.PriceSheetDto) (*bean.PriceSheetDto, error)
+	// DeletePriceSheet removes the override of the cluster, the cluster is priced with the default sheet after
+	DeletePriceSheet(clusterId int, userId int32) error
+}
+
+type CostAllocationServiceImpl struct {
+	logger                *zap.SugaredLogger
+	clusterService        cluster.ClusterService
+	k8sCommonService      k8s.K8sCommonService
+	K8sUtil               *k8s2.K8sServiceImpl
+	appRepository         app.AppRepository
+	environmentRepository environmentRepository.EnvironmentRepository
+	teamRepository        teamRepository.TeamRepository
+	repository            repository.CostAllocationRepository
+	config                *CostAllocationConfig
+}
+
+func NewCostAllocationServiceImpl(logger *zap.SugaredLogger,
+	clusterService cluster.ClusterService,
+	k8sCommonService k8s.K8sCommonService,
+	K8sUtil *k8s2.K8sServiceImpl,
+	appRepository app.AppRepository,
+	environmentRepository environmentRepository.EnvironmentRepository,
+	teamRepository teamRepository.TeamRepository,
+	repository repository.CostAllocationRepository,
+	config *CostAllocationConfig,
+	cronLogger *cronUtil.CronLoggerImpl) (*CostAllocationServiceImpl, error) {
+	impl := &CostAllocationServiceImpl{
+		logger:                logger,
+		clusterService:        clusterService,
+		k8sCommonService:      k8sCommonService,
+		K8sUtil:               K8sUtil,
+		appRepository:         appRepository,
+		environmentRepository: environmentRepository,
+		teamRepository:        teamRepository,
+		repository:            repository,
+		config:                config,
+	}
+	if config.SampleIntervalMins > 0 {
+		sampleCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+		_, err := sampleCron.AddFunc(fmt.Sprintf("@every %dm", config.SampleIntervalMins), impl.SampleClusters)
+		if err != nil {
+			logger.Errorw("error in adding cost allocation cron", "err", err)
+			return nil, err
+		}
+		sampleCron.Start()
+	}
+	return impl, nil
+}
+
+func (impl *CostAllocationServiceImpl) SampleClusters() {
+	interval := time.Duration(impl.config.SampleIntervalMins) * time.Minute
+	// the interval is sampled once whichever orchestrator samples it first
+	sampledAt := time.Now().UTC().Truncate(interval)
+	defaultSheet, overrides, err := impl.getPriceSheets()
+	if err != nil {
+		impl.logger.Errorw("error in getting price sheets for cost allocation", "err", err)
+		return
+	}
+	clusters, err := impl.clusterService.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in getting clusters for cost allocation", "err", err)
+		return
+	}
+	wp := workerpool.New(max(impl.config.Concurrency, 1))
+	for i := range clusters {
+		clusterDetail := &clusters[i]
+		if clusterDetail.IsVirtualCluster {
+			continue
+		}
+		priceSheet := adapter.MergePriceSheets(defaultSheet, overrides[clusterDetail.Id])
+		wp.Submit(func() {
+			if err := impl.sampleCluster(clusterDetail, priceSheet, sampledAt, interval); err != nil {
+				impl.logger.Errorw("error in allocating cost of cluster", "clusterId", clusterDetail.Id, "sampledAt", sampledAt, "err", err)
+			}
+		})
+	}
+	wp.StopWait()
+}
+
+func (impl *CostAllocationServiceImpl) sampleCluster(clusterDetail *clusterBean.ClusterBean, priceSheet *bean.PriceSheetDto, sampledAt time.Time, interval time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(impl.config.ClusterTimeoutSecs)*time.Second)
+	defer cancel()
+	restConfig, k8sHttpClient, k8sClientSet, err := impl.k8sCommonService.GetK8sConfigAndClients(ctx, clusterDetail)
+	if err != nil {
+		return err
+	}
+	nodeList, err := impl.K8sUtil.GetNodesList(ctx, k8sClientSet)
+	if err != nil {
+		return err
+	}
+	podList, err := impl.K8sUtil.GetPodsListForNamespace(ctx, k8sClientSet, "")
+	if err != nil {
+		return err
+	}
+	usage := impl.getPodUsage(ctx, clusterDetail.Id, restConfig, k8sHttpClient)
+	allocations := helper.AllocateCost(nodeList.Items, podList.Items, usage, priceSheet, interval.Hours())
+	teamIds, err := impl.getTeamIdsOfApps(allocations)
+	if err != nil {
+		return err
+	}
+	date := time.Date(sampledAt.Year(), sampledAt.Month(), sampledAt.Day(), 0, 0, 0, 0, time.UTC)
+	dailyAllocations := make([]*repository.CostAllocationDaily, 0, len(allocations))
+	for _, allocation := range allocations {
+		dailyAllocations = append(dailyAllocations, adapter.BuildDailyAllocation(clusterDetail.Id, date, allocation, teamIds[allocation.AppId]))
+	}
+	tx, err := impl.repository.StartTx()
+	if err != nil {
+		return err
+	}
+	defer impl.repository.RollbackTx(tx)
+	sample := &repository.CostAllocationSample{
+		ClusterId:    clusterDetail.Id,
+		SampledAt:    sampledAt,
+		IntervalMins: impl.config.SampleIntervalMins,
+		CreatedOn:    time.Now(),
+	}
+	saved, err := impl.repository.SaveSample(tx, sample)
+	if err != nil {
+		return err
+	} else if !saved {
+		impl.logger.Debugw("cost allocation interval already sampled", "clusterId", clusterDetail.Id, "sampledAt", sampledAt)
+		return nil
+	}
+	err = impl.repository.AddDailyAllocations(tx, dailyAllocations)
+	if err != nil {
+		return err
+	}
+	return impl.repository.CommitTx(tx)
+}
+
+// getPodUsage returns the usage of the pods from the metrics server, nil if the cluster has no metrics server in which
+// case the cost is allocated by requests only
+func (impl *CostAllocationServiceImpl) getPodUsage(ctx context.Context, clusterId int, restConfig *rest.Config, k8sHttpClient *http.Client) map[string]corev1.ResourceList {
+	metricsClientSet, err := impl.K8sUtil.GetMetricsClientSet(restConfig, k8sHttpClient)
+	if err != nil {
+		impl.logger.Warnw("error in getting metrics client set, allocating cost by requests", "clusterId", clusterId, "err", err)
+		return nil
+	}
+	podMetricsList, err := metricsClientSet.MetricsV1beta1().PodMetricses("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		impl.logger.Warnw("error in getting pod metrics, allocating cost by requests", "clusterId", clusterId, "err", err)
+		return nil
+	}
+	usage := make(map[string]corev1.ResourceList, len(podMetricsList.Items))
+	for _, podMetrics := range podMetricsList.Items {
+		podUsage := corev1.ResourceList{}
+		for _, container := range podMetrics.Containers {
+			for name, quantity := range container.Usage {
+				total := podUsage[name]
+				total.Add(quantity)
+				podUsage[name] = total
+			}
+		}
+		usage[helper.PodUsageKey(podMetrics.Namespace, podMetrics.Name)] = podUsage
+	}
+	return usage
+}
+
+func (impl *CostAllocationServiceImpl) getTeamIdsOfApps(allocations []*bean.Allocation) (map[int]int, error) {
+	var appIds []int
+	for _, allocation := range allocations {
+		if allocation.AppId > 0 {
+			appIds = append(appIds, allocation.AppId)
+		}
+	}
+	teamIds := make(map[int]int)
+	if len(appIds) == 0 {
+		return teamIds, nil
+	}
+	apps, err := impl.appRepository.FindAppAndProjectByIdsIn(appIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting apps for cost allocation", "appIds", appIds, "err", err)
+		return nil, err
+	}
+	for _, appDetail := range apps {
+		teamIds[appDetail.Id] = appDetail.TeamId
+	}
+	return teamIds, nil
+}
+
+// getPriceSheets returns the default price sheet and the overrides by cluster id
+func (impl *CostAllocationServiceImpl) getPriceSheets() (*bean.PriceSheetDto, map[int]*bean.PriceSheetDto, error) {
+	priceSheets, err := impl.repository.FindActivePriceSheets()
+	if err != nil {
+		return nil, nil, err
+	}
+	var defaultSheet *bean.PriceSheetDto
+	overrides := make(map[int]*bean.PriceSheetDto)
+	for _, priceSheet := range priceSheets {
+		priceSheetDto, err := adapter.GetPriceSheetDto(priceSheet)
+		if err != nil {
+			impl.logger.Errorw("error in parsing price sheet", "id", priceSheet.Id, "err", err)
+			return nil, nil, err
+		}
+		if priceSheet.ClusterId == 0 {
+			defaultSheet = priceSheetDto
+		} else {
+			overrides[priceSheet.ClusterId] = priceSheetDto
+		}
+	}
+	if defaultSheet == nil {
+		return nil, nil, fmt.Errorf("default price sheet not found")
+	}
+	return defaultSheet, overrides, nil
+}
+
+func (impl *CostAllocationServiceImpl) GetAllocationReport(request *bean.ReportRequest) (*bean.AllocationReport, error) {
+	if len(request.GroupBy) == 0 {
+		request.GroupBy = bean.GroupByApp
+	}
+	if err := request.Validate(); err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	defaultSheet, _, err := impl.getPriceSheets()
+	if err != nil {
+		return nil, err
+	}
+	allocations, err := impl.repository.FindAllocationsSummedByOwner(request.From, request.To, request.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	items, idleCost := helper.GroupAllocations(allocations, request.GroupBy)
+	err = impl.setItemNames(items, request.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+	report := &bean.AllocationReport{
+		From:     request.From.Format(bean.DateLayout),
+		To:       request.To.Format(bean.DateLayout),
+		GroupBy:  request.GroupBy,
+		Currency: defaultSheet.Currency,
+		Items:    items,
+		IdleCost: idleCost,
+	}
+	for _, item := range items {
+		report.AllocatedCost += item.TotalCost
+	}
+	report.TotalCost = report.AllocatedCost + report.IdleCost
+	return report, nil
+}
+
+// setItemNames resolves the names of the owners the items are grouped by, owners deleted since are named by their id
+func (impl *CostAllocationServiceImpl) setItemNames(items []*bean.CostItem, groupBy bean.GroupBy) error {
+	if groupBy == bean.GroupByNamespace {
+		return nil
+	}
+	var ids []int
+	for _, item := range items {
+		if item.Id > 0 {
+			ids = append(ids, item.Id)
+		}
+	}
+	names := make(map[int]string)
+	if len(ids) > 0 {
+		var err error
+		names, err = impl.getNamesByIds(ids, groupBy)
+		if err != nil {
+			impl.logger.Errorw("error in getting names of cost allocation owners", "groupBy", groupBy, "ids", ids, "err", err)
+			return err
+		}
+	}
+	for _, item := range items {
+		if item.Id == 0 {
+			item.Name = bean.UnallocatedName
+		} else if name, ok := names[item.Id]; ok {
+			item.Name = name
+		} else {
+			item.Name = fmt.Sprintf("%s-%d", groupBy, item.Id)
+		}
+	}
+	return nil
+}
+
+func (impl *CostAllocationServiceImpl) getNamesByIds(ids []int, groupBy bean.GroupBy) (map[int]string, error) {
+	names := make(map[int]string, len(ids))
+	idPtrs := make([]*int, 0, len(ids))
+	for i := range ids {
+		idPtrs = append(idPtrs, &ids[i])
+	}
+	switch groupBy {
+	case bean.GroupByApp:
+		apps, err := impl.appRepository.FindAppAndProjectByIdsIn(ids)
+		if err != nil && err != pg.ErrNoRows {
+			return nil, err
+		}
+		for _, appDetail := range apps {
+			names[appDetail.Id] = appDetail.AppName
+		}
+	case bean.GroupByEnvironment:
+		envs, err := impl.environmentRepository.FindByIds(idPtrs)
+		if err != nil && err != pg.ErrNoRows {
+			return nil, err
+		}
+		for _, env := range envs {
+			names[env.Id] = env.Name
+		}
+	case bean.GroupByTeam:
+		teams, err := impl.teamRepository.FindByIds(idPtrs)
+		if err != nil && err != pg.ErrNoRows {
+			return nil, err
+		}
+		for _, team := range teams {
+			names[team.Id] = team.Name
+		}
+	case bean.GroupByCluster:
+		clusters, err := impl.clusterService.FindByIds(ids)
+		if err != nil && err != pg.ErrNoRows {
+			return nil, err
+		}
+		for _, clusterDetail := range clusters {
+			names[clusterDetail.Id] = clusterDetail.ClusterName
+		}
+	}
+	return names, nil
+}
+
+func (impl *CostAllocationServiceImpl) GetIdleCostReport(from, to time.Time) (*bean.IdleCostReport, error) {
+	request := &bean.ReportRequest{From: from, To: to}
+	if err := request.Validate(); err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	defaultSheet, _, err := impl.getPriceSheets()
+	if err != nil {
+		return nil, err
+	}
+	allocations, err := impl.repository.FindAllocationsSummedByOwner(from, to, 0)
+	if err != nil {
+		return nil, err
+	}
+	clusterCosts := make(map[int]*bean.ClusterIdleCost)
+	var clusterIds []int
+	for _, allocation := range allocations {
+		clusterCost, ok := clusterCosts[allocation.ClusterId]
+		if !ok {
+			clusterCost = &bean.ClusterIdleCost{ClusterId: allocation.ClusterId}
+			clusterCosts[allocation.ClusterId] = clusterCost
+			clusterIds = append(clusterIds, allocation.ClusterId)
+		}
+		cost := allocation.CpuCost + allocation.MemoryCost
+		if allocation.Idle {
+			clusterCost.IdleCost += cost
+		} else {
+			clusterCost.AllocatedCost += cost
+		}
+	}
+	names := make(map[int]string)
+	if len(clusterIds) > 0 {
+		names, err = impl.getNamesByIds(clusterIds, bean.GroupByCluster)
+		if err != nil {
+			impl.logger.Errorw("error in getting cluster names", "clusterIds", clusterIds, "err", err)
+			return nil, err
+		}
+	}
+	report := &bean.IdleCostReport{
+		From:     from.Format(bean.DateLayout),
+		To:       to.Format(bean.DateLayout),
+		Currency: defaultSheet.Currency,
+		Clusters: make([]*bean.ClusterIdleCost, 0, len(clusterIds)),
+	}
+	for _, clusterId := range clusterIds {
+		clusterCost := clusterCosts[clusterId]
+		clusterCost.ClusterName = names[clusterId]
+		clusterCost.TotalCost = clusterCost.AllocatedCost + clusterCost.IdleCost
+		if clusterCost.TotalCost > 0 {
+			clusterCost.IdlePercentage = clusterCost.IdleCost * 100 / clusterCost.TotalCost
+		}
+		report.Clusters = append(report.Clusters, clusterCost)
+	}
+	sort.Slice(report.Clusters, func(i, j int) bool {
+		return report.Clusters[i].IdleCost > report.Clusters[j].IdleCost
+	})
+	return report, nil
+}
+
+func (impl *CostAllocationServiceImpl) GetPriceSheets() ([]*bean.PriceSheetDto, error) {
+	defaultSheet, overrides, err := impl.getPriceSheets()
+	if err != nil {
+		impl.logger.Errorw("error in getting price sheets", "err", err)
+		return nil, err
+	}
+	priceSheets := []*bean.PriceSheetDto{defaultSheet}
+	if len(overrides) == 0 {
+		return priceSheets, nil
+	}
+	clusterIds := make([]int, 0, len(overrides))
+	for clusterId := range overrides {
+		clusterIds = append(clusterIds, clusterId)
+	}
+	sort.Ints(clusterIds)
+	names, err := impl.getNamesByIds(clusterIds, bean.GroupByCluster)
+	if err != nil {
+		impl.logger.Errorw("error in getting cluster names", "clusterIds", clusterIds, "err", err)
+		return nil, err
+	}
+	for _, clusterId := range clusterIds {
+		override := overrides[clusterId]
+		override.ClusterName = names[clusterId]
+		override.Currency = defaultSheet.Currency
+		priceSheets = append(priceSheets, override)
+	}
+	return priceSheets, nil
+}
+
+func (impl *CostAllocationServiceImpl) SavePriceSheet(request *bean.PriceSheetDto) (*bean.PriceSheetDto, error) {
+	if request.ClusterId > 0 {
+		clusterDetail, err := impl.clusterService.FindById(request.ClusterId)
+		if err != nil {
+			impl.logger.Errorw("error in getting cluster", "clusterId", request.ClusterId, "err", err)
+			if util.IsErrNoRows(err) {
+				return nil, util.NewApiError(http.StatusNotFound, "cluster not found", err.Error())
+			}
+			return nil, err
+		}
+		request.ClusterName = clusterDetail.ClusterName
+		// overrides are priced in the currency of the default sheet
+		request.Currency = ""
+	} else if len(request.Currency) == 0 {
+		request.Currency = bean.DefaultCurrency
+	}
+	for instanceType, price := range request.NodeTypePrices {
+		if price == nil || price.CpuCoreHourPrice < 0 || price.MemoryGibHourPrice < 0 {
+			errMsg := fmt.Sprintf("invalid price of node type %s", instanceType)
+			return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+	}
+	priceSheet, err := impl.repository.FindActivePriceSheetByClusterId(request.ClusterId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting price sheet", "clusterId", request.ClusterId, "err", err)
+		return nil, err
+	}
+	if err = adapter.UpdatePriceSheet(priceSheet, request); err != nil {
+		return nil, err
+	}
+	if priceSheet.Id > 0 {
+		priceSheet.UpdateAuditLog(request.UserId)
+		err = impl.repository.UpdatePriceSheet(priceSheet)
+	} else {
+		priceSheet.Active = true
+		priceSheet.AuditLog = sql.NewDefaultAuditLog(request.UserId)
+		err = impl.repository.SavePriceSheet(priceSheet)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving price sheet", "clusterId", request.ClusterId, "err", err)
+		return nil, err
+	}
+	request.UpdatedOn = priceSheet.UpdatedOn
+	return request, nil
+}
+
+func (impl *CostAllocationServiceImpl) DeletePriceSheet(clusterId int, userId int32) error {
+	if clusterId <= 0 {
+		return util.NewApiError(http.StatusBadRequest, "default price sheet can not be deleted", "default price sheet can not be deleted")
+	}
+	priceSheet, err := impl.repository.FindActivePriceSheetByClusterId(clusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting price sheet", "clusterId", clusterId, "err", err)
+		if util.IsErrNoRows(err) {
+			return util.NewApiError(http.StatusNotFound, "price sheet not found for the cluster", err.Error())
+		}
+		return err
+	}
+	priceSheet.Active = false
+	priceSheet.UpdateAuditLog(userId)
+	return impl.repository.UpdatePriceSheet(priceSheet)
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/k8s/costAllocation/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/costAllocation/repository"
+	"time"
+)
+
+func GetPriceSheetDto(priceSheet *repository.CostPriceSheet) (*bean.PriceSheetDto, error) {
+	priceSheetDto := &bean.PriceSheetDto{
+		ClusterId:          priceSheet.ClusterId,
+		Currency:           priceSheet.Currency,
+		CpuCoreHourPrice:   priceSheet.CpuCoreHourPrice,
+		MemoryGibHourPrice: priceSheet.MemoryGibHourPrice,
+		NodeTypePrices:     make(map[string]*bean.NodeTypePrice),
+		UpdatedOn:          priceSheet.UpdatedOn,
+	}
+	if len(priceSheet.NodeTypePrices) > 0 {
+		if err := json.Unmarshal([]byte(priceSheet.NodeTypePrices), &priceSheetDto.NodeTypePrices); err != nil {
+			return nil, err
+		}
+	}
+	return priceSheetDto, nil
+}
+
+// UpdatePriceSheet sets the prices of the dto on the price sheet model
+func UpdatePriceSheet(priceSheet *repository.CostPriceSheet, priceSheetDto *bean.PriceSheetDto) error {
+	nodeTypePrices, err := json.Marshal(priceSheetDto.NodeTypePrices)
+	if err != nil {
+		return err
+	}
+	priceSheet.ClusterId = priceSheetDto.ClusterId
+	priceSheet.Currency = priceSheetDto.Currency
+	priceSheet.CpuCoreHourPrice = priceSheetDto.CpuCoreHourPrice
+	priceSheet.MemoryGibHourPrice = priceSheetDto.MemoryGibHourPrice
+	priceSheet.NodeTypePrices = string(nodeTypePrices)
+	return nil
+}
+
+// MergePriceSheets returns the price sheet of a cluster, the node type prices of the override take precedence over the
+// ones of the default sheet and the override is priced in the currency of the default sheet
+func MergePriceSheets(defaultSheet, override *bean.PriceSheetDto) *bean.PriceSheetDto {
+	if override == nil {
+		return defaultSheet
+	}
+	merged := *override
+	merged.Currency = defaultSheet.Currency
+	merged.NodeTypePrices = make(map[string]*bean.NodeTypePrice, len(defaultSheet.NodeTypePrices)+len(override.NodeTypePrices))
+	for instanceType, price := range defaultSheet.NodeTypePrices {
+		merged.NodeTypePrices[instanceType] = price
+	}
+	for instanceType, price := range override.NodeTypePrices {
+		merged.NodeTypePrices[instanceType] = price
+	}
+	return &merged
+}
+
+func BuildDailyAllocation(clusterId int, date time.Time, allocation *bean.Allocation, teamId int) *repository.CostAllocationDaily {
+	now := time.Now()
+	return &repository.CostAllocationDaily{
+		Date:           date,
+		ClusterId:      clusterId,
+		Namespace:      allocation.Namespace,
+		AppId:          allocation.AppId,
+		EnvId:          allocation.EnvId,
+		TeamId:         teamId,
+		Idle:           allocation.Idle,
+		CpuCoreHours:   allocation.CpuCoreHours,
+		MemoryGibHours: allocation.MemoryGibHours,
+		CpuCost:        allocation.CpuCost,
+		MemoryCost:     allocation.MemoryCost,
+		CreatedOn:      now,
+		UpdatedOn:      now,
+	}
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bean
+
+import (
+	"fmt"
+	"time"
+)
+
+type GroupBy string
+
+const (
+	GroupByApp         GroupBy = "app"
+	GroupByEnvironment GroupBy = "environment"
+	GroupByTeam        GroupBy = "team"
+	GroupByNamespace   GroupBy = "namespace"
+	GroupByCluster     GroupBy = "cluster"
+)
+
+func (g GroupBy) IsValid() bool {
+	switch g {
+	case GroupByApp, GroupByEnvironment, GroupByTeam, GroupByNamespace, GroupByCluster:
+		return true
+	}
+	return false
+}
+
+const (
+	DefaultCurrency = "USD"
+	DateLayout      = "2006-01-02"
+	// MaxReportDays bounds the range of a cost report
+	MaxReportDays = 366
+	// UnallocatedName is the name of the group of the cost of pods not deployed by devtron
+	UnallocatedName = "unallocated"
+	// InstanceTypeLabel is the well known label holding the instance type of a node
+	InstanceTypeLabel = "node.kubernetes.io/instance-type"
+	// AppIdLabel and EnvIdLabel are set on the pods of devtron deployment charts
+	AppIdLabel = "appId"
+	EnvIdLabel = "envId"
+)
+
+// NodeTypePrice is the price of a node instance type, overriding the per unit prices of the sheet for the nodes of the type
+type NodeTypePrice struct {
+	CpuCoreHourPrice   float64 `json:"cpuCoreHourPrice" validate:"gte=0"`
+	MemoryGibHourPrice float64 `json:"memoryGibHourPrice" validate:"gte=0"`
+}
+
+// PriceSheetDto is the default price sheet when ClusterId is 0, else the override of the cluster
+type PriceSheetDto struct {
+	ClusterId          int                       `json:"clusterId"`
+	ClusterName        string                    `json:"clusterName,omitempty"`
+	Currency           string                    `json:"currency"`
+	CpuCoreHourPrice   float64                   `json:"cpuCoreHourPrice" validate:"gte=0"`
+	MemoryGibHourPrice float64                   `json:"memoryGibHourPrice" validate:"gte=0"`
+	NodeTypePrices     map[string]*NodeTypePrice `json:"nodeTypePrices,omitempty"`
+	UpdatedOn          time.Time                 `json:"updatedOn,omitempty"`
+	UserId             int32                     `json:"-"`
+}
+
+// PriceOf returns the hourly cpu core and memory GiB prices of a node of the instance type
+func (p *PriceSheetDto) PriceOf(instanceType string) (float64, float64) {
+	if nodeTypePrice, ok := p.NodeTypePrices[instanceType]; ok && nodeTypePrice != nil {
+		return nodeTypePrice.CpuCoreHourPrice, nodeTypePrice.MemoryGibHourPrice
+	}
+	return p.CpuCoreHourPrice, p.MemoryGibHourPrice
+}
+
+// AllocationKey identifies the owner the cost of a pod is allocated to, app and env ids are 0 for pods not deployed by devtron
+type AllocationKey struct {
+	Namespace string
+	AppId     int
+	EnvId     int
+	Idle      bool
+}
+
+type Allocation struct {
+	AllocationKey
+	CpuCoreHours   float64
+	MemoryGibHours float64
+	CpuCost        float64
+	MemoryCost     float64
+}
+
+func (a *Allocation) Add(cpuCoreHours, memoryGibHours, cpuCost, memoryCost float64) {
+	a.CpuCoreHours += cpuCoreHours
+	a.MemoryGibHours += memoryGibHours
+	a.CpuCost += cpuCost
+	a.MemoryCost += memoryCost
+}
+
+func (a *Allocation) TotalCost() float64 {
+	return a.CpuCost + a.MemoryCost
+}
+
+type ReportRequest struct {
+	From      time.Time
+	To        time.Time
+	GroupBy   GroupBy
+	ClusterId int
+}
+
+func (r *ReportRequest) Validate() error {
+	if r.To.Before(r.From) {
+		return fmt.Errorf("to %s is before from %s", r.To.Format(DateLayout), r.From.Format(DateLayout))
+	}
+	if r.To.Sub(r.From) > MaxReportDays*24*time.Hour {
+		return fmt.Errorf("report range can not exceed %d days", MaxReportDays)
+	}
+	if len(r.GroupBy) > 0 && !r.GroupBy.IsValid() {
+		return fmt.Errorf("invalid groupBy %s", r.GroupBy)
+	}
+	return nil
+}
+
+type CostItem struct {
+	Id             int     `json:"id,omitempty"`
+	Name           string  `json:"name"`
+	CpuCoreHours   float64 `json:"cpuCoreHours"`
+	MemoryGibHours float64 `json:"memoryGibHours"`
+	CpuCost        float64 `json:"cpuCost"`
+	MemoryCost     float64 `json:"memoryCost"`
+	TotalCost      float64 `json:"totalCost"`
+}
+
+type AllocationReport struct {
+	From          string      `json:"from"`
+	To            string      `json:"to"`
+	GroupBy       GroupBy     `json:"groupBy"`
+	Currency      string      `json:"currency"`
+	Items         []*CostItem `json:"items"`
+	AllocatedCost float64     `json:"allocatedCost"`
+	IdleCost      float64     `json:"idleCost"`
+	TotalCost     float64     `json:"totalCost"`
+}
+
+type ClusterIdleCost struct {
+	ClusterId      int     `json:"clusterId"`
+	ClusterName    string  `json:"clusterName"`
+	AllocatedCost  float64 `json:"allocatedCost"`
+	IdleCost       float64 `json:"idleCost"`
+	TotalCost      float64 `json:"totalCost"`
+	IdlePercentage float64 `json:"idlePercentage"`
+}
+
+type IdleCostReport struct {
+	From     string             `json:"from"`
+	To       string             `json:"to"`
+	Currency string             `json:"currency"`
+	Clusters []*ClusterIdleCost `json:"clusters"`
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package helper
+
+import (
+	"github.com/devtron-labs/devtron/pkg/k8s/costAllocation/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/costAllocation/repository"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
+	"sort"
+	"strconv"
+)
+
+const bytesPerGib = 1024 * 1024 * 1024
+
+// PodUsageKey is the key of the usage of a pod in the usage map passed to AllocateCost
+func PodUsageKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+type nodeCost struct {
+	cpuPrice       float64
+	memoryPrice    float64
+	cpuCores       float64
+	memoryGib      float64
+	allocatedCpu   float64
+	allocatedMemGb float64
+}
+
+// AllocateCost allocates the cost of the nodes over hours to the pods running on them. A pod is charged for the higher of
+// its requests and its usage, usage being nil when the metrics server is not available. The allocatable capacity of the
+// nodes not charged to any pod is returned as the idle allocation.
+func AllocateCost(nodes []corev1.Node, pods []corev1.Pod, usage map[string]corev1.ResourceList, priceSheet *bean.PriceSheetDto, hours float64) []*bean.Allocation {
+	nodeCosts := make(map[string]*nodeCost, len(nodes))
+	for _, node := range nodes {
+		cpuPrice, memoryPrice := priceSheet.PriceOf(node.Labels[bean.InstanceTypeLabel])
+		nodeCosts[node.Name] = &nodeCost{
+			cpuPrice:    cpuPrice,
+			memoryPrice: memoryPrice,
+			cpuCores:    cpuCores(node.Status.Allocatable[corev1.ResourceCPU]),
+			memoryGib:   memoryGib(node.Status.Allocatable[corev1.ResourceMemory]),
+		}
+	}
+	allocations := make(map[bean.AllocationKey]*bean.Allocation)
+	var keys []bean.AllocationKey
+	addAllocation := func(key bean.AllocationKey, cpu, memory, cpuPrice, memoryPrice float64) {
+		allocation, ok := allocations[key]
+		if !ok {
+			allocation = &bean.Allocation{AllocationKey: key}
+			allocations[key] = allocation
+			keys = append(keys, key)
+		}
+		allocation.Add(cpu*hours, memory*hours, cpu*cpuPrice*hours, memory*memoryPrice*hours)
+	}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != corev1.PodRunning || len(pod.Spec.NodeName) == 0 {
+			continue
+		}
+		requests, _ := resourcehelper.PodRequestsAndLimits(pod)
+		cpu := cpuCores(requests[corev1.ResourceCPU])
+		memory := memoryGib(requests[corev1.ResourceMemory])
+		if podUsage, ok := usage[PodUsageKey(pod.Namespace, pod.Name)]; ok {
+			cpu = max(cpu, cpuCores(podUsage[corev1.ResourceCPU]))
+			memory = max(memory, memoryGib(podUsage[corev1.ResourceMemory]))
+		}
+		cpuPrice, memoryPrice := priceSheet.CpuCoreHourPrice, priceSheet.MemoryGibHourPrice
+		if node, ok := nodeCosts[pod.Spec.NodeName]; ok {
+			cpuPrice, memoryPrice = node.cpuPrice, node.memoryPrice
+			node.allocatedCpu += cpu
+			node.allocatedMemGb += memory
+		}
+		addAllocation(GetAllocationKey(pod), cpu, memory, cpuPrice, memoryPrice)
+	}
+	for _, node := range nodes {
+		cost := nodeCosts[node.Name]
+		idleCpu := max(cost.cpuCores-cost.allocatedCpu, 0)
+		idleMemory := max(cost.memoryGib-cost.allocatedMemGb, 0)
+		if idleCpu == 0 && idleMemory == 0 {
+			continue
+		}
+		addAllocation(bean.AllocationKey{Idle: true}, idleCpu, idleMemory, cost.cpuPrice, cost.memoryPrice)
+	}
+	result := make([]*bean.Allocation, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, allocations[key])
+	}
+	return result
+}
+
+// GetAllocationKey returns the owner of the pod, the devtron app and environment from the labels of the devtron charts
+func GetAllocationKey(pod *corev1.Pod) bean.AllocationKey {
+	key := bean.AllocationKey{Namespace: pod.Namespace}
+	appId, appErr := strconv.Atoi(pod.Labels[bean.AppIdLabel])
+	envId, envErr := strconv.Atoi(pod.Labels[bean.EnvIdLabel])
+	if appErr == nil && envErr == nil && appId > 0 && envId > 0 {
+		key.AppId = appId
+		key.EnvId = envId
+	}
+	return key
+}
+
+func cpuCores(quantity resource.Quantity) float64 {
+	return float64(quantity.MilliValue()) / 1000
+}
+
+func memoryGib(quantity resource.Quantity) float64 {
+	return float64(quantity.Value()) / bytesPerGib
+}
+
+// GroupAllocations groups the summed daily allocations by the owner of groupBy, sorted by cost. Items are keyed by the id
+// of the owner with an empty name to be resolved by the caller, but for namespaces which are named. Idle rows are not
+// grouped and returned as the idle cost.
+func GroupAllocations(allocations []*repository.CostAllocationDaily, groupBy bean.GroupBy) ([]*bean.CostItem, float64) {
+	type groupKey struct {
+		id   int
+		name string
+	}
+	items := make(map[groupKey]*bean.CostItem)
+	var idleCost float64
+	for _, allocation := range allocations {
+		if allocation.Idle {
+			idleCost += allocation.CpuCost + allocation.MemoryCost
+			continue
+		}
+		var key groupKey
+		switch groupBy {
+		case bean.GroupByApp:
+			key.id = allocation.AppId
+		case bean.GroupByEnvironment:
+			key.id = allocation.EnvId
+		case bean.GroupByTeam:
+			key.id = allocation.TeamId
+		case bean.GroupByNamespace:
+			key.name = allocation.Namespace
+		case bean.GroupByCluster:
+			key.id = allocation.ClusterId
+		}
+		item, ok := items[key]
+		if !ok {
+			item = &bean.CostItem{Id: key.id, Name: key.name}
+			items[key] = item
+		}
+		item.CpuCoreHours += allocation.CpuCoreHours
+		item.MemoryGibHours += allocation.MemoryGibHours
+		item.CpuCost += allocation.CpuCost
+		item.MemoryCost += allocation.MemoryCost
+		item.TotalCost += allocation.CpuCost + allocation.MemoryCost
+	}
+	result := make([]*bean.CostItem, 0, len(items))
+	for _, item := range items {
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalCost != result[j].TotalCost {
+			return result[i].TotalCost > result[j].TotalCost
+		}
+		return result[i].Id < result[j].Id || (result[i].Id == result[j].Id && result[i].Name < result[j].Name)
+	})
+	return result, idleCost
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package helper
+
+import (
+	"github.com/devtron-labs/devtron/pkg/k8s/costAllocation/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/costAllocation/repository"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func newNode(name, instanceType, cpu, memory string) corev1.Node {
+	node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{bean.InstanceTypeLabel: instanceType}}}
+	node.Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}
+	return node
+}
+
+func newPod(namespace, name, nodeName string, labels map[string]string, cpu, memory string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			}}}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestAllocateCost(t *testing.T) {
+	priceSheet := &bean.PriceSheetDto{
+		CpuCoreHourPrice:   1,
+		MemoryGibHourPrice: 0.1,
+		NodeTypePrices:     map[string]*bean.NodeTypePrice{"large": {CpuCoreHourPrice: 2, MemoryGibHourPrice: 0.2}},
+	}
+	nodes := []corev1.Node{newNode("n1", "small", "4", "16Gi"), newNode("n2", "large", "2", "8Gi")}
+	devtronLabels := map[string]string{bean.AppIdLabel: "1", bean.EnvIdLabel: "2"}
+	pending := newPod("ns1", "pending", "", nil, "1", "1Gi")
+	pending.Status.Phase = corev1.PodPending
+	pods := []corev1.Pod{
+		newPod("ns1", "p1", "n1", devtronLabels, "1", "2Gi"),
+		newPod("ns1", "p2", "n2", devtronLabels, "500m", "1Gi"),
+		newPod("ns2", "p3", "n1", map[string]string{bean.AppIdLabel: "invalid"}, "1", "4Gi"),
+		pending,
+	}
+	usage := map[string]corev1.ResourceList{
+		// usage above requests is charged, below is not
+		PodUsageKey("ns1", "p2"): {corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+	}
+	allocations := AllocateCost(nodes, pods, usage, priceSheet, 2)
+	assert.Len(t, allocations, 3)
+
+	devtron := allocations[0]
+	assert.Equal(t, bean.AllocationKey{Namespace: "ns1", AppId: 1, EnvId: 2}, devtron.AllocationKey)
+	assert.InDelta(t, 4.0, devtron.CpuCoreHours, 1e-9)   // (1 + 1) * 2h
+	assert.InDelta(t, 6.0, devtron.MemoryGibHours, 1e-9) // (2 + 1) * 2h
+	assert.InDelta(t, 6.0, devtron.CpuCost, 1e-9)        // 1*1*2 + 1*2*2
+	assert.InDelta(t, 0.8, devtron.MemoryCost, 1e-9)     // 2*0.1*2 + 1*0.2*2
+
+	other := allocations[1]
+	assert.Equal(t, bean.AllocationKey{Namespace: "ns2"}, other.AllocationKey)
+	assert.InDelta(t, 2.0, other.CpuCost, 1e-9)
+
+	idle := allocations[2]
+	assert.True(t, idle.Idle)
+	// n1 has 2 cores and 10Gi left, n2 1 core and 7Gi
+	assert.InDelta(t, 6.0, idle.CpuCoreHours, 1e-9)
+	assert.InDelta(t, 34.0, idle.MemoryGibHours, 1e-9)
+	assert.InDelta(t, 4.0+4.0, idle.CpuCost, 1e-9)
+	assert.InDelta(t, 2.0+2.8, idle.MemoryCost, 1e-9)
+}
+
+func TestAllocateCostWithoutNodes(t *testing.T) {
+	priceSheet := &bean.PriceSheetDto{CpuCoreHourPrice: 1, MemoryGibHourPrice: 1}
+	pods := []corev1.Pod{newPod("ns1", "p1", "unknown", nil, "1", "1Gi")}
+	allocations := AllocateCost(nil, pods, nil, priceSheet, 1)
+	assert.Len(t, allocations, 1)
+	assert.InDelta(t, 2.0, allocations[0].TotalCost(), 1e-9)
+}
+
+func TestGroupAllocations(t *testing.T) {
+	allocations := []*repository.CostAllocationDaily{
+		{ClusterId: 1, Namespace: "ns1", AppId: 1, EnvId: 1, TeamId: 1, CpuCost: 1, MemoryCost: 1},
+		{ClusterId: 1, Namespace: "ns2", AppId: 1, EnvId: 2, TeamId: 1, CpuCost: 2, MemoryCost: 1},
+		{ClusterId: 2, Namespace: "ns1", AppId: 2, EnvId: 3, TeamId: 2, CpuCost: 1},
+		{ClusterId: 2, Namespace: "kube-system", CpuCost: 4},
+		{ClusterId: 1, Idle: true, CpuCost: 5, MemoryCost: 5},
+	}
+	items, idleCost := GroupAllocations(allocations, bean.GroupByApp)
+	assert.InDelta(t, 10.0, idleCost, 1e-9)
+	assert.Len(t, items, 3)
+	assert.Equal(t, 1, items[0].Id)
+	assert.InDelta(t, 5.0, items[0].TotalCost, 1e-9)
+	assert.Equal(t, 0, items[1].Id)
+	assert.Equal(t, 2, items[2].Id)
+
+	items, _ = GroupAllocations(allocations, bean.GroupByNamespace)
+	assert.Len(t, items, 3)
+	assert.Equal(t, "kube-system", items[0].Name)
+	assert.InDelta(t, 4.0, items[0].TotalCost, 1e-9)
+
+	items, _ = GroupAllocations(allocations, bean.GroupByCluster)
+	assert.Len(t, items, 2)
+	// equal costs are ordered by id
+	assert.Equal(t, 1, items[0].Id)
+	assert.InDelta(t, 5.0, items[0].TotalCost, 1e-9)
+	assert.Equal(t, 2, items[1].Id)
+}
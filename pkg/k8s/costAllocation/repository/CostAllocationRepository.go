@@ -0,0 +1,174 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CostPriceSheet struct {
+	tableName          struct{} `sql:"cost_price_sheet" pg:",discard_unknown_columns"`
+	Id                 int      `sql:"id,pk"`
+	ClusterId          int      `sql:"cluster_id"` // null for the default price sheet
+	Currency           string   `sql:"currency,notnull"`
+	CpuCoreHourPrice   float64  `sql:"cpu_core_hour_price,notnull"`
+	MemoryGibHourPrice float64  `sql:"memory_gib_hour_price,notnull"`
+	NodeTypePrices     string   `sql:"node_type_prices"`
+	Active             bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type CostAllocationSample struct {
+	tableName    struct{}  `sql:"cost_allocation_sample" pg:",discard_unknown_columns"`
+	Id           int       `sql:"id,pk"`
+	ClusterId    int       `sql:"cluster_id,notnull"`
+	SampledAt    time.Time `sql:"sampled_at,notnull"`
+	IntervalMins int       `sql:"interval_mins,notnull"`
+	CreatedOn    time.Time `sql:"created_on,notnull"`
+}
+
+type CostAllocationDaily struct {
+	tableName      struct{}  `sql:"cost_allocation_daily" pg:",discard_unknown_columns"`
+	Id             int       `sql:"id,pk"`
+	Date           time.Time `sql:"date,notnull"`
+	ClusterId      int       `sql:"cluster_id,notnull"`
+	Namespace      string    `sql:"namespace,notnull"`
+	AppId          int       `sql:"app_id,notnull"`
+	EnvId          int       `sql:"env_id,notnull"`
+	TeamId         int       `sql:"team_id,notnull"`
+	Idle           bool      `sql:"idle,notnull"`
+	CpuCoreHours   float64   `sql:"cpu_core_hours,notnull"`
+	MemoryGibHours float64   `sql:"memory_gib_hours,notnull"`
+	CpuCost        float64   `sql:"cpu_cost,notnull"`
+	MemoryCost     float64   `sql:"memory_cost,notnull"`
+	CreatedOn      time.Time `sql:"created_on,notnull"`
+	UpdatedOn      time.Time `sql:"updated_on,notnull"`
+}
+
+type CostAllocationRepository interface {
+	sql.TransactionWrapper
+	FindActivePriceSheets() ([]*CostPriceSheet, error)
+	// FindActivePriceSheetByClusterId returns the default price sheet for clusterId 0
+	FindActivePriceSheetByClusterId(clusterId int) (*CostPriceSheet, error)
+	SavePriceSheet(priceSheet *CostPriceSheet) error
+	UpdatePriceSheet(priceSheet *CostPriceSheet) error
+	// SaveSample records the sample of the cluster, false if the interval is already sampled by another orchestrator
+	SaveSample(tx *pg.Tx, sample *CostAllocationSample) (bool, error)
+	// AddDailyAllocations adds the allocations to the daily rollups, creating the missing rows
+	AddDailyAllocations(tx *pg.Tx, allocations []*CostAllocationDaily) error
+	// FindAllocationsSummedByOwner sums the daily rollups in the date range per cluster, namespace, app, env and idle
+	FindAllocationsSummedByOwner(from, to time.Time, clusterId int) ([]*CostAllocationDaily, error)
+}
+
+type CostAllocationRepositoryImpl struct {
+	*sql.TransactionUtilImpl
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCostAllocationRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, TransactionUtilImpl *sql.TransactionUtilImpl) *CostAllocationRepositoryImpl {
+	return &CostAllocationRepositoryImpl{
+		TransactionUtilImpl: TransactionUtilImpl,
+		dbConnection:        dbConnection,
+		logger:              logger,
+	}
+}
+
+func (impl *CostAllocationRepositoryImpl) FindActivePriceSheets() ([]*CostPriceSheet, error) {
+	var priceSheets []*CostPriceSheet
+	err := impl.dbConnection.Model(&priceSheets).
+		Where("active = ?", true).
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting cost price sheets", "err", err)
+		return nil, err
+	}
+	return priceSheets, nil
+}
+
+func (impl *CostAllocationRepositoryImpl) FindActivePriceSheetByClusterId(clusterId int) (*CostPriceSheet, error) {
+	priceSheet := &CostPriceSheet{}
+	query := impl.dbConnection.Model(priceSheet).
+		Where("active = ?", true)
+	if clusterId > 0 {
+		query = query.Where("cluster_id = ?", clusterId)
+	} else {
+		query = query.Where("cluster_id IS NULL")
+	}
+	err := query.Select()
+	return priceSheet, err
+}
+
+func (impl *CostAllocationRepositoryImpl) SavePriceSheet(priceSheet *CostPriceSheet) error {
+	return impl.dbConnection.Insert(priceSheet)
+}
+
+func (impl *CostAllocationRepositoryImpl) UpdatePriceSheet(priceSheet *CostPriceSheet) error {
+	return impl.dbConnection.Update(priceSheet)
+}
+
+func (impl *CostAllocationRepositoryImpl) SaveSample(tx *pg.Tx, sample *CostAllocationSample) (bool, error) {
+	result, err := tx.Model(sample).
+		OnConflict("(cluster_id, sampled_at) DO NOTHING").
+		Insert()
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (impl *CostAllocationRepositoryImpl) AddDailyAllocations(tx *pg.Tx, allocations []*CostAllocationDaily) error {
+	if len(allocations) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&allocations).
+		OnConflict("(date, cluster_id, namespace, app_id, env_id, idle) DO UPDATE").
+		Set("team_id = EXCLUDED.team_id").
+		Set("cpu_core_hours = ?TableAlias.cpu_core_hours + EXCLUDED.cpu_core_hours").
+		Set("memory_gib_hours = ?TableAlias.memory_gib_hours + EXCLUDED.memory_gib_hours").
+		Set("cpu_cost = ?TableAlias.cpu_cost + EXCLUDED.cpu_cost").
+		Set("memory_cost = ?TableAlias.memory_cost + EXCLUDED.memory_cost").
+		Set("updated_on = EXCLUDED.updated_on").
+		Insert()
+	return err
+}
+
+func (impl *CostAllocationRepositoryImpl) FindAllocationsSummedByOwner(from, to time.Time, clusterId int) ([]*CostAllocationDaily, error) {
+	var allocations []*CostAllocationDaily
+	query := impl.dbConnection.Model(&allocations).
+		Column("cluster_id", "namespace", "app_id", "env_id", "idle").
+		ColumnExpr("MAX(team_id) AS team_id").
+		ColumnExpr("SUM(cpu_core_hours) AS cpu_core_hours").
+		ColumnExpr("SUM(memory_gib_hours) AS memory_gib_hours").
+		ColumnExpr("SUM(cpu_cost) AS cpu_cost").
+		ColumnExpr("SUM(memory_cost) AS memory_cost").
+		Where("date >= ?", from).
+		Where("date <= ?", to)
+	if clusterId > 0 {
+		query = query.Where("cluster_id = ?", clusterId)
+	}
+	err := query.
+		Group("cluster_id", "namespace", "app_id", "env_id", "idle").
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting cost allocations", "from", from, "to", to, "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	return allocations, nil
+}
@@ -0,0 +1,606 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package nodeMaintenance
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity"
+	capacityBean "github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance/adapter"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cronUtil "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type NodeMaintenanceConfig struct {
+	HeartbeatIntervalSecs   int `env:"NODE_MAINTENANCE_HEARTBEAT_INTERVAL_SECS" envDefault:"15" description:"Interval at which the orchestrator running a node maintenance workflow records that it is alive"`
+	ResumeCheckIntervalMins int `env:"NODE_MAINTENANCE_RESUME_CHECK_INTERVAL_MINS" envDefault:"1" description:"Interval at which running node maintenance workflows of a restarted or lost orchestrator are taken over"`
+}
+
+func GetNodeMaintenanceConfig() (*NodeMaintenanceConfig, error) {
+	cfg := &NodeMaintenanceConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+const (
+	podTerminationPollInterval = 5 * time.Second
+	replacementPollInterval    = 10 * time.Second
+	// staleHeartbeatFactor is the number of missed heartbeats after which a running workflow is taken over
+	staleHeartbeatFactor = 4
+)
+
+type NodeMaintenanceService interface {
+	// CreateWorkflow selects the nodes and starts draining them in rolling batches
+	CreateWorkflow(ctx context.Context, request *bean.CreateWorkflowRequest) (*bean.WorkflowDto, error)
+	GetWorkflow(id int) (*bean.WorkflowDto, error)
+	GetWorkflowsByClusterId(clusterId int) ([]*bean.WorkflowDto, error)
+	// PauseWorkflow stops the workflow, nodes being drained are drained again on resume
+	PauseWorkflow(id int, userId int32) error
+	// ResumeWorkflow continues a paused workflow or retries the failed nodes of a failed workflow
+	ResumeWorkflow(id int, userId int32) error
+	// AbortWorkflow stops the workflow for good and skips the remaining nodes, drained nodes stay cordoned
+	AbortWorkflow(id int, userId int32) error
+	// ResumeStaleWorkflows takes over the running workflows whose orchestrator stopped heart beating
+	ResumeStaleWorkflows()
+}
+
+type NodeMaintenanceServiceImpl struct {
+	logger             *zap.SugaredLogger
+	k8sCommonService   k8s.K8sCommonService
+	k8sCapacityService capacity.K8sCapacityService
+	repository         repository.NodeMaintenanceRepository
+	config             *NodeMaintenanceConfig
+	// runners are the cancel funcs of the workflows run by this orchestrator
+	runners map[int]context.CancelFunc
+	mux     sync.Mutex
+}
+
+func NewNodeMaintenanceServiceImpl(logger *zap.SugaredLogger,
+	k8sCommonService k8s.K8sCommonService,
+	k8sCapacityService capacity.K8sCapacityService,
+	repository repository.NodeMaintenanceRepository,
+	config *NodeMaintenanceConfig,
+	cronLogger *cronUtil.CronLoggerImpl) (*NodeMaintenanceServiceImpl, error) {
+	impl := &NodeMaintenanceServiceImpl{
+		logger:             logger,
+		k8sCommonService:   k8sCommonService,
+		k8sCapacityService: k8sCapacityService,
+		repository:         repository,
+		config:             config,
+		runners:            make(map[int]context.CancelFunc),
+	}
+	if config.ResumeCheckIntervalMins > 0 {
+		resumeCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+		_, err := resumeCron.AddFunc(fmt.Sprintf("@every %dm", config.ResumeCheckIntervalMins), impl.ResumeStaleWorkflows)
+		if err != nil {
+			logger.Errorw("error in adding node maintenance resume cron", "err", err)
+			return nil, err
+		}
+		resumeCron.Start()
+	}
+	return impl, nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) CreateWorkflow(ctx context.Context, request *bean.CreateWorkflowRequest) (*bean.WorkflowDto, error) {
+	if err := request.Selector.Validate(); err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	request.Options.SetDefaults()
+	inProgress, err := impl.repository.FindInProgressWorkflowByClusterId(request.ClusterId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting in progress node maintenance workflow", "clusterId", request.ClusterId, "err", err)
+		return nil, err
+	} else if inProgress.Id > 0 {
+		return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("node maintenance workflow %d is already in progress for the cluster", inProgress.Id), "node maintenance workflow in progress")
+	}
+	_, _, clientSet, err := impl.k8sCommonService.GetK8sConfigAndClientsByClusterId(ctx, request.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s client by cluster id", "clusterId", request.ClusterId, "err", err)
+		return nil, err
+	}
+	nodes, err := impl.selectNodes(ctx, clientSet, request.Selector)
+	if err != nil {
+		return nil, err
+	}
+	workflow, err := adapter.BuildNodeMaintenanceWorkflow(request)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	workflow.StartedOn = &now
+	workflow.AuditLog = sql.NewDefaultAuditLog(request.UserId)
+	tx, err := impl.repository.StartTx()
+	if err != nil {
+		return nil, err
+	}
+	defer impl.repository.RollbackTx(tx)
+	if err = impl.repository.SaveWorkflow(tx, workflow); err != nil {
+		impl.logger.Errorw("error in saving node maintenance workflow", "clusterId", request.ClusterId, "err", err)
+		return nil, err
+	}
+	maintenanceNodes := make([]*repository.NodeMaintenanceNode, 0, len(nodes))
+	for i := range nodes {
+		maintenanceNodes = append(maintenanceNodes, &repository.NodeMaintenanceNode{
+			WorkflowId: workflow.Id,
+			NodeName:   nodes[i].Name,
+			NodeGroup:  capacity.GetNodeGroup(&nodes[i]),
+			Status:     bean.NodeStatusPending,
+			AuditLog:   sql.NewDefaultAuditLog(request.UserId),
+		})
+	}
+	if err = impl.repository.SaveNodes(tx, maintenanceNodes); err != nil {
+		impl.logger.Errorw("error in saving node maintenance nodes", "workflowId", workflow.Id, "err", err)
+		return nil, err
+	}
+	if err = impl.repository.CommitTx(tx); err != nil {
+		return nil, err
+	}
+	impl.logger.Infow("created node maintenance workflow", "workflowId", workflow.Id, "clusterId", request.ClusterId, "nodes", len(maintenanceNodes))
+	impl.startRunner(workflow.Id)
+	return adapter.GetWorkflowDto(workflow, maintenanceNodes)
+}
+
+// selectNodes returns the nodes of the cluster matching the selector, every named node must exist
+func (impl *NodeMaintenanceServiceImpl) selectNodes(ctx context.Context, clientSet *kubernetes.Clientset, selector *bean.NodeSelector) ([]corev1.Node, error) {
+	nodeList, err := clientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		impl.logger.Errorw("error in listing nodes", "err", err)
+		return nil, err
+	}
+	nodes, missingNodeNames, err := filterNodes(nodeList.Items, selector)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	if len(missingNodeNames) > 0 {
+		return nil, util.NewApiError(http.StatusNotFound, fmt.Sprintf("nodes %v not found", missingNodeNames), "nodes not found")
+	}
+	if len(nodes) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "no nodes match the selector", "no nodes match the selector")
+	}
+	return nodes, nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) GetWorkflow(id int) (*bean.WorkflowDto, error) {
+	workflow, err := impl.repository.FindWorkflowById(id)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "node maintenance workflow not found", "node maintenance workflow not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in getting node maintenance workflow", "id", id, "err", err)
+		return nil, err
+	}
+	nodes, err := impl.repository.FindNodesByWorkflowId(id)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.GetWorkflowDto(workflow, nodes)
+}
+
+func (impl *NodeMaintenanceServiceImpl) GetWorkflowsByClusterId(clusterId int) ([]*bean.WorkflowDto, error) {
+	workflows, err := impl.repository.FindWorkflowsByClusterId(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	workflowDtos := make([]*bean.WorkflowDto, 0, len(workflows))
+	for _, workflow := range workflows {
+		workflowDto, err := adapter.GetWorkflowDto(workflow, nil)
+		if err != nil {
+			impl.logger.Errorw("error in parsing node maintenance workflow", "id", workflow.Id, "err", err)
+			return nil, err
+		}
+		workflowDtos = append(workflowDtos, workflowDto)
+	}
+	return workflowDtos, nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) PauseWorkflow(id int, userId int32) error {
+	updated, err := impl.repository.UpdateWorkflowStatus(id, []bean.WorkflowStatus{bean.WorkflowStatusRunning}, bean.WorkflowStatusPaused, "", userId)
+	if err != nil {
+		impl.logger.Errorw("error in pausing node maintenance workflow", "id", id, "err", err)
+		return err
+	} else if !updated {
+		return util.NewApiError(http.StatusBadRequest, "only a running workflow can be paused", "workflow not running")
+	}
+	impl.stopRunner(id)
+	return nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) ResumeWorkflow(id int, userId int32) error {
+	workflow, err := impl.repository.FindWorkflowById(id)
+	if err == pg.ErrNoRows {
+		return util.NewApiError(http.StatusNotFound, "node maintenance workflow not found", "node maintenance workflow not found")
+	} else if err != nil {
+		return err
+	}
+	if workflow.Status == bean.WorkflowStatusFailed {
+		inProgress, err := impl.repository.FindInProgressWorkflowByClusterId(workflow.ClusterId)
+		if err != nil && err != pg.ErrNoRows {
+			return err
+		} else if inProgress.Id > 0 {
+			return util.NewApiError(http.StatusConflict, fmt.Sprintf("node maintenance workflow %d is already in progress for the cluster", inProgress.Id), "node maintenance workflow in progress")
+		}
+		if err = impl.repository.ResetFailedNodes(id, userId); err != nil {
+			impl.logger.Errorw("error in resetting failed nodes", "id", id, "err", err)
+			return err
+		}
+	}
+	updated, err := impl.repository.UpdateWorkflowStatus(id, []bean.WorkflowStatus{bean.WorkflowStatusPaused, bean.WorkflowStatusFailed}, bean.WorkflowStatusRunning, "", userId)
+	if err != nil {
+		impl.logger.Errorw("error in resuming node maintenance workflow", "id", id, "err", err)
+		return err
+	} else if !updated {
+		return util.NewApiError(http.StatusBadRequest, "only a paused or failed workflow can be resumed", "workflow not paused or failed")
+	}
+	impl.startRunner(id)
+	return nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) AbortWorkflow(id int, userId int32) error {
+	inProgress := []bean.WorkflowStatus{bean.WorkflowStatusRunning, bean.WorkflowStatusPaused}
+	updated, err := impl.repository.UpdateWorkflowStatus(id, inProgress, bean.WorkflowStatusAborted, "", userId)
+	if err != nil {
+		impl.logger.Errorw("error in aborting node maintenance workflow", "id", id, "err", err)
+		return err
+	} else if !updated {
+		return util.NewApiError(http.StatusBadRequest, "only a running or paused workflow can be aborted", "workflow not in progress")
+	}
+	impl.stopRunner(id)
+	if err = impl.repository.SkipPendingNodes(id, "workflow aborted", userId); err != nil {
+		impl.logger.Errorw("error in skipping pending nodes of aborted workflow", "id", id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) ResumeStaleWorkflows() {
+	ids, err := impl.repository.FindStaleRunningWorkflowIds(impl.getStaleBefore())
+	if err != nil {
+		return
+	}
+	for _, id := range ids {
+		impl.logger.Infow("taking over node maintenance workflow", "id", id)
+		impl.startRunner(id)
+	}
+}
+
+func (impl *NodeMaintenanceServiceImpl) getStaleBefore() time.Time {
+	return time.Now().Add(-staleHeartbeatFactor * time.Duration(impl.config.HeartbeatIntervalSecs) * time.Second)
+}
+
+// startRunner runs the workflow in this orchestrator if no other orchestrator is running it
+func (impl *NodeMaintenanceServiceImpl) startRunner(workflowId int) {
+	impl.mux.Lock()
+	defer impl.mux.Unlock()
+	if _, ok := impl.runners[workflowId]; ok {
+		return
+	}
+	// a runner id per claim, a runner that lost the workflow to a takeover cannot update it anymore
+	runnerId := uuid.New().String()
+	claimed, err := impl.repository.ClaimWorkflow(workflowId, runnerId, impl.getStaleBefore())
+	if err != nil {
+		impl.logger.Errorw("error in claiming node maintenance workflow", "id", workflowId, "err", err)
+		return
+	} else if !claimed {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	impl.runners[workflowId] = cancel
+	go func() {
+		defer func() {
+			cancel()
+			impl.mux.Lock()
+			delete(impl.runners, workflowId)
+			impl.mux.Unlock()
+		}()
+		go impl.heartbeat(ctx, cancel, workflowId, runnerId)
+		impl.runWorkflow(ctx, workflowId, runnerId)
+	}()
+}
+
+func (impl *NodeMaintenanceServiceImpl) stopRunner(workflowId int) {
+	impl.mux.Lock()
+	defer impl.mux.Unlock()
+	if cancel, ok := impl.runners[workflowId]; ok {
+		cancel()
+	}
+}
+
+// heartbeat keeps the claim on the workflow and cancels the runner once the workflow is paused, aborted or taken over elsewhere
+func (impl *NodeMaintenanceServiceImpl) heartbeat(ctx context.Context, cancel context.CancelFunc, workflowId int, runnerId string) {
+	ticker := time.NewTicker(time.Duration(impl.config.HeartbeatIntervalSecs) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			running, err := impl.repository.HeartbeatWorkflow(workflowId, runnerId)
+			if err != nil {
+				impl.logger.Errorw("error in heart beating node maintenance workflow", "id", workflowId, "err", err)
+				continue
+			}
+			if !running {
+				impl.logger.Infow("node maintenance workflow is not run by this runner anymore, stopping", "id", workflowId, "runnerId", runnerId)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func (impl *NodeMaintenanceServiceImpl) runWorkflow(ctx context.Context, workflowId int, runnerId string) {
+	for ctx.Err() == nil {
+		workflow, err := impl.repository.FindWorkflowById(workflowId)
+		if err != nil {
+			impl.logger.Errorw("error in getting node maintenance workflow", "id", workflowId, "err", err)
+			return
+		}
+		if workflow.Status != bean.WorkflowStatusRunning || workflow.RunnerId != runnerId {
+			return
+		}
+		options, err := adapter.GetMaintenanceOptions(workflow)
+		if err != nil {
+			impl.finishWorkflow(workflowId, runnerId, bean.WorkflowStatusFailed, fmt.Sprintf("invalid options: %s", err.Error()))
+			return
+		}
+		nodes, err := impl.repository.FindNodesByWorkflowId(workflowId)
+		if err != nil {
+			return
+		}
+		batch := getNextBatch(nodes, options.MaxUnavailable)
+		if len(batch) == 0 {
+			impl.finishWorkflow(workflowId, runnerId, bean.WorkflowStatusSucceeded, "")
+			return
+		}
+		if err = impl.runBatch(ctx, workflow, runnerId, options, batch); err != nil {
+			if ctx.Err() != nil {
+				// paused or aborted, the batch is picked up again on resume
+				return
+			}
+			impl.finishWorkflow(workflowId, runnerId, bean.WorkflowStatusFailed, err.Error())
+			return
+		}
+	}
+}
+
+func (impl *NodeMaintenanceServiceImpl) finishWorkflow(workflowId int, runnerId string, status bean.WorkflowStatus, message string) {
+	finished, err := impl.repository.FinishWorkflow(workflowId, runnerId, status, message)
+	if err != nil {
+		impl.logger.Errorw("error in finishing node maintenance workflow", "id", workflowId, "status", status, "err", err)
+		return
+	} else if !finished {
+		impl.logger.Infow("node maintenance workflow is not run by this runner anymore, not finishing it", "id", workflowId, "runnerId", runnerId, "status", status)
+		return
+	}
+	impl.logger.Infow("node maintenance workflow finished", "id", workflowId, "status", status, "message", message)
+}
+
+// runBatch drains the nodes of the batch in parallel and waits for their replacements if asked to
+func (impl *NodeMaintenanceServiceImpl) runBatch(ctx context.Context, workflow *repository.NodeMaintenanceWorkflow, runnerId string, options bean.MaintenanceOptions, batch []*repository.NodeMaintenanceNode) error {
+	_, _, clientSet, err := impl.k8sCommonService.GetK8sConfigAndClientsByClusterId(ctx, workflow.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s client by cluster id", "clusterId", workflow.ClusterId, "err", err)
+		return err
+	}
+	batchStartedOn := time.Now()
+	for _, node := range batch {
+		if node.StartedOn != nil && node.StartedOn.Before(batchStartedOn) {
+			// a resumed batch counts the replacement nodes created since it first started
+			batchStartedOn = *node.StartedOn
+		}
+	}
+	drainErrors := make([]error, len(batch))
+	wg := sync.WaitGroup{}
+	for i, node := range batch {
+		wg.Add(1)
+		go func(i int, node *repository.NodeMaintenanceNode) {
+			defer wg.Done()
+			drainErrors[i] = impl.drainNode(ctx, workflow.ClusterId, runnerId, options, clientSet, node)
+		}(i, node)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err = utilerrors.NewAggregate(drainErrors); err != nil {
+		return err
+	}
+	if options.WaitForReplacement {
+		if err = impl.waitForReplacementNodes(ctx, options, clientSet, batch, batchStartedOn); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			for _, node := range batch {
+				impl.updateNodeStatus(node, runnerId, bean.NodeStatusFailed, err.Error())
+			}
+			return err
+		}
+	}
+	for _, node := range batch {
+		impl.updateNodeStatus(node, runnerId, bean.NodeStatusSucceeded, "")
+	}
+	return nil
+}
+
+// drainNode cordons and drains the node and waits for the evicted pods to leave it, within the node timeout
+func (impl *NodeMaintenanceServiceImpl) drainNode(ctx context.Context, clusterId int, runnerId string, options bean.MaintenanceOptions, clientSet *kubernetes.Clientset, node *repository.NodeMaintenanceNode) error {
+	if node.StartedOn == nil {
+		now := time.Now()
+		node.StartedOn = &now
+	}
+	impl.updateNodeStatus(node, runnerId, bean.NodeStatusDraining, "")
+	nodeCtx, cancel := context.WithTimeout(ctx, time.Duration(options.NodeTimeoutSecs)*time.Second)
+	defer cancel()
+	drainHelper := options.DrainOptions.GetNodeDrainHelper()
+	request := &capacityBean.NodeUpdateRequestDto{ClusterId: clusterId, Name: node.NodeName, NodeDrainHelper: drainHelper}
+	_, err := impl.k8sCapacityService.DrainNode(nodeCtx, request)
+	if err == nil {
+		// eviction only starts the termination of the pods, wait for them to leave the node
+		drainHelper.K8sClientSet = clientSet
+		err = wait.PollUntilContextCancel(nodeCtx, podTerminationPollInterval, true, func(ctx context.Context) (bool, error) {
+			list, errs := capacity.GetPodsByNodeNameForDeletion(node.NodeName, drainHelper)
+			if len(errs) > 0 {
+				return false, utilerrors.NewAggregate(errs)
+			}
+			return len(list.Pods()) == 0, nil
+		})
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if nodeCtx.Err() != nil {
+			err = fmt.Errorf("drain did not complete in %d seconds: %s", options.NodeTimeoutSecs, err.Error())
+		}
+		impl.logger.Errorw("error in draining node", "nodeName", node.NodeName, "err", err)
+		impl.updateNodeStatus(node, runnerId, bean.NodeStatusFailed, err.Error())
+		return fmt.Errorf("node %s: %s", node.NodeName, err.Error())
+	}
+	if options.WaitForReplacement {
+		impl.updateNodeStatus(node, runnerId, bean.NodeStatusWaitingForReplacement, "")
+	}
+	return nil
+}
+
+// waitForReplacementNodes waits for as many new Ready nodes in every node group as nodes of the group were drained
+func (impl *NodeMaintenanceServiceImpl) waitForReplacementNodes(ctx context.Context, options bean.MaintenanceOptions, clientSet *kubernetes.Clientset, batch []*repository.NodeMaintenanceNode, createdAfter time.Time) error {
+	required := make(map[string]int)
+	for _, node := range batch {
+		required[node.NodeGroup]++
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(options.ReplacementTimeoutSecs)*time.Second)
+	defer cancel()
+	err := wait.PollUntilContextCancel(timeoutCtx, replacementPollInterval, true, func(ctx context.Context) (bool, error) {
+		nodeList, err := clientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			impl.logger.Warnw("error in listing nodes, retrying", "err", err)
+			return false, nil
+		}
+		for nodeGroup, count := range required {
+			if countReplacementNodes(nodeList.Items, nodeGroup, createdAfter) < count {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("replacement nodes did not become ready in %d seconds", options.ReplacementTimeoutSecs)
+	}
+	return err
+}
+
+func (impl *NodeMaintenanceServiceImpl) updateNodeStatus(node *repository.NodeMaintenanceNode, runnerId string, status bean.NodeStatus, message string) {
+	node.Status = status
+	node.Message = message
+	if status.IsTerminal() {
+		now := time.Now()
+		node.FinishedOn = &now
+	}
+	node.UpdateAuditLog(userBean.SystemUserId)
+	updated, err := impl.repository.UpdateNode(node, runnerId)
+	if err != nil {
+		impl.logger.Errorw("error in updating node maintenance node", "nodeName", node.NodeName, "status", status, "err", err)
+	} else if !updated {
+		impl.logger.Infow("node maintenance workflow is not run by this runner anymore, node not updated", "nodeName", node.NodeName, "runnerId", runnerId, "status", status)
+	}
+}
+
+// getNextBatch returns up to maxUnavailable nodes not done with, nodes left in progress by a paused or lost runner first
+func getNextBatch(nodes []*repository.NodeMaintenanceNode, maxUnavailable int) []*repository.NodeMaintenanceNode {
+	batch := make([]*repository.NodeMaintenanceNode, 0, maxUnavailable)
+	for _, node := range nodes {
+		if len(batch) < maxUnavailable && !node.Status.IsTerminal() && node.Status != bean.NodeStatusPending {
+			batch = append(batch, node)
+		}
+	}
+	for _, node := range nodes {
+		if len(batch) < maxUnavailable && node.Status == bean.NodeStatusPending {
+			batch = append(batch, node)
+		}
+	}
+	return batch
+}
+
+// filterNodes returns the nodes matching any criteria of the selector and the named nodes not found
+func filterNodes(nodes []corev1.Node, selector *bean.NodeSelector) ([]corev1.Node, []string, error) {
+	labelSelector, err := labels.Parse(selector.LabelSelector)
+	if err != nil {
+		return nil, nil, err
+	}
+	nodeNames := make(map[string]bool, len(selector.NodeNames))
+	for _, nodeName := range selector.NodeNames {
+		nodeNames[nodeName] = false
+	}
+	var selected []corev1.Node
+	for _, node := range nodes {
+		_, named := nodeNames[node.Name]
+		if named {
+			nodeNames[node.Name] = true
+		}
+		if named ||
+			(len(selector.LabelSelector) > 0 && labelSelector.Matches(labels.Set(node.Labels))) ||
+			(len(selector.NodeGroup) > 0 && capacity.GetNodeGroup(&node) == selector.NodeGroup) {
+			selected = append(selected, node)
+		}
+	}
+	var missingNodeNames []string
+	for _, nodeName := range selector.NodeNames {
+		if !nodeNames[nodeName] {
+			missingNodeNames = append(missingNodeNames, nodeName)
+		}
+	}
+	return selected, missingNodeNames, nil
+}
+
+// countReplacementNodes counts the Ready and schedulable nodes of the node group created after createdAfter
+func countReplacementNodes(nodes []corev1.Node, nodeGroup string, createdAfter time.Time) int {
+	count := 0
+	for i := range nodes {
+		node := &nodes[i]
+		if !node.CreationTimestamp.Time.After(createdAfter) || node.Spec.Unschedulable {
+			continue
+		}
+		if len(nodeGroup) > 0 && capacity.GetNodeGroup(node) != nodeGroup {
+			continue
+		}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package nodeMaintenance
+
+import (
+	capacityBean "github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance/repository"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func newNode(name, nodeGroup string, createdOn time.Time, ready bool) corev1.Node {
+	node := corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:              name,
+		Labels:            map[string]string{"pool": "general"},
+		CreationTimestamp: metav1.NewTime(createdOn),
+	}}
+	if len(nodeGroup) > 0 {
+		node.Labels[capacityBean.AWSEKSNodeGroupLabel] = nodeGroup
+	}
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}
+	return node
+}
+
+func TestGetNextBatch(t *testing.T) {
+	nodes := []*repository.NodeMaintenanceNode{
+		{NodeName: "n1", Status: bean.NodeStatusSucceeded},
+		{NodeName: "n2", Status: bean.NodeStatusPending},
+		{NodeName: "n3", Status: bean.NodeStatusPending},
+		{NodeName: "n4", Status: bean.NodeStatusDraining},
+		{NodeName: "n5", Status: bean.NodeStatusSkipped},
+	}
+	batch := getNextBatch(nodes, 2)
+	assert.Equal(t, []string{"n4", "n2"}, []string{batch[0].NodeName, batch[1].NodeName})
+	assert.Len(t, getNextBatch(nodes, 5), 3)
+	assert.Empty(t, getNextBatch(nodes[:1], 2))
+}
+
+func TestFilterNodes(t *testing.T) {
+	now := time.Now()
+	nodes := []corev1.Node{
+		newNode("n1", "ng-a", now, true),
+		newNode("n2", "ng-b", now, true),
+		newNode("n3", "", now, true),
+	}
+	nodes[2].Labels["pool"] = "gpu"
+
+	selected, missing, err := filterNodes(nodes, &bean.NodeSelector{NodeGroup: "ng-a", NodeNames: []string{"n3", "n9"}})
+	assert.NoError(t, err)
+	assert.Len(t, selected, 2)
+	assert.Equal(t, []string{"n9"}, missing)
+
+	selected, _, err = filterNodes(nodes, &bean.NodeSelector{LabelSelector: "pool=general"})
+	assert.NoError(t, err)
+	assert.Len(t, selected, 2)
+}
+
+func TestCountReplacementNodes(t *testing.T) {
+	drainStartedOn := time.Now()
+	nodes := []corev1.Node{
+		newNode("old", "ng-a", drainStartedOn.Add(-time.Hour), true),
+		newNode("new-ready", "ng-a", drainStartedOn.Add(time.Minute), true),
+		newNode("new-not-ready", "ng-a", drainStartedOn.Add(time.Minute), false),
+		newNode("new-other-group", "ng-b", drainStartedOn.Add(time.Minute), true),
+	}
+	assert.Equal(t, 1, countReplacementNodes(nodes, "ng-a", drainStartedOn))
+	assert.Equal(t, 2, countReplacementNodes(nodes, "", drainStartedOn))
+	nodes[1].Spec.Unschedulable = true
+	assert.Equal(t, 0, countReplacementNodes(nodes, "ng-a", drainStartedOn))
+}
+
+func TestMaintenanceOptions(t *testing.T) {
+	options := bean.MaintenanceOptions{MaxUnavailable: 3}
+	options.SetDefaults()
+	assert.Equal(t, 3, options.MaxUnavailable)
+	assert.Equal(t, bean.DefaultNodeTimeoutSecs, options.NodeTimeoutSecs)
+	assert.Equal(t, bean.DefaultReplacementTimeoutSecs, options.ReplacementTimeoutSecs)
+	assert.True(t, options.DrainOptions.GetNodeDrainHelper().IgnoreAllDaemonSets)
+
+	assert.Error(t, (&bean.NodeSelector{}).Validate())
+	assert.Error(t, (&bean.NodeSelector{LabelSelector: "pool in (a"}).Validate())
+	assert.NoError(t, (&bean.NodeSelector{NodeGroup: "ng-a"}).Validate())
+}
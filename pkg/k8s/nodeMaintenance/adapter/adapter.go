@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance/repository"
+)
+
+func BuildNodeMaintenanceWorkflow(request *bean.CreateWorkflowRequest) (*repository.NodeMaintenanceWorkflow, error) {
+	selector, err := json.Marshal(request.Selector)
+	if err != nil {
+		return nil, err
+	}
+	options, err := json.Marshal(request.Options)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.NodeMaintenanceWorkflow{
+		ClusterId: request.ClusterId,
+		Selector:  string(selector),
+		Options:   string(options),
+		Status:    bean.WorkflowStatusRunning,
+	}, nil
+}
+
+func GetMaintenanceOptions(workflow *repository.NodeMaintenanceWorkflow) (bean.MaintenanceOptions, error) {
+	options := bean.MaintenanceOptions{}
+	err := json.Unmarshal([]byte(workflow.Options), &options)
+	return options, err
+}
+
+func GetWorkflowDto(workflow *repository.NodeMaintenanceWorkflow, nodes []*repository.NodeMaintenanceNode) (*bean.WorkflowDto, error) {
+	selector := &bean.NodeSelector{}
+	if err := json.Unmarshal([]byte(workflow.Selector), selector); err != nil {
+		return nil, err
+	}
+	options, err := GetMaintenanceOptions(workflow)
+	if err != nil {
+		return nil, err
+	}
+	workflowDto := &bean.WorkflowDto{
+		Id:         workflow.Id,
+		ClusterId:  workflow.ClusterId,
+		Selector:   selector,
+		Options:    options,
+		Status:     workflow.Status,
+		Message:    workflow.Message,
+		StartedOn:  workflow.StartedOn,
+		FinishedOn: workflow.FinishedOn,
+		CreatedBy:  workflow.CreatedBy,
+	}
+	for _, node := range nodes {
+		workflowDto.Nodes = append(workflowDto.Nodes, &bean.NodeDto{
+			NodeName:   node.NodeName,
+			NodeGroup:  node.NodeGroup,
+			Status:     node.Status,
+			Message:    node.Message,
+			StartedOn:  node.StartedOn,
+			FinishedOn: node.FinishedOn,
+		})
+	}
+	return workflowDto, nil
+}
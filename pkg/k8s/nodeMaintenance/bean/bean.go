@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bean
+
+import (
+	"errors"
+	"fmt"
+	capacityBean "github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"k8s.io/apimachinery/pkg/labels"
+	"time"
+)
+
+type WorkflowStatus string
+
+const (
+	WorkflowStatusRunning   WorkflowStatus = "Running"
+	WorkflowStatusPaused    WorkflowStatus = "Paused"
+	WorkflowStatusAborted   WorkflowStatus = "Aborted"
+	WorkflowStatusSucceeded WorkflowStatus = "Succeeded"
+	WorkflowStatusFailed    WorkflowStatus = "Failed"
+)
+
+// IsInProgress reports whether the workflow can still be paused, resumed or aborted
+func (status WorkflowStatus) IsInProgress() bool {
+	return status == WorkflowStatusRunning || status == WorkflowStatusPaused
+}
+
+type NodeStatus string
+
+const (
+	NodeStatusPending               NodeStatus = "Pending"
+	NodeStatusDraining              NodeStatus = "Draining"
+	NodeStatusWaitingForReplacement NodeStatus = "WaitingForReplacement"
+	NodeStatusSucceeded             NodeStatus = "Succeeded"
+	NodeStatusFailed                NodeStatus = "Failed"
+	NodeStatusSkipped               NodeStatus = "Skipped"
+)
+
+// IsTerminal reports whether the node is done with, nodes in Draining or WaitingForReplacement are picked up again on resume
+func (status NodeStatus) IsTerminal() bool {
+	return status == NodeStatusSucceeded || status == NodeStatusFailed || status == NodeStatusSkipped
+}
+
+const (
+	DefaultMaxUnavailable         = 1
+	DefaultNodeTimeoutSecs        = 600
+	DefaultReplacementTimeoutSecs = 900
+)
+
+// NodeSelector selects the nodes of the workflow, nodes matching any of the given criteria are selected
+type NodeSelector struct {
+	NodeNames     []string `json:"nodeNames,omitempty"`
+	LabelSelector string   `json:"labelSelector,omitempty"`
+	NodeGroup     string   `json:"nodeGroup,omitempty"`
+}
+
+func (selector *NodeSelector) Validate() error {
+	if selector == nil || (len(selector.NodeNames) == 0 && len(selector.LabelSelector) == 0 && len(selector.NodeGroup) == 0) {
+		return errors.New("node names, label selector or node group is required")
+	}
+	if _, err := labels.Parse(selector.LabelSelector); err != nil {
+		return fmt.Errorf("invalid label selector: %s", err.Error())
+	}
+	return nil
+}
+
+// DrainOptions are the drain options of every node, daemonSet pods are always ignored
+type DrainOptions struct {
+	Force              bool `json:"force"`
+	DeleteEmptyDirData bool `json:"deleteEmptyDirData"`
+	// GracePeriodSeconds is how long to wait for a pod to terminate, negative to use the terminationGracePeriodSeconds of the pod
+	GracePeriodSeconds int  `json:"gracePeriodSeconds"`
+	DisableEviction    bool `json:"disableEviction"`
+}
+
+func (options DrainOptions) GetNodeDrainHelper() *capacityBean.NodeDrainHelper {
+	return &capacityBean.NodeDrainHelper{
+		Force:               options.Force,
+		DeleteEmptyDirData:  options.DeleteEmptyDirData,
+		GracePeriodSeconds:  options.GracePeriodSeconds,
+		IgnoreAllDaemonSets: true,
+		DisableEviction:     options.DisableEviction,
+	}
+}
+
+type MaintenanceOptions struct {
+	// MaxUnavailable is the number of nodes drained at once
+	MaxUnavailable int `json:"maxUnavailable" validate:"min=0"`
+	// NodeTimeoutSecs bounds the drain of a node including the wait for pods blocked by pod disruption budgets
+	NodeTimeoutSecs int `json:"nodeTimeoutSecs" validate:"min=0"`
+	// WaitForReplacement waits, after every batch, for as many new Ready nodes in the node group of the drained nodes
+	WaitForReplacement     bool         `json:"waitForReplacement"`
+	ReplacementTimeoutSecs int          `json:"replacementTimeoutSecs" validate:"min=0"`
+	DrainOptions           DrainOptions `json:"drainOptions"`
+}
+
+func (options *MaintenanceOptions) SetDefaults() {
+	if options.MaxUnavailable == 0 {
+		options.MaxUnavailable = DefaultMaxUnavailable
+	}
+	if options.NodeTimeoutSecs == 0 {
+		options.NodeTimeoutSecs = DefaultNodeTimeoutSecs
+	}
+	if options.ReplacementTimeoutSecs == 0 {
+		options.ReplacementTimeoutSecs = DefaultReplacementTimeoutSecs
+	}
+}
+
+type CreateWorkflowRequest struct {
+	ClusterId int                `json:"clusterId" validate:"required"`
+	Selector  *NodeSelector      `json:"selector" validate:"required"`
+	Options   MaintenanceOptions `json:"options"`
+	UserId    int32              `json:"-"`
+}
+
+type NodeDto struct {
+	NodeName   string     `json:"nodeName"`
+	NodeGroup  string     `json:"nodeGroup,omitempty"`
+	Status     NodeStatus `json:"status"`
+	Message    string     `json:"message,omitempty"`
+	StartedOn  *time.Time `json:"startedOn,omitempty"`
+	FinishedOn *time.Time `json:"finishedOn,omitempty"`
+}
+
+type WorkflowDto struct {
+	Id         int                `json:"id"`
+	ClusterId  int                `json:"clusterId"`
+	Selector   *NodeSelector      `json:"selector"`
+	Options    MaintenanceOptions `json:"options"`
+	Status     WorkflowStatus     `json:"status"`
+	Message    string             `json:"message,omitempty"`
+	StartedOn  *time.Time         `json:"startedOn,omitempty"`
+	FinishedOn *time.Time         `json:"finishedOn,omitempty"`
+	CreatedBy  int32              `json:"createdBy"`
+	Nodes      []*NodeDto         `json:"nodes,omitempty"`
+}
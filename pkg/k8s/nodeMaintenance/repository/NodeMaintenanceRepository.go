@@ -0,0 +1,264 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package repository
+
+import (
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type NodeMaintenanceWorkflow struct {
+	tableName   struct{}            `sql:"node_maintenance_workflow" pg:",discard_unknown_columns"`
+	Id          int                 `sql:"id,pk"`
+	ClusterId   int                 `sql:"cluster_id,notnull"`
+	Selector    string              `sql:"selector,notnull"`
+	Options     string              `sql:"options,notnull"`
+	Status      bean.WorkflowStatus `sql:"status,notnull"`
+	Message     string              `sql:"message"`
+	RunnerId    string              `sql:"runner_id"`
+	HeartbeatOn *time.Time          `sql:"heartbeat_on"`
+	StartedOn   *time.Time          `sql:"started_on"`
+	FinishedOn  *time.Time          `sql:"finished_on"`
+	sql.AuditLog
+}
+
+type NodeMaintenanceNode struct {
+	tableName  struct{}        `sql:"node_maintenance_node" pg:",discard_unknown_columns"`
+	Id         int             `sql:"id,pk"`
+	WorkflowId int             `sql:"workflow_id,notnull"`
+	NodeName   string          `sql:"node_name,notnull"`
+	NodeGroup  string          `sql:"node_group"`
+	Status     bean.NodeStatus `sql:"status,notnull"`
+	Message    string          `sql:"message"`
+	StartedOn  *time.Time      `sql:"started_on"`
+	FinishedOn *time.Time      `sql:"finished_on"`
+	sql.AuditLog
+}
+
+type NodeMaintenanceRepository interface {
+	sql.TransactionWrapper
+	SaveWorkflow(tx *pg.Tx, workflow *NodeMaintenanceWorkflow) error
+	// UpdateWorkflowStatus moves the workflow to status if it is in one of fromStatuses, false if it is not
+	UpdateWorkflowStatus(id int, fromStatuses []bean.WorkflowStatus, status bean.WorkflowStatus, message string, userId int32) (bool, error)
+	// FinishWorkflow moves the running workflow owned by runnerId to status, false if the runner lost it
+	FinishWorkflow(id int, runnerId string, status bean.WorkflowStatus, message string) (bool, error)
+	FindWorkflowById(id int) (*NodeMaintenanceWorkflow, error)
+	FindWorkflowsByClusterId(clusterId int) ([]*NodeMaintenanceWorkflow, error)
+	FindInProgressWorkflowByClusterId(clusterId int) (*NodeMaintenanceWorkflow, error)
+	// FindStaleRunningWorkflowIds returns the running workflows not heart beaten since staleBefore, their orchestrator is gone
+	FindStaleRunningWorkflowIds(staleBefore time.Time) ([]int, error)
+	// ClaimWorkflow takes over a running workflow not heart beaten since staleBefore for runnerId, false if another orchestrator runs it
+	ClaimWorkflow(id int, runnerId string, staleBefore time.Time) (bool, error)
+	// HeartbeatWorkflow refreshes the heartbeat of a running workflow owned by runnerId, false once the runner lost it
+	HeartbeatWorkflow(id int, runnerId string) (bool, error)
+	SaveNodes(tx *pg.Tx, nodes []*NodeMaintenanceNode) error
+	// UpdateNode updates the node of a running workflow owned by runnerId, false if the runner lost the workflow
+	UpdateNode(node *NodeMaintenanceNode, runnerId string) (bool, error)
+	FindNodesByWorkflowId(workflowId int) ([]*NodeMaintenanceNode, error)
+	// SkipPendingNodes marks the nodes of the workflow which are not done with as skipped
+	SkipPendingNodes(workflowId int, message string, userId int32) error
+	// ResetFailedNodes marks the failed nodes of the workflow as pending to be retried
+	ResetFailedNodes(workflowId int, userId int32) error
+}
+
+type NodeMaintenanceRepositoryImpl struct {
+	*sql.TransactionUtilImpl
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewNodeMaintenanceRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, TransactionUtilImpl *sql.TransactionUtilImpl) *NodeMaintenanceRepositoryImpl {
+	return &NodeMaintenanceRepositoryImpl{
+		TransactionUtilImpl: TransactionUtilImpl,
+		dbConnection:        dbConnection,
+		logger:              logger,
+	}
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) SaveWorkflow(tx *pg.Tx, workflow *NodeMaintenanceWorkflow) error {
+	return tx.Insert(workflow)
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) UpdateWorkflowStatus(id int, fromStatuses []bean.WorkflowStatus, status bean.WorkflowStatus, message string, userId int32) (bool, error) {
+	query := impl.dbConnection.Model((*NodeMaintenanceWorkflow)(nil)).
+		Set("status = ?", status).
+		Set("message = ?", message).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId)
+	if status.IsInProgress() {
+		query = query.Set("finished_on = NULL")
+	} else {
+		query = query.Set("finished_on = ?", time.Now())
+	}
+	if status == bean.WorkflowStatusRunning {
+		// the workflow is claimed by the next runner
+		query = query.Set("heartbeat_on = NULL").Set("runner_id = NULL")
+	}
+	result, err := query.
+		Where("id = ?", id).
+		Where("status in (?)", pg.In(fromStatuses)).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) FinishWorkflow(id int, runnerId string, status bean.WorkflowStatus, message string) (bool, error) {
+	result, err := impl.dbConnection.Model((*NodeMaintenanceWorkflow)(nil)).
+		Set("status = ?", status).
+		Set("message = ?", message).
+		Set("finished_on = ?", time.Now()).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userBean.SystemUserId).
+		Where("id = ?", id).
+		Where("status = ?", bean.WorkflowStatusRunning).
+		Where("runner_id = ?", runnerId).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) FindWorkflowById(id int) (*NodeMaintenanceWorkflow, error) {
+	workflow := &NodeMaintenanceWorkflow{}
+	err := impl.dbConnection.Model(workflow).
+		Where("id = ?", id).
+		Select()
+	return workflow, err
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) FindWorkflowsByClusterId(clusterId int) ([]*NodeMaintenanceWorkflow, error) {
+	var workflows []*NodeMaintenanceWorkflow
+	err := impl.dbConnection.Model(&workflows).
+		Where("cluster_id = ?", clusterId).
+		Order("id DESC").
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting node maintenance workflows", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	return workflows, nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) FindInProgressWorkflowByClusterId(clusterId int) (*NodeMaintenanceWorkflow, error) {
+	workflow := &NodeMaintenanceWorkflow{}
+	err := impl.dbConnection.Model(workflow).
+		Where("cluster_id = ?", clusterId).
+		Where("status in (?)", pg.In([]bean.WorkflowStatus{bean.WorkflowStatusRunning, bean.WorkflowStatusPaused})).
+		Select()
+	return workflow, err
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) FindStaleRunningWorkflowIds(staleBefore time.Time) ([]int, error) {
+	var ids []int
+	err := impl.dbConnection.Model((*NodeMaintenanceWorkflow)(nil)).
+		Column("id").
+		Where("status = ?", bean.WorkflowStatusRunning).
+		Where("heartbeat_on IS NULL OR heartbeat_on < ?", staleBefore).
+		Select(&ids)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting stale node maintenance workflows", "err", err)
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) ClaimWorkflow(id int, runnerId string, staleBefore time.Time) (bool, error) {
+	result, err := impl.dbConnection.Model((*NodeMaintenanceWorkflow)(nil)).
+		Set("runner_id = ?", runnerId).
+		Set("heartbeat_on = ?", time.Now()).
+		Where("id = ?", id).
+		Where("status = ?", bean.WorkflowStatusRunning).
+		Where("heartbeat_on IS NULL OR heartbeat_on < ?", staleBefore).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) HeartbeatWorkflow(id int, runnerId string) (bool, error) {
+	result, err := impl.dbConnection.Model((*NodeMaintenanceWorkflow)(nil)).
+		Set("heartbeat_on = ?", time.Now()).
+		Where("id = ?", id).
+		Where("status = ?", bean.WorkflowStatusRunning).
+		Where("runner_id = ?", runnerId).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) SaveNodes(tx *pg.Tx, nodes []*NodeMaintenanceNode) error {
+	_, err := tx.Model(&nodes).Insert()
+	return err
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) UpdateNode(node *NodeMaintenanceNode, runnerId string) (bool, error) {
+	result, err := impl.dbConnection.Model(node).
+		WherePK().
+		Where("EXISTS (SELECT 1 FROM node_maintenance_workflow w WHERE w.id = ? AND w.status = ? AND w.runner_id = ?)",
+			node.WorkflowId, bean.WorkflowStatusRunning, runnerId).
+		Update()
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) FindNodesByWorkflowId(workflowId int) ([]*NodeMaintenanceNode, error) {
+	var nodes []*NodeMaintenanceNode
+	err := impl.dbConnection.Model(&nodes).
+		Where("workflow_id = ?", workflowId).
+		Order("id ASC").
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting node maintenance nodes", "workflowId", workflowId, "err", err)
+		return nil, err
+	}
+	return nodes, nil
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) SkipPendingNodes(workflowId int, message string, userId int32) error {
+	_, err := impl.dbConnection.Model((*NodeMaintenanceNode)(nil)).
+		Set("status = ?", bean.NodeStatusSkipped).
+		Set("message = ?", message).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("workflow_id = ?", workflowId).
+		Where("status in (?)", pg.In([]bean.NodeStatus{bean.NodeStatusPending, bean.NodeStatusDraining, bean.NodeStatusWaitingForReplacement})).
+		Update()
+	return err
+}
+
+func (impl *NodeMaintenanceRepositoryImpl) ResetFailedNodes(workflowId int, userId int32) error {
+	_, err := impl.dbConnection.Model((*NodeMaintenanceNode)(nil)).
+		Set("status = ?", bean.NodeStatusPending).
+		Set("message = ?", "").
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("workflow_id = ?", workflowId).
+		Where("status = ?", bean.NodeStatusFailed).
+		Update()
+	return err
+}
@@ -0,0 +1,342 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resourceSearch
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env"
+	k8s2 "github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	k8sBean "github.com/devtron-labs/devtron/pkg/k8s/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceSearch/adapter"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceSearch/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceSearch/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/gammazero/workerpool"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ResourceSearchConfig struct {
+	Concurrency          int `env:"RESOURCE_SEARCH_CONCURRENCY" envDefault:"5" description:"Number of clusters searched in parallel by the cross-cluster resource search"`
+	ClusterTimeoutSecs   int `env:"RESOURCE_SEARCH_CLUSTER_TIMEOUT_SECS" envDefault:"30" description:"Timeout of the resource search in one cluster, the cluster is reported failed after it"`
+	MaxClusters          int `env:"RESOURCE_SEARCH_MAX_CLUSTERS" envDefault:"50" description:"Maximum number of clusters in one resource search"`
+	MaxResultsPerCluster int `env:"RESOURCE_SEARCH_MAX_RESULTS_PER_CLUSTER" envDefault:"1000" description:"Maximum number of resources returned from one cluster, the cluster result is marked truncated beyond it"`
+}
+
+func GetResourceSearchConfig() (*ResourceSearchConfig, error) {
+	cfg := &ResourceSearchConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+type ResourceAccessValidator func(token string, clusterName string, request k8sBean.ResourceRequestBean, casbinAction string) bool
+
+type ResourceSearchService interface {
+	// SearchResources lists the resources matching query in every cluster of the query in parallel, resources are
+	// filtered by validateResourceAccess. Clusters failing the search are reported in the response.
+	SearchResources(ctx context.Context, token string, query *bean.ResourceSearchQuery, validateResourceAccess ResourceAccessValidator) (*bean.ResourceSearchResponse, error)
+
+	SaveView(request *bean.ResourceViewDto) (*bean.ResourceViewDto, error)
+	UpdateView(request *bean.ResourceViewDto) (*bean.ResourceViewDto, error)
+	// GetView returns the view only to the user who saved it
+	GetView(id int, userId int32) (*bean.ResourceViewDto, error)
+	GetAllViews(userId int32) ([]*bean.ResourceViewDto, error)
+	DeleteView(id int, userId int32) error
+	// SearchView runs the query of a saved view with the access of the current user
+	SearchView(ctx context.Context, token string, id int, userId int32, validateResourceAccess ResourceAccessValidator) (*bean.ResourceSearchResponse, error)
+}
+
+type ResourceSearchServiceImpl struct {
+	logger           *zap.SugaredLogger
+	k8sCommonService k8s.K8sCommonService
+	K8sUtil          *k8s2.K8sServiceImpl
+	viewRepository   repository.ResourceBrowserViewRepository
+	config           *ResourceSearchConfig
+}
+
+func NewResourceSearchServiceImpl(logger *zap.SugaredLogger,
+	k8sCommonService k8s.K8sCommonService,
+	K8sUtil *k8s2.K8sServiceImpl,
+	viewRepository repository.ResourceBrowserViewRepository,
+	config *ResourceSearchConfig) *ResourceSearchServiceImpl {
+	return &ResourceSearchServiceImpl{
+		logger:           logger,
+		k8sCommonService: k8sCommonService,
+		K8sUtil:          K8sUtil,
+		viewRepository:   viewRepository,
+		config:           config,
+	}
+}
+
+func (impl *ResourceSearchServiceImpl) SearchResources(ctx context.Context, token string, query *bean.ResourceSearchQuery,
+	validateResourceAccess ResourceAccessValidator) (*bean.ResourceSearchResponse, error) {
+	if err := impl.validateQuery(query); err != nil {
+		return nil, err
+	}
+	clusterIds := getUniqueClusterIds(query.ClusterIds)
+	type clusterSearchOutput struct {
+		result *bean.ClusterSearchResult
+		list   *k8s2.ClusterResourceListMap
+	}
+	outputs := make([]*clusterSearchOutput, len(clusterIds))
+	wp := workerpool.New(impl.config.Concurrency)
+	for i := range clusterIds {
+		index, clusterId := i, clusterIds[i]
+		wp.Submit(func() {
+			result, list := impl.searchCluster(ctx, token, clusterId, query, validateResourceAccess)
+			outputs[index] = &clusterSearchOutput{result: result, list: list}
+		})
+	}
+	wp.StopWait()
+	response := &bean.ResourceSearchResponse{
+		Data:     make([]map[string]interface{}, 0),
+		Clusters: make([]*bean.ClusterSearchResult, 0, len(outputs)),
+	}
+	for _, output := range outputs {
+		response.Clusters = append(response.Clusters, output.result)
+		if output.list == nil {
+			continue
+		}
+		if response.Headers == nil {
+			response.Headers = append([]string{bean.ClusterNameKey}, output.list.Headers...)
+		}
+		response.Data = append(response.Data, output.list.Data...)
+	}
+	return response, nil
+}
+
+func (impl *ResourceSearchServiceImpl) validateQuery(query *bean.ResourceSearchQuery) error {
+	if err := query.Validate(); err != nil {
+		return util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	if len(getUniqueClusterIds(query.ClusterIds)) > impl.config.MaxClusters {
+		msg := fmt.Sprintf("at most %d clusters can be searched at once", impl.config.MaxClusters)
+		return util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	return nil
+}
+
+// searchCluster lists the resources of one cluster, the returned list is nil when the search failed in the cluster
+func (impl *ResourceSearchServiceImpl) searchCluster(ctx context.Context, token string, clusterId int, query *bean.ResourceSearchQuery,
+	validateResourceAccess ResourceAccessValidator) (*bean.ClusterSearchResult, *k8s2.ClusterResourceListMap) {
+	result := &bean.ClusterSearchResult{ClusterId: clusterId, Status: bean.ClusterSearchStatusFailed}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(impl.config.ClusterTimeoutSecs)*time.Second)
+	defer cancel()
+	restConfig, err, clusterBean := impl.k8sCommonService.GetRestConfigByClusterId(ctx, clusterId)
+	if err == pg.ErrNoRows {
+		result.Error = "cluster not found"
+		return result, nil
+	} else if err != nil {
+		result.Error = util.GetClientErrorDetailedMessage(err)
+		return result, nil
+	}
+	result.ClusterName = clusterBean.ClusterName
+	if clusterBean.IsVirtualCluster {
+		result.Error = "resources of virtual clusters cannot be listed"
+		return result, nil
+	}
+	gvk := query.GroupVersionKind
+	listOptions := &metav1.ListOptions{
+		TypeMeta:      metav1.TypeMeta{Kind: gvk.Kind, APIVersion: gvk.GroupVersion().String()},
+		LabelSelector: query.LabelSelector,
+		FieldSelector: query.FieldSelector,
+	}
+	resp, namespaced, err := impl.K8sUtil.GetResourceList(ctx, restConfig, gvk, query.Namespace, true, listOptions)
+	if err != nil {
+		impl.logger.Errorw("error in searching resources in cluster", "clusterId", clusterId, "gvk", gvk, "err", err)
+		result.Error = util.GetClientErrorDetailedMessage(err)
+		return result, nil
+	}
+	checkForResourceCallback := func(namespace, group, kind, resourceName string) bool {
+		if validateResourceAccess == nil {
+			return true
+		}
+		resourceIdentifier := k8s2.ResourceIdentifier{Name: resourceName, Namespace: namespace, GroupVersionKind: gvk}
+		if group != "" && kind != "" {
+			resourceIdentifier.GroupVersionKind = schema.GroupVersionKind{Group: group, Kind: kind}
+		}
+		request := k8sBean.ResourceRequestBean{ClusterId: clusterId, K8sRequest: &k8s2.K8sRequestBean{ResourceIdentifier: resourceIdentifier}}
+		return validateResourceAccess(token, clusterBean.ClusterName, request, casbin.ActionGet)
+	}
+	resourceList, err := impl.K8sUtil.BuildK8sObjectListTableData(&resp.Resources, namespaced, gvk, false, checkForResourceCallback)
+	if err != nil {
+		impl.logger.Errorw("error in parsing resources of cluster", "clusterId", clusterId, "gvk", gvk, "err", err)
+		result.Error = util.GetClientErrorDetailedMessage(err)
+		return result, nil
+	}
+	resourceList.Data, result.Truncated = filterRows(resourceList.Data, query.NameContains, impl.config.MaxResultsPerCluster)
+	for _, row := range resourceList.Data {
+		row[bean.ClusterIdKey] = clusterId
+		row[bean.ClusterNameKey] = clusterBean.ClusterName
+	}
+	result.Status, result.Count = bean.ClusterSearchStatusSucceeded, len(resourceList.Data)
+	return result, resourceList
+}
+
+// filterRows keeps the rows whose name contains nameContains, at most limit rows are kept
+func filterRows(rows []map[string]interface{}, nameContains string, limit int) ([]map[string]interface{}, bool) {
+	nameContains = strings.ToLower(nameContains)
+	filtered := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if len(nameContains) > 0 {
+			name, _ := row[commonBean.K8sClusterResourceNameKey].(string)
+			if !strings.Contains(strings.ToLower(name), nameContains) {
+				continue
+			}
+		}
+		if len(filtered) == limit {
+			return filtered, true
+		}
+		filtered = append(filtered, row)
+	}
+	return filtered, false
+}
+
+func getUniqueClusterIds(clusterIds []int) []int {
+	uniqueIds := make([]int, 0, len(clusterIds))
+	seen := make(map[int]bool, len(clusterIds))
+	for _, clusterId := range clusterIds {
+		if !seen[clusterId] {
+			seen[clusterId] = true
+			uniqueIds = append(uniqueIds, clusterId)
+		}
+	}
+	return uniqueIds
+}
+
+func (impl *ResourceSearchServiceImpl) SaveView(request *bean.ResourceViewDto) (*bean.ResourceViewDto, error) {
+	if err := impl.validateQuery(request.Query); err != nil {
+		return nil, err
+	}
+	existing, err := impl.viewRepository.FindByName(request.Name, request.UserId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting resource browser view by name", "name", request.Name, "err", err)
+		return nil, err
+	} else if existing.Id > 0 {
+		return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("view %s already exists", request.Name), "resource browser view already exists")
+	}
+	view, err := adapter.BuildResourceBrowserView(request)
+	if err != nil {
+		return nil, err
+	}
+	view.Id = 0
+	view.AuditLog = sql.NewDefaultAuditLog(request.UserId)
+	if err = impl.viewRepository.Save(view); err != nil {
+		impl.logger.Errorw("error in saving resource browser view", "name", request.Name, "err", err)
+		return nil, err
+	}
+	request.Id = view.Id
+	return request, nil
+}
+
+func (impl *ResourceSearchServiceImpl) UpdateView(request *bean.ResourceViewDto) (*bean.ResourceViewDto, error) {
+	if err := impl.validateQuery(request.Query); err != nil {
+		return nil, err
+	}
+	existing, err := impl.getView(request.Id, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Name != request.Name {
+		sameName, err := impl.viewRepository.FindByName(request.Name, request.UserId)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in getting resource browser view by name", "name", request.Name, "err", err)
+			return nil, err
+		} else if sameName.Id > 0 {
+			return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("view %s already exists", request.Name), "resource browser view already exists")
+		}
+	}
+	view, err := adapter.BuildResourceBrowserView(request)
+	if err != nil {
+		return nil, err
+	}
+	view.AuditLog = existing.AuditLog
+	view.UpdateAuditLog(request.UserId)
+	if err = impl.viewRepository.Update(view); err != nil {
+		impl.logger.Errorw("error in updating resource browser view", "id", request.Id, "err", err)
+		return nil, err
+	}
+	return request, nil
+}
+
+func (impl *ResourceSearchServiceImpl) GetView(id int, userId int32) (*bean.ResourceViewDto, error) {
+	view, err := impl.getView(id, userId)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.GetResourceViewDto(view)
+}
+
+func (impl *ResourceSearchServiceImpl) GetAllViews(userId int32) ([]*bean.ResourceViewDto, error) {
+	views, err := impl.viewRepository.FindAllByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	viewDtos := make([]*bean.ResourceViewDto, 0, len(views))
+	for _, view := range views {
+		viewDto, err := adapter.GetResourceViewDto(view)
+		if err != nil {
+			impl.logger.Errorw("error in parsing resource browser view query", "id", view.Id, "err", err)
+			return nil, err
+		}
+		viewDtos = append(viewDtos, viewDto)
+	}
+	return viewDtos, nil
+}
+
+func (impl *ResourceSearchServiceImpl) DeleteView(id int, userId int32) error {
+	view, err := impl.getView(id, userId)
+	if err != nil {
+		return err
+	}
+	view.Active = false
+	view.UpdateAuditLog(userId)
+	if err = impl.viewRepository.Update(view); err != nil {
+		impl.logger.Errorw("error in deleting resource browser view", "id", id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ResourceSearchServiceImpl) SearchView(ctx context.Context, token string, id int, userId int32,
+	validateResourceAccess ResourceAccessValidator) (*bean.ResourceSearchResponse, error) {
+	view, err := impl.GetView(id, userId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.SearchResources(ctx, token, view.Query, validateResourceAccess)
+}
+
+func (impl *ResourceSearchServiceImpl) getView(id int, userId int32) (*repository.ResourceBrowserView, error) {
+	view, err := impl.viewRepository.FindById(id, userId)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "view not found", "resource browser view not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in getting resource browser view", "id", id, "err", err)
+		return nil, err
+	}
+	return view, nil
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package resourceSearch
+
+import (
+	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceSearch/bean"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"testing"
+)
+
+func TestFilterRows(t *testing.T) {
+	rows := []map[string]interface{}{
+		{commonBean.K8sClusterResourceNameKey: "payments-api"},
+		{commonBean.K8sClusterResourceNameKey: "orders-api"},
+		{commonBean.K8sClusterResourceNameKey: "Payments-worker"},
+	}
+	t.Run("name filter is case insensitive", func(t *testing.T) {
+		filtered, truncated := filterRows(rows, "payments", 10)
+		assert.Len(t, filtered, 2)
+		assert.False(t, truncated)
+	})
+	t.Run("rows beyond the limit are truncated", func(t *testing.T) {
+		filtered, truncated := filterRows(rows, "", 2)
+		assert.Len(t, filtered, 2)
+		assert.True(t, truncated)
+	})
+	t.Run("exact limit is not truncated", func(t *testing.T) {
+		filtered, truncated := filterRows(rows, "", 3)
+		assert.Len(t, filtered, 3)
+		assert.False(t, truncated)
+	})
+}
+
+func TestGetUniqueClusterIds(t *testing.T) {
+	assert.Equal(t, []int{3, 1, 2}, getUniqueClusterIds([]int{3, 1, 3, 2, 1}))
+}
+
+func TestResourceSearchQueryValidate(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	assert.NoError(t, (&bean.ResourceSearchQuery{ClusterIds: []int{1}, GroupVersionKind: gvk, LabelSelector: "app=web", FieldSelector: "metadata.namespace=prod"}).Validate())
+	assert.Error(t, (&bean.ResourceSearchQuery{GroupVersionKind: gvk}).Validate())
+	assert.Error(t, (&bean.ResourceSearchQuery{ClusterIds: []int{1}, GroupVersionKind: schema.GroupVersionKind{Kind: "Pod"}}).Validate())
+	assert.Error(t, (&bean.ResourceSearchQuery{ClusterIds: []int{1}, GroupVersionKind: gvk, LabelSelector: "app in (web"}).Validate())
+	assert.Error(t, (&bean.ResourceSearchQuery{ClusterIds: []int{1}, GroupVersionKind: gvk, FieldSelector: "a==b==c"}).Validate())
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceSearch/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceSearch/repository"
+)
+
+func BuildResourceBrowserView(dto *bean.ResourceViewDto) (*repository.ResourceBrowserView, error) {
+	query, err := json.Marshal(dto.Query)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.ResourceBrowserView{
+		Id:          dto.Id,
+		Name:        dto.Name,
+		Description: dto.Description,
+		Query:       string(query),
+		Active:      true,
+	}, nil
+}
+
+func GetResourceViewDto(view *repository.ResourceBrowserView) (*bean.ResourceViewDto, error) {
+	query := &bean.ResourceSearchQuery{}
+	if err := json.Unmarshal([]byte(view.Query), query); err != nil {
+		return nil, err
+	}
+	return &bean.ResourceViewDto{
+		Id:          view.Id,
+		Name:        view.Name,
+		Description: view.Description,
+		Query:       query,
+		UserId:      view.CreatedBy,
+	}, nil
+}
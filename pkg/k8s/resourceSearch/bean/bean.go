@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bean
+
+import (
+	"errors"
+	"fmt"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	ClusterIdKey   = "clusterId"
+	ClusterNameKey = "clusterName"
+)
+
+// ResourceSearchQuery lists a GroupVersionKind across clusters, filtered by selectors and a name substring
+type ResourceSearchQuery struct {
+	ClusterIds       []int                   `json:"clusterIds" validate:"required,min=1"`
+	GroupVersionKind schema.GroupVersionKind `json:"groupVersionKind"`
+	// Namespace is ignored for cluster scoped resources, all namespaces are searched when empty
+	Namespace     string `json:"namespace,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+	FieldSelector string `json:"fieldSelector,omitempty"`
+	// NameContains is matched case insensitively against the resource name
+	NameContains string `json:"nameContains,omitempty"`
+}
+
+func (query *ResourceSearchQuery) Validate() error {
+	if len(query.ClusterIds) == 0 {
+		return errors.New("at least one cluster is required")
+	}
+	if len(query.GroupVersionKind.Kind) == 0 || len(query.GroupVersionKind.Version) == 0 {
+		return errors.New("kind and version are required")
+	}
+	if _, err := labels.Parse(query.LabelSelector); err != nil {
+		return fmt.Errorf("invalid label selector: %s", err.Error())
+	}
+	if _, err := fields.ParseSelector(query.FieldSelector); err != nil {
+		return fmt.Errorf("invalid field selector: %s", err.Error())
+	}
+	return nil
+}
+
+type ClusterSearchStatus string
+
+const (
+	ClusterSearchStatusSucceeded ClusterSearchStatus = "Succeeded"
+	ClusterSearchStatusFailed    ClusterSearchStatus = "Failed"
+)
+
+// ClusterSearchResult reports the outcome of the search in one cluster, a failed cluster does not fail the search
+type ClusterSearchResult struct {
+	ClusterId   int                 `json:"clusterId"`
+	ClusterName string              `json:"clusterName,omitempty"`
+	Status      ClusterSearchStatus `json:"status"`
+	Count       int                 `json:"count"`
+	// Truncated is set when the matching resources of the cluster exceed the per cluster limit
+	Truncated bool   `json:"truncated,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ResourceSearchResponse struct {
+	// Headers are the table columns of the first cluster searched successfully, prefixed with clusterName
+	Headers []string `json:"headers"`
+	// Data rows carry clusterId and clusterName along with the table columns
+	Data     []map[string]interface{} `json:"data"`
+	Clusters []*ClusterSearchResult   `json:"clusters"`
+}
+
+type ResourceViewDto struct {
+	Id          int                  `json:"id"`
+	Name        string               `json:"name" validate:"required,max=100"`
+	Description string               `json:"description,omitempty"`
+	Query       *ResourceSearchQuery `json:"query" validate:"required"`
+	UserId      int32                `json:"-"`
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// ResourceBrowserView is a named resource search query saved by a user
+type ResourceBrowserView struct {
+	tableName   struct{} `sql:"resource_browser_view" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	Name        string   `sql:"name,notnull"`
+	Description string   `sql:"description"`
+	Query       string   `sql:"query,notnull"`
+	Active      bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ResourceBrowserViewRepository interface {
+	Save(view *ResourceBrowserView) error
+	Update(view *ResourceBrowserView) error
+	// FindById returns the active view only if it was created by userId
+	FindById(id int, userId int32) (*ResourceBrowserView, error)
+	FindByName(name string, userId int32) (*ResourceBrowserView, error)
+	FindAllByUserId(userId int32) ([]*ResourceBrowserView, error)
+}
+
+type ResourceBrowserViewRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewResourceBrowserViewRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ResourceBrowserViewRepositoryImpl {
+	return &ResourceBrowserViewRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ResourceBrowserViewRepositoryImpl) Save(view *ResourceBrowserView) error {
+	return impl.dbConnection.Insert(view)
+}
+
+func (impl *ResourceBrowserViewRepositoryImpl) Update(view *ResourceBrowserView) error {
+	return impl.dbConnection.Update(view)
+}
+
+func (impl *ResourceBrowserViewRepositoryImpl) FindById(id int, userId int32) (*ResourceBrowserView, error) {
+	view := &ResourceBrowserView{}
+	err := impl.dbConnection.Model(view).
+		Where("id = ?", id).
+		Where("created_by = ?", userId).
+		Where("active = ?", true).
+		Select()
+	return view, err
+}
+
+func (impl *ResourceBrowserViewRepositoryImpl) FindByName(name string, userId int32) (*ResourceBrowserView, error) {
+	view := &ResourceBrowserView{}
+	err := impl.dbConnection.Model(view).
+		Where("name = ?", name).
+		Where("created_by = ?", userId).
+		Where("active = ?", true).
+		Select()
+	return view, err
+}
+
+func (impl *ResourceBrowserViewRepositoryImpl) FindAllByUserId(userId int32) ([]*ResourceBrowserView, error) {
+	var views []*ResourceBrowserView
+	err := impl.dbConnection.Model(&views).
+		Where("created_by = ?", userId).
+		Where("active = ?", true).
+		Order("name ASC").
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting resource browser views", "userId", userId, "err", err)
+		return nil, err
+	}
+	return views, nil
+}
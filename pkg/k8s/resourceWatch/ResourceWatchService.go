@@ -0,0 +1,347 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package resourceWatch
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env"
+	k8s2 "github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	k8sBean "github.com/devtron-labs/devtron/pkg/k8s/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceSearch"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceWatch/bean"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type ResourceWatchConfig struct {
+	IdleTimeoutSecs      int `env:"RESOURCE_WATCH_IDLE_TIMEOUT_SECS" envDefault:"120" description:"Time after the last subscriber of a watch leaves, after which its informer is stopped"`
+	SyncTimeoutSecs      int `env:"RESOURCE_WATCH_SYNC_TIMEOUT_SECS" envDefault:"30" description:"Time to wait for the initial list of a new watch"`
+	SubscriberBufferSize int `env:"RESOURCE_WATCH_SUBSCRIBER_BUFFER_SIZE" envDefault:"500" description:"Events buffered per subscriber, a subscriber falling further behind is disconnected"`
+	MaxWatches           int `env:"RESOURCE_WATCH_MAX_WATCHES" envDefault:"100" description:"Max informers running at once across all clusters"`
+}
+
+func GetResourceWatchConfig() (*ResourceWatchConfig, error) {
+	cfg := &ResourceWatchConfig{}
+	err := env.Parse(cfg)
+	return cfg, err
+}
+
+type ResourceWatchService interface {
+	// Subscribe streams the events of the resources matching the request, the existing resources are sent as ADDED events first.
+	// Subscribers of the same request share one informer. The returned channel is closed when ctx is done or the subscriber falls behind.
+	Subscribe(ctx context.Context, token string, request *bean.ResourceWatchRequest, validator resourceSearch.ResourceAccessValidator) (<-chan *bean.ResourceWatchEvent, error)
+}
+
+type ResourceWatchServiceImpl struct {
+	logger           *zap.SugaredLogger
+	k8sCommonService k8s.K8sCommonService
+	K8sUtil          *k8s2.K8sServiceImpl
+	config           *ResourceWatchConfig
+	watches          map[string]*sharedWatch
+	watchTargetGroup singleflight.Group
+	mux              sync.Mutex
+}
+
+func NewResourceWatchServiceImpl(logger *zap.SugaredLogger,
+	k8sCommonService k8s.K8sCommonService,
+	K8sUtil *k8s2.K8sServiceImpl,
+	config *ResourceWatchConfig) *ResourceWatchServiceImpl {
+	return &ResourceWatchServiceImpl{
+		logger:           logger,
+		k8sCommonService: k8sCommonService,
+		K8sUtil:          K8sUtil,
+		config:           config,
+		watches:          make(map[string]*sharedWatch),
+	}
+}
+
+// sharedWatch is an informer shared by the subscribers of one request, reference counted by subscribers
+type sharedWatch struct {
+	key         string
+	clusterName string
+	request     *bean.ResourceWatchRequest
+	informer    cache.SharedIndexInformer
+	stopCh      chan struct{}
+	subscribers int
+	idleTimer   *time.Timer
+}
+
+// subscriber receives the events of a shared watch allowed by its rbac, events are dropped once it is closed
+type subscriber struct {
+	token     string
+	watch     *sharedWatch
+	validator resourceSearch.ResourceAccessValidator
+	events    chan *bean.ResourceWatchEvent
+	closed    bool
+	lagged    chan struct{}
+	mux       sync.Mutex
+}
+
+func (impl *ResourceWatchServiceImpl) Subscribe(ctx context.Context, token string, request *bean.ResourceWatchRequest, validator resourceSearch.ResourceAccessValidator) (<-chan *bean.ResourceWatchEvent, error) {
+	if err := request.Validate(); err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	sw, err := impl.acquire(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	syncCtx, cancel := context.WithTimeout(ctx, time.Duration(impl.config.SyncTimeoutSecs)*time.Second)
+	defer cancel()
+	if !cache.WaitForCacheSync(syncCtx.Done(), sw.informer.HasSynced) {
+		impl.release(sw, true)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		impl.logger.Errorw("timed out waiting for the resource watch to sync", "key", sw.key)
+		return nil, util.NewApiError(http.StatusGatewayTimeout, "could not list the resources of the cluster in time", "resource watch sync timed out")
+	}
+	sub := &subscriber{
+		token:     token,
+		watch:     sw,
+		validator: validator,
+		events:    make(chan *bean.ResourceWatchEvent, impl.config.SubscriberBufferSize),
+		lagged:    make(chan struct{}),
+	}
+	registration, err := sw.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			sub.send(bean.ResourceWatchEventAdded, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			sub.send(bean.ResourceWatchEventModified, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			sub.send(bean.ResourceWatchEventDeleted, obj)
+		},
+	})
+	if err != nil {
+		impl.logger.Errorw("error in adding handler to resource watch", "key", sw.key, "err", err)
+		impl.release(sw, false)
+		return nil, err
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-sub.lagged:
+			impl.logger.Warnw("resource watch subscriber fell behind, disconnecting", "key", sw.key)
+		}
+		if err := sw.informer.RemoveEventHandler(registration); err != nil {
+			impl.logger.Errorw("error in removing handler from resource watch", "key", sw.key, "err", err)
+		}
+		sub.close()
+		impl.release(sw, false)
+	}()
+	return sub.events, nil
+}
+
+// acquire returns the running watch of the request, starting an informer if there is none, and takes a reference on it
+func (impl *ResourceWatchServiceImpl) acquire(ctx context.Context, request *bean.ResourceWatchRequest) (*sharedWatch, error) {
+	key := request.GetKey()
+	if sw, err := impl.acquireRunningWatch(key); sw != nil || err != nil {
+		return sw, err
+	}
+	// cluster calls are made outside impl.mux so that a slow cluster does not block watches on other clusters,
+	// concurrent requests for the same key share one lookup
+	result, err, _ := impl.watchTargetGroup.Do(key, func() (interface{}, error) {
+		return impl.getWatchTarget(ctx, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+	target := result.(*watchTarget)
+	impl.mux.Lock()
+	defer impl.mux.Unlock()
+	if sw, ok := impl.watches[key]; ok {
+		impl.addSubscriber(sw)
+		return sw, nil
+	}
+	if len(impl.watches) >= impl.config.MaxWatches {
+		return nil, util.NewApiError(http.StatusTooManyRequests, "too many resource watches running, please retry later", "max resource watches reached")
+	}
+	labelSelector := request.LabelSelector
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = labelSelector
+			return target.resourceIf.List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = labelSelector
+			return target.resourceIf.Watch(context.Background(), options)
+		},
+	}
+	sw := &sharedWatch{
+		key:         key,
+		clusterName: target.clusterName,
+		request:     request,
+		informer:    cache.NewSharedIndexInformer(listWatch, &unstructured.Unstructured{}, 0, cache.Indexers{}),
+		stopCh:      make(chan struct{}),
+		subscribers: 1,
+	}
+	err = sw.informer.SetWatchErrorHandler(func(r *cache.Reflector, err error) {
+		impl.logger.Warnw("error in resource watch", "key", key, "err", err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	go sw.informer.Run(sw.stopCh)
+	impl.watches[key] = sw
+	impl.logger.Infow("started resource watch", "key", key)
+	return sw, nil
+}
+
+// acquireRunningWatch subscribes to the running watch of the key, if any
+func (impl *ResourceWatchServiceImpl) acquireRunningWatch(key string) (*sharedWatch, error) {
+	impl.mux.Lock()
+	defer impl.mux.Unlock()
+	if sw, ok := impl.watches[key]; ok {
+		impl.addSubscriber(sw)
+		return sw, nil
+	}
+	if len(impl.watches) >= impl.config.MaxWatches {
+		return nil, util.NewApiError(http.StatusTooManyRequests, "too many resource watches running, please retry later", "max resource watches reached")
+	}
+	return nil, nil
+}
+
+// addSubscriber adds a reference on the watch and keeps it from being stopped, callers hold impl.mux
+func (impl *ResourceWatchServiceImpl) addSubscriber(sw *sharedWatch) {
+	if sw.idleTimer != nil {
+		sw.idleTimer.Stop()
+		sw.idleTimer = nil
+	}
+	sw.subscribers++
+}
+
+// watchTarget is the resource interface a watch lists and watches
+type watchTarget struct {
+	resourceIf  dynamic.ResourceInterface
+	clusterName string
+}
+
+func (impl *ResourceWatchServiceImpl) getWatchTarget(ctx context.Context, request *bean.ResourceWatchRequest) (*watchTarget, error) {
+	restConfig, err, cluster := impl.k8sCommonService.GetRestConfigByClusterId(ctx, request.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting rest config by cluster id", "clusterId", request.ClusterId, "err", err)
+		return nil, err
+	}
+	resourceIf, namespaced, err := impl.K8sUtil.GetResourceIf(restConfig, request.GroupVersionKind)
+	if err != nil {
+		impl.logger.Errorw("error in getting resource interface", "gvk", request.GroupVersionKind, "err", err)
+		return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("kind %s is not served by the cluster", request.GroupVersionKind.Kind), err.Error())
+	}
+	target := &watchTarget{resourceIf: resourceIf, clusterName: cluster.ClusterName}
+	if namespaced && len(request.Namespace) > 0 {
+		target.resourceIf = resourceIf.Namespace(request.Namespace)
+	}
+	return target, nil
+}
+
+// release drops a reference on the watch, the informer is stopped once it has been without subscribers for the idle timeout
+func (impl *ResourceWatchServiceImpl) release(sw *sharedWatch, stopNow bool) {
+	impl.mux.Lock()
+	defer impl.mux.Unlock()
+	sw.subscribers--
+	if sw.subscribers > 0 {
+		return
+	}
+	if stopNow || impl.config.IdleTimeoutSecs <= 0 {
+		impl.stopWatch(sw)
+		return
+	}
+	sw.idleTimer = time.AfterFunc(time.Duration(impl.config.IdleTimeoutSecs)*time.Second, func() {
+		impl.mux.Lock()
+		defer impl.mux.Unlock()
+		if sw.subscribers == 0 {
+			impl.stopWatch(sw)
+		}
+	})
+}
+
+// stopWatch stops the informer of the watch, callers hold impl.mux
+func (impl *ResourceWatchServiceImpl) stopWatch(sw *sharedWatch) {
+	if impl.watches[sw.key] != sw {
+		return
+	}
+	delete(impl.watches, sw.key)
+	close(sw.stopCh)
+	impl.logger.Infow("stopped idle resource watch", "key", sw.key)
+}
+
+func (sub *subscriber) send(eventType bean.ResourceWatchEventType, obj interface{}) {
+	if deleted, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = deleted.Obj
+	}
+	resource, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	if !sub.isAllowed(resource) {
+		return
+	}
+	event := &bean.ResourceWatchEvent{
+		Type:            eventType,
+		Name:            resource.GetName(),
+		Namespace:       resource.GetNamespace(),
+		ResourceVersion: resource.GetResourceVersion(),
+	}
+	if eventType != bean.ResourceWatchEventDeleted {
+		event.Object = resource.Object
+	}
+	sub.mux.Lock()
+	defer sub.mux.Unlock()
+	if sub.closed {
+		return
+	}
+	select {
+	case sub.events <- event:
+	default:
+		// the informer must never block on a slow subscriber
+		sub.closed = true
+		close(sub.events)
+		close(sub.lagged)
+	}
+}
+
+func (sub *subscriber) isAllowed(resource *unstructured.Unstructured) bool {
+	resourceIdentifier := k8s2.ResourceIdentifier{
+		Name:             resource.GetName(),
+		Namespace:        resource.GetNamespace(),
+		GroupVersionKind: sub.watch.request.GroupVersionKind,
+	}
+	request := k8sBean.ResourceRequestBean{ClusterId: sub.watch.request.ClusterId, K8sRequest: &k8s2.K8sRequestBean{ResourceIdentifier: resourceIdentifier}}
+	return sub.validator(sub.token, sub.watch.clusterName, request, casbin.ActionGet)
+}
+
+func (sub *subscriber) close() {
+	sub.mux.Lock()
+	defer sub.mux.Unlock()
+	if !sub.closed {
+		sub.closed = true
+		close(sub.events)
+	}
+}
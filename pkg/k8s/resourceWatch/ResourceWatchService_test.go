@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package resourceWatch
+
+import (
+	k8s2 "github.com/devtron-labs/common-lib/utils/k8s"
+	k8sBean "github.com/devtron-labs/devtron/pkg/k8s/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/resourceWatch/bean"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"testing"
+)
+
+func newTestSubscriber(bufferSize int, allowedNamespace string) *subscriber {
+	return &subscriber{
+		token: "token",
+		watch: &sharedWatch{
+			clusterName: "default_cluster",
+			request:     &bean.ResourceWatchRequest{ClusterId: 1, GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Pod"}},
+		},
+		validator: func(token, clusterName string, request k8sBean.ResourceRequestBean, casbinAction string) bool {
+			return request.K8sRequest.ResourceIdentifier.Namespace == allowedNamespace
+		},
+		events: make(chan *bean.ResourceWatchEvent, bufferSize),
+		lagged: make(chan struct{}),
+	}
+}
+
+func newPod(name, namespace string) *unstructured.Unstructured {
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "Pod"}}
+	pod.SetName(name)
+	pod.SetNamespace(namespace)
+	return pod
+}
+
+func TestSubscriberSend(t *testing.T) {
+	t.Run("events are filtered by rbac", func(t *testing.T) {
+		sub := newTestSubscriber(10, "prod")
+		sub.send(bean.ResourceWatchEventAdded, newPod("web", "prod"))
+		sub.send(bean.ResourceWatchEventAdded, newPod("web", "staging"))
+		sub.send(bean.ResourceWatchEventDeleted, cache.DeletedFinalStateUnknown{Key: "prod/api", Obj: newPod("api", "prod")})
+		assert.Len(t, sub.events, 2)
+		added := <-sub.events
+		assert.Equal(t, bean.ResourceWatchEventAdded, added.Type)
+		assert.NotNil(t, added.Object)
+		deleted := <-sub.events
+		assert.Equal(t, bean.ResourceWatchEventDeleted, deleted.Type)
+		assert.Equal(t, "api", deleted.Name)
+		assert.Nil(t, deleted.Object)
+	})
+	t.Run("lagging subscriber is closed", func(t *testing.T) {
+		sub := newTestSubscriber(1, "prod")
+		sub.send(bean.ResourceWatchEventAdded, newPod("web", "prod"))
+		sub.send(bean.ResourceWatchEventAdded, newPod("api", "prod"))
+		_, open := <-sub.lagged
+		assert.False(t, open)
+		<-sub.events
+		_, open = <-sub.events
+		assert.False(t, open)
+		// events after close are dropped and close is idempotent
+		sub.send(bean.ResourceWatchEventAdded, newPod("db", "prod"))
+		sub.close()
+	})
+}
+
+func TestRelease(t *testing.T) {
+	impl := NewResourceWatchServiceImpl(zap.NewNop().Sugar(), nil, &k8s2.K8sServiceImpl{}, &ResourceWatchConfig{IdleTimeoutSecs: 0})
+	sw := &sharedWatch{key: "1/v1, Kind=Pod//", stopCh: make(chan struct{}), subscribers: 2}
+	impl.watches[sw.key] = sw
+	impl.release(sw, false)
+	assert.Contains(t, impl.watches, sw.key)
+	impl.release(sw, false)
+	assert.NotContains(t, impl.watches, sw.key)
+	_, open := <-sw.stopCh
+	assert.False(t, open)
+}
+
+func TestResourceWatchRequest(t *testing.T) {
+	request := &bean.ResourceWatchRequest{ClusterId: 1, GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, Namespace: "prod", LabelSelector: "app=web"}
+	assert.NoError(t, request.Validate())
+	assert.Equal(t, "1/apps/v1, Kind=Deployment/prod/app=web", request.GetKey())
+	assert.Error(t, (&bean.ResourceWatchRequest{GroupVersionKind: request.GroupVersionKind}).Validate())
+	assert.Error(t, (&bean.ResourceWatchRequest{ClusterId: 1, GroupVersionKind: schema.GroupVersionKind{Kind: "Pod"}}).Validate())
+	assert.Error(t, (&bean.ResourceWatchRequest{ClusterId: 1, GroupVersionKind: request.GroupVersionKind, LabelSelector: "app in (web"}).Validate())
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bean
+
+import (
+	"errors"
+	"fmt"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceWatchRequest selects the resources streamed to a subscriber, subscribers with the same request share one informer
+type ResourceWatchRequest struct {
+	ClusterId        int                     `json:"clusterId"`
+	GroupVersionKind schema.GroupVersionKind `json:"groupVersionKind"`
+	// Namespace is ignored for cluster scoped kinds, all namespaces are watched when empty
+	Namespace     string `json:"namespace,omitempty"`
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+func (request *ResourceWatchRequest) Validate() error {
+	if request.ClusterId <= 0 {
+		return errors.New("cluster is required")
+	}
+	if len(request.GroupVersionKind.Kind) == 0 || len(request.GroupVersionKind.Version) == 0 {
+		return errors.New("kind and version are required")
+	}
+	if _, err := labels.Parse(request.LabelSelector); err != nil {
+		return fmt.Errorf("invalid label selector: %s", err.Error())
+	}
+	return nil
+}
+
+// GetKey returns the key of the shared informer serving the request
+func (request *ResourceWatchRequest) GetKey() string {
+	return fmt.Sprintf("%d/%s/%s/%s", request.ClusterId, request.GroupVersionKind.String(), request.Namespace, request.LabelSelector)
+}
+
+type ResourceWatchEventType string
+
+const (
+	ResourceWatchEventAdded    ResourceWatchEventType = "ADDED"
+	ResourceWatchEventModified ResourceWatchEventType = "MODIFIED"
+	ResourceWatchEventDeleted  ResourceWatchEventType = "DELETED"
+)
+
+type ResourceWatchEvent struct {
+	Type            ResourceWatchEventType `json:"type"`
+	Name            string                 `json:"name"`
+	Namespace       string                 `json:"namespace,omitempty"`
+	ResourceVersion string                 `json:"resourceVersion,omitempty"`
+	// Object is the manifest of the resource, it is not set for DELETED events
+	Object map[string]interface{} `json:"object,omitempty"`
+}
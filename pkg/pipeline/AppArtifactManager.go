@@ -22,6 +22,8 @@ import (
 	"github.com/devtron-labs/devtron/pkg/build/artifacts/imageTagging"
 	"github.com/devtron-labs/devtron/pkg/build/pipeline"
 	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/artifactPromotion/read"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning"
 	"sort"
 	"strings"
 
@@ -48,18 +50,20 @@ type AppArtifactManager interface {
 }
 
 type AppArtifactManagerImpl struct {
-	logger                  *zap.SugaredLogger
-	cdWorkflowRepository    pipelineConfig.CdWorkflowRepository
-	userService             user.UserService
-	imageTaggingService     imageTagging.ImageTaggingService
-	ciArtifactRepository    repository.CiArtifactRepository
-	ciWorkflowRepository    pipelineConfig.CiWorkflowRepository
-	pipelineStageService    PipelineStageService
-	config                  *types.CdConfig
-	cdPipelineConfigService CdPipelineConfigService
-	dockerArtifactRegistry  dockerArtifactStoreRegistry.DockerArtifactStoreRepository
-	CiPipelineRepository    pipelineConfig.CiPipelineRepository
-	ciTemplateService       pipeline.CiTemplateReadService
+	logger                       *zap.SugaredLogger
+	cdWorkflowRepository         pipelineConfig.CdWorkflowRepository
+	userService                  user.UserService
+	imageTaggingService          imageTagging.ImageTaggingService
+	ciArtifactRepository         repository.CiArtifactRepository
+	ciWorkflowRepository         pipelineConfig.CiWorkflowRepository
+	pipelineStageService         PipelineStageService
+	config                       *types.CdConfig
+	cdPipelineConfigService      CdPipelineConfigService
+	dockerArtifactRegistry       dockerArtifactStoreRegistry.DockerArtifactStoreRepository
+	CiPipelineRepository         pipelineConfig.CiPipelineRepository
+	ciTemplateService            pipeline.CiTemplateReadService
+	imageSigningService          imageSigning.ImageSigningService
+	artifactPromotionReadService read.ArtifactPromotionReadService
 }
 
 func NewAppArtifactManagerImpl(
@@ -73,24 +77,28 @@ func NewAppArtifactManagerImpl(
 	cdPipelineConfigService CdPipelineConfigService,
 	dockerArtifactRegistry dockerArtifactStoreRegistry.DockerArtifactStoreRepository,
 	CiPipelineRepository pipelineConfig.CiPipelineRepository,
-	ciTemplateService pipeline.CiTemplateReadService) *AppArtifactManagerImpl {
+	ciTemplateService pipeline.CiTemplateReadService,
+	imageSigningService imageSigning.ImageSigningService,
+	artifactPromotionReadService read.ArtifactPromotionReadService) *AppArtifactManagerImpl {
 	cdConfig, err := types.GetCdConfig()
 	if err != nil {
 		return nil
 	}
 	return &AppArtifactManagerImpl{
-		logger:                  logger,
-		cdWorkflowRepository:    cdWorkflowRepository,
-		userService:             userService,
-		imageTaggingService:     imageTaggingService,
-		ciArtifactRepository:    ciArtifactRepository,
-		ciWorkflowRepository:    ciWorkflowRepository,
-		cdPipelineConfigService: cdPipelineConfigService,
-		pipelineStageService:    pipelineStageService,
-		config:                  cdConfig,
-		dockerArtifactRegistry:  dockerArtifactRegistry,
-		CiPipelineRepository:    CiPipelineRepository,
-		ciTemplateService:       ciTemplateService,
+		logger:                       logger,
+		cdWorkflowRepository:         cdWorkflowRepository,
+		userService:                  userService,
+		imageTaggingService:          imageTaggingService,
+		ciArtifactRepository:         ciArtifactRepository,
+		ciWorkflowRepository:         ciWorkflowRepository,
+		cdPipelineConfigService:      cdPipelineConfigService,
+		pipelineStageService:         pipelineStageService,
+		config:                       cdConfig,
+		dockerArtifactRegistry:       dockerArtifactRegistry,
+		CiPipelineRepository:         CiPipelineRepository,
+		ciTemplateService:            ciTemplateService,
+		imageSigningService:          imageSigningService,
+		artifactPromotionReadService: artifactPromotionReadService,
 	}
 }
 
@@ -448,6 +456,16 @@ func (impl *AppArtifactManagerImpl) setAdditionalDataInArtifacts(ciArtifacts []b
 		return ciArtifacts, err
 	}
 
+	artifactIdDigestMap := make(map[int]string, len(ciArtifacts))
+	for _, artifact := range ciArtifacts {
+		artifactIdDigestMap[artifact.Id] = artifact.ImageDigest
+	}
+	signatureSummaryMap, err := impl.imageSigningService.GetSignatureSummaryForArtifacts(artifactIdDigestMap, pipeline.Id)
+	if err != nil {
+		impl.logger.Errorw("error in getting image signature summary for artifacts", "err", err, "pipelineId", pipeline.Id, "artifactIds", artifactIds)
+		return ciArtifacts, err
+	}
+
 	for i, _ := range ciArtifacts {
 		imageTaggingResp := imageTagsDataMap[ciArtifacts[i].Id]
 		if imageTaggingResp != nil {
@@ -456,6 +474,7 @@ func (impl *AppArtifactManagerImpl) setAdditionalDataInArtifacts(ciArtifacts []b
 		if imageCommentResp := imageCommentsDataMap[ciArtifacts[i].Id]; imageCommentResp != nil {
 			ciArtifacts[i].ImageComment = imageCommentResp
 		}
+		ciArtifacts[i].ImageSignature = signatureSummaryMap[ciArtifacts[i].Id]
 		var dockerRegistryId string
 		if ciArtifacts[i].DataSource == repository.POST_CI || ciArtifacts[i].DataSource == repository.PRE_CD || ciArtifacts[i].DataSource == repository.POST_CD {
 			if ciArtifacts[i].CredentialsSourceType == repository.GLOBAL_CONTAINER_REGISTRY {
@@ -596,7 +615,8 @@ func (impl *AppArtifactManagerImpl) BuildArtifactsList(listingFilterOpts *bean.A
 			currentRunningArtifactBean.CiWorkflowId = *currentRunningArtifact.WorkflowId
 		}
 	}
-	//2) get artifact list limited by filterOptions
+	//2) get artifact list limited by filterOptions, artifacts promoted to this pipeline are deployable irrespective of the parent
+	listingFilterOpts.IncludePromotedArtifacts = listingFilterOpts.StageType == bean.CD_WORKFLOW_TYPE_DEPLOY
 	if listingFilterOpts.ParentStageType == bean.CI_WORKFLOW_TYPE || listingFilterOpts.ParentStageType == bean.WEBHOOK_WORKFLOW_TYPE {
 		ciArtifacts, totalCount, err = impl.BuildArtifactsForCIParentV2(listingFilterOpts)
 		if err != nil {
@@ -616,6 +636,15 @@ func (impl *AppArtifactManagerImpl) BuildArtifactsList(listingFilterOpts *bean.A
 		}
 	}
 
+	//3) mark the listed artifacts which were promoted to this pipeline
+	if listingFilterOpts.IncludePromotedArtifacts {
+		err = impl.setPromotionMetadata(listingFilterOpts.PipelineId, ciArtifacts, currentRunningArtifactBean)
+		if err != nil {
+			impl.logger.Errorw("error in getting promoted artifacts", "pipelineId", listingFilterOpts.PipelineId, "err", err)
+			return ciArtifacts, 0, "", totalCount, err
+		}
+	}
+
 	//if no artifact deployed skip adding currentRunningArtifactBean in ciArtifacts arr
 	if currentRunningArtifactBean != nil {
 		searchString := listingFilterOpts.SearchString[1 : len(listingFilterOpts.SearchString)-1]
@@ -628,6 +657,20 @@ func (impl *AppArtifactManagerImpl) BuildArtifactsList(listingFilterOpts *bean.A
 	return ciArtifacts, currentRunningArtifactId, currentRunningWorkflowStatus, totalCount, nil
 }
 
+func (impl *AppArtifactManagerImpl) setPromotionMetadata(pipelineId int, ciArtifacts []*bean2.CiArtifactBean, currentRunningArtifactBean *bean2.CiArtifactBean) error {
+	promotedArtifacts, err := impl.artifactPromotionReadService.GetPromotedArtifactsForPipeline(pipelineId)
+	if err != nil || len(promotedArtifacts) == 0 {
+		return err
+	}
+	if currentRunningArtifactBean != nil {
+		currentRunningArtifactBean.PromotionMetadata = promotedArtifacts[currentRunningArtifactBean.Id]
+	}
+	for _, ciArtifact := range ciArtifacts {
+		ciArtifact.PromotionMetadata = promotedArtifacts[ciArtifact.Id]
+	}
+	return nil
+}
+
 func (impl *AppArtifactManagerImpl) BuildArtifactsForCdStageV2(listingFilterOpts *bean.ArtifactsListFilterOptions) ([]*bean2.CiArtifactBean, int, error) {
 	cdArtifacts, totalCount, err := impl.ciArtifactRepository.FindArtifactByListFilter(listingFilterOpts)
 	if err != nil {
@@ -46,6 +46,7 @@ import (
 	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline/types"
 	resourceGroup2 "github.com/devtron-labs/devtron/pkg/resourceGroup"
+	testReportRead "github.com/devtron-labs/devtron/pkg/testReport/read"
 	globalUtil "github.com/devtron-labs/devtron/util"
 	"github.com/devtron-labs/devtron/util/rbac"
 	"github.com/go-pg/pg"
@@ -89,6 +90,7 @@ type CdHandlerImpl struct {
 	deploymentConfigService      common2.DeploymentConfigService
 	workflowStageStatusService   workflowStatus.WorkFlowStageStatusService
 	cdWorkflowRunnerService      cd.CdWorkflowRunnerService
+	testReportReadService        testReportRead.TestReportReadService
 }
 
 func NewCdHandlerImpl(Logger *zap.SugaredLogger, userService user.UserService,
@@ -103,6 +105,7 @@ func NewCdHandlerImpl(Logger *zap.SugaredLogger, userService user.UserService,
 	deploymentConfigService common2.DeploymentConfigService,
 	workflowStageStatusService workflowStatus.WorkFlowStageStatusService,
 	cdWorkflowRunnerService cd.CdWorkflowRunnerService,
+	testReportReadService testReportRead.TestReportReadService,
 ) *CdHandlerImpl {
 	cdh := &CdHandlerImpl{
 		Logger:                       Logger,
@@ -121,6 +124,7 @@ func NewCdHandlerImpl(Logger *zap.SugaredLogger, userService user.UserService,
 		deploymentConfigService:      deploymentConfigService,
 		workflowStageStatusService:   workflowStageStatusService,
 		cdWorkflowRunnerService:      cdWorkflowRunnerService,
+		testReportReadService:        testReportReadService,
 	}
 	config, err := types.GetCdConfig()
 	if err != nil {
@@ -384,6 +388,20 @@ func (impl *CdHandlerImpl) GetCdBuildHistory(appId int, environmentId int, pipel
 			}
 		}
 	}
+
+	// test reports are only ingested for pre/post cd stages
+	prePostWfrIds := make([]int, 0, len(wfIdToWfTypeMap))
+	for wfrId := range wfIdToWfTypeMap {
+		prePostWfrIds = append(prePostWfrIds, wfrId)
+	}
+	testReportSummaries, err := impl.testReportReadService.GetSummariesByCdWorkflowRunnerIds(prePostWfrIds)
+	if err != nil {
+		impl.Logger.Errorw("error in fetching test report summaries", "err", err, "wfrIds", prePostWfrIds)
+		return cdWorkflowArtifact, err
+	}
+	for i, item := range cdWorkflowArtifact {
+		cdWorkflowArtifact[i].TestReportSummary = testReportSummaries[item.Id]
+	}
 	return cdWorkflowArtifact, nil
 }
 
@@ -519,6 +537,8 @@ func (impl *CdHandlerImpl) converterWFR(wfr pipelineConfig.CdWorkflowRunner) pip
 		workflow.IsArtifactUploaded = isArtifactUploaded
 		workflow.BlobStorageEnabled = wfr.BlobStorageEnabled
 		workflow.RefCdWorkflowRunnerId = wfr.RefCdWorkflowRunnerId
+		workflow.PullRequestUrl = wfr.PullRequestUrl
+		workflow.PullRequestState = wfr.PullRequestState
 	}
 	return workflow
 }
@@ -49,6 +49,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/pipeline/executors"
 	"github.com/devtron-labs/devtron/pkg/pipeline/types"
 	"github.com/devtron-labs/devtron/pkg/resourceGroup"
+	testReportRead "github.com/devtron-labs/devtron/pkg/testReport/read"
 	"github.com/devtron-labs/devtron/util/rbac"
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
@@ -97,6 +98,7 @@ type CiHandlerImpl struct {
 	config                       *types.CiConfig
 	k8sCommonService             k8sPkg.K8sCommonService
 	workFlowStageStatusService   workflowStatus.WorkFlowStageStatusService
+	testReportReadService        testReportRead.TestReportReadService
 }
 
 func NewCiHandlerImpl(Logger *zap.SugaredLogger, ciService CiService, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository, gitSensorClient gitSensor.Client, ciWorkflowRepository pipelineConfig.CiWorkflowRepository,
@@ -104,6 +106,7 @@ func NewCiHandlerImpl(Logger *zap.SugaredLogger, ciService CiService, ciPipeline
 	appListingRepository repository.AppListingRepository, cdPipelineRepository pipelineConfig.PipelineRepository, enforcerUtil rbac.EnforcerUtil, resourceGroupService resourceGroup.ResourceGroupService, envRepository repository2.EnvironmentRepository,
 	imageTaggingService imageTagging.ImageTaggingService, k8sCommonService k8sPkg.K8sCommonService, appWorkflowRepository appWorkflow.AppWorkflowRepository, customTagService CustomTagService,
 	workFlowStageStatusService workflowStatus.WorkFlowStageStatusService,
+	testReportReadService testReportRead.TestReportReadService,
 ) *CiHandlerImpl {
 	cih := &CiHandlerImpl{
 		Logger:                       Logger,
@@ -126,6 +129,7 @@ func NewCiHandlerImpl(Logger *zap.SugaredLogger, ciService CiService, ciPipeline
 		appWorkflowRepository:        appWorkflowRepository,
 		k8sCommonService:             k8sCommonService,
 		workFlowStageStatusService:   workFlowStageStatusService,
+		testReportReadService:        testReportReadService,
 	}
 	config, err := types.GetCiConfig()
 	if err != nil {
@@ -342,6 +346,12 @@ func (impl *CiHandlerImpl) GetBuildHistory(pipelineId int, appId int, offset int
 		return nil, err
 	}
 
+	testReportSummaries, err := impl.testReportReadService.GetSummariesByCiWorkflowIds(workflowIds)
+	if err != nil {
+		impl.Logger.Errorw("error in fetching test report summaries", "err", err, "workflowIds", workflowIds)
+		return nil, err
+	}
+
 	// this map contains artifactId -> imageComment of that artifact
 	imageCommetnsDataMap, err := impl.imageTaggingService.GetImageCommentsDataMapByArtifactIds(artifactIds)
 	if err != nil {
@@ -382,6 +392,7 @@ func (impl *CiHandlerImpl) GetBuildHistory(pipelineId int, appId int, offset int
 			PodName:                w.PodName,
 			TargetPlatforms:        utils.ConvertTargetPlatformStringToObject(w.TargetPlatforms),
 			WorkflowExecutionStage: impl.workFlowStageStatusService.ConvertDBWorkflowStageToMap(allWfStagesDetail, w.Id, w.Status, w.PodStatus, w.Message, bean2.CI_WORKFLOW_TYPE.String(), w.StartedOn, w.FinishedOn),
+			TestReportSummary:      testReportSummaries[w.Id],
 		}
 
 		if w.Message == pipelineConfigBean.ImageTagUnavailableMessage {
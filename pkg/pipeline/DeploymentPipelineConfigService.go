@@ -61,6 +61,7 @@ import (
 	commonBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/common/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/monorepo"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/validation"
 	validationBean "github.com/devtron-labs/devtron/pkg/deployment/gitOps/validation/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deployedAppMetrics"
@@ -208,6 +209,7 @@ type CdPipelineConfigServiceImpl struct {
 	helmAppReadService                read4.HelmAppReadService
 	K8sUtil                           *k8s.K8sServiceImpl
 	fluxCDDeploymentService           fluxcd.DeploymentService
+	gitOpsMonorepoService             monorepo.GitOpsMonorepoService
 }
 
 func NewCdPipelineConfigServiceImpl(logger *zap.SugaredLogger, pipelineRepository pipelineConfig.PipelineRepository,
@@ -243,7 +245,8 @@ func NewCdPipelineConfigServiceImpl(logger *zap.SugaredLogger, pipelineRepositor
 	chartReadService read3.ChartReadService,
 	helmAppReadService read4.HelmAppReadService,
 	K8sUtil *k8s.K8sServiceImpl,
-	fluxCDDeploymentService fluxcd.DeploymentService) *CdPipelineConfigServiceImpl {
+	fluxCDDeploymentService fluxcd.DeploymentService,
+	gitOpsMonorepoService monorepo.GitOpsMonorepoService) *CdPipelineConfigServiceImpl {
 	return &CdPipelineConfigServiceImpl{
 		logger:                            logger,
 		pipelineRepository:                pipelineRepository,
@@ -291,6 +294,7 @@ func NewCdPipelineConfigServiceImpl(logger *zap.SugaredLogger, pipelineRepositor
 		helmAppReadService:                helmAppReadService,
 		K8sUtil:                           K8sUtil,
 		fluxCDDeploymentService:           fluxCDDeploymentService,
+		gitOpsMonorepoService:             gitOpsMonorepoService,
 	}
 }
 
@@ -643,6 +647,11 @@ func (impl *CdPipelineConfigServiceImpl) CreateCdPipelines(pipelineCreateRequest
 			if releaseConfig != nil && releaseConfig.ArgoCDSpec.Spec.Source != nil {
 				envDeploymentConfig = envDeploymentConfig.SetRepoURL(releaseConfig.ArgoCDSpec.Spec.Source.RepoURL) //for backward compatibility
 			}
+			err = impl.gitOpsMonorepoService.ApplyPathPrefix(envDeploymentConfig)
+			if err != nil {
+				impl.logger.Errorw("error in applying gitOps monorepo path prefix", "appId", app.Id, "envId", pipeline.EnvironmentId, "err", err)
+				return nil, err
+			}
 			envDeploymentConfig, err = impl.deploymentConfigService.CreateOrUpdateConfig(nil, envDeploymentConfig, pipelineCreateRequest.UserId)
 			if err != nil {
 				impl.logger.Errorw("error in fetching creating env config", "appId", app.Id, "envId", pipeline.EnvironmentId, "err", err)
@@ -28,7 +28,9 @@ import (
 	"github.com/devtron-labs/devtron/pkg/pipeline/helper"
 	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
 	"github.com/devtron-labs/devtron/pkg/plugin"
+	pluginBean "github.com/devtron-labs/devtron/pkg/plugin/bean"
 	repository2 "github.com/devtron-labs/devtron/pkg/plugin/repository"
+	pluginUtils "github.com/devtron-labs/devtron/pkg/plugin/utils"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/pkg/variables"
@@ -37,6 +39,7 @@ import (
 	"github.com/go-pg/pg"
 	"go.uber.org/zap"
 	"net/http"
+	"slices"
 	"time"
 )
 
@@ -243,6 +246,9 @@ func (impl *PipelineStageServiceImpl) BuildPipelineStageDataDeepCopy(pipelineSta
 			Index:                    step.Index,
 			Description:              step.Description,
 			OutputDirectoryPath:      step.OutputDirectoryPath,
+			TestReportPaths:          step.TestReportPaths,
+			CoverageReportPaths:      step.CoverageReportPaths,
+			CoverageReportFormat:     step.CoverageReportFormat,
 			StepType:                 step.StepType,
 			TriggerIfParentStageFail: step.TriggerIfParentStageFail,
 		}
@@ -333,7 +339,8 @@ func (impl *PipelineStageServiceImpl) BuildInlineStepDataDeepCopy(step *reposito
 
 func (impl *PipelineStageServiceImpl) BuildRefPluginStepDataDeepCopy(step *repository.PipelineStageStep) (*bean.RefPluginStepDetailDto, error) {
 	refPluginStepDetail := &bean.RefPluginStepDetailDto{
-		PluginId: step.RefPluginId,
+		PluginId:                step.RefPluginId,
+		PluginVersionConstraint: step.PluginVersionConstraint,
 	}
 	inputVariablesDto, outputVariablesDto, conditionsDto, err := impl.BuildVariableAndConditionDataByStepIdDeepCopy(step.Id)
 	if err != nil {
@@ -449,6 +456,9 @@ func (impl *PipelineStageServiceImpl) BuildCiStageData(ciStage *repository.Pipel
 			Index:                    step.Index,
 			Description:              step.Description,
 			OutputDirectoryPath:      step.OutputDirectoryPath,
+			TestReportPaths:          step.TestReportPaths,
+			CoverageReportPaths:      step.CoverageReportPaths,
+			CoverageReportFormat:     step.CoverageReportFormat,
 			StepType:                 step.StepType,
 			TriggerIfParentStageFail: step.TriggerIfParentStageFail,
 		}
@@ -539,7 +549,8 @@ func (impl *PipelineStageServiceImpl) BuildInlineStepData(step *repository.Pipel
 
 func (impl *PipelineStageServiceImpl) BuildRefPluginStepData(step *repository.PipelineStageStep) (*bean.RefPluginStepDetailDto, error) {
 	refPluginStepDetail := &bean.RefPluginStepDetailDto{
-		PluginId: step.RefPluginId,
+		PluginId:                step.RefPluginId,
+		PluginVersionConstraint: step.PluginVersionConstraint,
 	}
 	inputVariablesDto, outputVariablesDto, conditionsDto, err := impl.BuildVariableAndConditionDataByStepId(step.Id)
 	if err != nil {
@@ -682,15 +693,18 @@ func (impl *PipelineStageServiceImpl) CreateStageSteps(steps []*bean.PipelineSta
 			}
 
 			inlineStep := &repository.PipelineStageStep{
-				PipelineStageId:     stageId,
-				Name:                step.Name,
-				Description:         step.Description,
-				Index:               step.Index,
-				StepType:            step.StepType,
-				ScriptId:            scriptEntryId,
-				OutputDirectoryPath: helper.FilterReservedPathFromOutputDirPath(step.OutputDirectoryPath), // TODO: silently filtering reserved paths, not throwing error as of now since this flow is not in tx
-				DependentOnStep:     dependentOnStep,
-				Deleted:             false,
+				PipelineStageId:      stageId,
+				Name:                 step.Name,
+				Description:          step.Description,
+				Index:                step.Index,
+				StepType:             step.StepType,
+				ScriptId:             scriptEntryId,
+				OutputDirectoryPath:  helper.FilterReservedPathFromOutputDirPath(step.OutputDirectoryPath), // TODO: silently filtering reserved paths, not throwing error as of now since this flow is not in tx
+				TestReportPaths:      step.TestReportPaths,
+				CoverageReportPaths:  step.CoverageReportPaths,
+				CoverageReportFormat: step.CoverageReportFormat,
+				DependentOnStep:      dependentOnStep,
+				Deleted:              false,
 				AuditLog: sql.AuditLog{
 					CreatedOn: time.Now(),
 					CreatedBy: userId,
@@ -710,16 +724,23 @@ func (impl *PipelineStageServiceImpl) CreateStageSteps(steps []*bean.PipelineSta
 			conditionDetails = inlineStepDetail.ConditionDetails
 		} else if step.StepType == repository.PIPELINE_STEP_TYPE_REF_PLUGIN {
 			refPluginStepDetail := step.RefPluginStepDetail
+			if err := impl.validateRefPluginVersionConstraint(refPluginStepDetail); err != nil {
+				return err
+			}
 			refPluginStep := &repository.PipelineStageStep{
-				PipelineStageId:     stageId,
-				Name:                step.Name,
-				Description:         step.Description,
-				Index:               step.Index,
-				StepType:            step.StepType,
-				RefPluginId:         refPluginStepDetail.PluginId,
-				OutputDirectoryPath: step.OutputDirectoryPath,
-				DependentOnStep:     dependentOnStep,
-				Deleted:             false,
+				PipelineStageId:         stageId,
+				Name:                    step.Name,
+				Description:             step.Description,
+				Index:                   step.Index,
+				StepType:                step.StepType,
+				RefPluginId:             refPluginStepDetail.PluginId,
+				PluginVersionConstraint: refPluginStepDetail.PluginVersionConstraint,
+				OutputDirectoryPath:     step.OutputDirectoryPath,
+				TestReportPaths:         step.TestReportPaths,
+				CoverageReportPaths:     step.CoverageReportPaths,
+				CoverageReportFormat:    step.CoverageReportFormat,
+				DependentOnStep:         dependentOnStep,
+				Deleted:                 false,
 				AuditLog: sql.AuditLog{
 					CreatedOn: time.Now(),
 					CreatedBy: userId,
@@ -1201,15 +1222,18 @@ func (impl *PipelineStageServiceImpl) UpdateStageStepsWithTx(steps []*bean.Pipel
 			return err
 		}
 		stepUpdateReq := &repository.PipelineStageStep{
-			Id:                  step.Id,
-			PipelineStageId:     stageId,
-			Name:                step.Name,
-			Description:         step.Description,
-			Index:               step.Index,
-			StepType:            step.StepType,
-			OutputDirectoryPath: helper.FilterReservedPathFromOutputDirPath(step.OutputDirectoryPath),
-			DependentOnStep:     dependentOnStep,
-			Deleted:             false,
+			Id:                   step.Id,
+			PipelineStageId:      stageId,
+			Name:                 step.Name,
+			Description:          step.Description,
+			Index:                step.Index,
+			StepType:             step.StepType,
+			OutputDirectoryPath:  helper.FilterReservedPathFromOutputDirPath(step.OutputDirectoryPath),
+			TestReportPaths:      step.TestReportPaths,
+			CoverageReportPaths:  step.CoverageReportPaths,
+			CoverageReportFormat: step.CoverageReportFormat,
+			DependentOnStep:      dependentOnStep,
+			Deleted:              false,
 			AuditLog: sql.AuditLog{
 				CreatedOn: savedStep.CreatedOn,
 				CreatedBy: savedStep.CreatedBy,
@@ -1240,8 +1264,12 @@ func (impl *PipelineStageServiceImpl) UpdateStageStepsWithTx(steps []*bean.Pipel
 					return err
 				}
 			}
+			if err := impl.validateRefPluginVersionConstraint(step.RefPluginStepDetail); err != nil {
+				return err
+			}
 			//updating ref plugin id in step update req
 			stepUpdateReq.RefPluginId = step.RefPluginStepDetail.PluginId
+			stepUpdateReq.PluginVersionConstraint = step.RefPluginStepDetail.PluginVersionConstraint
 			inputVariables = step.RefPluginStepDetail.InputVariables
 			outputVariables = step.RefPluginStepDetail.OutputVariables
 			conditionDetails = step.RefPluginStepDetail.ConditionDetails
@@ -1912,12 +1940,111 @@ func (impl *PipelineStageServiceImpl) GetRefPluginStepsByIds(refPluginIds []int,
 	return pluginIdStepsMap, nil
 }
 
+// validateRefPluginVersionConstraint checks that the version constraint, if any, is satisfied by at least one version of the referred plugin
+func (impl *PipelineStageServiceImpl) validateRefPluginVersionConstraint(refPluginStepDetail *bean.RefPluginStepDetailDto) error {
+	if refPluginStepDetail == nil || len(refPluginStepDetail.PluginVersionConstraint) == 0 {
+		return nil
+	}
+	resolvedPlugin, err := impl.resolvePluginVersionConstraint(refPluginStepDetail.PluginId, refPluginStepDetail.PluginVersionConstraint)
+	if err != nil {
+		impl.logger.Errorw("error in resolving plugin version constraint", "pluginId", refPluginStepDetail.PluginId, "constraint", refPluginStepDetail.PluginVersionConstraint, "err", err)
+		return err
+	}
+	if resolvedPlugin == nil {
+		return util.NewApiError(http.StatusBadRequest, pluginBean.PluginVersionConstraintUnsatisfiedError, pluginBean.PluginVersionConstraintUnsatisfiedError)
+	}
+	return nil
+}
+
+// resolvePluginVersionConstraint returns the highest version of pluginId's parent plugin satisfying the constraint
+func (impl *PipelineStageServiceImpl) resolvePluginVersionConstraint(pluginId int, constraint string) (*repository2.PluginMetadata, error) {
+	if err := pluginUtils.ValidatePluginVersionConstraint(constraint); err != nil {
+		return nil, err
+	}
+	pluginMetadata, err := impl.globalPluginRepository.GetMetaDataByPluginId(pluginId)
+	if err != nil {
+		return nil, err
+	}
+	pluginVersions, err := impl.globalPluginRepository.GetPluginVersionsByParentId(pluginMetadata.PluginParentMetadataId)
+	if err != nil {
+		return nil, err
+	}
+	return pluginUtils.ResolvePluginVersion(constraint, pluginVersions)
+}
+
+// resolveRefPluginIdForStep returns the plugin version to be used for the step at trigger time,
+// the trigger fails if the version constraint of the step is no longer satisfied by any version of the plugin
+func (impl *PipelineStageServiceImpl) resolveRefPluginIdForStep(step *repository.PipelineStageStep) (int, error) {
+	if len(step.PluginVersionConstraint) == 0 {
+		return step.RefPluginId, nil
+	}
+	resolvedPlugin, err := impl.resolvePluginVersionConstraint(step.RefPluginId, step.PluginVersionConstraint)
+	if err != nil {
+		impl.logger.Errorw("error in resolving plugin version constraint", "stepId", step.Id, "refPluginId", step.RefPluginId, "constraint", step.PluginVersionConstraint, "err", err)
+		return 0, err
+	}
+	if resolvedPlugin == nil {
+		errMsg := fmt.Sprintf("step %s: %s %s", step.Name, pluginBean.PluginVersionConstraintUnsatisfiedError, step.PluginVersionConstraint)
+		return 0, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	return resolvedPlugin.Id, nil
+}
+
+// getStepVariablesForPluginVersion maps the variables configured on the step for its pinned plugin version onto the
+// variables of pluginId, the version resolved at trigger time. Values are carried over by name, variables not present
+// in the resolved version are dropped and new ones take their default value.
+func (impl *PipelineStageServiceImpl) getStepVariablesForPluginVersion(step *repository.PipelineStageStep,
+	stepVariables []*repository.PipelineStageStepVariable, pluginId int) ([]*repository.PipelineStageStepVariable, error) {
+	mappedVariables := make([]*repository.PipelineStageStepVariable, 0, len(stepVariables))
+	for _, variableType := range []repository.PipelineStageStepVariableType{repository.PIPELINE_STAGE_STEP_VARIABLE_TYPE_INPUT, repository.PIPELINE_STAGE_STEP_VARIABLE_TYPE_OUTPUT} {
+		pluginVariableType := repository2.PLUGIN_VARIABLE_TYPE_INPUT
+		if variableType == repository.PIPELINE_STAGE_STEP_VARIABLE_TYPE_OUTPUT {
+			pluginVariableType = repository2.PLUGIN_VARIABLE_TYPE_OUTPUT
+		}
+		targetVariables, err := impl.globalPluginRepository.GetExposedVariablesByPluginIdAndVariableType(pluginId, pluginVariableType)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in getting plugin variables", "pluginId", pluginId, "variableType", pluginVariableType, "err", err)
+			return nil, err
+		}
+		variablesOfType := sliceUtil.Filter(nil, stepVariables, func(variable *repository.PipelineStageStepVariable) bool {
+			return variable.VariableType == variableType
+		})
+		mapping := pluginUtils.MapStepVariablesToPluginVersion(step.Id, variablesOfType, targetVariables, nil, variableType, step.UpdatedBy)
+		if len(mapping.MissingRequired) > 0 {
+			errMsg := fmt.Sprintf("step %s: required inputs %v of the resolved plugin version have no value, configure them on the pipeline", step.Name, mapping.MissingRequired)
+			return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+		}
+		for i := range mapping.ToUpdate {
+			mappedVariables = append(mappedVariables, &mapping.ToUpdate[i])
+		}
+		for i := range mapping.ToCreate {
+			mappedVariables = append(mappedVariables, &mapping.ToCreate[i])
+		}
+	}
+	return mappedVariables, nil
+}
+
+// getStepArtifactPaths adds the test and coverage report paths of the step to its output directories so that the
+// reports are uploaded along with the step artifacts
+func getStepArtifactPaths(step *repository.PipelineStageStep) []string {
+	if len(step.TestReportPaths) == 0 && len(step.CoverageReportPaths) == 0 {
+		return step.OutputDirectoryPath
+	}
+	artifactPaths := make([]string, 0, len(step.OutputDirectoryPath)+len(step.TestReportPaths)+len(step.CoverageReportPaths))
+	for _, reportPath := range slices.Concat(step.OutputDirectoryPath, step.TestReportPaths, step.CoverageReportPaths) {
+		if !slices.Contains(artifactPaths, reportPath) {
+			artifactPaths = append(artifactPaths, reportPath)
+		}
+	}
+	return artifactPaths
+}
+
 func (impl *PipelineStageServiceImpl) buildPipelineStepDataForWfRequest(step *repository.PipelineStageStep) (*bean.StepObject, error) {
 	stepData := &bean.StepObject{
 		Name:                     step.Name,
 		Index:                    step.Index,
 		StepType:                 string(step.StepType),
-		ArtifactPaths:            step.OutputDirectoryPath,
+		ArtifactPaths:            getStepArtifactPaths(step),
 		TriggerIfParentStageFail: step.TriggerIfParentStageFail,
 	}
 	if step.StepType == repository.PIPELINE_STEP_TYPE_INLINE {
@@ -1971,9 +2098,13 @@ func (impl *PipelineStageServiceImpl) buildPipelineStepDataForWfRequest(step *re
 		}
 	} else if step.StepType == repository.PIPELINE_STEP_TYPE_REF_PLUGIN {
 		stepData.ExecutorType = "PLUGIN" //added only to avoid un-marshaling issues at ci-runner side, will not be used
-		stepData.RefPluginId = step.RefPluginId
+		refPluginId, err := impl.resolveRefPluginIdForStep(step)
+		if err != nil {
+			return nil, err
+		}
+		stepData.RefPluginId = refPluginId
 	}
-	variableAndConditionData, err := impl.buildVariableAndConditionDataForWfRequest(step.Id)
+	variableAndConditionData, err := impl.buildVariableAndConditionDataForWfRequest(step, stepData.RefPluginId)
 	if err != nil {
 		impl.logger.Errorw("error in getting variable and conditions data for wf request", "err", err, "stepId", step.Id)
 		return nil, err
@@ -1985,7 +2116,8 @@ func (impl *PipelineStageServiceImpl) buildPipelineStepDataForWfRequest(step *re
 	return stepData, nil
 }
 
-func (impl *PipelineStageServiceImpl) buildVariableAndConditionDataForWfRequest(stepId int) (*bean.VariableAndConditionDataForStep, error) {
+func (impl *PipelineStageServiceImpl) buildVariableAndConditionDataForWfRequest(step *repository.PipelineStageStep, refPluginId int) (*bean.VariableAndConditionDataForStep, error) {
+	stepId := step.Id
 	variableAndConditionData := bean.NewVariableAndConditionDataForStep()
 	// getting all variables in the step
 	stepVariables, err := impl.pipelineStageRepository.GetVariablesByStepId(stepId)
@@ -1993,9 +2125,20 @@ func (impl *PipelineStageServiceImpl) buildVariableAndConditionDataForWfRequest(
 		impl.logger.Errorw("error in getting variables by stepId", "err", err, "stepId", stepId)
 		return variableAndConditionData, err
 	}
+	if step.StepType == repository.PIPELINE_STEP_TYPE_REF_PLUGIN && refPluginId != step.RefPluginId {
+		// the version constraint resolved to another version than the pinned one, its variables are used
+		stepVariables, err = impl.getStepVariablesForPluginVersion(step, stepVariables, refPluginId)
+		if err != nil {
+			impl.logger.Errorw("error in mapping step variables to resolved plugin version", "err", err, "stepId", stepId, "refPluginId", refPluginId)
+			return variableAndConditionData, err
+		}
+	}
 	variableNameIdMap := make(map[int]string)
 	for _, variable := range stepVariables {
-		variableNameIdMap[variable.Id] = variable.Name
+		// variables added by the resolved plugin version are not saved and have no id, no condition can refer to them
+		if variable.Id > 0 {
+			variableNameIdMap[variable.Id] = variable.Name
+		}
 		// getting format
 		// ignoring error as it is already validated in func validatePipelineStageStepVariableForTrigger
 		format, _ := commonBean.NewFormat(variable.Format.String())
@@ -2048,6 +2191,9 @@ func (impl *PipelineStageServiceImpl) buildVariableAndConditionDataForWfRequest(
 		varName, ok := variableNameIdMap[condition.ConditionVariableId]
 		if ok {
 			conditionData.ConditionOnVariable = varName
+		} else if refPluginId != step.RefPluginId {
+			// variable of the condition is not present in the resolved plugin version
+			continue
 		}
 		if condition.ConditionType == repository.PIPELINE_STAGE_STEP_CONDITION_TYPE_TRIGGER || condition.ConditionType == repository.PIPELINE_STAGE_STEP_CONDITION_TYPE_SKIP {
 			variableAndConditionData = variableAndConditionData.AddTriggerSkipCondition(conditionData)
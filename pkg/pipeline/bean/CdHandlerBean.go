@@ -6,6 +6,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
 	buildBean "github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
 	bean2 "github.com/devtron-labs/devtron/pkg/pipeline/workflowStatus/bean"
+	testReportBean "github.com/devtron-labs/devtron/pkg/testReport/bean"
 	"time"
 )
 
@@ -38,4 +39,7 @@ type CdWorkflowWithArtifact struct {
 	ImageComment           *repository.ImageComment               `json:"imageComment"`
 	RefCdWorkflowRunnerId  int                                    `json:"referenceCdWorkflowRunnerId"`
 	WorkflowExecutionStage map[string][]*bean2.WorkflowStageDto   `json:"workflowExecutionStages"`
+	TestReportSummary      *testReportBean.TestReportSummaryDto   `json:"testReportSummary,omitempty"`
+	PullRequestUrl         string                                 `json:"pullRequestUrl,omitempty"`
+	PullRequestState       string                                 `json:"pullRequestState,omitempty"`
 }
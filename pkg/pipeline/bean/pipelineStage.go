@@ -40,6 +40,9 @@ type PipelineStageStepDto struct {
 	Index                    int                         `json:"index"`
 	StepType                 repository.PipelineStepType `json:"stepType" validate:"omitempty,oneof=INLINE REF_PLUGIN"`
 	OutputDirectoryPath      []string                    `json:"outputDirectoryPath"`
+	TestReportPaths          []string                    `json:"testReportPaths,omitempty"`
+	CoverageReportPaths      []string                    `json:"coverageReportPaths,omitempty"`
+	CoverageReportFormat     string                      `json:"coverageReportFormat,omitempty" validate:"omitempty,oneof=COBERTURA LCOV"`
 	InlineStepDetail         *InlineStepDetailDto        `json:"inlineStepDetail" validate:"omitempty,dive"`
 	RefPluginStepDetail      *RefPluginStepDetailDto     `json:"pluginRefStepDetail" validate:"omitempty,dive"`
 	TriggerIfParentStageFail bool                        `json:"triggerIfParentStageFail"`
@@ -66,10 +69,13 @@ type InlineStepDetailDto struct {
 }
 
 type RefPluginStepDetailDto struct {
-	PluginId         int                   `json:"pluginId"`
-	InputVariables   []*StepVariableDto    `json:"inputVariables"`
-	OutputVariables  []*StepVariableDto    `json:"outputVariables"`
-	ConditionDetails []*ConditionDetailDto `json:"conditionDetails"`
+	PluginId int `json:"pluginId"`
+	// PluginVersionConstraint is an optional semver constraint (e.g. ^1.2) on the versions of PluginId's parent plugin,
+	// the highest satisfying version is used at trigger time
+	PluginVersionConstraint string                `json:"pluginVersionConstraint,omitempty"`
+	InputVariables          []*StepVariableDto    `json:"inputVariables"`
+	OutputVariables         []*StepVariableDto    `json:"outputVariables"`
+	ConditionDetails        []*ConditionDetailDto `json:"conditionDetails"`
 }
 
 // StepVariableDto is used to define the input/output variables for a step
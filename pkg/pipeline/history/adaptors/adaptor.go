@@ -9,7 +9,7 @@ func GetHistoryDetailDto(history *repository.DeploymentTemplateHistory, variable
 	if history == nil {
 		return &bean.HistoryDetailDto{}
 	}
-	return &bean.HistoryDetailDto{
+	historyDetail := &bean.HistoryDetailDto{
 		TemplateName:        history.TemplateName,
 		TemplateVersion:     history.TemplateVersion,
 		IsAppMetricsEnabled: &history.IsAppMetricsEnabled,
@@ -20,4 +20,11 @@ func GetHistoryDetailDto(history *repository.DeploymentTemplateHistory, variable
 			ResolvedValue:    resolvedTemplate,
 		},
 	}
+	if len(history.PostRenderPatches) != 0 {
+		historyDetail.PostRenderPatches = &bean.HistoryDetailConfig{
+			DisplayName: "post-render-patches.json",
+			Value:       history.PostRenderPatches,
+		}
+	}
+	return historyDetail
 }
@@ -62,6 +62,8 @@ type HistoryDetailDto struct {
 	TemplateName        string `json:"templateName,omitempty"`
 	TemplateVersion     string `json:"templateVersion,omitempty"`
 	IsAppMetricsEnabled *bool  `json:"isAppMetricsEnabled,omitempty"`
+	// PostRenderPatches are the patches applied to the rendered manifest of the deployment, json encoded
+	PostRenderPatches *HistoryDetailConfig `json:"postRenderPatches,omitempty"`
 	//for pipeline strategy
 	PipelineTriggerType pipelineConfig.TriggerType `json:"pipelineTriggerType,omitempty"`
 	Strategy            string                     `json:"strategy,omitempty"`
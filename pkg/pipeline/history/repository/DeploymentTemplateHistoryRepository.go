@@ -58,6 +58,7 @@ type DeploymentTemplateHistory struct {
 	DeployedBy              int32     `sql:"deployed_by"`
 	MergeStrategy           string    `sql:"merge_strategy"`
 	PipelineIds             []int     `sql:"pipeline_ids,array"`
+	PostRenderPatches       string    `sql:"post_render_patches"`
 	sql.AuditLog
 	//getting below data from cd_workflow_runner and users join
 	DeploymentStatus  string `sql:"-"`
@@ -138,8 +138,12 @@ type PipelineStageStep struct {
 	Index                    int              `sql:"index"`
 	StepType                 PipelineStepType `sql:"step_type"`
 	ScriptId                 int              `sql:"script_id"`
-	RefPluginId              int              `sql:"ref_plugin_id"` //id of plugin used as reference
+	RefPluginId              int              `sql:"ref_plugin_id"`             //id of plugin used as reference
+	PluginVersionConstraint  string           `sql:"plugin_version_constraint"` //semver constraint on ref plugin version, resolved at trigger time
 	OutputDirectoryPath      []string         `sql:"output_directory_path" pg:",array"`
+	TestReportPaths          []string         `sql:"test_report_paths" pg:",array"`     //junit xml files or directories, parsed after the workflow completes
+	CoverageReportPaths      []string         `sql:"coverage_report_paths" pg:",array"` //cobertura xml or lcov files
+	CoverageReportFormat     string           `sql:"coverage_report_format"`            //COBERTURA or LCOV, inferred from file extension when empty
 	DependentOnStep          string           `sql:"dependent_on_step"`
 	Deleted                  bool             `sql:"deleted,notnull"`
 	TriggerIfParentStageFail bool             `sql:"trigger_if_parent_stage_fail"`
@@ -221,6 +225,20 @@ type PipelineStageStepCondition struct {
 	sql.AuditLog
 }
 
+// RefPluginStepUsage is a ref plugin step along with the pipeline and app it belongs to
+type RefPluginStepUsage struct {
+	StepId                  int               `sql:"step_id"`
+	StepName                string            `sql:"step_name"`
+	RefPluginId             int               `sql:"ref_plugin_id"`
+	PluginVersionConstraint string            `sql:"plugin_version_constraint"`
+	StageType               PipelineStageType `sql:"stage_type"`
+	CiPipelineId            int               `sql:"ci_pipeline_id"`
+	CdPipelineId            int               `sql:"cd_pipeline_id"`
+	AppId                   int               `sql:"app_id"`
+	AppName                 string            `sql:"app_name"`
+	EnvironmentName         string            `sql:"environment_name"`
+}
+
 type PipelineStageRepository interface {
 	GetConnection() *pg.DB
 
@@ -246,6 +264,7 @@ type PipelineStageRepository interface {
 	MarkStepsDeletedByStageId(stageId int) error
 	MarkStepsDeletedExcludingActiveStepsInUpdateReq(activeStepIdsPresentInReq []int, stageId int) error
 	GetActiveStepsByRefPluginId(refPluginId int) ([]*PipelineStageStep, error)
+	GetActiveRefPluginStepUsages(refPluginIds []int) ([]*RefPluginStepUsage, error)
 	CheckIfPluginExistsInPipelineStage(pipelineId int, stageType PipelineStageType, pluginId int) (bool, error)
 
 	CreatePipelineScript(pipelineScript *PluginPipelineScript, tx *pg.Tx) (*PluginPipelineScript, error)
@@ -534,6 +553,29 @@ func (impl *PipelineStageRepositoryImpl) GetActiveStepsByRefPluginId(refPluginId
 	return steps, nil
 }
 
+func (impl *PipelineStageRepositoryImpl) GetActiveRefPluginStepUsages(refPluginIds []int) ([]*RefPluginStepUsage, error) {
+	var usages []*RefPluginStepUsage
+	if len(refPluginIds) == 0 {
+		return usages, nil
+	}
+	query := `SELECT pss.id AS step_id, pss.name AS step_name, pss.ref_plugin_id, pss.plugin_version_constraint,
+		ps.type AS stage_type, ps.ci_pipeline_id, ps.cd_pipeline_id, a.id AS app_id, a.app_name, e.environment_name
+		FROM pipeline_stage_step pss
+		INNER JOIN pipeline_stage ps ON ps.id = pss.pipeline_stage_id AND ps.deleted = false
+		LEFT JOIN ci_pipeline cp ON cp.id = ps.ci_pipeline_id AND cp.deleted = false
+		LEFT JOIN pipeline p ON p.id = ps.cd_pipeline_id AND p.deleted = false
+		LEFT JOIN environment e ON e.id = p.environment_id
+		INNER JOIN app a ON a.id = COALESCE(cp.app_id, p.app_id) AND a.active = true
+		WHERE pss.deleted = false AND pss.step_type = ? AND pss.ref_plugin_id IN (?)
+		ORDER BY a.app_name, pss.id;`
+	_, err := impl.dbConnection.Query(&usages, query, PIPELINE_STEP_TYPE_REF_PLUGIN, pg.In(refPluginIds))
+	if err != nil {
+		impl.logger.Errorw("err in getting ref plugin step usages", "refPluginIds", refPluginIds, "err", err)
+		return nil, err
+	}
+	return usages, nil
+}
+
 func (impl *PipelineStageRepositoryImpl) CreatePipelineScript(pipelineScript *PluginPipelineScript, tx *pg.Tx) (*PluginPipelineScript, error) {
 	var err error
 	if tx != nil {
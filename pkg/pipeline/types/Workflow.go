@@ -41,6 +41,7 @@ import (
 	bean6 "github.com/devtron-labs/devtron/pkg/pipeline/workflowStatus/bean"
 	bean4 "github.com/devtron-labs/devtron/pkg/plugin/bean"
 	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	testReportBean "github.com/devtron-labs/devtron/pkg/testReport/bean"
 	"io"
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -762,6 +763,7 @@ type WorkflowResponse struct {
 	ReferenceWorkflowId    int                                    `json:"referenceWorkflowId"`
 	TargetPlatforms        []*commonBean.TargetPlatform           `json:"targetPlatforms"`
 	WorkflowExecutionStage map[string][]*bean6.WorkflowStageDto   `json:"workflowExecutionStages"`
+	TestReportSummary      *testReportBean.TestReportSummaryDto   `json:"testReportSummary,omitempty"`
 }
 
 type ConfigMapSecretDto struct {
@@ -0,0 +1,315 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package plugin
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	repository2 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	bean2 "github.com/devtron-labs/devtron/pkg/plugin/bean"
+	"github.com/devtron-labs/devtron/pkg/plugin/repository"
+	"github.com/devtron-labs/devtron/pkg/plugin/utils"
+	"github.com/devtron-labs/devtron/util/sliceUtil"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+// PluginUpgradeService lists pipeline stage steps referring to outdated plugin versions and upgrades them in bulk
+type PluginUpgradeService interface {
+	// GetOutdatedPluginUsages returns the steps whose plugin version (or resolved version constraint) is older than the latest version,
+	// parentPluginIds optionally narrows down the plugins to look for
+	GetOutdatedPluginUsages(parentPluginIds []int) ([]*bean2.OutdatedPluginUsageDto, error)
+	// BulkUpgradePluginVersion moves the steps to the target plugin version, carrying over input values by name
+	BulkUpgradePluginVersion(request *bean2.PluginBulkUpgradeRequest, userId int32) (*bean2.PluginBulkUpgradeResponse, error)
+}
+
+type PluginUpgradeServiceImpl struct {
+	logger                  *zap.SugaredLogger
+	globalPluginRepository  repository.GlobalPluginRepository
+	pipelineStageRepository repository2.PipelineStageRepository
+}
+
+func NewPluginUpgradeServiceImpl(logger *zap.SugaredLogger, globalPluginRepository repository.GlobalPluginRepository,
+	pipelineStageRepository repository2.PipelineStageRepository) *PluginUpgradeServiceImpl {
+	return &PluginUpgradeServiceImpl{
+		logger:                  logger,
+		globalPluginRepository:  globalPluginRepository,
+		pipelineStageRepository: pipelineStageRepository,
+	}
+}
+
+func (impl *PluginUpgradeServiceImpl) GetOutdatedPluginUsages(parentPluginIds []int) ([]*bean2.OutdatedPluginUsageDto, error) {
+	allPluginVersions, err := impl.globalPluginRepository.GetAllPluginMetaData()
+	if err != nil {
+		impl.logger.Errorw("error in getting all plugin versions", "err", err)
+		return nil, err
+	}
+	parentPluginIdsFilter := sliceUtil.GetMapOf(parentPluginIds, true)
+	pluginIdVersionMap := make(map[int]*repository.PluginMetadata, len(allPluginVersions))
+	parentIdVersionsMap := make(map[int][]*repository.PluginMetadata)
+	for _, pluginVersion := range allPluginVersions {
+		if len(parentPluginIdsFilter) > 0 && !parentPluginIdsFilter[pluginVersion.PluginParentMetadataId] {
+			continue
+		}
+		pluginIdVersionMap[pluginVersion.Id] = pluginVersion
+		parentIdVersionsMap[pluginVersion.PluginParentMetadataId] = append(parentIdVersionsMap[pluginVersion.PluginParentMetadataId], pluginVersion)
+	}
+	parentIdLatestVersionMap := make(map[int]*repository.PluginMetadata, len(parentIdVersionsMap))
+	outdatedPluginIds := make([]int, 0)
+	for parentId, pluginVersions := range parentIdVersionsMap {
+		latestVersion := utils.GetLatestPluginVersion(pluginVersions)
+		if latestVersion == nil {
+			continue
+		}
+		parentIdLatestVersionMap[parentId] = latestVersion
+		for _, pluginVersion := range pluginVersions {
+			if pluginVersion.Id != latestVersion.Id {
+				outdatedPluginIds = append(outdatedPluginIds, pluginVersion.Id)
+			}
+		}
+	}
+	usages, err := impl.pipelineStageRepository.GetActiveRefPluginStepUsages(outdatedPluginIds)
+	if err != nil {
+		impl.logger.Errorw("error in getting ref plugin step usages", "outdatedPluginIds", outdatedPluginIds, "err", err)
+		return nil, err
+	}
+	parentIdNameMap, err := impl.getParentPluginIdNameMap(parentIdLatestVersionMap)
+	if err != nil {
+		return nil, err
+	}
+	outdatedUsages := make([]*bean2.OutdatedPluginUsageDto, 0, len(usages))
+	for _, usage := range usages {
+		pluginVersion := pluginIdVersionMap[usage.RefPluginId]
+		latestVersion := parentIdLatestVersionMap[pluginVersion.PluginParentMetadataId]
+		outdatedUsage := &bean2.OutdatedPluginUsageDto{
+			PipelineStageStepId:     usage.StepId,
+			StepName:                usage.StepName,
+			StageType:               usage.StageType.ToString(),
+			CiPipelineId:            usage.CiPipelineId,
+			CdPipelineId:            usage.CdPipelineId,
+			AppId:                   usage.AppId,
+			AppName:                 usage.AppName,
+			EnvironmentName:         usage.EnvironmentName,
+			ParentPluginId:          pluginVersion.PluginParentMetadataId,
+			PluginName:              parentIdNameMap[pluginVersion.PluginParentMetadataId],
+			PluginId:                pluginVersion.Id,
+			PluginVersion:           pluginVersion.PluginVersion,
+			PluginVersionConstraint: usage.PluginVersionConstraint,
+			LatestPluginId:          latestVersion.Id,
+			LatestPluginVersion:     latestVersion.PluginVersion,
+		}
+		effectiveVersion := pluginVersion.PluginVersion
+		if len(usage.PluginVersionConstraint) > 0 {
+			resolvedVersion, err := utils.ResolvePluginVersion(usage.PluginVersionConstraint, parentIdVersionsMap[pluginVersion.PluginParentMetadataId])
+			if err == nil && resolvedVersion != nil {
+				if resolvedVersion.Id == latestVersion.Id {
+					// constraint already picks up the latest version at trigger time
+					continue
+				}
+				outdatedUsage.ResolvedPluginVersion = resolvedVersion.PluginVersion
+				effectiveVersion = resolvedVersion.PluginVersion
+			}
+		}
+		outdatedUsage.IsMajorUpgrade = utils.IsMajorVersionUpgrade(effectiveVersion, latestVersion.PluginVersion)
+		outdatedUsages = append(outdatedUsages, outdatedUsage)
+	}
+	return outdatedUsages, nil
+}
+
+func (impl *PluginUpgradeServiceImpl) getParentPluginIdNameMap(parentIdLatestVersionMap map[int]*repository.PluginMetadata) (map[int]string, error) {
+	parentIds := make([]int, 0, len(parentIdLatestVersionMap))
+	for parentId := range parentIdLatestVersionMap {
+		parentIds = append(parentIds, parentId)
+	}
+	parentIdNameMap := make(map[int]string, len(parentIds))
+	if len(parentIds) == 0 {
+		return parentIdNameMap, nil
+	}
+	parentPlugins, err := impl.globalPluginRepository.GetPluginParentMetadataByIds(parentIds)
+	if err != nil {
+		impl.logger.Errorw("error in getting parent plugins by ids", "parentIds", parentIds, "err", err)
+		return nil, err
+	}
+	for _, parentPlugin := range parentPlugins {
+		parentIdNameMap[parentPlugin.Id] = parentPlugin.Name
+	}
+	return parentIdNameMap, nil
+}
+
+func (impl *PluginUpgradeServiceImpl) BulkUpgradePluginVersion(request *bean2.PluginBulkUpgradeRequest, userId int32) (*bean2.PluginBulkUpgradeResponse, error) {
+	var targetPlugin *repository.PluginMetadata
+	if request.TargetPluginId > 0 {
+		var err error
+		targetPlugin, err = impl.globalPluginRepository.GetMetaDataByPluginId(request.TargetPluginId)
+		if err != nil {
+			impl.logger.Errorw("error in getting target plugin version", "targetPluginId", request.TargetPluginId, "err", err)
+			return nil, util.NewApiError(http.StatusBadRequest, "target plugin version not found", err.Error())
+		}
+	}
+	response := &bean2.PluginBulkUpgradeResponse{
+		DryRun:  request.DryRun,
+		Results: make([]*bean2.PluginStepUpgradeResult, 0, len(request.PipelineStageStepIds)),
+	}
+	for _, stepId := range request.PipelineStageStepIds {
+		result, err := impl.upgradeStepPluginVersion(stepId, targetPlugin, request.InputRenames, request.DryRun, userId)
+		if err != nil {
+			impl.logger.Errorw("error in upgrading plugin version of step", "stepId", stepId, "err", err)
+			if result == nil {
+				result = &bean2.PluginStepUpgradeResult{PipelineStageStepId: stepId}
+			}
+			result.Upgraded = false
+			result.Error = err.Error()
+		}
+		response.Results = append(response.Results, result)
+	}
+	return response, nil
+}
+
+func (impl *PluginUpgradeServiceImpl) upgradeStepPluginVersion(stepId int, targetPlugin *repository.PluginMetadata, inputRenames map[string]string,
+	dryRun bool, userId int32) (*bean2.PluginStepUpgradeResult, error) {
+	step, err := impl.pipelineStageRepository.GetStepById(stepId)
+	if err != nil {
+		return nil, err
+	}
+	if step.StepType != repository2.PIPELINE_STEP_TYPE_REF_PLUGIN {
+		return nil, fmt.Errorf("step is not a plugin step")
+	}
+	currentPlugin, err := impl.globalPluginRepository.GetMetaDataByPluginId(step.RefPluginId)
+	if err != nil {
+		return nil, err
+	}
+	if targetPlugin == nil {
+		pluginVersions, err := impl.globalPluginRepository.GetPluginVersionsByParentId(currentPlugin.PluginParentMetadataId)
+		if err != nil {
+			return nil, err
+		}
+		targetPlugin = utils.GetLatestPluginVersion(pluginVersions)
+		if targetPlugin == nil {
+			return nil, fmt.Errorf("no active version found for plugin")
+		}
+	} else if targetPlugin.PluginParentMetadataId != currentPlugin.PluginParentMetadataId {
+		return nil, fmt.Errorf(bean2.PluginUpgradeTargetMismatchError)
+	}
+	result := &bean2.PluginStepUpgradeResult{
+		PipelineStageStepId:   step.Id,
+		StepName:              step.Name,
+		FromPluginId:          currentPlugin.Id,
+		FromPluginVersion:     currentPlugin.PluginVersion,
+		ToPluginId:            targetPlugin.Id,
+		ToPluginVersion:       targetPlugin.PluginVersion,
+		CarriedOverInputs:     make([]string, 0),
+		RenamedInputs:         make(map[string]string),
+		RemovedInputs:         make([]string, 0),
+		AddedInputs:           make([]string, 0),
+		MissingRequiredInputs: make([]string, 0),
+	}
+	if currentPlugin.Id == targetPlugin.Id {
+		return result, nil
+	}
+	stepInputs, err := impl.pipelineStageRepository.GetVariablesByStepIdAndVariableType(step.Id, repository2.PIPELINE_STAGE_STEP_VARIABLE_TYPE_INPUT)
+	if err != nil {
+		return result, err
+	}
+	targetInputs, err := impl.globalPluginRepository.GetExposedVariablesByPluginIdAndVariableType(targetPlugin.Id, repository.PLUGIN_VARIABLE_TYPE_INPUT)
+	if err != nil {
+		return result, err
+	}
+	inputMapping := utils.MapStepVariablesToPluginVersion(step.Id, stepInputs, targetInputs, inputRenames, repository2.PIPELINE_STAGE_STEP_VARIABLE_TYPE_INPUT, userId)
+	result.CarriedOverInputs = inputMapping.CarriedOver
+	result.RenamedInputs = inputMapping.Renamed
+	result.RemovedInputs = inputMapping.Removed
+	result.AddedInputs = inputMapping.Added
+	result.MissingRequiredInputs = inputMapping.MissingRequired
+	if len(result.MissingRequiredInputs) > 0 {
+		return result, fmt.Errorf("required inputs %v of version %s have no value, configure them on the pipeline to upgrade", result.MissingRequiredInputs, targetPlugin.PluginVersion)
+	}
+	if dryRun {
+		return result, nil
+	}
+	stepOutputs, err := impl.pipelineStageRepository.GetVariablesByStepIdAndVariableType(step.Id, repository2.PIPELINE_STAGE_STEP_VARIABLE_TYPE_OUTPUT)
+	if err != nil {
+		return result, err
+	}
+	targetOutputs, err := impl.globalPluginRepository.GetExposedVariablesByPluginIdAndVariableType(targetPlugin.Id, repository.PLUGIN_VARIABLE_TYPE_OUTPUT)
+	if err != nil {
+		return result, err
+	}
+	outputMapping := utils.MapStepVariablesToPluginVersion(step.Id, stepOutputs, targetOutputs, nil, repository2.PIPELINE_STAGE_STEP_VARIABLE_TYPE_OUTPUT, userId)
+	err = impl.applyStepPluginUpgrade(step, targetPlugin.Id, userId, inputMapping, outputMapping)
+	if err != nil {
+		return result, err
+	}
+	result.Upgraded = true
+	return result, nil
+}
+
+func (impl *PluginUpgradeServiceImpl) applyStepPluginUpgrade(step *repository2.PipelineStageStep, targetPluginId int, userId int32, mappings ...*utils.StepVariableMapping) error {
+	tx, err := impl.pipelineStageRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	step.RefPluginId = targetPluginId
+	step.UpdatedOn = time.Now()
+	step.UpdatedBy = userId
+	_, err = impl.pipelineStageRepository.UpdatePipelineStageStep(step, tx)
+	if err != nil {
+		return err
+	}
+	removedVariableIds := make([]int, 0)
+	for _, mapping := range mappings {
+		if len(mapping.ToUpdate) > 0 {
+			_, err = impl.pipelineStageRepository.UpdatePipelineStageStepVariables(mapping.ToUpdate, tx)
+			if err != nil {
+				return err
+			}
+		}
+		if len(mapping.ToCreate) > 0 {
+			_, err = impl.pipelineStageRepository.CreatePipelineStageStepVariables(mapping.ToCreate, tx)
+			if err != nil {
+				return err
+			}
+		}
+		removedVariableIds = append(removedVariableIds, mapping.ToDeleteIds...)
+	}
+	if len(removedVariableIds) > 0 {
+		err = impl.pipelineStageRepository.MarkPipelineStageStepVariablesDeletedByIds(removedVariableIds, userId, tx)
+		if err != nil {
+			return err
+		}
+		// conditions on removed variables can no longer be evaluated
+		conditions, err := impl.pipelineStageRepository.GetConditionsByStepId(step.Id)
+		if err != nil {
+			return err
+		}
+		removedVariableIdMap := sliceUtil.GetMapOf(removedVariableIds, true)
+		staleConditionIds := make([]int, 0)
+		for _, condition := range conditions {
+			if removedVariableIdMap[condition.ConditionVariableId] {
+				staleConditionIds = append(staleConditionIds, condition.Id)
+			}
+		}
+		if len(staleConditionIds) > 0 {
+			err = impl.pipelineStageRepository.MarkPipelineStageStepConditionDeletedByIds(staleConditionIds, userId, tx)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
@@ -437,6 +437,9 @@ const (
 	PluginIconNotCorrectOrReachableError     = "cannot validate icon, make sure that provided url link is reachable"
 	PluginVersionAlreadyExistError           = "this plugin version already exists, please provide another plugin version"
 	NoStepDataToProceedError                 = "no step data provided to save, please provide a plugin step to proceed further"
+	PluginVersionConstraintInvalidError      = "please provide a valid semantic version constraint, e.g. ^1.2 or >=1.0.0, <2.0.0"
+	PluginVersionConstraintUnsatisfiedError  = "no version of this plugin satisfies the provided version constraint"
+	PluginUpgradeTargetMismatchError         = "target plugin version does not belong to the same plugin as the step"
 )
 
 const (
@@ -492,3 +495,54 @@ func (r *PluginDetailsMinQuery) IsValidPluginType() bool {
 		return false
 	}
 }
+
+// OutdatedPluginUsageDto is a pipeline stage step referring to a plugin version older than the latest one
+type OutdatedPluginUsageDto struct {
+	PipelineStageStepId     int    `json:"pipelineStageStepId"`
+	StepName                string `json:"stepName"`
+	StageType               string `json:"stageType"`
+	CiPipelineId            int    `json:"ciPipelineId,omitempty"`
+	CdPipelineId            int    `json:"cdPipelineId,omitempty"`
+	AppId                   int    `json:"appId"`
+	AppName                 string `json:"appName"`
+	EnvironmentName         string `json:"environmentName,omitempty"`
+	ParentPluginId          int    `json:"parentPluginId"`
+	PluginName              string `json:"pluginName"`
+	PluginId                int    `json:"pluginId"`
+	PluginVersion           string `json:"pluginVersion"`
+	PluginVersionConstraint string `json:"pluginVersionConstraint,omitempty"`
+	ResolvedPluginVersion   string `json:"resolvedPluginVersion,omitempty"`
+	LatestPluginId          int    `json:"latestPluginId"`
+	LatestPluginVersion     string `json:"latestPluginVersion"`
+	IsMajorUpgrade          bool   `json:"isMajorUpgrade"`
+}
+
+type PluginBulkUpgradeRequest struct {
+	PipelineStageStepIds []int `json:"pipelineStageStepIds" validate:"required,min=1"`
+	// TargetPluginId defaults to the latest version of the plugin used by each step
+	TargetPluginId int `json:"targetPluginId,omitempty"`
+	// InputRenames maps an input variable name of the current version to its name in the target version
+	InputRenames map[string]string `json:"inputRenames,omitempty"`
+	DryRun       bool              `json:"dryRun"`
+}
+
+type PluginStepUpgradeResult struct {
+	PipelineStageStepId   int               `json:"pipelineStageStepId"`
+	StepName              string            `json:"stepName"`
+	FromPluginId          int               `json:"fromPluginId"`
+	FromPluginVersion     string            `json:"fromPluginVersion"`
+	ToPluginId            int               `json:"toPluginId"`
+	ToPluginVersion       string            `json:"toPluginVersion"`
+	CarriedOverInputs     []string          `json:"carriedOverInputs"`
+	RenamedInputs         map[string]string `json:"renamedInputs,omitempty"`
+	RemovedInputs         []string          `json:"removedInputs"`
+	AddedInputs           []string          `json:"addedInputs"`
+	MissingRequiredInputs []string          `json:"missingRequiredInputs"`
+	Upgraded              bool              `json:"upgraded"`
+	Error                 string            `json:"error,omitempty"`
+}
+
+type PluginBulkUpgradeResponse struct {
+	DryRun  bool                       `json:"dryRun"`
+	Results []*PluginStepUpgradeResult `json:"results"`
+}
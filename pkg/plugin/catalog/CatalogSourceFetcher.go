@@ -0,0 +1,182 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package catalog
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
+	"github.com/devtron-labs/devtron/pkg/plugin/catalog/bean"
+	"github.com/devtron-labs/devtron/pkg/plugin/catalog/repository"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"io"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// fetchManifestFiles reads all plugin manifest files from the catalog source
+func (impl *PluginCatalogServiceImpl) fetchManifestFiles(ctx context.Context, source *repository.PluginCatalogSource) ([]*bean.PluginManifestFile, error) {
+	switch bean.CatalogSourceType(source.SourceType) {
+	case bean.CatalogSourceTypeGit:
+		return impl.fetchGitManifestFiles(source)
+	case bean.CatalogSourceTypeOci:
+		return impl.fetchOciManifestFiles(ctx, source)
+	default:
+		return nil, fmt.Errorf("unsupported catalog source type %q", source.SourceType)
+	}
+}
+
+func (impl *PluginCatalogServiceImpl) fetchGitManifestFiles(source *repository.PluginCatalogSource) ([]*bean.PluginManifestFile, error) {
+	gitProvider, err := impl.gitProviderRepository.FindOne(strconv.Itoa(source.GitProviderId))
+	if err != nil {
+		impl.logger.Errorw("error in getting git provider of catalog source", "sourceId", source.Id, "gitProviderId", source.GitProviderId, "err", err)
+		return nil, err
+	}
+	repoFiles, _, err := git.CloneAndReadFiles(&gitProvider, impl.logger, source.GitRepoUrl, source.GitBranch,
+		fmt.Sprintf("plugin-catalog-%d", source.Id), getManifestPath(source), isManifestFile)
+	if err != nil {
+		impl.logger.Errorw("error in reading plugin manifests of catalog source", "sourceId", source.Id, "repoUrl", source.GitRepoUrl, "err", err)
+		return nil, err
+	}
+	manifestFiles := make([]*bean.PluginManifestFile, 0, len(repoFiles))
+	for _, repoFile := range repoFiles {
+		manifestFiles = append(manifestFiles, &bean.PluginManifestFile{Path: repoFile.Path, Content: repoFile.Content})
+	}
+	return manifestFiles, nil
+}
+
+func (impl *PluginCatalogServiceImpl) fetchOciManifestFiles(ctx context.Context, source *repository.PluginCatalogSource) ([]*bean.PluginManifestFile, error) {
+	registry, err := impl.dockerArtifactStoreRepository.FindOne(source.OciRegistryId)
+	if err != nil {
+		impl.logger.Errorw("error in getting registry of catalog source", "sourceId", source.Id, "registryId", source.OciRegistryId, "err", err)
+		return nil, err
+	}
+	registryHost := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(registry.RegistryURL, "https://"), "http://"), "/")
+	ociRepo, err := remote.NewRepository(path.Join(registryHost, source.OciRepository))
+	if err != nil {
+		return nil, err
+	}
+	ociRepo.PlainHTTP = strings.HasPrefix(registry.RegistryURL, "http://")
+	ociRepo.Client = &auth.Client{
+		Client: auth.DefaultClient.Client,
+		Cache:  auth.NewCache(),
+		Credential: auth.StaticCredential(ociRepo.Reference.Registry, auth.Credential{
+			Username: registry.Username,
+			Password: registry.Password,
+		}),
+	}
+	tag := source.OciTag
+	if len(tag) == 0 {
+		tag = "latest"
+	}
+	manifestDescriptor, manifestReader, err := ociRepo.FetchReference(ctx, tag)
+	if err != nil {
+		impl.logger.Errorw("error in fetching catalog artifact manifest", "sourceId", source.Id, "repository", source.OciRepository, "tag", tag, "err", err)
+		return nil, err
+	}
+	defer manifestReader.Close()
+	manifestContent, err := content.ReadAll(manifestReader, manifestDescriptor)
+	if err != nil {
+		return nil, err
+	}
+	artifactManifest := &ocispec.Manifest{}
+	if err = json.Unmarshal(manifestContent, artifactManifest); err != nil {
+		return nil, err
+	}
+	maxLayerSize := int64(impl.config.MaxLayerSizeMB) * 1024 * 1024
+	manifestFiles := make([]*bean.PluginManifestFile, 0)
+	for _, layer := range artifactManifest.Layers {
+		// FetchAll reads exactly the declared size and verifies the digest, so the declared size bounds the download
+		if layer.Size > maxLayerSize {
+			return nil, fmt.Errorf("layer %s is larger than %d MB", layer.Digest, impl.config.MaxLayerSizeMB)
+		}
+		layerContent, err := content.FetchAll(ctx, ociRepo, layer)
+		if err != nil {
+			impl.logger.Errorw("error in fetching catalog artifact layer", "sourceId", source.Id, "digest", layer.Digest, "err", err)
+			return nil, err
+		}
+		layerName := layer.Annotations[ocispec.AnnotationTitle]
+		if len(layerName) == 0 {
+			layerName = layer.Digest.String()
+		}
+		if layer.MediaType == bean.OciPluginManifestMediaType {
+			manifestFiles = append(manifestFiles, &bean.PluginManifestFile{Path: layerName, Content: layerContent})
+			continue
+		}
+		tarballFiles, err := readManifestFilesFromTarball(layerContent, maxLayerSize)
+		if err != nil {
+			return nil, fmt.Errorf("error in reading layer %s as a gzipped tarball: %w", layerName, err)
+		}
+		manifestFiles = append(manifestFiles, tarballFiles...)
+	}
+	return manifestFiles, nil
+}
+
+// readManifestFilesFromTarball reads the manifest files of a gzipped tarball, at most maxSize bytes are extracted
+func readManifestFilesFromTarball(tarball []byte, maxSize int64) ([]*bean.PluginManifestFile, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(tarball))
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+	// a small layer can extract to a lot more, reading is capped on the decompressed stream
+	limitedReader := &io.LimitedReader{R: gzipReader, N: maxSize + 1}
+	manifestFiles := make([]*bean.PluginManifestFile, 0)
+	tarReader := tar.NewReader(limitedReader)
+	for {
+		header, err := tarReader.Next()
+		if limitedReader.N <= 0 {
+			return nil, fmt.Errorf("extracted layer is larger than %d bytes", maxSize)
+		} else if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg || !isManifestFile(header.Name) {
+			continue
+		}
+		fileContent, err := io.ReadAll(tarReader)
+		if limitedReader.N <= 0 {
+			return nil, fmt.Errorf("extracted layer is larger than %d bytes", maxSize)
+		} else if err != nil {
+			return nil, err
+		}
+		manifestFiles = append(manifestFiles, &bean.PluginManifestFile{Path: header.Name, Content: fileContent})
+	}
+	return manifestFiles, nil
+}
+
+func isManifestFile(fileName string) bool {
+	extension := strings.ToLower(filepath.Ext(fileName))
+	return extension == ".yaml" || extension == ".yml"
+}
+
+func getManifestPath(source *repository.PluginCatalogSource) string {
+	if len(source.ManifestPath) > 0 {
+		return source.ManifestPath
+	}
+	return bean.DefaultManifestPath
+}
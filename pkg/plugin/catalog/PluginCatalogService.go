@@ -0,0 +1,550 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	gitProviderRepository "github.com/devtron-labs/devtron/pkg/build/git/gitProvider/repository"
+	"github.com/devtron-labs/devtron/pkg/plugin"
+	"github.com/devtron-labs/devtron/pkg/plugin/catalog/adapter"
+	"github.com/devtron-labs/devtron/pkg/plugin/catalog/bean"
+	"github.com/devtron-labs/devtron/pkg/plugin/catalog/repository"
+	pluginRepository "github.com/devtron-labs/devtron/pkg/plugin/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cronUtil "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type PluginCatalogConfig struct {
+	SyncIntervalMins int `env:"PLUGIN_CATALOG_SYNC_INTERVAL_MINS" envDefault:"60" description:"Interval in minutes at which plugin catalog sources are synced, 0 disables the periodic sync"`
+	MaxLayerSizeMB   int `env:"PLUGIN_CATALOG_MAX_LAYER_SIZE_MB" envDefault:"10" description:"Maximum size in MB of an oci catalog artifact layer, compressed and extracted, larger layers fail the sync"`
+}
+
+// PluginCatalogService manages git/oci sources of plugin manifests and syncs them into global plugins
+type PluginCatalogService interface {
+	CreateSource(request *bean.PluginCatalogSourceDto, userId int32) (*bean.PluginCatalogSourceDto, error)
+	UpdateSource(request *bean.PluginCatalogSourceDto, userId int32) (*bean.PluginCatalogSourceDto, error)
+	DeleteSource(id int, userId int32) error
+	GetSource(id int) (*bean.PluginCatalogSourceDto, error)
+	GetAllSources() ([]*bean.PluginCatalogSourceDto, error)
+	// SyncSource creates or updates the plugin versions found in the source, deprecates the versions removed from it
+	// and records per plugin results on the source
+	SyncSource(ctx context.Context, id int, userId int32) (*bean.CatalogSyncResponse, error)
+}
+
+type PluginCatalogServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	pluginCatalogSourceRepository repository.PluginCatalogSourceRepository
+	sourceVersionRepository       repository.PluginCatalogSourceVersionRepository
+	globalPluginService           plugin.GlobalPluginService
+	globalPluginRepository        pluginRepository.GlobalPluginRepository
+	gitProviderRepository         gitProviderRepository.GitProviderRepository
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository
+	config                        *PluginCatalogConfig
+	// syncLock guards against the cron and the api syncing the same source concurrently
+	syncLock sync.Mutex
+}
+
+func NewPluginCatalogServiceImpl(logger *zap.SugaredLogger,
+	pluginCatalogSourceRepository repository.PluginCatalogSourceRepository,
+	sourceVersionRepository repository.PluginCatalogSourceVersionRepository,
+	globalPluginService plugin.GlobalPluginService,
+	globalPluginRepository pluginRepository.GlobalPluginRepository,
+	gitProviderRepository gitProviderRepository.GitProviderRepository,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository,
+	cronLogger *cronUtil.CronLoggerImpl) (*PluginCatalogServiceImpl, error) {
+	impl := &PluginCatalogServiceImpl{
+		logger:                        logger,
+		pluginCatalogSourceRepository: pluginCatalogSourceRepository,
+		sourceVersionRepository:       sourceVersionRepository,
+		globalPluginService:           globalPluginService,
+		globalPluginRepository:        globalPluginRepository,
+		gitProviderRepository:         gitProviderRepository,
+		dockerArtifactStoreRepository: dockerArtifactStoreRepository,
+	}
+	cfg := &PluginCatalogConfig{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+	impl.config = cfg
+	if cfg.SyncIntervalMins > 0 {
+		syncCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
+		_, err := syncCron.AddFunc(fmt.Sprintf("@every %dm", cfg.SyncIntervalMins), impl.syncAllSources)
+		if err != nil {
+			logger.Errorw("error in adding plugin catalog sync cron", "err", err)
+			return nil, err
+		}
+		syncCron.Start()
+	}
+	return impl, nil
+}
+
+func (impl *PluginCatalogServiceImpl) CreateSource(request *bean.PluginCatalogSourceDto, userId int32) (*bean.PluginCatalogSourceDto, error) {
+	if err := validateSourceRequest(request); err != nil {
+		return nil, err
+	}
+	existing, err := impl.pluginCatalogSourceRepository.FindActiveByName(request.Name)
+	if err != nil && !errors.Is(err, pg.ErrNoRows) {
+		impl.logger.Errorw("error in checking catalog source name", "name", request.Name, "err", err)
+		return nil, err
+	} else if existing != nil && existing.Id > 0 {
+		return nil, util.NewApiError(http.StatusConflict, "catalog source with the same name already exists", "catalog source with the same name already exists")
+	}
+	source := &repository.PluginCatalogSource{Active: true, AuditLog: sql.NewDefaultAuditLog(userId)}
+	setSourceFields(source, request)
+	if err = impl.pluginCatalogSourceRepository.Save(source); err != nil {
+		impl.logger.Errorw("error in saving catalog source", "request", request, "err", err)
+		return nil, err
+	}
+	return buildSourceDto(source), nil
+}
+
+func (impl *PluginCatalogServiceImpl) UpdateSource(request *bean.PluginCatalogSourceDto, userId int32) (*bean.PluginCatalogSourceDto, error) {
+	if err := validateSourceRequest(request); err != nil {
+		return nil, err
+	}
+	source, err := impl.getSourceById(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if source.Name != request.Name {
+		existing, err := impl.pluginCatalogSourceRepository.FindActiveByName(request.Name)
+		if err != nil && !errors.Is(err, pg.ErrNoRows) {
+			return nil, err
+		} else if existing != nil && existing.Id > 0 {
+			return nil, util.NewApiError(http.StatusConflict, "catalog source with the same name already exists", "catalog source with the same name already exists")
+		}
+	}
+	setSourceFields(source, request)
+	source.UpdateAuditLog(userId)
+	if err = impl.pluginCatalogSourceRepository.Update(source); err != nil {
+		impl.logger.Errorw("error in updating catalog source", "request", request, "err", err)
+		return nil, err
+	}
+	return buildSourceDto(source), nil
+}
+
+// DeleteSource deactivates the source, plugin versions already created by it are left untouched
+func (impl *PluginCatalogServiceImpl) DeleteSource(id int, userId int32) error {
+	source, err := impl.getSourceById(id)
+	if err != nil {
+		return err
+	}
+	sourceVersions, err := impl.sourceVersionRepository.FindActiveBySourceId(id)
+	if err != nil {
+		return err
+	}
+	for _, sourceVersion := range sourceVersions {
+		sourceVersion.Active = false
+		sourceVersion.UpdateAuditLog(userId)
+		if err = impl.sourceVersionRepository.Update(sourceVersion); err != nil {
+			impl.logger.Errorw("error in deactivating catalog source version", "sourceVersionId", sourceVersion.Id, "err", err)
+			return err
+		}
+	}
+	source.Active = false
+	source.UpdateAuditLog(userId)
+	return impl.pluginCatalogSourceRepository.Update(source)
+}
+
+func (impl *PluginCatalogServiceImpl) GetSource(id int) (*bean.PluginCatalogSourceDto, error) {
+	source, err := impl.getSourceById(id)
+	if err != nil {
+		return nil, err
+	}
+	return buildSourceDto(source), nil
+}
+
+func (impl *PluginCatalogServiceImpl) GetAllSources() ([]*bean.PluginCatalogSourceDto, error) {
+	sources, err := impl.pluginCatalogSourceRepository.FindAllActive()
+	if err != nil {
+		return nil, err
+	}
+	sourceDtos := make([]*bean.PluginCatalogSourceDto, 0, len(sources))
+	for _, source := range sources {
+		sourceDtos = append(sourceDtos, buildSourceDto(source))
+	}
+	return sourceDtos, nil
+}
+
+func (impl *PluginCatalogServiceImpl) getSourceById(id int) (*repository.PluginCatalogSource, error) {
+	source, err := impl.pluginCatalogSourceRepository.FindById(id)
+	if errors.Is(err, pg.ErrNoRows) {
+		return nil, util.NewApiError(http.StatusNotFound, "catalog source not found", err.Error())
+	} else if err != nil {
+		impl.logger.Errorw("error in getting catalog source", "id", id, "err", err)
+		return nil, err
+	}
+	return source, nil
+}
+
+func (impl *PluginCatalogServiceImpl) syncAllSources() {
+	sources, err := impl.pluginCatalogSourceRepository.FindAllActive()
+	if err != nil {
+		return
+	}
+	for _, source := range sources {
+		_, err = impl.SyncSource(context.Background(), source.Id, userBean.SystemUserId)
+		if err != nil {
+			impl.logger.Errorw("error in syncing plugin catalog source", "sourceId", source.Id, "err", err)
+		}
+	}
+}
+
+func (impl *PluginCatalogServiceImpl) SyncSource(ctx context.Context, id int, userId int32) (*bean.CatalogSyncResponse, error) {
+	impl.syncLock.Lock()
+	defer impl.syncLock.Unlock()
+	source, err := impl.getSourceById(id)
+	if err != nil {
+		return nil, err
+	}
+	response := &bean.CatalogSyncResponse{SourceId: source.Id, Results: make([]*bean.PluginSyncResult, 0)}
+	manifestFiles, err := impl.fetchManifestFiles(ctx, source)
+	if err != nil {
+		response.Status = bean.SyncStatusFailed
+		response.Error = err.Error()
+	} else {
+		response.Results, err = impl.syncManifestFiles(source, manifestFiles, userId)
+		if err != nil {
+			return nil, err
+		}
+		response.Status = adapter.GetSyncStatus(response.Results)
+	}
+	syncResult, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	source.LastSyncedOn = time.Now()
+	source.LastSyncStatus = string(response.Status)
+	source.LastSyncResult = string(syncResult)
+	source.UpdateAuditLog(userId)
+	if err = impl.pluginCatalogSourceRepository.Update(source); err != nil {
+		impl.logger.Errorw("error in saving catalog source sync result", "sourceId", source.Id, "err", err)
+		return nil, err
+	}
+	return response, nil
+}
+
+func (impl *PluginCatalogServiceImpl) syncManifestFiles(source *repository.PluginCatalogSource, manifestFiles []*bean.PluginManifestFile, userId int32) ([]*bean.PluginSyncResult, error) {
+	sourceVersions, err := impl.sourceVersionRepository.FindActiveBySourceId(source.Id)
+	if err != nil {
+		return nil, err
+	}
+	syncedVersions := make(map[string]*repository.PluginCatalogSourceVersion, len(sourceVersions))
+	for _, sourceVersion := range sourceVersions {
+		syncedVersions[getVersionKey(sourceVersion.PluginIdentifier, sourceVersion.PluginVersion)] = sourceVersion
+	}
+	results := make([]*bean.PluginSyncResult, 0)
+	presentVersions := make(map[string]bool)
+	// versions are deprecated only when every manifest file could be read, else a broken file would deprecate its plugin
+	allFilesParsed := true
+	for _, manifestFile := range manifestFiles {
+		manifests, err := adapter.ParsePluginManifests(manifestFile.Content)
+		if err != nil {
+			allFilesParsed = false
+			results = append(results, &bean.PluginSyncResult{ManifestFile: manifestFile.Path, Action: bean.PluginSyncActionFailed, Error: err.Error()})
+			continue
+		}
+		for _, manifest := range manifests {
+			versionKey := getVersionKey(manifest.Metadata.Identifier, manifest.Spec.Version)
+			result := &bean.PluginSyncResult{
+				ManifestFile:     manifestFile.Path,
+				PluginIdentifier: manifest.Metadata.Identifier,
+				PluginVersion:    manifest.Spec.Version,
+			}
+			if presentVersions[versionKey] {
+				result.Action, result.Error = bean.PluginSyncActionFailed, "duplicate manifest for this plugin version in the source"
+			} else {
+				presentVersions[versionKey] = true
+				impl.syncManifest(source, manifest, syncedVersions[versionKey], result, userId)
+			}
+			results = append(results, result)
+		}
+	}
+	if !allFilesParsed {
+		return results, nil
+	}
+	for versionKey, sourceVersion := range syncedVersions {
+		if presentVersions[versionKey] {
+			continue
+		}
+		result := &bean.PluginSyncResult{
+			PluginIdentifier: sourceVersion.PluginIdentifier,
+			PluginVersion:    sourceVersion.PluginVersion,
+			PluginVersionId:  sourceVersion.PluginVersionId,
+			Action:           bean.PluginSyncActionDeprecated,
+		}
+		if err = impl.deprecateRemovedVersion(sourceVersion, userId); err != nil {
+			impl.logger.Errorw("error in deprecating plugin version removed from catalog source", "sourceId", source.Id, "pluginVersionId", sourceVersion.PluginVersionId, "err", err)
+			result.Action, result.Error = bean.PluginSyncActionFailed, err.Error()
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// syncManifest creates the plugin version of a manifest, updates the version in place when its manifest changed
+// and restores a version which was removed from the source earlier and is back
+func (impl *PluginCatalogServiceImpl) syncManifest(source *repository.PluginCatalogSource, manifest *bean.PluginManifest,
+	syncedVersion *repository.PluginCatalogSourceVersion, result *bean.PluginSyncResult, userId int32) {
+	if err := adapter.ValidatePluginManifest(manifest); err != nil {
+		result.Action, result.Error = bean.PluginSyncActionFailed, err.Error()
+		return
+	}
+	digest, err := adapter.GetManifestDigest(manifest)
+	if err != nil {
+		result.Action, result.Error = bean.PluginSyncActionFailed, err.Error()
+		return
+	}
+	if syncedVersion != nil {
+		result.PluginVersionId = syncedVersion.PluginVersionId
+		if syncedVersion.ManifestDigest == digest {
+			result.Action = bean.PluginSyncActionUnchanged
+			return
+		}
+		if err = impl.updateSyncedVersion(syncedVersion, manifest, digest, userId); err != nil {
+			impl.logger.Errorw("error in updating plugin version to changed catalog manifest", "sourceId", source.Id, "pluginVersionId", syncedVersion.PluginVersionId, "err", err)
+			result.Action, result.Error = bean.PluginSyncActionFailed, getErrorMessage(err)
+			return
+		}
+		result.Action = bean.PluginSyncActionUpdated
+		return
+	}
+	removedVersion, err := impl.getRemovedVersion(source.Id, manifest)
+	if err != nil {
+		result.Action, result.Error = bean.PluginSyncActionFailed, err.Error()
+		return
+	} else if removedVersion != nil {
+		result.PluginVersionId = removedVersion.PluginVersionId
+		if err = impl.restoreRemovedVersion(removedVersion, manifest, digest, userId); err != nil {
+			impl.logger.Errorw("error in restoring plugin version back in catalog source", "sourceId", source.Id, "pluginVersionId", removedVersion.PluginVersionId, "err", err)
+			result.Action, result.Error = bean.PluginSyncActionFailed, getErrorMessage(err)
+			return
+		}
+		result.Action = bean.PluginSyncActionRestored
+		return
+	}
+	parentPluginId := 0
+	parentPlugin, err := impl.globalPluginRepository.GetPluginParentMetadataByIdentifier(manifest.Metadata.Identifier)
+	if err != nil && !errors.Is(err, pg.ErrNoRows) {
+		result.Action, result.Error = bean.PluginSyncActionFailed, err.Error()
+		return
+	} else if parentPlugin != nil {
+		parentPluginId = parentPlugin.Id
+	}
+	pluginVersionId, err := impl.globalPluginService.CreatePluginOrVersions(adapter.BuildPluginCreateRequest(manifest, parentPluginId), userId)
+	if err != nil {
+		impl.logger.Errorw("error in creating plugin version from catalog manifest", "sourceId", source.Id, "identifier", manifest.Metadata.Identifier, "version", manifest.Spec.Version, "err", err)
+		result.Action, result.Error = bean.PluginSyncActionFailed, getErrorMessage(err)
+		return
+	}
+	sourceVersion := &repository.PluginCatalogSourceVersion{
+		PluginCatalogSourceId: source.Id,
+		PluginVersionId:       pluginVersionId,
+		PluginIdentifier:      manifest.Metadata.Identifier,
+		PluginVersion:         manifest.Spec.Version,
+		ManifestDigest:        digest,
+		Active:                true,
+		AuditLog:              sql.NewDefaultAuditLog(userId),
+	}
+	if err = impl.sourceVersionRepository.Save(sourceVersion); err != nil {
+		impl.logger.Errorw("error in saving catalog source version", "sourceId", source.Id, "pluginVersionId", pluginVersionId, "err", err)
+		result.Action, result.Error = bean.PluginSyncActionFailed, err.Error()
+		return
+	}
+	result.PluginVersionId = pluginVersionId
+	result.Action = bean.PluginSyncActionCreated
+}
+
+func (impl *PluginCatalogServiceImpl) updateSyncedVersion(sourceVersion *repository.PluginCatalogSourceVersion, manifest *bean.PluginManifest, digest string, userId int32) error {
+	if err := impl.updatePluginVersion(sourceVersion.PluginVersionId, manifest, userId); err != nil {
+		return err
+	}
+	sourceVersion.ManifestDigest = digest
+	sourceVersion.UpdateAuditLog(userId)
+	return impl.sourceVersionRepository.Update(sourceVersion)
+}
+
+func (impl *PluginCatalogServiceImpl) updatePluginVersion(pluginVersionId int, manifest *bean.PluginManifest, userId int32) error {
+	existing, err := impl.globalPluginService.GetDetailedPluginInfoByPluginId(pluginVersionId)
+	if err != nil {
+		return err
+	}
+	_, err = impl.globalPluginService.PatchPlugin(adapter.BuildPluginUpdateRequest(manifest, existing), userId)
+	return err
+}
+
+// getRemovedVersion returns the source version deactivated when the plugin version was removed from the source,
+// nil if the version was never synced from the source or its plugin version was deleted since
+func (impl *PluginCatalogServiceImpl) getRemovedVersion(sourceId int, manifest *bean.PluginManifest) (*repository.PluginCatalogSourceVersion, error) {
+	removedVersion, err := impl.sourceVersionRepository.FindLatestInactive(sourceId, manifest.Metadata.Identifier, manifest.Spec.Version)
+	if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	_, err = impl.globalPluginRepository.GetMetaDataByPluginId(removedVersion.PluginVersionId)
+	if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return removedVersion, nil
+}
+
+// restoreRemovedVersion un-deprecates the plugin version, updates it when the manifest changed while it was removed and reactivates it for the source
+func (impl *PluginCatalogServiceImpl) restoreRemovedVersion(sourceVersion *repository.PluginCatalogSourceVersion, manifest *bean.PluginManifest, digest string, userId int32) error {
+	if sourceVersion.ManifestDigest != digest {
+		if err := impl.updatePluginVersion(sourceVersion.PluginVersionId, manifest, userId); err != nil {
+			return err
+		}
+	}
+	pluginVersion, err := impl.globalPluginRepository.GetMetaDataByPluginId(sourceVersion.PluginVersionId)
+	if err != nil {
+		return err
+	}
+	if pluginVersion.IsDeprecated {
+		tx, err := impl.globalPluginRepository.GetConnection().Begin()
+		if err != nil {
+			return err
+		}
+		// Rollback tx on error.
+		defer tx.Rollback()
+		pluginVersion.IsDeprecated = false
+		pluginVersion.UpdatedOn = time.Now()
+		pluginVersion.UpdatedBy = userId
+		if err = impl.globalPluginRepository.UpdatePluginMetadata(pluginVersion, tx); err != nil {
+			return err
+		}
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+	sourceVersion.ManifestDigest = digest
+	sourceVersion.Active = true
+	sourceVersion.UpdateAuditLog(userId)
+	return impl.sourceVersionRepository.Update(sourceVersion)
+}
+
+func (impl *PluginCatalogServiceImpl) deprecateRemovedVersion(sourceVersion *repository.PluginCatalogSourceVersion, userId int32) error {
+	pluginVersion, err := impl.globalPluginRepository.GetMetaDataByPluginId(sourceVersion.PluginVersionId)
+	if err != nil && !errors.Is(err, pg.ErrNoRows) {
+		return err
+	}
+	if pluginVersion != nil && pluginVersion.Id > 0 && !pluginVersion.IsDeprecated {
+		tx, err := impl.globalPluginRepository.GetConnection().Begin()
+		if err != nil {
+			return err
+		}
+		// Rollback tx on error.
+		defer tx.Rollback()
+		pluginVersion.IsDeprecated = true
+		pluginVersion.UpdatedOn = time.Now()
+		pluginVersion.UpdatedBy = userId
+		if err = impl.globalPluginRepository.UpdatePluginMetadata(pluginVersion, tx); err != nil {
+			return err
+		}
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+	sourceVersion.Active = false
+	sourceVersion.UpdateAuditLog(userId)
+	return impl.sourceVersionRepository.Update(sourceVersion)
+}
+
+func validateSourceRequest(request *bean.PluginCatalogSourceDto) error {
+	var errMsg string
+	switch request.SourceType {
+	case bean.CatalogSourceTypeGit:
+		if request.GitProviderId == 0 || len(request.GitRepoUrl) == 0 {
+			errMsg = "gitProviderId and gitRepoUrl are required for a GIT catalog source"
+		}
+	case bean.CatalogSourceTypeOci:
+		if len(request.OciRegistryId) == 0 || len(request.OciRepository) == 0 {
+			errMsg = "ociRegistryId and ociRepository are required for an OCI catalog source"
+		}
+	}
+	if len(errMsg) > 0 {
+		return util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	}
+	return nil
+}
+
+func setSourceFields(source *repository.PluginCatalogSource, request *bean.PluginCatalogSourceDto) {
+	source.Name = request.Name
+	source.SourceType = string(request.SourceType)
+	source.GitProviderId = request.GitProviderId
+	source.GitRepoUrl = request.GitRepoUrl
+	source.GitBranch = request.GitBranch
+	source.OciRegistryId = request.OciRegistryId
+	source.OciRepository = request.OciRepository
+	source.OciTag = request.OciTag
+	source.ManifestPath = request.ManifestPath
+}
+
+func buildSourceDto(source *repository.PluginCatalogSource) *bean.PluginCatalogSourceDto {
+	sourceDto := &bean.PluginCatalogSourceDto{
+		Id:             source.Id,
+		Name:           source.Name,
+		SourceType:     bean.CatalogSourceType(source.SourceType),
+		GitProviderId:  source.GitProviderId,
+		GitRepoUrl:     source.GitRepoUrl,
+		GitBranch:      source.GitBranch,
+		OciRegistryId:  source.OciRegistryId,
+		OciRepository:  source.OciRepository,
+		OciTag:         source.OciTag,
+		ManifestPath:   source.ManifestPath,
+		LastSyncStatus: bean.SyncStatus(source.LastSyncStatus),
+	}
+	if !source.LastSyncedOn.IsZero() {
+		lastSyncedOn := source.LastSyncedOn
+		sourceDto.LastSyncedOn = &lastSyncedOn
+	}
+	if len(source.LastSyncResult) > 0 {
+		lastSync := &bean.CatalogSyncResponse{}
+		if err := json.Unmarshal([]byte(source.LastSyncResult), lastSync); err == nil {
+			sourceDto.LastSyncResult = lastSync.Results
+		}
+	}
+	return sourceDto
+}
+
+func getVersionKey(identifier, version string) string {
+	return fmt.Sprintf("%s@%s", identifier, version)
+}
+
+func getErrorMessage(err error) string {
+	var apiErr *util.ApiError
+	if errors.As(err, &apiErr) {
+		if userMessage, ok := apiErr.UserMessage.(string); ok && len(userMessage) > 0 {
+			return userMessage
+		}
+	}
+	return err.Error()
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	pluginBean "github.com/devtron-labs/devtron/pkg/plugin/bean"
+	"github.com/devtron-labs/devtron/pkg/plugin/catalog/bean"
+	"github.com/devtron-labs/devtron/pkg/plugin/utils"
+	"io"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+// ParsePluginManifests parses all the yaml documents of a manifest file, empty documents are skipped
+func ParsePluginManifests(content []byte) ([]*bean.PluginManifest, error) {
+	manifests := make([]*bean.PluginManifest, 0)
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(content)))
+	for {
+		document, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if len(strings.TrimSpace(string(document))) == 0 {
+			continue
+		}
+		manifest := &bean.PluginManifest{}
+		if err = yaml.UnmarshalStrict(document, manifest); err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
+
+// ValidatePluginManifest validates the fields which are not covered by the plugin create request validations
+func ValidatePluginManifest(manifest *bean.PluginManifest) error {
+	if manifest.ApiVersion != bean.PluginManifestApiVersion {
+		return fmt.Errorf("unsupported apiVersion %q, expected %q", manifest.ApiVersion, bean.PluginManifestApiVersion)
+	}
+	if manifest.Kind != bean.PluginManifestKind {
+		return fmt.Errorf("unsupported kind %q, expected %q", manifest.Kind, bean.PluginManifestKind)
+	}
+	if len(manifest.Metadata.Identifier) == 0 || len(manifest.Metadata.Name) == 0 {
+		return fmt.Errorf("metadata.identifier and metadata.name are required")
+	}
+	if err := utils.ValidatePluginVersion(manifest.Spec.Version); err != nil {
+		return fmt.Errorf("invalid spec.version %q: %s", manifest.Spec.Version, pluginBean.PluginVersionNotSemanticallyCorrectError)
+	}
+	if len(manifest.Spec.Steps) == 0 {
+		return fmt.Errorf("spec.steps: %s", pluginBean.PluginStepsNotProvidedError)
+	}
+	return nil
+}
+
+// GetManifestDigest returns the digest of the normalised manifest, used to detect changes in already synced versions
+func GetManifestDigest(manifest *bean.PluginManifest) (string, error) {
+	manifestJson, err := json.Marshal(manifest)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(manifestJson)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// BuildPluginCreateRequest builds the request for GlobalPluginService.CreatePluginOrVersions, parentPluginId is 0 for a new plugin
+func BuildPluginCreateRequest(manifest *bean.PluginManifest, parentPluginId int) *pluginBean.PluginParentMetadataDto {
+	pluginVersion := pluginBean.NewPluginsVersionDetail()
+	pluginVersion.Name = manifest.Metadata.Name
+	pluginVersion.Description = manifest.Metadata.Description
+	pluginVersion.Type = string(pluginBean.SHARED)
+	pluginVersion.Icon = manifest.Metadata.Icon
+	pluginVersion.Tags = manifest.Metadata.Tags
+	pluginVersion.AreNewTagsPresent = len(manifest.Metadata.Tags) > 0
+	pluginVersion.PluginSteps = manifest.Spec.Steps
+	pluginVersion.DocLink = manifest.Spec.DocLink
+	pluginVersion.Version = manifest.Spec.Version
+	return &pluginBean.PluginParentMetadataDto{
+		Id:               parentPluginId,
+		Name:             manifest.Metadata.Name,
+		PluginIdentifier: manifest.Metadata.Identifier,
+		Description:      manifest.Metadata.Description,
+		Type:             string(pluginBean.SHARED),
+		Icon:             manifest.Metadata.Icon,
+		PluginStageType:  manifest.Metadata.StageType,
+		Versions: pluginBean.NewPluginVersions().
+			WithDetailedPluginVersionData([]*pluginBean.PluginsVersionDetail{pluginVersion}),
+	}
+}
+
+// BuildPluginUpdateRequest builds the request updating an already synced plugin version in place to a changed manifest.
+// The update matches steps and variables by id, so ids of the existing steps (by index) and variables (by name and type) are reused,
+// steps and variables without a match are created and the remaining ones are deleted
+func BuildPluginUpdateRequest(manifest *bean.PluginManifest, existing *pluginBean.PluginMetadataDto) *pluginBean.PluginMetadataDto {
+	existingSteps := make(map[int]*pluginBean.PluginStepsDto, len(existing.PluginSteps))
+	for _, existingStep := range existing.PluginSteps {
+		existingSteps[existingStep.Index] = existingStep
+	}
+	pluginSteps := make([]*pluginBean.PluginStepsDto, 0, len(manifest.Spec.Steps))
+	for _, manifestStep := range manifest.Spec.Steps {
+		pluginStep := *manifestStep
+		pluginStep.Id = 0
+		pluginStep.PluginStepVariable = make([]*pluginBean.PluginVariableDto, 0, len(manifestStep.PluginStepVariable))
+		existingStep, ok := existingSteps[manifestStep.Index]
+		existingVariables := make(map[string]int)
+		if ok {
+			pluginStep.Id = existingStep.Id
+			pluginScript := &pluginBean.PluginPipelineScript{}
+			if manifestStep.PluginPipelineScript != nil {
+				*pluginScript = *manifestStep.PluginPipelineScript
+			}
+			if existingStep.PluginPipelineScript != nil {
+				pluginScript.Id = existingStep.PluginPipelineScript.Id
+			}
+			pluginStep.PluginPipelineScript = pluginScript
+			for _, existingVariable := range existingStep.PluginStepVariable {
+				existingVariables[getVariableKey(existingVariable)] = existingVariable.Id
+			}
+		}
+		for _, manifestVariable := range manifestStep.PluginStepVariable {
+			pluginVariable := *manifestVariable
+			pluginVariable.Id = existingVariables[getVariableKey(manifestVariable)]
+			pluginStep.PluginStepVariable = append(pluginStep.PluginStepVariable, &pluginVariable)
+		}
+		pluginSteps = append(pluginSteps, &pluginStep)
+	}
+	return &pluginBean.PluginMetadataDto{
+		Id:                existing.Id,
+		Name:              manifest.Metadata.Name,
+		Description:       manifest.Metadata.Description,
+		Type:              string(pluginBean.SHARED),
+		Icon:              manifest.Metadata.Icon,
+		Tags:              manifest.Metadata.Tags,
+		AreNewTagsPresent: len(manifest.Metadata.Tags) > 0,
+		Action:            pluginBean.UPDATEPLUGIN,
+		// the stage of a synced plugin is not changed by a manifest update
+		PluginStage: existing.PluginStage,
+		PluginSteps: pluginSteps,
+	}
+}
+
+func getVariableKey(variable *pluginBean.PluginVariableDto) string {
+	return fmt.Sprintf("%s/%s", variable.VariableType, variable.Name)
+}
+
+// GetSyncStatus derives the overall status of a sync from per plugin results
+func GetSyncStatus(results []*bean.PluginSyncResult) bean.SyncStatus {
+	failed := 0
+	for _, result := range results {
+		if result.Action == bean.PluginSyncActionFailed {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return bean.SyncStatusSucceeded
+	} else if failed == len(results) {
+		return bean.SyncStatusFailed
+	}
+	return bean.SyncStatusPartiallySucceeded
+}
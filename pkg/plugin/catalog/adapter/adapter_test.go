@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	pluginBean "github.com/devtron-labs/devtron/pkg/plugin/bean"
+	"github.com/devtron-labs/devtron/pkg/plugin/catalog/bean"
+	"testing"
+)
+
+const testManifests = `
+apiVersion: plugin.devtron.ai/v1
+kind: Plugin
+metadata:
+  identifier: slack-notify
+  name: Slack Notify
+  tags: [notification]
+spec:
+  version: 1.0.0
+  steps:
+    - name: notify
+      stepType: INLINE
+      pluginStepVariable:
+        - name: WEBHOOK_URL
+          format: STRING
+          variableType: INPUT
+          isExposed: true
+      pluginPipelineScript:
+        type: SHELL
+        script: curl -X POST "$WEBHOOK_URL"
+---
+apiVersion: plugin.devtron.ai/v1
+kind: Plugin
+metadata:
+  identifier: slack-notify
+  name: Slack Notify
+spec:
+  version: not-semver
+  steps:
+    - name: notify
+`
+
+func TestParseAndValidatePluginManifests(t *testing.T) {
+	manifests, err := ParsePluginManifests([]byte(testManifests))
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("expected 2 manifests, got %d", len(manifests))
+	}
+	if err = ValidatePluginManifest(manifests[0]); err != nil {
+		t.Errorf("expected first manifest to be valid, got %v", err)
+	}
+	if err = ValidatePluginManifest(manifests[1]); err == nil {
+		t.Errorf("expected invalid version error for second manifest")
+	}
+
+	request := BuildPluginCreateRequest(manifests[0], 7)
+	if request.Id != 7 || request.PluginIdentifier != "slack-notify" {
+		t.Errorf("unexpected plugin create request %+v", request)
+	}
+	versionDetail := request.Versions.DetailedPluginVersionData[0]
+	if versionDetail.Version != "1.0.0" || len(versionDetail.PluginSteps) != 1 || !versionDetail.AreNewTagsPresent {
+		t.Errorf("unexpected plugin version detail %+v", versionDetail)
+	}
+	if versionDetail.PluginSteps[0].PluginPipelineScript == nil || len(versionDetail.PluginSteps[0].PluginStepVariable) != 1 {
+		t.Errorf("expected script and variables to be parsed from the step")
+	}
+
+	digest, err := GetManifestDigest(manifests[0])
+	if err != nil || len(digest) == 0 {
+		t.Fatalf("unexpected digest error: %v", err)
+	}
+	reparsed, _ := ParsePluginManifests([]byte(testManifests))
+	if reparsedDigest, _ := GetManifestDigest(reparsed[0]); reparsedDigest != digest {
+		t.Errorf("expected digest to be stable across parses")
+	}
+}
+
+func TestParsePluginManifestsRejectsUnknownFields(t *testing.T) {
+	_, err := ParsePluginManifests([]byte("apiVersion: plugin.devtron.ai/v1\nkind: Plugin\nspec:\n  versoin: 1.0.0\n"))
+	if err == nil {
+		t.Errorf("expected error for unknown field")
+	}
+}
+
+func TestGetSyncStatus(t *testing.T) {
+	created := &bean.PluginSyncResult{Action: bean.PluginSyncActionCreated}
+	failed := &bean.PluginSyncResult{Action: bean.PluginSyncActionFailed}
+	if status := GetSyncStatus([]*bean.PluginSyncResult{created}); status != bean.SyncStatusSucceeded {
+		t.Errorf("expected SUCCEEDED, got %s", status)
+	}
+	if status := GetSyncStatus([]*bean.PluginSyncResult{created, failed}); status != bean.SyncStatusPartiallySucceeded {
+		t.Errorf("expected PARTIALLY_SUCCEEDED, got %s", status)
+	}
+	if status := GetSyncStatus([]*bean.PluginSyncResult{failed}); status != bean.SyncStatusFailed {
+		t.Errorf("expected FAILED, got %s", status)
+	}
+}
+
+func TestBuildPluginUpdateRequest(t *testing.T) {
+	manifests, err := ParsePluginManifests([]byte(testManifests))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	existing := &pluginBean.PluginMetadataDto{
+		Id:          7,
+		PluginStage: pluginBean.CI_TYPE_PLUGIN,
+		PluginSteps: []*pluginBean.PluginStepsDto{{
+			Id:                   11,
+			Index:                manifests[0].Spec.Steps[0].Index,
+			PluginPipelineScript: &pluginBean.PluginPipelineScript{Id: 21},
+			PluginStepVariable: []*pluginBean.PluginVariableDto{
+				{Id: 31, Name: "WEBHOOK_URL", VariableType: manifests[0].Spec.Steps[0].PluginStepVariable[0].VariableType},
+				{Id: 32, Name: "CHANNEL", VariableType: manifests[0].Spec.Steps[0].PluginStepVariable[0].VariableType},
+			},
+		}},
+	}
+	request := BuildPluginUpdateRequest(manifests[0], existing)
+	if request.Id != 7 || request.Action != pluginBean.UPDATEPLUGIN || request.PluginStage != pluginBean.CI_TYPE_PLUGIN {
+		t.Errorf("expected update of plugin version 7 keeping its stage, got %+v", request)
+	}
+	if len(request.PluginSteps) != 1 || request.PluginSteps[0].Id != 11 || request.PluginSteps[0].PluginPipelineScript.Id != 21 {
+		t.Fatalf("expected step and script ids to be reused, got %+v", request.PluginSteps)
+	}
+	if request.PluginSteps[0].PluginPipelineScript.Script != manifests[0].Spec.Steps[0].PluginPipelineScript.Script {
+		t.Errorf("expected script of the manifest")
+	}
+	if variables := request.PluginSteps[0].PluginStepVariable; len(variables) != 1 || variables[0].Id != 31 {
+		t.Errorf("expected only WEBHOOK_URL with its existing id, got %+v", variables)
+	}
+	if manifests[0].Spec.Steps[0].Id != 0 || manifests[0].Spec.Steps[0].PluginStepVariable[0].Id != 0 {
+		t.Errorf("expected manifest to be left untouched")
+	}
+}
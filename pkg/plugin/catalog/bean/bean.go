@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package bean
+
+import (
+	pluginBean "github.com/devtron-labs/devtron/pkg/plugin/bean"
+	"time"
+)
+
+type CatalogSourceType string
+
+const (
+	CatalogSourceTypeGit CatalogSourceType = "GIT"
+	CatalogSourceTypeOci CatalogSourceType = "OCI"
+)
+
+type SyncStatus string
+
+const (
+	SyncStatusSucceeded          SyncStatus = "SUCCEEDED"
+	SyncStatusPartiallySucceeded SyncStatus = "PARTIALLY_SUCCEEDED"
+	SyncStatusFailed             SyncStatus = "FAILED"
+)
+
+type PluginSyncAction string
+
+const (
+	PluginSyncActionCreated    PluginSyncAction = "CREATED"
+	PluginSyncActionUpdated    PluginSyncAction = "UPDATED"
+	PluginSyncActionRestored   PluginSyncAction = "RESTORED"
+	PluginSyncActionUnchanged  PluginSyncAction = "UNCHANGED"
+	PluginSyncActionDeprecated PluginSyncAction = "DEPRECATED"
+	PluginSyncActionFailed     PluginSyncAction = "FAILED"
+)
+
+const (
+	PluginManifestApiVersion = "plugin.devtron.ai/v1"
+	PluginManifestKind       = "Plugin"
+	// OciPluginManifestMediaType is the layer media type of a single plugin manifest yaml in an oci artifact,
+	// layers of other media types are read as gzipped tarballs of manifest files
+	OciPluginManifestMediaType = "application/vnd.devtron.plugin.manifest.v1+yaml"
+	DefaultManifestPath        = "plugins"
+)
+
+type PluginCatalogSourceDto struct {
+	Id             int                 `json:"id"`
+	Name           string              `json:"name" validate:"required,min=3,max=250"`
+	SourceType     CatalogSourceType   `json:"sourceType" validate:"oneof=GIT OCI"`
+	GitProviderId  int                 `json:"gitProviderId,omitempty"`
+	GitRepoUrl     string              `json:"gitRepoUrl,omitempty"`
+	GitBranch      string              `json:"gitBranch,omitempty"`
+	OciRegistryId  string              `json:"ociRegistryId,omitempty"`
+	OciRepository  string              `json:"ociRepository,omitempty"`
+	OciTag         string              `json:"ociTag,omitempty"`
+	ManifestPath   string              `json:"manifestPath,omitempty"` // directory in the git repo holding manifests, defaults to DefaultManifestPath
+	LastSyncedOn   *time.Time          `json:"lastSyncedOn,omitempty"`
+	LastSyncStatus SyncStatus          `json:"lastSyncStatus,omitempty"`
+	LastSyncResult []*PluginSyncResult `json:"lastSyncResult,omitempty"`
+}
+
+type PluginSyncResult struct {
+	ManifestFile     string           `json:"manifestFile,omitempty"`
+	PluginIdentifier string           `json:"pluginIdentifier,omitempty"`
+	PluginVersion    string           `json:"pluginVersion,omitempty"`
+	PluginVersionId  int              `json:"pluginVersionId,omitempty"`
+	Action           PluginSyncAction `json:"action"`
+	Error            string           `json:"error,omitempty"`
+}
+
+type CatalogSyncResponse struct {
+	SourceId int                 `json:"sourceId"`
+	Status   SyncStatus          `json:"status"`
+	Error    string              `json:"error,omitempty"`
+	Results  []*PluginSyncResult `json:"results"`
+}
+
+// PluginManifest is the documented yaml format of a plugin version kept in a catalog source, see specs/plugin/plugin-catalog.yaml
+type PluginManifest struct {
+	ApiVersion string                 `json:"apiVersion"`
+	Kind       string                 `json:"kind"`
+	Metadata   PluginManifestMetadata `json:"metadata"`
+	Spec       PluginManifestSpec     `json:"spec"`
+}
+
+type PluginManifestMetadata struct {
+	Identifier  string   `json:"identifier"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Icon        string   `json:"icon,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// StageType is "scanner" for image scanning plugins, any other value creates a ci/cd plugin
+	StageType string `json:"stageType,omitempty"`
+}
+
+type PluginManifestSpec struct {
+	Version string                       `json:"version"`
+	DocLink string                       `json:"docLink,omitempty"`
+	Steps   []*pluginBean.PluginStepsDto `json:"steps"`
+}
+
+// PluginManifestFile is a raw manifest read from a catalog source
+type PluginManifestFile struct {
+	Path    string
+	Content []byte
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type PluginCatalogSource struct {
+	tableName      struct{}  `sql:"plugin_catalog_source" pg:",discard_unknown_columns"`
+	Id             int       `sql:"id,pk"`
+	Name           string    `sql:"name,notnull"`
+	SourceType     string    `sql:"source_type,notnull"`
+	GitProviderId  int       `sql:"git_provider_id"`
+	GitRepoUrl     string    `sql:"git_repo_url"`
+	GitBranch      string    `sql:"git_branch"`
+	OciRegistryId  string    `sql:"oci_registry_id"`
+	OciRepository  string    `sql:"oci_repository"`
+	OciTag         string    `sql:"oci_tag"`
+	ManifestPath   string    `sql:"manifest_path"`
+	Active         bool      `sql:"active,notnull"`
+	LastSyncedOn   time.Time `sql:"last_synced_on"`
+	LastSyncStatus string    `sql:"last_sync_status"`
+	LastSyncResult string    `sql:"last_sync_result"`
+	sql.AuditLog
+}
+
+type PluginCatalogSourceRepository interface {
+	Save(source *PluginCatalogSource) error
+	Update(source *PluginCatalogSource) error
+	FindById(id int) (*PluginCatalogSource, error)
+	FindActiveByName(name string) (*PluginCatalogSource, error)
+	FindAllActive() ([]*PluginCatalogSource, error)
+}
+
+type PluginCatalogSourceRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewPluginCatalogSourceRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *PluginCatalogSourceRepositoryImpl {
+	return &PluginCatalogSourceRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *PluginCatalogSourceRepositoryImpl) Save(source *PluginCatalogSource) error {
+	return impl.dbConnection.Insert(source)
+}
+
+func (impl *PluginCatalogSourceRepositoryImpl) Update(source *PluginCatalogSource) error {
+	return impl.dbConnection.Update(source)
+}
+
+func (impl *PluginCatalogSourceRepositoryImpl) FindById(id int) (*PluginCatalogSource, error) {
+	source := &PluginCatalogSource{}
+	err := impl.dbConnection.Model(source).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return source, err
+}
+
+func (impl *PluginCatalogSourceRepositoryImpl) FindActiveByName(name string) (*PluginCatalogSource, error) {
+	source := &PluginCatalogSource{}
+	err := impl.dbConnection.Model(source).
+		Where("name = ?", name).
+		Where("active = ?", true).
+		Select()
+	return source, err
+}
+
+func (impl *PluginCatalogSourceRepositoryImpl) FindAllActive() ([]*PluginCatalogSource, error) {
+	var sources []*PluginCatalogSource
+	err := impl.dbConnection.Model(&sources).
+		Where("active = ?", true).
+		Order("id").
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting active plugin catalog sources", "err", err)
+		return nil, err
+	}
+	return sources, nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// PluginCatalogSourceVersion is a plugin version created by a catalog source
+type PluginCatalogSourceVersion struct {
+	tableName             struct{} `sql:"plugin_catalog_source_version" pg:",discard_unknown_columns"`
+	Id                    int      `sql:"id,pk"`
+	PluginCatalogSourceId int      `sql:"plugin_catalog_source_id,notnull"`
+	PluginVersionId       int      `sql:"plugin_version_id,notnull"`
+	PluginIdentifier      string   `sql:"plugin_identifier,notnull"`
+	PluginVersion         string   `sql:"plugin_version,notnull"`
+	ManifestDigest        string   `sql:"manifest_digest,notnull"`
+	Active                bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type PluginCatalogSourceVersionRepository interface {
+	Save(sourceVersion *PluginCatalogSourceVersion) error
+	Update(sourceVersion *PluginCatalogSourceVersion) error
+	FindActiveBySourceId(sourceId int) ([]*PluginCatalogSourceVersion, error)
+	FindActiveByPluginVersionIds(pluginVersionIds []int) ([]*PluginCatalogSourceVersion, error)
+	// FindLatestInactive returns the last deactivated entry of a plugin version of the source, pg.ErrNoRows if there is none
+	FindLatestInactive(sourceId int, pluginIdentifier, pluginVersion string) (*PluginCatalogSourceVersion, error)
+}
+
+type PluginCatalogSourceVersionRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewPluginCatalogSourceVersionRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *PluginCatalogSourceVersionRepositoryImpl {
+	return &PluginCatalogSourceVersionRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *PluginCatalogSourceVersionRepositoryImpl) Save(sourceVersion *PluginCatalogSourceVersion) error {
+	return impl.dbConnection.Insert(sourceVersion)
+}
+
+func (impl *PluginCatalogSourceVersionRepositoryImpl) Update(sourceVersion *PluginCatalogSourceVersion) error {
+	return impl.dbConnection.Update(sourceVersion)
+}
+
+func (impl *PluginCatalogSourceVersionRepositoryImpl) FindActiveBySourceId(sourceId int) ([]*PluginCatalogSourceVersion, error) {
+	var sourceVersions []*PluginCatalogSourceVersion
+	err := impl.dbConnection.Model(&sourceVersions).
+		Where("plugin_catalog_source_id = ?", sourceId).
+		Where("active = ?", true).
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting plugin catalog source versions", "sourceId", sourceId, "err", err)
+		return nil, err
+	}
+	return sourceVersions, nil
+}
+
+func (impl *PluginCatalogSourceVersionRepositoryImpl) FindActiveByPluginVersionIds(pluginVersionIds []int) ([]*PluginCatalogSourceVersion, error) {
+	var sourceVersions []*PluginCatalogSourceVersion
+	if len(pluginVersionIds) == 0 {
+		return sourceVersions, nil
+	}
+	err := impl.dbConnection.Model(&sourceVersions).
+		Where("plugin_version_id IN (?)", pg.In(pluginVersionIds)).
+		Where("active = ?", true).
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting plugin catalog source versions by plugin version ids", "pluginVersionIds", pluginVersionIds, "err", err)
+		return nil, err
+	}
+	return sourceVersions, nil
+}
+
+func (impl *PluginCatalogSourceVersionRepositoryImpl) FindLatestInactive(sourceId int, pluginIdentifier, pluginVersion string) (*PluginCatalogSourceVersion, error) {
+	sourceVersion := &PluginCatalogSourceVersion{}
+	err := impl.dbConnection.Model(sourceVersion).
+		Where("plugin_catalog_source_id = ?", sourceId).
+		Where("plugin_identifier = ?", pluginIdentifier).
+		Where("plugin_version = ?", pluginVersion).
+		Where("active = ?", false).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	return sourceVersion, err
+}
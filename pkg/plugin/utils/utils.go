@@ -19,6 +19,7 @@ package utils
 import (
 	"errors"
 	"fmt"
+	semver2 "github.com/Masterminds/semver/v3"
 	"github.com/devtron-labs/devtron/internal/util"
 	bean2 "github.com/devtron-labs/devtron/pkg/plugin/bean"
 	"github.com/devtron-labs/devtron/pkg/plugin/repository"
@@ -110,3 +111,54 @@ func ValidatePluginVersion(version string) error {
 	}
 	return nil
 }
+
+// ValidatePluginVersionConstraint checks that the given semver constraint (e.g. ^1.2, ~1.4.0, >=1.0.0, <2.0.0) is parsable
+func ValidatePluginVersionConstraint(constraint string) error {
+	if _, err := semver2.NewConstraint(constraint); err != nil {
+		return util.NewApiError(http.StatusBadRequest, bean2.PluginVersionConstraintInvalidError, err.Error())
+	}
+	return nil
+}
+
+// ResolvePluginVersion returns the highest non-deprecated plugin version satisfying the constraint,
+// nil is returned if none of the versions satisfy it
+func ResolvePluginVersion(constraint string, pluginVersions []*repository.PluginMetadata) (*repository.PluginMetadata, error) {
+	semverConstraint, err := semver2.NewConstraint(constraint)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, bean2.PluginVersionConstraintInvalidError, err.Error())
+	}
+	var resolved *repository.PluginMetadata
+	var resolvedVersion *semver2.Version
+	for _, pluginVersion := range pluginVersions {
+		if pluginVersion.IsDeprecated || pluginVersion.Deleted {
+			continue
+		}
+		version, err := semver2.NewVersion(pluginVersion.PluginVersion)
+		if err != nil || !semverConstraint.Check(version) {
+			continue
+		}
+		if resolvedVersion == nil || version.GreaterThan(resolvedVersion) {
+			resolved, resolvedVersion = pluginVersion, version
+		}
+	}
+	return resolved, nil
+}
+
+// GetLatestPluginVersion returns the highest non-deprecated plugin version by semantic versioning
+func GetLatestPluginVersion(pluginVersions []*repository.PluginMetadata) *repository.PluginMetadata {
+	latest, _ := ResolvePluginVersion("*", pluginVersions)
+	return latest
+}
+
+// IsMajorVersionUpgrade returns true if the major version of toVersion is greater than that of fromVersion
+func IsMajorVersionUpgrade(fromVersion, toVersion string) bool {
+	from, err := semver2.NewVersion(fromVersion)
+	if err != nil {
+		return false
+	}
+	to, err := semver2.NewVersion(toVersion)
+	if err != nil {
+		return false
+	}
+	return to.Major() > from.Major()
+}
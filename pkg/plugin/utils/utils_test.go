@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package utils
+
+import (
+	"github.com/devtron-labs/devtron/pkg/plugin/repository"
+	"testing"
+)
+
+func TestResolvePluginVersion(t *testing.T) {
+	versions := []*repository.PluginMetadata{
+		{Id: 1, PluginVersion: "1.0.0"},
+		{Id: 2, PluginVersion: "1.2.0"},
+		{Id: 3, PluginVersion: "1.3.1"},
+		{Id: 4, PluginVersion: "1.4.0", IsDeprecated: true},
+		{Id: 5, PluginVersion: "2.0.0"},
+	}
+	tests := []struct {
+		constraint string
+		wantId     int
+	}{
+		{constraint: "^1.2", wantId: 3},
+		{constraint: "~1.2.0", wantId: 2},
+		{constraint: ">=1.0.0, <1.2.0", wantId: 1},
+		{constraint: "^2", wantId: 5},
+		{constraint: "^3", wantId: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			resolved, err := ResolvePluginVersion(tt.constraint, versions)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			gotId := 0
+			if resolved != nil {
+				gotId = resolved.Id
+			}
+			if gotId != tt.wantId {
+				t.Errorf("ResolvePluginVersion(%q) = %d, want %d", tt.constraint, gotId, tt.wantId)
+			}
+		})
+	}
+	if _, err := ResolvePluginVersion("not-a-constraint", versions); err == nil {
+		t.Errorf("expected error for invalid constraint")
+	}
+	if latest := GetLatestPluginVersion(versions); latest == nil || latest.Id != 5 {
+		t.Errorf("GetLatestPluginVersion() = %v, want id 5", latest)
+	}
+}
+
+func TestIsMajorVersionUpgrade(t *testing.T) {
+	if !IsMajorVersionUpgrade("1.3.1", "2.0.0") {
+		t.Errorf("expected 1.3.1 -> 2.0.0 to be a major upgrade")
+	}
+	if IsMajorVersionUpgrade("1.2.0", "1.3.1") {
+		t.Errorf("expected 1.2.0 -> 1.3.1 not to be a major upgrade")
+	}
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	repository2 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"github.com/devtron-labs/devtron/pkg/plugin/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"time"
+)
+
+// StepVariableMapping is the result of mapping the variables of a plugin step onto another version of the plugin
+type StepVariableMapping struct {
+	CarriedOver     []string
+	Renamed         map[string]string
+	Removed         []string
+	Added           []string
+	MissingRequired []string
+	ToUpdate        []repository2.PipelineStageStepVariable
+	ToCreate        []repository2.PipelineStageStepVariable
+	ToDeleteIds     []int
+}
+
+// MapStepVariablesToPluginVersion maps the step's variables onto the variables of the target plugin version,
+// values are carried over by name (after applying renames), variables missing in the target version are removed
+// and new ones are added with their default value
+func MapStepVariablesToPluginVersion(stepId int, stepVariables []*repository2.PipelineStageStepVariable, targetVariables []*repository.PluginStepVariable,
+	renames map[string]string, variableType repository2.PipelineStageStepVariableType, userId int32) *StepVariableMapping {
+	mapping := &StepVariableMapping{
+		CarriedOver:     make([]string, 0),
+		Renamed:         make(map[string]string),
+		Removed:         make([]string, 0),
+		Added:           make([]string, 0),
+		MissingRequired: make([]string, 0),
+	}
+	targetVariableNameMap := make(map[string]*repository.PluginStepVariable, len(targetVariables))
+	for _, targetVariable := range targetVariables {
+		targetVariableNameMap[targetVariable.Name] = targetVariable
+	}
+	mappedTargetNames := make(map[string]bool)
+	now := time.Now()
+	for _, stepVariable := range stepVariables {
+		targetName := stepVariable.Name
+		if newName, ok := renames[stepVariable.Name]; ok && len(newName) > 0 {
+			targetName = newName
+		}
+		targetVariable, exists := targetVariableNameMap[targetName]
+		if !exists || mappedTargetNames[targetName] {
+			mapping.Removed = append(mapping.Removed, stepVariable.Name)
+			mapping.ToDeleteIds = append(mapping.ToDeleteIds, stepVariable.Id)
+			continue
+		}
+		mappedTargetNames[targetName] = true
+		if targetName != stepVariable.Name {
+			mapping.Renamed[stepVariable.Name] = targetName
+		} else {
+			mapping.CarriedOver = append(mapping.CarriedOver, stepVariable.Name)
+		}
+		updatedVariable := *stepVariable
+		updatedVariable.Name = targetVariable.Name
+		updatedVariable.Format = repository2.PipelineStageStepVariableFormatType(targetVariable.Format)
+		updatedVariable.Description = targetVariable.Description
+		updatedVariable.AllowEmptyValue = targetVariable.AllowEmptyValue
+		updatedVariable.DefaultValue = targetVariable.DefaultValue
+		updatedVariable.VariableStepIndexInPlugin = targetVariable.VariableStepIndexInPlugin
+		updatedVariable.UpdatedOn = now
+		updatedVariable.UpdatedBy = userId
+		mapping.ToUpdate = append(mapping.ToUpdate, updatedVariable)
+	}
+	for _, targetVariable := range targetVariables {
+		if mappedTargetNames[targetVariable.Name] {
+			continue
+		}
+		mapping.Added = append(mapping.Added, targetVariable.Name)
+		if variableType == repository2.PIPELINE_STAGE_STEP_VARIABLE_TYPE_INPUT && !targetVariable.AllowEmptyValue && len(targetVariable.DefaultValue) == 0 {
+			mapping.MissingRequired = append(mapping.MissingRequired, targetVariable.Name)
+		}
+		mapping.ToCreate = append(mapping.ToCreate, repository2.PipelineStageStepVariable{
+			PipelineStageStepId:       stepId,
+			Name:                      targetVariable.Name,
+			Format:                    repository2.PipelineStageStepVariableFormatType(targetVariable.Format),
+			Description:               targetVariable.Description,
+			IsExposed:                 true,
+			AllowEmptyValue:           targetVariable.AllowEmptyValue,
+			DefaultValue:              targetVariable.DefaultValue,
+			Value:                     targetVariable.DefaultValue,
+			VariableType:              variableType,
+			ValueType:                 repository2.PIPELINE_STAGE_STEP_VARIABLE_VALUE_TYPE_NEW,
+			VariableStepIndexInPlugin: targetVariable.VariableStepIndexInPlugin,
+			AuditLog:                  sql.AuditLog{CreatedOn: now, CreatedBy: userId, UpdatedOn: now, UpdatedBy: userId},
+		})
+	}
+	return mapping
+}
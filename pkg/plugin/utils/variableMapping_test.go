@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ */
+
+package utils
+
+import (
+	repository2 "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"github.com/devtron-labs/devtron/pkg/plugin/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMapStepVariablesToPluginVersion(t *testing.T) {
+	stepVariables := []*repository2.PipelineStageStepVariable{
+		{Id: 1, Name: "IMAGE", Value: "nginx", VariableStepIndexInPlugin: 1},
+		{Id: 2, Name: "TIMEOUT", Value: "30", VariableStepIndexInPlugin: 1},
+		{Id: 3, Name: "OLD_FLAG", Value: "true", VariableStepIndexInPlugin: 1},
+	}
+	targetVariables := []*repository.PluginStepVariable{
+		{Name: "IMAGE", VariableStepIndexInPlugin: 2},
+		{Name: "TIMEOUT_SECONDS", VariableStepIndexInPlugin: 2},
+		{Name: "RETRIES", DefaultValue: "3", VariableStepIndexInPlugin: 2},
+		{Name: "TOKEN", VariableStepIndexInPlugin: 2},
+	}
+	mapping := MapStepVariablesToPluginVersion(10, stepVariables, targetVariables, map[string]string{"TIMEOUT": "TIMEOUT_SECONDS"},
+		repository2.PIPELINE_STAGE_STEP_VARIABLE_TYPE_INPUT, 1)
+
+	assert.Equal(t, []string{"IMAGE"}, mapping.CarriedOver)
+	assert.Equal(t, map[string]string{"TIMEOUT": "TIMEOUT_SECONDS"}, mapping.Renamed)
+	assert.Equal(t, []string{"OLD_FLAG"}, mapping.Removed)
+	assert.Equal(t, []int{3}, mapping.ToDeleteIds)
+	assert.Equal(t, []string{"RETRIES", "TOKEN"}, mapping.Added)
+	assert.Equal(t, []string{"TOKEN"}, mapping.MissingRequired)
+
+	assert.Len(t, mapping.ToUpdate, 2)
+	assert.Equal(t, "nginx", mapping.ToUpdate[0].Value)
+	assert.Equal(t, 2, mapping.ToUpdate[0].VariableStepIndexInPlugin)
+	assert.Equal(t, "TIMEOUT_SECONDS", mapping.ToUpdate[1].Name)
+	assert.Equal(t, "30", mapping.ToUpdate[1].Value)
+
+	assert.Len(t, mapping.ToCreate, 2)
+	assert.Equal(t, "3", mapping.ToCreate[0].Value)
+	assert.Equal(t, 10, mapping.ToCreate[0].PipelineStageStepId)
+}
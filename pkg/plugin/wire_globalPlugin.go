@@ -11,4 +11,7 @@ var WireSet = wire.NewSet(
 
 	NewGlobalPluginService,
 	wire.Bind(new(GlobalPluginService), new(*GlobalPluginServiceImpl)),
+
+	NewPluginUpgradeServiceImpl,
+	wire.Bind(new(PluginUpgradeService), new(*PluginUpgradeServiceImpl)),
 )
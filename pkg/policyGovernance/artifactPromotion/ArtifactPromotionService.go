@@ -0,0 +1,706 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package artifactPromotion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/cel"
+	argoApplication "github.com/devtron-labs/devtron/client/argocdServer/bean"
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
+	imageTagging "github.com/devtron-labs/devtron/internal/sql/repository/imageTagging"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	triggerBean "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/artifactPromotion/adapter"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/artifactPromotion/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/artifactPromotion/repository"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	testReportRead "github.com/devtron-labs/devtron/pkg/testReport/read"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type ArtifactPromotionService interface {
+	SavePolicy(request *bean.PromotionPolicyDto) (*bean.PromotionPolicyDto, error)
+	GetAllPolicies() ([]*bean.PromotionPolicyDto, error)
+	DeletePolicy(id int, userId int32) error
+
+	// PromoteArtifact creates a promotion request of the artifact for every target pipeline, a request is promoted right away
+	// when the applicable policies need no approval and all conditions pass
+	PromoteArtifact(request *bean.PromoteArtifactRequest) ([]*bean.PromotionResponse, error)
+	// ApprovePromotion records the approval of the user and re-evaluates the policies once enough approvals are received
+	ApprovePromotion(promotionRequestId int, userId int32) (*bean.PromotionResponse, error)
+	CancelPromotion(promotionRequestId int, userId int32) error
+	GetPromotionHistory(filter *bean.PromotionHistoryFilter) ([]*bean.PromotionHistoryDto, error)
+	// GetPipelinesForRequest returns the source and target cd pipelines of a promotion request, used for rbac
+	GetPipelinesForRequest(promotionRequestId int) (source *pipelineConfig.Pipeline, target *pipelineConfig.Pipeline, err error)
+}
+
+type ArtifactPromotionServiceImpl struct {
+	logger                              *zap.SugaredLogger
+	artifactPromotionPolicyRepository   repository.ArtifactPromotionPolicyRepository
+	artifactPromotionRequestRepository  repository.ArtifactPromotionRequestRepository
+	artifactPromotionApprovalRepository repository.ArtifactPromotionApprovalRepository
+	policyScopeService                  policyScope.PolicyScopeService
+	celEvaluatorService                 cel.EvaluatorService
+	pipelineRepository                  pipelineConfig.PipelineRepository
+	cdWorkflowRepository                pipelineConfig.CdWorkflowRepository
+	ciArtifactRepository                repository2.CiArtifactRepository
+	imageTaggingRepository              imageTagging.ImageTaggingRepository
+	imageScanService                    imageScanning.ImageScanService
+	userService                         user.UserService
+	testReportReadService               testReportRead.TestReportReadService
+}
+
+func NewArtifactPromotionServiceImpl(logger *zap.SugaredLogger,
+	artifactPromotionPolicyRepository repository.ArtifactPromotionPolicyRepository,
+	artifactPromotionRequestRepository repository.ArtifactPromotionRequestRepository,
+	artifactPromotionApprovalRepository repository.ArtifactPromotionApprovalRepository,
+	policyScopeService policyScope.PolicyScopeService,
+	celEvaluatorService cel.EvaluatorService,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	ciArtifactRepository repository2.CiArtifactRepository,
+	imageTaggingRepository imageTagging.ImageTaggingRepository,
+	imageScanService imageScanning.ImageScanService,
+	userService user.UserService,
+	testReportReadService testReportRead.TestReportReadService) *ArtifactPromotionServiceImpl {
+	return &ArtifactPromotionServiceImpl{
+		logger:                              logger,
+		artifactPromotionPolicyRepository:   artifactPromotionPolicyRepository,
+		artifactPromotionRequestRepository:  artifactPromotionRequestRepository,
+		artifactPromotionApprovalRepository: artifactPromotionApprovalRepository,
+		policyScopeService:                  policyScopeService,
+		celEvaluatorService:                 celEvaluatorService,
+		pipelineRepository:                  pipelineRepository,
+		cdWorkflowRepository:                cdWorkflowRepository,
+		ciArtifactRepository:                ciArtifactRepository,
+		imageTaggingRepository:              imageTaggingRepository,
+		imageScanService:                    imageScanService,
+		userService:                         userService,
+		testReportReadService:               testReportReadService,
+	}
+}
+
+func (impl *ArtifactPromotionServiceImpl) SavePolicy(request *bean.PromotionPolicyDto) (*bean.PromotionPolicyDto, error) {
+	if len(request.Conditions) == 0 && request.ApprovalCount == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "at least one condition or approval is required", "empty promotion policy")
+	}
+	existing, err := impl.artifactPromotionPolicyRepository.FindActiveByName(request.Name)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching promotion policy by name", "name", request.Name, "err", err)
+		return nil, err
+	} else if err == nil && existing.Id != request.Id {
+		return nil, util.NewApiError(http.StatusConflict, "promotion policy with this name already exists", "duplicate promotion policy name")
+	}
+	// conditions are type checked against the fact declarations so that broken expressions never reach promotion
+	for _, condition := range request.Conditions {
+		_, _, err = impl.celEvaluatorService.Validate(adapter.BuildCELRequest(condition.Expression, &bean.ArtifactFacts{}))
+		if err != nil {
+			return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("invalid condition %q: %s", condition.Expression, err.Error()), err.Error())
+		}
+	}
+	policy, err := adapter.BuildPolicyModel(request)
+	if err != nil {
+		impl.logger.Errorw("error in building promotion policy", "request", request, "err", err)
+		return nil, err
+	}
+	if request.Id > 0 {
+		savedPolicy, err := impl.getPolicyById(request.Id)
+		if err != nil {
+			return nil, err
+		}
+		policy.CreatedOn, policy.CreatedBy = savedPolicy.CreatedOn, savedPolicy.CreatedBy
+	}
+
+	tx, err := impl.artifactPromotionPolicyRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.artifactPromotionPolicyRepository.RollbackTx(tx)
+	if policy.Id > 0 {
+		err = impl.artifactPromotionPolicyRepository.Update(tx, policy)
+	} else {
+		err = impl.artifactPromotionPolicyRepository.Save(tx, policy)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving promotion policy", "name", policy.Name, "err", err)
+		return nil, err
+	}
+	scope := &policyScope.PolicyScope{EnvironmentIds: request.EnvironmentIds, ClusterIds: request.ClusterIds}
+	err = impl.policyScopeService.SaveScope(tx, resourceQualifiers.ImagePromotionPolicy, policy.Id, scope, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.artifactPromotionPolicyRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	request.Id = policy.Id
+	return request, nil
+}
+
+func (impl *ArtifactPromotionServiceImpl) getPolicyById(id int) (*repository.ArtifactPromotionPolicy, error) {
+	policy, err := impl.artifactPromotionPolicyRepository.FindById(id)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "promotion policy not found", "promotion policy not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching promotion policy", "id", id, "err", err)
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (impl *ArtifactPromotionServiceImpl) GetAllPolicies() ([]*bean.PromotionPolicyDto, error) {
+	policies, err := impl.artifactPromotionPolicyRepository.FindAllActive()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching promotion policies", "err", err)
+		return nil, err
+	}
+	result := make([]*bean.PromotionPolicyDto, 0, len(policies))
+	if len(policies) == 0 {
+		return result, nil
+	}
+	policyIds := make([]int, 0, len(policies))
+	for _, policy := range policies {
+		policyIds = append(policyIds, policy.Id)
+	}
+	scopes, err := impl.policyScopeService.GetScopes(resourceQualifiers.ImagePromotionPolicy, policyIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		dto, err := adapter.BuildPolicyDto(policy, scopes[policy.Id].EnvironmentIds, scopes[policy.Id].ClusterIds)
+		if err != nil {
+			impl.logger.Errorw("error in building promotion policy", "policyId", policy.Id, "err", err)
+			return nil, err
+		}
+		result = append(result, dto)
+	}
+	return result, nil
+}
+
+func (impl *ArtifactPromotionServiceImpl) DeletePolicy(id int, userId int32) error {
+	policy, err := impl.getPolicyById(id)
+	if err != nil {
+		return err
+	}
+	policy.Active = false
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = userId
+	tx, err := impl.artifactPromotionPolicyRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.artifactPromotionPolicyRepository.RollbackTx(tx)
+	err = impl.artifactPromotionPolicyRepository.Update(tx, policy)
+	if err != nil {
+		impl.logger.Errorw("error in deleting promotion policy", "id", id, "err", err)
+		return err
+	}
+	err = impl.policyScopeService.DeleteScope(tx, resourceQualifiers.ImagePromotionPolicy, id, userId)
+	if err != nil {
+		return err
+	}
+	return impl.artifactPromotionPolicyRepository.CommitTx(tx)
+}
+
+// uniqueViolationPgErrorCode is returned when a request of the artifact for the target is already awaiting approval or promoted
+const uniqueViolationPgErrorCode = "23505"
+
+func (impl *ArtifactPromotionServiceImpl) PromoteArtifact(request *bean.PromoteArtifactRequest) ([]*bean.PromotionResponse, error) {
+	artifact, err := impl.ciArtifactRepository.Get(request.ArtifactId)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "artifact not found", "artifact not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching artifact", "artifactId", request.ArtifactId, "err", err)
+		return nil, err
+	}
+	sourcePipeline, targetPipelines, err := impl.getSourceAndTargetPipelines(request.SourcePipelineId, request.TargetPipelineIds)
+	if err != nil {
+		return nil, err
+	}
+	if sourcePipeline.AppId != request.AppId {
+		return nil, util.NewApiError(http.StatusBadRequest, "source pipeline does not belong to the app", "source pipeline of a different app")
+	}
+	sourceFacts, err := impl.getSourceFacts(artifact, sourcePipeline)
+	if err != nil {
+		return nil, err
+	}
+	if !sourceFacts.DeployedOnSource {
+		return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("artifact is not successfully deployed on %s", sourcePipeline.Environment.Name), "artifact not deployed on source")
+	}
+
+	responses := make([]*bean.PromotionResponse, 0, len(targetPipelines))
+	for _, targetPipeline := range targetPipelines {
+		existingRequest, err := impl.artifactPromotionRequestRepository.FindPendingOrPromoted(artifact.Id, targetPipeline.Id)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching existing promotion request", "artifactId", artifact.Id, "targetPipelineId", targetPipeline.Id, "err", err)
+			return nil, err
+		} else if err == nil {
+			// promoting again is a no-op, the request already in flight is returned
+			approvals, err := impl.artifactPromotionApprovalRepository.FindByPromotionRequestIds([]int{existingRequest.Id})
+			if err != nil && err != pg.ErrNoRows {
+				impl.logger.Errorw("error in fetching promotion approvals", "promotionRequestId", existingRequest.Id, "err", err)
+				return nil, err
+			}
+			responses = append(responses, impl.buildPromotionResponse(existingRequest, targetPipeline, len(approvals)))
+			continue
+		}
+		promotionRequest := &repository.ArtifactPromotionRequest{
+			CiArtifactId:     artifact.Id,
+			SourcePipelineId: sourcePipeline.Id,
+			TargetPipelineId: targetPipeline.Id,
+			Comment:          request.Comment,
+			AuditLog:         adapter.NewAuditLog(request.UserId),
+		}
+		err = impl.evaluatePromotion(promotionRequest, artifact, sourceFacts, targetPipeline, 0, true)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := impl.artifactPromotionRequestRepository.StartTx()
+		if err != nil {
+			impl.logger.Errorw("error in starting transaction", "err", err)
+			return nil, err
+		}
+		err = impl.artifactPromotionRequestRepository.Save(tx, promotionRequest)
+		if pgErr, ok := err.(pg.Error); ok && pgErr.Field('C') == uniqueViolationPgErrorCode {
+			// a concurrent promote call created the request after the check above
+			impl.artifactPromotionRequestRepository.RollbackTx(tx)
+			return nil, util.NewApiError(http.StatusConflict, fmt.Sprintf("artifact is already being promoted to %s", targetPipeline.Environment.Name), "duplicate promotion request")
+		} else if err != nil {
+			impl.artifactPromotionRequestRepository.RollbackTx(tx)
+			impl.logger.Errorw("error in saving promotion request", "artifactId", artifact.Id, "targetPipelineId", targetPipeline.Id, "err", err)
+			return nil, err
+		}
+		err = impl.artifactPromotionRequestRepository.CommitTx(tx)
+		if err != nil {
+			impl.logger.Errorw("error in committing transaction", "err", err)
+			return nil, err
+		}
+		responses = append(responses, impl.buildPromotionResponse(promotionRequest, targetPipeline, 0))
+	}
+	return responses, nil
+}
+
+// getSourceAndTargetPipelines validates that all pipelines exist and belong to the same app, targets can be in any workflow of the app
+func (impl *ArtifactPromotionServiceImpl) getSourceAndTargetPipelines(sourcePipelineId int, targetPipelineIds []int) (*pipelineConfig.Pipeline, []*pipelineConfig.Pipeline, error) {
+	pipelineIds := append([]int{sourcePipelineId}, targetPipelineIds...)
+	pipelines, err := impl.pipelineRepository.FindByIdsIn(pipelineIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching pipelines", "pipelineIds", pipelineIds, "err", err)
+		return nil, nil, err
+	}
+	pipelineIdPipelineMap := make(map[int]*pipelineConfig.Pipeline, len(pipelines))
+	for _, pipeline := range pipelines {
+		pipelineIdPipelineMap[pipeline.Id] = pipeline
+	}
+	sourcePipeline := pipelineIdPipelineMap[sourcePipelineId]
+	if sourcePipeline == nil {
+		return nil, nil, util.NewApiError(http.StatusNotFound, "source pipeline not found", "source pipeline not found")
+	}
+	targetPipelines := make([]*pipelineConfig.Pipeline, 0, len(targetPipelineIds))
+	visited := make(map[int]bool, len(targetPipelineIds))
+	for _, targetPipelineId := range targetPipelineIds {
+		targetPipeline := pipelineIdPipelineMap[targetPipelineId]
+		switch {
+		case visited[targetPipelineId]:
+			continue
+		case targetPipeline == nil:
+			return nil, nil, util.NewApiError(http.StatusNotFound, fmt.Sprintf("target pipeline %d not found", targetPipelineId), "target pipeline not found")
+		case targetPipelineId == sourcePipelineId:
+			return nil, nil, util.NewApiError(http.StatusBadRequest, "artifact cannot be promoted to the source pipeline", "target same as source")
+		case targetPipeline.AppId != sourcePipeline.AppId:
+			return nil, nil, util.NewApiError(http.StatusBadRequest, "artifact can only be promoted within the same application", "target pipeline of a different app")
+		}
+		visited[targetPipelineId] = true
+		targetPipelines = append(targetPipelines, targetPipeline)
+	}
+	return sourcePipeline, targetPipelines, nil
+}
+
+// getSourceFacts computes the facts which do not depend on the target environment
+func (impl *ArtifactPromotionServiceImpl) getSourceFacts(artifact *repository2.CiArtifact, sourcePipeline *pipelineConfig.Pipeline) (*bean.ArtifactFacts, error) {
+	facts := &bean.ArtifactFacts{
+		AppName:          sourcePipeline.App.AppName,
+		SourceEnvName:    sourcePipeline.Environment.Name,
+		ContainerImage:   artifact.Image,
+		IsScanned:        artifact.Scanned,
+		ArtifactAgeHours: int(time.Since(artifact.CreatedOn).Hours()),
+	}
+	containerRepo, containerImageTag, err := artifact.ExtractImageRepoAndTag()
+	if err != nil {
+		impl.logger.Errorw("error in getting image tag and repo", "image", artifact.Image, "err", err)
+	}
+	facts.ContainerRepo, facts.ContainerImageTag = containerRepo, containerImageTag
+
+	deployRunner, err := impl.cdWorkflowRepository.FindLatestRunnerByPipelineIdArtifactIdAndRunnerType(sourcePipeline.Id, artifact.Id, apiBean.CD_WORKFLOW_TYPE_DEPLOY)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployment of artifact on source", "pipelineId", sourcePipeline.Id, "artifactId", artifact.Id, "err", err)
+		return nil, err
+	}
+	facts.DeployedOnSource = err == nil && (deployRunner.Status == argoApplication.Healthy || deployRunner.Status == argoApplication.SUCCEEDED)
+
+	postRunner, err := impl.cdWorkflowRepository.FindLatestRunnerByPipelineIdArtifactIdAndRunnerType(sourcePipeline.Id, artifact.Id, apiBean.CD_WORKFLOW_TYPE_POST)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching post stage of artifact on source", "pipelineId", sourcePipeline.Id, "artifactId", artifact.Id, "err", err)
+		return nil, err
+	}
+	var ciWorkflowId, postWfrId int
+	if err == nil {
+		facts.PostStageStatus = postRunner.Status
+		facts.PostStageTestsPassed = postRunner.Status == argoApplication.SUCCEEDED
+		postWfrId = postRunner.Id
+	}
+	if artifact.WorkflowId != nil {
+		ciWorkflowId = *artifact.WorkflowId
+	}
+	testFacts, err := impl.testReportReadService.GetArtifactTestFacts(ciWorkflowId, postWfrId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching test report facts of artifact", "artifactId", artifact.Id, "ciWorkflowId", ciWorkflowId, "postWfrId", postWfrId, "err", err)
+		return nil, err
+	}
+	facts.HasTestReport = testFacts.ReportFound
+	facts.TestsFailed = testFacts.FailedTests
+	facts.TestPassRate = testFacts.PassRate
+	facts.TestLineCoverage = testFacts.LineCoverage
+
+	imageTags, err := impl.imageTaggingRepository.GetTagsByArtifactId(artifact.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching image tags", "artifactId", artifact.Id, "err", err)
+		return nil, err
+	}
+	facts.ImageLabels = make([]string, 0, len(imageTags))
+	for _, imageTag := range imageTags {
+		facts.ImageLabels = append(facts.ImageLabels, imageTag.TagName)
+	}
+	return facts, nil
+}
+
+// evaluatePromotion evaluates conditions of the policies applicable on the target and sets status and results on the request.
+// requiredApprovals are computed only for new requests, approvals are not re-requested when policies change mid-way
+func (impl *ArtifactPromotionServiceImpl) evaluatePromotion(promotionRequest *repository.ArtifactPromotionRequest, artifact *repository2.CiArtifact,
+	sourceFacts *bean.ArtifactFacts, targetPipeline *pipelineConfig.Pipeline, approvalCount int, isNewRequest bool) error {
+	policies, err := impl.getApplicablePolicies(targetPipeline.EnvironmentId, targetPipeline.Environment.ClusterId)
+	if err != nil {
+		return err
+	}
+	if isNewRequest {
+		for _, policy := range policies {
+			promotionRequest.RequiredApprovals = max(promotionRequest.RequiredApprovals, policy.ApprovalCount)
+		}
+	}
+	facts := *sourceFacts
+	facts.EnvName = targetPipeline.Environment.Name
+	facts.IsProdEnv = targetPipeline.Environment.Default
+	facts.ApprovalCount = approvalCount
+	facts.IsVulnerable, err = impl.imageScanService.GetArtifactVulnerabilityStatus(context.Background(), &triggerBean.VulnerabilityCheckRequest{ImageDigest: artifact.ImageDigest, CdPipeline: targetPipeline})
+	if err != nil {
+		impl.logger.Errorw("error in fetching vulnerability status of artifact", "artifactId", artifact.Id, "targetPipelineId", targetPipeline.Id, "err", err)
+		return err
+	}
+
+	allPassed := true
+	results := make([]*bean.PolicyResultDto, 0)
+	for _, policy := range policies {
+		policyDto, err := adapter.BuildPolicyDto(policy, nil, nil)
+		if err != nil {
+			impl.logger.Errorw("error in building promotion policy", "policyId", policy.Id, "err", err)
+			return err
+		}
+		for _, condition := range policyDto.Conditions {
+			result := &bean.PolicyResultDto{PolicyId: policy.Id, PolicyName: policy.Name, Expression: condition.Expression}
+			result.Passed, err = impl.celEvaluatorService.EvaluateCELRequest(adapter.BuildCELRequest(condition.Expression, &facts))
+			if err != nil {
+				result.Message = err.Error()
+			} else if !result.Passed {
+				result.Message = condition.Message
+			}
+			allPassed = allPassed && result.Passed
+			results = append(results, result)
+		}
+	}
+	policyResults, err := json.Marshal(results)
+	if err != nil {
+		impl.logger.Errorw("error in marshalling policy results", "err", err)
+		return err
+	}
+	promotionRequest.PolicyResults = string(policyResults)
+	switch {
+	case approvalCount < promotionRequest.RequiredApprovals:
+		promotionRequest.Status = bean.PromotionAwaitingApproval
+	case allPassed:
+		promotedOn := time.Now()
+		promotionRequest.Status = bean.PromotionPromoted
+		promotionRequest.PromotedOn = &promotedOn
+	default:
+		promotionRequest.Status = bean.PromotionPolicyFailed
+	}
+	return nil
+}
+
+func (impl *ArtifactPromotionServiceImpl) getApplicablePolicies(envId, clusterId int) ([]*repository.ArtifactPromotionPolicy, error) {
+	policyIds, err := impl.policyScopeService.GetApplicablePolicyIds(resourceQualifiers.ImagePromotionPolicy, envId, clusterId)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := impl.artifactPromotionPolicyRepository.FindByIds(policyIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching promotion policies", "policyIds", policyIds, "err", err)
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (impl *ArtifactPromotionServiceImpl) buildPromotionResponse(promotionRequest *repository.ArtifactPromotionRequest, targetPipeline *pipelineConfig.Pipeline, approvalCount int) *bean.PromotionResponse {
+	return &bean.PromotionResponse{
+		PromotionRequestId: promotionRequest.Id,
+		TargetPipelineId:   targetPipeline.Id,
+		TargetEnvName:      targetPipeline.Environment.Name,
+		Status:             promotionRequest.Status,
+		RequiredApprovals:  promotionRequest.RequiredApprovals,
+		ApprovalCount:      approvalCount,
+		PolicyResults:      adapter.GetPolicyResults(promotionRequest),
+	}
+}
+
+func (impl *ArtifactPromotionServiceImpl) getPromotionRequest(promotionRequestId int) (*repository.ArtifactPromotionRequest, error) {
+	promotionRequest, err := impl.artifactPromotionRequestRepository.FindById(promotionRequestId)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "promotion request not found", "promotion request not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching promotion request", "id", promotionRequestId, "err", err)
+		return nil, err
+	}
+	return promotionRequest, nil
+}
+
+func (impl *ArtifactPromotionServiceImpl) GetPipelinesForRequest(promotionRequestId int) (*pipelineConfig.Pipeline, *pipelineConfig.Pipeline, error) {
+	promotionRequest, err := impl.getPromotionRequest(promotionRequestId)
+	if err != nil {
+		return nil, nil, err
+	}
+	sourcePipeline, targetPipelines, err := impl.getSourceAndTargetPipelines(promotionRequest.SourcePipelineId, []int{promotionRequest.TargetPipelineId})
+	if err != nil {
+		return nil, nil, err
+	}
+	return sourcePipeline, targetPipelines[0], nil
+}
+
+func (impl *ArtifactPromotionServiceImpl) ApprovePromotion(promotionRequestId int, userId int32) (*bean.PromotionResponse, error) {
+	promotionRequest, err := impl.getPromotionRequest(promotionRequestId)
+	if err != nil {
+		return nil, err
+	}
+	if promotionRequest.Status != bean.PromotionAwaitingApproval {
+		return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("promotion request is %s, only requests awaiting approval can be approved", promotionRequest.Status), "promotion request not awaiting approval")
+	}
+	if promotionRequest.CreatedBy == userId {
+		return nil, util.NewApiError(http.StatusForbidden, "promotion request cannot be approved by the requester", "self approval")
+	}
+	approvals, err := impl.artifactPromotionApprovalRepository.FindByPromotionRequestIds([]int{promotionRequestId})
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching promotion approvals", "promotionRequestId", promotionRequestId, "err", err)
+		return nil, err
+	}
+	for _, approval := range approvals {
+		if approval.UserId == userId {
+			return nil, util.NewApiError(http.StatusConflict, "promotion request is already approved by the user", "duplicate approval")
+		}
+	}
+	artifact, err := impl.ciArtifactRepository.Get(promotionRequest.CiArtifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching artifact", "artifactId", promotionRequest.CiArtifactId, "err", err)
+		return nil, err
+	}
+	sourcePipeline, targetPipelines, err := impl.getSourceAndTargetPipelines(promotionRequest.SourcePipelineId, []int{promotionRequest.TargetPipelineId})
+	if err != nil {
+		return nil, err
+	}
+	sourceFacts, err := impl.getSourceFacts(artifact, sourcePipeline)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.evaluatePromotion(promotionRequest, artifact, sourceFacts, targetPipelines[0], len(approvals)+1, false)
+	if err != nil {
+		return nil, err
+	}
+	promotionRequest.UpdatedOn = time.Now()
+	promotionRequest.UpdatedBy = userId
+
+	tx, err := impl.artifactPromotionRequestRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.artifactPromotionRequestRepository.RollbackTx(tx)
+	err = impl.artifactPromotionApprovalRepository.Save(tx, &repository.ArtifactPromotionApproval{
+		PromotionRequestId: promotionRequestId,
+		UserId:             userId,
+		AuditLog:           adapter.NewAuditLog(userId),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving promotion approval", "promotionRequestId", promotionRequestId, "userId", userId, "err", err)
+		return nil, err
+	}
+	err = impl.artifactPromotionRequestRepository.Update(tx, promotionRequest)
+	if err != nil {
+		impl.logger.Errorw("error in updating promotion request", "promotionRequestId", promotionRequestId, "err", err)
+		return nil, err
+	}
+	err = impl.artifactPromotionRequestRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return impl.buildPromotionResponse(promotionRequest, targetPipelines[0], len(approvals)+1), nil
+}
+
+// CancelPromotion withdraws a pending request or revokes a promoted one, the artifact is no longer deployable on the target after it
+func (impl *ArtifactPromotionServiceImpl) CancelPromotion(promotionRequestId int, userId int32) error {
+	promotionRequest, err := impl.getPromotionRequest(promotionRequestId)
+	if err != nil {
+		return err
+	}
+	if promotionRequest.Status != bean.PromotionAwaitingApproval && promotionRequest.Status != bean.PromotionPromoted {
+		return util.NewApiError(http.StatusBadRequest, fmt.Sprintf("promotion request is already %s", promotionRequest.Status), "promotion request not cancellable")
+	}
+	promotionRequest.Status = bean.PromotionCancelled
+	promotionRequest.UpdatedOn = time.Now()
+	promotionRequest.UpdatedBy = userId
+	tx, err := impl.artifactPromotionRequestRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.artifactPromotionRequestRepository.RollbackTx(tx)
+	err = impl.artifactPromotionRequestRepository.Update(tx, promotionRequest)
+	if err != nil {
+		impl.logger.Errorw("error in cancelling promotion request", "promotionRequestId", promotionRequestId, "err", err)
+		return err
+	}
+	return impl.artifactPromotionRequestRepository.CommitTx(tx)
+}
+
+func (impl *ArtifactPromotionServiceImpl) GetPromotionHistory(filter *bean.PromotionHistoryFilter) ([]*bean.PromotionHistoryDto, error) {
+	result := make([]*bean.PromotionHistoryDto, 0)
+	pipelines, err := impl.pipelineRepository.FindActiveByAppId(filter.AppId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching pipelines of app", "appId", filter.AppId, "err", err)
+		return nil, err
+	}
+	pipelineIdEnvNameMap := make(map[int]string, len(pipelines))
+	pipelineIds := make([]int, 0, len(pipelines))
+	for _, pipeline := range pipelines {
+		pipelineIdEnvNameMap[pipeline.Id] = pipeline.Environment.Name
+		if filter.TargetPipelineId == 0 || filter.TargetPipelineId == pipeline.Id {
+			pipelineIds = append(pipelineIds, pipeline.Id)
+		}
+	}
+	requests, err := impl.artifactPromotionRequestRepository.FindByFilter(filter.ArtifactId, pipelineIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching promotion requests", "filter", filter, "err", err)
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return result, nil
+	}
+	requestIds, artifactIds, userIds := make([]int, 0, len(requests)), make([]int, 0, len(requests)), make([]int32, 0, len(requests))
+	for _, request := range requests {
+		if filter.TargetPipelineId > 0 && request.TargetPipelineId != filter.TargetPipelineId {
+			continue
+		}
+		requestIds = append(requestIds, request.Id)
+		artifactIds = append(artifactIds, request.CiArtifactId)
+		userIds = append(userIds, request.CreatedBy)
+	}
+	if len(requestIds) == 0 {
+		return result, nil
+	}
+	approvals, err := impl.artifactPromotionApprovalRepository.FindByPromotionRequestIds(requestIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching promotion approvals", "promotionRequestIds", requestIds, "err", err)
+		return nil, err
+	}
+	for _, approval := range approvals {
+		userIds = append(userIds, approval.UserId)
+	}
+	artifacts, err := impl.ciArtifactRepository.GetByIds(artifactIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching artifacts", "artifactIds", artifactIds, "err", err)
+		return nil, err
+	}
+	artifactIdImageMap := make(map[int]string, len(artifacts))
+	for _, artifact := range artifacts {
+		artifactIdImageMap[artifact.Id] = artifact.Image
+	}
+	users, err := impl.userService.GetByIds(userIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching users", "userIds", userIds, "err", err)
+		return nil, err
+	}
+	userIdEmailMap := make(map[int32]string, len(users))
+	for _, userInfo := range users {
+		userIdEmailMap[userInfo.Id] = userInfo.EmailId
+	}
+	requestIdApprovals := make(map[int][]*bean.PromotionApprovalDto)
+	for _, approval := range approvals {
+		requestIdApprovals[approval.PromotionRequestId] = append(requestIdApprovals[approval.PromotionRequestId], &bean.PromotionApprovalDto{
+			UserId:     approval.UserId,
+			UserEmail:  userIdEmailMap[approval.UserId],
+			ApprovedOn: approval.CreatedOn,
+		})
+	}
+	for _, request := range requests {
+		if filter.TargetPipelineId > 0 && request.TargetPipelineId != filter.TargetPipelineId {
+			continue
+		}
+		requestApprovals := requestIdApprovals[request.Id]
+		if requestApprovals == nil {
+			requestApprovals = make([]*bean.PromotionApprovalDto, 0)
+		}
+		result = append(result, &bean.PromotionHistoryDto{
+			Id:                request.Id,
+			ArtifactId:        request.CiArtifactId,
+			Image:             artifactIdImageMap[request.CiArtifactId],
+			SourcePipelineId:  request.SourcePipelineId,
+			SourceEnvName:     pipelineIdEnvNameMap[request.SourcePipelineId],
+			TargetPipelineId:  request.TargetPipelineId,
+			TargetEnvName:     pipelineIdEnvNameMap[request.TargetPipelineId],
+			Status:            request.Status,
+			Comment:           request.Comment,
+			PolicyResults:     adapter.GetPolicyResults(request),
+			RequiredApprovals: request.RequiredApprovals,
+			Approvals:         requestApprovals,
+			RequestedBy:       userIdEmailMap[request.CreatedBy],
+			RequestedOn:       request.CreatedOn,
+			PromotedOn:        request.PromotedOn,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/cel"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/artifactPromotion/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/artifactPromotion/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"time"
+)
+
+func NewAuditLog(userId int32) sql.AuditLog {
+	return sql.AuditLog{
+		CreatedOn: time.Now(),
+		CreatedBy: userId,
+		UpdatedOn: time.Now(),
+		UpdatedBy: userId,
+	}
+}
+
+func BuildPolicyModel(dto *bean.PromotionPolicyDto) (*repository.ArtifactPromotionPolicy, error) {
+	conditions := dto.Conditions
+	if conditions == nil {
+		conditions = make([]*bean.PromotionCondition, 0)
+	}
+	conditionsJson, err := json.Marshal(conditions)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.ArtifactPromotionPolicy{
+		Id:            dto.Id,
+		Name:          dto.Name,
+		Description:   dto.Description,
+		Conditions:    string(conditionsJson),
+		ApprovalCount: dto.ApprovalCount,
+		Active:        true,
+		AuditLog:      NewAuditLog(dto.UserId),
+	}, nil
+}
+
+func BuildPolicyDto(policy *repository.ArtifactPromotionPolicy, envIds, clusterIds []int) (*bean.PromotionPolicyDto, error) {
+	dto := &bean.PromotionPolicyDto{
+		Id:             policy.Id,
+		Name:           policy.Name,
+		Description:    policy.Description,
+		Conditions:     make([]*bean.PromotionCondition, 0),
+		ApprovalCount:  policy.ApprovalCount,
+		EnvironmentIds: envIds,
+		ClusterIds:     clusterIds,
+	}
+	if len(policy.Conditions) > 0 {
+		err := json.Unmarshal([]byte(policy.Conditions), &dto.Conditions)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dto, nil
+}
+
+func GetPolicyResults(request *repository.ArtifactPromotionRequest) []*bean.PolicyResultDto {
+	results := make([]*bean.PolicyResultDto, 0)
+	if len(request.PolicyResults) > 0 {
+		// results are informational, a corrupt value should not fail the history listing
+		_ = json.Unmarshal([]byte(request.PolicyResults), &results)
+	}
+	return results
+}
+
+// BuildCELRequest builds the request for evaluating a promotion condition against the artifact facts,
+// test pass rate and coverage facts are doubles which conditions compare with integer literals
+func BuildCELRequest(expression string, facts *bean.ArtifactFacts) cel.Request {
+	return cel.Request{
+		Expression:                  expression,
+		ExpressionMetadata:          cel.ExpressionMetadata{Params: BuildCELParams(facts)},
+		CrossTypeNumericComparisons: true,
+	}
+}
+
+// BuildCELParams declares every artifact fact as a CEL variable, the same declarations are used for
+// validating conditions at policy save time with zero values
+func BuildCELParams(facts *bean.ArtifactFacts) []cel.ExpressionParam {
+	imageLabels := facts.ImageLabels
+	if imageLabels == nil {
+		imageLabels = make([]string, 0)
+	}
+	return []cel.ExpressionParam{
+		{ParamName: cel.AppName, Value: facts.AppName, Type: cel.ParamTypeString},
+		{ParamName: cel.SourceEnvName, Value: facts.SourceEnvName, Type: cel.ParamTypeString},
+		{ParamName: cel.EnvName, Value: facts.EnvName, Type: cel.ParamTypeString},
+		{ParamName: cel.IsProdEnv, Value: facts.IsProdEnv, Type: cel.ParamTypeBool},
+		{ParamName: cel.ContainerRepo, Value: facts.ContainerRepo, Type: cel.ParamTypeString},
+		{ParamName: cel.ContainerImage, Value: facts.ContainerImage, Type: cel.ParamTypeString},
+		{ParamName: cel.ContainerImageTag, Value: facts.ContainerImageTag, Type: cel.ParamTypeString},
+		{ParamName: cel.ImageLabels, Value: imageLabels, Type: cel.ParamTypeList},
+		{ParamName: cel.DeployedOnSource, Value: facts.DeployedOnSource, Type: cel.ParamTypeBool},
+		{ParamName: cel.PostStageStatus, Value: facts.PostStageStatus, Type: cel.ParamTypeString},
+		{ParamName: cel.PostStageTestsPassed, Value: facts.PostStageTestsPassed, Type: cel.ParamTypeBool},
+		{ParamName: cel.ArtifactAgeHours, Value: facts.ArtifactAgeHours, Type: cel.ParamTypeInteger},
+		{ParamName: cel.IsScanned, Value: facts.IsScanned, Type: cel.ParamTypeBool},
+		{ParamName: cel.IsVulnerable, Value: facts.IsVulnerable, Type: cel.ParamTypeBool},
+		{ParamName: cel.ApprovalCount, Value: facts.ApprovalCount, Type: cel.ParamTypeInteger},
+		{ParamName: cel.HasTestReport, Value: facts.HasTestReport, Type: cel.ParamTypeBool},
+		{ParamName: cel.TestsFailed, Value: facts.TestsFailed, Type: cel.ParamTypeInteger},
+		{ParamName: cel.TestPassRate, Value: facts.TestPassRate, Type: cel.ParamTypeDouble},
+		{ParamName: cel.TestLineCoverage, Value: facts.TestLineCoverage, Type: cel.ParamTypeDouble},
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"github.com/devtron-labs/devtron/cel"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/artifactPromotion/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuildCELParams(t *testing.T) {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	evaluator := cel.NewCELServiceImpl(logger)
+	facts := &bean.ArtifactFacts{
+		AppName:              "payments",
+		SourceEnvName:        "staging",
+		EnvName:              "prod",
+		IsProdEnv:            true,
+		ContainerImage:       "docker.io/devtron/payments:abc-12",
+		ContainerImageTag:    "abc-12",
+		ImageLabels:          []string{"release-candidate"},
+		DeployedOnSource:     true,
+		PostStageStatus:      "Succeeded",
+		PostStageTestsPassed: true,
+		ArtifactAgeHours:     30,
+		IsScanned:            true,
+		ApprovalCount:        1,
+		TestPassRate:         97.5,
+	}
+	tests := []struct {
+		expression string
+		want       bool
+		wantErr    bool
+	}{
+		{expression: "postStageTestsPassed && !isVulnerable", want: true},
+		{expression: "artifactAgeHours < 24", want: false},
+		{expression: "'release-candidate' in imageLabels && sourceEnvName == 'staging'", want: true},
+		{expression: "isScanned && approvalCount >= 2", want: false},
+		{expression: "testPassRate >= 95", want: true},
+		{expression: "unknownFact == true", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := evaluator.EvaluateCELRequest(BuildCELRequest(tt.expression, facts))
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("zero value facts validate", func(t *testing.T) {
+		_, _, err := evaluator.Validate(BuildCELRequest("size(imageLabels) > 0", &bean.ArtifactFacts{}))
+		assert.Nil(t, err)
+	})
+
+	t.Run("cross type numeric comparisons only for promotion conditions", func(t *testing.T) {
+		_, _, err := evaluator.Validate(cel.Request{Expression: "testPassRate >= 95", ExpressionMetadata: cel.ExpressionMetadata{Params: BuildCELParams(facts)}})
+		assert.NotNil(t, err)
+	})
+}
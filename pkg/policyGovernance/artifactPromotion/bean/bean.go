@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type PromotionStatus string
+
+const (
+	PromotionAwaitingApproval PromotionStatus = "AWAITING_APPROVAL"
+	PromotionPromoted         PromotionStatus = "PROMOTED"
+	PromotionPolicyFailed     PromotionStatus = "POLICY_FAILED"
+	PromotionCancelled        PromotionStatus = "CANCELLED"
+)
+
+type PromotionPolicyDto struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name" validate:"required,max=250"`
+	Description string `json:"description"`
+	// Conditions are CEL expressions evaluated over the artifact facts, all of them must evaluate to true for promotion
+	Conditions     []*PromotionCondition `json:"conditions" validate:"dive"`
+	ApprovalCount  int                   `json:"approvalCount" validate:"min=0,max=10"`
+	EnvironmentIds []int                 `json:"environmentIds"`
+	ClusterIds     []int                 `json:"clusterIds"`
+	UserId         int32                 `json:"-"`
+}
+
+type PromotionCondition struct {
+	Expression string `json:"expression" validate:"required"`
+	// Message is shown to the user when the condition fails
+	Message string `json:"message"`
+}
+
+type PromoteArtifactRequest struct {
+	AppId             int    `json:"appId" validate:"required,min=1"`
+	ArtifactId        int    `json:"artifactId" validate:"required,min=1"`
+	SourcePipelineId  int    `json:"sourcePipelineId" validate:"required,min=1"`
+	TargetPipelineIds []int  `json:"targetPipelineIds" validate:"required,min=1"`
+	Comment           string `json:"comment"`
+	UserId            int32  `json:"-"`
+}
+
+type PolicyResultDto struct {
+	PolicyId   int    `json:"policyId"`
+	PolicyName string `json:"policyName"`
+	Expression string `json:"expression"`
+	Passed     bool   `json:"passed"`
+	Message    string `json:"message,omitempty"`
+}
+
+type PromotionResponse struct {
+	PromotionRequestId int                `json:"promotionRequestId"`
+	TargetPipelineId   int                `json:"targetPipelineId"`
+	TargetEnvName      string             `json:"targetEnvName"`
+	Status             PromotionStatus    `json:"status"`
+	RequiredApprovals  int                `json:"requiredApprovals"`
+	ApprovalCount      int                `json:"approvalCount"`
+	PolicyResults      []*PolicyResultDto `json:"policyResults"`
+}
+
+type PromotionApprovalDto struct {
+	UserId     int32     `json:"userId"`
+	UserEmail  string    `json:"userEmail"`
+	ApprovedOn time.Time `json:"approvedOn"`
+}
+
+type PromotionHistoryDto struct {
+	Id                int                     `json:"id"`
+	ArtifactId        int                     `json:"artifactId"`
+	Image             string                  `json:"image"`
+	SourcePipelineId  int                     `json:"sourcePipelineId"`
+	SourceEnvName     string                  `json:"sourceEnvName"`
+	TargetPipelineId  int                     `json:"targetPipelineId"`
+	TargetEnvName     string                  `json:"targetEnvName"`
+	Status            PromotionStatus         `json:"status"`
+	Comment           string                  `json:"comment"`
+	PolicyResults     []*PolicyResultDto      `json:"policyResults"`
+	RequiredApprovals int                     `json:"requiredApprovals"`
+	Approvals         []*PromotionApprovalDto `json:"approvals"`
+	RequestedBy       string                  `json:"requestedBy"`
+	RequestedOn       time.Time               `json:"requestedOn"`
+	PromotedOn        *time.Time              `json:"promotedOn,omitempty"`
+}
+
+type PromotionHistoryFilter struct {
+	AppId            int
+	ArtifactId       int
+	TargetPipelineId int
+}
+
+// PromotionMetadata is attached to artifacts listed for deployment on a pipeline where they were promoted
+type PromotionMetadata struct {
+	PromotionRequestId int       `json:"promotionRequestId"`
+	SourceEnvName      string    `json:"sourceEnvName"`
+	PromotedBy         string    `json:"promotedBy"`
+	PromotedOn         time.Time `json:"promotedOn"`
+}
+
+// ArtifactFacts are exposed to promotion policy conditions as CEL variables
+type ArtifactFacts struct {
+	AppName              string
+	SourceEnvName        string
+	EnvName              string
+	IsProdEnv            bool
+	ContainerRepo        string
+	ContainerImage       string
+	ContainerImageTag    string
+	ImageLabels          []string
+	DeployedOnSource     bool
+	PostStageStatus      string
+	PostStageTestsPassed bool
+	ArtifactAgeHours     int
+	IsScanned            bool
+	IsVulnerable         bool
+	ApprovalCount        int
+	HasTestReport        bool
+	TestsFailed          int
+	TestPassRate         float64
+	TestLineCoverage     float64
+}
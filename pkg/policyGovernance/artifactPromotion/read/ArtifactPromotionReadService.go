@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package read
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/artifactPromotion/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/artifactPromotion/repository"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type ArtifactPromotionReadService interface {
+	// GetPromotedArtifactsForPipeline returns promotion metadata of all artifacts promoted to the cd pipeline, keyed by artifact id
+	GetPromotedArtifactsForPipeline(pipelineId int) (map[int]*bean.PromotionMetadata, error)
+}
+
+type ArtifactPromotionReadServiceImpl struct {
+	logger                             *zap.SugaredLogger
+	artifactPromotionRequestRepository repository.ArtifactPromotionRequestRepository
+	pipelineRepository                 pipelineConfig.PipelineRepository
+	userService                        user.UserService
+}
+
+func NewArtifactPromotionReadServiceImpl(logger *zap.SugaredLogger,
+	artifactPromotionRequestRepository repository.ArtifactPromotionRequestRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	userService user.UserService) *ArtifactPromotionReadServiceImpl {
+	return &ArtifactPromotionReadServiceImpl{
+		logger:                             logger,
+		artifactPromotionRequestRepository: artifactPromotionRequestRepository,
+		pipelineRepository:                 pipelineRepository,
+		userService:                        userService,
+	}
+}
+
+func (impl *ArtifactPromotionReadServiceImpl) GetPromotedArtifactsForPipeline(pipelineId int) (map[int]*bean.PromotionMetadata, error) {
+	result := make(map[int]*bean.PromotionMetadata)
+	requests, err := impl.artifactPromotionRequestRepository.FindPromotedByTargetPipelineId(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching promoted artifacts", "pipelineId", pipelineId, "err", err)
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return result, nil
+	}
+	sourcePipelineIds, userIds := make([]int, 0, len(requests)), make([]int32, 0, len(requests))
+	for _, request := range requests {
+		sourcePipelineIds = append(sourcePipelineIds, request.SourcePipelineId)
+		userIds = append(userIds, request.CreatedBy)
+	}
+	sourcePipelines, err := impl.pipelineRepository.FindByIdsIn(sourcePipelineIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching source pipelines", "pipelineIds", sourcePipelineIds, "err", err)
+		return nil, err
+	}
+	pipelineIdEnvNameMap := make(map[int]string, len(sourcePipelines))
+	for _, sourcePipeline := range sourcePipelines {
+		pipelineIdEnvNameMap[sourcePipeline.Id] = sourcePipeline.Environment.Name
+	}
+	users, err := impl.userService.GetByIds(userIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching users", "userIds", userIds, "err", err)
+		return nil, err
+	}
+	userIdEmailMap := make(map[int32]string, len(users))
+	for _, userInfo := range users {
+		userIdEmailMap[userInfo.Id] = userInfo.EmailId
+	}
+	for _, request := range requests {
+		// requests are ordered latest first, keep the latest promotion of an artifact
+		if _, ok := result[request.CiArtifactId]; ok || request.PromotedOn == nil {
+			continue
+		}
+		result[request.CiArtifactId] = &bean.PromotionMetadata{
+			PromotionRequestId: request.Id,
+			SourceEnvName:      pipelineIdEnvNameMap[request.SourcePipelineId],
+			PromotedBy:         userIdEmailMap[request.CreatedBy],
+			PromotedOn:         *request.PromotedOn,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type ArtifactPromotionApproval struct {
+	tableName          struct{} `sql:"artifact_promotion_approval" pg:",discard_unknown_columns"`
+	Id                 int      `sql:"id,pk"`
+	PromotionRequestId int      `sql:"promotion_request_id,notnull"`
+	UserId             int32    `sql:"user_id,notnull"`
+	sql.AuditLog
+}
+
+type ArtifactPromotionApprovalRepository interface {
+	Save(tx *pg.Tx, approval *ArtifactPromotionApproval) error
+	FindByPromotionRequestIds(promotionRequestIds []int) ([]*ArtifactPromotionApproval, error)
+}
+
+type ArtifactPromotionApprovalRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewArtifactPromotionApprovalRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ArtifactPromotionApprovalRepositoryImpl {
+	return &ArtifactPromotionApprovalRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ArtifactPromotionApprovalRepositoryImpl) Save(tx *pg.Tx, approval *ArtifactPromotionApproval) error {
+	return tx.Insert(approval)
+}
+
+func (impl *ArtifactPromotionApprovalRepositoryImpl) FindByPromotionRequestIds(promotionRequestIds []int) ([]*ArtifactPromotionApproval, error) {
+	var approvals []*ArtifactPromotionApproval
+	if len(promotionRequestIds) == 0 {
+		return approvals, nil
+	}
+	err := impl.dbConnection.Model(&approvals).
+		Where("promotion_request_id IN (?)", pg.In(promotionRequestIds)).
+		Order("id ASC").
+		Select()
+	return approvals, err
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type ArtifactPromotionPolicy struct {
+	tableName     struct{} `sql:"artifact_promotion_policy" pg:",discard_unknown_columns"`
+	Id            int      `sql:"id,pk"`
+	Name          string   `sql:"name,notnull"`
+	Description   string   `sql:"description"`
+	Conditions    string   `sql:"conditions,notnull"`
+	ApprovalCount int      `sql:"approval_count,notnull"`
+	Active        bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ArtifactPromotionPolicyRepository interface {
+	sql.TransactionWrapper
+	Save(tx *pg.Tx, policy *ArtifactPromotionPolicy) error
+	Update(tx *pg.Tx, policy *ArtifactPromotionPolicy) error
+	FindById(id int) (*ArtifactPromotionPolicy, error)
+	FindActiveByName(name string) (*ArtifactPromotionPolicy, error)
+	FindByIds(ids []int) ([]*ArtifactPromotionPolicy, error)
+	FindAllActive() ([]*ArtifactPromotionPolicy, error)
+}
+
+type ArtifactPromotionPolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewArtifactPromotionPolicyRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, transactionUtilImpl *sql.TransactionUtilImpl) *ArtifactPromotionPolicyRepositoryImpl {
+	return &ArtifactPromotionPolicyRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (impl *ArtifactPromotionPolicyRepositoryImpl) Save(tx *pg.Tx, policy *ArtifactPromotionPolicy) error {
+	return tx.Insert(policy)
+}
+
+func (impl *ArtifactPromotionPolicyRepositoryImpl) Update(tx *pg.Tx, policy *ArtifactPromotionPolicy) error {
+	return tx.Update(policy)
+}
+
+func (impl *ArtifactPromotionPolicyRepositoryImpl) FindById(id int) (*ArtifactPromotionPolicy, error) {
+	policy := &ArtifactPromotionPolicy{}
+	err := impl.dbConnection.Model(policy).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return policy, err
+}
+
+func (impl *ArtifactPromotionPolicyRepositoryImpl) FindActiveByName(name string) (*ArtifactPromotionPolicy, error) {
+	policy := &ArtifactPromotionPolicy{}
+	err := impl.dbConnection.Model(policy).
+		Where("name = ?", name).
+		Where("active = ?", true).
+		Select()
+	return policy, err
+}
+
+func (impl *ArtifactPromotionPolicyRepositoryImpl) FindByIds(ids []int) ([]*ArtifactPromotionPolicy, error) {
+	var policies []*ArtifactPromotionPolicy
+	if len(ids) == 0 {
+		return policies, nil
+	}
+	err := impl.dbConnection.Model(&policies).
+		Where("id IN (?)", pg.In(ids)).
+		Where("active = ?", true).
+		Select()
+	return policies, err
+}
+
+func (impl *ArtifactPromotionPolicyRepositoryImpl) FindAllActive() ([]*ArtifactPromotionPolicy, error) {
+	var policies []*ArtifactPromotionPolicy
+	err := impl.dbConnection.Model(&policies).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return policies, err
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/artifactPromotion/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ArtifactPromotionRequest struct {
+	tableName         struct{}             `sql:"artifact_promotion_request" pg:",discard_unknown_columns"`
+	Id                int                  `sql:"id,pk"`
+	CiArtifactId      int                  `sql:"ci_artifact_id,notnull"`
+	SourcePipelineId  int                  `sql:"source_pipeline_id,notnull"`
+	TargetPipelineId  int                  `sql:"target_pipeline_id,notnull"`
+	Status            bean.PromotionStatus `sql:"status,notnull"`
+	PolicyResults     string               `sql:"policy_results"`
+	RequiredApprovals int                  `sql:"required_approvals,notnull"` // highest approval count of the policies applicable at request time
+	Comment           string               `sql:"comment"`
+	PromotedOn        *time.Time           `sql:"promoted_on"`
+	sql.AuditLog
+}
+
+type ArtifactPromotionRequestRepository interface {
+	sql.TransactionWrapper
+	Save(tx *pg.Tx, request *ArtifactPromotionRequest) error
+	Update(tx *pg.Tx, request *ArtifactPromotionRequest) error
+	FindById(id int) (*ArtifactPromotionRequest, error)
+	// FindPendingOrPromoted returns the latest request of the artifact on the target pipeline which is either awaiting approval or promoted
+	FindPendingOrPromoted(artifactId, targetPipelineId int) (*ArtifactPromotionRequest, error)
+	FindPromotedByTargetPipelineId(targetPipelineId int) ([]*ArtifactPromotionRequest, error)
+	FindByFilter(artifactId int, pipelineIds []int) ([]*ArtifactPromotionRequest, error)
+}
+
+type ArtifactPromotionRequestRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewArtifactPromotionRequestRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, transactionUtilImpl *sql.TransactionUtilImpl) *ArtifactPromotionRequestRepositoryImpl {
+	return &ArtifactPromotionRequestRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (impl *ArtifactPromotionRequestRepositoryImpl) Save(tx *pg.Tx, request *ArtifactPromotionRequest) error {
+	return tx.Insert(request)
+}
+
+func (impl *ArtifactPromotionRequestRepositoryImpl) Update(tx *pg.Tx, request *ArtifactPromotionRequest) error {
+	return tx.Update(request)
+}
+
+func (impl *ArtifactPromotionRequestRepositoryImpl) FindById(id int) (*ArtifactPromotionRequest, error) {
+	request := &ArtifactPromotionRequest{}
+	err := impl.dbConnection.Model(request).
+		Where("id = ?", id).
+		Select()
+	return request, err
+}
+
+func (impl *ArtifactPromotionRequestRepositoryImpl) FindPendingOrPromoted(artifactId, targetPipelineId int) (*ArtifactPromotionRequest, error) {
+	request := &ArtifactPromotionRequest{}
+	err := impl.dbConnection.Model(request).
+		Where("ci_artifact_id = ?", artifactId).
+		Where("target_pipeline_id = ?", targetPipelineId).
+		Where("status IN (?)", pg.In([]bean.PromotionStatus{bean.PromotionAwaitingApproval, bean.PromotionPromoted})).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	return request, err
+}
+
+func (impl *ArtifactPromotionRequestRepositoryImpl) FindPromotedByTargetPipelineId(targetPipelineId int) ([]*ArtifactPromotionRequest, error) {
+	var requests []*ArtifactPromotionRequest
+	err := impl.dbConnection.Model(&requests).
+		Where("target_pipeline_id = ?", targetPipelineId).
+		Where("status = ?", bean.PromotionPromoted).
+		Order("promoted_on DESC").
+		Select()
+	return requests, err
+}
+
+// FindByFilter returns promotion requests of the artifact (if non-zero) where either source or target is one of pipelineIds
+func (impl *ArtifactPromotionRequestRepositoryImpl) FindByFilter(artifactId int, pipelineIds []int) ([]*ArtifactPromotionRequest, error) {
+	var requests []*ArtifactPromotionRequest
+	if len(pipelineIds) == 0 {
+		return requests, nil
+	}
+	query := impl.dbConnection.Model(&requests).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.WhereOr("source_pipeline_id IN (?)", pg.In(pipelineIds)).
+				WhereOr("target_pipeline_id IN (?)", pg.In(pipelineIds)), nil
+		})
+	if artifactId > 0 {
+		query = query.Where("ci_artifact_id = ?", artifactId)
+	}
+	err := query.Order("id DESC").Select()
+	return requests, err
+}
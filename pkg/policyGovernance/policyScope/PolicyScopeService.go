@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policyScope
+
+import (
+	devtronResourceBean "github.com/devtron-labs/devtron/pkg/devtronResource/bean"
+	"github.com/devtron-labs/devtron/pkg/devtronResource/read"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// PolicyScope is the set of environments and clusters a policy is mapped on, a policy without any applies globally
+type PolicyScope struct {
+	EnvironmentIds []int
+	ClusterIds     []int
+}
+
+// PolicyScopeService maps policies on environments and clusters through resource qualifier mappings
+type PolicyScopeService interface {
+	// SaveScope replaces the mappings of the policy with the given scope
+	SaveScope(tx *pg.Tx, resourceType resourceQualifiers.ResourceType, policyId int, scope *PolicyScope, userId int32) error
+	DeleteScope(tx *pg.Tx, resourceType resourceQualifiers.ResourceType, policyId int, userId int32) error
+	// GetScopes returns the scope of every policy keyed by policy id, globally applicable policies have an empty scope
+	GetScopes(resourceType resourceQualifiers.ResourceType, policyIds []int) (map[int]*PolicyScope, error)
+	// GetApplicablePolicyIds returns ids of the policies mapped on the environment, its cluster or globally
+	GetApplicablePolicyIds(resourceType resourceQualifiers.ResourceType, envId, clusterId int) ([]int, error)
+}
+
+type PolicyScopeServiceImpl struct {
+	logger                              *zap.SugaredLogger
+	qualifierMappingService             resourceQualifiers.QualifierMappingService
+	devtronResourceSearchableKeyService read.DevtronResourceSearchableKeyService
+}
+
+func NewPolicyScopeServiceImpl(logger *zap.SugaredLogger,
+	qualifierMappingService resourceQualifiers.QualifierMappingService,
+	devtronResourceSearchableKeyService read.DevtronResourceSearchableKeyService) *PolicyScopeServiceImpl {
+	return &PolicyScopeServiceImpl{
+		logger:                              logger,
+		qualifierMappingService:             qualifierMappingService,
+		devtronResourceSearchableKeyService: devtronResourceSearchableKeyService,
+	}
+}
+
+func (impl *PolicyScopeServiceImpl) SaveScope(tx *pg.Tx, resourceType resourceQualifiers.ResourceType, policyId int, scope *PolicyScope, userId int32) error {
+	err := impl.DeleteScope(tx, resourceType, policyId, userId)
+	if err != nil {
+		return err
+	}
+	_, err = impl.qualifierMappingService.CreateQualifierMappings(impl.buildMappings(resourceType, policyId, scope, userId), tx)
+	if err != nil {
+		impl.logger.Errorw("error in saving policy mappings", "resourceType", resourceType, "policyId", policyId, "err", err)
+	}
+	return err
+}
+
+func (impl *PolicyScopeServiceImpl) buildMappings(resourceType resourceQualifiers.ResourceType, policyId int, scope *PolicyScope, userId int32) []*resourceQualifiers.QualifierMapping {
+	searchableKeyNameIdMap := impl.devtronResourceSearchableKeyService.GetAllSearchableKeyNameIdMap()
+	buildMapping := func(qualifier resourceQualifiers.Qualifier, identifierKey, identifierValue int) *resourceQualifiers.QualifierMapping {
+		return &resourceQualifiers.QualifierMapping{
+			ResourceId:         policyId,
+			ResourceType:       resourceType,
+			QualifierId:        int(qualifier),
+			IdentifierKey:      identifierKey,
+			IdentifierValueInt: identifierValue,
+			Active:             true,
+			AuditLog:           sql.NewDefaultAuditLog(userId),
+		}
+	}
+	mappings := make([]*resourceQualifiers.QualifierMapping, 0, len(scope.EnvironmentIds)+len(scope.ClusterIds))
+	for _, envId := range scope.EnvironmentIds {
+		mappings = append(mappings, buildMapping(resourceQualifiers.ENV_QUALIFIER, searchableKeyNameIdMap[devtronResourceBean.DEVTRON_RESOURCE_SEARCHABLE_KEY_ENV_ID], envId))
+	}
+	for _, clusterId := range scope.ClusterIds {
+		mappings = append(mappings, buildMapping(resourceQualifiers.CLUSTER_QUALIFIER, searchableKeyNameIdMap[devtronResourceBean.DEVTRON_RESOURCE_SEARCHABLE_KEY_CLUSTER_ID], clusterId))
+	}
+	if len(mappings) == 0 {
+		mappings = append(mappings, buildMapping(resourceQualifiers.GLOBAL_QUALIFIER, 0, 0))
+	}
+	return mappings
+}
+
+func (impl *PolicyScopeServiceImpl) DeleteScope(tx *pg.Tx, resourceType resourceQualifiers.ResourceType, policyId int, userId int32) error {
+	mappings, err := impl.qualifierMappingService.GetQualifierMappings(resourceType, nil, []int{policyId})
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching policy mappings", "resourceType", resourceType, "policyId", policyId, "err", err)
+		return err
+	}
+	if len(mappings) == 0 {
+		return nil
+	}
+	mappingIds := make([]int, 0, len(mappings))
+	for _, mapping := range mappings {
+		mappingIds = append(mappingIds, mapping.Id)
+	}
+	err = impl.qualifierMappingService.DeleteAllByIds(mappingIds, userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting policy mappings", "resourceType", resourceType, "policyId", policyId, "err", err)
+	}
+	return err
+}
+
+func (impl *PolicyScopeServiceImpl) GetScopes(resourceType resourceQualifiers.ResourceType, policyIds []int) (map[int]*PolicyScope, error) {
+	scopes := make(map[int]*PolicyScope, len(policyIds))
+	if len(policyIds) == 0 {
+		return scopes, nil
+	}
+	for _, policyId := range policyIds {
+		scopes[policyId] = &PolicyScope{}
+	}
+	mappings, err := impl.qualifierMappingService.GetQualifierMappings(resourceType, nil, policyIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching policy mappings", "resourceType", resourceType, "policyIds", policyIds, "err", err)
+		return nil, err
+	}
+	for _, mapping := range mappings {
+		scope, ok := scopes[mapping.ResourceId]
+		if !ok {
+			continue
+		}
+		switch resourceQualifiers.Qualifier(mapping.QualifierId) {
+		case resourceQualifiers.ENV_QUALIFIER:
+			scope.EnvironmentIds = append(scope.EnvironmentIds, mapping.IdentifierValueInt)
+		case resourceQualifiers.CLUSTER_QUALIFIER:
+			scope.ClusterIds = append(scope.ClusterIds, mapping.IdentifierValueInt)
+		}
+	}
+	return scopes, nil
+}
+
+func (impl *PolicyScopeServiceImpl) GetApplicablePolicyIds(resourceType resourceQualifiers.ResourceType, envId, clusterId int) ([]int, error) {
+	valuesMap := map[resourceQualifiers.Qualifier][][]int{
+		resourceQualifiers.APP_AND_ENV_QUALIFIER: {{}, {}},
+		resourceQualifiers.ENV_QUALIFIER:         {{envId}},
+		resourceQualifiers.CLUSTER_QUALIFIER:     {{clusterId}},
+	}
+	mappings, err := impl.qualifierMappingService.GetQualifierMappingsForListOfQualifierValues(resourceType, valuesMap, nil)
+	if err != nil {
+		impl.logger.Errorw("error in fetching policy mappings", "resourceType", resourceType, "envId", envId, "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	policyIds := make([]int, 0, len(mappings))
+	for _, mapping := range mappings {
+		policyIds = append(policyIds, mapping.ResourceId)
+	}
+	return policyIds, nil
+}
@@ -85,15 +85,15 @@ func NewImageScanServiceImpl(Logger *zap.SugaredLogger, scanHistoryRepository re
 	cdWorkflowReadService read.CdWorkflowReadService) *ImageScanServiceImpl {
 	return &ImageScanServiceImpl{Logger: Logger, scanHistoryRepository: scanHistoryRepository, scanResultRepository: scanResultRepository,
 		scanObjectMetaRepository: scanObjectMetaRepository, cveStoreRepository: cveStoreRepository,
-		imageScanDeployInfoRepository:             imageScanDeployInfoRepository,
-		userService:                               userService,
-		appRepository:                             appRepository,
-		envService:                                envService,
-		ciArtifactRepository:                      ciArtifactRepository,
-		policyService:                             policyService,
-		pipelineRepository:                        pipelineRepository,
-		ciPipelineRepository:                      ciPipelineRepository,
-		scanToolMetaDataRepository:                scanToolMetaDataRepository,
+		imageScanDeployInfoRepository: imageScanDeployInfoRepository,
+		userService:                   userService,
+		appRepository:                 appRepository,
+		envService:                    envService,
+		ciArtifactRepository:          ciArtifactRepository,
+		policyService:                 policyService,
+		pipelineRepository:            pipelineRepository,
+		ciPipelineRepository:          ciPipelineRepository,
+		scanToolMetaDataRepository:    scanToolMetaDataRepository,
 		scanToolExecutionHistoryMappingRepository: scanToolExecutionHistoryMappingRepository,
 		cvePolicyRepository:                       cvePolicyRepository,
 		cdWorkflowReadService:                     cdWorkflowReadService,
@@ -0,0 +1,775 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageSigning
+
+import (
+	"crypto/x509"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/util"
+	argoApplicationBean "github.com/devtron-labs/devtron/pkg/argoApplication/bean"
+	repository3 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/policyScope"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/adapter"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/helper"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/repository"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ImageSigningService interface {
+	SaveSigningKey(request *bean.SigningKeyDto) (*bean.SigningKeyDto, error)
+	GetAllSigningKeys() ([]*bean.SigningKeyDto, error)
+	DeleteSigningKey(id int, userId int32) error
+
+	SavePolicy(request *bean.SignaturePolicyDto) (*bean.SignaturePolicyDto, error)
+	GetAllPolicies() ([]*bean.SignaturePolicyDto, error)
+	DeletePolicy(id int, userId int32) error
+
+	// SignArtifact records the keyless signatures and attestations reported by ci-runner once they are verified and signs
+	// the artifact digest with the default signing key, it is invoked by the orchestrator while handling the ci success
+	// event once the artifact is saved. The signature is recorded in devtron and not pushed to the registry.
+	SignArtifact(artifact *repository2.CiArtifact, keylessSignatures []*bean.KeylessSignatureMetadata, userId int32) error
+	// VerifyArtifactForDeployment evaluates all signature policies applicable on the environment (and its cluster)
+	// and persists the result against the artifact
+	VerifyArtifactForDeployment(artifact *repository2.CiArtifact, cdPipelineId, envId int, userId int32) (*bean.DeploymentVerificationResult, error)
+	// GetSignatureSummaryForArtifacts returns signatures and the latest verifications for artifacts keyed by artifact id,
+	// verifications are filtered on cdPipelineId when it is non-zero
+	GetSignatureSummaryForArtifacts(artifactIdDigestMap map[int]string, cdPipelineId int) (map[int]*bean.ArtifactSignatureSummary, error)
+	GetArtifactSignatureDetail(artifactId int) (*bean.ArtifactSignatureSummary, error)
+}
+
+type ImageSigningServiceImpl struct {
+	logger                               *zap.SugaredLogger
+	imageSigningKeyRepository            repository.ImageSigningKeyRepository
+	imageSignatureRepository             repository.ImageSignatureRepository
+	imageSignaturePolicyRepository       repository.ImageSignaturePolicyRepository
+	imageSignatureVerificationRepository repository.ImageSignatureVerificationRepository
+	policyScopeService                   policyScope.PolicyScopeService
+	environmentRepository                repository3.EnvironmentRepository
+	ciArtifactRepository                 repository2.CiArtifactRepository
+	k8sUtil                              *k8s.K8sServiceImpl
+	// fulcioRoots are the roots keyless certificates have to chain up to, nil if keyless signing is not configured
+	fulcioRoots *x509.CertPool
+}
+
+func NewImageSigningServiceImpl(logger *zap.SugaredLogger,
+	imageSigningKeyRepository repository.ImageSigningKeyRepository,
+	imageSignatureRepository repository.ImageSignatureRepository,
+	imageSignaturePolicyRepository repository.ImageSignaturePolicyRepository,
+	imageSignatureVerificationRepository repository.ImageSignatureVerificationRepository,
+	policyScopeService policyScope.PolicyScopeService,
+	environmentRepository repository3.EnvironmentRepository,
+	ciArtifactRepository repository2.CiArtifactRepository,
+	k8sUtil *k8s.K8sServiceImpl) (*ImageSigningServiceImpl, error) {
+	impl := &ImageSigningServiceImpl{
+		logger:                               logger,
+		imageSigningKeyRepository:            imageSigningKeyRepository,
+		imageSignatureRepository:             imageSignatureRepository,
+		imageSignaturePolicyRepository:       imageSignaturePolicyRepository,
+		imageSignatureVerificationRepository: imageSignatureVerificationRepository,
+		policyScopeService:                   policyScopeService,
+		environmentRepository:                environmentRepository,
+		ciArtifactRepository:                 ciArtifactRepository,
+		k8sUtil:                              k8sUtil,
+	}
+	cfg := &bean.ImageSigningConfig{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+	if len(strings.TrimSpace(cfg.FulcioRootCerts)) > 0 {
+		fulcioRoots, err := helper.ParseCertPool(cfg.FulcioRootCerts)
+		if err != nil {
+			logger.Errorw("error in parsing fulcio root certificates", "err", err)
+			return nil, err
+		}
+		impl.fulcioRoots = fulcioRoots
+	}
+	return impl, nil
+}
+
+func (impl *ImageSigningServiceImpl) SaveSigningKey(request *bean.SigningKeyDto) (*bean.SigningKeyDto, error) {
+	existing, err := impl.imageSigningKeyRepository.FindActiveByName(request.Name)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching signing key by name", "name", request.Name, "err", err)
+		return nil, err
+	} else if err == nil && existing.Id != request.Id {
+		return nil, util.NewApiError(http.StatusConflict, "signing key with this name already exists", "duplicate signing key name")
+	}
+	key := &repository.ImageSigningKey{
+		Id:        request.Id,
+		Name:      request.Name,
+		IsDefault: request.IsDefault,
+		Active:    true,
+		AuditLog:  adapter.NewAuditLog(request.UserId),
+	}
+	privateKey := ""
+	if request.Id > 0 {
+		savedKey, err := impl.imageSigningKeyRepository.FindById(request.Id)
+		if err == pg.ErrNoRows {
+			return nil, util.NewApiError(http.StatusNotFound, "signing key not found", "signing key not found")
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching signing key", "id", request.Id, "err", err)
+			return nil, err
+		}
+		// key material is immutable, a new key must be created for rotation so that older signatures stay verifiable
+		key.PublicKey, key.PrivateKeySecretName, key.CreatedOn, key.CreatedBy = savedKey.PublicKey, savedKey.PrivateKeySecretName, savedKey.CreatedOn, savedKey.CreatedBy
+	} else {
+		key.PublicKey, privateKey, err = impl.getKeyMaterial(request)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if key.IsDefault && len(key.PrivateKeySecretName) == 0 && len(privateKey) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "only a key with private key can be used for signing", "default key without private key")
+	}
+
+	tx, err := impl.imageSigningKeyRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.imageSigningKeyRepository.RollbackTx(tx)
+	if key.IsDefault {
+		err = impl.imageSigningKeyRepository.UnsetDefault(tx, request.UserId)
+		if err != nil {
+			impl.logger.Errorw("error in un setting default signing key", "err", err)
+			return nil, err
+		}
+	}
+	if key.Id > 0 {
+		err = impl.imageSigningKeyRepository.Update(tx, key)
+	} else {
+		err = impl.imageSigningKeyRepository.Save(tx, key)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving signing key", "name", key.Name, "err", err)
+		return nil, err
+	}
+	if len(privateKey) > 0 {
+		// the secret is named after the key id so the row is saved first, a failure here rolls the row back
+		key.PrivateKeySecretName, err = impl.savePrivateKeySecret(key.Id, privateKey)
+		if err != nil {
+			return nil, err
+		}
+		err = impl.imageSigningKeyRepository.Update(tx, key)
+		if err != nil {
+			impl.logger.Errorw("error in saving signing key secret reference", "name", key.Name, "err", err)
+			return nil, err
+		}
+	}
+	err = impl.imageSigningKeyRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return adapter.BuildSigningKeyDto(key), nil
+}
+
+// savePrivateKeySecret keeps the private key in a kubernetes secret in the devtron namespace and returns the secret name
+func (impl *ImageSigningServiceImpl) savePrivateKeySecret(keyId int, privateKey string) (string, error) {
+	k8sClient, err := impl.k8sUtil.GetCoreV1ClientInCluster()
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s client", "err", err)
+		return "", err
+	}
+	secretName := bean.SigningKeySecretPrefix + strconv.Itoa(keyId)
+	data := map[string][]byte{bean.SigningKeySecretKey: []byte(privateKey)}
+	_, err = impl.k8sUtil.CreateSecret(argoApplicationBean.DevtronCDNamespae, data, secretName, "", k8sClient, nil, nil)
+	if err != nil {
+		impl.logger.Errorw("error in creating signing key secret", "secretName", secretName, "err", err)
+		return "", err
+	}
+	return secretName, nil
+}
+
+func (impl *ImageSigningServiceImpl) getPrivateKey(key *repository.ImageSigningKey) (string, error) {
+	k8sClient, err := impl.k8sUtil.GetCoreV1ClientInCluster()
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s client", "err", err)
+		return "", err
+	}
+	secret, err := impl.k8sUtil.GetSecret(argoApplicationBean.DevtronCDNamespae, key.PrivateKeySecretName, k8sClient)
+	if err != nil {
+		impl.logger.Errorw("error in fetching signing key secret", "keyId", key.Id, "secretName", key.PrivateKeySecretName, "err", err)
+		return "", err
+	}
+	privateKey := string(secret.Data[bean.SigningKeySecretKey])
+	if len(privateKey) == 0 {
+		return "", fmt.Errorf("signing key secret %s has no %s", key.PrivateKeySecretName, bean.SigningKeySecretKey)
+	}
+	return privateKey, nil
+}
+
+func (impl *ImageSigningServiceImpl) deletePrivateKeySecret(key *repository.ImageSigningKey) error {
+	k8sClient, err := impl.k8sUtil.GetCoreV1ClientInCluster()
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s client", "err", err)
+		return err
+	}
+	err = impl.k8sUtil.DeleteSecret(argoApplicationBean.DevtronCDNamespae, key.PrivateKeySecretName, k8sClient)
+	if err != nil && !k8sError.IsNotFound(err) {
+		impl.logger.Errorw("error in deleting signing key secret", "keyId", key.Id, "secretName", key.PrivateKeySecretName, "err", err)
+		return err
+	}
+	return nil
+}
+
+// getKeyMaterial generates a new key pair if no key is provided, public key only keys can be used for verification of
+// signatures created outside devtron
+func (impl *ImageSigningServiceImpl) getKeyMaterial(request *bean.SigningKeyDto) (publicKey string, privateKey string, err error) {
+	switch {
+	case len(request.PrivateKey) > 0:
+		publicKey, err = helper.GetPublicKeyFromPrivateKey(request.PrivateKey)
+		if err != nil {
+			impl.logger.Errorw("invalid private key", "name", request.Name, "err", err)
+			return "", "", util.NewApiError(http.StatusBadRequest, "invalid private key, only unencrypted ECDSA keys are supported", err.Error())
+		}
+		return publicKey, request.PrivateKey, nil
+	case len(request.PublicKey) > 0:
+		err = helper.ValidatePublicKey(request.PublicKey)
+		if err != nil {
+			impl.logger.Errorw("invalid public key", "name", request.Name, "err", err)
+			return "", "", util.NewApiError(http.StatusBadRequest, "invalid public key, only ECDSA keys are supported", err.Error())
+		}
+		return request.PublicKey, "", nil
+	default:
+		publicKey, privateKey, err = helper.GenerateKeyPair()
+		if err != nil {
+			impl.logger.Errorw("error in generating key pair", "name", request.Name, "err", err)
+		}
+		return publicKey, privateKey, err
+	}
+}
+
+func (impl *ImageSigningServiceImpl) GetAllSigningKeys() ([]*bean.SigningKeyDto, error) {
+	keys, err := impl.imageSigningKeyRepository.FindAllActive()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching signing keys", "err", err)
+		return nil, err
+	}
+	result := make([]*bean.SigningKeyDto, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, adapter.BuildSigningKeyDto(key))
+	}
+	return result, nil
+}
+
+func (impl *ImageSigningServiceImpl) DeleteSigningKey(id int, userId int32) error {
+	key, err := impl.imageSigningKeyRepository.FindById(id)
+	if err == pg.ErrNoRows {
+		return util.NewApiError(http.StatusNotFound, "signing key not found", "signing key not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching signing key", "id", id, "err", err)
+		return err
+	}
+	key.Active = false
+	key.IsDefault = false
+	key.UpdatedOn = time.Now()
+	key.UpdatedBy = userId
+	tx, err := impl.imageSigningKeyRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.imageSigningKeyRepository.RollbackTx(tx)
+	err = impl.imageSigningKeyRepository.Update(tx, key)
+	if err != nil {
+		impl.logger.Errorw("error in deleting signing key", "id", id, "err", err)
+		return err
+	}
+	err = impl.imageSigningKeyRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return err
+	}
+	// the public key is kept so that signatures created with the key can still be verified
+	if len(key.PrivateKeySecretName) > 0 {
+		return impl.deletePrivateKeySecret(key)
+	}
+	return nil
+}
+
+func (impl *ImageSigningServiceImpl) SavePolicy(request *bean.SignaturePolicyDto) (*bean.SignaturePolicyDto, error) {
+	if len(request.TrustedKeyIds) == 0 && len(request.TrustedIdentities) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "at least one trusted key or keyless identity is required", "no trust root in policy")
+	}
+	keys, err := impl.imageSigningKeyRepository.FindByIds(request.TrustedKeyIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching signing keys", "keyIds", request.TrustedKeyIds, "err", err)
+		return nil, err
+	} else if len(keys) != len(request.TrustedKeyIds) {
+		return nil, util.NewApiError(http.StatusBadRequest, "one or more trusted keys do not exist", "invalid trusted key ids")
+	}
+	policy, err := adapter.BuildPolicyModel(request)
+	if err != nil {
+		impl.logger.Errorw("error in building signature policy", "request", request, "err", err)
+		return nil, err
+	}
+	if request.Id > 0 {
+		savedPolicy, err := impl.getPolicyById(request.Id)
+		if err != nil {
+			return nil, err
+		}
+		policy.CreatedOn, policy.CreatedBy = savedPolicy.CreatedOn, savedPolicy.CreatedBy
+	}
+
+	tx, err := impl.imageSignaturePolicyRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.imageSignaturePolicyRepository.RollbackTx(tx)
+	if policy.Id > 0 {
+		err = impl.imageSignaturePolicyRepository.Update(tx, policy)
+	} else {
+		err = impl.imageSignaturePolicyRepository.Save(tx, policy)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving signature policy", "name", policy.Name, "err", err)
+		return nil, err
+	}
+	scope := &policyScope.PolicyScope{EnvironmentIds: request.EnvironmentIds, ClusterIds: request.ClusterIds}
+	err = impl.policyScopeService.SaveScope(tx, resourceQualifiers.ImageSignaturePolicy, policy.Id, scope, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.imageSignaturePolicyRepository.CommitTx(tx)
+	if err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	request.Id = policy.Id
+	return request, nil
+}
+
+func (impl *ImageSigningServiceImpl) getPolicyById(id int) (*repository.ImageSignaturePolicy, error) {
+	policy, err := impl.imageSignaturePolicyRepository.FindById(id)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "signature policy not found", "signature policy not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching signature policy", "id", id, "err", err)
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (impl *ImageSigningServiceImpl) GetAllPolicies() ([]*bean.SignaturePolicyDto, error) {
+	policies, err := impl.imageSignaturePolicyRepository.FindAllActive()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching signature policies", "err", err)
+		return nil, err
+	}
+	result := make([]*bean.SignaturePolicyDto, 0, len(policies))
+	if len(policies) == 0 {
+		return result, nil
+	}
+	policyIds := make([]int, 0, len(policies))
+	for _, policy := range policies {
+		policyIds = append(policyIds, policy.Id)
+	}
+	scopes, err := impl.policyScopeService.GetScopes(resourceQualifiers.ImageSignaturePolicy, policyIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		dto, err := adapter.BuildPolicyDto(policy, scopes[policy.Id].EnvironmentIds, scopes[policy.Id].ClusterIds)
+		if err != nil {
+			impl.logger.Errorw("error in building signature policy", "policyId", policy.Id, "err", err)
+			return nil, err
+		}
+		result = append(result, dto)
+	}
+	return result, nil
+}
+
+func (impl *ImageSigningServiceImpl) DeletePolicy(id int, userId int32) error {
+	policy, err := impl.getPolicyById(id)
+	if err != nil {
+		return err
+	}
+	policy.Active = false
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = userId
+	tx, err := impl.imageSignaturePolicyRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.imageSignaturePolicyRepository.RollbackTx(tx)
+	err = impl.imageSignaturePolicyRepository.Update(tx, policy)
+	if err != nil {
+		impl.logger.Errorw("error in deleting signature policy", "id", id, "err", err)
+		return err
+	}
+	err = impl.policyScopeService.DeleteScope(tx, resourceQualifiers.ImageSignaturePolicy, id, userId)
+	if err != nil {
+		return err
+	}
+	return impl.imageSignaturePolicyRepository.CommitTx(tx)
+}
+
+func (impl *ImageSigningServiceImpl) SignArtifact(artifact *repository2.CiArtifact, keylessSignatures []*bean.KeylessSignatureMetadata, userId int32) error {
+	for _, keylessSignature := range keylessSignatures {
+		if keylessSignature == nil {
+			continue
+		}
+		signature, err := impl.verifyKeylessSignature(artifact, keylessSignature, userId)
+		if err != nil {
+			// an unverifiable signature is dropped, policies trusting the identity fail for the artifact
+			impl.logger.Warnw("skipping keyless signature which could not be verified", "artifactId", artifact.Id, "signatureType", keylessSignature.SignatureType, "err", err)
+			continue
+		}
+		err = impl.imageSignatureRepository.Save(signature)
+		if err != nil {
+			impl.logger.Errorw("error in saving keyless signature", "artifactId", artifact.Id, "err", err)
+			return err
+		}
+	}
+	signingKey, err := impl.imageSigningKeyRepository.FindDefault()
+	if err == pg.ErrNoRows {
+		return nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching default signing key", "err", err)
+		return err
+	}
+	if len(artifact.ImageDigest) == 0 {
+		impl.logger.Warnw("skipping image signing as image digest is not available", "artifactId", artifact.Id, "image", artifact.Image)
+		return nil
+	}
+	privateKey, err := impl.getPrivateKey(signingKey)
+	if err != nil {
+		return err
+	}
+	payload, err := helper.BuildSimpleSigningPayload(helper.GetImageRepository(artifact.Image), artifact.ImageDigest)
+	if err != nil {
+		impl.logger.Errorw("error in building signing payload", "artifactId", artifact.Id, "err", err)
+		return err
+	}
+	signatureValue, err := helper.Sign(privateKey, payload)
+	if err != nil {
+		impl.logger.Errorw("error in signing image", "artifactId", artifact.Id, "keyId", signingKey.Id, "err", err)
+		return err
+	}
+	signature := &repository.ImageSignature{
+		CiArtifactId:  artifact.Id,
+		Image:         artifact.Image,
+		ImageDigest:   artifact.ImageDigest,
+		SignatureType: bean.SignatureTypeSignature,
+		SigningMode:   bean.SigningModeKey,
+		SigningKeyId:  signingKey.Id,
+		Payload:       string(payload),
+		Signature:     signatureValue,
+		AuditLog:      adapter.NewAuditLog(userId),
+	}
+	err = impl.imageSignatureRepository.Save(signature)
+	if err != nil {
+		impl.logger.Errorw("error in saving image signature", "artifactId", artifact.Id, "err", err)
+	}
+	return err
+}
+
+// verifyKeylessSignature checks the reported certificate chain, signature and signed digest and builds the signature
+// to record with the identity of the certificate, the identity and predicate type reported by ci-runner are not trusted
+func (impl *ImageSigningServiceImpl) verifyKeylessSignature(artifact *repository2.CiArtifact, metadata *bean.KeylessSignatureMetadata, userId int32) (*repository.ImageSignature, error) {
+	if len(metadata.SignatureType) == 0 {
+		metadata.SignatureType = bean.SignatureTypeSignature
+	}
+	payload := []byte(metadata.Payload)
+	identity, err := helper.VerifyKeylessSignature(impl.fulcioRoots, metadata.Certificate+"\n"+metadata.CertificateChain,
+		helper.GetSignedBytes(metadata.SignatureType, payload), metadata.Signature)
+	if err != nil {
+		return nil, err
+	}
+	signedDigest, err := helper.GetSignedDigest(metadata.SignatureType, payload)
+	if err != nil {
+		return nil, err
+	} else if len(artifact.ImageDigest) == 0 || signedDigest != artifact.ImageDigest {
+		return nil, fmt.Errorf("signature is for digest %s and not for the image digest %s", signedDigest, artifact.ImageDigest)
+	}
+	predicateType := ""
+	if metadata.SignatureType == bean.SignatureTypeAttestation {
+		statement, err := helper.ParseInTotoStatement(payload)
+		if err != nil {
+			return nil, err
+		}
+		predicateType = statement.PredicateType
+	}
+	return adapter.BuildKeylessSignatureModel(artifact.Id, artifact.Image, artifact.ImageDigest, metadata, identity, predicateType, userId), nil
+}
+
+func (impl *ImageSigningServiceImpl) VerifyArtifactForDeployment(artifact *repository2.CiArtifact, cdPipelineId, envId int, userId int32) (*bean.DeploymentVerificationResult, error) {
+	result := &bean.DeploymentVerificationResult{Status: bean.VerificationNotRequired, Results: make([]*bean.VerificationResultDto, 0)}
+	policies, err := impl.getApplicablePolicies(envId)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return result, nil
+	}
+	signatures, err := impl.getArtifactSignatures(artifact)
+	if err != nil {
+		return nil, err
+	}
+	trustedKeyIds := make([]int, 0)
+	for _, policy := range policies {
+		trustedKeyIds = append(trustedKeyIds, policy.TrustedKeyIds...)
+	}
+	keys, err := impl.imageSigningKeyRepository.FindByIds(trustedKeyIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching trusted keys", "keyIds", trustedKeyIds, "err", err)
+		return nil, err
+	}
+	keyIdKeyMap := make(map[int]*repository.ImageSigningKey, len(keys))
+	for _, key := range keys {
+		keyIdKeyMap[key.Id] = key
+	}
+
+	result.Status = bean.VerificationPassed
+	verifications := make([]*repository.ImageSignatureVerification, 0, len(policies))
+	for _, policy := range policies {
+		policyResult, signatureId, err := impl.evaluatePolicy(policy, artifact, signatures, keyIdKeyMap)
+		if err != nil {
+			impl.logger.Errorw("error in evaluating signature policy", "policyId", policy.Id, "artifactId", artifact.Id, "err", err)
+			return nil, err
+		}
+		policyResult.EnvId = envId
+		policyResult.CdPipelineId = cdPipelineId
+		if policyResult.Status == bean.VerificationFailed {
+			result.Status = bean.VerificationFailed
+		}
+		result.Results = append(result.Results, policyResult)
+		verifications = append(verifications, adapter.BuildVerificationModel(artifact.Id, cdPipelineId, envId, policyResult, signatureId, userId))
+	}
+	err = impl.imageSignatureVerificationRepository.SaveAll(verifications)
+	if err != nil {
+		impl.logger.Errorw("error in saving signature verifications", "artifactId", artifact.Id, "cdPipelineId", cdPipelineId, "err", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (impl *ImageSigningServiceImpl) getApplicablePolicies(envId int) ([]*repository.ImageSignaturePolicy, error) {
+	env, err := impl.environmentRepository.FindById(envId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment", "envId", envId, "err", err)
+		return nil, err
+	}
+	policyIds, err := impl.policyScopeService.GetApplicablePolicyIds(resourceQualifiers.ImageSignaturePolicy, envId, env.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := impl.imageSignaturePolicyRepository.FindByIds(policyIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching signature policies", "policyIds", policyIds, "err", err)
+		return nil, err
+	}
+	return policies, nil
+}
+
+// getArtifactSignatures looks up signatures by digest so that signatures stay valid for artifacts re-created from the same image
+func (impl *ImageSigningServiceImpl) getArtifactSignatures(artifact *repository2.CiArtifact) ([]*repository.ImageSignature, error) {
+	var signatures []*repository.ImageSignature
+	var err error
+	if len(artifact.ImageDigest) > 0 {
+		signatures, err = impl.imageSignatureRepository.FindByImageDigest(artifact.ImageDigest)
+	} else {
+		signatures, err = impl.imageSignatureRepository.FindByArtifactIds([]int{artifact.Id})
+	}
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching image signatures", "artifactId", artifact.Id, "err", err)
+		return nil, err
+	}
+	return signatures, nil
+}
+
+// evaluatePolicy checks that the image carries a signature from a trusted signer and every required attestation.
+// Key based signatures are verified against the trusted public keys, keyless signatures are verified against their
+// certificate which has to chain up to the trusted fulcio roots and carry a trusted identity.
+func (impl *ImageSigningServiceImpl) evaluatePolicy(policy *repository.ImageSignaturePolicy, artifact *repository2.CiArtifact,
+	signatures []*repository.ImageSignature, keyIdKeyMap map[int]*repository.ImageSigningKey) (*bean.VerificationResultDto, int, error) {
+	policyDto, err := adapter.BuildPolicyDto(policy, nil, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	result := &bean.VerificationResultDto{
+		PolicyId:   policy.Id,
+		PolicyName: policy.Name,
+		Status:     bean.VerificationFailed,
+		Enforced:   policy.Enforce,
+		VerifiedOn: time.Now(),
+	}
+	isTrusted := func(signature *repository.ImageSignature) bool {
+		payload := []byte(signature.Payload)
+		if len(artifact.ImageDigest) > 0 {
+			signedDigest, err := helper.GetSignedDigest(signature.SignatureType, payload)
+			if err != nil || signedDigest != artifact.ImageDigest {
+				return false
+			}
+		}
+		signedBytes := helper.GetSignedBytes(signature.SignatureType, payload)
+		if signature.SigningMode == bean.SigningModeKeyless {
+			identity, err := helper.VerifyKeylessSignature(impl.fulcioRoots, signature.Certificate, signedBytes, signature.Signature)
+			return err == nil && helper.IsIdentityTrusted(policyDto.TrustedIdentities, identity.Issuer, identity.Subject)
+		}
+		for _, keyId := range policy.TrustedKeyIds {
+			if key, ok := keyIdKeyMap[keyId]; ok && helper.Verify(key.PublicKey, signedBytes, signature.Signature) == nil {
+				return true
+			}
+		}
+		return false
+	}
+
+	signatureId := 0
+	for _, signature := range signatures {
+		if signature.SignatureType == bean.SignatureTypeSignature && isTrusted(signature) {
+			signatureId = signature.Id
+			break
+		}
+	}
+	if signatureId == 0 {
+		result.Message = "image is not signed by any trusted key or identity"
+		return result, 0, nil
+	}
+	missingAttestations := make([]string, 0)
+	for _, predicateType := range policyDto.RequiredAttestations {
+		found := false
+		for _, signature := range signatures {
+			if signature.SignatureType == bean.SignatureTypeAttestation && signature.PredicateType == predicateType && isTrusted(signature) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missingAttestations = append(missingAttestations, predicateType)
+		}
+	}
+	if len(missingAttestations) > 0 {
+		result.Message = fmt.Sprintf("missing trusted attestations: %s", strings.Join(missingAttestations, ", "))
+		return result, signatureId, nil
+	}
+	result.Status = bean.VerificationPassed
+	result.Message = "image signature verified"
+	return result, signatureId, nil
+}
+
+func (impl *ImageSigningServiceImpl) GetSignatureSummaryForArtifacts(artifactIdDigestMap map[int]string, cdPipelineId int) (map[int]*bean.ArtifactSignatureSummary, error) {
+	summaries := make(map[int]*bean.ArtifactSignatureSummary, len(artifactIdDigestMap))
+	if len(artifactIdDigestMap) == 0 {
+		return summaries, nil
+	}
+	artifactIds := make([]int, 0, len(artifactIdDigestMap))
+	digests := make([]string, 0, len(artifactIdDigestMap))
+	for artifactId, digest := range artifactIdDigestMap {
+		artifactIds = append(artifactIds, artifactId)
+		if len(digest) > 0 {
+			digests = append(digests, digest)
+		}
+		summaries[artifactId] = &bean.ArtifactSignatureSummary{}
+	}
+	signaturesByArtifact, err := impl.imageSignatureRepository.FindByArtifactIds(artifactIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching image signatures", "artifactIds", artifactIds, "err", err)
+		return nil, err
+	}
+	signaturesByDigest, err := impl.imageSignatureRepository.FindByImageDigests(digests)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching image signatures", "digests", digests, "err", err)
+		return nil, err
+	}
+	keys, err := impl.imageSigningKeyRepository.FindAllActive()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching signing keys", "err", err)
+		return nil, err
+	}
+	keyIdNameMap := make(map[int]string, len(keys))
+	for _, key := range keys {
+		keyIdNameMap[key.Id] = key.Name
+	}
+	digestSignatures := make(map[string][]*repository.ImageSignature)
+	for _, signature := range signaturesByDigest {
+		digestSignatures[signature.ImageDigest] = append(digestSignatures[signature.ImageDigest], signature)
+	}
+	for artifactId, summary := range summaries {
+		seen := make(map[int]bool)
+		candidates := make([]*repository.ImageSignature, 0, len(signaturesByArtifact))
+		candidates = append(candidates, digestSignatures[artifactIdDigestMap[artifactId]]...)
+		candidates = append(candidates, signaturesByArtifact...)
+		for _, signature := range candidates {
+			if seen[signature.Id] || (signature.CiArtifactId != artifactId && signature.ImageDigest != artifactIdDigestMap[artifactId]) {
+				continue
+			}
+			seen[signature.Id] = true
+			summary.Signed = true
+			summary.Signatures = append(summary.Signatures, adapter.BuildImageSignatureDto(signature, keyIdNameMap))
+		}
+	}
+
+	verifications, err := impl.imageSignatureVerificationRepository.FindLatestByArtifactIds(artifactIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching signature verifications", "artifactIds", artifactIds, "err", err)
+		return nil, err
+	}
+	policyIds := make([]int, 0, len(verifications))
+	for _, verification := range verifications {
+		policyIds = append(policyIds, verification.PolicyId)
+	}
+	policies, err := impl.imageSignaturePolicyRepository.FindByIds(policyIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching signature policies", "policyIds", policyIds, "err", err)
+		return nil, err
+	}
+	policyIdPolicyMap := make(map[int]*repository.ImageSignaturePolicy, len(policies))
+	for _, policy := range policies {
+		policyIdPolicyMap[policy.Id] = policy
+	}
+	for _, verification := range verifications {
+		summary, ok := summaries[verification.CiArtifactId]
+		if !ok || (cdPipelineId > 0 && verification.CdPipelineId != cdPipelineId) {
+			continue
+		}
+		summary.Verifications = append(summary.Verifications, adapter.BuildVerificationResultDto(verification, policyIdPolicyMap[verification.PolicyId]))
+		if verification.Status == bean.VerificationFailed || len(summary.VerificationStatus) == 0 {
+			summary.VerificationStatus = verification.Status
+		}
+	}
+	return summaries, nil
+}
+
+func (impl *ImageSigningServiceImpl) GetArtifactSignatureDetail(artifactId int) (*bean.ArtifactSignatureSummary, error) {
+	artifact, err := impl.ciArtifactRepository.Get(artifactId)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "artifact not found", "artifact not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching artifact", "artifactId", artifactId, "err", err)
+		return nil, err
+	}
+	summaries, err := impl.GetSignatureSummaryForArtifacts(map[int]string{artifact.Id: artifact.ImageDigest}, 0)
+	if err != nil {
+		return nil, err
+	}
+	return summaries[artifact.Id], nil
+}
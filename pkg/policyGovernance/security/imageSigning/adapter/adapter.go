@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/helper"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"strings"
+	"time"
+)
+
+func NewAuditLog(userId int32) sql.AuditLog {
+	return sql.AuditLog{
+		CreatedOn: time.Now(),
+		CreatedBy: userId,
+		UpdatedOn: time.Now(),
+		UpdatedBy: userId,
+	}
+}
+
+func BuildSigningKeyDto(key *repository.ImageSigningKey) *bean.SigningKeyDto {
+	return &bean.SigningKeyDto{
+		Id:        key.Id,
+		Name:      key.Name,
+		PublicKey: key.PublicKey,
+		IsDefault: key.IsDefault,
+	}
+}
+
+func BuildPolicyDto(policy *repository.ImageSignaturePolicy, envIds, clusterIds []int) (*bean.SignaturePolicyDto, error) {
+	dto := &bean.SignaturePolicyDto{
+		Id:                   policy.Id,
+		Name:                 policy.Name,
+		Description:          policy.Description,
+		TrustedKeyIds:        policy.TrustedKeyIds,
+		TrustedIdentities:    make([]*bean.TrustedIdentity, 0),
+		RequiredAttestations: make([]string, 0),
+		Enforce:              policy.Enforce,
+		EnvironmentIds:       envIds,
+		ClusterIds:           clusterIds,
+	}
+	if len(policy.TrustedIdentities) > 0 {
+		err := json.Unmarshal([]byte(policy.TrustedIdentities), &dto.TrustedIdentities)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(policy.RequiredAttestations) > 0 {
+		err := json.Unmarshal([]byte(policy.RequiredAttestations), &dto.RequiredAttestations)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dto, nil
+}
+
+func BuildPolicyModel(dto *bean.SignaturePolicyDto) (*repository.ImageSignaturePolicy, error) {
+	trustedIdentities, err := json.Marshal(dto.TrustedIdentities)
+	if err != nil {
+		return nil, err
+	}
+	requiredAttestations, err := json.Marshal(dto.RequiredAttestations)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.ImageSignaturePolicy{
+		Id:                   dto.Id,
+		Name:                 dto.Name,
+		Description:          dto.Description,
+		TrustedKeyIds:        dto.TrustedKeyIds,
+		TrustedIdentities:    string(trustedIdentities),
+		RequiredAttestations: string(requiredAttestations),
+		Enforce:              dto.Enforce,
+		Active:               true,
+		AuditLog:             NewAuditLog(dto.UserId),
+	}, nil
+}
+
+// BuildKeylessSignatureModel records a reported keyless signature with the identity verified from its certificate
+func BuildKeylessSignatureModel(artifactId int, image, imageDigest string, metadata *bean.KeylessSignatureMetadata,
+	identity *helper.CertificateIdentity, predicateType string, userId int32) *repository.ImageSignature {
+	return &repository.ImageSignature{
+		CiArtifactId:         artifactId,
+		Image:                image,
+		ImageDigest:          imageDigest,
+		SignatureType:        metadata.SignatureType,
+		SigningMode:          bean.SigningModeKeyless,
+		PredicateType:        predicateType,
+		Payload:              metadata.Payload,
+		Signature:            metadata.Signature,
+		Certificate:          strings.TrimSpace(metadata.Certificate) + "\n" + metadata.CertificateChain,
+		CertificateIdentity:  identity.Subject,
+		CertificateIssuer:    identity.Issuer,
+		TransparencyLogIndex: metadata.TransparencyLogIndex,
+		AuditLog:             NewAuditLog(userId),
+	}
+}
+
+func BuildImageSignatureDto(signature *repository.ImageSignature, keyIdNameMap map[int]string) *bean.ImageSignatureDto {
+	return &bean.ImageSignatureDto{
+		Id:                   signature.Id,
+		SignatureType:        signature.SignatureType,
+		SigningMode:          signature.SigningMode,
+		SigningKeyName:       keyIdNameMap[signature.SigningKeyId],
+		PredicateType:        signature.PredicateType,
+		CertificateIdentity:  signature.CertificateIdentity,
+		CertificateIssuer:    signature.CertificateIssuer,
+		TransparencyLogIndex: signature.TransparencyLogIndex,
+		SignedOn:             signature.CreatedOn,
+	}
+}
+
+func BuildVerificationModel(artifactId, cdPipelineId, envId int, result *bean.VerificationResultDto, signatureId int, userId int32) *repository.ImageSignatureVerification {
+	return &repository.ImageSignatureVerification{
+		CiArtifactId: artifactId,
+		CdPipelineId: cdPipelineId,
+		EnvId:        envId,
+		PolicyId:     result.PolicyId,
+		Status:       result.Status,
+		Message:      result.Message,
+		SignatureId:  signatureId,
+		VerifiedOn:   result.VerifiedOn,
+		AuditLog:     NewAuditLog(userId),
+	}
+}
+
+func BuildVerificationResultDto(verification *repository.ImageSignatureVerification, policy *repository.ImageSignaturePolicy) *bean.VerificationResultDto {
+	result := &bean.VerificationResultDto{
+		PolicyId:     verification.PolicyId,
+		EnvId:        verification.EnvId,
+		CdPipelineId: verification.CdPipelineId,
+		Status:       verification.Status,
+		Message:      verification.Message,
+		VerifiedOn:   verification.VerifiedOn,
+	}
+	if policy != nil {
+		result.PolicyName = policy.Name
+		result.Enforced = policy.Enforce
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type SigningMode string
+
+const (
+	SigningModeKey     SigningMode = "KEY"
+	SigningModeKeyless SigningMode = "KEYLESS"
+)
+
+type SignatureType string
+
+const (
+	SignatureTypeSignature   SignatureType = "SIGNATURE"
+	SignatureTypeAttestation SignatureType = "ATTESTATION"
+)
+
+type VerificationStatus string
+
+const (
+	VerificationPassed VerificationStatus = "PASSED"
+	VerificationFailed VerificationStatus = "FAILED"
+	// VerificationNotRequired is never persisted, it is returned when no policy applies to the environment
+	VerificationNotRequired VerificationStatus = "NOT_REQUIRED"
+)
+
+// SimpleSigningSignatureType is the type identifier of the simple signing payload signed for an image
+const SimpleSigningSignatureType = "cosign container image signature"
+
+const (
+	// SigningKeySecretKey is the key of the private key in the kubernetes secret of a signing key
+	SigningKeySecretKey = "cosign.key"
+	// SigningKeySecretPrefix prefixes the kubernetes secret name of a signing key, the key id is appended to it
+	SigningKeySecretPrefix = "image-signing-key-"
+)
+
+const (
+	// InTotoPayloadType is the dsse payload type of attestations, the signature of an attestation is over the dsse
+	// pre-authentication encoding of the in-toto statement
+	InTotoPayloadType = "application/vnd.in-toto+json"
+)
+
+type ImageSigningConfig struct {
+	FulcioRootCerts string `env:"IMAGE_SIGNING_FULCIO_ROOT_CERTS" envDefault:"" description:"PEM bundle of the fulcio root and intermediate certificates trusted for keyless signatures, keyless signatures are not recorded when empty"`
+}
+
+type SigningKeyDto struct {
+	Id         int    `json:"id"`
+	Name       string `json:"name" validate:"required,max=250"`
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey,omitempty"` // write only, never returned in get apis
+	IsDefault  bool   `json:"isDefault"`
+	UserId     int32  `json:"-"`
+}
+
+type TrustedIdentity struct {
+	Issuer  string `json:"issuer" validate:"required"`
+	Subject string `json:"subject" validate:"required"` // supports glob patterns, e.g. https://github.com/my-org/*
+}
+
+type SignaturePolicyDto struct {
+	Id                   int                `json:"id"`
+	Name                 string             `json:"name" validate:"required,max=250"`
+	Description          string             `json:"description"`
+	TrustedKeyIds        []int              `json:"trustedKeyIds"`
+	TrustedIdentities    []*TrustedIdentity `json:"trustedIdentities" validate:"dive"`
+	RequiredAttestations []string           `json:"requiredAttestations"`
+	Enforce              bool               `json:"enforce"`
+	EnvironmentIds       []int              `json:"environmentIds"`
+	ClusterIds           []int              `json:"clusterIds"`
+	UserId               int32              `json:"-"`
+}
+
+// KeylessSignatureMetadata is reported by ci-runner when the image is signed keyless in the post build step. Nothing
+// in it is trusted as reported, the certificate chain and the signature are verified before it is recorded.
+type KeylessSignatureMetadata struct {
+	SignatureType SignatureType `json:"signatureType"`
+	PredicateType string        `json:"predicateType"`
+	// Payload is the simple signing payload of a signature or the in-toto statement of an attestation
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"` // base64 encoded
+	// Certificate is the PEM encoded fulcio leaf certificate, CertificateChain holds the intermediates up to the root
+	Certificate          string `json:"certificate"`
+	CertificateChain     string `json:"certificateChain"`
+	TransparencyLogIndex string `json:"transparencyLogIndex"`
+}
+
+type ImageSignatureDto struct {
+	Id                   int           `json:"id"`
+	SignatureType        SignatureType `json:"signatureType"`
+	SigningMode          SigningMode   `json:"signingMode"`
+	SigningKeyName       string        `json:"signingKeyName,omitempty"`
+	PredicateType        string        `json:"predicateType,omitempty"`
+	CertificateIdentity  string        `json:"certificateIdentity,omitempty"`
+	CertificateIssuer    string        `json:"certificateIssuer,omitempty"`
+	TransparencyLogIndex string        `json:"transparencyLogIndex,omitempty"`
+	SignedOn             time.Time     `json:"signedOn"`
+}
+
+type VerificationResultDto struct {
+	PolicyId     int                `json:"policyId"`
+	PolicyName   string             `json:"policyName"`
+	EnvId        int                `json:"envId"`
+	CdPipelineId int                `json:"cdPipelineId"`
+	Status       VerificationStatus `json:"status"`
+	Message      string             `json:"message"`
+	Enforced     bool               `json:"enforced"`
+	VerifiedOn   time.Time          `json:"verifiedOn"`
+}
+
+type DeploymentVerificationResult struct {
+	Status  VerificationStatus       `json:"status"`
+	Results []*VerificationResultDto `json:"results"`
+}
+
+// IsBlocking returns true if any enforced policy failed for the artifact
+func (result *DeploymentVerificationResult) IsBlocking() bool {
+	if result == nil {
+		return false
+	}
+	for _, policyResult := range result.Results {
+		if policyResult.Enforced && policyResult.Status == VerificationFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// ArtifactSignatureSummary is shown against every artifact in the artifact list
+type ArtifactSignatureSummary struct {
+	Signed             bool                     `json:"signed"`
+	Signatures         []*ImageSignatureDto     `json:"signatures,omitempty"`
+	VerificationStatus VerificationStatus       `json:"verificationStatus,omitempty"`
+	Verifications      []*VerificationResultDto `json:"verifications,omitempty"`
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
+	"path"
+	"strings"
+)
+
+var (
+	// fulcio records the oidc issuer of the signer in these certificate extensions, the first one is deprecated
+	// in favour of the second which holds a DER encoded string
+	fulcioIssuerV1Oid = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	fulcioIssuerV2Oid = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+const certificatePemType = "CERTIFICATE"
+
+// CertificateIdentity is the oidc identity a fulcio certificate was issued to
+type CertificateIdentity struct {
+	Issuer  string
+	Subject string
+}
+
+// InTotoStatement is the part of an in-toto attestation statement needed to bind it to an image
+type InTotoStatement struct {
+	PredicateType string          `json:"predicateType"`
+	Subject       []InTotoSubject `json:"subject"`
+}
+
+type InTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// ParseCertPool builds a pool from a PEM bundle, it fails if the bundle holds no certificate
+func ParseCertPool(certsPem string) (*x509.CertPool, error) {
+	certs, err := parseCertificates(certsPem)
+	if err != nil {
+		return nil, err
+	} else if len(certs) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+	pool := x509.NewCertPool()
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+	return pool, nil
+}
+
+// VerifyKeylessSignature verifies that the leaf certificate chains up to the trusted fulcio roots and that the signature
+// over signedBytes is made by the key of the certificate, the identity is read from the certificate. Fulcio certificates
+// expire minutes after they are issued so the chain is verified as of the time the leaf was issued.
+func VerifyKeylessSignature(roots *x509.CertPool, certificatePem string, signedBytes []byte, signature string) (*CertificateIdentity, error) {
+	if roots == nil {
+		return nil, errors.New("no fulcio root is trusted for keyless signatures")
+	}
+	certs, err := parseCertificates(certificatePem)
+	if err != nil {
+		return nil, err
+	} else if len(certs) == 0 {
+		return nil, errors.New("keyless signature has no certificate")
+	}
+	leaf := certs[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   leaf.NotBefore,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("certificate is not issued by a trusted fulcio root: %w", err)
+	}
+	publicKey, ok := leaf.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("only ECDSA certificates are supported")
+	}
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	digest := sha256.Sum256(signedBytes)
+	if !ecdsa.VerifyASN1(publicKey, digest[:], signatureBytes) {
+		return nil, errors.New("signature does not match the certificate")
+	}
+	return getCertificateIdentity(leaf)
+}
+
+// GetSignedBytes returns what the signer signed for the payload, attestations are signed as dsse envelopes
+func GetSignedBytes(signatureType bean.SignatureType, payload []byte) []byte {
+	if signatureType == bean.SignatureTypeAttestation {
+		return BuildDssePae(bean.InTotoPayloadType, payload)
+	}
+	return payload
+}
+
+// BuildDssePae builds the dsse pre-authentication encoding of the payload
+func BuildDssePae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// GetSignedDigest returns the image digest the signature or attestation payload is bound to
+func GetSignedDigest(signatureType bean.SignatureType, payload []byte) (string, error) {
+	if signatureType != bean.SignatureTypeAttestation {
+		return GetDigestFromPayload(payload)
+	}
+	statement, err := ParseInTotoStatement(payload)
+	if err != nil {
+		return "", err
+	}
+	for _, subject := range statement.Subject {
+		if digest, ok := subject.Digest["sha256"]; ok {
+			return "sha256:" + digest, nil
+		}
+	}
+	return "", errors.New("attestation has no sha256 subject")
+}
+
+func ParseInTotoStatement(payload []byte) (*InTotoStatement, error) {
+	statement := &InTotoStatement{}
+	err := json.Unmarshal(payload, statement)
+	if err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// IsIdentityTrusted matches a certificate identity against the trusted identities,
+// subject supports glob patterns as supported by path.Match
+func IsIdentityTrusted(trustedIdentities []*bean.TrustedIdentity, issuer, subject string) bool {
+	if len(issuer) == 0 || len(subject) == 0 {
+		return false
+	}
+	for _, trustedIdentity := range trustedIdentities {
+		if trustedIdentity == nil || !strings.EqualFold(trustedIdentity.Issuer, issuer) {
+			continue
+		}
+		if trustedIdentity.Subject == subject {
+			return true
+		}
+		if matched, err := path.Match(trustedIdentity.Subject, subject); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func getCertificateIdentity(cert *x509.Certificate) (*CertificateIdentity, error) {
+	identity := &CertificateIdentity{}
+	for _, extension := range cert.Extensions {
+		if extension.Id.Equal(fulcioIssuerV2Oid) {
+			var issuer string
+			if _, err := asn1.Unmarshal(extension.Value, &issuer); err != nil {
+				return nil, fmt.Errorf("invalid issuer extension in certificate: %w", err)
+			}
+			identity.Issuer = issuer
+			break
+		} else if extension.Id.Equal(fulcioIssuerV1Oid) {
+			identity.Issuer = string(extension.Value)
+		}
+	}
+	if len(cert.EmailAddresses) > 0 {
+		identity.Subject = cert.EmailAddresses[0]
+	} else if len(cert.URIs) > 0 {
+		identity.Subject = cert.URIs[0].String()
+	}
+	if len(identity.Issuer) == 0 || len(identity.Subject) == 0 {
+		return nil, errors.New("certificate does not carry a fulcio identity")
+	}
+	return identity, nil
+}
+
+func parseCertificates(certsPem string) ([]*x509.Certificate, error) {
+	certs := make([]*x509.Certificate, 0)
+	rest := []byte(certsPem)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != certificatePemType {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
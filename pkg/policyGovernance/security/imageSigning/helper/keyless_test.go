@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type testFulcio struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+}
+
+func newTestFulcio(t *testing.T) *testFulcio {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test fulcio"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	return &testFulcio{cert: cert, key: key, pem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))}
+}
+
+// signKeyless issues a short lived certificate to the identity and signs signedBytes with its key
+func (fulcio *testFulcio) signKeyless(t *testing.T, issuer, subject string, signedBytes []byte) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	issuerValue, err := asn1.Marshal(issuer)
+	assert.Nil(t, err)
+	subjectUri, err := url.Parse(subject)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       time.Now().Add(-30 * time.Minute),
+		NotAfter:        time.Now().Add(-20 * time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		URIs:            []*url.URL{subjectUri},
+		ExtraExtensions: []pkix.Extension{{Id: fulcioIssuerV2Oid, Value: issuerValue}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, fulcio.cert, &key.PublicKey, fulcio.key)
+	assert.Nil(t, err)
+	digest := sha256.Sum256(signedBytes)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	assert.Nil(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), base64.StdEncoding.EncodeToString(signature)
+}
+
+func TestVerifyKeylessSignature(t *testing.T) {
+	fulcio := newTestFulcio(t)
+	roots, err := ParseCertPool(fulcio.pem)
+	assert.Nil(t, err)
+	issuer, subject := "https://token.actions.githubusercontent.com", "https://github.com/devtron-labs/devtron/.github/workflows/ci.yaml@refs/heads/main"
+	payload, err := BuildSimpleSigningPayload("docker.io/devtron/app", "sha256:abc")
+	assert.Nil(t, err)
+	certificate, signature := fulcio.signKeyless(t, issuer, subject, payload)
+
+	t.Run("expired certificate is verified as of issuance", func(t *testing.T) {
+		identity, err := VerifyKeylessSignature(roots, certificate, payload, signature)
+		assert.Nil(t, err)
+		assert.Equal(t, issuer, identity.Issuer)
+		assert.Equal(t, subject, identity.Subject)
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		tampered, _ := BuildSimpleSigningPayload("docker.io/devtron/app", "sha256:def")
+		_, err := VerifyKeylessSignature(roots, certificate, tampered, signature)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("untrusted fulcio", func(t *testing.T) {
+		otherRoots, err := ParseCertPool(newTestFulcio(t).pem)
+		assert.Nil(t, err)
+		_, err = VerifyKeylessSignature(otherRoots, certificate, payload, signature)
+		assert.NotNil(t, err)
+		_, err = VerifyKeylessSignature(nil, certificate, payload, signature)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("attestation is signed as dsse envelope", func(t *testing.T) {
+		statement := []byte(`{"predicateType":"https://slsa.dev/provenance/v1","subject":[{"name":"docker.io/devtron/app","digest":{"sha256":"abc"}}]}`)
+		certificate, signature := fulcio.signKeyless(t, issuer, subject, GetSignedBytes(bean.SignatureTypeAttestation, statement))
+		_, err := VerifyKeylessSignature(roots, certificate, GetSignedBytes(bean.SignatureTypeAttestation, statement), signature)
+		assert.Nil(t, err)
+		_, err = VerifyKeylessSignature(roots, certificate, statement, signature)
+		assert.NotNil(t, err)
+		digest, err := GetSignedDigest(bean.SignatureTypeAttestation, statement)
+		assert.Nil(t, err)
+		assert.Equal(t, "sha256:abc", digest)
+	})
+}
+
+func TestIsIdentityTrusted(t *testing.T) {
+	trusted := []*bean.TrustedIdentity{
+		{Issuer: "https://token.actions.githubusercontent.com", Subject: "https://github.com/devtron-labs/*"},
+		{Issuer: "https://accounts.google.com", Subject: "ci@devtron.ai"},
+	}
+	assert.True(t, IsIdentityTrusted(trusted, "https://token.actions.githubusercontent.com", "https://github.com/devtron-labs/devtron"))
+	assert.True(t, IsIdentityTrusted(trusted, "https://accounts.google.com", "ci@devtron.ai"))
+	assert.False(t, IsIdentityTrusted(trusted, "https://accounts.google.com", "someone@devtron.ai"))
+	assert.False(t, IsIdentityTrusted(trusted, "https://issuer.example.com", "ci@devtron.ai"))
+	assert.False(t, IsIdentityTrusted(nil, "https://accounts.google.com", "ci@devtron.ai"))
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
+	"strings"
+)
+
+const (
+	publicKeyPemType    = "PUBLIC KEY"
+	privateKeyPemType   = "PRIVATE KEY"
+	ecPrivateKeyPemType = "EC PRIVATE KEY"
+)
+
+// SimpleSigningPayload binds a signature to an image repository and manifest digest. It follows the simple signing
+// layout but signatures are only recorded in devtron and are not published to the registry.
+type SimpleSigningPayload struct {
+	Critical SimpleSigningCritical `json:"critical"`
+	Optional map[string]string     `json:"optional,omitempty"`
+}
+
+type SimpleSigningCritical struct {
+	Identity SimpleSigningIdentity `json:"identity"`
+	Image    SimpleSigningImage    `json:"image"`
+	Type     string                `json:"type"`
+}
+
+type SimpleSigningIdentity struct {
+	DockerReference string `json:"docker-reference"`
+}
+
+type SimpleSigningImage struct {
+	DockerManifestDigest string `json:"docker-manifest-digest"`
+}
+
+// GenerateKeyPair generates an ECDSA P-256 key pair and returns both keys PEM encoded
+func GenerateKeyPair() (publicKeyPem string, privateKeyPem string, err error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	publicKeyPem, err = encodePublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return "", "", err
+	}
+	privateKeyPem = string(pem.EncodeToMemory(&pem.Block{Type: privateKeyPemType, Bytes: privateKeyBytes}))
+	return publicKeyPem, privateKeyPem, nil
+}
+
+// GetPublicKeyFromPrivateKey derives the PEM encoded public key from a PEM encoded private key
+func GetPublicKeyFromPrivateKey(privateKeyPem string) (string, error) {
+	privateKey, err := parsePrivateKey(privateKeyPem)
+	if err != nil {
+		return "", err
+	}
+	return encodePublicKey(privateKey.Public())
+}
+
+// ValidatePublicKey checks that the key is a PEM encoded ECDSA public key
+func ValidatePublicKey(publicKeyPem string) error {
+	_, err := parsePublicKey(publicKeyPem)
+	return err
+}
+
+// BuildSimpleSigningPayload builds the simple signing payload for an image repo and digest
+func BuildSimpleSigningPayload(imageRepo, imageDigest string) ([]byte, error) {
+	payload := SimpleSigningPayload{
+		Critical: SimpleSigningCritical{
+			Identity: SimpleSigningIdentity{DockerReference: imageRepo},
+			Image:    SimpleSigningImage{DockerManifestDigest: imageDigest},
+			Type:     bean.SimpleSigningSignatureType,
+		},
+	}
+	return json.Marshal(payload)
+}
+
+// Sign signs sha256 of the payload with the private key and returns the base64 encoded ASN.1 signature
+func Sign(privateKeyPem string, payload []byte) (string, error) {
+	privateKey, err := parsePrivateKey(privateKeyPem)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(payload)
+	signature, err := privateKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// Verify verifies the base64 encoded signature of the payload against the public key
+func Verify(publicKeyPem string, payload []byte, signature string) error {
+	publicKey, err := parsePublicKey(publicKeyPem)
+	if err != nil {
+		return err
+	}
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	digest := sha256.Sum256(payload)
+	if !ecdsa.VerifyASN1(publicKey, digest[:], signatureBytes) {
+		return errors.New("signature does not match the trusted key")
+	}
+	return nil
+}
+
+// GetDigestFromPayload extracts the manifest digest the simple signing payload was created for
+func GetDigestFromPayload(payload []byte) (string, error) {
+	signingPayload := &SimpleSigningPayload{}
+	err := json.Unmarshal(payload, signingPayload)
+	if err != nil {
+		return "", err
+	}
+	return signingPayload.Critical.Image.DockerManifestDigest, nil
+}
+
+// GetImageRepository strips the tag from the image, the repository and not the tag is signed
+func GetImageRepository(image string) string {
+	image = strings.Split(image, "@")[0]
+	lastSlash := strings.LastIndex(image, "/")
+	if lastColon := strings.LastIndex(image, ":"); lastColon > lastSlash {
+		return image[:lastColon]
+	}
+	return image
+}
+
+func encodePublicKey(publicKey crypto.PublicKey) (string, error) {
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: publicKeyPemType, Bytes: publicKeyBytes})), nil
+}
+
+func parsePublicKey(publicKeyPem string) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicKeyPem))
+	if block == nil || block.Type != publicKeyPemType {
+		return nil, errors.New("public key is not a valid PEM encoded public key")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ecdsaPublicKey, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("only ECDSA public keys are supported")
+	}
+	return ecdsaPublicKey, nil
+}
+
+func parsePrivateKey(privateKeyPem string) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKeyPem))
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+	switch block.Type {
+	case ecPrivateKeyPemType:
+		return x509.ParseECPrivateKey(block.Bytes)
+	case privateKeyPemType:
+		privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		ecdsaPrivateKey, ok := privateKey.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("only ECDSA private keys are supported")
+		}
+		return ecdsaPrivateKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %q, encrypted keys must be decrypted before import", block.Type)
+	}
+}
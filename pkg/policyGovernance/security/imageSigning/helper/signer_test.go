@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	publicKey, privateKey, err := GenerateKeyPair()
+	assert.Nil(t, err)
+
+	derivedPublicKey, err := GetPublicKeyFromPrivateKey(privateKey)
+	assert.Nil(t, err)
+	assert.Equal(t, publicKey, derivedPublicKey)
+
+	payload, err := BuildSimpleSigningPayload("docker.io/devtron/app", "sha256:abc")
+	assert.Nil(t, err)
+	signature, err := Sign(privateKey, payload)
+	assert.Nil(t, err)
+
+	t.Run("valid signature", func(t *testing.T) {
+		assert.Nil(t, Verify(publicKey, payload, signature))
+		digest, err := GetDigestFromPayload(payload)
+		assert.Nil(t, err)
+		assert.Equal(t, "sha256:abc", digest)
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		tampered, _ := BuildSimpleSigningPayload("docker.io/devtron/app", "sha256:def")
+		assert.NotNil(t, Verify(publicKey, tampered, signature))
+	})
+
+	t.Run("untrusted key", func(t *testing.T) {
+		otherPublicKey, _, err := GenerateKeyPair()
+		assert.Nil(t, err)
+		assert.NotNil(t, Verify(otherPublicKey, payload, signature))
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		assert.NotNil(t, ValidatePublicKey("not a key"))
+		assert.NotNil(t, ValidatePublicKey(privateKey))
+	})
+}
+
+func TestGetImageRepository(t *testing.T) {
+	assert.Equal(t, "docker.io/devtron/app", GetImageRepository("docker.io/devtron/app:abc-1"))
+	assert.Equal(t, "localhost:5000/app", GetImageRepository("localhost:5000/app:v1"))
+	assert.Equal(t, "localhost:5000/app", GetImageRepository("localhost:5000/app"))
+	assert.Equal(t, "devtron/app", GetImageRepository("devtron/app@sha256:abc"))
+}
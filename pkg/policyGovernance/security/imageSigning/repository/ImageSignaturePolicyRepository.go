@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type ImageSignaturePolicy struct {
+	tableName            struct{} `sql:"image_signature_policy" pg:",discard_unknown_columns"`
+	Id                   int      `sql:"id,pk"`
+	Name                 string   `sql:"name,notnull"`
+	Description          string   `sql:"description"`
+	TrustedKeyIds        []int    `sql:"trusted_key_ids" pg:",array"`
+	TrustedIdentities    string   `sql:"trusted_identities"`
+	RequiredAttestations string   `sql:"required_attestations"`
+	Enforce              bool     `sql:"enforce,notnull"`
+	Active               bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ImageSignaturePolicyRepository interface {
+	sql.TransactionWrapper
+	Save(tx *pg.Tx, policy *ImageSignaturePolicy) error
+	Update(tx *pg.Tx, policy *ImageSignaturePolicy) error
+	FindById(id int) (*ImageSignaturePolicy, error)
+	FindByIds(ids []int) ([]*ImageSignaturePolicy, error)
+	FindAllActive() ([]*ImageSignaturePolicy, error)
+}
+
+type ImageSignaturePolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewImageSignaturePolicyRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, transactionUtilImpl *sql.TransactionUtilImpl) *ImageSignaturePolicyRepositoryImpl {
+	return &ImageSignaturePolicyRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (impl *ImageSignaturePolicyRepositoryImpl) Save(tx *pg.Tx, policy *ImageSignaturePolicy) error {
+	return tx.Insert(policy)
+}
+
+func (impl *ImageSignaturePolicyRepositoryImpl) Update(tx *pg.Tx, policy *ImageSignaturePolicy) error {
+	return tx.Update(policy)
+}
+
+func (impl *ImageSignaturePolicyRepositoryImpl) FindById(id int) (*ImageSignaturePolicy, error) {
+	policy := &ImageSignaturePolicy{}
+	err := impl.dbConnection.Model(policy).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return policy, err
+}
+
+func (impl *ImageSignaturePolicyRepositoryImpl) FindByIds(ids []int) ([]*ImageSignaturePolicy, error) {
+	var policies []*ImageSignaturePolicy
+	if len(ids) == 0 {
+		return policies, nil
+	}
+	err := impl.dbConnection.Model(&policies).
+		Where("id IN (?)", pg.In(ids)).
+		Where("active = ?", true).
+		Select()
+	return policies, err
+}
+
+func (impl *ImageSignaturePolicyRepositoryImpl) FindAllActive() ([]*ImageSignaturePolicy, error) {
+	var policies []*ImageSignaturePolicy
+	err := impl.dbConnection.Model(&policies).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return policies, err
+}
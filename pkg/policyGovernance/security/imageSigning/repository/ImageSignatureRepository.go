@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type ImageSignature struct {
+	tableName     struct{}           `sql:"image_signature" pg:",discard_unknown_columns"`
+	Id            int                `sql:"id,pk"`
+	CiArtifactId  int                `sql:"ci_artifact_id,notnull"`
+	Image         string             `sql:"image,notnull"`
+	ImageDigest   string             `sql:"image_digest,notnull"`
+	SignatureType bean.SignatureType `sql:"signature_type,notnull"`
+	SigningMode   bean.SigningMode   `sql:"signing_mode,notnull"`
+	SigningKeyId  int                `sql:"signing_key_id"`
+	PredicateType string             `sql:"predicate_type"`
+	Payload       string             `sql:"payload,notnull"`
+	Signature     string             `sql:"signature,notnull"`
+	// Certificate is the fulcio leaf certificate of a keyless signature followed by its chain, PEM encoded
+	Certificate          string `sql:"certificate"`
+	CertificateIdentity  string `sql:"certificate_identity"`
+	CertificateIssuer    string `sql:"certificate_issuer"`
+	TransparencyLogIndex string `sql:"transparency_log_index"`
+	sql.AuditLog
+}
+
+type ImageSignatureRepository interface {
+	Save(signature *ImageSignature) error
+	FindByImageDigest(imageDigest string) ([]*ImageSignature, error)
+	FindByArtifactIds(artifactIds []int) ([]*ImageSignature, error)
+	FindByImageDigests(imageDigests []string) ([]*ImageSignature, error)
+}
+
+type ImageSignatureRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewImageSignatureRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ImageSignatureRepositoryImpl {
+	return &ImageSignatureRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ImageSignatureRepositoryImpl) Save(signature *ImageSignature) error {
+	return impl.dbConnection.Insert(signature)
+}
+
+func (impl *ImageSignatureRepositoryImpl) FindByImageDigest(imageDigest string) ([]*ImageSignature, error) {
+	var signatures []*ImageSignature
+	err := impl.dbConnection.Model(&signatures).
+		Where("image_digest = ?", imageDigest).
+		Order("id DESC").
+		Select()
+	return signatures, err
+}
+
+func (impl *ImageSignatureRepositoryImpl) FindByArtifactIds(artifactIds []int) ([]*ImageSignature, error) {
+	var signatures []*ImageSignature
+	if len(artifactIds) == 0 {
+		return signatures, nil
+	}
+	err := impl.dbConnection.Model(&signatures).
+		Where("ci_artifact_id IN (?)", pg.In(artifactIds)).
+		Order("id DESC").
+		Select()
+	return signatures, err
+}
+
+func (impl *ImageSignatureRepositoryImpl) FindByImageDigests(imageDigests []string) ([]*ImageSignature, error) {
+	var signatures []*ImageSignature
+	if len(imageDigests) == 0 {
+		return signatures, nil
+	}
+	err := impl.dbConnection.Model(&signatures).
+		Where("image_digest IN (?)", pg.In(imageDigests)).
+		Order("id DESC").
+		Select()
+	return signatures, err
+}
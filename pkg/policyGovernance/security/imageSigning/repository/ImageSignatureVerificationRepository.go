@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ImageSignatureVerification struct {
+	tableName    struct{}                `sql:"image_signature_verification" pg:",discard_unknown_columns"`
+	Id           int                     `sql:"id,pk"`
+	CiArtifactId int                     `sql:"ci_artifact_id,notnull"`
+	CdPipelineId int                     `sql:"cd_pipeline_id,notnull"`
+	EnvId        int                     `sql:"env_id,notnull"`
+	PolicyId     int                     `sql:"policy_id,notnull"`
+	Status       bean.VerificationStatus `sql:"status,notnull"`
+	Message      string                  `sql:"message"`
+	SignatureId  int                     `sql:"signature_id"`
+	VerifiedOn   time.Time               `sql:"verified_on,notnull"`
+	sql.AuditLog
+}
+
+type ImageSignatureVerificationRepository interface {
+	SaveAll(verifications []*ImageSignatureVerification) error
+	// FindLatestByArtifactIds returns the latest verification per artifact, pipeline and policy
+	FindLatestByArtifactIds(artifactIds []int) ([]*ImageSignatureVerification, error)
+}
+
+type ImageSignatureVerificationRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewImageSignatureVerificationRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ImageSignatureVerificationRepositoryImpl {
+	return &ImageSignatureVerificationRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (impl *ImageSignatureVerificationRepositoryImpl) SaveAll(verifications []*ImageSignatureVerification) error {
+	if len(verifications) == 0 {
+		return nil
+	}
+	return impl.dbConnection.Insert(&verifications)
+}
+
+func (impl *ImageSignatureVerificationRepositoryImpl) FindLatestByArtifactIds(artifactIds []int) ([]*ImageSignatureVerification, error) {
+	var verifications []*ImageSignatureVerification
+	if len(artifactIds) == 0 {
+		return verifications, nil
+	}
+	query := "SELECT DISTINCT ON (isv.ci_artifact_id, isv.cd_pipeline_id, isv.policy_id) isv.* " +
+		" FROM image_signature_verification isv " +
+		" WHERE isv.ci_artifact_id IN (?) " +
+		" ORDER BY isv.ci_artifact_id, isv.cd_pipeline_id, isv.policy_id, isv.id DESC;"
+	_, err := impl.dbConnection.Query(&verifications, query, pg.In(artifactIds))
+	return verifications, err
+}
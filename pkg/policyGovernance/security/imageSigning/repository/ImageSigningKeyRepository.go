@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type ImageSigningKey struct {
+	tableName struct{} `sql:"image_signing_key" pg:",discard_unknown_columns"`
+	Id        int      `sql:"id,pk"`
+	Name      string   `sql:"name,notnull"`
+	PublicKey string   `sql:"public_key,notnull"`
+	// PrivateKeySecretName is the kubernetes secret holding the private key, empty for verification only keys
+	PrivateKeySecretName string `sql:"private_key_secret_name"`
+	IsDefault            bool   `sql:"is_default,notnull"`
+	Active               bool   `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ImageSigningKeyRepository interface {
+	sql.TransactionWrapper
+	Save(tx *pg.Tx, key *ImageSigningKey) error
+	Update(tx *pg.Tx, key *ImageSigningKey) error
+	UnsetDefault(tx *pg.Tx, userId int32) error
+	FindById(id int) (*ImageSigningKey, error)
+	FindByIds(ids []int) ([]*ImageSigningKey, error)
+	FindActiveByName(name string) (*ImageSigningKey, error)
+	FindDefault() (*ImageSigningKey, error)
+	FindAllActive() ([]*ImageSigningKey, error)
+}
+
+type ImageSigningKeyRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewImageSigningKeyRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger, transactionUtilImpl *sql.TransactionUtilImpl) *ImageSigningKeyRepositoryImpl {
+	return &ImageSigningKeyRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (impl *ImageSigningKeyRepositoryImpl) Save(tx *pg.Tx, key *ImageSigningKey) error {
+	return tx.Insert(key)
+}
+
+func (impl *ImageSigningKeyRepositoryImpl) Update(tx *pg.Tx, key *ImageSigningKey) error {
+	return tx.Update(key)
+}
+
+func (impl *ImageSigningKeyRepositoryImpl) UnsetDefault(tx *pg.Tx, userId int32) error {
+	_, err := tx.Model((*ImageSigningKey)(nil)).
+		Set("is_default = ?", false).
+		Set("updated_by = ?", userId).
+		Set("updated_on = now()").
+		Where("is_default = ?", true).
+		Update()
+	return err
+}
+
+func (impl *ImageSigningKeyRepositoryImpl) FindById(id int) (*ImageSigningKey, error) {
+	key := &ImageSigningKey{}
+	err := impl.dbConnection.Model(key).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return key, err
+}
+
+func (impl *ImageSigningKeyRepositoryImpl) FindByIds(ids []int) ([]*ImageSigningKey, error) {
+	var keys []*ImageSigningKey
+	if len(ids) == 0 {
+		return keys, nil
+	}
+	err := impl.dbConnection.Model(&keys).
+		Where("id IN (?)", pg.In(ids)).
+		Where("active = ?", true).
+		Select()
+	return keys, err
+}
+
+func (impl *ImageSigningKeyRepositoryImpl) FindActiveByName(name string) (*ImageSigningKey, error) {
+	key := &ImageSigningKey{}
+	err := impl.dbConnection.Model(key).
+		Where("name = ?", name).
+		Where("active = ?", true).
+		Select()
+	return key, err
+}
+
+func (impl *ImageSigningKeyRepositoryImpl) FindDefault() (*ImageSigningKey, error) {
+	key := &ImageSigningKey{}
+	err := impl.dbConnection.Model(key).
+		Where("is_default = ?", true).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return key, err
+}
+
+func (impl *ImageSigningKeyRepositoryImpl) FindAllActive() ([]*ImageSigningKey, error) {
+	var keys []*ImageSigningKey
+	err := impl.dbConnection.Model(&keys).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	return keys, err
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageSigning
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/repository"
+	"github.com/google/wire"
+)
+
+var ImageSigningWireSet = wire.NewSet(
+	repository.NewImageSigningKeyRepositoryImpl,
+	wire.Bind(new(repository.ImageSigningKeyRepository), new(*repository.ImageSigningKeyRepositoryImpl)),
+	repository.NewImageSignatureRepositoryImpl,
+	wire.Bind(new(repository.ImageSignatureRepository), new(*repository.ImageSignatureRepositoryImpl)),
+	repository.NewImageSignaturePolicyRepositoryImpl,
+	wire.Bind(new(repository.ImageSignaturePolicyRepository), new(*repository.ImageSignaturePolicyRepositoryImpl)),
+	repository.NewImageSignatureVerificationRepositoryImpl,
+	wire.Bind(new(repository.ImageSignatureVerificationRepository), new(*repository.ImageSignatureVerificationRepositoryImpl)),
+
+	NewImageSigningServiceImpl,
+	wire.Bind(new(ImageSigningService), new(*ImageSigningServiceImpl)),
+)
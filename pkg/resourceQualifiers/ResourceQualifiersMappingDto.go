@@ -28,6 +28,7 @@ const (
 	InfraProfile                       = 3
 	ImagePromotionPolicy  ResourceType = 4
 	DeploymentWindow      ResourceType = 5
+	ImageSignaturePolicy  ResourceType = 6
 )
 
 type ResourceQualifierMappings struct {
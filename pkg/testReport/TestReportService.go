@@ -0,0 +1,452 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testReport
+
+import (
+	"archive/zip"
+	"fmt"
+	"github.com/argoproj/argo-workflows/v3/pkg/apis/workflow/v1alpha1"
+	"github.com/caarlos0/env/v6"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/build/trigger"
+	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps"
+	pipelineStageRepository "github.com/devtron-labs/devtron/pkg/pipeline/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/testReport/adapter"
+	"github.com/devtron-labs/devtron/pkg/testReport/bean"
+	"github.com/devtron-labs/devtron/pkg/testReport/parser"
+	testReportRepository "github.com/devtron-labs/devtron/pkg/testReport/repository"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+type TestReportConfig struct {
+	MaxTestCasesPerReport int `env:"TEST_REPORT_MAX_TEST_CASES" envDefault:"5000" description:"Maximum test cases stored per workflow, failed and errored test cases are always kept. Summary counts always cover all test cases"`
+	FlakyTestLookbackDays int `env:"TEST_REPORT_FLAKY_LOOKBACK_DAYS" envDefault:"30" description:"Number of days of test results considered for flaky test detection"`
+	MaxReportFileSizeMB   int `env:"TEST_REPORT_MAX_FILE_SIZE_MB" envDefault:"50" description:"Report files larger than this are skipped during ingestion"`
+}
+
+type TestReportService interface {
+	// IngestCiWorkflowReports parses the test and coverage reports uploaded by a completed ci workflow, it is a no-op
+	// for workflows without declared report paths or which are already ingested
+	IngestCiWorkflowReports(ciWorkflowId int) error
+	// IngestCdWorkflowRunnerReports does the same as IngestCiWorkflowReports for pre/post cd workflow runners
+	IngestCdWorkflowRunnerReports(cdWorkflowRunnerId int) error
+
+	GetCiWorkflowTestReport(ciWorkflowId int) (*bean.TestReportDetailDto, error)
+	GetCdWorkflowRunnerTestReport(cdWorkflowRunnerId int) (*bean.TestReportDetailDto, error)
+	GetCiPipelineFlakyTests(ciPipelineId int) (*bean.FlakyTestsResponse, error)
+	GetCdPipelineFlakyTests(cdPipelineId int) (*bean.FlakyTestsResponse, error)
+}
+
+type TestReportServiceImpl struct {
+	logger                  *zap.SugaredLogger
+	config                  *TestReportConfig
+	testReportRepository    testReportRepository.TestReportRepository
+	ciWorkflowRepository    pipelineConfig.CiWorkflowRepository
+	cdWorkflowRepository    pipelineConfig.CdWorkflowRepository
+	ciPipelineRepository    pipelineConfig.CiPipelineRepository
+	pipelineRepository      pipelineConfig.PipelineRepository
+	pipelineStageRepository pipelineStageRepository.PipelineStageRepository
+	ciHandlerService        trigger.HandlerService
+	cdHandlerService        devtronApps.HandlerService
+}
+
+func NewTestReportServiceImpl(logger *zap.SugaredLogger,
+	testReportRepository testReportRepository.TestReportRepository,
+	ciWorkflowRepository pipelineConfig.CiWorkflowRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	pipelineStageRepository pipelineStageRepository.PipelineStageRepository,
+	ciHandlerService trigger.HandlerService,
+	cdHandlerService devtronApps.HandlerService) (*TestReportServiceImpl, error) {
+	config := &TestReportConfig{}
+	if err := env.Parse(config); err != nil {
+		logger.Errorw("error in parsing test report config", "err", err)
+		return nil, err
+	}
+	return &TestReportServiceImpl{
+		logger:                  logger,
+		config:                  config,
+		testReportRepository:    testReportRepository,
+		ciWorkflowRepository:    ciWorkflowRepository,
+		cdWorkflowRepository:    cdWorkflowRepository,
+		ciPipelineRepository:    ciPipelineRepository,
+		pipelineRepository:      pipelineRepository,
+		pipelineStageRepository: pipelineStageRepository,
+		ciHandlerService:        ciHandlerService,
+		cdHandlerService:        cdHandlerService,
+	}, nil
+}
+
+// reports are only uploaded by workflows which ran to completion, aborted and cancelled workflows are skipped
+var reportIngestionStatuses = []string{cdWorkflow.WorkflowSucceeded, cdWorkflow.WorkflowFailed, string(v1alpha1.NodeError)}
+
+// uniqueViolationPgErrorCode is returned when a summary for the workflow was saved by a concurrent ingestion
+const uniqueViolationPgErrorCode = "23505"
+
+// IsReportIngestionStatus returns true for the terminal workflow statuses after which reports can be ingested
+func IsReportIngestionStatus(status string) bool {
+	return slices.Contains(reportIngestionStatuses, status)
+}
+
+func (impl *TestReportServiceImpl) IngestCiWorkflowReports(ciWorkflowId int) error {
+	ciWorkflow, err := impl.ciWorkflowRepository.FindById(ciWorkflowId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci workflow", "ciWorkflowId", ciWorkflowId, "err", err)
+		return err
+	}
+	if !IsReportIngestionStatus(ciWorkflow.Status) || !ciWorkflow.BlobStorageEnabled {
+		return nil
+	}
+	if ingested, err := impl.isIngested(impl.testReportRepository.FindByCiWorkflowId(ciWorkflowId)); err != nil || ingested {
+		return err
+	}
+	stages, err := impl.pipelineStageRepository.GetAllCiStagesByCiPipelineId(ciWorkflow.CiPipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		return err
+	}
+	reportPaths, err := impl.getReportPaths(stages)
+	if err != nil || reportPaths.IsEmpty() {
+		return err
+	}
+	summary := &testReportRepository.TestReportSummary{
+		CiWorkflowId:   ciWorkflow.Id,
+		WorkflowType:   string(bean.WorkflowTypeCi),
+		CiPipelineId:   ciWorkflow.CiPipelineId,
+		SourceRevision: adapter.GetSourceRevisionFromGitTriggers(ciWorkflow.GitTriggers),
+		AuditLog:       sql.NewDefaultAuditLog(userBean.SystemUserId),
+	}
+	artifactFile, err := impl.ciHandlerService.DownloadCiWorkflowArtifacts(ciWorkflow.CiPipelineId, ciWorkflow.Id)
+	return impl.ingestReports(summary, reportPaths, artifactFile, err)
+}
+
+func (impl *TestReportServiceImpl) IngestCdWorkflowRunnerReports(cdWorkflowRunnerId int) error {
+	wfr, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(cdWorkflowRunnerId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd workflow runner", "cdWorkflowRunnerId", cdWorkflowRunnerId, "err", err)
+		return err
+	}
+	var stageType pipelineStageRepository.PipelineStageType
+	switch wfr.WorkflowType {
+	case cdWorkflow.WorkflowTypePre:
+		stageType = pipelineStageRepository.PIPELINE_STAGE_TYPE_PRE_CD
+	case cdWorkflow.WorkflowTypePost:
+		stageType = pipelineStageRepository.PIPELINE_STAGE_TYPE_POST_CD
+	default:
+		return nil
+	}
+	if !IsReportIngestionStatus(wfr.Status) || !wfr.BlobStorageEnabled {
+		return nil
+	}
+	if ingested, err := impl.isIngested(impl.testReportRepository.FindByCdWorkflowRunnerId(cdWorkflowRunnerId)); err != nil || ingested {
+		return err
+	}
+	stage, err := impl.pipelineStageRepository.GetCdStageByCdPipelineIdAndStageType(wfr.CdWorkflow.PipelineId, stageType)
+	if err == pg.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	reportPaths, err := impl.getReportPaths([]*pipelineStageRepository.PipelineStage{stage})
+	if err != nil || reportPaths.IsEmpty() {
+		return err
+	}
+	summary := &testReportRepository.TestReportSummary{
+		CdWorkflowRunnerId: wfr.Id,
+		WorkflowType:       string(wfr.WorkflowType),
+		CdPipelineId:       wfr.CdWorkflow.PipelineId,
+		AuditLog:           sql.NewDefaultAuditLog(userBean.SystemUserId),
+	}
+	if wfr.CdWorkflow.CiArtifact != nil {
+		summary.SourceRevision = adapter.GetSourceRevisionFromMaterialInfo(wfr.CdWorkflow.CiArtifact.MaterialInfo)
+	}
+	artifactFile, err := impl.cdHandlerService.DownloadCdWorkflowArtifacts(wfr.Id)
+	return impl.ingestReports(summary, reportPaths, artifactFile, err)
+}
+
+func (impl *TestReportServiceImpl) isIngested(summary *testReportRepository.TestReportSummary, err error) (bool, error) {
+	if err == pg.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching test report summary", "err", err)
+		return false, err
+	}
+	return summary.Id > 0, nil
+}
+
+func (impl *TestReportServiceImpl) getReportPaths(stages []*pipelineStageRepository.PipelineStage) (*bean.ReportPaths, error) {
+	reportPaths := &bean.ReportPaths{CoverageFormats: make(map[string]bean.CoverageFormat)}
+	for _, stage := range stages {
+		steps, err := impl.pipelineStageRepository.GetAllStepsByStageId(stage.Id)
+		if err != nil && err != pg.ErrNoRows {
+			return nil, err
+		}
+		for _, step := range steps {
+			reportPaths.TestReportPaths = append(reportPaths.TestReportPaths, step.TestReportPaths...)
+			reportPaths.CoverageReportPaths = append(reportPaths.CoverageReportPaths, step.CoverageReportPaths...)
+			for _, coverageReportPath := range step.CoverageReportPaths {
+				reportPaths.CoverageFormats[coverageReportPath] = bean.CoverageFormat(step.CoverageReportFormat)
+			}
+		}
+	}
+	return reportPaths, nil
+}
+
+// ingestReports parses the report files out of the downloaded artifacts and saves the summary. A summary is saved
+// even when nothing could be parsed so that the failure reason is visible in the build history.
+func (impl *TestReportServiceImpl) ingestReports(summary *testReportRepository.TestReportSummary, reportPaths *bean.ReportPaths,
+	artifactFile *os.File, downloadErr error) error {
+	var testCases []*bean.TestCaseResult
+	if downloadErr != nil {
+		impl.logger.Errorw("error in downloading workflow artifacts for test reports", "ciWorkflowId", summary.CiWorkflowId, "cdWorkflowRunnerId", summary.CdWorkflowRunnerId, "err", downloadErr)
+		summary.Status = string(bean.IngestionFailed)
+		summary.Error = fmt.Sprintf("unable to download workflow artifacts: %s", downloadErr.Error())
+	} else {
+		defer func() {
+			artifactFile.Close()
+			os.Remove(artifactFile.Name())
+		}()
+		testCases = impl.parseReports(summary, reportPaths, artifactFile.Name())
+	}
+	testCaseResults := adapter.BuildTestCaseResults(summary, testCases, impl.config.MaxTestCasesPerReport)
+	summary.TestCasesTruncated = len(testCaseResults) < len(testCases)
+	err := impl.testReportRepository.Save(summary, testCaseResults)
+	if pgErr, ok := err.(pg.Error); ok && pgErr.Field('C') == uniqueViolationPgErrorCode {
+		// status updates of a workflow can be delivered more than once, the first ingestion wins
+		impl.logger.Infow("test reports already ingested", "ciWorkflowId", summary.CiWorkflowId, "cdWorkflowRunnerId", summary.CdWorkflowRunnerId)
+		return nil
+	} else if err != nil {
+		impl.logger.Errorw("error in saving test report", "ciWorkflowId", summary.CiWorkflowId, "cdWorkflowRunnerId", summary.CdWorkflowRunnerId, "err", err)
+		return err
+	}
+	impl.logger.Infow("ingested test reports", "ciWorkflowId", summary.CiWorkflowId, "cdWorkflowRunnerId", summary.CdWorkflowRunnerId,
+		"status", summary.Status, "total", summary.Total, "failed", summary.Failed)
+	return nil
+}
+
+func (impl *TestReportServiceImpl) parseReports(summary *testReportRepository.TestReportSummary, reportPaths *bean.ReportPaths, artifactFilePath string) []*bean.TestCaseResult {
+	zipReader, err := zip.OpenReader(artifactFilePath)
+	if err != nil {
+		summary.Status = string(bean.IngestionFailed)
+		summary.Error = fmt.Sprintf("unable to read workflow artifacts: %s", err.Error())
+		return nil
+	}
+	defer zipReader.Close()
+
+	testCases := make([]*bean.TestCaseResult, 0)
+	var coverage *bean.CoverageResult
+	var parseErrors []string
+	parsedFiles := 0
+	for _, file := range zipReader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		isTestReport, isCoverageReport, coverageFormat := impl.classifyReportFile(file.Name, reportPaths)
+		if !isTestReport && !isCoverageReport {
+			continue
+		}
+		content, err := impl.readZipFile(file)
+		if err == nil && isTestReport {
+			var fileTestCases []*bean.TestCaseResult
+			if fileTestCases, err = parser.ParseJUnitReport(content); err == nil {
+				testCases = append(testCases, fileTestCases...)
+			}
+		} else if err == nil {
+			var fileCoverage *bean.CoverageResult
+			switch coverageFormat {
+			case bean.CoverageFormatLcov:
+				fileCoverage, err = parser.ParseLcovReport(content)
+			default:
+				fileCoverage, err = parser.ParseCoberturaReport(content)
+			}
+			if err == nil {
+				if coverage == nil {
+					coverage = &bean.CoverageResult{}
+				}
+				coverage.Add(fileCoverage)
+			}
+		}
+		if err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("%s: %s", file.Name, err.Error()))
+			continue
+		}
+		parsedFiles++
+	}
+	adapter.SetTestCounts(summary, testCases)
+	if coverage != nil {
+		summary.LinesCovered, summary.LinesValid = &coverage.LinesCovered, &coverage.LinesValid
+	}
+	switch {
+	case parsedFiles == 0 && len(parseErrors) == 0:
+		summary.Status = string(bean.IngestionFailed)
+		summary.Error = "no report files found for the declared report paths"
+	case parsedFiles == 0:
+		summary.Status = string(bean.IngestionFailed)
+	case len(parseErrors) > 0:
+		summary.Status = string(bean.IngestionPartiallySucceeded)
+	default:
+		summary.Status = string(bean.IngestionSucceeded)
+	}
+	if len(parseErrors) > 0 {
+		summary.Error = strings.Join(parseErrors, "\n")
+		if len(summary.Error) > bean.MaxFailureMessageLength {
+			summary.Error = summary.Error[:bean.MaxFailureMessageLength]
+		}
+	}
+	return testCases
+}
+
+// classifyReportFile matches a file of the artifacts against the declared paths. Directories declared as test
+// report paths may hold other files, only xml files of those directories are read.
+func (impl *TestReportServiceImpl) classifyReportFile(fileName string, reportPaths *bean.ReportPaths) (isTestReport bool, isCoverageReport bool, coverageFormat bean.CoverageFormat) {
+	for _, testReportPath := range reportPaths.TestReportPaths {
+		if adapter.MatchesReportPath(fileName, testReportPath) && adapter.IsJUnitReportFile(fileName) {
+			return true, false, ""
+		}
+	}
+	for _, coverageReportPath := range reportPaths.CoverageReportPaths {
+		if !adapter.MatchesReportPath(fileName, coverageReportPath) {
+			continue
+		}
+		if format, ok := adapter.GetCoverageFormat(fileName, reportPaths.CoverageFormats[coverageReportPath]); ok {
+			return false, true, format
+		}
+	}
+	return false, false, ""
+}
+
+func (impl *TestReportServiceImpl) readZipFile(file *zip.File) ([]byte, error) {
+	maxSize := int64(impl.config.MaxReportFileSizeMB) * 1024 * 1024
+	if int64(file.UncompressedSize64) > maxSize {
+		return nil, fmt.Errorf("report file is larger than %d MB", impl.config.MaxReportFileSizeMB)
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	// the declared size can not be trusted, reading is capped as well
+	content, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > maxSize {
+		return nil, fmt.Errorf("report file is larger than %d MB", impl.config.MaxReportFileSizeMB)
+	}
+	return content, nil
+}
+
+func (impl *TestReportServiceImpl) GetCiWorkflowTestReport(ciWorkflowId int) (*bean.TestReportDetailDto, error) {
+	ciWorkflow, err := impl.ciWorkflowRepository.FindById(ciWorkflowId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci workflow", "ciWorkflowId", ciWorkflowId, "err", err)
+		return nil, err
+	}
+	summary, err := impl.testReportRepository.FindByCiWorkflowId(ciWorkflowId)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "test report not found", "test report not found")
+	} else if err != nil {
+		return nil, err
+	}
+	flakyTests, err := impl.testReportRepository.FindFlakyTestsByCiPipelineId(summary.CiPipelineId, impl.getFlakyLookbackStart())
+	if err != nil {
+		return nil, err
+	}
+	return impl.getTestReportDetail(ciWorkflow.CiPipeline.AppId, summary, flakyTests)
+}
+
+func (impl *TestReportServiceImpl) GetCdWorkflowRunnerTestReport(cdWorkflowRunnerId int) (*bean.TestReportDetailDto, error) {
+	wfr, err := impl.cdWorkflowRepository.FindWorkflowRunnerById(cdWorkflowRunnerId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd workflow runner", "cdWorkflowRunnerId", cdWorkflowRunnerId, "err", err)
+		return nil, err
+	}
+	summary, err := impl.testReportRepository.FindByCdWorkflowRunnerId(cdWorkflowRunnerId)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "test report not found", "test report not found")
+	} else if err != nil {
+		return nil, err
+	}
+	flakyTests, err := impl.testReportRepository.FindFlakyTestsByCdPipelineId(summary.CdPipelineId, impl.getFlakyLookbackStart())
+	if err != nil {
+		return nil, err
+	}
+	return impl.getTestReportDetail(wfr.CdWorkflow.Pipeline.AppId, summary, flakyTests)
+}
+
+func (impl *TestReportServiceImpl) getTestReportDetail(appId int, summary *testReportRepository.TestReportSummary, flakyTests []*testReportRepository.FlakyTest) (*bean.TestReportDetailDto, error) {
+	testCases, err := impl.testReportRepository.FindTestCasesBySummaryId(summary.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &bean.TestReportDetailDto{
+		AppId:     appId,
+		Summary:   adapter.BuildSummaryDto(summary),
+		TestCases: adapter.BuildTestCaseDtos(testCases, flakyTests),
+	}, nil
+}
+
+func (impl *TestReportServiceImpl) GetCiPipelineFlakyTests(ciPipelineId int) (*bean.FlakyTestsResponse, error) {
+	ciPipeline, err := impl.ciPipelineRepository.FindById(ciPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching ci pipeline", "ciPipelineId", ciPipelineId, "err", err)
+		return nil, err
+	}
+	flakyTests, err := impl.testReportRepository.FindFlakyTestsByCiPipelineId(ciPipelineId, impl.getFlakyLookbackStart())
+	if err != nil {
+		return nil, err
+	}
+	return &bean.FlakyTestsResponse{
+		AppId:        ciPipeline.AppId,
+		PipelineId:   ciPipelineId,
+		LookbackDays: impl.config.FlakyTestLookbackDays,
+		FlakyTests:   adapter.BuildFlakyTestDtos(flakyTests),
+	}, nil
+}
+
+func (impl *TestReportServiceImpl) GetCdPipelineFlakyTests(cdPipelineId int) (*bean.FlakyTestsResponse, error) {
+	cdPipeline, err := impl.pipelineRepository.FindById(cdPipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cd pipeline", "cdPipelineId", cdPipelineId, "err", err)
+		return nil, err
+	}
+	flakyTests, err := impl.testReportRepository.FindFlakyTestsByCdPipelineId(cdPipelineId, impl.getFlakyLookbackStart())
+	if err != nil {
+		return nil, err
+	}
+	return &bean.FlakyTestsResponse{
+		AppId:        cdPipeline.AppId,
+		PipelineId:   cdPipelineId,
+		LookbackDays: impl.config.FlakyTestLookbackDays,
+		FlakyTests:   adapter.BuildFlakyTestDtos(flakyTests),
+	}, nil
+}
+
+func (impl *TestReportServiceImpl) getFlakyLookbackStart() time.Time {
+	return time.Now().AddDate(0, 0, -impl.config.FlakyTestLookbackDays)
+}
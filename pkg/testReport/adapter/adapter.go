@@ -0,0 +1,254 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/testReport/bean"
+	testReportRepository "github.com/devtron-labs/devtron/pkg/testReport/repository"
+	"math"
+	"path"
+	"slices"
+	"strings"
+)
+
+const maxSourceRevisionLength = 500
+
+// GetSourceRevisionFromGitTriggers joins the commits a ci workflow was triggered with, the revision identifies
+// runs of the same code across workflows for flaky test detection
+func GetSourceRevisionFromGitTriggers(gitTriggers map[int]pipelineConfig.GitCommit) string {
+	commits := make([]string, 0, len(gitTriggers))
+	for _, gitTrigger := range gitTriggers {
+		commits = append(commits, gitTrigger.Commit)
+	}
+	return joinRevisions(commits)
+}
+
+// GetSourceRevisionFromMaterialInfo joins the commits in the material info of an artifact, in the same format as GetSourceRevisionFromGitTriggers
+func GetSourceRevisionFromMaterialInfo(materialInfo string) string {
+	var ciMaterials []repository.CiMaterialInfo
+	if err := json.Unmarshal([]byte(materialInfo), &ciMaterials); err != nil {
+		return ""
+	}
+	commits := make([]string, 0, len(ciMaterials))
+	for _, ciMaterial := range ciMaterials {
+		for _, modification := range ciMaterial.Modifications {
+			commits = append(commits, modification.Revision)
+		}
+	}
+	return joinRevisions(commits)
+}
+
+func joinRevisions(commits []string) string {
+	revisions := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		if len(commit) > 0 && !slices.Contains(revisions, commit) {
+			revisions = append(revisions, commit)
+		}
+	}
+	slices.Sort(revisions)
+	revision := strings.Join(revisions, ",")
+	if len(revision) > maxSourceRevisionLength {
+		revision = revision[:maxSourceRevisionLength]
+	}
+	return revision
+}
+
+// MatchesReportPath checks if a file of the uploaded artifacts belongs to a declared report path. Artifacts are
+// stored with their path on the runner, so declared paths are matched as a suffix of the file path or as one
+// of its parent directories. Glob patterns are matched against the trailing segments of the file path.
+func MatchesReportPath(filePath, reportPath string) bool {
+	filePath = strings.Trim(path.Clean("/"+filePath), "/")
+	reportPath = strings.Trim(path.Clean("/"+reportPath), "/")
+	if len(reportPath) == 0 || len(filePath) == 0 {
+		return false
+	}
+	if strings.ContainsAny(reportPath, "*?[") {
+		patternSegments := strings.Count(reportPath, "/") + 1
+		fileSegments := strings.Split(filePath, "/")
+		if len(fileSegments) < patternSegments {
+			return false
+		}
+		matched, err := path.Match(reportPath, strings.Join(fileSegments[len(fileSegments)-patternSegments:], "/"))
+		return err == nil && matched
+	}
+	paddedFilePath := "/" + filePath
+	return strings.HasSuffix(paddedFilePath, "/"+reportPath) || strings.Contains(paddedFilePath, "/"+reportPath+"/")
+}
+
+// IsJUnitReportFile skips non xml files found in declared test report directories
+func IsJUnitReportFile(filePath string) bool {
+	return strings.EqualFold(path.Ext(filePath), ".xml")
+}
+
+// GetCoverageFormat returns the declared format, or infers it from the file extension
+func GetCoverageFormat(filePath string, declaredFormat bean.CoverageFormat) (bean.CoverageFormat, bool) {
+	if len(declaredFormat) > 0 {
+		return declaredFormat, true
+	}
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".xml":
+		return bean.CoverageFormatCobertura, true
+	case ".info", ".lcov", ".dat":
+		return bean.CoverageFormatLcov, true
+	}
+	return "", false
+}
+
+// SetTestCounts aggregates the parsed test cases on the summary
+func SetTestCounts(summary *testReportRepository.TestReportSummary, testCases []*bean.TestCaseResult) {
+	for _, testCase := range testCases {
+		summary.Total++
+		summary.DurationMs += testCase.DurationMs
+		switch testCase.Status {
+		case bean.TestStatusPassed:
+			summary.Passed++
+		case bean.TestStatusFailed:
+			summary.Failed++
+		case bean.TestStatusErrored:
+			summary.Errored++
+		case bean.TestStatusSkipped:
+			summary.Skipped++
+		}
+	}
+}
+
+// GetPassRate is the percentage of executed tests that passed, a report without executed tests has a zero pass rate
+func GetPassRate(passed, failed, errored int) float64 {
+	executed := passed + failed + errored
+	if executed == 0 {
+		return 0
+	}
+	return roundPercentage(float64(passed) * 100 / float64(executed))
+}
+
+func GetLineCoverage(linesCovered, linesValid *int) *float64 {
+	if linesCovered == nil || linesValid == nil || *linesValid == 0 {
+		return nil
+	}
+	coverage := roundPercentage(float64(*linesCovered) * 100 / float64(*linesValid))
+	return &coverage
+}
+
+func roundPercentage(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// BuildTestCaseResults builds the test case rows of a report. Beyond maxTestCases only passed and skipped test cases
+// are dropped, failed and errored ones are always kept as they are the results looked at
+func BuildTestCaseResults(summary *testReportRepository.TestReportSummary, testCases []*bean.TestCaseResult, maxTestCases int) []*testReportRepository.TestCaseResult {
+	if maxTestCases > 0 && len(testCases) > maxTestCases {
+		failures := make([]*bean.TestCaseResult, 0)
+		others := make([]*bean.TestCaseResult, 0)
+		for _, testCase := range testCases {
+			if testCase.Status.IsFailure() {
+				failures = append(failures, testCase)
+			} else {
+				others = append(others, testCase)
+			}
+		}
+		keptOthers := max(maxTestCases-len(failures), 0)
+		testCases = append(failures, others[:keptOthers]...)
+	}
+	results := make([]*testReportRepository.TestCaseResult, 0, len(testCases))
+	for _, testCase := range testCases {
+		results = append(results, &testReportRepository.TestCaseResult{
+			CiPipelineId:   summary.CiPipelineId,
+			CdPipelineId:   summary.CdPipelineId,
+			SourceRevision: summary.SourceRevision,
+			SuiteName:      truncate(testCase.SuiteName),
+			ClassName:      truncate(testCase.ClassName),
+			TestName:       truncate(testCase.TestName),
+			Status:         string(testCase.Status),
+			DurationMs:     testCase.DurationMs,
+			FailureMessage: testCase.FailureMessage,
+			CreatedOn:      summary.CreatedOn,
+		})
+	}
+	return results
+}
+
+func truncate(value string) string {
+	if len(value) > 500 {
+		return value[:500]
+	}
+	return value
+}
+
+func BuildSummaryDto(summary *testReportRepository.TestReportSummary) *bean.TestReportSummaryDto {
+	return &bean.TestReportSummaryDto{
+		Id:                 summary.Id,
+		CiWorkflowId:       summary.CiWorkflowId,
+		CdWorkflowRunnerId: summary.CdWorkflowRunnerId,
+		WorkflowType:       bean.WorkflowType(summary.WorkflowType),
+		SourceRevision:     summary.SourceRevision,
+		Total:              summary.Total,
+		Passed:             summary.Passed,
+		Failed:             summary.Failed,
+		Errored:            summary.Errored,
+		Skipped:            summary.Skipped,
+		PassRate:           GetPassRate(summary.Passed, summary.Failed, summary.Errored),
+		LineCoverage:       GetLineCoverage(summary.LinesCovered, summary.LinesValid),
+		DurationMs:         summary.DurationMs,
+		Status:             bean.IngestionStatus(summary.Status),
+		Error:              summary.Error,
+		TestCasesTruncated: summary.TestCasesTruncated,
+		CreatedOn:          summary.CreatedOn,
+	}
+}
+
+func GetTestKey(suiteName, className, testName string) string {
+	return strings.Join([]string{suiteName, className, testName}, "/")
+}
+
+func BuildTestCaseDtos(testCases []*testReportRepository.TestCaseResult, flakyTests []*testReportRepository.FlakyTest) []*bean.TestCaseResultDto {
+	flakyTestKeys := make(map[string]bool, len(flakyTests))
+	for _, flakyTest := range flakyTests {
+		flakyTestKeys[GetTestKey(flakyTest.SuiteName, flakyTest.ClassName, flakyTest.TestName)] = true
+	}
+	dtos := make([]*bean.TestCaseResultDto, 0, len(testCases))
+	for _, testCase := range testCases {
+		dtos = append(dtos, &bean.TestCaseResultDto{
+			SuiteName:      testCase.SuiteName,
+			ClassName:      testCase.ClassName,
+			TestName:       testCase.TestName,
+			Status:         bean.TestStatus(testCase.Status),
+			DurationMs:     testCase.DurationMs,
+			FailureMessage: testCase.FailureMessage,
+			IsFlaky:        flakyTestKeys[GetTestKey(testCase.SuiteName, testCase.ClassName, testCase.TestName)],
+		})
+	}
+	return dtos
+}
+
+func BuildFlakyTestDtos(flakyTests []*testReportRepository.FlakyTest) []*bean.FlakyTestDto {
+	dtos := make([]*bean.FlakyTestDto, 0, len(flakyTests))
+	for _, flakyTest := range flakyTests {
+		dtos = append(dtos, &bean.FlakyTestDto{
+			SuiteName:      flakyTest.SuiteName,
+			ClassName:      flakyTest.ClassName,
+			TestName:       flakyTest.TestName,
+			FlakyRevisions: flakyTest.FlakyRevisions,
+			PassCount:      flakyTest.PassCount,
+			FailCount:      flakyTest.FailCount,
+			LastFailedOn:   flakyTest.LastFailedOn,
+		})
+	}
+	return dtos
+}
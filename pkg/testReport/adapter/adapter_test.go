@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package adapter
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/testReport/bean"
+	testReportRepository "github.com/devtron-labs/devtron/pkg/testReport/repository"
+	"testing"
+)
+
+func TestMatchesReportPath(t *testing.T) {
+	cases := []struct {
+		filePath, reportPath string
+		expected             bool
+	}{
+		{"devtroncd/app/target/surefire-reports/TEST-a.xml", "/devtroncd/app/target/surefire-reports", true},
+		{"devtroncd/app/target/surefire-reports/TEST-a.xml", "target/surefire-reports/TEST-a.xml", true},
+		{"devtroncd/app/target/surefire-reports/TEST-a.xml", "./target/surefire-reports/", true},
+		{"devtroncd/app/target/surefire-reports-old/TEST-a.xml", "target/surefire-reports", false},
+		{"devtroncd/app/reports/junit-1.xml", "reports/junit-*.xml", true},
+		{"devtroncd/app/reports/unit/junit-1.xml", "reports/junit-*.xml", false},
+		{"junit.xml", "/tmp/reports/junit.xml", false},
+		{"devtroncd/app/junit.xml", "/", false},
+	}
+	for _, c := range cases {
+		if actual := MatchesReportPath(c.filePath, c.reportPath); actual != c.expected {
+			t.Errorf("MatchesReportPath(%q, %q) = %v, expected %v", c.filePath, c.reportPath, actual, c.expected)
+		}
+	}
+}
+
+func TestGetSourceRevisionFromGitTriggers(t *testing.T) {
+	revision := GetSourceRevisionFromGitTriggers(map[int]pipelineConfig.GitCommit{
+		2: {Commit: "bbb"},
+		1: {Commit: "aaa"},
+		3: {Commit: ""},
+	})
+	if revision != "aaa,bbb" {
+		t.Errorf("unexpected revision %q", revision)
+	}
+	materialInfo := `[{"material":{},"modifications":[{"revision":"bbb"}]},{"material":{},"modifications":[{"revision":"aaa"}]}]`
+	if fromArtifact := GetSourceRevisionFromMaterialInfo(materialInfo); fromArtifact != revision {
+		t.Errorf("revision from material info %q does not match revision from git triggers %q", fromArtifact, revision)
+	}
+}
+
+func TestSummaryAggregation(t *testing.T) {
+	testCases := []*bean.TestCaseResult{
+		{TestName: "a", Status: bean.TestStatusPassed, DurationMs: 10},
+		{TestName: "b", Status: bean.TestStatusPassed, DurationMs: 10},
+		{TestName: "c", Status: bean.TestStatusFailed, DurationMs: 10},
+		{TestName: "d", Status: bean.TestStatusSkipped},
+	}
+	summary := &testReportRepository.TestReportSummary{}
+	SetTestCounts(summary, testCases)
+	if summary.Total != 4 || summary.Passed != 2 || summary.Failed != 1 || summary.Skipped != 1 || summary.DurationMs != 30 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if passRate := GetPassRate(summary.Passed, summary.Failed, summary.Errored); passRate != 66.67 {
+		t.Errorf("unexpected pass rate %v", passRate)
+	}
+	if passRate := GetPassRate(0, 0, 0); passRate != 0 {
+		t.Errorf("expected zero pass rate without executed tests, got %v", passRate)
+	}
+	results := BuildTestCaseResults(summary, testCases, 2)
+	if len(results) != 2 || results[0].TestName != "c" {
+		t.Errorf("expected failures to be kept first when truncating, got %+v", results)
+	}
+	failures := []*bean.TestCaseResult{
+		{TestName: "e", Status: bean.TestStatusFailed},
+		{TestName: "f", Status: bean.TestStatusErrored},
+		{TestName: "g", Status: bean.TestStatusPassed},
+		{TestName: "h", Status: bean.TestStatusFailed},
+	}
+	results = BuildTestCaseResults(summary, failures, 2)
+	if len(results) != 3 || results[0].TestName != "e" || results[1].TestName != "f" || results[2].TestName != "h" {
+		t.Errorf("expected every failed and errored test case to be kept beyond the limit, got %+v", results)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type WorkflowType string
+
+const (
+	WorkflowTypeCi     WorkflowType = "CI"
+	WorkflowTypePreCd  WorkflowType = "PRE"
+	WorkflowTypePostCd WorkflowType = "POST"
+)
+
+type TestStatus string
+
+const (
+	TestStatusPassed  TestStatus = "PASSED"
+	TestStatusFailed  TestStatus = "FAILED"
+	TestStatusErrored TestStatus = "ERRORED"
+	TestStatusSkipped TestStatus = "SKIPPED"
+)
+
+func (s TestStatus) IsFailure() bool {
+	return s == TestStatusFailed || s == TestStatusErrored
+}
+
+type CoverageFormat string
+
+const (
+	CoverageFormatCobertura CoverageFormat = "COBERTURA"
+	CoverageFormatLcov      CoverageFormat = "LCOV"
+)
+
+type IngestionStatus string
+
+const (
+	IngestionSucceeded          IngestionStatus = "SUCCEEDED"
+	IngestionPartiallySucceeded IngestionStatus = "PARTIALLY_SUCCEEDED"
+	IngestionFailed             IngestionStatus = "FAILED"
+)
+
+const MaxFailureMessageLength = 4096
+
+// TestCaseResult is a single test case parsed from a JUnit report
+type TestCaseResult struct {
+	SuiteName      string
+	ClassName      string
+	TestName       string
+	Status         TestStatus
+	DurationMs     int64
+	FailureMessage string
+}
+
+// CoverageResult is the line coverage parsed from a coverage report
+type CoverageResult struct {
+	LinesCovered int
+	LinesValid   int
+}
+
+func (c *CoverageResult) Add(other *CoverageResult) {
+	c.LinesCovered += other.LinesCovered
+	c.LinesValid += other.LinesValid
+}
+
+// ReportPaths are the report paths declared on the steps of a workflow
+type ReportPaths struct {
+	TestReportPaths     []string
+	CoverageReportPaths []string
+	// CoverageFormats maps a coverage report path to the format declared on its step
+	CoverageFormats map[string]CoverageFormat
+}
+
+func (r *ReportPaths) IsEmpty() bool {
+	return len(r.TestReportPaths) == 0 && len(r.CoverageReportPaths) == 0
+}
+
+type TestReportSummaryDto struct {
+	Id                 int             `json:"id"`
+	CiWorkflowId       int             `json:"ciWorkflowId,omitempty"`
+	CdWorkflowRunnerId int             `json:"cdWorkflowRunnerId,omitempty"`
+	WorkflowType       WorkflowType    `json:"workflowType"`
+	SourceRevision     string          `json:"sourceRevision,omitempty"`
+	Total              int             `json:"total"`
+	Passed             int             `json:"passed"`
+	Failed             int             `json:"failed"`
+	Errored            int             `json:"errored"`
+	Skipped            int             `json:"skipped"`
+	PassRate           float64         `json:"passRate"` // percentage of executed (non skipped) tests which passed
+	LineCoverage       *float64        `json:"lineCoverage,omitempty"`
+	DurationMs         int64           `json:"durationMs"`
+	Status             IngestionStatus `json:"status"`
+	Error              string          `json:"error,omitempty"`
+	TestCasesTruncated bool            `json:"testCasesTruncated"` // only part of the passed and skipped test cases are stored
+	CreatedOn          time.Time       `json:"createdOn"`
+}
+
+type TestCaseResultDto struct {
+	SuiteName      string     `json:"suiteName,omitempty"`
+	ClassName      string     `json:"className,omitempty"`
+	TestName       string     `json:"testName"`
+	Status         TestStatus `json:"status"`
+	DurationMs     int64      `json:"durationMs"`
+	FailureMessage string     `json:"failureMessage,omitempty"`
+	IsFlaky        bool       `json:"isFlaky"`
+}
+
+type TestReportDetailDto struct {
+	AppId     int                   `json:"-"`
+	Summary   *TestReportSummaryDto `json:"summary"`
+	TestCases []*TestCaseResultDto  `json:"testCases"`
+}
+
+// FlakyTestDto is a test which both passed and failed on the same source revision
+type FlakyTestDto struct {
+	SuiteName      string    `json:"suiteName,omitempty"`
+	ClassName      string    `json:"className,omitempty"`
+	TestName       string    `json:"testName"`
+	FlakyRevisions int       `json:"flakyRevisions"`
+	PassCount      int       `json:"passCount"`
+	FailCount      int       `json:"failCount"`
+	LastFailedOn   time.Time `json:"lastFailedOn"`
+}
+
+type FlakyTestsResponse struct {
+	AppId        int             `json:"-"`
+	PipelineId   int             `json:"pipelineId"`
+	LookbackDays int             `json:"lookbackDays"`
+	FlakyTests   []*FlakyTestDto `json:"flakyTests"`
+}
+
+// ArtifactTestFacts aggregates the test reports of the build of an artifact and of the post stage of the
+// artifact on a cd pipeline, used as inputs to deployment policies
+type ArtifactTestFacts struct {
+	ReportFound  bool
+	TotalTests   int
+	FailedTests  int
+	PassRate     float64
+	LineCoverage float64
+}
@@ -0,0 +1,201 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/testReport/bean"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure"`
+	Error     *junitFailure `xml:"error"`
+	Skipped   *junitFailure `xml:"skipped"`
+}
+
+type junitTestSuite struct {
+	XMLName   xml.Name          `xml:"testsuite"`
+	Name      string            `xml:"name,attr"`
+	Suites    []*junitTestSuite `xml:"testsuite"`
+	TestCases []*junitTestCase  `xml:"testcase"`
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name          `xml:"testsuites"`
+	Suites  []*junitTestSuite `xml:"testsuite"`
+}
+
+// ParseJUnitReport parses a JUnit XML report having either <testsuites> or a single <testsuite> as root,
+// nested test suites are flattened
+func ParseJUnitReport(content []byte) ([]*bean.TestCaseResult, error) {
+	rootName, err := getRootElementName(content)
+	if err != nil {
+		return nil, err
+	}
+	var suites []*junitTestSuite
+	switch rootName {
+	case "testsuites":
+		root := &junitTestSuites{}
+		if err = xml.Unmarshal(content, root); err != nil {
+			return nil, err
+		}
+		suites = root.Suites
+	case "testsuite":
+		root := &junitTestSuite{}
+		if err = xml.Unmarshal(content, root); err != nil {
+			return nil, err
+		}
+		suites = []*junitTestSuite{root}
+	default:
+		return nil, fmt.Errorf("unexpected root element %q in junit report", rootName)
+	}
+	results := make([]*bean.TestCaseResult, 0)
+	for _, suite := range suites {
+		results = appendSuiteResults(results, suite)
+	}
+	return results, nil
+}
+
+func appendSuiteResults(results []*bean.TestCaseResult, suite *junitTestSuite) []*bean.TestCaseResult {
+	for _, testCase := range suite.TestCases {
+		result := &bean.TestCaseResult{
+			SuiteName:  suite.Name,
+			ClassName:  testCase.ClassName,
+			TestName:   testCase.Name,
+			Status:     bean.TestStatusPassed,
+			DurationMs: parseDurationMs(testCase.Time),
+		}
+		switch {
+		case testCase.Failure != nil:
+			result.Status = bean.TestStatusFailed
+			result.FailureMessage = getFailureMessage(testCase.Failure)
+		case testCase.Error != nil:
+			result.Status = bean.TestStatusErrored
+			result.FailureMessage = getFailureMessage(testCase.Error)
+		case testCase.Skipped != nil:
+			result.Status = bean.TestStatusSkipped
+		}
+		results = append(results, result)
+	}
+	for _, nestedSuite := range suite.Suites {
+		results = appendSuiteResults(results, nestedSuite)
+	}
+	return results
+}
+
+func getRootElementName(content []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		if element, ok := token.(xml.StartElement); ok {
+			return element.Name.Local, nil
+		}
+	}
+}
+
+// parseDurationMs parses the time attribute in seconds, some reporters add thousand separators
+func parseDurationMs(seconds string) int64 {
+	value, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(seconds), ",", ""), 64)
+	if err != nil || value < 0 {
+		return 0
+	}
+	return int64(math.Round(value * 1000))
+}
+
+func getFailureMessage(failure *junitFailure) string {
+	message := strings.TrimSpace(failure.Message)
+	if len(message) == 0 {
+		message = strings.TrimSpace(failure.Text)
+	}
+	if len(message) > bean.MaxFailureMessageLength {
+		message = message[:bean.MaxFailureMessageLength]
+	}
+	return message
+}
+
+type coberturaCoverage struct {
+	XMLName      xml.Name `xml:"coverage"`
+	LineRate     string   `xml:"line-rate,attr"`
+	LinesCovered string   `xml:"lines-covered,attr"`
+	LinesValid   string   `xml:"lines-valid,attr"`
+}
+
+// ParseCoberturaReport reads the line totals of a Cobertura XML report
+func ParseCoberturaReport(content []byte) (*bean.CoverageResult, error) {
+	report := &coberturaCoverage{}
+	if err := xml.Unmarshal(content, report); err != nil {
+		return nil, err
+	}
+	linesCovered, coveredErr := strconv.Atoi(report.LinesCovered)
+	linesValid, validErr := strconv.Atoi(report.LinesValid)
+	if coveredErr != nil || validErr != nil {
+		// older cobertura versions only report the rate
+		return nil, errors.New("cobertura report does not have lines-covered and lines-valid attributes")
+	}
+	return &bean.CoverageResult{LinesCovered: linesCovered, LinesValid: linesValid}, nil
+}
+
+// ParseLcovReport sums the LH (lines hit) and LF (lines found) records of all source files of an LCOV tracefile
+func ParseLcovReport(content []byte) (*bean.CoverageResult, error) {
+	result := &bean.CoverageResult{}
+	recordFound := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		var target *int
+		switch {
+		case strings.HasPrefix(line, "LH:"):
+			target = &result.LinesCovered
+		case strings.HasPrefix(line, "LF:"):
+			target = &result.LinesValid
+		default:
+			continue
+		}
+		value, err := strconv.Atoi(line[3:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid lcov record %q", line)
+		}
+		*target += value
+		recordFound = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !recordFound {
+		return nil, errors.New("no LF/LH records found in lcov report")
+	}
+	return result, nil
+}
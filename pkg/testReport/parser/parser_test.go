@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package parser
+
+import (
+	"github.com/devtron-labs/devtron/pkg/testReport/bean"
+	"testing"
+)
+
+const multiSuiteReport = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="api" tests="3">
+    <testcase classname="api.UserTest" name="testCreate" time="0.512"/>
+    <testcase classname="api.UserTest" name="testDelete" time="1,001.5">
+      <failure message="expected 200 but was 500">stack trace</failure>
+    </testcase>
+    <testsuite name="api.nested">
+      <testcase classname="api.nested.AuthTest" name="testLogin" time="0.1">
+        <skipped/>
+      </testcase>
+    </testsuite>
+  </testsuite>
+  <testsuite name="db">
+    <testcase classname="db.MigrationTest" name="testUp">
+      <error>connection refused</error>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+const singleSuiteReport = `<testsuite name="unit"><testcase name="TestAdd" classname="math" time="0.002"/></testsuite>`
+
+func TestParseJUnitReport(t *testing.T) {
+	results, err := ParseJUnitReport([]byte(multiSuiteReport))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 test cases, got %d", len(results))
+	}
+	expected := []struct {
+		suite, name    string
+		status         bean.TestStatus
+		durationMs     int64
+		failureMessage string
+	}{
+		{"api", "testCreate", bean.TestStatusPassed, 512, ""},
+		{"api", "testDelete", bean.TestStatusFailed, 1001500, "expected 200 but was 500"},
+		{"api.nested", "testLogin", bean.TestStatusSkipped, 100, ""},
+		{"db", "testUp", bean.TestStatusErrored, 0, "connection refused"},
+	}
+	for i, exp := range expected {
+		result := results[i]
+		if result.SuiteName != exp.suite || result.TestName != exp.name || result.Status != exp.status ||
+			result.DurationMs != exp.durationMs || result.FailureMessage != exp.failureMessage {
+			t.Errorf("test case %d: got %+v, expected %+v", i, result, exp)
+		}
+	}
+
+	results, err = ParseJUnitReport([]byte(singleSuiteReport))
+	if err != nil || len(results) != 1 || results[0].ClassName != "math" || results[0].Status != bean.TestStatusPassed {
+		t.Errorf("unexpected result for single testsuite root: %+v, err: %v", results, err)
+	}
+
+	if _, err = ParseJUnitReport([]byte(`<coverage line-rate="1"/>`)); err == nil {
+		t.Errorf("expected error for non junit report")
+	}
+}
+
+func TestParseCoberturaReport(t *testing.T) {
+	result, err := ParseCoberturaReport([]byte(`<coverage line-rate="0.75" lines-covered="75" lines-valid="100"><packages/></coverage>`))
+	if err != nil || result.LinesCovered != 75 || result.LinesValid != 100 {
+		t.Errorf("unexpected result: %+v, err: %v", result, err)
+	}
+	if _, err = ParseCoberturaReport([]byte(`<coverage line-rate="0.75"/>`)); err == nil {
+		t.Errorf("expected error when line totals are missing")
+	}
+}
+
+func TestParseLcovReport(t *testing.T) {
+	lcov := "TN:\nSF:src/a.js\nDA:1,1\nLF:10\nLH:8\nend_of_record\nSF:src/b.js\nLF:5\nLH:1\nend_of_record\n"
+	result, err := ParseLcovReport([]byte(lcov))
+	if err != nil || result.LinesCovered != 9 || result.LinesValid != 15 {
+		t.Errorf("unexpected result: %+v, err: %v", result, err)
+	}
+	if _, err = ParseLcovReport([]byte("TN:\nend_of_record\n")); err == nil {
+		t.Errorf("expected error for tracefile without line records")
+	}
+}
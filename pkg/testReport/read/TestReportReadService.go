@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package read
+
+import (
+	"github.com/devtron-labs/devtron/pkg/testReport/adapter"
+	"github.com/devtron-labs/devtron/pkg/testReport/bean"
+	"github.com/devtron-labs/devtron/pkg/testReport/repository"
+	"go.uber.org/zap"
+)
+
+type TestReportReadService interface {
+	// GetSummariesByCiWorkflowIds returns test report summaries keyed by ci workflow id
+	GetSummariesByCiWorkflowIds(ciWorkflowIds []int) (map[int]*bean.TestReportSummaryDto, error)
+	// GetSummariesByCdWorkflowRunnerIds returns test report summaries keyed by cd workflow runner id
+	GetSummariesByCdWorkflowRunnerIds(cdWorkflowRunnerIds []int) (map[int]*bean.TestReportSummaryDto, error)
+	// GetArtifactTestFacts aggregates the reports of the ci workflow which built an artifact and of a post cd
+	// workflow runner of the artifact, either id can be 0
+	GetArtifactTestFacts(ciWorkflowId int, postCdWorkflowRunnerId int) (*bean.ArtifactTestFacts, error)
+}
+
+type TestReportReadServiceImpl struct {
+	logger               *zap.SugaredLogger
+	testReportRepository repository.TestReportRepository
+}
+
+func NewTestReportReadServiceImpl(logger *zap.SugaredLogger,
+	testReportRepository repository.TestReportRepository) *TestReportReadServiceImpl {
+	return &TestReportReadServiceImpl{
+		logger:               logger,
+		testReportRepository: testReportRepository,
+	}
+}
+
+func (impl *TestReportReadServiceImpl) GetSummariesByCiWorkflowIds(ciWorkflowIds []int) (map[int]*bean.TestReportSummaryDto, error) {
+	summaries, err := impl.testReportRepository.FindByCiWorkflowIds(ciWorkflowIds)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int]*bean.TestReportSummaryDto, len(summaries))
+	for _, summary := range summaries {
+		result[summary.CiWorkflowId] = adapter.BuildSummaryDto(summary)
+	}
+	return result, nil
+}
+
+func (impl *TestReportReadServiceImpl) GetSummariesByCdWorkflowRunnerIds(cdWorkflowRunnerIds []int) (map[int]*bean.TestReportSummaryDto, error) {
+	summaries, err := impl.testReportRepository.FindByCdWorkflowRunnerIds(cdWorkflowRunnerIds)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int]*bean.TestReportSummaryDto, len(summaries))
+	for _, summary := range summaries {
+		result[summary.CdWorkflowRunnerId] = adapter.BuildSummaryDto(summary)
+	}
+	return result, nil
+}
+
+func (impl *TestReportReadServiceImpl) GetArtifactTestFacts(ciWorkflowId int, postCdWorkflowRunnerId int) (*bean.ArtifactTestFacts, error) {
+	summaries := make([]*repository.TestReportSummary, 0, 2)
+	if ciWorkflowId > 0 {
+		ciSummaries, err := impl.testReportRepository.FindByCiWorkflowIds([]int{ciWorkflowId})
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, ciSummaries...)
+	}
+	if postCdWorkflowRunnerId > 0 {
+		cdSummaries, err := impl.testReportRepository.FindByCdWorkflowRunnerIds([]int{postCdWorkflowRunnerId})
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, cdSummaries...)
+	}
+	facts := &bean.ArtifactTestFacts{}
+	var passed, failed, errored, linesCovered, linesValid int
+	coverageFound := false
+	for _, summary := range summaries {
+		facts.ReportFound = true
+		facts.TotalTests += summary.Total
+		facts.FailedTests += summary.Failed + summary.Errored
+		passed += summary.Passed
+		failed += summary.Failed
+		errored += summary.Errored
+		if summary.LinesCovered != nil && summary.LinesValid != nil {
+			coverageFound = true
+			linesCovered += *summary.LinesCovered
+			linesValid += *summary.LinesValid
+		}
+	}
+	facts.PassRate = adapter.GetPassRate(passed, failed, errored)
+	if coverageFound {
+		if lineCoverage := adapter.GetLineCoverage(&linesCovered, &linesValid); lineCoverage != nil {
+			facts.LineCoverage = *lineCoverage
+		}
+	}
+	return facts, nil
+}
@@ -0,0 +1,219 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// TestReportSummary holds the aggregated test and coverage results of a ci workflow or a pre/post cd workflow runner
+type TestReportSummary struct {
+	tableName          struct{} `sql:"test_report_summary" pg:",discard_unknown_columns"`
+	Id                 int      `sql:"id,pk"`
+	CiWorkflowId       int      `sql:"ci_workflow_id"`
+	CdWorkflowRunnerId int      `sql:"cd_workflow_runner_id"`
+	WorkflowType       string   `sql:"workflow_type,notnull"`
+	CiPipelineId       int      `sql:"ci_pipeline_id"`
+	CdPipelineId       int      `sql:"cd_pipeline_id"`
+	SourceRevision     string   `sql:"source_revision"`
+	Total              int      `sql:"total,notnull"`
+	Passed             int      `sql:"passed,notnull"`
+	Failed             int      `sql:"failed,notnull"`
+	Errored            int      `sql:"errored,notnull"`
+	Skipped            int      `sql:"skipped,notnull"`
+	DurationMs         int64    `sql:"duration_ms,notnull"`
+	LinesCovered       *int     `sql:"lines_covered"`
+	LinesValid         *int     `sql:"lines_valid"`
+	Status             string   `sql:"status,notnull"`
+	Error              string   `sql:"error"`
+	TestCasesTruncated bool     `sql:"test_cases_truncated,notnull"`
+	sql.AuditLog
+}
+
+// TestCaseResult is a single test case of a report, pipeline and revision are copied from the summary
+type TestCaseResult struct {
+	tableName           struct{}  `sql:"test_case_result" pg:",discard_unknown_columns"`
+	Id                  int       `sql:"id,pk"`
+	TestReportSummaryId int       `sql:"test_report_summary_id,notnull"`
+	CiPipelineId        int       `sql:"ci_pipeline_id"`
+	CdPipelineId        int       `sql:"cd_pipeline_id"`
+	SourceRevision      string    `sql:"source_revision"`
+	SuiteName           string    `sql:"suite_name"`
+	ClassName           string    `sql:"class_name"`
+	TestName            string    `sql:"test_name,notnull"`
+	Status              string    `sql:"status,notnull"`
+	DurationMs          int64     `sql:"duration_ms,notnull"`
+	FailureMessage      string    `sql:"failure_message"`
+	CreatedOn           time.Time `sql:"created_on,notnull"`
+}
+
+// FlakyTest is a test which both passed and failed on at least one source revision
+type FlakyTest struct {
+	SuiteName      string    `sql:"suite_name"`
+	ClassName      string    `sql:"class_name"`
+	TestName       string    `sql:"test_name"`
+	FlakyRevisions int       `sql:"flaky_revisions"`
+	PassCount      int       `sql:"pass_count"`
+	FailCount      int       `sql:"fail_count"`
+	LastFailedOn   time.Time `sql:"last_failed_on"`
+}
+
+type TestReportRepository interface {
+	Save(summary *TestReportSummary, testCases []*TestCaseResult) error
+	FindByCiWorkflowId(ciWorkflowId int) (*TestReportSummary, error)
+	FindByCdWorkflowRunnerId(cdWorkflowRunnerId int) (*TestReportSummary, error)
+	FindByCiWorkflowIds(ciWorkflowIds []int) ([]*TestReportSummary, error)
+	FindByCdWorkflowRunnerIds(cdWorkflowRunnerIds []int) ([]*TestReportSummary, error)
+	FindTestCasesBySummaryId(summaryId int) ([]*TestCaseResult, error)
+	FindFlakyTestsByCiPipelineId(ciPipelineId int, since time.Time) ([]*FlakyTest, error)
+	FindFlakyTestsByCdPipelineId(cdPipelineId int, since time.Time) ([]*FlakyTest, error)
+}
+
+type TestReportRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewTestReportRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *TestReportRepositoryImpl {
+	return &TestReportRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+const testCaseInsertBatchSize = 1000
+
+// Save inserts the summary and its test cases in a single transaction
+func (impl *TestReportRepositoryImpl) Save(summary *TestReportSummary, testCases []*TestCaseResult) error {
+	tx, err := impl.dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err = tx.Insert(summary); err != nil {
+		impl.logger.Errorw("error in saving test report summary", "ciWorkflowId", summary.CiWorkflowId, "cdWorkflowRunnerId", summary.CdWorkflowRunnerId, "err", err)
+		return err
+	}
+	for start := 0; start < len(testCases); start += testCaseInsertBatchSize {
+		batch := testCases[start:min(start+testCaseInsertBatchSize, len(testCases))]
+		for _, testCase := range batch {
+			testCase.TestReportSummaryId = summary.Id
+		}
+		if _, err = tx.Model(&batch).Insert(); err != nil {
+			impl.logger.Errorw("error in saving test case results", "summaryId", summary.Id, "err", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (impl *TestReportRepositoryImpl) FindByCiWorkflowId(ciWorkflowId int) (*TestReportSummary, error) {
+	summary := &TestReportSummary{}
+	err := impl.dbConnection.Model(summary).
+		Where("ci_workflow_id = ?", ciWorkflowId).
+		Select()
+	return summary, err
+}
+
+func (impl *TestReportRepositoryImpl) FindByCdWorkflowRunnerId(cdWorkflowRunnerId int) (*TestReportSummary, error) {
+	summary := &TestReportSummary{}
+	err := impl.dbConnection.Model(summary).
+		Where("cd_workflow_runner_id = ?", cdWorkflowRunnerId).
+		Select()
+	return summary, err
+}
+
+func (impl *TestReportRepositoryImpl) FindByCiWorkflowIds(ciWorkflowIds []int) ([]*TestReportSummary, error) {
+	var summaries []*TestReportSummary
+	if len(ciWorkflowIds) == 0 {
+		return summaries, nil
+	}
+	err := impl.dbConnection.Model(&summaries).
+		Where("ci_workflow_id IN (?)", pg.In(ciWorkflowIds)).
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting test report summaries", "ciWorkflowIds", ciWorkflowIds, "err", err)
+		return nil, err
+	}
+	return summaries, nil
+}
+
+func (impl *TestReportRepositoryImpl) FindByCdWorkflowRunnerIds(cdWorkflowRunnerIds []int) ([]*TestReportSummary, error) {
+	var summaries []*TestReportSummary
+	if len(cdWorkflowRunnerIds) == 0 {
+		return summaries, nil
+	}
+	err := impl.dbConnection.Model(&summaries).
+		Where("cd_workflow_runner_id IN (?)", pg.In(cdWorkflowRunnerIds)).
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting test report summaries", "cdWorkflowRunnerIds", cdWorkflowRunnerIds, "err", err)
+		return nil, err
+	}
+	return summaries, nil
+}
+
+func (impl *TestReportRepositoryImpl) FindTestCasesBySummaryId(summaryId int) ([]*TestCaseResult, error) {
+	var testCases []*TestCaseResult
+	err := impl.dbConnection.Model(&testCases).
+		Where("test_report_summary_id = ?", summaryId).
+		Order("id ASC").
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting test case results", "summaryId", summaryId, "err", err)
+		return nil, err
+	}
+	return testCases, nil
+}
+
+func (impl *TestReportRepositoryImpl) FindFlakyTestsByCiPipelineId(ciPipelineId int, since time.Time) ([]*FlakyTest, error) {
+	return impl.findFlakyTests("ci_pipeline_id", ciPipelineId, since)
+}
+
+func (impl *TestReportRepositoryImpl) FindFlakyTestsByCdPipelineId(cdPipelineId int, since time.Time) ([]*FlakyTest, error) {
+	return impl.findFlakyTests("cd_pipeline_id", cdPipelineId, since)
+}
+
+// findFlakyTests returns the tests of the pipeline having both passed and failed runs for the same source revision.
+// pipelineColumn is one of the constant column names above and never user input.
+func (impl *TestReportRepositoryImpl) findFlakyTests(pipelineColumn string, pipelineId int, since time.Time) ([]*FlakyTest, error) {
+	var flakyTests []*FlakyTest
+	query := `WITH per_revision AS (
+				SELECT suite_name, class_name, test_name, source_revision,
+					COUNT(*) FILTER (WHERE status = 'PASSED') AS pass_count,
+					COUNT(*) FILTER (WHERE status IN ('FAILED', 'ERRORED')) AS fail_count,
+					MAX(created_on) FILTER (WHERE status IN ('FAILED', 'ERRORED')) AS last_failed_on
+				FROM test_case_result
+				WHERE ` + pipelineColumn + ` = ? AND created_on >= ? AND COALESCE(source_revision, '') <> ''
+				GROUP BY suite_name, class_name, test_name, source_revision
+			)
+			SELECT suite_name, class_name, test_name, COUNT(*) AS flaky_revisions,
+				SUM(pass_count) AS pass_count, SUM(fail_count) AS fail_count, MAX(last_failed_on) AS last_failed_on
+			FROM per_revision
+			WHERE pass_count > 0 AND fail_count > 0
+			GROUP BY suite_name, class_name, test_name
+			ORDER BY flaky_revisions DESC, last_failed_on DESC;`
+	_, err := impl.dbConnection.Query(&flakyTests, query, pipelineId, since)
+	if err != nil {
+		impl.logger.Errorw("error in getting flaky tests", "pipelineColumn", pipelineColumn, "pipelineId", pipelineId, "err", err)
+		return nil, err
+	}
+	return flakyTests, nil
+}
@@ -47,6 +47,10 @@ func ConvertCdWorkflowRunnerDbObjToDto(dbObj *pipelineConfig.CdWorkflowRunner) *
 		RefCdWorkflowRunnerId:   dbObj.RefCdWorkflowRunnerId,
 		ImagePathReservationIds: dbObj.ImagePathReservationIds,
 		ReferenceId:             &newReferenceId,
+		PullRequestUrl:          dbObj.PullRequestUrl,
+		PullRequestNumber:       dbObj.PullRequestNumber,
+		PullRequestState:        dbObj.PullRequestState,
+		PullRequestBranch:       dbObj.PullRequestBranch,
 	}
 }
 
@@ -83,6 +87,10 @@ func ConvertCdWorkflowRunnerDtoToDbObj(dto *bean.CdWorkflowRunnerDto) *pipelineC
 		RefCdWorkflowRunnerId:   dto.RefCdWorkflowRunnerId,
 		ImagePathReservationIds: dto.ImagePathReservationIds,
 		ReferenceId:             dto.ReferenceId,
+		PullRequestUrl:          dto.PullRequestUrl,
+		PullRequestNumber:       dto.PullRequestNumber,
+		PullRequestState:        dto.PullRequestState,
+		PullRequestBranch:       dto.PullRequestBranch,
 		AuditLog: sql.AuditLog{
 			CreatedOn: dto.StartedOn,
 			CreatedBy: dto.TriggeredBy,
@@ -51,4 +51,8 @@ type CdWorkflowRunnerDto struct {
 	ImagePathReservationIds []int                           `json:"imagePathReservationIds"`
 	ReferenceId             *string                         `json:"referenceId"`
 	IsArtifactUploaded      bool                            `json:"isArtifactUploaded"`
+	PullRequestUrl          string                          `json:"pullRequestUrl,omitempty"`
+	PullRequestNumber       int                             `json:"pullRequestNumber,omitempty"`
+	PullRequestState        string                          `json:"pullRequestState,omitempty"`
+	PullRequestBranch       string                          `json:"pullRequestBranch,omitempty"`
 }
@@ -59,6 +59,7 @@ import (
 	constants2 "github.com/devtron-labs/devtron/pkg/pipeline/constants"
 	repository2 "github.com/devtron-labs/devtron/pkg/plugin/repository"
 	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning"
 	repository3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/pkg/workflow/cd"
@@ -159,6 +160,7 @@ type WorkflowDagExecutorImpl struct {
 	ciHandlerService            trigger.HandlerService
 	workflowTriggerAuditService auditService.WorkflowTriggerAuditService
 	fluxApplicationService fluxApplication.FluxApplicationService
+	imageSigningService    imageSigning.ImageSigningService
 }
 
 func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pipelineConfig.PipelineRepository,
@@ -195,6 +197,7 @@ func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pi
 	ciHandlerService trigger.HandlerService,
 	workflowTriggerAuditService auditService.WorkflowTriggerAuditService,
 	fluxApplicationService fluxApplication.FluxApplicationService,
+	imageSigningService imageSigning.ImageSigningService,
 ) *WorkflowDagExecutorImpl {
 	wde := &WorkflowDagExecutorImpl{logger: Logger,
 		pipelineRepository:            pipelineRepository,
@@ -231,6 +234,7 @@ func NewWorkflowDagExecutorImpl(Logger *zap.SugaredLogger, pipelineRepository pi
 		ciHandlerService:              ciHandlerService,
 		workflowTriggerAuditService:   workflowTriggerAuditService,
 		fluxApplicationService:        fluxApplicationService,
+		imageSigningService:           imageSigningService,
 	}
 	config, err := types.GetCdConfig()
 	if err != nil {
@@ -1005,6 +1009,10 @@ func (impl *WorkflowDagExecutorImpl) HandleCiSuccessEvent(triggerContext trigger
 		impl.logger.Errorw("error in saving material", "err", err)
 		return 0, err
 	}
+	// signing failures are not propagated, deployments are gated by the signature policies of the target environment
+	if err = impl.imageSigningService.SignArtifact(buildArtifact, request.KeylessSignatures, request.UserId); err != nil {
+		impl.logger.Errorw("error in signing artifact", "artifactId", buildArtifact.Id, "err", err)
+	}
 
 	var pluginArtifacts []*repository.CiArtifact
 	for registry, artifacts := range request.PluginRegistryArtifactDetails {
@@ -20,22 +20,24 @@ import (
 	"encoding/json"
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	bean3 "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	imageSigningBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageSigning/bean"
 )
 
 type CiArtifactWebhookRequest struct {
-	Image                         string                         `json:"image" validate:"required"`
-	ImageDigest                   string                         `json:"imageDigest"`
-	MaterialInfo                  json.RawMessage                `json:"materialInfo"`
-	DataSource                    repository.ArtifactsSourceType `json:"dataSource" validate:"oneof=CI-RUNNER EXTERNAL pre_cd post_cd post_ci GOCD"`
-	PipelineName                  string                         `json:"pipelineName"`
-	WorkflowId                    *int                           `json:"workflowId"`
-	UserId                        int32                          `json:"userId"`
-	IsArtifactUploaded            bool                           `json:"isArtifactUploaded"`
-	FailureReason                 string                         `json:"failureReason"`                 // FailureReason is used for notifying the failure reason to the user. Should be short and user-friendly
-	PluginRegistryArtifactDetails map[string][]string            `json:"PluginRegistryArtifactDetails"` //map of registry and array of images generated by Copy container image plugin
-	PluginArtifactStage           string                         `json:"pluginArtifactStage"`           // at which stage of CI artifact was generated by plugin ("pre_ci/post_ci")
-	IsScanEnabled                 bool                           `json:"isScanEnabled"`
-	TargetPlatforms               []string                       `json:"targetPlatforms"`
+	Image                         string                                       `json:"image" validate:"required"`
+	ImageDigest                   string                                       `json:"imageDigest"`
+	MaterialInfo                  json.RawMessage                              `json:"materialInfo"`
+	DataSource                    repository.ArtifactsSourceType               `json:"dataSource" validate:"oneof=CI-RUNNER EXTERNAL pre_cd post_cd post_ci GOCD"`
+	PipelineName                  string                                       `json:"pipelineName"`
+	WorkflowId                    *int                                         `json:"workflowId"`
+	UserId                        int32                                        `json:"userId"`
+	IsArtifactUploaded            bool                                         `json:"isArtifactUploaded"`
+	FailureReason                 string                                       `json:"failureReason"`                 // FailureReason is used for notifying the failure reason to the user. Should be short and user-friendly
+	PluginRegistryArtifactDetails map[string][]string                          `json:"PluginRegistryArtifactDetails"` //map of registry and array of images generated by Copy container image plugin
+	PluginArtifactStage           string                                       `json:"pluginArtifactStage"`           // at which stage of CI artifact was generated by plugin ("pre_ci/post_ci")
+	IsScanEnabled                 bool                                         `json:"isScanEnabled"`
+	TargetPlatforms               []string                                     `json:"targetPlatforms"`
+	KeylessSignatures             []*imageSigningBean.KeylessSignatureMetadata `json:"keylessSignatures"`
 }
 
 const (
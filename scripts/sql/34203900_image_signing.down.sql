@@ -0,0 +1,17 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."image_signature_verification";
+DROP SEQUENCE IF EXISTS id_seq_image_signature_verification;
+
+DROP TABLE IF EXISTS "public"."image_signature_policy";
+DROP SEQUENCE IF EXISTS id_seq_image_signature_policy;
+
+DROP TABLE IF EXISTS "public"."image_signature";
+DROP SEQUENCE IF EXISTS id_seq_image_signature;
+
+DROP TABLE IF EXISTS "public"."image_signing_key";
+DROP SEQUENCE IF EXISTS id_seq_image_signing_key;
+
+UPDATE resource_qualifier_mapping SET active = false WHERE resource_type = 6;
+
+COMMIT;
@@ -0,0 +1,100 @@
+BEGIN;
+
+-- key pairs used by devtron to sign built images, the private key is kept in a kubernetes secret in the devtron namespace
+-- and only the name of that secret is stored here
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_signing_key;
+
+CREATE TABLE IF NOT EXISTS "public"."image_signing_key"
+(
+    "id"                      int4         NOT NULL DEFAULT nextval('id_seq_image_signing_key'::regclass),
+    "name"                    varchar(250) NOT NULL,
+    "public_key"              text         NOT NULL,
+    "private_key_secret_name" varchar(250),
+    "is_default"              bool         NOT NULL DEFAULT false,
+    "active"                  bool         NOT NULL DEFAULT true,
+    "created_on"              timestamptz  NOT NULL,
+    "created_by"              int4         NOT NULL,
+    "updated_on"              timestamptz  NOT NULL,
+    "updated_by"              int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS image_signing_key_name_active_uq ON image_signing_key (name) WHERE active = true;
+
+-- signatures and attestations recorded against a ci artifact, either signed by devtron with the default signing key (KEY)
+-- or reported by ci-runner with a fulcio certificate (KEYLESS), keyless entries are recorded only once the certificate
+-- chain and the signature are verified and the identity is taken from the certificate
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_signature;
+
+CREATE TABLE IF NOT EXISTS "public"."image_signature"
+(
+    "id"                     int4         NOT NULL DEFAULT nextval('id_seq_image_signature'::regclass),
+    "ci_artifact_id"         int4         NOT NULL,
+    "image"                  text         NOT NULL,
+    "image_digest"           varchar(250) NOT NULL,
+    "signature_type"         varchar(50)  NOT NULL, -- SIGNATURE, ATTESTATION
+    "signing_mode"           varchar(50)  NOT NULL, -- KEY, KEYLESS
+    "signing_key_id"         int4,
+    "predicate_type"         varchar(250),
+    "payload"                text         NOT NULL,
+    "signature"              text         NOT NULL,
+    "certificate"            text, -- PEM leaf certificate followed by its chain, KEYLESS only
+    "certificate_identity"   varchar(500),
+    "certificate_issuer"     varchar(500),
+    "transparency_log_index" varchar(100),
+    "created_on"             timestamptz  NOT NULL,
+    "created_by"             int4         NOT NULL,
+    "updated_on"             timestamptz  NOT NULL,
+    "updated_by"             int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT image_signature_ci_artifact_id_fkey FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id"),
+    CONSTRAINT image_signature_signing_key_id_fkey FOREIGN KEY ("signing_key_id") REFERENCES "public"."image_signing_key" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS image_signature_image_digest_idx ON image_signature (image_digest);
+
+-- environments and clusters are attached to a policy through resource_qualifier_mapping (resource_type = 6)
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_signature_policy;
+
+CREATE TABLE IF NOT EXISTS "public"."image_signature_policy"
+(
+    "id"                    int4         NOT NULL DEFAULT nextval('id_seq_image_signature_policy'::regclass),
+    "name"                  varchar(250) NOT NULL,
+    "description"           text,
+    "trusted_key_ids"       int4[],
+    "trusted_identities"    text, -- json array of {issuer, subject}
+    "required_attestations" text, -- json array of predicate types
+    "enforce"               bool         NOT NULL DEFAULT true,
+    "active"                bool         NOT NULL DEFAULT true,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_signature_verification;
+
+CREATE TABLE IF NOT EXISTS "public"."image_signature_verification"
+(
+    "id"                int4         NOT NULL DEFAULT nextval('id_seq_image_signature_verification'::regclass),
+    "ci_artifact_id"    int4         NOT NULL,
+    "cd_pipeline_id"    int4         NOT NULL,
+    "env_id"            int4         NOT NULL,
+    "policy_id"         int4         NOT NULL,
+    "status"            varchar(50)  NOT NULL, -- PASSED, FAILED
+    "message"           text,
+    "signature_id"      int4,
+    "verified_on"       timestamptz  NOT NULL,
+    "created_on"        timestamptz  NOT NULL,
+    "created_by"        int4         NOT NULL,
+    "updated_on"        timestamptz  NOT NULL,
+    "updated_by"        int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT image_signature_verification_ci_artifact_id_fkey FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id"),
+    CONSTRAINT image_signature_verification_policy_id_fkey FOREIGN KEY ("policy_id") REFERENCES "public"."image_signature_policy" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS image_signature_verification_ci_artifact_id_idx ON image_signature_verification (ci_artifact_id);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."artifact_promotion_approval";
+DROP SEQUENCE IF EXISTS id_seq_artifact_promotion_approval;
+
+DROP TABLE IF EXISTS "public"."artifact_promotion_request";
+DROP SEQUENCE IF EXISTS id_seq_artifact_promotion_request;
+
+DROP TABLE IF EXISTS "public"."artifact_promotion_policy";
+DROP SEQUENCE IF EXISTS id_seq_artifact_promotion_policy;
+
+UPDATE resource_qualifier_mapping SET active = false WHERE resource_type = 4;
+
+COMMIT;
@@ -0,0 +1,69 @@
+BEGIN;
+
+-- promotion policies are mapped on environments/clusters via resource_qualifier_mapping (resource_type = 4)
+CREATE SEQUENCE IF NOT EXISTS id_seq_artifact_promotion_policy;
+
+CREATE TABLE IF NOT EXISTS "public"."artifact_promotion_policy"
+(
+    "id"             int4         NOT NULL DEFAULT nextval('id_seq_artifact_promotion_policy'::regclass),
+    "name"           varchar(250) NOT NULL,
+    "description"    text,
+    "conditions"     text         NOT NULL, -- json array of CEL expressions
+    "approval_count" int4         NOT NULL DEFAULT 0,
+    "active"         bool         NOT NULL DEFAULT true,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     int4         NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS artifact_promotion_policy_name_active_uq ON artifact_promotion_policy (name) WHERE active = true;
+
+-- every promotion attempt of an artifact to a target cd pipeline, promoted requests make the artifact deployable on the target
+CREATE SEQUENCE IF NOT EXISTS id_seq_artifact_promotion_request;
+
+CREATE TABLE IF NOT EXISTS "public"."artifact_promotion_request"
+(
+    "id"                 int4        NOT NULL DEFAULT nextval('id_seq_artifact_promotion_request'::regclass),
+    "ci_artifact_id"     int4        NOT NULL,
+    "source_pipeline_id" int4        NOT NULL,
+    "target_pipeline_id" int4        NOT NULL,
+    "status"             varchar(50) NOT NULL, -- AWAITING_APPROVAL, PROMOTED, POLICY_FAILED, CANCELLED
+    "policy_results"     text,
+    "required_approvals" int4        NOT NULL DEFAULT 0,
+    "comment"            text,
+    "promoted_on"        timestamptz,
+    "created_on"         timestamptz NOT NULL,
+    "created_by"         int4        NOT NULL,
+    "updated_on"         timestamptz NOT NULL,
+    "updated_by"         int4        NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT artifact_promotion_request_ci_artifact_id_fkey FOREIGN KEY ("ci_artifact_id") REFERENCES "public"."ci_artifact" ("id"),
+    CONSTRAINT artifact_promotion_request_source_pipeline_id_fkey FOREIGN KEY ("source_pipeline_id") REFERENCES "public"."pipeline" ("id"),
+    CONSTRAINT artifact_promotion_request_target_pipeline_id_fkey FOREIGN KEY ("target_pipeline_id") REFERENCES "public"."pipeline" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS artifact_promotion_request_target_status_idx ON artifact_promotion_request (target_pipeline_id, status);
+CREATE INDEX IF NOT EXISTS artifact_promotion_request_artifact_idx ON artifact_promotion_request (ci_artifact_id);
+-- an artifact has at most one request in flight or promoted per target, concurrent promote calls fail on this index
+CREATE UNIQUE INDEX IF NOT EXISTS artifact_promotion_request_artifact_target_uq ON artifact_promotion_request (ci_artifact_id, target_pipeline_id) WHERE status IN ('AWAITING_APPROVAL', 'PROMOTED');
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_artifact_promotion_approval;
+
+CREATE TABLE IF NOT EXISTS "public"."artifact_promotion_approval"
+(
+    "id"                   int4        NOT NULL DEFAULT nextval('id_seq_artifact_promotion_approval'::regclass),
+    "promotion_request_id" int4        NOT NULL,
+    "user_id"              int4        NOT NULL,
+    "created_on"           timestamptz NOT NULL,
+    "created_by"           int4        NOT NULL,
+    "updated_on"           timestamptz NOT NULL,
+    "updated_by"           int4        NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT artifact_promotion_approval_request_id_fkey FOREIGN KEY ("promotion_request_id") REFERENCES "public"."artifact_promotion_request" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS artifact_promotion_approval_request_user_uq ON artifact_promotion_approval (promotion_request_id, user_id);
+
+COMMIT;
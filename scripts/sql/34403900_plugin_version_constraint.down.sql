@@ -0,0 +1 @@
+ALTER TABLE pipeline_stage_step DROP COLUMN IF EXISTS plugin_version_constraint;
@@ -0,0 +1,2 @@
+-- semver constraint (e.g. ^1.2) resolved against plugin versions of the same parent at trigger time
+ALTER TABLE pipeline_stage_step ADD COLUMN IF NOT EXISTS plugin_version_constraint VARCHAR(100);
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."plugin_catalog_source_version";
+DROP SEQUENCE IF EXISTS id_seq_plugin_catalog_source_version;
+
+DROP TABLE IF EXISTS "public"."plugin_catalog_source";
+DROP SEQUENCE IF EXISTS id_seq_plugin_catalog_source;
+
+COMMIT;
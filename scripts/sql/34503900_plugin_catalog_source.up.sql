@@ -0,0 +1,56 @@
+BEGIN;
+
+-- git repositories / oci artifacts holding plugin manifests, synced periodically into global plugins
+CREATE SEQUENCE IF NOT EXISTS id_seq_plugin_catalog_source;
+
+CREATE TABLE IF NOT EXISTS "public"."plugin_catalog_source"
+(
+    "id"               int4         NOT NULL DEFAULT nextval('id_seq_plugin_catalog_source'::regclass),
+    "name"             varchar(250) NOT NULL,
+    "source_type"      varchar(20)  NOT NULL, -- GIT, OCI
+    "git_provider_id"  int4,
+    "git_repo_url"     text,
+    "git_branch"       varchar(250),
+    "oci_registry_id"  varchar(250),
+    "oci_repository"   text,
+    "oci_tag"          varchar(250),
+    "manifest_path"    text,
+    "active"           bool         NOT NULL DEFAULT true,
+    "last_synced_on"   timestamptz,
+    "last_sync_status" varchar(50), -- SUCCEEDED, PARTIALLY_SUCCEEDED, FAILED
+    "last_sync_result" text,        -- json of per plugin sync results
+    "created_on"       timestamptz  NOT NULL,
+    "created_by"       int4         NOT NULL,
+    "updated_on"       timestamptz  NOT NULL,
+    "updated_by"       int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "plugin_catalog_source_git_provider_id_fkey" FOREIGN KEY ("git_provider_id") REFERENCES "public"."git_provider" ("id"),
+    CONSTRAINT "plugin_catalog_source_oci_registry_id_fkey" FOREIGN KEY ("oci_registry_id") REFERENCES "public"."docker_artifact_store" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS plugin_catalog_source_name_active_uq ON plugin_catalog_source (name) WHERE active = true;
+
+-- plugin versions created by a catalog source, used to detect changed manifests and versions removed from the source
+CREATE SEQUENCE IF NOT EXISTS id_seq_plugin_catalog_source_version;
+
+CREATE TABLE IF NOT EXISTS "public"."plugin_catalog_source_version"
+(
+    "id"                       int4         NOT NULL DEFAULT nextval('id_seq_plugin_catalog_source_version'::regclass),
+    "plugin_catalog_source_id" int4         NOT NULL,
+    "plugin_version_id"        int4         NOT NULL,
+    "plugin_identifier"        varchar(100) NOT NULL,
+    "plugin_version"           varchar(50)  NOT NULL,
+    "manifest_digest"          varchar(100) NOT NULL,
+    "active"                   bool         NOT NULL DEFAULT true,
+    "created_on"               timestamptz  NOT NULL,
+    "created_by"               int4         NOT NULL,
+    "updated_on"               timestamptz  NOT NULL,
+    "updated_by"               int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "plugin_catalog_source_version_source_id_fkey" FOREIGN KEY ("plugin_catalog_source_id") REFERENCES "public"."plugin_catalog_source" ("id"),
+    CONSTRAINT "plugin_catalog_source_version_plugin_version_id_fkey" FOREIGN KEY ("plugin_version_id") REFERENCES "public"."plugin_metadata" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS plugin_catalog_source_version_plugin_version_active_uq ON plugin_catalog_source_version (plugin_version_id) WHERE active = true;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."test_case_result";
+DROP SEQUENCE IF EXISTS id_seq_test_case_result;
+
+DROP TABLE IF EXISTS "public"."test_report_summary";
+DROP SEQUENCE IF EXISTS id_seq_test_report_summary;
+
+ALTER TABLE "public"."pipeline_stage_step" DROP COLUMN IF EXISTS "coverage_report_format";
+ALTER TABLE "public"."pipeline_stage_step" DROP COLUMN IF EXISTS "coverage_report_paths";
+ALTER TABLE "public"."pipeline_stage_step" DROP COLUMN IF EXISTS "test_report_paths";
+
+COMMIT;
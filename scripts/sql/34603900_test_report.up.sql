@@ -0,0 +1,68 @@
+BEGIN;
+
+-- report paths declared on a stage step, uploaded with the step artifacts and parsed once the workflow completes
+ALTER TABLE "public"."pipeline_stage_step" ADD COLUMN IF NOT EXISTS "test_report_paths" text[];
+ALTER TABLE "public"."pipeline_stage_step" ADD COLUMN IF NOT EXISTS "coverage_report_paths" text[];
+ALTER TABLE "public"."pipeline_stage_step" ADD COLUMN IF NOT EXISTS "coverage_report_format" varchar(20); -- COBERTURA, LCOV
+
+-- one summary per ci workflow or pre/post cd workflow runner
+CREATE SEQUENCE IF NOT EXISTS id_seq_test_report_summary;
+
+CREATE TABLE IF NOT EXISTS "public"."test_report_summary"
+(
+    "id"                    int4         NOT NULL DEFAULT nextval('id_seq_test_report_summary'::regclass),
+    "ci_workflow_id"        int4,
+    "cd_workflow_runner_id" int4,
+    "workflow_type"         varchar(20)  NOT NULL, -- CI, PRE, POST
+    "ci_pipeline_id"        int4,
+    "cd_pipeline_id"        int4,
+    "source_revision"       varchar(500),
+    "total"                 int4         NOT NULL DEFAULT 0,
+    "passed"                int4         NOT NULL DEFAULT 0,
+    "failed"                int4         NOT NULL DEFAULT 0,
+    "errored"               int4         NOT NULL DEFAULT 0,
+    "skipped"               int4         NOT NULL DEFAULT 0,
+    "duration_ms"           int8         NOT NULL DEFAULT 0,
+    "lines_covered"         int4,
+    "lines_valid"           int4,
+    "status"                varchar(20)  NOT NULL, -- SUCCEEDED, PARTIALLY_SUCCEEDED, FAILED
+    "error"                 text,
+    "test_cases_truncated"  bool         NOT NULL DEFAULT false, -- passed and skipped test cases beyond TEST_REPORT_MAX_TEST_CASES were not stored
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "test_report_summary_ci_workflow_id_fkey" FOREIGN KEY ("ci_workflow_id") REFERENCES "public"."ci_workflow" ("id"),
+    CONSTRAINT "test_report_summary_cd_workflow_runner_id_fkey" FOREIGN KEY ("cd_workflow_runner_id") REFERENCES "public"."cd_workflow_runner" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS test_report_summary_ci_workflow_id_uq ON test_report_summary (ci_workflow_id) WHERE ci_workflow_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS test_report_summary_cd_workflow_runner_id_uq ON test_report_summary (cd_workflow_runner_id) WHERE cd_workflow_runner_id IS NOT NULL;
+
+-- individual test case results, pipeline and revision are denormalised for flaky test lookups
+CREATE SEQUENCE IF NOT EXISTS id_seq_test_case_result;
+
+CREATE TABLE IF NOT EXISTS "public"."test_case_result"
+(
+    "id"                     int4         NOT NULL DEFAULT nextval('id_seq_test_case_result'::regclass),
+    "test_report_summary_id" int4         NOT NULL,
+    "ci_pipeline_id"         int4,
+    "cd_pipeline_id"         int4,
+    "source_revision"        varchar(500),
+    "suite_name"             varchar(500),
+    "class_name"             varchar(500),
+    "test_name"              varchar(500) NOT NULL,
+    "status"                 varchar(20)  NOT NULL, -- PASSED, FAILED, ERRORED, SKIPPED
+    "duration_ms"            int8         NOT NULL DEFAULT 0,
+    "failure_message"        text,
+    "created_on"             timestamptz  NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "test_case_result_test_report_summary_id_fkey" FOREIGN KEY ("test_report_summary_id") REFERENCES "public"."test_report_summary" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS test_case_result_summary_id_idx ON test_case_result (test_report_summary_id);
+CREATE INDEX IF NOT EXISTS test_case_result_ci_pipeline_id_created_on_idx ON test_case_result (ci_pipeline_id, created_on) WHERE ci_pipeline_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS test_case_result_cd_pipeline_id_created_on_idx ON test_case_result (cd_pipeline_id, created_on) WHERE cd_pipeline_id IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE "public"."gitops_config" DROP COLUMN IF EXISTS "dry_run_repo_name";
+ALTER TABLE "public"."gitops_config" DROP COLUMN IF EXISTS "repo_hook_url";
+ALTER TABLE "public"."gitops_config" DROP COLUMN IF EXISTS "gitea_org_name";
+ALTER TABLE "public"."gitops_config" DROP COLUMN IF EXISTS "ssh_known_hosts";
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- ssh_key was added in 260_gitops_ssh and is now used by the GIT_SSH provider as the deploy key
+ALTER TABLE "public"."gitops_config" ADD COLUMN IF NOT EXISTS "ssh_key" text;
+ALTER TABLE "public"."gitops_config" ADD COLUMN IF NOT EXISTS "ssh_known_hosts" text;
+-- GITEA, organisation owning the created repositories
+ALTER TABLE "public"."gitops_config" ADD COLUMN IF NOT EXISTS "gitea_org_name" varchar(250);
+-- GIT_SSH, optional hook creating/deleting repositories and the pre-created repository used for validation
+ALTER TABLE "public"."gitops_config" ADD COLUMN IF NOT EXISTS "repo_hook_url" varchar(500);
+ALTER TABLE "public"."gitops_config" ADD COLUMN IF NOT EXISTS "dry_run_repo_name" varchar(250);
+
+COMMIT;
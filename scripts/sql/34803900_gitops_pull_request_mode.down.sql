@@ -0,0 +1,13 @@
+BEGIN;
+
+DROP INDEX IF EXISTS cd_workflow_runner_awaiting_merge_idx;
+
+ALTER TABLE "public"."cd_workflow_runner" DROP COLUMN IF EXISTS "pull_request_url";
+ALTER TABLE "public"."cd_workflow_runner" DROP COLUMN IF EXISTS "pull_request_number";
+ALTER TABLE "public"."cd_workflow_runner" DROP COLUMN IF EXISTS "pull_request_state";
+ALTER TABLE "public"."cd_workflow_runner" DROP COLUMN IF EXISTS "pull_request_branch";
+
+DROP TABLE IF EXISTS "public"."gitops_pr_mode_config";
+DROP SEQUENCE IF EXISTS id_seq_gitops_pr_mode_config;
+
+COMMIT;
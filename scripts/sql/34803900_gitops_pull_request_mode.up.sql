@@ -0,0 +1,29 @@
+BEGIN;
+
+-- environments for which gitops commits are delivered through a pull request instead of a direct push
+CREATE SEQUENCE IF NOT EXISTS id_seq_gitops_pr_mode_config;
+
+CREATE TABLE IF NOT EXISTS "public"."gitops_pr_mode_config"
+(
+    "id"             int4        NOT NULL DEFAULT nextval('id_seq_gitops_pr_mode_config'::regclass),
+    "environment_id" int4        NOT NULL,
+    "enabled"        bool        NOT NULL DEFAULT false,
+    "created_on"     timestamptz NOT NULL,
+    "created_by"     int4        NOT NULL,
+    "updated_on"     timestamptz NOT NULL,
+    "updated_by"     int4        NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "gitops_pr_mode_config_environment_id_fkey" FOREIGN KEY ("environment_id") REFERENCES "public"."environment" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS gitops_pr_mode_config_environment_id_uq ON gitops_pr_mode_config (environment_id);
+
+-- pull request opened for the deployment, the runner waits in AwaitingMerge till it is merged or closed
+ALTER TABLE "public"."cd_workflow_runner" ADD COLUMN IF NOT EXISTS "pull_request_url" text;
+ALTER TABLE "public"."cd_workflow_runner" ADD COLUMN IF NOT EXISTS "pull_request_number" int4;
+ALTER TABLE "public"."cd_workflow_runner" ADD COLUMN IF NOT EXISTS "pull_request_state" varchar(20); -- OPEN, MERGED, CLOSED
+ALTER TABLE "public"."cd_workflow_runner" ADD COLUMN IF NOT EXISTS "pull_request_branch" varchar(250);
+
+CREATE INDEX IF NOT EXISTS cd_workflow_runner_awaiting_merge_idx ON cd_workflow_runner (status) WHERE status = 'AwaitingMerge';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE "public"."gitops_config" DROP COLUMN IF EXISTS "monorepo_path_template";
+ALTER TABLE "public"."gitops_config" DROP COLUMN IF EXISTS "monorepo_name";
+ALTER TABLE "public"."gitops_config" DROP COLUMN IF EXISTS "repository_layout";
+
+COMMIT;
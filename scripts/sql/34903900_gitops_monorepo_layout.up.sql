@@ -0,0 +1,9 @@
+BEGIN;
+
+-- PER_APP creates a repository per devtron app, MONOREPO keeps the charts of all apps in monorepo_name
+-- under monorepo_path_template ({team}, {app} and {env} placeholders)
+ALTER TABLE "public"."gitops_config" ADD COLUMN IF NOT EXISTS "repository_layout" varchar(50) NOT NULL DEFAULT 'PER_APP';
+ALTER TABLE "public"."gitops_config" ADD COLUMN IF NOT EXISTS "monorepo_name" varchar(250);
+ALTER TABLE "public"."gitops_config" ADD COLUMN IF NOT EXISTS "monorepo_path_template" varchar(250);
+
+COMMIT;
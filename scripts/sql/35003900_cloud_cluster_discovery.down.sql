@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."cluster_cloud_source";
+DROP SEQUENCE IF EXISTS id_seq_cluster_cloud_source;
+DROP TABLE IF EXISTS "public"."cloud_credential";
+DROP SEQUENCE IF EXISTS id_seq_cloud_credential;
+
+COMMIT;
@@ -0,0 +1,49 @@
+BEGIN;
+
+-- cloud credentials used to discover EKS, GKE and AKS clusters, config holds the provider specific credential json
+CREATE SEQUENCE IF NOT EXISTS id_seq_cloud_credential;
+
+CREATE TABLE IF NOT EXISTS "public"."cloud_credential"
+(
+    "id"         int4         NOT NULL DEFAULT nextval('id_seq_cloud_credential'::regclass),
+    "name"       varchar(100) NOT NULL,
+    "provider"   varchar(20)  NOT NULL, -- EKS, GKE, AKS
+    "config"     text         NOT NULL,
+    "active"     bool         NOT NULL DEFAULT true,
+    "created_on" timestamptz  NOT NULL,
+    "created_by" int4         NOT NULL,
+    "updated_on" timestamptz  NOT NULL,
+    "updated_by" int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS cloud_credential_name_uq ON cloud_credential (name) WHERE active = true;
+
+-- clusters imported from a cloud credential, the bearer token of the service account created in the cluster
+-- is re-issued through the cloud credential before token_expires_at
+CREATE SEQUENCE IF NOT EXISTS id_seq_cluster_cloud_source;
+
+CREATE TABLE IF NOT EXISTS "public"."cluster_cloud_source"
+(
+    "id"                        int4         NOT NULL DEFAULT nextval('id_seq_cluster_cloud_source'::regclass),
+    "cluster_id"                int4         NOT NULL,
+    "cloud_credential_id"       int4         NOT NULL,
+    "provider_cluster_id"       varchar(500) NOT NULL,
+    "service_account_namespace" varchar(250) NOT NULL,
+    "service_account_name"      varchar(250) NOT NULL,
+    "token_expires_at"          timestamptz,
+    "last_refreshed_on"         timestamptz,
+    "refresh_error"             text,
+    "active"                    bool         NOT NULL DEFAULT true,
+    "created_on"                timestamptz  NOT NULL,
+    "created_by"                int4         NOT NULL,
+    "updated_on"                timestamptz  NOT NULL,
+    "updated_by"                int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "cluster_cloud_source_cluster_id_fkey" FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id"),
+    CONSTRAINT "cluster_cloud_source_cloud_credential_id_fkey" FOREIGN KEY ("cloud_credential_id") REFERENCES "public"."cloud_credential" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS cluster_cloud_source_cluster_id_uq ON cluster_cloud_source (cluster_id) WHERE active = true;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DELETE FROM "public"."notification_templates" WHERE event_type_id = 10;
+DELETE FROM "public"."event" WHERE id = 10;
+DROP TABLE IF EXISTS "public"."cluster_credential_rotation_audit";
+DROP SEQUENCE IF EXISTS id_seq_cluster_credential_rotation_audit;
+DROP TABLE IF EXISTS "public"."cluster_credential_expiry";
+DROP SEQUENCE IF EXISTS id_seq_cluster_credential_expiry;
+
+COMMIT;
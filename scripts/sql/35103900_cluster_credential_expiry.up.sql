@@ -0,0 +1,85 @@
+BEGIN;
+
+-- expiry of the bearer token, client certificate and ca certificate stored in the cluster config, parsed where possible
+CREATE SEQUENCE IF NOT EXISTS id_seq_cluster_credential_expiry;
+
+CREATE TABLE IF NOT EXISTS "public"."cluster_credential_expiry"
+(
+    "id"                     int4        NOT NULL DEFAULT nextval('id_seq_cluster_credential_expiry'::regclass),
+    "cluster_id"             int4        NOT NULL,
+    "token_expires_at"       timestamptz,
+    "client_cert_expires_at" timestamptz,
+    "ca_cert_expires_at"     timestamptz,
+    "parse_error"            text,
+    "last_checked_on"        timestamptz,
+    "last_notified_on"       timestamptz,
+    "active"                 bool        NOT NULL DEFAULT true,
+    "created_on"             timestamptz NOT NULL,
+    "created_by"             int4        NOT NULL,
+    "updated_on"             timestamptz NOT NULL,
+    "updated_by"             int4        NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT cluster_credential_expiry_cluster_id_fkey FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS cluster_credential_expiry_cluster_id_uq ON cluster_credential_expiry (cluster_id) WHERE active = true;
+
+-- every service account token rotation attempt, successful or not
+CREATE SEQUENCE IF NOT EXISTS id_seq_cluster_credential_rotation_audit;
+
+CREATE TABLE IF NOT EXISTS "public"."cluster_credential_rotation_audit"
+(
+    "id"                        int4        NOT NULL DEFAULT nextval('id_seq_cluster_credential_rotation_audit'::regclass),
+    "cluster_id"                int4        NOT NULL,
+    "service_account_namespace" varchar(250),
+    "service_account_name"      varchar(250),
+    "status"                    varchar(20) NOT NULL, -- Succeeded, Failed, RolledBack
+    "message"                   text,
+    "previous_expires_at"       timestamptz,
+    "new_expires_at"            timestamptz,
+    "created_on"                timestamptz NOT NULL,
+    "created_by"                int4        NOT NULL,
+    "updated_on"                timestamptz NOT NULL,
+    "updated_by"                int4        NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT cluster_credential_rotation_audit_cluster_id_fkey FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS cluster_credential_rotation_audit_cluster_id_idx ON cluster_credential_rotation_audit (cluster_id);
+
+INSERT INTO public.event (id, event_type, description) VALUES (10, 'CLUSTER CREDENTIAL EXPIRY', '');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('slack', 'CLUSTER', 10, 'cluster credential expiry slack template', '{
+    "text": ":warning: Credentials of cluster {{clusterName}} expire on {{credentialExpiresAt}}",
+    "blocks": [
+        {
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":warning: *Cluster credentials expiring*\n{{credentialType}} of cluster *{{clusterName}}* expires on {{credentialExpiresAt}}. Rotate the credentials to avoid losing connectivity."
+            }
+        }
+    ]
+}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('ses', 'CLUSTER', 10, 'cluster credential expiry ses template', '{
+    "from": "{{fromEmail}}",
+    "to": "{{toEmail}}",
+    "subject": "Credentials of cluster {{clusterName}} expire on {{credentialExpiresAt}}",
+    "html": "<p>{{credentialType}} of cluster <b>{{clusterName}}</b> expires on {{credentialExpiresAt}}.</p><p>Rotate the credentials to avoid losing connectivity.</p>"
+}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('smtp', 'CLUSTER', 10, 'cluster credential expiry smtp template', '{
+    "from": "{{fromEmail}}",
+    "to": "{{toEmail}}",
+    "subject": "Credentials of cluster {{clusterName}} expire on {{credentialExpiresAt}}",
+    "html": "<p>{{credentialType}} of cluster <b>{{clusterName}}</b> expires on {{credentialExpiresAt}}.</p><p>Rotate the credentials to avoid losing connectivity.</p>"
+}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('webhook', 'CLUSTER', 10, 'cluster credential expiry webhook template', '{"text": "{{credentialType}} of cluster {{clusterName}} expires on {{credentialExpiresAt}}"}');
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."resource_browser_view";
+DROP SEQUENCE IF EXISTS id_seq_resource_browser_view;
+
+COMMIT;
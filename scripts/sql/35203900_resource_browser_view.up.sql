@@ -0,0 +1,22 @@
+BEGIN;
+
+-- named cross-cluster resource search queries saved by users in the resource browser, query holds the search json
+CREATE SEQUENCE IF NOT EXISTS id_seq_resource_browser_view;
+
+CREATE TABLE IF NOT EXISTS "public"."resource_browser_view"
+(
+    "id"          int4         NOT NULL DEFAULT nextval('id_seq_resource_browser_view'::regclass),
+    "name"        varchar(100) NOT NULL,
+    "description" text,
+    "query"       text         NOT NULL,
+    "active"      bool         NOT NULL DEFAULT true,
+    "created_on"  timestamptz  NOT NULL,
+    "created_by"  int4         NOT NULL,
+    "updated_on"  timestamptz  NOT NULL,
+    "updated_by"  int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS resource_browser_view_name_created_by_uq ON resource_browser_view (name, created_by) WHERE active = true;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."node_maintenance_node";
+DROP SEQUENCE IF EXISTS id_seq_node_maintenance_node;
+DROP TABLE IF EXISTS "public"."node_maintenance_workflow";
+DROP SEQUENCE IF EXISTS id_seq_node_maintenance_workflow;
+
+COMMIT;
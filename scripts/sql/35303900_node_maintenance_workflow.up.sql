@@ -0,0 +1,51 @@
+BEGIN;
+
+-- rolling drain of a set of nodes of a cluster, progress is persisted so that the workflow survives a restart
+CREATE SEQUENCE IF NOT EXISTS id_seq_node_maintenance_workflow;
+
+CREATE TABLE IF NOT EXISTS "public"."node_maintenance_workflow"
+(
+    "id"           int4        NOT NULL DEFAULT nextval('id_seq_node_maintenance_workflow'::regclass),
+    "cluster_id"   int4        NOT NULL,
+    "selector"     text        NOT NULL, -- json of the node names, label selector and node group the nodes were selected by
+    "options"      text        NOT NULL, -- json of max unavailable, timeouts and drain options
+    "status"       varchar(20) NOT NULL, -- Running, Paused, Aborted, Succeeded, Failed
+    "message"      text,
+    "runner_id"    varchar(50),          -- runner owning the workflow, set on claim and required by its heartbeats and updates
+    "heartbeat_on" timestamptz,          -- refreshed by the orchestrator running the workflow
+    "started_on"   timestamptz,
+    "finished_on"  timestamptz,
+    "created_on"   timestamptz NOT NULL,
+    "created_by"   int4        NOT NULL,
+    "updated_on"   timestamptz NOT NULL,
+    "updated_by"   int4        NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT node_maintenance_workflow_cluster_id_fkey FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id")
+);
+
+-- a cluster can have one workflow in progress at a time
+CREATE UNIQUE INDEX IF NOT EXISTS node_maintenance_workflow_cluster_id_uq ON node_maintenance_workflow (cluster_id) WHERE status IN ('Running', 'Paused');
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_node_maintenance_node;
+
+CREATE TABLE IF NOT EXISTS "public"."node_maintenance_node"
+(
+    "id"          int4         NOT NULL DEFAULT nextval('id_seq_node_maintenance_node'::regclass),
+    "workflow_id" int4         NOT NULL,
+    "node_name"   varchar(250) NOT NULL,
+    "node_group"  varchar(250),
+    "status"      varchar(30)  NOT NULL, -- Pending, Draining, WaitingForReplacement, Succeeded, Failed, Skipped
+    "message"     text,
+    "started_on"  timestamptz,
+    "finished_on" timestamptz,
+    "created_on"  timestamptz  NOT NULL,
+    "created_by"  int4         NOT NULL,
+    "updated_on"  timestamptz  NOT NULL,
+    "updated_by"  int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT node_maintenance_node_workflow_id_fkey FOREIGN KEY ("workflow_id") REFERENCES "public"."node_maintenance_workflow" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS node_maintenance_node_workflow_id_idx ON node_maintenance_node (workflow_id);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."cost_allocation_daily";
+DROP SEQUENCE IF EXISTS id_seq_cost_allocation_daily;
+DROP TABLE IF EXISTS "public"."cost_allocation_sample";
+DROP SEQUENCE IF EXISTS id_seq_cost_allocation_sample;
+DROP TABLE IF EXISTS "public"."cost_price_sheet";
+DROP SEQUENCE IF EXISTS id_seq_cost_price_sheet;
+
+COMMIT;
@@ -0,0 +1,70 @@
+BEGIN;
+
+-- prices the cost of clusters is allocated with, the sheet without cluster is the default of all clusters
+CREATE SEQUENCE IF NOT EXISTS id_seq_cost_price_sheet;
+
+CREATE TABLE IF NOT EXISTS "public"."cost_price_sheet"
+(
+    "id"                    int4          NOT NULL DEFAULT nextval('id_seq_cost_price_sheet'::regclass),
+    "cluster_id"            int4,
+    "currency"              varchar(10)   NOT NULL,
+    "cpu_core_hour_price"   numeric(14,6) NOT NULL,
+    "memory_gib_hour_price" numeric(14,6) NOT NULL,
+    "node_type_prices"      text,         -- json of the prices per node instance type, overriding the prices above
+    "active"                bool          NOT NULL DEFAULT true,
+    "created_on"            timestamptz   NOT NULL,
+    "created_by"            int4          NOT NULL,
+    "updated_on"            timestamptz   NOT NULL,
+    "updated_by"            int4          NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT cost_price_sheet_cluster_id_fkey FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS cost_price_sheet_cluster_id_uq ON cost_price_sheet (COALESCE(cluster_id, 0)) WHERE active = true;
+
+INSERT INTO cost_price_sheet (cluster_id, currency, cpu_core_hour_price, memory_gib_hour_price, node_type_prices, active, created_on, created_by, updated_on, updated_by)
+SELECT NULL, 'USD', 0.031611, 0.004237, '{}', true, now(), 1, now(), 1
+WHERE NOT EXISTS (SELECT 1 FROM cost_price_sheet WHERE cluster_id IS NULL AND active = true);
+
+-- a sample of a cluster per interval, guards against the same interval being allocated twice by two orchestrators
+CREATE SEQUENCE IF NOT EXISTS id_seq_cost_allocation_sample;
+
+CREATE TABLE IF NOT EXISTS "public"."cost_allocation_sample"
+(
+    "id"            int4        NOT NULL DEFAULT nextval('id_seq_cost_allocation_sample'::regclass),
+    "cluster_id"    int4        NOT NULL,
+    "sampled_at"    timestamptz NOT NULL, -- start of the sampled interval
+    "interval_mins" int4        NOT NULL,
+    "created_on"    timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT cost_allocation_sample_cluster_id_fkey FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS cost_allocation_sample_cluster_id_sampled_at_uq ON cost_allocation_sample (cluster_id, sampled_at);
+
+-- daily rollup of the allocated cost per cluster, namespace, app and environment, idle rows hold the unallocated node cost
+CREATE SEQUENCE IF NOT EXISTS id_seq_cost_allocation_daily;
+
+CREATE TABLE IF NOT EXISTS "public"."cost_allocation_daily"
+(
+    "id"               int4          NOT NULL DEFAULT nextval('id_seq_cost_allocation_daily'::regclass),
+    "date"             date          NOT NULL,
+    "cluster_id"       int4          NOT NULL,
+    "namespace"        varchar(250)  NOT NULL DEFAULT '',
+    "app_id"           int4          NOT NULL DEFAULT 0, -- 0 for pods not deployed by devtron
+    "env_id"           int4          NOT NULL DEFAULT 0,
+    "team_id"          int4          NOT NULL DEFAULT 0,
+    "idle"             bool          NOT NULL DEFAULT false,
+    "cpu_core_hours"   numeric(18,6) NOT NULL DEFAULT 0,
+    "memory_gib_hours" numeric(18,6) NOT NULL DEFAULT 0,
+    "cpu_cost"         numeric(18,6) NOT NULL DEFAULT 0,
+    "memory_cost"      numeric(18,6) NOT NULL DEFAULT 0,
+    "created_on"       timestamptz   NOT NULL,
+    "updated_on"       timestamptz   NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT cost_allocation_daily_cluster_id_fkey FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS cost_allocation_daily_uq ON cost_allocation_daily (date, cluster_id, namespace, app_id, env_id, idle);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."environment_namespace_spec";
+DROP SEQUENCE IF EXISTS id_seq_environment_namespace_spec;
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+-- namespace spec owned by an environment, reconciled into the namespace of the environment
+CREATE SEQUENCE IF NOT EXISTS id_seq_environment_namespace_spec;
+
+CREATE TABLE IF NOT EXISTS "public"."environment_namespace_spec"
+(
+    "id"                 int4         NOT NULL DEFAULT nextval('id_seq_environment_namespace_spec'::regclass),
+    "environment_id"     int4         NOT NULL,
+    "spec"               text         NOT NULL, -- json of the labels, annotations, quota, limit range, network policies and role bindings
+    "active"             bool         NOT NULL DEFAULT true,
+    "status"             varchar(50)  NOT NULL, -- Pending till the spec is first reconciled, then Synced, Drifted or Failed
+    "message"            text,
+    "drift"              text,                  -- json of the objects last found changed out of band
+    "drift_detected_on"  timestamptz,
+    "last_reconciled_on" timestamptz,
+    "created_on"         timestamptz  NOT NULL,
+    "created_by"         int4         NOT NULL,
+    "updated_on"         timestamptz  NOT NULL,
+    "updated_by"         int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT environment_namespace_spec_environment_id_fkey FOREIGN KEY ("environment_id") REFERENCES "public"."environment" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS environment_namespace_spec_environment_id_uq ON environment_namespace_spec (environment_id) WHERE active = true;
+
+COMMIT;
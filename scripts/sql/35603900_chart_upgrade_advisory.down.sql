@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."chart_upgrade_advisory";
+DROP SEQUENCE IF EXISTS id_seq_chart_upgrade_advisory;
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+-- latest chart version available for each installed chart store app, refreshed by the upgrade advisor
+CREATE SEQUENCE IF NOT EXISTS id_seq_chart_upgrade_advisory;
+
+CREATE TABLE IF NOT EXISTS "public"."chart_upgrade_advisory"
+(
+    "id"                                       int4         NOT NULL DEFAULT nextval('id_seq_chart_upgrade_advisory'::regclass),
+    "installed_app_id"                         int4         NOT NULL,
+    "installed_app_version_id"                 int4         NOT NULL,
+    "current_app_store_application_version_id" int4         NOT NULL,
+    "current_version"                          varchar(100) NOT NULL,
+    "latest_app_store_application_version_id"  int4,
+    "latest_version"                           varchar(100),
+    "upgrade_type"                             varchar(20)  NOT NULL, -- none, patch, minor or major
+    "values_diff"                              text,                  -- json of the override keys removed, renamed or newly required in the latest version
+    "message"                                  text,                  -- why the advisory could not be computed
+    "computed_on"                              timestamptz  NOT NULL,
+    "created_on"                               timestamptz  NOT NULL,
+    "created_by"                               int4         NOT NULL,
+    "updated_on"                               timestamptz  NOT NULL,
+    "updated_by"                               int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT chart_upgrade_advisory_installed_app_id_fkey FOREIGN KEY ("installed_app_id") REFERENCES "public"."installed_apps" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS chart_upgrade_advisory_installed_app_id_uq ON chart_upgrade_advisory (installed_app_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."app_store_values_schema";
+DROP SEQUENCE IF EXISTS id_seq_app_store_values_schema;
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+-- values schema of a chart version, from the values.schema.json of the chart or inferred from its default values
+CREATE SEQUENCE IF NOT EXISTS id_seq_app_store_values_schema;
+
+CREATE TABLE IF NOT EXISTS "public"."app_store_values_schema"
+(
+    "id"                               int4        NOT NULL DEFAULT nextval('id_seq_app_store_values_schema'::regclass),
+    "app_store_application_version_id" int4        NOT NULL,
+    "schema"                           text        NOT NULL,
+    "source"                           varchar(20) NOT NULL, -- chart or inferred
+    "created_on"                       timestamptz NOT NULL,
+    "created_by"                       int4        NOT NULL,
+    "updated_on"                       timestamptz NOT NULL,
+    "updated_by"                       int4        NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT app_store_values_schema_app_store_application_version_id_fkey FOREIGN KEY ("app_store_application_version_id") REFERENCES "public"."app_store_application_version" ("id") ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS app_store_values_schema_app_store_application_version_id_uq ON app_store_values_schema (app_store_application_version_id);
+
+COMMIT;
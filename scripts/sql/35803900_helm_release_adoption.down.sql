@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."helm_release_adoption";
+DROP SEQUENCE IF EXISTS id_seq_helm_release_adoption;
+
+COMMIT;
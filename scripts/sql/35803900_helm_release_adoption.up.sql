@@ -0,0 +1,28 @@
+BEGIN;
+
+-- external helm releases adopted into the chart store, with the chart version they were matched to
+CREATE SEQUENCE IF NOT EXISTS id_seq_helm_release_adoption;
+
+CREATE TABLE IF NOT EXISTS "public"."helm_release_adoption"
+(
+    "id"                               int4         NOT NULL DEFAULT nextval('id_seq_helm_release_adoption'::regclass),
+    "installed_app_id"                 int4         NOT NULL,
+    "cluster_id"                       int4         NOT NULL,
+    "namespace"                        varchar(250) NOT NULL,
+    "release_name"                     varchar(250) NOT NULL,
+    "chart_name"                       varchar(250) NOT NULL,
+    "chart_version"                    varchar(250) NOT NULL,
+    "app_store_application_version_id" int4         NOT NULL,
+    "status"                           varchar(50)  NOT NULL, -- adopted, gitops_migration_initiated, gitops_migrated or gitops_migration_failed
+    "message"                          text,
+    "created_on"                       timestamptz  NOT NULL,
+    "created_by"                       int4         NOT NULL,
+    "updated_on"                       timestamptz  NOT NULL,
+    "updated_by"                       int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT helm_release_adoption_installed_app_id_fkey FOREIGN KEY ("installed_app_id") REFERENCES "public"."installed_apps" ("id") ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS helm_release_adoption_installed_app_id_uq ON helm_release_adoption (installed_app_id);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE deployment_template_history DROP COLUMN IF EXISTS post_render_patches;
+
+DROP INDEX IF EXISTS deployment_post_render_patch_app_id_env_id_uq;
+DROP TABLE IF EXISTS "public"."deployment_post_render_patch";
+DROP SEQUENCE IF EXISTS id_seq_deployment_post_render_patch;
+
+COMMIT;
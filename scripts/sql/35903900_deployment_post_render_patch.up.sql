@@ -0,0 +1,27 @@
+BEGIN;
+
+-- kustomize style patches applied to the rendered manifest of an app on an environment before it is deployed
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_post_render_patch;
+
+CREATE TABLE IF NOT EXISTS "public"."deployment_post_render_patch"
+(
+    "id"         int4        NOT NULL DEFAULT nextval('id_seq_deployment_post_render_patch'::regclass),
+    "app_id"     int4        NOT NULL,
+    "env_id"     int4        NOT NULL,
+    "patches"    text        NOT NULL, -- json list of strategicMerge and json6902 patches
+    "active"     bool        NOT NULL DEFAULT true,
+    "created_on" timestamptz NOT NULL,
+    "created_by" int4        NOT NULL,
+    "updated_on" timestamptz NOT NULL,
+    "updated_by" int4        NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT deployment_post_render_patch_app_id_fkey FOREIGN KEY ("app_id") REFERENCES "public"."app" ("id"),
+    CONSTRAINT deployment_post_render_patch_env_id_fkey FOREIGN KEY ("env_id") REFERENCES "public"."environment" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS deployment_post_render_patch_app_id_env_id_uq ON deployment_post_render_patch (app_id, env_id) WHERE active = true;
+
+-- patches used by a deployment, kept with the deployment template it was rendered from
+ALTER TABLE deployment_template_history ADD COLUMN IF NOT EXISTS post_render_patches text;
+
+COMMIT;
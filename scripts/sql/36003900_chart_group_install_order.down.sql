@@ -0,0 +1,15 @@
+BEGIN;
+
+DROP INDEX IF EXISTS chart_group_install_entry_install_id_idx;
+DROP TABLE IF EXISTS "public"."chart_group_install_entry";
+DROP SEQUENCE IF EXISTS id_seq_chart_group_install_entry;
+
+DROP INDEX IF EXISTS chart_group_install_status_idx;
+DROP TABLE IF EXISTS "public"."chart_group_install";
+DROP SEQUENCE IF EXISTS id_seq_chart_group_install;
+
+ALTER TABLE chart_group_entry DROP COLUMN IF EXISTS readiness;
+ALTER TABLE chart_group_entry DROP COLUMN IF EXISTS depends_on;
+ALTER TABLE chart_group_entry DROP COLUMN IF EXISTS entry_key;
+
+COMMIT;
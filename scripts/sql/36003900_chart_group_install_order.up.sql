@@ -0,0 +1,61 @@
+BEGIN;
+
+-- key other entries of the chart group refer to in their dependencies, and the readiness the dependents wait for
+ALTER TABLE chart_group_entry ADD COLUMN IF NOT EXISTS entry_key varchar(250);
+ALTER TABLE chart_group_entry ADD COLUMN IF NOT EXISTS depends_on text; -- json list of entry keys
+ALTER TABLE chart_group_entry ADD COLUMN IF NOT EXISTS readiness text;  -- json of type, conditions and timeoutSeconds
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_chart_group_install;
+
+-- an installation of a chart group, its entries are deployed in the order of their dependencies
+CREATE TABLE IF NOT EXISTS "public"."chart_group_install"
+(
+    "id"             int4         NOT NULL DEFAULT nextval('id_seq_chart_group_install'::regclass),
+    "chart_group_id" int4         NOT NULL,
+    "project_id"     int4         NOT NULL,
+    "failure_policy" varchar(50)  NOT NULL,
+    "status"         varchar(50)  NOT NULL,
+    "message"        text,
+    "started_on"     timestamptz  NOT NULL,
+    "finished_on"    timestamptz,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     int4         NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT chart_group_install_chart_group_id_fkey FOREIGN KEY ("chart_group_id") REFERENCES "public"."chart_group" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS chart_group_install_status_idx ON chart_group_install (status);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_chart_group_install_entry;
+
+CREATE TABLE IF NOT EXISTS "public"."chart_group_install_entry"
+(
+    "id"                               int4         NOT NULL DEFAULT nextval('id_seq_chart_group_install_entry'::regclass),
+    "chart_group_install_id"           int4         NOT NULL,
+    "chart_group_entry_id"             int4,
+    "entry_key"                        varchar(250) NOT NULL,
+    "depends_on"                       text,
+    "readiness"                        text,
+    "installed_app_id"                 int4         NOT NULL,
+    "installed_app_version_id"         int4         NOT NULL,
+    "installed_app_version_history_id" int4,
+    "app_name"                         varchar(250) NOT NULL,
+    "environment_id"                   int4         NOT NULL,
+    "status"                           varchar(50)  NOT NULL,
+    "message"                          text,
+    "deployed_on"                      timestamptz,
+    "ready_on"                         timestamptz,
+    "readiness_deadline"               timestamptz,
+    "created_on"                       timestamptz  NOT NULL,
+    "created_by"                       int4         NOT NULL,
+    "updated_on"                       timestamptz  NOT NULL,
+    "updated_by"                       int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT chart_group_install_entry_install_id_fkey FOREIGN KEY ("chart_group_install_id") REFERENCES "public"."chart_group_install" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS chart_group_install_entry_install_id_idx ON chart_group_install_entry (chart_group_install_id);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."chart_mirror_version";
+DROP SEQUENCE IF EXISTS id_seq_chart_mirror_version;
+DROP TABLE IF EXISTS "public"."chart_mirror";
+DROP SEQUENCE IF EXISTS id_seq_chart_mirror;
+
+COMMIT;
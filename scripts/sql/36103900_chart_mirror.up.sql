@@ -0,0 +1,54 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_chart_mirror;
+
+-- a chart of the chart store mirrored into an OCI registry configured in Devtron, for installs on air-gapped clusters
+CREATE TABLE IF NOT EXISTS "public"."chart_mirror"
+(
+    "id"                 int4         NOT NULL DEFAULT nextval('id_seq_chart_mirror'::regclass),
+    "app_store_id"       int4,
+    "chart_name"         varchar(250) NOT NULL,
+    "version_constraint" varchar(250), -- semver range of the versions to mirror, empty for all versions
+    "target_registry_id" varchar(250) NOT NULL,
+    "target_repository"  varchar(500) NOT NULL,
+    "active"             bool         NOT NULL DEFAULT true,
+    "last_synced_on"     timestamptz,
+    "created_on"         timestamptz  NOT NULL,
+    "created_by"         int4         NOT NULL,
+    "updated_on"         timestamptz  NOT NULL,
+    "updated_by"         int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS chart_mirror_target_unique_idx ON chart_mirror (chart_name, target_registry_id, target_repository);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_chart_mirror_version;
+
+-- manifest of the chart versions mirrored with their digests
+CREATE TABLE IF NOT EXISTS "public"."chart_mirror_version"
+(
+    "id"                               int4         NOT NULL DEFAULT nextval('id_seq_chart_mirror_version'::regclass),
+    "chart_mirror_id"                  int4         NOT NULL,
+    "app_store_application_version_id" int4,
+    "chart_name"                       varchar(250) NOT NULL,
+    "chart_version"                    varchar(250) NOT NULL,
+    "source_url"                       text,
+    "target_registry_id"               varchar(250) NOT NULL,
+    "mirror_repository"                varchar(750) NOT NULL,
+    "digest"                           varchar(100), -- sha256 of the chart archive
+    "manifest_digest"                  varchar(100), -- digest of the OCI manifest pushed to the target registry
+    "status"                           varchar(50)  NOT NULL,
+    "message"                          text,
+    "mirrored_on"                      timestamptz,
+    "created_on"                       timestamptz  NOT NULL,
+    "created_by"                       int4         NOT NULL,
+    "updated_on"                       timestamptz  NOT NULL,
+    "updated_by"                       int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT chart_mirror_version_chart_mirror_id_fkey FOREIGN KEY ("chart_mirror_id") REFERENCES "public"."chart_mirror" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS chart_mirror_version_unique_idx ON chart_mirror_version (chart_mirror_id, chart_version);
+CREATE INDEX IF NOT EXISTS chart_mirror_version_app_store_application_version_id_idx ON chart_mirror_version (app_store_application_version_id);
+
+COMMIT;
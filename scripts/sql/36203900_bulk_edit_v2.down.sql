@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."bulk_edit_target";
+DROP SEQUENCE IF EXISTS id_seq_bulk_edit_target;
+DROP TABLE IF EXISTS "public"."bulk_edit";
+DROP SEQUENCE IF EXISTS id_seq_bulk_edit;
+
+COMMIT;
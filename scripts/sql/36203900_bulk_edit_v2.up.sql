@@ -0,0 +1,54 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_bulk_edit;
+
+-- a bulk edit v2 request, created on preview and applied asynchronously
+CREATE TABLE IF NOT EXISTS "public"."bulk_edit"
+(
+    "id"              int4         NOT NULL DEFAULT nextval('id_seq_bulk_edit'::regclass),
+    "name"            varchar(250),
+    "request"         text         NOT NULL, -- selector, targets and operations as json
+    "status"          varchar(50)  NOT NULL,
+    "total_targets"   int4         NOT NULL DEFAULT 0,
+    "changed_targets" int4         NOT NULL DEFAULT 0,
+    "succeeded"       int4         NOT NULL DEFAULT 0,
+    "failed"          int4         NOT NULL DEFAULT 0,
+    "rolled_back"     int4         NOT NULL DEFAULT 0,
+    "created_on"      timestamptz  NOT NULL,
+    "created_by"      int4         NOT NULL,
+    "updated_on"      timestamptz  NOT NULL,
+    "updated_by"      int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_bulk_edit_target;
+
+-- one document edited by a bulk edit with its preview diff and the applied change set used for rollback
+CREATE TABLE IF NOT EXISTS "public"."bulk_edit_target"
+(
+    "id"            int4         NOT NULL DEFAULT nextval('id_seq_bulk_edit_target'::regclass),
+    "bulk_edit_id"  int4         NOT NULL,
+    "kind"          varchar(50)  NOT NULL,
+    "app_id"        int4         NOT NULL,
+    "app_name"      varchar(250) NOT NULL,
+    "env_id"        int4         NOT NULL DEFAULT 0, -- 0 for base configuration
+    "env_name"      varchar(250),
+    "resource_id"   int4         NOT NULL,
+    "resource_name" varchar(250),
+    "status"        varchar(50)  NOT NULL,
+    "diff"          text,
+    "error"         text,
+    "before_data"   text,
+    "after_data"    text,
+    "before_values" text,
+    "created_on"    timestamptz  NOT NULL,
+    "created_by"    int4         NOT NULL,
+    "updated_on"    timestamptz  NOT NULL,
+    "updated_by"    int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "bulk_edit_target_bulk_edit_id_fkey" FOREIGN KEY ("bulk_edit_id") REFERENCES "public"."bulk_edit" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS bulk_edit_target_bulk_edit_id_idx ON bulk_edit_target (bulk_edit_id);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."chart_ref_migration_target";
+DROP SEQUENCE IF EXISTS id_seq_chart_ref_migration_target;
+DROP TABLE IF EXISTS "public"."chart_ref_migration";
+DROP SEQUENCE IF EXISTS id_seq_chart_ref_migration;
+
+COMMIT;
@@ -0,0 +1,55 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_chart_ref_migration;
+
+-- a bulk chart ref migration, created on dry run and committed asynchronously
+CREATE TABLE IF NOT EXISTS "public"."chart_ref_migration"
+(
+    "id"                   int4         NOT NULL DEFAULT nextval('id_seq_chart_ref_migration'::regclass),
+    "name"                 varchar(250),
+    "request"              text         NOT NULL, -- selector, target chart ref and custom rules as json
+    "status"               varchar(50)  NOT NULL,
+    "target_chart_ref_id"  int4         NOT NULL,
+    "target_chart_name"    varchar(250),
+    "target_chart_version" varchar(250),
+    "total_targets"        int4         NOT NULL DEFAULT 0,
+    "ready_targets"        int4         NOT NULL DEFAULT 0,
+    "succeeded"            int4         NOT NULL DEFAULT 0,
+    "failed"               int4         NOT NULL DEFAULT 0,
+    "created_on"           timestamptz  NOT NULL,
+    "created_by"           int4         NOT NULL,
+    "updated_on"           timestamptz  NOT NULL,
+    "updated_by"           int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_chart_ref_migration_target;
+
+-- one base or environment deployment template of a migration with its dry run report
+CREATE TABLE IF NOT EXISTS "public"."chart_ref_migration_target"
+(
+    "id"                     int4         NOT NULL DEFAULT nextval('id_seq_chart_ref_migration_target'::regclass),
+    "chart_ref_migration_id" int4         NOT NULL,
+    "app_id"                 int4         NOT NULL,
+    "app_name"               varchar(250) NOT NULL,
+    "env_id"                 int4         NOT NULL DEFAULT 0, -- 0 for base deployment template
+    "env_name"               varchar(250),
+    "source_chart_ref_id"    int4         NOT NULL,
+    "source_chart_name"      varchar(250),
+    "source_chart_version"   varchar(250),
+    "status"                 varchar(50)  NOT NULL,
+    "applied_rules"          text,
+    "values_diff"            text,
+    "manifest_diff"          text,
+    "message"                text,
+    "created_on"             timestamptz  NOT NULL,
+    "created_by"             int4         NOT NULL,
+    "updated_on"             timestamptz  NOT NULL,
+    "updated_by"             int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "chart_ref_migration_target_migration_id_fkey" FOREIGN KEY ("chart_ref_migration_id") REFERENCES "public"."chart_ref_migration" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS chart_ref_migration_target_migration_id_idx ON chart_ref_migration_target (chart_ref_migration_id);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."app_template_app";
+DROP SEQUENCE IF EXISTS id_seq_app_template_app;
+DROP TABLE IF EXISTS "public"."app_template_version";
+DROP SEQUENCE IF EXISTS id_seq_app_template_version;
+DROP TABLE IF EXISTS "public"."app_template";
+DROP SEQUENCE IF EXISTS id_seq_app_template;
+
+COMMIT;
@@ -0,0 +1,63 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_app_template;
+
+-- a reusable app blueprint, its content lives in the published versions
+CREATE TABLE IF NOT EXISTS "public"."app_template"
+(
+    "id"             int4         NOT NULL DEFAULT nextval('id_seq_app_template'::regclass),
+    "name"           varchar(250) NOT NULL,
+    "description"    text,
+    "latest_version" int4         NOT NULL,
+    "active"         bool         NOT NULL DEFAULT true,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     int4         NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS app_template_name_active_idx ON app_template (name) WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_app_template_version;
+
+-- immutable published version of a template
+CREATE TABLE IF NOT EXISTS "public"."app_template_version"
+(
+    "id"              int4        NOT NULL DEFAULT nextval('id_seq_app_template_version'::regclass),
+    "app_template_id" int4        NOT NULL,
+    "version"         int4        NOT NULL,
+    "parameters"      text, -- declared parameters as json
+    "definition"      text        NOT NULL, -- app definition with ${{ parameter }} placeholders
+    "change_note"     text,
+    "created_on"      timestamptz NOT NULL,
+    "created_by"      int4        NOT NULL,
+    "updated_on"      timestamptz NOT NULL,
+    "updated_by"      int4        NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "app_template_version_app_template_id_fkey" FOREIGN KEY ("app_template_id") REFERENCES "public"."app_template" ("id"),
+    UNIQUE ("app_template_id", "version")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_app_template_app;
+
+-- app created from a template with the version and parameter values used
+CREATE TABLE IF NOT EXISTS "public"."app_template_app"
+(
+    "id"              int4        NOT NULL DEFAULT nextval('id_seq_app_template_app'::regclass),
+    "app_id"          int4        NOT NULL,
+    "app_template_id" int4        NOT NULL,
+    "version"         int4        NOT NULL,
+    "parameters"      text, -- values as json, sensitive values are redacted
+    "created_on"      timestamptz NOT NULL,
+    "created_by"      int4        NOT NULL,
+    "updated_on"      timestamptz NOT NULL,
+    "updated_by"      int4        NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "app_template_app_app_id_fkey" FOREIGN KEY ("app_id") REFERENCES "public"."app" ("id"),
+    CONSTRAINT "app_template_app_app_template_id_fkey" FOREIGN KEY ("app_template_id") REFERENCES "public"."app_template" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS app_template_app_app_template_id_idx ON app_template_app (app_template_id);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS "public"."app_as_code_app";
+DROP SEQUENCE IF EXISTS id_seq_app_as_code_app;
+DROP TABLE IF EXISTS "public"."app_as_code_source";
+DROP SEQUENCE IF EXISTS id_seq_app_as_code_source;
+
+COMMIT;
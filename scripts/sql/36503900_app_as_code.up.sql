@@ -0,0 +1,64 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_app_as_code_source;
+
+-- git repository holding declarative app definitions
+CREATE TABLE IF NOT EXISTS "public"."app_as_code_source"
+(
+    "id"                      int4         NOT NULL DEFAULT nextval('id_seq_app_as_code_source'::regclass),
+    "name"                    varchar(250) NOT NULL,
+    "git_provider_id"         int4         NOT NULL,
+    "git_repo_url"            text         NOT NULL,
+    "git_branch"              varchar(250),
+    "spec_path"               text,
+    "drift_mode"              varchar(50)  NOT NULL, -- GIT_WINS or WARN_ONLY
+    "git_material_id"         int4, -- material registered with git-sensor, id taken from git_material_id_seq
+    "ci_pipeline_material_id" int4, -- branch polled by git-sensor, id taken from ci_pipeline_material_id_seq
+    "active"                  bool         NOT NULL DEFAULT true,
+    "last_synced_commit"      varchar(100),
+    "last_synced_on"          timestamptz,
+    "last_sync_status"        varchar(50),
+    "last_sync_message"       text,
+    "sync_started_on"         timestamptz, -- set while an orchestrator syncs the source, cleared when the sync ends
+    "created_on"              timestamptz  NOT NULL,
+    "created_by"              int4         NOT NULL,
+    "updated_on"              timestamptz  NOT NULL,
+    "updated_by"              int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "app_as_code_source_git_provider_id_fkey" FOREIGN KEY ("git_provider_id") REFERENCES "public"."git_provider" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS app_as_code_source_name_active_idx ON app_as_code_source (name) WHERE active = true;
+CREATE INDEX IF NOT EXISTS app_as_code_source_ci_pipeline_material_id_idx ON app_as_code_source (ci_pipeline_material_id) WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_app_as_code_app;
+
+-- app managed by a source with the result of its last sync
+CREATE TABLE IF NOT EXISTS "public"."app_as_code_app"
+(
+    "id"              int4         NOT NULL DEFAULT nextval('id_seq_app_as_code_app'::regclass),
+    "source_id"       int4         NOT NULL,
+    "app_id"          int4,
+    "app_name"        varchar(250),
+    "file_path"       text         NOT NULL,
+    "definition"      text, -- definition file content as of applied_commit
+    "status"          varchar(50)  NOT NULL,
+    "message"         text,
+    "section_results" text, -- json, secret data is redacted
+    "spec_hashes"     text, -- json of section digests of the definition last synced
+    "state_hashes"    text, -- json of section digests of the app state after the last sync
+    "applied_commit"  varchar(100),
+    "last_synced_on"  timestamptz,
+    "active"          bool         NOT NULL DEFAULT true,
+    "created_on"      timestamptz  NOT NULL,
+    "created_by"      int4         NOT NULL,
+    "updated_on"      timestamptz  NOT NULL,
+    "updated_by"      int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "app_as_code_app_source_id_fkey" FOREIGN KEY ("source_id") REFERENCES "public"."app_as_code_source" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS app_as_code_app_source_id_idx ON app_as_code_app (source_id) WHERE active = true;
+CREATE UNIQUE INDEX IF NOT EXISTS app_as_code_app_app_id_active_idx ON app_as_code_app (app_id) WHERE active = true AND app_id > 0;
+
+COMMIT;
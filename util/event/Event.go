@@ -21,11 +21,13 @@ type EventType int
 const Trigger EventType = 1
 const Success EventType = 2
 const Fail EventType = 3
+const ClusterCredentialExpiry EventType = 10
 
 type PipelineType string
 
 const CI PipelineType = "CI"
 const CD PipelineType = "CD"
+const CLUSTER PipelineType = "CLUSTER"
 
 type Level string
 